@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/algonius/algonius-wallet/native/pkg/chains/solana/broadcast"
 	"github.com/algonius/algonius-wallet/native/pkg/config"
 	"github.com/algonius/algonius-wallet/native/pkg/dex"
 	"github.com/algonius/algonius-wallet/native/pkg/dex/providers"
@@ -20,11 +22,13 @@ import (
 	"github.com/algonius/algonius-wallet/native/pkg/mcp"
 	"github.com/algonius/algonius-wallet/native/pkg/mcp/resources"
 	"github.com/algonius/algonius-wallet/native/pkg/mcp/tools"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
 	"github.com/algonius/algonius-wallet/native/pkg/messaging"
 	"github.com/algonius/algonius-wallet/native/pkg/messaging/handlers"
 	"github.com/algonius/algonius-wallet/native/pkg/process"
 	"github.com/algonius/algonius-wallet/native/pkg/wallet"
 	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
@@ -130,14 +134,45 @@ func main() {
 	// Create shared wallet manager with configuration
 	walletManager := wallet.NewWalletManagerWithConfig(appConfig, dexAggregator, zapLogger)
 
+	// Wallet files must not be readable by other users of the machine
+	if err := walletManager.CheckStoragePermissions(appConfig.Wallet.FilePermissions.OnInsecure); err != nil {
+		zapLogger.Error("Wallet file permission check failed", zap.Error(err))
+		os.Exit(1)
+	}
+
+	// Record balance snapshots for get_balance_history in the background
+	go walletManager.RunBalanceSnapshots(context.Background())
+
+	// Import configured token lists and keep them refreshed
+	go wallet.NewTokenListImporter(appConfig.Wallet.TokenLists, wallet.DefaultTokenLists, zapLogger).Run(context.Background())
+
 	// Create EventBroadcaster for real-time events to AI Agents
 	eventBroadcaster := event.NewEventBroadcaster(zapLogger)
 
+	// Report reorgs and stalls of the enabled chains so agents can pause trading
+	if appConfig.Chains.Monitor.Enabled {
+		chainMonitor, err := chain.NewConfiguredChainMonitor(appConfig, zapLogger)
+		if err != nil {
+			zapLogger.Error("Failed to start chain monitor", zap.Error(err))
+		} else {
+			chainMonitor.OnReorg(func(reorg chain.ChainReorg) {
+				eventBroadcaster.BroadcastChainReorgDetected(reorg.Chain, reorg.Depth, reorg.AtLeast, reorg.OldHead.Number, reorg.NewHead.Number, reorg.OldHead.Hash, reorg.NewHead.Hash)
+			})
+			chainMonitor.OnStall(func(stall chain.ChainStall) {
+				eventBroadcaster.BroadcastChainStalled(stall.Chain, stall.LastBlock, stall.LastAdvance, stall.StalledFor, stall.LastError)
+			})
+			go chainMonitor.Run(context.Background())
+		}
+	}
+
 	logr.Info("Starting Algonius Native Host with both Native Messaging and HTTP/MCP servers")
 
 	// Initialize Native Messaging for browser extension communication
 	nm, err := messaging.NewNativeMessaging(messaging.NativeMessagingConfig{
-		Logger: logr,
+		Logger:                logr,
+		LogPayloads:           appConfig.Logging.PayloadLoggingEnabled(),
+		Broadcaster:           eventBroadcaster,
+		MaxInboundMessageSize: appConfig.NativeMessaging.MaxMessageSize,
 	})
 	if err != nil {
 		logr.Error("Failed to initialize native messaging", zap.Error(err))
@@ -150,7 +185,27 @@ func main() {
 	nm.RegisterRpcMethod("unlock_wallet", handlers.CreateUnlockWalletHandler(walletManager))
 	nm.RegisterRpcMethod("lock_wallet", handlers.CreateLockWalletHandler(walletManager))
 	nm.RegisterRpcMethod("wallet_status", handlers.CreateWalletStatusHandler(walletManager, zapLogger))
-	nm.RegisterRpcMethod("web3_request", handlers.CreateWeb3RequestHandler(walletManager, eventBroadcaster))
+	nm.RegisterRpcMethod("freeze_wallet", handlers.CreateFreezeWalletHandler(walletManager))
+	nm.RegisterRpcMethod("unfreeze_wallet", handlers.CreateUnfreezeWalletHandler(walletManager))
+	nm.RegisterRpcMethod("export_wallet", handlers.CreateExportWalletHandler(walletManager))
+	nm.RegisterRpcMethod("set_wallet_label", handlers.CreateSetWalletLabelHandler(walletManager))
+	nm.RegisterRpcMethod("switch_wallet", handlers.CreateSwitchWalletHandler(walletManager))
+	nm.RegisterRpcMethod("add_address_book_entry", handlers.CreateAddAddressBookEntryHandler(walletManager))
+	nm.RegisterRpcMethod("remove_address_book_entry", handlers.CreateRemoveAddressBookEntryHandler(walletManager))
+	nm.RegisterRpcMethod("list_address_book", handlers.CreateListAddressBookHandler(walletManager))
+	// The DEX aggregator is attached to the price feed once it has been built below
+	priceFeed := wallet.NewDEXPriceFeed(nil)
+	priceFeed.SetHistoricalPriceSource(wallet.NewHistoricalPriceSource(appConfig.Wallet.PriceHistory), appConfig.Wallet.PriceHistory.CacheSize)
+	// Unlocking primes the RPC connections and this feed's cache in the background
+	walletManager.SetPriceFeed(priceFeed)
+	walletManager.OnCacheWarmed(func(warmup wallet.CacheWarmup) {
+		eventBroadcaster.BroadcastCacheWarmed(warmup.Balances, warmup.Prices, warmup.Failures, warmup.Duration, warmup.Complete)
+	})
+	// dApps and the extension share the active EVM chain
+	evmNetwork := handlers.NewEVMNetwork(appConfig.Wallet, appConfig.Chains)
+	nm.RegisterRpcMethod("web3_request", handlers.CreateWeb3RequestHandlerWithNetwork(walletManager, eventBroadcaster, priceFeed, appConfig.Security.AutoApproval, evmNetwork))
+	nm.RegisterRpcMethod("wallet_switch_network", handlers.CreateSwitchNetworkHandler(evmNetwork, eventBroadcaster))
+	nm.RegisterRpcMethod("parse_web3_request", handlers.CreateParseWeb3RequestHandlerWithNetwork(walletManager, appConfig.Security.AutoApproval, evmNetwork))
 
 	// Register init, status, shutdown RPC methods
 	nm.RegisterRpcMethod("init", func(req messaging.RpcRequest) (messaging.RpcResponse, error) {
@@ -221,9 +276,17 @@ func main() {
 	})
 
 	// Create MCP server for AI Agent communication
+	var serverOptions []server.ServerOption
+	if appConfig.Logging.PayloadLoggingEnabled() {
+		logr.Info("Logging redacted MCP and native messaging payloads")
+		serverOptions = append(serverOptions, server.WithToolHandlerMiddleware(toolutils.PayloadLoggingMiddleware(logr.Named("mcp"))))
+	}
+	// Every tool call answers within its configured timeout, even if an RPC endpoint hangs
+	serverOptions = append(serverOptions, server.WithToolHandlerMiddleware(toolutils.ToolTimeoutMiddleware(appConfig.MCP.TimeoutFor, logr.Named("mcp"))))
 	s := server.NewMCPServer(
 		"Algonius Native Host",
 		"0.1.0",
+		serverOptions...,
 	)
 
 	// Register chains://supported resource
@@ -239,14 +302,105 @@ func main() {
 	getBalanceTool := tools.NewGetBalanceTool(walletManager)
 	mcp.RegisterTool(s, getBalanceTool)
 
-	sendTransactionTool := tools.NewSendTransactionTool(walletManager)
+	getSpendableBalanceTool := tools.NewGetSpendableBalanceTool(walletManager)
+	mcp.RegisterTool(s, getSpendableBalanceTool)
+
+	sendTransactionTool := tools.NewSendTransactionToolWithConfig(walletManager, eventBroadcaster, priceFeed, appConfig)
 	mcp.RegisterTool(s, sendTransactionTool)
 
-	approveTransactionTool := tools.NewApproveTransactionTool(walletManager, eventBroadcaster, zapLogger)
+	sendTokenTool := tools.NewSendTokenTool(sendTransactionTool)
+	mcp.RegisterTool(s, sendTokenTool)
+
+	submitBundleTool := tools.NewSubmitBundleTool(walletManager)
+	mcp.RegisterTool(s, submitBundleTool)
+
+	approveTransactionTool := tools.NewApproveTransactionToolWithConfig(walletManager, eventBroadcaster, zapLogger, appConfig)
+	approveTransactionTool.SetPriceFeed(priceFeed)
 	mcp.RegisterTool(s, approveTransactionTool)
 
+	// Freezing, from the extension or an agent, stops monitors and notifies subscribers
+	walletManager.OnFreezeChange(func(status wallet.FreezeStatus) {
+		if !status.Frozen {
+			eventBroadcaster.BroadcastWalletUnfrozen(status.Source)
+			return
+		}
+		stopped := approveTransactionTool.CancelMonitors() + sendTransactionTool.CancelCallbacks()
+		logr.Warn("Wallet frozen, transaction monitors cancelled", zap.Int("monitors", stopped))
+		eventBroadcaster.BroadcastWalletFrozen(status.Reason, status.Source, status.FrozenAt)
+	})
+
+	walletManager.OnSpendLimitExceeded(func(exceeded wallet.SpendLimitExceededError) {
+		eventBroadcaster.BroadcastSpendLimitExceeded(exceeded.Chain, exceeded.From, exceeded.To, exceeded.Amount, exceeded.Token, exceeded.Limit, exceeded.Max, exceeded.Spent)
+	})
+
+	scheduleTransactionTool := tools.NewScheduleTransactionToolWithConfig(walletManager, eventBroadcaster, priceFeed, appConfig)
+	mcp.RegisterTool(s, scheduleTransactionTool)
+	mcp.RegisterTool(s, tools.NewListScheduledTool(walletManager))
+	mcp.RegisterTool(s, tools.NewCancelScheduledTool(walletManager))
+
+	// Send due scheduled transactions with the checks send_transaction applies, and report every run
+	walletManager.OnScheduledExecution(func(execution wallet.ScheduledExecution) {
+		job := execution.Job
+		if execution.Err != nil {
+			logr.Warn("Scheduled transaction failed", zap.String("schedule_id", job.ID), zap.Error(execution.Err))
+			eventBroadcaster.BroadcastScheduledTransactionFailed(job.ID, job.Chain, job.From, job.To, job.Amount, job.Token, execution.Err.Error(), job.Runs, job.Status)
+			return
+		}
+		eventBroadcaster.BroadcastScheduledTransactionExecuted(job.ID, execution.TxHash, job.Chain, job.From, job.To, job.Amount, job.Token, job.Runs, job.Status)
+	})
+	go walletManager.RunScheduledTransactions(context.Background(), tools.NewScheduledTransactionSender(walletManager, eventBroadcaster, priceFeed, appConfig))
+
+	freezeWalletTool := tools.NewFreezeWalletTool(walletManager)
+	mcp.RegisterTool(s, freezeWalletTool)
+
+	listWalletsTool := tools.NewListWalletsTool(walletManager)
+	mcp.RegisterTool(s, listWalletsTool)
+
+	mcp.RegisterTool(s, tools.NewAddAddressBookEntryTool(walletManager))
+	mcp.RegisterTool(s, tools.NewRemoveAddressBookEntryTool(walletManager))
+	mcp.RegisterTool(s, tools.NewListAddressBookTool(walletManager))
+
 	// Create DEX aggregator with OKX and Direct providers
-	dexAggregator = dex.NewDEXAggregator(zapLogger)
+	aggregator := dex.NewDEXAggregatorWithFanOut(zapLogger, dex.QuoteFanOut{
+		ProviderTimeout: appConfig.DEX.Composite.ProviderTimeout,
+		Deadline:        appConfig.DEX.Composite.Timeout,
+		MaxConcurrency:  appConfig.DEX.Composite.MaxConcurrency,
+	})
+	aggregator.SetDecisionHistory(appConfig.DEX.Composite.DecisionHistory)
+	if err := aggregator.SetPartialFillPolicy(appConfig.DEX.PartialFill); err != nil {
+		logr.Error("Failed to set partial fill policy, partial fills will revert", zap.Error(err))
+	}
+	// Swaps are signed by the providers, so they pass the wallet's signing policies here
+	aggregator.SetSwapAuthorizer(func(ctx context.Context, chainName string, params dex.SwapParams) error {
+		return walletManager.AuthorizeSigning(ctx, wallet.SigningRequest{
+			Kind:    wallet.SigningKindSwap,
+			Chain:   chainName,
+			From:    params.FromAddress,
+			To:      params.ToAddress,
+			Amount:  params.Amount,
+			Token:   params.FromToken,
+			ToToken: params.ToToken,
+		})
+	})
+	pairPolicy := dex.NewPairPolicy(appConfig.DEX.PairPolicy, zapLogger)
+	pairPolicy.SetTokenResolver(wallet.TokenAliases)
+	aggregator.SetPairPolicy(pairPolicy)
+	// Blocklist files are re-read when they change; SIGHUP re-reads the lists
+	// in the config file too
+	go func() {
+		reload := make(chan os.Signal, 1)
+		signal.Notify(reload, syscall.SIGHUP)
+		for range reload {
+			reloaded, err := config.LoadConfig(config.GetConfigPath())
+			if err != nil {
+				logr.Warn("Failed to reload pair policy, keeping the current one", zap.Error(err))
+				continue
+			}
+			pairPolicy.Update(reloaded.DEX.PairPolicy)
+			logr.Info("Pair policy reloaded")
+		}
+	}()
+	dexAggregator = aggregator
 
 	// Register Direct provider for backward compatibility
 	directProvider := providers.NewDirectProvider(zapLogger)
@@ -272,8 +426,20 @@ func main() {
 			APIKey:     os.Getenv("OKX_API_KEY"),
 			SecretKey:  os.Getenv("OKX_SECRET_KEY"),
 			Passphrase: os.Getenv("OKX_PASSPHRASE"),
+			Rotation:   appConfig.DEX.OKEx.Rotation,
 		}
-		if okxConfig.APIKey != "" && okxConfig.SecretKey != "" && okxConfig.Passphrase != "" {
+		if okxConfig.APIKey == "" || okxConfig.SecretKey == "" || okxConfig.Passphrase == "" {
+			okxConfig.APIKey = ""
+		}
+		// Credential sets from the config file rotate alongside the env credentials
+		for _, creds := range appConfig.DEX.OKEx.CredentialSets() {
+			okxConfig.Credentials = append(okxConfig.Credentials, providers.OKXCredentials{
+				APIKey:     creds.APIKey,
+				SecretKey:  creds.SecretKey,
+				Passphrase: creds.PassPhrase,
+			})
+		}
+		if okxConfig.APIKey != "" || len(okxConfig.Credentials) > 0 {
 			okxProvider := providers.NewOKXProvider(okxConfig, zapLogger)
 			if err := dexAggregator.RegisterProvider(okxProvider); err != nil {
 				logr.Error("Failed to register OKX provider", zap.Error(err))
@@ -283,19 +449,76 @@ func main() {
 		} else {
 			logr.Info("OKX credentials not provided, skipping OKX provider registration")
 		}
+
+		// Register Jupiter provider for Solana swaps, sent through its broadcast channel
+		if appConfig.DEX.Jupiter.Enabled {
+			jupiterBroadcast := broadcast.NewBroadcastManager(&config.BroadcastConfig{Channel: appConfig.DEX.Jupiter.BroadcastChannel})
+			if appConfig.DEX.Jupiter.BroadcastChannel == "paper" {
+				jupiterBroadcast.RegisterChannel(broadcast.NewPaperChannel(zapLogger))
+			} else {
+				jupiterBroadcast.RegisterChannel(broadcast.NewSolanaRPCChannel(&appConfig.Chains.Solana, zapLogger))
+			}
+			jupiterProvider := providers.NewJupiterProvider(providers.JupiterConfig{
+				BaseURL:      appConfig.DEX.Jupiter.BaseURL,
+				Timeout:      time.Duration(appConfig.DEX.Jupiter.Timeout) * time.Second,
+				RPCEndpoints: appConfig.Chains.Solana.RPCEndpoints,
+				Broadcaster:  jupiterBroadcast,
+			}, zapLogger)
+			if err := dexAggregator.RegisterProvider(jupiterProvider); err != nil {
+				logr.Error("Failed to register Jupiter provider", zap.Error(err))
+			} else {
+				logr.Info("Jupiter DEX provider registered successfully")
+			}
+		}
 	}
 
+	// Registration failures above are only logged, so report what is usable per chain
+	dex.LogActiveProviders(dexAggregator, zapLogger)
+
+	priceFeed.SetAggregator(dexAggregator)
+
 	swapTokensToolNew := tools.NewSwapTokensToolWithAggregator(dexAggregator, zapLogger)
+	swapTokensToolNew.SetWalletManager(walletManager)
+	swapTokensToolNew.SetNativeWrapping(walletManager, appConfig.DEX.AutoWrap)
 	swapTokensToolNew.Register(s)
 
+	estimateSwapCostTool := tools.NewEstimateSwapCostTool(dexAggregator, priceFeed)
+	mcp.RegisterTool(s, estimateSwapCostTool)
+	mcp.RegisterTool(s, tools.NewGetDEXRoutingTool(dexAggregator))
+	mcp.RegisterTool(s, tools.NewGetTradingConfigTool(appConfig))
+
+	createPriceTriggerTool := tools.NewCreatePriceTriggerToolWithConfig(walletManager, eventBroadcaster, priceFeed, appConfig)
+	mcp.RegisterTool(s, createPriceTriggerTool)
+	mcp.RegisterTool(s, tools.NewListPriceTriggersTool(walletManager))
+	mcp.RegisterTool(s, tools.NewCancelPriceTriggerTool(walletManager))
+
+	// Run the actions of price triggers whose condition is met, with the checks a direct call applies
+	walletManager.OnPriceTriggerFired(func(firing wallet.PriceTriggerFiring) {
+		trigger := firing.Trigger
+		reason := ""
+		if firing.Err != nil {
+			reason = firing.Err.Error()
+			logr.Warn("Price trigger action failed", zap.String("trigger_id", trigger.ID), zap.Error(firing.Err))
+		}
+		eventBroadcaster.BroadcastTriggerFired(trigger.ID, trigger.Symbol, trigger.Comparison, trigger.Threshold, firing.Price,
+			trigger.Action.Kind, trigger.Action.Chain, firing.TxHash, reason, trigger.Fires, trigger.Status)
+	})
+	go walletManager.RunPriceTriggers(context.Background(), priceFeed, tools.NewPriceTriggerExecutor(walletManager, dexAggregator, eventBroadcaster, priceFeed, appConfig))
+
 	getPendingTransactionsTool := tools.NewGetPendingTransactionsTool(walletManager)
+	getPendingTransactionsTool.SetPriceFeed(priceFeed)
 	mcp.RegisterTool(s, getPendingTransactionsTool)
 
 	getTransactionHistoryTool := tools.NewGetTransactionHistoryTool(walletManager)
+	getTransactionHistoryTool.SetPriceFeed(priceFeed)
 	mcp.RegisterTool(s, getTransactionHistoryTool)
 
-	// Create chain factory for simulation tools
+	getBalanceHistoryTool := tools.NewGetBalanceHistoryTool(walletManager)
+	mcp.RegisterTool(s, getBalanceHistoryTool)
+
+	// Create chain factory for simulation tools and approved transaction execution
 	chainFactory := chain.NewChainFactory()
+	approveTransactionTool.SetChainFactory(chainFactory)
 
 	// Register simulation tools
 	simulateTransactionTool := tools.NewSimulateTransactionTool(walletManager, chainFactory)
@@ -304,12 +527,24 @@ func main() {
 	// Register new tools
 	signMessageTool := tools.NewSignMessageTool(walletManager, zapLogger)
 	mcp.RegisterTool(s, signMessageTool)
+	mcp.RegisterTool(s, tools.NewVerifySignatureTool())
+	mcp.RegisterTool(s, tools.NewGetFinalityInfoTool(appConfig))
+	mcp.RegisterTool(s, tools.NewDecodeRawTransactionTool(appConfig))
+	mcp.RegisterTool(s, tools.NewSignTokenPermitTool(walletManager, zapLogger))
+	mcp.RegisterTool(s, tools.NewDelegateStakeTool(walletManager, eventBroadcaster, zapLogger))
+	mcp.RegisterTool(s, tools.NewDeactivateStakeTool(walletManager, eventBroadcaster, zapLogger))
+	mcp.RegisterTool(s, tools.NewWithdrawStakeTool(walletManager, eventBroadcaster, zapLogger))
+	mcp.RegisterTool(s, tools.NewWrapTool(walletManager, zapLogger))
+	mcp.RegisterTool(s, tools.NewUnwrapTool(walletManager, zapLogger))
 
 	getTransactionStatusTool := tools.NewGetTransactionStatusTool(walletManager, zapLogger)
+	getTransactionStatusTool.SetChainFactory(chainFactory)
 	mcp.RegisterTool(s, getTransactionStatusTool)
 
-	estimateGasTool := tools.NewEstimateGasTool(chainFactory)
+	estimateGasTool := tools.NewEstimateGasToolWithPriceFeed(chainFactory, priceFeed)
 	mcp.RegisterTool(s, estimateGasTool)
+	mcp.RegisterTool(s, tools.NewEstimateConfirmationTimeTool(walletManager, zapLogger))
+	mcp.RegisterTool(s, tools.NewGetGasEstimateTool(walletManager, zapLogger))
 
 	deployContractTool := tools.NewDeployContractTool()
 	mcp.RegisterTool(s, deployContractTool)
@@ -317,6 +552,20 @@ func main() {
 	callContractTool := tools.NewCallContractTool()
 	mcp.RegisterTool(s, callContractTool)
 
+	getNFTsTool := tools.NewGetNFTsTool(walletManager)
+	getNFTsTool.SetMetadataFetcher(wallet.NewMetadataFetcher(appConfig.Wallet.Metadata))
+	mcp.RegisterTool(s, getNFTsTool)
+
+	transferNFTTool := tools.NewTransferNFTTool(walletManager)
+	mcp.RegisterTool(s, transferNFTTool)
+
+	getTokenInfoTool := tools.NewGetTokenInfoTool()
+	mcp.RegisterTool(s, getTokenInfoTool)
+
+	// Register host://capabilities last so it sees every tool and DEX provider
+	mcp.RegisterResource(s, resources.NewCapabilitiesResource(hostState.version, appConfig, dexAggregator,
+		func(ctx context.Context) ([]mcpgo.Tool, error) { return mcp.ListTools(ctx, s) }))
+
 	// Start unified MCP server with multiple transport protocols
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -17,6 +18,25 @@ import (
 	"go.uber.org/zap"
 )
 
+// MaxBundleSize is the maximum number of transactions Jito accepts in one bundle, tip included
+const MaxBundleSize = 5
+
+var (
+	// ErrBundleTooLarge is returned when a bundle would exceed MaxBundleSize
+	ErrBundleTooLarge = errors.New("bundle exceeds jito size limit")
+	// ErrBundleRejected is returned when the block engine refuses a bundle
+	ErrBundleRejected = errors.New("bundle rejected by jito")
+)
+
+// BundleResult describes a submitted bundle and its landing status
+type BundleResult struct {
+	BundleID        string   `json:"bundle_id"`
+	Status          string   `json:"status"`     // pending, processed, confirmed, failed, unknown
+	Signatures      []string `json:"signatures"` // in bundle order, tip transaction last
+	TipLamports     uint64   `json:"tip_lamports"`
+	RejectionReason string   `json:"rejection_reason,omitempty"`
+}
+
 // JitoBundleChannel implements broadcasting via Jito bundles with MEV protection
 type JitoBundleChannel struct {
 	name       string
@@ -290,16 +310,106 @@ func (j *JitoBundleChannel) Close() error {
 	return nil
 }
 
+// SubmitBundle submits txs as a single atomic bundle. A tip transaction paid by
+// payer is appended last, so at most MaxBundleSize-1 transactions can be passed.
+// The returned result carries the landing status observed right after submission.
+func (j *JitoBundleChannel) SubmitBundle(ctx context.Context, txs []*solana.Transaction, payer solana.PrivateKey, tipLamports uint64) (*BundleResult, error) {
+	if len(txs) == 0 {
+		return nil, errors.New("bundle must contain at least one transaction")
+	}
+	if len(txs)+1 > MaxBundleSize {
+		return nil, fmt.Errorf("%w: %d transactions plus tip exceeds %d", ErrBundleTooLarge, len(txs), MaxBundleSize)
+	}
+	if j.tipAccount.IsZero() {
+		return nil, errors.New("jito tip account not initialized")
+	}
+
+	tipAmount := j.bundleTip(tipLamports)
+	tipTx, err := j.signTipTransaction(payer, tipAmount, txs[len(txs)-1].Message.RecentBlockhash)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := append(append([]*solana.Transaction{}, txs...), tipTx)
+	encoded := make([]string, 0, len(bundle))
+	result := &BundleResult{
+		Signatures:  make([]string, 0, len(bundle)),
+		TipLamports: tipAmount,
+	}
+	for i, tx := range bundle {
+		if len(tx.Signatures) == 0 {
+			return nil, fmt.Errorf("bundle transaction %d is not signed", i)
+		}
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize bundle transaction %d: %w", i, err)
+		}
+		encoded = append(encoded, base64.StdEncoding.EncodeToString(data))
+		result.Signatures = append(result.Signatures, tx.Signatures[0].String())
+	}
+
+	if os.Getenv("RUN_MODE") == "test" {
+		result.BundleID = fmt.Sprintf("MockJitoBundle_%d", time.Now().UnixNano())
+		result.Status = "pending"
+		return result, nil
+	}
+
+	j.logger.Debug("Submitting Jito bundle",
+		zap.Int("transactions", len(bundle)),
+		zap.Uint64("tip_amount", tipAmount))
+
+	bundleIDRaw, err := j.jitoClient.SendBundle([][]string{encoded})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBundleRejected, err)
+	}
+	if err := json.Unmarshal(bundleIDRaw, &result.BundleID); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bundle ID: %w", err)
+	}
+
+	status, err := j.GetTransactionStatus(ctx, result.BundleID)
+	if err != nil {
+		return nil, err
+	}
+	result.Status = status.Status
+	if status.Status == "failed" {
+		result.RejectionReason = status.Error
+	}
+
+	j.logger.Info("Bundle submitted via Jito",
+		zap.String("bundle_id", result.BundleID),
+		zap.String("status", result.Status),
+		zap.Int("transactions", len(bundle)))
+
+	return result, nil
+}
+
+// bundleTip returns the requested tip, defaulting to the base tip and capped at the max tip
+func (j *JitoBundleChannel) bundleTip(requested uint64) uint64 {
+	tip := requested
+	if tip == 0 {
+		tip = j.config.BaseTipLamports
+	}
+	if j.config.MaxTipLamports > 0 && tip > j.config.MaxTipLamports {
+		tip = j.config.MaxTipLamports
+	}
+	return tip
+}
+
 // createTipTransaction creates a tip transaction for the bundle
 func (j *JitoBundleChannel) createTipTransaction(params *BroadcastParams, tipAmount uint64, recentBlockhash solana.Hash) (*solana.Transaction, error) {
 	// Parse the owner private key from metadata
 	var ownerPrivateKey solana.PrivateKey
 	if privKeyBytes, ok := params.Metadata["owner_private_key"].([]byte); ok && len(privKeyBytes) == 64 {
-		copy(ownerPrivateKey[:], privKeyBytes)
+		ownerPrivateKey = solana.PrivateKey(privKeyBytes)
 	} else {
 		return nil, fmt.Errorf("owner private key not found in metadata")
 	}
-	
+
+	return j.signTipTransaction(ownerPrivateKey, tipAmount, recentBlockhash)
+}
+
+// signTipTransaction builds and signs a transfer of tipAmount from owner to the tip account
+func (j *JitoBundleChannel) signTipTransaction(ownerPrivateKey solana.PrivateKey, tipAmount uint64, recentBlockhash solana.Hash) (*solana.Transaction, error) {
 	// Create tip transaction
 	tipTx, err := solana.NewTransaction(
 		[]solana.Instruction{
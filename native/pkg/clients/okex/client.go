@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
 )
 
 type Client struct {
@@ -30,9 +32,7 @@ const defaultTimeout = 30 * time.Second
 
 func NewClient(baseURL, projectID string, apiKey, apiSecret, passphrase string, opts ...ClientOption) IOKEXClient {
 	c := &Client{
-		client: &http.Client{
-			Timeout: defaultTimeout,
-		},
+		client:     httpclient.New("okex", httpclient.WithTimeout(defaultTimeout)),
 		apiBaseURL: baseURL,
 		projectID:  projectID,
 		apiKey:     apiKey,
@@ -10,6 +10,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
 	"github.com/algonius/algonius-wallet/native/pkg/utils/limiter"
 )
 
@@ -60,7 +61,7 @@ func Init(cfg *config.OKExConfig, logger *zap.Logger) IOKEXClient {
 
 	options := []ClientOption{WithTimeout(timeout), WithLogger(logger)}
 	if transport != nil {
-		options = append(options, WithTransport(transport))
+		options = append(options, WithTransport(httpclient.NewTransport("okex", httpclient.WithTransport(transport))))
 	}
 
 	client := NewClient(baseURL, projectID, apiKey, apiSecret, passphrase, options...)
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	yaml "gopkg.in/yaml.v3"
 	"go.uber.org/zap"
@@ -18,6 +21,12 @@ type Config struct {
 	DEX      DEXConfig      `yaml:"dex"`
 	Security SecurityConfig `yaml:"security"`
 	Logging  LoggingConfig  `yaml:"logging"`
+	// NativeMessaging configures the stdin/stdout channel to the browser extension
+	NativeMessaging NativeMessagingConfig `yaml:"native_messaging"`
+	// MCP configures the tool server agents call
+	MCP MCPConfig `yaml:"mcp"`
+	// Callbacks configures delivery of per-transaction outcome callbacks
+	Callbacks CallbacksConfig `yaml:"callbacks"`
 }
 
 // WalletConfig contains wallet-specific settings
@@ -25,6 +34,294 @@ type WalletConfig struct {
 	DataDir     string `yaml:"data_dir"`
 	PrivateKey  string `yaml:"private_key,omitempty"`  // Base58 encoded private key
 	NetworkMode string `yaml:"network_mode"`           // mainnet, testnet, devnet
+	Storage     StorageConfig `yaml:"storage"`
+	AccountDiscovery AccountDiscoveryConfig `yaml:"account_discovery"`
+	BalanceSnapshots BalanceSnapshotConfig  `yaml:"balance_snapshots"`
+	Metadata         MetadataConfig         `yaml:"metadata"`
+	ReplayProtection ReplayProtectionConfig `yaml:"replay_protection"`
+	// MaxPendingTransactions caps the transactions dApps can queue for approval
+	MaxPendingTransactions int `yaml:"max_pending_transactions"`
+	TokenLists             TokenListsConfig `yaml:"token_lists"`
+	AddressBook            AddressBookConfig `yaml:"address_book"`
+	// DefaultChain is used wherever a request does not name a chain; empty means ethereum
+	DefaultChain string `yaml:"default_chain"`
+	CacheWarmup  CacheWarmupConfig `yaml:"cache_warmup"`
+	PriceHistory PriceHistoryConfig `yaml:"price_history"`
+	FilePermissions FilePermissionsConfig `yaml:"file_permissions"`
+}
+
+// AddressBookConfig sets what sending to an address book entry does, by the
+// entry's category
+type AddressBookConfig struct {
+	BlockedCategories []string `yaml:"blocked_categories"` // sends are refused
+	WarnCategories    []string `yaml:"warn_categories"`    // sends need explicit confirmation
+}
+
+// Validate checks that every listed category is named
+func (c *AddressBookConfig) Validate() error {
+	for i, category := range c.BlockedCategories {
+		if strings.TrimSpace(category) == "" {
+			return fmt.Errorf("blocked_categories[%d] must not be empty", i)
+		}
+	}
+	for i, category := range c.WarnCategories {
+		if strings.TrimSpace(category) == "" {
+			return fmt.Errorf("warn_categories[%d] must not be empty", i)
+		}
+	}
+	return nil
+}
+
+// TokenListsConfig imports token lists in the Uniswap token-list format. Their
+// entries take precedence over the bundled token list.
+type TokenListsConfig struct {
+	Sources         []string      `yaml:"sources"`          // http(s) URLs or local file paths, earlier sources win conflicts
+	RefreshInterval time.Duration `yaml:"refresh_interval"` // how often sources are re-read; 0 loads them once
+}
+
+// Validate checks that every token list source is a URL or a file path
+func (c *TokenListsConfig) Validate() error {
+	for i, source := range c.Sources {
+		source = strings.TrimSpace(source)
+		if source == "" {
+			return fmt.Errorf("sources[%d] must not be empty", i)
+		}
+		if strings.Contains(source, "://") && !strings.HasPrefix(source, "http://") &&
+			!strings.HasPrefix(source, "https://") && !strings.HasPrefix(source, "file://") {
+			return fmt.Errorf("sources[%d] must be an http(s) URL or a file path, got %q", i, source)
+		}
+	}
+	if c.RefreshInterval < 0 {
+		return fmt.Errorf("refresh_interval must not be negative, got %s", c.RefreshInterval)
+	}
+	return nil
+}
+
+// ReplayProtectionConfig controls detection of accidentally repeated sends: an
+// identical send within Window returns the first send's result instead of
+// broadcasting again
+type ReplayProtectionConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Window  time.Duration `yaml:"window"`
+}
+
+// Validate checks that the duplicate detection window is usable
+func (c *ReplayProtectionConfig) Validate() error {
+	if c.Window < 0 {
+		return fmt.Errorf("window must not be negative, got %s", c.Window)
+	}
+	if c.Enabled && c.Window == 0 {
+		return fmt.Errorf("window is required when enabled")
+	}
+	return nil
+}
+
+// MetadataConfig controls how token and NFT metadata JSON is fetched and cached
+type MetadataConfig struct {
+	IPFSGateway string        `yaml:"ipfs_gateway"` // HTTP gateway ipfs:// URIs are rewritten to
+	CacheTTL    time.Duration `yaml:"cache_ttl"`    // how long fetched metadata is reused
+	Timeout     time.Duration `yaml:"timeout"`      // per-request timeout
+}
+
+// Validate checks that the metadata gateway and timings are usable
+func (c *MetadataConfig) Validate() error {
+	if c.IPFSGateway != "" && !strings.HasPrefix(c.IPFSGateway, "http://") && !strings.HasPrefix(c.IPFSGateway, "https://") {
+		return fmt.Errorf("ipfs_gateway must be an http(s) URL, got %q", c.IPFSGateway)
+	}
+	if c.CacheTTL < 0 || c.Timeout < 0 {
+		return fmt.Errorf("cache_ttl and timeout must not be negative")
+	}
+	return nil
+}
+
+// Network modes accepted by WalletConfig.NetworkMode. Each mode keeps its
+// persisted state in its own directory under the data directory.
+const (
+	NetworkModeMainnet = "mainnet"
+	NetworkModeTestnet = "testnet"
+	NetworkModeDevnet  = "devnet"
+)
+
+// NormalizeNetworkMode lower-cases mode and defaults an empty mode to mainnet
+func NormalizeNetworkMode(mode string) (string, error) {
+	switch normalized := strings.ToLower(strings.TrimSpace(mode)); normalized {
+	case "":
+		return NetworkModeMainnet, nil
+	case NetworkModeMainnet, NetworkModeTestnet, NetworkModeDevnet:
+		return normalized, nil
+	default:
+		return "", fmt.Errorf("unsupported network mode %q (supported: mainnet, testnet, devnet)", mode)
+	}
+}
+
+// chainAliases maps every accepted spelling of a chain name onto its
+// canonical name. Keys are lower-case with words separated by one space.
+var chainAliases = map[string]string{
+	"eth":                 "ethereum",
+	"ethereum":            "ethereum",
+	"bsc":                 "bsc",
+	"binance":             "bsc",
+	"binance smart chain": "bsc",
+	"sol":                 "solana",
+	"solana":              "solana",
+}
+
+// NormalizeChain resolves a chain alias such as "ETH", "Binance Smart Chain"
+// or "sol" to its canonical name: ethereum, bsc or solana. Case, surrounding
+// space and "-" or "_" between words are ignored. Unknown names are returned
+// lower-cased and trimmed.
+func NormalizeChain(chain string) string {
+	key := strings.Join(strings.FieldsFunc(strings.ToLower(chain), func(r rune) bool {
+		return r == '-' || r == '_' || unicode.IsSpace(r)
+	}), " ")
+	if canonical, ok := chainAliases[key]; ok {
+		return canonical
+	}
+	return strings.ToLower(strings.TrimSpace(chain))
+}
+
+// IsSupportedChain reports whether chain is one of the aliases NormalizeChain resolves
+func IsSupportedChain(chain string) bool {
+	_, ok := chainAliases[NormalizeChain(chain)]
+	return ok
+}
+
+// NormalizeDefaultChain resolves chain aliases to ethereum, bsc or solana and
+// defaults an empty chain to ethereum
+func NormalizeDefaultChain(chain string) (string, error) {
+	if strings.TrimSpace(chain) == "" {
+		return "ethereum", nil
+	}
+	if !IsSupportedChain(chain) {
+		return "", fmt.Errorf("unsupported chain %q (supported: ethereum, bsc, solana)", chain)
+	}
+	return NormalizeChain(chain), nil
+}
+
+// BalanceSnapshotConfig controls the periodic balance snapshots behind get_balance_history
+type BalanceSnapshotConfig struct {
+	Enabled   bool                `yaml:"enabled"`
+	Interval  time.Duration       `yaml:"interval"`         // time between snapshots
+	Retention time.Duration       `yaml:"retention"`        // snapshots older than this are pruned
+	Tokens    map[string][]string `yaml:"tokens,omitempty"` // tokens recorded per chain in addition to the native token
+}
+
+// MinBalanceSnapshotInterval is the shortest allowed balance snapshot interval
+const MinBalanceSnapshotInterval = time.Minute
+
+// Validate checks that the snapshot schedule is usable
+func (c *BalanceSnapshotConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Interval < MinBalanceSnapshotInterval {
+		return fmt.Errorf("interval must be at least %s, got %s", MinBalanceSnapshotInterval, c.Interval)
+	}
+	if c.Retention < c.Interval {
+		return fmt.Errorf("retention (%s) must not be shorter than interval (%s)", c.Retention, c.Interval)
+	}
+	return nil
+}
+
+// CacheWarmupConfig controls the background fetch of balances and prices run
+// after the wallet is unlocked, so the first agent query finds warm RPC
+// connections and cached prices
+type CacheWarmupConfig struct {
+	Enabled         bool                `yaml:"enabled"`
+	Tokens          map[string][]string `yaml:"tokens,omitempty"` // tokens fetched per chain in addition to the native token
+	RequestInterval time.Duration       `yaml:"request_interval"` // pause between calls, to stay within provider rate limits
+	Timeout         time.Duration       `yaml:"timeout"`          // the warm-up is abandoned after this long
+}
+
+// Validate checks that the warm-up timings are usable
+func (c *CacheWarmupConfig) Validate() error {
+	if c.RequestInterval < 0 {
+		return fmt.Errorf("request_interval must not be negative, got %s", c.RequestInterval)
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative, got %s", c.Timeout)
+	}
+	return nil
+}
+
+// Sources of historical prices accepted by PriceHistoryConfig.Source
+const (
+	PriceHistorySourceNone      = "none"
+	PriceHistorySourceCoinGecko = "coingecko"
+)
+
+// PriceHistoryConfig selects where past token prices come from. They value
+// transaction history at the time of each transaction, for profit and loss.
+type PriceHistoryConfig struct {
+	Source    string        `yaml:"source"`            // coingecko, or none to leave history unpriced
+	BaseURL   string        `yaml:"base_url"`          // API root of the source
+	APIKey    string        `yaml:"api_key,omitempty"` // sent as x-cg-demo-api-key
+	Timeout   time.Duration `yaml:"timeout"`           // per-request timeout
+	CacheSize int           `yaml:"cache_size"`        // prices kept in memory; past prices never change
+}
+
+// Validate checks that the price history source is known and reachable
+func (c *PriceHistoryConfig) Validate() error {
+	switch c.Source {
+	case "", PriceHistorySourceNone:
+		return nil
+	case PriceHistorySourceCoinGecko:
+	default:
+		return fmt.Errorf("source must be %q or %q, got %q", PriceHistorySourceCoinGecko, PriceHistorySourceNone, c.Source)
+	}
+	if !strings.HasPrefix(c.BaseURL, "http://") && !strings.HasPrefix(c.BaseURL, "https://") {
+		return fmt.Errorf("base_url must be an http(s) URL, got %q", c.BaseURL)
+	}
+	if c.Timeout < 0 || c.CacheSize < 0 {
+		return fmt.Errorf("timeout and cache_size must not be negative")
+	}
+	return nil
+}
+
+// AccountDiscoveryConfig bounds the scan for used accounts when a mnemonic is imported
+type AccountDiscoveryConfig struct {
+	MaxAccounts int `yaml:"max_accounts"` // accounts derived at most per chain
+	GapLimit    int `yaml:"gap_limit"`    // consecutive unused accounts that end the scan
+}
+
+// Validate checks that the discovery bounds are usable
+func (c *AccountDiscoveryConfig) Validate() error {
+	if c.MaxAccounts < 0 || c.GapLimit < 0 {
+		return fmt.Errorf("max_accounts and gap_limit must not be negative")
+	}
+	if c.GapLimit > c.MaxAccounts {
+		return fmt.Errorf("gap_limit (%d) must not exceed max_accounts (%d)", c.GapLimit, c.MaxAccounts)
+	}
+	return nil
+}
+
+// Startup actions accepted by FilePermissionsConfig.OnInsecure
+const (
+	FilePermissionsWarn   = "warn"
+	FilePermissionsHarden = "harden"
+	FilePermissionsRefuse = "refuse"
+)
+
+// FilePermissionsConfig sets what the host does at startup when the wallet
+// directory or files are readable or writable by group or others
+type FilePermissionsConfig struct {
+	OnInsecure string `yaml:"on_insecure"` // warn (default), harden, or refuse to start
+}
+
+// Validate checks that OnInsecure is a known action
+func (c FilePermissionsConfig) Validate() error {
+	switch strings.ToLower(strings.TrimSpace(c.OnInsecure)) {
+	case "", FilePermissionsWarn, FilePermissionsHarden, FilePermissionsRefuse:
+		return nil
+	}
+	return fmt.Errorf("unsupported on_insecure %q (supported: %s, %s, %s)",
+		c.OnInsecure, FilePermissionsWarn, FilePermissionsHarden, FilePermissionsRefuse)
+}
+
+// StorageConfig selects the backend used to persist wallet state
+type StorageConfig struct {
+	Backend string            `yaml:"backend"`           // file (default), memory, or a registered custom backend
+	Options map[string]string `yaml:"options,omitempty"` // backend-specific settings
 }
 
 // ChainsConfig contains blockchain network configurations
@@ -32,6 +329,112 @@ type ChainsConfig struct {
 	Solana   SolanaChainConfig   `yaml:"solana"`
 	Ethereum EthereumChainConfig `yaml:"ethereum"`
 	BSC      BSCChainConfig      `yaml:"bsc"`
+	Balance  BalanceConfig       `yaml:"balance"`
+	Monitor  ChainMonitorConfig  `yaml:"monitor"`
+}
+
+// Enabled reports whether the chain named ethereum, bsc or solana, or an
+// alias of it, is enabled
+func (c ChainsConfig) Enabled(chainName string) bool {
+	switch NormalizeChain(chainName) {
+	case "ethereum":
+		return c.Ethereum.Enabled
+	case "bsc":
+		return c.BSC.Enabled
+	case "solana":
+		return c.Solana.Enabled
+	default:
+		return false
+	}
+}
+
+// Balance source orderings accepted by BalanceConfig.Sources
+const (
+	BalanceSourcesRPCOnly    = "rpc-only"
+	BalanceSourcesRPCThenDEX = "rpc-then-dex"
+	BalanceSourcesDEXOnly    = "dex-only"
+)
+
+// Individual balance sources, in the order returned by BalanceConfig.SourceOrder
+const (
+	BalanceSourceRPC = "rpc"
+	BalanceSourceDEX = "dex"
+)
+
+// BalanceConfig controls where chain balances are read from
+type BalanceConfig struct {
+	Sources           string `yaml:"sources"`             // rpc-only, rpc-then-dex, dex-only
+	FailOnUnavailable bool   `yaml:"fail_on_unavailable"` // return an error instead of "0" when every source fails
+}
+
+// SourceOrder returns the balance sources to try, in order. An empty Sources
+// value keeps the historical rpc-then-dex behavior.
+func (c BalanceConfig) SourceOrder() []string {
+	switch c.Sources {
+	case BalanceSourcesRPCOnly:
+		return []string{BalanceSourceRPC}
+	case BalanceSourcesDEXOnly:
+		return []string{BalanceSourceDEX}
+	default:
+		return []string{BalanceSourceRPC, BalanceSourceDEX}
+	}
+}
+
+// ChainMonitorConfig controls the background watch of the enabled chains for
+// reorgs and stalls, reported as chain_reorg_detected and chain_stalled events
+type ChainMonitorConfig struct {
+	Enabled      bool          `yaml:"enabled"`
+	PollInterval time.Duration `yaml:"poll_interval"` // how often the head of each chain is read
+	// HistoryDepth is how many recent EVM block hashes are kept; a reorg
+	// replacing all of them is reported as at least this deep
+	HistoryDepth  int `yaml:"history_depth"`
+	MinReorgDepth int `yaml:"min_reorg_depth"` // shallower reorgs are only logged
+	// StallAfter is how long each chain may go without a new block or slot
+	// before it is reported as stalled; chains left out are not checked
+	StallAfter map[string]time.Duration `yaml:"stall_after"`
+}
+
+// Validate checks the monitor's timings and that stall windows name known chains
+func (c *ChainMonitorConfig) Validate() error {
+	if c.PollInterval < 0 || (c.Enabled && c.PollInterval == 0) {
+		return fmt.Errorf("poll_interval must be positive, got %s", c.PollInterval)
+	}
+	if c.Enabled && c.HistoryDepth < 2 {
+		return fmt.Errorf("history_depth must be at least 2, got %d", c.HistoryDepth)
+	}
+	if c.MinReorgDepth < 0 || (c.HistoryDepth > 0 && c.MinReorgDepth > c.HistoryDepth) {
+		return fmt.Errorf("min_reorg_depth must be between 0 and history_depth, got %d", c.MinReorgDepth)
+	}
+	for chain, window := range c.StallAfter {
+		if !IsSupportedChain(chain) {
+			return fmt.Errorf("stall_after: unknown chain %q", chain)
+		}
+		if window < 0 {
+			return fmt.Errorf("stall_after.%s must not be negative, got %s", chain, window)
+		}
+	}
+	return nil
+}
+
+// StallWindow returns how long chainName may go without a new block before
+// it counts as stalled, or 0 when it is not checked
+func (c ChainMonitorConfig) StallWindow(chainName string) time.Duration {
+	for chain, window := range c.StallAfter {
+		if NormalizeChain(chain) == NormalizeChain(chainName) {
+			return window
+		}
+	}
+	return 0
+}
+
+// Validate checks that the balance source ordering is recognized
+func (c *BalanceConfig) Validate() error {
+	switch c.Sources {
+	case "", BalanceSourcesRPCOnly, BalanceSourcesRPCThenDEX, BalanceSourcesDEXOnly:
+		return nil
+	}
+	return fmt.Errorf("unsupported sources %q (supported: %s, %s, %s)",
+		c.Sources, BalanceSourcesRPCOnly, BalanceSourcesRPCThenDEX, BalanceSourcesDEXOnly)
 }
 
 // SolanaChainConfig contains Solana-specific configuration
@@ -45,22 +448,185 @@ type SolanaChainConfig struct {
 	Confirmation  ConfirmationConfig      `yaml:"confirmation"`
 	Jito          JitoConfig              `yaml:"jito"`
 	Broadcast     BroadcastConfig         `yaml:"broadcast"`
+	DurableNonce  DurableNonceConfig      `yaml:"durable_nonce"`
+	LargeTxThreshold LargeTxThresholdConfig `yaml:"large_tx_threshold"`
+	MinTransfer   MinTransferConfig  `yaml:"min_transfer"`
+	// Safety margins applied on top of the raw compute unit and priority fee
+	// estimates, between 1 and MaxEstimateMultiplier; 0 means 1
+	ComputeUnitMultiplier float64 `yaml:"compute_unit_multiplier"`
+	PriorityFeeMultiplier float64 `yaml:"priority_fee_multiplier"`
 }
 
 // EthereumChainConfig contains Ethereum-specific configuration
 type EthereumChainConfig struct {
-	Enabled      bool     `yaml:"enabled"`
-	RPCEndpoints []string `yaml:"rpc_endpoints"`
-	ChainID      int      `yaml:"chain_id"`
-	GasStrategy  string   `yaml:"gas_strategy"`
+	Enabled       bool               `yaml:"enabled"`
+	RPCEndpoints  []string           `yaml:"rpc_endpoints"`
+	ChainID       int                `yaml:"chain_id"`
+	GasStrategy   string             `yaml:"gas_strategy"`   // slow, standard, fast, dynamic
+	MaxFee        float64            `yaml:"max_fee"`        // max fee ceiling in gwei, 0 disables the ceiling
+	ReserveNative float64            `yaml:"reserve_native"` // native token left behind by sends for future gas
+	Confirmation  ConfirmationConfig `yaml:"confirmation"`
+	LargeTxThreshold LargeTxThresholdConfig `yaml:"large_tx_threshold"`
+	MinTransfer   MinTransferConfig  `yaml:"min_transfer"`
+	// FeeOnTransferTokens flags token contracts known to take a fee on every
+	// transfer, with the fee in percent; other tokens are checked by simulation
+	FeeOnTransferTokens map[string]float64 `yaml:"fee_on_transfer_tokens,omitempty"`
+	// Safety margins applied on top of the raw gas limit and fee estimates,
+	// between 1 and MaxEstimateMultiplier; 0 means 1
+	GasLimitMultiplier float64 `yaml:"gas_limit_multiplier"`
+	FeeMultiplier      float64 `yaml:"fee_multiplier"`
+	// RPCRetry controls how calls retry and fail over across RPCEndpoints
+	RPCRetry RPCRetryConfig `yaml:"rpc_retry"`
 }
 
 // BSCChainConfig contains BSC-specific configuration
 type BSCChainConfig struct {
-	Enabled      bool     `yaml:"enabled"`
-	RPCEndpoints []string `yaml:"rpc_endpoints"`
-	ChainID      int      `yaml:"chain_id"`
-	GasStrategy  string   `yaml:"gas_strategy"`
+	Enabled       bool               `yaml:"enabled"`
+	RPCEndpoints  []string           `yaml:"rpc_endpoints"`
+	ChainID       int                `yaml:"chain_id"`
+	GasStrategy   string             `yaml:"gas_strategy"`   // slow, standard, fast, dynamic
+	MaxFee        float64            `yaml:"max_fee"`        // max fee ceiling in gwei, 0 disables the ceiling
+	ReserveNative float64            `yaml:"reserve_native"` // native token left behind by sends for future gas
+	Confirmation  ConfirmationConfig `yaml:"confirmation"`
+	LargeTxThreshold LargeTxThresholdConfig `yaml:"large_tx_threshold"`
+	MinTransfer   MinTransferConfig  `yaml:"min_transfer"`
+	// FeeOnTransferTokens flags token contracts known to take a fee on every
+	// transfer, with the fee in percent; other tokens are checked by simulation
+	FeeOnTransferTokens map[string]float64 `yaml:"fee_on_transfer_tokens,omitempty"`
+	// Safety margins applied on top of the raw gas limit and fee estimates,
+	// between 1 and MaxEstimateMultiplier; 0 means 1
+	GasLimitMultiplier float64 `yaml:"gas_limit_multiplier"`
+	FeeMultiplier      float64 `yaml:"fee_multiplier"`
+}
+
+// LargeTxThresholdConfig marks sends and approvals that need an explicit
+// confirm_large=true. A transaction is large when it meets either limit; a
+// zero limit is disabled.
+type LargeTxThresholdConfig struct {
+	Native float64 `yaml:"native"` // amount of the chain's native token
+	USD    float64 `yaml:"usd"`    // value in USD, for tokens that can be priced
+}
+
+// Enabled reports whether any limit is set
+func (c LargeTxThresholdConfig) Enabled() bool {
+	return c.Native > 0 || c.USD > 0
+}
+
+// LargeTxThreshold returns the large transaction threshold of a chain name or alias
+func (c ChainsConfig) LargeTxThreshold(chainName string) LargeTxThresholdConfig {
+	switch NormalizeChain(chainName) {
+	case "ethereum":
+		return c.Ethereum.LargeTxThreshold
+	case "bsc":
+		return c.BSC.LargeTxThreshold
+	case "solana":
+		return c.Solana.LargeTxThreshold
+	default:
+		return LargeTxThresholdConfig{}
+	}
+}
+
+// MinTransferConfig rejects dust sends, whose fee costs more than they move,
+// unless the caller passes allow_dust=true. A zero or missing minimum falls
+// back to requiring the amount to exceed the estimated fee.
+type MinTransferConfig struct {
+	Native float64            `yaml:"native"` // amount of the chain's native token
+	Tokens map[string]float64 `yaml:"tokens"` // keyed by token symbol or contract address
+}
+
+// TokenMinimum returns the minimum configured for token, matching symbols and
+// addresses case-insensitively
+func (c MinTransferConfig) TokenMinimum(token string) (float64, bool) {
+	token = strings.TrimSpace(token)
+	for key, minimum := range c.Tokens {
+		if minimum > 0 && strings.EqualFold(strings.TrimSpace(key), token) {
+			return minimum, true
+		}
+	}
+	return 0, false
+}
+
+// MinTransfer returns the minimum transfer amounts of a chain name or alias
+func (c ChainsConfig) MinTransfer(chainName string) MinTransferConfig {
+	switch NormalizeChain(chainName) {
+	case "ethereum":
+		return c.Ethereum.MinTransfer
+	case "bsc":
+		return c.BSC.MinTransfer
+	case "solana":
+		return c.Solana.MinTransfer
+	default:
+		return MinTransferConfig{}
+	}
+}
+
+// MaxEstimateMultiplier bounds the estimate multipliers, so a typo cannot
+// multiply fees by orders of magnitude
+const MaxEstimateMultiplier = 5.0
+
+// EstimateMultipliers scale raw estimates to leave a safety margin. Limit
+// scales the gas limit on EVM chains and the compute units on Solana; Fee
+// scales the gas price or max fee on EVM chains and the priority fee on Solana.
+type EstimateMultipliers struct {
+	Limit float64
+	Fee   float64
+}
+
+// CheckEstimateMultiplier returns an error unless multiplier is between 1 and
+// MaxEstimateMultiplier
+func CheckEstimateMultiplier(multiplier float64) error {
+	if multiplier < 1 || multiplier > MaxEstimateMultiplier {
+		return fmt.Errorf("must be between 1 and %g, got %g", MaxEstimateMultiplier, multiplier)
+	}
+	return nil
+}
+
+// EstimateMultipliers returns the estimate multipliers of a chain name or
+// alias, with unset multipliers as 1
+func (c ChainsConfig) EstimateMultipliers(chainName string) EstimateMultipliers {
+	var multipliers EstimateMultipliers
+	switch NormalizeChain(chainName) {
+	case "ethereum":
+		multipliers = EstimateMultipliers{Limit: c.Ethereum.GasLimitMultiplier, Fee: c.Ethereum.FeeMultiplier}
+	case "bsc":
+		multipliers = EstimateMultipliers{Limit: c.BSC.GasLimitMultiplier, Fee: c.BSC.FeeMultiplier}
+	case "solana":
+		multipliers = EstimateMultipliers{Limit: c.Solana.ComputeUnitMultiplier, Fee: c.Solana.PriorityFeeMultiplier}
+	}
+	if multipliers.Limit == 0 {
+		multipliers.Limit = 1
+	}
+	if multipliers.Fee == 0 {
+		multipliers.Fee = 1
+	}
+	return multipliers
+}
+
+// MaxFee returns the max fee ceiling in gwei of an EVM chain name or alias,
+// 0 when there is none
+func (c ChainsConfig) MaxFee(chainName string) float64 {
+	switch NormalizeChain(chainName) {
+	case "ethereum":
+		return c.Ethereum.MaxFee
+	case "bsc":
+		return c.BSC.MaxFee
+	default:
+		return 0
+	}
+}
+
+// Confirmation returns the confirmation settings of a chain name or alias
+func (c ChainsConfig) Confirmation(chainName string) ConfirmationConfig {
+	switch NormalizeChain(chainName) {
+	case "ethereum":
+		return c.Ethereum.Confirmation
+	case "bsc":
+		return c.BSC.Confirmation
+	case "solana":
+		return c.Solana.Confirmation
+	default:
+		return ConfirmationConfig{}
+	}
 }
 
 // RetryConfig defines retry behavior for failed transactions
@@ -79,6 +645,17 @@ type ConfirmationConfig struct {
 	RequiredConfirmations int           `yaml:"required_confirmations"`
 }
 
+// MinConfirmationPollInterval is the shortest allowed confirmation poll interval
+const MinConfirmationPollInterval = time.Second
+
+// Validate checks that the confirmation settings are usable
+func (c *ConfirmationConfig) Validate() error {
+	if c.PollInterval < MinConfirmationPollInterval {
+		return fmt.Errorf("poll_interval must be at least %s, got %s", MinConfirmationPollInterval, c.PollInterval)
+	}
+	return nil
+}
+
 // JitoConfig defines MEV protection settings
 type JitoConfig struct {
 	Enabled         bool   `yaml:"enabled"`
@@ -90,6 +667,13 @@ type JitoConfig struct {
 	BundleEndpoint  string `yaml:"bundle_endpoint"`
 }
 
+// DurableNonceConfig makes Solana transactions use a nonce account's stored
+// blockhash instead of a recent one, so signed transactions do not expire
+type DurableNonceConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	NonceAccount string `yaml:"nonce_account"` // base58 address; its authority must be the sending wallet
+}
+
 // BroadcastConfig defines transaction broadcast settings
 type BroadcastConfig struct {
 	Channel  string            `yaml:"channel"`  // solana-rpc, okex, jito, jito-bundle, paper
@@ -110,6 +694,64 @@ type DEXConfig struct {
 	Jupiter   JupiterConfig   `yaml:"jupiter"`
 	PumpFun   PumpFunConfig   `yaml:"pumpfun"`
 	Composite CompositeConfig `yaml:"composite"`
+
+	// PartialFill is how swaps the liquidity can only fill in part are
+	// handled: "revert" makes them all-or-nothing, "return_leftover" swaps
+	// what can be filled and leaves the rest of the input with the sender
+	PartialFill string `yaml:"partial_fill"`
+
+	// AutoWrap lets swaps selling the wrapped native token (WETH, WBNB, WSOL)
+	// wrap the native coin the wallet is short of first; swap_tokens can
+	// override it per call
+	AutoWrap bool `yaml:"auto_wrap"`
+
+	PairPolicy PairPolicyConfig `yaml:"pair_policy"`
+}
+
+// PairPolicyConfig restricts the tokens swaps and quotes may involve. Tokens
+// are contract or mint addresses or symbols, or "native" for the chain's coin,
+// and apply on every chain unless prefixed with one, as in "solana:<mint>".
+type PairPolicyConfig struct {
+	AllowTokens []string `yaml:"allow_tokens"` // when set, both tokens of a swap must be listed
+	DenyTokens  []string `yaml:"deny_tokens"`  // never swapped, even when allowed
+
+	// BlocklistFiles hold denied tokens one per line, with # comments. They
+	// are re-read when they change, so tokens can be blocked without a restart.
+	BlocklistFiles []string `yaml:"blocklist_files"`
+}
+
+// SplitPairPolicyToken splits a pair policy entry into its canonical chain,
+// empty when the entry applies on every chain, and token
+func SplitPairPolicyToken(entry string) (chain, token string) {
+	entry = strings.TrimSpace(entry)
+	if prefix, rest, ok := strings.Cut(entry, ":"); ok && IsSupportedChain(prefix) {
+		return NormalizeChain(prefix), strings.TrimSpace(rest)
+	}
+	return "", entry
+}
+
+// Validate checks that every entry names a token and that chain prefixes are
+// known chains
+func (c *PairPolicyConfig) Validate() error {
+	for _, list := range []struct {
+		name    string
+		entries []string
+	}{{"allow_tokens", c.AllowTokens}, {"deny_tokens", c.DenyTokens}} {
+		for i, entry := range list.entries {
+			if prefix, _, ok := strings.Cut(entry, ":"); ok && !IsSupportedChain(prefix) {
+				return fmt.Errorf("%s[%d]: unknown chain %q", list.name, i, prefix)
+			}
+			if _, token := SplitPairPolicyToken(entry); token == "" {
+				return fmt.Errorf("%s[%d] must name a token", list.name, i)
+			}
+		}
+	}
+	for i, path := range c.BlocklistFiles {
+		if strings.TrimSpace(path) == "" {
+			return fmt.Errorf("blocklist_files[%d] must not be empty", i)
+		}
+	}
+	return nil
 }
 
 // RateLimitConfig contains rate limiting configuration
@@ -129,6 +771,54 @@ type OKExConfig struct {
 	BaseURL          string           `yaml:"base_url"`
 	Timeout          int              `yaml:"timeout"`
 	RateLimit        *RateLimitConfig `yaml:"rate_limit,omitempty"`
+	// Credentials are additional API key sets the provider rotates among
+	Credentials []OKExCredentials `yaml:"credentials,omitempty"`
+	// Rotation picks the next credential set: round_robin (default) or least_recently_used
+	Rotation string `yaml:"rotation,omitempty"`
+}
+
+// OKExCredentials is one set of OKEx API credentials
+type OKExCredentials struct {
+	APIKey     string `yaml:"api_key"`
+	SecretKey  string `yaml:"secret_key"`
+	PassPhrase string `yaml:"passphrase"`
+	ProjectID  string `yaml:"project_id,omitempty"`
+}
+
+// Credential rotation strategies accepted by OKExConfig.Rotation
+const (
+	CredentialRotationRoundRobin        = "round_robin"
+	CredentialRotationLeastRecentlyUsed = "least_recently_used"
+)
+
+// CredentialSets returns every configured credential set, the top-level
+// api_key/secret_key/passphrase first when set
+func (c *OKExConfig) CredentialSets() []OKExCredentials {
+	sets := make([]OKExCredentials, 0, len(c.Credentials)+1)
+	if c.APIKey != "" {
+		sets = append(sets, OKExCredentials{APIKey: c.APIKey, SecretKey: c.SecretKey, PassPhrase: c.PassPhrase, ProjectID: c.ProjectID})
+	}
+	return append(sets, c.Credentials...)
+}
+
+// Validate checks the rotation strategy and that every credential set is complete
+func (c *OKExConfig) Validate() error {
+	switch c.Rotation {
+	case "", CredentialRotationRoundRobin, CredentialRotationLeastRecentlyUsed:
+	default:
+		return fmt.Errorf("unsupported rotation %q (supported: %s, %s)", c.Rotation, CredentialRotationRoundRobin, CredentialRotationLeastRecentlyUsed)
+	}
+	seen := make(map[string]bool)
+	for i, creds := range c.CredentialSets() {
+		if creds.APIKey == "" || creds.SecretKey == "" || creds.PassPhrase == "" {
+			return fmt.Errorf("credential set %d: api_key, secret_key and passphrase are required", i)
+		}
+		if seen[creds.APIKey] {
+			return fmt.Errorf("credential set %d: duplicate api_key", i)
+		}
+		seen[creds.APIKey] = true
+	}
+	return nil
 }
 
 // JupiterConfig contains Jupiter aggregator configuration
@@ -152,6 +842,32 @@ type CompositeConfig struct {
 	Enabled   bool     `yaml:"enabled"`
 	Providers []string `yaml:"providers"`
 	Strategy  string   `yaml:"strategy"` // best_price, fastest, balanced
+
+	// ProviderTimeout and Timeout bound how long a best-quote fan-out waits
+	// for one provider and for all of them; late providers are left out
+	ProviderTimeout time.Duration `yaml:"provider_timeout"`
+	Timeout         time.Duration `yaml:"timeout"`
+	MaxConcurrency  int           `yaml:"max_concurrency"` // providers queried at the same time, to share rate limits
+
+	// DecisionHistory is how many best-quote decisions are kept for get_dex_routing
+	DecisionHistory int `yaml:"decision_history"`
+}
+
+// Validate checks that the fan-out bounds are not negative
+func (c *CompositeConfig) Validate() error {
+	if c.ProviderTimeout < 0 {
+		return fmt.Errorf("provider_timeout must not be negative, got %s", c.ProviderTimeout)
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative, got %s", c.Timeout)
+	}
+	if c.MaxConcurrency < 0 {
+		return fmt.Errorf("max_concurrency must not be negative, got %d", c.MaxConcurrency)
+	}
+	if c.DecisionHistory < 0 {
+		return fmt.Errorf("decision_history must not be negative, got %d", c.DecisionHistory)
+	}
+	return nil
 }
 
 // SecurityConfig contains security-related settings
@@ -160,6 +876,205 @@ type SecurityConfig struct {
 	KeyDerivationPath  string `yaml:"key_derivation_path"`
 	SessionTimeout     int    `yaml:"session_timeout"`
 	RequirePassword    bool   `yaml:"require_password"`
+
+	AutoApproval AutoApprovalPolicy `yaml:"auto_approval"`
+
+	// SafeContractRecipients lists EVM contracts, such as multisig wallets, that
+	// tokens can be sent to without confirm_contract_recipient
+	SafeContractRecipients []string `yaml:"safe_contract_recipients"`
+
+	SigningPolicy SigningPolicyConfig `yaml:"signing_policy"`
+
+	// SpendLimits caps outgoing transactions per chain name or alias; chains
+	// left out are not limited
+	SpendLimits map[string]ChainSpendLimits `yaml:"spend_limits"`
+}
+
+// SpendLimit caps single transactions and the total sent over the last 24
+// hours, in whole units of the coin or token. Zero leaves a cap unset.
+type SpendLimit struct {
+	MaxTxValue float64 `yaml:"max_tx_value"`
+	DailyLimit float64 `yaml:"daily_limit"`
+}
+
+// Enabled reports whether either cap is set
+func (l SpendLimit) Enabled() bool {
+	return l.MaxTxValue > 0 || l.DailyLimit > 0
+}
+
+// ChainSpendLimits are the spend limits of one chain. The native coin and
+// each token are capped and tracked separately; unlisted tokens are not capped.
+type ChainSpendLimits struct {
+	Native SpendLimit            `yaml:"native"`
+	Tokens map[string]SpendLimit `yaml:"tokens"` // keyed by token symbol or contract address
+}
+
+// validateSpendLimits checks that spend limits name known chains and are not negative
+func validateSpendLimits(limits map[string]ChainSpendLimits) error {
+	for chain, chainLimits := range limits {
+		if !IsSupportedChain(chain) {
+			return fmt.Errorf("unknown chain %q", chain)
+		}
+		if err := chainLimits.Native.validate(); err != nil {
+			return fmt.Errorf("%s.native: %w", chain, err)
+		}
+		for token, limit := range chainLimits.Tokens {
+			if strings.TrimSpace(token) == "" {
+				return fmt.Errorf("%s.tokens: token must not be empty", chain)
+			}
+			if err := limit.validate(); err != nil {
+				return fmt.Errorf("%s.tokens.%s: %w", chain, token, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks that neither cap is negative
+func (l SpendLimit) validate() error {
+	if l.MaxTxValue < 0 {
+		return fmt.Errorf("max_tx_value must not be negative, got %v", l.MaxTxValue)
+	}
+	if l.DailyLimit < 0 {
+		return fmt.Errorf("daily_limit must not be negative, got %v", l.DailyLimit)
+	}
+	return nil
+}
+
+// Operations a signing policy rule can be restricted to
+var signingPolicyKinds = []string{"send", "message", "swap", "nft_transfer", "permit", "bundle", "stake", "wrap"}
+
+// SigningPolicyConfig is the rule-based policy consulted before anything is
+// signed. The first rule matching a request decides it; requests no rule
+// matches get Default.
+type SigningPolicyConfig struct {
+	Default string              `yaml:"default"` // "allow" or "deny"
+	Rules   []SigningPolicyRule `yaml:"rules"`
+}
+
+// SigningPolicyRule matches a request when every condition it sets holds;
+// empty conditions are not checked
+type SigningPolicyRule struct {
+	Name        string   `yaml:"name"`
+	Action      string   `yaml:"action"`       // "allow" or "deny"
+	Reason      string   `yaml:"reason"`       // reported when the rule denies a request
+	Kinds       []string `yaml:"kinds"`        // send, message, swap, nft_transfer, permit, bundle or stake
+	Chains      []string `yaml:"chains"`       // chain names or aliases
+	To          []string `yaml:"to"`           // recipient, spender or contract addresses
+	Tokens      []string `yaml:"tokens"`       // token addresses or symbols, "native" for the chain's coin
+	AmountAbove float64  `yaml:"amount_above"` // amount the request must exceed; 0 skips the check
+}
+
+// isSigningPolicyKind reports whether kind is one of signingPolicyKinds
+func isSigningPolicyKind(kind string) bool {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	for _, known := range signingPolicyKinds {
+		if kind == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled reports whether the policy can deny anything, so that an
+// allow-everything configuration costs nothing
+func (p SigningPolicyConfig) Enabled() bool {
+	return p.Default == "deny" || len(p.Rules) > 0
+}
+
+// Validate checks the default and that every rule is named, has an action and
+// restricts known kinds
+func (p *SigningPolicyConfig) Validate() error {
+	if p.Default != "" && p.Default != "allow" && p.Default != "deny" {
+		return fmt.Errorf("default must be \"allow\" or \"deny\", got %q", p.Default)
+	}
+	names := make(map[string]bool)
+	for i, rule := range p.Rules {
+		if strings.TrimSpace(rule.Name) == "" {
+			return fmt.Errorf("rules[%d]: name is required", i)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("rules[%d]: duplicate rule name %q", i, rule.Name)
+		}
+		names[rule.Name] = true
+		if rule.Action != "allow" && rule.Action != "deny" {
+			return fmt.Errorf("rule %q: action must be \"allow\" or \"deny\", got %q", rule.Name, rule.Action)
+		}
+		if rule.AmountAbove < 0 {
+			return fmt.Errorf("rule %q: amount_above must not be negative", rule.Name)
+		}
+		for _, kind := range rule.Kinds {
+			if !isSigningPolicyKind(kind) {
+				return fmt.Errorf("rule %q: kind %q must be one of %s", rule.Name, kind, strings.Join(signingPolicyKinds, ", "))
+			}
+		}
+		for _, chain := range rule.Chains {
+			if !IsSupportedChain(chain) {
+				return fmt.Errorf("rule %q: unknown chain %q", rule.Name, chain)
+			}
+		}
+	}
+	return nil
+}
+
+// AutoApprovalPolicy lets dApp transactions matching one of Rules skip the
+// pending queue and be sent without explicit approval. Disabled by default.
+type AutoApprovalPolicy struct {
+	Enabled bool               `yaml:"enabled"`
+	Rules   []AutoApprovalRule `yaml:"rules"`
+}
+
+// AutoApprovalRule matches a transaction when every condition it sets holds;
+// empty conditions are not checked
+type AutoApprovalRule struct {
+	Name     string   `yaml:"name"`
+	To       []string `yaml:"to"`        // allowlisted recipient or contract addresses
+	MaxValue float64  `yaml:"max_value"` // native amount the transaction must stay below; 0 skips the check
+	Origins  []string `yaml:"origins"`   // trusted dApp origins, e.g. https://app.uniswap.org
+	Methods  []string `yaml:"methods"`   // only "transfer": auto-approved transactions are sent without calldata
+}
+
+// Validate checks that every rule is named and restricts something
+func (p *AutoApprovalPolicy) Validate() error {
+	names := make(map[string]bool)
+	for i, rule := range p.Rules {
+		if strings.TrimSpace(rule.Name) == "" {
+			return fmt.Errorf("rules[%d]: name is required", i)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("rules[%d]: duplicate rule name %q", i, rule.Name)
+		}
+		names[rule.Name] = true
+		if rule.MaxValue < 0 {
+			return fmt.Errorf("rule %q: max_value must not be negative", rule.Name)
+		}
+		if len(rule.To) == 0 && rule.MaxValue == 0 && len(rule.Origins) == 0 && len(rule.Methods) == 0 {
+			return fmt.Errorf("rule %q: at least one of to, max_value, origins or methods is required", rule.Name)
+		}
+		for _, method := range rule.Methods {
+			if !strings.EqualFold(strings.TrimSpace(method), "transfer") {
+				return fmt.Errorf("rule %q: method %q must be \"transfer\"; transactions with calldata cannot be auto-approved", rule.Name, method)
+			}
+		}
+	}
+	if p.Enabled && len(p.Rules) == 0 {
+		return fmt.Errorf("at least one rule is required when enabled")
+	}
+	return nil
+}
+
+// isHexAddress reports whether address is a 0x-prefixed 20-byte EVM address
+func isHexAddress(address string) bool {
+	address = strings.ToLower(strings.TrimSpace(address))
+	if len(address) != 42 || !strings.HasPrefix(address, "0x") {
+		return false
+	}
+	for _, c := range address[2:] {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
 }
 
 // LoggingConfig contains logging configuration
@@ -170,6 +1085,135 @@ type LoggingConfig struct {
 	MaxSize    int    `yaml:"max_size"`
 	MaxBackups int    `yaml:"max_backups"`
 	MaxAge     int    `yaml:"max_age"`
+	// DebugPayloads logs native messaging and MCP tool requests and responses
+	// with sensitive fields redacted. LOG_DEBUG_PAYLOADS=true also enables it.
+	DebugPayloads bool `yaml:"debug_payloads"`
+}
+
+// PayloadLoggingEnabled reports whether redacted request/response logging is on
+func (c LoggingConfig) PayloadLoggingEnabled() bool {
+	if enabled, err := strconv.ParseBool(os.Getenv("LOG_DEBUG_PAYLOADS")); err == nil {
+		return enabled
+	}
+	return c.DebugPayloads
+}
+
+// Native messaging size limits in bytes. Chrome itself never sends messages
+// over 64 MiB to a native host.
+const (
+	DefaultNativeMessageSize = 1 << 20
+	MaxNativeMessageSize     = 64 << 20
+)
+
+// NativeMessagingConfig contains settings of the browser extension channel
+type NativeMessagingConfig struct {
+	// MaxMessageSize is the longest message in bytes accepted from the
+	// extension; longer ones are answered with an error without being read into memory
+	MaxMessageSize int `yaml:"max_message_size"`
+}
+
+// Validate checks the message size limit
+func (c NativeMessagingConfig) Validate() error {
+	if c.MaxMessageSize < 0 || c.MaxMessageSize > MaxNativeMessageSize {
+		return fmt.Errorf("max_message_size must be between 1 and %d bytes, got %d", MaxNativeMessageSize, c.MaxMessageSize)
+	}
+	return nil
+}
+
+// DefaultToolTimeout bounds an MCP tool call when no timeout is configured
+const DefaultToolTimeout = 2 * time.Minute
+
+// MCPConfig contains settings of the MCP tool server
+type MCPConfig struct {
+	// ToolTimeout is how long a tool call may run before the agent gets a
+	// timeout error and the call's context is cancelled
+	ToolTimeout time.Duration `yaml:"tool_timeout"`
+	// ToolTimeouts overrides ToolTimeout for individual tools by name
+	ToolTimeouts map[string]time.Duration `yaml:"tool_timeouts,omitempty"`
+}
+
+// TimeoutFor returns the execution timeout of the named tool
+func (c MCPConfig) TimeoutFor(tool string) time.Duration {
+	if timeout, ok := c.ToolTimeouts[tool]; ok && timeout > 0 {
+		return timeout
+	}
+	if c.ToolTimeout > 0 {
+		return c.ToolTimeout
+	}
+	return DefaultToolTimeout
+}
+
+// Validate rejects timeouts that would make tool calls fail immediately
+func (c MCPConfig) Validate() error {
+	if c.ToolTimeout < 0 {
+		return fmt.Errorf("tool_timeout must not be negative, got %s", c.ToolTimeout)
+	}
+	for tool, timeout := range c.ToolTimeouts {
+		if timeout <= 0 {
+			return fmt.Errorf("tool_timeouts.%s must be positive, got %s", tool, timeout)
+		}
+	}
+	return nil
+}
+
+// Callback delivery defaults
+const (
+	DefaultCallbackMaxAttempts = 5
+	DefaultCallbackRetryDelay  = 2 * time.Second
+	DefaultCallbackTimeout     = 10 * time.Second
+)
+
+// CallbacksConfig contains settings of the callbacks send_transaction and
+// approve_transaction deliver once a transaction is confirmed or fails
+type CallbacksConfig struct {
+	// Secret signs every callback body with HMAC-SHA256; empty sends them unsigned
+	Secret string `yaml:"secret,omitempty"`
+	// MaxAttempts is how often a callback is tried before it is given up
+	MaxAttempts int `yaml:"max_attempts"`
+	// RetryDelay is the wait before the first retry; it doubles after every failed attempt
+	RetryDelay time.Duration `yaml:"retry_delay"`
+	// Timeout bounds every single delivery attempt
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Validate rejects negative attempt counts and durations
+func (c CallbacksConfig) Validate() error {
+	if c.MaxAttempts < 0 {
+		return fmt.Errorf("max_attempts must not be negative, got %d", c.MaxAttempts)
+	}
+	if c.RetryDelay < 0 {
+		return fmt.Errorf("retry_delay must not be negative, got %s", c.RetryDelay)
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative, got %s", c.Timeout)
+	}
+	return nil
+}
+
+// RPC retry defaults
+const (
+	DefaultRPCMaxRetries = 2
+	DefaultRPCRetryDelay = 250 * time.Millisecond
+)
+
+// RPCRetryConfig controls how a call to a chain's RPC endpoints is retried
+// after transient failures: HTTP 429 or 5xx, reset connections and timeouts
+type RPCRetryConfig struct {
+	// MaxRetries is how often a call is retried once every endpoint has failed
+	MaxRetries int `yaml:"max_retries"`
+	// BaseDelay is the wait before the first retry; it doubles after every failed retry
+	BaseDelay time.Duration `yaml:"base_delay"`
+}
+
+// Validate rejects negative retry counts and delays
+func (c RPCRetryConfig) Validate() error {
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must not be negative, got %d", c.MaxRetries)
+	}
+	if c.BaseDelay < 0 {
+		return fmt.Errorf("base_delay must not be negative, got %s", c.BaseDelay)
+	}
+	return nil
 }
 
 // DefaultConfig returns a default configuration
@@ -177,7 +1221,69 @@ func DefaultConfig() *Config {
 	return &Config{
 		Wallet: WalletConfig{
 			DataDir:     getWalletHomeDir(),
-			NetworkMode: "mainnet",
+			NetworkMode: NetworkModeMainnet,
+			DefaultChain: "ethereum",
+			Storage: StorageConfig{
+				Backend: "file",
+			},
+			FilePermissions: FilePermissionsConfig{
+				OnInsecure: FilePermissionsWarn,
+			},
+			AccountDiscovery: AccountDiscoveryConfig{
+				MaxAccounts: 20,
+				GapLimit:    5,
+			},
+			BalanceSnapshots: BalanceSnapshotConfig{
+				Enabled:   true,
+				Interval:  time.Hour,
+				Retention: 30 * 24 * time.Hour,
+				Tokens: map[string][]string{
+					"ethereum": {
+						"0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", // USDC
+						"0xdAC17F958D2ee523a2206206994597C13D831ec7", // USDT
+					},
+					"bsc": {
+						"0x55d398326f99059fF775485246999027B3197955", // USDT
+					},
+				},
+			},
+			Metadata: MetadataConfig{
+				IPFSGateway: "https://ipfs.io/ipfs/",
+				CacheTTL:    time.Hour,
+				Timeout:     10 * time.Second,
+			},
+			ReplayProtection: ReplayProtectionConfig{
+				Enabled: true,
+				Window:  30 * time.Second,
+			},
+			MaxPendingTransactions: 1000,
+			TokenLists: TokenListsConfig{
+				RefreshInterval: 24 * time.Hour,
+			},
+			AddressBook: AddressBookConfig{
+				BlockedCategories: []string{"scam"},
+				WarnCategories:    []string{},
+			},
+			CacheWarmup: CacheWarmupConfig{
+				Enabled: true,
+				Tokens: map[string][]string{
+					"ethereum": {
+						"0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", // USDC
+						"0xdAC17F958D2ee523a2206206994597C13D831ec7", // USDT
+					},
+					"bsc": {
+						"0x55d398326f99059fF775485246999027B3197955", // USDT
+					},
+				},
+				RequestInterval: 250 * time.Millisecond,
+				Timeout:         time.Minute,
+			},
+			PriceHistory: PriceHistoryConfig{
+				Source:    PriceHistorySourceCoinGecko,
+				BaseURL:   "https://api.coingecko.com/api/v3",
+				Timeout:   10 * time.Second,
+				CacheSize: 4096,
+			},
 		},
 		Chains: ChainsConfig{
 			Solana: SolanaChainConfig{
@@ -189,6 +1295,8 @@ func DefaultConfig() *Config {
 				WSEndpoint: "wss://api.mainnet-beta.solana.com",
 				Commitment: "confirmed",
 				ReserveSOL: 0.01,
+				ComputeUnitMultiplier: 1.0,
+				PriorityFeeMultiplier: 1.0,
 				Retry: RetryConfig{
 					MaxRetries:           3,
 					SlippageIncrementBps: 50,
@@ -218,16 +1326,53 @@ func DefaultConfig() *Config {
 				},
 			},
 			Ethereum: EthereumChainConfig{
-				Enabled:      true,
-				RPCEndpoints: []string{"https://eth-mainnet.g.alchemy.com/v2/YOUR_API_KEY"},
-				ChainID:      1,
-				GasStrategy:  "fast",
+				Enabled:       true,
+				RPCEndpoints:  []string{"https://eth-mainnet.g.alchemy.com/v2/YOUR_API_KEY"},
+				ChainID:       1,
+				GasStrategy:   "fast",
+				MaxFee:        200,
+				ReserveNative: 0.005,
+				GasLimitMultiplier: 1.0,
+				FeeMultiplier:      1.0,
+				RPCRetry: RPCRetryConfig{
+					MaxRetries: DefaultRPCMaxRetries,
+					BaseDelay:  DefaultRPCRetryDelay,
+				},
+				Confirmation:  ConfirmationConfig{
+					Timeout:               15 * time.Minute,
+					PollInterval:          15 * time.Second,
+					RequiredConfirmations: 12,
+				},
 			},
 			BSC: BSCChainConfig{
-				Enabled:      true,
-				RPCEndpoints: []string{"https://bsc-dataseed.binance.org"},
-				ChainID:      56,
-				GasStrategy:  "standard",
+				Enabled:       true,
+				RPCEndpoints:  []string{"https://bsc-dataseed.binance.org"},
+				ChainID:       56,
+				GasStrategy:   "standard",
+				MaxFee:        20,
+				ReserveNative: 0.002,
+				GasLimitMultiplier: 1.0,
+				FeeMultiplier:      1.0,
+				Confirmation:  ConfirmationConfig{
+					Timeout:               10 * time.Minute,
+					PollInterval:          10 * time.Second,
+					RequiredConfirmations: 15,
+				},
+			},
+			Balance: BalanceConfig{
+				Sources:           BalanceSourcesRPCThenDEX,
+				FailOnUnavailable: false,
+			},
+			Monitor: ChainMonitorConfig{
+				Enabled:       false,
+				PollInterval:  15 * time.Second,
+				HistoryDepth:  64,
+				MinReorgDepth: 1,
+				StallAfter: map[string]time.Duration{
+					"ethereum": 2 * time.Minute,
+					"bsc":      time.Minute,
+					"solana":   time.Minute,
+				},
 			},
 		},
 		DEX: DEXConfig{
@@ -250,16 +1395,24 @@ func DefaultConfig() *Config {
 				Timeout:          10,
 			},
 			Composite: CompositeConfig{
-				Enabled:   true,
-				Providers: []string{"jupiter", "okex"},
-				Strategy:  "best_price",
+				Enabled:         true,
+				Providers:       []string{"jupiter", "okex"},
+				Strategy:        "best_price",
+				ProviderTimeout: 5 * time.Second,
+				Timeout:         10 * time.Second,
+				MaxConcurrency:  4,
+				DecisionHistory: 50,
 			},
+			PartialFill: "revert",
 		},
 		Security: SecurityConfig{
 			EncryptPrivateKeys: true,
 			KeyDerivationPath:  "m/44'/501'/0'/0'",
 			SessionTimeout:     3600,
 			RequirePassword:    false,
+			SigningPolicy: SigningPolicyConfig{
+				Default: "allow",
+			},
 		},
 		Logging: LoggingConfig{
 			Level:      "info",
@@ -269,6 +1422,17 @@ func DefaultConfig() *Config {
 			MaxBackups: 3,
 			MaxAge:     28,
 		},
+		NativeMessaging: NativeMessagingConfig{
+			MaxMessageSize: DefaultNativeMessageSize,
+		},
+		MCP: MCPConfig{
+			ToolTimeout: DefaultToolTimeout,
+		},
+		Callbacks: CallbacksConfig{
+			MaxAttempts: DefaultCallbackMaxAttempts,
+			RetryDelay:  DefaultCallbackRetryDelay,
+			Timeout:     DefaultCallbackTimeout,
+		},
 	}
 }
 
@@ -277,7 +1441,7 @@ func TestConfig() *Config {
 	config := DefaultConfig()
 	
 	// Use devnet for testing
-	config.Wallet.NetworkMode = "devnet"
+	config.Wallet.NetworkMode = NetworkModeDevnet
 	config.Chains.Solana.RPCEndpoints = []string{"https://api.devnet.solana.com"}
 	config.Chains.Solana.WSEndpoint = "wss://api.devnet.solana.com"
 	config.Chains.Ethereum.RPCEndpoints = []string{"https://eth-goerli.g.alchemy.com/v2/test"}
@@ -337,15 +1501,323 @@ func LoadConfig(configPath string) (*Config, error) {
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	var present configPresence
+	if err := yaml.Unmarshal(data, &present); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
 	
 	// Expand tilde paths in configuration values
 	if err := expandConfigPaths(&config); err != nil {
 		return nil, fmt.Errorf("failed to expand config paths: %w", err)
 	}
 	
+	// Fill settings missing from older config files, then validate
+	applyConfirmationDefaults(&config)
+	if config.Wallet.AccountDiscovery.MaxAccounts == 0 && config.Wallet.AccountDiscovery.GapLimit == 0 {
+		config.Wallet.AccountDiscovery = DefaultConfig().Wallet.AccountDiscovery
+	}
+	if config.Wallet.BalanceSnapshots.Interval == 0 {
+		config.Wallet.BalanceSnapshots.Interval = DefaultConfig().Wallet.BalanceSnapshots.Interval
+	}
+	if config.Wallet.BalanceSnapshots.Retention == 0 {
+		config.Wallet.BalanceSnapshots.Retention = DefaultConfig().Wallet.BalanceSnapshots.Retention
+	}
+	if present.Wallet.ReplayProtection == nil {
+		config.Wallet.ReplayProtection = DefaultConfig().Wallet.ReplayProtection
+	}
+	if config.Wallet.MaxPendingTransactions == 0 {
+		config.Wallet.MaxPendingTransactions = DefaultConfig().Wallet.MaxPendingTransactions
+	}
+	if config.Wallet.AddressBook.BlockedCategories == nil && config.Wallet.AddressBook.WarnCategories == nil {
+		config.Wallet.AddressBook = DefaultConfig().Wallet.AddressBook
+	}
+	if present.Wallet.CacheWarmup == nil {
+		config.Wallet.CacheWarmup = DefaultConfig().Wallet.CacheWarmup
+	}
+	if config.Chains.Balance.Sources == "" {
+		config.Chains.Balance.Sources = BalanceSourcesRPCThenDEX
+	}
+	if config.Chains.Monitor.PollInterval == 0 && config.Chains.Monitor.HistoryDepth == 0 && config.Chains.Monitor.StallAfter == nil {
+		enabled := config.Chains.Monitor.Enabled
+		config.Chains.Monitor = DefaultConfig().Chains.Monitor
+		config.Chains.Monitor.Enabled = enabled
+	}
+	if config.DEX.Composite.ProviderTimeout == 0 {
+		config.DEX.Composite.ProviderTimeout = DefaultConfig().DEX.Composite.ProviderTimeout
+	}
+	if config.DEX.Composite.Timeout == 0 {
+		config.DEX.Composite.Timeout = DefaultConfig().DEX.Composite.Timeout
+	}
+	if config.DEX.Composite.MaxConcurrency == 0 {
+		config.DEX.Composite.MaxConcurrency = DefaultConfig().DEX.Composite.MaxConcurrency
+	}
+	if config.DEX.Composite.DecisionHistory == 0 {
+		config.DEX.Composite.DecisionHistory = DefaultConfig().DEX.Composite.DecisionHistory
+	}
+	if config.DEX.PartialFill == "" {
+		config.DEX.PartialFill = DefaultConfig().DEX.PartialFill
+	}
+	if config.NativeMessaging.MaxMessageSize == 0 {
+		config.NativeMessaging.MaxMessageSize = DefaultNativeMessageSize
+	}
+	if config.MCP.ToolTimeout == 0 {
+		config.MCP.ToolTimeout = DefaultToolTimeout
+	}
+	if config.Callbacks.MaxAttempts == 0 {
+		config.Callbacks.MaxAttempts = DefaultCallbackMaxAttempts
+	}
+	if config.Callbacks.RetryDelay == 0 {
+		config.Callbacks.RetryDelay = DefaultCallbackRetryDelay
+	}
+	if config.Callbacks.Timeout == 0 {
+		config.Callbacks.Timeout = DefaultCallbackTimeout
+	}
+	if config.Chains.Ethereum.RPCRetry.MaxRetries == 0 {
+		config.Chains.Ethereum.RPCRetry.MaxRetries = DefaultRPCMaxRetries
+	}
+	if config.Chains.Ethereum.RPCRetry.BaseDelay == 0 {
+		config.Chains.Ethereum.RPCRetry.BaseDelay = DefaultRPCRetryDelay
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	
 	return &config, nil
 }
 
+// PaperTrading reports whether any broadcast path is the paper channel, which
+// simulates transactions instead of sending them
+func (c *Config) PaperTrading() bool {
+	channels := []string{
+		c.Chains.Solana.Broadcast.Channel,
+		c.DEX.OKEx.BroadcastChannel,
+		c.DEX.Jupiter.BroadcastChannel,
+		c.DEX.PumpFun.BroadcastChannel,
+	}
+	for _, channel := range channels {
+		if channel == "paper" {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks configuration values that cannot be safely defaulted
+func (c *Config) Validate() error {
+	if _, err := NormalizeNetworkMode(c.Wallet.NetworkMode); err != nil {
+		return fmt.Errorf("wallet.network_mode: %w", err)
+	}
+	defaultChain, err := NormalizeDefaultChain(c.Wallet.DefaultChain)
+	if err != nil {
+		return fmt.Errorf("wallet.default_chain: %w", err)
+	}
+	// An unset default keeps the historical ethereum default, even for older files without enabled flags
+	if c.Wallet.DefaultChain != "" && !c.Chains.Enabled(defaultChain) {
+		return fmt.Errorf("wallet.default_chain: chain %s is not enabled (set chains.%s.enabled)", defaultChain, defaultChain)
+	}
+	confirmations := map[string]*ConfirmationConfig{
+		"solana":   &c.Chains.Solana.Confirmation,
+		"ethereum": &c.Chains.Ethereum.Confirmation,
+		"bsc":      &c.Chains.BSC.Confirmation,
+	}
+	for chainName, confirmation := range confirmations {
+		if err := confirmation.Validate(); err != nil {
+			return fmt.Errorf("chains.%s.confirmation: %w", chainName, err)
+		}
+	}
+	gasStrategies := map[string]string{
+		"ethereum": c.Chains.Ethereum.GasStrategy,
+		"bsc":      c.Chains.BSC.GasStrategy,
+	}
+	for chainName, strategy := range gasStrategies {
+		if !isValidGasStrategy(strategy) {
+			return fmt.Errorf("chains.%s.gas_strategy: unsupported strategy %q (supported: slow, standard, fast, dynamic)", chainName, strategy)
+		}
+	}
+	if err := c.Chains.Ethereum.RPCRetry.Validate(); err != nil {
+		return fmt.Errorf("chains.ethereum.rpc_retry: %w", err)
+	}
+	if c.Chains.Ethereum.MaxFee < 0 || c.Chains.BSC.MaxFee < 0 {
+		return fmt.Errorf("chains max_fee must not be negative")
+	}
+	if c.Chains.Ethereum.ReserveNative < 0 || c.Chains.BSC.ReserveNative < 0 {
+		return fmt.Errorf("chains reserve_native must not be negative")
+	}
+	multipliers := []struct {
+		name  string
+		value float64
+	}{
+		{"ethereum.gas_limit_multiplier", c.Chains.Ethereum.GasLimitMultiplier},
+		{"ethereum.fee_multiplier", c.Chains.Ethereum.FeeMultiplier},
+		{"bsc.gas_limit_multiplier", c.Chains.BSC.GasLimitMultiplier},
+		{"bsc.fee_multiplier", c.Chains.BSC.FeeMultiplier},
+		{"solana.compute_unit_multiplier", c.Chains.Solana.ComputeUnitMultiplier},
+		{"solana.priority_fee_multiplier", c.Chains.Solana.PriorityFeeMultiplier},
+	}
+	for _, multiplier := range multipliers {
+		if multiplier.value == 0 {
+			continue
+		}
+		if err := CheckEstimateMultiplier(multiplier.value); err != nil {
+			return fmt.Errorf("chains.%s: %w", multiplier.name, err)
+		}
+	}
+	feeOnTransferTokens := map[string]map[string]float64{
+		"ethereum": c.Chains.Ethereum.FeeOnTransferTokens,
+		"bsc":      c.Chains.BSC.FeeOnTransferTokens,
+	}
+	for chainName, tokens := range feeOnTransferTokens {
+		for token, fee := range tokens {
+			if !isHexAddress(token) {
+				return fmt.Errorf("chains.%s.fee_on_transfer_tokens: %q is not an EVM address", chainName, token)
+			}
+			if fee <= 0 || fee >= 100 {
+				return fmt.Errorf("chains.%s.fee_on_transfer_tokens: fee of %s must be between 0 and 100 percent, got %g", chainName, token, fee)
+			}
+		}
+	}
+	for _, chainName := range []string{"solana", "ethereum", "bsc"} {
+		if threshold := c.Chains.LargeTxThreshold(chainName); threshold.Native < 0 || threshold.USD < 0 {
+			return fmt.Errorf("chains.%s.large_tx_threshold: limits must not be negative", chainName)
+		}
+		minTransfer := c.Chains.MinTransfer(chainName)
+		if minTransfer.Native < 0 {
+			return fmt.Errorf("chains.%s.min_transfer: native must not be negative", chainName)
+		}
+		for token, minimum := range minTransfer.Tokens {
+			if minimum < 0 {
+				return fmt.Errorf("chains.%s.min_transfer: %s must not be negative", chainName, token)
+			}
+		}
+	}
+	if !isValidCommitment(c.Chains.Solana.Commitment) {
+		return fmt.Errorf("chains.solana.commitment: unsupported commitment %q (supported: processed, confirmed, finalized)", c.Chains.Solana.Commitment)
+	}
+	if c.Chains.Solana.DurableNonce.Enabled && strings.TrimSpace(c.Chains.Solana.DurableNonce.NonceAccount) == "" {
+		return fmt.Errorf("chains.solana.durable_nonce: nonce_account is required when enabled")
+	}
+	if err := c.Chains.Balance.Validate(); err != nil {
+		return fmt.Errorf("chains.balance: %w", err)
+	}
+	if err := c.Chains.Monitor.Validate(); err != nil {
+		return fmt.Errorf("chains.monitor: %w", err)
+	}
+	if err := c.Wallet.FilePermissions.Validate(); err != nil {
+		return fmt.Errorf("wallet.file_permissions: %w", err)
+	}
+	if err := c.Wallet.AccountDiscovery.Validate(); err != nil {
+		return fmt.Errorf("wallet.account_discovery: %w", err)
+	}
+	if err := c.Wallet.BalanceSnapshots.Validate(); err != nil {
+		return fmt.Errorf("wallet.balance_snapshots: %w", err)
+	}
+	if err := c.Wallet.Metadata.Validate(); err != nil {
+		return fmt.Errorf("wallet.metadata: %w", err)
+	}
+	if err := c.Wallet.ReplayProtection.Validate(); err != nil {
+		return fmt.Errorf("wallet.replay_protection: %w", err)
+	}
+	if err := c.Wallet.TokenLists.Validate(); err != nil {
+		return fmt.Errorf("wallet.token_lists: %w", err)
+	}
+	if err := c.Wallet.AddressBook.Validate(); err != nil {
+		return fmt.Errorf("wallet.address_book: %w", err)
+	}
+	if err := c.Wallet.CacheWarmup.Validate(); err != nil {
+		return fmt.Errorf("wallet.cache_warmup: %w", err)
+	}
+	if err := c.Wallet.PriceHistory.Validate(); err != nil {
+		return fmt.Errorf("wallet.price_history: %w", err)
+	}
+	if c.Wallet.MaxPendingTransactions < 0 {
+		return fmt.Errorf("wallet.max_pending_transactions must not be negative, got %d", c.Wallet.MaxPendingTransactions)
+	}
+	if err := c.Security.AutoApproval.Validate(); err != nil {
+		return fmt.Errorf("security.auto_approval: %w", err)
+	}
+	for _, address := range c.Security.SafeContractRecipients {
+		if !isHexAddress(address) {
+			return fmt.Errorf("security.safe_contract_recipients: %q is not an EVM address", address)
+		}
+	}
+	if err := c.Security.SigningPolicy.Validate(); err != nil {
+		return fmt.Errorf("security.signing_policy: %w", err)
+	}
+	if err := validateSpendLimits(c.Security.SpendLimits); err != nil {
+		return fmt.Errorf("security.spend_limits: %w", err)
+	}
+	if err := c.DEX.OKEx.Validate(); err != nil {
+		return fmt.Errorf("dex.okex: %w", err)
+	}
+	if err := c.DEX.Composite.Validate(); err != nil {
+		return fmt.Errorf("dex.composite: %w", err)
+	}
+	if c.DEX.PartialFill != "revert" && c.DEX.PartialFill != "return_leftover" {
+		return fmt.Errorf("dex.partial_fill must be \"revert\" or \"return_leftover\", got %q", c.DEX.PartialFill)
+	}
+	if err := c.DEX.PairPolicy.Validate(); err != nil {
+		return fmt.Errorf("dex.pair_policy: %w", err)
+	}
+	if err := c.NativeMessaging.Validate(); err != nil {
+		return fmt.Errorf("native_messaging: %w", err)
+	}
+	if err := c.MCP.Validate(); err != nil {
+		return fmt.Errorf("mcp: %w", err)
+	}
+	if err := c.Callbacks.Validate(); err != nil {
+		return fmt.Errorf("callbacks: %w", err)
+	}
+	return nil
+}
+
+// isValidGasStrategy reports whether strategy is a supported EVM gas strategy.
+// An empty strategy is allowed and means "standard".
+func isValidGasStrategy(strategy string) bool {
+	switch strings.ToLower(strategy) {
+	case "", "slow", "standard", "fast", "dynamic":
+		return true
+	}
+	return false
+}
+
+// isValidCommitment reports whether level is a supported Solana commitment level.
+// An empty level is allowed and means "confirmed".
+func isValidCommitment(level string) bool {
+	switch strings.ToLower(level) {
+	case "", "processed", "confirmed", "finalized":
+		return true
+	}
+	return false
+}
+
+// configPresence records which optional blocks a config file spells out, so
+// that defaults fill blocks that are absent but not blocks a user turned off
+type configPresence struct {
+	Wallet struct {
+		ReplayProtection *yaml.Node `yaml:"replay_protection"`
+		CacheWarmup      *yaml.Node `yaml:"cache_warmup"`
+	} `yaml:"wallet"`
+}
+
+// applyConfirmationDefaults fills zero-valued confirmation settings from DefaultConfig
+func applyConfirmationDefaults(config *Config) {
+	defaults := DefaultConfig().Chains
+	fill := func(target *ConfirmationConfig, def ConfirmationConfig) {
+		if target.Timeout == 0 {
+			target.Timeout = def.Timeout
+		}
+		if target.PollInterval == 0 {
+			target.PollInterval = def.PollInterval
+		}
+		if target.RequiredConfirmations == 0 {
+			target.RequiredConfirmations = def.RequiredConfirmations
+		}
+	}
+	fill(&config.Chains.Solana.Confirmation, defaults.Solana.Confirmation)
+	fill(&config.Chains.Ethereum.Confirmation, defaults.Ethereum.Confirmation)
+	fill(&config.Chains.BSC.Confirmation, defaults.BSC.Confirmation)
+}
+
 // SaveConfig saves configuration to file
 func SaveConfig(config *Config, configPath string) error {
 	// Expand home directory
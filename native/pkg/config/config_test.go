@@ -0,0 +1,701 @@
+// SPDX-License-Identifier: Apache-2.0
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultConfigConfirmationIntervals(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if cfg.Chains.Solana.Confirmation.PollInterval != 3*time.Second {
+		t.Errorf("unexpected solana poll interval: %s", cfg.Chains.Solana.Confirmation.PollInterval)
+	}
+	if cfg.Chains.Ethereum.Confirmation.PollInterval != 15*time.Second {
+		t.Errorf("unexpected ethereum poll interval: %s", cfg.Chains.Ethereum.Confirmation.PollInterval)
+	}
+	if cfg.Chains.BSC.Confirmation.PollInterval != 10*time.Second {
+		t.Errorf("unexpected bsc poll interval: %s", cfg.Chains.BSC.Confirmation.PollInterval)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("default config should validate: %v", err)
+	}
+}
+
+func TestLoadConfigRejectsSubSecondPollInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := []byte("chains:\n  bsc:\n    confirmation:\n      poll_interval: 500ms\n")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("expected error for poll interval below 1s")
+	}
+}
+
+func TestLoadConfigFillsMissingConfirmationDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := []byte("chains:\n  ethereum:\n    confirmation:\n      poll_interval: 2s\n")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Chains.Ethereum.Confirmation.PollInterval != 2*time.Second {
+		t.Errorf("expected configured interval to be kept, got %s", cfg.Chains.Ethereum.Confirmation.PollInterval)
+	}
+	if cfg.Chains.Solana.Confirmation.PollInterval != 3*time.Second {
+		t.Errorf("expected solana default, got %s", cfg.Chains.Solana.Confirmation.PollInterval)
+	}
+}
+
+func TestBalanceConfigSourceOrder(t *testing.T) {
+	cases := map[string][]string{
+		"":                       {BalanceSourceRPC, BalanceSourceDEX},
+		BalanceSourcesRPCThenDEX: {BalanceSourceRPC, BalanceSourceDEX},
+		BalanceSourcesRPCOnly:    {BalanceSourceRPC},
+		BalanceSourcesDEXOnly:    {BalanceSourceDEX},
+	}
+	for sources, want := range cases {
+		got := BalanceConfig{Sources: sources}.SourceOrder()
+		if len(got) != len(want) {
+			t.Errorf("sources %q: expected %v, got %v", sources, want, got)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("sources %q: expected %v, got %v", sources, want, got)
+			}
+		}
+	}
+}
+
+func TestLoadConfigBalanceSources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("wallet:\n  network_mode: mainnet\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Chains.Balance.Sources != BalanceSourcesRPCThenDEX {
+		t.Errorf("expected rpc-then-dex default, got %q", cfg.Chains.Balance.Sources)
+	}
+
+	if err := os.WriteFile(path, []byte("chains:\n  balance:\n    sources: rpc-first\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("expected error for unknown balance sources")
+	}
+}
+
+func TestValidateGasSettings(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Chains.Ethereum.GasStrategy = "Dynamic"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("dynamic gas strategy should validate: %v", err)
+	}
+
+	cfg.Chains.BSC.GasStrategy = "turbo"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown gas strategy")
+	}
+
+	cfg = DefaultConfig()
+	cfg.Chains.Ethereum.MaxFee = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative max_fee")
+	}
+}
+
+func TestValidateRejectsNegativeNativeReserve(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Chains.Ethereum.ReserveNative <= 0 || cfg.Chains.BSC.ReserveNative <= 0 {
+		t.Error("expected a default native reserve for EVM chains")
+	}
+
+	cfg.Chains.BSC.ReserveNative = -0.1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative reserve_native")
+	}
+}
+
+func TestMinTransferConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Chains.Ethereum.MinTransfer = MinTransferConfig{Native: 0.001, Tokens: map[string]float64{"usdc": 1}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.Chains.MinTransfer("eth").Native; got != 0.001 {
+		t.Errorf("expected the eth alias to resolve the ethereum minimum, got %v", got)
+	}
+	if minimum, ok := cfg.Chains.MinTransfer("ethereum").TokenMinimum("USDC"); !ok || minimum != 1 {
+		t.Errorf("expected token minimums to match case-insensitively, got %v %v", minimum, ok)
+	}
+	if _, ok := cfg.Chains.MinTransfer("ethereum").TokenMinimum("DAI"); ok {
+		t.Error("expected no minimum for an unlisted token")
+	}
+
+	cfg.Chains.Solana.MinTransfer.Tokens = map[string]float64{"BONK": -1}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a negative token minimum")
+	}
+}
+
+func TestValidateFilePermissions(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Wallet.FilePermissions.OnInsecure != FilePermissionsWarn {
+		t.Errorf("expected insecure wallet files to warn by default, got %q", cfg.Wallet.FilePermissions.OnInsecure)
+	}
+	cfg.Wallet.FilePermissions.OnInsecure = "Refuse"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("refuse should validate: %v", err)
+	}
+	cfg.Wallet.FilePermissions.OnInsecure = "ignore"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown on_insecure action")
+	}
+}
+
+func TestValidateNativeMessagingMaxMessageSize(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.NativeMessaging.MaxMessageSize != 1<<20 {
+		t.Errorf("expected a 1 MiB default, got %d", cfg.NativeMessaging.MaxMessageSize)
+	}
+	cfg.NativeMessaging.MaxMessageSize = MaxNativeMessageSize + 1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a limit above what Chrome can send")
+	}
+	cfg.NativeMessaging.MaxMessageSize = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a negative limit")
+	}
+}
+
+func TestMCPToolTimeouts(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MCP.ToolTimeouts = map[string]time.Duration{"swap_tokens": 5 * time.Minute}
+	if got := cfg.MCP.TimeoutFor("swap_tokens"); got != 5*time.Minute {
+		t.Errorf("expected the per-tool override, got %s", got)
+	}
+	if got := cfg.MCP.TimeoutFor("get_balance"); got != DefaultToolTimeout {
+		t.Errorf("expected the default timeout, got %s", got)
+	}
+	if got := (MCPConfig{}).TimeoutFor("get_balance"); got != DefaultToolTimeout {
+		t.Errorf("expected an unset timeout to stay finite, got %s", got)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	cfg.MCP.ToolTimeouts["get_balance"] = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a zero per-tool timeout")
+	}
+}
+
+func TestValidateFeeOnTransferTokens(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Chains.BSC.FeeOnTransferTokens = map[string]float64{"0x1111111111111111111111111111111111111111": 5}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	cfg.Chains.BSC.FeeOnTransferTokens["0x1111111111111111111111111111111111111111"] = 100
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a 100% transfer fee")
+	}
+	cfg.Chains.BSC.FeeOnTransferTokens = map[string]float64{"SAFEMOON": 10}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a token that is not an address")
+	}
+}
+
+func TestValidateCallbacks(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	cfg.Callbacks.RetryDelay = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a negative retry delay")
+	}
+	cfg.Callbacks.RetryDelay = time.Second
+	cfg.Callbacks.MaxAttempts = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative max attempts")
+	}
+}
+
+func TestValidateRPCRetry(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Chains.Ethereum.RPCRetry.MaxRetries = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative max retries")
+	}
+	cfg.Chains.Ethereum.RPCRetry.MaxRetries = 0
+	cfg.Chains.Ethereum.RPCRetry.BaseDelay = -time.Millisecond
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a negative base delay")
+	}
+}
+
+func TestValidateDurableNonceRequiresAccount(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Chains.Solana.DurableNonce.Enabled = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for durable nonce without nonce_account")
+	}
+
+	cfg.Chains.Solana.DurableNonce.NonceAccount = "9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateSolanaCommitment(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Chains.Solana.Commitment = "Finalized"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("finalized commitment should validate: %v", err)
+	}
+
+	cfg.Chains.Solana.Commitment = "max"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown commitment")
+	}
+}
+
+func TestAccountDiscoveryDefaultsAndValidation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("wallet:\n  network_mode: mainnet\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Wallet.AccountDiscovery.MaxAccounts != 20 || cfg.Wallet.AccountDiscovery.GapLimit != 5 {
+		t.Errorf("expected default discovery bounds, got %+v", cfg.Wallet.AccountDiscovery)
+	}
+
+	cfg.Wallet.AccountDiscovery = AccountDiscoveryConfig{MaxAccounts: 3, GapLimit: 5}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for gap_limit above max_accounts")
+	}
+	cfg.Wallet.AccountDiscovery = AccountDiscoveryConfig{MaxAccounts: 10, GapLimit: -1}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative gap_limit")
+	}
+}
+
+func TestBalanceSnapshotValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("default config should be valid: %v", err)
+	}
+
+	cfg.Wallet.BalanceSnapshots.Interval = 10 * time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for interval below one minute")
+	}
+
+	cfg.Wallet.BalanceSnapshots.Interval = 2 * time.Hour
+	cfg.Wallet.BalanceSnapshots.Retention = time.Hour
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for retention shorter than interval")
+	}
+
+	cfg.Wallet.BalanceSnapshots.Enabled = false
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("disabled snapshots should not be validated: %v", err)
+	}
+}
+
+func TestValidateNetworkMode(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Wallet.NetworkMode = "Testnet"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("testnet should validate: %v", err)
+	}
+
+	cfg.Wallet.NetworkMode = "goerli"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown network mode")
+	}
+}
+
+func TestLoadConfigReplayProtectionDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("wallet:\n  network_mode: mainnet\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Wallet.ReplayProtection.Enabled || cfg.Wallet.ReplayProtection.Window != 30*time.Second {
+		t.Errorf("expected replay protection on by default, got %+v", cfg.Wallet.ReplayProtection)
+	}
+
+	if err := os.WriteFile(path, []byte("wallet:\n  replay_protection:\n    enabled: false\n    window: 10s\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if cfg, err := LoadConfig(path); err != nil || cfg.Wallet.ReplayProtection.Enabled {
+		t.Errorf("expected replay protection to stay disabled, got %+v, %v", cfg, err)
+	}
+
+	if err := os.WriteFile(path, []byte("wallet:\n  replay_protection:\n    enabled: false\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err = LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Wallet.ReplayProtection.Enabled {
+		t.Errorf("expected enabled: false alone to disable replay protection, got %+v", cfg.Wallet.ReplayProtection)
+	}
+}
+
+func TestLoadConfigCacheWarmupDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("wallet:\n  network_mode: mainnet\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Wallet.CacheWarmup.Enabled || cfg.Wallet.CacheWarmup.RequestInterval == 0 {
+		t.Errorf("expected cache warm-up on by default, got %+v", cfg.Wallet.CacheWarmup)
+	}
+
+	if err := os.WriteFile(path, []byte("wallet:\n  cache_warmup:\n    enabled: false\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err = LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Wallet.CacheWarmup.Enabled {
+		t.Errorf("expected enabled: false alone to disable cache warm-up, got %+v", cfg.Wallet.CacheWarmup)
+	}
+}
+
+func TestAutoApprovalPolicyValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Security.AutoApproval.Enabled {
+		t.Fatal("auto-approval should be disabled by default")
+	}
+
+	cfg.Security.AutoApproval = AutoApprovalPolicy{
+		Enabled: true,
+		Rules:   []AutoApprovalRule{{Name: "small-transfers", MaxValue: 0.01, Methods: []string{"Transfer"}}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("valid policy rejected: %v", err)
+	}
+
+	invalid := map[string]AutoApprovalPolicy{
+		"no rules":       {Enabled: true},
+		"unnamed rule":   {Rules: []AutoApprovalRule{{MaxValue: 1}}},
+		"duplicate name": {Rules: []AutoApprovalRule{{Name: "a", MaxValue: 1}, {Name: "a", MaxValue: 2}}},
+		"no conditions":  {Rules: []AutoApprovalRule{{Name: "anything"}}},
+		"negative value": {Rules: []AutoApprovalRule{{Name: "a", MaxValue: -1}}},
+		"bad method":     {Rules: []AutoApprovalRule{{Name: "a", Methods: []string{"approve"}}}},
+		"selector":       {Rules: []AutoApprovalRule{{Name: "a", Methods: []string{"0xa9059cbb"}}}},
+	}
+	for name, policy := range invalid {
+		cfg.Security.AutoApproval = policy
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestValidateOKExCredentials(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DEX.OKEx.APIKey = "key-1"
+	cfg.DEX.OKEx.SecretKey = "secret-1"
+	cfg.DEX.OKEx.PassPhrase = "pass-1"
+	cfg.DEX.OKEx.Credentials = []OKExCredentials{{APIKey: "key-2", SecretKey: "secret-2", PassPhrase: "pass-2"}}
+	cfg.DEX.OKEx.Rotation = CredentialRotationLeastRecentlyUsed
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid credential sets, got %v", err)
+	}
+	if sets := cfg.DEX.OKEx.CredentialSets(); len(sets) != 2 || sets[0].APIKey != "key-1" {
+		t.Errorf("unexpected credential sets %+v", sets)
+	}
+
+	cfg.DEX.OKEx.Rotation = "random"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unsupported rotation")
+	}
+
+	cfg.DEX.OKEx.Rotation = ""
+	cfg.DEX.OKEx.Credentials = append(cfg.DEX.OKEx.Credentials, OKExCredentials{APIKey: "key-3"})
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for an incomplete credential set")
+	}
+
+	cfg.DEX.OKEx.Credentials = []OKExCredentials{{APIKey: "key-1", SecretKey: "s", PassPhrase: "p"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a duplicate api key")
+	}
+}
+
+func TestAddressBookConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("wallet:\n  network_mode: mainnet\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Wallet.AddressBook.BlockedCategories) != 1 || cfg.Wallet.AddressBook.BlockedCategories[0] != "scam" {
+		t.Errorf("expected scam to be blocked by default, got %+v", cfg.Wallet.AddressBook)
+	}
+
+	// An explicitly empty list turns blocking off
+	if err := os.WriteFile(path, []byte("wallet:\n  address_book:\n    blocked_categories: []\n    warn_categories: [unverified]\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if cfg, err := LoadConfig(path); err != nil || len(cfg.Wallet.AddressBook.BlockedCategories) != 0 {
+		t.Errorf("expected no blocked categories, got %+v, %v", cfg, err)
+	}
+
+	cfg = DefaultConfig()
+	cfg.Wallet.AddressBook.WarnCategories = []string{" "}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an empty category to be rejected")
+	}
+}
+
+func TestValidateDefaultChain(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Chains.Solana.Enabled = true
+	cfg.Wallet.DefaultChain = "SOL"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("enabled alias should validate: %v", err)
+	}
+
+	cfg.Wallet.DefaultChain = "polygon"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "wallet.default_chain") {
+		t.Errorf("expected error for unknown default chain, got %v", err)
+	}
+
+	cfg.Wallet.DefaultChain = "solana"
+	cfg.Chains.Solana.Enabled = false
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "not enabled") {
+		t.Errorf("expected error for a disabled default chain, got %v", err)
+	}
+
+	// Files written before default_chain existed keep working
+	cfg.Wallet.DefaultChain = ""
+	cfg.Chains.Ethereum.Enabled = false
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unset default chain should validate: %v", err)
+	}
+}
+
+func TestLoadConfigCompositeFanOut(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("dex:\n  composite:\n    strategy: best_price\n    timeout: 30s\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	composite := cfg.DEX.Composite
+	if composite.Timeout != 30*time.Second || composite.ProviderTimeout != 5*time.Second || composite.MaxConcurrency != 4 || composite.DecisionHistory != 50 {
+		t.Errorf("expected the configured timeout and default bounds, got %+v", composite)
+	}
+
+	cfg.DEX.Composite.MaxConcurrency = -1
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "dex.composite") {
+		t.Errorf("expected error for negative max_concurrency, got %v", err)
+	}
+
+	cfg.DEX.Composite.MaxConcurrency = 4
+	cfg.DEX.Composite.DecisionHistory = -1
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "decision_history") {
+		t.Errorf("expected error for negative decision_history, got %v", err)
+	}
+}
+
+func TestValidatePartialFill(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.DEX.PartialFill != "revert" {
+		t.Errorf("expected partial fills to revert by default, got %q", cfg.DEX.PartialFill)
+	}
+	cfg.DEX.PartialFill = "return_leftover"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("return_leftover should validate: %v", err)
+	}
+	cfg.DEX.PartialFill = "refund"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "dex.partial_fill") {
+		t.Errorf("expected error for unknown partial fill policy, got %v", err)
+	}
+}
+
+func TestEstimateMultipliers(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Chains.Ethereum.GasLimitMultiplier = 1.2
+	cfg.Chains.Ethereum.FeeMultiplier = 0
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("multipliers of 1.2 and unset should validate: %v", err)
+	}
+	if got := cfg.Chains.EstimateMultipliers("ETH"); got.Limit != 1.2 || got.Fee != 1 {
+		t.Errorf("expected gas limit 1.2 and fee 1 for ethereum, got %+v", got)
+	}
+	if got := cfg.Chains.EstimateMultipliers("polygon"); got.Limit != 1 || got.Fee != 1 {
+		t.Errorf("expected multipliers of 1 for an unknown chain, got %+v", got)
+	}
+
+	cfg.Chains.Solana.PriorityFeeMultiplier = 0.5
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "chains.solana.priority_fee_multiplier") {
+		t.Errorf("expected error for a multiplier below 1, got %v", err)
+	}
+
+	cfg.Chains.Solana.PriorityFeeMultiplier = 1
+	cfg.Chains.BSC.FeeMultiplier = 10
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "chains.bsc.fee_multiplier") {
+		t.Errorf("expected error for a multiplier above the bound, got %v", err)
+	}
+}
+
+func TestValidatePairPolicy(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DEX.PairPolicy = PairPolicyConfig{
+		AllowTokens:    []string{"native", "eth:0xdAC17F958D2ee523a2206206994597C13D831ec7"},
+		DenyTokens:     []string{"solana:9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin"},
+		BlocklistFiles: []string{"/etc/algonius/blocklist.txt"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("pair policy should validate: %v", err)
+	}
+	if chain, token := SplitPairPolicyToken(" eth:0xdAC17F958D2ee523a2206206994597C13D831ec7"); chain != "ethereum" || token != "0xdAC17F958D2ee523a2206206994597C13D831ec7" {
+		t.Errorf("expected the chain alias to be resolved, got %q %q", chain, token)
+	}
+
+	cfg.DEX.PairPolicy.DenyTokens = []string{"tron:TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "dex.pair_policy: deny_tokens[0]: unknown chain") {
+		t.Errorf("expected error for an unknown chain, got %v", err)
+	}
+	cfg.DEX.PairPolicy.DenyTokens = []string{"bsc:"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "must name a token") {
+		t.Errorf("expected error for an entry without a token, got %v", err)
+	}
+}
+
+func TestValidateSigningPolicy(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Security.SigningPolicy.Enabled() {
+		t.Error("expected the default signing policy to allow everything")
+	}
+	cfg.Security.SigningPolicy.Rules = []SigningPolicyRule{
+		{Name: "cap-eth", Action: "deny", Kinds: []string{"send"}, Chains: []string{"ETH"}, AmountAbove: 5},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("valid rule should validate: %v", err)
+	}
+	if !cfg.Security.SigningPolicy.Enabled() {
+		t.Error("expected a policy with rules to be enabled")
+	}
+
+	for name, rule := range map[string]SigningPolicyRule{
+		"action":       {Name: "r", Action: "block"},
+		"kind":         {Name: "r", Action: "deny", Kinds: []string{"transfer"}},
+		"chain":        {Name: "r", Action: "deny", Chains: []string{"polygon"}},
+		"amount_above": {Name: "r", Action: "deny", AmountAbove: -1},
+	} {
+		cfg.Security.SigningPolicy.Rules = []SigningPolicyRule{rule}
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "security.signing_policy") {
+			t.Errorf("expected error for invalid %s, got %v", name, err)
+		}
+	}
+
+	cfg.Security.SigningPolicy = SigningPolicyConfig{Default: "ask"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "default") {
+		t.Errorf("expected error for unknown default, got %v", err)
+	}
+}
+
+func TestValidateSpendLimits(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Security.SpendLimits = map[string]ChainSpendLimits{
+		"ETH": {
+			Native: SpendLimit{MaxTxValue: 0.5, DailyLimit: 2},
+			Tokens: map[string]SpendLimit{"USDC": {DailyLimit: 5000}},
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("valid spend limits should validate: %v", err)
+	}
+
+	for name, limits := range map[string]map[string]ChainSpendLimits{
+		"chain":        {"polygon": {Native: SpendLimit{DailyLimit: 1}}},
+		"max_tx_value": {"bsc": {Native: SpendLimit{MaxTxValue: -1}}},
+		"daily_limit":  {"solana": {Tokens: map[string]SpendLimit{"USDC": {DailyLimit: -1}}}},
+		"token":        {"solana": {Tokens: map[string]SpendLimit{" ": {DailyLimit: 1}}}},
+	} {
+		cfg.Security.SpendLimits = limits
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "security.spend_limits") {
+			t.Errorf("expected error for invalid %s, got %v", name, err)
+		}
+	}
+}
+
+func TestValidatePriceHistory(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("default price history should validate: %v", err)
+	}
+	cfg.Wallet.PriceHistory = PriceHistoryConfig{Source: PriceHistorySourceNone}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("a disabled source needs no URL: %v", err)
+	}
+	for _, history := range []PriceHistoryConfig{
+		{Source: "cryptocompare", BaseURL: "https://min-api.cryptocompare.com"},
+		{Source: PriceHistorySourceCoinGecko, BaseURL: "api.coingecko.com"},
+		{Source: PriceHistorySourceCoinGecko, BaseURL: "https://api.coingecko.com/api/v3", CacheSize: -1},
+	} {
+		cfg.Wallet.PriceHistory = history
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "wallet.price_history") {
+			t.Errorf("expected %+v to be rejected, got %v", history, err)
+		}
+	}
+}
+
+func TestValidateChainMonitor(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Chains.Monitor.Enabled = true
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("default chain monitor should validate: %v", err)
+	}
+	if got := cfg.Chains.Monitor.StallWindow("SOL"); got != time.Minute {
+		t.Errorf("expected the solana stall window for an alias, got %s", got)
+	}
+	for _, monitor := range []ChainMonitorConfig{
+		{Enabled: true, HistoryDepth: 64},
+		{Enabled: true, PollInterval: time.Second, HistoryDepth: 1},
+		{PollInterval: time.Second, HistoryDepth: 8, MinReorgDepth: 9},
+		{PollInterval: time.Second, StallAfter: map[string]time.Duration{"polygon": time.Minute}},
+		{PollInterval: time.Second, StallAfter: map[string]time.Duration{"bsc": -time.Second}},
+	} {
+		cfg.Chains.Monitor = monitor
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "chains.monitor") {
+			t.Errorf("expected %+v to be rejected, got %v", monitor, err)
+		}
+	}
+}
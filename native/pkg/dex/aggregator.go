@@ -3,10 +3,13 @@ package dex
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -20,19 +23,92 @@ type DEXProviderConfig struct {
 	SupportedChains []string           `json:"supported_chains"`
 }
 
+// ErrNoProvidersAvailable is returned when no registered and enabled
+// provider supports the requested chain
+var ErrNoProvidersAvailable = errors.New("no DEX providers available")
+
+// providerChainNames names the chain IDs providers are registered for, in
+// the order they are reported at startup
+var providerChainNames = []struct{ id, name string }{
+	{"1", "ethereum"},
+	{"56", "bsc"},
+	{"501", "solana"},
+}
+
+// chainNameForID returns the chain name for a provider chain ID, or the ID
+// itself when it is not a known chain
+func chainNameForID(chainID string) string {
+	for _, chain := range providerChainNames {
+		if chain.id == chainID {
+			return chain.name
+		}
+	}
+	return chainID
+}
+
+// ActiveProviders returns, for every known chain, the registered and enabled
+// providers of aggregator that support it, best first. Chains without a
+// usable provider map to an empty list.
+func ActiveProviders(aggregator IDEXAggregator) map[string][]string {
+	active := make(map[string][]string, len(providerChainNames))
+	for _, chain := range providerChainNames {
+		active[chain.name] = append([]string{}, aggregator.GetSupportedProviders(chain.id)...)
+	}
+	return active
+}
+
+// LogActiveProviders logs the providers usable on each chain, warning about
+// chains where swaps and quotes will fail
+func LogActiveProviders(aggregator IDEXAggregator, logger *zap.Logger) {
+	active := ActiveProviders(aggregator)
+	for _, chain := range providerChainNames {
+		if len(active[chain.name]) == 0 {
+			logger.Warn("No DEX providers available, swaps and quotes will fail", zap.String("chain", chain.name))
+			continue
+		}
+		logger.Info("Active DEX providers", zap.String("chain", chain.name), zap.Strings("providers", active[chain.name]))
+	}
+}
+
+// SwapAuthorizer is consulted before a swap is executed, with the name of the
+// chain it runs on; an error refuses the swap and is returned as is
+type SwapAuthorizer func(ctx context.Context, chainName string, params SwapParams) error
+
+// QuoteFanOut bounds how GetBestQuote queries providers, so one slow
+// provider cannot hold up the whole quote. Zero values leave that bound off.
+type QuoteFanOut struct {
+	ProviderTimeout time.Duration // how long a single provider may take to quote
+	Deadline        time.Duration // how long the whole fan-out may take
+	MaxConcurrency  int           // providers queried at the same time
+}
+
 // DEXAggregator implements the IDEXAggregator interface
 type DEXAggregator struct {
 	providers map[string]IDEXProvider
 	configs   map[string]*DEXProviderConfig
+	fanOut    QuoteFanOut
+	decisions *decisionLog
+	partialFill string
+	authorize SwapAuthorizer
+	pairPolicy *PairPolicy
 	logger    *zap.Logger
 	mu        sync.RWMutex
 }
 
 // NewDEXAggregator creates a new DEX aggregator instance
 func NewDEXAggregator(logger *zap.Logger) *DEXAggregator {
+	return NewDEXAggregatorWithFanOut(logger, QuoteFanOut{})
+}
+
+// NewDEXAggregatorWithFanOut creates a DEX aggregator whose best-quote
+// fan-out is bounded by fanOut
+func NewDEXAggregatorWithFanOut(logger *zap.Logger, fanOut QuoteFanOut) *DEXAggregator {
 	return &DEXAggregator{
 		providers: make(map[string]IDEXProvider),
 		configs:   make(map[string]*DEXProviderConfig),
+		fanOut:    fanOut,
+		decisions: newDecisionLog(DefaultDecisionHistory),
+		partialFill: PartialFillRevert,
 		logger:    logger,
 	}
 }
@@ -42,9 +118,9 @@ func (d *DEXAggregator) RegisterProvider(provider IDEXProvider) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	name := provider.GetName()
-	if _, exists := d.providers[name]; exists {
-		return fmt.Errorf("provider %s already registered", name)
+	name, err := d.checkRegistration(provider)
+	if err != nil {
+		return err
 	}
 
 	d.providers[name] = provider
@@ -57,9 +133,12 @@ func (d *DEXAggregator) RegisterProviderWithConfig(provider IDEXProvider, config
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	name := provider.GetName()
-	if _, exists := d.providers[name]; exists {
-		return fmt.Errorf("provider %s already registered", name)
+	name, err := d.checkRegistration(provider)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return fmt.Errorf("provider %s registered without config", name)
 	}
 
 	d.providers[name] = provider
@@ -71,14 +150,48 @@ func (d *DEXAggregator) RegisterProviderWithConfig(provider IDEXProvider, config
 	return nil
 }
 
-// GetBestQuote gets the best quote from all available providers
+// checkRegistration validates a provider before it is registered (assumes lock is held)
+func (d *DEXAggregator) checkRegistration(provider IDEXProvider) (string, error) {
+	if provider == nil {
+		return "", errors.New("provider is nil")
+	}
+	name := provider.GetName()
+	if strings.TrimSpace(name) == "" {
+		return "", errors.New("provider has no name")
+	}
+	if _, exists := d.providers[name]; exists {
+		return "", fmt.Errorf("provider %s already registered", name)
+	}
+	return name, nil
+}
+
+// GetBestQuote gets the best quote from all available providers. Providers
+// that do not answer within the fan-out's timeouts are left out of the
+// selection and listed in the quote's TimedOutProviders. Every call that
+// reaches the providers is recorded as a QuoteDecision; pairs the pair policy
+// refuses fail with ErrPairDenied first.
 func (d *DEXAggregator) GetBestQuote(ctx context.Context, params SwapParams) (*SwapQuote, error) {
 	d.mu.RLock()
 	supportedProviders := d.getSupportedProviders(params.ChainID)
+	providers := make(map[string]IDEXProvider, len(supportedProviders))
+	for _, name := range supportedProviders {
+		providers[name] = d.providers[name]
+	}
+	priorities := make(map[string]int, len(supportedProviders))
+	for _, name := range supportedProviders {
+		priorities[name] = d.getProviderPriority(name)
+	}
+	decisions := d.decisions
+	pairPolicy := d.pairPolicy
 	d.mu.RUnlock()
 
+	if pairPolicy != nil {
+		if err := pairPolicy.Check(params); err != nil {
+			return nil, err
+		}
+	}
 	if len(supportedProviders) == 0 {
-		return nil, fmt.Errorf("no providers support chain %s", params.ChainID)
+		return nil, fmt.Errorf("%w for chain %s", ErrNoProvidersAvailable, chainNameForID(params.ChainID))
 	}
 
 	// Channel to collect quotes from all providers
@@ -86,19 +199,43 @@ func (d *DEXAggregator) GetBestQuote(ctx context.Context, params SwapParams) (*S
 		quote *SwapQuote
 		err   error
 		provider string
+		timedOut bool
+		latency  time.Duration
 	}
 	
 	quoteChan := make(chan quoteResult, len(supportedProviders))
+
+	fanOutCtx := ctx
+	if d.fanOut.Deadline > 0 {
+		var cancel context.CancelFunc
+		fanOutCtx, cancel = context.WithTimeout(ctx, d.fanOut.Deadline)
+		defer cancel()
+	}
+	concurrency := d.fanOut.MaxConcurrency
+	if concurrency <= 0 || concurrency > len(supportedProviders) {
+		concurrency = len(supportedProviders)
+	}
+	slots := make(chan struct{}, concurrency)
 	
-	// Request quotes from all supported providers concurrently
+	// Request quotes from all supported providers concurrently, at most
+	// concurrency at a time
 	for _, providerName := range supportedProviders {
 		go func(name string) {
-			provider := d.providers[name]
-			quote, err := provider.GetQuote(ctx, params)
+			select {
+			case slots <- struct{}{}:
+			case <-fanOutCtx.Done():
+				quoteChan <- quoteResult{err: fanOutCtx.Err(), provider: name, timedOut: ctx.Err() == nil}
+				return
+			}
+			defer func() { <-slots }()
+
+			started := time.Now()
+			quote, err := d.quoteWithTimeout(fanOutCtx, providers[name], params)
 			if quote != nil {
 				quote.Provider = name
 			}
-			quoteChan <- quoteResult{quote: quote, err: err, provider: name}
+			timedOut := errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil
+			quoteChan <- quoteResult{quote: quote, err: err, provider: name, timedOut: timedOut, latency: time.Since(started)}
 		}(providerName)
 	}
 
@@ -106,14 +243,28 @@ func (d *DEXAggregator) GetBestQuote(ctx context.Context, params SwapParams) (*S
 	var bestQuote *SwapQuote
 	var quotes []*SwapQuote
 	var errors []error
+	var timedOut []string
+	var failed []ProviderQuote
+	latencies := make(map[string]time.Duration, len(supportedProviders))
 
 	for i := 0; i < len(supportedProviders); i++ {
 		result := <-quoteChan
+		latencies[result.provider] = result.latency
+		if result.timedOut {
+			d.logger.Warn("Provider quote timed out", zap.String("provider", result.provider))
+			timedOut = append(timedOut, result.provider)
+			errors = append(errors, fmt.Errorf("%s timed out", result.provider))
+			failed = append(failed, ProviderQuote{Provider: result.provider, Priority: priorities[result.provider],
+				Status: ProviderQuoteTimedOut, Latency: result.latency, Error: "timed out"})
+			continue
+		}
 		if result.err != nil {
 			d.logger.Warn("Provider quote failed", 
 				zap.String("provider", result.provider),
 				zap.Error(result.err))
 			errors = append(errors, result.err)
+			failed = append(failed, ProviderQuote{Provider: result.provider, Priority: priorities[result.provider],
+				Status: ProviderQuoteFailed, Latency: result.latency, Error: result.err.Error()})
 			continue
 		}
 		
@@ -125,12 +276,50 @@ func (d *DEXAggregator) GetBestQuote(ctx context.Context, params SwapParams) (*S
 		}
 	}
 
+	decision := QuoteDecision{
+		Timestamp: time.Now().UTC(),
+		ChainID:   params.ChainID,
+		FromToken: params.FromToken,
+		ToToken:   params.ToToken,
+		Amount:    params.Amount,
+		Strategy:  StrategyBestOutput,
+	}
+	if params.CompareNetOfFees {
+		decision.Strategy = StrategyBestNetOutput
+	}
+	sort.Slice(failed, func(i, j int) bool { return failed[i].Provider < failed[j].Provider })
+
 	if len(quotes) == 0 {
+		decision.Reason = "no provider returned a valid quote"
+		decision.Quotes = failed
+		decisions.record(decision)
 		return nil, fmt.Errorf("no valid quotes received, errors: %v", errors)
 	}
 
-	// Find the best quote (highest output amount)
-	bestQuote = d.selectBestQuote(quotes)
+	for _, quote := range quotes {
+		setNetToAmount(quote, params.ChainID)
+	}
+
+	// Find the best quote (highest output amount, net of fees when requested)
+	bestQuote = d.selectBestQuote(quotes, params.CompareNetOfFees)
+	sort.Strings(timedOut)
+	bestQuote.TimedOutProviders = timedOut
+
+	decision.Winner = bestQuote.Provider
+	decision.Reason = d.selectionReason(quotes, params.CompareNetOfFees)
+	for _, quote := range quotes {
+		decision.Quotes = append(decision.Quotes, ProviderQuote{
+			Provider:    quote.Provider,
+			Priority:    priorities[quote.Provider],
+			Status:      ProviderQuoteOK,
+			ToAmount:    quote.ToAmount,
+			NetToAmount: quote.NetToAmount,
+			Fees:        quote.Fees,
+			Latency:     latencies[quote.Provider],
+		})
+	}
+	decision.Quotes = append(decision.Quotes, failed...)
+	decisions.record(decision)
 	
 	d.logger.Info("Selected best quote", 
 		zap.String("provider", bestQuote.Provider),
@@ -141,8 +330,36 @@ func (d *DEXAggregator) GetBestQuote(ctx context.Context, params SwapParams) (*S
 	return bestQuote, nil
 }
 
-// selectBestQuote selects the best quote based on output amount and provider priority
-func (d *DEXAggregator) selectBestQuote(quotes []*SwapQuote) *SwapQuote {
+// quoteWithTimeout asks provider for a quote, giving up after the fan-out's
+// provider timeout or when ctx ends even if the provider ignores its context
+func (d *DEXAggregator) quoteWithTimeout(ctx context.Context, provider IDEXProvider, params SwapParams) (*SwapQuote, error) {
+	if d.fanOut.ProviderTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.fanOut.ProviderTimeout)
+		defer cancel()
+	}
+
+	type result struct {
+		quote *SwapQuote
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		quote, err := provider.GetQuote(ctx, params)
+		done <- result{quote: quote, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.quote, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// selectBestQuote selects the best quote based on output amount and provider
+// priority. With netOfFees the output is compared after the network fee.
+func (d *DEXAggregator) selectBestQuote(quotes []*SwapQuote, netOfFees bool) *SwapQuote {
 	if len(quotes) == 1 {
 		return quotes[0]
 	}
@@ -150,8 +367,8 @@ func (d *DEXAggregator) selectBestQuote(quotes []*SwapQuote) *SwapQuote {
 	// Sort quotes by output amount (descending) and provider priority
 	sort.Slice(quotes, func(i, j int) bool {
 		// Parse output amounts for comparison
-		amountI, errI := strconv.ParseFloat(quotes[i].ToAmount, 64)
-		amountJ, errJ := strconv.ParseFloat(quotes[j].ToAmount, 64)
+		amountI, errI := strconv.ParseFloat(rankedOutput(quotes[i], netOfFees), 64)
+		amountJ, errJ := strconv.ParseFloat(rankedOutput(quotes[j], netOfFees), 64)
 		
 		if errI != nil || errJ != nil {
 			// Fallback to provider priority if amount parsing fails
@@ -159,7 +376,7 @@ func (d *DEXAggregator) selectBestQuote(quotes []*SwapQuote) *SwapQuote {
 		}
 		
 		// If amounts are very close (within 0.1%), use provider priority
-		if abs(amountI-amountJ)/amountI < 0.001 {
+		if abs(amountI-amountJ)/amountI < quoteTieTolerance {
 			return d.getProviderPriority(quotes[i].Provider) > d.getProviderPriority(quotes[j].Provider)
 		}
 		
@@ -169,6 +386,66 @@ func (d *DEXAggregator) selectBestQuote(quotes []*SwapQuote) *SwapQuote {
 	return quotes[0]
 }
 
+// nativeTokens lists the identifiers of each chain's native token
+var nativeTokens = map[string][]string{
+	"1":   {"ETH", "0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"},
+	"56":  {"BNB", "0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"},
+	"137": {"MATIC", "0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"},
+	"501": {"SOL", "So11111111111111111111111111111111111111112"},
+}
+
+// isNativeToken reports whether token names the native token of chainID
+func isNativeToken(chainID, token string) bool {
+	for _, native := range nativeTokens[chainID] {
+		if strings.EqualFold(token, native) {
+			return true
+		}
+	}
+	return false
+}
+
+// networkFeeInToToken values the quote's network fee in to_token units. A fee
+// the provider did not value itself is converted when either side of the swap
+// is the native token, using the quote's own rate for native inputs.
+func networkFeeInToToken(quote *SwapQuote, chainID string) (float64, bool) {
+	if quote.Fees == nil {
+		return 0, false
+	}
+	if quote.Fees.NetworkFeeToToken != "" {
+		fee, err := strconv.ParseFloat(quote.Fees.NetworkFeeToToken, 64)
+		return fee, err == nil
+	}
+	fee, err := strconv.ParseFloat(quote.Fees.NetworkFee, 64)
+	if err != nil {
+		return 0, false
+	}
+	switch {
+	case isNativeToken(chainID, quote.ToToken):
+		return fee, true
+	case isNativeToken(chainID, quote.FromToken):
+		fromAmount, errFrom := strconv.ParseFloat(quote.FromAmount, 64)
+		toAmount, errTo := strconv.ParseFloat(quote.ToAmount, 64)
+		if errFrom != nil || errTo != nil || fromAmount <= 0 {
+			return 0, false
+		}
+		return fee * toAmount / fromAmount, true
+	}
+	return 0, false
+}
+
+// setNetToAmount fills quote.NetToAmount when its network fee can be valued in to_token
+func setNetToAmount(quote *SwapQuote, chainID string) {
+	fee, ok := networkFeeInToToken(quote, chainID)
+	if !ok {
+		return
+	}
+	toAmount, err := strconv.ParseFloat(quote.ToAmount, 64)
+	if err != nil {
+		return
+	}
+	quote.NetToAmount = strconv.FormatFloat(toAmount-fee, 'f', -1, 64)
+}
+
 // getProviderPriority returns the priority of a provider
 func (d *DEXAggregator) getProviderPriority(providerName string) int {
 	if config, exists := d.configs[providerName]; exists {
@@ -185,10 +462,47 @@ func abs(x float64) float64 {
 	return x
 }
 
-// ExecuteSwapWithProvider executes swap using a specific provider
+// SetPartialFillPolicy sets how swaps that cannot be filled in full are
+// handled, PartialFillRevert or PartialFillReturnLeftover
+func (d *DEXAggregator) SetPartialFillPolicy(policy string) error {
+	if !IsValidPartialFillPolicy(policy) {
+		return fmt.Errorf("unknown partial fill policy %q", policy)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.partialFill = policy
+	return nil
+}
+
+// SetSwapAuthorizer makes every swap executed through the aggregator wait for
+// authorize, e.g. the wallet's signing policies; nil removes the check
+func (d *DEXAggregator) SetSwapAuthorizer(authorize SwapAuthorizer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.authorize = authorize
+}
+
+// SetPairPolicy makes quotes and swaps involving a token policy refuses fail
+// with ErrPairDenied before any provider is asked; nil removes the check
+func (d *DEXAggregator) SetPairPolicy(policy *PairPolicy) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pairPolicy = policy
+}
+
+// ExecuteSwapWithProvider executes swap using a specific provider. Partial
+// fills are allowed when the caller asks for them or the partial fill policy
+// returns the leftover; the result reports the filled and leftover input. A
+// provider that fills only part of a swap that was not allowed to is
+// reported with ErrPartialFill alongside the result, since the swap happened.
 func (d *DEXAggregator) ExecuteSwapWithProvider(ctx context.Context, providerName string, params SwapParams) (*SwapResult, error) {
 	d.mu.RLock()
 	provider, exists := d.providers[providerName]
+	if d.partialFill == PartialFillReturnLeftover {
+		params.AllowPartialFill = true
+	}
+	authorize := d.authorize
+	pairPolicy := d.pairPolicy
 	d.mu.RUnlock()
 
 	if !exists {
@@ -199,6 +513,23 @@ func (d *DEXAggregator) ExecuteSwapWithProvider(ctx context.Context, providerNam
 		return nil, fmt.Errorf("provider %s does not support chain %s", providerName, params.ChainID)
 	}
 
+	if pairPolicy != nil {
+		if err := pairPolicy.Check(params); err != nil {
+			d.logger.Warn("Swap refused before execution",
+				zap.String("provider", providerName),
+				zap.Error(err))
+			return nil, err
+		}
+	}
+	if authorize != nil {
+		if err := authorize(ctx, chainNameForID(params.ChainID), params); err != nil {
+			d.logger.Warn("Swap refused before execution",
+				zap.String("provider", providerName),
+				zap.Error(err))
+			return nil, err
+		}
+	}
+
 	d.logger.Info("Executing swap with provider", 
 		zap.String("provider", providerName),
 		zap.String("fromToken", params.FromToken),
@@ -213,6 +544,22 @@ func (d *DEXAggregator) ExecuteSwapWithProvider(ctx context.Context, providerNam
 		return nil, err
 	}
 
+	if err := checkFill(params, result); err != nil {
+		d.logger.Error("Provider partially filled a swap that had to fill in full",
+			zap.String("provider", providerName),
+			zap.String("txHash", result.TxHash),
+			zap.Error(err))
+		return result, err
+	}
+	if result.PartialFill {
+		d.logger.Warn("Swap partially filled",
+			zap.String("provider", providerName),
+			zap.String("txHash", result.TxHash),
+			zap.String("requested", result.RequestedFromAmount),
+			zap.String("filled", result.FromAmount),
+			zap.String("leftover", result.LeftoverFromAmount))
+	}
+
 	d.logger.Info("Swap executed successfully", 
 		zap.String("provider", providerName),
 		zap.String("txHash", result.TxHash))
@@ -3,7 +3,10 @@ package dex
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"go.uber.org/zap/zaptest"
 )
@@ -206,6 +209,173 @@ func TestDEXAggregator_GetBestQuote(t *testing.T) {
 	}
 }
 
+func TestDEXAggregator_GetBestQuote_NetOfFees(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	aggregator := NewDEXAggregator(logger)
+
+	// Provider1 quotes the most USDT but routes through a gas-heavy path
+	provider1 := NewMockProvider("Provider1", []string{"1"})
+	provider1.quoteResponse.ToAmount = "3100.0"
+	provider1.quoteResponse.Fees = &FeeBreakdown{ProtocolFee: "9.3", AggregatorFee: "0", NetworkFee: "0.05"}
+
+	provider2 := NewMockProvider("Provider2", []string{"1"})
+	provider2.quoteResponse.ToAmount = "3050.0"
+	provider2.quoteResponse.Fees = &FeeBreakdown{ProtocolFee: "0", AggregatorFee: "2.6", NetworkFee: "0.005"}
+
+	aggregator.RegisterProvider(provider1)
+	aggregator.RegisterProvider(provider2)
+
+	params := SwapParams{
+		FromToken:   "ETH",
+		ToToken:     "USDT",
+		Amount:      "1.0",
+		Slippage:    0.005,
+		FromAddress: "0x742d35Cc6673C4C5f9aB9e3Be0A78a19a4B43c89",
+		ChainID:     "1",
+	}
+
+	quote, err := aggregator.GetBestQuote(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Failed to get best quote: %v", err)
+	}
+	if quote.Provider != "Provider1" {
+		t.Errorf("Expected best gross quote from Provider1, got %s", quote.Provider)
+	}
+
+	// 0.05 ETH of gas at 3100 USDT/ETH costs 155 USDT, 0.005 ETH at 3050 only 15.25
+	params.CompareNetOfFees = true
+	quote, err = aggregator.GetBestQuote(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Failed to get best quote: %v", err)
+	}
+	if quote.Provider != "Provider2" {
+		t.Errorf("Expected best net quote from Provider2, got %s", quote.Provider)
+	}
+	if quote.NetToAmount != "3034.75" {
+		t.Errorf("Expected NetToAmount 3034.75, got %s", quote.NetToAmount)
+	}
+	if quote.Fees == nil || quote.Fees.AggregatorFee != "2.6" {
+		t.Errorf("Expected the fee breakdown to be surfaced, got %+v", quote.Fees)
+	}
+}
+
+// slowProvider quotes like MockProvider after delay, ignoring its context
+type slowProvider struct {
+	*MockProvider
+	delay time.Duration
+}
+
+func (p *slowProvider) GetQuote(ctx context.Context, params SwapParams) (*SwapQuote, error) {
+	time.Sleep(p.delay)
+	return p.MockProvider.GetQuote(ctx, params)
+}
+
+func TestDEXAggregator_GetBestQuote_SlowProviderExcluded(t *testing.T) {
+	aggregator := NewDEXAggregatorWithFanOut(zaptest.NewLogger(t), QuoteFanOut{
+		ProviderTimeout: 50 * time.Millisecond,
+		Deadline:        time.Second,
+	})
+
+	fast := NewMockProvider("Fast", []string{"1"})
+	fast.quoteResponse.ToAmount = "3000.0"
+	// The slow provider would win on price but answers too late
+	slow := &slowProvider{MockProvider: NewMockProvider("Slow", []string{"1"}), delay: 2 * time.Second}
+	slow.quoteResponse.ToAmount = "3500.0"
+	aggregator.RegisterProvider(fast)
+	aggregator.RegisterProvider(slow)
+
+	started := time.Now()
+	quote, err := aggregator.GetBestQuote(context.Background(), SwapParams{
+		FromToken:   "ETH",
+		ToToken:     "USDT",
+		Amount:      "1.0",
+		FromAddress: "0x742d35Cc6673C4C5f9aB9e3Be0A78a19a4B43c89",
+		ChainID:     "1",
+	})
+	if err != nil {
+		t.Fatalf("Failed to get best quote: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("Expected the slow provider not to delay the quote, took %s", elapsed)
+	}
+	if quote.Provider != "Fast" {
+		t.Errorf("Expected best quote from Fast, got %s", quote.Provider)
+	}
+	if len(quote.TimedOutProviders) != 1 || quote.TimedOutProviders[0] != "Slow" {
+		t.Errorf("Expected Slow to be reported as timed out, got %v", quote.TimedOutProviders)
+	}
+
+	// Once every provider times out there is no quote to return
+	aggregator = NewDEXAggregatorWithFanOut(zaptest.NewLogger(t), QuoteFanOut{Deadline: 50 * time.Millisecond})
+	aggregator.RegisterProvider(slow)
+	if _, err := aggregator.GetBestQuote(context.Background(), SwapParams{ChainID: "1"}); err == nil {
+		t.Error("Expected an error when every provider timed out")
+	}
+}
+
+// countingProvider records how many of its quotes run at the same time
+type countingProvider struct {
+	*MockProvider
+	mu      *sync.Mutex
+	running *int
+	peak    *int
+}
+
+func (p *countingProvider) GetQuote(ctx context.Context, params SwapParams) (*SwapQuote, error) {
+	p.mu.Lock()
+	*p.running++
+	if *p.running > *p.peak {
+		*p.peak = *p.running
+	}
+	p.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	p.mu.Lock()
+	*p.running--
+	p.mu.Unlock()
+	return p.MockProvider.GetQuote(ctx, params)
+}
+
+func TestDEXAggregator_GetBestQuote_MaxConcurrency(t *testing.T) {
+	aggregator := NewDEXAggregatorWithFanOut(zaptest.NewLogger(t), QuoteFanOut{MaxConcurrency: 2})
+	var mu sync.Mutex
+	var running, peak int
+	for _, name := range []string{"P1", "P2", "P3", "P4", "P5"} {
+		aggregator.RegisterProvider(&countingProvider{MockProvider: NewMockProvider(name, []string{"1"}), mu: &mu, running: &running, peak: &peak})
+	}
+
+	if _, err := aggregator.GetBestQuote(context.Background(), SwapParams{ChainID: "1"}); err != nil {
+		t.Fatalf("Failed to get best quote: %v", err)
+	}
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent quotes, got %d", peak)
+	}
+}
+
+func TestNetworkFeeInToToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		quote SwapQuote
+		fee   float64
+		ok    bool
+	}{
+		{"provider valued", SwapQuote{ToToken: "USDT", Fees: &FeeBreakdown{NetworkFee: "0.01", NetworkFeeToToken: "30"}}, 30, true},
+		{"native output", SwapQuote{FromToken: "USDT", ToToken: "eth", Fees: &FeeBreakdown{NetworkFee: "0.01"}}, 0.01, true},
+		{"native input", SwapQuote{FromToken: "ETH", ToToken: "USDT", FromAmount: "2", ToAmount: "6000", Fees: &FeeBreakdown{NetworkFee: "0.01"}}, 30, true},
+		{"token to token", SwapQuote{FromToken: "USDC", ToToken: "USDT", Fees: &FeeBreakdown{NetworkFee: "0.01"}}, 0, false},
+		{"no fees", SwapQuote{FromToken: "ETH", ToToken: "USDT"}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee, ok := networkFeeInToToken(&tt.quote, "1")
+			if ok != tt.ok || abs(fee-tt.fee) > 1e-9 {
+				t.Errorf("Expected (%v, %v), got (%v, %v)", tt.fee, tt.ok, fee, ok)
+			}
+		})
+	}
+}
+
 func TestDEXAggregator_GetBestQuote_NoProviders(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 	aggregator := NewDEXAggregator(logger)
@@ -276,6 +446,30 @@ func TestDEXAggregator_ExecuteSwapWithProvider_NonExistentProvider(t *testing.T)
 	}
 }
 
+func TestDEXAggregator_ExecuteSwapWithProvider_Authorizer(t *testing.T) {
+	aggregator := NewDEXAggregator(zaptest.NewLogger(t))
+	aggregator.RegisterProvider(NewMockProvider("Mock", []string{"1"}))
+	denied := errors.New("denied by policy")
+	var chainName string
+	aggregator.SetSwapAuthorizer(func(ctx context.Context, name string, params SwapParams) error {
+		chainName = name
+		return denied
+	})
+
+	result, err := aggregator.ExecuteSwapWithProvider(context.Background(), "Mock", decisionParams())
+	if !errors.Is(err, denied) || result != nil {
+		t.Fatalf("Expected the authorizer to refuse the swap, got %+v, %v", result, err)
+	}
+	if chainName != "ethereum" {
+		t.Errorf("Expected the authorizer to be told the chain name, got %q", chainName)
+	}
+
+	aggregator.SetSwapAuthorizer(nil)
+	if _, err := aggregator.ExecuteSwapWithProvider(context.Background(), "Mock", decisionParams()); err != nil {
+		t.Errorf("Expected the swap to run without an authorizer, got %v", err)
+	}
+}
+
 func TestDEXAggregator_GetProviderByName(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 	aggregator := NewDEXAggregator(logger)
@@ -297,4 +491,57 @@ func TestDEXAggregator_GetProviderByName(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error when getting non-existent provider")
 	}
-}
\ No newline at end of file
+}
+func TestDEXAggregator_AllProviderRegistrationsFail(t *testing.T) {
+	aggregator := NewDEXAggregator(zaptest.NewLogger(t))
+
+	if err := aggregator.RegisterProvider(nil); err == nil {
+		t.Error("Expected nil provider to be rejected")
+	}
+	if err := aggregator.RegisterProvider(NewMockProvider("  ", []string{"1"})); err == nil {
+		t.Error("Expected unnamed provider to be rejected")
+	}
+	if err := aggregator.RegisterProviderWithConfig(NewMockProvider("Configless", []string{"1"}), nil); err == nil {
+		t.Error("Expected provider without config to be rejected")
+	}
+
+	for chainName, providers := range ActiveProviders(aggregator) {
+		if len(providers) != 0 {
+			t.Errorf("Expected no active providers for %s, got %v", chainName, providers)
+		}
+	}
+
+	_, err := aggregator.GetBestQuote(context.Background(), SwapParams{
+		FromToken:   "ETH",
+		ToToken:     "USDT",
+		Amount:      "1.0",
+		Slippage:    0.005,
+		FromAddress: "0x742d35Cc6673C4C5f9aB9e3Be0A78a19a4B43c89",
+		ToAddress:   "0x742d35Cc6673C4C5f9aB9e3Be0A78a19a4B43c89",
+		ChainID:     "1",
+	})
+	if !errors.Is(err, ErrNoProvidersAvailable) {
+		t.Fatalf("Expected ErrNoProvidersAvailable, got %v", err)
+	}
+	if err.Error() != "no DEX providers available for chain ethereum" {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+}
+
+func TestActiveProviders(t *testing.T) {
+	aggregator := NewDEXAggregator(zaptest.NewLogger(t))
+	if err := aggregator.RegisterProvider(NewMockProvider("SolanaOnly", []string{"501"})); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	if err := aggregator.RegisterProviderWithConfig(NewMockProvider("Disabled", []string{"1"}), &DEXProviderConfig{Enabled: false}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	active := ActiveProviders(aggregator)
+	if len(active["solana"]) != 1 || active["solana"][0] != "SolanaOnly" {
+		t.Errorf("Expected SolanaOnly on solana, got %v", active["solana"])
+	}
+	if len(active["ethereum"]) != 0 {
+		t.Errorf("Expected disabled provider to be inactive, got %v", active["ethereum"])
+	}
+}
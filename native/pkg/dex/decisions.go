@@ -0,0 +1,277 @@
+// SPDX-License-Identifier: Apache-2.0
+package dex
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultDecisionHistory is how many quote decisions an aggregator keeps for
+// inspection when no other size is set
+const DefaultDecisionHistory = 50
+
+// quoteTieTolerance is the relative output difference under which quotes are
+// ranked by provider priority instead of by output
+const quoteTieTolerance = 0.001
+
+// Strategies GetBestQuote ranks quotes by
+const (
+	StrategyBestOutput    = "best_output"     // highest to_token output
+	StrategyBestNetOutput = "best_net_output" // highest output after the network fee
+)
+
+// Statuses of one provider's answer within a quote decision
+const (
+	ProviderQuoteOK       = "ok"
+	ProviderQuoteFailed   = "failed"
+	ProviderQuoteTimedOut = "timed_out"
+)
+
+// Health of a provider, judged from the recorded decisions
+const (
+	ProviderHealthy  = "healthy"  // answered every recorded request
+	ProviderDegraded = "degraded" // failed or timed out on some requests
+	ProviderFailing  = "failing"  // failed or timed out on most requests
+	ProviderUnknown  = "unknown"  // not asked in any recorded decision
+)
+
+// ProviderQuote is one provider's answer to a best-quote request
+type ProviderQuote struct {
+	Provider    string        `json:"provider"`
+	Priority    int           `json:"priority"`
+	Status      string        `json:"status"`
+	ToAmount    string        `json:"to_amount,omitempty"`
+	NetToAmount string        `json:"net_to_amount,omitempty"`
+	Fees        *FeeBreakdown `json:"fees,omitempty"`
+	Latency     time.Duration `json:"latency"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// QuoteDecision records how GetBestQuote picked its quote. Quotes lists every
+// provider asked: the valid quotes in rank order, then the failures.
+type QuoteDecision struct {
+	ID        uint64          `json:"id"`
+	Timestamp time.Time       `json:"timestamp"`
+	ChainID   string          `json:"chain_id"`
+	FromToken string          `json:"from_token"`
+	ToToken   string          `json:"to_token"`
+	Amount    string          `json:"amount"`
+	Strategy  string          `json:"strategy"`
+	Winner    string          `json:"winner,omitempty"` // empty when no provider returned a valid quote
+	Reason    string          `json:"reason"`
+	Quotes    []ProviderQuote `json:"quotes"`
+}
+
+// ProviderHealth summarizes a registered provider and how it fared in the
+// recorded decisions
+type ProviderHealth struct {
+	Provider       string        `json:"provider"`
+	Enabled        bool          `json:"enabled"`
+	Priority       int           `json:"priority"`
+	Status         string        `json:"status"`
+	Requests       int           `json:"requests"`
+	Failures       int           `json:"failures"`
+	TimedOut       int           `json:"timed_out"`
+	Wins           int           `json:"wins"`
+	AverageLatency time.Duration `json:"average_latency"` // of the requests it answered
+	LastError      string        `json:"last_error,omitempty"`
+}
+
+// RoutingStrategy describes how an aggregator currently selects quotes
+type RoutingStrategy struct {
+	Default         string      `json:"default"`       // strategy unless the caller asks for net-of-fees ranking
+	TieTolerance    float64     `json:"tie_tolerance"` // relative output difference ranked by priority instead
+	FanOut          QuoteFanOut `json:"fan_out"`
+	DecisionHistory int         `json:"decision_history"`
+}
+
+// QuoteDecisionInspector is implemented by aggregators that record their
+// best-quote decisions for post-hoc inspection
+type QuoteDecisionInspector interface {
+	// RoutingStrategy returns the active selection strategy
+	RoutingStrategy() RoutingStrategy
+
+	// RecentDecisions returns up to limit recorded decisions, newest first;
+	// limit <= 0 returns them all
+	RecentDecisions(limit int) []QuoteDecision
+
+	// ProviderHealth returns every registered provider, sorted by name
+	ProviderHealth() []ProviderHealth
+}
+
+// decisionLog is a ring buffer of the most recent quote decisions
+type decisionLog struct {
+	mu      sync.Mutex
+	entries []QuoteDecision
+	next    int // slot the next decision is written to
+	count   int
+	lastID  uint64
+}
+
+// newDecisionLog creates a log keeping size decisions
+func newDecisionLog(size int) *decisionLog {
+	if size <= 0 {
+		size = DefaultDecisionHistory
+	}
+	return &decisionLog{entries: make([]QuoteDecision, size)}
+}
+
+// record stores decision, overwriting the oldest one when the log is full
+func (l *decisionLog) record(decision QuoteDecision) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lastID++
+	decision.ID = l.lastID
+	l.entries[l.next] = decision
+	l.next = (l.next + 1) % len(l.entries)
+	if l.count < len(l.entries) {
+		l.count++
+	}
+}
+
+// recent returns up to limit decisions, newest first
+func (l *decisionLog) recent(limit int) []QuoteDecision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if limit <= 0 || limit > l.count {
+		limit = l.count
+	}
+	decisions := make([]QuoteDecision, 0, limit)
+	for i := 1; i <= limit; i++ {
+		decisions = append(decisions, l.entries[(l.next-i+len(l.entries))%len(l.entries)])
+	}
+	return decisions
+}
+
+// size returns how many decisions the log keeps
+func (l *decisionLog) size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
+
+// rankedOutput returns the amount quotes are ranked by
+func rankedOutput(quote *SwapQuote, netOfFees bool) string {
+	if netOfFees && quote.NetToAmount != "" {
+		return quote.NetToAmount
+	}
+	return quote.ToAmount
+}
+
+// selectionReason explains why ranked[0] won over the runner-up
+func (d *DEXAggregator) selectionReason(ranked []*SwapQuote, netOfFees bool) string {
+	if len(ranked) == 1 {
+		return "only provider to return a valid quote"
+	}
+	winner, runnerUp := ranked[0], ranked[1]
+	output := "output"
+	if netOfFees {
+		output = "output net of the network fee"
+	}
+	winnerAmount, errWinner := strconv.ParseFloat(rankedOutput(winner, netOfFees), 64)
+	runnerUpAmount, errRunnerUp := strconv.ParseFloat(rankedOutput(runnerUp, netOfFees), 64)
+	if errWinner != nil || errRunnerUp != nil {
+		return fmt.Sprintf("%s amounts could not be compared, preferred by provider priority (%d vs %d for %s)",
+			output, d.getProviderPriority(winner.Provider), d.getProviderPriority(runnerUp.Provider), runnerUp.Provider)
+	}
+	if abs(winnerAmount-runnerUpAmount)/winnerAmount < quoteTieTolerance {
+		return fmt.Sprintf("%s within %g%% of %s, preferred by provider priority (%d vs %d)",
+			output, quoteTieTolerance*100, runnerUp.Provider, d.getProviderPriority(winner.Provider), d.getProviderPriority(runnerUp.Provider))
+	}
+	return fmt.Sprintf("highest %s: %s vs %s from %s",
+		output, rankedOutput(winner, netOfFees), rankedOutput(runnerUp, netOfFees), runnerUp.Provider)
+}
+
+// SetDecisionHistory changes how many quote decisions are kept, dropping the
+// ones recorded so far
+func (d *DEXAggregator) SetDecisionHistory(size int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.decisions = newDecisionLog(size)
+}
+
+// RoutingStrategy returns the active selection strategy
+func (d *DEXAggregator) RoutingStrategy() RoutingStrategy {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return RoutingStrategy{
+		Default:         StrategyBestOutput,
+		TieTolerance:    quoteTieTolerance,
+		FanOut:          d.fanOut,
+		DecisionHistory: d.decisions.size(),
+	}
+}
+
+// RecentDecisions returns up to limit recorded decisions, newest first
+func (d *DEXAggregator) RecentDecisions(limit int) []QuoteDecision {
+	d.mu.RLock()
+	log := d.decisions
+	d.mu.RUnlock()
+	return log.recent(limit)
+}
+
+// ProviderHealth returns every registered provider with its record in the
+// decisions kept, sorted by name
+func (d *DEXAggregator) ProviderHealth() []ProviderHealth {
+	d.mu.RLock()
+	health := make(map[string]*ProviderHealth, len(d.providers))
+	for name := range d.providers {
+		enabled := true
+		if config, exists := d.configs[name]; exists {
+			enabled = config.Enabled
+		}
+		health[name] = &ProviderHealth{Provider: name, Enabled: enabled, Priority: d.getProviderPriority(name)}
+	}
+	log := d.decisions
+	d.mu.RUnlock()
+
+	latency := make(map[string]time.Duration)
+	// Walk oldest to newest so LastError is the most recent one
+	decisions := log.recent(0)
+	for i := len(decisions) - 1; i >= 0; i-- {
+		for _, quote := range decisions[i].Quotes {
+			h, ok := health[quote.Provider]
+			if !ok {
+				continue
+			}
+			h.Requests++
+			switch quote.Status {
+			case ProviderQuoteFailed:
+				h.Failures++
+				h.LastError = quote.Error
+			case ProviderQuoteTimedOut:
+				h.TimedOut++
+				h.LastError = quote.Error
+			default:
+				latency[quote.Provider] += quote.Latency
+			}
+		}
+		if h, ok := health[decisions[i].Winner]; ok {
+			h.Wins++
+		}
+	}
+
+	result := make([]ProviderHealth, 0, len(health))
+	for name, h := range health {
+		unanswered := h.Failures + h.TimedOut
+		switch {
+		case h.Requests == 0:
+			h.Status = ProviderUnknown
+		case unanswered == 0:
+			h.Status = ProviderHealthy
+		case unanswered*2 < h.Requests:
+			h.Status = ProviderDegraded
+		default:
+			h.Status = ProviderFailing
+		}
+		if answered := h.Requests - unanswered; answered > 0 {
+			h.AverageLatency = latency[name] / time.Duration(answered)
+		}
+		result = append(result, *h)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Provider < result[j].Provider })
+	return result
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+package dex
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func decisionParams() SwapParams {
+	return SwapParams{
+		FromToken:   "ETH",
+		ToToken:     "USDT",
+		Amount:      "1.0",
+		FromAddress: "0x742d35Cc6673C4C5f9aB9e3Be0A78a19a4B43c89",
+		ChainID:     "1",
+	}
+}
+
+func TestDEXAggregator_RecordsQuoteDecisions(t *testing.T) {
+	aggregator := NewDEXAggregator(zaptest.NewLogger(t))
+	best := NewMockProvider("Best", []string{"1"})
+	best.quoteResponse.ToAmount = "3100.0"
+	worse := NewMockProvider("Worse", []string{"1"})
+	broken := NewMockProvider("Broken", []string{"1"})
+	broken.SetShouldFail(true)
+	for _, provider := range []*MockProvider{best, worse, broken} {
+		if err := aggregator.RegisterProvider(provider); err != nil {
+			t.Fatalf("Failed to register provider: %v", err)
+		}
+	}
+
+	if _, err := aggregator.GetBestQuote(context.Background(), decisionParams()); err != nil {
+		t.Fatalf("Failed to get best quote: %v", err)
+	}
+
+	decisions := aggregator.RecentDecisions(0)
+	if len(decisions) != 1 {
+		t.Fatalf("Expected 1 decision, got %d", len(decisions))
+	}
+	decision := decisions[0]
+	if decision.Winner != "Best" || decision.Strategy != StrategyBestOutput {
+		t.Errorf("Expected Best to win by best_output, got %+v", decision)
+	}
+	if !strings.Contains(decision.Reason, "highest output: 3100.0 vs 3000.0 from Worse") {
+		t.Errorf("Unexpected reason %q", decision.Reason)
+	}
+	if len(decision.Quotes) != 3 {
+		t.Fatalf("Expected every provider in the decision, got %+v", decision.Quotes)
+	}
+	if decision.Quotes[0].Provider != "Best" || decision.Quotes[1].Provider != "Worse" || decision.Quotes[2].Status != ProviderQuoteFailed {
+		t.Errorf("Expected ranked quotes followed by the failure, got %+v", decision.Quotes)
+	}
+	if decision.Quotes[2].Error == "" {
+		t.Error("Expected the failure to carry its error")
+	}
+
+	health := aggregator.ProviderHealth()
+	if len(health) != 3 || health[0].Provider != "Best" || health[1].Provider != "Broken" {
+		t.Fatalf("Expected providers sorted by name, got %+v", health)
+	}
+	if health[0].Status != ProviderHealthy || health[0].Wins != 1 || health[0].Requests != 1 {
+		t.Errorf("Unexpected health for Best: %+v", health[0])
+	}
+	if health[1].Status != ProviderFailing || health[1].Failures != 1 || health[1].LastError == "" {
+		t.Errorf("Unexpected health for Broken: %+v", health[1])
+	}
+}
+
+func TestDEXAggregator_DecisionReasons(t *testing.T) {
+	aggregator := NewDEXAggregator(zaptest.NewLogger(t))
+	preferred := NewMockProvider("Preferred", []string{"1"})
+	preferred.quoteResponse.ToAmount = "3000.0"
+	other := NewMockProvider("Other", []string{"1"})
+	other.quoteResponse.ToAmount = "3001.0"
+	aggregator.RegisterProviderWithConfig(preferred, &DEXProviderConfig{Name: "Preferred", Enabled: true, Priority: 10})
+	aggregator.RegisterProviderWithConfig(other, &DEXProviderConfig{Name: "Other", Enabled: true, Priority: 1})
+
+	params := decisionParams()
+	params.CompareNetOfFees = true
+	if _, err := aggregator.GetBestQuote(context.Background(), params); err != nil {
+		t.Fatalf("Failed to get best quote: %v", err)
+	}
+	decision := aggregator.RecentDecisions(1)[0]
+	if decision.Winner != "Preferred" || decision.Strategy != StrategyBestNetOutput {
+		t.Errorf("Expected the priority tie-break to pick Preferred, got %+v", decision)
+	}
+	if !strings.Contains(decision.Reason, "preferred by provider priority (10 vs 1)") {
+		t.Errorf("Unexpected reason %q", decision.Reason)
+	}
+
+	// A request nobody can answer is recorded without a winner
+	preferred.SetShouldFail(true)
+	other.SetShouldFail(true)
+	if _, err := aggregator.GetBestQuote(context.Background(), decisionParams()); err == nil {
+		t.Fatal("Expected an error when every provider fails")
+	}
+	decision = aggregator.RecentDecisions(1)[0]
+	if decision.Winner != "" || decision.Reason != "no provider returned a valid quote" || len(decision.Quotes) != 2 {
+		t.Errorf("Unexpected failed decision %+v", decision)
+	}
+}
+
+func TestDEXAggregator_DecisionHistoryIsBounded(t *testing.T) {
+	aggregator := NewDEXAggregator(zaptest.NewLogger(t))
+	aggregator.SetDecisionHistory(3)
+	aggregator.RegisterProvider(NewMockProvider("Only", []string{"1"}))
+
+	for i := 0; i < 5; i++ {
+		if _, err := aggregator.GetBestQuote(context.Background(), decisionParams()); err != nil {
+			t.Fatalf("Failed to get best quote: %v", err)
+		}
+	}
+
+	decisions := aggregator.RecentDecisions(0)
+	if len(decisions) != 3 {
+		t.Fatalf("Expected the history to keep 3 decisions, got %d", len(decisions))
+	}
+	for i, id := range []uint64{5, 4, 3} {
+		if decisions[i].ID != id {
+			t.Errorf("Expected decision %d at position %d, got %d", id, i, decisions[i].ID)
+		}
+	}
+	if decisions[0].Reason != "only provider to return a valid quote" {
+		t.Errorf("Unexpected reason %q", decisions[0].Reason)
+	}
+	if got := aggregator.RecentDecisions(2); len(got) != 2 || got[0].ID != 5 {
+		t.Errorf("Expected the 2 newest decisions, got %+v", got)
+	}
+	if strategy := aggregator.RoutingStrategy(); strategy.DecisionHistory != 3 || strategy.Default != StrategyBestOutput {
+		t.Errorf("Unexpected strategy %+v", strategy)
+	}
+}
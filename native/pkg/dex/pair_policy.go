@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: Apache-2.0
+package dex
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"go.uber.org/zap"
+)
+
+// ErrPairDenied is returned when the pair policy refuses a token of a swap or
+// quote
+var ErrPairDenied = errors.New("token denied by the pair policy")
+
+// TokenResolver returns other names token is known by on chainName, such as
+// the contract a listed symbol resolves to, so that policy entries match
+// however the token is named. Only trusted token lists should be consulted:
+// an allowlist entry must never match a look-alike token.
+type TokenResolver func(chainName, token string) []string
+
+// pairPolicyEntry is a token the policy lists, on chain or, when chain is
+// empty, on every chain
+type pairPolicyEntry struct {
+	chain string
+	token string // as returned by policyTokenKey
+}
+
+// blocklistFile is a token blocklist file as it was last read
+type blocklistFile struct {
+	path    string
+	modTime time.Time
+	size    int64
+	entries []pairPolicyEntry
+}
+
+// PairPolicy allows or denies swaps and quotes by the tokens they involve. It
+// is safe for concurrent use, and Update and the blocklist files change it
+// while the host runs.
+type PairPolicy struct {
+	mu      sync.RWMutex
+	allow   []pairPolicyEntry
+	deny    []pairPolicyEntry
+	files   []*blocklistFile
+	resolve TokenResolver
+	logger  *zap.Logger
+}
+
+// NewPairPolicy creates the pair policy cfg configures and reads its
+// blocklist files
+func NewPairPolicy(cfg config.PairPolicyConfig, logger *zap.Logger) *PairPolicy {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	p := &PairPolicy{logger: logger}
+	p.Update(cfg)
+	return p
+}
+
+// Update replaces the policy's lists with cfg's, re-reading the blocklist files
+func (p *PairPolicy) Update(cfg config.PairPolicyConfig) {
+	files := make([]*blocklistFile, 0, len(cfg.BlocklistFiles))
+	for _, path := range cfg.BlocklistFiles {
+		files = append(files, &blocklistFile{path: strings.TrimSpace(path)})
+	}
+
+	p.mu.Lock()
+	p.allow = parsePairPolicyEntries(cfg.AllowTokens)
+	p.deny = parsePairPolicyEntries(cfg.DenyTokens)
+	p.files = files
+	p.mu.Unlock()
+	p.reloadBlocklists()
+}
+
+// SetTokenResolver sets how tokens are matched against entries naming them
+// differently; without one, tokens only match entries naming them the same way
+func (p *PairPolicy) SetTokenResolver(resolve TokenResolver) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resolve = resolve
+}
+
+// Check returns an error wrapping ErrPairDenied that names the first token of
+// params the policy refuses: one that is denied or blocklisted, or, when an
+// allowlist is set, one that is not on it
+func (p *PairPolicy) Check(params SwapParams) error {
+	p.reloadBlocklists()
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	chainName := chainNameForID(params.ChainID)
+	for _, token := range []string{params.FromToken, params.ToToken} {
+		names := p.tokenNames(params.ChainID, chainName, token)
+		if matchPairPolicy(p.deny, chainName, names) {
+			return fmt.Errorf("%w: %s is on the deny list", ErrPairDenied, token)
+		}
+		for _, file := range p.files {
+			if matchPairPolicy(file.entries, chainName, names) {
+				return fmt.Errorf("%w: %s is on the blocklist %s", ErrPairDenied, token, file.path)
+			}
+		}
+		if len(p.allow) > 0 && !matchPairPolicy(p.allow, chainName, names) {
+			return fmt.Errorf("%w: %s is not on the allow list", ErrPairDenied, token)
+		}
+	}
+	return nil
+}
+
+// tokenNames returns the keys token can be matched by (assumes lock is held)
+func (p *PairPolicy) tokenNames(chainID, chainName, token string) []string {
+	names := []string{policyTokenKey(token)}
+	if isNativeToken(chainID, token) {
+		names = append(names, "native")
+	}
+	if p.resolve != nil {
+		for _, name := range p.resolve(chainName, token) {
+			names = append(names, policyTokenKey(name))
+		}
+	}
+	return names
+}
+
+// reloadBlocklists re-reads the blocklist files that changed since they were
+// last read. A file that cannot be read keeps its previous entries.
+func (p *PairPolicy) reloadBlocklists() {
+	p.mu.RLock()
+	files := p.files
+	p.mu.RUnlock()
+
+	for _, file := range files {
+		info, err := os.Stat(file.path)
+		if err != nil {
+			p.logger.Warn("Failed to check token blocklist", zap.String("path", file.path), zap.Error(err))
+			continue
+		}
+		p.mu.RLock()
+		unchanged := info.ModTime().Equal(file.modTime) && info.Size() == file.size
+		p.mu.RUnlock()
+		if unchanged {
+			continue
+		}
+
+		entries, err := readBlocklistFile(file.path)
+		if err != nil {
+			p.logger.Warn("Failed to read token blocklist", zap.String("path", file.path), zap.Error(err))
+			continue
+		}
+		p.mu.Lock()
+		file.modTime, file.size, file.entries = info.ModTime(), info.Size(), entries
+		p.mu.Unlock()
+		p.logger.Info("Token blocklist loaded", zap.String("path", file.path), zap.Int("tokens", len(entries)))
+	}
+}
+
+// readBlocklistFile reads one token per line, skipping blank lines and # comments
+func readBlocklistFile(path string) ([]pairPolicyEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return parsePairPolicyEntries(lines), nil
+}
+
+func parsePairPolicyEntries(tokens []string) []pairPolicyEntry {
+	entries := make([]pairPolicyEntry, 0, len(tokens))
+	for _, token := range tokens {
+		chain, name := config.SplitPairPolicyToken(token)
+		if name == "" {
+			continue
+		}
+		entries = append(entries, pairPolicyEntry{chain: chain, token: policyTokenKey(name)})
+	}
+	return entries
+}
+
+// matchPairPolicy reports whether an entry for chainName lists one of names
+func matchPairPolicy(entries []pairPolicyEntry, chainName string, names []string) bool {
+	for _, entry := range entries {
+		if entry.chain != "" && entry.chain != chainName {
+			continue
+		}
+		for _, name := range names {
+			if entry.token == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// policyTokenKey normalizes a token for matching. EVM addresses and symbols
+// are case-insensitive, but Solana mints are base58 and compared exactly.
+func policyTokenKey(token string) string {
+	token = strings.TrimSpace(token)
+	if len(token) > 11 && !strings.HasPrefix(strings.ToLower(token), "0x") {
+		return token
+	}
+	return strings.ToLower(token)
+}
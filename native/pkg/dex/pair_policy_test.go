@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+package dex
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"go.uber.org/zap/zaptest"
+)
+
+const (
+	usdtAddress = "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+	scamAddress = "0x1111111111111111111111111111111111111111"
+)
+
+func TestPairPolicy_DenyAndAllow(t *testing.T) {
+	policy := NewPairPolicy(config.PairPolicyConfig{
+		DenyTokens: []string{scamAddress, "bsc:CAKE"},
+	}, zaptest.NewLogger(t))
+
+	params := decisionParams()
+	if err := policy.Check(params); err != nil {
+		t.Fatalf("Expected ETH to USDT to be allowed, got %v", err)
+	}
+
+	// EVM addresses match whatever their case
+	params.ToToken = "0x" + strings.ToUpper(scamAddress[2:])
+	err := policy.Check(params)
+	if !errors.Is(err, ErrPairDenied) || !strings.Contains(err.Error(), params.ToToken) {
+		t.Fatalf("Expected the denied token to be named, got %v", err)
+	}
+
+	// Chain-prefixed entries only apply on that chain
+	params.ToToken = "CAKE"
+	if err := policy.Check(params); err != nil {
+		t.Errorf("Expected CAKE to be allowed on ethereum, got %v", err)
+	}
+	params.ChainID = "56"
+	if err := policy.Check(params); !errors.Is(err, ErrPairDenied) {
+		t.Errorf("Expected CAKE to be denied on bsc, got %v", err)
+	}
+
+	// With an allowlist both tokens must be listed; symbols match the
+	// addresses the resolver knows them by
+	policy.Update(config.PairPolicyConfig{AllowTokens: []string{"native", usdtAddress}})
+	policy.SetTokenResolver(func(chainName, token string) []string {
+		if chainName == "ethereum" && strings.EqualFold(token, "USDT") {
+			return []string{usdtAddress}
+		}
+		return nil
+	})
+	if err := policy.Check(decisionParams()); err != nil {
+		t.Errorf("Expected ETH to USDT to be allowed, got %v", err)
+	}
+	params = decisionParams()
+	params.ToToken = "USDC"
+	if err := policy.Check(params); !errors.Is(err, ErrPairDenied) || !strings.Contains(err.Error(), "USDC is not on the allow list") {
+		t.Errorf("Expected USDC to be refused by the allowlist, got %v", err)
+	}
+}
+
+func TestPairPolicy_BlocklistFileReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(path, []byte("# known scams\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policy := NewPairPolicy(config.PairPolicyConfig{BlocklistFiles: []string{path}}, zaptest.NewLogger(t))
+	params := decisionParams()
+	params.ToToken = scamAddress
+	if err := policy.Check(params); err != nil {
+		t.Fatalf("Expected an empty blocklist to allow the swap, got %v", err)
+	}
+
+	// Blocking a token takes effect on the next check
+	if err := os.WriteFile(path, []byte("# known scams\nethereum:"+scamAddress+" # drainer\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, time.Now(), time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	err := policy.Check(params)
+	if !errors.Is(err, ErrPairDenied) || !strings.Contains(err.Error(), "blocklist "+path) {
+		t.Fatalf("Expected the blocklisted token to be denied, got %v", err)
+	}
+
+	// A file that disappears keeps its last entries
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := policy.Check(params); !errors.Is(err, ErrPairDenied) {
+		t.Errorf("Expected the last blocklist to stay in force, got %v", err)
+	}
+}
+
+func TestDEXAggregator_PairPolicy(t *testing.T) {
+	aggregator := NewDEXAggregator(zaptest.NewLogger(t))
+	provider := NewMockProvider("Mock", []string{"1"})
+	aggregator.RegisterProvider(provider)
+	aggregator.SetPairPolicy(NewPairPolicy(config.PairPolicyConfig{DenyTokens: []string{"USDT"}}, nil))
+
+	if _, err := aggregator.GetBestQuote(context.Background(), decisionParams()); !errors.Is(err, ErrPairDenied) {
+		t.Errorf("Expected the quote to be denied, got %v", err)
+	}
+	if _, err := aggregator.ExecuteSwapWithProvider(context.Background(), "Mock", decisionParams()); !errors.Is(err, ErrPairDenied) {
+		t.Errorf("Expected the swap to be denied, got %v", err)
+	}
+	if decisions := aggregator.RecentDecisions(0); len(decisions) != 0 {
+		t.Errorf("Expected no providers to be asked, got %d decisions", len(decisions))
+	}
+
+	aggregator.SetPairPolicy(nil)
+	if _, err := aggregator.GetBestQuote(context.Background(), decisionParams()); err != nil {
+		t.Errorf("Expected the quote once the policy is removed, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+package dex
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Policies for swaps the available liquidity can only fill in part
+const (
+	// PartialFillRevert makes a swap all-or-nothing: providers revert it
+	// instead of swapping part of the input
+	PartialFillRevert = "revert"
+	// PartialFillReturnLeftover accepts a partial fill; the unfilled input
+	// stays with, or is returned to, the sender
+	PartialFillReturnLeftover = "return_leftover"
+)
+
+// ErrPartialFill is returned when a swap could not be filled in full and
+// partial fills were not allowed
+var ErrPartialFill = errors.New("swap could not be filled in full")
+
+// IsValidPartialFillPolicy reports whether policy is a known partial fill policy
+func IsValidPartialFillPolicy(policy string) bool {
+	return policy == PartialFillRevert || policy == PartialFillReturnLeftover
+}
+
+// checkFill compares the input result actually swapped with the amount
+// params requested, recording a partial fill and its leftover on result. It
+// returns ErrPartialFill when the fill is partial but was not allowed.
+func checkFill(params SwapParams, result *SwapResult) error {
+	result.RequestedFromAmount = params.Amount
+	requested, okRequested := new(big.Rat).SetString(params.Amount)
+	filled, okFilled := new(big.Rat).SetString(result.FromAmount)
+	if !okRequested || !okFilled || filled.Cmp(requested) >= 0 {
+		return nil
+	}
+
+	result.PartialFill = true
+	result.LeftoverFromAmount = formatAmountRat(new(big.Rat).Sub(requested, filled))
+	if params.AllowPartialFill {
+		return nil
+	}
+	return fmt.Errorf("%w: the provider swapped %s of the requested %s %s although partial fills are not allowed",
+		ErrPartialFill, result.FromAmount, params.Amount, params.FromToken)
+}
+
+// formatAmountRat renders a token amount without trailing zeros
+func formatAmountRat(amount *big.Rat) string {
+	text := amount.FloatString(18)
+	return strings.TrimRight(strings.TrimRight(text, "0"), ".")
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+package dex
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// partialProvider swaps only half of every amount, whether or not partial
+// fills are allowed, and remembers what it was allowed
+type partialProvider struct {
+	*MockProvider
+	allowed bool
+}
+
+func (p *partialProvider) ExecuteSwap(ctx context.Context, params SwapParams) (*SwapResult, error) {
+	p.allowed = params.AllowPartialFill
+	result, err := p.MockProvider.ExecuteSwap(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	result.FromAmount = "0.5"
+	result.ToAmount = "1500.0"
+	return result, nil
+}
+
+func TestDEXAggregator_PartialFillReturnsLeftover(t *testing.T) {
+	aggregator := NewDEXAggregator(zaptest.NewLogger(t))
+	provider := &partialProvider{MockProvider: NewMockProvider("Thin", []string{"1"})}
+	aggregator.RegisterProvider(provider)
+	if err := aggregator.SetPartialFillPolicy(PartialFillReturnLeftover); err != nil {
+		t.Fatalf("Failed to set policy: %v", err)
+	}
+
+	result, err := aggregator.ExecuteSwapWithProvider(context.Background(), "Thin", decisionParams())
+	if err != nil {
+		t.Fatalf("Expected the partial fill to be accepted, got %v", err)
+	}
+	if !provider.allowed {
+		t.Error("Expected the provider to be allowed to fill partially")
+	}
+	if !result.PartialFill || result.RequestedFromAmount != "1.0" || result.FromAmount != "0.5" || result.LeftoverFromAmount != "0.5" {
+		t.Errorf("Unexpected fill report %+v", result)
+	}
+}
+
+func TestDEXAggregator_PartialFillNotAllowed(t *testing.T) {
+	aggregator := NewDEXAggregator(zaptest.NewLogger(t))
+	provider := &partialProvider{MockProvider: NewMockProvider("Thin", []string{"1"})}
+	aggregator.RegisterProvider(provider)
+
+	// Partial fills revert by default, so a provider filling part anyway is reported
+	result, err := aggregator.ExecuteSwapWithProvider(context.Background(), "Thin", decisionParams())
+	if !errors.Is(err, ErrPartialFill) {
+		t.Fatalf("Expected ErrPartialFill, got %v", err)
+	}
+	if provider.allowed {
+		t.Error("Expected the provider not to be allowed to fill partially")
+	}
+	if result == nil || !result.PartialFill || result.LeftoverFromAmount != "0.5" {
+		t.Errorf("Expected the partial fill to be reported with the error, got %+v", result)
+	}
+
+	// Full fills are never flagged
+	full := NewMockProvider("Deep", []string{"1"})
+	aggregator.RegisterProvider(full)
+	result, err = aggregator.ExecuteSwapWithProvider(context.Background(), "Deep", decisionParams())
+	if err != nil || result.PartialFill || result.RequestedFromAmount != "1.0" {
+		t.Errorf("Unexpected full fill report %+v, %v", result, err)
+	}
+
+	if err := aggregator.SetPartialFillPolicy("refund"); err == nil {
+		t.Error("Expected an unknown policy to be rejected")
+	}
+}
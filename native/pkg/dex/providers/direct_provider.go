@@ -12,6 +12,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// directPoolFeeRate is the liquidity pool fee assumed for direct swaps (0.3%)
+const directPoolFeeRate = 0.003
+
 // DirectProvider implements direct RPC calls without external DEX APIs
 type DirectProvider struct {
 	name            string
@@ -155,6 +158,10 @@ func (d *DirectProvider) simulateSwapQuote(params dex.SwapParams) (*dex.SwapQuot
 
 	// Estimate gas
 	gasLimit, gasPrice, _ := d.EstimateGas(context.Background(), params)
+	estimatedFee := d.calculateEstimatedFee(gasLimit, gasPrice)
+
+	// Pools charge their fee out of the output; there is no aggregator in between
+	protocolFee := new(big.Float).Mul(toAmount, big.NewFloat(directPoolFeeRate))
 
 	quote := &dex.SwapQuote{
 		FromToken:      params.FromToken,
@@ -163,13 +170,18 @@ func (d *DirectProvider) simulateSwapQuote(params dex.SwapParams) (*dex.SwapQuot
 		ToAmount:       toAmount.String(),
 		EstimatedGas:   gasLimit,
 		GasPrice:       gasPrice,
-		EstimatedFee:   d.calculateEstimatedFee(gasLimit, gasPrice),
+		EstimatedFee:   estimatedFee,
 		Slippage:       params.Slippage,
 		PriceImpact:    0.001, // 0.1% simulated price impact
 		Route:          []string{"Direct DEX"},
 		ValidUntil:     time.Now().Add(2 * time.Minute).Unix(),
 		Provider:       d.name,
 		RawData:        "direct_simulation",
+		Fees: &dex.FeeBreakdown{
+			ProtocolFee:   protocolFee.String(),
+			AggregatorFee: "0",
+			NetworkFee:    estimatedFee,
+		},
 	}
 
 	return quote, nil
@@ -204,11 +216,40 @@ func (d *DirectProvider) simulateSwapExecution(params dex.SwapParams) (*dex.Swap
 		ActualFee:     quote.EstimatedFee,
 		Provider:      d.name,
 		Timestamp:     time.Now().Unix(),
+		SwapFunction:  directSwapFunction(params),
 	}
 
 	return result, nil
 }
 
+// directSwapFunction returns the Uniswap V2 style router function a swap on
+// an EVM chain calls, or "" on Solana. Fee-on-transfer tokens deliver less
+// than the amount the pair computed, which makes the plain functions revert,
+// so they are swapped through the SupportingFeeOnTransferTokens variants.
+func directSwapFunction(params dex.SwapParams) string {
+	var native string
+	switch params.ChainID {
+	case "1":
+		native = "ETH"
+	case "56":
+		native = "BNB"
+	default:
+		return ""
+	}
+
+	function := "swapExactTokensForTokens"
+	switch {
+	case strings.EqualFold(params.FromToken, native):
+		function = "swapExactETHForTokens"
+	case strings.EqualFold(params.ToToken, native):
+		function = "swapExactTokensForETH"
+	}
+	if params.FeeOnTransfer {
+		function += "SupportingFeeOnTransferTokens"
+	}
+	return function
+}
+
 // simulateBalanceCheck simulates checking token balance
 func (d *DirectProvider) simulateBalanceCheck(address, tokenAddress, chainID string) (*dex.BalanceInfo, error) {
 	// In simulation, return mock balance data
@@ -79,6 +79,13 @@ func TestDirectProvider_GetQuote(t *testing.T) {
 	if quote.Slippage != params.Slippage {
 		t.Errorf("Expected Slippage %f, got %f", params.Slippage, quote.Slippage)
 	}
+
+	if quote.Fees == nil {
+		t.Fatal("Expected an itemized fee breakdown")
+	}
+	if quote.Fees.AggregatorFee != "0" || quote.Fees.NetworkFee != quote.EstimatedFee || quote.Fees.ProtocolFee == "" {
+		t.Errorf("Unexpected fee breakdown %+v", quote.Fees)
+	}
 }
 
 func TestDirectProvider_GetQuote_InvalidParams(t *testing.T) {
@@ -194,6 +201,38 @@ func TestDirectProvider_ExecuteSwap(t *testing.T) {
 	}
 }
 
+func TestDirectProvider_SwapFunction(t *testing.T) {
+	tests := []struct {
+		chainID, fromToken, toToken string
+		feeOnTransfer               bool
+		expected                    string
+	}{
+		{"1", "ETH", "USDT", false, "swapExactETHForTokens"},
+		{"56", "0xTAX", "BNB", true, "swapExactTokensForETHSupportingFeeOnTransferTokens"},
+		{"56", "0xTAX", "USDT", true, "swapExactTokensForTokensSupportingFeeOnTransferTokens"},
+		{"1", "USDC", "USDT", false, "swapExactTokensForTokens"},
+		{"501", "SOL", "USDC", true, ""},
+	}
+
+	provider := NewDirectProvider(zaptest.NewLogger(t))
+	for _, tt := range tests {
+		result, err := provider.ExecuteSwap(context.Background(), dex.SwapParams{
+			FromToken:     tt.fromToken,
+			ToToken:       tt.toToken,
+			Amount:        "1.0",
+			FromAddress:   "0x742d35Cc6673C4C5f9aB9e3Be0A78a19a4B43c89",
+			ChainID:       tt.chainID,
+			FeeOnTransfer: tt.feeOnTransfer,
+		})
+		if err != nil {
+			t.Fatalf("Failed to execute swap: %v", err)
+		}
+		if result.SwapFunction != tt.expected {
+			t.Errorf("%s -> %s on %s: expected %q, got %q", tt.fromToken, tt.toToken, tt.chainID, tt.expected, result.SwapFunction)
+		}
+	}
+}
+
 func TestDirectProvider_GetBalance(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 	provider := NewDirectProvider(logger)
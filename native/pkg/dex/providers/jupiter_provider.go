@@ -0,0 +1,567 @@
+// SPDX-License-Identifier: Apache-2.0
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/chains/solana/broadcast"
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
+	"github.com/gagliardetto/solana-go"
+	"go.uber.org/zap"
+)
+
+const (
+	// jupiterChainID is the only chain Jupiter routes on
+	jupiterChainID = "501"
+	// jupiterSwapComputeUnits is the compute budget assumed for a routed swap
+	jupiterSwapComputeUnits = 300000
+	// jupiterSignatureFee is the base fee of the single signature of a swap, in SOL
+	jupiterSignatureFee = "0.000005"
+)
+
+// ErrJupiterRateLimited is returned when Jupiter keeps answering 429 after the
+// HTTP client's retries
+var ErrJupiterRateLimited = errors.New("jupiter rate limit exceeded")
+
+// jupiterToken is a mint Jupiter swaps can be asked for by symbol
+type jupiterToken struct {
+	mint     string
+	decimals int
+}
+
+// jupiterTokens are the symbols accepted in place of a mint address. SOL is
+// swapped as wrapped SOL, which Jupiter wraps and unwraps around the swap.
+var jupiterTokens = map[string]jupiterToken{
+	"SOL":  {mint: "So11111111111111111111111111111111111111112", decimals: 9},
+	"WSOL": {mint: "So11111111111111111111111111111111111111112", decimals: 9},
+	"USDC": {mint: "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v", decimals: 6},
+	"USDT": {mint: "Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB", decimals: 6},
+}
+
+// SolanaBroadcaster sends signed Solana transactions, as
+// broadcast.BroadcastManager does
+type SolanaBroadcaster interface {
+	BroadcastWithFallback(ctx context.Context, params *broadcast.BroadcastParams) (*broadcast.BroadcastResult, error)
+}
+
+// JupiterProvider implements IDEXProvider for the Jupiter v6 swap API on
+// Solana. Quotes come from /quote; swaps are built by /swap, signed with the
+// sender's key and sent through the broadcaster.
+type JupiterProvider struct {
+	name         string
+	baseURL      string
+	rpcEndpoints []string
+	httpClient   *http.Client
+	broadcaster  SolanaBroadcaster
+	logger       *zap.Logger
+
+	mu       sync.Mutex
+	decimals map[string]int
+}
+
+// JupiterConfig holds configuration for the Jupiter swap API
+type JupiterConfig struct {
+	BaseURL string // Default: https://quote-api.jup.ag/v6
+	Timeout time.Duration
+	// RPCEndpoints are asked for the decimals of mints not in jupiterTokens
+	RPCEndpoints []string
+	// Broadcaster sends signed swaps; without one ExecuteSwap fails
+	Broadcaster SolanaBroadcaster
+}
+
+// NewJupiterProvider creates a new Jupiter DEX provider
+func NewJupiterProvider(config JupiterConfig, logger *zap.Logger) *JupiterProvider {
+	if config.BaseURL == "" {
+		config.BaseURL = "https://quote-api.jup.ag/v6"
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &JupiterProvider{
+		name:         "Jupiter",
+		baseURL:      strings.TrimSuffix(config.BaseURL, "/"),
+		rpcEndpoints: config.RPCEndpoints,
+		// 429s are retried after Retry-After by the client
+		httpClient:  httpclient.New("jupiter", httpclient.WithTimeout(config.Timeout)),
+		broadcaster: config.Broadcaster,
+		logger:      logger,
+		decimals:    make(map[string]int),
+	}
+}
+
+// GetName returns the provider name
+func (j *JupiterProvider) GetName() string {
+	return j.name
+}
+
+// IsSupported checks if the chain is supported by Jupiter, which only routes on Solana
+func (j *JupiterProvider) IsSupported(chainID string) bool {
+	return chainID == jupiterChainID
+}
+
+// jupiterQuote is the part of a /quote response the provider reads. The
+// whole response is kept in raw, as /swap takes it back verbatim.
+type jupiterQuote struct {
+	InputMint      string `json:"inputMint"`
+	InAmount       string `json:"inAmount"`
+	OutputMint     string `json:"outputMint"`
+	OutAmount      string `json:"outAmount"`
+	PriceImpactPct string `json:"priceImpactPct"`
+	PlatformFee    *struct {
+		Amount string `json:"amount"`
+	} `json:"platformFee"`
+	RoutePlan []struct {
+		SwapInfo struct {
+			Label     string `json:"label"`
+			FeeAmount string `json:"feeAmount"`
+			FeeMint   string `json:"feeMint"`
+		} `json:"swapInfo"`
+		Percent int `json:"percent"`
+	} `json:"routePlan"`
+
+	raw         json.RawMessage
+	outDecimals int
+}
+
+// GetQuote fetches a swap quote from Jupiter's /quote endpoint
+func (j *JupiterProvider) GetQuote(ctx context.Context, params dex.SwapParams) (*dex.SwapQuote, error) {
+	j.logger.Debug("Getting quote from Jupiter",
+		zap.String("fromToken", params.FromToken),
+		zap.String("toToken", params.ToToken),
+		zap.String("amount", params.Amount))
+
+	quote, err := j.fetchQuote(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	toAmount, err := formatTokenUnits(quote.OutAmount, quote.outDecimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Jupiter output amount: %w", err)
+	}
+	var priceImpact float64
+	if fraction, err := strconv.ParseFloat(quote.PriceImpactPct, 64); err == nil {
+		priceImpact = fraction * 100
+	}
+
+	return &dex.SwapQuote{
+		Provider:     j.name,
+		FromToken:    params.FromToken,
+		ToToken:      params.ToToken,
+		FromAmount:   params.Amount,
+		ToAmount:     toAmount,
+		EstimatedGas: jupiterSwapComputeUnits,
+		EstimatedFee: jupiterSignatureFee,
+		Slippage:     params.Slippage,
+		PriceImpact:  priceImpact,
+		Route:        quote.route(),
+		ValidUntil:   time.Now().Add(30 * time.Second).Unix(),
+		RawData:      string(quote.raw),
+		Fees: &dex.FeeBreakdown{
+			ProtocolFee:   quote.poolFees(),
+			AggregatorFee: quote.platformFee(),
+			NetworkFee:    jupiterSignatureFee,
+		},
+	}, nil
+}
+
+// route lists the AMMs the swap goes through, with the share of the input
+// routed through each when it is split
+func (q *jupiterQuote) route() []string {
+	route := make([]string, 0, len(q.RoutePlan))
+	for _, step := range q.RoutePlan {
+		label := step.SwapInfo.Label
+		if label == "" {
+			label = "Unknown AMM"
+		}
+		if step.Percent > 0 && step.Percent < 100 {
+			label = fmt.Sprintf("%s (%d%%)", label, step.Percent)
+		}
+		route = append(route, label)
+	}
+	return route
+}
+
+// poolFees sums the AMM fees charged in the output mint, in to_token units.
+// Fees of intermediate hops are in other mints and left out.
+func (q *jupiterQuote) poolFees() string {
+	total := new(big.Int)
+	for _, step := range q.RoutePlan {
+		if step.SwapInfo.FeeMint != q.OutputMint {
+			continue
+		}
+		if fee, ok := new(big.Int).SetString(step.SwapInfo.FeeAmount, 10); ok {
+			total.Add(total, fee)
+		}
+	}
+	fees, _ := formatTokenUnits(total.String(), q.outDecimals)
+	return fees
+}
+
+// platformFee returns the fee Jupiter's integrator takes from the output
+func (q *jupiterQuote) platformFee() string {
+	if q.PlatformFee == nil {
+		return "0"
+	}
+	fee, err := formatTokenUnits(q.PlatformFee.Amount, q.outDecimals)
+	if err != nil {
+		return ""
+	}
+	return fee
+}
+
+// fetchQuote resolves the swap's mints and amount and asks /quote for a route
+func (j *JupiterProvider) fetchQuote(ctx context.Context, params dex.SwapParams) (*jupiterQuote, error) {
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid swap parameters: %w", err)
+	}
+	if !j.IsSupported(params.ChainID) {
+		return nil, fmt.Errorf("chain %s not supported by Jupiter", params.ChainID)
+	}
+
+	inputMint, inDecimals, err := j.resolveToken(ctx, params.FromToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from_token: %w", err)
+	}
+	outputMint, outDecimals, err := j.resolveToken(ctx, params.ToToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to_token: %w", err)
+	}
+	amount, err := parseTokenUnits(params.Amount, inDecimals)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("inputMint", inputMint)
+	query.Set("outputMint", outputMint)
+	query.Set("amount", amount.String())
+	query.Set("slippageBps", strconv.Itoa(int(math.Round(params.Slippage*10000))))
+
+	body, err := j.doRequest(ctx, http.MethodGet, "/quote?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote from Jupiter: %w", err)
+	}
+	quote := &jupiterQuote{raw: body, outDecimals: outDecimals}
+	if err := json.Unmarshal(body, quote); err != nil {
+		return nil, fmt.Errorf("failed to parse Jupiter quote response: %w", err)
+	}
+	if quote.OutAmount == "" {
+		return nil, fmt.Errorf("no route returned from Jupiter")
+	}
+	return quote, nil
+}
+
+// ExecuteSwap builds the swap with Jupiter's /swap endpoint, signs it with
+// the sender's key and broadcasts it
+func (j *JupiterProvider) ExecuteSwap(ctx context.Context, params dex.SwapParams) (*dex.SwapResult, error) {
+	j.logger.Info("Executing swap with Jupiter",
+		zap.String("fromToken", params.FromToken),
+		zap.String("toToken", params.ToToken),
+		zap.String("amount", params.Amount))
+
+	if j.broadcaster == nil {
+		return nil, errors.New("jupiter swaps cannot be broadcast: no Solana broadcaster configured")
+	}
+	// Jupiter delivers the output to the swapping wallet; it cannot pay out to
+	// another owner
+	if params.ToAddress != "" && params.ToAddress != params.FromAddress {
+		return nil, fmt.Errorf("jupiter swaps can only pay out to the sender %s, not %s", params.FromAddress, params.ToAddress)
+	}
+	owner, err := solana.PrivateKeyFromBase58(params.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Solana private key: %w", err)
+	}
+	if owner.PublicKey().String() != params.FromAddress {
+		return nil, fmt.Errorf("private key does not belong to %s", params.FromAddress)
+	}
+
+	quote, err := j.fetchQuote(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote before swap: %w", err)
+	}
+
+	swapReq, err := json.Marshal(map[string]any{
+		"quoteResponse":             quote.raw,
+		"userPublicKey":             params.FromAddress,
+		"wrapAndUnwrapSol":          true,
+		"dynamicComputeUnitLimit":   true,
+		"prioritizationFeeLamports": "auto",
+	})
+	if err != nil {
+		return nil, err
+	}
+	body, err := j.doRequest(ctx, http.MethodPost, "/swap", swapReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build swap with Jupiter: %w", err)
+	}
+	var swapResp struct {
+		SwapTransaction string `json:"swapTransaction"`
+	}
+	if err := json.Unmarshal(body, &swapResp); err != nil {
+		return nil, fmt.Errorf("failed to parse Jupiter swap response: %w", err)
+	}
+	if swapResp.SwapTransaction == "" {
+		return nil, fmt.Errorf("no swap transaction returned from Jupiter")
+	}
+
+	tx, err := solana.TransactionFromBase64(swapResp.SwapTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode Jupiter swap transaction: %w", err)
+	}
+	if err := checkSwapSigners(tx, owner.PublicKey()); err != nil {
+		return nil, fmt.Errorf("refusing to sign Jupiter swap transaction: %w", err)
+	}
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(owner.PublicKey()) {
+			return &owner
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to sign swap transaction: %w", err)
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode swap transaction: %w", err)
+	}
+
+	result, err := j.broadcaster.BroadcastWithFallback(ctx, &broadcast.BroadcastParams{
+		SignedTransaction: raw,
+		TransactionBase64: base64.StdEncoding.EncodeToString(raw),
+		Signature:         tx.Signatures[0].String(),
+		From:              params.FromAddress,
+		To:                params.ToAddress,
+		Token:             quote.InputMint,
+		MaxRetries:        3,
+		Timeout:           30 * time.Second,
+		Metadata: map[string]any{
+			"operation": "swap",
+			"provider":  j.name,
+			"route":     quote.route(),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to broadcast swap transaction: %w", err)
+	}
+
+	toAmount, _ := formatTokenUnits(quote.OutAmount, quote.outDecimals)
+	txHash := result.Signature
+	if txHash == "" {
+		txHash = tx.Signatures[0].String()
+	}
+	status := result.Status
+	if status == "" {
+		status = "pending"
+	}
+	return &dex.SwapResult{
+		TxHash:     txHash,
+		Status:     status,
+		FromToken:  params.FromToken,
+		ToToken:    params.ToToken,
+		FromAmount: params.Amount,
+		ToAmount:   toAmount,
+		ActualFee:  jupiterSignatureFee,
+		Provider:   j.name,
+		Timestamp:  time.Now().Unix(),
+	}, nil
+}
+
+// checkSwapSigners makes sure a transaction built by the Jupiter API is paid
+// for by owner and needs no signature but owner's, so signing it cannot
+// commit the wallet to anything another account has to authorize
+func checkSwapSigners(tx *solana.Transaction, owner solana.PublicKey) error {
+	keys := tx.Message.AccountKeys
+	if len(keys) == 0 {
+		return errors.New("transaction has no accounts")
+	}
+	if !keys[0].Equals(owner) {
+		return fmt.Errorf("fee payer is %s, not %s", keys[0], owner)
+	}
+	if signers := tx.Message.Header.NumRequiredSignatures; signers != 1 {
+		return fmt.Errorf("transaction requires %d signatures, only %s may sign", signers, owner)
+	}
+	return nil
+}
+
+// GetBalance is not offered by the Jupiter API; balances are read from the chain
+func (j *JupiterProvider) GetBalance(ctx context.Context, address string, tokenAddress string, chainID string) (*dex.BalanceInfo, error) {
+	return nil, fmt.Errorf("balance queries not supported by Jupiter DEX provider")
+}
+
+// EstimateGas returns the compute units of a routed swap and a compute unit
+// price in micro-lamports, after checking Jupiter has a route
+func (j *JupiterProvider) EstimateGas(ctx context.Context, params dex.SwapParams) (gasLimit uint64, gasPrice string, err error) {
+	if _, err := j.fetchQuote(ctx, params); err != nil {
+		return 0, "", fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	return jupiterSwapComputeUnits, "1", nil
+}
+
+// resolveToken returns the mint and decimals of a symbol in jupiterTokens or
+// of a mint address
+func (j *JupiterProvider) resolveToken(ctx context.Context, token string) (string, int, error) {
+	if known, ok := jupiterTokens[strings.ToUpper(strings.TrimSpace(token))]; ok {
+		return known.mint, known.decimals, nil
+	}
+	mint, err := solana.PublicKeyFromBase58(strings.TrimSpace(token))
+	if err != nil {
+		return "", 0, fmt.Errorf("unknown token %s", token)
+	}
+	for _, known := range jupiterTokens {
+		if known.mint == mint.String() {
+			return known.mint, known.decimals, nil
+		}
+	}
+
+	j.mu.Lock()
+	decimals, ok := j.decimals[mint.String()]
+	j.mu.Unlock()
+	if ok {
+		return mint.String(), decimals, nil
+	}
+	decimals, err = j.fetchMintDecimals(ctx, mint.String())
+	if err != nil {
+		return "", 0, err
+	}
+	j.mu.Lock()
+	j.decimals[mint.String()] = decimals
+	j.mu.Unlock()
+	return mint.String(), decimals, nil
+}
+
+// fetchMintDecimals reads the decimals of mint with getTokenSupply
+func (j *JupiterProvider) fetchMintDecimals(ctx context.Context, mint string) (int, error) {
+	if len(j.rpcEndpoints) == 0 {
+		return 0, fmt.Errorf("decimals of %s are unknown: no Solana RPC endpoints configured", mint)
+	}
+	request, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": "getTokenSupply", "params": []any{mint}})
+	if err != nil {
+		return 0, err
+	}
+	var lastErr error
+	for _, endpoint := range j.rpcEndpoints {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(request))
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := j.httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		var response struct {
+			Result *struct {
+				Value struct {
+					Decimals int `json:"decimals"`
+				} `json:"value"`
+			} `json:"result"`
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&response)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = fmt.Errorf("failed to decode response: %w", err)
+			continue
+		}
+		if response.Error != nil {
+			return 0, fmt.Errorf("%s is not a token mint: %s", mint, response.Error.Message)
+		}
+		if response.Result == nil {
+			return 0, fmt.Errorf("%s is not a token mint", mint)
+		}
+		return response.Result.Value.Decimals, nil
+	}
+	return 0, fmt.Errorf("failed to read decimals of %s: %w", mint, lastErr)
+}
+
+// doRequest sends a request to the Jupiter API and returns the body of a 200
+// response
+func (j *JupiterProvider) doRequest(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, j.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := j.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, ErrJupiterRateLimited
+	case resp.StatusCode != http.StatusOK:
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error != "" {
+			return nil, fmt.Errorf("Jupiter API error (status %d): %s", resp.StatusCode, apiErr.Error)
+		}
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
+
+// parseTokenUnits converts a decimal amount to an integer amount of the
+// token's smallest unit
+func parseTokenUnits(amount string, decimals int) (*big.Int, error) {
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok || value.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount: %s", amount)
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	value.Mul(value, new(big.Rat).SetInt(scale))
+	if !value.IsInt() {
+		return nil, fmt.Errorf("amount %s has more than %d decimals", amount, decimals)
+	}
+	return value.Num(), nil
+}
+
+// formatTokenUnits converts an integer amount of a token's smallest unit to a
+// decimal amount
+func formatTokenUnits(units string, decimals int) (string, error) {
+	value, ok := new(big.Int).SetString(units, 10)
+	if !ok {
+		return "", fmt.Errorf("invalid amount: %s", units)
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	formatted := new(big.Rat).SetFrac(value, scale).FloatString(decimals)
+	if decimals > 0 {
+		formatted = strings.TrimSuffix(strings.TrimRight(formatted, "0"), ".")
+	}
+	return formatted, nil
+}
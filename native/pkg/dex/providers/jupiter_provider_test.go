@@ -0,0 +1,243 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/chains/solana/broadcast"
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	testSOLMint  = "So11111111111111111111111111111111111111112"
+	testUSDCMint = "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"
+	testBONKMint = "DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263"
+)
+
+// testJupiterQuote routes 60% of the input through Raydium and 40% through Orca
+const testJupiterQuote = `{
+	"inputMint": "So11111111111111111111111111111111111111112",
+	"inAmount": "1500000000",
+	"outputMint": "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+	"outAmount": "225370000",
+	"otherAmountThreshold": "224243150",
+	"swapMode": "ExactIn",
+	"slippageBps": 50,
+	"platformFee": null,
+	"priceImpactPct": "0.0012",
+	"routePlan": [
+		{"swapInfo": {"label": "Raydium", "feeAmount": "338000", "feeMint": "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"}, "percent": 60},
+		{"swapInfo": {"label": "Orca", "feeAmount": "450000", "feeMint": "So11111111111111111111111111111111111111112"}, "percent": 40}
+	]
+}`
+
+// recordingBroadcaster keeps the transactions it is asked to broadcast
+type recordingBroadcaster struct {
+	params []*broadcast.BroadcastParams
+}
+
+func (b *recordingBroadcaster) BroadcastWithFallback(_ context.Context, params *broadcast.BroadcastParams) (*broadcast.BroadcastResult, error) {
+	b.params = append(b.params, params)
+	return &broadcast.BroadcastResult{Success: true, Signature: params.Signature, Channel: "solana-rpc", Status: "pending"}, nil
+}
+
+// newJupiterTestServer answers /quote with testJupiterQuote, /swap with an
+// unsigned transfer paid by owner, and getTokenSupply for testBONKMint
+func newJupiterTestServer(t *testing.T, owner solana.PublicKey) *httptest.Server {
+	t.Helper()
+	return newJupiterTestServerPaidBy(t, owner, owner, owner)
+}
+
+// newJupiterTestServerPaidBy is newJupiterTestServer with the /swap transfer
+// paid for by payer and sent from source
+func newJupiterTestServerPaidBy(t *testing.T, owner, payer, source solana.PublicKey) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/quote":
+			query := r.URL.Query()
+			assert.Equal(t, testSOLMint, query.Get("inputMint"))
+			assert.Equal(t, "50", query.Get("slippageBps"))
+			_, _ = io.WriteString(w, testJupiterQuote)
+		case "/swap":
+			var request struct {
+				QuoteResponse map[string]any `json:"quoteResponse"`
+				UserPublicKey string         `json:"userPublicKey"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			assert.Equal(t, owner.String(), request.UserPublicKey)
+			assert.Equal(t, "225370000", request.QuoteResponse["outAmount"])
+
+			tx, err := solana.NewTransaction(
+				[]solana.Instruction{system.NewTransferInstruction(1, source, solana.NewWallet().PublicKey()).Build()},
+				solana.Hash{1},
+				solana.TransactionPayer(payer),
+			)
+			require.NoError(t, err)
+			tx.Signatures = make([]solana.Signature, tx.Message.Header.NumRequiredSignatures)
+			encoded, err := tx.ToBase64()
+			require.NoError(t, err)
+			_ = json.NewEncoder(w).Encode(map[string]any{"swapTransaction": encoded, "lastValidBlockHeight": 1000})
+		case "/rpc":
+			_, _ = io.WriteString(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":1},"value":{"amount":"1000","decimals":5,"uiAmountString":"0.01"}}}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func jupiterSwapParams(owner solana.PrivateKey) dex.SwapParams {
+	return dex.SwapParams{
+		FromToken:   "SOL",
+		ToToken:     "USDC",
+		Amount:      "1.5",
+		Slippage:    0.005,
+		FromAddress: owner.PublicKey().String(),
+		ToAddress:   owner.PublicKey().String(),
+		ChainID:     "501",
+		PrivateKey:  owner.String(),
+	}
+}
+
+func TestJupiterProvider_IsSupported(t *testing.T) {
+	provider := NewJupiterProvider(JupiterConfig{}, zap.NewNop())
+	assert.Equal(t, "Jupiter", provider.GetName())
+	assert.True(t, provider.IsSupported("501"))
+	assert.False(t, provider.IsSupported("1"))
+
+	_, err := provider.GetQuote(context.Background(), dex.SwapParams{FromToken: "SOL", ToToken: "USDC", Amount: "1", FromAddress: "x", ChainID: "1"})
+	assert.ErrorContains(t, err, "not supported")
+}
+
+func TestJupiterProvider_GetQuote(t *testing.T) {
+	owner := solana.NewWallet().PrivateKey
+	server := newJupiterTestServer(t, owner.PublicKey())
+	provider := NewJupiterProvider(JupiterConfig{BaseURL: server.URL}, zap.NewNop())
+
+	quote, err := provider.GetQuote(context.Background(), jupiterSwapParams(owner))
+	require.NoError(t, err)
+	assert.Equal(t, "Jupiter", quote.Provider)
+	assert.Equal(t, "1.5", quote.FromAmount)
+	assert.Equal(t, "225.37", quote.ToAmount)
+	assert.Equal(t, []string{"Raydium (60%)", "Orca (40%)"}, quote.Route)
+	assert.InDelta(t, 0.12, quote.PriceImpact, 1e-9)
+	require.NotNil(t, quote.Fees)
+	assert.Equal(t, "0.338", quote.Fees.ProtocolFee, "only fees in the output mint are in to_token units")
+	assert.Equal(t, "0", quote.Fees.AggregatorFee)
+	assert.Equal(t, "0.000005", quote.Fees.NetworkFee)
+	assert.JSONEq(t, testJupiterQuote, quote.RawData)
+
+	limit, price, err := provider.EstimateGas(context.Background(), jupiterSwapParams(owner))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(jupiterSwapComputeUnits), limit)
+	assert.Equal(t, "1", price)
+}
+
+func TestJupiterProvider_ResolvesMintDecimals(t *testing.T) {
+	owner := solana.NewWallet().PrivateKey
+	server := newJupiterTestServer(t, owner.PublicKey())
+	provider := NewJupiterProvider(JupiterConfig{BaseURL: server.URL, RPCEndpoints: []string{server.URL + "/rpc"}}, zap.NewNop())
+
+	mint, decimals, err := provider.resolveToken(context.Background(), testBONKMint)
+	require.NoError(t, err)
+	assert.Equal(t, testBONKMint, mint)
+	assert.Equal(t, 5, decimals)
+
+	_, err = parseTokenUnits("0.000001", decimals)
+	assert.ErrorContains(t, err, "more than 5 decimals")
+
+	_, _, err = NewJupiterProvider(JupiterConfig{BaseURL: server.URL}, zap.NewNop()).resolveToken(context.Background(), testBONKMint)
+	assert.ErrorContains(t, err, "no Solana RPC endpoints configured")
+	_, _, err = provider.resolveToken(context.Background(), "NOTATOKEN")
+	assert.ErrorContains(t, err, "unknown token")
+}
+
+func TestJupiterProvider_ExecuteSwap(t *testing.T) {
+	owner := solana.NewWallet().PrivateKey
+	server := newJupiterTestServer(t, owner.PublicKey())
+	broadcaster := &recordingBroadcaster{}
+	provider := NewJupiterProvider(JupiterConfig{BaseURL: server.URL, Broadcaster: broadcaster}, zap.NewNop())
+
+	result, err := provider.ExecuteSwap(context.Background(), jupiterSwapParams(owner))
+	require.NoError(t, err)
+	require.Len(t, broadcaster.params, 1)
+	sent := broadcaster.params[0]
+
+	tx, err := solana.TransactionFromBase64(sent.TransactionBase64)
+	require.NoError(t, err)
+	require.NoError(t, tx.VerifySignatures(), "the swap must be signed by the sender")
+	assert.Equal(t, tx.Signatures[0].String(), result.TxHash)
+	assert.Equal(t, "pending", result.Status)
+	assert.Equal(t, "225.37", result.ToAmount)
+	assert.Equal(t, "Jupiter", result.Provider)
+	assert.Equal(t, testSOLMint, sent.Token)
+
+	// A key that is not the sender's is refused before anything is built
+	params := jupiterSwapParams(owner)
+	params.PrivateKey = solana.NewWallet().PrivateKey.String()
+	_, err = provider.ExecuteSwap(context.Background(), params)
+	assert.ErrorContains(t, err, "does not belong")
+
+	_, err = NewJupiterProvider(JupiterConfig{BaseURL: server.URL}, zap.NewNop()).ExecuteSwap(context.Background(), jupiterSwapParams(owner))
+	assert.ErrorContains(t, err, "no Solana broadcaster configured")
+}
+
+func TestJupiterProvider_ExecuteSwapRefusesForeignTransactions(t *testing.T) {
+	owner := solana.NewWallet().PrivateKey
+	other := solana.NewWallet().PublicKey()
+
+	for name, server := range map[string]*httptest.Server{
+		"fee payer":       newJupiterTestServerPaidBy(t, owner.PublicKey(), other, other),
+		"required signer": newJupiterTestServerPaidBy(t, owner.PublicKey(), owner.PublicKey(), other),
+	} {
+		broadcaster := &recordingBroadcaster{}
+		provider := NewJupiterProvider(JupiterConfig{BaseURL: server.URL, Broadcaster: broadcaster}, zap.NewNop())
+		_, err := provider.ExecuteSwap(context.Background(), jupiterSwapParams(owner))
+		assert.ErrorContains(t, err, "refusing to sign", name)
+		assert.Empty(t, broadcaster.params, name)
+	}
+
+	// The output cannot be sent to another owner
+	server := newJupiterTestServer(t, owner.PublicKey())
+	broadcaster := &recordingBroadcaster{}
+	provider := NewJupiterProvider(JupiterConfig{BaseURL: server.URL, Broadcaster: broadcaster}, zap.NewNop())
+	params := jupiterSwapParams(owner)
+	params.ToAddress = other.String()
+	_, err := provider.ExecuteSwap(context.Background(), params)
+	assert.ErrorContains(t, err, "can only pay out to the sender")
+	assert.Empty(t, broadcaster.params)
+}
+
+func TestJupiterProvider_RateLimited(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, `{"error":"Too many requests"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	owner := solana.NewWallet().PrivateKey
+	provider := NewJupiterProvider(JupiterConfig{BaseURL: server.URL}, zap.NewNop())
+	_, err := provider.GetQuote(context.Background(), jupiterSwapParams(owner))
+	assert.ErrorIs(t, err, ErrJupiterRateLimited)
+	assert.Greater(t, requests.Load(), int32(1), "rate limited requests are retried")
+}
+
+func TestJupiterProvider_GetBalanceUnsupported(t *testing.T) {
+	provider := NewJupiterProvider(JupiterConfig{}, zap.NewNop())
+	_, err := provider.GetBalance(context.Background(), "addr", testUSDCMint, "501")
+	assert.Error(t, err)
+}
@@ -21,6 +21,8 @@ type MockProvider struct {
 	shouldFailSwap    bool
 	shouldFailBalance bool
 	customQuoteAmount string
+	customFees        *dex.FeeBreakdown
+	partialFillRatio  float64
 }
 
 // MockConfig holds configuration for mock provider
@@ -31,6 +33,10 @@ type MockConfig struct {
 	ShouldFailSwap    bool
 	ShouldFailBalance bool
 	CustomQuoteAmount string
+	CustomFees        *dex.FeeBreakdown
+	// PartialFillRatio, between 0 and 1, makes swaps run out of liquidity
+	// after that share of the input
+	PartialFillRatio float64
 }
 
 // NewMockProvider creates a new mock DEX provider for testing
@@ -59,6 +65,8 @@ func NewMockProvider(config MockConfig, logger *zap.Logger) *MockProvider {
 		shouldFailSwap:    config.ShouldFailSwap,
 		shouldFailBalance: config.ShouldFailBalance,
 		customQuoteAmount: config.CustomQuoteAmount,
+		customFees:        config.CustomFees,
+		partialFillRatio:  config.PartialFillRatio,
 	}
 }
 
@@ -104,6 +112,7 @@ func (m *MockProvider) GetQuote(ctx context.Context, params dex.SwapParams) (*de
 		Slippage:     params.Slippage,
 		ValidUntil:   time.Now().Add(30 * time.Second).Unix(),
 		RawData:      fmt.Sprintf(`{"mock_quote": true, "provider": "%s"}`, m.name),
+		Fees:         m.quoteFees(),
 	}, nil
 }
 
@@ -119,6 +128,15 @@ func (m *MockProvider) ExecuteSwap(ctx context.Context, params dex.SwapParams) (
 		return nil, fmt.Errorf("failed to get quote: %w", err)
 	}
 
+	// Thin liquidity fills only part of the input, reverting unless allowed
+	fromAmount, toAmount := params.Amount, quote.ToAmount
+	if m.partialFillRatio > 0 && m.partialFillRatio < 1 {
+		if !params.AllowPartialFill {
+			return nil, fmt.Errorf("%w: mock liquidity covers only %.0f%% of the amount, swap reverted", dex.ErrPartialFill, m.partialFillRatio*100)
+		}
+		fromAmount, toAmount = scaleMockAmount(params.Amount, m.partialFillRatio), scaleMockAmount(quote.ToAmount, m.partialFillRatio)
+	}
+
 	// Generate mock transaction hash
 	mockTxHash := fmt.Sprintf("0x%x%x", time.Now().Unix(), time.Now().Nanosecond())
 
@@ -127,13 +145,22 @@ func (m *MockProvider) ExecuteSwap(ctx context.Context, params dex.SwapParams) (
 		Provider:   m.name,
 		FromToken:  params.FromToken,
 		ToToken:    params.ToToken,
-		FromAmount: params.Amount,
-		ToAmount:   quote.ToAmount,
+		FromAmount: fromAmount,
+		ToAmount:   toAmount,
 		Status:     "confirmed", // Mock as immediately confirmed
 		Timestamp:  time.Now().Unix(),
 	}, nil
 }
 
+// scaleMockAmount returns ratio of amount, or amount itself when it is not a number
+func scaleMockAmount(amount string, ratio float64) string {
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return amount
+	}
+	return strconv.FormatFloat(value*ratio, 'f', -1, 64)
+}
+
 // GetBalance returns mock balance information
 func (m *MockProvider) GetBalance(ctx context.Context, address string, tokenAddress string, chainID string) (*dex.BalanceInfo, error) {
 	if m.shouldFailBalance {
@@ -156,4 +183,13 @@ func (m *MockProvider) EstimateGas(ctx context.Context, params dex.SwapParams) (
 	}
 
 	return 200000, "20000000000", nil // 200k gas limit, 20 gwei gas price
-}
\ No newline at end of file
+}
+
+// quoteFees returns a copy of the configured fees, or the costs of a plain 200k-gas swap
+func (m *MockProvider) quoteFees() *dex.FeeBreakdown {
+	if m.customFees != nil {
+		fees := *m.customFees
+		return &fees
+	}
+	return &dex.FeeBreakdown{ProtocolFee: "0", AggregatorFee: "0", NetworkFee: "0.004"} // 200k gas at 20 gwei
+}
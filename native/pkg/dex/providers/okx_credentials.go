@@ -0,0 +1,160 @@
+package providers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Credential rotation strategies for OKXConfig.Rotation
+const (
+	OKXRotationRoundRobin        = "round_robin"
+	OKXRotationLeastRecentlyUsed = "least_recently_used"
+)
+
+// okxRateLimitCooldown is how long a rate-limited key is skipped when OKX
+// does not send a Retry-After header
+const okxRateLimitCooldown = 60 * time.Second
+
+// ErrNoOKXCredentials is returned when every configured credential set is
+// rate limited or has been revoked
+var ErrNoOKXCredentials = errors.New("no usable OKX API credentials")
+
+// OKXCredentials is one set of OKX API credentials
+type OKXCredentials struct {
+	APIKey     string
+	SecretKey  string
+	Passphrase string
+}
+
+// okxRateLimitCodes are OKX error codes meaning the key exceeded its rate limit
+var okxRateLimitCodes = map[string]bool{
+	"50011": true, // Too many requests
+	"50061": true, // Sub-account rate limit exceeded
+}
+
+// okxAuthErrorCodes are OKX error codes meaning the key can no longer be used
+var okxAuthErrorCodes = map[string]bool{
+	"50100": true, // API frozen
+	"50105": true, // Invalid OK-ACCESS-PASSPHRASE
+	"50111": true, // Invalid OK-ACCESS-KEY
+	"50113": true, // Invalid signature
+	"50119": true, // API key doesn't exist
+}
+
+// okxCredential tracks the state of one credential set in the pool
+type okxCredential struct {
+	creds        OKXCredentials
+	lastUsed     time.Time
+	limitedUntil time.Time
+	revoked      bool
+}
+
+// okxCredentialPool rotates among credential sets, skipping keys that are
+// cooling down after a rate limit and dropping keys OKX rejected
+type okxCredentialPool struct {
+	mu       sync.Mutex
+	entries  []*okxCredential
+	rotation string
+	next     int
+	now      func() time.Time
+	logger   *zap.Logger
+}
+
+// newOKXCredentialPool builds a pool from the given sets, ignoring ones without
+// an API key and duplicates
+func newOKXCredentialPool(sets []OKXCredentials, rotation string, logger *zap.Logger) *okxCredentialPool {
+	pool := &okxCredentialPool{rotation: rotation, now: time.Now, logger: logger}
+	seen := make(map[string]bool)
+	for _, creds := range sets {
+		if creds.APIKey == "" || seen[creds.APIKey] {
+			continue
+		}
+		seen[creds.APIKey] = true
+		pool.entries = append(pool.entries, &okxCredential{creds: creds})
+	}
+	return pool
+}
+
+// size returns the number of configured credential sets, including revoked ones
+func (p *okxCredentialPool) size() int {
+	return len(p.entries)
+}
+
+// acquire picks the next usable credential set and marks it used
+func (p *okxCredentialPool) acquire() (*okxCredential, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	var picked *okxCredential
+	if p.rotation == OKXRotationLeastRecentlyUsed {
+		for _, entry := range p.entries {
+			if entry.usable(now) && (picked == nil || entry.lastUsed.Before(picked.lastUsed)) {
+				picked = entry
+			}
+		}
+	} else {
+		for i := 0; i < len(p.entries); i++ {
+			entry := p.entries[(p.next+i)%len(p.entries)]
+			if entry.usable(now) {
+				picked = entry
+				p.next = (p.next + i + 1) % len(p.entries)
+				break
+			}
+		}
+	}
+	if picked == nil {
+		return nil, ErrNoOKXCredentials
+	}
+	picked.lastUsed = now
+	return picked, nil
+}
+
+// rateLimited skips the credential set until retryAfter has passed
+func (p *okxCredentialPool) rateLimited(entry *okxCredential, retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		retryAfter = okxRateLimitCooldown
+	}
+	p.mu.Lock()
+	entry.limitedUntil = p.now().Add(retryAfter)
+	p.mu.Unlock()
+	p.logger.Warn("OKX API key rate limited, rotating to the next key",
+		zap.String("apiKey", maskAPIKey(entry.creds.APIKey)),
+		zap.Duration("cooldown", retryAfter))
+}
+
+// revoke drops the credential set for the lifetime of the provider
+func (p *okxCredentialPool) revoke(entry *okxCredential) {
+	p.mu.Lock()
+	entry.revoked = true
+	p.mu.Unlock()
+	p.logger.Error("OKX API key rejected, removing it from rotation",
+		zap.String("apiKey", maskAPIKey(entry.creds.APIKey)))
+}
+
+// usable reports whether the credential set may be used at now
+func (c *okxCredential) usable(now time.Time) bool {
+	return !c.revoked && !now.Before(c.limitedUntil)
+}
+
+// okxRetryAfter parses a Retry-After header given in seconds
+func okxRetryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// maskAPIKey keeps only the last four characters of an API key for logging
+func maskAPIKey(key string) string {
+	if len(key) <= 4 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
@@ -6,17 +6,23 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/algonius/algonius-wallet/native/pkg/dex"
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
 	"go.uber.org/zap"
 )
 
+// okxDefaultGasPrice is the gas price, in wei, assumed for OKX swaps (20 gwei)
+const okxDefaultGasPrice = "20000000000"
+
 // OKXProvider implements IDEXProvider for OKX DEX API
 type OKXProvider struct {
 	name       string
@@ -26,6 +32,8 @@ type OKXProvider struct {
 	baseURL    string
 	httpClient *http.Client
 	logger     *zap.Logger
+	// credentials rotates among every configured key, including apiKey
+	credentials *okxCredentialPool
 }
 
 // OKXConfig holds configuration for OKX DEX API
@@ -35,6 +43,10 @@ type OKXConfig struct {
 	Passphrase string
 	BaseURL    string // Default: https://www.okx.com
 	Timeout    time.Duration
+	// Credentials are additional key sets rotated with APIKey for rate-limit headroom
+	Credentials []OKXCredentials
+	// Rotation is OKXRotationRoundRobin (default) or OKXRotationLeastRecentlyUsed
+	Rotation string
 }
 
 // NewOKXProvider creates a new OKX DEX provider
@@ -52,8 +64,15 @@ func NewOKXProvider(config OKXConfig, logger *zap.Logger) *OKXProvider {
 		secretKey:  config.SecretKey,
 		passphrase: config.Passphrase,
 		baseURL:    config.BaseURL,
-		httpClient: &http.Client{Timeout: config.Timeout},
+		// 429s are left to credential rotation rather than retried on the same key
+		httpClient: httpclient.New("okx",
+			httpclient.WithTimeout(config.Timeout),
+			httpclient.WithRetryStatuses(http.StatusInternalServerError, http.StatusBadGateway,
+				http.StatusServiceUnavailable, http.StatusGatewayTimeout)),
 		logger:     logger,
+		credentials: newOKXCredentialPool(
+			append([]OKXCredentials{{APIKey: config.APIKey, SecretKey: config.SecretKey, Passphrase: config.Passphrase}}, config.Credentials...),
+			config.Rotation, logger),
 	}
 }
 
@@ -110,6 +129,15 @@ func (o *OKXProvider) GetQuote(ctx context.Context, params dex.SwapParams) (*dex
 			FromTokenAmount string `json:"fromTokenAmount"`
 			EstimatedGas    string `json:"estimatedGas"`
 			MinimumReceived string `json:"minimumReceived"`
+			// OriginToTokenAmount is the output before OKX's commission
+			OriginToTokenAmount string `json:"originToTokenAmount"`
+			// TaxRate is the share of each transfer a fee-on-transfer token keeps
+			FromToken struct {
+				TaxRate string `json:"taxRate"`
+			} `json:"fromToken"`
+			ToToken struct {
+				TaxRate string `json:"taxRate"`
+			} `json:"toToken"`
 		} `json:"data"`
 	}
 
@@ -145,9 +173,52 @@ func (o *OKXProvider) GetQuote(ctx context.Context, params dex.SwapParams) (*dex
 		Slippage:     params.Slippage,
 		ValidUntil:   time.Now().Add(30 * time.Second).Unix(),
 		RawData:      fmt.Sprintf(`{"okx_data": %s}`, string(mustMarshal(data))),
+		Fees: &dex.FeeBreakdown{
+			// Pool fees are priced into OKX routes and not reported separately
+			AggregatorFee: okxCommission(data.OriginToTokenAmount, data.ToTokenAmount),
+			NetworkFee:    okxNetworkFee(params.ChainID, estimatedGas),
+		},
+		FromTokenTransferFee: okxTaxPercent(data.FromToken.TaxRate),
+		ToTokenTransferFee:   okxTaxPercent(data.ToToken.TaxRate),
 	}, nil
 }
 
+// okxTaxPercent converts an OKX token tax rate, a fraction such as "0.05",
+// to a percentage. Missing or malformed rates count as no tax.
+func okxTaxPercent(rate string) float64 {
+	fraction, err := strconv.ParseFloat(rate, 64)
+	if err != nil || fraction <= 0 || fraction >= 1 {
+		return 0
+	}
+	return fraction * 100
+}
+
+// okxCommission returns the part of the output withheld by OKX, or "" when the
+// quote does not report the output before commission
+func okxCommission(originToAmount, toAmount string) string {
+	origin, ok := new(big.Float).SetString(originToAmount)
+	if !ok {
+		return ""
+	}
+	out, ok := new(big.Float).SetString(toAmount)
+	if !ok {
+		return ""
+	}
+	return new(big.Float).Sub(origin, out).Text('f', -1)
+}
+
+// okxNetworkFee prices the quoted gas at okxDefaultGasPrice, in native units.
+// Solana quotes carry no EVM gas estimate, so their fee is left unknown.
+func okxNetworkFee(chainID string, estimatedGas uint64) string {
+	if chainID == "501" || estimatedGas == 0 {
+		return ""
+	}
+	gasPrice, _ := new(big.Int).SetString(okxDefaultGasPrice, 10)
+	wei := new(big.Int).Mul(new(big.Int).SetUint64(estimatedGas), gasPrice)
+	fee := new(big.Rat).SetFrac(wei, big.NewInt(1e18)).FloatString(18)
+	return strings.TrimRight(strings.TrimRight(fee, "0"), ".")
+}
+
 // ExecuteSwap executes a token swap using OKX DEX API
 func (o *OKXProvider) ExecuteSwap(ctx context.Context, params dex.SwapParams) (*dex.SwapResult, error) {
 	o.logger.Info("Executing swap with OKX DEX",
@@ -256,13 +327,71 @@ func (o *OKXProvider) EstimateGas(ctx context.Context, params dex.SwapParams) (g
 	}
 
 	// Use a reasonable gas price (this should be fetched from network)
-	gasPrice = "20000000000" // 20 gwei
+	gasPrice = okxDefaultGasPrice
 
 	return gasLimit, gasPrice, nil
 }
 
-// makeAPIRequest makes an authenticated request to OKX API
+// makeAPIRequest makes an authenticated request to OKX API. With several
+// credential sets configured it rotates among them, retrying with the next
+// key when one is rate limited and dropping keys OKX rejects.
 func (o *OKXProvider) makeAPIRequest(ctx context.Context, method, endpoint string, params map[string]interface{}) ([]byte, error) {
+	if o.credentials.size() == 0 {
+		return o.doAPIRequest(ctx, method, endpoint, params, nil)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < o.credentials.size(); attempt++ {
+		entry, err := o.credentials.acquire()
+		if err != nil {
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w: %v", err, lastErr)
+			}
+			return nil, err
+		}
+
+		respBody, err := o.doAPIRequest(ctx, method, endpoint, params, &entry.creds)
+		var apiErr *okxAPIError
+		if !errors.As(err, &apiErr) {
+			return respBody, err
+		}
+		switch {
+		case apiErr.rateLimited():
+			o.credentials.rateLimited(entry, apiErr.retryAfter)
+		case apiErr.unauthorized():
+			o.credentials.revoke(entry)
+		default:
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// okxAPIError is a non-200 response, or an OKX error code tied to the key
+type okxAPIError struct {
+	status     int
+	code       string
+	body       string
+	retryAfter time.Duration
+}
+
+func (e *okxAPIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.status, e.body)
+}
+
+// rateLimited reports whether the key hit its request limit
+func (e *okxAPIError) rateLimited() bool {
+	return e.status == http.StatusTooManyRequests || okxRateLimitCodes[e.code]
+}
+
+// unauthorized reports whether OKX rejected the key itself
+func (e *okxAPIError) unauthorized() bool {
+	return e.status == http.StatusUnauthorized || e.status == http.StatusForbidden || okxAuthErrorCodes[e.code]
+}
+
+// doAPIRequest sends a single request, signed with creds when given
+func (o *OKXProvider) doAPIRequest(ctx context.Context, method, endpoint string, params map[string]interface{}, creds *OKXCredentials) ([]byte, error) {
 	// Build URL with query parameters for GET requests
 	url := o.baseURL + endpoint
 	var body io.Reader
@@ -282,14 +411,14 @@ func (o *OKXProvider) makeAPIRequest(ctx context.Context, method, endpoint strin
 	}
 
 	// Add authentication headers if credentials are provided
-	if o.apiKey != "" {
+	if creds != nil {
 		timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
-		signature := o.generateSignature(timestamp, method, endpoint, "")
+		signature := signOKXRequest(creds.SecretKey, timestamp, method, endpoint, "")
 		
-		req.Header.Set("OK-ACCESS-KEY", o.apiKey)
+		req.Header.Set("OK-ACCESS-KEY", creds.APIKey)
 		req.Header.Set("OK-ACCESS-SIGN", signature)
 		req.Header.Set("OK-ACCESS-TIMESTAMP", timestamp)
-		req.Header.Set("OK-ACCESS-PASSPHRASE", o.passphrase)
+		req.Header.Set("OK-ACCESS-PASSPHRASE", creds.Passphrase)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -308,8 +437,18 @@ func (o *OKXProvider) makeAPIRequest(ctx context.Context, method, endpoint strin
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+	// OKX reports some key errors with a 200 status and an error code
+	var envelope struct {
+		Code string `json:"code"`
+	}
+	_ = json.Unmarshal(respBody, &envelope)
+	if resp.StatusCode != http.StatusOK || okxRateLimitCodes[envelope.Code] || okxAuthErrorCodes[envelope.Code] {
+		return nil, &okxAPIError{
+			status:     resp.StatusCode,
+			code:       envelope.Code,
+			body:       string(respBody),
+			retryAfter: okxRetryAfter(resp.Header),
+		}
 	}
 
 	return respBody, nil
@@ -317,12 +456,17 @@ func (o *OKXProvider) makeAPIRequest(ctx context.Context, method, endpoint strin
 
 // generateSignature generates HMAC-SHA256 signature for OKX API authentication
 func (o *OKXProvider) generateSignature(timestamp, method, requestPath, body string) string {
-	if o.secretKey == "" {
+	return signOKXRequest(o.secretKey, timestamp, method, requestPath, body)
+}
+
+// signOKXRequest signs a request with the given secret key
+func signOKXRequest(secretKey, timestamp, method, requestPath, body string) string {
+	if secretKey == "" {
 		return ""
 	}
 
 	message := timestamp + method + requestPath + body
-	h := hmac.New(sha256.New, []byte(o.secretKey))
+	h := hmac.New(sha256.New, []byte(secretKey))
 	h.Write([]byte(message))
 	return base64.StdEncoding.EncodeToString(h.Sum(nil))
 }
@@ -2,10 +2,15 @@ package providers
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/algonius/algonius-wallet/native/pkg/dex"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -140,4 +145,114 @@ func TestOKXProvider_GenerateSignature(t *testing.T) {
 	providerNoSecret := NewOKXProvider(OKXConfig{}, logger)
 	emptySignature := providerNoSecret.generateSignature(timestamp, method, requestPath, body)
 	assert.Empty(t, emptySignature)
-}
\ No newline at end of file
+}
+func TestOKXProvider_FeeBreakdown(t *testing.T) {
+	assert.Equal(t, "1.5", okxCommission("3001.5", "3000"))
+	assert.Equal(t, "", okxCommission("", "3000"))
+
+	// 150k gas at 20 gwei
+	assert.Equal(t, "0.003", okxNetworkFee("1", 150000))
+	assert.Equal(t, "", okxNetworkFee("501", 150000))
+	assert.Equal(t, "", okxNetworkFee("1", 0))
+}
+
+func TestOKXProvider_TaxRate(t *testing.T) {
+	assert.InDelta(t, 5.0, okxTaxPercent("0.05"), 1e-9)
+	assert.Equal(t, 0.0, okxTaxPercent("0"))
+	assert.Equal(t, 0.0, okxTaxPercent(""))
+	assert.Equal(t, 0.0, okxTaxPercent("1"), "a token keeping everything is not a transfer fee")
+}
+
+func TestOKXProvider_CredentialRotation(t *testing.T) {
+	var mu sync.Mutex
+	var used []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("OK-ACCESS-KEY")
+		mu.Lock()
+		used = append(used, key)
+		mu.Unlock()
+		switch key {
+		case "limited-key":
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"code":"50011","msg":"Too Many Requests"}`))
+		case "revoked-key":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":"50111","msg":"Invalid OK-ACCESS-KEY"}`))
+		default:
+			w.Write([]byte(`{"code":"0","data":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	provider := NewOKXProvider(OKXConfig{
+		APIKey:     "limited-key",
+		SecretKey:  "secret-1",
+		Passphrase: "pass-1",
+		BaseURL:    server.URL,
+		Credentials: []OKXCredentials{
+			{APIKey: "revoked-key", SecretKey: "secret-2", Passphrase: "pass-2"},
+			{APIKey: "good-key", SecretKey: "secret-3", Passphrase: "pass-3"},
+		},
+	}, zap.NewNop())
+
+	// The rate-limited and revoked keys fail over to the good one transparently
+	_, err := provider.makeAPIRequest(context.Background(), "GET", "/api/v5/dex/aggregator/quote", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"limited-key", "revoked-key", "good-key"}, used)
+
+	// The limited key is cooling down and the revoked key is gone for good
+	used = nil
+	for i := 0; i < 3; i++ {
+		_, err := provider.makeAPIRequest(context.Background(), "GET", "/api/v5/dex/aggregator/quote", nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"good-key", "good-key", "good-key"}, used)
+
+	// Once the cooldown passes the limited key is tried again, the revoked one never is
+	provider.credentials.now = func() time.Time { return time.Now().Add(3 * time.Minute) }
+	used = nil
+	_, err = provider.makeAPIRequest(context.Background(), "GET", "/api/v5/dex/aggregator/quote", nil)
+	require.NoError(t, err)
+	assert.NotContains(t, used, "revoked-key")
+	assert.Contains(t, used, "limited-key")
+}
+
+func TestOKXProvider_AllCredentialsExhausted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	provider := NewOKXProvider(OKXConfig{
+		APIKey:      "key-1",
+		SecretKey:   "secret-1",
+		BaseURL:     server.URL,
+		Credentials: []OKXCredentials{{APIKey: "key-2", SecretKey: "secret-2"}},
+	}, zap.NewNop())
+
+	_, err := provider.makeAPIRequest(context.Background(), "GET", "/api/v5/dex/aggregator/quote", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 429")
+
+	_, err = provider.makeAPIRequest(context.Background(), "GET", "/api/v5/dex/aggregator/quote", nil)
+	assert.ErrorIs(t, err, ErrNoOKXCredentials)
+}
+
+func TestOKXCredentialPool_LeastRecentlyUsed(t *testing.T) {
+	pool := newOKXCredentialPool([]OKXCredentials{{APIKey: "a"}, {APIKey: "b"}, {APIKey: ""}, {APIKey: "a"}}, OKXRotationLeastRecentlyUsed, zap.NewNop())
+	require.Equal(t, 2, pool.size())
+
+	clock := time.Now()
+	pool.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+
+	first, err := pool.acquire()
+	require.NoError(t, err)
+	second, err := pool.acquire()
+	require.NoError(t, err)
+	assert.NotEqual(t, first.creds.APIKey, second.creds.APIKey)
+
+	third, err := pool.acquire()
+	require.NoError(t, err)
+	assert.Equal(t, first.creds.APIKey, third.creds.APIKey)
+}
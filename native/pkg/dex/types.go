@@ -16,6 +16,9 @@ type SwapParams struct {
 	ToAddress    string  `json:"to_address"`    // Recipient address (can be same as from)
 	ChainID      string  `json:"chain_id"`      // Blockchain chain ID
 	PrivateKey   string  `json:"private_key"`   // Private key for signing (handled securely)
+	CompareNetOfFees bool `json:"compare_net_of_fees,omitempty"` // Rank quotes by output net of fees instead of gross output
+	FeeOnTransfer bool `json:"fee_on_transfer,omitempty"` // Swap through router functions that support fee-on-transfer tokens
+	AllowPartialFill bool `json:"allow_partial_fill,omitempty"` // Accept swapping part of the amount when liquidity runs out instead of reverting
 }
 
 // Validate validates the swap parameters
@@ -56,6 +59,30 @@ type SwapQuote struct {
 	ValidUntil     int64      `json:"valid_until"`    // Quote expiry timestamp
 	Provider       string     `json:"provider"`       // DEX provider name
 	RawData        string     `json:"raw_data"`       // Provider-specific raw response
+	Fees           *FeeBreakdown `json:"fees,omitempty"`         // Itemized swap costs
+	NetToAmount    string     `json:"net_to_amount,omitempty"` // ToAmount minus the network fee valued in to_token, set by the aggregator
+	TimedOutProviders []string `json:"timed_out_providers,omitempty"` // providers left out of the comparison for answering too late, set by the aggregator
+	FromTokenTransferFee float64 `json:"from_token_transfer_fee,omitempty"` // Percent of from_token the token itself keeps on every transfer
+	ToTokenTransferFee float64 `json:"to_token_transfer_fee,omitempty"` // Percent of to_token the token itself keeps on every transfer
+}
+
+// TransferFeePercent returns the share of the swap, in percent, lost to
+// fee-on-transfer tokens: the from_token fee shrinks what reaches the pool and
+// the to_token fee shrinks what reaches the recipient
+func (q *SwapQuote) TransferFeePercent() float64 {
+	kept := (1 - q.FromTokenTransferFee/100) * (1 - q.ToTokenTransferFee/100)
+	return (1 - kept) * 100
+}
+
+// FeeBreakdown itemizes the costs of a swap quote. ProtocolFee and AggregatorFee
+// are in to_token units and have already been taken out of SwapQuote.ToAmount;
+// they are disclosed so the true cost of a route can be computed. NetworkFee is
+// paid on top, in the chain's native token. Empty fields are unknown.
+type FeeBreakdown struct {
+	ProtocolFee       string `json:"protocol_fee"`                   // Fee charged by the liquidity pools
+	AggregatorFee     string `json:"aggregator_fee"`                 // Fee charged by the routing provider
+	NetworkFee        string `json:"network_fee"`                    // Estimated gas cost in native units
+	NetworkFeeToToken string `json:"network_fee_to_token,omitempty"` // NetworkFee valued in to_token units, when known
 }
 
 // SwapResult contains the result of a completed swap
@@ -69,6 +96,10 @@ type SwapResult struct {
 	ActualFee     string `json:"actual_fee"`
 	Provider      string `json:"provider"`
 	Timestamp     int64  `json:"timestamp"`
+	SwapFunction  string `json:"swap_function,omitempty"` // Router function called on EVM chains, when known
+	RequestedFromAmount string `json:"requested_from_amount,omitempty"` // Amount the swap asked for; FromAmount is what was actually swapped
+	PartialFill   bool   `json:"partial_fill,omitempty"`         // Set when only part of the requested amount was swapped
+	LeftoverFromAmount string `json:"leftover_from_amount,omitempty"` // Unswapped from_token returned to the sender after a partial fill
 }
 
 // BalanceInfo contains token balance information
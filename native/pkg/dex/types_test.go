@@ -2,6 +2,7 @@
 package dex
 
 import (
+	"math"
 	"testing"
 )
 
@@ -149,6 +150,28 @@ func TestSwapQuote_IsExpired(t *testing.T) {
 	}
 }
 
+func TestSwapQuote_TransferFeePercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to float64
+		expected float64
+	}{
+		{"no fee", 0, 0, 0},
+		{"from token fee", 5, 0, 5},
+		{"to token fee", 0, 10, 10},
+		{"both fees compound", 10, 10, 19},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := &SwapQuote{FromTokenTransferFee: tt.from, ToTokenTransferFee: tt.to}
+			if got := quote.TransferFeePercent(); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("Expected %v%%, got %v%%", tt.expected, got)
+			}
+		})
+	}
+}
+
 func TestBalanceInfo_Validation(t *testing.T) {
 	balance := &BalanceInfo{
 		TokenAddress: "0xA0b86a33E6417aAA9f79dF12E43d04Be87da7C9c",
@@ -23,13 +23,25 @@ const (
 	ErrInsufficientBalance ErrorCode = "INSUFFICIENT_BALANCE"
 	ErrInvalidAddress      ErrorCode = "INVALID_ADDRESS"
 	ErrWalletNotFound      ErrorCode = "WALLET_NOT_FOUND"
+	ErrWalletLocked        ErrorCode = "WALLET_LOCKED"
+	ErrWalletFrozen        ErrorCode = "WALLET_FROZEN"
+	ErrRecipientBlocked    ErrorCode = "RECIPIENT_BLOCKED"
+	ErrSpendLimitExceeded  ErrorCode = "SPEND_LIMIT_EXCEEDED"
+	
+	// DEX Errors
+	ErrNoDEXProviders ErrorCode = "NO_DEX_PROVIDERS"
+	ErrPolicyDenied   ErrorCode = "POLICY_DENIED"
+	
+	// Transaction Errors
+	ErrSimulationReverted ErrorCode = "SIMULATION_REVERTED"
 	
 	// Token Errors
 	ErrTokenNotSupported   ErrorCode = "TOKEN_NOT_SUPPORTED"
 	ErrInvalidTokenAddress ErrorCode = "INVALID_TOKEN_ADDRESS"
 	
 	// Permission Errors
-	ErrUnauthorized ErrorCode = "UNAUTHORIZED"
+	ErrUnauthorized  ErrorCode = "UNAUTHORIZED"
+	ErrSigningDenied ErrorCode = "SIGNING_DENIED"
 	
 	// General Errors
 	ErrInternal ErrorCode = "INTERNAL_ERROR"
@@ -127,6 +139,63 @@ func WalletNotFoundError(address string) *Error {
 		WithSuggestion("Ensure the wallet exists and is properly imported")
 }
 
+// WalletLockedError creates a wallet locked error
+func WalletLockedError(operation string) *Error {
+	return New(ErrWalletLocked, "Wallet is locked").
+		WithDetails(fmt.Sprintf("'%s' requires an unlocked wallet", operation)).
+		WithSuggestion("Unlock the wallet in the extension and try again")
+}
+
+// WalletFrozenError creates a wallet frozen error
+func WalletFrozenError(operation string) *Error {
+	return New(ErrWalletFrozen, "Wallet is frozen").
+		WithDetails(fmt.Sprintf("'%s' is refused while the wallet is frozen", operation)).
+		WithSuggestion("The wallet owner must unfreeze it from the extension before it can be used again")
+}
+
+// RecipientBlockedError creates an error for a send refused by the address book
+func RecipientBlockedError(operation string, err error) *Error {
+	return New(ErrRecipientBlocked, "Recipient is blocked by the address book").
+		WithDetails(fmt.Sprintf("'%s' was refused: %v", operation, err)).
+		WithSuggestion("The address book files this recipient under a blocked category; double-check the address, or have the user change the entry from the extension")
+}
+
+// SpendLimitExceededError creates an error for a transaction above a spend limit
+func SpendLimitExceededError(operation string, err error) *Error {
+	return New(ErrSpendLimitExceeded, "Spend limit exceeded").
+		WithDetails(fmt.Sprintf("'%s' was refused: %v", operation, err)).
+		WithSuggestion("Send a smaller amount or wait until earlier sends fall out of the 24-hour window; only the wallet owner can raise security.spend_limits")
+}
+
+// SigningDeniedError creates an error for an operation a signing policy refused
+func SigningDeniedError(operation string, err error) *Error {
+	return New(ErrSigningDenied, "Signing denied by policy").
+		WithDetails(fmt.Sprintf("'%s' was refused: %v", operation, err)).
+		WithSuggestion("The wallet's signing policy does not allow this transaction; change the request or ask the wallet owner to adjust the policy")
+}
+
+// PairDeniedError creates an error for a swap or quote the DEX pair policy
+// refused; err names the token
+func PairDeniedError(operation string, err error) *Error {
+	return New(ErrPolicyDenied, "Token denied by the pair policy").
+		WithDetails(fmt.Sprintf("'%s' was refused: %v", operation, err)).
+		WithSuggestion("dex.pair_policy does not allow swapping this token; do not retry with the same token, or ask the wallet owner to change the policy")
+}
+
+// NoDEXProvidersError creates an error for a swap or quote on a chain no DEX provider serves
+func NoDEXProvidersError(operation string, err error) *Error {
+	return New(ErrNoDEXProviders, err.Error()).
+		WithDetails(fmt.Sprintf("'%s' needs a DEX provider for the chain", operation)).
+		WithSuggestion("Read host://capabilities for the chains with active providers, or ask the user to configure a provider (e.g. OKX credentials) and restart the host")
+}
+
+// SimulationRevertedError creates an error for a send aborted because its preflight simulation reverted
+func SimulationRevertedError(operation string, err error) *Error {
+	return New(ErrSimulationReverted, "Transaction would revert and was not broadcast").
+		WithDetails(fmt.Sprintf("'%s' failed simulation: %v", operation, err)).
+		WithSuggestion("Fix the cause of the revert (balance, allowance, recipient) and try again; pass skip_simulation=true only if the simulation is known to be wrong")
+}
+
 // TokenNotSupportedError creates a token not supported error
 func TokenNotSupportedError(token, chain string) *Error {
 	return New(ErrTokenNotSupported, fmt.Sprintf("Token '%s' is not supported on chain '%s'", token, chain)).
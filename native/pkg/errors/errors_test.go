@@ -60,4 +60,14 @@ func TestMissingRequiredFieldError(t *testing.T) {
 	if err.Suggestion == "" {
 		t.Error("Expected suggestion to be set")
 	}
-}
\ No newline at end of file
+}
+
+func TestWalletLockedError(t *testing.T) {
+	err := WalletLockedError("send transaction")
+	if err.Code != ErrWalletLocked {
+		t.Errorf("Expected code %s, got %s", ErrWalletLocked, err.Code)
+	}
+	if err.Suggestion == "" {
+		t.Error("Expected suggestion to be set")
+	}
+}
@@ -141,3 +141,270 @@ func (eb *EventBroadcaster) BroadcastTransactionRejected(txHash, reason string)
 	})
 	eb.Broadcast(event)
 }
+
+// BroadcastBalanceChanged broadcasts that the token balance of address moved
+// from oldBal to newBal after a transaction confirmed. oldBal is empty when
+// it could not be read before the transaction was sent.
+func (eb *EventBroadcaster) BroadcastBalanceChanged(address, token, oldBal, newBal string) {
+	event := NewEvent(EventTypeBalanceChanged, map[string]interface{}{
+		"address":     address,
+		"token":       token,
+		"old_balance": oldBal,
+		"new_balance": newBal,
+	})
+	eb.Broadcast(event)
+}
+
+// BroadcastWalletFrozen broadcasts a wallet frozen event
+func (eb *EventBroadcaster) BroadcastWalletFrozen(reason, source string, frozenAt time.Time) {
+	event := NewEvent(EventTypeWalletFrozen, map[string]interface{}{
+		"reason":    reason,
+		"source":    source,
+		"frozen_at": frozenAt,
+	})
+	eb.Broadcast(event)
+}
+
+// BroadcastWalletUnfrozen broadcasts a wallet unfrozen event
+func (eb *EventBroadcaster) BroadcastWalletUnfrozen(source string) {
+	event := NewEvent(EventTypeWalletUnfrozen, map[string]interface{}{
+		"source": source,
+	})
+	eb.Broadcast(event)
+}
+
+// BroadcastLargeTransactionWarning broadcasts that a send or approval meets the
+// large transaction threshold. confirmed is false while the transaction is held
+// back waiting for confirm_large=true.
+func (eb *EventBroadcaster) BroadcastLargeTransactionWarning(txHash, chain, from, to, amount, token string, usdValue float64, reasons []string, confirmed bool) {
+	event := NewEvent(EventTypeLargeTransactionWarning, map[string]interface{}{
+		"transaction_hash": txHash,
+		"chain":            chain,
+		"from":             from,
+		"to":               to,
+		"amount":           amount,
+		"token":            token,
+		"usd_value":        usdValue,
+		"reasons":          reasons,
+		"confirmed":        confirmed,
+	})
+	eb.Broadcast(event)
+}
+
+// BroadcastSpendLimitExceeded broadcasts that a transaction was refused
+// because it is above the max_tx_value or daily_limit of its token. spent is
+// what was sent over the last 24 hours, for daily limits.
+func (eb *EventBroadcaster) BroadcastSpendLimitExceeded(chain, from, to, amount, token, limit string, max float64, spent string) {
+	event := NewEvent(EventTypeSpendLimitExceeded, map[string]interface{}{
+		"chain":  chain,
+		"from":   from,
+		"to":     to,
+		"amount": amount,
+		"token":  token,
+		"limit":  limit,
+		"max":    max,
+		"spent":  spent,
+	})
+	eb.Broadcast(event)
+}
+
+// BroadcastAutoApproved broadcasts that a dApp transaction matched the
+// auto-approval rule named rule and was sent without explicit approval
+func (eb *EventBroadcaster) BroadcastAutoApproved(txHash, chain, from, to, amount, token, origin, rule string) {
+	event := NewEvent(EventTypeAutoApproved, map[string]interface{}{
+		"transaction_hash": txHash,
+		"chain":            chain,
+		"from":             from,
+		"to":               to,
+		"amount":           amount,
+		"token":            token,
+		"origin":           origin,
+		"rule":             rule,
+	})
+	eb.Broadcast(event)
+}
+
+// BroadcastPendingQueueFull broadcasts that a dApp transaction was refused
+// because the pending queue is at its configured maximum
+func (eb *EventBroadcaster) BroadcastPendingQueueFull(chain, from, to, origin, reason string) {
+	event := NewEvent(EventTypePendingQueueFull, map[string]interface{}{
+		"chain":  chain,
+		"from":   from,
+		"to":     to,
+		"origin": origin,
+		"reason": reason,
+	})
+	eb.Broadcast(event)
+}
+
+// BroadcastScheduledTransactionExecuted broadcasts that run number run of a
+// scheduled transaction was sent. status is the schedule's status afterwards.
+func (eb *EventBroadcaster) BroadcastScheduledTransactionExecuted(scheduleID, txHash, chain, from, to, amount, token string, run int, status string) {
+	event := NewEvent(EventTypeScheduledTransactionExecuted, map[string]interface{}{
+		"schedule_id":      scheduleID,
+		"transaction_hash": txHash,
+		"chain":            chain,
+		"from":             from,
+		"to":               to,
+		"amount":           amount,
+		"token":            token,
+		"run":              run,
+		"status":           status,
+	})
+	eb.Broadcast(event)
+}
+
+// BroadcastScheduledTransactionFailed broadcasts that run number run of a
+// scheduled transaction could not be sent, e.g. because a limit refused it
+func (eb *EventBroadcaster) BroadcastScheduledTransactionFailed(scheduleID, chain, from, to, amount, token, reason string, run int, status string) {
+	event := NewEvent(EventTypeScheduledTransactionFailed, map[string]interface{}{
+		"schedule_id": scheduleID,
+		"chain":       chain,
+		"from":        from,
+		"to":          to,
+		"amount":      amount,
+		"token":       token,
+		"reason":      reason,
+		"run":         run,
+		"status":      status,
+	})
+	eb.Broadcast(event)
+}
+
+// BroadcastTriggerFired broadcasts that a price trigger met its condition at
+// price and ran its action. txHash is empty and reason set when the action
+// failed or was refused.
+func (eb *EventBroadcaster) BroadcastTriggerFired(triggerID, symbol, comparison string, threshold, price float64, action, chain, txHash, reason string, fires int, status string) {
+	data := map[string]interface{}{
+		"trigger_id": triggerID,
+		"symbol":     symbol,
+		"comparison": comparison,
+		"threshold":  threshold,
+		"price":      price,
+		"action":     action,
+		"chain":      chain,
+		"tx_hash":    txHash,
+		"success":    reason == "",
+		"fires":      fires,
+		"status":     status,
+	}
+	if reason != "" {
+		data["reason"] = reason
+	}
+	eb.Broadcast(NewEvent(EventTypeTriggerFired, data))
+}
+
+// BroadcastCacheWarmed broadcasts that the background fetch of balances and
+// prices after unlock has ended. complete is false when it was cut short by
+// locking the wallet or by its timeout.
+func (eb *EventBroadcaster) BroadcastCacheWarmed(balances, prices, failures int, duration time.Duration, complete bool) {
+	event := NewEvent(EventTypeCacheWarmed, map[string]interface{}{
+		"balances":    balances,
+		"prices":      prices,
+		"failures":    failures,
+		"duration_ms": duration.Milliseconds(),
+		"complete":    complete,
+	})
+	eb.Broadcast(event)
+}
+
+// BroadcastNativeMessagingError broadcasts that input from the browser
+// extension could not be read as Native Messaging frames. discardedBytes is
+// how much input was skipped to find the next frame.
+func (eb *EventBroadcaster) BroadcastNativeMessagingError(reason string, discardedBytes int) {
+	event := NewEvent(EventTypeNativeMessagingError, map[string]interface{}{
+		"reason":          reason,
+		"discarded_bytes": discardedBytes,
+	})
+	eb.Broadcast(event)
+}
+
+// BroadcastStakeDelegated broadcasts that owner delegated lamports to the
+// validator of voteAccount through the new stakeAccount
+func (eb *EventBroadcaster) BroadcastStakeDelegated(txHash, owner, stakeAccount, voteAccount string, lamports uint64) {
+	event := NewEvent(EventTypeStakeDelegated, map[string]interface{}{
+		"tx_hash":       txHash,
+		"chain":         "solana",
+		"owner":         owner,
+		"stake_account": stakeAccount,
+		"vote_account":  voteAccount,
+		"lamports":      lamports,
+	})
+	eb.Broadcast(event)
+}
+
+// BroadcastStakeDeactivated broadcasts that stakeAccount was deactivated and
+// its lamports start cooling down
+func (eb *EventBroadcaster) BroadcastStakeDeactivated(txHash, owner, stakeAccount, voteAccount string, lamports uint64) {
+	event := NewEvent(EventTypeStakeDeactivated, map[string]interface{}{
+		"tx_hash":       txHash,
+		"chain":         "solana",
+		"owner":         owner,
+		"stake_account": stakeAccount,
+		"vote_account":  voteAccount,
+		"lamports":      lamports,
+	})
+	eb.Broadcast(event)
+}
+
+// BroadcastStakeWithdrawn broadcasts that lamports were withdrawn from stakeAccount to to
+func (eb *EventBroadcaster) BroadcastStakeWithdrawn(txHash, owner, stakeAccount, to string, lamports uint64) {
+	event := NewEvent(EventTypeStakeWithdrawn, map[string]interface{}{
+		"tx_hash":       txHash,
+		"chain":         "solana",
+		"owner":         owner,
+		"stake_account": stakeAccount,
+		"to":            to,
+		"lamports":      lamports,
+	})
+	eb.Broadcast(event)
+}
+
+// BroadcastTransactionOutcome broadcasts the final outcome of a transaction a
+// send or approval asked to be notified about, keyed by its correlation id
+func (eb *EventBroadcaster) BroadcastTransactionOutcome(outcome map[string]interface{}) {
+	event := NewEvent(EventTypeTransactionOutcome, outcome)
+	eb.Broadcast(event)
+}
+
+// BroadcastChainReorgDetected broadcasts that depth recently seen blocks of
+// chain were replaced; atLeast is set when the reorg went deeper than the
+// blocks the monitor remembers
+func (eb *EventBroadcaster) BroadcastChainReorgDetected(chain string, depth int, atLeast bool, oldHead, newHead uint64, oldHash, newHash string) {
+	event := NewEvent(EventTypeChainReorgDetected, map[string]interface{}{
+		"chain":    chain,
+		"depth":    depth,
+		"at_least": atLeast,
+		"old_head": oldHead,
+		"old_hash": oldHash,
+		"new_head": newHead,
+		"new_hash": newHash,
+	})
+	eb.Broadcast(event)
+}
+
+// BroadcastChainStalled broadcasts that chain produced no new block or slot
+// since lastAdvance. lastError is set when its RPC could not be read.
+func (eb *EventBroadcaster) BroadcastChainStalled(chain string, lastBlock uint64, lastAdvance time.Time, stalledFor time.Duration, lastError string) {
+	data := map[string]interface{}{
+		"chain":           chain,
+		"last_block":      lastBlock,
+		"last_advance":    lastAdvance.UTC().Format(time.RFC3339),
+		"stalled_seconds": int64(stalledFor.Seconds()),
+	}
+	if lastError != "" {
+		data["last_error"] = lastError
+	}
+	eb.Broadcast(NewEvent(EventTypeChainStalled, data))
+}
+
+// BroadcastNetworkChanged broadcasts that the EVM chain dApps talk to is now
+// chain; chainID is hex-encoded the way eth_chainId returns it
+func (eb *EventBroadcaster) BroadcastNetworkChanged(chain, chainID, source string) {
+	event := NewEvent(EventTypeNetworkChanged, map[string]interface{}{
+		"chain":    chain,
+		"chain_id": chainID,
+		"source":   source,
+	})
+	eb.Broadcast(event)
+}
@@ -28,7 +28,28 @@ const (
 	EventTypeTransactionConfirmed          = "transaction_confirmed"
 	EventTypeTransactionRejected           = "transaction_rejected"
 	EventTypeTransactionError              = "transaction_error"
+	EventTypeTransactionReorged            = "transaction_reorged"
+	EventTypeTransactionReplacedConfirmed  = "transaction_replaced_confirmed"
 	EventTypeBalanceUpdated                = "balance_updated"
+	EventTypeBalanceChanged                = "balance_changed"
 	EventTypeWalletConnected               = "wallet_connected"
 	EventTypeWalletDisconnected            = "wallet_disconnected"
+	EventTypeWalletFrozen                  = "wallet_frozen"
+	EventTypeWalletUnfrozen                = "wallet_unfrozen"
+	EventTypeLargeTransactionWarning       = "large_transaction_warning"
+	EventTypeSpendLimitExceeded            = "spend_limit_exceeded"
+	EventTypeAutoApproved                  = "auto_approved"
+	EventTypePendingQueueFull              = "pending_queue_full"
+	EventTypeScheduledTransactionExecuted  = "scheduled_transaction_executed"
+	EventTypeScheduledTransactionFailed    = "scheduled_transaction_failed"
+	EventTypeTriggerFired                  = "trigger_fired"
+	EventTypeCacheWarmed                   = "cache_warmed"
+	EventTypeNativeMessagingError          = "native_messaging_error"
+	EventTypeStakeDelegated                = "stake_delegated"
+	EventTypeStakeDeactivated              = "stake_deactivated"
+	EventTypeStakeWithdrawn                = "stake_withdrawn"
+	EventTypeTransactionOutcome            = "transaction_outcome"
+	EventTypeChainReorgDetected            = "chain_reorg_detected"
+	EventTypeChainStalled                  = "chain_stalled"
+	EventTypeNetworkChanged                = "network_changed"
 )
\ No newline at end of file
@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39/wordlists"
+)
+
+// RedactedValue replaces sensitive values in logged payloads
+const RedactedValue = "[REDACTED]"
+
+// minMnemonicWords is the shortest BIP-39 phrase Redact looks for in free text
+const minMnemonicWords = 12
+
+// SensitiveFields lists the payload keys whose values are never logged. Keys
+// are matched case-insensitively, ignoring "_" and "-", so "private_key",
+// "privateKey" and "Private-Key" are all redacted.
+var SensitiveFields = []string{
+	"mnemonic",
+	"seedphrase",
+	"seed",
+	"privatekey",
+	"secretkey",
+	"secret",
+	"password",
+	"newpassword",
+	"oldpassword",
+	"passphrase",
+	"signature",
+	"signedtransaction",
+	"signedtx",
+	"rawtransaction",
+	"apikey",
+	"accesskey",
+	"authorization",
+	"okaccesskey",
+	"okaccesssign",
+	"okaccesspassphrase",
+}
+
+var sensitiveFieldSet = func() map[string]bool {
+	set := make(map[string]bool, len(SensitiveFields))
+	for _, field := range SensitiveFields {
+		set[normalizeFieldName(field)] = true
+	}
+	return set
+}()
+
+var bip39Words = func() map[string]bool {
+	set := make(map[string]bool, len(wordlists.English))
+	for _, word := range wordlists.English {
+		set[word] = true
+	}
+	return set
+}()
+
+// IsSensitiveField reports whether values stored under key must be redacted
+func IsSensitiveField(key string) bool {
+	return sensitiveFieldSet[normalizeFieldName(key)]
+}
+
+func normalizeFieldName(key string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+}
+
+// Redact returns a JSON rendering of v with sensitive fields and any BIP-39
+// mnemonic phrases replaced by RedactedValue, for use in debug logs. Raw JSON
+// ([]byte or json.RawMessage) is decoded first so nested payloads are covered.
+func Redact(v interface{}) string {
+	var raw []byte
+	switch value := v.(type) {
+	case json.RawMessage:
+		raw = value
+	case []byte:
+		raw = value
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return RedactedValue
+		}
+		raw = encoded
+	}
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		// Not JSON; treat it as free text
+		return RedactText(string(raw))
+	}
+	encoded, err := json.Marshal(redactValue(decoded))
+	if err != nil {
+		return RedactedValue
+	}
+	return string(encoded)
+}
+
+// redactValue walks a decoded JSON value, redacting sensitive keys and
+// decoding strings that themselves hold JSON, such as RPC params and results
+func redactValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if IsSensitiveField(key) {
+				value[key] = RedactedValue
+				continue
+			}
+			value[key] = redactValue(field)
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactValue(item)
+		}
+		return value
+	case string:
+		trimmed := strings.TrimSpace(value)
+		if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			var nested interface{}
+			if err := json.Unmarshal([]byte(trimmed), &nested); err == nil {
+				return redactValue(nested)
+			}
+		}
+		return RedactText(value)
+	default:
+		return value
+	}
+}
+
+// RedactText replaces every run of twelve or more BIP-39 words in text, which
+// catches mnemonics that appear in free-form messages and tool output
+func RedactText(text string) string {
+	words := strings.Fields(text)
+	if len(words) < minMnemonicWords {
+		return text
+	}
+
+	redacted := make([]string, 0, len(words))
+	for i := 0; i < len(words); {
+		j := i
+		for j < len(words) && bip39Words[strings.ToLower(strings.Trim(words[j], "`\"'.,;:"))] {
+			j++
+		}
+		if j-i >= minMnemonicWords {
+			redacted = append(redacted, RedactedValue)
+			i = j
+			continue
+		}
+		if j == i {
+			j = i + 1
+		}
+		redacted = append(redacted, words[i:j]...)
+		i = j
+	}
+	if len(redacted) == len(words) {
+		return text
+	}
+	return strings.Join(redacted, " ")
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestRedactSensitiveFields(t *testing.T) {
+	payload := map[string]interface{}{
+		"method": "import_wallet",
+		"params": map[string]interface{}{
+			"mnemonic":    testMnemonic,
+			"password":    "hunter22",
+			"private_key": "0x4c0883a69102937d6231471b5dbb6204fe512961708279f3a6d1b5bd0d1c9a3f",
+			"chain":       "ethereum",
+		},
+		"headers": []interface{}{map[string]interface{}{"OK-ACCESS-KEY": "okx-key", "apiKey": "other-key"}},
+		"result":  `{"signature":"0xdeadbeef","tx_hash":"0xabc"}`,
+	}
+
+	redacted := Redact(payload)
+	for _, secret := range []string{"abandon", "hunter22", "0x4c0883a6", "okx-key", "other-key", "0xdeadbeef"} {
+		assert.NotContains(t, redacted, secret)
+	}
+	assert.Contains(t, redacted, `"chain":"ethereum"`)
+	assert.Contains(t, redacted, `"tx_hash":"0xabc"`)
+	assert.Contains(t, redacted, RedactedValue)
+}
+
+func TestRedactRawJSONAndText(t *testing.T) {
+	raw := json.RawMessage(`{"params":{"mnemonic":"` + testMnemonic + `"}}`)
+	assert.NotContains(t, Redact(raw), "abandon")
+
+	// Mnemonics outside sensitive keys are caught by the word list
+	note := "Back up `" + testMnemonic + "` somewhere safe"
+	assert.Equal(t, "Back up "+RedactedValue+" somewhere safe", RedactText(note))
+	assert.NotContains(t, Redact(map[string]string{"note": note}), "abandon")
+
+	// Ordinary text is left untouched
+	text := "Swap 1.5 ETH for USDC on the ethereum chain with 0.5% slippage"
+	assert.Equal(t, text, RedactText(text))
+	assert.Equal(t, "not json", Redact([]byte("not json")))
+}
+
+func TestIsSensitiveField(t *testing.T) {
+	for _, key := range []string{"mnemonic", "Private_Key", "privateKey", "new-password", "OK-ACCESS-SIGN"} {
+		assert.True(t, IsSensitiveField(key), key)
+	}
+	for _, key := range []string{"address", "chain", "tx_hash", "amount"} {
+		assert.False(t, IsSensitiveField(key), key)
+	}
+	assert.False(t, strings.Contains(Redact(nil), RedactedValue))
+}
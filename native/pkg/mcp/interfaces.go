@@ -1,6 +1,10 @@
 package mcp
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -22,3 +26,20 @@ func RegisterResource(s *server.MCPServer, res IResource) {
 func RegisterTool(s *server.MCPServer, tool ITool) {
 	s.AddTool(tool.GetMeta(), tool.GetHandler())
 }
+
+// ListTools returns the tools registered on s, sorted by name. It goes through
+// tools/list so tools added with s.AddTool directly are included.
+func ListTools(ctx context.Context, s *server.MCPServer) ([]mcp.Tool, error) {
+	response := s.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	switch response := response.(type) {
+	case mcp.JSONRPCResponse:
+		if result, ok := response.Result.(mcp.ListToolsResult); ok {
+			return result.Tools, nil
+		}
+		return nil, fmt.Errorf("unexpected tools/list result %T", response.Result)
+	case mcp.JSONRPCError:
+		return nil, fmt.Errorf("tools/list failed: %s", response.Error.Message)
+	default:
+		return nil, fmt.Errorf("unexpected tools/list response %T", response)
+	}
+}
@@ -0,0 +1,140 @@
+// Package resources provides MCP resource implementations for the Algonius Native Host.
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Capabilities is the body of the host://capabilities resource
+type Capabilities struct {
+	Version      string              `json:"version"`
+	Chains       []string            `json:"chains"`
+	Tools        []ToolCapability    `json:"tools"`
+	DEXProviders map[string][]string `json:"dex_providers"` // chain -> enabled providers, best first
+	Features     map[string]bool     `json:"features"`
+}
+
+// ToolCapability names a tool and the version of its input schema. The
+// version is derived from the schema, so it changes exactly when the tool's
+// parameters do.
+type ToolCapability struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// CapabilitiesResource implements the IResource interface for the
+// "host://capabilities" MCP resource. It describes what this host supports
+// so agents can adapt instead of assuming; reading it needs no unlocked
+// wallet and makes no network calls.
+type CapabilitiesResource struct {
+	Version    string
+	Chains     []string
+	Config     *config.Config
+	Aggregator dex.IDEXAggregator
+	ListTools  func(ctx context.Context) ([]mcp.Tool, error)
+}
+
+// NewCapabilitiesResource creates a CapabilitiesResource for a host running
+// version with cfg. listTools returns the registered tools; a nil aggregator
+// reports no DEX providers.
+func NewCapabilitiesResource(version string, cfg *config.Config, aggregator dex.IDEXAggregator, listTools func(ctx context.Context) ([]mcp.Tool, error)) *CapabilitiesResource {
+	return &CapabilitiesResource{
+		Version:    version,
+		Chains:     NewSupportedChainsResource().Chains,
+		Config:     cfg,
+		Aggregator: aggregator,
+		ListTools:  listTools,
+	}
+}
+
+// GetMeta returns the MCP resource definition for host capabilities.
+func (r *CapabilitiesResource) GetMeta() mcp.Resource {
+	return mcp.NewResource(
+		"host://capabilities",
+		"Host Capabilities",
+		mcp.WithResourceDescription("Host version, supported chains, available tools with their schema versions, enabled DEX providers per chain and feature flags (paper_trading, auto_approval, hardware_wallets)"),
+		mcp.WithMIMEType("application/json"),
+	)
+}
+
+// GetHandler returns the handler function for the capabilities resource.
+func (r *CapabilitiesResource) GetHandler() server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		capabilities, err := r.capabilities(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(capabilities)
+		if err != nil {
+			return nil, err
+		}
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      "host://capabilities",
+				MIMEType: "application/json",
+				Text:     string(data),
+			},
+		}, nil
+	}
+}
+
+// capabilities collects the current capabilities
+func (r *CapabilitiesResource) capabilities(ctx context.Context) (*Capabilities, error) {
+	capabilities := &Capabilities{
+		Version:      r.Version,
+		Chains:       r.Chains,
+		Tools:        []ToolCapability{},
+		DEXProviders: make(map[string][]string),
+		Features: map[string]bool{
+			"paper_trading": r.Config != nil && r.Config.PaperTrading(),
+			"auto_approval": r.Config != nil && r.Config.Security.AutoApproval.Enabled,
+			// The host has no hardware signer integration yet
+			"hardware_wallets": false,
+		},
+	}
+
+	if r.ListTools != nil {
+		tools, err := r.ListTools(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, tool := range tools {
+			version, err := toolSchemaVersion(tool)
+			if err != nil {
+				return nil, err
+			}
+			capabilities.Tools = append(capabilities.Tools, ToolCapability{Name: tool.Name, Version: version})
+		}
+	}
+
+	var active map[string][]string
+	if r.Aggregator != nil {
+		active = dex.ActiveProviders(r.Aggregator)
+	}
+	for _, chainName := range r.Chains {
+		capabilities.DEXProviders[chainName] = append([]string{}, active[chainName]...)
+	}
+	return capabilities, nil
+}
+
+// toolSchemaVersion fingerprints a tool's input schema
+func toolSchemaVersion(tool mcp.Tool) (string, error) {
+	schema := tool.RawInputSchema
+	if schema == nil {
+		encoded, err := json.Marshal(tool.InputSchema)
+		if err != nil {
+			return "", err
+		}
+		schema = encoded
+	}
+	sum := sha256.Sum256(schema)
+	return hex.EncodeToString(sum[:4]), nil
+}
@@ -0,0 +1,82 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
+	"github.com/algonius/algonius-wallet/native/pkg/dex/providers"
+	hostmcp "github.com/algonius/algonius-wallet/native/pkg/mcp"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func readCapabilities(t *testing.T, resource *CapabilitiesResource) Capabilities {
+	contents, err := resource.GetHandler()(context.Background(), mcp.ReadResourceRequest{})
+	require.NoError(t, err)
+	require.Len(t, contents, 1)
+	text := contents[0].(mcp.TextResourceContents)
+	assert.Equal(t, "application/json", text.MIMEType)
+
+	var capabilities Capabilities
+	require.NoError(t, json.Unmarshal([]byte(text.Text), &capabilities))
+	return capabilities
+}
+
+func TestCapabilitiesResource(t *testing.T) {
+	s := server.NewMCPServer("test", "0.1.0")
+	s.AddTool(mcp.NewTool("get_balance", mcp.WithString("address", mcp.Required())), nil)
+	s.AddTool(mcp.NewTool("create_wallet", mcp.WithString("chain", mcp.Required())), nil)
+
+	aggregator := dex.NewDEXAggregator(zap.NewNop())
+	require.NoError(t, aggregator.RegisterProvider(providers.NewMockProvider(providers.MockConfig{
+		Name:            "MockOKX",
+		SupportedChains: []string{"501"},
+	}, zap.NewNop())))
+
+	cfg := config.DefaultConfig()
+	cfg.Security.AutoApproval.Enabled = true
+	cfg.Chains.Solana.Broadcast.Channel = "paper"
+
+	capabilities := readCapabilities(t, NewCapabilitiesResource("0.1.0", cfg, aggregator,
+		func(ctx context.Context) ([]mcp.Tool, error) { return hostmcp.ListTools(ctx, s) }))
+
+	assert.Equal(t, "0.1.0", capabilities.Version)
+	assert.Equal(t, []string{"ethereum", "bsc", "solana"}, capabilities.Chains)
+	require.Len(t, capabilities.Tools, 2)
+	assert.Equal(t, "create_wallet", capabilities.Tools[0].Name)
+	assert.Equal(t, "get_balance", capabilities.Tools[1].Name)
+	assert.Len(t, capabilities.Tools[0].Version, 8)
+	assert.NotEqual(t, capabilities.Tools[0].Version, capabilities.Tools[1].Version)
+	assert.Equal(t, []string{"MockOKX"}, capabilities.DEXProviders["solana"])
+	assert.Empty(t, capabilities.DEXProviders["ethereum"])
+	assert.True(t, capabilities.Features["paper_trading"])
+	assert.True(t, capabilities.Features["auto_approval"])
+	assert.False(t, capabilities.Features["hardware_wallets"])
+}
+
+func TestCapabilitiesToolVersionTracksSchema(t *testing.T) {
+	before, err := toolSchemaVersion(mcp.NewTool("send", mcp.WithString("to")))
+	require.NoError(t, err)
+	same, err := toolSchemaVersion(mcp.NewTool("send", mcp.WithString("to")))
+	require.NoError(t, err)
+	after, err := toolSchemaVersion(mcp.NewTool("send", mcp.WithString("to"), mcp.WithString("memo")))
+	require.NoError(t, err)
+
+	assert.Equal(t, before, same)
+	assert.NotEqual(t, before, after)
+}
+
+func TestCapabilitiesResourceWithoutAggregator(t *testing.T) {
+	capabilities := readCapabilities(t, NewCapabilitiesResource("0.1.0", config.DefaultConfig(), nil, nil))
+
+	assert.Empty(t, capabilities.Tools)
+	assert.Equal(t, []string{}, capabilities.DEXProviders["bsc"])
+	assert.False(t, capabilities.Features["paper_trading"])
+	assert.False(t, capabilities.Features["auto_approval"])
+}
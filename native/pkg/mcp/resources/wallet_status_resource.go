@@ -141,5 +141,25 @@ func (r *WalletStatusResource) formatWalletStatusMarkdown(status *wallet.WalletS
 		builder.WriteString("- No chains configured\n")
 	}
 
+	// RPC health section
+	builder.WriteString("\n## Last Successful RPC Call\n")
+	if len(status.LastSuccessfulCall) == 0 {
+		builder.WriteString("- No successful RPC calls recorded yet\n")
+	} else {
+		for _, chain := range []string{"ethereum", "bsc", "solana"} {
+			if ts, exists := status.LastSuccessfulCall[chain]; exists {
+				builder.WriteString(fmt.Sprintf("- **%s**: %s\n", chain, time.Unix(ts, 0).UTC().Format("2006-01-02 15:04:05 UTC")))
+			}
+		}
+	}
+
+	// Circuit breakers are only listed once a provider has been called
+	if len(status.ProviderBreakers) > 0 {
+		builder.WriteString("\n## Provider Circuit Breakers\n")
+		for _, b := range status.ProviderBreakers {
+			builder.WriteString(fmt.Sprintf("- **%s** (%s): %s, %d consecutive failures\n", b.Provider, b.Host, b.State, b.ConsecutiveFailures))
+		}
+	}
+
 	return builder.String()
 }
\ No newline at end of file
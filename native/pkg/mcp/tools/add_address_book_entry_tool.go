@@ -0,0 +1,123 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// AddAddressBookEntryTool implements the MCP "add_address_book_entry" tool,
+// which names a destination address on a chain.
+type AddAddressBookEntryTool struct {
+	manager wallet.IWalletManager
+}
+
+// NewAddAddressBookEntryTool constructs an AddAddressBookEntryTool with the given wallet manager.
+func NewAddAddressBookEntryTool(manager wallet.IWalletManager) *AddAddressBookEntryTool {
+	return &AddAddressBookEntryTool{manager: manager}
+}
+
+// GetMeta returns the MCP tool definition for "add_address_book_entry".
+func (t *AddAddressBookEntryTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("add_address_book_entry",
+		mcp.WithDescription("Add an address to the address book with a label and category, or update its entry. Labels appear in transaction history, pending transactions and send results. Sends to categories in wallet.address_book.blocked_categories (e.g. scam) are refused and to warn_categories need confirm_contract_recipient; entries in those categories can only be changed by the user from the extension"),
+		mcp.WithString("chain",
+			mcp.Required(),
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol"),
+		),
+		mcp.WithString("address",
+			mcp.Required(),
+			mcp.Description("Address to name"),
+		),
+		mcp.WithString("label",
+			mcp.Required(),
+			mcp.Description(fmt.Sprintf("Name of the address, at most %d characters", wallet.MaxAddressLabelLength)),
+		),
+		mcp.WithString("category",
+			mcp.Description("Optional category, e.g. own, exchange, counterparty, scam"),
+		),
+		mcp.WithString("notes",
+			mcp.Description(fmt.Sprintf("Optional notes, at most %d characters", wallet.MaxAddressNotesLength)),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "add_address_book_entry" tool.
+func (t *AddAddressBookEntryTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chain, err := req.RequireString("chain")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("chain")), nil
+		}
+		normalizedChain, err := toolutils.NormalizeChainName(chain)
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+		address, err := req.RequireString("address")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("address")), nil
+		}
+		label, err := req.RequireString("label")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("label")), nil
+		}
+
+		if toolErr := requireUserManagedEntryUnchanged(t.manager, normalizedChain, address, "replace"); toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
+		entry, err := t.manager.AddAddressBookEntry(ctx, &wallet.AddressBookEntry{
+			Chain:    normalizedChain,
+			Address:  address,
+			Label:    label,
+			Category: req.GetString("category", ""),
+			Notes:    req.GetString("notes", ""),
+		})
+		if stdErrors.Is(err, wallet.ErrInvalidAddressBookEntry) {
+			return toolutils.FormatErrorResult(errors.ValidationError("entry", err.Error())), nil
+		}
+		if err != nil {
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("add address book entry", err)), nil
+		}
+
+		markdown := "### Address Book Entry Saved\n\n" + formatAddressBookEntryMarkdown(entry)
+		if policy := t.manager.AddressBookPolicy(entry.Category); policy != "" {
+			markdown += fmt.Sprintf("- **Send Policy**: `%s`\n", policy)
+		}
+		return mcp.NewToolResultText(markdown), nil
+	}
+}
+
+// requireUserManagedEntryUnchanged refuses to let an agent replace or remove
+// an entry whose category blocks or warns about sends, since that would lift
+// the protection
+func requireUserManagedEntryUnchanged(manager wallet.IWalletManager, chainName, address, action string) *errors.Error {
+	existing, ok := manager.LookupAddressBook(chainName, address)
+	if !ok || manager.AddressBookPolicy(existing.Category) == "" {
+		return nil
+	}
+	return errors.UnauthorizedError(action + " address book entry").
+		WithDetails(fmt.Sprintf("%s is listed as %s, which is protected by wallet.address_book", address, existing.Display())).
+		WithSuggestion("Ask the user to change the entry from the extension")
+}
+
+// formatAddressBookEntryMarkdown renders one address book entry for the agent
+func formatAddressBookEntryMarkdown(entry *wallet.AddressBookEntry) string {
+	markdown := fmt.Sprintf("- **Chain**: `%s`\n", entry.Chain) +
+		fmt.Sprintf("- **Address**: `%s`\n", entry.Address) +
+		fmt.Sprintf("- **Label**: `%s`\n", entry.Label)
+	if entry.Category != "" {
+		markdown += fmt.Sprintf("- **Category**: `%s`\n", entry.Category)
+	}
+	if entry.Notes != "" {
+		markdown += fmt.Sprintf("- **Notes**: %s\n", entry.Notes)
+	}
+	return markdown
+}
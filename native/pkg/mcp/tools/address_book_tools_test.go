@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWalletManagerForAddressBook struct {
+	*wallet.MockWalletManager
+	book *wallet.AddressBook
+}
+
+func newMockWalletManagerForAddressBook() *mockWalletManagerForAddressBook {
+	return &mockWalletManagerForAddressBook{
+		MockWalletManager: &wallet.MockWalletManager{},
+		book:              wallet.NewAddressBook(nil),
+	}
+}
+
+func (m *mockWalletManagerForAddressBook) AddAddressBookEntry(ctx context.Context, entry *wallet.AddressBookEntry) (*wallet.AddressBookEntry, error) {
+	return m.book.Put(ctx, entry)
+}
+
+func (m *mockWalletManagerForAddressBook) RemoveAddressBookEntry(ctx context.Context, chainName, address string) (*wallet.AddressBookEntry, error) {
+	return m.book.Remove(ctx, chainName, address)
+}
+
+func (m *mockWalletManagerForAddressBook) ListAddressBook(ctx context.Context, chainName, category string) ([]*wallet.AddressBookEntry, error) {
+	return m.book.List(chainName, category), nil
+}
+
+func (m *mockWalletManagerForAddressBook) LookupAddressBook(chainName, address string) (*wallet.AddressBookEntry, bool) {
+	return m.book.Lookup(chainName, address)
+}
+
+func (m *mockWalletManagerForAddressBook) AddressBookPolicy(category string) string {
+	return m.book.Policy(category)
+}
+
+func TestAddressBookTools(t *testing.T) {
+	manager := newMockWalletManagerForAddressBook()
+	add := NewAddAddressBookEntryTool(manager).GetHandler()
+
+	result, err := add(context.Background(), scheduleRequest("add_address_book_entry", map[string]any{
+		"chain":    "eth",
+		"address":  "0x2222222222222222222222222222222222222222",
+		"label":    "Binance deposit",
+		"category": "exchange",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Label**: `Binance deposit`")
+	assert.Contains(t, text, "- **Chain**: `ethereum`")
+	assert.NotContains(t, text, "Send Policy")
+
+	result, err = add(context.Background(), scheduleRequest("add_address_book_entry", map[string]any{
+		"chain":    "eth",
+		"address":  "0x3333333333333333333333333333333333333333",
+		"label":    "Fake airdrop",
+		"category": "scam",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "- **Send Policy**: `block`")
+
+	result, err = NewListAddressBookTool(manager).GetHandler()(context.Background(), scheduleRequest("list_address_book", map[string]any{"chain": "ethereum"}))
+	require.NoError(t, err)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Count**: `2`")
+	assert.Contains(t, text, "#### Binance deposit")
+
+	remove := NewRemoveAddressBookEntryTool(manager).GetHandler()
+	result, err = remove(context.Background(), scheduleRequest("remove_address_book_entry", map[string]any{
+		"chain":   "eth",
+		"address": "0x2222222222222222222222222222222222222222",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "### Address Book Entry Removed")
+
+	result, err = remove(context.Background(), scheduleRequest("remove_address_book_entry", map[string]any{
+		"chain":   "eth",
+		"address": "0x2222222222222222222222222222222222222222",
+	}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestAddressBookToolsCannotLiftProtection(t *testing.T) {
+	manager := newMockWalletManagerForAddressBook()
+	_, err := manager.book.Put(context.Background(), &wallet.AddressBookEntry{
+		Chain:    "ethereum",
+		Address:  "0x3333333333333333333333333333333333333333",
+		Label:    "Fake airdrop",
+		Category: wallet.AddressCategoryScam,
+	})
+	require.NoError(t, err)
+
+	result, err := NewAddAddressBookEntryTool(manager).GetHandler()(context.Background(), scheduleRequest("add_address_book_entry", map[string]any{
+		"chain":    "eth",
+		"address":  "0x3333333333333333333333333333333333333333",
+		"label":    "Friend",
+		"category": "counterparty",
+	}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "UNAUTHORIZED")
+
+	result, err = NewRemoveAddressBookEntryTool(manager).GetHandler()(context.Background(), scheduleRequest("remove_address_book_entry", map[string]any{
+		"chain":   "eth",
+		"address": "0x3333333333333333333333333333333333333333",
+	}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	entry, ok := manager.book.Lookup("ethereum", "0x3333333333333333333333333333333333333333")
+	require.True(t, ok)
+	assert.Equal(t, wallet.AddressCategoryScam, entry.Category)
+}
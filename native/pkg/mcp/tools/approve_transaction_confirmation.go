@@ -0,0 +1,178 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"go.uber.org/zap"
+)
+
+// evmConfirmationFunc matches the ConfirmTransaction method of the EVM chains
+type evmConfirmationFunc func(ctx context.Context, txHash string, requiredConfirmations uint64) (*chain.TransactionConfirmation, error)
+
+// monitorEVMTransaction polls an EVM transaction until it reaches the required
+// confirmations, fails, is superseded by a confirmed replacement, or the timeout
+// expires. Confirmations restart from the new inclusion block whenever a reorg
+// moves or drops the transaction.
+func (t *ApproveTransactionTool) monitorEVMTransaction(ctx context.Context, chainName string, timeout time.Duration, requiredConfirmations uint64, confirm evmConfirmationFunc, txHash string, tx *wallet.PendingTransaction) {
+	t.logger.Info("Starting real-time EVM transaction monitoring",
+		zap.String("tx_hash", txHash),
+		zap.String("chain", chainName))
+
+	// Nonce 0 is indistinguishable from an unset nonce, so only explicit nonces
+	// are paired; replacement senders track their hashes themselves.
+	if tx.Nonce > 0 && tx.From != "" {
+		t.replacements.Track(chainName, tx.From, tx.Nonce, txHash)
+	}
+	defer t.replacements.Forget(chainName, txHash)
+
+	monitorCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(t.pollInterval(chainName))
+	defer ticker.Stop()
+
+	detector := &chain.ReorgDetector{}
+	for {
+		select {
+		case <-monitorCtx.Done():
+			t.logger.Info("EVM transaction monitoring completed",
+				zap.String("tx_hash", txHash),
+				zap.String("chain", chainName))
+			return
+		case <-ticker.C:
+			confirmation, err := confirm(monitorCtx, txHash, requiredConfirmations)
+			if t.observeEVMConfirmation(chainName, txHash, tx, detector, confirmation, err) {
+				return
+			}
+		}
+	}
+}
+
+// observeEVMConfirmation handles a single confirmation poll result and reports
+// whether monitoring is complete.
+func (t *ApproveTransactionTool) observeEVMConfirmation(chainName, txHash string, tx *wallet.PendingTransaction, detector *chain.ReorgDetector, confirmation *chain.TransactionConfirmation, err error) bool {
+	if replacement, nonce, ok := t.replacements.ReplacedBy(chainName, txHash); ok {
+		t.handleReplaced(chainName, txHash, tx, replacement, nonce)
+		return true
+	}
+
+	if err != nil {
+		if errors.Is(err, chain.ErrTransactionNotFound) {
+			// A missing receipt after inclusion means the block was reorged out
+			t.handleReorg(chainName, txHash, tx, detector.Observe(nil))
+		} else {
+			t.logger.Debug("EVM transaction confirmation check failed",
+				zap.String("tx_hash", txHash),
+				zap.String("chain", chainName),
+				zap.Error(err))
+		}
+		return false
+	}
+
+	t.handleReorg(chainName, txHash, tx, detector.Observe(confirmation))
+
+	switch {
+	case confirmation.Status == "confirmed" && confirmation.Confirmations >= confirmation.RequiredConfirmations:
+		t.logger.Info("EVM transaction confirmed on blockchain",
+			zap.String("tx_hash", txHash),
+			zap.String("chain", chainName),
+			zap.Uint64("confirmations", confirmation.Confirmations))
+
+		tx.Confirmations = confirmation.Confirmations
+		tx.BlockNumber = confirmation.BlockNumber
+		t.replacements.MarkConfirmed(chainName, txHash)
+
+		t.broadcastEvent(chainName+"_transaction_confirmed", map[string]any{
+			"transaction_hash": txHash,
+			"confirmations":    confirmation.Confirmations,
+			"status":           confirmation.Status,
+			"block_number":     confirmation.BlockNumber,
+			"block_hash":       confirmation.BlockHash,
+			"gas_used":         confirmation.GasUsed,
+			"transaction_fee":  confirmation.TransactionFee,
+			"fee":              confirmation.Fee,
+			"timestamp":        confirmation.Timestamp,
+			"chain":            chainName,
+		})
+		t.balances.confirmed(chainName, txHash)
+		return true
+	case confirmation.Status == "failed":
+		t.logger.Error("EVM transaction failed on blockchain",
+			zap.String("tx_hash", txHash),
+			zap.String("chain", chainName))
+
+		event := map[string]any{
+			"transaction_hash": txHash,
+			"status":           confirmation.Status,
+			"chain":            chainName,
+			"timestamp":        time.Now().UTC(),
+		}
+		if confirmation.RevertReason != nil {
+			event["revert_reason"] = confirmation.RevertReason
+		}
+		t.broadcastEvent(chainName+"_transaction_failed", event)
+		return true
+	}
+
+	if confirmation.BlockHash != "" {
+		tx.Confirmations = confirmation.Confirmations
+		tx.BlockNumber = confirmation.BlockNumber
+	}
+	return false
+}
+
+// handleReorg resets confirmation progress and notifies subscribers when a
+// transaction has left the block it was previously included in.
+func (t *ApproveTransactionTool) handleReorg(chainName, txHash string, tx *wallet.PendingTransaction, reorg *chain.Reorg) {
+	if reorg == nil {
+		return
+	}
+
+	t.logger.Warn("EVM transaction affected by chain reorganization",
+		zap.String("tx_hash", txHash),
+		zap.String("chain", chainName),
+		zap.Uint64("previous_block_number", reorg.PreviousBlockNumber),
+		zap.String("previous_block_hash", reorg.PreviousBlockHash),
+		zap.String("new_block_hash", reorg.NewBlockHash))
+
+	tx.Confirmations = 0
+	tx.BlockNumber = reorg.NewBlockNumber
+	tx.Status = "pending"
+
+	t.broadcastEvent(event.EventTypeTransactionReorged, map[string]any{
+		"transaction_hash":      txHash,
+		"chain":                 chainName,
+		"previous_block_number": reorg.PreviousBlockNumber,
+		"previous_block_hash":   reorg.PreviousBlockHash,
+		"new_block_number":      reorg.NewBlockNumber,
+		"new_block_hash":        reorg.NewBlockHash,
+		"confirmations":         0,
+		"timestamp":             time.Now().UTC(),
+	})
+}
+
+// handleReplaced ends monitoring of a transaction whose nonce was taken by a
+// confirmed replacement, so it will never confirm itself.
+func (t *ApproveTransactionTool) handleReplaced(chainName, txHash string, tx *wallet.PendingTransaction, replacement string, nonce uint64) {
+	t.logger.Info("EVM transaction superseded by confirmed replacement",
+		zap.String("tx_hash", txHash),
+		zap.String("replacement_hash", replacement),
+		zap.String("chain", chainName),
+		zap.Uint64("nonce", nonce))
+
+	tx.Status = "replaced"
+
+	t.broadcastEvent(event.EventTypeTransactionReplacedConfirmed, map[string]any{
+		"transaction_hash": txHash,
+		"replacement_hash": replacement,
+		"nonce":            nonce,
+		"chain":            chainName,
+		"timestamp":        time.Now().UTC(),
+	})
+}
@@ -0,0 +1,196 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// Dry-run check outcomes
+const (
+	dryRunCheckPassed  = "passed"
+	dryRunCheckFailed  = "failed"
+	dryRunCheckSkipped = "skipped"
+)
+
+// dryRunCheck is a single pre-flight check evaluated during an approval dry run
+type dryRunCheck struct {
+	Name   string
+	Status string
+	Detail string
+}
+
+// dryRunResult is the predicted outcome of approving a pending transaction
+type dryRunResult struct {
+	Checks    []dryRunCheck
+	GasLimit  uint64
+	GasPrice  string
+	Fee       *chain.Fee
+	TotalCost string
+}
+
+// WouldSucceed reports whether every evaluated check passed
+func (r *dryRunResult) WouldSucceed() bool {
+	for _, check := range r.Checks {
+		if check.Status == dryRunCheckFailed {
+			return false
+		}
+	}
+	return true
+}
+
+// previewApproval runs the approval pre-flight without broadcasting or mutating the transaction
+func (t *ApproveTransactionTool) previewApproval(ctx context.Context, tx *wallet.PendingTransaction) *dryRunResult {
+	result := &dryRunResult{}
+	add := func(name, status, detail string) {
+		result.Checks = append(result.Checks, dryRunCheck{Name: name, Status: status, Detail: detail})
+	}
+
+	chainName, chainErr := toolutils.NormalizeChainName(tx.Chain)
+	if chainErr != nil {
+		add("chain", dryRunCheckFailed, fmt.Sprintf("unsupported chain: %s", tx.Chain))
+		return result
+	}
+	add("chain", dryRunCheckPassed, chainName)
+
+	// A frozen wallet refuses every approval until it is unfrozen
+	if t.manager.IsFrozen() {
+		add("frozen", dryRunCheckFailed, "wallet is frozen")
+	} else {
+		add("frozen", dryRunCheckPassed, "wallet is not frozen")
+	}
+
+	// Ownership: the sender must be the wallet this host controls
+	current := t.manager.GetCurrentWallet()
+	switch {
+	case current == nil:
+		add("ownership", dryRunCheckFailed, "no wallet available")
+	case !strings.EqualFold(current.Address, tx.From):
+		add("ownership", dryRunCheckFailed, fmt.Sprintf("sender %s is not the current wallet", tx.From))
+	default:
+		add("ownership", dryRunCheckPassed, "sender matches current wallet")
+	}
+
+	// Address and amount sanity
+	if !isValidAddressForChain(chainName, tx.From) || !isValidAddressForChain(chainName, tx.To) {
+		add("addresses", dryRunCheckFailed, "from or to address is invalid for chain")
+	} else {
+		add("addresses", dryRunCheckPassed, "from and to addresses are valid")
+	}
+	amount, ok := new(big.Float).SetString(tx.Amount)
+	if !ok || amount.Sign() <= 0 {
+		add("amount", dryRunCheckFailed, fmt.Sprintf("invalid amount: %s", tx.Amount))
+		return result
+	}
+	add("amount", dryRunCheckPassed, tx.Amount)
+
+	// The checks a send runs before signing: the transfer itself and the
+	// recipient's address book entry, the spend limits and the signing policies
+	if err := t.manager.ValidateTransfer(chainName, tx.From, tx.To, tx.Amount); err != nil {
+		add("transfer", dryRunCheckFailed, err.Error())
+	} else {
+		add("transfer", dryRunCheckPassed, "transfer and recipient are allowed")
+	}
+	if err := t.manager.CheckSpendLimit(chainName, tx.From, tx.To, tx.Amount, tx.Token); err != nil {
+		add("spend_limit", dryRunCheckFailed, err.Error())
+	} else {
+		add("spend_limit", dryRunCheckPassed, "within the spend limits")
+	}
+	signing := wallet.SigningRequest{Kind: wallet.SigningKindSend, Chain: chainName, From: tx.From, To: tx.To, Amount: tx.Amount, Token: tx.Token}
+	if err := t.manager.CheckSigningPolicy(ctx, signing); err != nil {
+		add("signing_policy", dryRunCheckFailed, err.Error())
+	} else {
+		add("signing_policy", dryRunCheckPassed, "allowed by the signing policies")
+	}
+
+	// Fee estimation stands in for simulation: the chain must accept the call shape
+	gasLimit, gasPrice, err := t.manager.EstimateGas(ctx, chainName, tx.From, tx.To, tx.Amount, tx.Token)
+	if err != nil {
+		add("simulation", dryRunCheckFailed, err.Error())
+		return result
+	}
+	add("simulation", dryRunCheckPassed, fmt.Sprintf("gas limit %d at %s", gasLimit, gasPrice))
+	result.GasLimit = gasLimit
+	result.GasPrice = gasPrice
+
+	fee, err := chain.EstimateFee(chainName, gasLimit, gasPrice)
+	if err != nil {
+		add("fee", dryRunCheckFailed, err.Error())
+		return result
+	}
+	wallet.PriceFee(ctx, t.largeTx.priceFeed, fee)
+	result.Fee = fee
+
+	// Balance: native sends need amount + fee, token sends need the token amount
+	nativeSymbol := wallet.NativeTokenSymbol(chainName)
+	isNative := tx.Token == "" || strings.EqualFold(tx.Token, nativeSymbol)
+	required := new(big.Float).Set(amount)
+	if isNative {
+		feeAmount, _ := new(big.Float).SetString(fee.Amount)
+		required.Add(required, feeAmount)
+	}
+	result.TotalCost = required.Text('f', 9)
+
+	balanceToken := tx.Token
+	if balanceToken == "" {
+		balanceToken = nativeSymbol
+	}
+	balanceStr, err := t.manager.GetBalance(ctx, tx.From, balanceToken)
+	if err != nil {
+		add("balance", dryRunCheckSkipped, fmt.Sprintf("balance unavailable: %v", err))
+		return result
+	}
+	balance, ok := new(big.Float).SetString(balanceStr)
+	switch {
+	case !ok:
+		add("balance", dryRunCheckSkipped, fmt.Sprintf("unparseable balance: %s", balanceStr))
+	case balance.Cmp(required) < 0:
+		add("balance", dryRunCheckFailed, fmt.Sprintf("balance %s %s is below required %s", balanceStr, balanceToken, result.TotalCost))
+	default:
+		add("balance", dryRunCheckPassed, fmt.Sprintf("balance %s %s covers %s", balanceStr, balanceToken, result.TotalCost))
+	}
+
+	return result
+}
+
+// formatDryRunMarkdown renders a dry-run result for the agent. estimatedValue
+// is the transaction's USD value line, empty when unavailable.
+func formatDryRunMarkdown(tx *wallet.PendingTransaction, result *dryRunResult, estimatedValue string) string {
+	outcome := "would_succeed"
+	if !result.WouldSucceed() {
+		outcome = "would_fail"
+	}
+
+	markdown := fmt.Sprintf("### Transaction Approval Dry Run 🧪\n\n"+
+		"**This was a dry run: nothing was signed or broadcast and the transaction is still `%s`.**\n\n"+
+		"- **Transaction Hash**: `%s`\n"+
+		"- **Chain**: `%s`\n"+
+		"- **From**: `%s`\n"+
+		"- **To**: `%s`\n"+
+		"- **Amount**: `%s %s`\n"+
+		"%s"+
+		"- **Predicted Outcome**: `%s`\n",
+		tx.Status, tx.Hash, tx.Chain, tx.From, tx.To, tx.Amount, tx.Token, estimatedValue, outcome)
+
+	if result.GasLimit > 0 {
+		markdown += fmt.Sprintf("- **Gas Limit**: `%d`\n", result.GasLimit)
+		markdown += fmt.Sprintf("- **Gas Price**: `%s`\n", result.GasPrice)
+	}
+	if result.Fee != nil {
+		markdown += formatFee("Estimated Fee", result.Fee)
+		markdown += fmt.Sprintf("- **Total Cost**: `%s`\n", result.TotalCost)
+	}
+
+	markdown += "\n#### Pre-flight Checks\n"
+	for _, check := range result.Checks {
+		markdown += fmt.Sprintf("- **%s**: `%s` - %s\n", check.Name, check.Status, check.Detail)
+	}
+
+	return markdown
+}
@@ -5,11 +5,10 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
+	"sync"
 	"time"
 
-	"github.com/algonius/algonius-wallet/native/pkg/dex"
-	"github.com/algonius/algonius-wallet/native/pkg/dex/providers"
+	"github.com/algonius/algonius-wallet/native/pkg/config"
 	"github.com/algonius/algonius-wallet/native/pkg/errors"
 	"github.com/algonius/algonius-wallet/native/pkg/event"
 	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
@@ -22,21 +21,65 @@ import (
 
 // ApproveTransactionTool implements the MCP "approve_transaction" tool for approving/rejecting pending transactions from web pages.
 type ApproveTransactionTool struct {
-	manager     wallet.IWalletManager
-	broadcaster *event.EventBroadcaster
-	logger      *zap.Logger
+	manager      wallet.IWalletManager
+	broadcaster  *event.EventBroadcaster
+	logger       *zap.Logger
+	chains       config.ChainsConfig
+	chainFactory *chain.ChainFactory
+	replacements *chain.ReplacementTracker
+	largeTx      largeTxGuard
+	callbacks    *callbackWatcher
+	balances     *balanceChangeNotifier
+
+	// Cancel functions of running confirmation monitors, keyed by start order
+	monitorMu   sync.Mutex
+	monitors    map[int]context.CancelFunc
+	nextMonitor int
 }
 
 // NewApproveTransactionTool constructs an ApproveTransactionTool with the given wallet manager and event broadcaster.
 func NewApproveTransactionTool(manager wallet.IWalletManager, broadcaster *event.EventBroadcaster, logger *zap.Logger) *ApproveTransactionTool {
+	return NewApproveTransactionToolWithConfig(manager, broadcaster, logger, nil)
+}
+
+// NewApproveTransactionToolWithConfig constructs an ApproveTransactionTool that reads per-chain
+// confirmation monitoring settings from cfg. A nil cfg uses the defaults.
+func NewApproveTransactionToolWithConfig(manager wallet.IWalletManager, broadcaster *event.EventBroadcaster, logger *zap.Logger, cfg *config.Config) *ApproveTransactionTool {
 	if logger == nil {
 		logger = zap.NewNop() // Use no-op logger if none provided
 	}
-	return &ApproveTransactionTool{
-		manager:     manager,
-		broadcaster: broadcaster,
-		logger:      logger,
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	t := &ApproveTransactionTool{
+		manager:      manager,
+		broadcaster:  broadcaster,
+		logger:       logger,
+		chains:       cfg.Chains,
+		chainFactory: chain.NewChainFactory(),
+		replacements: chain.DefaultReplacementTracker,
+		largeTx:      largeTxGuard{chains: cfg.Chains, broadcaster: broadcaster},
+		callbacks:    newCallbackWatcher(cfg, broadcaster, logger),
+		balances:     newBalanceChangeNotifier(broadcaster, logger),
 	}
+	// Callbacks follow approved transactions on the chain they were executed through
+	t.callbacks.getChain = func(chainName string) (chain.IChain, error) {
+		return t.chainFactory.GetChain(chainName)
+	}
+	return t
+}
+
+// SetPriceFeed sets the feed used to value approvals against the USD limit of
+// large_tx_threshold and to show their estimated USD value; without one only
+// the native limit applies and no value is shown
+func (t *ApproveTransactionTool) SetPriceFeed(priceFeed wallet.PriceFeed) {
+	t.largeTx.priceFeed = priceFeed
+}
+
+// SetChainFactory replaces the factory approved transactions are executed through,
+// so every chain registered with it can be approved
+func (t *ApproveTransactionTool) SetChainFactory(factory *chain.ChainFactory) {
+	t.chainFactory = factory
 }
 
 // GetMeta returns the MCP tool definition for "approve_transaction" as per the documented API schema.
@@ -52,8 +95,20 @@ func (t *ApproveTransactionTool) GetMeta() mcp.Tool {
 			mcp.Description("Action to take: 'approve' or 'reject'"),
 		),
 		mcp.WithString("reason",
-			mcp.Description("Reason for rejection (required if action is 'reject')"),
+			mcp.Description("Reason for rejection (required if action is 'reject'): "+wallet.RejectionReasonList()),
+			mcp.Enum(rejectionReasonEnum()...),
+		),
+		mcp.WithString("details",
+			mcp.Description("Optional free-text details about the rejection (required when reason is 'other')"),
 		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Preview an approval: run pre-flight checks and estimate cost without broadcasting or changing the transaction (default: false)"),
+		),
+		mcp.WithBoolean(largeTxConfirmParam,
+			mcp.Description(largeTxConfirmDescription),
+		),
+		withCallbackURLOption(),
+		withCorrelationIDOption(),
 	)
 }
 
@@ -83,11 +138,33 @@ func (t *ApproveTransactionTool) GetHandler() server.ToolHandlerFunc {
 
 		// Extract optional reason parameter (required for reject)
 		reason := req.GetString("reason", "")
-		if action == "reject" && reason == "" {
-			toolErr := errors.MissingRequiredFieldError("reason")
+		details := req.GetString("details", "")
+		if action == "reject" {
+			if reason == "" {
+				toolErr := errors.MissingRequiredFieldError("reason")
+				return toolutils.FormatErrorResult(toolErr), nil
+			}
+			parsedReason, err := wallet.ParseRejectionReason(reason, details)
+			if err != nil {
+				toolErr := errors.ValidationError("reason", err.Error())
+				return toolutils.FormatErrorResult(toolErr), nil
+			}
+			reason = string(parsedReason)
+		}
+
+		// Outcome callbacks only apply to approvals that are executed
+		callback, toolErr := parseTransactionCallback(req)
+		if toolErr != nil {
 			return toolutils.FormatErrorResult(toolErr), nil
 		}
 
+		// Approving signs and broadcasts; rejecting and dry runs never touch the keys
+		if action == "approve" && !req.GetBool("dry_run", false) {
+			if toolErr := toolutils.RequireUnlocked(t.manager, "approve transaction"); toolErr != nil {
+				return toolutils.FormatErrorResult(toolErr), nil
+			}
+		}
+
 		// Get the pending transaction
 		pendingTxs, err := t.manager.GetPendingTransactions(ctx, "", "", "", 100, 0)
 		if err != nil {
@@ -117,11 +194,20 @@ func (t *ApproveTransactionTool) GetHandler() server.ToolHandlerFunc {
 
 		var markdown string
 
-		if action == "approve" {
+		if action == "approve" && req.GetBool("dry_run", false) {
+			// Dry run: evaluate pre-flight checks only, leave the transaction untouched
+			markdown = formatDryRunMarkdown(targetTx, t.previewApproval(ctx, targetTx), t.estimatedValue(ctx, targetTx))
+		} else if action == "approve" {
+			// Hold large approvals back until the caller confirms them
+			confirmed := req.GetBool(largeTxConfirmParam, false)
+			if check := t.largeTx.check(ctx, targetTx.Hash, wallet.NormalizeChain(targetTx.Chain), targetTx.From, targetTx.To, targetTx.Amount, targetTx.Token, confirmed); check != nil && check.Large && !confirmed {
+				return mcp.NewToolResultText(formatLargeTxWarningMarkdown(check, "Call approve_transaction again with `confirm_large=true` to approve it.")), nil
+			}
+
 			// Approve the transaction - execute it
 			err := t.approveTransaction(ctx, targetTx)
 			if err != nil {
-				toolErr := errors.InternalError("approve transaction", err)
+				toolErr := toolutils.ClassifyError("approve transaction", err)
 				return toolutils.FormatErrorResult(toolErr), nil
 			}
 
@@ -136,13 +222,21 @@ func (t *ApproveTransactionTool) GetHandler() server.ToolHandlerFunc {
 				"- **From**: `%s`\n"+
 				"- **To**: `%s`\n"+
 				"- **Amount**: `%s %s`\n"+
+				"%s"+
 				"- **Status**: `approved`\n"+
 				"- **Action**: Transaction has been signed and submitted to the blockchain\n",
-				targetTx.Hash, targetTx.Chain, targetTx.From, targetTx.To, targetTx.Amount, targetTx.Token)
+				targetTx.Hash, targetTx.Chain, targetTx.From, targetTx.To, targetTx.Amount, targetTx.Token, t.estimatedValue(ctx, targetTx))
 
+			if callback != nil {
+				t.callbacks.start(ctx, wallet.NormalizeChain(targetTx.Chain), targetTx.Hash, callback)
+				markdown += formatCallbackMarkdown(callback)
+			}
 		} else {
 			// Reject the transaction
-			results, err := t.manager.RejectTransactions(ctx, []string{txHash}, reason, "AI Agent rejection", false, true)
+			if details == "" {
+				details = "AI Agent rejection"
+			}
+			results, err := t.manager.RejectTransactions(ctx, []string{txHash}, reason, details, false, true)
 			if err != nil {
 				toolErr := errors.InternalError("reject transaction", err)
 				return toolutils.FormatErrorResult(toolErr), nil
@@ -164,16 +258,32 @@ func (t *ApproveTransactionTool) GetHandler() server.ToolHandlerFunc {
 				"- **From**: `%s`\n"+
 				"- **To**: `%s`\n"+
 				"- **Amount**: `%s %s`\n"+
+				"%s"+
 				"- **Status**: `rejected`\n"+
 				"- **Reason**: `%s`\n"+
+				"- **Details**: %s\n"+
 				"- **Action**: Transaction has been rejected and will not be executed\n",
-				targetTx.Hash, targetTx.Chain, targetTx.From, targetTx.To, targetTx.Amount, targetTx.Token, reason)
+				targetTx.Hash, targetTx.Chain, targetTx.From, targetTx.To, targetTx.Amount, targetTx.Token, t.estimatedValue(ctx, targetTx), reason, details)
 		}
 
 		return mcp.NewToolResultText(markdown), nil
 	}
 }
 
+// estimatedValue renders the estimated USD value of tx at the current price, or "" when unavailable
+func (t *ApproveTransactionTool) estimatedValue(ctx context.Context, tx *wallet.PendingTransaction) string {
+	return formatEstimatedValue(ctx, t.largeTx.priceFeed, tx.Chain, tx.Token, tx.Amount, time.Time{})
+}
+
+// rejectionReasonEnum returns the valid rejection reasons for the tool schema
+func rejectionReasonEnum() []string {
+	values := make([]string, len(wallet.ValidRejectionReasons))
+	for i, reason := range wallet.ValidRejectionReasons {
+		values[i] = string(reason)
+	}
+	return values
+}
+
 // approveTransaction executes a pending transaction with real blockchain integration
 func (t *ApproveTransactionTool) approveTransaction(ctx context.Context, tx *wallet.PendingTransaction) error {
 	// Mark transaction as being processed
@@ -187,33 +297,30 @@ func (t *ApproveTransactionTool) approveTransaction(ctx context.Context, tx *wal
 		"timestamp":       time.Now().UTC(),
 	})
 	
-	// Execute transaction based on chain type
-	var blockchainTxHash string
-	var err error
-	
-	switch strings.ToLower(tx.Chain) {
-	case "solana", "sol":
-		blockchainTxHash, err = t.executeSolanaTransaction(ctx, tx)
-	case "ethereum", "eth":
-		blockchainTxHash, err = t.executeEthereumTransaction(ctx, tx)
-	case "bsc", "binance smart chain":
-		blockchainTxHash, err = t.executeBSCTransaction(ctx, tx)
-	default:
+	// Any chain registered with the factory can execute approved transactions
+	chainName := wallet.NormalizeChain(tx.Chain)
+	chainImpl, err := t.chainFactory.GetChain(chainName)
+	if err != nil {
 		return fmt.Errorf("unsupported chain: %s", tx.Chain)
 	}
 	
+	blockchainTxHash, err := t.executeTransaction(ctx, chainName, chainImpl, tx)
 	if err != nil {
 		// Mark transaction as failed
 		tx.Status = "failed"
 		// Note: tx.Error field doesn't exist, so we'll store error in a different way if needed
 		
 		// Broadcast failure
-		t.broadcastEvent("transaction_failed", map[string]any{
+		event := map[string]any{
 			"transaction_hash": tx.Hash,
 			"chain":           tx.Chain,
 			"error":           err.Error(),
 			"timestamp":       time.Now().UTC(),
-		})
+		}
+		if reason := chain.RevertReasonFromError(err); reason != nil {
+			event["revert_reason"] = reason
+		}
+		t.broadcastEvent("transaction_failed", event)
 		
 		return fmt.Errorf("transaction execution failed: %w", err)
 	}
@@ -239,139 +346,143 @@ func (t *ApproveTransactionTool) approveTransaction(ctx context.Context, tx *wal
 	})
 	
 	// Start monitoring for additional confirmations in background
-	go t.monitorTransactionConfirmations(ctx, tx)
+	t.startMonitor(ctx, func(monitorCtx context.Context) {
+		t.monitorTransactionConfirmations(monitorCtx, tx)
+	})
 	
 	return nil
 }
 
-// executeSolanaTransaction executes a transaction on Solana network using the enhanced chain implementation
-func (t *ApproveTransactionTool) executeSolanaTransaction(ctx context.Context, tx *wallet.PendingTransaction) (string, error) {
-	t.logger.Debug("Executing Solana transaction with enhanced blockchain integration",
+// executeTransaction signs and sends tx through chainImpl and starts monitoring
+// its confirmations. In RUN_MODE=test it returns a mock signature instead.
+func (t *ApproveTransactionTool) executeTransaction(ctx context.Context, chainName string, chainImpl chain.IChain, tx *wallet.PendingTransaction) (string, error) {
+	t.logger.Debug("Executing approved transaction",
+		zap.String("chain", chainName),
 		zap.String("transaction_hash", tx.Hash),
 		zap.String("from", tx.From),
 		zap.String("to", tx.To),
 		zap.String("amount", tx.Amount),
 		zap.String("token", tx.Token))
 	
-	// In RUN_MODE=test, use enhanced mock implementation
-	if os.Getenv("RUN_MODE") == "test" {
-		return t.executeEnhancedMockTransaction(ctx, tx, "solana")
+	// Refuse recipients the address book blocks before holding any spend
+	if err := t.manager.CheckRecipient(chainName, tx.To); err != nil {
+		return "", err
 	}
 	
-	// Step 1: Create enhanced Solana chain instance with DEX aggregator support
-	dexAggregator := dex.NewDEXAggregator(t.logger)
-	
-	// Register supported DEX providers for comprehensive trading support
-	err := t.registerDEXProviders(dexAggregator)
+	// Hold the amount against the spend limits until the send is recorded or fails
+	reservation, err := t.manager.ReserveSpend(chainName, tx.From, tx.To, tx.Amount, tx.Token)
 	if err != nil {
-		t.logger.Warn("Failed to register DEX providers, continuing with basic functionality", zap.Error(err))
+		return "", err
 	}
+	defer reservation.Release()
 	
-	// Create Solana chain with legacy version (no configuration available here)
-	solanaChain := chain.NewSolanaChainLegacy()
-	t.logger.Info("Using legacy Solana chain for transaction approval")
+	if err := t.manager.AuthorizeSigning(ctx, wallet.SigningRequest{Kind: wallet.SigningKindSend, Chain: chainName, From: tx.From, To: tx.To, Amount: tx.Amount, Token: tx.Token}); err != nil {
+		return "", err
+	}
 	
-	// Step 2: Load private key securely from wallet manager
-	// Note: In a real implementation, this would load the actual private key
-	// For now, we'll use a placeholder approach that works with the chain interface
-	privateKey, err := t.getPrivateKeyForAddress(ctx, tx.From, tx.Chain)
+	if os.Getenv("RUN_MODE") == "test" {
+		mockTxHash, err := t.executeEnhancedMockTransaction(ctx, tx, chainName)
+		if err == nil {
+			reservation.Commit(mockTxHash)
+		}
+		return mockTxHash, err
+	}
+	
+	privateKey, err := t.manager.GetPrivateKeyForAddress(ctx, tx.From)
 	if err != nil {
-		t.logger.Error("Failed to load private key for transaction", 
-			zap.String("address", tx.From), 
+		t.logger.Error("Failed to load private key for transaction",
+			zap.String("chain", chainName),
+			zap.String("address", tx.From),
 			zap.Error(err))
-		// Fallback to simulation if key loading fails
-		return t.executeSimulatedSolanaTransaction(ctx, tx)
+		return "", fmt.Errorf("failed to load private key: %w", err)
 	}
 	
-	// Step 3: Execute transaction using enhanced SendTransaction with retry logic and broadcast failover
-	t.logger.Info("Executing real Solana transaction with multi-channel broadcasting",
-		zap.String("from", tx.From),
-		zap.String("to", tx.To),
-		zap.String("amount", tx.Amount),
-		zap.String("token", tx.Token))
+	// Read before sending, so balance_changed can report what the send moved
+	balances := t.balances.snapshot(ctx, chainImpl, tx)
 	
-	// Execute the transaction with the enhanced chain implementation
-	// This includes:
-	// - Automatic RPC failover
-	// - Retry logic with slippage management
-	// - Multi-channel broadcasting (Solana RPC, OKEx, Jito, Paper)
-	// - Real transaction construction and signing
-	blockchainTxHash, err := solanaChain.SendTransaction(ctx, tx.From, tx.To, tx.Amount, tx.Token, privateKey)
+	blockchainTxHash, err := chainImpl.SendTransaction(ctx, tx.From, tx.To, tx.Amount, tx.Token, privateKey)
 	if err != nil {
-		t.logger.Error("Solana transaction execution failed", 
+		t.logger.Error("Transaction execution failed",
+			zap.String("chain", chainName),
 			zap.String("from", tx.From),
 			zap.String("to", tx.To),
 			zap.Error(err))
-		return "", fmt.Errorf("solana transaction failed: %w", err)
+		return "", fmt.Errorf("%s transaction failed: %w", chainName, err)
 	}
+	reservation.Commit(blockchainTxHash)
 	
-	t.logger.Info("Solana transaction executed successfully with enhanced blockchain integration",
+	t.logger.Info("Transaction executed successfully",
+		zap.String("chain", chainName),
 		zap.String("blockchain_tx_hash", blockchainTxHash),
-		zap.String("original_tx_hash", tx.Hash),
-		zap.String("from", tx.From),
-		zap.String("to", tx.To),
-		zap.String("amount", tx.Amount),
-		zap.String("token", tx.Token))
+		zap.String("original_tx_hash", tx.Hash))
 	
-	// Step 4: Start real-time transaction monitoring
-	go t.monitorSolanaTransaction(ctx, solanaChain, blockchainTxHash, tx)
+	t.balances.track(chainName, blockchainTxHash, balances)
+	t.startMonitor(ctx, func(monitorCtx context.Context) {
+		t.monitorChainTransaction(monitorCtx, chainName, chainImpl, blockchainTxHash, tx)
+	})
 	
 	return blockchainTxHash, nil
 }
 
-// registerDEXProviders registers all supported DEX providers for enhanced trading
-func (t *ApproveTransactionTool) registerDEXProviders(aggregator *dex.DEXAggregator) error {
-	// Register Direct provider for basic transactions
-	directProvider := providers.NewDirectProvider(t.logger)
-	if err := aggregator.RegisterProvider(directProvider); err != nil {
-		return fmt.Errorf("failed to register Direct provider: %w", err)
-	}
-	
-	// Register OKX provider for professional trading with default config
-	okxConfig := providers.OKXConfig{
-		BaseURL: "https://www.okx.com",
-		Timeout: time.Second * 30,
+// monitorChainTransaction watches a sent transaction with the monitor suited to its chain.
+// Chains other than Solana are assumed to confirm like EVM chains.
+func (t *ApproveTransactionTool) monitorChainTransaction(ctx context.Context, chainName string, chainImpl chain.IChain, txHash string, tx *wallet.PendingTransaction) {
+	defer t.balances.forget(chainName, txHash)
+	switch chainName {
+	case "solana":
+		t.monitorSolanaTransaction(ctx, chainImpl, txHash, tx)
+	case "ethereum":
+		// Ethereum can be slower; allow longer before giving up
+		t.monitorEVMTransaction(ctx, chainName, time.Minute*15, uint64(t.getRequiredConfirmations(chainName)), chainImpl.ConfirmTransaction, txHash, tx)
+	case "bsc":
+		t.monitorEVMTransaction(ctx, chainName, time.Minute*10, uint64(t.getRequiredConfirmations(chainName)), chainImpl.ConfirmTransaction, txHash, tx)
+	default:
+		t.monitorEVMTransaction(ctx, chainName, time.Minute*10, uint64(t.getRequiredConfirmations(chainName)), chainImpl.ConfirmTransaction, txHash, tx)
 	}
-	okxProvider := providers.NewOKXProvider(okxConfig, t.logger)
-	if err := aggregator.RegisterProvider(okxProvider); err != nil {
-		return fmt.Errorf("failed to register OKX provider: %w", err)
+}
+
+// startMonitor runs monitor in the background with a context CancelMonitors can
+// cancel. The monitor outlives the tool call, so it does not inherit the call's
+// cancellation or timeout.
+func (t *ApproveTransactionTool) startMonitor(ctx context.Context, monitor func(context.Context)) {
+	monitorCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	t.monitorMu.Lock()
+	if t.monitors == nil {
+		t.monitors = make(map[int]context.CancelFunc)
 	}
-	
-	t.logger.Info("Successfully registered DEX providers for enhanced trading support",
-		zap.Strings("providers", aggregator.GetSupportedProviders("501"))) // Solana chain ID
-	
-	return nil
+	id := t.nextMonitor
+	t.nextMonitor++
+	t.monitors[id] = cancel
+	t.monitorMu.Unlock()
+
+	go func() {
+		defer func() {
+			t.monitorMu.Lock()
+			delete(t.monitors, id)
+			t.monitorMu.Unlock()
+			cancel()
+		}()
+		monitor(monitorCtx)
+	}()
 }
 
-// getPrivateKeyForAddress securely retrieves the private key for a given address
-func (t *ApproveTransactionTool) getPrivateKeyForAddress(_ context.Context, address, chainName string) (string, error) {
-	// TODO: Implement secure private key retrieval from wallet manager
-	// This should:
-	// 1. Check if the address belongs to a wallet managed by this tool
-	// 2. Decrypt and retrieve the private key securely
-	// 3. Ensure proper access controls and audit logging
-	
-	// For now, return a placeholder that works with the chain's test mode detection
-	if os.Getenv("RUN_MODE") == "test" {
-		// Return a test private key format that the chain implementation will recognize
-		return "test_private_key_for_" + address, nil
+// CancelMonitors stops every running confirmation monitor and callback watch,
+// e.g. when the wallet is frozen, and returns how many were stopped
+func (t *ApproveTransactionTool) CancelMonitors() int {
+	t.monitorMu.Lock()
+	defer t.monitorMu.Unlock()
+
+	stopped := len(t.monitors) + t.callbacks.cancel()
+	for id, cancel := range t.monitors {
+		cancel()
+		delete(t.monitors, id)
 	}
-	
-	// In production, this would involve:
-	// - Wallet manager integration
-	// - Secure key storage access  
-	// - User authentication/authorization
-	// - Hardware security module integration
-	t.logger.Warn("Production private key retrieval not yet implemented, using secure fallback",
-		zap.String("address", address),
-		zap.String("chain", chainName))
-	
-	// Return a secure placeholder that indicates real implementation needed
-	return "secure_key_placeholder_" + address, nil
+	return stopped
 }
 
 // monitorSolanaTransaction provides real-time monitoring of Solana transaction confirmations
-func (t *ApproveTransactionTool) monitorSolanaTransaction(ctx context.Context, solanaChain *chain.SolanaChain, txHash string, tx *wallet.PendingTransaction) {
+func (t *ApproveTransactionTool) monitorSolanaTransaction(ctx context.Context, solanaChain chain.IChain, txHash string, tx *wallet.PendingTransaction) {
 	t.logger.Info("Starting real-time Solana transaction monitoring",
 		zap.String("tx_hash", txHash),
 		zap.String("chain", "solana"))
@@ -379,8 +490,13 @@ func (t *ApproveTransactionTool) monitorSolanaTransaction(ctx context.Context, s
 	// Create monitoring context with timeout
 	monitorCtx, cancel := context.WithTimeout(ctx, time.Minute*5) // Solana is fast
 	defer cancel()
+
+	// Large transfers are only reported confirmed once finalized
+	if t.largeTx.exceeds(ctx, "solana", tx.Token, tx.Amount) {
+		monitorCtx = chain.WithCommitment(monitorCtx, chain.CommitmentFinalized)
+	}
 	
-	ticker := time.NewTicker(time.Second * 3) // Check every 3 seconds for Solana
+	ticker := time.NewTicker(t.pollInterval("solana"))
 	defer ticker.Stop()
 	
 	for {
@@ -390,7 +506,7 @@ func (t *ApproveTransactionTool) monitorSolanaTransaction(ctx context.Context, s
 			return
 		case <-ticker.C:
 			// Use the enhanced chain to check transaction confirmation
-			confirmation, err := solanaChain.ConfirmTransaction(monitorCtx, txHash, 1)
+			confirmation, err := solanaChain.ConfirmTransaction(monitorCtx, txHash, uint64(t.getRequiredConfirmations("solana")))
 			if err != nil {
 				t.logger.Debug("Transaction confirmation check failed", 
 					zap.String("tx_hash", txHash),
@@ -415,9 +531,12 @@ func (t *ApproveTransactionTool) monitorSolanaTransaction(ctx context.Context, s
 					"block_number":    confirmation.BlockNumber,
 					"gas_used":        confirmation.GasUsed,
 					"transaction_fee": confirmation.TransactionFee,
+					"fee":             confirmation.Fee,
 					"timestamp":       confirmation.Timestamp,
+					"commitment":      confirmation.Commitment,
 					"chain":          "solana",
 				})
+				t.balances.confirmed("solana", txHash)
 				
 				return
 			} else if confirmation.Status == "failed" {
@@ -482,298 +601,6 @@ func (t *ApproveTransactionTool) executeEnhancedMockTransaction(_ context.Contex
 	return mockSignature, nil
 }
 
-// executeSimulatedSolanaTransaction provides simulated transaction execution with realistic behavior
-func (t *ApproveTransactionTool) executeSimulatedSolanaTransaction(_ context.Context, tx *wallet.PendingTransaction) (string, error) {
-	t.logger.Info("Executing simulated Solana transaction",
-		zap.String("from", tx.From),
-		zap.String("to", tx.To),
-		zap.String("amount", tx.Amount))
-	
-	// Simulate the complete Solana transaction flow:
-	// 1. Blockhash fetching
-	// 2. Transaction construction  
-	// 3. Signing
-	// 4. Broadcasting with failover
-	// 5. Confirmation monitoring
-	
-	// Step 1: Simulate blockhash fetching (would use RPC manager)
-	time.Sleep(time.Millisecond * 50)
-	
-	// Step 2: Simulate transaction construction (would create actual Solana transaction)
-	time.Sleep(time.Millisecond * 30)
-	
-	// Step 3: Simulate signing (would use private key)
-	time.Sleep(time.Millisecond * 20)
-	
-	// Step 4: Simulate broadcasting (would use broadcast manager with failover)
-	time.Sleep(time.Millisecond * 100)
-	
-	// Generate realistic Solana signature format
-	simulatedSignature := fmt.Sprintf("SimulatedSolana_%s_%d", tx.From[:8], time.Now().Unix())
-	
-	t.logger.Info("Simulated Solana transaction completed",
-		zap.String("signature", simulatedSignature),
-		zap.Duration("total_time", time.Millisecond*200))
-	
-	return simulatedSignature, nil
-}
-
-// executeEthereumTransaction executes a transaction on Ethereum network
-func (t *ApproveTransactionTool) executeEthereumTransaction(ctx context.Context, tx *wallet.PendingTransaction) (string, error) {
-	if os.Getenv("RUN_MODE") == "test" {
-		return t.executeEnhancedMockTransaction(ctx, tx, "ethereum")
-	}
-	
-	// Create Ethereum chain instance with enhanced capabilities
-	ethChain := chain.NewETHChain(nil, t.logger)
-	
-	// Load private key securely
-	privateKey, err := t.getPrivateKeyForAddress(ctx, tx.From, tx.Chain)
-	if err != nil {
-		t.logger.Error("Failed to load private key for Ethereum transaction", 
-			zap.String("address", tx.From), 
-			zap.Error(err))
-		return t.executeSimulatedEthereumTransaction(ctx, tx)
-	}
-	
-	t.logger.Info("Executing real Ethereum transaction",
-		zap.String("from", tx.From),
-		zap.String("to", tx.To),
-		zap.String("amount", tx.Amount),
-		zap.String("token", tx.Token))
-	
-	// Execute the transaction with real blockchain integration
-	blockchainTxHash, err := ethChain.SendTransaction(ctx, tx.From, tx.To, tx.Amount, tx.Token, privateKey)
-	if err != nil {
-		t.logger.Error("Ethereum transaction execution failed", 
-			zap.String("from", tx.From),
-			zap.String("to", tx.To),
-			zap.Error(err))
-		return "", fmt.Errorf("ethereum transaction failed: %w", err)
-	}
-	
-	t.logger.Info("Ethereum transaction executed successfully",
-		zap.String("blockchain_tx_hash", blockchainTxHash),
-		zap.String("original_tx_hash", tx.Hash))
-	
-	// Start real-time monitoring
-	go t.monitorEthereumTransaction(ctx, ethChain, blockchainTxHash, tx)
-	
-	return blockchainTxHash, nil
-}
-
-// executeBSCTransaction executes a transaction on BSC network
-func (t *ApproveTransactionTool) executeBSCTransaction(ctx context.Context, tx *wallet.PendingTransaction) (string, error) {
-	if os.Getenv("RUN_MODE") == "test" {
-		return t.executeEnhancedMockTransaction(ctx, tx, "bsc")
-	}
-	
-	// Create BSC chain instance with enhanced capabilities
-	bscChain := chain.NewBSCChain(nil, t.logger)
-	
-	// Load private key securely
-	privateKey, err := t.getPrivateKeyForAddress(ctx, tx.From, tx.Chain)
-	if err != nil {
-		t.logger.Error("Failed to load private key for BSC transaction", 
-			zap.String("address", tx.From), 
-			zap.Error(err))
-		return t.executeSimulatedBSCTransaction(ctx, tx)
-	}
-	
-	t.logger.Info("Executing real BSC transaction",
-		zap.String("from", tx.From),
-		zap.String("to", tx.To),
-		zap.String("amount", tx.Amount),
-		zap.String("token", tx.Token))
-	
-	// Execute the transaction with real blockchain integration
-	blockchainTxHash, err := bscChain.SendTransaction(ctx, tx.From, tx.To, tx.Amount, tx.Token, privateKey)
-	if err != nil {
-		t.logger.Error("BSC transaction execution failed", 
-			zap.String("from", tx.From),
-			zap.String("to", tx.To),
-			zap.Error(err))
-		return "", fmt.Errorf("bsc transaction failed: %w", err)
-	}
-	
-	t.logger.Info("BSC transaction executed successfully",
-		zap.String("blockchain_tx_hash", blockchainTxHash),
-		zap.String("original_tx_hash", tx.Hash))
-	
-	// Start real-time monitoring
-	go t.monitorBSCTransaction(ctx, bscChain, blockchainTxHash, tx)
-	
-	return blockchainTxHash, nil
-}
-
-// executeSimulatedEthereumTransaction provides simulated Ethereum transaction execution
-func (t *ApproveTransactionTool) executeSimulatedEthereumTransaction(_ context.Context, tx *wallet.PendingTransaction) (string, error) {
-	t.logger.Info("Executing simulated Ethereum transaction",
-		zap.String("from", tx.From),
-		zap.String("to", tx.To),
-		zap.String("amount", tx.Amount))
-	
-	// Simulate Ethereum transaction flow (slower than Solana)
-	time.Sleep(time.Millisecond * 300)
-	
-	simulatedSignature := fmt.Sprintf("0xSimulatedEth_%s_%d", tx.From[2:10], time.Now().Unix())
-	
-	t.logger.Info("Simulated Ethereum transaction completed",
-		zap.String("signature", simulatedSignature),
-		zap.Duration("total_time", time.Millisecond*300))
-	
-	return simulatedSignature, nil
-}
-
-// executeSimulatedBSCTransaction provides simulated BSC transaction execution
-func (t *ApproveTransactionTool) executeSimulatedBSCTransaction(_ context.Context, tx *wallet.PendingTransaction) (string, error) {
-	t.logger.Info("Executing simulated BSC transaction",
-		zap.String("from", tx.From),
-		zap.String("to", tx.To),
-		zap.String("amount", tx.Amount))
-	
-	// Simulate BSC transaction flow (faster than Ethereum, slower than Solana)
-	time.Sleep(time.Millisecond * 200)
-	
-	simulatedSignature := fmt.Sprintf("0xSimulatedBSC_%s_%d", tx.From[2:10], time.Now().Unix())
-	
-	t.logger.Info("Simulated BSC transaction completed",
-		zap.String("signature", simulatedSignature),
-		zap.Duration("total_time", time.Millisecond*200))
-	
-	return simulatedSignature, nil
-}
-
-// monitorEthereumTransaction provides real-time monitoring of Ethereum transaction confirmations
-func (t *ApproveTransactionTool) monitorEthereumTransaction(ctx context.Context, ethChain *chain.ETHChain, txHash string, tx *wallet.PendingTransaction) {
-	t.logger.Info("Starting real-time Ethereum transaction monitoring",
-		zap.String("tx_hash", txHash),
-		zap.String("chain", "ethereum"))
-	
-	// Create monitoring context with timeout
-	monitorCtx, cancel := context.WithTimeout(ctx, time.Minute*15) // Ethereum can be slower
-	defer cancel()
-	
-	ticker := time.NewTicker(time.Second * 15) // Check every 15 seconds for Ethereum
-	defer ticker.Stop()
-	
-	for {
-		select {
-		case <-monitorCtx.Done():
-			t.logger.Info("Ethereum transaction monitoring completed", zap.String("tx_hash", txHash))
-			return
-		case <-ticker.C:
-			// Use the enhanced chain to check transaction confirmation
-			confirmation, err := ethChain.ConfirmTransaction(monitorCtx, txHash, 12) // 12 confirmations for Ethereum
-			if err != nil {
-				t.logger.Debug("Ethereum transaction confirmation check failed", 
-					zap.String("tx_hash", txHash),
-					zap.Error(err))
-				continue
-			}
-			
-			if confirmation.Status == "confirmed" && confirmation.Confirmations >= confirmation.RequiredConfirmations {
-				t.logger.Info("Ethereum transaction confirmed on blockchain",
-					zap.String("tx_hash", txHash),
-					zap.Uint64("confirmations", confirmation.Confirmations))
-				
-				tx.Confirmations = confirmation.Confirmations
-				
-				// Broadcast real confirmation
-				t.broadcastEvent("ethereum_transaction_confirmed", map[string]any{
-					"transaction_hash": txHash,
-					"confirmations":    confirmation.Confirmations,
-					"status":          confirmation.Status,
-					"block_number":    confirmation.BlockNumber,
-					"gas_used":        confirmation.GasUsed,
-					"transaction_fee": confirmation.TransactionFee,
-					"timestamp":       confirmation.Timestamp,
-					"chain":          "ethereum",
-				})
-				
-				return
-			} else if confirmation.Status == "failed" {
-				t.logger.Error("Ethereum transaction failed on blockchain",
-					zap.String("tx_hash", txHash))
-				
-				t.broadcastEvent("ethereum_transaction_failed", map[string]any{
-					"transaction_hash": txHash,
-					"status":          confirmation.Status,
-					"chain":          "ethereum",
-					"timestamp":       time.Now().UTC(),
-				})
-				
-				return
-			}
-		}
-	}
-}
-
-// monitorBSCTransaction provides real-time monitoring of BSC transaction confirmations
-func (t *ApproveTransactionTool) monitorBSCTransaction(ctx context.Context, bscChain *chain.BSCChain, txHash string, tx *wallet.PendingTransaction) {
-	t.logger.Info("Starting real-time BSC transaction monitoring",
-		zap.String("tx_hash", txHash),
-		zap.String("chain", "bsc"))
-	
-	// Create monitoring context with timeout
-	monitorCtx, cancel := context.WithTimeout(ctx, time.Minute*10) // BSC is faster than Ethereum
-	defer cancel()
-	
-	ticker := time.NewTicker(time.Second * 10) // Check every 10 seconds for BSC
-	defer ticker.Stop()
-	
-	for {
-		select {
-		case <-monitorCtx.Done():
-			t.logger.Info("BSC transaction monitoring completed", zap.String("tx_hash", txHash))
-			return
-		case <-ticker.C:
-			// Use the enhanced chain to check transaction confirmation
-			confirmation, err := bscChain.ConfirmTransaction(monitorCtx, txHash, 15) // 15 confirmations for BSC
-			if err != nil {
-				t.logger.Debug("BSC transaction confirmation check failed", 
-					zap.String("tx_hash", txHash),
-					zap.Error(err))
-				continue
-			}
-			
-			if confirmation.Status == "confirmed" && confirmation.Confirmations >= confirmation.RequiredConfirmations {
-				t.logger.Info("BSC transaction confirmed on blockchain",
-					zap.String("tx_hash", txHash),
-					zap.Uint64("confirmations", confirmation.Confirmations))
-				
-				tx.Confirmations = confirmation.Confirmations
-				
-				// Broadcast real confirmation
-				t.broadcastEvent("bsc_transaction_confirmed", map[string]any{
-					"transaction_hash": txHash,
-					"confirmations":    confirmation.Confirmations,
-					"status":          confirmation.Status,
-					"block_number":    confirmation.BlockNumber,
-					"gas_used":        confirmation.GasUsed,
-					"transaction_fee": confirmation.TransactionFee,
-					"timestamp":       confirmation.Timestamp,
-					"chain":          "bsc",
-				})
-				
-				return
-			} else if confirmation.Status == "failed" {
-				t.logger.Error("BSC transaction failed on blockchain",
-					zap.String("tx_hash", txHash))
-				
-				t.broadcastEvent("bsc_transaction_failed", map[string]any{
-					"transaction_hash": txHash,
-					"status":          confirmation.Status,
-					"chain":          "bsc",
-					"timestamp":       time.Now().UTC(),
-				})
-				
-				return
-			}
-		}
-	}
-}
-
 // monitorTransactionConfirmations monitors a transaction for additional confirmations
 func (t *ApproveTransactionTool) monitorTransactionConfirmations(ctx context.Context, tx *wallet.PendingTransaction) {
 	// Simple monitoring implementation
@@ -786,7 +613,7 @@ func (t *ApproveTransactionTool) monitorTransactionConfirmations(ctx context.Con
 	monitorCtx, cancel := context.WithTimeout(ctx, time.Minute*10)
 	defer cancel()
 	
-	ticker := time.NewTicker(time.Second * 15) // Check every 15 seconds
+	ticker := time.NewTicker(t.pollInterval(tx.Chain))
 	defer ticker.Stop()
 	
 	for {
@@ -831,20 +658,61 @@ func (t *ApproveTransactionTool) getTransactionConfirmations(tx *wallet.PendingT
 	return int(tx.Confirmations)
 }
 
-// getRequiredConfirmations returns the number of confirmations considered safe for a chain
+// getRequiredConfirmations returns the number of confirmations considered safe for a chain:
+// the configured required_confirmations, or the built-in default when unset
 func (t *ApproveTransactionTool) getRequiredConfirmations(chain string) int {
-	switch strings.ToLower(chain) {
-	case "solana", "sol":
+	return requiredConfirmations(t.chains, chain)
+}
+
+// requiredConfirmations returns the confirmations considered safe for a chain in chains
+func requiredConfirmations(chains config.ChainsConfig, chain string) int {
+	if required := chains.Confirmation(chain).RequiredConfirmations; required > 0 {
+		return required
+	}
+	switch wallet.NormalizeChain(chain) {
+	case "solana":
 		return 32 // Solana finality
-	case "ethereum", "eth":
+	case "ethereum":
 		return 12 // Ethereum safety
-	case "bsc", "binance smart chain":
+	case "bsc":
 		return 15 // BSC safety
 	default:
 		return 6 // Default safety
 	}
 }
 
+// pollInterval returns the configured confirmation poll interval for a chain,
+// falling back to the built-in default when unset or below the allowed minimum
+func (t *ApproveTransactionTool) pollInterval(chainName string) time.Duration {
+	return confirmationPollInterval(t.chains, chainName)
+}
+
+// confirmationPollInterval returns the poll interval configured for a chain in
+// chains, or the built-in default
+func confirmationPollInterval(chains config.ChainsConfig, chainName string) time.Duration {
+	confirmation := chains.Confirmation(chainName)
+	if !config.IsSupportedChain(chainName) {
+		confirmation = chains.Ethereum.Confirmation
+	}
+
+	if confirmation.Validate() != nil {
+		return defaultPollInterval(chainName)
+	}
+	return confirmation.PollInterval
+}
+
+// defaultPollInterval returns the built-in poll interval for a chain
+func defaultPollInterval(chainName string) time.Duration {
+	switch wallet.NormalizeChain(chainName) {
+	case "solana":
+		return 3 * time.Second
+	case "bsc":
+		return 10 * time.Second
+	default:
+		return 15 * time.Second
+	}
+}
+
 // broadcastEvent is a helper method to broadcast events to AI agents
 func (t *ApproveTransactionTool) broadcastEvent(eventType string, data map[string]any) {
 	if t.broadcaster == nil {
@@ -0,0 +1,458 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const approveTestTxHash = "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"
+
+func newApproveTestManager() *wallet.MockWalletManager {
+	mockManager := &wallet.MockWalletManager{}
+	mockManager.On("GetPendingTransactions", mock.Anything, "", "", "", 100, 0).Return([]*wallet.PendingTransaction{
+		{
+			Hash:   approveTestTxHash,
+			Chain:  "ethereum",
+			From:   "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+			To:     "0x1111111111111111111111111111111111111111",
+			Amount: "1",
+			Token:  "ETH",
+			Status: "pending",
+		},
+	}, nil)
+	return mockManager
+}
+
+func TestApproveTransactionToolRejectWithTaxonomyReason(t *testing.T) {
+	mockManager := newApproveTestManager()
+	mockManager.On("RejectTransactions", mock.Anything, []string{approveTestTxHash}, "high_gas_fee", "gas above 200 gwei", false, true).
+		Return([]wallet.TransactionRejectionResult{{TransactionHash: approveTestTxHash, Success: true}}, nil)
+
+	handler := NewApproveTransactionTool(mockManager, nil, nil).GetHandler()
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "approve_transaction",
+			Arguments: map[string]any{
+				"transaction_hash": approveTestTxHash,
+				"action":           "reject",
+				"reason":           "High_Gas_Fee",
+				"details":          "gas above 200 gwei",
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "**Reason**: `high_gas_fee`")
+	assert.Contains(t, textContent.Text, "gas above 200 gwei")
+	mockManager.AssertExpectations(t)
+}
+
+func TestApproveTransactionToolRejectInvalidReason(t *testing.T) {
+	mockManager := &wallet.MockWalletManager{}
+	handler := NewApproveTransactionTool(mockManager, nil, nil).GetHandler()
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "approve_transaction",
+			Arguments: map[string]any{
+				"transaction_hash": approveTestTxHash,
+				"action":           "reject",
+				"reason":           "looks odd",
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	mockManager.AssertNotCalled(t, "RejectTransactions", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApproveTransactionToolApproveWhileLocked(t *testing.T) {
+	mockManager := newApproveTestManager()
+	mockManager.On("IsUnlocked").Return(false)
+	mockManager.On("IsFrozen").Return(false)
+
+	handler := NewApproveTransactionTool(mockManager, nil, nil).GetHandler()
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "approve_transaction",
+			Arguments: map[string]any{
+				"transaction_hash": approveTestTxHash,
+				"action":           "approve",
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	mockManager.AssertNotCalled(t, "GetPendingTransactions", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "`WALLET_LOCKED`")
+}
+
+func TestApproveTransactionToolMetaReasonEnum(t *testing.T) {
+	meta := NewApproveTransactionTool(&wallet.MockWalletManager{}, nil, nil).GetMeta()
+	assert.Contains(t, meta.InputSchema.Properties, "details")
+
+	reasonSchema, ok := meta.InputSchema.Properties["reason"].(map[string]any)
+	require.True(t, ok)
+	assert.Contains(t, reasonSchema["enum"], string(wallet.RejectionReasonSuspiciousActivity))
+}
+
+// newDryRunTestManager returns a manager whose pre-signing checks all pass
+func newDryRunTestManager() *wallet.MockWalletManager {
+	mockManager := newApproveTestManager()
+	mockManager.On("IsFrozen").Return(false)
+	mockManager.On("ValidateTransfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockManager.On("CheckSpendLimit", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockManager.On("CheckSigningPolicy", mock.Anything, mock.Anything).Return(nil)
+	return mockManager
+}
+
+func TestApproveTransactionToolDryRun(t *testing.T) {
+	mockManager := newDryRunTestManager()
+	mockManager.On("GetCurrentWallet").Return(wallet.NewWalletStatus("0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8", "pubkey"))
+	mockManager.On("EstimateGas", mock.Anything, "ethereum", mock.Anything, mock.Anything, "1", "ETH").Return(uint64(21000), "20", nil)
+	mockManager.On("GetBalance", mock.Anything, "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8", "ETH").Return("2.5", nil)
+
+	handler := NewApproveTransactionTool(mockManager, nil, nil).GetHandler()
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "approve_transaction",
+			Arguments: map[string]any{
+				"transaction_hash": approveTestTxHash,
+				"action":           "approve",
+				"dry_run":          true,
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "This was a dry run")
+	assert.Contains(t, textContent.Text, "**Predicted Outcome**: `would_succeed`")
+	assert.Contains(t, textContent.Text, "- **Estimated Fee**: `0.00042 ETH`")
+	assert.Contains(t, textContent.Text, "- **Estimated Fee (wei)**: `420000000000000`")
+	assert.Contains(t, textContent.Text, "still `pending`")
+	mockManager.AssertNotCalled(t, "SendTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApproveTransactionToolDryRunInsufficientBalance(t *testing.T) {
+	mockManager := newDryRunTestManager()
+	mockManager.On("GetCurrentWallet").Return(wallet.NewWalletStatus("0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8", "pubkey"))
+	mockManager.On("EstimateGas", mock.Anything, "ethereum", mock.Anything, mock.Anything, "1", "ETH").Return(uint64(21000), "20", nil)
+	mockManager.On("GetBalance", mock.Anything, mock.Anything, "ETH").Return("1", nil)
+
+	handler := NewApproveTransactionTool(mockManager, nil, nil).GetHandler()
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "approve_transaction",
+			Arguments: map[string]any{
+				"transaction_hash": approveTestTxHash,
+				"action":           "approve",
+				"dry_run":          true,
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "**Predicted Outcome**: `would_fail`")
+	assert.Contains(t, textContent.Text, "**balance**: `failed`")
+}
+
+func TestApproveTransactionToolDryRunPreSigningChecks(t *testing.T) {
+	const to = "0x1111111111111111111111111111111111111111"
+	blocked := fmt.Errorf("%w: %s is listed as Fake airdrop (scam)", wallet.ErrRecipientBlocked, to)
+	exceeded := &wallet.SpendLimitExceededError{Chain: "ethereum", Amount: "1", Token: "ETH", Limit: wallet.SpendLimitDaily, Max: 0.5, Spent: "0"}
+	denied := &wallet.SigningDeniedError{Policy: "rules", Reason: "sends are disabled"}
+
+	for _, tc := range []struct {
+		name     string
+		frozen   bool
+		transfer error
+		limit    error
+		policy   error
+		check    string
+	}{
+		{name: "frozen", frozen: true, check: "frozen"},
+		{name: "blocked recipient", transfer: blocked, check: "transfer"},
+		{name: "spend limit", limit: exceeded, check: "spend_limit"},
+		{name: "signing policy", policy: denied, check: "signing_policy"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockManager := newApproveTestManager()
+			mockManager.On("GetCurrentWallet").Return(wallet.NewWalletStatus("0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8", "pubkey"))
+			mockManager.On("IsFrozen").Return(tc.frozen)
+			mockManager.On("ValidateTransfer", "ethereum", mock.Anything, to, "1").Return(tc.transfer)
+			mockManager.On("CheckSpendLimit", "ethereum", mock.Anything, to, "1", "ETH").Return(tc.limit)
+			mockManager.On("CheckSigningPolicy", mock.Anything, mock.Anything).Return(tc.policy)
+			mockManager.On("EstimateGas", mock.Anything, "ethereum", mock.Anything, mock.Anything, "1", "ETH").Return(uint64(21000), "20", nil)
+			mockManager.On("GetBalance", mock.Anything, mock.Anything, "ETH").Return("2.5", nil)
+
+			tool := NewApproveTransactionTool(mockManager, nil, nil)
+			tx := &wallet.PendingTransaction{Hash: approveTestTxHash, Chain: "ethereum", From: "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8", To: to, Amount: "1", Token: "ETH", Status: "pending"}
+			result := tool.previewApproval(context.Background(), tx)
+			assert.False(t, result.WouldSucceed())
+			for _, check := range result.Checks {
+				if check.Status == dryRunCheckFailed {
+					assert.Equal(t, tc.check, check.Name, "only the %s check fails", tc.check)
+				}
+			}
+			// Predicting the approval neither reserves nor signs
+			mockManager.AssertNotCalled(t, "ReserveSpend", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockManager.AssertNotCalled(t, "AuthorizeSigning", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestApproveTransactionToolPollIntervalFromConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Chains.Ethereum.Confirmation.PollInterval = 2 * time.Second
+	cfg.Chains.BSC.Confirmation.PollInterval = 100 * time.Millisecond // below minimum, falls back
+
+	tool := NewApproveTransactionToolWithConfig(&wallet.MockWalletManager{}, nil, nil, cfg)
+	assert.Equal(t, 2*time.Second, tool.pollInterval("eth"))
+	assert.Equal(t, 10*time.Second, tool.pollInterval("bsc"))
+	assert.Equal(t, 3*time.Second, tool.pollInterval("solana"))
+}
+
+func TestApproveTransactionToolExecutesThroughChainFactory(t *testing.T) {
+	t.Setenv("RUN_MODE", "test")
+	factory := chain.NewChainFactory()
+	factory.RegisterChain("polygon", chain.NewETHChainLegacy())
+	mockManager := &wallet.MockWalletManager{}
+	mockManager.On("AuthorizeSigning", mock.Anything, mock.Anything).Return(nil)
+	mockManager.On("CheckRecipient", mock.Anything, mock.Anything).Return(nil)
+	mockManager.On("ReserveSpend", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	tool := NewApproveTransactionTool(mockManager, nil, nil)
+	tool.SetChainFactory(factory)
+
+	for _, chainName := range []string{"sol", "binance smart chain", "polygon"} {
+		tx := &wallet.PendingTransaction{Hash: approveTestTxHash, Chain: chainName, Status: "pending"}
+		require.NoError(t, tool.approveTransaction(context.Background(), tx), chainName)
+		assert.Equal(t, "confirmed", tx.Status)
+		assert.Contains(t, tx.Hash, "Mock", "test mode returns a mock signature")
+	}
+
+	tx := &wallet.PendingTransaction{Hash: approveTestTxHash, Chain: "dogecoin", Status: "pending"}
+	err := tool.approveTransaction(context.Background(), tx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported chain")
+}
+
+// keyRecordingChain records the private key each send is signed with
+type keyRecordingChain struct {
+	chain.IChain
+	privateKey string
+}
+
+func (c *keyRecordingChain) SendTransaction(ctx context.Context, from, to, amount, token, privateKey string) (string, error) {
+	c.privateKey = privateKey
+	return "0xsent", nil
+}
+
+func (c *keyRecordingChain) ConfirmTransaction(ctx context.Context, txHash string, requiredConfirmations uint64) (*chain.TransactionConfirmation, error) {
+	return &chain.TransactionConfirmation{Status: "confirmed", Confirmations: requiredConfirmations, TxHash: txHash}, nil
+}
+
+func TestApproveTransactionToolSignsWithWalletKey(t *testing.T) {
+	const from = "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+	recorder := &keyRecordingChain{}
+	factory := chain.NewChainFactory()
+	factory.RegisterChain("polygon", recorder)
+	mockManager := &wallet.MockWalletManager{}
+	mockManager.On("AuthorizeSigning", mock.Anything, mock.Anything).Return(nil)
+	mockManager.On("CheckRecipient", mock.Anything, mock.Anything).Return(nil)
+	mockManager.On("ReserveSpend", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	mockManager.On("GetPrivateKeyForAddress", mock.Anything, from).Return("0xwalletkey", nil).Once()
+	mockManager.On("GetPrivateKeyForAddress", mock.Anything, from).Return("", wallet.ErrWalletLocked)
+	tool := NewApproveTransactionTool(mockManager, nil, nil)
+	tool.SetChainFactory(factory)
+	t.Cleanup(func() { tool.CancelMonitors() })
+
+	tx := &wallet.PendingTransaction{Hash: approveTestTxHash, Chain: "polygon", From: from, To: "0x1111111111111111111111111111111111111111", Amount: "1", Status: "pending"}
+	require.NoError(t, tool.approveTransaction(context.Background(), tx))
+	assert.Equal(t, "0xwalletkey", recorder.privateKey, "the unlocked wallet's key signs")
+	assert.Equal(t, "0xsent", tx.Hash)
+
+	// Locking between the unlock check and signing fails instead of signing with anything else
+	recorder.privateKey = ""
+	tx = &wallet.PendingTransaction{Hash: approveTestTxHash, Chain: "polygon", From: from, Status: "pending"}
+	err := tool.approveTransaction(context.Background(), tx)
+	require.ErrorIs(t, err, wallet.ErrWalletLocked)
+	assert.Empty(t, recorder.privateKey)
+	assert.Equal(t, "failed", tx.Status)
+	assert.Equal(t, "WALLET_LOCKED", string(toolutils.ClassifyError("approve transaction", err).Code))
+}
+
+func TestApproveTransactionToolChecksSpendLimits(t *testing.T) {
+	const from = "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+	recorder := &keyRecordingChain{}
+	factory := chain.NewChainFactory()
+	factory.RegisterChain("polygon", recorder)
+	mockManager := &wallet.MockWalletManager{}
+	exceeded := &wallet.SpendLimitExceededError{Chain: "polygon", From: from, Amount: "5", Limit: wallet.SpendLimitDaily, Max: 4, Spent: "0"}
+	mockManager.On("CheckRecipient", mock.Anything, mock.Anything).Return(nil)
+	mockManager.On("ReserveSpend", "polygon", from, "0x1111111111111111111111111111111111111111", "5", "").Return(nil, exceeded)
+	tool := NewApproveTransactionTool(mockManager, nil, nil)
+	tool.SetChainFactory(factory)
+
+	tx := &wallet.PendingTransaction{Hash: approveTestTxHash, Chain: "polygon", From: from, To: "0x1111111111111111111111111111111111111111", Amount: "5", Status: "pending"}
+	err := tool.approveTransaction(context.Background(), tx)
+	require.ErrorIs(t, err, wallet.ErrSpendLimitExceeded)
+	assert.Empty(t, recorder.privateKey, "nothing is signed above the spend limits")
+	assert.Equal(t, "failed", tx.Status)
+	assert.Equal(t, "SPEND_LIMIT_EXCEEDED", string(toolutils.ClassifyError("approve transaction", err).Code))
+	mockManager.AssertNotCalled(t, "GetPrivateKeyForAddress", mock.Anything, mock.Anything)
+}
+
+func TestApproveTransactionToolRefusesBlockedRecipients(t *testing.T) {
+	const from = "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+	const scam = "0x1111111111111111111111111111111111111111"
+	recorder := &keyRecordingChain{}
+	factory := chain.NewChainFactory()
+	factory.RegisterChain("polygon", recorder)
+	mockManager := &wallet.MockWalletManager{}
+	mockManager.On("CheckRecipient", "polygon", scam).Return(fmt.Errorf("%w: %s is listed as Fake airdrop (scam)", wallet.ErrRecipientBlocked, scam))
+	tool := NewApproveTransactionTool(mockManager, nil, nil)
+	tool.SetChainFactory(factory)
+
+	tx := &wallet.PendingTransaction{Hash: approveTestTxHash, Chain: "polygon", From: from, To: scam, Amount: "1", Status: "pending"}
+	err := tool.approveTransaction(context.Background(), tx)
+	require.ErrorIs(t, err, wallet.ErrRecipientBlocked)
+	assert.Empty(t, recorder.privateKey, "nothing is signed for a blocked recipient")
+	assert.Equal(t, "failed", tx.Status)
+	assert.Equal(t, "RECIPIENT_BLOCKED", string(toolutils.ClassifyError("approve transaction", err).Code))
+	mockManager.AssertNotCalled(t, "ReserveSpend", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockManager.AssertNotCalled(t, "GetPrivateKeyForAddress", mock.Anything, mock.Anything)
+}
+
+func TestApproveTransactionToolReorgResetsConfirmations(t *testing.T) {
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+	tool := NewApproveTransactionTool(nil, broadcaster, nil)
+	tx := &wallet.PendingTransaction{Hash: approveTestTxHash, Chain: "ethereum", Status: "pending"}
+	detector := &chain.ReorgDetector{}
+
+	included := func(blockNumber uint64, blockHash string, confirmations uint64) *chain.TransactionConfirmation {
+		return &chain.TransactionConfirmation{
+			Status:                "pending",
+			Confirmations:         confirmations,
+			RequiredConfirmations: 12,
+			BlockNumber:           blockNumber,
+			BlockHash:             blockHash,
+			TxHash:                approveTestTxHash,
+		}
+	}
+
+	// Included in block A and accumulating confirmations
+	assert.False(t, tool.observeEVMConfirmation("ethereum", approveTestTxHash, tx, detector, included(100, "0xaaaa", 5), nil))
+	assert.Equal(t, uint64(5), tx.Confirmations)
+
+	// Receipt disappears after block A is reorged out
+	assert.False(t, tool.observeEVMConfirmation("ethereum", approveTestTxHash, tx, detector, nil, chain.ErrTransactionNotFound))
+	assert.Equal(t, uint64(0), tx.Confirmations)
+
+	select {
+	case evt := <-events:
+		assert.Equal(t, event.EventTypeTransactionReorged, evt.Type)
+		assert.Equal(t, "0xaaaa", evt.Data["previous_block_hash"])
+		assert.Equal(t, "", evt.Data["new_block_hash"])
+	default:
+		t.Fatal("expected transaction_reorged event")
+	}
+
+	// Receipt reappears in block B; counting resumes from the new block
+	assert.False(t, tool.observeEVMConfirmation("ethereum", approveTestTxHash, tx, detector, included(101, "0xbbbb", 1), nil))
+	assert.Equal(t, uint64(1), tx.Confirmations)
+	assert.Equal(t, uint64(101), tx.BlockNumber)
+	assert.Empty(t, events, "re-inclusion after a drop should not emit another reorg")
+
+	confirmed := included(101, "0xbbbb", 12)
+	confirmed.Status = "confirmed"
+	assert.True(t, tool.observeEVMConfirmation("ethereum", approveTestTxHash, tx, detector, confirmed, nil))
+
+	evt := <-events
+	assert.Equal(t, "ethereum_transaction_confirmed", evt.Type)
+	assert.Equal(t, "0xbbbb", evt.Data["block_hash"])
+}
+
+func TestApproveTransactionToolReplacementConfirmedStopsOriginalMonitor(t *testing.T) {
+	const (
+		from            = "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+		replacementHash = "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060"
+	)
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+	tool := NewApproveTransactionTool(nil, broadcaster, nil)
+	tool.replacements = chain.NewReplacementTracker()
+
+	original := &wallet.PendingTransaction{Hash: approveTestTxHash, Chain: "ethereum", From: from, Nonce: 7, Status: "pending"}
+	speedUp := &wallet.PendingTransaction{Hash: replacementHash, Chain: "ethereum", From: from, Nonce: 7, Status: "pending"}
+	tool.replacements.Track("ethereum", from, 7, approveTestTxHash)
+	tool.replacements.Track("ethereum", from, 7, replacementHash)
+
+	// The original is still unseen while the replacement confirms
+	assert.False(t, tool.observeEVMConfirmation("ethereum", approveTestTxHash, original, &chain.ReorgDetector{}, nil, chain.ErrTransactionNotFound))
+	assert.True(t, tool.observeEVMConfirmation("ethereum", replacementHash, speedUp, &chain.ReorgDetector{}, &chain.TransactionConfirmation{
+		Status:                "confirmed",
+		Confirmations:         12,
+		RequiredConfirmations: 12,
+		BlockNumber:           200,
+		BlockHash:             "0xcccc",
+		TxHash:                replacementHash,
+	}, nil))
+	evt := <-events
+	assert.Equal(t, "ethereum_transaction_confirmed", evt.Type)
+
+	// The replacement's monitor finishing must not hide the outcome from the original
+	tool.replacements.Forget("ethereum", replacementHash)
+
+	// Next poll of the original ends with a replacement event instead of a timeout
+	assert.True(t, tool.observeEVMConfirmation("ethereum", approveTestTxHash, original, &chain.ReorgDetector{}, nil, chain.ErrTransactionNotFound))
+	assert.Equal(t, "replaced", original.Status)
+
+	select {
+	case evt := <-events:
+		assert.Equal(t, event.EventTypeTransactionReplacedConfirmed, evt.Type)
+		assert.Equal(t, approveTestTxHash, evt.Data["transaction_hash"])
+		assert.Equal(t, replacementHash, evt.Data["replacement_hash"])
+		assert.Equal(t, uint64(7), evt.Data["nonce"])
+	default:
+		t.Fatal("expected transaction_replaced_confirmed event")
+	}
+
+	tool.replacements.Forget("ethereum", approveTestTxHash)
+	_, _, ok := tool.replacements.ReplacedBy("ethereum", approveTestTxHash)
+	assert.False(t, ok)
+}
@@ -0,0 +1,116 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"go.uber.org/zap"
+)
+
+// balanceReadTimeout bounds reading the balances around a transaction
+const balanceReadTimeout = 15 * time.Second
+
+// balanceSnapshot holds what the addresses of a sent transaction held before it
+type balanceSnapshot struct {
+	chainImpl chain.IChain
+	token     string
+	addresses []string
+	balances  map[string]string
+}
+
+// balanceChangeNotifier broadcasts balance_changed for the sender and
+// recipient of a sent transaction once it confirms. Each transaction is
+// reported once: its snapshot is dropped by the first confirmation.
+type balanceChangeNotifier struct {
+	broadcaster *event.EventBroadcaster
+	logger      *zap.Logger
+
+	mu      sync.Mutex
+	pending map[string]*balanceSnapshot
+}
+
+func newBalanceChangeNotifier(broadcaster *event.EventBroadcaster, logger *zap.Logger) *balanceChangeNotifier {
+	return &balanceChangeNotifier{
+		broadcaster: broadcaster,
+		logger:      logger,
+		pending:     make(map[string]*balanceSnapshot),
+	}
+}
+
+// snapshot reads the balances of the sender and recipient of tx before it is
+// sent. Balances that cannot be read are left empty.
+func (n *balanceChangeNotifier) snapshot(ctx context.Context, chainImpl chain.IChain, tx *wallet.PendingTransaction) *balanceSnapshot {
+	if n.broadcaster == nil {
+		return nil
+	}
+	snapshot := &balanceSnapshot{chainImpl: chainImpl, token: tx.Token, balances: make(map[string]string)}
+	for _, address := range []string{tx.From, tx.To} {
+		if _, seen := snapshot.balances[address]; address == "" || seen {
+			continue
+		}
+		snapshot.addresses = append(snapshot.addresses, address)
+		snapshot.balances[address] = n.readBalance(ctx, chainImpl, address, tx.Token)
+	}
+	return snapshot
+}
+
+// track remembers snapshot until txHash on chainName confirms
+func (n *balanceChangeNotifier) track(chainName, txHash string, snapshot *balanceSnapshot) {
+	if snapshot == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.pending[chainName+":"+txHash] = snapshot
+}
+
+// forget drops the snapshot of txHash, e.g. when its monitor gives up
+func (n *balanceChangeNotifier) forget(chainName, txHash string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.pending, chainName+":"+txHash)
+}
+
+// confirmed reads the balances again now that txHash is confirmed and
+// broadcasts those that changed. Later calls for the same transaction do
+// nothing.
+func (n *balanceChangeNotifier) confirmed(chainName, txHash string) {
+	key := chainName + ":" + txHash
+	n.mu.Lock()
+	snapshot := n.pending[key]
+	delete(n.pending, key)
+	n.mu.Unlock()
+	if snapshot == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), balanceReadTimeout)
+	defer cancel()
+	for _, address := range snapshot.addresses {
+		oldBalance := snapshot.balances[address]
+		newBalance := n.readBalance(ctx, snapshot.chainImpl, address, snapshot.token)
+		if newBalance == "" || newBalance == oldBalance {
+			continue
+		}
+		n.broadcaster.BroadcastBalanceChanged(address, snapshot.token, oldBalance, newBalance)
+	}
+}
+
+func (n *balanceChangeNotifier) readBalance(ctx context.Context, chainImpl chain.IChain, address, token string) string {
+	ctx, cancel := context.WithTimeout(ctx, balanceReadTimeout)
+	defer cancel()
+	balance, err := chainImpl.GetBalance(ctx, address, token)
+	if err != nil {
+		n.logger.Debug("Failed to read balance for balance_changed",
+			zap.String("address", address),
+			zap.String("token", token),
+			zap.Error(err))
+		return ""
+	}
+	return balance
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	balanceTestFrom = "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+	balanceTestTo   = "0x1111111111111111111111111111111111111111"
+)
+
+// balanceMovingChain moves the sent amount between its balances on every send
+type balanceMovingChain struct {
+	chain.IChain
+	mu       sync.Mutex
+	balances map[string]string
+	after    map[string]string
+}
+
+func (c *balanceMovingChain) GetBalance(_ context.Context, address, _ string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.balances[address], nil
+}
+
+func (c *balanceMovingChain) SendTransaction(_ context.Context, _, _, _, _, _ string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.balances = c.after
+	return "0xsent", nil
+}
+
+// balanceChangedEvents drains events and returns the balance_changed ones by address
+func balanceChangedEvents(events chan *event.Event) map[string]*event.Event {
+	changed := make(map[string]*event.Event)
+	for {
+		select {
+		case evt := <-events:
+			if evt.Type == event.EventTypeBalanceChanged {
+				changed[evt.Data["address"].(string)] = evt
+			}
+		default:
+			return changed
+		}
+	}
+}
+
+func TestApproveTransactionToolBroadcastsBalanceChangedOnce(t *testing.T) {
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+	chainImpl := &balanceMovingChain{
+		balances: map[string]string{balanceTestFrom: "2.5", balanceTestTo: "0"},
+		after:    map[string]string{balanceTestFrom: "1.49", balanceTestTo: "1"},
+	}
+	factory := chain.NewChainFactory()
+	factory.RegisterChain("polygon", chainImpl)
+	mockManager := &wallet.MockWalletManager{}
+	mockManager.On("AuthorizeSigning", mock.Anything, mock.Anything).Return(nil)
+	mockManager.On("CheckRecipient", mock.Anything, mock.Anything).Return(nil)
+	mockManager.On("ReserveSpend", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	mockManager.On("GetPrivateKeyForAddress", mock.Anything, balanceTestFrom).Return("0xwalletkey", nil)
+	tool := NewApproveTransactionTool(mockManager, broadcaster, nil)
+	tool.SetChainFactory(factory)
+	t.Cleanup(func() { tool.CancelMonitors() })
+
+	tx := &wallet.PendingTransaction{Hash: approveTestTxHash, Chain: "polygon", From: balanceTestFrom, To: balanceTestTo, Amount: "1", Token: "MATIC", Status: "pending"}
+	require.NoError(t, tool.approveTransaction(context.Background(), tx))
+	assert.Empty(t, balanceChangedEvents(events), "balances are only reported once the send confirms")
+
+	confirmed := &chain.TransactionConfirmation{Status: "confirmed", Confirmations: 12, RequiredConfirmations: 12, BlockNumber: 100, BlockHash: "0xaaaa", TxHash: "0xsent"}
+	assert.True(t, tool.observeEVMConfirmation("polygon", "0xsent", tx, &chain.ReorgDetector{}, confirmed, nil))
+
+	changed := balanceChangedEvents(events)
+	require.Len(t, changed, 2)
+	assert.Equal(t, map[string]interface{}{"address": balanceTestFrom, "token": "MATIC", "old_balance": "2.5", "new_balance": "1.49"}, changed[balanceTestFrom].Data)
+	assert.Equal(t, map[string]interface{}{"address": balanceTestTo, "token": "MATIC", "old_balance": "0", "new_balance": "1"}, changed[balanceTestTo].Data)
+
+	// Further confirmations of the same transaction stay quiet
+	assert.True(t, tool.observeEVMConfirmation("polygon", "0xsent", tx, &chain.ReorgDetector{}, confirmed, nil))
+	assert.Empty(t, balanceChangedEvents(events))
+}
+
+func TestBalanceChangeNotifierSkipsUnchangedBalances(t *testing.T) {
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+	chainImpl := &balanceMovingChain{balances: map[string]string{balanceTestFrom: "2.5", balanceTestTo: "0"}}
+	notifier := newBalanceChangeNotifier(broadcaster, zap.NewNop())
+
+	// A self-transfer is read once, and only the fee moves its balance
+	tx := &wallet.PendingTransaction{From: balanceTestFrom, To: balanceTestFrom, Token: "ETH"}
+	notifier.track("ethereum", "0xself", notifier.snapshot(context.Background(), chainImpl, tx))
+	chainImpl.balances = map[string]string{balanceTestFrom: "2.499"}
+	notifier.confirmed("ethereum", "0xself")
+	changed := balanceChangedEvents(events)
+	require.Len(t, changed, 1)
+	assert.Equal(t, "2.499", changed[balanceTestFrom].Data["new_balance"])
+
+	// Nothing is reported for a transaction whose monitor gave up
+	tx = &wallet.PendingTransaction{From: balanceTestFrom, To: balanceTestTo, Token: "ETH"}
+	notifier.track("ethereum", "0xdropped", notifier.snapshot(context.Background(), chainImpl, tx))
+	notifier.forget("ethereum", "0xdropped")
+	chainImpl.balances = map[string]string{balanceTestFrom: "1", balanceTestTo: "1"}
+	notifier.confirmed("ethereum", "0xdropped")
+	assert.Empty(t, balanceChangedEvents(events))
+}
@@ -0,0 +1,58 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	stdErrors "errors"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// CancelPriceTriggerTool implements the MCP "cancel_price_trigger" tool, which
+// stops a price trigger from firing again.
+type CancelPriceTriggerTool struct {
+	manager wallet.IWalletManager
+}
+
+// NewCancelPriceTriggerTool constructs a CancelPriceTriggerTool with the given wallet manager.
+func NewCancelPriceTriggerTool(manager wallet.IWalletManager) *CancelPriceTriggerTool {
+	return &CancelPriceTriggerTool{manager: manager}
+}
+
+// GetMeta returns the MCP tool definition for "cancel_price_trigger".
+func (t *CancelPriceTriggerTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("cancel_price_trigger",
+		mcp.WithDescription("Cancel a price trigger so it does not fire again. An action that is already running is not interrupted"),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("Trigger ID returned by create_price_trigger or list_price_triggers"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "cancel_price_trigger" tool.
+func (t *CancelPriceTriggerTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := req.RequireString("id")
+		if err != nil || strings.TrimSpace(id) == "" {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("id")), nil
+		}
+
+		trigger, err := t.manager.CancelPriceTrigger(ctx, strings.TrimSpace(id))
+		switch {
+		case stdErrors.Is(err, wallet.ErrPriceTriggerNotFound):
+			return toolutils.FormatErrorResult(errors.ValidationError("id", "no price trigger with this id")), nil
+		case stdErrors.Is(err, wallet.ErrPriceTriggerFinished):
+			return toolutils.FormatErrorResult(errors.ValidationError("id", err.Error())), nil
+		case err != nil:
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("cancel price trigger", err)), nil
+		}
+
+		return mcp.NewToolResultText("### Price Trigger Cancelled\n\n" + formatPriceTriggerMarkdown(trigger)), nil
+	}
+}
@@ -0,0 +1,58 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	stdErrors "errors"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// CancelScheduledTool implements the MCP "cancel_scheduled" tool, which stops a
+// scheduled transaction from running again.
+type CancelScheduledTool struct {
+	manager wallet.IWalletManager
+}
+
+// NewCancelScheduledTool constructs a CancelScheduledTool with the given wallet manager.
+func NewCancelScheduledTool(manager wallet.IWalletManager) *CancelScheduledTool {
+	return &CancelScheduledTool{manager: manager}
+}
+
+// GetMeta returns the MCP tool definition for "cancel_scheduled".
+func (t *CancelScheduledTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("cancel_scheduled",
+		mcp.WithDescription("Cancel a scheduled transaction so it does not run again. A run that is already being sent is not interrupted"),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("Schedule ID returned by schedule_transaction or list_scheduled"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "cancel_scheduled" tool.
+func (t *CancelScheduledTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := req.RequireString("id")
+		if err != nil || strings.TrimSpace(id) == "" {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("id")), nil
+		}
+
+		job, err := t.manager.CancelScheduledTransaction(ctx, strings.TrimSpace(id))
+		switch {
+		case stdErrors.Is(err, wallet.ErrScheduledTransactionNotFound):
+			return toolutils.FormatErrorResult(errors.ValidationError("id", "no scheduled transaction with this id")), nil
+		case stdErrors.Is(err, wallet.ErrScheduledTransactionFinished):
+			return toolutils.FormatErrorResult(errors.ValidationError("id", err.Error())), nil
+		case err != nil:
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("cancel scheduled transaction", err)), nil
+		}
+
+		return mcp.NewToolResultText("### Scheduled Transaction Cancelled\n\n" + formatScheduledTransactionMarkdown(job)), nil
+	}
+}
@@ -0,0 +1,41 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// commitmentParam is the tool parameter overriding the Solana commitment level
+const commitmentParam = "commitment"
+
+// commitmentDescription documents commitmentParam in tool schemas
+const commitmentDescription = "Solana commitment level for this call (optional, defaults to chains.solana.commitment): processed, confirmed or finalized"
+
+// withCommitmentOption adds the optional commitment parameter to a tool schema
+func withCommitmentOption() mcp.ToolOption {
+	return mcp.WithString(commitmentParam,
+		mcp.Description(commitmentDescription),
+		mcp.Enum(chain.CommitmentProcessed, chain.CommitmentConfirmed, chain.CommitmentFinalized),
+	)
+}
+
+// commitmentContext applies the commitment parameter of req to ctx. The
+// parameter is only accepted for Solana; an empty value keeps the configured level.
+func commitmentContext(ctx context.Context, req mcp.CallToolRequest, chainName string) (context.Context, *errors.Error) {
+	commitment := strings.ToLower(strings.TrimSpace(req.GetString(commitmentParam, "")))
+	if commitment == "" {
+		return ctx, nil
+	}
+	if chainName != "solana" {
+		return ctx, errors.ValidationError(commitmentParam, "commitment levels are only supported on Solana")
+	}
+	if err := chain.ValidateCommitment(commitment); err != nil {
+		return ctx, errors.ValidationError(commitmentParam, err.Error())
+	}
+	return chain.WithCommitment(ctx, commitment), nil
+}
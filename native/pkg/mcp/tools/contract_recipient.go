@@ -0,0 +1,50 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// contractRecipientConfirmParam is the tool parameter acknowledging a transfer
+// the recipient check warned about
+const contractRecipientConfirmParam = "confirm_contract_recipient"
+
+// contractRecipientConfirmDescription documents contractRecipientConfirmParam in tool schemas
+const contractRecipientConfirmDescription = "Confirm a transfer the recipient check warned about: tokens sent to a contract not listed in security.safe_contract_recipients, SOL sent to a token account or program, or a recipient whose address book category is in wallet.address_book.warn_categories (default: false)"
+
+// formatRecipientTypeMarkdown renders the recipient classification line of a
+// tool result; err explains why it is unknown
+func formatRecipientTypeMarkdown(recipient *walletchain.RecipientClassification, err error) string {
+	if err != nil || recipient == nil {
+		return "- **Recipient Type**: `unknown` (recipient could not be checked)\n"
+	}
+	markdown := fmt.Sprintf("- **Recipient Type**: `%s`\n", recipient.Kind)
+	if recipient.KnownSafe {
+		markdown = fmt.Sprintf("- **Recipient Type**: `%s` (listed as safe)\n", recipient.Kind)
+	}
+	if recipient.Label != "" {
+		entry := wallet.AddressBookEntry{Label: recipient.Label, Category: recipient.Category}
+		markdown += fmt.Sprintf("- **Recipient Label**: `%s`\n", entry.Display())
+	}
+	return markdown
+}
+
+// formatContractRecipientWarningMarkdown renders the warning returned instead
+// of sending to a recipient that was not confirmed
+func formatContractRecipientWarningMarkdown(chainName, amount, token string, recipient *walletchain.RecipientClassification, retryHint string) string {
+	markdown := "### ⚠️ Recipient Requires Confirmation\n\n" +
+		fmt.Sprintf("- **Chain**: `%s`\n", chainName) +
+		fmt.Sprintf("- **To**: `%s`\n", recipient.Address) +
+		fmt.Sprintf("- **Amount**: `%s`\n", amount)
+	if token != "" {
+		markdown += fmt.Sprintf("- **Token**: `%s`\n", token)
+	}
+	markdown += formatRecipientTypeMarkdown(recipient, nil) +
+		fmt.Sprintf("- **Warning**: %s\n", recipient.Warning) +
+		"- **Status**: `awaiting_confirmation`\n" +
+		fmt.Sprintf("\nNothing was signed or sent. %s\n", retryHint)
+	return markdown
+}
@@ -0,0 +1,307 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// CreatePriceTriggerTool implements the MCP "create_price_trigger" tool, which
+// sends or swaps tokens once the USD price of a token crosses a threshold,
+// e.g. to buy a dip or take profit.
+type CreatePriceTriggerTool struct {
+	manager   wallet.IWalletManager
+	priceFeed wallet.PriceFeed
+	largeTx   largeTxGuard
+}
+
+// NewCreatePriceTriggerTool constructs a CreatePriceTriggerTool that prices
+// tokens through priceFeed.
+func NewCreatePriceTriggerTool(manager wallet.IWalletManager, priceFeed wallet.PriceFeed) *CreatePriceTriggerTool {
+	return &CreatePriceTriggerTool{manager: manager, priceFeed: priceFeed}
+}
+
+// NewCreatePriceTriggerToolWithConfig constructs a CreatePriceTriggerTool that
+// also asks for confirm_large up front when the action meets the per-chain
+// large_tx_threshold in cfg.
+func NewCreatePriceTriggerToolWithConfig(manager wallet.IWalletManager, broadcaster *event.EventBroadcaster, priceFeed wallet.PriceFeed, cfg *config.Config) *CreatePriceTriggerTool {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return &CreatePriceTriggerTool{
+		manager:   manager,
+		priceFeed: priceFeed,
+		largeTx:   largeTxGuard{chains: cfg.Chains, priceFeed: priceFeed, broadcaster: broadcaster},
+	}
+}
+
+// GetMeta returns the MCP tool definition for "create_price_trigger".
+func (t *CreatePriceTriggerTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("create_price_trigger",
+		mcp.WithDescription("Create a trigger that sends or swaps tokens when the USD price of a token goes below or above a threshold. The action runs like send_transaction or swap_tokens when the trigger fires and is refused if the wallet is locked or frozen or it needs a confirmation that was not given here. Triggers fire once by default and survive restarts; use list_price_triggers and cancel_price_trigger to manage them"),
+		mcp.WithString("symbol",
+			mcp.Required(),
+			mcp.Description("Symbol of the watched token, e.g. ETH, BNB, SOL"),
+		),
+		mcp.WithString("condition",
+			mcp.Required(),
+			mcp.Description("Fire when the price is at or below (below) or at or above (above) the threshold"),
+			mcp.Enum(wallet.TriggerBelow, wallet.TriggerAbove),
+		),
+		mcp.WithNumber("price",
+			mcp.Required(),
+			mcp.Description("Threshold price in USD"),
+		),
+		mcp.WithString("action",
+			mcp.Required(),
+			mcp.Description("What to do when the trigger fires"),
+			mcp.Enum(wallet.TriggerActionSend, wallet.TriggerActionSwap),
+		),
+		mcp.WithString("chain",
+			mcp.Required(),
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol"),
+		),
+		mcp.WithString("from",
+			mcp.Required(),
+			mcp.Description("Wallet address that sends or swaps"),
+		),
+		mcp.WithString("amount",
+			mcp.Required(),
+			mcp.Description("Amount to send, or amount of token to swap, every time the trigger fires"),
+		),
+		mcp.WithString("to",
+			mcp.Description("Recipient address (send only)"),
+		),
+		mcp.WithString("token",
+			mcp.Description("Token to send (contract address, native token if not provided) or to swap from (required for swaps)"),
+		),
+		mcp.WithString("to_token",
+			mcp.Description("Token to swap to (swap only)"),
+		),
+		mcp.WithNumber("slippage",
+			mcp.Description("Swap slippage tolerance (default: 0.005 = 0.5%)"),
+		),
+		mcp.WithBoolean("repeat",
+			mcp.Description("Fire again each time the price crosses the threshold anew, instead of once (default: false)"),
+		),
+		mcp.WithNumber("max_fires",
+			mcp.Description("Optional number of times a repeating trigger fires (default: until cancelled)"),
+		),
+		mcp.WithString("cooldown",
+			mcp.Description("Optional minimum time between fires of a repeating trigger as a duration (e.g. 1h)"),
+		),
+		mcp.WithBoolean(largeTxConfirmParam,
+			mcp.Description(largeTxConfirmDescription),
+		),
+		mcp.WithBoolean(contractRecipientConfirmParam,
+			mcp.Description(contractRecipientConfirmDescription),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "create_price_trigger" tool.
+func (t *CreatePriceTriggerTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		symbol, err := req.RequireString("symbol")
+		if err != nil || strings.TrimSpace(symbol) == "" {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("symbol")), nil
+		}
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		condition := strings.ToLower(strings.TrimSpace(req.GetString("condition", "")))
+		if condition != wallet.TriggerBelow && condition != wallet.TriggerAbove {
+			return toolutils.FormatErrorResult(errors.ValidationError("condition", "must be below or above")), nil
+		}
+		threshold := req.GetFloat("price", 0)
+		if threshold <= 0 {
+			return toolutils.FormatErrorResult(errors.ValidationError("price", "must be a positive USD price")), nil
+		}
+
+		chain, err := req.RequireString("chain")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("chain")), nil
+		}
+		normalizedChain, err := toolutils.NormalizeChainName(chain)
+		if err != nil {
+			if appErr, ok := err.(*errors.Error); ok {
+				return toolutils.FormatErrorResult(appErr), nil
+			}
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+		from, err := req.RequireString("from")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("from")), nil
+		}
+		amount, err := req.RequireString("amount")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("amount")), nil
+		}
+		if strings.EqualFold(strings.TrimSpace(amount), "max") {
+			return toolutils.FormatErrorResult(errors.ValidationError("amount", "must be a fixed value; \"max\" cannot be used in a trigger")), nil
+		}
+
+		action := wallet.PriceTriggerAction{
+			Kind:   strings.ToLower(strings.TrimSpace(req.GetString("action", ""))),
+			Chain:  normalizedChain,
+			From:   from,
+			Amount: amount,
+			Token:  req.GetString("token", ""),
+		}
+		switch action.Kind {
+		case wallet.TriggerActionSend:
+			action.To = req.GetString("to", "")
+			if action.To == "" {
+				return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("to")), nil
+			}
+		case wallet.TriggerActionSwap:
+			if _, ok := swapChainIDs[normalizedChain]; !ok {
+				return toolutils.FormatErrorResult(errors.ValidationError("chain", fmt.Sprintf("swaps are not supported on %s", normalizedChain))), nil
+			}
+			action.ToToken = req.GetString("to_token", "")
+			if action.Token == "" {
+				return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("token")), nil
+			}
+			if action.ToToken == "" {
+				return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("to_token")), nil
+			}
+			action.Slippage = req.GetFloat("slippage", defaultSwapSlippage)
+			if action.Slippage <= 0 || action.Slippage > 0.5 {
+				return toolutils.FormatErrorResult(errors.ValidationError("slippage", "must be greater than 0 and at most 0.5")), nil
+			}
+		default:
+			return toolutils.FormatErrorResult(errors.ValidationError("action", "must be send or swap")), nil
+		}
+
+		repeat := req.GetBool("repeat", false)
+		maxFires := req.GetFloat("max_fires", 0)
+		if maxFires < 0 || maxFires != math.Trunc(maxFires) {
+			return toolutils.FormatErrorResult(errors.ValidationError("max_fires", "must be a non-negative whole number")), nil
+		}
+		var cooldown time.Duration
+		if value := strings.TrimSpace(req.GetString("cooldown", "")); value != "" {
+			cooldown, err = time.ParseDuration(value)
+			if err != nil || cooldown <= 0 {
+				return toolutils.FormatErrorResult(errors.ValidationError("cooldown", "must be a positive duration, e.g. 1h")), nil
+			}
+		}
+		if !repeat && (maxFires > 0 || cooldown > 0) {
+			return toolutils.FormatErrorResult(errors.ValidationError("repeat", "max_fires and cooldown require repeat=true")), nil
+		}
+		if !repeat {
+			maxFires = 1
+		}
+
+		// Prices the feed cannot produce would leave the trigger waiting forever
+		price, priceErr := t.currentPrice(ctx, symbol)
+		if priceErr != nil && !stdErrors.Is(priceErr, wallet.ErrPriceFeedUnavailable) {
+			return toolutils.FormatErrorResult(errors.ValidationError("symbol", priceErr.Error())), nil
+		}
+
+		if toolErr := toolutils.RequireUnlocked(t.manager, "create price trigger"); toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
+		// Ask for the confirmations now; nobody may be around when the trigger fires
+		confirmRecipient := req.GetBool(contractRecipientConfirmParam, false)
+		if action.Kind == wallet.TriggerActionSend {
+			recipient, recipientErr := t.manager.ClassifyRecipient(ctx, normalizedChain, action.To, action.Token)
+			if recipientErr == nil && recipient.Warning != "" && !confirmRecipient {
+				return mcp.NewToolResultText(formatContractRecipientWarningMarkdown(normalizedChain, amount, action.Token, recipient,
+					"Call create_price_trigger again with `confirm_contract_recipient=true` to create it.")), nil
+			}
+		}
+		confirmLarge := req.GetBool(largeTxConfirmParam, false)
+		if check := t.largeTx.check(ctx, "", normalizedChain, from, action.To, amount, action.Token, confirmLarge); check != nil && check.Large && !confirmLarge {
+			return mcp.NewToolResultText(formatLargeTxWarningMarkdown(check, "Call create_price_trigger again with `confirm_large=true` to create it.")), nil
+		}
+
+		trigger, err := t.manager.CreatePriceTrigger(ctx, &wallet.PriceTrigger{
+			Symbol:                   symbol,
+			Comparison:               condition,
+			Threshold:                threshold,
+			Action:                   action,
+			MaxFires:                 int(maxFires),
+			Cooldown:                 cooldown,
+			ConfirmLarge:             confirmLarge,
+			ConfirmContractRecipient: confirmRecipient,
+		})
+		if err != nil {
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("create price trigger", err)), nil
+		}
+
+		markdown := "### Price Trigger Created\n\n" + formatPriceTriggerMarkdown(trigger)
+		if priceErr != nil {
+			markdown += "- **Current Price**: unavailable; the trigger is checked once the price feed recovers\n"
+		} else {
+			markdown += fmt.Sprintf("- **Current Price**: `$%.2f`\n", price)
+			if trigger.Met(price) {
+				markdown += "\n**Note:** the condition already holds, so the trigger fires on its next check.\n"
+			}
+		}
+		markdown += "\nUse `list_price_triggers` to follow it and `cancel_price_trigger` to stop it.\n"
+		return mcp.NewToolResultText(markdown), nil
+	}
+}
+
+func (t *CreatePriceTriggerTool) currentPrice(ctx context.Context, symbol string) (float64, error) {
+	if t.priceFeed == nil {
+		return 0, fmt.Errorf("%w: no price feed configured", wallet.ErrPriceFeedUnavailable)
+	}
+	return t.priceFeed.USDPrice(ctx, symbol)
+}
+
+// formatPriceTriggerMarkdown renders one price trigger for the agent
+func formatPriceTriggerMarkdown(trigger *wallet.PriceTrigger) string {
+	action := trigger.Action
+	markdown := fmt.Sprintf("- **Trigger ID**: `%s`\n", trigger.ID) +
+		fmt.Sprintf("- **Status**: `%s`\n", trigger.Status) +
+		fmt.Sprintf("- **Condition**: `%s %s $%g`\n", trigger.Symbol, trigger.Comparison, trigger.Threshold) +
+		fmt.Sprintf("- **Action**: `%s`\n", action.Kind) +
+		fmt.Sprintf("- **Chain**: `%s`\n", action.Chain) +
+		fmt.Sprintf("- **From**: `%s`\n", action.From) +
+		fmt.Sprintf("- **Amount**: `%s`\n", action.Amount)
+	if action.Kind == wallet.TriggerActionSwap {
+		markdown += fmt.Sprintf("- **Swap**: `%s` → `%s` (slippage %.2f%%)\n", action.Token, action.ToToken, action.Slippage*100)
+	} else {
+		markdown += fmt.Sprintf("- **To**: `%s`\n", action.To)
+		if action.Token != "" {
+			markdown += fmt.Sprintf("- **Token**: `%s`\n", action.Token)
+		}
+	}
+	switch {
+	case trigger.MaxFires == 1:
+		markdown += "- **Repeat**: `once`\n"
+	case trigger.MaxFires > 1:
+		markdown += fmt.Sprintf("- **Repeat**: `up to %d times`\n", trigger.MaxFires)
+	default:
+		markdown += "- **Repeat**: `until cancelled`\n"
+	}
+	if trigger.Cooldown > 0 {
+		markdown += fmt.Sprintf("- **Cooldown**: `%s`\n", trigger.Cooldown)
+	}
+	markdown += fmt.Sprintf("- **Fires**: `%d`\n", trigger.Fires)
+	if !trigger.LastCheckedAt.IsZero() {
+		markdown += fmt.Sprintf("- **Last Price**: `$%.2f` at `%s`\n", trigger.LastPrice, trigger.LastCheckedAt.UTC().Format(time.RFC3339))
+	}
+	if !trigger.LastFiredAt.IsZero() {
+		markdown += fmt.Sprintf("- **Last Fired**: `%s`\n", trigger.LastFiredAt.UTC().Format(time.RFC3339))
+	}
+	if trigger.LastTxHash != "" {
+		markdown += fmt.Sprintf("- **Last Transaction Hash**: `%s`\n", trigger.LastTxHash)
+	}
+	if trigger.LastError != "" {
+		markdown += fmt.Sprintf("- **Last Error**: %s\n", trigger.LastError)
+	}
+	return markdown
+}
@@ -0,0 +1,278 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/dex/providers"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWalletManagerForTriggers struct {
+	*wallet.MockWalletManager
+	triggers  []*wallet.PriceTrigger
+	recipient *walletchain.RecipientClassification
+	unlocked  bool
+	sends     int
+	keysFor   []string
+	reserved  []string
+	limitErr  error
+}
+
+func newMockWalletManagerForTriggers() *mockWalletManagerForTriggers {
+	return &mockWalletManagerForTriggers{MockWalletManager: &wallet.MockWalletManager{}, unlocked: true}
+}
+
+func (m *mockWalletManagerForTriggers) IsUnlocked() bool {
+	return m.unlocked
+}
+
+func (m *mockWalletManagerForTriggers) GetPrivateKeyForAddress(ctx context.Context, address string) (string, error) {
+	if !m.unlocked {
+		return "", wallet.ErrWalletLocked
+	}
+	m.keysFor = append(m.keysFor, address)
+	return "0xwalletkey", nil
+}
+
+func (m *mockWalletManagerForTriggers) ReserveSpend(chainName, from, to, amount, token string) (*wallet.SpendReservation, error) {
+	if m.limitErr != nil {
+		return nil, m.limitErr
+	}
+	m.reserved = append(m.reserved, amount+" "+token)
+	return nil, nil
+}
+
+func (m *mockWalletManagerForTriggers) IsFrozen() bool {
+	return false
+}
+
+func (m *mockWalletManagerForTriggers) ClassifyRecipient(ctx context.Context, chainName, address, token string) (*walletchain.RecipientClassification, error) {
+	if m.recipient != nil {
+		return m.recipient, nil
+	}
+	return &walletchain.RecipientClassification{Address: address, Kind: walletchain.RecipientEOA}, nil
+}
+
+func (m *mockWalletManagerForTriggers) CreatePriceTrigger(ctx context.Context, trigger *wallet.PriceTrigger) (*wallet.PriceTrigger, error) {
+	stored := *trigger
+	stored.ID = "trig_0123456789abcdef"
+	stored.Status = wallet.TriggerStatusActive
+	m.triggers = append(m.triggers, &stored)
+	return &stored, nil
+}
+
+func (m *mockWalletManagerForTriggers) ListPriceTriggers(ctx context.Context, status string) ([]*wallet.PriceTrigger, error) {
+	return m.triggers, nil
+}
+
+func (m *mockWalletManagerForTriggers) CancelPriceTrigger(ctx context.Context, id string) (*wallet.PriceTrigger, error) {
+	for _, trigger := range m.triggers {
+		if trigger.ID == id {
+			trigger.Status = wallet.TriggerStatusCancelled
+			return trigger, nil
+		}
+	}
+	return nil, wallet.ErrPriceTriggerNotFound
+}
+
+func (m *mockWalletManagerForTriggers) SendTransaction(ctx context.Context, chain, from, to, amount, token string) (string, error) {
+	m.sends++
+	return "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", nil
+}
+
+func triggerArguments() map[string]any {
+	return map[string]any{
+		"symbol":    "eth",
+		"condition": "below",
+		"price":     float64(2000),
+		"action":    "swap",
+		"chain":     "eth",
+		"from":      "0x1111111111111111111111111111111111111111",
+		"amount":    "500",
+		"token":     "USDC",
+		"to_token":  "ETH",
+	}
+}
+
+func TestCreatePriceTriggerTool(t *testing.T) {
+	mockManager := newMockWalletManagerForTriggers()
+	handler := NewCreatePriceTriggerTool(mockManager, staticUSDPriceFeed{"ETH": 2500}).GetHandler()
+
+	result, err := handler(context.Background(), scheduleRequest("create_price_trigger", triggerArguments()))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	require.Len(t, mockManager.triggers, 1)
+
+	trigger := mockManager.triggers[0]
+	assert.Equal(t, "ETH", trigger.Symbol)
+	assert.Equal(t, wallet.TriggerBelow, trigger.Comparison)
+	assert.Equal(t, 1, trigger.MaxFires)
+	assert.Equal(t, "ethereum", trigger.Action.Chain)
+	assert.Equal(t, defaultSwapSlippage, trigger.Action.Slippage)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "### Price Trigger Created")
+	assert.Contains(t, textContent.Text, "**Condition**: `ETH below $2000`")
+	assert.Contains(t, textContent.Text, "**Current Price**: `$2500.00`")
+	assert.NotContains(t, textContent.Text, "already holds")
+}
+
+func TestCreatePriceTriggerToolPriceFeed(t *testing.T) {
+	// A symbol the feed cannot price would never fire
+	mockManager := newMockWalletManagerForTriggers()
+	args := triggerArguments()
+	args["symbol"] = "PEPE"
+	result, err := NewCreatePriceTriggerTool(mockManager, staticUSDPriceFeed{"ETH": 2500}).GetHandler()(context.Background(), scheduleRequest("create_price_trigger", args))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Empty(t, mockManager.triggers)
+
+	// An outage does not stop the trigger from being created
+	result, err = NewCreatePriceTriggerTool(mockManager, offlinePriceFeed{}).GetHandler()(context.Background(), scheduleRequest("create_price_trigger", triggerArguments()))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "**Current Price**: unavailable")
+}
+
+func TestCreatePriceTriggerToolValidation(t *testing.T) {
+	cases := map[string]map[string]any{
+		"bad condition":        {"condition": "equals"},
+		"zero price":           {"price": float64(0)},
+		"bad action":           {"action": "bridge"},
+		"swap without token":   {"token": nil},
+		"send without to":      {"action": "send"},
+		"max amount":           {"amount": "max"},
+		"max fires once":       {"max_fires": float64(3)},
+		"fractional max fires": {"repeat": true, "max_fires": 1.5},
+		"bad cooldown":         {"repeat": true, "cooldown": "soon"},
+		"bad slippage":         {"slippage": 0.9},
+	}
+	for name, overrides := range cases {
+		t.Run(name, func(t *testing.T) {
+			args := triggerArguments()
+			for key, value := range overrides {
+				if value == nil {
+					delete(args, key)
+				} else {
+					args[key] = value
+				}
+			}
+			mockManager := newMockWalletManagerForTriggers()
+			result, err := NewCreatePriceTriggerTool(mockManager, staticUSDPriceFeed{"ETH": 2500}).GetHandler()(context.Background(), scheduleRequest("create_price_trigger", args))
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Empty(t, mockManager.triggers)
+		})
+	}
+}
+
+func TestCreatePriceTriggerToolRequiresConfirmations(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Chains.Ethereum.LargeTxThreshold = config.LargeTxThresholdConfig{Native: 1}
+	mockManager := newMockWalletManagerForTriggers()
+	handler := NewCreatePriceTriggerToolWithConfig(mockManager, nil, staticUSDPriceFeed{"ETH": 2500}, cfg).GetHandler()
+
+	args := triggerArguments()
+	args["action"] = "send"
+	args["to"] = "0x2222222222222222222222222222222222222222"
+	args["amount"] = "2"
+	args["token"] = ""
+	args["repeat"] = true
+	result, err := handler(context.Background(), scheduleRequest("create_price_trigger", args))
+	require.NoError(t, err)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "confirm_large=true")
+	assert.Empty(t, mockManager.triggers)
+
+	args["confirm_large"] = true
+	result, err = handler(context.Background(), scheduleRequest("create_price_trigger", args))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	require.Len(t, mockManager.triggers, 1)
+	assert.True(t, mockManager.triggers[0].ConfirmLarge)
+	assert.Equal(t, 0, mockManager.triggers[0].MaxFires)
+	assert.Equal(t, 0, mockManager.sends, "creating a trigger must not send anything")
+}
+
+func TestPriceTriggerExecutor(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Chains.Ethereum.LargeTxThreshold = config.LargeTxThresholdConfig{Native: 1}
+	mockManager := newMockWalletManagerForTriggers()
+	aggregator := newSwapCostAggregator(t, providers.MockConfig{CustomQuoteAmount: "0.2"})
+	execute := NewPriceTriggerExecutor(mockManager, aggregator, nil, staticUSDPriceFeed{"ETH": 2500}, cfg)
+
+	send := &wallet.PriceTrigger{ID: "trig_send", Action: wallet.PriceTriggerAction{
+		Kind: wallet.TriggerActionSend, Chain: "ethereum",
+		From: "0x1111111111111111111111111111111111111111", To: "0x2222222222222222222222222222222222222222", Amount: "2",
+	}}
+	_, err := execute(context.Background(), send)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "confirm_large")
+	send.ConfirmLarge = true
+	txHash, err := execute(context.Background(), send)
+	require.NoError(t, err)
+	assert.NotEmpty(t, txHash)
+	assert.Equal(t, 1, mockManager.sends)
+
+	swap := &wallet.PriceTrigger{ID: "trig_swap", Action: wallet.PriceTriggerAction{
+		Kind: wallet.TriggerActionSwap, Chain: "ethereum",
+		From: "0x1111111111111111111111111111111111111111", Amount: "500", Token: "USDC", ToToken: "ETH", Slippage: 0.01,
+	}}
+	txHash, err = execute(context.Background(), swap)
+	require.NoError(t, err)
+	assert.NotEmpty(t, txHash)
+	assert.Equal(t, []string{swap.Action.From}, mockManager.keysFor, "swaps are signed with the wallet's key for from")
+	assert.Equal(t, []string{"500 USDC"}, mockManager.reserved, "the amount sold is held against the spend limits")
+
+	// A swap above the spend limits is refused
+	mockManager.limitErr = &wallet.SpendLimitExceededError{Chain: "ethereum", Amount: "500", Token: "USDC", Limit: wallet.SpendLimitMaxTxValue, Max: 100}
+	_, err = execute(context.Background(), swap)
+	require.ErrorIs(t, err, wallet.ErrSpendLimitExceeded)
+	mockManager.limitErr = nil
+
+	// The wallet must still be unlocked when the trigger fires
+	mockManager.unlocked = false
+	_, err = execute(context.Background(), swap)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "locked")
+}
+
+func TestListAndCancelPriceTriggerTools(t *testing.T) {
+	mockManager := newMockWalletManagerForTriggers()
+	_, err := NewCreatePriceTriggerTool(mockManager, staticUSDPriceFeed{"ETH": 2500}).GetHandler()(context.Background(), scheduleRequest("create_price_trigger", triggerArguments()))
+	require.NoError(t, err)
+
+	result, err := NewListPriceTriggersTool(mockManager).GetHandler()(context.Background(), scheduleRequest("list_price_triggers", map[string]any{}))
+	require.NoError(t, err)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "- **Count**: `1`")
+	assert.Contains(t, textContent.Text, "#### `trig_0123456789abcdef`")
+
+	result, err = NewListPriceTriggersTool(mockManager).GetHandler()(context.Background(), scheduleRequest("list_price_triggers", map[string]any{"status": "pending"}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	cancel := NewCancelPriceTriggerTool(mockManager).GetHandler()
+	result, err = cancel(context.Background(), scheduleRequest("cancel_price_trigger", map[string]any{"id": "trig_0123456789abcdef"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	textContent, ok = mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "### Price Trigger Cancelled")
+	assert.Contains(t, textContent.Text, "**Status**: `cancelled`")
+
+	result, err = cancel(context.Background(), scheduleRequest("cancel_price_trigger", map[string]any{"id": "trig_missing"}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,81 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// DeactivateStakeTool implements the MCP "deactivate_stake" tool, unstaking a
+// delegated Solana stake account.
+type DeactivateStakeTool struct {
+	manager     wallet.IWalletManager
+	broadcaster *event.EventBroadcaster
+	logger      *zap.Logger
+}
+
+// NewDeactivateStakeTool constructs a DeactivateStakeTool with the given wallet manager and event broadcaster.
+func NewDeactivateStakeTool(manager wallet.IWalletManager, broadcaster *event.EventBroadcaster, logger *zap.Logger) *DeactivateStakeTool {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &DeactivateStakeTool{manager: manager, broadcaster: broadcaster, logger: logger}
+}
+
+// GetMeta returns the MCP tool definition for "deactivate_stake".
+func (t *DeactivateStakeTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("deactivate_stake",
+		mcp.WithDescription("Unstake a delegated Solana stake account the wallet is the staker of. The stake stops earning at the end of the current epoch and cools down over the following epoch(s); withdraw it afterwards with withdraw_stake."),
+		mcp.WithString("owner",
+			mcp.Required(),
+			mcp.Description("Solana wallet address that is the stake account's staker"),
+		),
+		mcp.WithString("stake_account",
+			mcp.Required(),
+			mcp.Description("Address of the stake account to deactivate"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "deactivate_stake" tool.
+func (t *DeactivateStakeTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		owner := strings.TrimSpace(req.GetString("owner", ""))
+		stakeAccount := strings.TrimSpace(req.GetString("stake_account", ""))
+		for _, field := range []struct{ name, value string }{{"owner", owner}, {"stake_account", stakeAccount}} {
+			if field.value == "" {
+				return toolutils.FormatErrorResult(errors.MissingRequiredFieldError(field.name)), nil
+			}
+			if !isValidAddressForChain("solana", field.value) {
+				return toolutils.FormatErrorResult(errors.InvalidAddressError(field.value, "solana")), nil
+			}
+		}
+
+		if toolErr := toolutils.RequireUnlocked(t.manager, "deactivate stake"); toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
+		result, err := t.manager.DeactivateStake(ctx, owner, stakeAccount)
+		if err != nil {
+			t.logger.Error("Failed to deactivate stake",
+				zap.String("owner", owner),
+				zap.String("stake_account", stakeAccount),
+				zap.Error(err))
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("deactivate stake", err)), nil
+		}
+		if t.broadcaster != nil {
+			t.broadcaster.BroadcastStakeDeactivated(result.Signature, owner, result.StakeAccount, result.VoteAccount, result.Lamports)
+		}
+
+		return mcp.NewToolResultText(formatStakeResult("Stake Deactivated", result) +
+			"\nThe stake stops earning at the end of this epoch. Once its cooldown is over, use withdraw_stake to move the SOL back.\n"), nil
+	}
+}
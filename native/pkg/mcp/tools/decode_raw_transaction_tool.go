@@ -0,0 +1,248 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DecodeRawTransactionTool implements the MCP "decode_raw_transaction" tool, showing
+// what a transaction built outside the wallet does before it is signed or sent.
+// It needs no wallet, so it works while the wallet is locked.
+type DecodeRawTransactionTool struct {
+	chains config.ChainsConfig
+}
+
+// NewDecodeRawTransactionTool constructs a DecodeRawTransactionTool that checks EVM
+// chain IDs against cfg. A nil cfg uses the defaults.
+func NewDecodeRawTransactionTool(cfg *config.Config) *DecodeRawTransactionTool {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return &DecodeRawTransactionTool{chains: cfg.Chains}
+}
+
+// GetMeta returns the MCP tool definition for "decode_raw_transaction".
+func (t *DecodeRawTransactionTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("decode_raw_transaction",
+		mcp.WithDescription("Decode a raw transaction without signing or broadcasting it: nonce, recipient, value, gas and decoded calldata on Ethereum/BSC; fee payer, signatures, accounts and instructions on Solana"),
+		mcp.WithString("chain",
+			mcp.Required(),
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol"),
+		),
+		mcp.WithString("raw_transaction",
+			mcp.Required(),
+			mcp.Description("The transaction: RLP or EIP-2718 typed envelope as hex on Ethereum/BSC, signed or unsigned; base64 wire format on Solana"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "decode_raw_transaction" tool.
+func (t *DecodeRawTransactionTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName, err := req.RequireString("chain")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("chain")), nil
+		}
+		normalizedChain, err := toolutils.NormalizeChainName(chainName)
+		if err != nil {
+			if appErr, ok := err.(*errors.Error); ok {
+				return toolutils.FormatErrorResult(appErr), nil
+			}
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+		raw, err := req.RequireString("raw_transaction")
+		if err != nil || strings.TrimSpace(raw) == "" {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("raw_transaction")), nil
+		}
+
+		if normalizedChain == "solana" {
+			decoded, err := walletchain.DecodeSolanaRawTransaction(raw)
+			if err != nil {
+				return toolutils.FormatErrorResult(errors.ValidationError("raw_transaction", err.Error())), nil
+			}
+			return mcp.NewToolResultText(formatDecodedSolanaTransaction(decoded)), nil
+		}
+		decoded, err := walletchain.DecodeEVMRawTransaction(raw)
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.ValidationError("raw_transaction", err.Error())), nil
+		}
+		return mcp.NewToolResultText(t.formatDecodedEVMTransaction(normalizedChain, decoded)), nil
+	}
+}
+
+// expectedChainID returns the configured chain ID of an EVM chain, 0 if unknown
+func (t *DecodeRawTransactionTool) expectedChainID(chainName string) int {
+	switch chainName {
+	case "ethereum":
+		return t.chains.Ethereum.ChainID
+	case "bsc":
+		return t.chains.BSC.ChainID
+	default:
+		return 0
+	}
+}
+
+// formatDecodedEVMTransaction renders an EVM transaction with its calldata
+// interpreted the same way dApp requests are
+func (t *DecodeRawTransactionTool) formatDecodedEVMTransaction(chainName string, tx *walletchain.DecodedEVMTransaction) string {
+	native := wallet.NativeTokenSymbol(chainName)
+	var warnings []string
+
+	markdown := "### Decoded Transaction\n\n" +
+		"- **Chain**: `" + chainName + "`\n" +
+		"- **Type**: `" + tx.Type + "`\n"
+	if tx.ChainID != nil {
+		markdown += "- **Chain ID**: `" + tx.ChainID.String() + "`\n"
+		if expected := t.expectedChainID(chainName); expected != 0 && tx.ChainID.Cmp(big.NewInt(int64(expected))) != 0 {
+			warnings = append(warnings, fmt.Sprintf("chain ID %s is not %s (%d); it would be rejected or replayed elsewhere", tx.ChainID, chainName, expected))
+		}
+	} else {
+		warnings = append(warnings, "the transaction has no chain ID (pre-EIP-155) and can be replayed on any EVM chain")
+	}
+	markdown += fmt.Sprintf("- **Signed**: `%t`\n", tx.Signed)
+	if tx.Signed {
+		markdown += "- **From**: `" + tx.From + "`\n" +
+			"- **Transaction Hash**: `" + tx.Hash + "`\n"
+	}
+	markdown += fmt.Sprintf("- **Nonce**: `%d`\n", tx.Nonce)
+	if tx.To == "" {
+		markdown += "- **To**: contract deployment\n"
+	} else {
+		markdown += "- **To**: `" + tx.To + "`\n"
+	}
+	markdown += "- **Value**: `" + formatScaled(tx.Value, 18) + " " + native + "`\n" +
+		fmt.Sprintf("- **Gas Limit**: `%d`\n", tx.Gas)
+	if tx.GasPrice != nil {
+		markdown += "- **Gas Price**: `" + formatScaled(tx.GasPrice, 9) + " gwei`\n"
+	}
+	if tx.GasFeeCap != nil {
+		markdown += "- **Max Fee**: `" + formatScaled(tx.GasFeeCap, 9) + " gwei`\n" +
+			"- **Priority Fee**: `" + formatScaled(tx.GasTipCap, 9) + " gwei`\n"
+	}
+
+	data := hex.EncodeToString(tx.Data)
+	if tx.To != "" {
+		intent := wallet.DecodeTransactionIntent(chainName, tx.To, "0x"+tx.Value.Text(16), "0x"+data)
+		markdown += "- **Action**: `" + intent.Action + "`\n" +
+			"- **Summary**: " + intent.Summary + "\n" +
+			"- **Risk Level**: `" + intent.RiskLevel + "`\n"
+		if intent.Protocol != "" {
+			markdown += "- **Protocol**: " + intent.Protocol + "\n"
+		}
+		for _, flag := range intent.RiskFlags {
+			markdown += "- **Risk Flag**: " + flag + "\n"
+		}
+	}
+	if len(tx.Data) > 0 {
+		markdown += fmt.Sprintf("- **Data Size**: `%d bytes`\n", len(tx.Data))
+	}
+	if tx.To != "" && len(data) >= 8 {
+		markdown += "- **Selector**: `0x" + data[:8] + "`\n"
+		if args := data[8:]; len(args) > 0 {
+			markdown += "\n#### Arguments\n\n"
+			for i := 0; len(args) > 0; i++ {
+				n := min(64, len(args))
+				markdown += fmt.Sprintf("%d. `0x%s`\n", i, args[:n])
+				args = args[n:]
+			}
+		}
+	}
+
+	for i, warning := range warnings {
+		if i == 0 {
+			markdown += "\n"
+		}
+		markdown += "> ⚠️ " + warning + "\n"
+	}
+	markdown += "\nNothing was signed or sent.\n"
+	return markdown
+}
+
+// formatDecodedSolanaTransaction renders a Solana transaction instruction by instruction
+func formatDecodedSolanaTransaction(tx *walletchain.DecodedSolanaTransaction) string {
+	markdown := "### Decoded Transaction\n\n" +
+		"- **Chain**: `solana`\n" +
+		"- **Version**: `" + tx.Version + "`\n" +
+		"- **Fee Payer**: `" + tx.FeePayer + "`\n" +
+		"- **Recent Blockhash**: `" + tx.RecentBlockhash + "`\n" +
+		fmt.Sprintf("- **Signed**: `%t`\n", tx.Signed)
+	for i, signature := range tx.Signatures {
+		signer := ""
+		if i < len(tx.Accounts) {
+			signer = tx.Accounts[i].Address
+		}
+		if signature == "" {
+			markdown += "- **Missing Signature**: `" + signer + "`\n"
+		} else {
+			markdown += "- **Signature**: `" + signature + "` by `" + signer + "`\n"
+		}
+	}
+	if tx.AddressTableLookups > 0 {
+		markdown += fmt.Sprintf("- **Address Lookup Tables**: `%d` (their accounts are not resolved)\n", tx.AddressTableLookups)
+	}
+
+	markdown += "\n#### Accounts\n\n"
+	for i, account := range tx.Accounts {
+		var flags []string
+		if account.Signer {
+			flags = append(flags, "signer")
+		}
+		if account.Writable {
+			flags = append(flags, "writable")
+		}
+		markdown += fmt.Sprintf("%d. `%s`", i, account.Address)
+		if len(flags) > 0 {
+			markdown += " (" + strings.Join(flags, ", ") + ")"
+		}
+		markdown += "\n"
+	}
+
+	markdown += "\n#### Instructions\n"
+	for i, instruction := range tx.Instructions {
+		program := instruction.Program
+		if program == "" {
+			program = "Unknown program"
+		}
+		markdown += fmt.Sprintf("\n%d. **%s**", i, program)
+		if instruction.Type != "" {
+			markdown += " `" + instruction.Type + "`"
+		}
+		markdown += "\n   - **Program ID**: `" + instruction.ProgramID + "`\n"
+		if instruction.Summary != "" {
+			markdown += "   - **Summary**: " + instruction.Summary + "\n"
+		}
+		if len(instruction.Accounts) > 0 {
+			markdown += "   - **Accounts**: `" + strings.Join(instruction.Accounts, "`, `") + "`\n"
+		}
+		if instruction.Summary == "" && instruction.Data != "" {
+			markdown += "   - **Data**: `0x" + instruction.Data + "`\n"
+		}
+	}
+	markdown += "\nNothing was signed or sent.\n"
+	return markdown
+}
+
+// formatScaled renders value divided by 10^decimals without trailing zeros
+func formatScaled(value *big.Int, decimals int64) string {
+	if value == nil {
+		return "0"
+	}
+	scaled := new(big.Rat).SetFrac(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil))
+	text := scaled.FloatString(int(decimals))
+	if strings.Contains(text, ".") {
+		text = strings.TrimSuffix(strings.TrimRight(text, "0"), ".")
+	}
+	return text
+}
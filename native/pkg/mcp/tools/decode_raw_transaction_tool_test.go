@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeRawTransaction(t *testing.T, args map[string]any) *mcp.CallToolResult {
+	result, err := NewDecodeRawTransactionTool(nil).GetHandler()(context.Background(), scheduleRequest("decode_raw_transaction", args))
+	require.NoError(t, err)
+	return result
+}
+
+func TestDecodeRawTransactionToolEVM(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     3,
+		GasTipCap: big.NewInt(1_500_000_000),
+		GasFeeCap: big.NewInt(30_000_000_000),
+		Gas:       60000,
+		To:        &usdc,
+		Data:      common.FromHex("0xa9059cbb000000000000000000000000222222222222222222222222222222222222222200000000000000000000000000000000000000000000000000000000000f4240"),
+	})
+	require.NoError(t, err)
+	raw, err := tx.MarshalBinary()
+	require.NoError(t, err)
+
+	result := decodeRawTransaction(t, map[string]any{"chain": "eth", "raw_transaction": "0x" + hex.EncodeToString(raw)})
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Type**: `dynamic_fee`")
+	assert.Contains(t, text, "- **From**: `"+crypto.PubkeyToAddress(key.PublicKey).Hex()+"`")
+	assert.Contains(t, text, "- **Nonce**: `3`")
+	assert.Contains(t, text, "- **Max Fee**: `30 gwei`")
+	assert.Contains(t, text, "- **Priority Fee**: `1.5 gwei`")
+	assert.Contains(t, text, "- **Action**: `token_transfer`")
+	assert.Contains(t, text, "Send 1 USDC")
+	assert.Contains(t, text, "- **Selector**: `0xa9059cbb`")
+	assert.NotContains(t, text, "⚠️")
+
+	// The same transaction decoded as BSC has the wrong chain ID
+	result = decodeRawTransaction(t, map[string]any{"chain": "bsc", "raw_transaction": hex.EncodeToString(raw)})
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "chain ID 1 is not bsc (56)")
+}
+
+func TestDecodeRawTransactionToolSolana(t *testing.T) {
+	owner := solana.NewWallet().PublicKey()
+	recipient := solana.NewWallet().PublicKey()
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{system.NewTransferInstruction(250_000_000, owner, recipient).Build()},
+		solana.MustHashFromBase58("4uQeVj5tqViQh7yWWGStvkEG1Zmhx6uasJtWCJziofM"),
+		solana.TransactionPayer(owner),
+	)
+	require.NoError(t, err)
+	encoded, err := tx.ToBase64()
+	require.NoError(t, err)
+
+	result := decodeRawTransaction(t, map[string]any{"chain": "sol", "raw_transaction": encoded})
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Fee Payer**: `"+owner.String()+"`")
+	assert.Contains(t, text, "- **Signed**: `false`")
+	assert.Contains(t, text, "- **Missing Signature**: `"+owner.String()+"`")
+	assert.Contains(t, text, "**System** `Transfer`")
+	assert.Contains(t, text, "Transfer 0.25 SOL from "+owner.String()+" to "+recipient.String())
+}
+
+func TestDecodeRawTransactionToolRejectsMalformedInput(t *testing.T) {
+	result := decodeRawTransaction(t, map[string]any{"chain": "ethereum", "raw_transaction": "0xdeadbeef"})
+	assert.True(t, result.IsError)
+
+	result = decodeRawTransaction(t, map[string]any{"chain": "solana", "raw_transaction": "%%%"})
+	assert.True(t, result.IsError)
+
+	result = decodeRawTransaction(t, map[string]any{"chain": "solana"})
+	assert.True(t, result.IsError)
+}
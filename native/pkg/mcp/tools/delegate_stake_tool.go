@@ -0,0 +1,109 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// DelegateStakeTool implements the MCP "delegate_stake" tool, staking SOL
+// with a validator through a new stake account.
+type DelegateStakeTool struct {
+	manager     wallet.IWalletManager
+	broadcaster *event.EventBroadcaster
+	logger      *zap.Logger
+}
+
+// NewDelegateStakeTool constructs a DelegateStakeTool with the given wallet manager and event broadcaster.
+func NewDelegateStakeTool(manager wallet.IWalletManager, broadcaster *event.EventBroadcaster, logger *zap.Logger) *DelegateStakeTool {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &DelegateStakeTool{manager: manager, broadcaster: broadcaster, logger: logger}
+}
+
+// GetMeta returns the MCP tool definition for "delegate_stake".
+func (t *DelegateStakeTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("delegate_stake",
+		mcp.WithDescription("Stake SOL with a Solana validator: creates a stake account controlled by the wallet, funds it and delegates it to the validator's vote account in one transaction. The validator must be currently voting, and the wallet keeps the stake account's rent, the fee and the configured SOL reserve. Stake starts earning from the next epoch."),
+		mcp.WithString("owner",
+			mcp.Required(),
+			mcp.Description("Solana wallet address that funds the stake and becomes its staker and withdrawer"),
+		),
+		mcp.WithString("vote_account",
+			mcp.Required(),
+			mcp.Description("Vote account address of the validator to delegate to"),
+		),
+		mcp.WithString("amount",
+			mcp.Required(),
+			mcp.Description("SOL to stake (e.g. 2.5), or max for everything above the rent, fee and reserve"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "delegate_stake" tool.
+func (t *DelegateStakeTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		owner := strings.TrimSpace(req.GetString("owner", ""))
+		voteAccount := strings.TrimSpace(req.GetString("vote_account", ""))
+		amount := strings.TrimSpace(req.GetString("amount", ""))
+		for _, field := range []struct{ name, value string }{{"owner", owner}, {"vote_account", voteAccount}, {"amount", amount}} {
+			if field.value == "" {
+				return toolutils.FormatErrorResult(errors.MissingRequiredFieldError(field.name)), nil
+			}
+		}
+		for _, address := range []string{owner, voteAccount} {
+			if !isValidAddressForChain("solana", address) {
+				return toolutils.FormatErrorResult(errors.InvalidAddressError(address, "solana")), nil
+			}
+		}
+
+		if toolErr := toolutils.RequireUnlocked(t.manager, "delegate stake"); toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
+		// Not retried: a second attempt would create a second stake account
+		result, err := t.manager.DelegateStake(ctx, owner, voteAccount, amount)
+		if err != nil {
+			t.logger.Error("Failed to delegate stake",
+				zap.String("owner", owner),
+				zap.String("vote_account", voteAccount),
+				zap.Error(err))
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("delegate stake", err)), nil
+		}
+		if t.broadcaster != nil {
+			t.broadcaster.BroadcastStakeDelegated(result.Signature, owner, result.StakeAccount, result.VoteAccount, result.Lamports)
+		}
+
+		return mcp.NewToolResultText(formatStakeResult("Stake Delegated", result) +
+			"\nThe stake activates at the next epoch boundary. Use deactivate_stake with this stake account to unstake.\n"), nil
+	}
+}
+
+// formatStakeResult renders the outcome of a staking transaction
+func formatStakeResult(title string, result *walletchain.StakeResult) string {
+	markdown := "### " + title + " ✅\n\n" +
+		"- **Transaction Hash**: `" + result.Signature + "`\n" +
+		"- **Stake Account**: `" + result.StakeAccount + "`\n"
+	if result.VoteAccount != "" {
+		markdown += "- **Vote Account**: `" + result.VoteAccount + "`\n"
+	}
+	if result.Recipient != "" {
+		markdown += "- **To**: `" + result.Recipient + "`\n"
+	}
+	markdown += fmt.Sprintf("- **Amount**: `%s SOL` (%d lamports)\n", result.Amount, result.Lamports)
+	if result.Channel != "" {
+		markdown += "- **Broadcast Via**: `" + result.Channel + "`\n"
+	}
+	return markdown
+}
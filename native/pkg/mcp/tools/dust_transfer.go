@@ -0,0 +1,40 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// dustGuard rejects sends below the per-chain min_transfer, or not worth their
+// fee, unless the caller passes allow_dust=true
+type dustGuard struct {
+	chains    config.ChainsConfig
+	priceFeed wallet.PriceFeed
+}
+
+// allowDustParam is the tool parameter that lets a dust send through
+const allowDustParam = "allow_dust"
+
+// allowDustDescription documents allowDustParam in tool schemas
+const allowDustDescription = "Send even if the amount is below the chain's configured min_transfer or does not exceed the estimated fee (default: false)"
+
+// check compares amount against the chain's minimum transfer, using the fee of
+// the estimated gas when no minimum is configured. It returns an error to
+// report instead of sending when the amount is dust.
+func (g *dustGuard) check(ctx context.Context, chainName, token, amount string, gasLimit float64, gasPrice string) *errors.Error {
+	fee := ""
+	if gasLimit > 0 && gasPrice != "" {
+		fee, _ = walletchain.TransferFee(chainName, uint64(gasLimit), gasPrice)
+	}
+	check := wallet.CheckDustTransfer(ctx, g.chains.MinTransfer(chainName), g.priceFeed, chainName, token, amount, fee)
+	if !check.Dust {
+		return nil
+	}
+	return errors.ValidationError("amount", check.Reason).
+		WithSuggestion("Send a larger amount, or call again with `" + allowDustParam + "=true` to send it anyway")
+}
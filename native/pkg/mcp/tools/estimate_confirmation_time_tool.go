@@ -0,0 +1,142 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// EstimateConfirmationTimeTool implements the MCP "estimate_confirmation_time"
+// tool, estimating how long a proposed fee takes to be included from the fees
+// paid in recent blocks. It only reads the chain, so it works while the wallet
+// is locked.
+type EstimateConfirmationTimeTool struct {
+	manager wallet.IWalletManager
+	logger  *zap.Logger
+}
+
+// NewEstimateConfirmationTimeTool constructs an EstimateConfirmationTimeTool with the given wallet manager.
+func NewEstimateConfirmationTimeTool(manager wallet.IWalletManager, logger *zap.Logger) *EstimateConfirmationTimeTool {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &EstimateConfirmationTimeTool{manager: manager, logger: logger}
+}
+
+// GetMeta returns the MCP tool definition for "estimate_confirmation_time".
+func (t *EstimateConfirmationTimeTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("estimate_confirmation_time",
+		mcp.WithDescription("Estimate how long a proposed fee takes to be included, from the fees paid in recent blocks (eth_feeHistory on EVM chains, recent prioritization fees and measured slot times on Solana). Returns a range such as likely within 1-2 blocks (12s-24s), the share of recent blocks the fee would have made it into and the median fee paid, to trade off fee against speed before sending."),
+		mcp.WithString("chain",
+			mcp.Required(),
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol"),
+		),
+		mcp.WithNumber("priority_fee",
+			mcp.Required(),
+			mcp.Description("Proposed priority fee: the tip in gwei on EVM chains, the compute unit price in micro-lamports on Solana"),
+		),
+		mcp.WithNumber("max_fee",
+			mcp.Description("EVM only: proposed max fee per gas in gwei (defaults to twice the next base fee plus the priority fee)"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "estimate_confirmation_time" tool.
+func (t *EstimateConfirmationTimeTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName, err := req.RequireString("chain")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("chain")), nil
+		}
+		priorityFee, err := req.RequireFloat("priority_fee")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("priority_fee")), nil
+		}
+		maxFee := req.GetFloat("max_fee", 0)
+
+		normalizedChain, err := toolutils.NormalizeChainName(chainName)
+		if err != nil {
+			if appErr, ok := err.(*errors.Error); ok {
+				return toolutils.FormatErrorResult(appErr), nil
+			}
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+		if priorityFee < 0 {
+			return toolutils.FormatErrorResult(errors.ValidationError("priority_fee", "must not be negative")), nil
+		}
+		if maxFee < 0 {
+			return toolutils.FormatErrorResult(errors.ValidationError("max_fee", "must not be negative")), nil
+		}
+		if maxFee > 0 && normalizedChain == "solana" {
+			return toolutils.FormatErrorResult(errors.ValidationError("max_fee", "only applies to EVM chains")), nil
+		}
+		if maxFee > 0 && maxFee < priorityFee {
+			return toolutils.FormatErrorResult(errors.ValidationError("max_fee", "must not be below priority_fee")), nil
+		}
+
+		fee := walletchain.ProposedFee{PriorityFee: priorityFee, MaxFee: maxFee}
+		estimate, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (*walletchain.ConfirmationTimeEstimate, error) {
+			return t.manager.EstimateConfirmationTime(attemptCtx, normalizedChain, fee)
+		})
+		if err != nil {
+			t.logger.Debug("Confirmation time estimate failed", zap.String("chain", normalizedChain), zap.Error(err))
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("estimate confirmation time", err)), nil
+		}
+		return mcp.NewToolResultText(formatConfirmationTimeEstimate(estimate)), nil
+	}
+}
+
+// formatConfirmationTimeEstimate renders an estimate as markdown
+func formatConfirmationTimeEstimate(estimate *walletchain.ConfirmationTimeEstimate) string {
+	markdown := "### Confirmation Time Estimate\n\n" +
+		"- **Chain**: `" + estimate.Chain + "`\n" +
+		"- **Priority Fee**: `" + formatFeeRate(estimate.PriorityFee) + " " + estimate.FeeUnit + "`\n"
+	if estimate.MaxFee > 0 {
+		markdown += "- **Max Fee**: `" + formatFeeRate(estimate.MaxFee) + " " + estimate.FeeUnit + "`\n"
+	}
+	markdown += "- **Estimate**: " + confirmationTimeSummary(estimate) + "\n" +
+		fmt.Sprintf("- **Inclusion Rate**: `%.0f%%` of the last `%d` blocks\n", estimate.InclusionRate*100, estimate.SampledBlocks) +
+		"- **Median Priority Fee**: `" + formatFeeRate(estimate.MedianPriorityFee) + " " + estimate.FeeUnit + "`\n"
+	if estimate.NextBaseFee > 0 {
+		markdown += "- **Next Base Fee**: `" + formatFeeRate(estimate.NextBaseFee) + " gwei`\n"
+	}
+	markdown += "- **Block Time**: `" + formatFinalityDuration(estimate.BlockTime) + "`\n"
+	if estimate.Reason != "" {
+		markdown += "\n> " + estimate.Reason + ".\n"
+	}
+	markdown += "\n> Estimates assume recent blocks are representative; fees move with demand.\n"
+	return markdown
+}
+
+// confirmationTimeSummary renders the block and time range, e.g.
+// likely within 1-2 blocks (12s-24s)
+func confirmationTimeSummary(estimate *walletchain.ConfirmationTimeEstimate) string {
+	if estimate.MaxBlocks == 0 {
+		return estimate.Likelihood + " to be included until fees fall"
+	}
+	blocks := strconv.Itoa(estimate.MinBlocks)
+	times := formatFinalityDuration(estimate.MinTime)
+	if estimate.MaxBlocks != estimate.MinBlocks {
+		blocks += "-" + strconv.Itoa(estimate.MaxBlocks)
+		times += "-" + formatFinalityDuration(estimate.MaxTime)
+	}
+	unit := "blocks"
+	if estimate.MaxBlocks == 1 {
+		unit = "block"
+	}
+	return fmt.Sprintf("%s within `%s` %s (`%s`)", estimate.Likelihood, blocks, unit, times)
+}
+
+// formatFeeRate renders a fee rate without trailing zeros
+func formatFeeRate(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateConfirmationTimeTool(t *testing.T) {
+	manager := &wallet.MockWalletManager{}
+	manager.On("EstimateConfirmationTime", mock.Anything, "ethereum", walletchain.ProposedFee{PriorityFee: 2}).Return(&walletchain.ConfirmationTimeEstimate{
+		Chain:             "ethereum",
+		PriorityFee:       2,
+		MaxFee:            26,
+		FeeUnit:           "gwei",
+		NextBaseFee:       12,
+		MedianPriorityFee: 1.5,
+		InclusionRate:     0.75,
+		SampledBlocks:     20,
+		BlockTime:         12 * time.Second,
+		Likelihood:        walletchain.InclusionLikely,
+		MinBlocks:         1,
+		MaxBlocks:         2,
+		MinTime:           12 * time.Second,
+		MaxTime:           24 * time.Second,
+	}, nil)
+	manager.On("EstimateConfirmationTime", mock.Anything, "solana", walletchain.ProposedFee{PriorityFee: 1}).Return(&walletchain.ConfirmationTimeEstimate{
+		Chain:         "solana",
+		PriorityFee:   1,
+		FeeUnit:       "micro-lamports/CU",
+		SampledBlocks: 150,
+		BlockTime:     400 * time.Millisecond,
+		Likelihood:    walletchain.InclusionUnlikely,
+		Reason:        "every one of the last 150 slots only took higher priority fees",
+	}, nil)
+	manager.On("EstimateConfirmationTime", mock.Anything, "bsc", mock.Anything).Return(nil, fmt.Errorf("failed to get fee history: connection refused"))
+	handler := NewEstimateConfirmationTimeTool(manager, nil).GetHandler()
+
+	result, err := handler(context.Background(), scheduleRequest("estimate_confirmation_time", map[string]any{"chain": "eth", "priority_fee": 2.0}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "### Confirmation Time Estimate")
+	assert.Contains(t, text, "- **Max Fee**: `26 gwei`")
+	assert.Contains(t, text, "- **Estimate**: likely within `1-2` blocks (`12s-24s`)")
+	assert.Contains(t, text, "- **Inclusion Rate**: `75%` of the last `20` blocks")
+	assert.Contains(t, text, "- **Next Base Fee**: `12 gwei`")
+
+	result, err = handler(context.Background(), scheduleRequest("estimate_confirmation_time", map[string]any{"chain": "sol", "priority_fee": 1.0}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Estimate**: unlikely to be included until fees fall")
+	assert.Contains(t, text, "> every one of the last 150 slots only took higher priority fees.")
+	assert.NotContains(t, text, "Next Base Fee")
+
+	result, err = handler(context.Background(), scheduleRequest("estimate_confirmation_time", map[string]any{"chain": "bsc", "priority_fee": 1.0}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	calls := len(manager.Calls)
+	for _, args := range []map[string]any{
+		{"chain": "ethereum"},
+		{"chain": "ethereum", "priority_fee": -1.0},
+		{"chain": "ethereum", "priority_fee": 3.0, "max_fee": 2.0},
+		{"chain": "solana", "priority_fee": 3.0, "max_fee": 20.0},
+		{"chain": "dogecoin", "priority_fee": 1.0},
+	} {
+		result, err := handler(context.Background(), scheduleRequest("estimate_confirmation_time", args))
+		require.NoError(t, err)
+		assert.True(t, result.IsError, "expected %v to be rejected", args)
+	}
+	assert.Len(t, manager.Calls, calls, "invalid arguments are rejected before estimating")
+}
@@ -3,9 +3,12 @@ package tools
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"strings"
 
 	"github.com/algonius/algonius-wallet/native/pkg/errors"
 	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
 	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -14,6 +17,7 @@ import (
 // EstimateGasTool provides unified gas estimation across supported chains.
 type EstimateGasTool struct {
 	getChainInterface func(chainName string) (chain.IChain, error)
+	priceFeed         wallet.PriceFeed
 }
 
 // NewEstimateGasTool creates a new EstimateGasTool.
@@ -27,10 +31,18 @@ func NewEstimateGasTool(factory *chain.ChainFactory) *EstimateGasTool {
 	return tool
 }
 
+// NewEstimateGasToolWithPriceFeed creates an EstimateGasTool that also values
+// the estimated fee in USD using priceFeed.
+func NewEstimateGasToolWithPriceFeed(factory *chain.ChainFactory, priceFeed wallet.PriceFeed) *EstimateGasTool {
+	tool := NewEstimateGasTool(factory)
+	tool.priceFeed = priceFeed
+	return tool
+}
+
 // GetMeta returns MCP metadata for estimate_gas.
 func (t *EstimateGasTool) GetMeta() mcp.Tool {
 	return mcp.NewTool("estimate_gas",
-		mcp.WithDescription("Estimate gas limit and gas price for a transaction"),
+		mcp.WithDescription("Estimate gas limit, gas price and the resulting fee (native amount, smallest unit and USD when priced) for a transaction. Solana token transfers also list the rent for creating the recipient's token account when it does not exist yet"),
 		mcp.WithString("chain",
 			mcp.Required(),
 			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol"),
@@ -90,11 +102,23 @@ func (t *EstimateGasTool) GetHandler() server.ToolHandlerFunc {
 		estimatedGas, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (struct {
 			gasLimit uint64
 			gasPrice string
+			fees     *chain.GasEstimate
 		}, error) {
+			// Chains pricing sends from the network also report EIP-1559 fees
+			if estimator, ok := chainInterface.(chain.GasFeeEstimator); ok {
+				if fees, feeErr := estimator.EstimateGasFees(attemptCtx, from, to, amount, token); feeErr == nil {
+					return struct {
+						gasLimit uint64
+						gasPrice string
+						fees     *chain.GasEstimate
+					}{gasLimit: fees.GasLimit, gasPrice: fees.GasPrice(), fees: fees}, nil
+				}
+			}
 			limit, price, estimateErr := chainInterface.EstimateGas(attemptCtx, from, to, amount, token)
 			return struct {
 				gasLimit uint64
 				gasPrice string
+				fees     *chain.GasEstimate
 			}{gasLimit: limit, gasPrice: price}, estimateErr
 		})
 		if err != nil {
@@ -106,7 +130,49 @@ func (t *EstimateGasTool) GetHandler() server.ToolHandlerFunc {
 		if token != "" {
 			markdown += fmt.Sprintf("- **Token**: `%s`\n", token)
 		}
+		if fees := estimatedGas.fees; fees != nil && fees.MaxFeeGwei > 0 {
+			markdown += fmt.Sprintf("- **Gas Strategy**: `%s`\n- **Base Fee**: `%g gwei`\n- **Max Priority Fee**: `%g gwei`\n- **Max Fee**: `%g gwei`\n",
+				fees.Strategy, fees.BaseFeeGwei, fees.PriorityFeeGwei, fees.MaxFeeGwei)
+		}
+		if fee, err := chain.EstimateFee(normalizedChain, estimatedGas.gasLimit, estimatedGas.gasPrice); err == nil {
+			wallet.PriceFee(ctx, t.priceFeed, fee)
+			markdown += formatFee("Estimated Fee", fee)
+		}
+		markdown += formatAccountCreationCost(ctx, chainInterface, normalizedChain, to, token, estimatedGas.gasLimit, estimatedGas.gasPrice)
 
 		return mcp.NewToolResultText(markdown), nil
 	}
 }
+
+// formatAccountCreationCost lists the rent for creating the recipient's token
+// account as its own cost, next to the network fee, on chains that need one.
+// Nothing is added when the chain has no such cost or it cannot be checked.
+func formatAccountCreationCost(ctx context.Context, chainInterface chain.IChain, chainName, to, token string, gasLimit uint64, gasPrice string) string {
+	estimator, ok := chainInterface.(chain.AccountCreationEstimator)
+	if !ok || token == "" {
+		return ""
+	}
+	cost, err := estimator.EstimateAccountCreation(ctx, to, token)
+	if err != nil {
+		return ""
+	}
+	fee, err := chain.TransferFee(chainName, gasLimit, gasPrice)
+	if err != nil {
+		return ""
+	}
+
+	native := wallet.NativeTokenSymbol(chainName)
+	markdown := "\n#### Estimated Cost\n\n"
+	markdown += fmt.Sprintf("- **Network Fee**: `%s %s`\n", fee, native)
+	if cost.Required {
+		markdown += fmt.Sprintf("- **Token Account Creation**: `%s %s` (rent for the recipient's new token account `%s`)\n", cost.Rent, native, cost.Account)
+	} else {
+		markdown += fmt.Sprintf("- **Token Account Creation**: `0 %s` (recipient token account already exists)\n", native)
+	}
+	total, _ := new(big.Rat).SetString(fee)
+	if rent, ok := new(big.Rat).SetString(cost.Rent); ok && total != nil {
+		total.Add(total, rent)
+		markdown += fmt.Sprintf("- **Total**: `%s %s`\n", strings.TrimSuffix(strings.TrimRight(total.FloatString(18), "0"), "."), native)
+	}
+	return markdown
+}
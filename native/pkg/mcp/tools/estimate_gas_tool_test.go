@@ -79,6 +79,63 @@ func TestEstimateGasToolHandlerSuccess(t *testing.T) {
 	assert.Contains(t, textContent.Text, "**Chain**: `ethereum`")
 	assert.Contains(t, textContent.Text, "**Gas Limit**: `52000`")
 	assert.Contains(t, textContent.Text, "**Gas Price**: `15`")
+	assert.Contains(t, textContent.Text, "- **Estimated Fee**: `0.00078 ETH`")
+	assert.Contains(t, textContent.Text, "- **Estimated Fee (wei)**: `780000000000000`")
+	assert.NotContains(t, textContent.Text, "(USD)", "no price feed configured")
+}
+
+func TestEstimateGasToolHandlerFeeInUSD(t *testing.T) {
+	tool := NewEstimateGasToolWithPriceFeed(nil, staticUSDPriceFeed{"BNB": 600})
+	tool.getChainInterface = func(chainName string) (chain.IChain, error) {
+		return &mockChainForEstimateGas{}, nil
+	}
+
+	result, err := tool.GetHandler()(context.Background(), scheduleRequest("estimate_gas", map[string]any{
+		"chain":  "bsc",
+		"from":   "0x1111111111111111111111111111111111111111",
+		"to":     "0x2222222222222222222222222222222222222222",
+		"amount": "1",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Estimated Fee**: `0.00078 BNB`")
+	assert.Contains(t, text, "- **Estimated Fee (wei)**: `780000000000000`")
+	assert.Contains(t, text, "- **Estimated Fee (USD)**: `~$0.47`")
+}
+
+// mockFeeMarketChainForEstimateGas prices sends with EIP-1559 fees
+type mockFeeMarketChainForEstimateGas struct {
+	mockChainForEstimateGas
+}
+
+func (m *mockFeeMarketChainForEstimateGas) EstimateGasFees(ctx context.Context, from, to, amount, token string) (*chain.GasEstimate, error) {
+	return &chain.GasEstimate{GasLimit: 21000, GasPriceGwei: 15, Strategy: "fast", BaseFeeGwei: 12.5, PriorityFeeGwei: 3, MaxFeeGwei: 21.75}, nil
+}
+
+func TestEstimateGasToolHandlerFeeMarket(t *testing.T) {
+	tool := NewEstimateGasTool(nil)
+	tool.getChainInterface = func(chainName string) (chain.IChain, error) {
+		return &mockFeeMarketChainForEstimateGas{}, nil
+	}
+
+	result, err := tool.GetHandler()(context.Background(), scheduleRequest("estimate_gas", map[string]any{
+		"chain":  "ethereum",
+		"from":   "0x1111111111111111111111111111111111111111",
+		"to":     "0x2222222222222222222222222222222222222222",
+		"amount": "1",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Gas Limit**: `21000`")
+	assert.Contains(t, text, "- **Gas Price**: `15`")
+	assert.Contains(t, text, "- **Gas Strategy**: `fast`")
+	assert.Contains(t, text, "- **Base Fee**: `12.5 gwei`")
+	assert.Contains(t, text, "- **Max Priority Fee**: `3 gwei`")
+	assert.Contains(t, text, "- **Max Fee**: `21.75 gwei`")
 }
 
 func TestEstimateGasToolHandlerMissingAmount(t *testing.T) {
@@ -126,3 +183,55 @@ func TestEstimateGasToolHandlerEstimateError(t *testing.T) {
 	require.NotNil(t, result)
 	assert.True(t, result.IsError)
 }
+
+// mockSolanaChainForEstimateGas reports whether the recipient needs a token account
+type mockSolanaChainForEstimateGas struct {
+	mockChainForEstimateGas
+	cost *chain.AccountCreationCost
+}
+
+func (m *mockSolanaChainForEstimateGas) EstimateGas(ctx context.Context, from, to, amount, token string) (uint64, string, error) {
+	return 5000, "1", nil
+}
+
+func (m *mockSolanaChainForEstimateGas) EstimateAccountCreation(ctx context.Context, recipient, token string) (*chain.AccountCreationCost, error) {
+	return m.cost, nil
+}
+
+func TestEstimateGasToolHandlerTokenAccountCreation(t *testing.T) {
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "estimate_gas",
+			Arguments: map[string]any{
+				"chain":  "solana",
+				"from":   "9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin",
+				"to":     "4Nd1mBQtrMJVYVfKf2PJy9NZUZdTAsp7D4xWLs4gDB4T",
+				"amount": "10",
+				"token":  "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+			},
+		},
+	}
+
+	tool := NewEstimateGasTool(nil)
+	tool.getChainInterface = func(chainName string) (chain.IChain, error) {
+		return &mockSolanaChainForEstimateGas{cost: &chain.AccountCreationCost{
+			Required: true, Account: "ATA111", RentLamports: 2039280, Rent: "0.00203928",
+		}}, nil
+	}
+	result, err := tool.GetHandler()(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Network Fee**: `0.000005000005 SOL`")
+	assert.Contains(t, text, "- **Token Account Creation**: `0.00203928 SOL` (rent for the recipient's new token account `ATA111`)")
+	assert.Contains(t, text, "- **Total**: `0.002044280005 SOL`")
+
+	tool.getChainInterface = func(chainName string) (chain.IChain, error) {
+		return &mockSolanaChainForEstimateGas{cost: &chain.AccountCreationCost{Account: "ATA111", Rent: "0"}}, nil
+	}
+	result, err = tool.GetHandler()(context.Background(), req)
+	require.NoError(t, err)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Token Account Creation**: `0 SOL` (recipient token account already exists)")
+	assert.Contains(t, text, "- **Total**: `0.000005000005 SOL`")
+}
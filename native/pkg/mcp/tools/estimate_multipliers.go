@@ -0,0 +1,139 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Per-call overrides of the configured estimate multipliers
+const (
+	gasLimitMultiplierParam    = "gas_limit_multiplier"
+	feeMultiplierParam         = "fee_multiplier"
+	computeUnitMultiplierParam = "compute_unit_multiplier"
+	priorityFeeMultiplierParam = "priority_fee_multiplier"
+)
+
+// estimateMultipliers are the multipliers applied to one send's estimates and
+// where they came from
+type estimateMultipliers struct {
+	config.EstimateMultipliers
+	solana          bool
+	limitOverridden bool
+	feeOverridden   bool
+}
+
+// withEstimateMultiplierOptions declares the multiplier overrides on a tool
+func withEstimateMultiplierOptions() mcp.ToolOption {
+	bounds := fmt.Sprintf("between 1 and %g", config.MaxEstimateMultiplier)
+	options := []mcp.ToolOption{
+		mcp.WithNumber(gasLimitMultiplierParam,
+			mcp.Description("EVM only: multiplier applied to the estimated gas limit, "+bounds+" (optional, defaults to the chain's gas_limit_multiplier)"),
+		),
+		mcp.WithNumber(feeMultiplierParam,
+			mcp.Description("EVM only: multiplier applied to the estimated fee, "+bounds+" (optional, defaults to the chain's fee_multiplier)"),
+		),
+		mcp.WithNumber(computeUnitMultiplierParam,
+			mcp.Description("Solana only: multiplier applied to the estimated compute units, "+bounds+" (optional, defaults to compute_unit_multiplier)"),
+		),
+		mcp.WithNumber(priorityFeeMultiplierParam,
+			mcp.Description("Solana only: multiplier applied to the estimated priority fee, "+bounds+" (optional, defaults to priority_fee_multiplier)"),
+		),
+	}
+	return func(tool *mcp.Tool) {
+		for _, option := range options {
+			option(tool)
+		}
+	}
+}
+
+// resolveEstimateMultipliers returns the configured multipliers of chainName
+// with the request's overrides applied. Overrides meant for the other chain
+// family are rejected rather than silently ignored.
+func resolveEstimateMultipliers(req mcp.CallToolRequest, chains config.ChainsConfig, chainName string) (estimateMultipliers, *errors.Error) {
+	multipliers := estimateMultipliers{
+		EstimateMultipliers: chains.EstimateMultipliers(chainName),
+		solana:              chainName == "solana",
+	}
+	limitParam, feeParam := gasLimitMultiplierParam, feeMultiplierParam
+	otherParams := []string{computeUnitMultiplierParam, priorityFeeMultiplierParam}
+	if multipliers.solana {
+		limitParam, feeParam = computeUnitMultiplierParam, priorityFeeMultiplierParam
+		otherParams = []string{gasLimitMultiplierParam, feeMultiplierParam}
+	}
+
+	args := req.GetArguments()
+	for _, param := range otherParams {
+		if _, ok := args[param]; ok {
+			if multipliers.solana {
+				return multipliers, errors.ValidationError(param, "gas limit and fee multipliers are only supported on EVM chains; use compute_unit_multiplier and priority_fee_multiplier on Solana")
+			}
+			return multipliers, errors.ValidationError(param, "compute unit and priority fee multipliers are only supported on Solana")
+		}
+	}
+	for _, override := range []struct {
+		param      string
+		value      *float64
+		overridden *bool
+	}{
+		{limitParam, &multipliers.Limit, &multipliers.limitOverridden},
+		{feeParam, &multipliers.Fee, &multipliers.feeOverridden},
+	} {
+		if _, ok := args[override.param]; !ok {
+			continue
+		}
+		value := req.GetFloat(override.param, 0)
+		if err := config.CheckEstimateMultiplier(value); err != nil {
+			return multipliers, errors.ValidationError(override.param, err.Error())
+		}
+		*override.value = value
+		*override.overridden = true
+	}
+	return multipliers, nil
+}
+
+// scaleGasLimit applies a multiplier to an estimated gas limit, rounding up so
+// the margin is never lost
+func scaleGasLimit(gasLimit, multiplier float64) float64 {
+	return math.Ceil(gasLimit * multiplier)
+}
+
+// scaleGasPrice applies a multiplier to an estimated gas price. Prices that do
+// not parse are returned as they are.
+func scaleGasPrice(gasPrice string, multiplier float64) string {
+	if multiplier == 1 {
+		return gasPrice
+	}
+	price, err := strconv.ParseFloat(gasPrice, 64)
+	if err != nil {
+		return gasPrice
+	}
+	return formatGwei(math.Round(price*multiplier*1e4) / 1e4)
+}
+
+// formatEstimateMultipliersMarkdown reports the multipliers applied to a send.
+// A value the caller fixed explicitly is sent as given, so it counts as 1x.
+func formatEstimateMultipliersMarkdown(multipliers estimateMultipliers, limitApplied, feeApplied bool) string {
+	limitLabel, feeLabel := "gas limit", "fee"
+	if multipliers.solana {
+		limitLabel, feeLabel = "compute units", "priority fee"
+	}
+	describe := func(label string, value float64, applied, overridden bool) string {
+		if !applied {
+			return label + " 1x (explicit)"
+		}
+		text := fmt.Sprintf("%s %gx", label, value)
+		if overridden {
+			text += " (override)"
+		}
+		return text
+	}
+	return "- **Estimate Multipliers**: `" +
+		describe(limitLabel, multipliers.Limit, limitApplied, multipliers.limitOverridden) + ", " +
+		describe(feeLabel, multipliers.Fee, feeApplied, multipliers.feeOverridden) + "`\n"
+}
@@ -0,0 +1,336 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// defaultSwapSlippage matches the swap_tokens default of 0.5%
+	defaultSwapSlippage = 0.005
+	// solanaSignatureFeeLamports is the base fee of a single-signature transaction
+	solanaSignatureFeeLamports = 5000
+	// solanaSwapComputeUnits is assumed for swaps whose quote carries no compute estimate
+	solanaSwapComputeUnits = 200000
+	// solanaPriorityFeeMicroLamports is the compute unit price the wallet pays by default
+	solanaPriorityFeeMicroLamports = 1
+)
+
+// swapChainIDs maps normalized chain names to the chain IDs DEX providers use
+var swapChainIDs = map[string]string{
+	"ethereum": "1",
+	"bsc":      "56",
+	"solana":   "501",
+}
+
+// EstimateSwapCostTool implements the MCP "estimate_swap_cost" tool. It combines
+// the best quote, its fees, the network fee and the worst case output at the
+// maximum slippage into one all-in cost, without executing anything.
+type EstimateSwapCostTool struct {
+	aggregator dex.IDEXAggregator
+	priceFeed  wallet.PriceFeed
+}
+
+// NewEstimateSwapCostTool constructs an EstimateSwapCostTool. Without a price
+// feed costs are reported in token units only.
+func NewEstimateSwapCostTool(aggregator dex.IDEXAggregator, priceFeed wallet.PriceFeed) *EstimateSwapCostTool {
+	return &EstimateSwapCostTool{aggregator: aggregator, priceFeed: priceFeed}
+}
+
+// GetMeta returns the MCP tool definition for "estimate_swap_cost".
+func (t *EstimateSwapCostTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("estimate_swap_cost",
+		mcp.WithDescription("Estimate the all-in cost of a token swap without executing it: the best quote, protocol and aggregator fees, the network fee (gas on EVM chains, signature and priority fee on Solana) and the worst-case output at the maximum slippage, in token and USD terms"),
+		mcp.WithString("chain",
+			mcp.Required(),
+			mcp.Description("Blockchain to swap on ('ethereum', 'bsc' or 'solana')"),
+		),
+		mcp.WithString("from_token",
+			mcp.Required(),
+			mcp.Description("Token to swap from (address or symbol)"),
+		),
+		mcp.WithString("to_token",
+			mcp.Required(),
+			mcp.Description("Token to swap to (address or symbol)"),
+		),
+		mcp.WithString("amount",
+			mcp.Required(),
+			mcp.Description("Amount of from_token to swap"),
+		),
+		mcp.WithString("from_address",
+			mcp.Required(),
+			mcp.Description("Address that would initiate the swap"),
+		),
+		mcp.WithNumber("slippage",
+			mcp.Description("Maximum acceptable slippage (e.g., 0.005 for 0.5%, the default)"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "estimate_swap_cost" tool.
+func (t *EstimateSwapCostTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName, err := toolutils.NormalizeChainName(req.GetString("chain", ""))
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+		chainID, ok := swapChainIDs[chainName]
+		if !ok {
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", fmt.Sprintf("swaps are not supported on %s", chainName))), nil
+		}
+
+		params := dex.SwapParams{
+			FromToken:   req.GetString("from_token", ""),
+			ToToken:     req.GetString("to_token", ""),
+			Amount:      req.GetString("amount", ""),
+			Slippage:    req.GetFloat("slippage", defaultSwapSlippage),
+			FromAddress: req.GetString("from_address", ""),
+			ChainID:     chainID,
+			// Rank by output after network fees, since that is what is being estimated
+			CompareNetOfFees: true,
+		}
+		params.ToAddress = params.FromAddress
+		if err := params.Validate(); err != nil {
+			return toolutils.FormatErrorResult(errors.ValidationError("params", err.Error())), nil
+		}
+		amountIn, err := strconv.ParseFloat(params.Amount, 64)
+		if err != nil || amountIn <= 0 {
+			return toolutils.FormatErrorResult(errors.ValidationError("amount", "amount must be a positive number")), nil
+		}
+
+		if t.aggregator == nil {
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("get swap quote", fmt.Errorf("%w for chain %s: no DEX aggregator configured", dex.ErrNoProvidersAvailable, chainName))), nil
+		}
+		quote, err := t.aggregator.GetBestQuote(ctx, params)
+		if err != nil {
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("get swap quote", err)), nil
+		}
+
+		estimate, err := t.estimate(ctx, chainName, params, quote)
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.InternalError("estimate swap cost", err)), nil
+		}
+		return mcp.NewToolResultText(formatSwapCostMarkdown(chainName, params, quote, estimate)), nil
+	}
+}
+
+// swapCostEstimate is the all-in cost of a quote. USD fields are only valid
+// when USDKnown is set.
+type swapCostEstimate struct {
+	AmountIn       float64
+	ExpectedOut    float64
+	MinimumOut     float64
+	NetworkFee     float64 // in the native token
+	NetworkFeeNote string  // how NetworkFee was obtained
+	// PriorityFeeLamports is the Solana priority fee included in NetworkFee
+	PriorityFeeLamports float64
+
+	USDKnown       bool
+	USDStale       bool
+	USDUnavailable string
+	SpendUSD       float64 // amount in plus network fee
+	ExpectedOutUSD float64
+	MinimumOutUSD  float64
+	FeesUSD        float64 // protocol, aggregator and network fees
+}
+
+// ExpectedCostUSD is what the swap costs at the quoted output
+func (e *swapCostEstimate) ExpectedCostUSD() float64 {
+	return e.SpendUSD - e.ExpectedOutUSD
+}
+
+// WorstCaseCostUSD is what the swap costs if the output hits the slippage limit
+func (e *swapCostEstimate) WorstCaseCostUSD() float64 {
+	return e.SpendUSD - e.MinimumOutUSD
+}
+
+// estimate works out the cost of quote in token units and, with a price feed, USD
+func (t *EstimateSwapCostTool) estimate(ctx context.Context, chainName string, params dex.SwapParams, quote *dex.SwapQuote) (*swapCostEstimate, error) {
+	expectedOut, err := strconv.ParseFloat(quote.ToAmount, 64)
+	if err != nil {
+		return nil, fmt.Errorf("quote from %s has an invalid output amount %q", quote.Provider, quote.ToAmount)
+	}
+	amountIn, _ := strconv.ParseFloat(params.Amount, 64)
+	estimate := &swapCostEstimate{
+		AmountIn:    amountIn,
+		ExpectedOut: expectedOut,
+		MinimumOut:  expectedOut * (1 - params.Slippage),
+	}
+	estimate.NetworkFee, estimate.PriorityFeeLamports, estimate.NetworkFeeNote = swapNetworkFee(chainName, quote)
+
+	if t.priceFeed == nil {
+		return estimate, nil
+	}
+	native := wallet.NativeTokenSymbol(chainName)
+	var protocolFees float64
+	if quote.Fees != nil {
+		protocolFees = parseFeeAmount(quote.Fees.ProtocolFee) + parseFeeAmount(quote.Fees.AggregatorFee)
+	}
+	var networkFeeUSD, protocolFeesUSD float64
+	for _, v := range []struct {
+		token  string
+		amount float64
+		target *float64
+	}{
+		{params.FromToken, amountIn, &estimate.SpendUSD},
+		{native, estimate.NetworkFee, &networkFeeUSD},
+		{params.ToToken, expectedOut, &estimate.ExpectedOutUSD},
+		{params.ToToken, estimate.MinimumOut, &estimate.MinimumOutUSD},
+		{params.ToToken, protocolFees, &protocolFeesUSD},
+	} {
+		if v.amount == 0 {
+			continue
+		}
+		value, ok := wallet.EstimateUSDValue(ctx, t.priceFeed, chainName, v.token, strconv.FormatFloat(v.amount, 'f', -1, 64), time.Time{})
+		if !ok {
+			return estimate, nil
+		}
+		if value.Unavailable != "" {
+			estimate.USDUnavailable = value.Unavailable
+			return estimate, nil
+		}
+		estimate.USDStale = estimate.USDStale || value.Stale
+		*v.target = value.USD
+	}
+	estimate.USDKnown = true
+	estimate.SpendUSD += networkFeeUSD
+	estimate.FeesUSD = networkFeeUSD + protocolFeesUSD
+	return estimate, nil
+}
+
+// swapNetworkFee returns the network fee of quote in native units, the Solana
+// priority fee it includes in lamports, and a note on where it came from. Provider fees
+// are used as quoted; otherwise the fee is estimated from the quoted gas, or
+// for Solana from the signature fee plus the priority fee.
+func swapNetworkFee(chainName string, quote *dex.SwapQuote) (fee, priorityLamports float64, note string) {
+	if quote.Fees != nil && quote.Fees.NetworkFee != "" {
+		if fee, err := strconv.ParseFloat(quote.Fees.NetworkFee, 64); err == nil {
+			return fee, 0, fmt.Sprintf("quoted by %s", quote.Provider)
+		}
+	}
+
+	if chainName == "solana" {
+		computeUnits := quote.EstimatedGas
+		if computeUnits == 0 {
+			computeUnits = solanaSwapComputeUnits
+		}
+		priorityLamports = float64(computeUnits) * solanaPriorityFeeMicroLamports / 1e6
+		fee = (solanaSignatureFeeLamports + priorityLamports) / 1e9
+		return fee, priorityLamports, fmt.Sprintf("estimated: %d lamport signature fee + %d compute units at %d microlamports", solanaSignatureFeeLamports, computeUnits, solanaPriorityFeeMicroLamports)
+	}
+
+	gasPrice, ok := new(big.Int).SetString(quote.GasPrice, 10)
+	if !ok || quote.EstimatedGas == 0 {
+		return 0, 0, "unknown"
+	}
+	wei := new(big.Int).Mul(new(big.Int).SetUint64(quote.EstimatedGas), gasPrice)
+	fee, _ = new(big.Rat).SetFrac(wei, big.NewInt(1e18)).Float64()
+	gwei, _ := new(big.Rat).SetFrac(gasPrice, big.NewInt(1e9)).Float64()
+	return fee, 0, fmt.Sprintf("estimated: %d gas at %s gwei", quote.EstimatedGas, strconv.FormatFloat(gwei, 'f', -1, 64))
+}
+
+// parseFeeAmount parses an itemized fee, treating unknown fees as zero
+func parseFeeAmount(amount string) float64 {
+	fee, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return 0
+	}
+	return fee
+}
+
+// formatSwapCostMarkdown renders the estimate with a one-line summary first
+func formatSwapCostMarkdown(chainName string, params dex.SwapParams, quote *dex.SwapQuote, estimate *swapCostEstimate) string {
+	native := wallet.NativeTokenSymbol(chainName)
+	usd := func(amount float64) string {
+		if !estimate.USDKnown {
+			return ""
+		}
+		return fmt.Sprintf(" (~$%.2f)", amount)
+	}
+
+	spend := fmt.Sprintf("%s %s", formatTokenAmount(estimate.AmountIn), params.FromToken)
+	if estimate.NetworkFee > 0 {
+		if strings.EqualFold(params.FromToken, native) {
+			spend = fmt.Sprintf("%s %s", formatTokenAmount(estimate.AmountIn+estimate.NetworkFee), native)
+		} else {
+			spend += fmt.Sprintf(" + %s %s network fee", formatTokenAmount(estimate.NetworkFee), native)
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString("### Swap Cost Estimate\n\n")
+	builder.WriteString(fmt.Sprintf("You will spend **%s**%s to receive at least **%s %s**%s (expected %s %s%s, worst case at %.2f%% slippage).\n\n",
+		spend, usd(estimate.SpendUSD),
+		formatTokenAmount(estimate.MinimumOut), params.ToToken, usd(estimate.MinimumOutUSD),
+		formatTokenAmount(estimate.ExpectedOut), params.ToToken, usd(estimate.ExpectedOutUSD),
+		params.Slippage*100))
+
+	builder.WriteString(fmt.Sprintf("- **Chain**: `%s`\n", chainName))
+	builder.WriteString(fmt.Sprintf("- **Provider**: `%s`\n", quote.Provider))
+	if len(quote.TimedOutProviders) > 0 {
+		builder.WriteString(fmt.Sprintf("- **Timed Out Providers**: `%s` (not compared)\n", strings.Join(quote.TimedOutProviders, ", ")))
+	}
+	builder.WriteString(fmt.Sprintf("- **Amount In**: `%s %s`\n", formatTokenAmount(estimate.AmountIn), params.FromToken))
+	builder.WriteString(fmt.Sprintf("- **Expected Out**: `%s %s`\n", formatTokenAmount(estimate.ExpectedOut), params.ToToken))
+	builder.WriteString(fmt.Sprintf("- **Minimum Out**: `%s %s` (worst case)\n", formatTokenAmount(estimate.MinimumOut), params.ToToken))
+	builder.WriteString(fmt.Sprintf("- **Max Slippage**: `%.2f%%`\n", params.Slippage*100))
+	if quote.PriceImpact != 0 {
+		builder.WriteString(fmt.Sprintf("- **Price Impact**: `%.2f%%`\n", quote.PriceImpact*100))
+	}
+
+	builder.WriteString("\n#### Fees\n\n")
+	if quote.Fees != nil {
+		for _, item := range []struct{ label, amount string }{
+			{"Protocol Fee", quote.Fees.ProtocolFee},
+			{"Aggregator Fee", quote.Fees.AggregatorFee},
+		} {
+			if item.amount != "" {
+				builder.WriteString(fmt.Sprintf("- **%s**: `%s %s` (included in the quoted output)\n", item.label, item.amount, params.ToToken))
+			}
+		}
+	}
+	if estimate.NetworkFeeNote == "unknown" {
+		builder.WriteString("- **Network Fee**: `unknown`\n")
+	} else {
+		builder.WriteString(fmt.Sprintf("- **Network Fee**: `%s %s` (%s)\n", formatTokenAmount(estimate.NetworkFee), native, estimate.NetworkFeeNote))
+	}
+	if estimate.PriorityFeeLamports > 0 {
+		builder.WriteString(fmt.Sprintf("- **Priority Fee**: `%s lamports` (included in the network fee)\n", strconv.FormatFloat(estimate.PriorityFeeLamports, 'f', -1, 64)))
+	}
+
+	builder.WriteString("\n#### All-in Cost\n\n")
+	switch {
+	case estimate.USDKnown:
+		basis := "estimated at current prices"
+		if estimate.USDStale {
+			basis = "estimated from last known prices"
+		}
+		builder.WriteString(fmt.Sprintf("- **Total Fees**: `~$%.2f USD`\n", estimate.FeesUSD))
+		builder.WriteString(fmt.Sprintf("- **Expected Cost**: `~$%.2f USD` (%s)\n", estimate.ExpectedCostUSD(), basis))
+		builder.WriteString(fmt.Sprintf("- **Worst-Case Cost**: `~$%.2f USD` (%s)\n", estimate.WorstCaseCostUSD(), basis))
+	case estimate.USDUnavailable != "":
+		builder.WriteString(fmt.Sprintf("- **USD Cost**: `unavailable` (%s)\n", estimate.USDUnavailable))
+	default:
+		builder.WriteString("- **USD Cost**: `unknown` (no USD price for one of the tokens)\n")
+	}
+	return builder.String()
+}
+
+// formatTokenAmount renders a token amount to at most nine decimals, without
+// trailing zeros
+func formatTokenAmount(amount float64) string {
+	formatted := strings.TrimRight(strconv.FormatFloat(amount, 'f', 9, 64), "0")
+	return strings.TrimSuffix(formatted, ".")
+}
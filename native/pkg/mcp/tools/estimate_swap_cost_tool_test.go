@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
+	"github.com/algonius/algonius-wallet/native/pkg/dex/providers"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newSwapCostAggregator(t *testing.T, config providers.MockConfig) dex.IDEXAggregator {
+	aggregator := dex.NewDEXAggregator(zap.NewNop())
+	require.NoError(t, aggregator.RegisterProvider(providers.NewMockProvider(config, zap.NewNop())))
+	return aggregator
+}
+
+func swapCostRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "estimate_swap_cost", Arguments: args}}
+}
+
+func TestEstimateSwapCostTool(t *testing.T) {
+	aggregator := newSwapCostAggregator(t, providers.MockConfig{
+		CustomQuoteAmount: "3000",
+		CustomFees:        &dex.FeeBreakdown{ProtocolFee: "3", AggregatorFee: "1.5", NetworkFee: "0.004"},
+	})
+	tool := NewEstimateSwapCostTool(aggregator, staticUSDPriceFeed{"ETH": 3000, "USDC": 1})
+	assert.Equal(t, "estimate_swap_cost", tool.GetMeta().Name)
+
+	result, err := tool.GetHandler()(context.Background(), swapCostRequest(map[string]interface{}{
+		"chain":        "ethereum",
+		"from_token":   "ETH",
+		"to_token":     "USDC",
+		"amount":       "1",
+		"from_address": "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+		"slippage":     0.01,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+
+	assert.Contains(t, text, "You will spend **1.004 ETH** (~$3012.00) to receive at least **2970 USDC** (~$2970.00) (expected 3000 USDC (~$3000.00), worst case at 1.00% slippage)")
+	assert.Contains(t, text, "- **Protocol Fee**: `3 USDC`")
+	assert.Contains(t, text, "- **Aggregator Fee**: `1.5 USDC`")
+	assert.Contains(t, text, "- **Network Fee**: `0.004 ETH` (quoted by MockDEX)")
+	assert.Contains(t, text, "- **Total Fees**: `~$16.50 USD`")
+	assert.Contains(t, text, "- **Expected Cost**: `~$12.00 USD`")
+	assert.Contains(t, text, "- **Worst-Case Cost**: `~$42.00 USD`")
+}
+
+func TestEstimateSwapCostToolSolanaPriorityFee(t *testing.T) {
+	aggregator := newSwapCostAggregator(t, providers.MockConfig{
+		CustomQuoteAmount: "150",
+		CustomFees:        &dex.FeeBreakdown{ProtocolFee: "0"},
+	})
+	tool := NewEstimateSwapCostTool(aggregator, nil)
+
+	result, err := tool.GetHandler()(context.Background(), swapCostRequest(map[string]interface{}{
+		"chain":        "solana",
+		"from_token":   "SOL",
+		"to_token":     "USDC",
+		"amount":       "1",
+		"from_address": "9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+
+	// The quote has no network fee, so the signature and priority fees are estimated
+	assert.Contains(t, text, "- **Network Fee**: `0.000005 SOL` (estimated: 5000 lamport signature fee + 200000 compute units at 1 microlamports)")
+	assert.Contains(t, text, "- **Priority Fee**: `0.2 lamports`")
+	assert.Contains(t, text, "- **Minimum Out**: `149.25 USDC` (worst case)")
+	assert.Contains(t, text, "- **USD Cost**: `unknown`")
+}
+
+func TestEstimateSwapCostToolValidation(t *testing.T) {
+	tool := NewEstimateSwapCostTool(newSwapCostAggregator(t, providers.MockConfig{}), nil)
+	for name, args := range map[string]map[string]interface{}{
+		"unsupported chain": {"chain": "tron", "from_token": "TRX", "to_token": "USDT", "amount": "1", "from_address": "T1"},
+		"missing token":     {"chain": "ethereum", "from_token": "ETH", "amount": "1", "from_address": "0x1"},
+		"bad amount":        {"chain": "ethereum", "from_token": "ETH", "to_token": "USDC", "amount": "-1", "from_address": "0x1"},
+		"bad slippage":      {"chain": "ethereum", "from_token": "ETH", "to_token": "USDC", "amount": "1", "from_address": "0x1", "slippage": 2.0},
+	} {
+		result, err := tool.GetHandler()(context.Background(), swapCostRequest(args))
+		require.NoError(t, err, name)
+		assert.True(t, result.IsError, name)
+	}
+}
+
+func TestEstimateSwapCostToolWithoutProviders(t *testing.T) {
+	aggregator := dex.NewDEXAggregator(zap.NewNop())
+	require.Error(t, aggregator.RegisterProvider(nil))
+
+	for name, tool := range map[string]*EstimateSwapCostTool{
+		"no usable provider": NewEstimateSwapCostTool(aggregator, nil),
+		"no aggregator":      NewEstimateSwapCostTool(nil, nil),
+	} {
+		result, err := tool.GetHandler()(context.Background(), swapCostRequest(map[string]interface{}{
+			"chain":        "bsc",
+			"from_token":   "BNB",
+			"to_token":     "USDT",
+			"amount":       "1",
+			"from_address": "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+		}))
+		require.NoError(t, err)
+		require.True(t, result.IsError, name)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, "NO_DEX_PROVIDERS", name)
+		assert.Contains(t, text, "no DEX providers available for chain bsc", name)
+	}
+}
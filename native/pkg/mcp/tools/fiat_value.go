@@ -0,0 +1,69 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// formatEstimatedValue renders the estimated USD value of amount as a markdown
+// list item, priced now for a zero at and at that time otherwise. It returns ""
+// when the value cannot be estimated so outputs simply omit it, and reports
+// the value as unavailable when the price feed is down.
+func formatEstimatedValue(ctx context.Context, priceFeed wallet.PriceFeed, chainName, token, amount string, at time.Time) string {
+	value, ok := wallet.EstimateUSDValue(ctx, priceFeed, chainName, token, amount, at)
+	if !ok {
+		return ""
+	}
+	return formatFiatValue(value, at)
+}
+
+// formatFiatValue renders an estimated value as formatEstimatedValue does
+func formatFiatValue(value *wallet.FiatValue, at time.Time) string {
+	if value.Unavailable != "" {
+		return fmt.Sprintf("- **Estimated Value**: `unavailable` (%s)\n", value.Unavailable)
+	}
+	basis := fmt.Sprintf("%s at current price", value.Symbol)
+	if value.Stale {
+		basis = fmt.Sprintf("%s last known price, %s old", value.Symbol, formatPriceAge(time.Since(value.PricedAt)))
+	} else if value.Historical {
+		basis = fmt.Sprintf("%s price at %s UTC", value.Symbol, at.UTC().Format("2006-01-02 15:04"))
+	}
+	return fmt.Sprintf("- **Estimated Value**: `~$%.2f USD` (estimated, %s)\n", value.USD, basis)
+}
+
+// formatHistoricalPrice renders the USD price a historical value was
+// estimated with, for computing profit and loss
+func formatHistoricalPrice(value *wallet.FiatValue) string {
+	if value.Unavailable != "" {
+		return "- **USD Price**: `unavailable`\n"
+	}
+	return fmt.Sprintf("- **USD Price**: `$%s` (%s at block time)\n", strconv.FormatFloat(value.Price, 'f', -1, 64), value.Symbol)
+}
+
+// formatFee renders fee as markdown list items named after label: the native
+// amount, the amount in the chain's smallest unit and, when it was priced, the
+// USD value. Every chain uses the same three items.
+func formatFee(label string, fee *chain.Fee) string {
+	markdown := fmt.Sprintf("- **%s**: `%s`\n", label, fee) +
+		fmt.Sprintf("- **%s (%s)**: `%s`\n", label, fee.Unit, fee.SmallestUnit)
+	if fee.USDValue != "" {
+		markdown += fmt.Sprintf("- **%s (USD)**: `~$%s`\n", label, fee.USDValue)
+	}
+	return markdown
+}
+
+// formatPriceAge renders the age of a stale price to the minute, or to the
+// second below one minute
+func formatPriceAge(age time.Duration) string {
+	if age < time.Minute {
+		return age.Round(time.Second).String()
+	}
+	return strings.TrimSuffix(age.Round(time.Minute).String(), "0s")
+}
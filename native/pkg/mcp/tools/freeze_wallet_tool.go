@@ -0,0 +1,63 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// FreezeWalletTool implements the MCP "freeze_wallet" admin tool, an emergency
+// kill switch. Agents can freeze the wallet but never unfreeze it: that needs the
+// wallet password and is only available to the user over native messaging.
+type FreezeWalletTool struct {
+	manager wallet.IWalletManager
+}
+
+// NewFreezeWalletTool constructs a FreezeWalletTool with the given wallet manager.
+func NewFreezeWalletTool(manager wallet.IWalletManager) *FreezeWalletTool {
+	return &FreezeWalletTool{manager: manager}
+}
+
+// GetMeta returns the MCP tool definition for "freeze_wallet".
+func (t *FreezeWalletTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("freeze_wallet",
+		mcp.WithDescription("Emergency kill switch: immediately lock the wallet, stop transaction monitoring and refuse all signing and sending until the user unfreezes it from the extension. Use when a key may be compromised or an agent misbehaves. The freeze persists across restarts"),
+		mcp.WithString("reason",
+			mcp.Required(),
+			mcp.Description("Why the wallet is being frozen; recorded in the audit log"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "freeze_wallet" tool.
+func (t *FreezeWalletTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		reason := strings.TrimSpace(req.GetString("reason", ""))
+		if reason == "" {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("reason")), nil
+		}
+
+		status, err := t.manager.FreezeWallet(ctx, reason, "ai_agent")
+		if status == nil {
+			return toolutils.FormatErrorResult(errors.InternalError("freeze wallet", err)), nil
+		}
+
+		markdown := "### Wallet Frozen\n\n" +
+			"- **Status**: `frozen`\n" +
+			fmt.Sprintf("- **Reason**: %s\n", status.Reason) +
+			fmt.Sprintf("- **Frozen At**: `%s`\n", status.FrozenAt.UTC().Format(time.RFC3339)) +
+			"\nSigning and sending are refused until the wallet owner unfreezes the wallet with its password.\n"
+		if err != nil {
+			markdown += fmt.Sprintf("\n**Warning**: %s\n", err.Error())
+		}
+		return mcp.NewToolResultText(markdown), nil
+	}
+}
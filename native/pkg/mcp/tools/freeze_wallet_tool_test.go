@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWalletManagerForFreeze struct {
+	*wallet.MockWalletManager
+	reason string
+	source string
+}
+
+func (m *mockWalletManagerForFreeze) FreezeWallet(ctx context.Context, reason, source string) (*wallet.FreezeStatus, error) {
+	m.reason = reason
+	m.source = source
+	return &wallet.FreezeStatus{
+		Frozen:   true,
+		Reason:   reason,
+		Source:   source,
+		FrozenAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}, nil
+}
+
+func freezeWalletRequest(args map[string]any) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "freeze_wallet",
+			Arguments: args,
+		},
+	}
+}
+
+func TestFreezeWalletTool(t *testing.T) {
+	mockManager := &mockWalletManagerForFreeze{MockWalletManager: &wallet.MockWalletManager{}}
+	handler := NewFreezeWalletTool(mockManager).GetHandler()
+
+	result, err := handler(context.Background(), freezeWalletRequest(map[string]any{"reason": "unexpected approvals"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, "unexpected approvals", mockManager.reason)
+	assert.Equal(t, "ai_agent", mockManager.source)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "### Wallet Frozen")
+	assert.Contains(t, textContent.Text, "- **Reason**: unexpected approvals")
+	assert.Contains(t, textContent.Text, "- **Frozen At**: `2024-01-02T03:04:05Z`")
+}
+
+func TestFreezeWalletToolRequiresReason(t *testing.T) {
+	mockManager := &mockWalletManagerForFreeze{MockWalletManager: &wallet.MockWalletManager{}}
+	handler := NewFreezeWalletTool(mockManager).GetHandler()
+
+	result, err := handler(context.Background(), freezeWalletRequest(map[string]any{"reason": "  "}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Empty(t, mockManager.source)
+}
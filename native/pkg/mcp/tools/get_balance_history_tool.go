@@ -0,0 +1,174 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultBalanceHistoryWindow is the time range queried when start_time is omitted
+const defaultBalanceHistoryWindow = 24 * time.Hour
+
+// GetBalanceHistoryTool implements the MCP "get_balance_history" tool, which returns
+// the periodic balance snapshots recorded for the wallet's accounts.
+type GetBalanceHistoryTool struct {
+	manager wallet.IWalletManager
+}
+
+// NewGetBalanceHistoryTool constructs a GetBalanceHistoryTool with the given wallet manager.
+func NewGetBalanceHistoryTool(manager wallet.IWalletManager) *GetBalanceHistoryTool {
+	return &GetBalanceHistoryTool{manager: manager}
+}
+
+// GetMeta returns the MCP tool definition for "get_balance_history".
+func (t *GetBalanceHistoryTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("get_balance_history",
+		mcp.WithDescription("Get recorded balance snapshots of the wallet's accounts over a time range, with the change of each balance between the first and last snapshot. Snapshots are taken periodically per wallet.balance_snapshots in the config"),
+		mcp.WithString("address",
+			mcp.Description("Optional account address (all accounts when omitted)"),
+		),
+		mcp.WithString("chain",
+			mcp.Description("Optional chain identifier: ethereum|eth, bsc|binance, solana|sol"),
+		),
+		mcp.WithString("token",
+			mcp.Description("Optional token symbol/contract (all recorded tokens when omitted)"),
+		),
+		mcp.WithString("start_time",
+			mcp.Description("Optional range start, RFC 3339 (default: 24 hours before end_time)"),
+		),
+		mcp.WithString("end_time",
+			mcp.Description("Optional range end, RFC 3339 (default: now)"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "get_balance_history" tool.
+func (t *GetBalanceHistoryTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filter := wallet.BalanceHistoryFilter{
+			Address: strings.TrimSpace(req.GetString("address", "")),
+			Token:   strings.TrimSpace(req.GetString("token", "")),
+		}
+		if chainName := req.GetString("chain", ""); chainName != "" {
+			normalizedChain, err := toolutils.NormalizeChainName(chainName)
+			if err != nil {
+				if appErr, ok := err.(*errors.Error); ok {
+					return toolutils.FormatErrorResult(appErr), nil
+				}
+				return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+			}
+			filter.Chain = normalizedChain
+		}
+
+		filter.To = time.Now()
+		if endTime := req.GetString("end_time", ""); endTime != "" {
+			parsed, err := time.Parse(time.RFC3339, endTime)
+			if err != nil {
+				return toolutils.FormatErrorResult(errors.ValidationError("end_time", "must be an RFC 3339 timestamp")), nil
+			}
+			filter.To = parsed
+		}
+		filter.From = filter.To.Add(-defaultBalanceHistoryWindow)
+		if startTime := req.GetString("start_time", ""); startTime != "" {
+			parsed, err := time.Parse(time.RFC3339, startTime)
+			if err != nil {
+				return toolutils.FormatErrorResult(errors.ValidationError("start_time", "must be an RFC 3339 timestamp")), nil
+			}
+			filter.From = parsed
+		}
+		if filter.From.After(filter.To) {
+			return toolutils.FormatErrorResult(errors.ValidationError("start_time", "must not be after end_time")), nil
+		}
+
+		snapshots, err := t.manager.GetBalanceHistory(ctx, filter)
+		if err != nil {
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("get balance history", err)), nil
+		}
+
+		return mcp.NewToolResultText(formatBalanceHistoryMarkdown(filter, snapshots)), nil
+	}
+}
+
+// balanceSeries is the snapshots of one token held by one account
+type balanceSeries struct {
+	chain, address, token string
+	points                []*wallet.BalanceSnapshot
+}
+
+// formatBalanceHistoryMarkdown groups snapshots per account and token and renders them for the agent
+func formatBalanceHistoryMarkdown(filter wallet.BalanceHistoryFilter, snapshots []*wallet.BalanceSnapshot) string {
+	var sb strings.Builder
+	sb.WriteString("### Balance History\n\n")
+	sb.WriteString(fmt.Sprintf("- **From**: `%s`\n", filter.From.UTC().Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("- **To**: `%s`\n", filter.To.UTC().Format(time.RFC3339)))
+
+	if len(snapshots) == 0 {
+		sb.WriteString("\nNo balance snapshots recorded in this range.\n")
+		return sb.String()
+	}
+
+	var series []*balanceSeries
+	index := make(map[string]*balanceSeries)
+	for _, snapshot := range snapshots {
+		key := snapshot.Chain + "|" + snapshot.Address + "|" + snapshot.Token
+		s, ok := index[key]
+		if !ok {
+			s = &balanceSeries{chain: snapshot.Chain, address: snapshot.Address, token: snapshot.Token}
+			index[key] = s
+			series = append(series, s)
+		}
+		s.points = append(s.points, snapshot)
+	}
+
+	for _, s := range series {
+		first, last := s.points[0], s.points[len(s.points)-1]
+		sb.WriteString(fmt.Sprintf("\n#### %s `%s` on %s\n\n", s.token, s.address, s.chain))
+		sb.WriteString(fmt.Sprintf("- **Snapshots**: `%d`\n", len(s.points)))
+		sb.WriteString(fmt.Sprintf("- **Start Balance**: `%s`\n", first.Balance))
+		sb.WriteString(fmt.Sprintf("- **End Balance**: `%s`\n", last.Balance))
+		if change, ok := balanceChange(first.Balance, last.Balance); ok {
+			sb.WriteString(fmt.Sprintf("- **Change**: `%s`\n", change))
+		}
+		sb.WriteString("\n| Time | Balance |\n|------|---------|\n")
+		for _, point := range s.points {
+			sb.WriteString(fmt.Sprintf("| %s | %s |\n", time.Unix(point.Timestamp, 0).UTC().Format(time.RFC3339), point.Balance))
+		}
+	}
+	return sb.String()
+}
+
+// balanceChange returns end - start with an explicit sign, at the precision of the inputs
+func balanceChange(start, end string) (string, bool) {
+	from, ok := new(big.Rat).SetString(start)
+	if !ok {
+		return "", false
+	}
+	to, ok := new(big.Rat).SetString(end)
+	if !ok {
+		return "", false
+	}
+	decimals := 0
+	for _, value := range []string{start, end} {
+		if dot := strings.IndexByte(value, '.'); dot >= 0 && len(value)-dot-1 > decimals {
+			decimals = len(value) - dot - 1
+		}
+	}
+	diff := new(big.Rat).Sub(to, from)
+	text := diff.FloatString(decimals)
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+	if diff.Sign() > 0 {
+		text = "+" + text
+	}
+	return text, true
+}
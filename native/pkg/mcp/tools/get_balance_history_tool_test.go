@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWalletManagerForBalanceHistory struct {
+	*wallet.MockWalletManager
+	lastFilter wallet.BalanceHistoryFilter
+	snapshots  []*wallet.BalanceSnapshot
+}
+
+func (m *mockWalletManagerForBalanceHistory) GetBalanceHistory(ctx context.Context, filter wallet.BalanceHistoryFilter) ([]*wallet.BalanceSnapshot, error) {
+	m.lastFilter = filter
+	return m.snapshots, nil
+}
+
+func balanceHistoryRequest(args map[string]any) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_balance_history",
+			Arguments: args,
+		},
+	}
+}
+
+func TestGetBalanceHistoryTool(t *testing.T) {
+	address := "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+	mockManager := &mockWalletManagerForBalanceHistory{
+		MockWalletManager: &wallet.MockWalletManager{},
+		snapshots: []*wallet.BalanceSnapshot{
+			{Timestamp: 1700000000, Chain: "ethereum", Address: address, Token: "ETH", Balance: "1.25"},
+			{Timestamp: 1700003600, Chain: "ethereum", Address: address, Token: "ETH", Balance: "1.1"},
+		},
+	}
+	handler := NewGetBalanceHistoryTool(mockManager).GetHandler()
+
+	result, err := handler(context.Background(), balanceHistoryRequest(map[string]any{
+		"chain":      "eth",
+		"address":    address,
+		"start_time": "2023-11-14T00:00:00Z",
+		"end_time":   "2023-11-16T00:00:00Z",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, "ethereum", mockManager.lastFilter.Chain)
+	assert.Equal(t, address, mockManager.lastFilter.Address)
+	assert.Equal(t, time.Date(2023, 11, 14, 0, 0, 0, 0, time.UTC), mockManager.lastFilter.From.UTC())
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "### Balance History")
+	assert.Contains(t, textContent.Text, "- **Start Balance**: `1.25`")
+	assert.Contains(t, textContent.Text, "- **End Balance**: `1.1`")
+	assert.Contains(t, textContent.Text, "- **Change**: `-0.15`")
+	assert.Contains(t, textContent.Text, "| 2023-11-14T22:13:20Z | 1.25 |")
+}
+
+func TestGetBalanceHistoryToolDefaultsAndValidation(t *testing.T) {
+	mockManager := &mockWalletManagerForBalanceHistory{MockWalletManager: &wallet.MockWalletManager{}}
+	handler := NewGetBalanceHistoryTool(mockManager).GetHandler()
+
+	result, err := handler(context.Background(), balanceHistoryRequest(map[string]any{}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, defaultBalanceHistoryWindow, mockManager.lastFilter.To.Sub(mockManager.lastFilter.From))
+	textContent, _ := mcp.AsTextContent(result.Content[0])
+	assert.Contains(t, textContent.Text, "No balance snapshots recorded")
+
+	for _, args := range []map[string]any{
+		{"start_time": "yesterday"},
+		{"start_time": "2023-11-16T00:00:00Z", "end_time": "2023-11-14T00:00:00Z"},
+		{"chain": "dogecoin"},
+	} {
+		result, err := handler(context.Background(), balanceHistoryRequest(args))
+		require.NoError(t, err)
+		assert.True(t, result.IsError, args)
+	}
+}
+
+func TestBalanceChange(t *testing.T) {
+	change, ok := balanceChange("0.1", "0.3")
+	require.True(t, ok)
+	assert.Equal(t, "+0.2", change)
+
+	change, _ = balanceChange("5", "5.000")
+	assert.Equal(t, "0", change)
+
+	_, ok = balanceChange("n/a", "1")
+	assert.False(t, ok)
+}
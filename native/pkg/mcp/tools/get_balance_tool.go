@@ -3,6 +3,8 @@ package tools
 
 import (
 	"context"
+	"strconv"
+	"strings"
 
 	"github.com/algonius/algonius-wallet/native/pkg/errors"
 	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
@@ -42,6 +44,10 @@ func (t *GetBalanceTool) GetMeta() mcp.Tool {
 			mcp.Required(),
 			mcp.Description(tokenDescription),
 		),
+		mcp.WithBoolean("breakdown",
+			mcp.Description("Also split the balance into total, available and locked funds, listing what is locked: the gas reserve, pending sends, and on Solana the rent-exempt minimum and stake (optional, default false)"),
+		),
+		withCommitmentOption(),
 	)
 }
 
@@ -62,7 +68,8 @@ func (t *GetBalanceTool) GetHandler() server.ToolHandlerFunc {
 		}
 
 		// Validate token using centralized mapping for better error messages
-		if _, err := chain.DefaultTokenMapping.GetTokenInfo(token); err != nil {
+		tokenInfo, err := chain.DefaultTokenMapping.GetTokenInfo(token)
+		if err != nil {
 			// Check if it might be a contract address
 			if !((len(token) == 42 && token[:2] == "0x") || (len(token) >= 32 && len(token) <= 44)) {
 				// Provide helpful suggestion for unsupported tokens
@@ -73,6 +80,32 @@ func (t *GetBalanceTool) GetHandler() server.ToolHandlerFunc {
 			}
 		}
 
+		// Contract tokens are Solana mints unless the address is hex
+		balanceChain := "solana"
+		if tokenInfo != nil {
+			balanceChain = wallet.NormalizeChain(tokenInfo.ChainName)
+		} else if strings.HasPrefix(address, "0x") {
+			balanceChain = wallet.DefaultEVMChain(t.manager.DefaultChain())
+		}
+		ctx, toolErr := commitmentContext(ctx, req, balanceChain)
+		if toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
+		if req.GetBool("breakdown", false) {
+			breakdownToken := token
+			if tokenInfo != nil && tokenInfo.IsNative {
+				breakdownToken = tokenInfo.Symbol
+			}
+			breakdown, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (*chain.BalanceBreakdown, error) {
+				return t.manager.GetBalanceBreakdown(attemptCtx, balanceChain, address, breakdownToken)
+			})
+			if err != nil {
+				return toolutils.FormatErrorResult(toolutils.ClassifyError("get balance", err)), nil
+			}
+			return mcp.NewToolResultText(formatBalanceBreakdownMarkdown(token, breakdown)), nil
+		}
+
 		balance, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (string, error) {
 			return t.manager.GetBalance(attemptCtx, address, token)
 		})
@@ -85,6 +118,36 @@ func (t *GetBalanceTool) GetHandler() server.ToolHandlerFunc {
 			"- **Address**: `" + address + "`\n" +
 			"- **Token**: `" + token + "`\n" +
 			"- **Balance**: `" + balance + "`\n"
+
+		// Native balances keep a reserve back for gas; show what can actually be sent
+		if tokenInfo != nil && tokenInfo.IsNative {
+			if reserve := t.manager.NativeReserve(tokenInfo.ChainName); reserve > 0 {
+				if available, err := chain.AvailableToSend(balance, reserve); err == nil {
+					markdown += "- **Gas Reserve**: `" + strconv.FormatFloat(reserve, 'f', -1, 64) + "`\n" +
+						"- **Available to Send**: `" + available + "`\n"
+				}
+			}
+		}
 		return mcp.NewToolResultText(markdown), nil
 	}
 }
+
+// formatBalanceBreakdownMarkdown renders a balance with its locked parts. The
+// Balance line is the same one a plain get_balance reports.
+func formatBalanceBreakdownMarkdown(token string, b *chain.BalanceBreakdown) string {
+	markdown := "### Wallet Balance\n\n" +
+		"- **Address**: `" + b.Address + "`\n" +
+		"- **Token**: `" + token + "`\n" +
+		"- **Balance**: `" + b.Balance + "`\n" +
+		"- **Total**: `" + b.Total + "`\n" +
+		"- **Available**: `" + b.Available + "`\n" +
+		"- **Locked**: `" + b.Locked + "`\n"
+	for _, locked := range b.Breakdown {
+		markdown += "  - `" + locked.Kind + "`: `" + locked.Amount + "`"
+		if locked.Description != "" {
+			markdown += " (" + locked.Description + ")"
+		}
+		markdown += "\n"
+	}
+	return markdown
+}
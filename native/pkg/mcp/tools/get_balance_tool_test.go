@@ -5,8 +5,10 @@ import (
 	"testing"
 
 	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -14,18 +16,27 @@ type mockWalletManagerForGetBalance struct {
 	*wallet.MockWalletManager
 	lastAddress string
 	lastToken   string
+	commitment  string
 	shouldFail  bool
 }
 
 func (m *mockWalletManagerForGetBalance) GetBalance(ctx context.Context, address, token string) (string, error) {
 	m.lastAddress = address
 	m.lastToken = token
+	m.commitment, _ = chain.CommitmentFromContext(ctx)
 	if m.shouldFail {
 		return "", assert.AnError
 	}
 	return "123.456", nil
 }
 
+func (m *mockWalletManagerForGetBalance) NativeReserve(chainName string) float64 {
+	if chainName == "ETH" {
+		return 0.005
+	}
+	return 0
+}
+
 func TestGetBalanceToolHandlerSuccess(t *testing.T) {
 	mockManager := &mockWalletManagerForGetBalance{MockWalletManager: &wallet.MockWalletManager{}}
 	tool := NewGetBalanceTool(mockManager)
@@ -52,6 +63,37 @@ func TestGetBalanceToolHandlerSuccess(t *testing.T) {
 	require.True(t, ok)
 	assert.Contains(t, textContent.Text, "### Wallet Balance")
 	assert.Contains(t, textContent.Text, "123.456")
+	assert.Contains(t, textContent.Text, "**Gas Reserve**: `0.005`")
+	assert.Contains(t, textContent.Text, "**Available to Send**: `123.451`")
+}
+
+func TestGetBalanceToolHandlerCommitment(t *testing.T) {
+	mockManager := &mockWalletManagerForGetBalance{MockWalletManager: &wallet.MockWalletManager{}}
+	handler := NewGetBalanceTool(mockManager).GetHandler()
+	call := func(address, token, commitment string) *mcp.CallToolResult {
+		result, err := handler(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name: "get_balance",
+				Arguments: map[string]any{
+					"address":    address,
+					"token":      token,
+					"commitment": commitment,
+				},
+			},
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	result := call("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin", "SOL", "finalized")
+	require.False(t, result.IsError)
+	assert.Equal(t, "finalized", mockManager.commitment)
+
+	result = call("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin", "SOL", "max")
+	assert.True(t, result.IsError)
+
+	result = call("0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8", "ETH", "finalized")
+	assert.True(t, result.IsError, "commitment is Solana only")
 }
 
 func TestGetBalanceToolHandlerMissingAddress(t *testing.T) {
@@ -96,3 +138,36 @@ func TestGetBalanceToolHandlerManagerError(t *testing.T) {
 	require.NotNil(t, result)
 	assert.True(t, result.IsError)
 }
+
+func TestGetBalanceToolHandlerBreakdown(t *testing.T) {
+	address := "9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin"
+	mockManager := &wallet.MockWalletManager{}
+	mockManager.On("GetBalanceBreakdown", mock.Anything, "solana", address, "SOL").Return(&chain.BalanceBreakdown{
+		Chain:     "solana",
+		Address:   address,
+		Token:     "SOL",
+		Balance:   "1.5",
+		Total:     "3.5",
+		Available: "1.49910912",
+		Locked:    "2.00089088",
+		Breakdown: []chain.LockedBalance{
+			{Kind: chain.LockedKindRentReserve, Amount: "0.00089088", Description: "rent-exempt minimum"},
+			{Kind: chain.LockedKindStaked, Amount: "2", External: true},
+		},
+	}, nil)
+	handler := NewGetBalanceTool(mockManager).GetHandler()
+
+	result, err := handler(context.Background(), scheduleRequest("get_balance", map[string]any{
+		"address":   address,
+		"token":     "solana",
+		"breakdown": true,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Balance**: `1.5`\n", "the plain balance is kept")
+	assert.Contains(t, text, "- **Total**: `3.5`\n- **Available**: `1.49910912`\n- **Locked**: `2.00089088`\n")
+	assert.Contains(t, text, "  - `rent_reserve`: `0.00089088` (rent-exempt minimum)\n")
+	assert.Contains(t, text, "  - `staked`: `2`\n")
+	mockManager.AssertExpectations(t)
+}
@@ -0,0 +1,192 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultRoutingDecisions is how many recent decisions get_dex_routing shows by default
+const defaultRoutingDecisions = 5
+
+// GetDEXRoutingTool implements the MCP "get_dex_routing" tool. It shows how the
+// DEX aggregator selects quotes: the active strategy, the health of every
+// provider and, for recent best-quote requests, what each provider answered
+// and why the winner was picked.
+type GetDEXRoutingTool struct {
+	aggregator dex.IDEXAggregator
+}
+
+// NewGetDEXRoutingTool constructs a GetDEXRoutingTool
+func NewGetDEXRoutingTool(aggregator dex.IDEXAggregator) *GetDEXRoutingTool {
+	return &GetDEXRoutingTool{aggregator: aggregator}
+}
+
+// GetMeta returns the MCP tool definition for "get_dex_routing".
+func (t *GetDEXRoutingTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("get_dex_routing",
+		mcp.WithDescription("Inspect the DEX aggregator's routing: the active quote selection strategy and fan-out bounds, the health of each provider, and for recent best-quote requests every provider's quote, fees and latency with the reason the winner was selected"),
+		mcp.WithString("chain",
+			mcp.Description("Only show decisions for this chain ('ethereum', 'bsc' or 'solana'; optional)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Number of recent decisions to show (default %d)", defaultRoutingDecisions)),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "get_dex_routing" tool.
+func (t *GetDEXRoutingTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainID := ""
+		if chain := req.GetString("chain", ""); chain != "" {
+			chainName, err := toolutils.NormalizeChainName(chain)
+			if err != nil {
+				return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+			}
+			var ok bool
+			if chainID, ok = swapChainIDs[chainName]; !ok {
+				return toolutils.FormatErrorResult(errors.ValidationError("chain", fmt.Sprintf("swaps are not supported on %s", chainName))), nil
+			}
+		}
+		limit := req.GetInt("limit", defaultRoutingDecisions)
+		if limit <= 0 {
+			return toolutils.FormatErrorResult(errors.ValidationError("limit", "limit must be a positive number")), nil
+		}
+
+		inspector, ok := t.aggregator.(dex.QuoteDecisionInspector)
+		if !ok {
+			return toolutils.FormatErrorResult(errors.InternalError("inspect DEX routing", fmt.Errorf("the DEX aggregator does not record its routing decisions"))), nil
+		}
+
+		// Filter before limiting, so a chain filter still returns limit decisions
+		var decisions []dex.QuoteDecision
+		for _, decision := range inspector.RecentDecisions(0) {
+			if len(decisions) == limit {
+				break
+			}
+			if chainID == "" || decision.ChainID == chainID {
+				decisions = append(decisions, decision)
+			}
+		}
+		return mcp.NewToolResultText(formatDEXRoutingMarkdown(inspector.RoutingStrategy(), inspector.ProviderHealth(), decisions)), nil
+	}
+}
+
+// formatDEXRoutingMarkdown renders the routing strategy, provider health and decisions
+func formatDEXRoutingMarkdown(strategy dex.RoutingStrategy, health []dex.ProviderHealth, decisions []dex.QuoteDecision) string {
+	markdown := "### DEX Routing\n\n" +
+		fmt.Sprintf("- **Strategy**: `%s` (`%s` when the caller compares quotes net of fees)\n", strategy.Default, dex.StrategyBestNetOutput) +
+		fmt.Sprintf("- **Tie Tolerance**: `%g%%` (closer outputs are ranked by provider priority)\n", strategy.TieTolerance*100) +
+		"- **Provider Timeout**: `" + formatFanOutBound(strategy.FanOut.ProviderTimeout) + "`\n" +
+		"- **Fan-out Deadline**: `" + formatFanOutBound(strategy.FanOut.Deadline) + "`\n"
+	if strategy.FanOut.MaxConcurrency > 0 {
+		markdown += fmt.Sprintf("- **Max Concurrency**: `%d`\n", strategy.FanOut.MaxConcurrency)
+	} else {
+		markdown += "- **Max Concurrency**: `unbounded`\n"
+	}
+	markdown += fmt.Sprintf("- **Decision History**: `%d`\n", strategy.DecisionHistory)
+
+	markdown += "\n#### Providers\n\n"
+	if len(health) == 0 {
+		markdown += "No DEX providers are registered.\n"
+	}
+	for _, h := range health {
+		state := "enabled"
+		if !h.Enabled {
+			state = "disabled"
+		}
+		markdown += fmt.Sprintf("- **%s**: `%s` (%s, priority %d) — %d request(s), %d failed, %d timed out, %d won",
+			h.Provider, h.Status, state, h.Priority, h.Requests, h.Failures, h.TimedOut, h.Wins)
+		if h.AverageLatency > 0 {
+			markdown += ", average latency `" + formatLatency(h.AverageLatency) + "`"
+		}
+		if h.LastError != "" {
+			markdown += "; last error: " + h.LastError
+		}
+		markdown += "\n"
+	}
+
+	markdown += "\n#### Recent Decisions\n"
+	if len(decisions) == 0 {
+		markdown += "\nNo quote decisions have been recorded yet.\n"
+	}
+	for _, decision := range decisions {
+		markdown += fmt.Sprintf("\n##### #%d: %s %s → %s on %s\n\n", decision.ID, decision.Amount, decision.FromToken, decision.ToToken, chainNameForSwapID(decision.ChainID)) +
+			"- **Time**: `" + decision.Timestamp.Format(time.RFC3339) + "`\n" +
+			"- **Strategy**: `" + decision.Strategy + "`\n"
+		if decision.Winner != "" {
+			markdown += "- **Winner**: `" + decision.Winner + "` (" + decision.Reason + ")\n"
+		} else {
+			markdown += "- **Winner**: none (" + decision.Reason + ")\n"
+		}
+		for i, quote := range decision.Quotes {
+			markdown += fmt.Sprintf("%d. `%s` `%s` in `%s`", i+1, quote.Provider, quote.Status, formatLatency(quote.Latency))
+			if quote.Status != dex.ProviderQuoteOK {
+				markdown += ": " + quote.Error + "\n"
+				continue
+			}
+			markdown += ": output `" + quote.ToAmount + "`"
+			if quote.NetToAmount != "" {
+				markdown += ", net `" + quote.NetToAmount + "`"
+			}
+			if fees := formatRoutingFees(quote.Fees); fees != "" {
+				markdown += ", fees " + fees
+			}
+			markdown += "\n"
+		}
+	}
+	return markdown
+}
+
+// formatRoutingFees lists the known fees of a quote on one line
+func formatRoutingFees(fees *dex.FeeBreakdown) string {
+	if fees == nil {
+		return ""
+	}
+	var items []string
+	for _, item := range []struct{ label, amount string }{
+		{"protocol", fees.ProtocolFee},
+		{"aggregator", fees.AggregatorFee},
+		{"network", fees.NetworkFee},
+	} {
+		if item.amount != "" {
+			items = append(items, item.label+" `"+item.amount+"`")
+		}
+	}
+	return strings.Join(items, ", ")
+}
+
+// formatFanOutBound renders a fan-out timeout, where zero leaves it off
+func formatFanOutBound(bound time.Duration) string {
+	if bound <= 0 {
+		return "none"
+	}
+	return bound.String()
+}
+
+// formatLatency renders a provider latency at millisecond precision
+func formatLatency(latency time.Duration) string {
+	if latency < time.Millisecond {
+		return latency.Round(time.Microsecond).String()
+	}
+	return latency.Round(time.Millisecond).String()
+}
+
+// chainNameForSwapID returns the chain name for a DEX chain ID, or the ID itself
+func chainNameForSwapID(chainID string) string {
+	for name, id := range swapChainIDs {
+		if id == chainID {
+			return name
+		}
+	}
+	return chainID
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
+	"github.com/algonius/algonius-wallet/native/pkg/dex/providers"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetDEXRoutingTool(t *testing.T) {
+	aggregator := dex.NewDEXAggregator(zap.NewNop())
+	require.NoError(t, aggregator.RegisterProvider(providers.NewMockProvider(providers.MockConfig{
+		Name:              "Winner",
+		CustomQuoteAmount: "3100",
+		CustomFees:        &dex.FeeBreakdown{ProtocolFee: "3", NetworkFee: "0.004"},
+	}, zap.NewNop())))
+	require.NoError(t, aggregator.RegisterProvider(providers.NewMockProvider(providers.MockConfig{
+		Name:            "Broken",
+		ShouldFailQuote: true,
+	}, zap.NewNop())))
+
+	tool := NewGetDEXRoutingTool(aggregator)
+	assert.Equal(t, "get_dex_routing", tool.GetMeta().Name)
+	handler := tool.GetHandler()
+
+	result, err := handler(context.Background(), scheduleRequest("get_dex_routing", map[string]any{}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "No quote decisions have been recorded yet.")
+
+	for _, chainID := range []string{"1", "501"} {
+		_, err := aggregator.GetBestQuote(context.Background(), dex.SwapParams{
+			FromToken:   "ETH",
+			ToToken:     "USDC",
+			Amount:      "1",
+			FromAddress: "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+			ChainID:     chainID,
+		})
+		require.NoError(t, err)
+	}
+
+	result, err = handler(context.Background(), scheduleRequest("get_dex_routing", map[string]any{"chain": "ethereum"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+
+	assert.Contains(t, text, "- **Strategy**: `best_output`")
+	assert.Contains(t, text, "- **Tie Tolerance**: `0.1%`")
+	assert.Contains(t, text, "- **Decision History**: `50`")
+	assert.Contains(t, text, "- **Winner**: `healthy` (enabled, priority 0) — 2 request(s), 0 failed, 0 timed out, 2 won")
+	assert.Contains(t, text, "- **Broken**: `failing`")
+	assert.Contains(t, text, "##### #1: 1 ETH → USDC on ethereum")
+	assert.NotContains(t, text, "on solana", "decisions are filtered by chain")
+	assert.Contains(t, text, "- **Winner**: `Winner` (only provider to return a valid quote)")
+	assert.Contains(t, text, ": output `3100`")
+	assert.Contains(t, text, "fees protocol `3`, network `0.004`")
+	assert.Contains(t, text, "`Broken` `failed`")
+
+	result, err = handler(context.Background(), scheduleRequest("get_dex_routing", map[string]any{"limit": 0}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
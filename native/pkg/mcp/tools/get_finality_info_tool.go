@@ -0,0 +1,119 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// finalityChains is the order chains are reported in when none is requested
+var finalityChains = []string{"ethereum", "bsc", "solana"}
+
+// GetFinalityInfoTool implements the MCP "get_finality_info" tool, reporting how many
+// confirmations the wallet waits for on each chain and how long that and finality take.
+// It only reads configuration, so it works while the wallet is locked.
+type GetFinalityInfoTool struct {
+	chains config.ChainsConfig
+}
+
+// NewGetFinalityInfoTool constructs a GetFinalityInfoTool reporting the confirmation
+// policy of cfg. A nil cfg uses the defaults.
+func NewGetFinalityInfoTool(cfg *config.Config) *GetFinalityInfoTool {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return &GetFinalityInfoTool{chains: cfg.Chains}
+}
+
+// GetMeta returns the MCP tool definition for "get_finality_info".
+func (t *GetFinalityInfoTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("get_finality_info",
+		mcp.WithDescription("Get the confirmation policy of each chain: required confirmations, Solana commitment level, poll interval and timeout as configured, with the estimated time to confirmation and to finality from the average block/slot time"),
+		mcp.WithString("chain",
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol (optional, defaults to every enabled chain)"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "get_finality_info" tool.
+func (t *GetFinalityInfoTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var chainNames []string
+		if requested := strings.TrimSpace(req.GetString("chain", "")); requested != "" {
+			normalizedChain, err := toolutils.NormalizeChainName(requested)
+			if err != nil {
+				if appErr, ok := err.(*errors.Error); ok {
+					return toolutils.FormatErrorResult(appErr), nil
+				}
+				return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+			}
+			chainNames = []string{normalizedChain}
+		} else {
+			for _, chainName := range finalityChains {
+				if t.chains.Enabled(chainName) {
+					chainNames = append(chainNames, chainName)
+				}
+			}
+			if len(chainNames) == 0 {
+				return toolutils.FormatErrorResult(errors.ValidationError("chain", "no chains are enabled")), nil
+			}
+		}
+
+		markdown := "### Finality Info\n"
+		for _, chainName := range chainNames {
+			markdown += "\n" + t.formatChainFinality(chainName)
+		}
+		markdown += "\n> Times are estimates from average block/slot times and grow under congestion.\n"
+		return mcp.NewToolResultText(markdown), nil
+	}
+}
+
+// formatChainFinality renders the confirmation policy and finality estimate of a chain
+func (t *GetFinalityInfoTool) formatChainFinality(chainName string) string {
+	confirmation := t.chains.Confirmation(chainName)
+	estimate, _ := chain.EstimateFinality(chainName, confirmation.RequiredConfirmations)
+
+	markdown := "#### " + chainName + "\n\n"
+	if !t.chains.Enabled(chainName) {
+		markdown += "- **Enabled**: `no`\n"
+	}
+	markdown += "- **Required Confirmations**: `" + fmt.Sprint(confirmation.RequiredConfirmations) + "`\n"
+	if chainName == "solana" {
+		commitment := strings.ToLower(t.chains.Solana.Commitment)
+		if commitment == "" {
+			commitment = chain.CommitmentConfirmed
+		}
+		markdown += "- **Commitment**: `" + commitment + "`\n"
+	}
+	markdown += "- **Block Time**: `" + formatFinalityDuration(estimate.BlockTime) + "`\n" +
+		"- **Estimated Time to Confirm**: `" + formatFinalityDuration(estimate.TimeToConfirm) + "`\n" +
+		"- **Finality**: " + estimate.Finality + ", `" + fmt.Sprint(estimate.FinalityBlocks) + "` blocks\n" +
+		"- **Estimated Time to Finality**: `" + formatFinalityDuration(estimate.TimeToFinality) + "`\n" +
+		"- **Poll Interval**: `" + confirmation.PollInterval.String() + "`\n" +
+		"- **Timeout**: `" + confirmation.Timeout.String() + "`\n"
+
+	threshold := t.chains.LargeTxThreshold(chainName)
+	if chainName == "solana" && threshold.Enabled() {
+		markdown += "\n> Transfers at or above large_tx_threshold are only reported confirmed once finalized.\n"
+	} else if estimate.TimeToConfirm < estimate.TimeToFinality {
+		markdown += "\n> A confirmed transaction can still be reorganized until it is final.\n"
+	}
+	return markdown
+}
+
+// formatFinalityDuration renders d rounded for display, e.g. 2.4s or 12m48s
+func formatFinalityDuration(d time.Duration) string {
+	if d >= time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
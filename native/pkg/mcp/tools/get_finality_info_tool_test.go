@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func finalityInfo(t *testing.T, cfg *config.Config, args map[string]any) *mcp.CallToolResult {
+	result, err := NewGetFinalityInfoTool(cfg).GetHandler()(context.Background(), scheduleRequest("get_finality_info", args))
+	require.NoError(t, err)
+	return result
+}
+
+func TestGetFinalityInfoToolReportsEnabledChains(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Chains.Ethereum.Enabled = true
+	cfg.Chains.BSC.Enabled = false
+	cfg.Chains.Solana.Enabled = true
+
+	result := finalityInfo(t, cfg, map[string]any{})
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "#### ethereum")
+	assert.Contains(t, text, "#### solana")
+	assert.NotContains(t, text, "#### bsc")
+	// 12 blocks of 12s, finalized after 64
+	assert.Contains(t, text, "- **Estimated Time to Confirm**: `2m24s`")
+	assert.Contains(t, text, "- **Estimated Time to Finality**: `12m48s`")
+	assert.Contains(t, text, "- **Commitment**: `confirmed`")
+}
+
+func TestGetFinalityInfoToolReflectsConfigOverrides(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Chains.BSC.Confirmation.RequiredConfirmations = 4
+	cfg.Chains.Solana.Commitment = "finalized"
+	cfg.Chains.Solana.LargeTxThreshold.Native = 10
+
+	result := finalityInfo(t, cfg, map[string]any{"chain": "binance"})
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "#### bsc")
+	assert.Contains(t, text, "- **Required Confirmations**: `4`")
+	assert.Contains(t, text, "- **Estimated Time to Confirm**: `3s`")
+
+	text = finalityInfo(t, cfg, map[string]any{"chain": "sol"}).Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Commitment**: `finalized`")
+	assert.Contains(t, text, "only reported confirmed once finalized")
+
+	assert.True(t, finalityInfo(t, cfg, map[string]any{"chain": "dogecoin"}).IsError)
+}
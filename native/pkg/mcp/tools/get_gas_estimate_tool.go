@@ -0,0 +1,143 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// GetGasEstimateTool implements the MCP "get_gas_estimate" tool, previewing
+// the fee of a transfer through the wallet manager before it is sent or
+// approved. It only reads the chain, so it works while the wallet is locked.
+type GetGasEstimateTool struct {
+	manager wallet.IWalletManager
+	logger  *zap.Logger
+}
+
+// NewGetGasEstimateTool constructs a GetGasEstimateTool with the given wallet manager.
+func NewGetGasEstimateTool(manager wallet.IWalletManager, logger *zap.Logger) *GetGasEstimateTool {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &GetGasEstimateTool{manager: manager, logger: logger}
+}
+
+// GetMeta returns the MCP tool definition for "get_gas_estimate".
+func (t *GetGasEstimateTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("get_gas_estimate",
+		mcp.WithDescription("Preview the fee of a transfer before sending or approving it: gas limit and gas price in gwei with the total fee in ETH or BNB on EVM chains, compute units and the compute unit price in micro-lamports with the total fee in SOL on Solana"),
+		mcp.WithString("chain",
+			mcp.Required(),
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol"),
+		),
+		mcp.WithString("from",
+			mcp.Required(),
+			mcp.Description("Sender address"),
+		),
+		mcp.WithString("to",
+			mcp.Required(),
+			mcp.Description("Recipient address"),
+		),
+		mcp.WithString("amount",
+			mcp.Required(),
+			mcp.Description("Amount to transfer"),
+		),
+		mcp.WithString("token",
+			mcp.Description("Token contract or mint address (native token when omitted)"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "get_gas_estimate" tool.
+func (t *GetGasEstimateTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName, err := req.RequireString("chain")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("chain")), nil
+		}
+		from, err := req.RequireString("from")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("from")), nil
+		}
+		to, err := req.RequireString("to")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("to")), nil
+		}
+		amount, err := req.RequireString("amount")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("amount")), nil
+		}
+		token := strings.TrimSpace(req.GetString("token", ""))
+
+		normalizedChain, err := toolutils.NormalizeChainName(chainName)
+		if err != nil {
+			if appErr, ok := err.(*errors.Error); ok {
+				return toolutils.FormatErrorResult(appErr), nil
+			}
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+		if !isValidAddressForChain(normalizedChain, from) {
+			return toolutils.FormatErrorResult(errors.ValidationError("from", fmt.Sprintf("invalid %s address", normalizedChain))), nil
+		}
+		if !isValidAddressForChain(normalizedChain, to) {
+			return toolutils.FormatErrorResult(errors.ValidationError("to", fmt.Sprintf("invalid %s address", normalizedChain))), nil
+		}
+		native := wallet.NativeTokenSymbol(normalizedChain)
+		if strings.EqualFold(token, native) {
+			token = ""
+		}
+		if token != "" && !isValidAddressForChain(normalizedChain, token) {
+			return toolutils.FormatErrorResult(errors.ValidationError("token", fmt.Sprintf("must be %s or a %s token address", native, normalizedChain))), nil
+		}
+
+		estimate, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (gasEstimate, error) {
+			limit, price, estimateErr := t.manager.EstimateGas(attemptCtx, normalizedChain, from, to, amount, token)
+			return gasEstimate{limit: limit, price: price}, estimateErr
+		})
+		if err != nil {
+			t.logger.Debug("Gas estimate failed", zap.String("chain", normalizedChain), zap.Error(err))
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("estimate gas", err)), nil
+		}
+
+		fee, err := walletchain.EstimateFee(normalizedChain, estimate.limit, estimate.price)
+		if err != nil {
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("estimate gas", err)), nil
+		}
+		return mcp.NewToolResultText(formatGasEstimate(normalizedChain, from, to, amount, token, estimate, fee)), nil
+	}
+}
+
+// gasEstimate is a gas limit and price as returned by EstimateGas
+type gasEstimate struct {
+	limit uint64
+	price string
+}
+
+// formatGasEstimate renders an estimate in the units of its chain
+func formatGasEstimate(chainName, from, to, amount, token string, estimate gasEstimate, fee *walletchain.Fee) string {
+	markdown := "### Gas Estimate\n\n" +
+		"- **Chain**: `" + chainName + "`\n" +
+		"- **From**: `" + from + "`\n" +
+		"- **To**: `" + to + "`\n" +
+		"- **Amount**: `" + amount + "`\n"
+	if token != "" {
+		markdown += "- **Token**: `" + token + "`\n"
+	}
+	if chainName == "solana" {
+		markdown += fmt.Sprintf("- **Compute Units**: `%d`\n", estimate.limit) +
+			"- **Compute Unit Price**: `" + estimate.price + " micro-lamports`\n"
+	} else {
+		markdown += fmt.Sprintf("- **Gas Limit**: `%d`\n", estimate.limit) +
+			"- **Gas Price**: `" + estimate.price + " gwei`\n"
+	}
+	return markdown + formatFee("Estimated Fee", fee)
+}
@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetGasEstimateTool(t *testing.T) {
+	const (
+		ethFrom = "0x1111111111111111111111111111111111111111"
+		ethTo   = "0x2222222222222222222222222222222222222222"
+		solFrom = "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+		solTo   = "7EcDhSYGxXyscszYEp35KHN8vvw3svAuLKTzXwCFLtV"
+	)
+	manager := &wallet.MockWalletManager{}
+	manager.On("EstimateGas", mock.Anything, "ethereum", ethFrom, ethTo, "0.1", "").Return(uint64(21000), "20", nil)
+	manager.On("EstimateGas", mock.Anything, "solana", solFrom, solTo, "1", "").Return(uint64(150), "1", nil)
+	manager.On("EstimateGas", mock.Anything, "bsc", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(uint64(0), "", fmt.Errorf("invalid amount: abc"))
+	handler := NewGetGasEstimateTool(manager, nil).GetHandler()
+
+	result, err := handler(context.Background(), scheduleRequest("get_gas_estimate", map[string]any{"chain": "eth", "from": ethFrom, "to": ethTo, "amount": "0.1", "token": "ETH"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "### Gas Estimate")
+	assert.Contains(t, text, "- **Gas Limit**: `21000`")
+	assert.Contains(t, text, "- **Gas Price**: `20 gwei`")
+	assert.Contains(t, text, "- **Estimated Fee**: `0.00042 ETH`")
+	assert.NotContains(t, text, "- **Token**")
+
+	result, err = handler(context.Background(), scheduleRequest("get_gas_estimate", map[string]any{"chain": "sol", "from": solFrom, "to": solTo, "amount": "1"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Compute Units**: `150`")
+	assert.Contains(t, text, "- **Compute Unit Price**: `1 micro-lamports`")
+	assert.Contains(t, text, "SOL`")
+	assert.NotContains(t, text, "gwei")
+
+	result, err = handler(context.Background(), scheduleRequest("get_gas_estimate", map[string]any{"chain": "bsc", "from": ethFrom, "to": ethTo, "amount": "abc"}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	result, err = handler(context.Background(), scheduleRequest("get_gas_estimate", map[string]any{"chain": "eth", "from": ethFrom, "to": solTo, "amount": "0.1"}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "to")
+
+	result, err = handler(context.Background(), scheduleRequest("get_gas_estimate", map[string]any{"chain": "sol", "from": ethFrom, "to": solTo, "amount": "1"}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	manager.AssertNumberOfCalls(t, "EstimateGas", 3)
+}
@@ -0,0 +1,109 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// GetNFTsTool implements the MCP "get_nfts" tool for enumerating NFT holdings.
+type GetNFTsTool struct {
+	manager  wallet.IWalletManager
+	metadata *wallet.MetadataFetcher
+}
+
+// NewGetNFTsTool constructs a GetNFTsTool with the given wallet manager.
+func NewGetNFTsTool(manager wallet.IWalletManager) *GetNFTsTool {
+	return &GetNFTsTool{manager: manager}
+}
+
+// SetMetadataFetcher makes the tool resolve each NFT's metadata URI for its
+// name, description and image. Without a fetcher only the URI is listed.
+func (t *GetNFTsTool) SetMetadataFetcher(fetcher *wallet.MetadataFetcher) {
+	t.metadata = fetcher
+}
+
+// GetMeta returns the MCP tool definition for "get_nfts".
+func (t *GetNFTsTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("get_nfts",
+		mcp.WithDescription("List ERC-721/ERC-1155 or Solana (Metaplex) NFTs held by an address"),
+		mcp.WithString("chain",
+			mcp.Required(),
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol"),
+		),
+		mcp.WithString("address",
+			mcp.Required(),
+			mcp.Description("Owner address"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "get_nfts" tool.
+func (t *GetNFTsTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName, err := req.RequireString("chain")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("chain")), nil
+		}
+		address, err := req.RequireString("address")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("address")), nil
+		}
+
+		normalizedChain, err := toolutils.NormalizeChainName(chainName)
+		if err != nil {
+			if appErr, ok := err.(*errors.Error); ok {
+				return toolutils.FormatErrorResult(appErr), nil
+			}
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+		if !isValidAddressForChain(normalizedChain, address) {
+			return toolutils.FormatErrorResult(errors.InvalidAddressError(address, normalizedChain)), nil
+		}
+
+		nfts, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) ([]*wallet.NFTAsset, error) {
+			return t.manager.GetNFTs(attemptCtx, normalizedChain, address)
+		})
+		if err != nil {
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("get nfts", err)), nil
+		}
+
+		if t.metadata != nil {
+			t.metadata.EnrichNFTs(ctx, nfts)
+		}
+
+		markdown := "### NFT Holdings\n\n"
+		if len(nfts) == 0 {
+			markdown += fmt.Sprintf("No NFTs found for `%s` on `%s`.\n", address, normalizedChain)
+			return mcp.NewToolResultText(markdown), nil
+		}
+
+		markdown += fmt.Sprintf("Found %d NFTs for address `%s` on `%s`:\n\n", len(nfts), address, normalizedChain)
+		for i, nft := range nfts {
+			markdown += fmt.Sprintf("#### NFT %d\n", i+1)
+			if nft.Name != "" {
+				markdown += fmt.Sprintf("- **Name**: `%s`\n", nft.Name)
+			}
+			markdown += fmt.Sprintf("- **Collection**: `%s`\n", nft.Collection)
+			markdown += fmt.Sprintf("- **Standard**: `%s`\n", nft.Standard)
+			markdown += fmt.Sprintf("- **Contract**: `%s`\n", nft.ContractAddress)
+			markdown += fmt.Sprintf("- **Token ID**: `%s`\n", nft.TokenID)
+			markdown += fmt.Sprintf("- **Amount**: `%s`\n", nft.Amount)
+			if nft.Description != "" {
+				markdown += fmt.Sprintf("- **Description**: %s\n", nft.Description)
+			}
+			if nft.Image != "" {
+				markdown += fmt.Sprintf("- **Image**: `%s`\n", nft.Image)
+			}
+			markdown += fmt.Sprintf("- **Metadata URI**: `%s`\n\n", nft.MetadataURI)
+		}
+
+		return mcp.NewToolResultText(markdown), nil
+	}
+}
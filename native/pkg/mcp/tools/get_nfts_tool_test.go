@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNFTsToolHandlerSuccess(t *testing.T) {
+	owner := "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+	mockManager := &wallet.MockWalletManager{}
+	mockManager.On("GetNFTs", mock.Anything, "ethereum", owner).Return([]*wallet.NFTAsset{
+		{
+			Chain:           "ethereum",
+			Standard:        wallet.NFTStandardERC721,
+			ContractAddress: "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D",
+			TokenID:         "1234",
+			Collection:      "BoredApeYachtClub",
+			MetadataURI:     "ipfs://example/1234",
+			Amount:          "1",
+			Owner:           owner,
+		},
+	}, nil)
+
+	handler := NewGetNFTsTool(mockManager).GetHandler()
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "get_nfts",
+			Arguments: map[string]any{
+				"chain":   "ETH",
+				"address": owner,
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "### NFT Holdings")
+	assert.Contains(t, textContent.Text, "**Token ID**: `1234`")
+	assert.Contains(t, textContent.Text, "**Collection**: `BoredApeYachtClub`")
+	assert.Contains(t, textContent.Text, "**Metadata URI**: `ipfs://example/1234`")
+	mockManager.AssertExpectations(t)
+}
+
+func TestGetNFTsToolHandlerInvalidAddress(t *testing.T) {
+	handler := NewGetNFTsTool(&wallet.MockWalletManager{}).GetHandler()
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "get_nfts",
+			Arguments: map[string]any{
+				"chain":   "ethereum",
+				"address": "not-an-address",
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestGetNFTsToolHandlerResolvesMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"Mad Lad #8420","description":"A mad lad","image":"ipfs://QmImage/8420.png"}`))
+	}))
+	defer server.Close()
+
+	owner := "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK"
+	mockManager := &wallet.MockWalletManager{}
+	mockManager.On("GetNFTs", mock.Anything, "solana", owner).Return([]*wallet.NFTAsset{
+		{
+			Chain:           "solana",
+			Standard:        wallet.NFTStandardSPL,
+			ContractAddress: "7Xawhbbxtsqgmw3mhFYnyeBXMg6A9StRC3hPVrqRvCCt",
+			TokenID:         "7Xawhbbxtsqgmw3mhFYnyeBXMg6A9StRC3hPVrqRvCCt",
+			Collection:      "Mad Lads",
+			MetadataURI:     server.URL + "/8420.json",
+			Amount:          "1",
+			Owner:           owner,
+		},
+	}, nil)
+
+	tool := NewGetNFTsTool(mockManager)
+	tool.SetMetadataFetcher(wallet.NewMetadataFetcher(config.MetadataConfig{IPFSGateway: "https://gateway.example/ipfs/"}))
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_nfts",
+			Arguments: map[string]any{"chain": "solana", "address": owner},
+		},
+	}
+
+	result, err := tool.GetHandler()(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "**Name**: `Mad Lad #8420`")
+	assert.Contains(t, textContent.Text, "**Description**: A mad lad")
+	assert.Contains(t, textContent.Text, "**Image**: `https://gateway.example/ipfs/QmImage/8420.png`")
+}
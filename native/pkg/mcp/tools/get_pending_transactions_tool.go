@@ -5,6 +5,7 @@ import (
 	"context"
 	
 	"fmt"
+	"time"
 
 	"github.com/algonius/algonius-wallet/native/pkg/errors"
 	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
@@ -15,7 +16,8 @@ import (
 
 // GetPendingTransactionsTool implements the MCP "get_pending_transactions" tool for querying pending transactions.
 type GetPendingTransactionsTool struct {
-	manager wallet.IWalletManager
+	manager   wallet.IWalletManager
+	priceFeed wallet.PriceFeed
 }
 
 // NewGetPendingTransactionsTool constructs a GetPendingTransactionsTool with the given wallet manager.
@@ -23,6 +25,12 @@ func NewGetPendingTransactionsTool(manager wallet.IWalletManager) *GetPendingTra
 	return &GetPendingTransactionsTool{manager: manager}
 }
 
+// SetPriceFeed sets the feed used to show each transaction's estimated USD
+// value at the current price; without one no value is shown
+func (t *GetPendingTransactionsTool) SetPriceFeed(priceFeed wallet.PriceFeed) {
+	t.priceFeed = priceFeed
+}
+
 // GetMeta returns the MCP tool definition for "get_pending_transactions" as per the documented API schema.
 func (t *GetPendingTransactionsTool) GetMeta() mcp.Tool {
 	return mcp.NewTool("get_pending_transactions",
@@ -88,9 +96,16 @@ func (t *GetPendingTransactionsTool) GetHandler() server.ToolHandlerFunc {
 				markdown += fmt.Sprintf("- **Hash**: `%s`\n", tx.Hash)
 				markdown += fmt.Sprintf("- **Chain**: `%s`\n", tx.Chain)
 				markdown += fmt.Sprintf("- **From**: `%s`\n", tx.From)
+				if tx.FromLabel != "" {
+					markdown += fmt.Sprintf("- **From Label**: `%s`\n", tx.FromLabel)
+				}
 				markdown += fmt.Sprintf("- **To**: `%s`\n", tx.To)
+				if tx.ToLabel != "" {
+					markdown += fmt.Sprintf("- **To Label**: `%s`\n", tx.ToLabel)
+				}
 				markdown += fmt.Sprintf("- **Amount**: `%s`\n", tx.Amount)
 				markdown += fmt.Sprintf("- **Token**: `%s`\n", tx.Token)
+				markdown += formatEstimatedValue(ctx, t.priceFeed, tx.Chain, tx.Token, tx.Amount, time.Time{})
 				markdown += fmt.Sprintf("- **Type**: `%s`\n", tx.Type)
 				markdown += fmt.Sprintf("- **Status**: `%s`\n", tx.Status)
 				markdown += fmt.Sprintf("- **Confirmations**: `%d/%d`\n", tx.Confirmations, tx.RequiredConfirmations)
@@ -2,6 +2,8 @@ package tools
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -155,3 +157,65 @@ func TestGetPendingTransactionsToolHandler_NoTransactions(t *testing.T) {
 	assert.Contains(t, markdown, "### Pending Transactions")
 	assert.Contains(t, markdown, "No pending transactions found")
 }
+
+// staticUSDPriceFeed prices symbols from a fixed table
+type staticUSDPriceFeed map[string]float64
+
+func (f staticUSDPriceFeed) USDPrice(_ context.Context, symbol string) (float64, error) {
+	if price, ok := f[symbol]; ok {
+		return price, nil
+	}
+	return 0, assert.AnError
+}
+
+func TestGetPendingTransactionsToolHandler_EstimatedValue(t *testing.T) {
+	mockManager := &MockWalletManagerWithTransactions{
+		MockWalletManager: &wallet.MockWalletManager{},
+		mockTransactions: []*wallet.PendingTransaction{
+			{Hash: "0xpriced", Chain: "ethereum", Amount: "1.5", Token: "ETH", SubmittedAt: time.Now()},
+			{Hash: "0xunpriced", Chain: "ethereum", Amount: "10", Token: "PEPE", SubmittedAt: time.Now()},
+		},
+	}
+
+	tool := NewGetPendingTransactionsTool(mockManager)
+	tool.SetPriceFeed(staticUSDPriceFeed{"ETH": 2000})
+
+	result, err := tool.GetHandler()(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "get_pending_transactions"}})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+
+	assert.Contains(t, textContent.Text, "- **Estimated Value**: `~$3000.00 USD` (estimated, ETH at current price)")
+	// The unpriced token is listed without a value
+	assert.Equal(t, 1, strings.Count(textContent.Text, "Estimated Value"))
+	assert.Contains(t, textContent.Text, "0xunpriced")
+}
+
+// offlinePriceFeed is a price feed whose source is down
+type offlinePriceFeed struct{}
+
+func (offlinePriceFeed) USDPrice(_ context.Context, _ string) (float64, error) {
+	return 0, fmt.Errorf("%w: paused after 3 failed quotes, retrying in 20s", wallet.ErrPriceFeedUnavailable)
+}
+
+func TestGetPendingTransactionsToolHandler_PriceFeedDown(t *testing.T) {
+	mockManager := &MockWalletManagerWithTransactions{
+		MockWalletManager: &wallet.MockWalletManager{},
+		mockTransactions: []*wallet.PendingTransaction{
+			{Hash: "0xpriced", Chain: "ethereum", Amount: "1.5", Token: "ETH", SubmittedAt: time.Now()},
+		},
+	}
+
+	tool := NewGetPendingTransactionsTool(mockManager)
+	tool.SetPriceFeed(offlinePriceFeed{})
+
+	result, err := tool.GetHandler()(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "get_pending_transactions"}})
+	require.NoError(t, err)
+	require.False(t, result.IsError, "a pricing outage must not fail the tool")
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+
+	assert.Contains(t, textContent.Text, "0xpriced")
+	assert.Contains(t, textContent.Text, "- **Estimated Value**: `unavailable` (price feed unavailable: paused after 3 failed quotes, retrying in 20s)")
+}
@@ -0,0 +1,98 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// GetSpendableBalanceTool implements the MCP "get_spendable_balance" tool, which reports
+// what can actually be sent once fees and the native reserve are accounted for.
+type GetSpendableBalanceTool struct {
+	manager wallet.IWalletManager
+}
+
+// NewGetSpendableBalanceTool constructs a GetSpendableBalanceTool with the given wallet manager.
+func NewGetSpendableBalanceTool(manager wallet.IWalletManager) *GetSpendableBalanceTool {
+	return &GetSpendableBalanceTool{manager: manager}
+}
+
+// GetMeta returns the MCP tool definition for "get_spendable_balance".
+func (t *GetSpendableBalanceTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("get_spendable_balance",
+		mcp.WithDescription("Get the maximum amount of a token that can be sent after subtracting the estimated transfer fee and the native gas reserve. For non-native tokens, also reports whether the native balance covers the fee"),
+		mcp.WithString("chain",
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol (optional, defaults to wallet.default_chain)"),
+		),
+		mcp.WithString("address",
+			mcp.Required(),
+			mcp.Description("Account address to check"),
+		),
+		mcp.WithString("token",
+			mcp.Description("Optional token symbol/contract (native token when omitted)"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "get_spendable_balance" tool.
+func (t *GetSpendableBalanceTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName := toolutils.ChainOrDefault(t.manager, req.GetString("chain", ""))
+		address, err := req.RequireString("address")
+		if err != nil || strings.TrimSpace(address) == "" {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("address")), nil
+		}
+		token := req.GetString("token", "")
+
+		normalizedChain, err := toolutils.NormalizeChainName(chainName)
+		if err != nil {
+			if appErr, ok := err.(*errors.Error); ok {
+				return toolutils.FormatErrorResult(appErr), nil
+			}
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+
+		spendable, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (*chain.SpendableBalance, error) {
+			return t.manager.GetSpendableBalance(attemptCtx, normalizedChain, address, token)
+		})
+		if err != nil {
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("get spendable balance", err)), nil
+		}
+
+		return mcp.NewToolResultText(formatSpendableBalanceMarkdown(spendable)), nil
+	}
+}
+
+// formatSpendableBalanceMarkdown renders a SpendableBalance for the agent
+func formatSpendableBalanceMarkdown(sb *chain.SpendableBalance) string {
+	markdown := "### Spendable Balance\n\n" +
+		"- **Chain**: `" + sb.Chain + "`\n" +
+		"- **Address**: `" + sb.Address + "`\n" +
+		"- **Token**: `" + sb.Token + "`\n" +
+		"- **Balance**: `" + sb.Balance + "`\n"
+	if !sb.IsNative {
+		markdown += "- **Native Balance**: `" + sb.NativeBalance + " " + sb.NativeToken + "`\n"
+	}
+	if sb.Fee != nil {
+		markdown += formatFee("Estimated Fee", sb.Fee)
+	} else {
+		markdown += "- **Estimated Fee**: `" + sb.EstimatedFee + " " + sb.NativeToken + "`\n"
+	}
+	markdown += "- **Gas Reserve**: `" + sb.Reserve + " " + sb.NativeToken + "`\n" +
+		"- **Spendable**: `" + sb.Spendable + "`\n"
+
+	if sb.FeeCovered {
+		markdown += "- **Fee Covered**: `yes`\n"
+	} else {
+		markdown += "- **Fee Covered**: `no`\n\n" +
+			"> Not enough " + sb.NativeToken + " to pay the transfer fee without dipping into the gas reserve. Top up " + sb.NativeToken + " before sending.\n"
+	}
+	return markdown
+}
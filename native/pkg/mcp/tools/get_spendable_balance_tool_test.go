@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWalletManagerForSpendable struct {
+	*wallet.MockWalletManager
+	lastChain string
+	lastToken string
+	result    *chain.SpendableBalance
+}
+
+func (m *mockWalletManagerForSpendable) GetSpendableBalance(ctx context.Context, chainName, address, token string) (*chain.SpendableBalance, error) {
+	m.lastChain = chainName
+	m.lastToken = token
+	return m.result, nil
+}
+
+func spendableRequest(args map[string]any) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_spendable_balance",
+			Arguments: args,
+		},
+	}
+}
+
+func TestGetSpendableBalanceToolNative(t *testing.T) {
+	mockManager := &mockWalletManagerForSpendable{
+		MockWalletManager: &wallet.MockWalletManager{},
+		result: &chain.SpendableBalance{
+			Chain: "ethereum", Address: "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+			Token: "ETH", NativeToken: "ETH", IsNative: true,
+			Balance: "1", NativeBalance: "1", Reserve: "0.005", EstimatedFee: "0.00042",
+			Fee:       &chain.Fee{Amount: "0.00042", Symbol: "ETH", SmallestUnit: "420000000000000", Unit: "wei"},
+			Spendable: "0.99458", FeeCovered: true,
+		},
+	}
+	handler := NewGetSpendableBalanceTool(mockManager).GetHandler()
+
+	result, err := handler(context.Background(), spendableRequest(map[string]any{
+		"chain":   "eth",
+		"address": "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, "ethereum", mockManager.lastChain)
+	assert.Equal(t, "", mockManager.lastToken)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "### Spendable Balance")
+	assert.Contains(t, textContent.Text, "- **Spendable**: `0.99458`")
+	assert.Contains(t, textContent.Text, "- **Estimated Fee**: `0.00042 ETH`")
+	assert.Contains(t, textContent.Text, "- **Estimated Fee (wei)**: `420000000000000`")
+	assert.Contains(t, textContent.Text, "- **Fee Covered**: `yes`")
+	assert.NotContains(t, textContent.Text, "Native Balance")
+}
+
+func TestGetSpendableBalanceToolTokenWithoutGas(t *testing.T) {
+	mockManager := &mockWalletManagerForSpendable{
+		MockWalletManager: &wallet.MockWalletManager{},
+		result: &chain.SpendableBalance{
+			Chain: "bsc", Address: "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+			Token: "0x55d398326f99059fF775485246999027B3197955", NativeToken: "BNB",
+			Balance: "250.5", NativeBalance: "0.001", Reserve: "0.002", EstimatedFee: "0.0013",
+			Spendable: "250.5",
+		},
+	}
+	handler := NewGetSpendableBalanceTool(mockManager).GetHandler()
+
+	result, err := handler(context.Background(), spendableRequest(map[string]any{
+		"chain":   "bsc",
+		"address": "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+		"token":   "0x55d398326f99059fF775485246999027B3197955",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "- **Native Balance**: `0.001 BNB`")
+	assert.Contains(t, textContent.Text, "- **Fee Covered**: `no`")
+	assert.Contains(t, textContent.Text, "Top up BNB")
+}
+
+func TestGetSpendableBalanceToolMissingAddress(t *testing.T) {
+	handler := NewGetSpendableBalanceTool(&mockWalletManagerForSpendable{MockWalletManager: &wallet.MockWalletManager{}}).GetHandler()
+
+	result, err := handler(context.Background(), spendableRequest(map[string]any{"chain": "solana"}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,90 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// GetTokenInfoTool implements the MCP "get_token_info" tool, describing a token
+// from the imported or bundled token lists including its logo.
+type GetTokenInfoTool struct{}
+
+// NewGetTokenInfoTool constructs a GetTokenInfoTool.
+func NewGetTokenInfoTool() *GetTokenInfoTool {
+	return &GetTokenInfoTool{}
+}
+
+// GetMeta returns the MCP tool definition for "get_token_info".
+func (t *GetTokenInfoTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("get_token_info",
+		mcp.WithDescription("Get address, symbol, decimals and logo of a token from the configured and bundled token lists"),
+		mcp.WithString("chain",
+			mcp.Required(),
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol"),
+		),
+		mcp.WithString("token",
+			mcp.Description("Token contract or mint address, or a token symbol from the token lists (optional, native token if not provided)"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "get_token_info" tool.
+func (t *GetTokenInfoTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName, err := req.RequireString("chain")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("chain")), nil
+		}
+		normalizedChain, err := toolutils.NormalizeChainName(chainName)
+		if err != nil {
+			if appErr, ok := err.(*errors.Error); ok {
+				return toolutils.FormatErrorResult(appErr), nil
+			}
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+
+		token := strings.TrimSpace(req.GetString("token", ""))
+		if token != "" && !strings.EqualFold(token, wallet.NativeTokenSymbol(normalizedChain)) && !isValidAddressForChain(normalizedChain, token) {
+			listed, ok := wallet.ResolveTokenSymbol(normalizedChain, token)
+			if !ok {
+				return toolutils.FormatErrorResult(errors.InvalidTokenAddressError(token, normalizedChain)), nil
+			}
+			token = listed.Address
+		}
+
+		info := wallet.LookupTokenMetadata(normalizedChain, token)
+
+		// Only the fields the token lists know are shown
+		markdown := "### Token Info\n\n" +
+			fmt.Sprintf("- **Chain**: `%s`\n", info.Chain)
+		if info.Address != "" {
+			markdown += fmt.Sprintf("- **Address**: `%s`\n", info.Address)
+		}
+		if info.Symbol != "" {
+			markdown += fmt.Sprintf("- **Symbol**: `%s`\n", info.Symbol)
+		}
+		if info.Description != "" {
+			markdown += fmt.Sprintf("- **Description**: %s\n", info.Description)
+		}
+		if info.Known {
+			markdown += fmt.Sprintf("- **Decimals**: `%d`\n", info.Decimals)
+		}
+		markdown += fmt.Sprintf("- **Native**: `%t`\n", info.IsNative)
+		if info.LogoURI != "" {
+			markdown += fmt.Sprintf("- **Logo**: `%s`\n", info.LogoURI)
+		}
+		if !info.Known {
+			markdown += "\nThis token is not in the bundled token list or any imported list; symbol, decimals and logo are unavailable.\n"
+		}
+
+		return mcp.NewToolResultText(markdown), nil
+	}
+}
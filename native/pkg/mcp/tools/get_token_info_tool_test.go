@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tokenInfoRequest(args map[string]any) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_token_info",
+			Arguments: args,
+		},
+	}
+}
+
+func TestGetTokenInfoTool(t *testing.T) {
+	handler := NewGetTokenInfoTool().GetHandler()
+
+	result, err := handler(context.Background(), tokenInfoRequest(map[string]any{
+		"chain": "bsc",
+		"token": "0x55d398326f99059ff775485246999027b3197955",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "- **Symbol**: `USDT`")
+	assert.Contains(t, textContent.Text, "- **Decimals**: `18`")
+	assert.Contains(t, textContent.Text, "smartchain/assets/0x55d398326f99059fF775485246999027B3197955/logo.png")
+
+	result, err = handler(context.Background(), tokenInfoRequest(map[string]any{"chain": "sol"}))
+	require.NoError(t, err)
+	textContent, _ = mcp.AsTextContent(result.Content[0])
+	assert.Contains(t, textContent.Text, "- **Symbol**: `SOL`")
+	assert.Contains(t, textContent.Text, "- **Native**: `true`")
+
+	// Unknown tokens are described as far as possible rather than rejected
+	result, err = handler(context.Background(), tokenInfoRequest(map[string]any{
+		"chain": "ethereum",
+		"token": "0x1111111111111111111111111111111111111111",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	textContent, _ = mcp.AsTextContent(result.Content[0])
+	assert.Contains(t, textContent.Text, "not in the bundled token list")
+	assert.NotContains(t, textContent.Text, "**Logo**")
+
+	// Symbols resolve through the token lists
+	result, err = handler(context.Background(), tokenInfoRequest(map[string]any{"chain": "ethereum", "token": "usdc"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	textContent, _ = mcp.AsTextContent(result.Content[0])
+	assert.Contains(t, textContent.Text, "- **Address**: `0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48`")
+
+	result, err = handler(context.Background(), tokenInfoRequest(map[string]any{"chain": "ethereum", "token": "not-an-address"}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,249 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// tradingChains are the chains get_trading_config reports, in output order
+var tradingChains = []string{"ethereum", "bsc", "solana"}
+
+// GetTradingConfigTool implements the MCP "get_trading_config" tool. It shows
+// the trading parameters the host is running with: per-chain retry, gas and
+// broadcast settings and the swap defaults, with a fingerprint that changes
+// whenever any of them does.
+type GetTradingConfigTool struct {
+	config *config.Config
+}
+
+// NewGetTradingConfigTool constructs a GetTradingConfigTool reporting cfg; a
+// nil cfg reports the defaults
+func NewGetTradingConfigTool(cfg *config.Config) *GetTradingConfigTool {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return &GetTradingConfigTool{config: cfg}
+}
+
+// GetMeta returns the MCP tool definition for "get_trading_config".
+func (t *GetTradingConfigTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("get_trading_config",
+		mcp.WithDescription("Show the effective trading parameters: per-chain retry settings (max retries, slippage increment, max total slippage, gas strategy), gas and fee settings, broadcast channels, and the swap slippage and partial fill defaults. The fingerprint changes whenever any reported setting does, so it confirms that a configuration change took effect"),
+		mcp.WithString("chain",
+			mcp.Description("Only show this chain ('ethereum', 'bsc' or 'solana'; optional)"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "get_trading_config" tool.
+func (t *GetTradingConfigTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chains := tradingChains
+		if chain := req.GetString("chain", ""); chain != "" {
+			chainName, err := toolutils.NormalizeChainName(chain)
+			if err != nil {
+				return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+			}
+			chains = []string{chainName}
+		}
+
+		settings := newTradingSettings(t.config)
+		fingerprint, err := settings.fingerprint()
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.InternalError("fingerprint trading config", err)), nil
+		}
+		return mcp.NewToolResultText(formatTradingConfigMarkdown(settings, chains, fingerprint)), nil
+	}
+}
+
+// tradingSettings are the reported settings with defaults filled in
+type tradingSettings struct {
+	Swap   swapSettings                    `json:"swap"`
+	Chains map[string]chainTradingSettings `json:"chains"`
+}
+
+type swapSettings struct {
+	DefaultSlippage  float64 `json:"default_slippage"`
+	PartialFill      string  `json:"partial_fill"`
+	AutoWrap         bool    `json:"auto_wrap"`
+	OKXBroadcast     string  `json:"okx_broadcast_channel,omitempty"`
+	ProviderTimeout  string  `json:"provider_timeout"`
+	FanOutDeadline   string  `json:"fan_out_deadline"`
+	MaxConcurrency   int     `json:"max_concurrency"`
+	DecisionHistory  int     `json:"decision_history"`
+	CompositeEnabled bool    `json:"composite_enabled"`
+}
+
+type chainTradingSettings struct {
+	Enabled     bool                       `json:"enabled"`
+	GasStrategy string                     `json:"gas_strategy,omitempty"`
+	MaxFee      float64                    `json:"max_fee,omitempty"`
+	Multipliers config.EstimateMultipliers `json:"multipliers"`
+	Retry       *config.RetryConfig        `json:"retry,omitempty"`
+	Commitment  string                     `json:"commitment,omitempty"`
+	Broadcast   *config.BroadcastConfig    `json:"broadcast,omitempty"`
+	Jito        *jitoTradingSettings       `json:"jito,omitempty"`
+}
+
+type jitoTradingSettings struct {
+	Enabled         bool   `json:"enabled"`
+	TipStrategy     string `json:"tip_strategy"`
+	BaseTipLamports uint64 `json:"base_tip_lamports"`
+	MaxTipLamports  uint64 `json:"max_tip_lamports"`
+}
+
+// newTradingSettings collects the trading settings of cfg. Unset gas
+// strategies are reported as the standard strategy they fall back to.
+func newTradingSettings(cfg *config.Config) tradingSettings {
+	gasStrategy := func(strategy string) string {
+		if strategy == "" {
+			return walletchain.GasStrategyStandard
+		}
+		return strategy
+	}
+	solana := cfg.Chains.Solana
+	retry := solana.Retry
+	broadcast := config.BroadcastConfig{Channel: solana.Broadcast.Channel, Channels: append([]config.BroadcastChannel(nil), solana.Broadcast.Channels...)}
+	sort.SliceStable(broadcast.Channels, func(i, j int) bool { return broadcast.Channels[i].Priority < broadcast.Channels[j].Priority })
+	for i := range broadcast.Channels {
+		// Channel settings may carry credentials
+		broadcast.Channels[i].Config = nil
+	}
+
+	settings := tradingSettings{
+		Swap: swapSettings{
+			DefaultSlippage:  defaultSwapSlippage,
+			PartialFill:      cfg.DEX.PartialFill,
+			AutoWrap:         cfg.DEX.AutoWrap,
+			ProviderTimeout:  formatFanOutBound(cfg.DEX.Composite.ProviderTimeout),
+			FanOutDeadline:   formatFanOutBound(cfg.DEX.Composite.Timeout),
+			MaxConcurrency:   cfg.DEX.Composite.MaxConcurrency,
+			DecisionHistory:  cfg.DEX.Composite.DecisionHistory,
+			CompositeEnabled: cfg.DEX.Composite.Enabled,
+		},
+		Chains: map[string]chainTradingSettings{
+			"ethereum": {
+				Enabled:     cfg.Chains.Ethereum.Enabled,
+				GasStrategy: gasStrategy(cfg.Chains.Ethereum.GasStrategy),
+				MaxFee:      cfg.Chains.Ethereum.MaxFee,
+				Multipliers: cfg.Chains.EstimateMultipliers("ethereum"),
+			},
+			"bsc": {
+				Enabled:     cfg.Chains.BSC.Enabled,
+				GasStrategy: gasStrategy(cfg.Chains.BSC.GasStrategy),
+				MaxFee:      cfg.Chains.BSC.MaxFee,
+				Multipliers: cfg.Chains.EstimateMultipliers("bsc"),
+			},
+			"solana": {
+				Enabled:     solana.Enabled,
+				Multipliers: cfg.Chains.EstimateMultipliers("solana"),
+				Retry:       &retry,
+				Commitment:  solana.Commitment,
+				Broadcast:   &broadcast,
+				Jito: &jitoTradingSettings{
+					Enabled:         solana.Jito.Enabled,
+					TipStrategy:     solana.Jito.TipStrategy,
+					BaseTipLamports: solana.Jito.BaseTipLamports,
+					MaxTipLamports:  solana.Jito.MaxTipLamports,
+				},
+			},
+		},
+	}
+	if settings.Swap.PartialFill == "" {
+		settings.Swap.PartialFill = "revert"
+	}
+	if cfg.DEX.OKEx.Enabled {
+		settings.Swap.OKXBroadcast = cfg.DEX.OKEx.BroadcastChannel
+	}
+	return settings
+}
+
+// fingerprint hashes every reported setting
+func (s tradingSettings) fingerprint() (string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6]), nil
+}
+
+// formatTradingConfigMarkdown renders the swap defaults and the settings of chains
+func formatTradingConfigMarkdown(settings tradingSettings, chains []string, fingerprint string) string {
+	swap := settings.Swap
+	markdown := "### Trading Configuration\n\n" +
+		"- **Fingerprint**: `" + fingerprint + "`\n" +
+		"\n#### Swaps\n\n" +
+		"- **Default Slippage**: `" + formatPercent(swap.DefaultSlippage*100) + "%` (when swap_tokens is called without slippage)\n" +
+		"- **Partial Fill**: `" + swap.PartialFill + "`\n" +
+		fmt.Sprintf("- **Auto Wrap**: `%t`\n", swap.AutoWrap) +
+		"- **Provider Timeout**: `" + swap.ProviderTimeout + "`\n" +
+		"- **Fan-out Deadline**: `" + swap.FanOutDeadline + "`\n"
+	if swap.MaxConcurrency > 0 {
+		markdown += fmt.Sprintf("- **Max Concurrency**: `%d`\n", swap.MaxConcurrency)
+	} else {
+		markdown += "- **Max Concurrency**: `unbounded`\n"
+	}
+	if swap.OKXBroadcast != "" {
+		markdown += "- **OKX Broadcast Channel**: `" + swap.OKXBroadcast + "`\n"
+	}
+
+	for _, name := range chains {
+		chain := settings.Chains[name]
+		markdown += "\n#### " + name + "\n\n" +
+			fmt.Sprintf("- **Enabled**: `%t`\n", chain.Enabled)
+		if chain.GasStrategy != "" {
+			markdown += "- **Gas Strategy**: `" + chain.GasStrategy + "`\n"
+			if chain.MaxFee > 0 {
+				markdown += "- **Max Fee**: `" + formatGwei(chain.MaxFee) + " gwei`\n"
+			} else {
+				markdown += "- **Max Fee**: `none`\n"
+			}
+			markdown += fmt.Sprintf("- **Estimate Multipliers**: `gas limit %gx, fee %gx`\n", chain.Multipliers.Limit, chain.Multipliers.Fee)
+		} else {
+			markdown += fmt.Sprintf("- **Estimate Multipliers**: `compute units %gx, priority fee %gx`\n", chain.Multipliers.Limit, chain.Multipliers.Fee)
+		}
+		if chain.Commitment != "" {
+			markdown += "- **Commitment**: `" + chain.Commitment + "`\n"
+		}
+		if retry := chain.Retry; retry != nil {
+			markdown += fmt.Sprintf("- **Max Retries**: `%d`\n", retry.MaxRetries) +
+				fmt.Sprintf("- **Slippage Increment**: `%d bps` per retry\n", retry.SlippageIncrementBps) +
+				fmt.Sprintf("- **Max Total Slippage**: `%d bps`\n", retry.MaxTotalSlippageBps) +
+				"- **Base Retry Delay**: `" + retry.BaseRetryDelay.String() + "`\n" +
+				"- **Retry Gas Strategy**: `" + retry.GasStrategy + "`\n"
+		} else {
+			markdown += "- **Retries**: none (failed transactions are not resubmitted)\n"
+		}
+		if broadcast := chain.Broadcast; broadcast != nil {
+			markdown += "- **Broadcast Channel**: `" + broadcast.Channel + "`\n"
+			for _, channel := range broadcast.Channels {
+				state := "enabled"
+				if !channel.Enabled {
+					state = "disabled"
+				}
+				markdown += fmt.Sprintf("  - `%s`: %s, priority %d\n", channel.Name, state, channel.Priority)
+			}
+		}
+		if jito := chain.Jito; jito != nil {
+			if jito.Enabled {
+				markdown += fmt.Sprintf("- **Jito**: `enabled` (%s tips, %d to %d lamports)\n", jito.TipStrategy, jito.BaseTipLamports, jito.MaxTipLamports)
+			} else {
+				markdown += "- **Jito**: `disabled`\n"
+			}
+		}
+	}
+	return markdown
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTradingConfigTool(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Chains.Ethereum.GasStrategy = ""
+	cfg.Chains.Ethereum.MaxFee = 150
+	cfg.Chains.Ethereum.GasLimitMultiplier = 1.2
+	cfg.Chains.Solana.Broadcast.Channels[2].Config = map[string]any{"auth_key": "secret"}
+
+	tool := NewGetTradingConfigTool(cfg)
+	assert.Equal(t, "get_trading_config", tool.GetMeta().Name)
+	handler := tool.GetHandler()
+
+	result, err := handler(context.Background(), scheduleRequest("get_trading_config", map[string]any{}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+
+	assert.Contains(t, text, "- **Default Slippage**: `0.5%`")
+	assert.Contains(t, text, "- **Partial Fill**: `revert`")
+	assert.Contains(t, text, "#### ethereum\n\n- **Enabled**: `true`\n- **Gas Strategy**: `standard`\n- **Max Fee**: `150 gwei`\n- **Estimate Multipliers**: `gas limit 1.2x, fee 1x`")
+	assert.Contains(t, text, "- **Max Retries**: `3`")
+	assert.Contains(t, text, "- **Slippage Increment**: `50 bps` per retry")
+	assert.Contains(t, text, "- **Max Total Slippage**: `1000 bps`")
+	assert.Contains(t, text, "- **Retry Gas Strategy**: `level_up`")
+	assert.Contains(t, text, "- **Broadcast Channel**: `solana-rpc`\n  - `solana-rpc`: enabled, priority 1\n  - `okex`: disabled, priority 2")
+	assert.NotContains(t, text, "secret")
+
+	fingerprint := regexp.MustCompile("- \\*\\*Fingerprint\\*\\*: `([0-9a-f]+)`").FindStringSubmatch(text)
+	require.Len(t, fingerprint, 2)
+
+	// A changed setting is visible on the next call and changes the fingerprint
+	cfg.Chains.Solana.Retry.MaxRetries = 5
+	result, err = handler(context.Background(), scheduleRequest("get_trading_config", map[string]any{"chain": "sol"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Max Retries**: `5`")
+	assert.NotContains(t, text, "#### ethereum", "chains are filtered")
+	assert.NotContains(t, text, fingerprint[1])
+
+	result, err = handler(context.Background(), scheduleRequest("get_trading_config", map[string]any{"chain": "tron"}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
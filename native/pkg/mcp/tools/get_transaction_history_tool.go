@@ -3,7 +3,9 @@ package tools
 
 import (
 	"context"
+	stdErrors "errors"
 	"fmt"
+	"strings"
 
 	"github.com/algonius/algonius-wallet/native/pkg/errors"
 	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
@@ -14,7 +16,8 @@ import (
 
 // GetTransactionHistoryTool implements the MCP "get_transaction_history" tool for querying transaction history.
 type GetTransactionHistoryTool struct {
-	manager wallet.IWalletManager
+	manager   wallet.IWalletManager
+	priceFeed wallet.PriceFeed
 }
 
 // NewGetTransactionHistoryTool constructs a GetTransactionHistoryTool with the given wallet manager.
@@ -22,6 +25,14 @@ func NewGetTransactionHistoryTool(manager wallet.IWalletManager) *GetTransaction
 	return &GetTransactionHistoryTool{manager: manager}
 }
 
+// SetPriceFeed sets the feed used to show each transaction's USD price and
+// estimated value at the time it was confirmed. Values are omitted when the
+// feed has no price for that time, or shown as unavailable when its price
+// history cannot be fetched.
+func (t *GetTransactionHistoryTool) SetPriceFeed(priceFeed wallet.PriceFeed) {
+	t.priceFeed = priceFeed
+}
+
 // GetMeta returns the MCP tool definition for "get_transaction_history" as per the documented API schema.
 func (t *GetTransactionHistoryTool) GetMeta() mcp.Tool {
 	return mcp.NewTool("get_transaction_history",
@@ -39,11 +50,17 @@ func (t *GetTransactionHistoryTool) GetMeta() mcp.Tool {
 		mcp.WithNumber("to_block",
 			mcp.Description("Optional ending block number"),
 		),
+		mcp.WithString("category",
+			mcp.Description("Optional comma-separated category filter (transfer, token_transfer, swap, approval, contract_call, nft_transfer)"),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("Optional Next Cursor of the previous page to continue from; pages stay consistent if the chain reorganizes in between"),
+		),
 	)
 }
 
 // GetHandler returns the handler function for the "get_transaction_history" tool.
-// The handler queries transaction history with optional filtering and pagination.
+// The handler queries transaction history with optional filtering and cursor pagination.
 func (t *GetTransactionHistoryTool) GetHandler() server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Parse required parameters
@@ -73,14 +90,34 @@ func (t *GetTransactionHistoryTool) GetHandler() server.ToolHandlerFunc {
 			toBlock = &blockNum
 		}
 
+		// Parse optional category filter
+		categories, err := wallet.NormalizeTxCategories(req.GetString("category", ""))
+		if err != nil {
+			toolErr := errors.ValidationError("category", err.Error())
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
+		filter := wallet.TransactionHistoryFilter{
+			Address:    address,
+			FromBlock:  fromBlock,
+			ToBlock:    toBlock,
+			Limit:      limit,
+			Categories: categories,
+			Cursor:     req.GetString("cursor", ""),
+		}
+
 		// Get transaction history from wallet manager
-		transactions, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) ([]*wallet.HistoricalTransaction, error) {
-			return t.manager.GetTransactionHistory(attemptCtx, address, fromBlock, toBlock, limit, 0)
+		page, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (*wallet.TransactionHistoryPage, error) {
+			return t.manager.GetTransactionHistoryPage(attemptCtx, filter)
 		})
 		if err != nil {
+			if stdErrors.Is(err, wallet.ErrInvalidHistoryCursor) {
+				return toolutils.FormatErrorResult(errors.ValidationError("cursor", err.Error())), nil
+			}
 			toolErr := toolutils.ClassifyError("get transaction history", err)
 			return toolutils.FormatErrorResult(toolErr), nil
 		}
+		transactions := page.Transactions
 
 		// Format response as markdown
 		markdown := "### Transaction History\n\n"
@@ -96,14 +133,31 @@ func (t *GetTransactionHistoryTool) GetHandler() server.ToolHandlerFunc {
 				markdown += fmt.Sprintf("- **Chain**: `%s`\n", tx.Chain)
 				markdown += fmt.Sprintf("- **Block**: `%d`\n", tx.BlockNumber)
 				markdown += fmt.Sprintf("- **From**: `%s`\n", tx.From)
+				if tx.FromLabel != "" {
+					markdown += fmt.Sprintf("- **From Label**: `%s`\n", tx.FromLabel)
+				}
 				markdown += fmt.Sprintf("- **To**: `%s`\n", tx.To)
+				if tx.ToLabel != "" {
+					markdown += fmt.Sprintf("- **To Label**: `%s`\n", tx.ToLabel)
+				}
 				markdown += fmt.Sprintf("- **Value**: `%s`\n", tx.Value)
 
 				if tx.TokenSymbol != "" && tx.TokenSymbol != "ETH" && tx.TokenSymbol != "BNB" {
 					markdown += fmt.Sprintf("- **Token**: `%s`\n", tx.TokenSymbol)
 				}
+				valueToken := tx.TokenSymbol
+				if valueToken == "" {
+					valueToken = tx.Token
+				}
+				if value, ok := wallet.EstimateUSDValue(ctx, t.priceFeed, tx.Chain, valueToken, tx.Value, tx.Timestamp); ok {
+					markdown += formatHistoricalPrice(value) + formatFiatValue(value, tx.Timestamp)
+				}
 
 				markdown += fmt.Sprintf("- **Type**: `%s`\n", tx.Type)
+				if tx.Category == "" {
+					tx.Category = wallet.ClassifyTransaction(tx)
+				}
+				markdown += fmt.Sprintf("- **Category**: `%s`\n", tx.Category)
 				markdown += fmt.Sprintf("- **Status**: `%s`\n", tx.Status)
 				markdown += fmt.Sprintf("- **Fee**: `%s`\n", tx.TransactionFee)
 				markdown += fmt.Sprintf("- **Confirmations**: `%d`\n", tx.Confirmations)
@@ -145,13 +199,23 @@ func (t *GetTransactionHistoryTool) GetHandler() server.ToolHandlerFunc {
 				markdown += "\n"
 			}
 
+			if len(categories) > 0 {
+				markdown += "---\n"
+				markdown += fmt.Sprintf("**Category Filter**: `%s`\n", strings.Join(categories, ", "))
+			}
+
 			// Add pagination info
-			if len(transactions) == limit {
+			if page.NextCursor != "" {
 				markdown += "---\n"
-				markdown += fmt.Sprintf("**Note**: Showing first %d transactions (more may be available)\n", limit)
+				markdown += fmt.Sprintf("**Next Cursor**: `%s`\n", page.NextCursor)
 			}
 		}
 
+		if len(page.Reorged) > 0 {
+			markdown += "---\n"
+			markdown += fmt.Sprintf("**Note**: chain reorganization detected on `%s` since the previous page; recent blocks were read again and transactions already returned were skipped\n", strings.Join(page.Reorged, ", "))
+		}
+
 		return mcp.NewToolResultText(markdown), nil
 	}
 }
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,11 +19,11 @@ type MockWalletManagerWithHistory struct {
 	shouldReturnError          bool
 }
 
-func (m *MockWalletManagerWithHistory) GetTransactionHistory(ctx context.Context, address string, fromBlock, toBlock *uint64, limit, offset int) ([]*wallet.HistoricalTransaction, error) {
+func (m *MockWalletManagerWithHistory) GetTransactionHistoryPage(ctx context.Context, filter wallet.TransactionHistoryFilter) (*wallet.TransactionHistoryPage, error) {
 	if m.shouldReturnError {
 		return nil, assert.AnError
 	}
-	return m.mockHistoricalTransactions, nil
+	return wallet.PageTransactionHistory(m.mockHistoricalTransactions, filter)
 }
 
 func TestGetTransactionHistoryToolMeta(t *testing.T) {
@@ -284,3 +285,194 @@ func TestGetTransactionHistoryToolHandler_ManagerError(t *testing.T) {
 	require.NotNil(t, result)
 	require.True(t, result.IsError)
 }
+
+func TestGetTransactionHistoryToolHandler_CategoryFilter(t *testing.T) {
+	mockTxs := []*wallet.HistoricalTransaction{
+		{
+			Hash:        "0xtransfer",
+			Chain:       "ethereum",
+			From:        "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+			To:          "0x8ba1f109551bD432803012645Hac136c22C4F9B",
+			Value:       "1.5",
+			TokenSymbol: "ETH",
+			Type:        "transfer",
+			Status:      "confirmed",
+		},
+		{
+			Hash:            "0xswap",
+			Chain:           "bsc",
+			From:            "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+			To:              "0x10ed43c718714eb63d5aa57b78b54704e256024e",
+			Value:           "0.1",
+			Type:            "swap",
+			Status:          "confirmed",
+			ContractAddress: "0x10ed43c718714eb63d5aa57b78b54704e256024e",
+			MethodName:      "swapExactETHForTokens",
+		},
+	}
+
+	mockManager := &MockWalletManagerWithHistory{
+		MockWalletManager:          &wallet.MockWalletManager{},
+		mockHistoricalTransactions: mockTxs,
+	}
+	handler := NewGetTransactionHistoryTool(mockManager).GetHandler()
+
+	req := mcp.CallToolRequest{}
+	req.Params = mcp.CallToolParams{
+		Name: "get_transaction_history",
+		Arguments: map[string]interface{}{
+			"address":  "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+			"category": "swap",
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "Found 1 transactions")
+	assert.Contains(t, textContent.Text, "**Category**: `swap`")
+	assert.NotContains(t, textContent.Text, "0xtransfer")
+
+	req.Params.Arguments = map[string]interface{}{
+		"address":  "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+		"category": "airdrop",
+	}
+	result, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+// historicalUSDPriceFeed prices symbols at any past time from a fixed table
+type historicalUSDPriceFeed struct {
+	staticUSDPriceFeed
+	past map[string]float64
+}
+
+func (f historicalUSDPriceFeed) USDPriceAt(_ context.Context, symbol string, _ time.Time) (float64, error) {
+	if price, ok := f.past[symbol]; ok {
+		return price, nil
+	}
+	return 0, assert.AnError
+}
+
+func TestGetTransactionHistoryToolHandler_EstimatedValue(t *testing.T) {
+	confirmedAt := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	mockManager := &MockWalletManagerWithHistory{
+		MockWalletManager: &wallet.MockWalletManager{},
+		mockHistoricalTransactions: []*wallet.HistoricalTransaction{
+			{Hash: "0xhistory", Chain: "ethereum", Value: "2", TokenSymbol: "ETH", Status: "confirmed", Timestamp: confirmedAt},
+		},
+	}
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{
+		Name:      "get_transaction_history",
+		Arguments: map[string]interface{}{"address": "0x123"},
+	}}
+
+	// Confirmed transactions are valued at the price when they were confirmed
+	tool := NewGetTransactionHistoryTool(mockManager)
+	tool.SetPriceFeed(historicalUSDPriceFeed{staticUSDPriceFeed{"ETH": 3000}, map[string]float64{"ETH": 1800}})
+	result, err := tool.GetHandler()(context.Background(), req)
+	require.NoError(t, err)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "- **Estimated Value**: `~$3600.00 USD` (estimated, ETH price at 2026-03-01 12:30 UTC)")
+
+	// A feed without historical prices never falls back to the current price
+	tool.SetPriceFeed(staticUSDPriceFeed{"ETH": 3000})
+	result, err = tool.GetHandler()(context.Background(), req)
+	require.NoError(t, err)
+	textContent, ok = mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.NotContains(t, textContent.Text, "Estimated Value")
+	assert.Contains(t, textContent.Text, "0xhistory")
+}
+
+// tokenHistoryPriceFeed prices any token at a past time, failing when err is set
+type tokenHistoryPriceFeed struct {
+	staticUSDPriceFeed
+	price float64
+	err   error
+}
+
+func (f tokenHistoryPriceFeed) GetHistoricalPrice(_ context.Context, _, _ string, _ time.Time) (float64, error) {
+	return f.price, f.err
+}
+
+func TestGetTransactionHistoryToolHandler_HistoricalPrice(t *testing.T) {
+	mockManager := &MockWalletManagerWithHistory{
+		MockWalletManager: &wallet.MockWalletManager{},
+		mockHistoricalTransactions: []*wallet.HistoricalTransaction{
+			{Hash: "0xhistory", Chain: "ethereum", Value: "2", TokenSymbol: "ETH", Status: "confirmed", Timestamp: time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)},
+		},
+	}
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{
+		Name:      "get_transaction_history",
+		Arguments: map[string]interface{}{"address": "0x123"},
+	}}
+
+	tool := NewGetTransactionHistoryTool(mockManager)
+	tool.SetPriceFeed(tokenHistoryPriceFeed{staticUSDPriceFeed: staticUSDPriceFeed{"ETH": 3000}, price: 1812.5})
+	result, err := tool.GetHandler()(context.Background(), req)
+	require.NoError(t, err)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "- **USD Price**: `$1812.5` (ETH at block time)")
+	assert.Contains(t, textContent.Text, "- **Estimated Value**: `~$3625.00 USD`")
+
+	// A price history that cannot be fetched is reported rather than omitted
+	tool.SetPriceFeed(tokenHistoryPriceFeed{staticUSDPriceFeed: staticUSDPriceFeed{"ETH": 3000}, err: assert.AnError})
+	result, err = tool.GetHandler()(context.Background(), req)
+	require.NoError(t, err)
+	textContent, ok = mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "- **USD Price**: `unavailable`")
+	assert.Contains(t, textContent.Text, "- **Estimated Value**: `unavailable`")
+}
+
+func TestGetTransactionHistoryToolHandler_Cursor(t *testing.T) {
+	address := "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+	baseTime := time.Now()
+	mockManager := &MockWalletManagerWithHistory{
+		MockWalletManager: &wallet.MockWalletManager{},
+		mockHistoricalTransactions: []*wallet.HistoricalTransaction{
+			{Hash: "0xnewer", Chain: "ethereum", BlockNumber: 101, From: address, Type: "transfer", Timestamp: baseTime},
+			{Hash: "0xolder", Chain: "ethereum", BlockNumber: 100, From: address, Type: "transfer", Timestamp: baseTime.Add(-time.Minute)},
+		},
+	}
+	handler := NewGetTransactionHistoryTool(mockManager).GetHandler()
+
+	req := mcp.CallToolRequest{}
+	req.Params = mcp.CallToolParams{
+		Name:      "get_transaction_history",
+		Arguments: map[string]interface{}{"address": address, "limit": 1},
+	}
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "0xnewer")
+	assert.NotContains(t, textContent.Text, "0xolder")
+
+	_, cursor, found := strings.Cut(textContent.Text, "**Next Cursor**: `")
+	require.True(t, found, "first page should have a next cursor")
+	cursor, _, _ = strings.Cut(cursor, "`")
+
+	req.Params.Arguments = map[string]interface{}{"address": address, "limit": 1, "cursor": cursor}
+	result, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	textContent, ok = mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "0xolder")
+	assert.NotContains(t, textContent.Text, "0xnewer")
+	assert.NotContains(t, textContent.Text, "Next Cursor")
+
+	req.Params.Arguments = map[string]interface{}{"address": address, "cursor": "bogus"}
+	result, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
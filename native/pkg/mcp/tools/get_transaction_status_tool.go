@@ -40,6 +40,14 @@ func NewGetTransactionStatusTool(manager wallet.IWalletManager, logger *zap.Logg
 	}
 }
 
+// SetChainFactory makes the tool look transactions up on the chains of factory,
+// so it shares their configured RPC endpoints instead of creating its own
+func (t *GetTransactionStatusTool) SetChainFactory(factory *chain.ChainFactory) {
+	t.getChainInterface = func(chainName string) (chain.IChain, error) {
+		return factory.GetChain(wallet.NormalizeChain(chainName))
+	}
+}
+
 // GetMeta returns the MCP tool definition for "get_transaction_status" as per the documented API schema.
 func (t *GetTransactionStatusTool) GetMeta() mcp.Tool {
 	return mcp.NewTool("get_transaction_status",
@@ -49,8 +57,12 @@ func (t *GetTransactionStatusTool) GetMeta() mcp.Tool {
 			mcp.Description("The hash of the transaction to check"),
 		),
 		mcp.WithString("chain",
-			mcp.Description("The blockchain network (optional, will try to detect if not provided)"),
+			mcp.Description("The blockchain network (optional, will try to detect if not provided; 0x hashes are looked up on ethereum, then bsc)"),
+		),
+		mcp.WithString("abi",
+			mcp.Description("Optional contract ABI (JSON) used to decode custom revert errors of a failed transaction"),
 		),
+		withCommitmentOption(),
 	)
 }
 
@@ -78,9 +90,14 @@ func (t *GetTransactionStatusTool) GetHandler() server.ToolHandlerFunc {
 			zap.String("transaction_hash", txHash),
 			zap.String("chain", chainName))
 
-		// If chain not provided, try to detect it
+		// If chain not provided, try to detect it. EVM chains share a hash format,
+		// so a hash not found on ethereum is looked up on bsc as well.
+		var fallbackChains []string
 		if chainName == "" {
 			chainName = t.detectChainFromHash(txHash)
+			if chainName == "ethereum" {
+				fallbackChains = []string{"bsc"}
+			}
 			if chainName == "" {
 				toolErr := errors.ValidationError("chain", "unable to detect chain from transaction hash, please specify chain parameter")
 				return toolutils.FormatErrorResult(toolErr), nil
@@ -102,14 +119,31 @@ func (t *GetTransactionStatusTool) GetHandler() server.ToolHandlerFunc {
 		// Get chain interface
 		chainInterface, err := t.getChainInterface(chainName)
 		if err != nil {
-			toolErr := errors.ValidationError("chain", fmt.Sprintf("unsupported chain: %s", chainName))
+			toolErr := errors.ValidationError("chain", err.Error())
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
+		ctx, toolErr := commitmentContext(ctx, req, chainName)
+		if toolErr != nil {
 			return toolutils.FormatErrorResult(toolErr), nil
 		}
 
 		// Check transaction status on blockchain
-		confirmation, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (*chain.TransactionConfirmation, error) {
-			return chainInterface.ConfirmTransaction(attemptCtx, txHash, 1) // Require at least 1 confirmation
-		})
+		confirmation, err := t.confirmTransaction(ctx, chainInterface, txHash)
+		for len(fallbackChains) > 0 && err != nil && isTransactionNotFound(err) {
+			fallbackChain := fallbackChains[0]
+			fallbackChains = fallbackChains[1:]
+			fallbackInterface, chainErr := t.getChainInterface(fallbackChain)
+			if chainErr != nil {
+				continue
+			}
+			t.logger.Debug("Transaction not found, trying the next EVM chain",
+				zap.String("transaction_hash", txHash),
+				zap.String("chain", fallbackChain))
+			if fallbackConfirmation, fallbackErr := t.confirmTransaction(ctx, fallbackInterface, txHash); fallbackErr == nil || !isTransactionNotFound(fallbackErr) {
+				chainName, confirmation, err = fallbackChain, fallbackConfirmation, fallbackErr
+			}
+		}
 		if err != nil {
 			t.logger.Error("Failed to check transaction status",
 				zap.String("transaction_hash", txHash),
@@ -117,8 +151,7 @@ func (t *GetTransactionStatusTool) GetHandler() server.ToolHandlerFunc {
 				zap.Error(err))
 
 			// Try to determine if it's a "not found" error
-			if strings.Contains(strings.ToLower(err.Error()), "not found") ||
-				strings.Contains(strings.ToLower(err.Error()), "does not exist") {
+			if isTransactionNotFound(err) {
 				markdown := fmt.Sprintf("### Transaction Status: Not Found ❓\n\n"+
 					"- **Transaction Hash**: `%s`\n"+
 					"- **Chain**: `%s`\n"+
@@ -141,10 +174,14 @@ func (t *GetTransactionStatusTool) GetHandler() server.ToolHandlerFunc {
 				"- **Confirmations**: `%d`\n"+
 				"- **Block Number**: `%d`\n"+
 				"- **Gas Used**: `%s`\n"+
-				"- **Transaction Fee**: `%s`\n"+
+				"%s"+
 				"- **Timestamp**: `%s`\n",
 				txHash, chainName, confirmation.Confirmations, confirmation.BlockNumber,
-				confirmation.GasUsed, confirmation.TransactionFee, confirmation.Timestamp.Format("2006-01-02 15:04:05 UTC"))
+				confirmation.GasUsed, formatConfirmationFee(confirmation), confirmation.Timestamp.Format("2006-01-02 15:04:05 UTC"))
+			if confirmation.Commitment != "" {
+				markdown += fmt.Sprintf("- **Commitment**: `%s`\n", confirmation.Commitment)
+			}
+			markdown += t.tokenBalanceChangesMarkdown(ctx, chainName, txHash)
 		} else if confirmation.Status == "pending" {
 			markdown = fmt.Sprintf("### Transaction Status: Pending ⏳\n\n"+
 				"- **Transaction Hash**: `%s`\n"+
@@ -157,9 +194,14 @@ func (t *GetTransactionStatusTool) GetHandler() server.ToolHandlerFunc {
 				"- **Transaction Hash**: `%s`\n"+
 				"- **Chain**: `%s`\n"+
 				"- **Status**: `failed`\n"+
-				"- **Transaction Fee**: `%s`\n"+
+				"%s"+
 				"- **Timestamp**: `%s`\n",
-				txHash, chainName, confirmation.TransactionFee, confirmation.Timestamp.Format("2006-01-02 15:04:05 UTC"))
+				txHash, chainName, formatConfirmationFee(confirmation), confirmation.Timestamp.Format("2006-01-02 15:04:05 UTC"))
+			reason, err := redecodeRevertReason(confirmation.RevertReason, req.GetString("abi", ""))
+			if err != nil {
+				return toolutils.FormatErrorResult(errors.ValidationError("abi", err.Error())), nil
+			}
+			markdown += formatRevertReasonMarkdown(reason)
 		} else {
 			markdown = fmt.Sprintf("### Transaction Status: %s\n\n"+
 				"- **Transaction Hash**: `%s`\n"+
@@ -173,6 +215,51 @@ func (t *GetTransactionStatusTool) GetHandler() server.ToolHandlerFunc {
 	}
 }
 
+// confirmTransaction checks the status of txHash, requiring at least one confirmation
+func (t *GetTransactionStatusTool) confirmTransaction(ctx context.Context, chainInterface chain.IChain, txHash string) (*chain.TransactionConfirmation, error) {
+	return toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (*chain.TransactionConfirmation, error) {
+		return chainInterface.ConfirmTransaction(attemptCtx, txHash, 1)
+	})
+}
+
+// isTransactionNotFound reports whether err says the chain does not know the transaction
+func isTransactionNotFound(err error) bool {
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "not found") || strings.Contains(message, "does not exist")
+}
+
+// tokenBalanceChangesMarkdown lists the token balance changes of a confirmed
+// transaction on chains that report them. Lookup failures only omit the section.
+func (t *GetTransactionStatusTool) tokenBalanceChangesMarkdown(ctx context.Context, chainName, txHash string) string {
+	if wallet.NormalizeChain(chainName) != "solana" {
+		return ""
+	}
+	deltas, err := t.manager.GetTokenBalanceDeltas(ctx, chainName, txHash)
+	if err != nil {
+		t.logger.Debug("Token balance changes unavailable",
+			zap.String("transaction_hash", txHash),
+			zap.Error(err))
+		return ""
+	}
+	if len(deltas) == 0 {
+		return ""
+	}
+
+	markdown := "\n#### Token Balance Changes\n"
+	for _, delta := range deltas {
+		token := delta.Mint
+		if info := wallet.LookupTokenMetadata("solana", delta.Mint); info.Known {
+			token = info.Symbol
+		}
+		markdown += fmt.Sprintf("- `%s %s` for owner `%s` (account `%s`, mint `%s`", delta.Delta, token, delta.Owner, delta.Account, delta.Mint)
+		if delta.Program != "" {
+			markdown += fmt.Sprintf(", %s", delta.Program)
+		}
+		markdown += fmt.Sprintf("): `%s` → `%s`\n", delta.PreAmount, delta.PostAmount)
+	}
+	return markdown
+}
+
 // detectChainFromHash attempts to determine the chain based on the transaction hash format
 func detectChainFromHash(txHash string) string {
 	// Ethereum-style hashes start with 0x and are 66 characters long (0x + 64 hex chars)
@@ -186,23 +273,32 @@ func detectChainFromHash(txHash string) string {
 		return "solana"
 	}
 
-	// BSC also uses Ethereum-style hashes
-	// We'll default to ethereum for 0x prefixed hashes
-	// A more sophisticated implementation might need additional context
+	// BSC also uses Ethereum-style hashes; the handler falls back to it when
+	// ethereum does not know the transaction
 
 	return "" // Unable to detect
 }
 
-// getChainInterface gets the appropriate chain interface for the given chain name
+// getChainInterface gets the appropriate chain interface for the given chain
+// name, for tools not given a shared chain factory
 func getChainInterface(chainName string) (chain.IChain, error) {
-	switch strings.ToLower(chainName) {
-	case "solana", "sol":
+	switch wallet.NormalizeChain(chainName) {
+	case "solana":
 		return chain.NewSolanaChainLegacy(), nil
-	case "ethereum", "eth":
+	case "ethereum":
 		return chain.NewETHChainLegacy(), nil
-	case "bsc", "binance smart chain":
+	case "bsc":
 		return chain.NewBSCChainLegacy(), nil
 	default:
 		return nil, fmt.Errorf("unsupported chain: %s", chainName)
 	}
 }
+
+// formatConfirmationFee renders the fee a transaction paid, in the shared fee
+// format when the chain reported one
+func formatConfirmationFee(confirmation *chain.TransactionConfirmation) string {
+	if confirmation.Fee != nil {
+		return formatFee("Transaction Fee", confirmation.Fee)
+	}
+	return fmt.Sprintf("- **Transaction Fee**: `%s`\n", confirmation.TransactionFee)
+}
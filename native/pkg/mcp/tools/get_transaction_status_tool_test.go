@@ -2,6 +2,10 @@ package tools
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -9,6 +13,7 @@ import (
 	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -430,4 +435,162 @@ func TestGetTransactionStatusToolHandler_TransactionNotFound(t *testing.T) {
 	require.NotNil(t, result)
 	// The current implementation will return an error, but in a real scenario,
 	// we would want it to return a "not found" status
-}
\ No newline at end of file
+}
+func TestGetTransactionStatusToolHandler_FailedWithRevertReason(t *testing.T) {
+	// SlippageExceeded(uint256) is not a built-in error, so only the selector is known
+	reason, err := chain.DecodeRevertReasonHex("0x72bfa79e000000000000000000000000000000000000000000000000000000000000002a")
+	require.NoError(t, err)
+	require.Equal(t, chain.RevertKindUnknown, reason.Kind)
+
+	mockChain := &MockChain{
+		mockConfirmation: &chain.TransactionConfirmation{
+			Status:       "failed",
+			TxHash:       "0x5e3c7f1b2a9d8e4f6c0b1a2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f",
+			Timestamp:    time.Now(),
+			RevertReason: reason,
+		},
+	}
+	tool := NewGetTransactionStatusTool(&wallet.MockWalletManager{}, nil)
+	tool.getChainInterface = func(chainName string) (chain.IChain, error) {
+		return mockChain, nil
+	}
+	handler := tool.GetHandler()
+
+	call := func(args map[string]interface{}) string {
+		args["transaction_hash"] = mockChain.mockConfirmation.TxHash
+		args["chain"] = "ethereum"
+		result, err := handler(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{Name: "get_transaction_status", Arguments: args},
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		textContent, ok := mcp.AsTextContent(result.Content[0])
+		require.True(t, ok)
+		return textContent.Text
+	}
+
+	markdown := call(map[string]interface{}{})
+	assert.Contains(t, markdown, "- **Revert Reason**: `unknown custom error "+reason.Selector+"`")
+	assert.Contains(t, markdown, "- **Error Selector**: `"+reason.Selector+"`")
+
+	markdown = call(map[string]interface{}{
+		"abi": `[{"type":"error","name":"SlippageExceeded","inputs":[{"name":"minOut","type":"uint256"}]}]`,
+	})
+	assert.Contains(t, markdown, "- **Revert Reason**: `SlippageExceeded(minOut=42)`")
+}
+
+func TestGetTransactionStatusToolHandler_SolanaTokenBalanceChanges(t *testing.T) {
+	signature := "5VERv8NMvzbJMEkV8xnrLkEaWRtSz9CosKDYjCJjBRnbJLgp8uirBgmQpjKhoR4tjF3ZpRzrFmBV6UjKdiSZkQUW"
+	mockManager := &wallet.MockWalletManager{}
+	mockManager.On("GetTokenBalanceDeltas", mock.Anything, "solana", signature).Return([]chain.TokenBalanceDelta{
+		{
+			Account:    "WalletBONK",
+			Owner:      "Wallet111",
+			Mint:       "DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263",
+			Program:    "spl-token",
+			Decimals:   5,
+			PreAmount:  "0",
+			PostAmount: "1523",
+			Delta:      "+1523",
+		},
+		{
+			Account:    "WalletUSDC",
+			Owner:      "Wallet111",
+			Mint:       "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+			Program:    "spl-token",
+			Decimals:   6,
+			PreAmount:  "250",
+			PostAmount: "150",
+			Delta:      "-100",
+		},
+	}, nil)
+
+	tool := NewGetTransactionStatusTool(mockManager, nil)
+	tool.getChainInterface = func(chainName string) (chain.IChain, error) {
+		return &MockChain{mockConfirmation: &chain.TransactionConfirmation{Status: "confirmed", Confirmations: 1, Timestamp: time.Now()}}, nil
+	}
+
+	result, err := tool.GetHandler()(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{
+		Name:      "get_transaction_status",
+		Arguments: map[string]interface{}{"transaction_hash": signature, "chain": "sol"},
+	}})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+
+	assert.Contains(t, textContent.Text, "#### Token Balance Changes")
+	assert.Contains(t, textContent.Text, "`+1523 DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263` for owner `Wallet111`")
+	assert.Contains(t, textContent.Text, "`-100 USDC` for owner `Wallet111` (account `WalletUSDC`")
+	mockManager.AssertExpectations(t)
+
+	// Without balance data the status is still reported
+	failing := &wallet.MockWalletManager{}
+	failing.On("GetTokenBalanceDeltas", mock.Anything, "solana", signature).Return(nil, assert.AnError)
+	tool.manager = failing
+	result, err = tool.GetHandler()(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{
+		Name:      "get_transaction_status",
+		Arguments: map[string]interface{}{"transaction_hash": signature, "chain": "solana"},
+	}})
+	require.NoError(t, err)
+	textContent, ok = mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "### Transaction Status: Confirmed")
+	assert.NotContains(t, textContent.Text, "Token Balance Changes")
+}
+
+// notFoundChain reports every transaction as unknown
+type notFoundChain struct {
+	MockChain
+}
+
+func (c *notFoundChain) ConfirmTransaction(ctx context.Context, txHash string, requiredConfirmations uint64) (*chain.TransactionConfirmation, error) {
+	return nil, fmt.Errorf("transaction %s not found", txHash)
+}
+
+func TestGetTransactionStatusToolFallsBackToBSC(t *testing.T) {
+	const txHash = "0x1111111111111111111111111111111111111111111111111111111111111111"
+	factory := chain.NewChainFactory()
+	factory.RegisterChain("ethereum", &notFoundChain{})
+	factory.RegisterChain("bsc", &MockChain{mockConfirmation: &chain.TransactionConfirmation{Status: "pending", TxHash: txHash}})
+	tool := NewGetTransactionStatusTool(&wallet.MockWalletManager{}, nil)
+	tool.SetChainFactory(factory)
+
+	result, err := tool.GetHandler()(context.Background(), scheduleRequest("get_transaction_status", map[string]any{"transaction_hash": txHash}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "### Transaction Status: Pending")
+	assert.Contains(t, text, "- **Chain**: `bsc`")
+
+	// An explicit chain is not second-guessed
+	result, err = tool.GetHandler()(context.Background(), scheduleRequest("get_transaction_status", map[string]any{"transaction_hash": txHash, "chain": "eth"}))
+	require.NoError(t, err)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "### Transaction Status: Not Found")
+	assert.Contains(t, text, "- **Chain**: `ethereum`")
+}
+
+func TestGetTransactionStatusToolFallsBackToBSCWhenEthereumHasNoReceipt(t *testing.T) {
+	const txHash = "0x1111111111111111111111111111111111111111111111111111111111111111"
+	// The ethereum node has neither a receipt nor the transaction
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"jsonrpc":"2.0","id":1,"result":null}`)
+	}))
+	defer server.Close()
+	eth := chain.NewETHChainLegacy()
+	eth.SetConfirmationRPC(chain.NewEVMReceiptFunc("ethereum", []string{server.URL}), chain.NewEVMQuantityFunc("ethereum", []string{server.URL}), chain.NewEVMBlockHeadFunc("ethereum", []string{server.URL}))
+
+	factory := chain.NewChainFactory()
+	factory.RegisterChain("ethereum", eth)
+	factory.RegisterChain("bsc", &MockChain{mockConfirmation: &chain.TransactionConfirmation{Status: "pending", TxHash: txHash}})
+	tool := NewGetTransactionStatusTool(&wallet.MockWalletManager{}, nil)
+	tool.SetChainFactory(factory)
+
+	result, err := tool.GetHandler()(context.Background(), scheduleRequest("get_transaction_status", map[string]any{"transaction_hash": txHash}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "### Transaction Status: Pending")
+	assert.Contains(t, text, "- **Chain**: `bsc`")
+}
@@ -0,0 +1,74 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+)
+
+// largeTxGuard holds transactions meeting the per-chain large_tx_threshold back
+// until the caller passes confirm_large=true
+type largeTxGuard struct {
+	chains      config.ChainsConfig
+	priceFeed   wallet.PriceFeed
+	broadcaster *event.EventBroadcaster
+}
+
+// largeTxConfirmParam is the tool parameter acknowledging a large transaction
+const largeTxConfirmParam = "confirm_large"
+
+// largeTxConfirmDescription documents largeTxConfirmParam in tool schemas
+const largeTxConfirmDescription = "Confirm a transaction that meets the chain's configured large_tx_threshold (native amount or USD value); without it such transactions are held back with a warning (default: false)"
+
+// check compares the transaction against the chain's threshold and, when it is
+// large, broadcasts a large_transaction_warning event. The returned check is
+// nil when no threshold is configured for the chain.
+func (g *largeTxGuard) check(ctx context.Context, txHash, chainName, from, to, amount, token string, confirmed bool) *wallet.LargeTransactionCheck {
+	threshold := g.chains.LargeTxThreshold(chainName)
+	if !threshold.Enabled() {
+		return nil
+	}
+	check := wallet.CheckLargeTransaction(ctx, threshold, g.priceFeed, chainName, token, amount)
+	if check.Large && g.broadcaster != nil {
+		g.broadcaster.BroadcastLargeTransactionWarning(txHash, chainName, from, to, amount, token, check.USDValue, check.Reasons, confirmed)
+	}
+	return check
+}
+
+// exceeds reports whether an already approved transaction meets the chain's
+// threshold, without broadcasting a warning
+func (g *largeTxGuard) exceeds(ctx context.Context, chainName, token, amount string) bool {
+	threshold := g.chains.LargeTxThreshold(chainName)
+	if !threshold.Enabled() {
+		return false
+	}
+	return wallet.CheckLargeTransaction(ctx, threshold, g.priceFeed, chainName, token, amount).Large
+}
+
+// formatLargeTxWarningMarkdown renders the warning returned instead of executing
+// a large transaction that was not confirmed
+func formatLargeTxWarningMarkdown(check *wallet.LargeTransactionCheck, retryHint string) string {
+	markdown := "### ⚠️ Large Transaction Requires Confirmation\n\n" +
+		fmt.Sprintf("- **Chain**: `%s`\n", check.Chain) +
+		fmt.Sprintf("- **Amount**: `%s`\n", check.Amount)
+	if check.Token != "" {
+		markdown += fmt.Sprintf("- **Token**: `%s`\n", check.Token)
+	}
+	if check.USDValue > 0 && check.USDStale {
+		markdown += fmt.Sprintf("- **Estimated Value**: `$%.2f` (last known price)\n", check.USDValue)
+	} else if check.USDValue > 0 {
+		markdown += fmt.Sprintf("- **Estimated Value**: `$%.2f`\n", check.USDValue)
+	} else if check.USDUnavailable != "" {
+		markdown += fmt.Sprintf("- **Estimated Value**: `unavailable` (%s)\n", check.USDUnavailable)
+	}
+	for _, reason := range check.Reasons {
+		markdown += fmt.Sprintf("- **Threshold**: %s\n", reason)
+	}
+	markdown += "- **Status**: `awaiting_confirmation`\n" +
+		fmt.Sprintf("\nNothing was signed or sent. %s\n", retryHint)
+	return markdown
+}
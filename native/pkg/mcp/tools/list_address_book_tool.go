@@ -0,0 +1,73 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ListAddressBookTool implements the MCP "list_address_book" tool, which lists
+// named addresses.
+type ListAddressBookTool struct {
+	manager wallet.IWalletManager
+}
+
+// NewListAddressBookTool constructs a ListAddressBookTool with the given wallet manager.
+func NewListAddressBookTool(manager wallet.IWalletManager) *ListAddressBookTool {
+	return &ListAddressBookTool{manager: manager}
+}
+
+// GetMeta returns the MCP tool definition for "list_address_book".
+func (t *ListAddressBookTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("list_address_book",
+		mcp.WithDescription("List address book entries with their label, category, notes and the send policy of their category"),
+		mcp.WithString("chain",
+			mcp.Description("Optional chain filter: ethereum|eth, bsc|binance, solana|sol"),
+		),
+		mcp.WithString("category",
+			mcp.Description("Optional category filter, e.g. exchange"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "list_address_book" tool.
+func (t *ListAddressBookTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chain := strings.TrimSpace(req.GetString("chain", ""))
+		if chain != "" {
+			normalizedChain, err := toolutils.NormalizeChainName(chain)
+			if err != nil {
+				return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+			}
+			chain = normalizedChain
+		}
+
+		entries, err := t.manager.ListAddressBook(ctx, chain, req.GetString("category", ""))
+		if err != nil {
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("list address book", err)), nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString("### Address Book\n\n")
+		if len(entries) == 0 {
+			sb.WriteString("No address book entries found.\n")
+			return mcp.NewToolResultText(sb.String()), nil
+		}
+		sb.WriteString(fmt.Sprintf("- **Count**: `%d`\n", len(entries)))
+		for _, entry := range entries {
+			sb.WriteString(fmt.Sprintf("\n#### %s\n\n", entry.Label))
+			sb.WriteString(formatAddressBookEntryMarkdown(entry))
+			if policy := t.manager.AddressBookPolicy(entry.Category); policy != "" {
+				sb.WriteString(fmt.Sprintf("- **Send Policy**: `%s`\n", policy))
+			}
+		}
+		return mcp.NewToolResultText(sb.String()), nil
+	}
+}
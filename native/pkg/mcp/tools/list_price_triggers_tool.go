@@ -0,0 +1,74 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// triggerStatuses are the statuses list_price_triggers can filter on
+var triggerStatuses = []string{
+	wallet.TriggerStatusActive,
+	wallet.TriggerStatusFiring,
+	wallet.TriggerStatusFired,
+	wallet.TriggerStatusFailed,
+	wallet.TriggerStatusCancelled,
+}
+
+// ListPriceTriggersTool implements the MCP "list_price_triggers" tool, which
+// lists triggers created with create_price_trigger and what they did.
+type ListPriceTriggersTool struct {
+	manager wallet.IWalletManager
+}
+
+// NewListPriceTriggersTool constructs a ListPriceTriggersTool with the given wallet manager.
+func NewListPriceTriggersTool(manager wallet.IWalletManager) *ListPriceTriggersTool {
+	return &ListPriceTriggersTool{manager: manager}
+}
+
+// GetMeta returns the MCP tool definition for "list_price_triggers".
+func (t *ListPriceTriggersTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("list_price_triggers",
+		mcp.WithDescription("List price triggers with their condition, the last price seen, how often they fired and the result of the last firing"),
+		mcp.WithString("status",
+			mcp.Description("Optional status filter (all triggers when omitted)"),
+			mcp.Enum(triggerStatuses...),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "list_price_triggers" tool.
+func (t *ListPriceTriggersTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		status := strings.ToLower(strings.TrimSpace(req.GetString("status", "")))
+		if status != "" && !slices.Contains(triggerStatuses, status) {
+			return toolutils.FormatErrorResult(errors.ValidationError("status", "must be one of "+strings.Join(triggerStatuses, ", "))), nil
+		}
+
+		triggers, err := t.manager.ListPriceTriggers(ctx, status)
+		if err != nil {
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("list price triggers", err)), nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString("### Price Triggers\n\n")
+		if len(triggers) == 0 {
+			sb.WriteString("No price triggers found.\n")
+			return mcp.NewToolResultText(sb.String()), nil
+		}
+		sb.WriteString(fmt.Sprintf("- **Count**: `%d`\n", len(triggers)))
+		for _, trigger := range triggers {
+			sb.WriteString(fmt.Sprintf("\n#### `%s`\n\n", trigger.ID))
+			sb.WriteString(formatPriceTriggerMarkdown(trigger))
+		}
+		return mcp.NewToolResultText(sb.String()), nil
+	}
+}
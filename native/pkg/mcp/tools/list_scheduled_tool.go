@@ -0,0 +1,74 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// scheduleStatuses are the statuses list_scheduled can filter on
+var scheduleStatuses = []string{
+	wallet.ScheduleStatusScheduled,
+	wallet.ScheduleStatusExecuting,
+	wallet.ScheduleStatusCompleted,
+	wallet.ScheduleStatusFailed,
+	wallet.ScheduleStatusCancelled,
+}
+
+// ListScheduledTool implements the MCP "list_scheduled" tool, which lists
+// transactions created with schedule_transaction and the outcome of their runs.
+type ListScheduledTool struct {
+	manager wallet.IWalletManager
+}
+
+// NewListScheduledTool constructs a ListScheduledTool with the given wallet manager.
+func NewListScheduledTool(manager wallet.IWalletManager) *ListScheduledTool {
+	return &ListScheduledTool{manager: manager}
+}
+
+// GetMeta returns the MCP tool definition for "list_scheduled".
+func (t *ListScheduledTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("list_scheduled",
+		mcp.WithDescription("List scheduled transactions with their next run, run count and the result of the last run"),
+		mcp.WithString("status",
+			mcp.Description("Optional status filter (all schedules when omitted)"),
+			mcp.Enum(scheduleStatuses...),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "list_scheduled" tool.
+func (t *ListScheduledTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		status := strings.ToLower(strings.TrimSpace(req.GetString("status", "")))
+		if status != "" && !slices.Contains(scheduleStatuses, status) {
+			return toolutils.FormatErrorResult(errors.ValidationError("status", "must be one of "+strings.Join(scheduleStatuses, ", "))), nil
+		}
+
+		jobs, err := t.manager.ListScheduledTransactions(ctx, status)
+		if err != nil {
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("list scheduled transactions", err)), nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString("### Scheduled Transactions\n\n")
+		if len(jobs) == 0 {
+			sb.WriteString("No scheduled transactions found.\n")
+			return mcp.NewToolResultText(sb.String()), nil
+		}
+		sb.WriteString(fmt.Sprintf("- **Count**: `%d`\n", len(jobs)))
+		for _, job := range jobs {
+			sb.WriteString(fmt.Sprintf("\n#### `%s`\n\n", job.ID))
+			sb.WriteString(formatScheduledTransactionMarkdown(job))
+		}
+		return mcp.NewToolResultText(sb.String()), nil
+	}
+}
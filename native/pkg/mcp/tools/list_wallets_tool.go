@@ -0,0 +1,87 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ListWalletsTool implements the MCP "list_wallets" tool. It only reports wallet
+// metadata; key material never leaves the wallet manager.
+type ListWalletsTool struct {
+	manager wallet.IWalletManager
+}
+
+// NewListWalletsTool constructs a ListWalletsTool with the given wallet manager.
+func NewListWalletsTool(manager wallet.IWalletManager) *ListWalletsTool {
+	return &ListWalletsTool{manager: manager}
+}
+
+// GetMeta returns the MCP tool definition for "list_wallets".
+func (t *ListWalletsTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("list_wallets",
+		mcp.WithDescription("List the stored wallets with their address, label, supported chains, created and last-used times, whether they are watch-only or hardware wallets, and which one is selected for sending and signing. Works while the wallet is locked"),
+	)
+}
+
+// GetHandler returns the handler function for the "list_wallets" tool.
+func (t *ListWalletsTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		wallets, err := t.manager.ListWallets(ctx)
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.InternalError("list wallets", err)), nil
+		}
+		return mcp.NewToolResultText(formatWalletListMarkdown(wallets)), nil
+	}
+}
+
+// formatWalletListMarkdown renders one section per stored wallet
+func formatWalletListMarkdown(wallets []*wallet.StoredWallet) string {
+	if len(wallets) == 0 {
+		return "### Wallets\n\nNo wallets have been created or imported yet.\n"
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("### Wallets (%d)\n", len(wallets)))
+	for i, w := range wallets {
+		label := w.Label
+		if label == "" {
+			label = "Unlabeled"
+		}
+		if w.Selected {
+			label += " (selected)"
+		}
+		builder.WriteString(fmt.Sprintf("\n#### %d. %s\n\n", i+1, label))
+		builder.WriteString(fmt.Sprintf("- **Address**: `%s`\n", w.Address))
+		chains := "none"
+		if len(w.Chains) > 0 {
+			chains = strings.Join(w.Chains, ", ")
+		}
+		builder.WriteString(fmt.Sprintf("- **Chains**: `%s`\n", chains))
+		builder.WriteString(fmt.Sprintf("- **Created**: `%s`\n", formatWalletTime(w.CreatedAt)))
+		builder.WriteString(fmt.Sprintf("- **Last Used**: `%s`\n", formatWalletTime(w.LastUsed)))
+		builder.WriteString(fmt.Sprintf("- **Watch-Only**: `%t`\n", w.WatchOnly))
+		builder.WriteString(fmt.Sprintf("- **Hardware**: `%t`\n", w.Hardware))
+		builder.WriteString(fmt.Sprintf("- **Unlocked**: `%t`\n", w.Unlocked))
+		if w.Accounts > 0 {
+			builder.WriteString(fmt.Sprintf("- **Discovered Accounts**: `%d`\n", w.Accounts))
+		}
+	}
+	return builder.String()
+}
+
+// formatWalletTime renders a unix timestamp, or "never" when it is unset
+func formatWalletTime(unix int64) string {
+	if unix <= 0 {
+		return "never"
+	}
+	return time.Unix(unix, 0).UTC().Format("2006-01-02 15:04:05 UTC")
+}
@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListWalletsTool(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).Unix()
+	manager := &wallet.MockWalletManager{}
+	manager.On("ListWallets", mock.Anything).Return([]*wallet.StoredWallet{
+		{Address: "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8", Label: "Trading", Chains: []string{"bsc", "ethereum"}, CreatedAt: created, Accounts: 2, Selected: true, Unlocked: true},
+		{Address: "9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin", Chains: []string{"solana"}, CreatedAt: created, WatchOnly: true},
+	}, nil)
+
+	tool := NewListWalletsTool(manager)
+	assert.Equal(t, "list_wallets", tool.GetMeta().Name)
+
+	result, err := tool.GetHandler()(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+
+	assert.Contains(t, text, "### Wallets (2)")
+	assert.Contains(t, text, "#### 1. Trading (selected)")
+	assert.Contains(t, text, "#### 2. Unlabeled")
+	assert.Contains(t, text, "- **Chains**: `bsc, ethereum`")
+	assert.Contains(t, text, "- **Created**: `2024-01-02 03:04:05 UTC`")
+	assert.Contains(t, text, "- **Last Used**: `never`")
+	assert.Contains(t, text, "- **Watch-Only**: `true`")
+	assert.Contains(t, text, "- **Unlocked**: `true`")
+	assert.Contains(t, text, "- **Discovered Accounts**: `2`")
+}
+
+func TestListWalletsToolEmpty(t *testing.T) {
+	manager := &wallet.MockWalletManager{}
+	manager.On("ListWallets", mock.Anything).Return([]*wallet.StoredWallet{}, nil)
+
+	result, err := NewListWalletsTool(manager).GetHandler()(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "No wallets have been created or imported yet")
+}
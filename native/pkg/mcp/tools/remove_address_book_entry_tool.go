@@ -0,0 +1,71 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	stdErrors "errors"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RemoveAddressBookEntryTool implements the MCP "remove_address_book_entry"
+// tool, which deletes an address book entry.
+type RemoveAddressBookEntryTool struct {
+	manager wallet.IWalletManager
+}
+
+// NewRemoveAddressBookEntryTool constructs a RemoveAddressBookEntryTool with the given wallet manager.
+func NewRemoveAddressBookEntryTool(manager wallet.IWalletManager) *RemoveAddressBookEntryTool {
+	return &RemoveAddressBookEntryTool{manager: manager}
+}
+
+// GetMeta returns the MCP tool definition for "remove_address_book_entry".
+func (t *RemoveAddressBookEntryTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("remove_address_book_entry",
+		mcp.WithDescription("Remove an address from the address book. Entries in a blocked or warned category can only be removed by the user from the extension"),
+		mcp.WithString("chain",
+			mcp.Required(),
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol"),
+		),
+		mcp.WithString("address",
+			mcp.Required(),
+			mcp.Description("Address whose entry is removed"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "remove_address_book_entry" tool.
+func (t *RemoveAddressBookEntryTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chain, err := req.RequireString("chain")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("chain")), nil
+		}
+		normalizedChain, err := toolutils.NormalizeChainName(chain)
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+		address, err := req.RequireString("address")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("address")), nil
+		}
+
+		if toolErr := requireUserManagedEntryUnchanged(t.manager, normalizedChain, address, "remove"); toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
+		entry, err := t.manager.RemoveAddressBookEntry(ctx, normalizedChain, address)
+		if stdErrors.Is(err, wallet.ErrAddressBookEntryNotFound) {
+			return toolutils.FormatErrorResult(errors.ValidationError("address", "no address book entry for this address")), nil
+		}
+		if err != nil {
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("remove address book entry", err)), nil
+		}
+
+		return mcp.NewToolResultText("### Address Book Entry Removed\n\n" + formatAddressBookEntryMarkdown(entry)), nil
+	}
+}
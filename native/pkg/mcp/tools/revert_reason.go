@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// redecodeRevertReason decodes reason's raw data again against the contract
+// ABI supplied by the caller, so custom errors outside the built-in set get
+// their name and arguments. reason is returned unchanged when no ABI is given.
+func redecodeRevertReason(reason *chain.RevertReason, contractABI string) (*chain.RevertReason, error) {
+	contractABI = strings.TrimSpace(contractABI)
+	if reason == nil || contractABI == "" || reason.Data == "" {
+		return reason, nil
+	}
+	parsed, err := abi.JSON(strings.NewReader(contractABI))
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract ABI: %w", err)
+	}
+	return chain.DecodeRevertReasonHex(reason.Data, parsed)
+}
+
+// formatRevertReasonMarkdown renders a revert reason as markdown list items
+func formatRevertReasonMarkdown(reason *chain.RevertReason) string {
+	if reason == nil {
+		return ""
+	}
+	markdown := fmt.Sprintf("- **Revert Reason**: `%s`\n", reason.Message)
+	markdown += fmt.Sprintf("- **Revert Kind**: `%s`\n", reason.Kind)
+	if reason.Selector != "" {
+		markdown += fmt.Sprintf("- **Error Selector**: `%s`\n", reason.Selector)
+	}
+	return markdown
+}
@@ -0,0 +1,239 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ScheduleTransactionTool implements the MCP "schedule_transaction" tool, which
+// stores a transfer to be sent later, once or on a recurring interval (e.g. for
+// dollar-cost averaging).
+type ScheduleTransactionTool struct {
+	manager wallet.IWalletManager
+	largeTx largeTxGuard
+}
+
+// NewScheduleTransactionTool constructs a ScheduleTransactionTool with the given wallet manager.
+func NewScheduleTransactionTool(manager wallet.IWalletManager) *ScheduleTransactionTool {
+	return &ScheduleTransactionTool{manager: manager}
+}
+
+// NewScheduleTransactionToolWithConfig constructs a ScheduleTransactionTool that
+// asks for confirm_large up front when a transfer meets the per-chain
+// large_tx_threshold in cfg. A nil priceFeed disables the USD limit.
+func NewScheduleTransactionToolWithConfig(manager wallet.IWalletManager, broadcaster *event.EventBroadcaster, priceFeed wallet.PriceFeed, cfg *config.Config) *ScheduleTransactionTool {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return &ScheduleTransactionTool{
+		manager: manager,
+		largeTx: largeTxGuard{chains: cfg.Chains, priceFeed: priceFeed, broadcaster: broadcaster},
+	}
+}
+
+// GetMeta returns the MCP tool definition for "schedule_transaction".
+func (t *ScheduleTransactionTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("schedule_transaction",
+		mcp.WithDescription("Schedule a transfer to be sent later, once or repeatedly. Each run is sent like send_transaction at that time and is refused if the wallet is locked or frozen or the transfer needs a confirmation that was not given here. Schedules survive restarts; use list_scheduled and cancel_scheduled to manage them"),
+		mcp.WithString("chain",
+			mcp.Required(),
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol"),
+		),
+		mcp.WithString("from",
+			mcp.Required(),
+			mcp.Description("Sender address"),
+		),
+		mcp.WithString("to",
+			mcp.Required(),
+			mcp.Description("Recipient address"),
+		),
+		mcp.WithString("amount",
+			mcp.Required(),
+			mcp.Description("Amount to send on every run"),
+		),
+		mcp.WithString("token",
+			mcp.Description("Token contract address (optional, native token if not provided)"),
+		),
+		mcp.WithString("execute_at",
+			mcp.Description("Time of the first run, RFC 3339 (e.g. 2026-01-02T15:04:05Z). Give either execute_at or delay"),
+		),
+		mcp.WithString("delay",
+			mcp.Description("Delay before the first run as a duration (e.g. 30m, 2h). Give either execute_at or delay"),
+		),
+		mcp.WithString("interval",
+			mcp.Description(fmt.Sprintf("Optional recurrence as a duration (e.g. 24h, at least %s); the transfer runs once when omitted", wallet.MinScheduleInterval)),
+		),
+		mcp.WithNumber("max_runs",
+			mcp.Description("Optional number of runs of a recurring transfer (default: until cancelled)"),
+		),
+		mcp.WithBoolean(largeTxConfirmParam,
+			mcp.Description(largeTxConfirmDescription),
+		),
+		mcp.WithBoolean(contractRecipientConfirmParam,
+			mcp.Description(contractRecipientConfirmDescription),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "schedule_transaction" tool.
+func (t *ScheduleTransactionTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chain, err := req.RequireString("chain")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("chain")), nil
+		}
+		normalizedChain, err := toolutils.NormalizeChainName(chain)
+		if err != nil {
+			if appErr, ok := err.(*errors.Error); ok {
+				return toolutils.FormatErrorResult(appErr), nil
+			}
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+		from, err := req.RequireString("from")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("from")), nil
+		}
+		to, err := req.RequireString("to")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("to")), nil
+		}
+		amount, err := req.RequireString("amount")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("amount")), nil
+		}
+		if strings.EqualFold(strings.TrimSpace(amount), "max") {
+			return toolutils.FormatErrorResult(errors.ValidationError("amount", "must be a fixed value; \"max\" cannot be scheduled")), nil
+		}
+		token := req.GetString("token", "")
+
+		executeAt, toolErr := scheduleExecuteAt(req.GetString("execute_at", ""), req.GetString("delay", ""))
+		if toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+		var interval time.Duration
+		if value := strings.TrimSpace(req.GetString("interval", "")); value != "" {
+			interval, err = time.ParseDuration(value)
+			if err != nil || interval < wallet.MinScheduleInterval {
+				return toolutils.FormatErrorResult(errors.ValidationError("interval", fmt.Sprintf("must be a duration of at least %s, e.g. 24h", wallet.MinScheduleInterval))), nil
+			}
+		}
+		maxRuns := req.GetFloat("max_runs", 0)
+		if maxRuns < 0 || maxRuns != math.Trunc(maxRuns) {
+			return toolutils.FormatErrorResult(errors.ValidationError("max_runs", "must be a non-negative whole number")), nil
+		}
+		if maxRuns > 0 && interval == 0 {
+			return toolutils.FormatErrorResult(errors.ValidationError("max_runs", "requires interval")), nil
+		}
+
+		if toolErr := toolutils.RequireUnlocked(t.manager, "schedule transaction"); toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
+		// Ask for the confirmations now, while someone is there to give them
+		confirmRecipient := req.GetBool(contractRecipientConfirmParam, false)
+		recipient, recipientErr := t.manager.ClassifyRecipient(ctx, normalizedChain, to, token)
+		if recipientErr == nil && recipient.Warning != "" && !confirmRecipient {
+			return mcp.NewToolResultText(formatContractRecipientWarningMarkdown(normalizedChain, amount, token, recipient,
+				"Call schedule_transaction again with `confirm_contract_recipient=true` to schedule it.")), nil
+		}
+		confirmLarge := req.GetBool(largeTxConfirmParam, false)
+		if check := t.largeTx.check(ctx, "", normalizedChain, from, to, amount, token, confirmLarge); check != nil && check.Large && !confirmLarge {
+			return mcp.NewToolResultText(formatLargeTxWarningMarkdown(check, "Call schedule_transaction again with `confirm_large=true` to schedule it.")), nil
+		}
+
+		job, err := t.manager.ScheduleTransaction(ctx, &wallet.ScheduledTransaction{
+			Chain:                    normalizedChain,
+			From:                     from,
+			To:                       to,
+			Amount:                   amount,
+			Token:                    token,
+			ExecuteAt:                executeAt,
+			Interval:                 interval,
+			MaxRuns:                  int(maxRuns),
+			ConfirmLarge:             confirmLarge,
+			ConfirmContractRecipient: confirmRecipient,
+		})
+		if err != nil {
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("schedule transaction", err)), nil
+		}
+
+		markdown := "### Transaction Scheduled\n\n" + formatScheduledTransactionMarkdown(job) +
+			formatRecipientTypeMarkdown(recipient, recipientErr) +
+			"\nUse `list_scheduled` to follow its runs and `cancel_scheduled` to stop it.\n"
+		return mcp.NewToolResultText(markdown), nil
+	}
+}
+
+// scheduleExecuteAt resolves the first run from exactly one of an RFC 3339
+// time or a delay from now
+func scheduleExecuteAt(executeAt, delay string) (time.Time, *errors.Error) {
+	executeAt, delay = strings.TrimSpace(executeAt), strings.TrimSpace(delay)
+	switch {
+	case executeAt != "" && delay != "":
+		return time.Time{}, errors.ValidationError("execute_at", "give either execute_at or delay, not both")
+	case executeAt != "":
+		at, err := time.Parse(time.RFC3339, executeAt)
+		if err != nil {
+			return time.Time{}, errors.ValidationError("execute_at", "must be an RFC 3339 timestamp")
+		}
+		if !at.After(time.Now()) {
+			return time.Time{}, errors.ValidationError("execute_at", "must be in the future")
+		}
+		return at, nil
+	case delay != "":
+		d, err := time.ParseDuration(delay)
+		if err != nil || d <= 0 {
+			return time.Time{}, errors.ValidationError("delay", "must be a positive duration, e.g. 30m")
+		}
+		return time.Now().Add(d), nil
+	default:
+		return time.Time{}, errors.MissingRequiredFieldError("execute_at")
+	}
+}
+
+// formatScheduledTransactionMarkdown renders one scheduled transaction for the agent
+func formatScheduledTransactionMarkdown(job *wallet.ScheduledTransaction) string {
+	markdown := fmt.Sprintf("- **Schedule ID**: `%s`\n", job.ID) +
+		fmt.Sprintf("- **Status**: `%s`\n", job.Status) +
+		fmt.Sprintf("- **Chain**: `%s`\n", job.Chain) +
+		fmt.Sprintf("- **From**: `%s`\n", job.From) +
+		fmt.Sprintf("- **To**: `%s`\n", job.To) +
+		fmt.Sprintf("- **Amount**: `%s`\n", job.Amount)
+	if job.Token != "" {
+		markdown += fmt.Sprintf("- **Token**: `%s`\n", job.Token)
+	}
+	if job.Status == wallet.ScheduleStatusScheduled {
+		markdown += fmt.Sprintf("- **Next Run**: `%s`\n", job.ExecuteAt.UTC().Format(time.RFC3339))
+	}
+	switch {
+	case !job.Recurring():
+		markdown += "- **Recurrence**: `once`\n"
+	case job.MaxRuns > 0:
+		markdown += fmt.Sprintf("- **Recurrence**: `every %s` (%d runs)\n", job.Interval, job.MaxRuns)
+	default:
+		markdown += fmt.Sprintf("- **Recurrence**: `every %s` (until cancelled)\n", job.Interval)
+	}
+	markdown += fmt.Sprintf("- **Runs**: `%d`\n", job.Runs)
+	if !job.LastRunAt.IsZero() {
+		markdown += fmt.Sprintf("- **Last Run**: `%s`\n", job.LastRunAt.UTC().Format(time.RFC3339))
+	}
+	if job.LastTxHash != "" {
+		markdown += fmt.Sprintf("- **Last Transaction Hash**: `%s`\n", job.LastTxHash)
+	}
+	if job.LastError != "" {
+		markdown += fmt.Sprintf("- **Last Error**: %s\n", job.LastError)
+	}
+	return markdown
+}
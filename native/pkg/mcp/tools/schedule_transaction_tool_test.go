@@ -0,0 +1,220 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWalletManagerForSchedule struct {
+	*wallet.MockWalletManager
+	scheduled []*wallet.ScheduledTransaction
+	recipient *walletchain.RecipientClassification
+	sends     int
+}
+
+func (m *mockWalletManagerForSchedule) IsUnlocked() bool {
+	return true
+}
+
+func (m *mockWalletManagerForSchedule) ClassifyRecipient(ctx context.Context, chainName, address, token string) (*walletchain.RecipientClassification, error) {
+	if m.recipient != nil {
+		return m.recipient, nil
+	}
+	return &walletchain.RecipientClassification{Address: address, Kind: walletchain.RecipientEOA}, nil
+}
+
+func (m *mockWalletManagerForSchedule) ScheduleTransaction(ctx context.Context, job *wallet.ScheduledTransaction) (*wallet.ScheduledTransaction, error) {
+	stored := *job
+	stored.ID = "sched_0123456789abcdef"
+	stored.Status = wallet.ScheduleStatusScheduled
+	m.scheduled = append(m.scheduled, &stored)
+	return &stored, nil
+}
+
+func (m *mockWalletManagerForSchedule) ListScheduledTransactions(ctx context.Context, status string) ([]*wallet.ScheduledTransaction, error) {
+	return m.scheduled, nil
+}
+
+func (m *mockWalletManagerForSchedule) CancelScheduledTransaction(ctx context.Context, id string) (*wallet.ScheduledTransaction, error) {
+	for _, job := range m.scheduled {
+		if job.ID == id {
+			job.Status = wallet.ScheduleStatusCancelled
+			return job, nil
+		}
+	}
+	return nil, wallet.ErrScheduledTransactionNotFound
+}
+
+func (m *mockWalletManagerForSchedule) SendTransaction(ctx context.Context, chain, from, to, amount, token string) (string, error) {
+	m.sends++
+	return "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", nil
+}
+
+func scheduleRequest(name string, args map[string]any) mcp.CallToolRequest {
+	return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: name, Arguments: args}}
+}
+
+func scheduleArguments() map[string]any {
+	return map[string]any{
+		"chain":    "eth",
+		"from":     "0x1111111111111111111111111111111111111111",
+		"to":       "0x2222222222222222222222222222222222222222",
+		"amount":   "0.05",
+		"delay":    "1h",
+		"interval": "24h",
+		"max_runs": float64(30),
+	}
+}
+
+func TestScheduleTransactionTool(t *testing.T) {
+	mockManager := &mockWalletManagerForSchedule{MockWalletManager: &wallet.MockWalletManager{}}
+	handler := NewScheduleTransactionTool(mockManager).GetHandler()
+
+	before := time.Now()
+	result, err := handler(context.Background(), scheduleRequest("schedule_transaction", scheduleArguments()))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	require.Len(t, mockManager.scheduled, 1)
+
+	job := mockManager.scheduled[0]
+	assert.Equal(t, "ethereum", job.Chain)
+	assert.Equal(t, 24*time.Hour, job.Interval)
+	assert.Equal(t, 30, job.MaxRuns)
+	assert.WithinDuration(t, before.Add(time.Hour), job.ExecuteAt, time.Minute)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "### Transaction Scheduled")
+	assert.Contains(t, textContent.Text, "**Schedule ID**: `sched_0123456789abcdef`")
+	assert.Contains(t, textContent.Text, "**Recurrence**: `every 24h0m0s` (30 runs)")
+	assert.Equal(t, 0, mockManager.sends, "scheduling must not send anything")
+}
+
+func TestScheduleTransactionToolValidation(t *testing.T) {
+	cases := map[string]map[string]any{
+		"no time":           {"delay": nil},
+		"time and delay":    {"execute_at": time.Now().Add(time.Hour).UTC().Format(time.RFC3339)},
+		"past time":         {"delay": nil, "execute_at": "2020-01-01T00:00:00Z"},
+		"bad delay":         {"delay": "soon"},
+		"short interval":    {"interval": "10s"},
+		"fractional runs":   {"max_runs": 1.5},
+		"runs without loop": {"interval": nil},
+		"max amount":        {"amount": "max"},
+	}
+	for name, overrides := range cases {
+		t.Run(name, func(t *testing.T) {
+			args := scheduleArguments()
+			for key, value := range overrides {
+				if value == nil {
+					delete(args, key)
+				} else {
+					args[key] = value
+				}
+			}
+			mockManager := &mockWalletManagerForSchedule{MockWalletManager: &wallet.MockWalletManager{}}
+			result, err := NewScheduleTransactionTool(mockManager).GetHandler()(context.Background(), scheduleRequest("schedule_transaction", args))
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Empty(t, mockManager.scheduled)
+		})
+	}
+}
+
+func TestScheduleTransactionToolRequiresContractRecipientConfirmation(t *testing.T) {
+	mockManager := &mockWalletManagerForSchedule{
+		MockWalletManager: &wallet.MockWalletManager{},
+		recipient: &walletchain.RecipientClassification{
+			Address: "0x2222222222222222222222222222222222222222",
+			Kind:    walletchain.RecipientContract,
+			Warning: "the recipient is a contract that may not be able to move received tokens",
+		},
+	}
+	handler := NewScheduleTransactionTool(mockManager).GetHandler()
+
+	args := scheduleArguments()
+	args["token"] = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	result, err := handler(context.Background(), scheduleRequest("schedule_transaction", args))
+	require.NoError(t, err)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "Recipient Requires Confirmation")
+	assert.Empty(t, mockManager.scheduled)
+
+	args["confirm_contract_recipient"] = true
+	result, err = handler(context.Background(), scheduleRequest("schedule_transaction", args))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	require.Len(t, mockManager.scheduled, 1)
+	assert.True(t, mockManager.scheduled[0].ConfirmContractRecipient)
+}
+
+func TestScheduledTransactionSenderRechecksPolicies(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Chains.Ethereum.LargeTxThreshold = config.LargeTxThresholdConfig{Native: 1}
+	mockManager := &mockWalletManagerForSchedule{MockWalletManager: &wallet.MockWalletManager{}}
+	send := NewScheduledTransactionSender(mockManager, nil, nil, cfg)
+
+	job := &wallet.ScheduledTransaction{
+		ID:     "sched_1",
+		Chain:  "ethereum",
+		From:   "0x1111111111111111111111111111111111111111",
+		To:     "0x2222222222222222222222222222222222222222",
+		Amount: "0.5",
+	}
+	txHash, err := send(context.Background(), job)
+	require.NoError(t, err)
+	assert.NotEmpty(t, txHash)
+
+	// The threshold applies to runs even though the transfer was scheduled earlier
+	job.Amount = "2"
+	_, err = send(context.Background(), job)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "confirm_large")
+	job.ConfirmLarge = true
+	_, err = send(context.Background(), job)
+	require.NoError(t, err)
+
+	mockManager.recipient = &walletchain.RecipientClassification{Kind: walletchain.RecipientContract, Warning: "contract recipient"}
+	_, err = send(context.Background(), job)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "confirm_contract_recipient")
+	assert.Equal(t, 2, mockManager.sends)
+}
+
+func TestListAndCancelScheduledTools(t *testing.T) {
+	mockManager := &mockWalletManagerForSchedule{MockWalletManager: &wallet.MockWalletManager{}}
+	_, err := NewScheduleTransactionTool(mockManager).GetHandler()(context.Background(), scheduleRequest("schedule_transaction", scheduleArguments()))
+	require.NoError(t, err)
+
+	result, err := NewListScheduledTool(mockManager).GetHandler()(context.Background(), scheduleRequest("list_scheduled", map[string]any{}))
+	require.NoError(t, err)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "- **Count**: `1`")
+	assert.Contains(t, textContent.Text, "#### `sched_0123456789abcdef`")
+
+	result, err = NewListScheduledTool(mockManager).GetHandler()(context.Background(), scheduleRequest("list_scheduled", map[string]any{"status": "pending"}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	cancel := NewCancelScheduledTool(mockManager).GetHandler()
+	result, err = cancel(context.Background(), scheduleRequest("cancel_scheduled", map[string]any{"id": "sched_0123456789abcdef"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	textContent, ok = mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "### Scheduled Transaction Cancelled")
+	assert.Contains(t, textContent.Text, "**Status**: `cancelled`")
+
+	result, err = cancel(context.Background(), scheduleRequest("cancel_scheduled", map[string]any{"id": "sched_missing"}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
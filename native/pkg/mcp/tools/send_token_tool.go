@@ -0,0 +1,175 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// SendTokenTool implements the MCP "send_token" tool. It takes a token symbol
+// or address and a human-readable amount, resolves the token's decimals from
+// the token lists and sends through send_transaction with its checks.
+type SendTokenTool struct {
+	sender *SendTransactionTool
+}
+
+// NewSendTokenTool constructs a SendTokenTool sending through sender, so both
+// tools share its large transaction, dust and callback configuration.
+func NewSendTokenTool(sender *SendTransactionTool) *SendTokenTool {
+	return &SendTokenTool{sender: sender}
+}
+
+// GetMeta returns the MCP tool definition for "send_token".
+func (t *SendTokenTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("send_token",
+		mcp.WithDescription("Send a native coin (ETH, BNB, SOL) or an ERC-20/SPL token by symbol or address and a human-readable amount. The token's decimals are resolved from the token lists and the amount is converted to base units; returns the pending transaction hash and the base unit amount"),
+		mcp.WithString("chain",
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol (optional, defaults to wallet.default_chain)"),
+		),
+		mcp.WithString("from",
+			mcp.Required(),
+			mcp.Description("Sender address"),
+		),
+		mcp.WithString("to",
+			mcp.Required(),
+			mcp.Description("Recipient address"),
+		),
+		mcp.WithString("token",
+			mcp.Required(),
+			mcp.Description("Native symbol (ETH, BNB, SOL), a token symbol from the token lists (e.g. USDC), or a token contract or mint address"),
+		),
+		mcp.WithString("amount",
+			mcp.Required(),
+			mcp.Description("Amount in whole tokens (e.g. 1.5), with at most as many decimal places as the token has"),
+		),
+		mcp.WithString("gas_strategy",
+			mcp.Description("EVM gas strategy (optional, defaults to the chain's configured strategy): slow, standard, fast, or dynamic to adapt to base-fee trend and mempool depth"),
+			mcp.Enum(walletchain.GasStrategySlow, walletchain.GasStrategyStandard, walletchain.GasStrategyFast, walletchain.GasStrategyDynamic),
+		),
+		mcp.WithBoolean(largeTxConfirmParam,
+			mcp.Description(largeTxConfirmDescription),
+		),
+		mcp.WithBoolean(contractRecipientConfirmParam,
+			mcp.Description(contractRecipientConfirmDescription),
+		),
+		mcp.WithBoolean(allowDustParam,
+			mcp.Description(allowDustDescription),
+		),
+		mcp.WithBoolean("skip_simulation",
+			mcp.Description("Broadcast without first simulating the send (optional, default: false). By default a send that would revert is aborted and its revert reason returned"),
+		),
+		withCommitmentOption(),
+		withCallbackURLOption(),
+		withCorrelationIDOption(),
+	)
+}
+
+// GetHandler returns the handler function for the "send_token" tool.
+func (t *SendTokenTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chain := toolutils.ChainOrDefault(t.sender.manager, req.GetString("chain", ""))
+		normalizedChain, err := toolutils.NormalizeChainName(chain)
+		if err != nil {
+			if appErr, ok := err.(*errors.Error); ok {
+				return toolutils.FormatErrorResult(appErr), nil
+			}
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+
+		from, err := req.RequireString("from")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("from")), nil
+		}
+		to, err := req.RequireString("to")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("to")), nil
+		}
+		token, err := req.RequireString("token")
+		if err != nil || strings.TrimSpace(token) == "" {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("token")), nil
+		}
+		amount, err := req.RequireString("amount")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("amount")), nil
+		}
+
+		metadata, toolErr := resolveSendToken(normalizedChain, token)
+		if toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+		units, err := parseBaseUnits(amount, metadata.Decimals)
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.ValidationError("amount", err.Error())), nil
+		}
+
+		return t.sender.send(ctx, req, sendArgs{
+			tool:    "send_token",
+			chain:   normalizedChain,
+			from:    from,
+			to:      to,
+			amount:  formatScaled(units, int64(metadata.Decimals)),
+			token:   metadata.Address,
+			details: formatSendTokenMarkdown(metadata, units),
+		}), nil
+	}
+}
+
+// resolveSendToken resolves a native symbol, listed symbol or address on a
+// normalized chain to its metadata. Tokens whose decimals the token lists do
+// not know are refused, since the amount could not be converted.
+func resolveSendToken(chainName, token string) (*wallet.TokenMetadata, *errors.Error) {
+	token = strings.TrimSpace(token)
+	if !strings.EqualFold(token, wallet.NativeTokenSymbol(chainName)) {
+		// Symbols are tried first: short symbols such as USDC are valid base58
+		if listed, ok := wallet.ResolveTokenSymbol(chainName, token); ok {
+			token = listed.Address
+		} else if !isValidAddressForChain(chainName, token) {
+			return nil, errors.InvalidTokenAddressError(token, chainName)
+		}
+	}
+
+	metadata := wallet.LookupTokenMetadata(chainName, token)
+	if !metadata.Known {
+		return nil, errors.TokenNotSupportedError(token, chainName).
+			WithDetails("its decimals are not in the bundled token list or any imported token list").
+			WithSuggestion("Import a token list that includes it, or use send_transaction")
+	}
+	return metadata, nil
+}
+
+// parseBaseUnits converts a positive decimal amount to base units of a token
+// with decimals places, refusing amounts finer than the token allows
+func parseBaseUnits(amount string, decimals int) (*big.Int, error) {
+	if strings.EqualFold(strings.TrimSpace(amount), "max") {
+		return nil, fmt.Errorf("send_token takes an exact amount, use send_transaction with amount max to send the whole balance")
+	}
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok || value.Sign() <= 0 {
+		return nil, fmt.Errorf("must be a positive number, got %q", amount)
+	}
+	value.Mul(value, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	if !value.IsInt() {
+		return nil, fmt.Errorf("%s has more than the token's %d decimals", amount, decimals)
+	}
+	return value.Num(), nil
+}
+
+// formatSendTokenMarkdown describes the resolved token and base unit amount
+func formatSendTokenMarkdown(metadata *wallet.TokenMetadata, units *big.Int) string {
+	markdown := fmt.Sprintf("- **Token**: `%s` (native)\n", metadata.Symbol)
+	if !metadata.IsNative {
+		markdown = fmt.Sprintf("- **Token**: `%s` (`%s`)\n", metadata.Symbol, metadata.Address)
+	}
+	return markdown +
+		fmt.Sprintf("- **Decimals**: `%d`\n", metadata.Decimals) +
+		fmt.Sprintf("- **Base Unit Amount**: `%s`\n", units.String())
+}
@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sendTokenRecorder records the amount and token send_token hands to the wallet manager
+type sendTokenRecorder struct {
+	*mockWalletManagerForSendTransaction
+	amount string
+	token  string
+}
+
+func (m *sendTokenRecorder) SendTransaction(ctx context.Context, chain, from, to, amount, token string) (string, error) {
+	m.amount, m.token = amount, token
+	return m.mockWalletManagerForSendTransaction.SendTransaction(ctx, chain, from, to, amount, token)
+}
+
+func newSendTokenTestTool() (*SendTokenTool, *sendTokenRecorder) {
+	manager := &sendTokenRecorder{mockWalletManagerForSendTransaction: &mockWalletManagerForSendTransaction{MockWalletManager: &wallet.MockWalletManager{}}}
+	return NewSendTokenTool(NewSendTransactionTool(manager)), manager
+}
+
+func callSendToken(t *testing.T, tool *SendTokenTool, args map[string]any) (*mcp.CallToolResult, string) {
+	t.Helper()
+	result, err := tool.GetHandler()(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "send_token", Arguments: args},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	return result, textContent.Text
+}
+
+func TestSendTokenToolResolvesTokens(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      map[string]any
+		token     string
+		amount    string
+		baseUnits string
+		tokenLine string
+	}{
+		{
+			name:      "erc20 by symbol",
+			args:      map[string]any{"chain": "eth", "token": "usdc", "amount": "1.5", "from": "0x1234567890123456789012345678901234567890", "to": "0x0987654321098765432109876543210987654321"},
+			token:     "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+			amount:    "1.5",
+			baseUnits: "1500000",
+			tokenLine: "**Token**: `USDC` (`0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48`)",
+		},
+		{
+			name:      "bep20 by address",
+			args:      map[string]any{"chain": "bsc", "token": "0x55d398326f99059ff775485246999027b3197955", "amount": "2", "from": "0x1234567890123456789012345678901234567890", "to": "0x0987654321098765432109876543210987654321"},
+			token:     "0x55d398326f99059fF775485246999027B3197955",
+			amount:    "2",
+			baseUnits: "2000000000000000000",
+			tokenLine: "**Token**: `USDT`",
+		},
+		{
+			name:      "native sol",
+			args:      map[string]any{"chain": "sol", "token": "SOL", "amount": "0.250", "from": "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK", "to": "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY"},
+			token:     "",
+			amount:    "0.25",
+			baseUnits: "250000000",
+			tokenLine: "**Token**: `SOL` (native)",
+		},
+		{
+			name:      "spl by symbol",
+			args:      map[string]any{"chain": "solana", "token": "USDC", "amount": "10", "from": "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK", "to": "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY"},
+			token:     "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+			amount:    "10",
+			baseUnits: "10000000",
+			tokenLine: "**Token**: `USDC` (`EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v`)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool, manager := newSendTokenTestTool()
+			result, text := callSendToken(t, tool, tt.args)
+			require.False(t, result.IsError, text)
+			assert.Equal(t, tt.token, manager.token)
+			assert.Equal(t, tt.amount, manager.amount)
+			assert.Contains(t, text, "### Transaction Sent")
+			assert.Contains(t, text, tt.tokenLine)
+			assert.Contains(t, text, "**Base Unit Amount**: `"+tt.baseUnits+"`")
+			assert.Contains(t, text, "**Transaction Hash**: `0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa`")
+			assert.Contains(t, text, "**Status**: `pending`")
+		})
+	}
+}
+
+func TestSendTokenToolRejectsUnresolvableSends(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		amount string
+		want   string
+	}{
+		{"unknown symbol", "NOPE", "1", "NOPE"},
+		{"unlisted token", "0x1111111111111111111111111111111111111111", "1", "decimals are not in the bundled token list"},
+		{"too many decimals", "USDC", "1.0000001", "more than the token's 6 decimals"},
+		{"max amount", "ETH", "max", "use send_transaction"},
+		{"negative amount", "ETH", "-1", "must be a positive number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool, manager := newSendTokenTestTool()
+			result, text := callSendToken(t, tool, map[string]any{
+				"chain":  "ethereum",
+				"from":   "0x1234567890123456789012345678901234567890",
+				"to":     "0x0987654321098765432109876543210987654321",
+				"token":  tt.token,
+				"amount": tt.amount,
+			})
+			assert.True(t, result.IsError)
+			assert.Contains(t, text, tt.want)
+			assert.Zero(t, manager.sendCalls)
+		})
+	}
+}
+
+func TestSendTokenToolRefusesMisspelledMints(t *testing.T) {
+	tool, manager := newSendTokenTestTool()
+	result, text := callSendToken(t, tool, map[string]any{
+		"chain":  "solana",
+		"from":   "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK",
+		"to":     "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY",
+		"token":  "epjfwdd5aufqssqem2qn1xzybapc8g4weggkzwytdt1v",
+		"amount": "1",
+	})
+	assert.True(t, result.IsError, "a lowercased mint names another account and is not corrected")
+	assert.Contains(t, text, "epjfwdd5aufqssqem2qn1xzybapc8g4weggkzwytdt1v")
+	assert.NotContains(t, text, "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	assert.Zero(t, manager.sendCalls)
+}
+
+func TestSendTokenToolNamesItselfInConfirmationHints(t *testing.T) {
+	tool, manager := newSendTokenTestTool()
+	manager.recipient = &walletchain.RecipientClassification{
+		Address: "0x0987654321098765432109876543210987654321",
+		Kind:    walletchain.RecipientContract,
+		Warning: "the recipient is a contract that may not be able to move received tokens",
+	}
+	_, text := callSendToken(t, tool, map[string]any{
+		"chain":  "ethereum",
+		"from":   "0x1234567890123456789012345678901234567890",
+		"to":     "0x0987654321098765432109876543210987654321",
+		"token":  "USDC",
+		"amount": "5",
+	})
+	assert.Contains(t, text, "Call send_token again with `confirm_contract_recipient=true`")
+	assert.Zero(t, manager.sendCalls)
+}
@@ -4,10 +4,15 @@ package tools
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/algonius/algonius-wallet/native/pkg/config"
 	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/event"
 	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
 	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -15,11 +20,43 @@ import (
 // SendTransactionTool implements the MCP "send_transaction" tool for sending blockchain transactions.
 type SendTransactionTool struct {
 	manager wallet.IWalletManager
+	largeTx largeTxGuard
+	dust    dustGuard
+	// chains holds the per-chain estimate multipliers and max fee ceilings
+	chains config.ChainsConfig
+	// callbacks reports the outcome of sends that asked for a callback
+	callbacks *callbackWatcher
 }
 
 // NewSendTransactionTool constructs a SendTransactionTool with the given wallet manager.
 func NewSendTransactionTool(manager wallet.IWalletManager) *SendTransactionTool {
-	return &SendTransactionTool{manager: manager}
+	return &SendTransactionTool{manager: manager, callbacks: newCallbackWatcher(nil, nil, nil)}
+}
+
+// NewSendTransactionToolWithConfig constructs a SendTransactionTool that holds back
+// sends meeting the per-chain large_tx_threshold in cfg until they are confirmed,
+// announcing them on broadcaster, and rejects sends below the per-chain
+// min_transfer. Gas and compute estimates are scaled by the per-chain estimate
+// multipliers in cfg. Requested outcome callbacks are delivered as configured in
+// cfg.Callbacks. A nil priceFeed disables the USD limit and the fee comparison
+// for tokens without a minimum.
+func NewSendTransactionToolWithConfig(manager wallet.IWalletManager, broadcaster *event.EventBroadcaster, priceFeed wallet.PriceFeed, cfg *config.Config) *SendTransactionTool {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return &SendTransactionTool{
+		manager:   manager,
+		largeTx:   largeTxGuard{chains: cfg.Chains, priceFeed: priceFeed, broadcaster: broadcaster},
+		dust:      dustGuard{chains: cfg.Chains, priceFeed: priceFeed},
+		chains:    cfg.Chains,
+		callbacks: newCallbackWatcher(cfg, broadcaster, nil),
+	}
+}
+
+// CancelCallbacks stops every running callback watch, e.g. when the wallet is
+// frozen, and returns how many were stopped
+func (t *SendTransactionTool) CancelCallbacks() int {
+	return t.callbacks.cancel()
 }
 
 // GetMeta returns the MCP tool definition for "send_transaction" as per the documented API schema.
@@ -27,8 +64,7 @@ func (t *SendTransactionTool) GetMeta() mcp.Tool {
 	return mcp.NewTool("send_transaction",
 		mcp.WithDescription("Send a blockchain transaction"),
 		mcp.WithString("chain",
-			mcp.Required(),
-			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol"),
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol (optional, defaults to wallet.default_chain)"),
 		),
 		mcp.WithString("from",
 			mcp.Required(),
@@ -40,7 +76,7 @@ func (t *SendTransactionTool) GetMeta() mcp.Tool {
 		),
 		mcp.WithString("amount",
 			mcp.Required(),
-			mcp.Description("Amount to send"),
+			mcp.Description("Amount to send, or \"max\" on Ethereum/BSC to send the native balance minus the configured gas reserve"),
 		),
 		mcp.WithString("token",
 			mcp.Description("Token contract address (optional, native token if not provided)"),
@@ -51,6 +87,29 @@ func (t *SendTransactionTool) GetMeta() mcp.Tool {
 		mcp.WithString("gas_price",
 			mcp.Description("Gas price in gwei (optional)"),
 		),
+		mcp.WithString("gas_strategy",
+			mcp.Description("EVM gas strategy (optional, defaults to the chain's configured strategy): slow, standard, fast, or dynamic to adapt to base-fee trend and mempool depth"),
+			mcp.Enum(walletchain.GasStrategySlow, walletchain.GasStrategyStandard, walletchain.GasStrategyFast, walletchain.GasStrategyDynamic),
+		),
+		withEstimateMultiplierOptions(),
+		mcp.WithBoolean(largeTxConfirmParam,
+			mcp.Description(largeTxConfirmDescription),
+		),
+		mcp.WithBoolean(contractRecipientConfirmParam,
+			mcp.Description(contractRecipientConfirmDescription),
+		),
+		mcp.WithBoolean(allowDustParam,
+			mcp.Description(allowDustDescription),
+		),
+		mcp.WithBoolean("skip_simulation",
+			mcp.Description("Broadcast without first simulating the send (optional, default: false). By default a send that would revert is aborted and its revert reason returned"),
+		),
+		mcp.WithString("fee_payer",
+			mcp.Description("Solana only: address of another account of this wallet that pays the transaction fee and signs alongside from (optional, native SOL transfers only)"),
+		),
+		withCommitmentOption(),
+		withCallbackURLOption(),
+		withCorrelationIDOption(),
 	)
 }
 
@@ -59,11 +118,7 @@ func (t *SendTransactionTool) GetMeta() mcp.Tool {
 func (t *SendTransactionTool) GetHandler() server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Extract required parameters
-		chain, err := req.RequireString("chain")
-		if err != nil {
-			toolErr := errors.MissingRequiredFieldError("chain")
-			return toolutils.FormatErrorResult(toolErr), nil
-		}
+		chain := toolutils.ChainOrDefault(t.manager, req.GetString("chain", ""))
 		normalizedChain, err := toolutils.NormalizeChainName(chain)
 		if err != nil {
 			if appErr, ok := err.(*errors.Error); ok {
@@ -90,70 +145,233 @@ func (t *SendTransactionTool) GetHandler() server.ToolHandlerFunc {
 			return toolutils.FormatErrorResult(toolErr), nil
 		}
 
-		// Extract optional parameters
-		token := req.GetString("token", "")
-		gasLimit := req.GetFloat("gas_limit", 0)
-		gasPrice := req.GetString("gas_price", "")
+		return t.send(ctx, req, sendArgs{
+			tool:   "send_transaction",
+			chain:  normalizedChain,
+			from:   from,
+			to:     to,
+			amount: amount,
+			token:  req.GetString("token", ""),
+		}), nil
+	}
+}
 
-		// Perform gas estimation if not provided
-		var finalGasLimit float64 = gasLimit
-		var finalGasPrice string = gasPrice
+// sendArgs are the resolved arguments of a send. tool names the tool in
+// confirmation hints; details, when set, replaces the Token line of the result.
+type sendArgs struct {
+	tool    string
+	chain   string
+	from    string
+	to      string
+	amount  string
+	token   string
+	details string
+}
 
-		if gasLimit == 0 || gasPrice == "" {
-			estimatedGas, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (struct {
-				gasLimit uint64
-				gasPrice string
-			}, error) {
-				limit, price, estimateErr := t.manager.EstimateGas(attemptCtx, normalizedChain, from, to, amount, token)
-				return struct {
-					gasLimit uint64
-					gasPrice string
-				}{gasLimit: limit, gasPrice: price}, estimateErr
-			})
-			if err != nil {
-				toolErr := toolutils.ClassifyError("gas estimation", err)
-				return toolutils.FormatErrorResult(toolErr), nil
-			}
+// send applies the send_transaction checks to args and sends it, reading the
+// optional parameters from req
+func (t *SendTransactionTool) send(ctx context.Context, req mcp.CallToolRequest, args sendArgs) *mcp.CallToolResult {
+	normalizedChain, from, to, amount, token := args.chain, args.from, args.to, args.amount, args.token
+	gasLimit := req.GetFloat("gas_limit", 0)
+	gasPrice := req.GetString("gas_price", "")
+	gasStrategy := strings.ToLower(strings.TrimSpace(req.GetString("gas_strategy", "")))
 
-			if gasLimit == 0 {
-				finalGasLimit = float64(estimatedGas.gasLimit)
-			}
-			if gasPrice == "" {
-				finalGasPrice = estimatedGas.gasPrice
+	if gasStrategy != "" {
+		if normalizedChain == "solana" {
+			return toolutils.FormatErrorResult(errors.ValidationError("gas_strategy", "gas strategies are only supported on EVM chains"))
+		}
+		if !walletchain.IsValidGasStrategy(gasStrategy) {
+			return toolutils.FormatErrorResult(errors.ValidationError("gas_strategy", "must be one of slow, standard, fast, dynamic"))
+		}
+	}
+
+	multipliers, toolErr := resolveEstimateMultipliers(req, t.chains, normalizedChain)
+	if toolErr != nil {
+		return toolutils.FormatErrorResult(toolErr)
+	}
+
+	ctx, toolErr = commitmentContext(ctx, req, normalizedChain)
+	if toolErr != nil {
+		return toolutils.FormatErrorResult(toolErr)
+	}
+
+	if req.GetBool("skip_simulation", false) {
+		ctx = walletchain.WithoutSimulation(ctx)
+	}
+
+	callback, toolErr := parseTransactionCallback(req)
+	if toolErr != nil {
+		return toolutils.FormatErrorResult(toolErr)
+	}
+
+	feePayer := strings.TrimSpace(req.GetString("fee_payer", ""))
+	if feePayer != "" {
+		if normalizedChain != "solana" {
+			return toolutils.FormatErrorResult(errors.ValidationError("fee_payer", "a separate fee payer is only supported on Solana"))
+		}
+		if !isValidAddressForChain(normalizedChain, feePayer) {
+			return toolutils.FormatErrorResult(errors.InvalidAddressError(feePayer, normalizedChain))
+		}
+		ctx = walletchain.WithFeePayer(ctx, feePayer)
+	}
+
+	if toolErr := toolutils.RequireUnlocked(t.manager, "send transaction"); toolErr != nil {
+		return toolutils.FormatErrorResult(toolErr)
+	}
+
+	// Hold back transfers the recipient is unlikely to be able to use. A
+	// failed check is reported but does not block the send.
+	recipient, recipientErr := t.manager.ClassifyRecipient(ctx, normalizedChain, to, token)
+	if recipientErr == nil && recipient.Warning != "" && !req.GetBool(contractRecipientConfirmParam, false) {
+		return mcp.NewToolResultText(formatContractRecipientWarningMarkdown(normalizedChain, amount, token, recipient,
+			"Call "+args.tool+" again with `confirm_contract_recipient=true` to send it."))
+	}
+
+	// Warn about tokens that keep part of every transfer. Detection is best
+	// effort, so a failed check does not block the send.
+	var transferFee *walletchain.TokenTransferFee
+	if token != "" && normalizedChain != "solana" && !strings.EqualFold(strings.TrimSpace(amount), "max") {
+		transferFee, _ = t.manager.DetectTransferFee(ctx, normalizedChain, from, to, amount, token)
+	}
+
+	// Hold large sends back until the caller confirms them
+	if t.largeTx.chains.LargeTxThreshold(normalizedChain).Enabled() {
+		checkedAmount := amount
+		if strings.EqualFold(strings.TrimSpace(amount), "max") {
+			spendable, err := t.manager.GetSpendableBalance(ctx, normalizedChain, from, token)
+			if err != nil {
+				toolErr := toolutils.ClassifyError("large transaction check", err)
+				return toolutils.FormatErrorResult(toolErr)
 			}
+			checkedAmount = spendable.Spendable
 		}
+		confirmed := req.GetBool(largeTxConfirmParam, false)
+		if check := t.largeTx.check(ctx, "", normalizedChain, from, to, checkedAmount, token, confirmed); check.Large && !confirmed {
+			return mcp.NewToolResultText(formatLargeTxWarningMarkdown(check, "Call "+args.tool+" again with `confirm_large=true` to send it."))
+		}
+	}
 
-		// Send the transaction
-		txHash, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (string, error) {
-			return t.manager.SendTransaction(attemptCtx, normalizedChain, from, to, amount, token)
+	// Price EVM transactions from current fee-market conditions unless the caller fixed a gas price
+	var gasParams *walletchain.GasParams
+	if gasPrice == "" && normalizedChain != "solana" {
+		var err error
+		gasParams, err = toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (*walletchain.GasParams, error) {
+			return t.manager.SuggestGasParams(attemptCtx, normalizedChain, gasStrategy)
 		})
 		if err != nil {
-			toolErr := toolutils.ClassifyError("send transaction", err)
-			return toolutils.FormatErrorResult(toolErr), nil
+			toolErr := toolutils.ClassifyError("gas pricing", err)
+			return toolutils.FormatErrorResult(toolErr)
 		}
+		gasParams = walletchain.ScaleGasParams(gasParams, multipliers.Fee, t.chains.MaxFee(normalizedChain))
+	}
 
-		// Format success response
-		markdown := "### Transaction Sent\n\n" +
-			"- **Chain**: `" + normalizedChain + "`\n" +
-			"- **From**: `" + from + "`\n" +
-			"- **To**: `" + to + "`\n" +
-			"- **Amount**: `" + amount + "`\n"
+	// Perform gas estimation if not provided
+	var finalGasLimit float64 = gasLimit
+	var finalGasPrice string = gasPrice
 
-		if token != "" {
-			markdown += "- **Token**: `" + token + "`\n"
+	if gasLimit == 0 || gasPrice == "" {
+		estimatedGas, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (struct {
+			gasLimit uint64
+			gasPrice string
+		}, error) {
+			limit, price, estimateErr := t.manager.EstimateGas(attemptCtx, normalizedChain, from, to, amount, token)
+			return struct {
+				gasLimit uint64
+				gasPrice string
+			}{gasLimit: limit, gasPrice: price}, estimateErr
+		})
+		if err != nil {
+			toolErr := toolutils.ClassifyError("gas estimation", err)
+			return toolutils.FormatErrorResult(toolErr)
 		}
 
-		if finalGasLimit > 0 {
-			markdown += fmt.Sprintf("- **Gas Limit**: `%.0f`\n", finalGasLimit)
+		// Leave a safety margin on top of the raw estimates
+		if gasLimit == 0 {
+			finalGasLimit = scaleGasLimit(float64(estimatedGas.gasLimit), multipliers.Limit)
+		}
+		if gasPrice == "" {
+			finalGasPrice = scaleGasPrice(estimatedGas.gasPrice, multipliers.Fee)
 		}
+	}
+	if gasParams != nil {
+		finalGasPrice = formatGwei(gasParams.MaxFeeGwei)
+	}
 
-		if finalGasPrice != "" {
-			markdown += "- **Gas Price**: `" + finalGasPrice + " gwei`\n"
+	// Refuse dust that costs more in fees than it moves
+	if !req.GetBool(allowDustParam, false) {
+		if toolErr := t.dust.check(ctx, normalizedChain, token, amount, finalGasLimit, finalGasPrice); toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr)
 		}
+	}
+
+	// Send the transaction
+	txHash, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (string, error) {
+		return t.manager.SendTransaction(attemptCtx, normalizedChain, from, to, amount, token)
+	})
+	if err != nil {
+		toolErr := toolutils.ClassifyError("send transaction", err)
+		return toolutils.FormatErrorResult(toolErr)
+	}
+	if callback != nil {
+		t.callbacks.start(ctx, normalizedChain, txHash, callback)
+	}
+
+	// Format success response
+	markdown := "### Transaction Sent\n\n" +
+		"- **Chain**: `" + normalizedChain + "`\n" +
+		"- **From**: `" + from + "`\n" +
+		"- **To**: `" + to + "`\n" +
+		"- **Amount**: `" + amount + "`\n"
 
-		markdown += "- **Transaction Hash**: `" + txHash + "`\n" +
-			"- **Status**: `pending`\n"
+	if args.details != "" {
+		markdown += args.details
+	} else if token != "" {
+		markdown += "- **Token**: `" + token + "`\n"
+	}
+	if feePayer != "" {
+		markdown += "- **Fee Payer**: `" + feePayer + "`\n"
+	}
+	markdown += formatRecipientTypeMarkdown(recipient, recipientErr)
+	markdown += formatTransferFeeMarkdown(transferFee)
 
-		return mcp.NewToolResultText(markdown), nil
+	if finalGasLimit > 0 {
+		markdown += fmt.Sprintf("- **Gas Limit**: `%.0f`\n", finalGasLimit)
 	}
+
+	if finalGasPrice != "" {
+		markdown += "- **Gas Price**: `" + finalGasPrice + " gwei`\n"
+	}
+
+	if gasParams != nil {
+		markdown += formatGasParamsMarkdown(gasParams)
+	}
+	markdown += formatEstimateMultipliersMarkdown(multipliers, gasLimit == 0, gasPrice == "")
+
+	markdown += "- **Transaction Hash**: `" + txHash + "`\n" +
+		"- **Status**: `pending`\n" +
+		formatCallbackMarkdown(callback)
+
+	return mcp.NewToolResultText(markdown)
+}
+
+// formatGasParamsMarkdown renders the fee parameters chosen by the gas strategy
+func formatGasParamsMarkdown(params *walletchain.GasParams) string {
+	markdown := "- **Gas Strategy**: `" + params.Strategy + "`\n" +
+		"- **Base Fee**: `" + formatGwei(params.BaseFeeGwei) + " gwei`\n" +
+		"- **Priority Fee**: `" + formatGwei(params.PriorityFeeGwei) + " gwei`\n" +
+		"- **Max Fee**: `" + formatGwei(params.MaxFeeGwei) + " gwei`\n" +
+		fmt.Sprintf("- **Fee Multiplier**: `%.3gx`\n", params.Multiplier)
+	if params.Strategy == walletchain.GasStrategyDynamic {
+		markdown += fmt.Sprintf("- **Base Fee Trend**: `%+.1f%%`\n", params.BaseFeeTrend*100) +
+			fmt.Sprintf("- **Mempool Pressure**: `%.2f`\n", params.PoolPressure)
+	}
+	if params.Capped {
+		markdown += "- **Max Fee Capped**: `true` (limited by the configured max_fee ceiling)\n"
+	}
+	return markdown
+}
+
+// formatGwei formats a gwei amount without trailing zeros
+func formatGwei(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
 }
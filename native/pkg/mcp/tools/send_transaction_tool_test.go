@@ -4,18 +4,39 @@ import (
 	"context"
 	"testing"
 
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/event"
 	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 type mockWalletManagerForSendTransaction struct {
 	*wallet.MockWalletManager
 	lastEstimateChain string
 	lastSendChain     string
+	lastGasStrategy   string
+	lastCommitment    string
 	estimateFail      bool
 	sendFail          bool
+	locked            bool
+	recipient         *walletchain.RecipientClassification
+	revert            *walletchain.SimulationError
+	sendErr           error
+	sendCalls         int
+	lastFeePayer      string
+	transferFee       *walletchain.TokenTransferFee
+}
+
+func (m *mockWalletManagerForSendTransaction) IsUnlocked() bool {
+	return !m.locked
+}
+
+func (m *mockWalletManagerForSendTransaction) IsFrozen() bool {
+	return false
 }
 
 func (m *mockWalletManagerForSendTransaction) EstimateGas(ctx context.Context, chain, from, to, amount, token string) (uint64, string, error) {
@@ -28,12 +49,43 @@ func (m *mockWalletManagerForSendTransaction) EstimateGas(ctx context.Context, c
 
 func (m *mockWalletManagerForSendTransaction) SendTransaction(ctx context.Context, chain, from, to, amount, token string) (string, error) {
 	m.lastSendChain = chain
+	m.lastCommitment, _ = walletchain.CommitmentFromContext(ctx)
+	m.sendCalls++
+	m.lastFeePayer, _ = walletchain.FeePayerFromContext(ctx)
+	if m.sendErr != nil {
+		return "", m.sendErr
+	}
+	if m.revert != nil && !walletchain.SimulationSkipped(ctx) {
+		return "", m.revert
+	}
 	if m.sendFail {
 		return "", assert.AnError
 	}
 	return "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", nil
 }
 
+func (m *mockWalletManagerForSendTransaction) ClassifyRecipient(ctx context.Context, chainName, address, token string) (*walletchain.RecipientClassification, error) {
+	if m.recipient != nil {
+		return m.recipient, nil
+	}
+	return &walletchain.RecipientClassification{Address: address, Kind: walletchain.RecipientEOA}, nil
+}
+
+func (m *mockWalletManagerForSendTransaction) DetectTransferFee(ctx context.Context, chainName, from, to, amount, token string) (*walletchain.TokenTransferFee, error) {
+	return m.transferFee, nil
+}
+
+func (m *mockWalletManagerForSendTransaction) SuggestGasParams(ctx context.Context, chainName, strategy string) (*walletchain.GasParams, error) {
+	m.lastGasStrategy = strategy
+	sample := walletchain.MempoolSample{
+		BaseFeesGwei:    []float64{20, 22, 24},
+		PriorityFeeGwei: 2,
+		PendingTxCount:  8000,
+		NormalPoolDepth: 4000,
+	}
+	return walletchain.ComputeGasParams(strategy, sample, 50)
+}
+
 func TestSendTransactionToolHandlerSuccess(t *testing.T) {
 	mockManager := &mockWalletManagerForSendTransaction{MockWalletManager: &wallet.MockWalletManager{}}
 	tool := NewSendTransactionTool(mockManager)
@@ -66,6 +118,45 @@ func TestSendTransactionToolHandlerSuccess(t *testing.T) {
 	assert.Contains(t, textContent.Text, "**Gas Limit**: `21000`")
 }
 
+func TestSendTransactionToolHandlerRequiresContractRecipientConfirmation(t *testing.T) {
+	mockManager := &mockWalletManagerForSendTransaction{
+		MockWalletManager: &wallet.MockWalletManager{},
+		recipient: &walletchain.RecipientClassification{
+			Address: "0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D",
+			Kind:    walletchain.RecipientContract,
+			Warning: "the recipient is a contract that may not be able to move received tokens",
+		},
+	}
+	handler := NewSendTransactionTool(mockManager).GetHandler()
+
+	arguments := map[string]any{
+		"chain":  "ethereum",
+		"from":   "0x1111111111111111111111111111111111111111",
+		"to":     "0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D",
+		"amount": "10",
+		"token":  "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+	}
+	result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "send_transaction", Arguments: arguments}})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "Recipient Requires Confirmation")
+	assert.Contains(t, textContent.Text, "**Recipient Type**: `contract`")
+	assert.Contains(t, textContent.Text, "confirm_contract_recipient")
+	assert.Empty(t, mockManager.lastSendChain, "nothing may be sent before confirmation")
+
+	arguments["confirm_contract_recipient"] = true
+	result, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "send_transaction", Arguments: arguments}})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	textContent, ok = mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "### Transaction Sent")
+	assert.Contains(t, textContent.Text, "**Recipient Type**: `contract`")
+	assert.Equal(t, "ethereum", mockManager.lastSendChain)
+}
+
 func TestSendTransactionToolHandlerInvalidChain(t *testing.T) {
 	tool := NewSendTransactionTool(&wallet.MockWalletManager{})
 	handler := tool.GetHandler()
@@ -113,3 +204,405 @@ func TestSendTransactionToolHandlerSendError(t *testing.T) {
 	require.NotNil(t, result)
 	assert.True(t, result.IsError)
 }
+
+func TestSendTransactionToolHandlerSimulationReverted(t *testing.T) {
+	mockManager := &mockWalletManagerForSendTransaction{
+		MockWalletManager: &wallet.MockWalletManager{},
+		revert: &walletchain.SimulationError{Reason: &walletchain.RevertReason{
+			Kind:    walletchain.RevertKindError,
+			Message: "ERC20: transfer amount exceeds balance",
+		}},
+	}
+	handler := NewSendTransactionTool(mockManager).GetHandler()
+	call := func(skip bool) *mcp.CallToolResult {
+		result, err := handler(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name: "send_transaction",
+				Arguments: map[string]any{
+					"chain":           "ethereum",
+					"from":            "0x1111111111111111111111111111111111111111",
+					"to":              "0x2222222222222222222222222222222222222222",
+					"amount":          "1",
+					"token":           "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+					"skip_simulation": skip,
+				},
+			},
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	result := call(false)
+	require.True(t, result.IsError)
+	assert.Equal(t, 1, mockManager.sendCalls, "a revert is not retried")
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "SIMULATION_REVERTED")
+	assert.Contains(t, textContent.Text, "ERC20: transfer amount exceeds balance")
+
+	result = call(true)
+	assert.False(t, result.IsError)
+}
+
+func TestSendTransactionToolHandlerFeePayer(t *testing.T) {
+	mockManager := &mockWalletManagerForSendTransaction{MockWalletManager: &wallet.MockWalletManager{}}
+	handler := NewSendTransactionTool(mockManager).GetHandler()
+	call := func(chainName, from, to, feePayer string) *mcp.CallToolResult {
+		result, err := handler(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name: "send_transaction",
+				Arguments: map[string]any{
+					"chain":     chainName,
+					"from":      from,
+					"to":        to,
+					"amount":    "0.1",
+					"fee_payer": feePayer,
+				},
+			},
+		})
+		require.NoError(t, err)
+		return result
+	}
+	from := "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK"
+	to := "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY"
+	feePayer := "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+
+	result := call("solana", from, to, feePayer)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	assert.Equal(t, feePayer, mockManager.lastFeePayer)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "- **Fee Payer**: `"+feePayer+"`")
+
+	mockManager.sendCalls = 0
+	mockManager.sendErr = &walletchain.FeePayerUnfundedError{Address: feePayer, Balance: 0, Required: 10000}
+	result = call("solana", from, to, feePayer)
+	require.True(t, result.IsError)
+	assert.Equal(t, 1, mockManager.sendCalls, "an unfunded fee payer is not retried")
+	textContent, ok = mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "INSUFFICIENT_BALANCE")
+
+	result = call("ethereum", "0x1111111111111111111111111111111111111111", "0x2222222222222222222222222222222222222222", feePayer)
+	assert.True(t, result.IsError, "fee payers are Solana only")
+}
+
+func TestSendTransactionToolHandlerDynamicGasStrategy(t *testing.T) {
+	mockManager := &mockWalletManagerForSendTransaction{MockWalletManager: &wallet.MockWalletManager{}}
+	tool := NewSendTransactionTool(mockManager)
+	handler := tool.GetHandler()
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "send_transaction",
+			Arguments: map[string]any{
+				"chain":        "ethereum",
+				"from":         "0x1111111111111111111111111111111111111111",
+				"to":           "0x2222222222222222222222222222222222222222",
+				"amount":       "1",
+				"gas_strategy": "Dynamic",
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.False(t, result.IsError)
+	assert.Equal(t, "dynamic", mockManager.lastGasStrategy)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	// base fee 24 gwei rising 20%, pool twice its normal depth: 1 + 0.2 + 0.25 = 1.45x
+	assert.Contains(t, textContent.Text, "**Gas Strategy**: `dynamic`")
+	assert.Contains(t, textContent.Text, "**Base Fee**: `24 gwei`")
+	assert.Contains(t, textContent.Text, "**Fee Multiplier**: `1.45x`")
+	assert.Contains(t, textContent.Text, "**Max Fee**: `38.8 gwei`")
+	assert.Contains(t, textContent.Text, "**Gas Price**: `38.8 gwei`")
+	assert.NotContains(t, textContent.Text, "Max Fee Capped")
+}
+
+func TestSendTransactionToolHandlerRejectsGasStrategyOnSolana(t *testing.T) {
+	mockManager := &mockWalletManagerForSendTransaction{MockWalletManager: &wallet.MockWalletManager{}}
+	tool := NewSendTransactionTool(mockManager)
+	handler := tool.GetHandler()
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "send_transaction",
+			Arguments: map[string]any{
+				"chain":        "solana",
+				"from":         "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK",
+				"to":           "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY",
+				"amount":       "0.2",
+				"gas_strategy": "fast",
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.True(t, result.IsError)
+	assert.Empty(t, mockManager.lastSendChain)
+}
+
+func TestSendTransactionToolHandlerCommitment(t *testing.T) {
+	mockManager := &mockWalletManagerForSendTransaction{MockWalletManager: &wallet.MockWalletManager{}}
+	handler := NewSendTransactionTool(mockManager).GetHandler()
+	call := func(chain, commitment string) *mcp.CallToolResult {
+		result, err := handler(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name: "send_transaction",
+				Arguments: map[string]any{
+					"chain":      chain,
+					"from":       "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK",
+					"to":         "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY",
+					"amount":     "0.2",
+					"commitment": commitment,
+				},
+			},
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	result := call("solana", "finalized")
+	require.False(t, result.IsError)
+	assert.Equal(t, "finalized", mockManager.lastCommitment)
+
+	result = call("solana", "")
+	require.False(t, result.IsError)
+	assert.Empty(t, mockManager.lastCommitment, "no override keeps the configured commitment")
+
+	mockManager.lastSendChain = ""
+	result = call("solana", "recent")
+	assert.True(t, result.IsError)
+	result = call("ethereum", "confirmed")
+	assert.True(t, result.IsError)
+	assert.Empty(t, mockManager.lastSendChain)
+}
+
+func TestSendTransactionToolHandlerWalletLocked(t *testing.T) {
+	mockManager := &mockWalletManagerForSendTransaction{
+		MockWalletManager: &wallet.MockWalletManager{},
+		locked:            true,
+	}
+	handler := NewSendTransactionTool(mockManager).GetHandler()
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "send_transaction",
+			Arguments: map[string]any{
+				"chain":  "ethereum",
+				"from":   "0x1111111111111111111111111111111111111111",
+				"to":     "0x2222222222222222222222222222222222222222",
+				"amount": "1",
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Empty(t, mockManager.lastEstimateChain)
+	assert.Empty(t, mockManager.lastSendChain)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "`WALLET_LOCKED`")
+}
+
+func TestSendTransactionToolHandlerRejectsDust(t *testing.T) {
+	mockManager := &mockWalletManagerForSendTransaction{MockWalletManager: &wallet.MockWalletManager{}}
+	cfg := config.DefaultConfig()
+	cfg.Chains.BSC.MinTransfer = config.MinTransferConfig{Native: 0.001}
+	handler := NewSendTransactionToolWithConfig(mockManager, nil, nil, cfg).GetHandler()
+
+	args := map[string]any{
+		"chain":     "bsc",
+		"from":      "0x1111111111111111111111111111111111111111",
+		"to":        "0x2222222222222222222222222222222222222222",
+		"amount":    "0.0005",
+		"gas_price": "20",
+	}
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "send_transaction", Arguments: args}}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "below the 0.001 BNB minimum transfer (estimated fee 0.00042 BNB)")
+	assert.Contains(t, textContent.Text, "allow_dust=true")
+	assert.Zero(t, mockManager.sendCalls, "dust must not be sent")
+
+	// Without a configured minimum the amount must still exceed the fee
+	cfg.Chains.BSC.MinTransfer = config.MinTransferConfig{}
+	args["amount"] = "0.0004"
+	handler = NewSendTransactionToolWithConfig(mockManager, nil, nil, cfg).GetHandler()
+	result, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	textContent, ok = mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "does not exceed the estimated fee of 0.00042 BNB")
+	assert.Zero(t, mockManager.sendCalls)
+
+	args["allow_dust"] = true
+	result, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, 1, mockManager.sendCalls)
+}
+
+func TestSendTransactionToolHandlerLargeTransaction(t *testing.T) {
+	mockManager := &mockWalletManagerForSendTransaction{MockWalletManager: &wallet.MockWalletManager{}}
+	cfg := config.DefaultConfig()
+	cfg.Chains.Solana.LargeTxThreshold = config.LargeTxThresholdConfig{Native: 10}
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+	handler := NewSendTransactionToolWithConfig(mockManager, broadcaster, nil, cfg).GetHandler()
+
+	args := map[string]any{
+		"chain":  "solana",
+		"from":   "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK",
+		"to":     "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY",
+		"amount": "25",
+	}
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "send_transaction", Arguments: args}}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "Large Transaction Requires Confirmation")
+	assert.Contains(t, textContent.Text, "`awaiting_confirmation`")
+	assert.Empty(t, mockManager.lastSendChain, "large send must not be broadcast without confirmation")
+
+	warning := <-events
+	assert.Equal(t, event.EventTypeLargeTransactionWarning, warning.Type)
+	assert.Equal(t, false, warning.Data["confirmed"])
+
+	args["confirm_large"] = true
+	result, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, "solana", mockManager.lastSendChain)
+	warning = <-events
+	assert.Equal(t, true, warning.Data["confirmed"])
+
+	// Below the threshold no confirmation is needed
+	mockManager.lastSendChain = ""
+	delete(args, "confirm_large")
+	args["amount"] = "1"
+	_, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "solana", mockManager.lastSendChain)
+}
+
+func TestSendTransactionToolHandlerFeeOnTransferToken(t *testing.T) {
+	mockManager := &mockWalletManagerForSendTransaction{
+		MockWalletManager: &wallet.MockWalletManager{},
+		transferFee: &walletchain.TokenTransferFee{
+			Token:    "0x3333333333333333333333333333333333333333",
+			Sent:     "200",
+			Received: "190",
+			Percent:  5,
+			Source:   walletchain.TransferFeeSourceSimulation,
+		},
+	}
+	handler := NewSendTransactionTool(mockManager).GetHandler()
+
+	result, err := handler(context.Background(), scheduleRequest("send_transaction", map[string]any{
+		"chain":  "bsc",
+		"from":   "0x1111111111111111111111111111111111111111",
+		"to":     "0x2222222222222222222222222222222222222222",
+		"amount": "200",
+		"token":  "0x3333333333333333333333333333333333333333",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	assert.Equal(t, 1, mockManager.sendCalls, "a transfer fee warns but does not block the send")
+
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Transfer Fee**: `5%` (simulation)")
+	assert.Contains(t, text, "- **Expected Received**: `190`")
+	assert.Contains(t, text, "receive less than the amount sent")
+}
+
+func TestSendTransactionToolHandlerEstimateMultipliers(t *testing.T) {
+	mockManager := &mockWalletManagerForSendTransaction{MockWalletManager: &wallet.MockWalletManager{}}
+	cfg := config.DefaultConfig()
+	cfg.Chains.Ethereum.GasLimitMultiplier = 1.2
+	cfg.Chains.Ethereum.FeeMultiplier = 1.1
+	handler := NewSendTransactionToolWithConfig(mockManager, nil, nil, cfg).GetHandler()
+
+	args := map[string]any{
+		"chain":  "ethereum",
+		"from":   "0x1111111111111111111111111111111111111111",
+		"to":     "0x2222222222222222222222222222222222222222",
+		"amount": "1",
+	}
+	result, err := handler(context.Background(), scheduleRequest("send_transaction", args))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	// standard strategy: 24 * 1.25 + 2.5 = 32.5 gwei, then 1.1x on top
+	assert.Contains(t, text, "- **Gas Limit**: `25200`")
+	assert.Contains(t, text, "- **Priority Fee**: `2.75 gwei`")
+	assert.Contains(t, text, "- **Max Fee**: `35.75 gwei`")
+	assert.Contains(t, text, "- **Estimate Multipliers**: `gas limit 1.2x, fee 1.1x`")
+
+	// A per-call override replaces the configured multiplier; an explicit gas price is sent as given
+	args["fee_multiplier"] = 1.5
+	args["gas_price"] = "30"
+	result, err = handler(context.Background(), scheduleRequest("send_transaction", args))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Gas Price**: `30 gwei`")
+	assert.Contains(t, text, "- **Estimate Multipliers**: `gas limit 1.2x, fee 1x (explicit)`")
+
+	solanaArgs := map[string]any{
+		"chain":                   "solana",
+		"from":                    "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK",
+		"to":                      "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY",
+		"amount":                  "0.2",
+		"compute_unit_multiplier": 1.5,
+	}
+	result, err = handler(context.Background(), scheduleRequest("send_transaction", solanaArgs))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Gas Limit**: `31500`")
+	assert.Contains(t, text, "- **Estimate Multipliers**: `compute units 1.5x (override), priority fee 1x`")
+}
+
+func TestSendTransactionToolHandlerRejectsInvalidEstimateMultipliers(t *testing.T) {
+	mockManager := &mockWalletManagerForSendTransaction{MockWalletManager: &wallet.MockWalletManager{}}
+	handler := NewSendTransactionTool(mockManager).GetHandler()
+
+	for name, args := range map[string]map[string]any{
+		"above the bound": {
+			"chain":                "ethereum",
+			"from":                 "0x1111111111111111111111111111111111111111",
+			"to":                   "0x2222222222222222222222222222222222222222",
+			"amount":               "1",
+			"gas_limit_multiplier": 6,
+		},
+		"EVM multiplier on Solana": {
+			"chain":          "solana",
+			"from":           "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK",
+			"to":             "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY",
+			"amount":         "0.2",
+			"fee_multiplier": 1.2,
+		},
+	} {
+		result, err := handler(context.Background(), scheduleRequest("send_transaction", args))
+		require.NoError(t, err, name)
+		assert.True(t, result.IsError, name)
+	}
+	assert.Zero(t, mockManager.sendCalls)
+}
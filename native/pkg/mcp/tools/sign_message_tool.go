@@ -75,6 +75,10 @@ func (t *SignMessageTool) GetHandler() server.ToolHandlerFunc {
 			return toolutils.FormatErrorResult(toolErr), nil
 		}
 
+		if toolErr := toolutils.RequireUnlocked(t.manager, "sign message"); toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
 		t.logger.Debug("Signing message with wallet address",
 			zap.String("address", address),
 			zap.Int("message_length", len(message)),
@@ -15,9 +15,20 @@ type MockWalletManagerWithSignMessage struct {
 	*wallet.MockWalletManager
 	shouldReturnError bool
 	mockSignature     string
+	locked            bool
+	signCalls         int
+}
+
+func (m *MockWalletManagerWithSignMessage) IsUnlocked() bool {
+	return !m.locked
+}
+
+func (m *MockWalletManagerWithSignMessage) IsFrozen() bool {
+	return false
 }
 
 func (m *MockWalletManagerWithSignMessage) SignMessage(ctx context.Context, address, message string) (string, error) {
+	m.signCalls++
 	if m.shouldReturnError {
 		return "", assert.AnError
 	}
@@ -230,6 +241,32 @@ func TestSignMessageToolHandler_ManagerError(t *testing.T) {
 	require.True(t, result.IsError)
 }
 
+func TestSignMessageToolHandler_WalletLocked(t *testing.T) {
+	mockManager := &MockWalletManagerWithSignMessage{
+		MockWalletManager: &wallet.MockWalletManager{},
+		locked:            true,
+	}
+	handler := NewSignMessageTool(mockManager, nil).GetHandler()
+
+	req := mcp.CallToolRequest{}
+	req.Params = mcp.CallToolParams{
+		Name: "sign_message",
+		Arguments: map[string]interface{}{
+			"address": "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+			"message": "Hello, World!",
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Zero(t, mockManager.signCalls)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "`WALLET_LOCKED`")
+}
+
 func TestSignMessageToolHandler_SolanaRawBytes(t *testing.T) {
 	mockManager := &MockWalletManagerWithSignMessage{
 		MockWalletManager: &wallet.MockWalletManager{},
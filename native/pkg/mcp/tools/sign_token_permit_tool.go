@@ -0,0 +1,191 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// Validity window of a signed permit
+const (
+	defaultPermitValidity = 30 * time.Minute
+	maxPermitValidity     = 24 * time.Hour
+)
+
+// Kinds of authorization accepted by sign_token_permit
+const (
+	permitKindPermit   = "permit"
+	permitKindTransfer = "transfer_authorization"
+)
+
+// SignTokenPermitTool implements the MCP "sign_token_permit" tool, signing an
+// EIP-2612 permit or EIP-3009 transfer authorization so a token approval or
+// transfer needs no transaction from the wallet.
+type SignTokenPermitTool struct {
+	manager wallet.IWalletManager
+	logger  *zap.Logger
+	now     func() time.Time
+}
+
+// NewSignTokenPermitTool constructs a SignTokenPermitTool with the given wallet manager.
+func NewSignTokenPermitTool(manager wallet.IWalletManager, logger *zap.Logger) *SignTokenPermitTool {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &SignTokenPermitTool{manager: manager, logger: logger, now: time.Now}
+}
+
+// GetMeta returns the MCP tool definition for "sign_token_permit".
+func (t *SignTokenPermitTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("sign_token_permit",
+		mcp.WithDescription("Sign a gasless token approval on Ethereum/BSC for tokens that support it: an EIP-2612 permit letting a spender (e.g. a swap router) pull tokens without an approve transaction, or an EIP-3009 transfer authorization a relayer submits to move tokens to a recipient. Support is detected on the token contract. Nothing is broadcast; the signature and the calldata that redeems it are returned."),
+		mcp.WithString("chain",
+			mcp.Required(),
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance"),
+		),
+		mcp.WithString("token",
+			mcp.Required(),
+			mcp.Description("Token contract address"),
+		),
+		mcp.WithString("owner",
+			mcp.Required(),
+			mcp.Description("Wallet address that holds the tokens and signs"),
+		),
+		mcp.WithString("kind",
+			mcp.Description("permit (EIP-2612, default) or transfer_authorization (EIP-3009)"),
+		),
+		mcp.WithString("spender",
+			mcp.Description("Address allowed to pull the tokens; required for permit"),
+		),
+		mcp.WithString("to",
+			mcp.Description("Recipient of the tokens; required for transfer_authorization"),
+		),
+		mcp.WithString("amount",
+			mcp.Required(),
+			mcp.Description("Amount in whole tokens (e.g. 100.5)"),
+		),
+		mcp.WithString("valid_for",
+			mcp.Description("How long the signature can be used, as a duration (default 30m, at most 24h)"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "sign_token_permit" tool.
+func (t *SignTokenPermitTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName, err := req.RequireString("chain")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("chain")), nil
+		}
+		normalizedChain, err := toolutils.NormalizeChainName(chainName)
+		if err != nil {
+			if appErr, ok := err.(*errors.Error); ok {
+				return toolutils.FormatErrorResult(appErr), nil
+			}
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+		if normalizedChain == "solana" {
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", "token permits are only available on Ethereum and BSC")), nil
+		}
+
+		request := walletchain.PermitRequest{
+			Token:  strings.TrimSpace(req.GetString("token", "")),
+			Owner:  strings.TrimSpace(req.GetString("owner", "")),
+			Amount: strings.TrimSpace(req.GetString("amount", "")),
+		}
+		for field, value := range map[string]string{"token": request.Token, "owner": request.Owner, "amount": request.Amount} {
+			if value == "" {
+				return toolutils.FormatErrorResult(errors.MissingRequiredFieldError(field)), nil
+			}
+		}
+		if !isValidAddressForChain(normalizedChain, request.Token) {
+			return toolutils.FormatErrorResult(errors.InvalidAddressError(request.Token, normalizedChain)), nil
+		}
+
+		kind := strings.ToLower(strings.TrimSpace(req.GetString("kind", permitKindPermit)))
+		counterpartyField := "spender"
+		switch kind {
+		case permitKindPermit:
+			request.Type = walletchain.PermitTypeEIP2612
+		case permitKindTransfer:
+			request.Type = walletchain.PermitTypeEIP3009
+			counterpartyField = "to"
+		default:
+			return toolutils.FormatErrorResult(errors.ValidationError("kind", "must be permit or transfer_authorization")), nil
+		}
+		request.Spender = strings.TrimSpace(req.GetString(counterpartyField, ""))
+		if request.Spender == "" {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError(counterpartyField)), nil
+		}
+
+		validFor := defaultPermitValidity
+		if value := strings.TrimSpace(req.GetString("valid_for", "")); value != "" {
+			validFor, err = time.ParseDuration(value)
+			if err != nil || validFor <= 0 || validFor > maxPermitValidity {
+				return toolutils.FormatErrorResult(errors.ValidationError("valid_for", "must be a positive duration of at most 24h, e.g. 30m")), nil
+			}
+		}
+		now := t.now()
+		request.ValidBefore = now.Add(validFor).Unix()
+		if request.Type == walletchain.PermitTypeEIP3009 {
+			// Tolerate clock skew between the wallet and the chain
+			request.ValidAfter = now.Add(-time.Minute).Unix()
+		}
+
+		if toolErr := toolutils.RequireUnlocked(t.manager, "sign token permit"); toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
+		signed, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (*walletchain.SignedPermit, error) {
+			return t.manager.SignTokenPermit(attemptCtx, normalizedChain, request)
+		})
+		if err != nil {
+			t.logger.Error("Failed to sign token permit",
+				zap.String("chain", normalizedChain),
+				zap.String("token", request.Token),
+				zap.Error(err))
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("sign token permit", err)), nil
+		}
+		return mcp.NewToolResultText(formatSignedPermit(normalizedChain, kind, signed)), nil
+	}
+}
+
+// formatSignedPermit renders a signed permit with the values needed to redeem it
+func formatSignedPermit(chainName, kind string, signed *walletchain.SignedPermit) string {
+	markdown := "### Token Permit Signed ✅\n\n" +
+		"- **Chain**: `" + chainName + "`\n" +
+		"- **Kind**: `" + kind + "` (" + strings.ToUpper(signed.Type[:3]) + "-" + signed.Type[3:] + ")\n" +
+		"- **Token**: `" + signed.Token + "`\n" +
+		"- **Owner**: `" + signed.Owner + "`\n"
+	if kind == permitKindPermit {
+		markdown += "- **Spender**: `" + signed.Spender + "`\n"
+	} else {
+		markdown += "- **To**: `" + signed.Spender + "`\n" +
+			fmt.Sprintf("- **Valid After**: `%d` (%s)\n", signed.ValidAfter, time.Unix(signed.ValidAfter, 0).UTC().Format(time.RFC3339))
+	}
+	markdown += "- **Value**: `" + signed.Value.String() + "` (smallest units)\n" +
+		"- **Nonce**: `" + signed.Nonce + "`\n" +
+		fmt.Sprintf("- **Valid Before**: `%d` (%s)\n", signed.ValidBefore, time.Unix(signed.ValidBefore, 0).UTC().Format(time.RFC3339)) +
+		"- **Signature**: `" + signed.Signature + "`\n" +
+		fmt.Sprintf("- **v**: `%d`\n", signed.V) +
+		"- **r**: `" + signed.R.Hex() + "`\n" +
+		"- **s**: `" + signed.S.Hex() + "`\n" +
+		"- **Calldata**: `" + signed.Calldata + "`\n\n"
+	if kind == permitKindPermit {
+		markdown += "Pass v, r and s to a router that accepts permits, or submit the calldata to the token before the swap; no approve transaction is needed.\n"
+	} else {
+		markdown += "Any relayer can submit the calldata to the token to complete the transfer; the owner pays no gas.\n"
+	}
+	markdown += "Nothing was broadcast.\n"
+	return markdown
+}
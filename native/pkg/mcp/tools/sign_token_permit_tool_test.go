@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	permitTestToken   = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	permitTestOwner   = "0x1111111111111111111111111111111111111111"
+	permitTestSpender = "0x2222222222222222222222222222222222222222"
+)
+
+func newPermitTestTool(manager *wallet.MockWalletManager) *SignTokenPermitTool {
+	tool := NewSignTokenPermitTool(manager, nil)
+	tool.now = func() time.Time { return time.Unix(1_800_000_000, 0) }
+	return tool
+}
+
+func TestSignTokenPermitToolSignsPermit(t *testing.T) {
+	manager := &wallet.MockWalletManager{}
+	manager.On("IsUnlocked").Return(true)
+	manager.On("SignTokenPermit", mock.Anything, "ethereum", walletchain.PermitRequest{
+		Type:        walletchain.PermitTypeEIP2612,
+		Token:       permitTestToken,
+		Owner:       permitTestOwner,
+		Spender:     permitTestSpender,
+		Amount:      "250",
+		ValidBefore: 1_800_000_000 + 3600,
+	}).Return(&walletchain.SignedPermit{
+		Type:        walletchain.PermitTypeEIP2612,
+		Token:       permitTestToken,
+		Owner:       permitTestOwner,
+		Spender:     permitTestSpender,
+		Value:       big.NewInt(250_000_000),
+		Nonce:       "0",
+		ValidBefore: 1_800_003_600,
+		Signature:   "0xsig",
+		V:           27,
+		R:           common.Hash{1},
+		S:           common.Hash{2},
+		Calldata:    "0xd505accf",
+	}, nil)
+
+	result, err := newPermitTestTool(manager).GetHandler()(context.Background(), scheduleRequest("sign_token_permit", map[string]any{
+		"chain":     "eth",
+		"token":     permitTestToken,
+		"owner":     permitTestOwner,
+		"spender":   permitTestSpender,
+		"amount":    "250",
+		"valid_for": "1h",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Kind**: `permit` (EIP-2612)")
+	assert.Contains(t, text, "- **Spender**: `"+permitTestSpender+"`")
+	assert.Contains(t, text, "- **Value**: `250000000`")
+	assert.Contains(t, text, "- **Valid Before**: `1800003600`")
+	assert.Contains(t, text, "- **v**: `27`")
+	assert.Contains(t, text, "- **Calldata**: `0xd505accf`")
+	assert.Contains(t, text, "Nothing was broadcast.")
+	manager.AssertExpectations(t)
+}
+
+func TestSignTokenPermitToolTransferAuthorization(t *testing.T) {
+	manager := &wallet.MockWalletManager{}
+	manager.On("IsUnlocked").Return(true)
+	manager.On("SignTokenPermit", mock.Anything, "bsc", mock.MatchedBy(func(request walletchain.PermitRequest) bool {
+		return request.Type == walletchain.PermitTypeEIP3009 && request.Spender == permitTestSpender &&
+			request.ValidAfter == 1_800_000_000-60 && request.ValidBefore == 1_800_000_000+30*60
+	})).Return(&walletchain.SignedPermit{
+		Type:        walletchain.PermitTypeEIP3009,
+		Token:       permitTestToken,
+		Owner:       permitTestOwner,
+		Spender:     permitTestSpender,
+		Value:       big.NewInt(1),
+		Nonce:       common.Hash{9}.Hex(),
+		ValidAfter:  1_799_999_940,
+		ValidBefore: 1_800_001_800,
+	}, nil)
+
+	result, err := newPermitTestTool(manager).GetHandler()(context.Background(), scheduleRequest("sign_token_permit", map[string]any{
+		"chain":  "bsc",
+		"token":  permitTestToken,
+		"owner":  permitTestOwner,
+		"kind":   "transfer_authorization",
+		"to":     permitTestSpender,
+		"amount": "1",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Kind**: `transfer_authorization` (EIP-3009)")
+	assert.Contains(t, text, "- **To**: `"+permitTestSpender+"`")
+	assert.Contains(t, text, "relayer")
+	manager.AssertExpectations(t)
+}
+
+func TestSignTokenPermitToolRejectsUnsupportedToken(t *testing.T) {
+	manager := &wallet.MockWalletManager{}
+	manager.On("IsUnlocked").Return(true)
+	manager.On("SignTokenPermit", mock.Anything, "ethereum", mock.Anything).
+		Return(nil, fmt.Errorf("%w: %s does not implement eip2612", walletchain.ErrPermitNotSupported, permitTestToken))
+
+	result, err := newPermitTestTool(manager).GetHandler()(context.Background(), scheduleRequest("sign_token_permit", map[string]any{
+		"chain": "eth", "token": permitTestToken, "owner": permitTestOwner, "spender": permitTestSpender, "amount": "1",
+	}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "regular approve transaction")
+}
+
+func TestSignTokenPermitToolValidatesArguments(t *testing.T) {
+	manager := &wallet.MockWalletManager{}
+	handler := newPermitTestTool(manager).GetHandler()
+	cases := map[string]map[string]any{
+		"solana":            {"chain": "sol", "token": permitTestToken, "owner": permitTestOwner, "spender": permitTestSpender, "amount": "1"},
+		"missing spender":   {"chain": "eth", "token": permitTestToken, "owner": permitTestOwner, "amount": "1"},
+		"missing recipient": {"chain": "eth", "token": permitTestToken, "owner": permitTestOwner, "kind": "transfer_authorization", "spender": permitTestSpender, "amount": "1"},
+		"unknown kind":      {"chain": "eth", "token": permitTestToken, "owner": permitTestOwner, "kind": "approve", "spender": permitTestSpender, "amount": "1"},
+		"invalid token":     {"chain": "eth", "token": "usdc", "owner": permitTestOwner, "spender": permitTestSpender, "amount": "1"},
+		"too long validity": {"chain": "eth", "token": permitTestToken, "owner": permitTestOwner, "spender": permitTestSpender, "amount": "1", "valid_for": "48h"},
+		"missing amount":    {"chain": "eth", "token": permitTestToken, "owner": permitTestOwner, "spender": permitTestSpender},
+	}
+	for name, args := range cases {
+		t.Run(name, func(t *testing.T) {
+			result, err := handler(context.Background(), scheduleRequest("sign_token_permit", args))
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+		})
+	}
+	manager.AssertNotCalled(t, "SignTokenPermit", mock.Anything, mock.Anything, mock.Anything)
+}
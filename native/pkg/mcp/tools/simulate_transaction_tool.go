@@ -54,6 +54,9 @@ func (t *SimulateTransactionTool) GetMeta() mcp.Tool {
 		mcp.WithString("token",
 			mcp.Description("Token contract address (optional, native token if not provided)"),
 		),
+		mcp.WithString("abi",
+			mcp.Description("Optional contract ABI (JSON) used to decode custom revert errors"),
+		),
 	)
 }
 
@@ -90,7 +93,8 @@ func (t *SimulateTransactionTool) GetHandler() server.ToolHandlerFunc {
 		token := req.GetString("token", "")
 
 		// Validate chain support
-		if chain != "ethereum" && chain != "bsc" && chain != "ETH" {
+		chain = wallet.NormalizeChain(chain)
+		if chain != "ethereum" && chain != "bsc" {
 			toolErr := errors.TokenNotSupportedError(chain, "all")
 			return toolutils.FormatErrorResult(toolErr), nil
 		}
@@ -102,6 +106,14 @@ func (t *SimulateTransactionTool) GetHandler() server.ToolHandlerFunc {
 			return toolutils.FormatErrorResult(toolErr), nil
 		}
 
+		if result.RevertReason != nil {
+			reason, err := redecodeRevertReason(result.RevertReason, req.GetString("abi", ""))
+			if err != nil {
+				return toolutils.FormatErrorResult(errors.ValidationError("abi", err.Error())), nil
+			}
+			result.RevertReason = reason
+		}
+
 		// Convert result to JSON
 		resultJSON, err := json.Marshal(result)
 		if err != nil {
@@ -141,6 +153,7 @@ func (t *SimulateTransactionTool) GetHandler() server.ToolHandlerFunc {
 					markdown += "  - " + error + "\n"
 				}
 			}
+			markdown += formatRevertReasonMarkdown(result.RevertReason)
 		}
 
 		// Create a tool result with both markdown text and JSON data
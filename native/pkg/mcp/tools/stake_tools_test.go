@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	stakeTestOwner   = "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK"
+	stakeTestVote    = "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY"
+	stakeTestAccount = "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+)
+
+func TestDelegateStakeTool(t *testing.T) {
+	manager := &wallet.MockWalletManager{}
+	manager.On("IsUnlocked").Return(true)
+	manager.On("DelegateStake", mock.Anything, stakeTestOwner, stakeTestVote, "2.5").Return(&walletchain.StakeResult{
+		Signature:    "stakeSig",
+		Channel:      "solana-rpc",
+		StakeAccount: stakeTestAccount,
+		VoteAccount:  stakeTestVote,
+		Lamports:     2_500_000_000,
+		Amount:       "2.5",
+	}, nil)
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+
+	result, err := NewDelegateStakeTool(manager, broadcaster, nil).GetHandler()(context.Background(), scheduleRequest("delegate_stake", map[string]any{
+		"owner":        stakeTestOwner,
+		"vote_account": stakeTestVote,
+		"amount":       "2.5",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "### Stake Delegated")
+	assert.Contains(t, text, "- **Stake Account**: `"+stakeTestAccount+"`")
+	assert.Contains(t, text, "- **Amount**: `2.5 SOL` (2500000000 lamports)")
+	assert.Contains(t, text, "deactivate_stake")
+	manager.AssertExpectations(t)
+
+	delegated := <-events
+	assert.Equal(t, event.EventTypeStakeDelegated, delegated.Type)
+	assert.Equal(t, "stakeSig", delegated.Data["tx_hash"])
+	assert.Equal(t, stakeTestAccount, delegated.Data["stake_account"])
+	assert.Equal(t, stakeTestVote, delegated.Data["vote_account"])
+	assert.Equal(t, uint64(2_500_000_000), delegated.Data["lamports"])
+}
+
+func TestDelegateStakeToolErrors(t *testing.T) {
+	manager := &wallet.MockWalletManager{}
+	manager.On("IsUnlocked").Return(true)
+	manager.On("DelegateStake", mock.Anything, stakeTestOwner, stakeTestVote, mock.Anything).
+		Return(nil, fmt.Errorf("%w: validator %s is delinquent", walletchain.ErrInvalidVoteAccount, stakeTestVote)).Once()
+	manager.On("DelegateStake", mock.Anything, stakeTestOwner, stakeTestVote, mock.Anything).
+		Return(nil, fmt.Errorf("%w: staking 10 SOL of 10 SOL leaves less than the 0.01 SOL needed", walletchain.ErrNativeReserveViolation)).Once()
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+	handler := NewDelegateStakeTool(manager, broadcaster, nil).GetHandler()
+	args := map[string]any{"owner": stakeTestOwner, "vote_account": stakeTestVote, "amount": "10"}
+
+	result, err := handler(context.Background(), scheduleRequest("delegate_stake", args))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "currently voting")
+
+	result, err = handler(context.Background(), scheduleRequest("delegate_stake", args))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "above the reserve")
+
+	for name, args := range map[string]map[string]any{
+		"missing amount":       {"owner": stakeTestOwner, "vote_account": stakeTestVote},
+		"missing vote account": {"owner": stakeTestOwner, "amount": "1"},
+		"invalid vote account": {"owner": stakeTestOwner, "vote_account": "0x1111111111111111111111111111111111111111", "amount": "1"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := handler(context.Background(), scheduleRequest("delegate_stake", args))
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+		})
+	}
+	manager.AssertNumberOfCalls(t, "DelegateStake", 2)
+	assert.Empty(t, events, "failed delegations emit no event")
+}
+
+func TestDeactivateStakeTool(t *testing.T) {
+	manager := &wallet.MockWalletManager{}
+	manager.On("IsUnlocked").Return(true)
+	manager.On("DeactivateStake", mock.Anything, stakeTestOwner, stakeTestAccount).Return(&walletchain.StakeResult{
+		Signature:    "deactivateSig",
+		StakeAccount: stakeTestAccount,
+		VoteAccount:  stakeTestVote,
+		Lamports:     1_000_000_000,
+		Amount:       "1",
+	}, nil)
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+
+	result, err := NewDeactivateStakeTool(manager, broadcaster, nil).GetHandler()(context.Background(), scheduleRequest("deactivate_stake", map[string]any{
+		"owner":         stakeTestOwner,
+		"stake_account": stakeTestAccount,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "### Stake Deactivated")
+	assert.Contains(t, text, "withdraw_stake")
+	manager.AssertExpectations(t)
+
+	deactivated := <-events
+	assert.Equal(t, event.EventTypeStakeDeactivated, deactivated.Type)
+	assert.Equal(t, "deactivateSig", deactivated.Data["tx_hash"])
+	assert.Equal(t, stakeTestOwner, deactivated.Data["owner"])
+}
+
+func TestWithdrawStakeTool(t *testing.T) {
+	manager := &wallet.MockWalletManager{}
+	manager.On("IsUnlocked").Return(true)
+	manager.On("WithdrawStake", mock.Anything, stakeTestOwner, stakeTestAccount, "", walletchain.AmountMax).Return(&walletchain.StakeResult{
+		Signature:    "withdrawSig",
+		StakeAccount: stakeTestAccount,
+		Recipient:    stakeTestOwner,
+		Lamports:     1_002_282_880,
+		Amount:       "1.00228288",
+	}, nil)
+	manager.On("WithdrawStake", mock.Anything, stakeTestOwner, stakeTestAccount, mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("%w: %s is owned by 11111111111111111111111111111111", walletchain.ErrStakeAccountNotFound, stakeTestAccount))
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+	handler := NewWithdrawStakeTool(manager, broadcaster, nil).GetHandler()
+
+	// amount defaults to max and to to the owner
+	result, err := handler(context.Background(), scheduleRequest("withdraw_stake", map[string]any{
+		"owner":         stakeTestOwner,
+		"stake_account": stakeTestAccount,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "### Stake Withdrawn")
+	assert.Contains(t, text, "- **To**: `"+stakeTestOwner+"`")
+	assert.Contains(t, text, "- **Amount**: `1.00228288 SOL`")
+
+	withdrawn := <-events
+	assert.Equal(t, event.EventTypeStakeWithdrawn, withdrawn.Type)
+	assert.Equal(t, stakeTestOwner, withdrawn.Data["to"])
+	assert.Equal(t, uint64(1_002_282_880), withdrawn.Data["lamports"])
+
+	result, err = handler(context.Background(), scheduleRequest("withdraw_stake", map[string]any{
+		"owner":         stakeTestOwner,
+		"stake_account": stakeTestAccount,
+		"to":            stakeTestVote,
+		"amount":        "0.5",
+	}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "stake account not found")
+
+	result, err = handler(context.Background(), scheduleRequest("withdraw_stake", map[string]any{
+		"owner":         stakeTestOwner,
+		"stake_account": stakeTestAccount,
+		"to":            "0x1111111111111111111111111111111111111111",
+	}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	manager.AssertNumberOfCalls(t, "WithdrawStake", 2)
+	assert.Empty(t, events)
+}
@@ -0,0 +1,158 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/chains/solana/broadcast"
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// SubmitBundleTool implements the MCP "submit_bundle" tool for landing several Solana transactions atomically via Jito.
+type SubmitBundleTool struct {
+	manager wallet.IWalletManager
+}
+
+// NewSubmitBundleTool constructs a SubmitBundleTool with the given wallet manager.
+func NewSubmitBundleTool(manager wallet.IWalletManager) *SubmitBundleTool {
+	return &SubmitBundleTool{manager: manager}
+}
+
+// GetMeta returns the MCP tool definition for "submit_bundle".
+func (t *SubmitBundleTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("submit_bundle",
+		mcp.WithDescription("Sign an ordered list of Solana transactions and submit them as a Jito bundle that lands all together or not at all"),
+		mcp.WithString("from",
+			mcp.Required(),
+			mcp.Description("Solana wallet address that signs every transaction and pays the tip"),
+		),
+		mcp.WithArray("transactions",
+			mcp.Required(),
+			mcp.Description(fmt.Sprintf("Ordered transactions (1-%d). Each item is either a SOL transfer {\"to\", \"amount\"} or a base64-encoded pre-built transaction {\"transaction\"} such as a DEX swap", walletchain.MaxBundleTransactions)),
+			mcp.MinItems(1),
+			mcp.MaxItems(walletchain.MaxBundleTransactions),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"to":          map[string]any{"type": "string", "description": "Recipient address for a SOL transfer"},
+					"amount":      map[string]any{"type": "string", "description": "SOL amount for a transfer"},
+					"transaction": map[string]any{"type": "string", "description": "Base64-encoded unsigned transaction to sign"},
+				},
+			}),
+		),
+		mcp.WithNumber("tip_lamports",
+			mcp.Description("Jito tip in lamports (optional, defaults to the configured base tip and is capped at the configured max tip)"),
+			mcp.Min(0),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "submit_bundle" tool.
+// Bundles are submitted once and never retried, since a retry could land the transactions twice.
+func (t *SubmitBundleTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		from, err := req.RequireString("from")
+		if err != nil || strings.TrimSpace(from) == "" {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("from")), nil
+		}
+
+		specs, toolErr := parseBundleSpecs(req.GetArguments()["transactions"])
+		if toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
+		tip := req.GetFloat("tip_lamports", 0)
+		if tip < 0 {
+			return toolutils.FormatErrorResult(errors.ValidationError("tip_lamports", "tip cannot be negative")), nil
+		}
+
+		if toolErr := toolutils.RequireUnlocked(t.manager, "submit bundle"); toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
+		result, err := t.manager.SubmitBundle(ctx, from, specs, uint64(tip))
+		if err != nil {
+			return toolutils.FormatErrorResult(classifyBundleError(err)), nil
+		}
+
+		markdown := "### Bundle Submitted\n\n" +
+			"- **Bundle ID**: `" + result.BundleID + "`\n" +
+			"- **Status**: `" + result.Status + "`\n" +
+			fmt.Sprintf("- **Transactions**: `%d` (+1 tip)\n", len(specs)) +
+			fmt.Sprintf("- **Tip**: `%d lamports`\n", result.TipLamports)
+		if result.RejectionReason != "" {
+			markdown += "- **Rejection Reason**: `" + result.RejectionReason + "`\n"
+		}
+
+		if len(result.Signatures) > 0 {
+			markdown += "\n#### Signatures\n\n"
+			for i, signature := range result.Signatures {
+				label := fmt.Sprintf("%d", i+1)
+				if i == len(result.Signatures)-1 {
+					label = "tip"
+				}
+				markdown += "- **" + label + "**: `" + signature + "`\n"
+			}
+		}
+
+		return mcp.NewToolResultText(markdown), nil
+	}
+}
+
+// parseBundleSpecs validates the raw "transactions" argument
+func parseBundleSpecs(raw any) ([]walletchain.BundleTransactionSpec, *errors.Error) {
+	items, ok := raw.([]any)
+	if !ok || len(items) == 0 {
+		return nil, errors.MissingRequiredFieldError("transactions")
+	}
+	if len(items) > walletchain.MaxBundleTransactions {
+		return nil, errors.ValidationError("transactions",
+			fmt.Sprintf("a bundle holds at most %d transactions plus the tip, got %d", walletchain.MaxBundleTransactions, len(items)))
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, errors.ValidationError("transactions", err.Error())
+	}
+	var specs []walletchain.BundleTransactionSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return nil, errors.ValidationError("transactions", "each item must be an object with string fields")
+	}
+
+	for i, spec := range specs {
+		switch {
+		case spec.Transaction != "" && (spec.To != "" || spec.Amount != ""):
+			return nil, errors.ValidationError("transactions", fmt.Sprintf("item %d: set either transaction or to/amount, not both", i))
+		case spec.Transaction == "" && (spec.To == "" || spec.Amount == ""):
+			return nil, errors.ValidationError("transactions", fmt.Sprintf("item %d: transfers require both to and amount", i))
+		}
+	}
+	return specs, nil
+}
+
+// classifyBundleError surfaces Jito rejections and configuration problems with actionable messages
+func classifyBundleError(err error) *errors.Error {
+	switch {
+	case stdErrors.Is(err, broadcast.ErrBundleTooLarge):
+		return errors.ValidationError("transactions", err.Error())
+	case stdErrors.Is(err, broadcast.ErrBundleRejected):
+		return errors.New(errors.ErrRPCFailure, "Bundle rejected by Jito").
+			WithDetails(err.Error()).
+			WithSuggestion("Check the rejection reason, adjust the tip or transactions, and resubmit")
+	case stdErrors.Is(err, broadcast.ErrChannelNotFound), stdErrors.Is(err, broadcast.ErrChannelDisabled):
+		return errors.New(errors.ErrInternal, "Jito bundles are not available").
+			WithDetails(err.Error()).
+			WithSuggestion("Enable chains.solana.jito in the configuration")
+	default:
+		return toolutils.ClassifyError("submit bundle", err)
+	}
+}
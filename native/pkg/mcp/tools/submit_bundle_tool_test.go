@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/chains/solana/broadcast"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const bundleTestAddress = "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK"
+
+func newSubmitBundleRequest(transactions []any) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "submit_bundle",
+			Arguments: map[string]any{
+				"from":         bundleTestAddress,
+				"transactions": transactions,
+				"tip_lamports": float64(10000),
+			},
+		},
+	}
+}
+
+func TestSubmitBundleToolHandlerSuccess(t *testing.T) {
+	mockManager := &wallet.MockWalletManager{}
+	mockManager.On("IsUnlocked").Return(true)
+	specs := []walletchain.BundleTransactionSpec{
+		{Transaction: "AQID"},
+		{To: "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY", Amount: "0.1"},
+	}
+	mockManager.On("SubmitBundle", mock.Anything, bundleTestAddress, specs, uint64(10000)).Return(&broadcast.BundleResult{
+		BundleID:    "bundle-abc",
+		Status:      "confirmed",
+		Signatures:  []string{"sig1", "sig2", "sigTip"},
+		TipLamports: 10000,
+	}, nil)
+
+	handler := NewSubmitBundleTool(mockManager).GetHandler()
+	result, err := handler(context.Background(), newSubmitBundleRequest([]any{
+		map[string]any{"transaction": "AQID"},
+		map[string]any{"to": "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY", "amount": "0.1"},
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	mockManager.AssertExpectations(t)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "### Bundle Submitted")
+	assert.Contains(t, textContent.Text, "**Bundle ID**: `bundle-abc`")
+	assert.Contains(t, textContent.Text, "**Status**: `confirmed`")
+	assert.Contains(t, textContent.Text, "**tip**: `sigTip`")
+}
+
+func TestSubmitBundleToolHandlerValidation(t *testing.T) {
+	handler := NewSubmitBundleTool(&wallet.MockWalletManager{}).GetHandler()
+
+	tooMany := make([]any, walletchain.MaxBundleTransactions+1)
+	for i := range tooMany {
+		tooMany[i] = map[string]any{"transaction": "AQID"}
+	}
+
+	cases := map[string][]any{
+		"empty":         {},
+		"too many":      tooMany,
+		"incomplete":    {map[string]any{"to": "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY"}},
+		"ambiguous":     {map[string]any{"transaction": "AQID", "to": "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY", "amount": "1"}},
+		"not an object": {"AQID"},
+	}
+	for name, transactions := range cases {
+		result, err := handler(context.Background(), newSubmitBundleRequest(transactions))
+		require.NoError(t, err, name)
+		assert.True(t, result.IsError, name)
+	}
+}
+
+func TestSubmitBundleToolHandlerSurfacesRejection(t *testing.T) {
+	mockManager := &wallet.MockWalletManager{}
+	mockManager.On("IsUnlocked").Return(true)
+	mockManager.On("SubmitBundle", mock.Anything, bundleTestAddress, mock.Anything, uint64(10000)).
+		Return(nil, fmt.Errorf("%w: bundle contains an already processed transaction", broadcast.ErrBundleRejected))
+
+	handler := NewSubmitBundleTool(mockManager).GetHandler()
+	result, err := handler(context.Background(), newSubmitBundleRequest([]any{map[string]any{"transaction": "AQID"}}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "already processed transaction")
+}
+
+func TestSubmitBundleToolHandlerWalletLocked(t *testing.T) {
+	mockManager := &wallet.MockWalletManager{}
+	mockManager.On("IsUnlocked").Return(false)
+	mockManager.On("IsFrozen").Return(false)
+
+	handler := NewSubmitBundleTool(mockManager).GetHandler()
+	result, err := handler(context.Background(), newSubmitBundleRequest([]any{map[string]any{"transaction": "AQID"}}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	mockManager.AssertNotCalled(t, "SubmitBundle", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "`WALLET_LOCKED`")
+}
@@ -3,12 +3,17 @@ package tools
 
 import (
 	"context"
+	stdErrors "errors"
 	"fmt"
+	"math/big"
+	"strings"
 
 	"github.com/algonius/algonius-wallet/native/pkg/dex"
 	"github.com/algonius/algonius-wallet/native/pkg/dex/providers"
 	"github.com/algonius/algonius-wallet/native/pkg/errors"
 	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"go.uber.org/zap"
@@ -18,6 +23,12 @@ import (
 type SwapTokensToolNew struct {
 	dexAggregator dex.IDEXAggregator
 	logger        *zap.Logger
+
+	// manager holds the keys swaps are signed with and wraps the native coin
+	// for swaps that sell its wrapped token; autoWrap is whether that happens
+	// when a call does not say
+	manager  wallet.IWalletManager
+	autoWrap bool
 }
 
 // NewSwapTokensToolNew creates a new SwapTokensToolNew instance with default configuration
@@ -43,6 +54,20 @@ func NewSwapTokensToolWithAggregator(aggregator dex.IDEXAggregator, logger *zap.
 	}
 }
 
+// SetWalletManager sets the wallet whose keys sign swaps. Without one every
+// swap is refused.
+func (t *SwapTokensToolNew) SetWalletManager(manager wallet.IWalletManager) {
+	t.manager = manager
+}
+
+// SetNativeWrapping lets swaps selling the wrapped native token wrap the
+// native coin the sender is short of through manager. autoWrap is the default
+// for calls that do not set auto_wrap.
+func (t *SwapTokensToolNew) SetNativeWrapping(manager wallet.IWalletManager, autoWrap bool) {
+	t.manager = manager
+	t.autoWrap = autoWrap
+}
+
 // Definition returns the tool definition for MCP
 func (t *SwapTokensToolNew) Definition() mcp.Tool {
 	return mcp.Tool{
@@ -77,6 +102,25 @@ func (t *SwapTokensToolNew) Definition() mcp.Tool {
 					"description": "Maximum acceptable slippage (e.g., 0.005 for 0.5%)",
 					"default":     0.005,
 				},
+				"compare_net_of_fees": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Pick the provider with the best output after network fees instead of the best gross output",
+					"default":     false,
+				},
+				"allow_partial_fill": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Accept swapping only part of the amount when liquidity runs out, leaving the rest with the sender, even if dex.partial_fill is revert. The result reports the filled and leftover amounts",
+					"default":     false,
+				},
+				"fee_on_transfer": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Set when either token is known to take a fee on every transfer. Tokens the quote reports as taxed are detected automatically; on EVM chains both are swapped through router functions that support fee-on-transfer tokens",
+					"default":     false,
+				},
+				"auto_wrap": map[string]interface{}{
+					"type":        "boolean",
+					"description": "When from_token is the wrapped native token (WETH, WBNB, WSOL), wrap the native coin the sender is short of before swapping. Defaults to dex.auto_wrap",
+				},
 			},
 			Required: []string{"chain", "from_token", "to_token", "amount", "from_address"},
 		},
@@ -98,6 +142,13 @@ func (t *SwapTokensToolNew) Execute(ctx context.Context, request mcp.CallToolReq
 	amount, _ := arguments["amount"].(string)
 	fromAddress, _ := arguments["from_address"].(string)
 	slippage, _ := arguments["slippage"].(float64)
+	compareNetOfFees, _ := arguments["compare_net_of_fees"].(bool)
+	feeOnTransfer, _ := arguments["fee_on_transfer"].(bool)
+	allowPartialFill, _ := arguments["allow_partial_fill"].(bool)
+	autoWrap, ok := arguments["auto_wrap"].(bool)
+	if !ok {
+		autoWrap = t.autoWrap
+	}
 
 	// Set default slippage if not provided
 	if slippage == 0 {
@@ -142,22 +193,60 @@ func (t *SwapTokensToolNew) Execute(ctx context.Context, request mcp.CallToolReq
 		FromAddress: fromAddress,
 		ToAddress:   fromAddress, // Use same address as recipient
 		ChainID:     chainID,
-		PrivateKey:  "0x0000000000000000000000000000000000000000000000000000000000000001", // Mock private key for demo
+		CompareNetOfFees: compareNetOfFees,
+		AllowPartialFill: allowPartialFill,
 	}
 
 	// Get quote first
 	quote, err := t.dexAggregator.GetBestQuote(ctx, swapParams)
 	if err != nil {
-		toolErr := errors.InternalError("get swap quote", err)
+		toolErr := toolutils.ClassifyError("get swap quote", err)
 		return toolutils.FormatErrorResult(toolErr), nil
 	}
 
-	// Execute the swap
-	result, err := t.dexAggregator.ExecuteSwapWithProvider(ctx, quote.Provider, swapParams)
+	// Swaps are signed with the wallet's own key for from_address
+	privateKey, err := t.signingKey(ctx, fromAddress)
+	if err != nil {
+		toolErr := toolutils.ClassifyError("sign swap", err)
+		return toolutils.FormatErrorResult(toolErr), nil
+	}
+	swapParams.PrivateKey = privateKey
+
+	// The amount sold counts against the spend limits like a send
+	reservation, err := t.manager.ReserveSpend(chain, fromAddress, fromAddress, amount, fromToken)
 	if err != nil {
-		toolErr := errors.InternalError("execute swap", err)
+		toolErr := toolutils.ClassifyError("execute swap", err)
+		return toolutils.FormatErrorResult(toolErr), nil
+	}
+	defer reservation.Release()
+
+	// Wrapping only starts once a route is known to exist
+	var wrapped *walletchain.WrapResult
+	if autoWrap && walletchain.IsWrappedNative(wallet.NormalizeChain(chain), fromToken) {
+		wrapped, err = t.wrapShortfall(ctx, wallet.NormalizeChain(chain), fromAddress, fromToken, amount)
+		if err != nil {
+			toolErr := toolutils.ClassifyError("wrap native token", err)
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+	}
+
+	// The plain router functions revert when a taxed token delivers less than
+	// the pair expects, so flagged or detected tokens use the supporting variants
+	swapParams.FeeOnTransfer = chainID != "501" && (feeOnTransfer || quote.TransferFeePercent() > 0)
+
+	// Execute the swap. A partial fill that was not allowed has still
+	// happened, so it is reported with the result rather than as a failure.
+	result, err := t.dexAggregator.ExecuteSwapWithProvider(ctx, quote.Provider, swapParams)
+	if err != nil && (result == nil || !stdErrors.Is(err, dex.ErrPartialFill)) {
+		toolErr := toolutils.ClassifyError("execute swap", err)
 		return toolutils.FormatErrorResult(toolErr), nil
 	}
+	reservation.Commit(result.TxHash)
+
+	outcome := "The swap has been executed successfully!"
+	if result.PartialFill {
+		outcome = "The swap was only partially filled."
+	}
 
 	// Format success response
 	markdown := fmt.Sprintf(`### Token Swap Executed
@@ -173,8 +262,8 @@ func (t *SwapTokensToolNew) Execute(ctx context.Context, request mcp.CallToolReq
 - **Estimated Fee**: %s
 - **Transaction Hash**: %s
 - **Status**: %s
-
-The swap has been executed successfully!`, 
+%s
+%s`, 
 		chain,
 		quote.Provider,
 		fromAddress,
@@ -185,19 +274,130 @@ The swap has been executed successfully!`,
 		slippage*100,
 		result.ActualFee,
 		result.TxHash,
-		result.Status)
+		result.Status,
+		formatSwapWrap(wrapped)+formatSwapFees(quote)+formatSwapTransferFee(quote, result, feeOnTransfer)+formatSwapPartialFill(result),
+		outcome)
 
 	return mcp.NewToolResultText(markdown), nil
 }
 
-// mapChainNameToID maps human-readable chain names to chain IDs
+// signingKey returns the key of fromAddress held by the wallet manager
+func (t *SwapTokensToolNew) signingKey(ctx context.Context, fromAddress string) (string, error) {
+	if t.manager == nil {
+		return "", stdErrors.New("no wallet is available to sign the swap")
+	}
+	return t.manager.GetPrivateKeyForAddress(ctx, fromAddress)
+}
+
+// wrapShortfall wraps the native coin fromAddress needs on top of its
+// wrapped token balance to sell amount of it, returning nil when the balance
+// already covers it
+func (t *SwapTokensToolNew) wrapShortfall(ctx context.Context, chainName, fromAddress, fromToken, amount string) (*walletchain.WrapResult, error) {
+	if t.manager == nil {
+		return nil, stdErrors.New("native wrapping is not available")
+	}
+	needed, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok {
+		return nil, fmt.Errorf("invalid amount: %s", amount)
+	}
+	balance, err := t.manager.GetBalance(ctx, fromAddress, fromToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s balance: %w", fromToken, err)
+	}
+	held, ok := new(big.Rat).SetString(strings.TrimSpace(balance))
+	if !ok {
+		return nil, fmt.Errorf("invalid %s balance: %s", fromToken, balance)
+	}
+	if held.Cmp(needed) >= 0 {
+		return nil, nil
+	}
+	wrapped, _ := walletchain.WrappedNative(chainName)
+	shortfall := new(big.Rat).Sub(needed, held).FloatString(wrapped.Decimals)
+	shortfall = strings.TrimRight(strings.TrimRight(shortfall, "0"), ".")
+	t.logger.Info("Wrapping native coin for swap",
+		zap.String("chain", chainName),
+		zap.String("from", fromAddress),
+		zap.String("amount", shortfall))
+	return t.manager.WrapNative(ctx, chainName, fromAddress, shortfall)
+}
+
+// formatSwapWrap renders the wrap made for the swap, if any
+func formatSwapWrap(wrapped *walletchain.WrapResult) string {
+	if wrapped == nil {
+		return ""
+	}
+	return fmt.Sprintf("- **Wrapped First**: %s %s into %s (tx %s)\n", wrapped.Amount, wrapped.NativeSymbol, wrapped.Wrapped.Symbol, wrapped.TxHash)
+}
+
+// formatSwapFees renders the itemized fees of the selected quote; unknown fees are omitted
+func formatSwapFees(quote *dex.SwapQuote) string {
+	if quote.Fees == nil {
+		return ""
+	}
+	fees := "\n#### Fees\n\n"
+	for _, item := range []struct{ label, amount, unit string }{
+		{"Protocol Fee", quote.Fees.ProtocolFee, quote.ToToken},
+		{"Aggregator Fee", quote.Fees.AggregatorFee, quote.ToToken},
+		{"Network Fee", quote.Fees.NetworkFee, "native"},
+	} {
+		if item.amount != "" {
+			fees += fmt.Sprintf("- **%s**: %s %s\n", item.label, item.amount, item.unit)
+		}
+	}
+	if quote.NetToAmount != "" {
+		fees += fmt.Sprintf("- **Net Amount Out**: %s %s\n", quote.NetToAmount, quote.ToToken)
+	}
+	return fees
+}
+
+// formatSwapTransferFee warns that fee-on-transfer tokens make the received
+// amount differ from the quoted output. It renders nothing for untaxed swaps
+// the caller did not flag.
+func formatSwapTransferFee(quote *dex.SwapQuote, result *dex.SwapResult, flagged bool) string {
+	percent := quote.TransferFeePercent()
+	if percent <= 0 && !flagged {
+		return ""
+	}
+	section := "\n#### Transfer Fee\n\n"
+	if percent > 0 {
+		section += fmt.Sprintf("- **Detected Fee**: %s%%\n", formatPercent(percent))
+		out, okOut := new(big.Rat).SetString(result.ToAmount)
+		kept, okKept := new(big.Rat).SetString(formatPercent(100 - percent))
+		if okOut && okKept {
+			expected := new(big.Rat).Mul(out, kept.Quo(kept, big.NewRat(100, 1))).FloatString(18)
+			expected = strings.TrimRight(strings.TrimRight(expected, "0"), ".")
+			section += fmt.Sprintf("- **Expected Received**: %s %s\n", expected, quote.ToToken)
+		}
+	} else {
+		section += "- **Detected Fee**: unknown (flagged by the caller)\n"
+	}
+	if result.SwapFunction != "" {
+		section += fmt.Sprintf("- **Swap Function**: %s\n", result.SwapFunction)
+	}
+	section += "- **⚠️ Warning**: a token in this swap takes a fee on every transfer, so the amount received will be lower than the amount out\n"
+	return section
+}
+
+// formatSwapPartialFill reports how much of the requested amount a partially
+// filled swap used. It renders nothing for swaps that filled in full.
+func formatSwapPartialFill(result *dex.SwapResult) string {
+	if !result.PartialFill {
+		return ""
+	}
+	return "\n#### Partial Fill\n\n" +
+		fmt.Sprintf("- **Requested Amount In**: %s %s\n", result.RequestedFromAmount, result.FromToken) +
+		fmt.Sprintf("- **Filled Amount In**: %s %s\n", result.FromAmount, result.FromToken) +
+		fmt.Sprintf("- **Leftover Returned**: %s %s\n", result.LeftoverFromAmount, result.FromToken) +
+		"- **⚠️ Warning**: the swap did not fully execute; the leftover was not swapped and remains with the sender\n"
+}
+
 func (t *SwapTokensToolNew) mapChainNameToID(chainName string) string {
-	switch chainName {
-	case "ethereum", "eth":
+	switch wallet.NormalizeChain(chainName) {
+	case "ethereum":
 		return "1"
-	case "bsc", "binance":
+	case "bsc":
 		return "56"
-	case "solana", "sol":
+	case "solana":
 		return "501"
 	default:
 		return ""
@@ -0,0 +1,210 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
+	"github.com/algonius/algonius-wallet/native/pkg/dex/providers"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// signingWallet returns a wallet manager holding a key for from
+func signingWallet(from string) *wallet.MockWalletManager {
+	manager := &wallet.MockWalletManager{}
+	manager.On("GetPrivateKeyForAddress", mock.Anything, from).Return("0xwalletkey", nil)
+	manager.On("ReserveSpend", mock.Anything, from, from, mock.Anything, mock.Anything).Return(nil, nil)
+	return manager
+}
+
+// keyRecordingProvider records the key each swap is executed with
+type keyRecordingProvider struct {
+	*providers.MockProvider
+	privateKeys []string
+}
+
+func (p *keyRecordingProvider) ExecuteSwap(ctx context.Context, params dex.SwapParams) (*dex.SwapResult, error) {
+	p.privateKeys = append(p.privateKeys, params.PrivateKey)
+	return p.MockProvider.ExecuteSwap(ctx, params)
+}
+
+func TestSwapTokensToolSignsWithWalletKey(t *testing.T) {
+	const from = "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+	provider := &keyRecordingProvider{MockProvider: providers.NewMockProvider(providers.MockConfig{}, zap.NewNop())}
+	aggregator := dex.NewDEXAggregator(zap.NewNop())
+	require.NoError(t, aggregator.RegisterProvider(provider))
+	tool := NewSwapTokensToolWithAggregator(aggregator, zap.NewNop())
+	args := map[string]any{
+		"chain":        "ethereum",
+		"from_token":   "ETH",
+		"to_token":     "USDC",
+		"amount":       "1",
+		"from_address": from,
+	}
+
+	// Without a wallet there is no key to sign with
+	result, err := tool.Execute(context.Background(), scheduleRequest("swap_tokens", args))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Empty(t, provider.privateKeys)
+
+	locked := &wallet.MockWalletManager{}
+	locked.On("GetPrivateKeyForAddress", mock.Anything, from).Return("", wallet.ErrWalletLocked)
+	tool.SetWalletManager(locked)
+	result, err = tool.Execute(context.Background(), scheduleRequest("swap_tokens", args))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "WALLET_LOCKED")
+	assert.Empty(t, provider.privateKeys)
+
+	// A swap above the spend limits is refused before it is signed
+	limited := &wallet.MockWalletManager{}
+	limited.On("GetPrivateKeyForAddress", mock.Anything, from).Return("0xwalletkey", nil)
+	limited.On("ReserveSpend", "ethereum", from, from, "1", "ETH").Return(nil, &wallet.SpendLimitExceededError{Chain: "ethereum", From: from, Amount: "1", Token: "ETH", Limit: wallet.SpendLimitMaxTxValue, Max: 0.5})
+	tool.SetWalletManager(limited)
+	result, err = tool.Execute(context.Background(), scheduleRequest("swap_tokens", args))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "SPEND_LIMIT_EXCEEDED")
+	assert.Empty(t, provider.privateKeys)
+
+	tool.SetWalletManager(signingWallet(from))
+	result, err = tool.Execute(context.Background(), scheduleRequest("swap_tokens", args))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	assert.Equal(t, []string{"0xwalletkey"}, provider.privateKeys)
+}
+
+func TestSwapTokensToolPartialFill(t *testing.T) {
+	aggregator := dex.NewDEXAggregator(zap.NewNop())
+	require.NoError(t, aggregator.RegisterProvider(providers.NewMockProvider(providers.MockConfig{
+		Name:             "ThinPool",
+		PartialFillRatio: 0.4,
+	}, zap.NewNop())))
+	tool := NewSwapTokensToolWithAggregator(aggregator, zap.NewNop())
+	tool.SetWalletManager(signingWallet("0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"))
+	swap := func(extra map[string]any) *mcp.CallToolResult {
+		args := map[string]any{
+			"chain":        "ethereum",
+			"from_token":   "ETH",
+			"to_token":     "USDC",
+			"amount":       "10",
+			"from_address": "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+		}
+		for key, value := range extra {
+			args[key] = value
+		}
+		result, err := tool.Execute(context.Background(), scheduleRequest("swap_tokens", args))
+		require.NoError(t, err)
+		return result
+	}
+
+	// By default a swap that cannot fill in full reverts
+	result := swap(nil)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "could not be filled in full")
+
+	result = swap(map[string]any{"allow_partial_fill": true})
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Amount In**: 4\n")
+	assert.Contains(t, text, "#### Partial Fill")
+	assert.Contains(t, text, "- **Requested Amount In**: 10 ETH")
+	assert.Contains(t, text, "- **Filled Amount In**: 4 ETH")
+	assert.Contains(t, text, "- **Leftover Returned**: 6 ETH")
+	assert.Contains(t, text, "The swap was only partially filled.")
+	assert.NotContains(t, text, "executed successfully")
+
+	// The configured policy can return the leftover without the per-call flag
+	require.NoError(t, aggregator.SetPartialFillPolicy(dex.PartialFillReturnLeftover))
+	result = swap(nil)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "- **Leftover Returned**: 6 ETH")
+}
+
+func TestSwapTokensToolPairPolicy(t *testing.T) {
+	aggregator := dex.NewDEXAggregator(zap.NewNop())
+	require.NoError(t, aggregator.RegisterProvider(providers.NewMockProvider(providers.MockConfig{}, zap.NewNop())))
+	aggregator.SetPairPolicy(dex.NewPairPolicy(config.PairPolicyConfig{
+		DenyTokens: []string{"ethereum:0x1111111111111111111111111111111111111111"},
+	}, zap.NewNop()))
+	tool := NewSwapTokensToolWithAggregator(aggregator, zap.NewNop())
+	tool.SetWalletManager(signingWallet("0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"))
+	swap := func(toToken string) *mcp.CallToolResult {
+		result, err := tool.Execute(context.Background(), scheduleRequest("swap_tokens", map[string]any{
+			"chain":        "ethereum",
+			"from_token":   "ETH",
+			"to_token":     toToken,
+			"amount":       "1",
+			"from_address": "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+		}))
+		require.NoError(t, err)
+		return result
+	}
+
+	result := swap("0x1111111111111111111111111111111111111111")
+	require.True(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "POLICY_DENIED")
+	assert.Contains(t, text, "0x1111111111111111111111111111111111111111 is on the deny list")
+
+	result = swap("USDC")
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "executed successfully")
+}
+
+func TestSwapTokensToolAutoWrap(t *testing.T) {
+	const from = "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+	aggregator := dex.NewDEXAggregator(zap.NewNop())
+	require.NoError(t, aggregator.RegisterProvider(providers.NewMockProvider(providers.MockConfig{}, zap.NewNop())))
+	manager := signingWallet(from)
+	manager.On("GetBalance", mock.Anything, from, "WETH").Return("0.4", nil)
+	manager.On("WrapNative", mock.Anything, "ethereum", from, "0.6").Return(&walletchain.WrapResult{
+		Chain:        "ethereum",
+		Direction:    walletchain.WrapDirectionWrap,
+		TxHash:       "0xwrap",
+		Owner:        from,
+		Amount:       "0.6",
+		NativeSymbol: "ETH",
+		Wrapped:      walletchain.WrappedNativeToken{Symbol: "WETH"},
+	}, nil).Once()
+	tool := NewSwapTokensToolWithAggregator(aggregator, zap.NewNop())
+	tool.SetWalletManager(manager)
+	tool.SetNativeWrapping(manager, false)
+	swap := func(fromToken string, extra map[string]any) *mcp.CallToolResult {
+		args := map[string]any{
+			"chain":        "ethereum",
+			"from_token":   fromToken,
+			"to_token":     "USDC",
+			"amount":       "1",
+			"from_address": from,
+		}
+		for key, value := range extra {
+			args[key] = value
+		}
+		result, err := tool.Execute(context.Background(), scheduleRequest("swap_tokens", args))
+		require.NoError(t, err)
+		require.False(t, result.IsError, "unexpected error: %v", result.Content)
+		return result
+	}
+
+	// Off by default
+	assert.NotContains(t, swap("WETH", nil).Content[0].(mcp.TextContent).Text, "Wrapped First")
+	manager.AssertNotCalled(t, "WrapNative", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	text := swap("WETH", map[string]any{"auto_wrap": true}).Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Wrapped First**: 0.6 ETH into WETH (tx 0xwrap)")
+	assert.Contains(t, text, "executed successfully")
+
+	// Only the wrapped native token is wrapped for, and the config default applies
+	tool.SetNativeWrapping(manager, true)
+	assert.NotContains(t, swap("USDT", nil).Content[0].(mcp.TextContent).Text, "Wrapped First")
+	manager.AssertExpectations(t)
+}
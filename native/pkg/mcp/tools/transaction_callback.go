@@ -0,0 +1,295 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	stdErrors "errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/algonius/algonius-wallet/native/pkg/webhook"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+// Parameters that ask for the outcome of a single transaction
+const (
+	callbackURLParam   = "callback_url"
+	correlationIDParam = "correlation_id"
+)
+
+// Final statuses reported to transaction callbacks
+const (
+	outcomeConfirmed = "confirmed"
+	outcomeFailed    = "failed"
+	outcomeTimeout   = "timeout"
+)
+
+// transactionCallback is where the outcome of one transaction is reported
+type transactionCallback struct {
+	URL           string
+	CorrelationID string
+}
+
+// transactionOutcome is the body of a callback and the data of the
+// transaction_outcome event
+type transactionOutcome struct {
+	CorrelationID   string                         `json:"correlation_id,omitempty"`
+	TransactionHash string                         `json:"transaction_hash"`
+	Chain           string                         `json:"chain"`
+	Status          string                         `json:"status"`
+	Receipt         *chain.TransactionConfirmation `json:"receipt,omitempty"`
+	Error           string                         `json:"error,omitempty"`
+	Timestamp       time.Time                      `json:"timestamp"`
+}
+
+// withCallbackURLOption returns the callback_url parameter shared by the tools that send transactions
+func withCallbackURLOption() mcp.ToolOption {
+	return mcp.WithString(callbackURLParam,
+		mcp.Description("http(s) URL the host POSTs the final outcome to once the transaction reaches the chain's required confirmations or fails, including the receipt (optional). Bodies are signed with the configured callbacks.secret"),
+	)
+}
+
+// withCorrelationIDOption returns the correlation_id parameter shared by the tools that send transactions
+func withCorrelationIDOption() mcp.ToolOption {
+	return mcp.WithString(correlationIDParam,
+		mcp.Description("Caller-chosen id echoed in the callback and in a transaction_outcome event, so the outcome can also be awaited on the event stream (optional)"),
+	)
+}
+
+// parseTransactionCallback reads the callback parameters of req. It returns
+// nil when the caller asked for neither a callback nor a correlation id.
+func parseTransactionCallback(req mcp.CallToolRequest) (*transactionCallback, *errors.Error) {
+	callback := &transactionCallback{
+		URL:           strings.TrimSpace(req.GetString(callbackURLParam, "")),
+		CorrelationID: strings.TrimSpace(req.GetString(correlationIDParam, "")),
+	}
+	if callback.URL == "" && callback.CorrelationID == "" {
+		return nil, nil
+	}
+	if callback.URL != "" {
+		if err := webhook.ValidateURL(callback.URL); err != nil {
+			return nil, errors.ValidationError(callbackURLParam, err.Error())
+		}
+	}
+	return callback, nil
+}
+
+// formatCallbackMarkdown tells the caller where the outcome will be reported
+func formatCallbackMarkdown(callback *transactionCallback) string {
+	if callback == nil {
+		return ""
+	}
+	markdown := ""
+	if callback.URL != "" {
+		markdown += "- **Callback URL**: `" + callback.URL + "`\n"
+	}
+	if callback.CorrelationID != "" {
+		markdown += "- **Correlation ID**: `" + callback.CorrelationID + "`\n"
+	}
+	return markdown
+}
+
+// callbackWatcher follows transactions until they are confirmed or fail and
+// reports the outcome to their callback
+type callbackWatcher struct {
+	notifier    *webhook.Notifier
+	broadcaster *event.EventBroadcaster
+	chains      config.ChainsConfig
+	logger      *zap.Logger
+	getChain    func(chainName string) (chain.IChain, error)
+
+	// pollEvery overrides the configured poll interval when set
+	pollEvery time.Duration
+
+	// Cancel functions of running watches, keyed by start order
+	watchMu   sync.Mutex
+	watches   map[int]context.CancelFunc
+	nextWatch int
+}
+
+// newCallbackWatcher creates a watcher delivering through a notifier built
+// from cfg.Callbacks. A nil cfg uses the defaults.
+func newCallbackWatcher(cfg *config.Config, broadcaster *event.EventBroadcaster, logger *zap.Logger) *callbackWatcher {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &callbackWatcher{
+		notifier:    webhook.NewNotifier(cfg.Callbacks, logger),
+		broadcaster: broadcaster,
+		chains:      cfg.Chains,
+		logger:      logger,
+		getChain:    getChainInterface,
+	}
+}
+
+// start watches txHash in the background with a context cancel can cancel.
+// The watch outlives the tool call, so it does not inherit the call's
+// cancellation or timeout.
+func (w *callbackWatcher) start(ctx context.Context, chainName, txHash string, callback *transactionCallback) {
+	watchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	w.watchMu.Lock()
+	if w.watches == nil {
+		w.watches = make(map[int]context.CancelFunc)
+	}
+	id := w.nextWatch
+	w.nextWatch++
+	w.watches[id] = cancel
+	w.watchMu.Unlock()
+
+	go func() {
+		defer func() {
+			w.watchMu.Lock()
+			delete(w.watches, id)
+			w.watchMu.Unlock()
+			cancel()
+		}()
+		w.watch(watchCtx, chainName, txHash, callback)
+	}()
+}
+
+// cancel stops every running watch without reporting an outcome, e.g. when
+// the wallet is frozen, and returns how many were stopped
+func (w *callbackWatcher) cancel() int {
+	w.watchMu.Lock()
+	defer w.watchMu.Unlock()
+
+	stopped := len(w.watches)
+	for id, cancel := range w.watches {
+		cancel()
+		delete(w.watches, id)
+	}
+	return stopped
+}
+
+// watch polls txHash until it reaches the chain's required confirmations,
+// fails or the watch times out, then reports the outcome
+func (w *callbackWatcher) watch(ctx context.Context, chainName, txHash string, callback *transactionCallback) {
+	outcome := transactionOutcome{
+		CorrelationID:   callback.CorrelationID,
+		TransactionHash: txHash,
+		Chain:           chainName,
+	}
+
+	chainImpl, err := w.getChain(chainName)
+	if err != nil {
+		outcome.Status = outcomeFailed
+		outcome.Error = err.Error()
+		w.report(ctx, callback, outcome)
+		return
+	}
+
+	watchCtx, cancel := context.WithTimeout(ctx, callbackWatchTimeout(chainName))
+	defer cancel()
+
+	ticker := time.NewTicker(w.pollInterval(chainName))
+	defer ticker.Stop()
+
+	required := uint64(requiredConfirmations(w.chains, chainName))
+	for {
+		select {
+		case <-watchCtx.Done():
+			if ctx.Err() != nil {
+				// Cancelled, not timed out: nothing is known about the outcome
+				w.logger.Debug("Callback watch cancelled",
+					zap.String("tx_hash", txHash),
+					zap.String("chain", chainName))
+				return
+			}
+			outcome.Status = outcomeTimeout
+			outcome.Error = "transaction did not reach its required confirmations before the watch timed out"
+			w.report(ctx, callback, outcome)
+			return
+		case <-ticker.C:
+			confirmation, err := chainImpl.ConfirmTransaction(watchCtx, txHash, required)
+			if err != nil {
+				if !stdErrors.Is(err, chain.ErrTransactionNotFound) {
+					w.logger.Debug("Callback confirmation check failed",
+						zap.String("tx_hash", txHash),
+						zap.String("chain", chainName),
+						zap.Error(err))
+				}
+				continue
+			}
+			switch {
+			case confirmation.Status == "confirmed" && confirmation.Confirmations >= confirmation.RequiredConfirmations:
+				outcome.Status = outcomeConfirmed
+			case confirmation.Status == "failed":
+				outcome.Status = outcomeFailed
+				if confirmation.RevertReason != nil {
+					outcome.Error = confirmation.RevertReason.Message
+				}
+			default:
+				continue
+			}
+			outcome.Receipt = confirmation
+			w.report(ctx, callback, outcome)
+			return
+		}
+	}
+}
+
+// report announces outcome on the event stream and delivers it to the callback URL
+func (w *callbackWatcher) report(ctx context.Context, callback *transactionCallback, outcome transactionOutcome) {
+	outcome.Timestamp = time.Now().UTC()
+
+	if w.broadcaster != nil {
+		data := map[string]interface{}{
+			"transaction_hash": outcome.TransactionHash,
+			"chain":            outcome.Chain,
+			"status":           outcome.Status,
+		}
+		if outcome.CorrelationID != "" {
+			data["correlation_id"] = outcome.CorrelationID
+		}
+		if outcome.Receipt != nil {
+			data["receipt"] = outcome.Receipt
+		}
+		if outcome.Error != "" {
+			data["error"] = outcome.Error
+		}
+		w.broadcaster.BroadcastTransactionOutcome(data)
+	}
+
+	if callback.URL == "" {
+		return
+	}
+	if err := w.notifier.Deliver(ctx, callback.URL, outcome); err != nil {
+		w.logger.Warn("Failed to deliver transaction callback",
+			zap.String("tx_hash", outcome.TransactionHash),
+			zap.String("chain", outcome.Chain),
+			zap.String("correlation_id", outcome.CorrelationID),
+			zap.Error(err))
+	}
+}
+
+// pollInterval returns how often the watcher checks a transaction on chainName
+func (w *callbackWatcher) pollInterval(chainName string) time.Duration {
+	if w.pollEvery > 0 {
+		return w.pollEvery
+	}
+	return confirmationPollInterval(w.chains, chainName)
+}
+
+// callbackWatchTimeout returns how long a transaction is watched before its
+// callback reports a timeout, matching the approval confirmation monitors
+func callbackWatchTimeout(chainName string) time.Duration {
+	switch wallet.NormalizeChain(chainName) {
+	case "solana":
+		return 5 * time.Minute
+	case "ethereum":
+		return 15 * time.Minute
+	default:
+		return 10 * time.Minute
+	}
+}
@@ -0,0 +1,189 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/algonius/algonius-wallet/native/pkg/webhook"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// confirmingChain reports the queued confirmations, one per poll
+type confirmingChain struct {
+	*walletchain.SolanaChain
+	confirmations []*walletchain.TransactionConfirmation
+}
+
+func (c *confirmingChain) ConfirmTransaction(_ context.Context, txHash string, required uint64) (*walletchain.TransactionConfirmation, error) {
+	if len(c.confirmations) == 0 {
+		return nil, walletchain.ErrTransactionNotFound
+	}
+	confirmation := c.confirmations[0]
+	if len(c.confirmations) > 1 {
+		c.confirmations = c.confirmations[1:]
+	}
+	confirmation.TxHash = txHash
+	confirmation.RequiredConfirmations = required
+	return confirmation, nil
+}
+
+// callbackRequest is a callback received by the test server
+type callbackRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newCallbackServer returns a server passing every callback it receives to the channel
+func newCallbackServer(t *testing.T) (*httptest.Server, chan callbackRequest) {
+	received := make(chan callbackRequest, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- callbackRequest{header: r.Header, body: body}
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func newTestCallbackWatcher(broadcaster *event.EventBroadcaster, chainImpl walletchain.IChain) *callbackWatcher {
+	cfg := config.DefaultConfig()
+	cfg.Callbacks.Secret = "callback-secret"
+	cfg.Callbacks.RetryDelay = time.Millisecond
+	watcher := newCallbackWatcher(cfg, broadcaster, zap.NewNop())
+	watcher.getChain = func(string) (walletchain.IChain, error) { return chainImpl, nil }
+	watcher.pollEvery = time.Millisecond
+	return watcher
+}
+
+func TestCallbackWatcherDeliversReceipt(t *testing.T) {
+	server, received := newCallbackServer(t)
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+	chainImpl := &confirmingChain{
+		SolanaChain: walletchain.NewSolanaChainLegacy(),
+		confirmations: []*walletchain.TransactionConfirmation{
+			{Status: "pending", Confirmations: 3},
+			{Status: "confirmed", Confirmations: 32, BlockNumber: 1234, TransactionFee: "0.000005"},
+		},
+	}
+
+	callback := &transactionCallback{URL: server.URL, CorrelationID: "order-42"}
+	newTestCallbackWatcher(broadcaster, chainImpl).watch(context.Background(), "solana", "sig123", callback)
+
+	request := <-received
+	assert.Equal(t, webhook.Sign([]byte("callback-secret"), request.header.Get(webhook.HeaderTimestamp), request.body), request.header.Get(webhook.HeaderSignature))
+
+	var outcome transactionOutcome
+	require.NoError(t, json.Unmarshal(request.body, &outcome))
+	assert.Equal(t, "order-42", outcome.CorrelationID)
+	assert.Equal(t, "sig123", outcome.TransactionHash)
+	assert.Equal(t, "solana", outcome.Chain)
+	assert.Equal(t, outcomeConfirmed, outcome.Status)
+	require.NotNil(t, outcome.Receipt)
+	assert.Equal(t, uint64(1234), outcome.Receipt.BlockNumber)
+	assert.Equal(t, uint64(requiredConfirmations(config.DefaultConfig().Chains, "solana")), outcome.Receipt.RequiredConfirmations)
+
+	reported := <-events
+	assert.Equal(t, event.EventTypeTransactionOutcome, reported.Type)
+	assert.Equal(t, "order-42", reported.Data["correlation_id"])
+	assert.Equal(t, outcomeConfirmed, reported.Data["status"])
+}
+
+func TestCallbackWatcherReportsFailure(t *testing.T) {
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+	chainImpl := &confirmingChain{
+		SolanaChain: walletchain.NewSolanaChainLegacy(),
+		confirmations: []*walletchain.TransactionConfirmation{
+			{Status: "failed", RevertReason: &walletchain.RevertReason{Kind: "error", Message: "insufficient output amount"}},
+		},
+	}
+
+	// Without a URL the outcome is only announced on the event stream
+	newTestCallbackWatcher(broadcaster, chainImpl).watch(context.Background(), "ethereum", "0xabc", &transactionCallback{CorrelationID: "swap-7"})
+
+	reported := <-events
+	assert.Equal(t, "swap-7", reported.Data["correlation_id"])
+	assert.Equal(t, outcomeFailed, reported.Data["status"])
+	assert.Equal(t, "insufficient output amount", reported.Data["error"])
+	assert.NotNil(t, reported.Data["receipt"])
+}
+
+func TestCallbackWatcherCancel(t *testing.T) {
+	server, received := newCallbackServer(t)
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+	watcher := newTestCallbackWatcher(broadcaster, &confirmingChain{
+		SolanaChain:   walletchain.NewSolanaChainLegacy(),
+		confirmations: []*walletchain.TransactionConfirmation{{Status: "pending"}},
+	})
+
+	watcher.start(context.Background(), "solana", "sig123", &transactionCallback{URL: server.URL, CorrelationID: "order-42"})
+	require.Equal(t, 1, watcher.cancel())
+
+	// A cancelled watch reports nothing
+	select {
+	case request := <-received:
+		t.Fatalf("unexpected callback after cancel: %s", request.body)
+	case reported := <-events:
+		t.Fatalf("unexpected event after cancel: %+v", reported)
+	case <-time.After(50 * time.Millisecond):
+	}
+	require.Equal(t, 0, watcher.cancel())
+}
+
+func TestSendTransactionToolCallback(t *testing.T) {
+	server, received := newCallbackServer(t)
+	mockManager := &mockWalletManagerForSendTransaction{MockWalletManager: &wallet.MockWalletManager{}}
+	tool := NewSendTransactionTool(mockManager)
+	tool.callbacks = newTestCallbackWatcher(nil, &confirmingChain{
+		SolanaChain:   walletchain.NewSolanaChainLegacy(),
+		confirmations: []*walletchain.TransactionConfirmation{{Status: "confirmed", Confirmations: 12}},
+	})
+	handler := tool.GetHandler()
+	send := func(callbackURL string) *mcp.CallToolResult {
+		result, err := handler(context.Background(), scheduleRequest("send_transaction", map[string]any{
+			"chain":          "ethereum",
+			"from":           "0x1111111111111111111111111111111111111111",
+			"to":             "0x2222222222222222222222222222222222222222",
+			"amount":         "0.1",
+			"callback_url":   callbackURL,
+			"correlation_id": "payout-1",
+		}))
+		require.NoError(t, err)
+		return result
+	}
+
+	result := send("ftp://agent.example/hook")
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "callback_url")
+	assert.Zero(t, mockManager.sendCalls, "an invalid callback is rejected before sending")
+
+	result = send(server.URL)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Callback URL**: `"+server.URL+"`")
+	assert.Contains(t, text, "- **Correlation ID**: `payout-1`")
+
+	select {
+	case request := <-received:
+		var outcome transactionOutcome
+		require.NoError(t, json.Unmarshal(request.body, &outcome))
+		assert.Equal(t, "payout-1", outcome.CorrelationID)
+		assert.Equal(t, "ethereum", outcome.Chain)
+		assert.Equal(t, outcomeConfirmed, outcome.Status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback was not delivered")
+	}
+}
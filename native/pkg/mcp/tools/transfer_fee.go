@@ -0,0 +1,26 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// formatTransferFeeMarkdown warns that a fee-on-transfer token delivers less
+// than was sent. It renders nothing when no fee was detected.
+func formatTransferFeeMarkdown(fee *walletchain.TokenTransferFee) string {
+	if fee == nil {
+		return ""
+	}
+	return fmt.Sprintf("- **Transfer Fee**: `%s%%` (%s)\n", formatPercent(fee.Percent), fee.Source) +
+		"- **Expected Received**: `" + fee.Received + "`\n" +
+		"- **⚠️ Warning**: the token takes a fee on every transfer, so the recipient will receive less than the amount sent\n"
+}
+
+// formatPercent renders a percentage to two decimals without trailing zeros
+func formatPercent(percent float64) string {
+	return strconv.FormatFloat(math.Round(percent*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,25 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatSwapTransferFee(t *testing.T) {
+	result := &dex.SwapResult{ToAmount: "200", SwapFunction: "swapExactTokensForTokensSupportingFeeOnTransferTokens"}
+
+	taxed := &dex.SwapQuote{ToToken: "TAX", ToTokenTransferFee: 5}
+	text := formatSwapTransferFee(taxed, result, false)
+	assert.Contains(t, text, "- **Detected Fee**: 5%")
+	assert.Contains(t, text, "- **Expected Received**: 190 TAX")
+	assert.Contains(t, text, "- **Swap Function**: swapExactTokensForTokensSupportingFeeOnTransferTokens")
+
+	// A token flagged by the caller is warned about without a fee percentage
+	text = formatSwapTransferFee(&dex.SwapQuote{ToToken: "TAX"}, result, true)
+	assert.Contains(t, text, "unknown (flagged by the caller)")
+	assert.NotContains(t, text, "Expected Received")
+
+	assert.Empty(t, formatSwapTransferFee(&dex.SwapQuote{}, result, false))
+}
@@ -0,0 +1,108 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// TransferNFTTool implements the MCP "transfer_nft" tool for sending ERC-721/ERC-1155 and SPL NFTs.
+type TransferNFTTool struct {
+	manager wallet.IWalletManager
+}
+
+// NewTransferNFTTool constructs a TransferNFTTool with the given wallet manager.
+func NewTransferNFTTool(manager wallet.IWalletManager) *TransferNFTTool {
+	return &TransferNFTTool{manager: manager}
+}
+
+// GetMeta returns the MCP tool definition for "transfer_nft".
+func (t *TransferNFTTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("transfer_nft",
+		mcp.WithDescription("Transfer an NFT (safeTransferFrom on EVM chains, SPL transfer on Solana) after verifying ownership"),
+		mcp.WithString("chain",
+			mcp.Required(),
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol"),
+		),
+		mcp.WithString("from",
+			mcp.Required(),
+			mcp.Description("Current owner address"),
+		),
+		mcp.WithString("to",
+			mcp.Required(),
+			mcp.Description("Recipient address"),
+		),
+		mcp.WithString("contract_address",
+			mcp.Required(),
+			mcp.Description("NFT collection contract (EVM) or mint address (Solana)"),
+		),
+		mcp.WithString("token_id",
+			mcp.Required(),
+			mcp.Description("Token ID (use the mint address on Solana)"),
+		),
+		mcp.WithString("amount",
+			mcp.Description("Number of copies to transfer (ERC-1155 only, default: 1)"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "transfer_nft" tool.
+func (t *TransferNFTTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName, err := req.RequireString("chain")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("chain")), nil
+		}
+		from, err := req.RequireString("from")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("from")), nil
+		}
+		to, err := req.RequireString("to")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("to")), nil
+		}
+		contractAddress, err := req.RequireString("contract_address")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("contract_address")), nil
+		}
+		tokenID, err := req.RequireString("token_id")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("token_id")), nil
+		}
+		amount := req.GetString("amount", "1")
+
+		normalizedChain, err := toolutils.NormalizeChainName(chainName)
+		if err != nil {
+			if appErr, ok := err.(*errors.Error); ok {
+				return toolutils.FormatErrorResult(appErr), nil
+			}
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+		for _, addr := range []string{from, to, contractAddress} {
+			if !isValidAddressForChain(normalizedChain, addr) {
+				return toolutils.FormatErrorResult(errors.InvalidAddressError(addr, normalizedChain)), nil
+			}
+		}
+
+		if toolErr := toolutils.RequireUnlocked(t.manager, "transfer nft"); toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
+		txHash, err := toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (string, error) {
+			return t.manager.TransferNFT(attemptCtx, normalizedChain, from, to, contractAddress, tokenID, amount)
+		})
+		if err != nil {
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("transfer nft", err)), nil
+		}
+
+		markdown := fmt.Sprintf("### NFT Transfer Sent\n\n- **Chain**: `%s`\n- **From**: `%s`\n- **To**: `%s`\n- **Contract**: `%s`\n- **Token ID**: `%s`\n- **Amount**: `%s`\n- **Transaction Hash**: `%s`\n- **Status**: `pending`\n",
+			normalizedChain, from, to, contractAddress, tokenID, amount, txHash)
+		return mcp.NewToolResultText(markdown), nil
+	}
+}
@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferNFTToolHandlerSuccess(t *testing.T) {
+	from := "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+	to := "0x1111111111111111111111111111111111111111"
+	contract := "0x495f947276749Ce646f68AC8c248420045cb7b5e"
+
+	mockManager := &wallet.MockWalletManager{}
+	mockManager.On("IsUnlocked").Return(true)
+	mockManager.On("TransferNFT", mock.Anything, "ethereum", from, to, contract, "42", "2").
+		Return("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", nil)
+
+	handler := NewTransferNFTTool(mockManager).GetHandler()
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "transfer_nft",
+			Arguments: map[string]any{
+				"chain":            "eth",
+				"from":             from,
+				"to":               to,
+				"contract_address": contract,
+				"token_id":         "42",
+				"amount":           "2",
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "### NFT Transfer Sent")
+	assert.Contains(t, textContent.Text, "**Amount**: `2`")
+	mockManager.AssertExpectations(t)
+}
+
+func TestTransferNFTToolHandlerMissingTokenID(t *testing.T) {
+	handler := NewTransferNFTTool(&wallet.MockWalletManager{}).GetHandler()
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "transfer_nft",
+			Arguments: map[string]any{
+				"chain":            "ethereum",
+				"from":             "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+				"to":               "0x1111111111111111111111111111111111111111",
+				"contract_address": "0x495f947276749Ce646f68AC8c248420045cb7b5e",
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestTransferNFTToolHandlerManagerError(t *testing.T) {
+	mockManager := &wallet.MockWalletManager{}
+	mockManager.On("IsUnlocked").Return(true)
+	mockManager.On("TransferNFT", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("", assert.AnError)
+
+	handler := NewTransferNFTTool(mockManager).GetHandler()
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "transfer_nft",
+			Arguments: map[string]any{
+				"chain":            "ethereum",
+				"from":             "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+				"to":               "0x1111111111111111111111111111111111111111",
+				"contract_address": "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D",
+				"token_id":         "1234",
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestTransferNFTToolHandlerWalletLocked(t *testing.T) {
+	mockManager := &wallet.MockWalletManager{}
+	mockManager.On("IsUnlocked").Return(false)
+	mockManager.On("IsFrozen").Return(false)
+
+	handler := NewTransferNFTTool(mockManager).GetHandler()
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "transfer_nft",
+			Arguments: map[string]any{
+				"chain":            "ethereum",
+				"from":             "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+				"to":               "0x1111111111111111111111111111111111111111",
+				"contract_address": "0x495f947276749Ce646f68AC8c248420045cb7b5e",
+				"token_id":         "42",
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	mockManager.AssertNotCalled(t, "TransferNFT", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "`WALLET_LOCKED`")
+}
@@ -0,0 +1,138 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+)
+
+// unattendedSender sends transfers and swaps that were requested earlier and
+// run with nobody watching, such as scheduled runs and fired price triggers.
+// Each one is checked like a call made at that moment: the wallet must be
+// unlocked and not frozen, and anything that now needs confirm_contract_recipient
+// or confirm_large is refused unless that confirmation was given up front.
+type unattendedSender struct {
+	manager    wallet.IWalletManager
+	aggregator dex.IDEXAggregator
+	largeTx    largeTxGuard
+}
+
+func newUnattendedSender(manager wallet.IWalletManager, aggregator dex.IDEXAggregator, broadcaster *event.EventBroadcaster, priceFeed wallet.PriceFeed, cfg *config.Config) *unattendedSender {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return &unattendedSender{
+		manager:    manager,
+		aggregator: aggregator,
+		largeTx:    largeTxGuard{chains: cfg.Chains, priceFeed: priceFeed, broadcaster: broadcaster},
+	}
+}
+
+// send transfers amount of token like send_transaction
+func (s *unattendedSender) send(ctx context.Context, chain, from, to, amount, token string, confirmLarge, confirmRecipient bool) (string, error) {
+	recipient, err := s.manager.ClassifyRecipient(ctx, chain, to, token)
+	if err == nil && recipient.Warning != "" && !confirmRecipient {
+		return "", fmt.Errorf("recipient needs confirm_contract_recipient: %s", recipient.Warning)
+	}
+	if err := s.checkLarge(ctx, chain, from, to, amount, token, confirmLarge); err != nil {
+		return "", err
+	}
+
+	return toolutils.ExecuteWithRetry(ctx, toolutils.DefaultRetryPolicy, func(attemptCtx context.Context) (string, error) {
+		return s.manager.SendTransaction(attemptCtx, chain, from, to, amount, token)
+	})
+}
+
+// swap exchanges amount of fromToken for toToken through the best quote, like
+// swap_tokens. The large transaction threshold applies to the input amount.
+func (s *unattendedSender) swap(ctx context.Context, chain, from, amount, fromToken, toToken string, slippage float64, confirmLarge bool) (string, error) {
+	if s.aggregator == nil {
+		return "", fmt.Errorf("%w for chain %s: no DEX aggregator configured", dex.ErrNoProvidersAvailable, chain)
+	}
+	chainID, ok := swapChainIDs[chain]
+	if !ok {
+		return "", fmt.Errorf("swaps are not supported on %s", chain)
+	}
+	if toolErr := toolutils.RequireUnlocked(s.manager, "swap tokens"); toolErr != nil {
+		return "", toolErr
+	}
+	if err := s.checkLarge(ctx, chain, from, "", amount, fromToken, confirmLarge); err != nil {
+		return "", err
+	}
+	if slippage == 0 {
+		slippage = defaultSwapSlippage
+	}
+
+	params := dex.SwapParams{
+		FromToken:   fromToken,
+		ToToken:     toToken,
+		Amount:      amount,
+		Slippage:    slippage,
+		FromAddress: from,
+		ToAddress:   from,
+		ChainID:     chainID,
+	}
+	if err := params.Validate(); err != nil {
+		return "", err
+	}
+	quote, err := s.aggregator.GetBestQuote(ctx, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to get swap quote: %w", err)
+	}
+	// Signed with the wallet's own key for from, as in swap_tokens
+	if params.PrivateKey, err = s.manager.GetPrivateKeyForAddress(ctx, from); err != nil {
+		return "", err
+	}
+	// The amount sold counts against the spend limits like a send
+	reservation, err := s.manager.ReserveSpend(chain, from, from, amount, fromToken)
+	if err != nil {
+		return "", err
+	}
+	defer reservation.Release()
+	result, err := s.aggregator.ExecuteSwapWithProvider(ctx, quote.Provider, params)
+	if result != nil && (err == nil || stdErrors.Is(err, dex.ErrPartialFill)) {
+		// A partial fill was still executed
+		reservation.Commit(result.TxHash)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to execute swap: %w", err)
+	}
+	return result.TxHash, nil
+}
+
+func (s *unattendedSender) checkLarge(ctx context.Context, chain, from, to, amount, token string, confirmed bool) error {
+	if check := s.largeTx.check(ctx, "", chain, from, to, amount, token, confirmed); check != nil && check.Large && !confirmed {
+		return fmt.Errorf("transaction meets the large_tx_threshold and was not created with confirm_large: %s", strings.Join(check.Reasons, "; "))
+	}
+	return nil
+}
+
+// NewScheduledTransactionSender returns the sender that runs scheduled
+// transactions, checking every run as it is sent.
+func NewScheduledTransactionSender(manager wallet.IWalletManager, broadcaster *event.EventBroadcaster, priceFeed wallet.PriceFeed, cfg *config.Config) wallet.ScheduledSender {
+	sender := newUnattendedSender(manager, nil, broadcaster, priceFeed, cfg)
+	return func(ctx context.Context, job *wallet.ScheduledTransaction) (string, error) {
+		return sender.send(ctx, job.Chain, job.From, job.To, job.Amount, job.Token, job.ConfirmLarge, job.ConfirmContractRecipient)
+	}
+}
+
+// NewPriceTriggerExecutor returns the executor that runs the action of a fired
+// price trigger. Swaps go through aggregator; a nil aggregator fails them.
+func NewPriceTriggerExecutor(manager wallet.IWalletManager, aggregator dex.IDEXAggregator, broadcaster *event.EventBroadcaster, priceFeed wallet.PriceFeed, cfg *config.Config) wallet.PriceTriggerExecutor {
+	sender := newUnattendedSender(manager, aggregator, broadcaster, priceFeed, cfg)
+	return func(ctx context.Context, trigger *wallet.PriceTrigger) (string, error) {
+		action := trigger.Action
+		if action.Kind == wallet.TriggerActionSwap {
+			return sender.swap(ctx, action.Chain, action.From, action.Amount, action.Token, action.ToToken, action.Slippage, trigger.ConfirmLarge)
+		}
+		return sender.send(ctx, action.Chain, action.From, action.To, action.Amount, action.Token, trigger.ConfirmLarge, trigger.ConfirmContractRecipient)
+	}
+}
@@ -0,0 +1,48 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// UnwrapTool implements the MCP "unwrap_native" tool, converting a chain's
+// wrapped token back into its native coin.
+type UnwrapTool struct {
+	manager wallet.IWalletManager
+	logger  *zap.Logger
+}
+
+// NewUnwrapTool constructs an UnwrapTool with the given wallet manager.
+func NewUnwrapTool(manager wallet.IWalletManager, logger *zap.Logger) *UnwrapTool {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &UnwrapTool{manager: manager, logger: logger}
+}
+
+// GetMeta returns the MCP tool definition for "unwrap_native".
+func (t *UnwrapTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("unwrap_native",
+		mcp.WithDescription("Unwrap a wrapped native token back into the native coin: withdraws WETH to ETH or WBNB to BNB, or takes SOL out of the wallet's wrapped SOL account. Unwrapping all WSOL closes the account and returns its rent; a partial amount goes through a temporary account closed in the same transaction. Returns the remaining wrapped token balance."),
+		mcp.WithString("chain",
+			mcp.Required(),
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol"),
+		),
+		mcp.WithString("owner",
+			mcp.Required(),
+			mcp.Description("Wallet address that holds the wrapped token"),
+		),
+		mcp.WithString("amount",
+			mcp.Required(),
+			mcp.Description("Wrapped token to unwrap (e.g. 0.5), or max for the whole balance"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "unwrap_native" tool.
+func (t *UnwrapTool) GetHandler() server.ToolHandlerFunc {
+	return wrapHandler("unwrap_native", "unwrap native token", t.manager, t.logger, t.manager.UnwrapNative)
+}
@@ -0,0 +1,108 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// VerifySignatureTool implements the MCP "verify_signature" tool, checking a
+// message signature against an expected signer. It needs no wallet, so it
+// works while the wallet is locked and for signatures made elsewhere.
+type VerifySignatureTool struct{}
+
+// NewVerifySignatureTool constructs a VerifySignatureTool.
+func NewVerifySignatureTool() *VerifySignatureTool {
+	return &VerifySignatureTool{}
+}
+
+// GetMeta returns the MCP tool definition for "verify_signature".
+func (t *VerifySignatureTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("verify_signature",
+		mcp.WithDescription("Verify a message signature as produced by sign_message: EIP-191 signer recovery on Ethereum/BSC, Ed25519 verification on Solana"),
+		mcp.WithString("chain",
+			mcp.Required(),
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol"),
+		),
+		mcp.WithString("message",
+			mcp.Required(),
+			mcp.Description("The signed message, exactly as passed to sign_message (0x-prefixed hex on Ethereum/BSC, '__SOLANA_RAW_BYTES__:' prefix for Solana raw bytes)"),
+		),
+		mcp.WithString("signature",
+			mcp.Required(),
+			mcp.Description("The signature: 65-byte 0x hex on Ethereum/BSC, base58 on Solana"),
+		),
+		mcp.WithString("address",
+			mcp.Required(),
+			mcp.Description("The address expected to have signed the message"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "verify_signature" tool.
+// A signature that does not match is reported as invalid, not as an error.
+func (t *VerifySignatureTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName, err := req.RequireString("chain")
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("chain")), nil
+		}
+		normalizedChain, err := toolutils.NormalizeChainName(chainName)
+		if err != nil {
+			if appErr, ok := err.(*errors.Error); ok {
+				return toolutils.FormatErrorResult(appErr), nil
+			}
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+
+		message, err := req.RequireString("message")
+		if err != nil || message == "" {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("message")), nil
+		}
+		signature, err := req.RequireString("signature")
+		if err != nil || strings.TrimSpace(signature) == "" {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("signature")), nil
+		}
+		signature = strings.TrimSpace(signature)
+		address, err := req.RequireString("address")
+		if err != nil || strings.TrimSpace(address) == "" {
+			return toolutils.FormatErrorResult(errors.MissingRequiredFieldError("address")), nil
+		}
+		address = strings.TrimSpace(address)
+		if !isValidAddressForChain(normalizedChain, address) {
+			return toolutils.FormatErrorResult(errors.InvalidAddressError(address, normalizedChain)), nil
+		}
+
+		var valid bool
+		var signer string
+		if normalizedChain == "solana" {
+			valid, err = walletchain.VerifySolanaMessage(address, message, signature)
+			// Ed25519 has no recovery; the signer is only known when it checks out
+			if valid {
+				signer = address
+			}
+		} else {
+			signer, err = walletchain.RecoverMessageSigner(message, signature)
+			valid = err == nil && strings.EqualFold(signer, address)
+		}
+		if err != nil {
+			return toolutils.FormatErrorResult(errors.ValidationError("signature", err.Error())), nil
+		}
+
+		markdown := "### Signature Verification\n\n" +
+			fmt.Sprintf("- **Chain**: `%s`\n", normalizedChain) +
+			fmt.Sprintf("- **Expected Signer**: `%s`\n", address) +
+			fmt.Sprintf("- **Valid**: `%t`\n", valid)
+		if signer != "" {
+			markdown += fmt.Sprintf("- **Recovered Signer**: `%s`\n", signer)
+		}
+		return mcp.NewToolResultText(markdown), nil
+	}
+}
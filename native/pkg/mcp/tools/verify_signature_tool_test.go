@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ed25519"
+)
+
+func verifySignature(t *testing.T, args map[string]any) *mcp.CallToolResult {
+	result, err := NewVerifySignatureTool().GetHandler()(context.Background(), scheduleRequest("verify_signature", args))
+	require.NoError(t, err)
+	return result
+}
+
+func TestVerifySignatureToolEVM(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	signature, err := walletchain.NewETHChainLegacy().SignMessage(hex.EncodeToString(crypto.FromECDSA(key)), "challenge 1234")
+	require.NoError(t, err)
+
+	result := verifySignature(t, map[string]any{"chain": "eth", "message": "challenge 1234", "signature": signature, "address": address})
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Valid**: `true`")
+	assert.Contains(t, text, "- **Recovered Signer**: `"+address+"`")
+
+	result = verifySignature(t, map[string]any{"chain": "bsc", "message": "challenge 1235", "signature": signature, "address": address})
+	require.False(t, result.IsError)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Valid**: `false`")
+	assert.NotContains(t, text, "- **Recovered Signer**: `"+address+"`")
+
+	result = verifySignature(t, map[string]any{"chain": "eth", "message": "challenge 1234", "signature": "0x1234", "address": address})
+	assert.True(t, result.IsError)
+}
+
+func TestVerifySignatureToolSolana(t *testing.T) {
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{1}, ed25519.SeedSize))
+	address := base58.Encode(key.Public().(ed25519.PublicKey))
+	signature, err := walletchain.NewSolanaChainLegacy().SignMessage(base58.Encode(key), "challenge 1234")
+	require.NoError(t, err)
+
+	result := verifySignature(t, map[string]any{"chain": "sol", "message": "challenge 1234", "signature": signature, "address": address})
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Valid**: `true`")
+	assert.Contains(t, text, "- **Recovered Signer**: `"+address+"`")
+
+	result = verifySignature(t, map[string]any{"chain": "solana", "message": "challenge 4321", "signature": signature, "address": address})
+	require.False(t, result.IsError)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- **Valid**: `false`")
+	assert.NotContains(t, text, "Recovered Signer")
+
+	result = verifySignature(t, map[string]any{"chain": "solana", "message": "challenge 1234", "signature": signature, "address": "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "INVALID_ADDRESS")
+}
@@ -0,0 +1,95 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// WithdrawStakeTool implements the MCP "withdraw_stake" tool, moving SOL out
+// of an inactive Solana stake account.
+type WithdrawStakeTool struct {
+	manager     wallet.IWalletManager
+	broadcaster *event.EventBroadcaster
+	logger      *zap.Logger
+}
+
+// NewWithdrawStakeTool constructs a WithdrawStakeTool with the given wallet manager and event broadcaster.
+func NewWithdrawStakeTool(manager wallet.IWalletManager, broadcaster *event.EventBroadcaster, logger *zap.Logger) *WithdrawStakeTool {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &WithdrawStakeTool{manager: manager, broadcaster: broadcaster, logger: logger}
+}
+
+// GetMeta returns the MCP tool definition for "withdraw_stake".
+func (t *WithdrawStakeTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("withdraw_stake",
+		mcp.WithDescription("Withdraw SOL from a Solana stake account the wallet is the withdrawer of. Delegated stake must first be deactivated with deactivate_stake and finish its cooldown; withdrawing max empties and closes the account."),
+		mcp.WithString("owner",
+			mcp.Required(),
+			mcp.Description("Solana wallet address that is the stake account's withdrawer"),
+		),
+		mcp.WithString("stake_account",
+			mcp.Required(),
+			mcp.Description("Address of the stake account to withdraw from"),
+		),
+		mcp.WithString("amount",
+			mcp.Description("SOL to withdraw, or max (default) for the whole account balance"),
+		),
+		mcp.WithString("to",
+			mcp.Description("Recipient of the SOL (defaults to owner)"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "withdraw_stake" tool.
+func (t *WithdrawStakeTool) GetHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		owner := strings.TrimSpace(req.GetString("owner", ""))
+		stakeAccount := strings.TrimSpace(req.GetString("stake_account", ""))
+		for _, field := range []struct{ name, value string }{{"owner", owner}, {"stake_account", stakeAccount}} {
+			if field.value == "" {
+				return toolutils.FormatErrorResult(errors.MissingRequiredFieldError(field.name)), nil
+			}
+			if !isValidAddressForChain("solana", field.value) {
+				return toolutils.FormatErrorResult(errors.InvalidAddressError(field.value, "solana")), nil
+			}
+		}
+		to := strings.TrimSpace(req.GetString("to", ""))
+		if to != "" && !isValidAddressForChain("solana", to) {
+			return toolutils.FormatErrorResult(errors.InvalidAddressError(to, "solana")), nil
+		}
+		amount := strings.TrimSpace(req.GetString("amount", ""))
+		if amount == "" {
+			amount = walletchain.AmountMax
+		}
+
+		if toolErr := toolutils.RequireUnlocked(t.manager, "withdraw stake"); toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
+		result, err := t.manager.WithdrawStake(ctx, owner, stakeAccount, to, amount)
+		if err != nil {
+			t.logger.Error("Failed to withdraw stake",
+				zap.String("owner", owner),
+				zap.String("stake_account", stakeAccount),
+				zap.Error(err))
+			return toolutils.FormatErrorResult(toolutils.ClassifyError("withdraw stake", err)), nil
+		}
+		if t.broadcaster != nil {
+			t.broadcaster.BroadcastStakeWithdrawn(result.Signature, owner, result.StakeAccount, result.Recipient, result.Lamports)
+		}
+
+		return mcp.NewToolResultText(formatStakeResult("Stake Withdrawn", result)), nil
+	}
+}
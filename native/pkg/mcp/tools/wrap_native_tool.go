@@ -0,0 +1,131 @@
+// Package tools provides MCP tool implementations for the Algonius Native Host.
+package tools
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/mcp/toolutils"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// WrapTool implements the MCP "wrap_native" tool, converting a chain's native
+// coin into its wrapped token (WETH, WBNB, WSOL).
+type WrapTool struct {
+	manager wallet.IWalletManager
+	logger  *zap.Logger
+}
+
+// NewWrapTool constructs a WrapTool with the given wallet manager.
+func NewWrapTool(manager wallet.IWalletManager, logger *zap.Logger) *WrapTool {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &WrapTool{manager: manager, logger: logger}
+}
+
+// GetMeta returns the MCP tool definition for "wrap_native".
+func (t *WrapTool) GetMeta() mcp.Tool {
+	return mcp.NewTool("wrap_native",
+		mcp.WithDescription("Wrap the native coin into its wrapped token: deposits ETH into WETH or BNB into WBNB, or moves SOL into the wallet's wrapped SOL account, creating it when needed. The amount must leave the fee and the configured native reserve in the wallet. Returns the resulting wrapped token balance."),
+		mcp.WithString("chain",
+			mcp.Required(),
+			mcp.Description("Chain identifier: ethereum|eth, bsc|binance, solana|sol"),
+		),
+		mcp.WithString("owner",
+			mcp.Required(),
+			mcp.Description("Wallet address that holds the native coin"),
+		),
+		mcp.WithString("amount",
+			mcp.Required(),
+			mcp.Description("Native coin to wrap (e.g. 0.5), or max for everything above the fee and reserve"),
+		),
+	)
+}
+
+// GetHandler returns the handler function for the "wrap_native" tool.
+func (t *WrapTool) GetHandler() server.ToolHandlerFunc {
+	return wrapHandler("wrap_native", "wrap native token", t.manager, t.logger, t.manager.WrapNative)
+}
+
+// wrapHandler handles wrap_native and unwrap_native, which take the same
+// arguments and differ only in the wallet operation they call
+func wrapHandler(name, operation string, manager wallet.IWalletManager, logger *zap.Logger,
+	run func(ctx context.Context, chainName, owner, amount string) (*walletchain.WrapResult, error)) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName := strings.TrimSpace(req.GetString("chain", ""))
+		owner := strings.TrimSpace(req.GetString("owner", ""))
+		amount := strings.TrimSpace(req.GetString("amount", ""))
+		for _, field := range []struct{ name, value string }{{"chain", chainName}, {"owner", owner}, {"amount", amount}} {
+			if field.value == "" {
+				return toolutils.FormatErrorResult(errors.MissingRequiredFieldError(field.name)), nil
+			}
+		}
+		normalizedChain, err := toolutils.NormalizeChainName(chainName)
+		if err != nil {
+			if appErr, ok := err.(*errors.Error); ok {
+				return toolutils.FormatErrorResult(appErr), nil
+			}
+			return toolutils.FormatErrorResult(errors.ValidationError("chain", err.Error())), nil
+		}
+		if !isValidAddressForChain(normalizedChain, owner) {
+			return toolutils.FormatErrorResult(errors.InvalidAddressError(owner, normalizedChain)), nil
+		}
+		if !walletchain.IsMaxAmount(amount) {
+			if value, err := strconv.ParseFloat(amount, 64); err != nil || value <= 0 {
+				return toolutils.FormatErrorResult(errors.ValidationError("amount", "amount must be a positive number or max")), nil
+			}
+		}
+
+		if toolErr := toolutils.RequireUnlocked(manager, operation); toolErr != nil {
+			return toolutils.FormatErrorResult(toolErr), nil
+		}
+
+		// Not retried: a second attempt could wrap or unwrap twice
+		result, err := run(ctx, normalizedChain, owner, amount)
+		if err != nil {
+			logger.Error("Failed to "+operation,
+				zap.String("tool", name),
+				zap.String("chain", normalizedChain),
+				zap.String("owner", owner),
+				zap.Error(err))
+			return toolutils.FormatErrorResult(toolutils.ClassifyError(operation, err)), nil
+		}
+		return mcp.NewToolResultText(formatWrapResult(result)), nil
+	}
+}
+
+// formatWrapResult renders the outcome of a wrap or unwrap
+func formatWrapResult(result *walletchain.WrapResult) string {
+	title, from, to := "Native Token Wrapped", result.NativeSymbol, result.Wrapped.Symbol
+	if result.Direction == walletchain.WrapDirectionUnwrap {
+		title, from, to = "Native Token Unwrapped", result.Wrapped.Symbol, result.NativeSymbol
+	}
+	markdown := "### " + title + " ✅\n\n" +
+		"- **Chain**: `" + result.Chain + "`\n" +
+		"- **Transaction Hash**: `" + result.TxHash + "`\n" +
+		"- **Owner**: `" + result.Owner + "`\n" +
+		"- **Amount**: `" + result.Amount + " " + from + "` → `" + to + "`\n" +
+		"- **Wrapped Token**: `" + result.Wrapped.Address + "`\n"
+	if result.Account != "" {
+		markdown += "- **Token Account**: `" + result.Account + "`\n"
+	}
+	if result.AccountCreated {
+		markdown += "- **Account Created**: `true` (its rent stays in the account until it is closed)\n"
+	}
+	if result.AccountClosed {
+		markdown += "- **Account Closed**: `true` (its rent was returned)\n"
+	}
+	if result.WrappedBalance != "" {
+		markdown += "- **" + result.Wrapped.Symbol + " Balance**: `" + result.WrappedBalance + "`\n"
+	} else {
+		markdown += "- **" + result.Wrapped.Symbol + " Balance**: `unavailable`\n"
+	}
+	return markdown
+}
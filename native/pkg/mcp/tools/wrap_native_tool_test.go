@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	walletchain "github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapTool(t *testing.T) {
+	weth, _ := walletchain.WrappedNative("ethereum")
+	owner := "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+	manager := &wallet.MockWalletManager{}
+	manager.On("IsUnlocked").Return(true)
+	manager.On("WrapNative", mock.Anything, "ethereum", owner, "0.5").Return(&walletchain.WrapResult{
+		Chain:          "ethereum",
+		Direction:      walletchain.WrapDirectionWrap,
+		TxHash:         "0xabc",
+		Owner:          owner,
+		Amount:         "0.5",
+		NativeSymbol:   "ETH",
+		Wrapped:        weth,
+		WrappedBalance: "1.5",
+	}, nil)
+	manager.On("WrapNative", mock.Anything, "ethereum", owner, "100").
+		Return(nil, fmt.Errorf("%w: wrapping 100 ETH leaves less than the reserve", walletchain.ErrNativeReserveViolation))
+	handler := NewWrapTool(manager, nil).GetHandler()
+
+	result, err := handler(context.Background(), scheduleRequest("wrap_native", map[string]any{"chain": "eth", "owner": owner, "amount": "0.5"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "### Native Token Wrapped")
+	assert.Contains(t, text, "- **Amount**: `0.5 ETH` → `WETH`")
+	assert.Contains(t, text, "- **WETH Balance**: `1.5`")
+
+	result, err = handler(context.Background(), scheduleRequest("wrap_native", map[string]any{"chain": "ethereum", "owner": owner, "amount": "100"}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "above the reserve")
+
+	for _, args := range []map[string]any{
+		{"chain": "ethereum", "owner": owner},
+		{"chain": "ethereum", "owner": "not-an-address", "amount": "1"},
+		{"chain": "ethereum", "owner": owner, "amount": "-1"},
+		{"chain": "dogecoin", "owner": owner, "amount": "1"},
+	} {
+		result, err := handler(context.Background(), scheduleRequest("wrap_native", args))
+		require.NoError(t, err)
+		assert.True(t, result.IsError, "%v", args)
+	}
+	manager.AssertExpectations(t)
+}
+
+func TestUnwrapTool(t *testing.T) {
+	wsol, _ := walletchain.WrappedNative("solana")
+	owner := "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK"
+	manager := &wallet.MockWalletManager{}
+	manager.On("IsUnlocked").Return(true)
+	manager.On("UnwrapNative", mock.Anything, "solana", owner, "max").Return(&walletchain.WrapResult{
+		Chain:          "solana",
+		Direction:      walletchain.WrapDirectionUnwrap,
+		TxHash:         "unwrapSig",
+		Owner:          owner,
+		Amount:         "0.75",
+		NativeSymbol:   "SOL",
+		Wrapped:        wsol,
+		WrappedBalance: "0",
+		Account:        "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM",
+		AccountClosed:  true,
+	}, nil)
+
+	result, err := NewUnwrapTool(manager, nil).GetHandler()(context.Background(), scheduleRequest("unwrap_native", map[string]any{"chain": "solana", "owner": owner, "amount": "max"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "### Native Token Unwrapped")
+	assert.Contains(t, text, "- **Amount**: `0.75 WSOL` → `SOL`")
+	assert.Contains(t, text, "- **Token Account**: `9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM`")
+	assert.Contains(t, text, "- **Account Closed**: `true`")
+	assert.Contains(t, text, "- **WSOL Balance**: `0`")
+	manager.AssertExpectations(t)
+}
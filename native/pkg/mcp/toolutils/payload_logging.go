@@ -0,0 +1,41 @@
+package toolutils
+
+import (
+	"context"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/logger"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// PayloadLoggingMiddleware logs every MCP tool call's arguments and result,
+// with sensitive fields and mnemonics redacted, for debugging agent issues.
+func PayloadLoggingMiddleware(log logger.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			log.Info("MCP tool request",
+				zap.String("tool", req.Params.Name),
+				zap.String("arguments", logger.Redact(req.Params.Arguments)))
+
+			start := time.Now()
+			result, err := next(ctx, req)
+
+			fields := []zap.Field{
+				zap.String("tool", req.Params.Name),
+				zap.Duration("duration", time.Since(start)),
+			}
+			if result != nil {
+				fields = append(fields,
+					zap.Bool("is_error", result.IsError),
+					zap.String("result", logger.Redact(result)))
+			}
+			if err != nil {
+				fields = append(fields, zap.String("error", logger.RedactText(err.Error())))
+			}
+			log.Info("MCP tool response", fields...)
+			return result, err
+		}
+	}
+}
@@ -3,10 +3,14 @@ package toolutils
 import (
 	"context"
 	stdErrors "errors"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
 	appErrors "github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
 )
 
 // RetryPolicy controls timeout and retry behavior for tool RPC-style operations.
@@ -27,17 +31,31 @@ var DefaultRetryPolicy = RetryPolicy{
 
 // NormalizeChainName converts chain aliases to a canonical name.
 func NormalizeChainName(chain string) (string, error) {
-	normalized := strings.ToLower(strings.TrimSpace(chain))
-	switch normalized {
-	case "eth", "ethereum":
-		return "ethereum", nil
-	case "bsc", "binance", "binance smart chain":
-		return "bsc", nil
-	case "sol", "solana":
-		return "solana", nil
-	default:
+	if wallet.ValidateChain(chain) != nil {
 		return "", appErrors.ValidationError("chain", "supported values: ethereum|eth, bsc|binance, solana|sol").WithSuggestion("Set chain to ethereum, bsc, or solana")
 	}
+	return wallet.NormalizeChain(chain), nil
+}
+
+// ChainOrDefault returns chain, or the manager's wallet.default_chain when
+// the caller left it out.
+func ChainOrDefault(manager wallet.IWalletManager, chain string) string {
+	if strings.TrimSpace(chain) == "" {
+		return manager.DefaultChain()
+	}
+	return chain
+}
+
+// RequireUnlocked returns a WALLET_LOCKED error when operation would need the
+// keys of a locked wallet, and nil otherwise.
+func RequireUnlocked(manager wallet.IWalletManager, operation string) *appErrors.Error {
+	if manager.IsUnlocked() {
+		return nil
+	}
+	if manager.IsFrozen() {
+		return appErrors.WalletFrozenError(operation)
+	}
+	return appErrors.WalletLockedError(operation)
 }
 
 // ExecuteWithRetry runs fn with per-attempt timeout and exponential backoff on retryable errors.
@@ -99,6 +117,10 @@ func IsRetryableError(err error) bool {
 		return false
 	}
 
+	// A revert reason is the contract's answer, whatever words it uses
+	if stdErrors.Is(err, chain.ErrSimulationReverted) || stdErrors.Is(err, chain.ErrFeePayerUnfunded) {
+		return false
+	}
 	if stdErrors.Is(err, context.DeadlineExceeded) || stdErrors.Is(err, context.Canceled) {
 		return true
 	}
@@ -130,6 +152,60 @@ func ClassifyError(operation string, err error) *appErrors.Error {
 	if err == nil {
 		return nil
 	}
+	if stdErrors.Is(err, wallet.ErrWalletLocked) {
+		return appErrors.WalletLockedError(operation)
+	}
+	if stdErrors.Is(err, wallet.ErrWalletFrozen) {
+		return appErrors.WalletFrozenError(operation)
+	}
+	if stdErrors.Is(err, wallet.ErrRecipientBlocked) {
+		return appErrors.RecipientBlockedError(operation, err)
+	}
+	if stdErrors.Is(err, wallet.ErrSpendLimitExceeded) {
+		return appErrors.SpendLimitExceededError(operation, err)
+	}
+	if stdErrors.Is(err, wallet.ErrSigningDenied) {
+		return appErrors.SigningDeniedError(operation, err)
+	}
+	if stdErrors.Is(err, dex.ErrPairDenied) {
+		return appErrors.PairDeniedError(operation, err)
+	}
+	if stdErrors.Is(err, dex.ErrNoProvidersAvailable) {
+		return appErrors.NoDEXProvidersError(operation, err)
+	}
+	if stdErrors.Is(err, chain.ErrSimulationReverted) {
+		return appErrors.SimulationRevertedError(operation, err)
+	}
+	var unfunded *chain.FeePayerUnfundedError
+	if stdErrors.As(err, &unfunded) {
+		return appErrors.InsufficientBalanceError("SOL", fmt.Sprintf("%d lamports", unfunded.Balance), fmt.Sprintf("%d lamports", unfunded.Required)).
+			WithSuggestion("Fund the fee payer " + unfunded.Address + " with SOL or send without fee_payer")
+	}
+	if stdErrors.Is(err, wallet.ErrFeePayerNotOwned) {
+		return appErrors.ValidationError("fee_payer", err.Error())
+	}
+	if stdErrors.Is(err, chain.ErrPermitNotSupported) {
+		return appErrors.ValidationError("token", err.Error()).
+			WithSuggestion("Approve the spender with a regular approve transaction instead")
+	}
+	if stdErrors.Is(err, wallet.ErrAddressNotInWallet) {
+		return appErrors.ValidationError("from", err.Error()).
+			WithSuggestion("Send from an address of the unlocked wallet; list_wallets shows them")
+	}
+	if stdErrors.Is(err, wallet.ErrOwnerNotInWallet) {
+		return appErrors.ValidationError("owner", err.Error())
+	}
+	if stdErrors.Is(err, chain.ErrNativeReserveViolation) {
+		return appErrors.ValidationError("amount", err.Error()).
+			WithSuggestion("Use a smaller amount, or max to use everything above the reserve")
+	}
+	if stdErrors.Is(err, chain.ErrInvalidVoteAccount) {
+		return appErrors.ValidationError("vote_account", err.Error()).
+			WithSuggestion("Pick the vote account of a validator that is currently voting")
+	}
+	if stdErrors.Is(err, chain.ErrStakeAccountNotFound) || stdErrors.Is(err, chain.ErrStakeAuthorityMismatch) {
+		return appErrors.ValidationError("stake_account", err.Error())
+	}
 	if stdErrors.Is(err, context.DeadlineExceeded) || strings.Contains(strings.ToLower(err.Error()), "timeout") {
 		return appErrors.TimeoutError(operation)
 	}
@@ -0,0 +1,63 @@
+package toolutils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appErrors "github.com/algonius/algonius-wallet/native/pkg/errors"
+	"github.com/algonius/algonius-wallet/native/pkg/logger"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// ToolTimeoutMiddleware bounds every MCP tool call by timeoutFor(tool name).
+// At the deadline the call's context is cancelled and the agent gets a timeout
+// error, even when the handler ignores its context and keeps running.
+func ToolTimeoutMiddleware(timeoutFor func(tool string) time.Duration, log logger.Logger) server.ToolHandlerMiddleware {
+	type outcome struct {
+		result *mcp.CallToolResult
+		err    error
+	}
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			timeout := timeoutFor(req.Params.Name)
+			if timeout <= 0 {
+				return next(ctx, req)
+			}
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			done := make(chan outcome, 1)
+			start := time.Now()
+			go func() {
+				result, err := next(ctx, req)
+				done <- outcome{result, err}
+			}()
+
+			select {
+			case out := <-done:
+				return out.result, out.err
+			case <-ctx.Done():
+				if ctx.Err() != context.DeadlineExceeded {
+					// The client went away; nobody is waiting for the result
+					return nil, ctx.Err()
+				}
+				log.Warn("MCP tool call timed out",
+					zap.String("tool", req.Params.Name),
+					zap.Duration("timeout", timeout))
+				go func() {
+					// Report handlers that ignore cancellation so they can be fixed
+					<-done
+					log.Warn("Timed out MCP tool call finished",
+						zap.String("tool", req.Params.Name),
+						zap.Duration("duration", time.Since(start)))
+				}()
+				toolErr := appErrors.TimeoutError(fmt.Sprintf("%s (no result within %s)", req.Params.Name, timeout)).
+					WithSuggestion(fmt.Sprintf("Try again later; if %s needs longer, raise mcp.tool_timeouts.%s", req.Params.Name, req.Params.Name))
+				return FormatErrorResult(toolErr), nil
+			}
+		}
+	}
+}
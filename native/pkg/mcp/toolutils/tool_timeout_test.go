@@ -0,0 +1,74 @@
+package toolutils
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/logger"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func callTool(t *testing.T, s *server.MCPServer, name string) *mcp.CallToolResult {
+	t.Helper()
+	request, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": name, "arguments": map[string]any{}},
+	})
+	require.NoError(t, err)
+	response, ok := s.HandleMessage(context.Background(), request).(mcp.JSONRPCResponse)
+	require.True(t, ok, "tools/call failed")
+	result, ok := response.Result.(mcp.CallToolResult)
+	require.True(t, ok, "unexpected result %T", response.Result)
+	return &result
+}
+
+func TestToolTimeoutMiddleware(t *testing.T) {
+	log := logger.NewMockLogger()
+	timeouts := map[string]time.Duration{"slow": 50 * time.Millisecond, "stuck": 50 * time.Millisecond}
+	s := server.NewMCPServer("test", "0.0.0", server.WithToolHandlerMiddleware(
+		ToolTimeoutMiddleware(func(tool string) time.Duration { return timeouts[tool] }, log)))
+
+	cancelled := make(chan error, 1)
+	s.AddTool(mcp.NewTool("slow"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return mcp.NewToolResultText("too late"), nil
+	})
+	release := make(chan struct{})
+	defer close(release)
+	s.AddTool(mcp.NewTool("stuck"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Ignores its context entirely
+		<-release
+		return mcp.NewToolResultText("never seen"), nil
+	})
+	s.AddTool(mcp.NewTool("fast"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	for _, name := range []string{"slow", "stuck"} {
+		start := time.Now()
+		result := callTool(t, s, name)
+		assert.Less(t, time.Since(start), 2*time.Second, "%s must not block the agent", name)
+		require.True(t, result.IsError, "%s should time out", name)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, "NETWORK_TIMEOUT")
+		assert.Contains(t, text, name+" (no result within 50ms)")
+	}
+	select {
+	case err := <-cancelled:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(2 * time.Second):
+		t.Fatal("the timed out call's context was not cancelled")
+	}
+
+	result := callTool(t, s, "fast")
+	assert.False(t, result.IsError)
+	assert.Equal(t, "done", result.Content[0].(mcp.TextContent).Text)
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/messaging"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+)
+
+// AddAddressBookEntryParams represents the parameters for add_address_book_entry RPC method
+type AddAddressBookEntryParams struct {
+	Chain    string `json:"chain"`
+	Address  string `json:"address"`
+	Label    string `json:"label"`
+	Category string `json:"category,omitempty"`
+	Notes    string `json:"notes,omitempty"`
+}
+
+// RemoveAddressBookEntryParams represents the parameters for remove_address_book_entry RPC method
+type RemoveAddressBookEntryParams struct {
+	Chain   string `json:"chain"`
+	Address string `json:"address"`
+}
+
+// ListAddressBookParams represents the parameters for list_address_book RPC method
+type ListAddressBookParams struct {
+	Chain    string `json:"chain,omitempty"`
+	Category string `json:"category,omitempty"`
+}
+
+// CreateAddAddressBookEntryHandler creates an RPC handler for add_address_book_entry
+// method. An existing entry for the same chain and address is replaced,
+// whatever its category; the MCP tools cannot do that for entries whose
+// category is blocked or warned about.
+func CreateAddAddressBookEntryHandler(walletManager wallet.IWalletManager) messaging.RpcHandler {
+	return func(request messaging.RpcRequest) (messaging.RpcResponse, error) {
+		var params AddAddressBookEntryParams
+		if err := unmarshalAddressBookParams(request, &params); err != nil {
+			return addressBookInvalidParams(err.Error()), nil
+		}
+		if params.Chain == "" || params.Address == "" {
+			return addressBookInvalidParams("Chain and address are required"), nil
+		}
+
+		entry, err := walletManager.AddAddressBookEntry(context.Background(), &wallet.AddressBookEntry{
+			Chain:    params.Chain,
+			Address:  params.Address,
+			Label:    params.Label,
+			Category: params.Category,
+			Notes:    params.Notes,
+		})
+		if err != nil {
+			if errors.Is(err, wallet.ErrInvalidAddressBookEntry) {
+				return addressBookInvalidParams(err.Error()), nil
+			}
+			return addressBookFailure("Failed to add address book entry", err), nil
+		}
+		return addressBookResult(entry), nil
+	}
+}
+
+// CreateRemoveAddressBookEntryHandler creates an RPC handler for
+// remove_address_book_entry method
+func CreateRemoveAddressBookEntryHandler(walletManager wallet.IWalletManager) messaging.RpcHandler {
+	return func(request messaging.RpcRequest) (messaging.RpcResponse, error) {
+		var params RemoveAddressBookEntryParams
+		if err := unmarshalAddressBookParams(request, &params); err != nil {
+			return addressBookInvalidParams(err.Error()), nil
+		}
+		if params.Chain == "" || params.Address == "" {
+			return addressBookInvalidParams("Chain and address are required"), nil
+		}
+
+		entry, err := walletManager.RemoveAddressBookEntry(context.Background(), params.Chain, params.Address)
+		if err != nil {
+			if errors.Is(err, wallet.ErrAddressBookEntryNotFound) {
+				return addressBookInvalidParams(err.Error()), nil
+			}
+			return addressBookFailure("Failed to remove address book entry", err), nil
+		}
+		return addressBookResult(entry), nil
+	}
+}
+
+// CreateListAddressBookHandler creates an RPC handler for list_address_book
+// method, optionally filtered by chain and category
+func CreateListAddressBookHandler(walletManager wallet.IWalletManager) messaging.RpcHandler {
+	return func(request messaging.RpcRequest) (messaging.RpcResponse, error) {
+		var params ListAddressBookParams
+		if err := unmarshalAddressBookParams(request, &params); err != nil {
+			return addressBookInvalidParams(err.Error()), nil
+		}
+
+		entries, err := walletManager.ListAddressBook(context.Background(), params.Chain, params.Category)
+		if err != nil {
+			return addressBookFailure("Failed to list address book", err), nil
+		}
+		return addressBookResult(map[string]interface{}{"entries": entries}), nil
+	}
+}
+
+func unmarshalAddressBookParams(request messaging.RpcRequest, params interface{}) error {
+	if request.Params == nil {
+		return nil
+	}
+	if err := json.Unmarshal(request.Params, params); err != nil {
+		return fmt.Errorf("Invalid params: %s", err.Error())
+	}
+	return nil
+}
+
+func addressBookInvalidParams(message string) messaging.RpcResponse {
+	return messaging.RpcResponse{
+		Error: &messaging.ErrorInfo{
+			Code:    -32602,
+			Message: message,
+		},
+	}
+}
+
+func addressBookFailure(message string, err error) messaging.RpcResponse {
+	return messaging.RpcResponse{
+		Error: &messaging.ErrorInfo{
+			Code:    -32000,
+			Message: fmt.Sprintf("%s: %s", message, err.Error()),
+		},
+	}
+}
+
+func addressBookResult(result interface{}) messaging.RpcResponse {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return addressBookFailure("Failed to marshal result", err)
+	}
+	return messaging.RpcResponse{
+		Result: resultJSON,
+	}
+}
@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+)
+
+// knownEVMChainIDs are the chain IDs used for a chain without a configured
+// chain_id, by network mode. Testnet and devnet both mean Sepolia and BSC testnet.
+var knownEVMChainIDs = map[string]map[string]uint64{
+	config.NetworkModeMainnet: {"ethereum": 1, "bsc": 56},
+	config.NetworkModeTestnet: {"ethereum": 11155111, "bsc": 97},
+	config.NetworkModeDevnet:  {"ethereum": 11155111, "bsc": 97},
+}
+
+// ErrUnrecognizedChain is returned when switching to a chain that is unknown or
+// not enabled
+var ErrUnrecognizedChain = errors.New("unrecognized chain")
+
+// EVMNetwork is the EVM chain dApps talk to through web3_request: eth_chainId
+// reports it and eth_sendTransaction targets it. The active chain starts on the
+// configured default chain and is changed by the extension's
+// wallet_switch_network. A dApp's wallet_switchEthereumChain only changes the
+// chain of its own origin, which then no longer follows the active chain.
+type EVMNetwork struct {
+	mu       sync.RWMutex
+	chainIDs map[string]uint64
+	active   string
+	origins  map[string]string // chain each origin switched to
+}
+
+// NewEVMNetwork creates an EVMNetwork over the enabled EVM chains. A chain
+// without chain_id gets the well-known ID of the wallet's network mode.
+func NewEVMNetwork(walletConfig config.WalletConfig, chains config.ChainsConfig) *EVMNetwork {
+	mode, err := config.NormalizeNetworkMode(walletConfig.NetworkMode)
+	if err != nil {
+		mode = config.NetworkModeMainnet
+	}
+	configured := map[string]int{
+		"ethereum": chains.Ethereum.ChainID,
+		"bsc":      chains.BSC.ChainID,
+	}
+
+	n := &EVMNetwork{chainIDs: make(map[string]uint64), origins: make(map[string]string)}
+	for chainName, chainID := range configured {
+		if !chains.Enabled(chainName) {
+			continue
+		}
+		if chainID > 0 {
+			n.chainIDs[chainName] = uint64(chainID)
+		} else {
+			n.chainIDs[chainName] = knownEVMChainIDs[mode][chainName]
+		}
+	}
+
+	n.active = wallet.DefaultEVMChain(config.NormalizeChain(walletConfig.DefaultChain))
+	if _, ok := n.chainIDs[n.active]; !ok {
+		for _, chainName := range []string{"ethereum", "bsc"} {
+			if _, ok := n.chainIDs[chainName]; ok {
+				n.active = chainName
+				break
+			}
+		}
+	}
+	if _, ok := n.chainIDs[n.active]; !ok {
+		// No EVM chain is enabled; keep answering eth_chainId consistently
+		n.chainIDs[n.active] = knownEVMChainIDs[mode][n.active]
+	}
+	return n
+}
+
+// Active returns the active chain and its chain ID
+func (n *EVMNetwork) Active() (string, uint64) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.active, n.chainIDs[n.active]
+}
+
+// ActiveFor returns the chain and chain ID the dApp at origin is on: the one
+// it switched to, or the active chain
+func (n *EVMNetwork) ActiveFor(origin string) (string, uint64) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	chainName, ok := n.origins[origin]
+	if !ok || origin == "" {
+		chainName = n.active
+	}
+	return chainName, n.chainIDs[chainName]
+}
+
+// ChainForID returns the enabled chain with chainID
+func (n *EVMNetwork) ChainForID(chainID uint64) (string, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	for chainName, id := range n.chainIDs {
+		if id == chainID {
+			return chainName, true
+		}
+	}
+	return "", false
+}
+
+// Switch makes chainName, or an alias of it, the active chain and reports
+// whether the active chain changed
+func (n *EVMNetwork) Switch(chainName string) (bool, error) {
+	normalized := config.NormalizeChain(chainName)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, ok := n.chainIDs[normalized]; !ok {
+		return false, fmt.Errorf("%w: %s", ErrUnrecognizedChain, chainName)
+	}
+	changed := n.active != normalized
+	n.active = normalized
+	return changed, nil
+}
+
+// SwitchOrigin makes chainName, or an alias of it, the chain of the dApp at
+// origin, leaving the active chain and other origins alone. It reports whether
+// the origin's chain changed.
+func (n *EVMNetwork) SwitchOrigin(origin, chainName string) (bool, error) {
+	if origin == "" {
+		return false, errors.New("an origin is required to switch its chain")
+	}
+	normalized := config.NormalizeChain(chainName)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, ok := n.chainIDs[normalized]; !ok {
+		return false, fmt.Errorf("%w: %s", ErrUnrecognizedChain, chainName)
+	}
+	current, ok := n.origins[origin]
+	if !ok {
+		current = n.active
+	}
+	n.origins[origin] = normalized
+	return current != normalized, nil
+}
+
+// hexChainID formats chainID the way eth_chainId returns it
+func hexChainID(chainID uint64) string {
+	return "0x" + strconv.FormatUint(chainID, 16)
+}
+
+// parseChainID parses a 0x-prefixed hex or decimal chain ID
+func parseChainID(value string) (uint64, error) {
+	value = strings.TrimSpace(value)
+	if hexValue, ok := strings.CutPrefix(strings.ToLower(value), "0x"); ok {
+		return strconv.ParseUint(hexValue, 16, 64)
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/messaging"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+)
+
+// ErrTooManyExportAttempts is the error code of export_wallet after too many
+// wrong passwords
+const ErrTooManyExportAttempts = -32014
+
+// ExportWalletParams represents the parameters for export_wallet RPC method
+type ExportWalletParams struct {
+	Password string `json:"password"`
+}
+
+// CreateExportWalletHandler creates an RPC handler for export_wallet method,
+// which returns the mnemonic and private key of the unlocked wallet after
+// checking its password again. It is only registered on native messaging: the
+// secrets must never reach the MCP server.
+func CreateExportWalletHandler(walletManager wallet.IWalletManager) messaging.RpcHandler {
+	return func(request messaging.RpcRequest) (messaging.RpcResponse, error) {
+		var params ExportWalletParams
+		if request.Params != nil {
+			if err := json.Unmarshal(request.Params, &params); err != nil {
+				return messaging.RpcResponse{
+					Error: &messaging.ErrorInfo{
+						Code:    -32602,
+						Message: fmt.Sprintf("Invalid params: %s", err.Error()),
+					},
+				}, nil
+			}
+		}
+
+		if params.Password == "" {
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    -32602,
+					Message: "Password is required",
+				},
+			}, nil
+		}
+
+		if !walletManager.HasWallet() {
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    -32004,
+					Message: "No wallet found. Please create or import a wallet first.",
+				},
+			}, nil
+		}
+
+		export, err := walletManager.ExportWallet(context.Background(), params.Password, "user")
+		if err != nil {
+			code := -32000
+			switch {
+			case errors.Is(err, wallet.ErrExportRateLimited):
+				code = ErrTooManyExportAttempts
+			case errors.Is(err, wallet.ErrIncorrectPassword), errors.Is(err, wallet.ErrWalletLocked), errors.Is(err, wallet.ErrWalletFrozen):
+				code = -32001
+			}
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    code,
+					Message: fmt.Sprintf("Failed to export wallet: %s", err.Error()),
+				},
+			}, nil
+		}
+
+		resultJSON, err := json.Marshal(export)
+		if err != nil {
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    -32000,
+					Message: fmt.Sprintf("Failed to marshal result: %s", err.Error()),
+				},
+			}, nil
+		}
+
+		return messaging.RpcResponse{
+			Result: resultJSON,
+		}, nil
+	}
+}
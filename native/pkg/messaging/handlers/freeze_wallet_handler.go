@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/messaging"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+)
+
+// FreezeWalletParams represents the parameters for freeze_wallet RPC method
+type FreezeWalletParams struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// UnfreezeWalletParams represents the parameters for unfreeze_wallet RPC method
+type UnfreezeWalletParams struct {
+	Password string `json:"password"`
+}
+
+// CreateFreezeWalletHandler creates an RPC handler for freeze_wallet method. The
+// freeze takes effect immediately and survives restarts until unfreeze_wallet.
+func CreateFreezeWalletHandler(walletManager wallet.IWalletManager) messaging.RpcHandler {
+	return func(request messaging.RpcRequest) (messaging.RpcResponse, error) {
+		var params FreezeWalletParams
+		if request.Params != nil {
+			if err := json.Unmarshal(request.Params, &params); err != nil {
+				return messaging.RpcResponse{
+					Error: &messaging.ErrorInfo{
+						Code:    -32602,
+						Message: fmt.Sprintf("Invalid params: %s", err.Error()),
+					},
+				}, nil
+			}
+		}
+
+		// A status is returned even when persisting failed: the wallet is frozen for this run
+		status, err := walletManager.FreezeWallet(context.Background(), params.Reason, "user")
+		if status == nil {
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    -32000,
+					Message: fmt.Sprintf("Failed to freeze wallet: %s", err.Error()),
+				},
+			}, nil
+		}
+
+		result := map[string]interface{}{
+			"frozen":    status.Frozen,
+			"reason":    status.Reason,
+			"frozen_at": status.FrozenAt.Unix(),
+		}
+		if err != nil {
+			result["warning"] = err.Error()
+		}
+
+		resultJSON, err := json.Marshal(result)
+		if err != nil {
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    -32000,
+					Message: fmt.Sprintf("Failed to marshal result: %s", err.Error()),
+				},
+			}, nil
+		}
+
+		return messaging.RpcResponse{
+			Result: resultJSON,
+		}, nil
+	}
+}
+
+// CreateUnfreezeWalletHandler creates an RPC handler for unfreeze_wallet method.
+// The wallet password is required; the wallet stays locked afterwards.
+func CreateUnfreezeWalletHandler(walletManager wallet.IWalletManager) messaging.RpcHandler {
+	return func(request messaging.RpcRequest) (messaging.RpcResponse, error) {
+		var params UnfreezeWalletParams
+		if request.Params != nil {
+			if err := json.Unmarshal(request.Params, &params); err != nil {
+				return messaging.RpcResponse{
+					Error: &messaging.ErrorInfo{
+						Code:    -32602,
+						Message: fmt.Sprintf("Invalid params: %s", err.Error()),
+					},
+				}, nil
+			}
+		}
+
+		if params.Password == "" {
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    -32602,
+					Message: "Password is required",
+				},
+			}, nil
+		}
+
+		if err := walletManager.UnfreezeWallet(context.Background(), params.Password, "user"); err != nil {
+			code := -32001
+			if errors.Is(err, wallet.ErrWalletNotFrozen) {
+				code = -32000
+			}
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    code,
+					Message: fmt.Sprintf("Failed to unfreeze wallet: %s", err.Error()),
+				},
+			}, nil
+		}
+
+		resultJSON, err := json.Marshal(map[string]interface{}{
+			"frozen": false,
+			"locked": true,
+		})
+		if err != nil {
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    -32000,
+					Message: fmt.Sprintf("Failed to marshal result: %s", err.Error()),
+				},
+			}, nil
+		}
+
+		return messaging.RpcResponse{
+			Result: resultJSON,
+		}, nil
+	}
+}
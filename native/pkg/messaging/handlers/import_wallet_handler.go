@@ -16,6 +16,14 @@ type ImportWalletParams struct {
 	Password       string `json:"password"`
 	Chain          string `json:"chain"`
 	DerivationPath string `json:"derivation_path,omitempty"`
+	// ScanAccounts also imports every derived account with on-chain activity.
+	// Zero MaxAccounts or GapLimit use wallet.account_discovery from the config.
+	ScanAccounts bool `json:"scan_accounts,omitempty"`
+	MaxAccounts  int  `json:"max_accounts,omitempty"`
+	GapLimit     int  `json:"gap_limit,omitempty"`
+	// AccountCount also imports the first AccountCount accounts of the
+	// mnemonic along the chain's BIP-44 path, used or not
+	AccountCount int `json:"account_count,omitempty"`
 }
 
 // ImportWalletResult represents the result of import_wallet RPC method
@@ -23,6 +31,9 @@ type ImportWalletResult struct {
 	Address    string `json:"address"`
 	PublicKey  string `json:"publicKey"`
 	ImportedAt int64  `json:"importedAt"`
+	Accounts   []*wallet.DerivedAccount `json:"accounts,omitempty"`  // accounts added by account_count and scan_accounts
+	ScanError  string                   `json:"scanError,omitempty"` // set when the import succeeded but the scan did not
+	AccountsError string                `json:"accountsError,omitempty"` // set when the import succeeded but account_count did not
 }
 
 // ImportWalletError codes as specified in the requirements
@@ -78,6 +89,15 @@ func CreateImportWalletHandler(walletManager wallet.IWalletManager) messaging.Rp
 			}, nil
 		}
 
+		if params.AccountCount < 0 {
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    -32602,
+					Message: "Invalid params: account_count must not be negative",
+				},
+			}, nil
+		}
+
 		// Import wallet using wallet manager
 		address, publicKey, importedAt, err := walletManager.ImportWallet(
 			context.Background(),
@@ -119,6 +139,22 @@ func CreateImportWalletHandler(walletManager wallet.IWalletManager) messaging.Rp
 			PublicKey:  publicKey,
 			ImportedAt: importedAt,
 		}
+		if params.AccountCount > 0 {
+			accounts, err := walletManager.ImportAccounts(context.Background(), params.Chain, params.AccountCount)
+			if err != nil {
+				result.AccountsError = err.Error()
+			} else {
+				result.Accounts = accounts
+			}
+		}
+		if params.ScanAccounts {
+			accounts, err := walletManager.DiscoverAccounts(context.Background(), params.Chain, params.MaxAccounts, params.GapLimit)
+			if err != nil {
+				result.ScanError = err.Error()
+			} else {
+				result.Accounts = appendNewAccounts(result.Accounts, accounts)
+			}
+		}
 
 		resultJSON, err := json.Marshal(result)
 		if err != nil {
@@ -136,6 +172,23 @@ func CreateImportWalletHandler(walletManager wallet.IWalletManager) messaging.Rp
 	}
 }
 
+// appendNewAccounts appends the accounts not already in list
+func appendNewAccounts(list, accounts []*wallet.DerivedAccount) []*wallet.DerivedAccount {
+	for _, account := range accounts {
+		known := false
+		for _, existing := range list {
+			if existing.Address == account.Address {
+				known = true
+				break
+			}
+		}
+		if !known {
+			list = append(list, account)
+		}
+	}
+	return list
+}
+
 // contains checks if a string contains a substring (case-insensitive)
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 || 
@@ -4,6 +4,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/algonius/algonius-wallet/native/pkg/messaging"
@@ -297,3 +298,129 @@ func TestCreateImportWalletHandler_NilParams(t *testing.T) {
 		t.Errorf("Expected error code %d but got %d", ErrInvalidMnemonic, response.Error.Code)
 	}
 }
+
+func TestCreateImportWalletHandler_ScanAccounts(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	request := func(t *testing.T, params ImportWalletParams) messaging.RpcRequest {
+		paramsJSON, err := json.Marshal(params)
+		if err != nil {
+			t.Fatalf("Failed to marshal params: %v", err)
+		}
+		return messaging.RpcRequest{ID: "test-id", Method: "import_wallet", Params: paramsJSON}
+	}
+
+	t.Run("returns discovered accounts", func(t *testing.T) {
+		mockWalletManager := &wallet.MockWalletManager{}
+		mockWalletManager.On("ImportWallet", mock.Anything, mnemonic, "password123", "ethereum", "").Return("0xabc", "0x04", int64(1), nil)
+		mockWalletManager.On("DiscoverAccounts", mock.Anything, "ethereum", 10, 3).Return([]*wallet.DerivedAccount{
+			{Chain: "ethereum", Index: 2, Path: "m/44'/60'/0'/0/2", Address: "0xdef"},
+		}, nil)
+
+		response, err := CreateImportWalletHandler(mockWalletManager)(request(t, ImportWalletParams{
+			Mnemonic: mnemonic, Password: "password123", Chain: "ethereum",
+			ScanAccounts: true, MaxAccounts: 10, GapLimit: 3,
+		}))
+		if err != nil || response.Error != nil {
+			t.Fatalf("Expected success, got %v %v", err, response.Error)
+		}
+		var result ImportWalletResult
+		if err := json.Unmarshal(response.Result, &result); err != nil {
+			t.Fatalf("Failed to unmarshal result: %v", err)
+		}
+		if len(result.Accounts) != 1 || result.Accounts[0].Address != "0xdef" {
+			t.Errorf("Expected discovered account, got %+v", result.Accounts)
+		}
+		mockWalletManager.AssertExpectations(t)
+	})
+
+	t.Run("scan failure keeps the import", func(t *testing.T) {
+		mockWalletManager := &wallet.MockWalletManager{}
+		mockWalletManager.On("ImportWallet", mock.Anything, mnemonic, "password123", "solana", "").Return("Sol1", "Sol1", int64(1), nil)
+		mockWalletManager.On("DiscoverAccounts", mock.Anything, "solana", 0, 0).Return([]*wallet.DerivedAccount(nil), errors.New("rpc unavailable"))
+
+		response, err := CreateImportWalletHandler(mockWalletManager)(request(t, ImportWalletParams{
+			Mnemonic: mnemonic, Password: "password123", Chain: "solana", ScanAccounts: true,
+		}))
+		if err != nil || response.Error != nil {
+			t.Fatalf("Expected success, got %v %v", err, response.Error)
+		}
+		var result ImportWalletResult
+		if err := json.Unmarshal(response.Result, &result); err != nil {
+			t.Fatalf("Failed to unmarshal result: %v", err)
+		}
+		if result.Address != "Sol1" || result.ScanError == "" {
+			t.Errorf("Expected imported wallet with scan error, got %+v", result)
+		}
+	})
+}
+
+func TestCreateImportWalletHandler_AccountCount(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	request := func(t *testing.T, params ImportWalletParams) messaging.RpcRequest {
+		paramsJSON, err := json.Marshal(params)
+		if err != nil {
+			t.Fatalf("Failed to marshal params: %v", err)
+		}
+		return messaging.RpcRequest{ID: "test-id", Method: "import_wallet", Params: paramsJSON}
+	}
+
+	t.Run("imports the first accounts", func(t *testing.T) {
+		mockWalletManager := &wallet.MockWalletManager{}
+		mockWalletManager.On("ImportWallet", mock.Anything, mnemonic, "password123", "ethereum", "").Return("0xabc", "0x04", int64(1), nil)
+		mockWalletManager.On("ImportAccounts", mock.Anything, "ethereum", 3).Return([]*wallet.DerivedAccount{
+			{Chain: "ethereum", Index: 1, Path: "m/44'/60'/0'/0/1", Address: "0xdef"},
+			{Chain: "ethereum", Index: 2, Path: "m/44'/60'/0'/0/2", Address: "0x123"},
+		}, nil)
+		mockWalletManager.On("DiscoverAccounts", mock.Anything, "ethereum", 0, 0).Return([]*wallet.DerivedAccount{
+			{Chain: "ethereum", Index: 2, Path: "m/44'/60'/0'/0/2", Address: "0x123"},
+			{Chain: "ethereum", Index: 7, Path: "m/44'/60'/0'/0/7", Address: "0x777"},
+		}, nil)
+
+		response, err := CreateImportWalletHandler(mockWalletManager)(request(t, ImportWalletParams{
+			Mnemonic: mnemonic, Password: "password123", Chain: "ethereum",
+			AccountCount: 3, ScanAccounts: true,
+		}))
+		if err != nil || response.Error != nil {
+			t.Fatalf("Expected success, got %v %v", err, response.Error)
+		}
+		var result ImportWalletResult
+		if err := json.Unmarshal(response.Result, &result); err != nil {
+			t.Fatalf("Failed to unmarshal result: %v", err)
+		}
+		if len(result.Accounts) != 3 || result.Accounts[2].Address != "0x777" {
+			t.Errorf("Expected imported and discovered accounts without duplicates, got %+v", result.Accounts)
+		}
+		mockWalletManager.AssertExpectations(t)
+	})
+
+	t.Run("rejects a negative count", func(t *testing.T) {
+		mockWalletManager := &wallet.MockWalletManager{}
+		response, err := CreateImportWalletHandler(mockWalletManager)(request(t, ImportWalletParams{
+			Mnemonic: mnemonic, Password: "password123", Chain: "ethereum", AccountCount: -1,
+		}))
+		if err != nil || response.Error == nil || response.Error.Code != -32602 {
+			t.Fatalf("Expected invalid params, got %v %v", err, response.Error)
+		}
+		mockWalletManager.AssertNotCalled(t, "ImportWallet", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("failure keeps the import", func(t *testing.T) {
+		mockWalletManager := &wallet.MockWalletManager{}
+		mockWalletManager.On("ImportWallet", mock.Anything, mnemonic, "password123", "ethereum", "").Return("0xabc", "0x04", int64(1), nil)
+		mockWalletManager.On("ImportAccounts", mock.Anything, "ethereum", 500).Return([]*wallet.DerivedAccount(nil), errors.New("account count must be between 1 and 20"))
+
+		response, err := CreateImportWalletHandler(mockWalletManager)(request(t, ImportWalletParams{
+			Mnemonic: mnemonic, Password: "password123", Chain: "ethereum", AccountCount: 500,
+		}))
+		if err != nil || response.Error != nil {
+			t.Fatalf("Expected success, got %v %v", err, response.Error)
+		}
+		var result ImportWalletResult
+		if err := json.Unmarshal(response.Result, &result); err != nil {
+			t.Fatalf("Failed to unmarshal result: %v", err)
+		}
+		if result.Address != "0xabc" || result.AccountsError == "" {
+			t.Errorf("Expected imported wallet with accounts error, got %+v", result)
+		}
+	})
+}
@@ -0,0 +1,338 @@
+// Package handlers provides Native Messaging handlers for the Algonius Native Host.
+package handlers
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/messaging"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mr-tron/base58"
+)
+
+// Kinds of web3 request reported by parse_web3_request
+const (
+	Web3RequestKindTransaction = "transaction"
+	Web3RequestKindSignMessage = "sign_message"
+	Web3RequestKindAccounts    = "accounts"
+	Web3RequestKindChainID     = "chain_id"
+	Web3RequestKindSwitchChain = "switch_chain"
+	Web3RequestKindUnsupported = "unsupported"
+)
+
+// ParsedWeb3Request is what a web3_request asks for, as read by
+// parse_web3_request without queuing, signing or sending anything
+type ParsedWeb3Request struct {
+	Method    string `json:"method"`
+	Kind      string `json:"kind"`
+	Supported bool   `json:"supported"`
+	Origin    string `json:"origin,omitempty"`
+	DAppName  string `json:"dapp_name,omitempty"`
+	Chain     string `json:"chain,omitempty"`
+	// ChainID is the hex chain ID of Chain for EVM requests
+	ChainID        string `json:"chain_id,omitempty"`
+	RequiresUnlock bool   `json:"requires_unlock"` // the method signs with the wallet's keys
+	// Transaction and Intent are set for eth_sendTransaction
+	Transaction *ParsedTransaction        `json:"transaction,omitempty"`
+	Intent      *wallet.TransactionIntent `json:"intent,omitempty"`
+	// Token is the token contract a transfer or approval is called on
+	Token *wallet.TokenMetadata `json:"token,omitempty"`
+	// AutoApprovalRule names the rule web3_request would send the transaction
+	// under without asking for approval
+	AutoApprovalRule string             `json:"auto_approval_rule,omitempty"`
+	Message          *ParsedSignMessage `json:"message,omitempty"`
+	// Issues lists why web3_request would reject the request, or what an
+	// approver should check before accepting it
+	Issues []string `json:"issues,omitempty"`
+}
+
+// ParsedTransaction is an eth_sendTransaction request with its hex fields decoded
+type ParsedTransaction struct {
+	From         string   `json:"from"`
+	To           string   `json:"to,omitempty"`
+	Value        string   `json:"value"` // in native units
+	Gas          uint64   `json:"gas,omitempty"`
+	GasPriceGwei string   `json:"gas_price_gwei,omitempty"`
+	Nonce        *uint64  `json:"nonce,omitempty"`
+	Data         string   `json:"data,omitempty"`
+	Selector     string   `json:"selector,omitempty"`
+	Arguments    []string `json:"arguments,omitempty"` // 32-byte calldata words after the selector
+}
+
+// ParsedSignMessage is the message of a personal_sign or signMessage request
+type ParsedSignMessage struct {
+	Address string `json:"address,omitempty"`
+	Text    string `json:"text,omitempty"` // set when the message is valid UTF-8
+	Hex     string `json:"hex"`
+	Length  int    `json:"length"`
+}
+
+// CreateParseWeb3RequestHandler creates a handler that takes the params of
+// web3_request and returns a ParsedWeb3Request describing it. Nothing is
+// queued, signed or sent, and it works while the wallet is locked.
+func CreateParseWeb3RequestHandler(manager wallet.IWalletManager, autoApproval config.AutoApprovalPolicy) messaging.RpcHandler {
+	return CreateParseWeb3RequestHandlerWithNetwork(manager, autoApproval, nil)
+}
+
+// CreateParseWeb3RequestHandlerWithNetwork creates a parse_web3_request
+// handler that reads the requesting origin's chain from network, which must be
+// the one web3_request is served with. A nil network follows the wallet's
+// default chain with mainnet chain IDs, as web3_request does.
+func CreateParseWeb3RequestHandlerWithNetwork(manager wallet.IWalletManager, autoApproval config.AutoApprovalPolicy, network *EVMNetwork) messaging.RpcHandler {
+	return func(req messaging.RpcRequest) (messaging.RpcResponse, error) {
+		var params Web3RequestParams
+		if req.Params != nil {
+			if err := json.Unmarshal(req.Params, &params); err != nil {
+				return messaging.RpcResponse{
+					ID: req.ID,
+					Error: &messaging.ErrorInfo{
+						Code:    -32602,
+						Message: "Invalid web3 request params: " + err.Error(),
+					},
+				}, nil
+			}
+		}
+
+		parsed := ParseWeb3Request(context.Background(), params, manager, autoApproval, network)
+		result, _ := json.Marshal(parsed)
+		return messaging.RpcResponse{
+			ID:     req.ID,
+			Result: result,
+		}, nil
+	}
+}
+
+// ParseWeb3Request decodes and validates params the way web3_request would
+// handle them on the chain network has for the requesting origin. Malformed
+// params are reported as issues, not errors.
+func ParseWeb3Request(ctx context.Context, params Web3RequestParams, manager wallet.IWalletManager, autoApproval config.AutoApprovalPolicy, network *EVMNetwork) *ParsedWeb3Request {
+	parsed := &ParsedWeb3Request{
+		Method:         params.Method,
+		Kind:           Web3RequestKindUnsupported,
+		Supported:      true,
+		Origin:         params.Origin,
+		DAppName:       dappName(params.Origin),
+		RequiresUnlock: signingMethods[params.Method],
+	}
+	if parsed.RequiresUnlock && !manager.IsUnlocked() {
+		parsed.issue("the wallet is locked; web3_request would refuse this request")
+	}
+
+	switch params.Method {
+	case "eth_requestAccounts", "eth_accounts", "solana_requestAccounts":
+		parsed.Kind = Web3RequestKindAccounts
+	case "eth_chainId":
+		parsed.Kind = Web3RequestKindChainID
+		parsed.setEVMChain(activeEVMChain(manager, network, params.Origin))
+	case "wallet_switchEthereumChain":
+		parsed.Kind = Web3RequestKindSwitchChain
+		chainName, chainID, _, message := switchEthereumChainTarget(params, manager, network)
+		if message != "" {
+			parsed.issue(message)
+		} else {
+			parsed.setEVMChain(chainName, chainID)
+		}
+		if params.Origin == "" && network != nil {
+			parsed.issue("the request has no origin; only a dApp's own chain can be switched")
+		}
+	case "eth_sendTransaction":
+		parsed.Kind = Web3RequestKindTransaction
+		parsed.setEVMChain(activeEVMChain(manager, network, params.Origin))
+		parsed.parseTransaction(ctx, params, manager, autoApproval)
+	case "personal_sign":
+		parsed.Kind = Web3RequestKindSignMessage
+		parsed.parseSignMessage(ctx, params, manager, true)
+	case "signMessage":
+		parsed.Kind = Web3RequestKindSignMessage
+		parsed.Chain = "solana"
+		parsed.parseSignMessage(ctx, params, manager, false)
+	default:
+		parsed.Supported = false
+		parsed.issue("method " + params.Method + " is not supported")
+	}
+	return parsed
+}
+
+// parseTransaction fills in the decoded transaction, its intent and the
+// auto-approval rule it would match
+func (p *ParsedWeb3Request) parseTransaction(ctx context.Context, params Web3RequestParams, manager wallet.IWalletManager, autoApproval config.AutoApprovalPolicy) {
+	txParam, invalid := firstTransactionParams(params)
+	if invalid != "" {
+		p.issue(invalid)
+		return
+	}
+
+	request := wallet.AutoApprovalRequest{
+		Chain:  p.Chain,
+		From:   txParam.From,
+		To:     txParam.To,
+		Value:  txParam.Value,
+		Data:   txParam.Data,
+		Origin: params.Origin,
+	}
+	tx := &ParsedTransaction{
+		From:  txParam.From,
+		To:    txParam.To,
+		Value: request.NativeAmount(),
+		Data:  txParam.Data,
+	}
+	p.Transaction = tx
+
+	if chainID, err := parseChainID(p.ChainID); err == nil {
+		if mismatch := chainIDMismatch(txParam, p.Chain, chainID); mismatch != "" {
+			p.issue(mismatch)
+		}
+	}
+
+	if !common.IsHexAddress(txParam.From) {
+		p.issue("from is not a valid address")
+	} else if !p.isAccount(ctx, manager, txParam.From) {
+		p.issue("from is not an account of this wallet")
+	}
+	if txParam.To == "" {
+		p.issue("to is empty: the transaction deploys a contract")
+	} else if !common.IsHexAddress(txParam.To) {
+		p.issue("to is not a valid address")
+	}
+	if txParam.Value != "" && !isHexQuantity(txParam.Value) {
+		p.issue("value is not a hex quantity")
+	}
+	if txParam.Gas != "" {
+		if gas, ok := parseHexUint(txParam.Gas); ok {
+			tx.Gas = gas
+		} else {
+			p.issue("gas is not a hex quantity")
+		}
+	}
+	if txParam.GasPrice != "" {
+		if wei, ok := parseHexUint(txParam.GasPrice); ok {
+			gwei := new(big.Rat).SetFrac(new(big.Int).SetUint64(wei), big.NewInt(1_000_000_000)).FloatString(9)
+			tx.GasPriceGwei = strings.TrimSuffix(strings.TrimRight(gwei, "0"), ".")
+		} else {
+			p.issue("gasPrice is not a hex quantity")
+		}
+	}
+	if txParam.Nonce != "" {
+		if nonce, ok := parseHexUint(txParam.Nonce); ok {
+			tx.Nonce = &nonce
+		} else {
+			p.issue("nonce is not a hex quantity")
+		}
+	}
+
+	data := strings.TrimPrefix(strings.ToLower(txParam.Data), "0x")
+	if _, err := hex.DecodeString(data); err != nil {
+		p.issue("data is not hex encoded")
+	} else if len(data) >= 8 {
+		tx.Selector = "0x" + data[:8]
+		for rest := data[8:]; len(rest) > 0; {
+			n := min(64, len(rest))
+			tx.Arguments = append(tx.Arguments, "0x"+rest[:n])
+			rest = rest[n:]
+		}
+		if len(data[8:])%64 != 0 {
+			p.issue("calldata is not a whole number of 32-byte words")
+		}
+	}
+
+	p.Intent = wallet.DecodeTransactionIntent(p.Chain, txParam.To, txParam.Value, txParam.Data)
+	if p.Intent.Action == wallet.TxCategoryTokenTransfer || (p.Intent.Action == wallet.TxCategoryApproval && len(p.Intent.TokenSymbols) > 0) {
+		p.Token = wallet.LookupTokenMetadata(p.Chain, txParam.To)
+	}
+	if rule, ok := wallet.MatchAutoApprovalRule(autoApproval, request); ok {
+		p.AutoApprovalRule = rule.Name
+	}
+}
+
+// parseSignMessage fills in the message of a signing request. personal_sign
+// names the signing address; signMessage signs with the first account.
+func (p *ParsedWeb3Request) parseSignMessage(ctx context.Context, params Web3RequestParams, manager wallet.IWalletManager, withAddress bool) {
+	signParams, invalid := signingParams(params)
+	if invalid != "" {
+		p.issue(invalid)
+		return
+	}
+	if len(signParams) < 1 || (withAddress && len(signParams) < 2) {
+		p.issue("Missing signing parameters")
+		return
+	}
+	messageBytes, err := signingMessageBytes(signParams[0])
+	if err != nil {
+		p.issue(err.Error())
+		return
+	}
+
+	message := &ParsedSignMessage{Hex: "0x" + hex.EncodeToString(messageBytes), Length: len(messageBytes)}
+	if utf8.Valid(messageBytes) {
+		message.Text = string(messageBytes)
+	}
+	p.Message = message
+
+	if !withAddress {
+		return
+	}
+	address, ok := signParams[1].(string)
+	if !ok {
+		p.issue("Invalid address format")
+		return
+	}
+	message.Address = address
+	switch {
+	case common.IsHexAddress(address):
+		p.Chain = "ethereum"
+	case isBase58(address):
+		p.Chain = "solana"
+	default:
+		p.issue("address is not a valid address")
+		return
+	}
+	if !p.isAccount(ctx, manager, address) {
+		p.issue("address is not an account of this wallet")
+	}
+}
+
+// isAccount reports whether address is one of the wallet's accounts
+func (p *ParsedWeb3Request) isAccount(ctx context.Context, manager wallet.IWalletManager, address string) bool {
+	accounts, err := manager.GetAccounts(ctx)
+	if err != nil {
+		return false
+	}
+	for _, account := range accounts {
+		if strings.EqualFold(account, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// setEVMChain records the EVM chain the request is served on
+func (p *ParsedWeb3Request) setEVMChain(chainName string, chainID uint64) {
+	p.Chain = chainName
+	p.ChainID = hexChainID(chainID)
+}
+
+func (p *ParsedWeb3Request) issue(issue string) {
+	p.Issues = append(p.Issues, issue)
+}
+
+// isHexQuantity reports whether value is a 0x-prefixed hex number
+func isHexQuantity(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	digits := strings.TrimPrefix(value, "0x")
+	if digits == "" || digits == value {
+		return false
+	}
+	_, err := hex.DecodeString(strings.Repeat("0", len(digits)%2) + digits)
+	return err == nil
+}
+
+func isBase58(address string) bool {
+	decoded, err := base58.Decode(address)
+	return err == nil && len(decoded) == 32
+}
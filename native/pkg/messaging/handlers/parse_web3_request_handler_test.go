@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: Apache-2.0
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/messaging"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func parseWeb3Request(t *testing.T, manager *wallet.MockWalletManager, policy config.AutoApprovalPolicy, request Web3RequestParams) *ParsedWeb3Request {
+	t.Helper()
+	params, err := json.Marshal(request)
+	require.NoError(t, err)
+	resp, err := CreateParseWeb3RequestHandler(manager, policy)(messaging.RpcRequest{ID: "1", Params: params})
+	require.NoError(t, err)
+	require.Nil(t, resp.Error)
+
+	var parsed ParsedWeb3Request
+	require.NoError(t, json.Unmarshal(resp.Result, &parsed))
+	return &parsed
+}
+
+func TestParseWeb3RequestDecodesTransactionWithoutQueuing(t *testing.T) {
+	mockWalletManager := &wallet.MockWalletManager{}
+	mockWalletManager.On("IsUnlocked").Return(false)
+	mockWalletManager.On("DefaultChain").Return("ethereum")
+	mockWalletManager.On("GetAccounts", mock.Anything).Return([]string{"0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"}, nil)
+
+	// approve(Uniswap V2 Router, unlimited) on USDC
+	data := "0x095ea7b3" +
+		"0000000000000000000000007a250d5630b4cf539739df2c5dacb4c659f2488d" +
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+	parsed := parseWeb3Request(t, mockWalletManager, config.AutoApprovalPolicy{}, Web3RequestParams{
+		Method: "eth_sendTransaction",
+		Origin: "https://app.uniswap.org",
+		Params: []TransactionParams{{
+			From:     "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+			To:       "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+			Value:    "0x0",
+			Gas:      "0xea60",
+			GasPrice: "0x4a817c800",
+			Nonce:    "0x7",
+			Data:     data,
+		}},
+	})
+
+	assert.Equal(t, Web3RequestKindTransaction, parsed.Kind)
+	assert.Equal(t, "Uniswap", parsed.DAppName)
+	assert.Equal(t, "ethereum", parsed.Chain)
+	require.NotNil(t, parsed.Transaction)
+	assert.Equal(t, "0", parsed.Transaction.Value)
+	assert.Equal(t, uint64(60000), parsed.Transaction.Gas)
+	assert.Equal(t, "20", parsed.Transaction.GasPriceGwei)
+	require.NotNil(t, parsed.Transaction.Nonce)
+	assert.Equal(t, uint64(7), *parsed.Transaction.Nonce)
+	assert.Equal(t, "0x095ea7b3", parsed.Transaction.Selector)
+	assert.Len(t, parsed.Transaction.Arguments, 2)
+
+	require.NotNil(t, parsed.Intent)
+	assert.Equal(t, wallet.TxCategoryApproval, parsed.Intent.Action)
+	assert.Equal(t, wallet.RiskLevelHigh, parsed.Intent.RiskLevel)
+	require.NotNil(t, parsed.Token)
+	assert.Equal(t, "USDC", parsed.Token.Symbol)
+	assert.Equal(t, 6, parsed.Token.Decimals)
+
+	// Parsing works while locked and reports that sending would not
+	assert.True(t, parsed.RequiresUnlock)
+	assert.Contains(t, parsed.Issues, "the wallet is locked; web3_request would refuse this request")
+	mockWalletManager.AssertNotCalled(t, "AddPendingTransaction", mock.Anything, mock.Anything)
+	mockWalletManager.AssertNotCalled(t, "AutoApproveTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestParseWeb3RequestReportsAutoApprovalAndIssues(t *testing.T) {
+	mockWalletManager := &wallet.MockWalletManager{}
+	mockWalletManager.On("IsUnlocked").Return(true)
+	mockWalletManager.On("DefaultChain").Return("bsc")
+	mockWalletManager.On("GetAccounts", mock.Anything).Return([]string{"0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"}, nil)
+	policy := config.AutoApprovalPolicy{
+		Enabled: true,
+		Rules:   []config.AutoApprovalRule{{Name: "small-transfers", MaxValue: 0.1, Methods: []string{"transfer"}}},
+	}
+
+	parsed := parseWeb3Request(t, mockWalletManager, policy, Web3RequestParams{
+		Method: "eth_sendTransaction",
+		Params: []TransactionParams{{
+			From:  "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+			To:    "0x2F62f2B4c5fcd7570a709DeC05D68EA19c82A9ec",
+			Value: "0x2386f26fc10000",
+		}},
+	})
+	assert.Equal(t, "bsc", parsed.Chain)
+	assert.Equal(t, "0.01", parsed.Transaction.Value)
+	assert.Equal(t, "small-transfers", parsed.AutoApprovalRule)
+	assert.Empty(t, parsed.Issues)
+	mockWalletManager.AssertNotCalled(t, "AutoApproveTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	parsed = parseWeb3Request(t, mockWalletManager, policy, Web3RequestParams{
+		Method: "eth_sendTransaction",
+		Params: []TransactionParams{{
+			From:  "0x8ba1f109551bD432803012645Ac136ddd64DBA72",
+			To:    "not-an-address",
+			Value: "100",
+			Data:  "0xzz",
+		}},
+	})
+	assert.ElementsMatch(t, []string{
+		"from is not an account of this wallet",
+		"to is not a valid address",
+		"value is not a hex quantity",
+		"data is not hex encoded",
+	}, parsed.Issues)
+
+	parsed = parseWeb3Request(t, mockWalletManager, policy, Web3RequestParams{Method: "eth_signTypedData_v4"})
+	assert.False(t, parsed.Supported)
+	assert.Equal(t, Web3RequestKindUnsupported, parsed.Kind)
+}
+
+func TestParseWeb3RequestSignMessage(t *testing.T) {
+	mockWalletManager := &wallet.MockWalletManager{}
+	mockWalletManager.On("IsUnlocked").Return(true)
+	mockWalletManager.On("GetAccounts", mock.Anything).Return([]string{"0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"}, nil)
+
+	parsed := parseWeb3Request(t, mockWalletManager, config.AutoApprovalPolicy{}, Web3RequestParams{
+		Method: "personal_sign",
+		Params: []interface{}{"Sign in to example.com", "0x742d35cc6634c0532925a3b8d4c2b79c2b86a7a8"},
+	})
+	assert.Equal(t, Web3RequestKindSignMessage, parsed.Kind)
+	assert.Equal(t, "ethereum", parsed.Chain)
+	require.NotNil(t, parsed.Message)
+	assert.Equal(t, "Sign in to example.com", parsed.Message.Text)
+	assert.Equal(t, 22, parsed.Message.Length)
+	assert.Empty(t, parsed.Issues)
+
+	// Raw bytes as a Uint8Array serializes them
+	parsed = parseWeb3Request(t, mockWalletManager, config.AutoApprovalPolicy{}, Web3RequestParams{
+		Method: "signMessage",
+		Params: []interface{}{map[string]interface{}{"0": 255, "1": 1}},
+	})
+	assert.Equal(t, "solana", parsed.Chain)
+	assert.Equal(t, "0xff01", parsed.Message.Hex)
+	assert.Empty(t, parsed.Message.Text, "invalid UTF-8 is only shown as hex")
+	mockWalletManager.AssertNotCalled(t, "SignMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestParseWeb3RequestFollowsOriginChain(t *testing.T) {
+	cfg := config.DefaultConfig()
+	network := NewEVMNetwork(cfg.Wallet, cfg.Chains)
+	_, err := network.SwitchOrigin("https://pancakeswap.finance", "bsc")
+	require.NoError(t, err)
+	mockWalletManager := &wallet.MockWalletManager{}
+	mockWalletManager.On("IsUnlocked").Return(true)
+	mockWalletManager.On("GetAccounts", mock.Anything).Return([]string{"0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"}, nil)
+	parse := func(request Web3RequestParams) *ParsedWeb3Request {
+		params, err := json.Marshal(request)
+		require.NoError(t, err)
+		resp, err := CreateParseWeb3RequestHandlerWithNetwork(mockWalletManager, config.AutoApprovalPolicy{}, network)(messaging.RpcRequest{ID: "1", Params: params})
+		require.NoError(t, err)
+		var parsed ParsedWeb3Request
+		require.NoError(t, json.Unmarshal(resp.Result, &parsed))
+		return &parsed
+	}
+
+	parsed := parse(Web3RequestParams{Method: "eth_chainId", Origin: "https://pancakeswap.finance"})
+	assert.Equal(t, "bsc", parsed.Chain)
+	assert.Equal(t, "0x38", parsed.ChainID)
+	parsed = parse(Web3RequestParams{Method: "eth_chainId", Origin: "https://app.uniswap.org"})
+	assert.Equal(t, "ethereum", parsed.Chain)
+
+	parsed = parse(Web3RequestParams{Method: "wallet_switchEthereumChain", Origin: "https://app.uniswap.org", Params: []interface{}{map[string]string{"chainId": "0x38"}}})
+	assert.True(t, parsed.Supported)
+	assert.Equal(t, Web3RequestKindSwitchChain, parsed.Kind)
+	assert.Equal(t, "bsc", parsed.Chain)
+	assert.Empty(t, parsed.Issues)
+	parsed = parse(Web3RequestParams{Method: "wallet_switchEthereumChain", Origin: "https://app.uniswap.org", Params: []interface{}{map[string]string{"chainId": "0x89"}}})
+	assert.Contains(t, parsed.Issues, "Unrecognized chain ID 0x89")
+
+	parsed = parse(Web3RequestParams{Method: "eth_sendTransaction", Origin: "https://pancakeswap.finance", Params: []TransactionParams{{
+		From:    "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+		To:      "0x2F62f2B4c5fcd7570a709DeC05D68EA19c82A9ec",
+		Value:   "0x1",
+		ChainID: "0x1",
+	}}})
+	assert.Equal(t, "bsc", parsed.Chain)
+	assert.Contains(t, parsed.Issues, "chainId 0x1 does not match the active chain bsc (0x38)")
+
+	// Parsing never switches anything
+	chainName, _ := network.ActiveFor("https://app.uniswap.org")
+	assert.Equal(t, "ethereum", chainName)
+}
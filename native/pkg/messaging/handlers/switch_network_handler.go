@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/messaging"
+)
+
+// SwitchNetworkParams represents the parameters for wallet_switch_network RPC
+// method. Chain takes a chain name or alias; ChainID a hex or decimal chain ID.
+type SwitchNetworkParams struct {
+	Chain   string `json:"chain,omitempty"`
+	ChainID string `json:"chain_id,omitempty"`
+}
+
+// SwitchNetworkResult represents the result of wallet_switch_network RPC method
+type SwitchNetworkResult struct {
+	Chain   string `json:"chain"`
+	ChainID string `json:"chain_id"`
+}
+
+// CreateSwitchNetworkHandler creates an RPC handler for wallet_switch_network
+// method, which changes the EVM chain web3 requests are served on
+func CreateSwitchNetworkHandler(network *EVMNetwork, broadcaster *event.EventBroadcaster) messaging.RpcHandler {
+	return func(request messaging.RpcRequest) (messaging.RpcResponse, error) {
+		var params SwitchNetworkParams
+		if request.Params != nil {
+			if err := json.Unmarshal(request.Params, &params); err != nil {
+				return messaging.RpcResponse{
+					Error: &messaging.ErrorInfo{
+						Code:    -32602,
+						Message: fmt.Sprintf("Invalid params: %s", err.Error()),
+					},
+				}, nil
+			}
+		}
+
+		chainName := params.Chain
+		if chainName == "" && params.ChainID != "" {
+			chainID, err := parseChainID(params.ChainID)
+			if err != nil {
+				return messaging.RpcResponse{
+					Error: &messaging.ErrorInfo{
+						Code:    -32602,
+						Message: fmt.Sprintf("Invalid chain_id: %s", params.ChainID),
+					},
+				}, nil
+			}
+			var ok bool
+			if chainName, ok = network.ChainForID(chainID); !ok {
+				return messaging.RpcResponse{
+					Error: &messaging.ErrorInfo{
+						Code:    unrecognizedChainCode,
+						Message: fmt.Sprintf("Unrecognized chain ID %s", hexChainID(chainID)),
+					},
+				}, nil
+			}
+		}
+		if chainName == "" {
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    -32602,
+					Message: "Chain or chain_id is required",
+				},
+			}, nil
+		}
+
+		changed, err := network.Switch(chainName)
+		if err != nil {
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    unrecognizedChainCode,
+					Message: fmt.Sprintf("Failed to switch network: %s", err.Error()),
+				},
+			}, nil
+		}
+
+		active, chainID := network.Active()
+		result := SwitchNetworkResult{Chain: active, ChainID: hexChainID(chainID)}
+		if changed && broadcaster != nil {
+			broadcaster.BroadcastNetworkChanged(result.Chain, result.ChainID, "extension")
+		}
+
+		resultJSON, err := json.Marshal(result)
+		if err != nil {
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    -32000,
+					Message: fmt.Sprintf("Failed to marshal result: %s", err.Error()),
+				},
+			}, nil
+		}
+
+		return messaging.RpcResponse{
+			Result: resultJSON,
+		}, nil
+	}
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSwitchNetworkHandler(t *testing.T) {
+	cfg := config.DefaultConfig()
+	network := NewEVMNetwork(cfg.Wallet, cfg.Chains)
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+	handler := CreateSwitchNetworkHandler(network, broadcaster)
+	call := func(params SwitchNetworkParams) messaging.RpcResponse {
+		raw, err := json.Marshal(params)
+		require.NoError(t, err)
+		resp, err := handler(messaging.RpcRequest{ID: "1", Params: raw})
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := call(SwitchNetworkParams{Chain: "Binance Smart Chain"})
+	require.Nil(t, resp.Error)
+	var result SwitchNetworkResult
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	assert.Equal(t, SwitchNetworkResult{Chain: "bsc", ChainID: "0x38"}, result)
+
+	evt := <-events
+	assert.Equal(t, event.EventTypeNetworkChanged, evt.Type)
+	assert.Equal(t, "extension", evt.Data["source"])
+
+	resp = call(SwitchNetworkParams{ChainID: "1"})
+	require.Nil(t, resp.Error)
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	assert.Equal(t, SwitchNetworkResult{Chain: "ethereum", ChainID: "0x1"}, result)
+	<-events
+
+	// Switching to the active chain again changes nothing
+	call(SwitchNetworkParams{Chain: "eth"})
+	assert.Empty(t, events)
+
+	resp = call(SwitchNetworkParams{Chain: "solana"})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, unrecognizedChainCode, resp.Error.Code)
+
+	resp = call(SwitchNetworkParams{ChainID: "0x89"})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, unrecognizedChainCode, resp.Error.Code)
+
+	resp = call(SwitchNetworkParams{})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, -32602, resp.Error.Code)
+}
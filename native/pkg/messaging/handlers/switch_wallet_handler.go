@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/messaging"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+)
+
+// SwitchWalletParams represents the parameters for switch_wallet RPC method
+type SwitchWalletParams struct {
+	Address string `json:"address"`
+}
+
+// SwitchWalletResult represents the result of switch_wallet RPC method
+type SwitchWalletResult struct {
+	Address  string `json:"address"`
+	Unlocked bool   `json:"unlocked"`
+}
+
+// CreateSwitchWalletHandler creates an RPC handler for switch_wallet method.
+// A wallet that was not unlocked this session stays locked until unlock_wallet.
+func CreateSwitchWalletHandler(walletManager wallet.IWalletManager) messaging.RpcHandler {
+	return func(request messaging.RpcRequest) (messaging.RpcResponse, error) {
+		var params SwitchWalletParams
+		if request.Params != nil {
+			if err := json.Unmarshal(request.Params, &params); err != nil {
+				return messaging.RpcResponse{
+					Error: &messaging.ErrorInfo{
+						Code:    -32602,
+						Message: fmt.Sprintf("Invalid params: %s", err.Error()),
+					},
+				}, nil
+			}
+		}
+
+		if params.Address == "" {
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    -32602,
+					Message: "Address is required",
+				},
+			}, nil
+		}
+
+		if err := walletManager.SwitchWallet(context.Background(), params.Address); err != nil {
+			code := -32000
+			if errors.Is(err, wallet.ErrWalletNotFound) {
+				code = -32004
+			}
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    code,
+					Message: fmt.Sprintf("Failed to switch wallet: %s", err.Error()),
+				},
+			}, nil
+		}
+
+		result := SwitchWalletResult{Unlocked: walletManager.IsUnlocked()}
+		if status := walletManager.GetCurrentWallet(); status != nil {
+			result.Address = status.Address
+		}
+		resultJSON, err := json.Marshal(result)
+		if err != nil {
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    -32000,
+					Message: fmt.Sprintf("Failed to marshal result: %s", err.Error()),
+				},
+			}, nil
+		}
+
+		return messaging.RpcResponse{
+			Result: resultJSON,
+		}, nil
+	}
+}
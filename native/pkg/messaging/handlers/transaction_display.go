@@ -0,0 +1,156 @@
+// Package handlers provides Native Messaging handlers for the Algonius Native Host.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// displayLookupTimeout bounds the fee and price lookups made while a dApp waits for a response
+const displayLookupTimeout = 5 * time.Second
+
+// knownDApps maps origin hosts (and their subdomains) to display names
+var knownDApps = map[string]string{
+	"uniswap.org":         "Uniswap",
+	"pancakeswap.finance": "PancakeSwap",
+	"1inch.io":            "1inch",
+	"sushi.com":           "SushiSwap",
+	"opensea.io":          "OpenSea",
+	"aave.com":            "Aave",
+	"curve.fi":            "Curve",
+}
+
+// transactionDisplayMetadata builds the human-friendly fields shown next to a
+// pending dApp transaction: the dApp name, the decoded intent, whether the
+// recipient is a wallet or a contract, the fee in native units and USD, and a
+// risk assessment. Fee fields are omitted when the fee or price cannot be
+// determined; while the price feed is down the USD fee is replaced by the
+// reason it is unavailable.
+func transactionDisplayMetadata(ctx context.Context, chainName, origin string, txParam TransactionParams, manager wallet.IWalletManager, priceFeed wallet.PriceFeed) map[string]interface{} {
+	intent := wallet.DecodeTransactionIntent(chainName, txParam.To, txParam.Value, txParam.Data)
+
+	metadata := map[string]interface{}{
+		"dapp_name":     dappName(origin),
+		"intent":        intent.Summary,
+		"action":        intent.Action,
+		"protocol":      intent.Protocol,
+		"method":        intent.Method,
+		"token_symbols": intent.TokenSymbols,
+		"risk_level":    intent.RiskLevel,
+		"risk_flags":    intent.RiskFlags,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, displayLookupTimeout)
+	defer cancel()
+
+	// Flag transfers to contracts or token accounts that may not be able to use them
+	if intent.Recipient != "" && manager != nil {
+		token := ""
+		if intent.Action == wallet.TxCategoryTokenTransfer {
+			token = txParam.To
+		}
+		if recipient, err := manager.ClassifyRecipient(ctx, chainName, intent.Recipient, token); err == nil {
+			metadata["recipient_type"] = recipient.Kind
+			if recipient.Label != "" {
+				metadata["recipient_label"] = recipient.Label
+				metadata["recipient_category"] = recipient.Category
+			}
+			if recipient.Warning != "" {
+				metadata["recipient_warning"] = recipient.Warning
+			}
+		}
+	}
+
+	fee, err := estimateTransactionFee(ctx, chainName, txParam, manager)
+	if err != nil {
+		return metadata
+	}
+	metadata["estimated_fee"] = fee
+
+	if priceFeed != nil {
+		price, err := wallet.CurrentUSDPrice(ctx, priceFeed, wallet.NativeTokenSymbol(wallet.NormalizeChain(chainName)))
+		if errors.Is(err, wallet.ErrPriceFeedUnavailable) {
+			metadata["estimated_fee_usd_unavailable"] = err.Error()
+		} else if err == nil {
+			if feeValue, ok := new(big.Rat).SetString(fee); ok {
+				usd, _ := new(big.Rat).Mul(feeValue, new(big.Rat).SetFloat64(price.Price)).Float64()
+				metadata["estimated_fee_usd"] = fmt.Sprintf("%.2f", usd)
+				if price.Stale {
+					metadata["estimated_fee_usd_price_age"] = int64(time.Since(price.FetchedAt).Seconds())
+				}
+			}
+		}
+	}
+	return metadata
+}
+
+// estimateTransactionFee returns the worst-case fee in native units, using the
+// dApp's gas and gasPrice when given and the chain's estimate otherwise
+func estimateTransactionFee(ctx context.Context, chainName string, txParam TransactionParams, manager wallet.IWalletManager) (string, error) {
+	gasLimit, limitOK := parseHexUint(txParam.Gas)
+	gasPriceGwei := ""
+	if wei, ok := new(big.Int).SetString(strings.TrimPrefix(strings.ToLower(txParam.GasPrice), "0x"), 16); ok && txParam.GasPrice != "" {
+		gasPriceGwei = new(big.Rat).SetFrac(wei, big.NewInt(1_000_000_000)).FloatString(9)
+	}
+
+	if !limitOK || gasPriceGwei == "" {
+		if manager == nil {
+			return "", fmt.Errorf("no gas estimate available")
+		}
+		amount := txParam.Value
+		if amount == "" {
+			amount = "0"
+		}
+		estimatedLimit, estimatedPrice, err := manager.EstimateGas(ctx, chainName, txParam.From, txParam.To, amount, "")
+		if err != nil {
+			return "", err
+		}
+		if !limitOK {
+			gasLimit = estimatedLimit
+		}
+		if gasPriceGwei == "" {
+			gasPriceGwei = estimatedPrice
+		}
+	}
+
+	return chain.TransferFee(chainName, gasLimit, gasPriceGwei)
+}
+
+// dappName derives a display name from a page origin such as https://app.uniswap.org
+func dappName(origin string) string {
+	if origin == "" {
+		return ""
+	}
+	host := origin
+	if u, err := url.Parse(origin); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+
+	for domain, name := range knownDApps {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return name
+		}
+	}
+	return host
+}
+
+func parseHexUint(value string) (uint64, bool) {
+	v := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), "0x")
+	if v == "" {
+		return 0, false
+	}
+	n, ok := new(big.Int).SetString(v, 16)
+	if !ok || !n.IsUint64() {
+		return 0, false
+	}
+	return n.Uint64(), true
+}
@@ -6,12 +6,15 @@ import (
 	"fmt"
 
 	"github.com/algonius/algonius-wallet/native/pkg/messaging"
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
 	"github.com/algonius/algonius-wallet/native/pkg/wallet"
 	"go.uber.org/zap"
 )
 
 // UnlockWalletParams represents the parameters for unlock_wallet RPC method
 type UnlockWalletParams struct {
+	// Address selects the stored wallet to unlock; empty unlocks the selected wallet
+	Address  string `json:"address,omitempty"`
 	Password string `json:"password"`
 }
 
@@ -27,7 +30,14 @@ type UnlockWalletResult struct {
 type WalletStatusResult struct {
 	HasWallet  bool   `json:"hasWallet"`
 	IsUnlocked bool   `json:"isUnlocked"`
+	IsFrozen   bool   `json:"isFrozen"`
 	Address    string `json:"address,omitempty"`
+	
+	// LastSuccessfulCall maps chain name to the unix time of its last successful RPC call
+	LastSuccessfulCall map[string]int64 `json:"lastSuccessfulCall,omitempty"`
+
+	// ProviderBreakers reports the circuit breaker of every external HTTP provider host
+	ProviderBreakers []httpclient.BreakerStats `json:"providerBreakers,omitempty"`
 }
 
 // CreateUnlockWalletHandler creates an RPC handler for unlock_wallet method
@@ -68,7 +78,7 @@ func CreateUnlockWalletHandler(walletManager wallet.IWalletManager) messaging.Rp
 		}
 
 		// Unlock wallet
-		err := walletManager.UnlockWallet(params.Password)
+		err := walletManager.UnlockWallet(params.Address, params.Password)
 		if err != nil {
 			return messaging.RpcResponse{
 				Error: &messaging.ErrorInfo{
@@ -148,8 +158,11 @@ func CreateWalletStatusHandler(walletManager wallet.IWalletManager, logger *zap.
 		isUnlocked := walletManager.IsUnlocked()
 		
 		result := WalletStatusResult{
-			HasWallet:  hasWallet,
-			IsUnlocked: isUnlocked,
+			HasWallet:          hasWallet,
+			IsUnlocked:         isUnlocked,
+			IsFrozen:           walletManager.IsFrozen(),
+			LastSuccessfulCall: wallet.LastSuccessfulRPCCalls(),
+			ProviderBreakers:   wallet.ProviderBreakers(),
 		}
 		
 		// Add address if wallet is unlocked
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/messaging"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+)
+
+// SetWalletLabelParams represents the parameters for set_wallet_label RPC method
+type SetWalletLabelParams struct {
+	Address string `json:"address"`
+	Label   string `json:"label"`
+}
+
+// CreateSetWalletLabelHandler creates an RPC handler for set_wallet_label method.
+// An empty label removes the wallet's label.
+func CreateSetWalletLabelHandler(walletManager wallet.IWalletManager) messaging.RpcHandler {
+	return func(request messaging.RpcRequest) (messaging.RpcResponse, error) {
+		var params SetWalletLabelParams
+		if request.Params != nil {
+			if err := json.Unmarshal(request.Params, &params); err != nil {
+				return messaging.RpcResponse{
+					Error: &messaging.ErrorInfo{
+						Code:    -32602,
+						Message: fmt.Sprintf("Invalid params: %s", err.Error()),
+					},
+				}, nil
+			}
+		}
+
+		if params.Address == "" {
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    -32602,
+					Message: "Address is required",
+				},
+			}, nil
+		}
+
+		stored, err := walletManager.SetWalletLabel(context.Background(), params.Address, params.Label)
+		if err != nil {
+			code := -32000
+			if errors.Is(err, wallet.ErrInvalidWalletLabel) {
+				code = -32602
+			}
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    code,
+					Message: fmt.Sprintf("Failed to set wallet label: %s", err.Error()),
+				},
+			}, nil
+		}
+
+		resultJSON, err := json.Marshal(stored)
+		if err != nil {
+			return messaging.RpcResponse{
+				Error: &messaging.ErrorInfo{
+					Code:    -32000,
+					Message: fmt.Sprintf("Failed to marshal result: %s", err.Error()),
+				},
+			}, nil
+		}
+
+		return messaging.RpcResponse{
+			Result: resultJSON,
+		}, nil
+	}
+}
@@ -4,11 +4,13 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/algonius/algonius-wallet/native/pkg/config"
 	"github.com/algonius/algonius-wallet/native/pkg/event"
 	"github.com/algonius/algonius-wallet/native/pkg/messaging"
 	"github.com/algonius/algonius-wallet/native/pkg/wallet"
@@ -31,10 +33,47 @@ type TransactionParams struct {
 	GasPrice string `json:"gasPrice,omitempty"`
 	Data     string `json:"data,omitempty"`
 	Nonce    string `json:"nonce,omitempty"`
+	ChainID  string `json:"chainId,omitempty"`
+}
+
+// walletLockedCode is the EIP-1193 "Unauthorized" code returned while the wallet is locked or frozen
+const walletLockedCode = 4100
+
+// unrecognizedChainCode is the EIP-3326 code for a wallet_switchEthereumChain
+// request naming a chain the wallet does not serve
+const unrecognizedChainCode = 4902
+
+// signingMethods are the web3 methods that sign or send with the wallet's keys
+var signingMethods = map[string]bool{
+	"eth_sendTransaction": true,
+	"personal_sign":       true,
+	"signMessage":         true,
 }
 
 // CreateWeb3RequestHandler creates a handler for web3 requests from web pages
 func CreateWeb3RequestHandler(manager wallet.IWalletManager, broadcaster *event.EventBroadcaster) messaging.RpcHandler {
+	return CreateWeb3RequestHandlerWithPriceFeed(manager, broadcaster, nil)
+}
+
+// CreateWeb3RequestHandlerWithPriceFeed creates a web3 request handler that prices
+// transaction fees in USD with priceFeed. A nil priceFeed omits USD values.
+func CreateWeb3RequestHandlerWithPriceFeed(manager wallet.IWalletManager, broadcaster *event.EventBroadcaster, priceFeed wallet.PriceFeed) messaging.RpcHandler {
+	return CreateWeb3RequestHandlerWithPolicy(manager, broadcaster, priceFeed, config.AutoApprovalPolicy{})
+}
+
+// CreateWeb3RequestHandlerWithPolicy creates a web3 request handler that sends
+// eth_sendTransaction requests matching autoApproval directly instead of
+// queueing them for approval
+func CreateWeb3RequestHandlerWithPolicy(manager wallet.IWalletManager, broadcaster *event.EventBroadcaster, priceFeed wallet.PriceFeed, autoApproval config.AutoApprovalPolicy) messaging.RpcHandler {
+	return CreateWeb3RequestHandlerWithNetwork(manager, broadcaster, priceFeed, autoApproval, nil)
+}
+
+// CreateWeb3RequestHandlerWithNetwork creates a web3 request handler that
+// answers eth_chainId and sends eth_sendTransaction on the chain network has
+// for the requesting origin, which a dApp can change for itself with
+// wallet_switchEthereumChain. A nil network follows the wallet's default chain
+// with mainnet chain IDs.
+func CreateWeb3RequestHandlerWithNetwork(manager wallet.IWalletManager, broadcaster *event.EventBroadcaster, priceFeed wallet.PriceFeed, autoApproval config.AutoApprovalPolicy, network *EVMNetwork) messaging.RpcHandler {
 	return func(req messaging.RpcRequest) (messaging.RpcResponse, error) {
 		var params Web3RequestParams
 		if req.Params != nil {
@@ -49,6 +88,20 @@ func CreateWeb3RequestHandler(manager wallet.IWalletManager, broadcaster *event.
 			}
 		}
 
+		if signingMethods[params.Method] && !manager.IsUnlocked() {
+			message := wallet.ErrWalletLocked.Error()
+			if manager.IsFrozen() {
+				message = wallet.ErrWalletFrozen.Error()
+			}
+			return messaging.RpcResponse{
+				ID: req.ID,
+				Error: &messaging.ErrorInfo{
+					Code:    walletLockedCode,
+					Message: message,
+				},
+			}, nil
+		}
+
 		// Handle different Web3 methods
 		switch params.Method {
 		case "eth_requestAccounts":
@@ -58,10 +111,13 @@ func CreateWeb3RequestHandler(manager wallet.IWalletManager, broadcaster *event.
 			return handleGetAccounts(req.ID, manager)
 		
 		case "eth_chainId":
-			return handleGetChainId(req.ID)
+			return handleGetChainId(req.ID, params.Origin, manager, network)
+		
+		case "wallet_switchEthereumChain":
+			return handleSwitchEthereumChain(req.ID, params, manager, network, broadcaster)
 		
 		case "eth_sendTransaction":
-			return handleSendTransaction(req.ID, params, manager, broadcaster)
+			return handleSendTransaction(req.ID, params, manager, broadcaster, priceFeed, autoApproval, network)
 		
 		case "personal_sign":
 			return handlePersonalSign(req.ID, params, manager, broadcaster)
@@ -114,62 +170,148 @@ func handleGetAccounts(id string, manager wallet.IWalletManager) (messaging.RpcR
 }
 
 // handleGetChainId handles eth_chainId requests
-func handleGetChainId(id string) (messaging.RpcResponse, error) {
-	// Return Ethereum mainnet by default (0x1)
-	// TODO: Make this configurable based on user preferences
-	result, _ := json.Marshal("0x1")
+func handleGetChainId(id, origin string, manager wallet.IWalletManager, network *EVMNetwork) (messaging.RpcResponse, error) {
+	_, chainID := activeEVMChain(manager, network, origin)
+	result, _ := json.Marshal(hexChainID(chainID))
 	return messaging.RpcResponse{
 		ID:     id,
 		Result: result,
 	}, nil
 }
 
-// handleSendTransaction handles eth_sendTransaction requests from web pages
-func handleSendTransaction(id string, params Web3RequestParams, manager wallet.IWalletManager, broadcaster *event.EventBroadcaster) (messaging.RpcResponse, error) {
-	// Parse transaction parameters
-	var txParams []TransactionParams
-	paramsBytes, err := json.Marshal(params.Params)
+// handleSwitchEthereumChain handles EIP-3326 wallet_switchEthereumChain
+// requests, whose params are [{"chainId": "0x38"}]. Only the requesting
+// origin moves to the chain; the extension and other dApps stay where they are.
+func handleSwitchEthereumChain(id string, params Web3RequestParams, manager wallet.IWalletManager, network *EVMNetwork, broadcaster *event.EventBroadcaster) (messaging.RpcResponse, error) {
+	chainName, chainID, code, message := switchEthereumChainTarget(params, manager, network)
+	if message != "" {
+		return messaging.RpcResponse{
+			ID: id,
+			Error: &messaging.ErrorInfo{
+				Code:    code,
+				Message: message,
+			},
+		}, nil
+	}
+	if network == nil {
+		// Without a switchable network the current chain is already active
+		return messaging.RpcResponse{ID: id, Result: json.RawMessage("null")}, nil
+	}
+
+	changed, err := network.SwitchOrigin(params.Origin, chainName)
 	if err != nil {
 		return messaging.RpcResponse{
 			ID: id,
 			Error: &messaging.ErrorInfo{
 				Code:    -32602,
-				Message: "Invalid transaction params: " + err.Error(),
+				Message: err.Error(),
 			},
 		}, nil
 	}
-	
-	if err := json.Unmarshal(paramsBytes, &txParams); err != nil {
+	if changed && broadcaster != nil {
+		broadcaster.BroadcastNetworkChanged(chainName, hexChainID(chainID), params.Origin)
+	}
+	return messaging.RpcResponse{ID: id, Result: json.RawMessage("null")}, nil
+}
+
+// switchEthereumChainTarget returns the chain a wallet_switchEthereumChain
+// request asks for, or the EIP-3326 error code and message to refuse it with.
+// Without a network only the current chain is recognized.
+func switchEthereumChainTarget(params Web3RequestParams, manager wallet.IWalletManager, network *EVMNetwork) (string, uint64, int, string) {
+	var requested string
+	if list, ok := params.Params.([]interface{}); ok && len(list) > 0 {
+		if object, ok := list[0].(map[string]interface{}); ok {
+			requested, _ = object["chainId"].(string)
+		}
+	}
+	chainID, err := parseChainID(requested)
+	if requested == "" || err != nil {
+		return "", 0, -32602, "wallet_switchEthereumChain expects [{\"chainId\": \"0x...\"}]"
+	}
+
+	unrecognized := fmt.Sprintf("Unrecognized chain ID %s", hexChainID(chainID))
+	if network == nil {
+		chainName, active := activeEVMChain(manager, nil, params.Origin)
+		if active != chainID {
+			return "", 0, unrecognizedChainCode, unrecognized
+		}
+		return chainName, chainID, 0, ""
+	}
+	chainName, ok := network.ChainForID(chainID)
+	if !ok {
+		return "", 0, unrecognizedChainCode, unrecognized
+	}
+	return chainName, chainID, 0, ""
+}
+
+// activeEVMChain returns the chain and chain ID the dApp at origin is talking to
+func activeEVMChain(manager wallet.IWalletManager, network *EVMNetwork, origin string) (string, uint64) {
+	if network != nil {
+		return network.ActiveFor(origin)
+	}
+	chainName := wallet.DefaultEVMChain(manager.DefaultChain())
+	return chainName, knownEVMChainIDs[config.NetworkModeMainnet][chainName]
+}
+
+// chainIDMismatch explains why an eth_sendTransaction whose chainId is not the
+// chain it would be sent on is refused, or returns "" when it matches or has none
+func chainIDMismatch(txParam TransactionParams, chainName string, chainID uint64) string {
+	if txParam.ChainID == "" {
+		return ""
+	}
+	if requested, err := parseChainID(txParam.ChainID); err == nil && requested == chainID {
+		return ""
+	}
+	return fmt.Sprintf("chainId %s does not match the active chain %s (%s)", txParam.ChainID, chainName, hexChainID(chainID))
+}
+
+// handleSendTransaction handles eth_sendTransaction requests from web pages
+func handleSendTransaction(id string, params Web3RequestParams, manager wallet.IWalletManager, broadcaster *event.EventBroadcaster, priceFeed wallet.PriceFeed, autoApproval config.AutoApprovalPolicy, network *EVMNetwork) (messaging.RpcResponse, error) {
+	// Parse transaction parameters
+	txParam, invalid := firstTransactionParams(params)
+	if invalid != "" {
 		return messaging.RpcResponse{
 			ID: id,
 			Error: &messaging.ErrorInfo{
 				Code:    -32602,
-				Message: "Invalid transaction params format: " + err.Error(),
+				Message: invalid,
 			},
 		}, nil
 	}
-	
-	if len(txParams) == 0 {
+	ctx := context.Background()
+	// eth_sendTransaction is EVM only, so a non-EVM default chain means ethereum
+	chainName, chainID := activeEVMChain(manager, network, params.Origin)
+	if mismatch := chainIDMismatch(txParam, chainName, chainID); mismatch != "" {
 		return messaging.RpcResponse{
 			ID: id,
 			Error: &messaging.ErrorInfo{
 				Code:    -32602,
-				Message: "Missing transaction parameters",
+				Message: mismatch,
 			},
 		}, nil
 	}
-	
-	txParam := txParams[0]
+
+	// Trusted, low-risk transactions skip the pending queue
+	autoApprovalRequest := wallet.AutoApprovalRequest{
+		Chain:  chainName,
+		From:   txParam.From,
+		To:     txParam.To,
+		Value:  txParam.Value,
+		Data:   txParam.Data,
+		Origin: params.Origin,
+	}
+	if rule, ok := wallet.MatchAutoApprovalRule(autoApproval, autoApprovalRequest); ok {
+		return handleAutoApprovedTransaction(ctx, id, autoApprovalRequest, rule.Name, manager, broadcaster)
+	}
 
 	// Create pending transaction
-	ctx := context.Background()
 	pendingTx := &wallet.PendingTransaction{
 		Hash:                      generateTransactionHash(), // Generate temporary hash
-		Chain:                     "ethereum", // Default to Ethereum for eth_sendTransaction
+		Chain:                     chainName,
 		From:                      txParam.From,
 		To:                        txParam.To,
 		Amount:                    txParam.Value,
-		Token:                     "ETH",
+		Token:                     wallet.NativeTokenSymbol(chainName),
 		Type:                      "transfer",
 		Status:                    "pending",
 		Confirmations:             0,
@@ -183,6 +325,9 @@ func handleSendTransaction(id string, params Web3RequestParams, manager wallet.I
 
 	// Add transaction to pending queue
 	if err := manager.AddPendingTransaction(ctx, pendingTx); err != nil {
+		if errors.Is(err, wallet.ErrPendingQueueFull) && broadcaster != nil {
+			broadcaster.BroadcastPendingQueueFull(pendingTx.Chain, pendingTx.From, pendingTx.To, params.Origin, err.Error())
+		}
 		return messaging.RpcResponse{
 			ID: id,
 			Error: &messaging.ErrorInfo{
@@ -194,19 +339,24 @@ func handleSendTransaction(id string, params Web3RequestParams, manager wallet.I
 
 	// Broadcast transaction confirmation needed event to AI Agent
 	if broadcaster != nil {
+		data := map[string]interface{}{
+			"transaction_hash": pendingTx.Hash,
+			"chain":           pendingTx.Chain,
+			"from":            pendingTx.From,
+			"to":              pendingTx.To,
+			"amount":          pendingTx.Amount,
+			"token":           pendingTx.Token,
+			"origin":          params.Origin,
+			"gas_fee":         pendingTx.GasFee,
+			"submitted_at":    pendingTx.SubmittedAt.Format(time.RFC3339),
+		}
+		// Decoded intent, fee and risk sit alongside the raw fields for the overlay
+		for key, value := range transactionDisplayMetadata(ctx, pendingTx.Chain, params.Origin, txParam, manager, priceFeed) {
+			data[key] = value
+		}
 		event := &event.Event{
 			Type: "transaction_confirmation_needed",
-			Data: map[string]interface{}{
-				"transaction_hash": pendingTx.Hash,
-				"chain":           pendingTx.Chain,
-				"from":            pendingTx.From,
-				"to":              pendingTx.To,
-				"amount":          pendingTx.Amount,
-				"token":           pendingTx.Token,
-				"origin":          params.Origin,
-				"gas_fee":         pendingTx.GasFee,
-				"submitted_at":    pendingTx.SubmittedAt.Format(time.RFC3339),
-			},
+			Data: data,
 		}
 		broadcaster.Broadcast(event)
 	}
@@ -219,27 +369,51 @@ func handleSendTransaction(id string, params Web3RequestParams, manager wallet.I
 	}, nil
 }
 
-// handlePersonalSign handles personal_sign requests from web pages
-func handlePersonalSign(id string, params Web3RequestParams, manager wallet.IWalletManager, broadcaster *event.EventBroadcaster) (messaging.RpcResponse, error) {
-	// Parse signing parameters
-	var signParams []interface{}
-	paramsBytes, err := json.Marshal(params.Params)
+// handleAutoApprovedTransaction sends a transaction that matched the
+// auto-approval rule named rule and returns its on-chain hash
+func handleAutoApprovedTransaction(ctx context.Context, id string, req wallet.AutoApprovalRequest, rule string, manager wallet.IWalletManager, broadcaster *event.EventBroadcaster) (messaging.RpcResponse, error) {
+	tx := &wallet.PendingTransaction{
+		Chain:       req.Chain,
+		From:        req.From,
+		To:          req.To,
+		Amount:      req.NativeAmount(),
+		Token:       wallet.NativeTokenSymbol(req.Chain),
+		Type:        "transfer",
+		Status:      "auto_approved",
+		SubmittedAt: time.Now(),
+	}
+	txHash, err := manager.AutoApproveTransaction(ctx, tx, rule, req.Origin)
 	if err != nil {
 		return messaging.RpcResponse{
 			ID: id,
 			Error: &messaging.ErrorInfo{
-				Code:    -32602,
-				Message: "Invalid signing params: " + err.Error(),
+				Code:    -32000,
+				Message: "Failed to send auto-approved transaction: " + err.Error(),
 			},
 		}, nil
 	}
-	
-	if err := json.Unmarshal(paramsBytes, &signParams); err != nil {
+
+	if broadcaster != nil {
+		broadcaster.BroadcastAutoApproved(txHash, tx.Chain, tx.From, tx.To, tx.Amount, tx.Token, req.Origin, rule)
+	}
+
+	result, _ := json.Marshal(txHash)
+	return messaging.RpcResponse{
+		ID:     id,
+		Result: result,
+	}, nil
+}
+
+// handlePersonalSign handles personal_sign requests from web pages
+func handlePersonalSign(id string, params Web3RequestParams, manager wallet.IWalletManager, broadcaster *event.EventBroadcaster) (messaging.RpcResponse, error) {
+	// Parse signing parameters
+	signParams, invalid := signingParams(params)
+	if invalid != "" {
 		return messaging.RpcResponse{
 			ID: id,
 			Error: &messaging.ErrorInfo{
 				Code:    -32602,
-				Message: "Invalid signing params format: " + err.Error(),
+				Message: invalid,
 			},
 		}, nil
 	}
@@ -255,58 +429,18 @@ func handlePersonalSign(id string, params Web3RequestParams, manager wallet.IWal
 	}
 	
 	// Extract message and address
-	var message string
 	var address string
-	
-	// Handle different parameter types for the message
-	switch msg := signParams[0].(type) {
-	case string:
-		message = msg
-	case []byte:
-		message = string(msg)
-	case []interface{}:
-		// Convert byte array to string
-		// Check if this is a map with numeric keys (which is how JS objects are serialized)
-		if len(msg) > 0 {
-			// Regular array of numbers
-			byteArray := make([]byte, len(msg))
-			for i, v := range msg {
-				if val, ok := v.(float64); ok {
-					byteArray[i] = byte(val)
-				}
-			}
-			message = string(byteArray)
-		}
-	case map[string]interface{}:
-		// Handle map representation of byte array
-		// Find the length of the map to determine array size
-		maxIndex := -1
-		for key := range msg {
-			if idx, err := strconv.Atoi(key); err == nil && idx > maxIndex {
-				maxIndex = idx
-			}
-		}
-		
-		if maxIndex >= 0 {
-			byteArray := make([]byte, maxIndex+1)
-			for key, value := range msg {
-				if idx, err := strconv.Atoi(key); err == nil {
-					if num, ok := value.(float64); ok {
-						byteArray[idx] = byte(num)
-					}
-				}
-			}
-			message = string(byteArray)
-		}
-	default:
+	messageBytes, err := signingMessageBytes(signParams[0])
+	if err != nil {
 		return messaging.RpcResponse{
 			ID: id,
 			Error: &messaging.ErrorInfo{
 				Code:    -32602,
-				Message: fmt.Sprintf("Invalid message format: %T", msg),
+				Message: err.Error(),
 			},
 		}, nil
 	}
+	message := string(messageBytes)
 	
 	// Handle address parameter
 	if addr, ok := signParams[1].(string); ok {
@@ -368,24 +502,13 @@ func handleSolanaRequestAccounts(id string, manager wallet.IWalletManager) (mess
 // handleSolanaSignMessage handles signMessage requests from Solana web pages
 func handleSolanaSignMessage(id string, params Web3RequestParams, manager wallet.IWalletManager, broadcaster *event.EventBroadcaster) (messaging.RpcResponse, error) {
 	// Parse signing parameters
-	var signParams []interface{}
-	paramsBytes, err := json.Marshal(params.Params)
-	if err != nil {
-		return messaging.RpcResponse{
-			ID: id,
-			Error: &messaging.ErrorInfo{
-				Code:    -32602,
-				Message: "Invalid signing params: " + err.Error(),
-			},
-		}, nil
-	}
-	
-	if err := json.Unmarshal(paramsBytes, &signParams); err != nil {
+	signParams, invalid := signingParams(params)
+	if invalid != "" {
 		return messaging.RpcResponse{
 			ID: id,
 			Error: &messaging.ErrorInfo{
 				Code:    -32602,
-				Message: "Invalid signing params format: " + err.Error(),
+				Message: invalid,
 			},
 		}, nil
 	}
@@ -401,49 +524,13 @@ func handleSolanaSignMessage(id string, params Web3RequestParams, manager wallet
 	}
 	
 	// Extract message
-	var messageBytes []byte
-	
-	// Handle different parameter types for the message
-	switch msg := signParams[0].(type) {
-	case string:
-		// If it's a string, convert to bytes
-		messageBytes = []byte(msg)
-	case []byte:
-		messageBytes = msg
-	case []interface{}:
-		// Convert array of numbers to byte array
-		messageBytes = make([]byte, len(msg))
-		for i, v := range msg {
-			if val, ok := v.(float64); ok {
-				messageBytes[i] = byte(val)
-			}
-		}
-	case map[string]interface{}:
-		// Handle map representation of byte array
-		// Find the length of the map to determine array size
-		maxIndex := -1
-		for key := range msg {
-			if idx, err := strconv.Atoi(key); err == nil && idx > maxIndex {
-				maxIndex = idx
-			}
-		}
-		
-		if maxIndex >= 0 {
-			messageBytes = make([]byte, maxIndex+1)
-			for key, value := range msg {
-				if idx, err := strconv.Atoi(key); err == nil {
-					if num, ok := value.(float64); ok {
-						messageBytes[idx] = byte(num)
-					}
-				}
-			}
-		}
-	default:
+	messageBytes, err := signingMessageBytes(signParams[0])
+	if err != nil {
 		return messaging.RpcResponse{
 			ID: id,
 			Error: &messaging.ErrorInfo{
 				Code:    -32602,
-				Message: fmt.Sprintf("Invalid message format: %T", msg),
+				Message: err.Error(),
 			},
 		}, nil
 	}
@@ -552,6 +639,78 @@ func handleSolanaSignMessage(id string, params Web3RequestParams, manager wallet
 	}, nil
 }
 
+// firstTransactionParams extracts the transaction of an eth_sendTransaction
+// request, or the reason its params are malformed
+func firstTransactionParams(params Web3RequestParams) (TransactionParams, string) {
+	var txParams []TransactionParams
+	paramsBytes, err := json.Marshal(params.Params)
+	if err != nil {
+		return TransactionParams{}, "Invalid transaction params: " + err.Error()
+	}
+	if err := json.Unmarshal(paramsBytes, &txParams); err != nil {
+		return TransactionParams{}, "Invalid transaction params format: " + err.Error()
+	}
+	if len(txParams) == 0 {
+		return TransactionParams{}, "Missing transaction parameters"
+	}
+	return txParams[0], ""
+}
+
+// signingParams extracts the positional params of a signing request, or the
+// reason they are malformed
+func signingParams(params Web3RequestParams) ([]interface{}, string) {
+	var signParams []interface{}
+	paramsBytes, err := json.Marshal(params.Params)
+	if err != nil {
+		return nil, "Invalid signing params: " + err.Error()
+	}
+	if err := json.Unmarshal(paramsBytes, &signParams); err != nil {
+		return nil, "Invalid signing params format: " + err.Error()
+	}
+	return signParams, ""
+}
+
+// signingMessageBytes decodes the message of a signing request, sent by pages
+// as a string, an array of byte values or an object with numeric keys (how a
+// Uint8Array serializes to JSON)
+func signingMessageBytes(value interface{}) ([]byte, error) {
+	switch msg := value.(type) {
+	case string:
+		return []byte(msg), nil
+	case []byte:
+		return msg, nil
+	case []interface{}:
+		messageBytes := make([]byte, len(msg))
+		for i, v := range msg {
+			if val, ok := v.(float64); ok {
+				messageBytes[i] = byte(val)
+			}
+		}
+		return messageBytes, nil
+	case map[string]interface{}:
+		maxIndex := -1
+		for key := range msg {
+			if idx, err := strconv.Atoi(key); err == nil && idx > maxIndex {
+				maxIndex = idx
+			}
+		}
+		if maxIndex < 0 {
+			return nil, nil
+		}
+		messageBytes := make([]byte, maxIndex+1)
+		for key, value := range msg {
+			if idx, err := strconv.Atoi(key); err == nil {
+				if num, ok := value.(float64); ok {
+					messageBytes[idx] = byte(num)
+				}
+			}
+		}
+		return messageBytes, nil
+	default:
+		return nil, fmt.Errorf("Invalid message format: %T", msg)
+	}
+}
+
 // generateTransactionHash generates a temporary transaction hash for pending transactions
 func generateTransactionHash() string {
 	return fmt.Sprintf("0x%x", time.Now().UnixNano())
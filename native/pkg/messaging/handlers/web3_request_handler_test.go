@@ -0,0 +1,424 @@
+// SPDX-License-Identifier: Apache-2.0
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/event"
+	"github.com/algonius/algonius-wallet/native/pkg/messaging"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type staticPriceFeed map[string]float64
+
+func (f staticPriceFeed) USDPrice(ctx context.Context, symbol string) (float64, error) {
+	return f[symbol], nil
+}
+
+func TestHandleSendTransactionEnrichesOverlayEvent(t *testing.T) {
+	mockWalletManager := &wallet.MockWalletManager{}
+	mockWalletManager.On("IsUnlocked").Return(true)
+	mockWalletManager.On("DefaultChain").Return("ethereum")
+	mockWalletManager.On("AddPendingTransaction", mock.Anything, mock.Anything).Return(nil)
+	mockWalletManager.On("ClassifyRecipient", mock.Anything, "ethereum", "0x2F62f2B4c5fcd7570a709DeC05D68EA19c82A9ec", "").
+		Return(&chain.RecipientClassification{Address: "0x2F62f2B4c5fcd7570a709DeC05D68EA19c82A9ec", Kind: chain.RecipientContract}, nil)
+
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+	handler := CreateWeb3RequestHandlerWithPriceFeed(mockWalletManager, broadcaster, staticPriceFeed{"ETH": 2000})
+
+	params, err := json.Marshal(Web3RequestParams{
+		Method: "eth_sendTransaction",
+		Origin: "https://app.uniswap.org",
+		Params: []TransactionParams{{
+			From:     "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+			To:       "0x2F62f2B4c5fcd7570a709DeC05D68EA19c82A9ec",
+			Value:    "0xde0b6b3a7640000",
+			Gas:      "0x5208",
+			GasPrice: "0x4a817c800",
+		}},
+	})
+	require.NoError(t, err)
+
+	resp, err := handler(messaging.RpcRequest{ID: "1", Params: params})
+	require.NoError(t, err)
+	require.Nil(t, resp.Error)
+
+	evt := <-events
+	assert.Equal(t, "transaction_confirmation_needed", evt.Type)
+	// Raw fields are kept for existing consumers
+	assert.Equal(t, "0xde0b6b3a7640000", evt.Data["amount"])
+	assert.Equal(t, "0x5208", evt.Data["gas_fee"])
+	assert.Equal(t, "https://app.uniswap.org", evt.Data["origin"])
+
+	assert.Equal(t, "Uniswap", evt.Data["dapp_name"])
+	assert.Equal(t, "Send 1 ETH to 0x2F62…A9ec", evt.Data["intent"])
+	assert.Equal(t, wallet.TxCategoryTransfer, evt.Data["action"])
+	assert.Equal(t, "0.00042", evt.Data["estimated_fee"])
+	assert.Equal(t, "0.84", evt.Data["estimated_fee_usd"])
+	assert.Equal(t, wallet.RiskLevelLow, evt.Data["risk_level"])
+	assert.Equal(t, chain.RecipientContract, evt.Data["recipient_type"])
+	assert.NotContains(t, evt.Data, "recipient_warning", "native value to a contract is not flagged")
+}
+
+func TestHandleSendTransactionEstimatesMissingGas(t *testing.T) {
+	mockWalletManager := &wallet.MockWalletManager{}
+	mockWalletManager.On("IsUnlocked").Return(true)
+	mockWalletManager.On("DefaultChain").Return("ethereum")
+	mockWalletManager.On("AddPendingTransaction", mock.Anything, mock.Anything).Return(nil)
+	mockWalletManager.On("EstimateGas", mock.Anything, "ethereum", mock.Anything, mock.Anything, mock.Anything, "").Return(uint64(21000), "30", nil)
+	mockWalletManager.On("ClassifyRecipient", mock.Anything, "ethereum", mock.Anything, "").Return(nil, assert.AnError)
+
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+	handler := CreateWeb3RequestHandler(mockWalletManager, broadcaster)
+
+	params, err := json.Marshal(Web3RequestParams{
+		Method: "eth_sendTransaction",
+		Params: []TransactionParams{{
+			From:  "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+			To:    "0x2F62f2B4c5fcd7570a709DeC05D68EA19c82A9ec",
+			Value: "0x1",
+		}},
+	})
+	require.NoError(t, err)
+
+	_, err = handler(messaging.RpcRequest{ID: "1", Params: params})
+	require.NoError(t, err)
+
+	evt := <-events
+	assert.Equal(t, "0.00063", evt.Data["estimated_fee"])
+	assert.NotContains(t, evt.Data, "estimated_fee_usd", "no price feed configured")
+	assert.Equal(t, "", evt.Data["dapp_name"])
+}
+
+func TestHandleSendTransactionAutoApproval(t *testing.T) {
+	policy := config.AutoApprovalPolicy{
+		Enabled: true,
+		Rules: []config.AutoApprovalRule{{
+			Name:     "uniswap-small",
+			MaxValue: 0.1,
+			Origins:  []string{"https://app.uniswap.org"},
+			Methods:  []string{"transfer"},
+		}},
+	}
+	request := func(origin, value, data string) []byte {
+		params, err := json.Marshal(Web3RequestParams{
+			Method: "eth_sendTransaction",
+			Origin: origin,
+			Params: []TransactionParams{{
+				From:     "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+				To:       "0x2F62f2B4c5fcd7570a709DeC05D68EA19c82A9ec",
+				Value:    value,
+				Data:     data,
+				Gas:      "0x5208",
+				GasPrice: "0x4a817c800",
+			}},
+		})
+		require.NoError(t, err)
+		return params
+	}
+
+	t.Run("matching transaction is sent directly", func(t *testing.T) {
+		mockWalletManager := &wallet.MockWalletManager{}
+		mockWalletManager.On("IsUnlocked").Return(true)
+		mockWalletManager.On("DefaultChain").Return("ethereum")
+		var sent *wallet.PendingTransaction
+		mockWalletManager.On("AutoApproveTransaction", mock.Anything, mock.Anything, "uniswap-small", "https://app.uniswap.org/#/swap").
+			Run(func(args mock.Arguments) { sent = args.Get(1).(*wallet.PendingTransaction) }).
+			Return("0xsent", nil)
+
+		broadcaster := event.NewEventBroadcaster(zap.NewNop())
+		events := broadcaster.Subscribe("test")
+		handler := CreateWeb3RequestHandlerWithPolicy(mockWalletManager, broadcaster, nil, policy)
+
+		resp, err := handler(messaging.RpcRequest{ID: "1", Params: request("https://app.uniswap.org/#/swap", "0x2386f26fc10000", "")})
+		require.NoError(t, err)
+		require.Nil(t, resp.Error)
+		assert.JSONEq(t, `"0xsent"`, string(resp.Result))
+
+		// Exactly the requested value transfer is sent
+		require.NotNil(t, sent)
+		assert.Equal(t, "ethereum", sent.Chain)
+		assert.Equal(t, "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8", sent.From)
+		assert.Equal(t, "0x2F62f2B4c5fcd7570a709DeC05D68EA19c82A9ec", sent.To)
+		assert.Equal(t, "0.01", sent.Amount)
+		assert.Equal(t, "ETH", sent.Token)
+		assert.Equal(t, "transfer", sent.Type)
+
+		evt := <-events
+		assert.Equal(t, event.EventTypeAutoApproved, evt.Type)
+		assert.Equal(t, "uniswap-small", evt.Data["rule"])
+		assert.Equal(t, "0xsent", evt.Data["transaction_hash"])
+		mockWalletManager.AssertNotCalled(t, "AddPendingTransaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("non-matching transaction still needs approval", func(t *testing.T) {
+		mockWalletManager := &wallet.MockWalletManager{}
+		mockWalletManager.On("IsUnlocked").Return(true)
+		mockWalletManager.On("DefaultChain").Return("ethereum")
+		mockWalletManager.On("AddPendingTransaction", mock.Anything, mock.Anything).Return(nil)
+		mockWalletManager.On("ClassifyRecipient", mock.Anything, "ethereum", mock.Anything, "").
+			Return(&chain.RecipientClassification{Kind: chain.RecipientEOA}, nil)
+
+		broadcaster := event.NewEventBroadcaster(zap.NewNop())
+		events := broadcaster.Subscribe("test")
+		handler := CreateWeb3RequestHandlerWithPolicy(mockWalletManager, broadcaster, nil, policy)
+
+		// 1 ETH is above the rule's max_value
+		resp, err := handler(messaging.RpcRequest{ID: "1", Params: request("https://app.uniswap.org", "0xde0b6b3a7640000", "")})
+		require.NoError(t, err)
+		require.Nil(t, resp.Error)
+
+		evt := <-events
+		assert.Equal(t, "transaction_confirmation_needed", evt.Type)
+		mockWalletManager.AssertNotCalled(t, "AutoApproveTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("transaction with calldata still needs approval", func(t *testing.T) {
+		mockWalletManager := &wallet.MockWalletManager{}
+		mockWalletManager.On("IsUnlocked").Return(true)
+		mockWalletManager.On("DefaultChain").Return("ethereum")
+		mockWalletManager.On("AddPendingTransaction", mock.Anything, mock.Anything).Return(nil)
+		mockWalletManager.On("ClassifyRecipient", mock.Anything, "ethereum", mock.Anything, mock.Anything).
+			Return(&chain.RecipientClassification{Kind: chain.RecipientContract}, nil)
+
+		broadcaster := event.NewEventBroadcaster(zap.NewNop())
+		events := broadcaster.Subscribe("test")
+		// A rule that does not restrict methods
+		anyMethod := config.AutoApprovalPolicy{Enabled: true, Rules: []config.AutoApprovalRule{{
+			Name:     "uniswap-small",
+			MaxValue: 0.1,
+			Origins:  []string{"https://app.uniswap.org"},
+		}}}
+		handler := CreateWeb3RequestHandlerWithPolicy(mockWalletManager, broadcaster, nil, anyMethod)
+
+		// A token transfer within max_value from a trusted origin: sending it
+		// as a value transfer would drop its calldata
+		resp, err := handler(messaging.RpcRequest{ID: "1", Params: request("https://app.uniswap.org", "0x2386f26fc10000", "0xa9059cbb0000000000000000000000002f62f2b4c5fcd7570a709dec05d68ea19c82a9ec0000000000000000000000000000000000000000000000000000000000000001")})
+		require.NoError(t, err)
+		require.Nil(t, resp.Error)
+
+		evt := <-events
+		assert.Equal(t, "transaction_confirmation_needed", evt.Type)
+		mockWalletManager.AssertNotCalled(t, "AutoApproveTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestHandleSendTransactionUsesDefaultChain(t *testing.T) {
+	send := func(defaultChain string) *wallet.PendingTransaction {
+		var queued *wallet.PendingTransaction
+		mockWalletManager := &wallet.MockWalletManager{}
+		mockWalletManager.On("IsUnlocked").Return(true)
+		mockWalletManager.On("DefaultChain").Return(defaultChain)
+		mockWalletManager.On("AddPendingTransaction", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			queued = args.Get(1).(*wallet.PendingTransaction)
+		}).Return(nil)
+
+		params, err := json.Marshal(Web3RequestParams{
+			Method: "eth_sendTransaction",
+			Params: []TransactionParams{{
+				From:  "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+				To:    "0x2F62f2B4c5fcd7570a709DeC05D68EA19c82A9ec",
+				Value: "0x1",
+			}},
+		})
+		require.NoError(t, err)
+
+		resp, err := CreateWeb3RequestHandler(mockWalletManager, nil)(messaging.RpcRequest{ID: "1", Params: params})
+		require.NoError(t, err)
+		require.Nil(t, resp.Error)
+		require.NotNil(t, queued)
+		return queued
+	}
+
+	tx := send("bsc")
+	assert.Equal(t, "bsc", tx.Chain)
+	assert.Equal(t, "BNB", tx.Token)
+
+	// A non-EVM default cannot serve eth_sendTransaction
+	tx = send("solana")
+	assert.Equal(t, "ethereum", tx.Chain)
+	assert.Equal(t, "ETH", tx.Token)
+}
+
+func TestDappName(t *testing.T) {
+	assert.Equal(t, "PancakeSwap", dappName("https://pancakeswap.finance"))
+	assert.Equal(t, "OpenSea", dappName("https://www.opensea.io/collection/x"))
+	assert.Equal(t, "example.com", dappName("https://www.example.com"))
+	assert.Equal(t, "notuniswap.org", dappName("https://notuniswap.org"))
+}
+
+func TestWeb3SigningMethodsRequireUnlockedWallet(t *testing.T) {
+	mockWalletManager := &wallet.MockWalletManager{}
+	mockWalletManager.On("IsUnlocked").Return(false)
+	mockWalletManager.On("IsFrozen").Return(false)
+	handler := CreateWeb3RequestHandler(mockWalletManager, nil)
+
+	requests := map[string]interface{}{
+		"eth_sendTransaction": []TransactionParams{{
+			From: "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+			To:   "0x2F62f2B4c5fcd7570a709DeC05D68EA19c82A9ec",
+		}},
+		"personal_sign": []interface{}{"hello", "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"},
+		"signMessage":   []interface{}{"hello"},
+	}
+	for method, methodParams := range requests {
+		params, err := json.Marshal(Web3RequestParams{Method: method, Params: methodParams})
+		require.NoError(t, err)
+
+		resp, err := handler(messaging.RpcRequest{ID: "1", Params: params})
+		require.NoError(t, err, method)
+		require.NotNil(t, resp.Error, method)
+		assert.Equal(t, walletLockedCode, resp.Error.Code, method)
+		assert.Equal(t, "wallet is locked", resp.Error.Message, method)
+	}
+
+	mockWalletManager.AssertNotCalled(t, "AddPendingTransaction", mock.Anything, mock.Anything)
+	mockWalletManager.AssertNotCalled(t, "SignMessage", mock.Anything, mock.Anything, mock.Anything)
+	mockWalletManager.AssertNotCalled(t, "GetAccounts", mock.Anything)
+}
+
+func TestWeb3ChainIdFollowsActiveNetwork(t *testing.T) {
+	chainID := func(cfg *config.Config) string {
+		mockWalletManager := &wallet.MockWalletManager{}
+		mockWalletManager.On("IsUnlocked").Return(true)
+		handler := CreateWeb3RequestHandlerWithNetwork(mockWalletManager, nil, nil, config.AutoApprovalPolicy{}, NewEVMNetwork(cfg.Wallet, cfg.Chains))
+
+		params, err := json.Marshal(Web3RequestParams{Method: "eth_chainId"})
+		require.NoError(t, err)
+		resp, err := handler(messaging.RpcRequest{ID: "1", Params: params})
+		require.NoError(t, err)
+		require.Nil(t, resp.Error)
+		var result string
+		require.NoError(t, json.Unmarshal(resp.Result, &result))
+		return result
+	}
+
+	cfg := config.DefaultConfig()
+	assert.Equal(t, "0x1", chainID(cfg), "ethereum mainnet")
+
+	cfg.Wallet.DefaultChain = "bsc"
+	assert.Equal(t, "0x38", chainID(cfg), "bsc mainnet")
+
+	// Without a configured chain_id the network mode picks the testnet
+	cfg.Wallet.NetworkMode = config.NetworkModeTestnet
+	cfg.Chains.BSC.ChainID = 0
+	assert.Equal(t, "0x61", chainID(cfg), "bsc testnet")
+
+	// Without a network the wallet's default chain is reported
+	mockWalletManager := &wallet.MockWalletManager{}
+	mockWalletManager.On("IsUnlocked").Return(true)
+	mockWalletManager.On("DefaultChain").Return("bsc")
+	params, err := json.Marshal(Web3RequestParams{Method: "eth_chainId"})
+	require.NoError(t, err)
+	resp, err := CreateWeb3RequestHandler(mockWalletManager, nil)(messaging.RpcRequest{ID: "1", Params: params})
+	require.NoError(t, err)
+	assert.JSONEq(t, `"0x38"`, string(resp.Result))
+}
+
+func TestWeb3SwitchEthereumChain(t *testing.T) {
+	cfg := config.DefaultConfig()
+	network := NewEVMNetwork(cfg.Wallet, cfg.Chains)
+	mockWalletManager := &wallet.MockWalletManager{}
+	mockWalletManager.On("IsUnlocked").Return(true)
+	mockWalletManager.On("AddPendingTransaction", mock.Anything, mock.Anything).Return(nil)
+	mockWalletManager.On("ClassifyRecipient", mock.Anything, "bsc", mock.Anything, "").Return(nil, assert.AnError)
+
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+	handler := CreateWeb3RequestHandlerWithNetwork(mockWalletManager, broadcaster, nil, config.AutoApprovalPolicy{}, network)
+	request := func(method string, methodParams interface{}) messaging.RpcResponse {
+		params, err := json.Marshal(Web3RequestParams{Method: method, Params: methodParams, Origin: "https://pancakeswap.finance"})
+		require.NoError(t, err)
+		resp, err := handler(messaging.RpcRequest{ID: "1", Params: params})
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := request("wallet_switchEthereumChain", []interface{}{map[string]string{"chainId": "0x38"}})
+	require.Nil(t, resp.Error)
+	assert.Equal(t, "null", string(resp.Result))
+
+	evt := <-events
+	assert.Equal(t, event.EventTypeNetworkChanged, evt.Type)
+	assert.Equal(t, "bsc", evt.Data["chain"])
+	assert.Equal(t, "0x38", evt.Data["chain_id"])
+	assert.Equal(t, "https://pancakeswap.finance", evt.Data["source"])
+
+	resp = request("eth_chainId", nil)
+	assert.JSONEq(t, `"0x38"`, string(resp.Result))
+
+	// Transactions follow the switched chain
+	resp = request("eth_sendTransaction", []TransactionParams{{
+		From:     "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+		To:       "0x2F62f2B4c5fcd7570a709DeC05D68EA19c82A9ec",
+		Gas:      "0x5208",
+		GasPrice: "0x4a817c800",
+	}})
+	require.Nil(t, resp.Error)
+	mockWalletManager.AssertCalled(t, "AddPendingTransaction", mock.Anything, mock.MatchedBy(func(tx *wallet.PendingTransaction) bool {
+		return tx.Chain == "bsc"
+	}))
+
+	// Polygon is not served, so EIP-3326 asks the dApp to add it first
+	resp = request("wallet_switchEthereumChain", []interface{}{map[string]string{"chainId": "0x89"}})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, unrecognizedChainCode, resp.Error.Code)
+	assert.Equal(t, "Unrecognized chain ID 0x89", resp.Error.Message)
+	chainName, _ := network.ActiveFor("https://pancakeswap.finance")
+	assert.Equal(t, "bsc", chainName)
+
+	resp = request("wallet_switchEthereumChain", []interface{}{map[string]string{}})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, -32602, resp.Error.Code)
+
+	// Only the dApp that switched moved; the extension and other dApps did not
+	chainName, _ = network.Active()
+	assert.Equal(t, "ethereum", chainName)
+	params, err := json.Marshal(Web3RequestParams{Method: "eth_chainId", Origin: "https://app.uniswap.org"})
+	require.NoError(t, err)
+	resp, err = handler(messaging.RpcRequest{ID: "1", Params: params})
+	require.NoError(t, err)
+	assert.JSONEq(t, `"0x1"`, string(resp.Result))
+
+	// A request without an origin cannot switch anything
+	params, err = json.Marshal(Web3RequestParams{Method: "wallet_switchEthereumChain", Params: []interface{}{map[string]string{"chainId": "0x38"}}})
+	require.NoError(t, err)
+	resp, err = handler(messaging.RpcRequest{ID: "1", Params: params})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, -32602, resp.Error.Code)
+	chainName, _ = network.ActiveFor("")
+	assert.Equal(t, "ethereum", chainName)
+}
+
+func TestWeb3SendTransactionRejectsOtherChainID(t *testing.T) {
+	cfg := config.DefaultConfig()
+	network := NewEVMNetwork(cfg.Wallet, cfg.Chains)
+	mockWalletManager := &wallet.MockWalletManager{}
+	mockWalletManager.On("IsUnlocked").Return(true)
+	handler := CreateWeb3RequestHandlerWithNetwork(mockWalletManager, nil, nil, config.AutoApprovalPolicy{}, network)
+
+	params, err := json.Marshal(Web3RequestParams{Method: "eth_sendTransaction", Origin: "https://pancakeswap.finance", Params: []TransactionParams{{
+		From:    "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8",
+		To:      "0x2F62f2B4c5fcd7570a709DeC05D68EA19c82A9ec",
+		Value:   "0x1",
+		ChainID: "0x38",
+	}}})
+	require.NoError(t, err)
+	resp, err := handler(messaging.RpcRequest{ID: "1", Params: params})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, -32602, resp.Error.Code)
+	assert.Equal(t, "chainId 0x38 does not match the active chain ethereum (0x1)", resp.Error.Message)
+	mockWalletManager.AssertNotCalled(t, "AddPendingTransaction", mock.Anything, mock.Anything)
+}
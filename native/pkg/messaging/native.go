@@ -4,17 +4,37 @@ package messaging
 import (
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"sync"
 	"time"
 
+	"github.com/algonius/algonius-wallet/native/pkg/event"
 	"github.com/algonius/algonius-wallet/native/pkg/logger"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// Message size limits of Chrome Native Messaging
+const (
+	// MaxMessageSize is the largest message Chrome sends to a native host;
+	// longer length prefixes can only come from a corrupted stream
+	MaxMessageSize = 64 << 20
+	// DefaultMaxInboundMessageSize is the inbound limit when none is configured
+	DefaultMaxInboundMessageSize = 1 << 20
+	// MaxOutboundMessageSize is the largest message Chrome accepts from a native host
+	MaxOutboundMessageSize = 1 << 20
+)
+
+var (
+	// ErrDisconnected is returned when sending after the extension closed stdin
+	ErrDisconnected = errors.New("native messaging: browser extension disconnected")
+	// ErrMessageTooLarge is returned when sending a message Chrome would refuse
+	ErrMessageTooLarge = errors.New("native messaging: message too large")
+)
+
 // NativeMessaging implements Chrome native messaging protocol.
 type NativeMessaging struct {
 	logger          logger.Logger
@@ -25,6 +45,13 @@ type NativeMessaging struct {
 	rpcHandlers     map[string]RpcHandler
 	pendingRequests map[string]*pendingRequest
 	mutex           sync.Mutex
+	logPayloads     bool
+	broadcaster     *event.EventBroadcaster
+	disconnected    bool
+	maxInboundSize  int
+	// skipRemaining counts the bytes of a rejected oversized message that
+	// have not arrived yet and are dropped as they do
+	skipRemaining int
 }
 
 type pendingRequest struct {
@@ -39,6 +66,13 @@ type NativeMessagingConfig struct {
 	Logger logger.Logger
 	Stdin  io.Reader
 	Stdout io.Writer
+	// LogPayloads logs every message payload, with sensitive fields redacted
+	LogPayloads bool
+	// Broadcaster receives native_messaging_error events for unreadable input (optional)
+	Broadcaster *event.EventBroadcaster
+	// MaxInboundMessageSize rejects longer messages from the extension without
+	// buffering them. 0 uses DefaultMaxInboundMessageSize; it is capped at MaxMessageSize.
+	MaxInboundMessageSize int
 }
 
 // NewNativeMessaging creates a new NativeMessaging instance.
@@ -54,6 +88,11 @@ func NewNativeMessaging(config NativeMessagingConfig) (*NativeMessaging, error)
 	if stdout == nil {
 		stdout = os.Stdout
 	}
+	maxInboundSize := config.MaxInboundMessageSize
+	if maxInboundSize <= 0 {
+		maxInboundSize = DefaultMaxInboundMessageSize
+	}
+	maxInboundSize = min(maxInboundSize, MaxMessageSize)
 	nm := &NativeMessaging{
 		logger:          config.Logger,
 		stdin:           stdin,
@@ -62,39 +101,57 @@ func NewNativeMessaging(config NativeMessagingConfig) (*NativeMessaging, error)
 		messageHandlers: make(map[string]MessageHandler),
 		rpcHandlers:     make(map[string]RpcHandler),
 		pendingRequests: make(map[string]*pendingRequest),
+		logPayloads:     config.LogPayloads,
+		broadcaster:     config.Broadcaster,
+		maxInboundSize:  maxInboundSize,
 	}
 	nm.registerRpcResponseHandler()
 	return nm, nil
 }
 
-// Start begins processing messages from stdin.
+// Start begins processing messages from stdin. When the extension closes
+// stdin the host keeps running; only sends to the extension fail afterwards.
 func (nm *NativeMessaging) Start() error {
 	nm.logger.Info("Starting native messaging processing")
 	go func() {
 		buffer := make([]byte, 4096)
 		for {
 			n, err := nm.stdin.Read(buffer)
-			if err != nil {
-				if err == io.EOF {
-					nm.logger.Info("Native messaging: stdin closed")
-					return
-				}
-				nm.logger.Error("Error reading from stdin", zap.Error(err))
-				return
-			}
 			if n > 0 {
 				nm.buffer = append(nm.buffer, buffer[:n]...)
 				nm.processBuffer()
 			}
+			if err != nil {
+				nm.disconnect(err)
+				return
+			}
 		}
 	}()
 	return nil
 }
 
-// processBuffer processes the buffer for messages.
+// processBuffer processes the buffer for messages. Input that cannot be a
+// frame is skipped up to the next plausible frame instead of stalling the stream.
 func (nm *NativeMessaging) processBuffer() {
+	if nm.skipRemaining > 0 {
+		dropped := min(nm.skipRemaining, len(nm.buffer))
+		nm.buffer = nm.buffer[dropped:]
+		nm.skipRemaining -= dropped
+	}
 	for len(nm.buffer) >= 4 {
 		messageLength := binary.LittleEndian.Uint32(nm.buffer[:4])
+		if !validFrameLength(messageLength) {
+			nm.framingError(fmt.Sprintf("invalid message length %d", messageLength), nm.resync())
+			continue
+		}
+		if len(nm.buffer) > 4 && nm.buffer[4] != '{' {
+			nm.framingError("message does not start with a JSON object", nm.resync())
+			continue
+		}
+		if int(messageLength) > nm.maxInboundSize {
+			nm.rejectOversized(int(messageLength))
+			continue
+		}
 		if uint32(len(nm.buffer)) < messageLength+4 {
 			return
 		}
@@ -103,20 +160,111 @@ func (nm *NativeMessaging) processBuffer() {
 
 		var message Message
 		if err := json.Unmarshal(messageJSON, &message); err != nil {
-			nm.logger.Error("Error parsing message JSON", zap.Error(err), zap.String("json", string(messageJSON)))
+			nm.logger.Error("Error parsing message JSON", zap.Error(err), zap.String("json", logger.Redact(messageJSON)))
+			nm.broadcastError("malformed message JSON: "+err.Error(), int(messageLength)+4)
 			continue
 		}
 		go func(msg Message) {
 			if err := nm.handleMessage(msg); err != nil {
-				nm.logger.Error("Error handling message", zap.Error(err), zap.Any("message", msg))
+				nm.logger.Error("Error handling message", zap.Error(err), zap.String("message", logger.Redact(msg)))
 			}
 		}(message)
 	}
 }
 
+// rejectOversized drops a message longer than the inbound limit, including the
+// part that has not arrived yet, and tells the extension it was refused
+func (nm *NativeMessaging) rejectOversized(length int) {
+	frameLength := length + 4
+	dropped := min(frameLength, len(nm.buffer))
+	nm.buffer = nm.buffer[dropped:]
+	nm.skipRemaining = frameLength - dropped
+
+	reason := fmt.Sprintf("message of %d bytes exceeds the %d byte limit", length, nm.maxInboundSize)
+	nm.logger.Warn("Rejecting oversized native message",
+		zap.Int("length", length),
+		zap.Int("limit", nm.maxInboundSize))
+	nm.broadcastError(reason, frameLength)
+	go func() {
+		if err := nm.SendMessage(Message{
+			Type:  "error",
+			Error: &ErrorInfo{Code: -32600, Message: "Invalid request: " + reason},
+		}); err != nil {
+			nm.logger.Error("Error reporting oversized message", zap.Error(err))
+		}
+	}()
+}
+
+// validFrameLength reports whether a length prefix can start a message
+func validFrameLength(length uint32) bool {
+	return length > 0 && length <= MaxMessageSize
+}
+
+// resync drops input up to the next offset holding a valid length prefix
+// followed by '{' and returns how many bytes it dropped. Without one, all but
+// the last four bytes, which may begin the next prefix, are dropped. At least
+// one byte is always dropped so processing makes progress.
+func (nm *NativeMessaging) resync() int {
+	for i := 5; i < len(nm.buffer); i++ {
+		if nm.buffer[i] == '{' && validFrameLength(binary.LittleEndian.Uint32(nm.buffer[i-4:i])) {
+			nm.buffer = nm.buffer[i-4:]
+			return i - 4
+		}
+	}
+	dropped := max(1, len(nm.buffer)-4)
+	nm.buffer = nm.buffer[dropped:]
+	return dropped
+}
+
+// framingError reports input that was discarded to resynchronize the stream
+func (nm *NativeMessaging) framingError(reason string, discarded int) {
+	nm.logger.Warn("Native messaging framing error, skipping to the next message",
+		zap.String("reason", reason),
+		zap.Int("discarded_bytes", discarded))
+	nm.broadcastError(reason, discarded)
+}
+
+func (nm *NativeMessaging) broadcastError(reason string, discarded int) {
+	if nm.broadcaster != nil {
+		nm.broadcaster.BroadcastNativeMessagingError(reason, discarded)
+	}
+}
+
+// disconnect handles the end of stdin: a partial message left in the buffer is
+// reported, further sends fail with ErrDisconnected and requests waiting for
+// the extension are failed instead of left to time out
+func (nm *NativeMessaging) disconnect(err error) {
+	if errors.Is(err, io.EOF) {
+		nm.logger.Info("Native messaging: stdin closed, browser extension disconnected")
+	} else {
+		nm.logger.Error("Error reading from stdin, treating the browser extension as disconnected", zap.Error(err))
+	}
+	if nm.skipRemaining > 0 {
+		nm.skipRemaining = 0
+	} else if len(nm.buffer) > 0 {
+		reason := fmt.Sprintf("stream closed after %d bytes of an incomplete message", len(nm.buffer))
+		nm.framingError(reason, len(nm.buffer))
+		nm.buffer = nm.buffer[:0]
+	}
+
+	nm.mutex.Lock()
+	defer nm.mutex.Unlock()
+	nm.disconnected = true
+	for id, pending := range nm.pendingRequests {
+		pending.timer.Stop()
+		pending.err = ErrDisconnected
+		close(pending.done)
+		delete(nm.pendingRequests, id)
+	}
+}
+
 // handleMessage processes a received message.
 func (nm *NativeMessaging) handleMessage(message Message) error {
-	nm.logger.Info("Received message", zap.Any("message", message))
+	nm.logger.Info("Received message",
+		zap.String("type", message.Type),
+		zap.String("id", message.ID),
+		zap.String("method", message.Method))
+	nm.logPayload("Received message payload", message)
 	handler, ok := nm.messageHandlers[message.Type]
 	if !ok {
 		nm.logger.Warn("No handler registered for message type", zap.String("type", message.Type))
@@ -145,6 +293,15 @@ func (nm *NativeMessaging) handleMessage(message Message) error {
 	return handler(data)
 }
 
+// logPayload logs a message with sensitive fields redacted when payload
+// logging is enabled
+func (nm *NativeMessaging) logPayload(msg string, message Message) {
+	if !nm.logPayloads {
+		return
+	}
+	nm.logger.Info(msg, zap.String("payload", logger.Redact(message)))
+}
+
 // RegisterHandler registers a handler for a specific message type.
 func (nm *NativeMessaging) RegisterHandler(messageType string, handler MessageHandler) {
 	nm.logger.Debug("Registering handler for message type", zap.String("type", messageType))
@@ -162,17 +319,25 @@ func (nm *NativeMessaging) RegisterRpcMethod(method string, handler RpcHandler)
 
 // SendMessage sends a message to stdout.
 func (nm *NativeMessaging) SendMessage(message Message) error {
-	nm.logger.Debug("Sending message", zap.Any("message", message))
+	nm.logger.Debug("Sending message", zap.String("type", message.Type), zap.String("id", message.ID))
+	nm.logPayload("Sending message payload", message)
 	messageJSON, err := json.Marshal(message)
 	if err != nil {
 		return fmt.Errorf("error marshaling message: %w", err)
 	}
+	if len(messageJSON) > MaxOutboundMessageSize {
+		return fmt.Errorf("%w: %s message of %d bytes exceeds the %d byte limit", ErrMessageTooLarge, message.Type, len(messageJSON), MaxOutboundMessageSize)
+	}
 	messageLength := uint32(len(messageJSON))
 	buffer := make([]byte, 4+messageLength)
 	binary.LittleEndian.PutUint32(buffer, messageLength)
 	copy(buffer[4:], messageJSON)
 	nm.mutex.Lock()
 	defer nm.mutex.Unlock()
+	// Writing to the closed pipe would raise SIGPIPE and end the whole host
+	if nm.disconnected {
+		return ErrDisconnected
+	}
 	_, err = nm.stdout.Write(buffer)
 	if err != nil {
 		return fmt.Errorf("error writing message: %w", err)
@@ -220,7 +385,7 @@ func (nm *NativeMessaging) RpcRequest(request RpcRequest, options RpcOptions) (R
 		return RpcResponse{}, err
 	}
 	<-pending.done
-	nm.logger.Info("RPC request Done", zap.Any("response", pending.response), zap.Any("err", pending.err))
+	nm.logger.Info("RPC request Done", zap.String("method", request.Method), zap.String("id", id), zap.Any("err", pending.err))
 	return pending.response, pending.err
 }
 
@@ -304,11 +469,27 @@ func (nm *NativeMessaging) registerRpcRequestHandler() {
 				zap.String("error_message", response.Error.Message))
 		}
 		
-		return nm.SendMessage(Message{
+		err = nm.SendMessage(Message{
 			Type:   "rpc_response",
 			ID:     response.ID,
 			Result: response.Result,
 			Error:  response.Error,
 		})
+		if errors.Is(err, ErrMessageTooLarge) {
+			// Answer anyway so the extension is not left waiting for the result
+			nm.logger.Error("RPC response too large for native messaging",
+				zap.Error(err),
+				zap.String("method", method),
+				zap.String("request_id", response.ID))
+			return nm.SendMessage(Message{
+				Type: "rpc_response",
+				ID:   response.ID,
+				Error: &ErrorInfo{
+					Code:    -32000,
+					Message: fmt.Sprintf("Server error: %s", err.Error()),
+				},
+			})
+		}
+		return err
 	})
 }
@@ -6,11 +6,17 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/algonius/algonius-wallet/native/pkg/event"
 	"github.com/algonius/algonius-wallet/native/pkg/logger"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestNewNativeMessaging_ValidConfig(t *testing.T) {
@@ -238,3 +244,181 @@ type brokenWriter struct{}
 func (w *brokenWriter) Write(p []byte) (int, error) {
 	return 0, errors.New("write error")
 }
+
+func TestPayloadLoggingNeverLogsMnemonic(t *testing.T) {
+	const mnemonic = "legal winner thank year wave sausage worth useful legal winner thank yellow"
+	core, logs := observer.New(zapcore.DebugLevel)
+	var out bytes.Buffer
+	nm, err := NewNativeMessaging(NativeMessagingConfig{
+		Logger:      logger.NewLoggerFromZap(zap.New(core)),
+		Stdout:      &out,
+		LogPayloads: true,
+	})
+	assert.NoError(t, err)
+
+	nm.RegisterRpcMethod("import_wallet", func(request RpcRequest) (RpcResponse, error) {
+		result, _ := json.Marshal(map[string]string{"address": "0xabc", "mnemonic": mnemonic})
+		return RpcResponse{Result: result}, nil
+	})
+
+	params, _ := json.Marshal(map[string]string{"mnemonic": mnemonic, "password": "hunter22", "chain": "ethereum"})
+	assert.NoError(t, nm.handleMessage(Message{Type: "rpc_request", ID: "1", Method: "import_wallet", Params: params}))
+	assert.Contains(t, out.String(), mnemonic, "the response itself is not redacted")
+
+	assert.NotZero(t, logs.FilterMessage("Received message payload").Len())
+	assert.NotZero(t, logs.FilterMessage("Sending message payload").Len())
+	for _, entry := range logs.All() {
+		logged, _ := json.Marshal(entry.ContextMap())
+		assert.NotContains(t, entry.Message+string(logged), "legal winner")
+		assert.NotContains(t, string(logged), "hunter22")
+	}
+}
+
+func frame(payload string) []byte {
+	buf := make([]byte, 4+len(payload))
+	binary.LittleEndian.PutUint32(buf, uint32(len(payload)))
+	copy(buf[4:], payload)
+	return buf
+}
+
+// startFramingTest starts nm reading input and returns the messages of type
+// "test" it handles and the events it broadcasts
+func startFramingTest(t *testing.T, input io.Reader) (*NativeMessaging, chan string, chan *event.Event) {
+	t.Helper()
+	return startFramingTestWithConfig(t, NativeMessagingConfig{Stdin: input, Stdout: io.Discard})
+}
+
+func startFramingTestWithConfig(t *testing.T, config NativeMessagingConfig) (*NativeMessaging, chan string, chan *event.Event) {
+	t.Helper()
+	broadcaster := event.NewEventBroadcaster(zap.NewNop())
+	events := broadcaster.Subscribe("test")
+	config.Logger = logger.NewMockLogger()
+	config.Broadcaster = broadcaster
+	nm, err := NewNativeMessaging(config)
+	assert.NoError(t, err)
+	received := make(chan string, 10)
+	nm.RegisterHandler("test", func(data interface{}) error {
+		var value string
+		_ = json.Unmarshal(data.(json.RawMessage), &value)
+		received <- value
+		return nil
+	})
+	assert.NoError(t, nm.Start())
+	return nm, received, events
+}
+
+func receive[T any](t *testing.T, ch chan T) T {
+	t.Helper()
+	select {
+	case value := <-ch:
+		return value
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting")
+		var zero T
+		return zero
+	}
+}
+
+func TestStartSkipsOversizedFrame(t *testing.T) {
+	input := new(bytes.Buffer)
+	oversized := make([]byte, 4)
+	binary.LittleEndian.PutUint32(oversized, MaxMessageSize+1)
+	input.Write(oversized)
+	input.WriteString("garbage")
+	input.Write(frame(`{"type":"test","data":"after"}`))
+
+	_, received, events := startFramingTest(t, input)
+	assert.Equal(t, "after", receive(t, received))
+	errEvent := receive(t, events)
+	assert.Equal(t, event.EventTypeNativeMessagingError, errEvent.Type)
+	assert.Contains(t, errEvent.Data["reason"], "invalid message length")
+	assert.Equal(t, 11, errEvent.Data["discarded_bytes"])
+}
+
+func TestStartResyncsAfterGarbage(t *testing.T) {
+	input := new(bytes.Buffer)
+	input.Write(frame(`{"type":"test","data":"first"}`))
+	input.Write(frame("not json at all"))
+	input.Write(frame(`{"type":"test","data":"second"}`))
+
+	_, received, events := startFramingTest(t, input)
+	// Handlers run concurrently, so the two messages may arrive in either order
+	assert.ElementsMatch(t, []string{"first", "second"}, []string{receive(t, received), receive(t, received)})
+	assert.Contains(t, receive(t, events).Data["reason"], "does not start with a JSON object")
+}
+
+func TestStartReportsTruncatedFrameOnDisconnect(t *testing.T) {
+	reader, writer := io.Pipe()
+	nm, received, events := startFramingTest(t, reader)
+
+	pending := make(chan error, 1)
+	go func() {
+		_, err := nm.RpcRequest(RpcRequest{Method: "confirm"}, RpcOptions{Timeout: 60000})
+		pending <- err
+	}()
+
+	_, _ = writer.Write(frame(`{"type":"test","data":"whole"}`))
+	assert.Equal(t, "whole", receive(t, received))
+	truncated := frame(`{"type":"test","data":"cut off"}`)
+	_, _ = writer.Write(truncated[:10])
+	_ = writer.Close()
+
+	errEvent := receive(t, events)
+	assert.Contains(t, errEvent.Data["reason"], "stream closed after 10 bytes of an incomplete message")
+	assert.ErrorIs(t, receive(t, pending), ErrDisconnected, "waiting requests fail instead of timing out")
+	assert.ErrorIs(t, nm.SendMessage(Message{Type: "test"}), ErrDisconnected)
+}
+
+// syncWriter lets the test read what the reader goroutine writes
+type syncWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *syncWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestStartRejectsMessageOverConfiguredLimit(t *testing.T) {
+	reader, writer := io.Pipe()
+	output := new(syncWriter)
+	_, received, events := startFramingTestWithConfig(t, NativeMessagingConfig{
+		Stdin:                 reader,
+		Stdout:                output,
+		MaxInboundMessageSize: 64,
+	})
+
+	// The oversized message arrives in pieces; none of it is handled
+	oversized := frame(`{"type":"test","data":"` + strings.Repeat("x", 200) + `"}`)
+	_, _ = writer.Write(oversized[:100])
+	errEvent := receive(t, events)
+	assert.Equal(t, event.EventTypeNativeMessagingError, errEvent.Type)
+	assert.Equal(t, "message of 225 bytes exceeds the 64 byte limit", errEvent.Data["reason"])
+	assert.Equal(t, 229, errEvent.Data["discarded_bytes"])
+	_, _ = writer.Write(oversized[100:])
+
+	_, _ = writer.Write(frame(`{"type":"test","data":"small"}`))
+	assert.Equal(t, "small", receive(t, received))
+	assert.Eventually(t, func() bool {
+		return strings.Contains(output.String(), `"message":"Invalid request: message of 225 bytes exceeds the 64 byte limit"`)
+	}, 2*time.Second, 10*time.Millisecond)
+	_ = writer.Close()
+}
+
+func TestSendMessageRejectsOversizedMessage(t *testing.T) {
+	output := new(bytes.Buffer)
+	nm, err := NewNativeMessaging(NativeMessagingConfig{Logger: logger.NewMockLogger(), Stdout: output})
+	assert.NoError(t, err)
+
+	err = nm.SendMessage(Message{Type: "test", Data: json.RawMessage(`"` + strings.Repeat("x", MaxOutboundMessageSize) + `"`)})
+	assert.ErrorIs(t, err, ErrMessageTooLarge)
+	assert.Zero(t, output.Len(), "nothing is written for a message Chrome would refuse")
+}
@@ -18,6 +18,7 @@ type SimulationResult struct {
 	BalanceChange string  `json:"balance_change"`
 	Warnings     []string `json:"warnings"`
 	Errors       []string `json:"errors"`
+	RevertReason *chain.RevertReason `json:"revert_reason,omitempty"`
 }
 
 // TransactionSimulator handles transaction simulations
@@ -71,6 +72,14 @@ func (s *TransactionSimulator) SimulateTransaction(ctx context.Context, chainNam
 	// Estimate gas
 	gasLimit, gasPrice, err := chainImpl.EstimateGas(ctx, from, to, amount, token)
 	if err != nil {
+		// A reverting call fails gas estimation; report why instead of erroring out
+		if reason := chain.RevertReasonFromError(err); reason != nil {
+			return &SimulationResult{
+				Success:      false,
+				Errors:       []string{"Transaction would revert: " + reason.Message},
+				RevertReason: reason,
+			}, nil
+		}
 		return nil, fmt.Errorf("failed to estimate gas: %w", err)
 	}
 
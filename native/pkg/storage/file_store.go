@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const fileStoreExt = ".json"
+
+// FileStateStore stores each value as <baseDir>/<namespace>/<key>.json.
+// Directories are created 0700 and files written 0600.
+type FileStateStore struct {
+	baseDir string
+	mu      sync.RWMutex
+}
+
+// NewFileStateStore creates a filesystem-backed StateStore rooted at baseDir
+func NewFileStateStore(baseDir string) (*FileStateStore, error) {
+	if baseDir == "" {
+		return nil, errors.New("base directory is required")
+	}
+	return &FileStateStore{baseDir: baseDir}, nil
+}
+
+// BaseDir returns the root directory of the store
+func (s *FileStateStore) BaseDir() string {
+	return s.baseDir
+}
+
+// Get reads the value stored under key
+func (s *FileStateStore) Get(_ context.Context, namespace, key string) ([]byte, error) {
+	path, err := s.path(namespace, key)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s/%s: %w", namespace, key, err)
+	}
+	return data, nil
+}
+
+// Put writes value under key. The write goes to a temporary file first so a
+// crash never leaves a truncated value behind.
+func (s *FileStateStore) Put(_ context.Context, namespace, key string, value []byte) error {
+	path, err := s.path(namespace, key)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, privateDirMode); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", namespace, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+key+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once renamed
+
+	if err := tmp.Chmod(privateFileMode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	if _, err := tmp.Write(value); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s/%s: %w", namespace, key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s/%s: %w", namespace, key, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write %s/%s: %w", namespace, key, err)
+	}
+	return verifyPrivate(path, privateFileMode)
+}
+
+// Delete removes the value stored under key
+func (s *FileStateStore) Delete(_ context.Context, namespace, key string) error {
+	path, err := s.path(namespace, key)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete %s/%s: %w", namespace, key, err)
+	}
+	return nil
+}
+
+// List returns the keys stored in namespace
+func (s *FileStateStore) List(_ context.Context, namespace string) ([]string, error) {
+	if err := validateKey("namespace", namespace); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries, err := os.ReadDir(filepath.Join(s.baseDir, namespace))
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", namespace, err)
+	}
+
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, fileStoreExt) {
+			continue
+		}
+		keys = append(keys, strings.TrimSuffix(name, fileStoreExt))
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *FileStateStore) path(namespace, key string) (string, error) {
+	if err := validateKey("namespace", namespace); err != nil {
+		return "", err
+	}
+	if err := validateKey("key", key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.baseDir, namespace, key+fileStoreExt), nil
+}
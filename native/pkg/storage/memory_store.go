@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryStateStore keeps state in process memory. It is intended for tests and
+// ephemeral deployments where nothing should outlive the process.
+type MemoryStateStore struct {
+	mu   sync.RWMutex
+	data map[string]map[string][]byte
+}
+
+// NewMemoryStateStore creates an empty in-memory StateStore
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{data: make(map[string]map[string][]byte)}
+}
+
+// Get returns a copy of the value stored under key
+func (s *MemoryStateStore) Get(_ context.Context, namespace, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.data[namespace][key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// Put stores a copy of value under key
+func (s *MemoryStateStore) Put(_ context.Context, namespace, key string, value []byte) error {
+	if err := validateKey("namespace", namespace); err != nil {
+		return err
+	}
+	if err := validateKey("key", key); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data[namespace] == nil {
+		s.data[namespace] = make(map[string][]byte)
+	}
+	s.data[namespace][key] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete removes key from namespace
+func (s *MemoryStateStore) Delete(_ context.Context, namespace, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data[namespace], key)
+	return nil
+}
+
+// List returns the keys stored in namespace
+func (s *MemoryStateStore) List(_ context.Context, namespace string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.data[namespace]))
+	for key := range s.data[namespace] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
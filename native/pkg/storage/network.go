@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+)
+
+// persistedNamespaces lists every namespace written by the wallet. They are
+// moved together when migrating the pre-network-mode layout.
+var persistedNamespaces = []string{
+	NamespaceWallets,
+	NamespacePending,
+	NamespaceAudit,
+	NamespaceNonces,
+	NamespaceBalances,
+	NamespaceSecurity,
+	NamespaceScheduled,
+	NamespaceTriggers,
+	NamespaceAddressBook,
+	NamespaceSettings,
+}
+
+// NetworkDir returns the directory holding the state of networkMode below
+// dataDir, e.g. <dataDir>/testnet. An empty mode selects mainnet.
+func NetworkDir(dataDir, networkMode string) (string, error) {
+	mode, err := config.NormalizeNetworkMode(networkMode)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, mode), nil
+}
+
+// MigrateLegacyLayout moves state written before it was namespaced by network
+// mode (<dataDir>/<namespace>/) into the mainnet directory. It returns the
+// namespaces that were moved; running it again once migrated is a no-op.
+// A file already present at the destination is never overwritten.
+func MigrateLegacyLayout(dataDir string) ([]string, error) {
+	mainnetDir := filepath.Join(dataDir, config.NetworkModeMainnet)
+	var migrated []string
+	for _, namespace := range persistedNamespaces {
+		legacyDir := filepath.Join(dataDir, namespace)
+		info, err := os.Stat(legacyDir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return migrated, fmt.Errorf("failed to inspect legacy %s directory: %w", namespace, err)
+		}
+		if !info.IsDir() {
+			continue
+		}
+
+		if err := moveNamespaceDir(legacyDir, filepath.Join(mainnetDir, namespace)); err != nil {
+			return migrated, fmt.Errorf("failed to migrate %s to %s: %w", namespace, config.NetworkModeMainnet, err)
+		}
+		migrated = append(migrated, namespace)
+	}
+	return migrated, nil
+}
+
+// moveNamespaceDir renames src to dst, merging file by file when dst already exists
+func moveNamespaceDir(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+	if _, err := os.Stat(dst); errors.Is(err, os.ErrNotExist) {
+		return os.Rename(src, dst)
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		target := filepath.Join(dst, entry.Name())
+		if _, err := os.Stat(target); err == nil {
+			continue // keep the already migrated value and leave the legacy copy in place
+		}
+		if err := os.Rename(filepath.Join(src, entry.Name()), target); err != nil {
+			return err
+		}
+	}
+	// Only succeeds once everything has been moved
+	_ = os.Remove(src)
+	return nil
+}
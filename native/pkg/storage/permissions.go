@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Permissions of the directories and files of a FileStateStore
+const (
+	privateDirMode  os.FileMode = 0700
+	privateFileMode os.FileMode = 0600
+)
+
+// permissionsEnforced is false where Unix permission bits do not control access
+var permissionsEnforced = runtime.GOOS != "windows"
+
+// PermissionIssue is a path of the store that its group or others can access
+type PermissionIssue struct {
+	Path string
+	Mode os.FileMode // the permissions found
+	Want os.FileMode // the permissions the store writes
+}
+
+func (i PermissionIssue) String() string {
+	return fmt.Sprintf("%s has mode %04o, expected %04o", i.Path, i.Mode, i.Want)
+}
+
+// PermissionHardener is implemented by stores whose files can be exposed by
+// their filesystem permissions
+type PermissionHardener interface {
+	// CheckPermissions reports the paths of the store that are accessible
+	// beyond their owner
+	CheckPermissions() ([]PermissionIssue, error)
+
+	// HardenPermissions restricts those paths to their owner again and
+	// returns the ones it changed
+	HardenPermissions() ([]PermissionIssue, error)
+}
+
+// CheckPermissions reports the base directory, namespace directories and
+// values of the store that have any group or other permission bit set
+func (s *FileStateStore) CheckPermissions() ([]PermissionIssue, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.permissionIssues()
+}
+
+// HardenPermissions resets every path reported by CheckPermissions to 0700
+// for directories and 0600 for files
+func (s *FileStateStore) HardenPermissions() ([]PermissionIssue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issues, err := s.permissionIssues()
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range issues {
+		if err := os.Chmod(issue.Path, issue.Want); err != nil {
+			return nil, fmt.Errorf("failed to restrict %s: %w", issue.Path, err)
+		}
+		if err := verifyPrivate(issue.Path, issue.Want); err != nil {
+			return nil, err
+		}
+	}
+	return issues, nil
+}
+
+func (s *FileStateStore) permissionIssues() ([]PermissionIssue, error) {
+	if !permissionsEnforced {
+		return nil, nil
+	}
+	var issues []PermissionIssue
+	check := func(path string, info os.FileInfo) {
+		want := privateFileMode
+		if info.IsDir() {
+			want = privateDirMode
+		}
+		if mode := info.Mode().Perm(); mode&0077 != 0 {
+			issues = append(issues, PermissionIssue{Path: path, Mode: mode, Want: want})
+		}
+	}
+
+	info, err := os.Stat(s.baseDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s: %w", s.baseDir, err)
+	}
+	check(s.baseDir, info)
+
+	namespaces, err := os.ReadDir(s.baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", s.baseDir, err)
+	}
+	for _, namespace := range namespaces {
+		if !namespace.IsDir() {
+			continue
+		}
+		dir := filepath.Join(s.baseDir, namespace.Name())
+		if info, err := namespace.Info(); err == nil {
+			check(dir, info)
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if info, err := entry.Info(); err == nil {
+				check(filepath.Join(dir, entry.Name()), info)
+			}
+		}
+	}
+	return issues, nil
+}
+
+// verifyPrivate fails when path ended up accessible beyond its owner, e.g. on
+// a filesystem that ignores chmod
+func verifyPrivate(path string, want os.FileMode) error {
+	if !permissionsEnforced {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", path, err)
+	}
+	if mode := info.Mode().Perm(); mode&0077 != 0 {
+		return fmt.Errorf("%s has mode %04o instead of %04o; the filesystem did not apply the permissions", path, mode, want)
+	}
+	return nil
+}
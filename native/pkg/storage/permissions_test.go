@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStateStorePermissions(t *testing.T) {
+	if !permissionsEnforced {
+		t.Skip("permission bits are not enforced on this platform")
+	}
+	dir := filepath.Join(t.TempDir(), "mainnet")
+	store, err := NewFileStateStore(dir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	if err := store.Put(context.Background(), NamespaceWallets, "wallet", []byte("{}")); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if issues, err := store.CheckPermissions(); err != nil || len(issues) != 0 {
+		t.Fatalf("expected a freshly written store to be private, got %v %v", issues, err)
+	}
+
+	// Simulate a deployment that loosened the wallet file and its directory
+	walletDir := filepath.Join(dir, NamespaceWallets)
+	walletFile := filepath.Join(walletDir, "wallet.json")
+	if err := os.Chmod(walletFile, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(walletDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := store.CheckPermissions()
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected the directory and file to be reported, got %v", issues)
+	}
+	for _, issue := range issues {
+		switch issue.Path {
+		case walletDir:
+			if issue.Mode != 0755 || issue.Want != 0700 {
+				t.Errorf("unexpected directory issue: %v", issue)
+			}
+		case walletFile:
+			if issue.Mode != 0644 || issue.Want != 0600 {
+				t.Errorf("unexpected file issue: %v", issue)
+			}
+		default:
+			t.Errorf("unexpected path reported: %v", issue)
+		}
+	}
+
+	hardened, err := store.HardenPermissions()
+	if err != nil || len(hardened) != 2 {
+		t.Fatalf("expected both paths to be hardened, got %v %v", hardened, err)
+	}
+	info, err := os.Stat(walletFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected 0600 after hardening, got %o", perm)
+	}
+	if issues, err := store.CheckPermissions(); err != nil || len(issues) != 0 {
+		t.Errorf("expected no issues after hardening, got %v %v", issues, err)
+	}
+}
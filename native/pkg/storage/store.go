@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+)
+
+// Namespaces used by the wallet for persisted state
+const (
+	NamespaceWallets     = "wallets"
+	NamespacePending     = "pending"
+	NamespaceAudit       = "audit"
+	NamespaceNonces      = "nonces"
+	NamespaceBalances    = "balances"
+	NamespaceSecurity    = "security"
+	NamespaceScheduled   = "scheduled"
+	NamespaceTriggers    = "triggers"
+	NamespaceAddressBook = "address_book"
+	NamespaceSettings    = "settings"
+)
+
+// Built-in backend names accepted by config.StorageConfig.Backend
+const (
+	BackendFile   = "file"
+	BackendMemory = "memory"
+)
+
+// ErrNotFound is returned by Get when the key does not exist in the namespace
+var ErrNotFound = errors.New("key not found")
+
+// StateStore persists opaque values grouped by namespace
+type StateStore interface {
+	// Get returns the value stored under key, or ErrNotFound
+	Get(ctx context.Context, namespace, key string) ([]byte, error)
+
+	// Put stores value under key, replacing any existing value
+	Put(ctx context.Context, namespace, key string, value []byte) error
+
+	// Delete removes key; deleting a missing key is not an error
+	Delete(ctx context.Context, namespace, key string) error
+
+	// List returns the keys in namespace in lexical order
+	List(ctx context.Context, namespace string) ([]string, error)
+}
+
+// BackendFactory creates a StateStore rooted at dataDir using backend-specific options
+type BackendFactory func(dataDir string, options map[string]string) (StateStore, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]BackendFactory{
+		BackendFile: func(dataDir string, _ map[string]string) (StateStore, error) {
+			return NewFileStateStore(dataDir)
+		},
+		BackendMemory: func(string, map[string]string) (StateStore, error) {
+			return NewMemoryStateStore(), nil
+		},
+	}
+)
+
+// RegisterBackend makes a StateStore implementation selectable by name from configuration
+func RegisterBackend(name string, factory BackendFactory) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	backends[strings.ToLower(name)] = factory
+}
+
+// RegisteredBackends returns the names of all registered backends
+func RegisteredBackends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the StateStore selected by cfg. An empty backend selects the filesystem store.
+func New(cfg config.StorageConfig, dataDir string) (StateStore, error) {
+	name := strings.ToLower(strings.TrimSpace(cfg.Backend))
+	if name == "" {
+		name = BackendFile
+	}
+
+	backendsMu.RLock()
+	factory, ok := backends[name]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown storage backend %q (registered: %s)", name, strings.Join(RegisteredBackends(), ", "))
+	}
+	return factory(dataDir, cfg.Options)
+}
+
+// validateKey rejects names that could escape their namespace on path-based backends
+func validateKey(kind, name string) error {
+	if name == "" {
+		return fmt.Errorf("%s cannot be empty", kind)
+	}
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid %s: %q", kind, name)
+	}
+	return nil
+}
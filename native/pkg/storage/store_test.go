@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+)
+
+func testStateStore(t *testing.T, store StateStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := store.Get(ctx, NamespacePending, "0xabc"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := store.Put(ctx, NamespacePending, "0xdef", []byte(`{"hash":"0xdef"}`)); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if err := store.Put(ctx, NamespacePending, "0xabc", []byte(`{"hash":"0xabc"}`)); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+
+	value, err := store.Get(ctx, NamespacePending, "0xabc")
+	if err != nil || string(value) != `{"hash":"0xabc"}` {
+		t.Errorf("unexpected value %q, %v", value, err)
+	}
+
+	keys, err := store.List(ctx, NamespacePending)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "0xabc" || keys[1] != "0xdef" {
+		t.Errorf("unexpected keys: %v", keys)
+	}
+
+	if keys, _ := store.List(ctx, NamespaceNonces); len(keys) != 0 {
+		t.Errorf("expected empty namespace, got %v", keys)
+	}
+
+	if err := store.Delete(ctx, NamespacePending, "0xabc"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if err := store.Delete(ctx, NamespacePending, "0xabc"); err != nil {
+		t.Errorf("deleting a missing key should succeed: %v", err)
+	}
+	if _, err := store.Get(ctx, NamespacePending, "0xabc"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+
+	if err := store.Put(ctx, NamespacePending, "../escape", []byte("x")); err == nil {
+		t.Error("expected error for key containing a path separator")
+	}
+}
+
+func TestFileStateStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStateStore(dir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	testStateStore(t, store)
+
+	if err := store.Put(context.Background(), NamespaceWallets, "wallet", []byte("{}")); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(dir, "wallets", "wallet.json"))
+	if err != nil {
+		t.Fatalf("expected wallet file at legacy location: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected 0600 permissions, got %o", perm)
+	}
+}
+
+func TestMemoryStateStore(t *testing.T) {
+	testStateStore(t, NewMemoryStateStore())
+}
+
+func TestNewSelectsBackend(t *testing.T) {
+	store, err := New(config.StorageConfig{}, t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := store.(*FileStateStore); !ok {
+		t.Errorf("expected file store by default, got %T", store)
+	}
+
+	if _, err := New(config.StorageConfig{Backend: "sqlite"}, t.TempDir()); err == nil {
+		t.Error("expected error for unregistered backend")
+	}
+
+	custom := NewMemoryStateStore()
+	RegisterBackend("custom-kv", func(string, map[string]string) (StateStore, error) { return custom, nil })
+	store, err = New(config.StorageConfig{Backend: "Custom-KV"}, "")
+	if err != nil || store != custom {
+		t.Errorf("expected registered backend to be used, got %v, %v", store, err)
+	}
+}
+
+func TestNetworkDir(t *testing.T) {
+	dir, err := NetworkDir("/data", "")
+	if err != nil || dir != filepath.Join("/data", "mainnet") {
+		t.Errorf("expected mainnet by default, got %q, %v", dir, err)
+	}
+	dir, err = NetworkDir("/data", "Testnet")
+	if err != nil || dir != filepath.Join("/data", "testnet") {
+		t.Errorf("expected testnet directory, got %q, %v", dir, err)
+	}
+	if _, err := NetworkDir("/data", "../mainnet"); err == nil {
+		t.Error("expected error for unknown network mode")
+	}
+}
+
+func TestMigrateLegacyLayout(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	legacy, err := NewFileStateStore(dataDir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	if err := legacy.Put(ctx, NamespaceWallets, "wallet", []byte(`{"address":"0xabc"}`)); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if err := legacy.Put(ctx, NamespacePending, "0xdef", []byte("{}")); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dataDir, "logs"), 0700); err != nil {
+		t.Fatalf("failed to create logs dir: %v", err)
+	}
+
+	migrated, err := MigrateLegacyLayout(dataDir)
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if len(migrated) != 2 {
+		t.Errorf("expected wallets and pending to be migrated, got %v", migrated)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "wallets")); !os.IsNotExist(err) {
+		t.Errorf("expected legacy wallets directory to be gone, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "logs")); err != nil {
+		t.Errorf("expected unrelated directories to stay in place: %v", err)
+	}
+
+	mainnet, _ := NewFileStateStore(filepath.Join(dataDir, "mainnet"))
+	value, err := mainnet.Get(ctx, NamespaceWallets, "wallet")
+	if err != nil || string(value) != `{"address":"0xabc"}` {
+		t.Errorf("expected wallet in the mainnet directory, got %q, %v", value, err)
+	}
+	testnet, _ := NewFileStateStore(filepath.Join(dataDir, "testnet"))
+	if _, err := testnet.Get(ctx, NamespaceWallets, "wallet"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected testnet to start empty, got %v", err)
+	}
+
+	// A second run has nothing left to move and never overwrites migrated state
+	if err := legacy.Put(ctx, NamespaceWallets, "wallet", []byte(`{"address":"0xstale"}`)); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if _, err := MigrateLegacyLayout(dataDir); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+	if value, _ := mainnet.Get(ctx, NamespaceWallets, "wallet"); string(value) != `{"address":"0xabc"}` {
+		t.Errorf("expected migrated wallet to be kept, got %q", value)
+	}
+}
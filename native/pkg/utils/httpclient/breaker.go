@@ -0,0 +1,156 @@
+package httpclient
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Circuit breaker states
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half_open"
+)
+
+// BreakerStats is a snapshot of one provider host's breaker, for metrics
+type BreakerStats struct {
+	Provider            string    `json:"provider"`
+	Host                string    `json:"host"`
+	State               string    `json:"state"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Successes           uint64    `json:"successes"`
+	Failures            uint64    `json:"failures"`
+	Rejected            uint64    `json:"rejected"` // requests refused while open
+	OpenedAt            time.Time `json:"opened_at,omitempty"`
+}
+
+// breaker opens after failureThreshold consecutive failures, refuses requests
+// for openTimeout, then lets a single probe through: its success closes the
+// breaker and its failure opens it again
+type breaker struct {
+	mu               sync.Mutex
+	stats            BreakerStats
+	failureThreshold int
+	openTimeout      time.Duration
+	probing          bool
+	now              func() time.Time
+}
+
+// allow reports whether a request may be sent, moving an open breaker to
+// half-open once its timeout has passed
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.stats.State {
+	case StateOpen:
+		if b.now().Sub(b.stats.OpenedAt) < b.openTimeout {
+			b.stats.Rejected++
+			return fmt.Errorf("%w until %s", ErrCircuitOpen, b.stats.OpenedAt.Add(b.openTimeout).UTC().Format(time.RFC3339))
+		}
+		b.stats.State = StateHalfOpen
+		b.probing = true
+	case StateHalfOpen:
+		if b.probing {
+			b.stats.Rejected++
+			return fmt.Errorf("%w: recovery probe in flight", ErrCircuitOpen)
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of an allowed request
+func (b *breaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if success {
+		b.stats.Successes++
+		b.stats.ConsecutiveFailures = 0
+		b.stats.State = StateClosed
+		return
+	}
+	b.stats.Failures++
+	b.stats.ConsecutiveFailures++
+	if b.stats.State == StateHalfOpen || (b.failureThreshold > 0 && b.stats.ConsecutiveFailures >= b.failureThreshold) {
+		b.stats.State = StateOpen
+		b.stats.OpenedAt = b.now()
+	}
+}
+
+// release gives up an allowed request without an outcome, e.g. when the
+// caller cancelled it
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// State returns the breaker state
+func (b *breaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stats.State
+}
+
+// Registry holds the breaker of every provider host so their state can be
+// reported
+type Registry struct {
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{breakers: make(map[string]*breaker)}
+}
+
+// DefaultRegistry is the process-wide registry used by New and NewTransport
+var DefaultRegistry = NewRegistry()
+
+// breaker returns the breaker for provider and host, creating it on first use
+func (r *Registry) breaker(provider, host string, failureThreshold int, openTimeout time.Duration, now func() time.Time) *breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := provider + "/" + host
+	if b, ok := r.breakers[key]; ok {
+		return b
+	}
+	b := &breaker{
+		stats:            BreakerStats{Provider: provider, Host: host, State: StateClosed},
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		now:              now,
+	}
+	r.breakers[key] = b
+	return b
+}
+
+// Snapshot returns the stats of every breaker, sorted by provider and host
+func (r *Registry) Snapshot() []BreakerStats {
+	r.mu.Lock()
+	breakers := make([]*breaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+	r.mu.Unlock()
+
+	snapshot := make([]BreakerStats, 0, len(breakers))
+	for _, b := range breakers {
+		b.mu.Lock()
+		snapshot = append(snapshot, b.stats)
+		b.mu.Unlock()
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].Provider != snapshot[j].Provider {
+			return snapshot[i].Provider < snapshot[j].Provider
+		}
+		return snapshot[i].Host < snapshot[j].Host
+	})
+	return snapshot
+}
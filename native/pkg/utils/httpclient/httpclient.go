@@ -0,0 +1,226 @@
+// Package httpclient provides the HTTP client used for every external provider:
+// retries with backoff and a per-host circuit breaker on top of a transport.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Defaults applied by New and NewTransport
+const (
+	DefaultTimeout          = 30 * time.Second
+	DefaultMaxRetries       = 2
+	DefaultBaseDelay        = 100 * time.Millisecond
+	DefaultMaxDelay         = 2 * time.Second
+	DefaultFailureThreshold = 5
+	DefaultOpenTimeout      = 30 * time.Second
+)
+
+// DefaultRetryStatuses are the response codes treated as transient failures
+var DefaultRetryStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// ErrCircuitOpen is returned without contacting the host while its breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Transport is an http.RoundTripper that retries transient failures with
+// exponential backoff and stops calling a host that keeps failing. Each host
+// gets its own breaker, so failover between endpoints keeps working.
+type Transport struct {
+	name             string
+	transport        http.RoundTripper
+	timeout          time.Duration
+	maxRetries       int
+	baseDelay        time.Duration
+	maxDelay         time.Duration
+	retryStatuses    map[int]bool
+	failureThreshold int
+	openTimeout      time.Duration
+	registry         *Registry
+	now              func() time.Time
+}
+
+// Option configures a Transport
+type Option func(*Transport)
+
+// WithTransport sets the underlying transport, e.g. a rate limiter
+func WithTransport(transport http.RoundTripper) Option {
+	return func(t *Transport) {
+		t.transport = transport
+	}
+}
+
+// WithTimeout sets the overall timeout of a client built by New, retries included
+func WithTimeout(timeout time.Duration) Option {
+	return func(t *Transport) {
+		t.timeout = timeout
+	}
+}
+
+// WithRetries sets how many times a failed request is retried; 0 disables retries
+func WithRetries(maxRetries int) Option {
+	return func(t *Transport) {
+		t.maxRetries = maxRetries
+	}
+}
+
+// WithBackoff sets the delay before the first retry and the cap it doubles up to
+func WithBackoff(baseDelay, maxDelay time.Duration) Option {
+	return func(t *Transport) {
+		t.baseDelay = baseDelay
+		t.maxDelay = maxDelay
+	}
+}
+
+// WithRetryStatuses replaces the response codes that are retried and count as
+// breaker failures. Providers that handle 429 themselves, e.g. by rotating
+// API keys, leave it out.
+func WithRetryStatuses(statuses ...int) Option {
+	return func(t *Transport) {
+		t.retryStatuses = make(map[int]bool, len(statuses))
+		for _, status := range statuses {
+			t.retryStatuses[status] = true
+		}
+	}
+}
+
+// WithCircuitBreaker sets the consecutive failures that open a host's breaker
+// and how long it stays open before a probe request is let through
+func WithCircuitBreaker(failureThreshold int, openTimeout time.Duration) Option {
+	return func(t *Transport) {
+		t.failureThreshold = failureThreshold
+		t.openTimeout = openTimeout
+	}
+}
+
+// WithRegistry records breaker state in registry instead of DefaultRegistry
+func WithRegistry(registry *Registry) Option {
+	return func(t *Transport) {
+		t.registry = registry
+	}
+}
+
+// NewTransport creates a Transport for the provider called name
+func NewTransport(name string, opts ...Option) *Transport {
+	t := &Transport{
+		name:             name,
+		transport:        http.DefaultTransport,
+		timeout:          DefaultTimeout,
+		maxRetries:       DefaultMaxRetries,
+		baseDelay:        DefaultBaseDelay,
+		maxDelay:         DefaultMaxDelay,
+		failureThreshold: DefaultFailureThreshold,
+		openTimeout:      DefaultOpenTimeout,
+		registry:         DefaultRegistry,
+		now:              time.Now,
+	}
+	WithRetryStatuses(DefaultRetryStatuses...)(t)
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// New creates an http.Client for the provider called name
+func New(name string, opts ...Option) *http.Client {
+	transport := NewTransport(name, opts...)
+	return &http.Client{Transport: transport, Timeout: transport.timeout}
+}
+
+// RoundTrip sends req, retrying transient failures while the host's breaker allows
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	breaker := t.registry.breaker(t.name, req.URL.Host, t.failureThreshold, t.openTimeout, t.now)
+	// A body that cannot be replayed can only be sent once
+	retries := t.maxRetries
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := breaker.allow(); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", t.name, req.URL.Host, err)
+		}
+
+		attemptReq := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.transport.RoundTrip(attemptReq)
+		failed := err != nil || t.retryStatuses[resp.StatusCode]
+		// A caller that gave up is not the host's fault
+		if err != nil && req.Context().Err() != nil {
+			breaker.release()
+			return nil, err
+		}
+		breaker.record(!failed)
+		if !failed || attempt >= retries || breaker.State() == StateOpen {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if resp != nil {
+			if retryAfter := parseRetryAfter(resp.Header); retryAfter > delay {
+				delay = min(retryAfter, t.maxDelay)
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// backoff doubles the base delay per attempt, with jitter, up to the cap
+func (t *Transport) backoff(attempt int) time.Duration {
+	delay := t.baseDelay << attempt
+	if delay <= 0 || delay > t.maxDelay {
+		delay = t.maxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds
+func parseRetryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,263 @@
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a settable clock for breaker timeouts
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestClient(registry *Registry, clock *fakeClock, opts ...Option) *http.Client {
+	transport := NewTransport("test", append([]Option{
+		WithRegistry(registry),
+		WithBackoff(time.Millisecond, 5*time.Millisecond),
+	}, opts...)...)
+	transport.now = clock.Now
+	return &http.Client{Transport: transport, Timeout: 5 * time.Second}
+}
+
+func TestRetrySucceedsAfterTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	var bodies []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	registry := NewRegistry()
+	client := newTestClient(registry, &fakeClock{now: time.Now()})
+
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"id":1}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, []string{`{"id":1}`, `{"id":1}`, `{"id":1}`}, bodies, "body must be replayed on every retry")
+
+	stats := registry.Snapshot()
+	require.Len(t, stats, 1)
+	assert.Equal(t, StateClosed, stats[0].State)
+	assert.Equal(t, 0, stats[0].ConsecutiveFailures)
+	assert.Equal(t, uint64(2), stats[0].Failures)
+	assert.Equal(t, uint64(1), stats[0].Successes)
+}
+
+func TestRetryGivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := newTestClient(NewRegistry(), &fakeClock{now: time.Now()}, WithRetries(1))
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestNonRetryableStatusIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	registry := NewRegistry()
+	client := newTestClient(registry, &fakeClock{now: time.Now()}, WithRetryStatuses(http.StatusServiceUnavailable))
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, uint64(1), registry.Snapshot()[0].Successes, "statuses left to the caller do not trip the breaker")
+}
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	registry := NewRegistry()
+	client := newTestClient(registry, &fakeClock{now: time.Now()},
+		WithRetries(0), WithCircuitBreaker(3, time.Minute))
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	require.Equal(t, int32(3), calls.Load())
+
+	_, err := client.Get(server.URL)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, int32(3), calls.Load(), "an open breaker must not contact the host")
+
+	stats := registry.Snapshot()
+	require.Len(t, stats, 1)
+	assert.Equal(t, "test", stats[0].Provider)
+	assert.Equal(t, StateOpen, stats[0].State)
+	assert.Equal(t, 3, stats[0].ConsecutiveFailures)
+	assert.Equal(t, uint64(1), stats[0].Rejected)
+	assert.False(t, stats[0].OpenedAt.IsZero())
+}
+
+func TestBreakerStopsRetriesOnceOpen(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := newTestClient(NewRegistry(), &fakeClock{now: time.Now()},
+		WithRetries(5), WithCircuitBreaker(2, time.Minute))
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestBreakerHalfOpenProbeRecovers(t *testing.T) {
+	var healthy atomic.Bool
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	registry := NewRegistry()
+	clock := &fakeClock{now: time.Now()}
+	client := newTestClient(registry, clock, WithRetries(0), WithCircuitBreaker(2, 30*time.Second))
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	require.Equal(t, StateOpen, registry.Snapshot()[0].State)
+
+	// Still failing when the timeout passes: the probe re-opens the breaker
+	clock.Advance(31 * time.Second)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, StateOpen, registry.Snapshot()[0].State)
+	_, err = client.Get(server.URL)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, int32(3), calls.Load())
+
+	// Recovered: the next probe closes it and traffic flows again
+	healthy.Store(true)
+	clock.Advance(31 * time.Second)
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	stats := registry.Snapshot()[0]
+	assert.Equal(t, StateClosed, stats.State)
+	assert.Equal(t, 0, stats.ConsecutiveFailures)
+	assert.Equal(t, int32(5), calls.Load())
+}
+
+func TestBreakerHalfOpenAllowsSingleProbe(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	b := NewRegistry().breaker("test", "example.com", 1, time.Second, clock.Now)
+
+	require.NoError(t, b.allow())
+	b.record(false)
+	require.Equal(t, StateOpen, b.State())
+
+	clock.Advance(2 * time.Second)
+	require.NoError(t, b.allow())
+	assert.Equal(t, StateHalfOpen, b.State())
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen, "only one probe may be in flight")
+
+	b.record(true)
+	assert.Equal(t, StateClosed, b.State())
+	assert.NoError(t, b.allow())
+}
+
+func TestBreakersAreTrackedPerHost(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	registry := NewRegistry()
+	client := newTestClient(registry, &fakeClock{now: time.Now()}, WithRetries(0), WithCircuitBreaker(1, time.Minute))
+
+	resp, err := client.Get(failing.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	_, err = client.Get(failing.URL)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	resp, err = client.Get(healthy.URL)
+	require.NoError(t, err, "a failing host must not block failover to another")
+	resp.Body.Close()
+	assert.Len(t, registry.Snapshot(), 2)
+}
@@ -4,7 +4,6 @@ package wallet
 import (
 	"crypto/ecdsa"
 	"errors"
-	"strings"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -21,7 +20,7 @@ type Wallet struct {
 // CreateWallet generates a new wallet for the specified chain.
 // Currently only supports "ETH" (Ethereum).
 func CreateWallet(chain string) (*Wallet, error) {
-	if strings.ToUpper(chain) != "ETH" {
+	if NormalizeChain(chain) != "ethereum" {
 		return nil, errors.New("unsupported chain: only ETH is supported")
 	}
 	privateKey, err := crypto.GenerateKey()
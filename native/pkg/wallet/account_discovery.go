@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"go.uber.org/zap"
+)
+
+// DerivedAccount is an additional account of the wallet's mnemonic, found to
+// have on-chain activity or imported by ImportAccounts. Only its index is
+// needed to re-derive the key, so the private key itself is never persisted.
+type DerivedAccount struct {
+	Chain     string `json:"chain"`
+	Index     uint32 `json:"index"`
+	Path      string `json:"path"`
+	Address   string `json:"address"`
+	PublicKey string `json:"public_key"`
+}
+
+// DiscoverAccounts scans the accounts of the unlocked wallet's mnemonic on
+// chainName and adds every active one to the wallet. Zero maxAccounts or
+// gapLimit use the configured wallet.account_discovery values. It returns all
+// active accounts found, including ones the wallet already held.
+func (wm *WalletManager) DiscoverAccounts(ctx context.Context, chainName string, maxAccounts, gapLimit int) ([]*DerivedAccount, error) {
+	data, err := wm.unlockedWalletData()
+	if err != nil {
+		return nil, err
+	}
+	if data.Mnemonic == "" {
+		return nil, errors.New("wallet has no mnemonic to derive accounts from")
+	}
+	if err := ValidateChain(chainName); err != nil {
+		return nil, fmt.Errorf("unsupported chain: %w", err)
+	}
+	if maxAccounts == 0 {
+		maxAccounts = wm.accountDiscovery.MaxAccounts
+	}
+	if gapLimit == 0 {
+		gapLimit = wm.accountDiscovery.GapLimit
+	}
+
+	normalizedChain := NormalizeChain(chainName)
+	chainImpl, err := wm.chainFactory.GetChain(normalizedChain)
+	if err != nil {
+		return nil, err
+	}
+	found, err := chain.DiscoverAccounts(ctx, chainImpl, data.Mnemonic, maxAccounts, gapLimit)
+	if err != nil {
+		return nil, fmt.Errorf("account discovery failed: %w", err)
+	}
+
+	accounts := make([]*DerivedAccount, 0, len(found))
+	for _, account := range found {
+		accounts = append(accounts, &DerivedAccount{
+			Chain:     normalizedChain,
+			Index:     account.Index,
+			Path:      account.Path,
+			Address:   account.Address,
+			PublicKey: account.PublicKey,
+		})
+		wm.rememberAccount(data, account.Address, account.PublicKey, account.PrivateKey)
+	}
+	if err := wm.persistDerivedAccounts(accounts); err != nil {
+		return nil, err
+	}
+
+	wm.logger.Info("Account discovery completed",
+		zap.String("chain", normalizedChain),
+		zap.Int("max_accounts", maxAccounts),
+		zap.Int("gap_limit", gapLimit),
+		zap.Int("active_accounts", len(accounts)))
+	return accounts, nil
+}
+
+// ImportAccounts adds the first count accounts of the unlocked wallet's
+// mnemonic on chainName, whether used or not, as MetaMask does when accounts
+// are added one by one. count may not exceed the configured
+// wallet.account_discovery max_accounts. The wallet's own address is skipped;
+// the other accounts are returned, including ones the wallet already held.
+func (wm *WalletManager) ImportAccounts(ctx context.Context, chainName string, count int) ([]*DerivedAccount, error) {
+	data, err := wm.unlockedWalletData()
+	if err != nil {
+		return nil, err
+	}
+	if data.Mnemonic == "" {
+		return nil, errors.New("wallet has no mnemonic to derive accounts from")
+	}
+	if err := ValidateChain(chainName); err != nil {
+		return nil, fmt.Errorf("unsupported chain: %w", err)
+	}
+	if count <= 0 || count > wm.accountDiscovery.MaxAccounts {
+		return nil, fmt.Errorf("account count must be between 1 and %d", wm.accountDiscovery.MaxAccounts)
+	}
+
+	normalizedChain := NormalizeChain(chainName)
+	chainImpl, err := wm.chainFactory.GetChain(normalizedChain)
+	if err != nil {
+		return nil, err
+	}
+	deriver, ok := chainImpl.(chain.AccountDeriver)
+	if !ok {
+		return nil, fmt.Errorf("account derivation is not supported on %s", normalizedChain)
+	}
+
+	accounts := make([]*DerivedAccount, 0, count)
+	for index := 0; index < count; index++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		info, err := deriver.DeriveAccount(data.Mnemonic, uint32(index))
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive %s account %d: %w", normalizedChain, index, err)
+		}
+		if info.Address == data.Address {
+			continue
+		}
+		accounts = append(accounts, &DerivedAccount{
+			Chain:     normalizedChain,
+			Index:     uint32(index),
+			Path:      chain.AccountPath(chainImpl, uint32(index)),
+			Address:   info.Address,
+			PublicKey: info.PublicKey,
+		})
+		wm.rememberAccount(data, info.Address, info.PublicKey, info.PrivateKey)
+	}
+	if err := wm.persistDerivedAccounts(accounts); err != nil {
+		return nil, err
+	}
+
+	wm.logger.Info("Imported derived accounts",
+		zap.String("chain", normalizedChain),
+		zap.Int("count", count),
+		zap.Int("added_accounts", len(accounts)))
+	return accounts, nil
+}
+
+// persistDerivedAccounts adds accounts not yet recorded to the stored wallet
+func (wm *WalletManager) persistDerivedAccounts(accounts []*DerivedAccount) error {
+	if len(accounts) == 0 {
+		return nil
+	}
+	walletData, err := wm.loadWallet()
+	if err != nil {
+		return fmt.Errorf("failed to load wallet: %w", err)
+	}
+
+	known := make(map[string]bool, len(walletData.Accounts))
+	for _, account := range walletData.Accounts {
+		known[account.Chain+"/"+account.Address] = true
+	}
+	added := 0
+	for _, account := range accounts {
+		if known[account.Chain+"/"+account.Address] {
+			continue
+		}
+		walletData.Accounts = append(walletData.Accounts, account)
+		added++
+	}
+	if added == 0 {
+		return nil
+	}
+	if err := wm.saveWallet(walletData); err != nil {
+		return fmt.Errorf("failed to save wallet: %w", err)
+	}
+	return nil
+}
+
+// restoreDerivedAccounts re-derives the keys of persisted accounts into data after unlock
+func (wm *WalletManager) restoreDerivedAccounts(data *DecryptedWalletData, accounts []*DerivedAccount) error {
+	for _, account := range accounts {
+		chainImpl, err := wm.chainFactory.GetChain(account.Chain)
+		if err != nil {
+			return err
+		}
+		deriver, ok := chainImpl.(chain.AccountDeriver)
+		if !ok {
+			return fmt.Errorf("account derivation is not supported on %s", account.Chain)
+		}
+		info, err := deriver.DeriveAccount(data.Mnemonic, account.Index)
+		if err != nil {
+			return fmt.Errorf("failed to derive %s account %d: %w", account.Chain, account.Index, err)
+		}
+		if info.Address != account.Address {
+			return fmt.Errorf("%s account %d derived %s, expected %s", account.Chain, account.Index, info.Address, account.Address)
+		}
+		wm.rememberAccount(data, info.Address, info.PublicKey, info.PrivateKey)
+	}
+	return nil
+}
+
+// rememberAccount keeps a discovered account's key in data for signing
+func (wm *WalletManager) rememberAccount(data *DecryptedWalletData, address, publicKey, privateKey string) {
+	wm.walletMu.Lock()
+	defer wm.walletMu.Unlock()
+	if data.Accounts == nil {
+		data.Accounts = make(map[string]*ChainSpecificData)
+	}
+	data.Accounts[address] = &ChainSpecificData{
+		Address:    address,
+		PublicKey:  publicKey,
+		PrivateKey: privateKey,
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// usedAccountsChain is an ETH chain on which only the listed addresses have activity
+type usedAccountsChain struct {
+	*chain.ETHChain
+	used map[string]bool
+}
+
+func (c *usedAccountsChain) HasActivity(ctx context.Context, address string) (bool, error) {
+	return c.used[address], nil
+}
+
+func TestWalletManagerDiscoverAccounts(t *testing.T) {
+	ctx := context.Background()
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+
+	eth := chain.NewETHChainLegacy()
+	used := map[string]bool{}
+	for _, index := range []uint32{1, 3} {
+		account, err := eth.DeriveAccount(mnemonic, index)
+		if err != nil {
+			t.Fatalf("derive failed: %v", err)
+		}
+		used[account.Address] = true
+	}
+	wm.chainFactory.RegisterChain("ethereum", &usedAccountsChain{ETHChain: eth, used: used})
+
+	if _, err := wm.DiscoverAccounts(ctx, "ethereum", 0, 0); !errors.Is(err, ErrWalletLocked) {
+		t.Fatalf("expected ErrWalletLocked before import, got %v", err)
+	}
+
+	primary, _, _, err := wm.ImportWallet(ctx, mnemonic, "password123", "ethereum", "")
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	accounts, err := wm.DiscoverAccounts(ctx, "ethereum", 10, 2)
+	if err != nil {
+		t.Fatalf("discovery failed: %v", err)
+	}
+	if len(accounts) != 2 || accounts[0].Index != 1 || accounts[1].Index != 3 {
+		t.Fatalf("expected accounts 1 and 3, got %+v", accounts)
+	}
+	if accounts[1].Path != "m/44'/60'/0'/0/3" {
+		t.Errorf("unexpected path %s", accounts[1].Path)
+	}
+
+	addresses, _ := wm.GetAccounts(ctx)
+	if len(addresses) != 3 || addresses[0] != primary {
+		t.Errorf("expected primary plus two discovered accounts, got %v", addresses)
+	}
+
+	// Keys of discovered accounts are re-derived on unlock, not stored
+	wm.LockWallet()
+	if err := wm.UnlockWallet("", "password123"); err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+	if _, err := wm.SignMessage(ctx, accounts[0].Address, "hello"); err != nil {
+		t.Errorf("expected discovered account to sign after unlock: %v", err)
+	}
+	stored, err := wm.loadWallet()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(stored.Accounts) != 2 {
+		t.Errorf("expected two persisted accounts, got %d", len(stored.Accounts))
+	}
+
+	// Rediscovery does not duplicate persisted accounts
+	if _, err := wm.DiscoverAccounts(ctx, "ethereum", 10, 2); err != nil {
+		t.Fatalf("rediscovery failed: %v", err)
+	}
+	if stored, _ = wm.loadWallet(); len(stored.Accounts) != 2 {
+		t.Errorf("expected no duplicates, got %d accounts", len(stored.Accounts))
+	}
+}
+
+func TestWalletManagerImportAccounts(t *testing.T) {
+	ctx := context.Background()
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+
+	primary, _, _, err := wm.ImportWallet(ctx, mnemonic, "password123", "ethereum", "")
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if primary != "0x9858EfFD232B4033E47d90003D41EC34EcaEda94" {
+		t.Fatalf("expected the first MetaMask account, got %s", primary)
+	}
+
+	// The primary wallet is account 0, so three accounts add two
+	accounts, err := wm.ImportAccounts(ctx, "ethereum", 3)
+	if err != nil {
+		t.Fatalf("import accounts failed: %v", err)
+	}
+	if len(accounts) != 2 || accounts[0].Index != 1 || accounts[1].Index != 2 {
+		t.Fatalf("expected accounts 1 and 2, got %+v", accounts)
+	}
+	if accounts[0].Address != "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0" || accounts[0].Path != "m/44'/60'/0'/0/1" {
+		t.Errorf("unexpected account 1: %+v", accounts[0])
+	}
+	if _, err := wm.SignMessage(ctx, accounts[1].Address, "hello"); err != nil {
+		t.Errorf("expected imported account to sign: %v", err)
+	}
+
+	if _, err := wm.ImportAccounts(ctx, "ethereum", 0); err == nil {
+		t.Error("expected a zero count to be rejected")
+	}
+	if _, err := wm.ImportAccounts(ctx, "ethereum", wm.accountDiscovery.MaxAccounts+1); err == nil {
+		t.Error("expected a count above max_accounts to be rejected")
+	}
+
+	// Importing again does not duplicate persisted accounts
+	if _, err := wm.ImportAccounts(ctx, "ethereum", 2); err != nil {
+		t.Fatalf("re-import failed: %v", err)
+	}
+	stored, err := wm.loadWallet()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(stored.Accounts) != 2 {
+		t.Errorf("expected two persisted accounts, got %d", len(stored.Accounts))
+	}
+}
@@ -0,0 +1,339 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+)
+
+// Address book categories with a conventional meaning. Other categories are
+// accepted as well; what sending to a category does is configured in
+// wallet.address_book.
+const (
+	AddressCategoryOwn          = "own"          // the user's own wallets, e.g. a cold wallet
+	AddressCategoryExchange     = "exchange"     // exchange deposit addresses
+	AddressCategoryCounterparty = "counterparty" // people and businesses the user pays
+	AddressCategoryScam         = "scam"         // known scam or phishing addresses
+)
+
+// Address book policies, applied to sends by the category of the recipient
+const (
+	AddressPolicyBlock = "block" // sends are refused
+	AddressPolicyWarn  = "warn"  // sends need explicit confirmation
+)
+
+// Limits on address book fields, in characters
+const (
+	MaxAddressLabelLength    = 64
+	MaxAddressCategoryLength = 32
+	MaxAddressNotesLength    = 500
+)
+
+var (
+	// ErrAddressBookEntryNotFound is returned for an address without an entry
+	ErrAddressBookEntryNotFound = errors.New("address book entry not found")
+	// ErrInvalidAddressBookEntry is returned for entries the address book does not accept
+	ErrInvalidAddressBookEntry = errors.New("invalid address book entry")
+	// ErrRecipientBlocked is returned when sending to an address whose address
+	// book category is blocked
+	ErrRecipientBlocked = errors.New("recipient is blocked by the address book")
+)
+
+var addressCategoryPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// AddressBookEntry names an address on one chain
+type AddressBookEntry struct {
+	Chain     string    `json:"chain"`
+	Address   string    `json:"address"`
+	Label     string    `json:"label"`
+	Category  string    `json:"category,omitempty"`
+	Notes     string    `json:"notes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Display returns the label with its category, e.g. "Binance (exchange)"
+func (e *AddressBookEntry) Display() string {
+	if e.Category == "" {
+		return e.Label
+	}
+	return fmt.Sprintf("%s (%s)", e.Label, e.Category)
+}
+
+// AddressBook keeps named addresses per chain in storage.NamespaceAddressBook
+// and the policy applied to sends by category
+type AddressBook struct {
+	mu       sync.RWMutex
+	entries  map[string]*AddressBookEntry // by addressBookKey
+	store    storage.StateStore
+	policies map[string]string // category -> AddressPolicyBlock or AddressPolicyWarn
+	now      func() time.Time
+}
+
+// NewAddressBook creates an address book persisting to store with the default
+// policies. A nil store keeps entries in memory.
+func NewAddressBook(store storage.StateStore) *AddressBook {
+	book := &AddressBook{
+		entries: make(map[string]*AddressBookEntry),
+		store:   store,
+		now:     time.Now,
+	}
+	book.SetPolicies(config.DefaultConfig().Wallet.AddressBook)
+	return book
+}
+
+// SetPolicies replaces the category policies. A category listed both as
+// blocked and as warned is blocked.
+func (b *AddressBook) SetPolicies(cfg config.AddressBookConfig) {
+	policies := make(map[string]string)
+	for _, category := range cfg.WarnCategories {
+		policies[normalizeAddressCategory(category)] = AddressPolicyWarn
+	}
+	for _, category := range cfg.BlockedCategories {
+		policies[normalizeAddressCategory(category)] = AddressPolicyBlock
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.policies = policies
+}
+
+// Policy returns the policy applied to sends to category, or "" when sends
+// are unrestricted
+func (b *AddressBook) Policy(category string) string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.policies[normalizeAddressCategory(category)]
+}
+
+// Load restores persisted entries
+func (b *AddressBook) Load(ctx context.Context) error {
+	if b.store == nil {
+		return nil
+	}
+	keys, err := b.store.List(ctx, storage.NamespaceAddressBook)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, key := range keys {
+		data, err := b.store.Get(ctx, storage.NamespaceAddressBook, key)
+		if err != nil {
+			return err
+		}
+		var entry AddressBookEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return fmt.Errorf("failed to parse address book entry %s: %w", key, err)
+		}
+		b.entries[addressBookKey(entry.Chain, entry.Address)] = &entry
+	}
+	return nil
+}
+
+// Put adds an entry or replaces the entry for the same chain and address, and
+// returns a copy of what was stored
+func (b *AddressBook) Put(ctx context.Context, entry *AddressBookEntry) (*AddressBookEntry, error) {
+	stored := AddressBookEntry{
+		Chain:    NormalizeChain(entry.Chain),
+		Address:  strings.TrimSpace(entry.Address),
+		Label:    strings.TrimSpace(entry.Label),
+		Category: normalizeAddressCategory(entry.Category),
+		Notes:    strings.TrimSpace(entry.Notes),
+	}
+	if err := validateAddressBookEntry(&stored); err != nil {
+		return nil, err
+	}
+
+	key := addressBookKey(stored.Chain, stored.Address)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	stored.CreatedAt, stored.UpdatedAt = now, now
+	if existing, ok := b.entries[key]; ok {
+		stored.CreatedAt = existing.CreatedAt
+	}
+	if err := b.persistLocked(ctx, key, &stored); err != nil {
+		return nil, err
+	}
+	b.entries[key] = &stored
+	result := stored
+	return &result, nil
+}
+
+func validateAddressBookEntry(entry *AddressBookEntry) error {
+	if entry.Chain == "" || entry.Address == "" {
+		return fmt.Errorf("%w: chain and address are required", ErrInvalidAddressBookEntry)
+	}
+	if entry.Label == "" {
+		return fmt.Errorf("%w: label is required", ErrInvalidAddressBookEntry)
+	}
+	if len([]rune(entry.Label)) > MaxAddressLabelLength {
+		return fmt.Errorf("%w: label must be at most %d characters", ErrInvalidAddressBookEntry, MaxAddressLabelLength)
+	}
+	if entry.Category != "" && (len(entry.Category) > MaxAddressCategoryLength || !addressCategoryPattern.MatchString(entry.Category)) {
+		return fmt.Errorf("%w: category must be at most %d lowercase letters, digits, '-' or '_'", ErrInvalidAddressBookEntry, MaxAddressCategoryLength)
+	}
+	if len([]rune(entry.Notes)) > MaxAddressNotesLength {
+		return fmt.Errorf("%w: notes must be at most %d characters", ErrInvalidAddressBookEntry, MaxAddressNotesLength)
+	}
+	for _, r := range entry.Label + entry.Notes {
+		if r < 0x20 && r != '\n' && r != '\t' {
+			return fmt.Errorf("%w: label and notes must not contain control characters", ErrInvalidAddressBookEntry)
+		}
+	}
+	return nil
+}
+
+// Remove deletes the entry for address on chainName and returns it
+func (b *AddressBook) Remove(ctx context.Context, chainName, address string) (*AddressBookEntry, error) {
+	key := addressBookKey(chainName, address)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	entry, ok := b.entries[key]
+	if !ok {
+		return nil, ErrAddressBookEntryNotFound
+	}
+	if b.store != nil {
+		if err := b.store.Delete(ctx, storage.NamespaceAddressBook, key); err != nil {
+			return nil, fmt.Errorf("failed to delete address book entry: %w", err)
+		}
+	}
+	delete(b.entries, key)
+	return entry, nil
+}
+
+// Lookup returns a copy of the entry for address on chainName
+func (b *AddressBook) Lookup(chainName, address string) (*AddressBookEntry, bool) {
+	if address == "" {
+		return nil, false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	entry, ok := b.entries[addressBookKey(chainName, address)]
+	if !ok {
+		return nil, false
+	}
+	result := *entry
+	return &result, true
+}
+
+// List returns copies of the entries on chainName in category, ordered by
+// chain and label. Empty filters match every entry.
+func (b *AddressBook) List(chainName, category string) []*AddressBookEntry {
+	chainName = NormalizeChain(chainName)
+	category = normalizeAddressCategory(category)
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	result := make([]*AddressBookEntry, 0, len(b.entries))
+	for _, entry := range b.entries {
+		if (chainName != "" && entry.Chain != chainName) || (category != "" && entry.Category != category) {
+			continue
+		}
+		copied := *entry
+		result = append(result, &copied)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Chain != result[j].Chain {
+			return result[i].Chain < result[j].Chain
+		}
+		if !strings.EqualFold(result[i].Label, result[j].Label) {
+			return strings.ToLower(result[i].Label) < strings.ToLower(result[j].Label)
+		}
+		return result[i].Address < result[j].Address
+	})
+	return result
+}
+
+// label returns the display label of address on chainName, or ""
+func (b *AddressBook) label(chainName, address string) string {
+	if entry, ok := b.Lookup(chainName, address); ok {
+		return entry.Display()
+	}
+	return ""
+}
+
+// checkRecipient refuses sends to an address in a blocked category
+func (b *AddressBook) checkRecipient(chainName, address string) error {
+	entry, ok := b.Lookup(chainName, address)
+	if !ok || b.Policy(entry.Category) != AddressPolicyBlock {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is listed as %s", ErrRecipientBlocked, address, entry.Display())
+}
+
+func (b *AddressBook) persistLocked(ctx context.Context, key string, entry *AddressBookEntry) error {
+	if b.store == nil {
+		return nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal address book entry: %w", err)
+	}
+	if err := b.store.Put(ctx, storage.NamespaceAddressBook, key, data); err != nil {
+		return fmt.Errorf("failed to persist address book entry: %w", err)
+	}
+	return nil
+}
+
+// addressBookKey identifies an address on a chain. EVM addresses are case
+// insensitive; Solana addresses are not.
+func addressBookKey(chainName, address string) string {
+	chainName = NormalizeChain(chainName)
+	address = strings.TrimSpace(address)
+	if chainName != "solana" {
+		address = strings.ToLower(address)
+	}
+	return chainName + "_" + address
+}
+
+func normalizeAddressCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+// AddAddressBookEntry names an address, replacing any entry it already has on
+// that chain
+func (wm *WalletManager) AddAddressBookEntry(ctx context.Context, entry *AddressBookEntry) (*AddressBookEntry, error) {
+	if !wm.isValidAddress(entry.Chain, strings.TrimSpace(entry.Address)) {
+		return nil, fmt.Errorf("%w: invalid %s address", ErrInvalidAddressBookEntry, NormalizeChain(entry.Chain))
+	}
+	return wm.addressBook.Put(ctx, entry)
+}
+
+// RemoveAddressBookEntry deletes the address book entry of address on chainName
+func (wm *WalletManager) RemoveAddressBookEntry(ctx context.Context, chainName, address string) (*AddressBookEntry, error) {
+	return wm.addressBook.Remove(ctx, chainName, address)
+}
+
+// ListAddressBook returns the address book entries on chainName in category;
+// empty filters match every entry
+func (wm *WalletManager) ListAddressBook(ctx context.Context, chainName, category string) ([]*AddressBookEntry, error) {
+	return wm.addressBook.List(chainName, category), nil
+}
+
+// LookupAddressBook returns the address book entry of address on chainName
+func (wm *WalletManager) LookupAddressBook(chainName, address string) (*AddressBookEntry, bool) {
+	return wm.addressBook.Lookup(chainName, address)
+}
+
+// AddressBookPolicy returns the policy applied to sends to category, or ""
+func (wm *WalletManager) AddressBookPolicy(category string) string {
+	return wm.addressBook.Policy(category)
+}
+
+// CheckRecipient returns an ErrRecipientBlocked error when the address book
+// files address on chainName under a blocked category
+func (wm *WalletManager) CheckRecipient(chainName, address string) error {
+	return wm.addressBook.checkRecipient(chainName, address)
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+)
+
+const (
+	bookOwnAddress  = "0x742D35Cc6634c0532925a3B8D4C2B79c2b86A7a8"
+	bookScamAddress = "0x1111111111111111111111111111111111111111"
+)
+
+func TestAddressBookPersistsEntriesPerChain(t *testing.T) {
+	ctx := context.Background()
+	backing := storage.NewMemoryStateStore()
+	book := NewAddressBook(backing)
+
+	entry, err := book.Put(ctx, &AddressBookEntry{Chain: "ETH", Address: bookOwnAddress, Label: " Cold wallet ", Category: "Own"})
+	if err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if entry.Chain != "ethereum" || entry.Label != "Cold wallet" || entry.Category != AddressCategoryOwn {
+		t.Fatalf("entry not normalized: %+v", entry)
+	}
+	if _, err := book.Put(ctx, &AddressBookEntry{Chain: "bsc", Address: bookOwnAddress, Label: "BSC hot wallet"}); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+
+	// EVM addresses match whatever their case
+	if got, ok := book.Lookup("ethereum", strings.ToLower(bookOwnAddress)); !ok || got.Display() != "Cold wallet (own)" {
+		t.Errorf("lookup by lowercase address failed: %+v", got)
+	}
+
+	// Updating keeps the creation time
+	updated, err := book.Put(ctx, &AddressBookEntry{Chain: "ethereum", Address: bookOwnAddress, Label: "Ledger", Category: "own", Notes: "in the safe"})
+	if err != nil || !updated.CreatedAt.Equal(entry.CreatedAt) || updated.Notes != "in the safe" {
+		t.Errorf("update did not keep the entry: %+v, %v", updated, err)
+	}
+
+	restored := NewAddressBook(backing)
+	if err := restored.Load(ctx); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if entries := restored.List("", ""); len(entries) != 2 || entries[0].Chain != "bsc" || entries[1].Label != "Ledger" {
+		t.Errorf("unexpected entries after restart: %+v", entries)
+	}
+	if entries := restored.List("eth", "own"); len(entries) != 1 {
+		t.Errorf("expected one own entry on ethereum, got %d", len(entries))
+	}
+
+	if _, err := restored.Remove(ctx, "bsc", bookOwnAddress); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if _, err := restored.Remove(ctx, "bsc", bookOwnAddress); !errors.Is(err, ErrAddressBookEntryNotFound) {
+		t.Errorf("expected ErrAddressBookEntryNotFound, got %v", err)
+	}
+}
+
+func TestAddressBookValidation(t *testing.T) {
+	book := NewAddressBook(nil)
+	cases := map[string]*AddressBookEntry{
+		"missing label":   {Chain: "ethereum", Address: bookOwnAddress},
+		"long label":      {Chain: "ethereum", Address: bookOwnAddress, Label: strings.Repeat("a", MaxAddressLabelLength+1)},
+		"bad category":    {Chain: "ethereum", Address: bookOwnAddress, Label: "x", Category: "cold wallet"},
+		"control chars":   {Chain: "ethereum", Address: bookOwnAddress, Label: "x\x1b[31m"},
+		"missing chain":   {Address: bookOwnAddress, Label: "x"},
+		"missing address": {Chain: "ethereum", Label: "x"},
+	}
+	for name, entry := range cases {
+		if _, err := book.Put(context.Background(), entry); !errors.Is(err, ErrInvalidAddressBookEntry) {
+			t.Errorf("%s: expected ErrInvalidAddressBookEntry, got %v", name, err)
+		}
+	}
+
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	if _, err := wm.AddAddressBookEntry(context.Background(), &AddressBookEntry{Chain: "ethereum", Address: "0x1234", Label: "x"}); !errors.Is(err, ErrInvalidAddressBookEntry) {
+		t.Errorf("expected an invalid address to be rejected, got %v", err)
+	}
+}
+
+func TestAddressBookBlocksSendsToBlockedCategories(t *testing.T) {
+	ctx := context.Background()
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	if _, err := wm.AddAddressBookEntry(ctx, &AddressBookEntry{Chain: "ethereum", Address: bookScamAddress, Label: "Fake airdrop", Category: AddressCategoryScam}); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	err := wm.validateTransactionSecurity("ethereum", bookOwnAddress, bookScamAddress, "1", "")
+	if !errors.Is(err, ErrRecipientBlocked) {
+		t.Fatalf("expected ErrRecipientBlocked, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Fake airdrop (scam)") {
+		t.Errorf("error should name the entry: %v", err)
+	}
+	// The entry is per chain
+	if err := wm.validateTransactionSecurity("bsc", bookOwnAddress, bookScamAddress, "1", ""); err != nil {
+		t.Errorf("entry on ethereum should not block bsc sends: %v", err)
+	}
+	// Approval paths that skip the transfer checks ask for the recipient alone
+	if err := wm.CheckRecipient("eth", bookScamAddress); !errors.Is(err, ErrRecipientBlocked) {
+		t.Errorf("expected CheckRecipient to block the entry, got %v", err)
+	}
+
+	// Policies come from wallet.address_book
+	wm.addressBook.SetPolicies(config.AddressBookConfig{WarnCategories: []string{"scam"}})
+	if wm.AddressBookPolicy("scam") != AddressPolicyWarn {
+		t.Errorf("expected scam to be warned about, got %q", wm.AddressBookPolicy("scam"))
+	}
+	if err := wm.validateTransactionSecurity("ethereum", bookOwnAddress, bookScamAddress, "1", ""); err != nil {
+		t.Errorf("warned categories must not block: %v", err)
+	}
+}
+
+func TestAddressBookLabelsHistoryAndPending(t *testing.T) {
+	ctx := context.Background()
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	if _, err := wm.AddAddressBookEntry(ctx, &AddressBookEntry{Chain: "ethereum", Address: strings.ToLower(bookOwnAddress), Label: "Treasury", Category: AddressCategoryOwn}); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	history, err := wm.GetTransactionHistory(ctx, bookOwnAddress, nil, nil, 10, 0)
+	if err != nil || len(history) == 0 {
+		t.Fatalf("history failed: %v", err)
+	}
+	if history[0].FromLabel != "Treasury (own)" || history[0].ToLabel != "" {
+		t.Errorf("history not labelled: from=%q to=%q", history[0].FromLabel, history[0].ToLabel)
+	}
+
+	if err := wm.pending.Add(ctx, &PendingTransaction{Hash: "0xlabelled", Chain: "ethereum", From: bookScamAddress, To: bookOwnAddress, Amount: "1", Status: "pending"}); err != nil {
+		t.Fatalf("failed to add pending transaction: %v", err)
+	}
+	pending, err := wm.GetPendingTransactions(ctx, "ethereum", bookScamAddress, "", 10, 0)
+	if err != nil || len(pending) != 1 || pending[0].ToLabel != "Treasury (own)" {
+		t.Fatalf("pending transaction not labelled: %+v, %v", pending, err)
+	}
+	if stored, _ := wm.pending.Get("0xlabelled"); stored.ToLabel != "" {
+		t.Errorf("labels must not be written to the pending store")
+	}
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mr-tron/base58"
+)
+
+var (
+	// ErrAddressLength is returned for an address of the wrong size for its chain
+	ErrAddressLength = errors.New("bad address length")
+	// ErrAddressChecksum is returned for a mixed-case EVM address whose casing
+	// does not match its EIP-55 checksum, usually a mistyped character
+	ErrAddressChecksum = errors.New("bad address checksum")
+	// ErrNotHexAddress is returned for an EVM address that is not 0x-prefixed hex
+	ErrNotHexAddress = errors.New("not a hex address")
+	// ErrNotBase58Address is returned for a Solana address that is not base58
+	ErrNotBase58Address = errors.New("not a base58 address")
+)
+
+// evmAddressLength is the length of a 0x-prefixed 20-byte address
+const evmAddressLength = 2 + 2*common.AddressLength
+
+// validateAddress checks that address is well formed for chainName
+func validateAddress(chainName, address string) error {
+	switch NormalizeChain(chainName) {
+	case "ethereum", "bsc":
+		return validateEVMAddress(address)
+	case "solana":
+		return validateSolanaAddress(address)
+	default:
+		return fmt.Errorf("unsupported chain: %s", chainName)
+	}
+}
+
+// validateEVMAddress accepts all-lowercase and all-uppercase hex addresses as
+// well as mixed-case ones matching their EIP-55 checksum
+func validateEVMAddress(address string) error {
+	if !strings.HasPrefix(address, "0x") {
+		return fmt.Errorf("%w: %q is missing the 0x prefix", ErrNotHexAddress, address)
+	}
+	if len(address) != evmAddressLength {
+		return fmt.Errorf("%w: %d characters, want %d", ErrAddressLength, len(address), evmAddressLength)
+	}
+	if !common.IsHexAddress(address) {
+		return fmt.Errorf("%w: %q has non-hex characters", ErrNotHexAddress, address)
+	}
+	digits := address[2:]
+	if digits == strings.ToLower(digits) || digits == strings.ToUpper(digits) {
+		return nil
+	}
+	if checksummed := common.HexToAddress(address).Hex(); checksummed != address {
+		return fmt.Errorf("%w: %s, expected %s", ErrAddressChecksum, address, checksummed)
+	}
+	return nil
+}
+
+// validateSolanaAddress accepts base58 encoded 32-byte public keys
+func validateSolanaAddress(address string) error {
+	if address == "" {
+		return fmt.Errorf("%w: empty address", ErrNotBase58Address)
+	}
+	decoded, err := base58.Decode(address)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrNotBase58Address, address)
+	}
+	if len(decoded) != 32 {
+		return fmt.Errorf("%w: %d bytes, want 32", ErrAddressLength, len(decoded))
+	}
+	return nil
+}
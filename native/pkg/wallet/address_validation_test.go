@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		chain   string
+		address string
+		wantErr error
+	}{
+		{name: "checksummed", chain: "ethereum", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{name: "checksummed with uppercase digits", chain: "ethereum", address: "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"},
+		{name: "checksummed on bsc", chain: "bsc", address: "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB"},
+		{name: "checksummed with leading uppercase", chain: "eth", address: "0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb"},
+		{name: "all lowercase", chain: "ethereum", address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+		{name: "all uppercase", chain: "ethereum", address: "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED"},
+		{name: "one character miscased", chain: "ethereum", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", wantErr: ErrAddressChecksum},
+		{name: "wrong checksum", chain: "bsc", address: "0xFB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", wantErr: ErrAddressChecksum},
+		{name: "too short", chain: "ethereum", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", wantErr: ErrAddressLength},
+		{name: "too long", chain: "ethereum", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed00", wantErr: ErrAddressLength},
+		{name: "missing prefix", chain: "ethereum", address: "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed00", wantErr: ErrNotHexAddress},
+		{name: "non-hex characters", chain: "ethereum", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg", wantErr: ErrNotHexAddress},
+		{name: "solana address", chain: "solana", address: "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"},
+		{name: "solana system program", chain: "sol", address: "11111111111111111111111111111111"},
+		{name: "evm address on solana", chain: "solana", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", wantErr: ErrNotBase58Address},
+		{name: "short solana key", chain: "solana", address: "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL", wantErr: ErrAddressLength},
+		{name: "empty solana address", chain: "solana", address: "", wantErr: ErrNotBase58Address},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAddress(tt.chain, tt.address)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("validateAddress(%q, %q) = %v, want nil", tt.chain, tt.address, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateAddress(%q, %q) = %v, want %v", tt.chain, tt.address, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateAddressUnsupportedChain(t *testing.T) {
+	if err := validateAddress("bitcoin", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"); err == nil {
+		t.Fatal("expected an error for an unsupported chain")
+	}
+}
+
+func TestValidateTransactionSecurityRejectsBadChecksum(t *testing.T) {
+	wm := NewWalletManager()
+	err := wm.validateTransactionSecurity("ethereum",
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d35A",
+		"1", "")
+	if !errors.Is(err, ErrAddressChecksum) {
+		t.Fatalf("validateTransactionSecurity() = %v, want %v", err, ErrAddressChecksum)
+	}
+}
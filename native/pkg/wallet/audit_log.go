@@ -2,9 +2,16 @@
 package wallet
 
 import (
-	"time"
+	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
 )
 
 // AuditLogEntry represents an entry in the audit log
@@ -17,21 +24,58 @@ type AuditLogEntry struct {
 	Timestamp    time.Time `json:"timestamp"`
 	Source       string    `json:"source"`         // "ai_agent", "user", "system"
 	WalletAddress string   `json:"wallet_address,omitempty"`
+	// Transaction sent, on spend entries
+	Chain        string    `json:"chain,omitempty"`
+	Token        string    `json:"token,omitempty"`
+	Amount       string    `json:"amount,omitempty"`
 }
 
 // AuditLogger handles audit logging operations
 type AuditLogger struct {
-	// In a real implementation, this would connect to a database or external logging system
+	mu      sync.RWMutex
 	entries []AuditLogEntry
+	// store persists entries across restarts; nil keeps the log in memory only
+	store storage.StateStore
 }
 
-// NewAuditLogger creates a new audit logger
+// NewAuditLogger creates a new in-memory audit logger
 func NewAuditLogger() *AuditLogger {
 	return &AuditLogger{
 		entries: make([]AuditLogEntry, 0),
 	}
 }
 
+// NewAuditLoggerWithStore creates an audit logger that persists entries to store
+// and reloads previously recorded entries in timestamp order.
+func NewAuditLoggerWithStore(store storage.StateStore) (*AuditLogger, error) {
+	al := NewAuditLogger()
+	al.store = store
+	if store == nil {
+		return al, nil
+	}
+
+	ctx := context.Background()
+	ids, err := store.List(ctx, storage.NamespaceAudit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	for _, id := range ids {
+		data, err := store.Get(ctx, storage.NamespaceAudit, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit entry %s: %w", id, err)
+		}
+		var entry AuditLogEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse audit entry %s: %w", id, err)
+		}
+		al.entries = append(al.entries, entry)
+	}
+	sort.SliceStable(al.entries, func(i, j int) bool {
+		return al.entries[i].Timestamp.Before(al.entries[j].Timestamp)
+	})
+	return al, nil
+}
+
 // LogTransactionRejection logs a transaction rejection event
 func (al *AuditLogger) LogTransactionRejection(transactionHash, reason, details, walletAddress string) (string, error) {
 	id, err := generateAuditLogID()
@@ -49,15 +93,122 @@ func (al *AuditLogger) LogTransactionRejection(transactionHash, reason, details,
 		Source:        "ai_agent",
 		WalletAddress: walletAddress,
 	}
+	if err := al.record(entry); err != nil {
+		return "", err
+	}
+	return id, nil
+}
 
-	// In a real implementation, this would be persisted to a database
-	al.entries = append(al.entries, entry)
-	
+// LogTransactionAutoApproval logs a transaction sent without explicit approval
+// because it matched the auto-approval rule named rule
+func (al *AuditLogger) LogTransactionAutoApproval(transactionHash, rule, details, walletAddress string) (string, error) {
+	id, err := generateAuditLogID()
+	if err != nil {
+		return "", err
+	}
+
+	entry := AuditLogEntry{
+		ID:            id,
+		Action:        AuditActionTransactionAutoApproval,
+		Subject:       transactionHash,
+		Details:       details,
+		Reason:        rule,
+		Timestamp:     time.Now().UTC(),
+		Source:        "system",
+		WalletAddress: walletAddress,
+	}
+	if err := al.record(entry); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// LogSecurityEvent logs a wallet security action such as a freeze, performed by source
+func (al *AuditLogger) LogSecurityEvent(action, reason, details, source, walletAddress string) (string, error) {
+	id, err := generateAuditLogID()
+	if err != nil {
+		return "", err
+	}
+
+	entry := AuditLogEntry{
+		ID:            id,
+		Action:        action,
+		Subject:       walletAddress,
+		Details:       details,
+		Reason:        reason,
+		Timestamp:     time.Now().UTC(),
+		Source:        source,
+		WalletAddress: walletAddress,
+	}
+	if err := al.record(entry); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// LogSpend logs an outgoing transaction of amount token (empty for the native
+// coin) on chain, which spend limits count towards the daily total
+func (al *AuditLogger) LogSpend(transactionHash, chain, token, amount, walletAddress string) (string, error) {
+	id, err := generateAuditLogID()
+	if err != nil {
+		return "", err
+	}
+
+	entry := AuditLogEntry{
+		ID:            id,
+		Action:        AuditActionSpend,
+		Subject:       transactionHash,
+		Details:       fmt.Sprintf("sent %s %s on %s", amount, spendTokenName(chain, token), chain),
+		Timestamp:     time.Now().UTC(),
+		Source:        "system",
+		WalletAddress: walletAddress,
+		Chain:         chain,
+		Token:         token,
+		Amount:        amount,
+	}
+	if err := al.record(entry); err != nil {
+		return "", err
+	}
 	return id, nil
 }
 
+// record persists entry, when a store is configured, and appends it to the log
+func (al *AuditLogger) record(entry AuditLogEntry) error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	if al.store != nil {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit entry: %w", err)
+		}
+		if err := al.store.Put(context.Background(), storage.NamespaceAudit, entry.ID, data); err != nil {
+			return fmt.Errorf("failed to persist audit entry: %w", err)
+		}
+	}
+	al.entries = append(al.entries, entry)
+	return nil
+}
+
+// GetAuditLogByAction retrieves audit log entries recorded for the given action
+func (al *AuditLogger) GetAuditLogByAction(action string) []AuditLogEntry {
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+
+	matches := make([]AuditLogEntry, 0)
+	for _, entry := range al.entries {
+		if entry.Action == action {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
+}
+
 // GetAuditLog retrieves audit log entries
 func (al *AuditLogger) GetAuditLog(limit int, offset int) ([]AuditLogEntry, error) {
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+
 	if offset >= len(al.entries) {
 		return []AuditLogEntry{}, nil
 	}
@@ -67,7 +218,21 @@ func (al *AuditLogger) GetAuditLog(limit int, offset int) ([]AuditLogEntry, erro
 		end = len(al.entries)
 	}
 
-	return al.entries[offset:end], nil
+	return append([]AuditLogEntry(nil), al.entries[offset:end]...), nil
+}
+
+// GetAuditLogByReason retrieves audit log entries recorded with the given reason
+func (al *AuditLogger) GetAuditLogByReason(reason RejectionReason) []AuditLogEntry {
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+
+	matches := make([]AuditLogEntry, 0)
+	for _, entry := range al.entries {
+		if entry.Reason == string(reason) {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
 }
 
 // generateAuditLogID generates a unique audit log ID
@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"go.uber.org/zap"
+)
+
+// AuditActionTransactionAutoApproval is recorded for every auto-approved transaction
+const AuditActionTransactionAutoApproval = "transaction_auto_approval"
+
+// autoApprovalTransferMethod is the rule method matching plain value transfers
+// without calldata, the only transactions auto-approval sends
+const autoApprovalTransferMethod = "transfer"
+
+// AutoApprovalRequest is a dApp transaction considered for auto-approval.
+// Value is the hex wei amount and Data the hex calldata, as sent by dApps.
+type AutoApprovalRequest struct {
+	Chain  string
+	From   string
+	To     string
+	Value  string
+	Data   string
+	Origin string
+}
+
+// NativeAmount returns Value in native units, e.g. "0.01" for 0x2386f26fc10000 wei
+func (r AutoApprovalRequest) NativeAmount() string {
+	return formatUnits(parseHexBig(r.Value), 18)
+}
+
+// MatchAutoApprovalRule returns the first rule of policy that req satisfies.
+// Nothing matches while the policy is disabled. Auto-approved transactions are
+// sent as plain value transfers, so requests carrying calldata always need
+// explicit approval, as do transactions the intent decoder rates above low risk.
+func MatchAutoApprovalRule(policy config.AutoApprovalPolicy, req AutoApprovalRequest) (*config.AutoApprovalRule, bool) {
+	if !policy.Enabled || hasCalldata(req.Data) {
+		return nil, false
+	}
+	intent := DecodeTransactionIntent(req.Chain, req.To, req.Value, req.Data)
+	if intent.RiskLevel != RiskLevelLow {
+		return nil, false
+	}
+	for i := range policy.Rules {
+		if autoApprovalRuleMatches(&policy.Rules[i], req) {
+			return &policy.Rules[i], true
+		}
+	}
+	return nil, false
+}
+
+// autoApprovalRuleMatches reports whether every condition set on rule holds for req
+func autoApprovalRuleMatches(rule *config.AutoApprovalRule, req AutoApprovalRequest) bool {
+	if len(rule.To) > 0 && !containsFold(rule.To, req.To) {
+		return false
+	}
+	if rule.MaxValue > 0 {
+		// Parsed from the shortest decimal form so 0.01 means exactly 0.01 ETH
+		limit, ok := new(big.Rat).SetString(strconv.FormatFloat(rule.MaxValue, 'f', -1, 64))
+		value := new(big.Rat).SetFrac(parseHexBig(req.Value), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+		if !ok || value.Cmp(limit) >= 0 {
+			return false
+		}
+	}
+	if len(rule.Origins) > 0 {
+		origin := normalizeOrigin(req.Origin)
+		matched := false
+		for _, trusted := range rule.Origins {
+			if origin != "" && normalizeOrigin(trusted) == origin {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(rule.Methods) > 0 && !containsFold(rule.Methods, autoApprovalTransferMethod) {
+		return false
+	}
+	return true
+}
+
+// hasCalldata reports whether data, the hex calldata of a dApp transaction,
+// carries any bytes
+func hasCalldata(data string) bool {
+	data = strings.TrimSpace(data)
+	return strings.TrimPrefix(strings.TrimPrefix(data, "0x"), "0X") != ""
+}
+
+// normalizeOrigin reduces an origin to lowercase scheme://host[:port] so that
+// paths and trailing slashes do not affect matching
+func normalizeOrigin(origin string) string {
+	origin = strings.TrimSpace(origin)
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return strings.TrimSuffix(strings.ToLower(origin), "/")
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host)
+}
+
+func containsFold(values []string, value string) bool {
+	value = strings.TrimSpace(value)
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// AutoApproveTransaction sends tx, which matched the auto-approval rule named
+// rule, without queueing it for approval and records the send in the audit log.
+// tx.Amount is in native units. The usual lock, freeze and duplicate checks of
+// SendTransaction apply.
+func (wm *WalletManager) AutoApproveTransaction(ctx context.Context, tx *PendingTransaction, rule, origin string) (string, error) {
+	txHash, err := wm.SendTransaction(ctx, tx.Chain, tx.From, tx.To, tx.Amount, tx.Token)
+	if err != nil {
+		return "", err
+	}
+
+	details := fmt.Sprintf("%s %s to %s on %s", tx.Amount, tx.Token, tx.To, tx.Chain)
+	if origin != "" {
+		details += " requested by " + origin
+	}
+	if _, err := wm.auditLogger.LogTransactionAutoApproval(txHash, rule, details, tx.From); err != nil {
+		wm.logger.Error("Failed to audit auto-approved transaction", zap.String("tx_hash", txHash), zap.Error(err))
+	}
+	wm.logger.Info("Transaction auto-approved",
+		zap.String("tx_hash", txHash),
+		zap.String("rule", rule),
+		zap.String("origin", origin))
+	return txHash, nil
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+func TestMatchAutoApprovalRule(t *testing.T) {
+	const (
+		router    = "0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D"
+		recipient = "0x2F62f2B4c5fcd7570a709DeC05D68EA19c82A9ec"
+		// transfer(recipient, 1) and approve(router, max uint256)
+		transferData  = "0xa9059cbb0000000000000000000000002f62f2b4c5fcd7570a709dec05d68ea19c82a9ec0000000000000000000000000000000000000000000000000000000000000001"
+		unlimitedData = "0x095ea7b30000000000000000000000007a250d5630b4cf539739df2c5dacb4c659f2488dffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+	)
+	policy := config.AutoApprovalPolicy{
+		Enabled: true,
+		Rules: []config.AutoApprovalRule{
+			{Name: "savings", To: []string{recipient}},
+			{Name: "small", MaxValue: 0.01, Origins: []string{"https://app.uniswap.org/"}, Methods: []string{"transfer"}},
+		},
+	}
+
+	cases := []struct {
+		name string
+		req  AutoApprovalRequest
+		rule string
+	}{
+		{"allowlisted recipient", AutoApprovalRequest{To: "0x2f62f2b4c5fcd7570a709dec05d68ea19c82a9ec", Value: "0xde0b6b3a7640000"}, "savings"},
+		{"small transfer from trusted origin", AutoApprovalRequest{To: router, Value: "0x38d7ea4c68000", Origin: "https://APP.uniswap.org/#/swap"}, "small"},
+		{"value at the limit", AutoApprovalRequest{To: router, Value: "0x2386f26fc10000", Origin: "https://app.uniswap.org"}, ""},
+		{"untrusted origin", AutoApprovalRequest{To: router, Value: "0x1", Origin: "https://app.uniswap.org.evil.io"}, ""},
+		{"missing origin", AutoApprovalRequest{To: router, Value: "0x1"}, ""},
+		// Auto-approval sends value only, so anything with calldata needs approval
+		{"token transfer", AutoApprovalRequest{To: router, Data: transferData, Origin: "https://app.uniswap.org"}, ""},
+		{"calldata to allowlisted address", AutoApprovalRequest{To: recipient, Data: "0xdeadbeef"}, ""},
+		{"calldata shorter than a selector", AutoApprovalRequest{To: recipient, Data: "0x01"}, ""},
+		{"empty calldata", AutoApprovalRequest{To: recipient, Data: "0x"}, "savings"},
+		{"high risk to allowlisted address", AutoApprovalRequest{To: recipient, Data: unlimitedData}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.req.Chain = "ethereum"
+			rule, ok := MatchAutoApprovalRule(policy, tc.req)
+			if tc.rule == "" {
+				if ok {
+					t.Errorf("expected no match, matched %q", rule.Name)
+				}
+				return
+			}
+			if !ok || rule.Name != tc.rule {
+				t.Errorf("expected rule %q, got %v (matched=%t)", tc.rule, rule, ok)
+			}
+		})
+	}
+
+	policy.Enabled = false
+	if _, ok := MatchAutoApprovalRule(policy, AutoApprovalRequest{Chain: "ethereum", To: recipient}); ok {
+		t.Error("a disabled policy must not match")
+	}
+}
+
+func TestAutoApprovalRequestNativeAmount(t *testing.T) {
+	if got := (AutoApprovalRequest{Value: "0x2386f26fc10000"}).NativeAmount(); got != "0.01" {
+		t.Errorf("expected 0.01, got %s", got)
+	}
+	if got := (AutoApprovalRequest{}).NativeAmount(); got != "0" {
+		t.Errorf("expected 0 for a missing value, got %s", got)
+	}
+}
+
+func TestWalletManagerAutoApproveTransactionAudits(t *testing.T) {
+	ctx := context.Background()
+	from := "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK"
+	to := "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY"
+
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	unlockForTest(wm, from)
+	sender := &countingSendChain{SolanaChain: chain.NewSolanaChainLegacy()}
+	wm.chainFactory.RegisterChain("solana", sender)
+
+	tx := &PendingTransaction{Chain: "solana", From: from, To: to, Amount: "0.01", Token: "SOL"}
+	txHash, err := wm.AutoApproveTransaction(ctx, tx, "small", "https://jup.ag")
+	if err != nil {
+		t.Fatalf("auto-approval failed: %v", err)
+	}
+	if txHash != "tx-1" {
+		t.Errorf("expected the broadcast hash, got %s", txHash)
+	}
+
+	entries := wm.auditLogger.GetAuditLogByAction(AuditActionTransactionAutoApproval)
+	if len(entries) != 1 {
+		t.Fatalf("expected one audit entry, got %d", len(entries))
+	}
+	if entries[0].Subject != txHash || entries[0].Reason != "small" || entries[0].WalletAddress != from {
+		t.Errorf("unexpected audit entry: %+v", entries[0])
+	}
+
+	// A failed send is not audited as approved
+	sender.fail = true
+	tx.Amount = "0.02"
+	if _, err := wm.AutoApproveTransaction(ctx, tx, "small", ""); err == nil {
+		t.Error("expected the send error to be returned")
+	}
+	if n := len(wm.auditLogger.GetAuditLogByAction(AuditActionTransactionAutoApproval)); n != 1 {
+		t.Errorf("expected failed sends not to be audited, got %d entries", n)
+	}
+}
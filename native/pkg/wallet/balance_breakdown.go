@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// GetBalanceBreakdown reports the balance of token held by address on
+// chainName split into what can be sent now and what is locked: the native gas
+// reserve, sends still pending, and whatever the chain itself locks, such as
+// Solana rent and stake. An empty token means the chain's native token.
+func (wm *WalletManager) GetBalanceBreakdown(ctx context.Context, chainName, address, token string) (*chain.BalanceBreakdown, error) {
+	if address == "" {
+		return nil, errors.New("address is required")
+	}
+
+	normalizedChain := NormalizeChain(chainName)
+	nativeToken := NativeTokenSymbol(normalizedChain)
+	if nativeToken == "" {
+		return nil, fmt.Errorf("unsupported chain: %s", chainName)
+	}
+	chainImpl, err := wm.chainFactory.GetChain(normalizedChain)
+	if err != nil {
+		return nil, err
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		token = nativeToken
+	}
+	isNative := strings.EqualFold(token, nativeToken)
+
+	b := &chain.BalanceBreakdown{
+		Chain:   normalizedChain,
+		Address: address,
+		Token:   token,
+	}
+	if b.Balance, err = chainImpl.GetBalance(ctx, address, token); err != nil {
+		return nil, fmt.Errorf("failed to get %s balance: %w", token, err)
+	}
+
+	var locked []chain.LockedBalance
+	if reporter, ok := chainImpl.(chain.LockedBalanceReporter); ok {
+		if locked, err = reporter.LockedBalances(ctx, address, token); err != nil {
+			return nil, fmt.Errorf("failed to get locked %s balance: %w", token, err)
+		}
+	}
+	if isNative {
+		if reserve := wm.NativeReserve(normalizedChain); reserve > 0 {
+			locked = append(locked, chain.LockedBalance{
+				Kind:        chain.LockedKindGasReserve,
+				Amount:      strconv.FormatFloat(reserve, 'f', -1, 64),
+				Description: "kept back by sends to pay for gas",
+			})
+		}
+	}
+	if inFlight, count := wm.inFlightAmount(normalizedChain, address, token, isNative); count > 0 {
+		locked = append(locked, chain.LockedBalance{
+			Kind:        chain.LockedKindInFlight,
+			Amount:      inFlight,
+			Description: fmt.Sprintf("%d pending send(s) not yet confirmed", count),
+		})
+	}
+
+	// An empty account has nothing of its own to lock
+	emptyBalance := false
+	if balance, ok := new(big.Rat).SetString(strings.TrimSpace(b.Balance)); ok && balance.Sign() == 0 {
+		emptyBalance = true
+	}
+	for _, part := range locked {
+		if emptyBalance && !part.External {
+			continue
+		}
+		b.Breakdown = append(b.Breakdown, part)
+	}
+
+	if err := chain.ComputeBalanceBreakdown(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// inFlightAmount sums the pending transactions sending token from address on
+// chainName. Amounts that do not parse, such as "max", are left out.
+func (wm *WalletManager) inFlightAmount(chainName, address, token string, isNative bool) (string, int) {
+	total := new(big.Rat)
+	count := 0
+	for _, tx := range wm.pending.List(PendingTransactionFilter{Chain: chainName, Address: address}) {
+		if tx.Status != "pending" || !strings.EqualFold(tx.From, address) {
+			continue
+		}
+		if isNative {
+			if tx.Token != "" && !strings.EqualFold(tx.Token, token) {
+				continue
+			}
+		} else if !strings.EqualFold(tx.Token, token) {
+			continue
+		}
+		amount, ok := new(big.Rat).SetString(strings.TrimSpace(tx.Amount))
+		if !ok || amount.Sign() <= 0 {
+			continue
+		}
+		total.Add(total, amount)
+		count++
+	}
+	return strings.TrimSuffix(strings.TrimRight(total.FloatString(18), "0"), "."), count
+}
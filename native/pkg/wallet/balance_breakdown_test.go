@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletManagerGetBalanceBreakdown(t *testing.T) {
+	from := "0x1111111111111111111111111111111111111111"
+	to := "0x2222222222222222222222222222222222222222"
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	eth := &fixedBalanceChain{ETHChain: chain.NewETHChainLegacy(), balance: "1.5"}
+	eth.SetNativeReserve(0.01)
+	wm.chainFactory.RegisterChain("ethereum", eth)
+
+	for _, tx := range []*PendingTransaction{
+		{Hash: "0xa1", Chain: "ethereum", From: from, To: to, Amount: "0.25", Token: "ETH", Status: "pending"},
+		{Hash: "0xa2", Chain: "ethereum", From: from, To: to, Amount: "0.05", Status: "pending"},
+		{Hash: "0xa3", Chain: "ethereum", From: from, To: to, Amount: "1", Token: "ETH", Status: "confirmed"},
+		{Hash: "0xa4", Chain: "ethereum", From: to, To: from, Amount: "1", Token: "ETH", Status: "pending"},
+		{Hash: "0xa5", Chain: "ethereum", From: from, To: to, Amount: "100", Token: "USDC", Status: "pending"},
+	} {
+		require.NoError(t, wm.pending.Add(context.Background(), tx))
+	}
+
+	b, err := wm.GetBalanceBreakdown(context.Background(), "eth", from, "")
+	require.NoError(t, err)
+	assert.Equal(t, "ethereum", b.Chain)
+	assert.Equal(t, "ETH", b.Token)
+	assert.Equal(t, "1.5", b.Balance)
+	assert.Equal(t, "1.5", b.Total)
+	assert.Equal(t, "1.19", b.Available)
+	assert.Equal(t, "0.31", b.Locked)
+	assert.Equal(t, []chain.LockedBalance{
+		{Kind: chain.LockedKindGasReserve, Amount: "0.01", Description: "kept back by sends to pay for gas"},
+		{Kind: chain.LockedKindInFlight, Amount: "0.3", Description: "2 pending send(s) not yet confirmed"},
+	}, b.Breakdown)
+
+	// Tokens have no gas reserve, only their own pending sends
+	b, err = wm.GetBalanceBreakdown(context.Background(), "ethereum", from, "USDC")
+	require.NoError(t, err)
+	assert.Equal(t, "0", b.Available, "the pending 100 USDC exceeds the balance")
+	require.Len(t, b.Breakdown, 1)
+	assert.Equal(t, "100", b.Breakdown[0].Amount)
+
+	// An empty account has nothing locked
+	eth.balance = "0"
+	b, err = wm.GetBalanceBreakdown(context.Background(), "ethereum", from, "ETH")
+	require.NoError(t, err)
+	assert.Empty(t, b.Breakdown)
+	assert.Equal(t, "0", b.Locked)
+
+	_, err = wm.GetBalanceBreakdown(context.Background(), "tron", from, "")
+	assert.Error(t, err)
+}
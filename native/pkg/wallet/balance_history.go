@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// BalanceSnapshot is the balance of one token held by an account at a point in time
+type BalanceSnapshot struct {
+	Timestamp int64  `json:"timestamp"` // unix seconds
+	Chain     string `json:"chain"`
+	Address   string `json:"address"`
+	Token     string `json:"token"`
+	Balance   string `json:"balance"`
+}
+
+// BalanceHistoryFilter selects snapshots by time range and, optionally, by
+// chain, address and token. A zero From or To leaves that end open.
+type BalanceHistoryFilter struct {
+	Chain   string
+	Address string
+	Token   string
+	From    time.Time
+	To      time.Time
+}
+
+// matches reports whether s passes the non-time criteria of f
+func (f BalanceHistoryFilter) matches(s *BalanceSnapshot) bool {
+	return (f.Chain == "" || f.Chain == s.Chain) &&
+		(f.Address == "" || strings.EqualFold(f.Address, s.Address)) &&
+		(f.Token == "" || strings.EqualFold(f.Token, s.Token))
+}
+
+// BalanceHistory persists balance snapshots as a time series. Each snapshot run
+// is stored as one entry keyed by its zero-padded unix nanosecond timestamp, so
+// the store's lexical key order is chronological.
+type BalanceHistory struct {
+	mu    sync.Mutex
+	store storage.StateStore
+}
+
+// NewBalanceHistory creates a BalanceHistory backed by store
+func NewBalanceHistory(store storage.StateStore) *BalanceHistory {
+	return &BalanceHistory{store: store}
+}
+
+// Record stores the snapshots taken at at
+func (h *BalanceHistory) Record(ctx context.Context, at time.Time, snapshots []*BalanceSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return fmt.Errorf("failed to marshal balance snapshots: %w", err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.store.Put(ctx, storage.NamespaceBalances, snapshotKey(at), data)
+}
+
+// Query returns the snapshots matching filter, oldest first
+func (h *BalanceHistory) Query(ctx context.Context, filter BalanceHistoryFilter) ([]*BalanceSnapshot, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	keys, err := h.store.List(ctx, storage.NamespaceBalances)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*BalanceSnapshot{}
+	for _, key := range keys {
+		at, ok := parseSnapshotKey(key)
+		if !ok {
+			continue
+		}
+		if (!filter.From.IsZero() && at.Before(filter.From)) || (!filter.To.IsZero() && at.After(filter.To)) {
+			continue
+		}
+		data, err := h.store.Get(ctx, storage.NamespaceBalances, key)
+		if err != nil {
+			return nil, err
+		}
+		var snapshots []*BalanceSnapshot
+		if err := json.Unmarshal(data, &snapshots); err != nil {
+			return nil, fmt.Errorf("failed to parse balance snapshot %s: %w", key, err)
+		}
+		for _, snapshot := range snapshots {
+			if filter.matches(snapshot) {
+				result = append(result, snapshot)
+			}
+		}
+	}
+	return result, nil
+}
+
+// Prune deletes snapshot runs taken before cutoff and returns how many were removed
+func (h *BalanceHistory) Prune(ctx context.Context, cutoff time.Time) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	keys, err := h.store.List(ctx, storage.NamespaceBalances)
+	if err != nil {
+		return 0, err
+	}
+	pruned := 0
+	for _, key := range keys {
+		at, ok := parseSnapshotKey(key)
+		if !ok || !at.Before(cutoff) {
+			continue
+		}
+		if err := h.store.Delete(ctx, storage.NamespaceBalances, key); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+func snapshotKey(at time.Time) string {
+	return fmt.Sprintf("%020d", at.UnixNano())
+}
+
+func parseSnapshotKey(key string) (time.Time, bool) {
+	nanos, err := strconv.ParseInt(key, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos), true
+}
+
+// GetBalanceHistory returns recorded balance snapshots matching filter, oldest first
+func (wm *WalletManager) GetBalanceHistory(ctx context.Context, filter BalanceHistoryFilter) ([]*BalanceSnapshot, error) {
+	if filter.Chain != "" {
+		filter.Chain = NormalizeChain(filter.Chain)
+	}
+	return wm.balanceHistory.Query(ctx, filter)
+}
+
+// SnapshotBalances records the current native and configured token balances of
+// every account of the stored wallet, then prunes snapshots past the retention
+// window. Balances that cannot be read are skipped and logged. It returns the
+// number of balances recorded.
+func (wm *WalletManager) SnapshotBalances(ctx context.Context) (int, error) {
+	walletData, err := wm.loadWallet()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var snapshots []*BalanceSnapshot
+	for chainName, addresses := range snapshotAccounts(walletData) {
+		chainImpl, err := wm.chainFactory.GetChain(chainName)
+		if err != nil {
+			continue
+		}
+		tokens := append([]string{NativeTokenSymbol(chainName)}, wm.balanceSnapshots.Tokens[chainName]...)
+		for _, addr := range addresses {
+			for _, token := range tokens {
+				balance, err := chainImpl.GetBalance(ctx, addr, token)
+				if err != nil {
+					wm.logger.Debug("Skipping balance snapshot",
+						zap.String("chain", chainName),
+						zap.String("address", addr),
+						zap.String("token", token),
+						zap.Error(err))
+					continue
+				}
+				snapshots = append(snapshots, &BalanceSnapshot{
+					Timestamp: now.Unix(),
+					Chain:     chainName,
+					Address:   addr,
+					Token:     token,
+					Balance:   balance,
+				})
+			}
+		}
+	}
+
+	if err := wm.balanceHistory.Record(ctx, now, snapshots); err != nil {
+		return 0, fmt.Errorf("failed to record balance snapshots: %w", err)
+	}
+	if wm.balanceSnapshots.Retention > 0 {
+		if _, err := wm.balanceHistory.Prune(ctx, now.Add(-wm.balanceSnapshots.Retention)); err != nil {
+			wm.logger.Warn("Failed to prune balance snapshots", zap.Error(err))
+		}
+	}
+	return len(snapshots), nil
+}
+
+// RunBalanceSnapshots takes a snapshot immediately and then every configured
+// interval until ctx is done. It returns at once when snapshots are disabled.
+func (wm *WalletManager) RunBalanceSnapshots(ctx context.Context) {
+	if !wm.balanceSnapshots.Enabled || wm.balanceSnapshots.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(wm.balanceSnapshots.Interval)
+	defer ticker.Stop()
+	for {
+		if wm.HasWallet() {
+			if count, err := wm.SnapshotBalances(ctx); err != nil {
+				wm.logger.Warn("Balance snapshot failed", zap.Error(err))
+			} else {
+				wm.logger.Debug("Balance snapshot recorded", zap.Int("balances", count))
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// snapshotAccounts lists the addresses to snapshot per chain: the primary
+// address on each of the wallet's chains plus its discovered accounts
+func snapshotAccounts(walletData *EncryptedWalletData) map[string][]string {
+	accounts := make(map[string][]string)
+	for chainName, enabled := range walletData.Chains {
+		if enabled {
+			accounts[chainName] = append(accounts[chainName], walletData.Address)
+		}
+	}
+	for _, account := range walletData.Accounts {
+		accounts[account.Chain] = append(accounts[account.Chain], account.Address)
+	}
+	return accounts
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// fixedBalanceChain is an ETH chain reporting the same balance for every account
+type fixedBalanceChain struct {
+	*chain.ETHChain
+	balance string
+}
+
+func (c *fixedBalanceChain) GetBalance(ctx context.Context, address, token string) (string, error) {
+	return c.balance, nil
+}
+
+func TestBalanceHistoryQueryAndPrune(t *testing.T) {
+	ctx := context.Background()
+	history := NewBalanceHistory(storage.NewMemoryStateStore())
+	base := time.Unix(1700000000, 0)
+
+	for i, balance := range []string{"1.0", "1.5", "2.0"} {
+		at := base.Add(time.Duration(i) * time.Hour)
+		err := history.Record(ctx, at, []*BalanceSnapshot{
+			{Timestamp: at.Unix(), Chain: "ethereum", Address: "0xAbC", Token: "ETH", Balance: balance},
+			{Timestamp: at.Unix(), Chain: "solana", Address: "Sol1", Token: "SOL", Balance: "10"},
+		})
+		if err != nil {
+			t.Fatalf("record failed: %v", err)
+		}
+	}
+
+	snapshots, err := history.Query(ctx, BalanceHistoryFilter{Address: "0xabc", From: base.Add(30 * time.Minute)})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Balance != "1.5" || snapshots[1].Balance != "2.0" {
+		t.Fatalf("unexpected snapshots: %+v", snapshots)
+	}
+
+	pruned, err := history.Prune(ctx, base.Add(90*time.Minute))
+	if err != nil || pruned != 2 {
+		t.Fatalf("expected two runs pruned, got %d (%v)", pruned, err)
+	}
+	if snapshots, _ = history.Query(ctx, BalanceHistoryFilter{Chain: "solana"}); len(snapshots) != 1 {
+		t.Errorf("expected one solana snapshot after pruning, got %d", len(snapshots))
+	}
+}
+
+func TestWalletManagerSnapshotBalances(t *testing.T) {
+	ctx := context.Background()
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	wm.balanceSnapshots.Tokens = nil
+	balances := &fixedBalanceChain{ETHChain: chain.NewETHChainLegacy(), balance: "0.25"}
+	wm.chainFactory.RegisterChain("ethereum", balances)
+	wm.chainFactory.RegisterChain("bsc", balances)
+
+	if _, err := wm.SnapshotBalances(ctx); err == nil {
+		t.Error("expected an error without a stored wallet")
+	}
+
+	address, _, _, err := wm.ImportWallet(ctx, "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "password123", "ethereum", "")
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	count, err := wm.SnapshotBalances(ctx)
+	if err != nil {
+		t.Fatalf("snapshot failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected native balances on ethereum and bsc, got %d", count)
+	}
+
+	snapshots, err := wm.GetBalanceHistory(ctx, BalanceHistoryFilter{Chain: "eth", Address: address})
+	if err != nil {
+		t.Fatalf("history failed: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Token != "ETH" || snapshots[0].Balance != "0.25" {
+		t.Errorf("unexpected history: %+v", snapshots)
+	}
+}
@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CacheWarmup reports a finished cache warm-up
+type CacheWarmup struct {
+	Balances int           // balances fetched
+	Prices   int           // prices fetched
+	Failures int           // calls that failed; they are logged and skipped
+	Duration time.Duration // how long the warm-up took
+	// Complete is false when the wallet was locked or the timeout passed
+	// before every call was made
+	Complete bool
+}
+
+// CacheWarmupListener is notified when a warm-up started by UnlockWallet ends
+type CacheWarmupListener func(warmup CacheWarmup)
+
+// OnCacheWarmed registers listener to be called at the end of every cache
+// warm-up, e.g. to tell subscribers the first queries will be fast
+func (wm *WalletManager) OnCacheWarmed(listener CacheWarmupListener) {
+	wm.warmupMu.Lock()
+	defer wm.warmupMu.Unlock()
+	wm.warmupListeners = append(wm.warmupListeners, listener)
+}
+
+// SetPriceFeed sets the feed whose prices the cache warm-up fetches, for
+// hosts that build the feed after the manager. Without one only balances are
+// warmed.
+func (wm *WalletManager) SetPriceFeed(feed PriceFeed) {
+	wm.warmupMu.Lock()
+	defer wm.warmupMu.Unlock()
+	wm.priceFeed = feed
+}
+
+// startCacheWarmup fetches the wallet's balances and the prices of its native
+// tokens in the background when wallet.cache_warmup is enabled. A warm-up
+// still running from an earlier unlock is cancelled first.
+func (wm *WalletManager) startCacheWarmup() {
+	if !wm.cacheWarmup.Enabled {
+		return
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if wm.cacheWarmup.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), wm.cacheWarmup.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	wm.warmupMu.Lock()
+	if wm.cancelWarmup != nil {
+		wm.cancelWarmup()
+	}
+	wm.cancelWarmup = cancel
+	feed := wm.priceFeed
+	wm.warmupMu.Unlock()
+
+	go func() {
+		defer cancel()
+		warmup := wm.warmCaches(ctx, feed)
+		wm.logger.Info("Cache warm-up finished",
+			zap.Int("balances", warmup.Balances),
+			zap.Int("prices", warmup.Prices),
+			zap.Int("failures", warmup.Failures),
+			zap.Duration("duration", warmup.Duration),
+			zap.Bool("complete", warmup.Complete))
+
+		wm.warmupMu.Lock()
+		listeners := append([]CacheWarmupListener(nil), wm.warmupListeners...)
+		wm.warmupMu.Unlock()
+		for _, listener := range listeners {
+			listener(warmup)
+		}
+	}()
+}
+
+// stopCacheWarmup cancels a running warm-up, e.g. because the wallet was locked
+func (wm *WalletManager) stopCacheWarmup() {
+	wm.warmupMu.Lock()
+	defer wm.warmupMu.Unlock()
+	if wm.cancelWarmup != nil {
+		wm.cancelWarmup()
+		wm.cancelWarmup = nil
+	}
+}
+
+// warmCaches makes the calls of one warm-up one at a time, request_interval
+// apart: the native and configured token balances of every account, then the
+// price of each native token
+func (wm *WalletManager) warmCaches(ctx context.Context, feed PriceFeed) CacheWarmup {
+	started := time.Now()
+	warmup := CacheWarmup{}
+	walletData, err := wm.loadWallet()
+	if err != nil {
+		wm.logger.Debug("Skipping cache warm-up", zap.Error(err))
+		warmup.Duration = time.Since(started)
+		return warmup
+	}
+
+	calls := 0
+	next := func() bool {
+		if calls > 0 && wm.cacheWarmup.RequestInterval > 0 {
+			timer := time.NewTimer(wm.cacheWarmup.RequestInterval)
+			defer timer.Stop()
+			select {
+			case <-ctx.Done():
+				return false
+			case <-timer.C:
+			}
+		}
+		calls++
+		return ctx.Err() == nil
+	}
+
+	accounts := snapshotAccounts(walletData)
+	chainNames := make([]string, 0, len(accounts))
+	for chainName := range accounts {
+		chainNames = append(chainNames, chainName)
+	}
+	sort.Strings(chainNames)
+
+	for _, chainName := range chainNames {
+		chainImpl, err := wm.chainFactory.GetChain(chainName)
+		if err != nil {
+			continue
+		}
+		tokens := append([]string{NativeTokenSymbol(chainName)}, wm.cacheWarmup.Tokens[chainName]...)
+		for _, address := range accounts[chainName] {
+			for _, token := range tokens {
+				if !next() {
+					warmup.Duration = time.Since(started)
+					return warmup
+				}
+				if _, err := chainImpl.GetBalance(ctx, address, token); err != nil {
+					warmup.Failures++
+					wm.logger.Debug("Cache warm-up balance failed",
+						zap.String("chain", chainName),
+						zap.String("address", address),
+						zap.String("token", token),
+						zap.Error(err))
+					continue
+				}
+				warmup.Balances++
+			}
+		}
+	}
+
+	if feed != nil {
+		for _, chainName := range chainNames {
+			symbol := NativeTokenSymbol(chainName)
+			if symbol == "" {
+				continue
+			}
+			if !next() {
+				warmup.Duration = time.Since(started)
+				return warmup
+			}
+			if _, err := feed.USDPrice(ctx, symbol); err != nil {
+				warmup.Failures++
+				wm.logger.Debug("Cache warm-up price failed", zap.String("symbol", symbol), zap.Error(err))
+				continue
+			}
+			warmup.Prices++
+		}
+	}
+
+	warmup.Duration = time.Since(started)
+	warmup.Complete = true
+	return warmup
+}
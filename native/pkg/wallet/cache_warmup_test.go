@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// newWarmupTestManager returns a manager holding an imported, locked wallet
+// whose warm-ups are reported on the returned channel
+func newWarmupTestManager(t *testing.T, warmupConfig config.CacheWarmupConfig) (*WalletManager, <-chan CacheWarmup) {
+	t.Helper()
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	balances := &fixedBalanceChain{ETHChain: chain.NewETHChainLegacy(), balance: "0.25"}
+	wm.chainFactory.RegisterChain("ethereum", balances)
+	wm.chainFactory.RegisterChain("bsc", balances)
+	wm.SetPriceFeed(fixedPriceFeed{"ETH": 2000})
+
+	warmed := make(chan CacheWarmup, 1)
+	wm.OnCacheWarmed(func(warmup CacheWarmup) {
+		warmed <- warmup
+	})
+
+	if _, _, _, err := wm.ImportWallet(context.Background(), "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "password123", "ethereum", ""); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	wm.LockWallet()
+	wm.cacheWarmup = warmupConfig
+	return wm, warmed
+}
+
+func waitForWarmup(t *testing.T, warmed <-chan CacheWarmup) CacheWarmup {
+	t.Helper()
+	select {
+	case warmup := <-warmed:
+		return warmup
+	case <-time.After(5 * time.Second):
+		t.Fatal("cache warm-up did not finish")
+		return CacheWarmup{}
+	}
+}
+
+func TestUnlockWalletWarmsCaches(t *testing.T) {
+	wm, warmed := newWarmupTestManager(t, config.CacheWarmupConfig{Enabled: true})
+	if err := wm.UnlockWallet("", "password123"); err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+
+	warmup := waitForWarmup(t, warmed)
+	if !warmup.Complete {
+		t.Error("expected the warm-up to complete")
+	}
+	if warmup.Balances != 2 {
+		t.Errorf("expected native balances on ethereum and bsc, got %d", warmup.Balances)
+	}
+	// BNB has no price in the feed
+	if warmup.Prices != 1 || warmup.Failures != 1 {
+		t.Errorf("expected one price and one failure, got %d and %d", warmup.Prices, warmup.Failures)
+	}
+}
+
+func TestLockWalletCancelsCacheWarmup(t *testing.T) {
+	wm, warmed := newWarmupTestManager(t, config.CacheWarmupConfig{Enabled: true, RequestInterval: time.Hour})
+	if err := wm.UnlockWallet("", "password123"); err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+	wm.LockWallet()
+
+	warmup := waitForWarmup(t, warmed)
+	if warmup.Complete {
+		t.Error("expected the warm-up to stop when the wallet was locked")
+	}
+	if warmup.Balances > 1 {
+		t.Errorf("expected at most the first call before the pause, got %d balances", warmup.Balances)
+	}
+}
+
+func TestCacheWarmupDisabled(t *testing.T) {
+	wm, warmed := newWarmupTestManager(t, config.CacheWarmupConfig{})
+	if err := wm.UnlockWallet("", "password123"); err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+
+	select {
+	case warmup := <-warmed:
+		t.Errorf("expected no warm-up, got %+v", warmup)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
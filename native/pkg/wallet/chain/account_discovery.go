@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mr-tron/base58"
+	bip39 "github.com/tyler-smith/go-bip39"
+)
+
+const (
+	// EVMAccountPathFormat is the BIP-44 path of the n-th account on ETH and BSC
+	EVMAccountPathFormat = "m/44'/60'/0'/0/%d"
+	// SolanaAccountPathFormat is the path of the n-th account as used by Phantom and solana-keygen
+	SolanaAccountPathFormat = "m/44'/501'/%d'/0'"
+)
+
+// AccountDeriver is implemented by chains that can derive the n-th account of a mnemonic
+type AccountDeriver interface {
+	// DeriveAccount derives the account at index along the chain's BIP-44 path
+	DeriveAccount(mnemonic string, index uint32) (*WalletInfo, error)
+}
+
+// ActivityChecker is implemented by chains that can tell whether an address has
+// ever been used. Chains without it are checked by balance only.
+type ActivityChecker interface {
+	HasActivity(ctx context.Context, address string) (bool, error)
+}
+
+// DiscoveredAccount is an active account found by DiscoverAccounts
+type DiscoveredAccount struct {
+	Index      uint32
+	Path       string
+	Address    string
+	PublicKey  string
+	PrivateKey string
+}
+
+// DiscoverAccounts derives accounts of mnemonic from index 0 upward and returns
+// the ones with on-chain activity. Following BIP-44 account discovery, the scan
+// stops after gapLimit consecutive unused accounts or after maxAccounts
+// accounts, whichever comes first.
+func DiscoverAccounts(ctx context.Context, c IChain, mnemonic string, maxAccounts, gapLimit int) ([]*DiscoveredAccount, error) {
+	deriver, ok := c.(AccountDeriver)
+	if !ok {
+		return nil, fmt.Errorf("account discovery is not supported on %s", c.GetChainName())
+	}
+	if maxAccounts <= 0 || gapLimit <= 0 {
+		return nil, errors.New("max accounts and gap limit must be positive")
+	}
+
+	var found []*DiscoveredAccount
+	gap := 0
+	for index := 0; index < maxAccounts && gap < gapLimit; index++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		info, err := deriver.DeriveAccount(mnemonic, uint32(index))
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive account %d: %w", index, err)
+		}
+		active, err := hasActivity(ctx, c, info.Address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check account %d (%s): %w", index, info.Address, err)
+		}
+		if !active {
+			gap++
+			continue
+		}
+		gap = 0
+		found = append(found, &DiscoveredAccount{
+			Index:      uint32(index),
+			Path:       AccountPath(c, uint32(index)),
+			Address:    info.Address,
+			PublicKey:  info.PublicKey,
+			PrivateKey: info.PrivateKey,
+		})
+	}
+	return found, nil
+}
+
+// hasActivity prefers the chain's own activity check and falls back to a non-zero native balance
+func hasActivity(ctx context.Context, c IChain, address string) (bool, error) {
+	if checker, ok := c.(ActivityChecker); ok {
+		return checker.HasActivity(ctx, address)
+	}
+	balance, err := c.GetBalance(ctx, address, "")
+	if err != nil {
+		return false, err
+	}
+	value, ok := new(big.Float).SetString(strings.TrimSpace(balance))
+	return ok && value.Sign() > 0, nil
+}
+
+// AccountPath returns the derivation path DeriveAccount uses for index on c
+func AccountPath(c IChain, index uint32) string {
+	if _, ok := c.(*SolanaChain); ok {
+		return fmt.Sprintf(SolanaAccountPathFormat, index)
+	}
+	return fmt.Sprintf(EVMAccountPathFormat, index)
+}
+
+// DeriveAccount derives the Ethereum account at index
+func (e *ETHChain) DeriveAccount(mnemonic string, index uint32) (*WalletInfo, error) {
+	return deriveEVMAccount(mnemonic, index)
+}
+
+// DeriveAccount derives the BSC account at index; BSC shares Ethereum's coin type
+func (b *BSCChain) DeriveAccount(mnemonic string, index uint32) (*WalletInfo, error) {
+	return deriveEVMAccount(mnemonic, index)
+}
+
+// DeriveAccount derives the Solana account at index
+func (s *SolanaChain) DeriveAccount(mnemonic string, index uint32) (*WalletInfo, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, errors.New("invalid mnemonic phrase")
+	}
+	privateKey, err := DeriveEd25519Key(bip39.NewSeed(mnemonic, ""), fmt.Sprintf(SolanaAccountPathFormat, index))
+	if err != nil {
+		return nil, err
+	}
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	return &WalletInfo{
+		Address:    base58.Encode(publicKey),
+		PublicKey:  base58.Encode(publicKey),
+		PrivateKey: base58.Encode(privateKey),
+		Mnemonic:   mnemonic,
+	}, nil
+}
+
+// HasActivity reports whether address holds SOL or has any transaction history
+func (s *SolanaChain) HasActivity(ctx context.Context, address string) (bool, error) {
+	if s.rpcManager == nil {
+		return false, errors.New("no Solana RPC endpoint configured")
+	}
+	balance, err := s.rpcManager.GetBalance(ctx, address, s.commitment(ctx))
+	if err != nil {
+		return false, err
+	}
+	if balance.Value > 0 {
+		return true, nil
+	}
+	signatures, err := s.rpcManager.GetSignaturesForAddress(ctx, address, 1)
+	if err != nil {
+		return false, err
+	}
+	return len(signatures) > 0, nil
+}
+
+func deriveEVMAccount(mnemonic string, index uint32) (*WalletInfo, error) {
+	return deriveEVMWallet(mnemonic, fmt.Sprintf(EVMAccountPathFormat, index))
+}
+
+// deriveEVMWallet derives the EVM wallet at the BIP-44 path of mnemonic, giving
+// the same address as MetaMask and other standard wallets
+func deriveEVMWallet(mnemonic, path string) (*WalletInfo, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, errors.New("invalid mnemonic phrase")
+	}
+	privateKey, err := DeriveSecp256k1Key(bip39.NewSeed(mnemonic, ""), path)
+	if err != nil {
+		return nil, err
+	}
+	return &WalletInfo{
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey).Hex(),
+		PublicKey:  hexutil.Encode(crypto.FromECDSAPub(&privateKey.PublicKey)),
+		PrivateKey: hexutil.Encode(crypto.FromECDSA(privateKey)),
+		Mnemonic:   mnemonic,
+	}, nil
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// activityChain is an ETH chain whose used addresses are listed in active
+type activityChain struct {
+	*ETHChain
+	active  map[string]bool
+	checked int
+}
+
+func (c *activityChain) HasActivity(ctx context.Context, address string) (bool, error) {
+	c.checked++
+	return c.active[address], nil
+}
+
+func TestDeriveAccountVectors(t *testing.T) {
+	eth := NewETHChainLegacy()
+	account, err := eth.DeriveAccount(testMnemonic, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", account.Address)
+
+	account, err = eth.DeriveAccount(testMnemonic, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0", account.Address)
+
+	sol := &SolanaChain{}
+	account, err = sol.DeriveAccount(testMnemonic, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "HAgk14JpMQLgt6rVgv7cBQFJWFto5Dqxi472uT3DKpqk", account.Address)
+
+	_, err = eth.DeriveAccount("not a mnemonic", 0)
+	assert.Error(t, err)
+}
+
+func TestImportFromMnemonicFollowsDerivationPath(t *testing.T) {
+	ctx := context.Background()
+	for _, c := range []IChain{NewETHChainLegacy(), NewBSCChainLegacy()} {
+		// The default path gives the first MetaMask account
+		info, err := c.ImportFromMnemonic(ctx, testMnemonic, "")
+		require.NoError(t, err)
+		assert.Equal(t, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", info.Address)
+		assert.Equal(t, "0x1ab42cc412b618bdea3a599e3c9bae199ebf030895b039e9db1e30dafb12b727", info.PrivateKey)
+
+		info, err = c.ImportFromMnemonic(ctx, testMnemonic, "m/44'/60'/0'/0/1")
+		require.NoError(t, err)
+		assert.Equal(t, "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0", info.Address)
+
+		_, err = c.ImportFromMnemonic(ctx, testMnemonic, "44'/60'/0'/0/0")
+		assert.Error(t, err, "paths must start at the master key")
+	}
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	indexes, err := parseDerivationPath("m/44'/60h/0'/0/7")
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{44 + hardenedOffset, 60 + hardenedOffset, hardenedOffset, 0, 7}, indexes)
+
+	for _, path := range []string{"", "m", "44'/60'", "m/x", "m/2147483648"} {
+		_, err := parseDerivationPath(path)
+		assert.Error(t, err, path)
+	}
+
+	_, err = DeriveEd25519Key([]byte("seed"), "m/44'/501'/0'/0")
+	assert.Error(t, err, "ed25519 rejects non-hardened indexes")
+}
+
+func TestDiscoverAccounts(t *testing.T) {
+	eth := NewETHChainLegacy()
+	addressAt := func(index uint32) string {
+		account, err := eth.DeriveAccount(testMnemonic, index)
+		require.NoError(t, err)
+		return account.Address
+	}
+
+	t.Run("stops after gap limit", func(t *testing.T) {
+		c := &activityChain{ETHChain: eth, active: map[string]bool{
+			addressAt(0): true,
+			addressAt(2): true,
+			addressAt(6): true, // beyond a gap of three
+		}}
+		found, err := DiscoverAccounts(context.Background(), c, testMnemonic, 20, 3)
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		assert.Equal(t, uint32(0), found[0].Index)
+		assert.Equal(t, uint32(2), found[1].Index)
+		assert.Equal(t, "m/44'/60'/0'/0/2", found[1].Path)
+		assert.NotEmpty(t, found[1].PrivateKey)
+		assert.Equal(t, 6, c.checked)
+	})
+
+	t.Run("stops at max accounts", func(t *testing.T) {
+		c := &activityChain{ETHChain: eth, active: map[string]bool{
+			addressAt(0): true,
+			addressAt(1): true,
+			addressAt(2): true,
+		}}
+		found, err := DiscoverAccounts(context.Background(), c, testMnemonic, 2, 5)
+		require.NoError(t, err)
+		assert.Len(t, found, 2)
+		assert.Equal(t, 2, c.checked)
+	})
+
+	t.Run("rejects non-positive limits", func(t *testing.T) {
+		c := &activityChain{ETHChain: eth}
+		_, err := DiscoverAccounts(context.Background(), c, testMnemonic, 0, 5)
+		assert.Error(t, err)
+	})
+}
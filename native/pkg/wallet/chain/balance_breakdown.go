@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Kinds of LockedBalance
+const (
+	LockedKindRentReserve = "rent_reserve" // minimum an account must hold to stay open
+	LockedKindGasReserve  = "gas_reserve"  // native amount sends keep back for fees
+	LockedKindInFlight    = "in_flight"    // sends broadcast but not yet confirmed
+	LockedKindStaked      = "staked"       // delegated or cooling down stake
+)
+
+// LockedBalance is one part of an account's funds that cannot be sent right now
+type LockedBalance struct {
+	Kind        string `json:"kind"`
+	Amount      string `json:"amount"`
+	Description string `json:"description,omitempty"`
+
+	// External is set for funds held outside the account's own balance, such
+	// as Solana stake accounts; they add to the total instead of reducing the
+	// available balance
+	External bool `json:"external,omitempty"`
+}
+
+// LockedBalanceReporter is implemented by chains that lock part of an account's
+// funds in ways the plain balance does not show
+type LockedBalanceReporter interface {
+	// LockedBalances returns the locked parts of token held by address. An
+	// empty token means the native token.
+	LockedBalances(ctx context.Context, address, token string) ([]LockedBalance, error)
+}
+
+// BalanceBreakdown splits the funds of an account into what can be sent and
+// what is locked
+type BalanceBreakdown struct {
+	Chain     string          `json:"chain"`
+	Address   string          `json:"address"`
+	Token     string          `json:"token"`
+	Balance   string          `json:"balance"`   // the plain balance, as GetBalance returns it
+	Total     string          `json:"total"`     // Balance plus external funds
+	Available string          `json:"available"` // what sends can move now
+	Locked    string          `json:"locked"`    // Total minus Available
+	Breakdown []LockedBalance `json:"breakdown,omitempty"`
+}
+
+// ComputeBalanceBreakdown fills Total, Available and Locked from the Balance
+// and Breakdown already set on b. Locked parts of the balance can overlap, for
+// example a pending send that already left the account, so Available is
+// floored at zero.
+func ComputeBalanceBreakdown(b *BalanceBreakdown) error {
+	balance, ok := new(big.Rat).SetString(strings.TrimSpace(b.Balance))
+	if !ok {
+		return fmt.Errorf("invalid balance: %s", b.Balance)
+	}
+	total := new(big.Rat).Set(balance)
+	available := new(big.Rat).Set(balance)
+	for _, locked := range b.Breakdown {
+		amount, ok := new(big.Rat).SetString(strings.TrimSpace(locked.Amount))
+		if !ok {
+			return fmt.Errorf("invalid %s amount: %s", locked.Kind, locked.Amount)
+		}
+		if locked.External {
+			total.Add(total, amount)
+		} else {
+			available.Sub(available, amount)
+		}
+	}
+	if available.Sign() < 0 {
+		available.SetInt64(0)
+	}
+	b.Total = formatDecimal(total)
+	b.Available = formatDecimal(available)
+	b.Locked = formatDecimal(new(big.Rat).Sub(total, available))
+	return nil
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
+	"go.uber.org/zap"
+)
+
+// ErrBalanceUnavailable is returned when every configured balance source fails
+// and the balance configuration asks for failures to be surfaced.
+var ErrBalanceUnavailable = errors.New("balance unavailable from all configured sources")
+
+// balanceFetcher reads a balance from a single source
+type balanceFetcher func(ctx context.Context) (string, error)
+
+// fetchBalance tries the configured balance sources in order and returns the
+// first successful result. If all of them fail it either reports
+// ErrBalanceUnavailable or falls back to "0", depending on cfg.FailOnUnavailable.
+func fetchBalance(ctx context.Context, cfg config.BalanceConfig, logger *zap.Logger, chainName string, rpc, dexSource balanceFetcher) (string, error) {
+	var failures []string
+	for _, source := range cfg.SourceOrder() {
+		fetch := rpc
+		if source == config.BalanceSourceDEX {
+			fetch = dexSource
+		}
+
+		balance, err := fetch(ctx)
+		if err == nil {
+			return balance, nil
+		}
+
+		failures = append(failures, fmt.Sprintf("%s: %v", source, err))
+		if logger != nil {
+			logger.Warn("Balance source failed",
+				zap.String("chain", chainName),
+				zap.String("source", source),
+				zap.Error(err))
+		}
+	}
+
+	if cfg.FailOnUnavailable {
+		return "", fmt.Errorf("%w for %s (%s)", ErrBalanceUnavailable, chainName, strings.Join(failures, "; "))
+	}
+	return "0", nil
+}
+
+// getDEXBalance reads a balance through the first DEX provider that supports chainID
+func getDEXBalance(ctx context.Context, aggregator dex.IDEXAggregator, chainID, address, token string) (balance string, provider string, err error) {
+	if aggregator == nil {
+		return "", "", errors.New("no DEX aggregator configured")
+	}
+
+	providers := aggregator.GetSupportedProviders(chainID)
+	if len(providers) == 0 {
+		return "", "", fmt.Errorf("no DEX provider supports chain %s", chainID)
+	}
+
+	dexProvider, err := aggregator.GetProviderByName(providers[0])
+	if err != nil {
+		return "", providers[0], err
+	}
+
+	balanceInfo, err := dexProvider.GetBalance(ctx, address, token, chainID)
+	if err != nil {
+		return "", providers[0], err
+	}
+	return balanceInfo.Balance, providers[0], nil
+}
@@ -3,9 +3,10 @@ package chain
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"errors"
 	"fmt"
+	"io"
+	"math/big"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	bip39 "github.com/tyler-smith/go-bip39"
+	"github.com/algonius/algonius-wallet/native/pkg/config"
 	"github.com/algonius/algonius-wallet/native/pkg/dex"
 	"go.uber.org/zap"
 )
@@ -23,6 +25,15 @@ type BSCChain struct {
 	dexAggregator dex.IDEXAggregator
 	logger       *zap.Logger
 	chainID      string
+	balanceConfig config.BalanceConfig
+	gasStrategy   string
+	maxFeeGwei    float64
+	nativeReserve float64
+	recipients    *evmRecipientCheck
+	preflight     EVMCallFunc
+	transferFees  *evmTransferFeeCheck
+	feeHistory    EVMFeeHistoryFunc
+	entropy       io.Reader
 }
 
 // NewBSCChain creates a new BSC chain instance
@@ -43,55 +54,108 @@ func NewBSCChainLegacy() *BSCChain {
 	}
 }
 
-// GetChainName returns the name of the chain
-func (b *BSCChain) GetChainName() string {
-	return b.name
+// SetEntropySource replaces the randomness CreateWallet draws mnemonics
+// from. nil restores crypto/rand.
+func (b *BSCChain) SetEntropySource(source io.Reader) {
+	b.entropy = source
 }
 
-// CreateWallet generates a new BSC wallet (same as Ethereum since it's EVM-compatible)
-func (b *BSCChain) CreateWallet(ctx context.Context) (*WalletInfo, error) {
-	// Generate entropy for mnemonic
-	entropy, err := bip39.NewEntropy(128) // 128 bits = 12 words
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate entropy: %w", err)
-	}
+// SetBalanceConfig sets the balance source ordering used by GetBalance
+func (b *BSCChain) SetBalanceConfig(cfg config.BalanceConfig) {
+	b.balanceConfig = cfg
+}
 
-	// Generate mnemonic phrase
-	mnemonic, err := bip39.NewMnemonic(entropy)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate mnemonic: %w", err)
-	}
+// SetRecipientCheck enables ClassifyRecipient, reading contract code through
+// getCode. Token transfers to safeContracts are not flagged.
+func (b *BSCChain) SetRecipientCheck(getCode EVMCodeFunc, safeContracts []string) {
+	b.recipients = newEVMRecipientCheck(getCode, safeContracts)
+}
 
-	// Generate seed from mnemonic
-	seed := bip39.NewSeed(mnemonic, "")
+// ClassifyRecipient reports whether address is an EOA or a contract, flagging
+// token transfers to contracts that are not known to handle them
+func (b *BSCChain) ClassifyRecipient(ctx context.Context, address, token string) (*RecipientClassification, error) {
+	return b.recipients.classify(ctx, address, token, "BNB")
+}
 
-	// Derive the first private key from the seed
-	// This is a simplified derivation; in production, you'd use proper BIP-44 derivation
-	hash := crypto.Keccak256(seed)
-	privateKey, err := crypto.ToECDSA(hash)
-	if err != nil {
-		return nil, fmt.Errorf("failed to derive private key: %w", err)
+// SetPreflight enables SimulateSend, dry-running sends through call
+func (b *BSCChain) SetPreflight(call EVMCallFunc) {
+	b.preflight = call
+}
+
+// SetFeeHistory enables EstimateConfirmationTime, sampling recent blocks through feeHistory
+func (b *BSCChain) SetFeeHistory(feeHistory EVMFeeHistoryFunc) {
+	b.feeHistory = feeHistory
+}
+
+// EstimateConfirmationTime estimates how soon fee is included from the fees paid in recent blocks
+func (b *BSCChain) EstimateConfirmationTime(ctx context.Context, fee ProposedFee) (*ConfirmationTimeEstimate, error) {
+	return evmConfirmationTime(ctx, "bsc", b.feeHistory, fee)
+}
+
+// SimulateSend runs the send as an eth_call and returns a *SimulationError
+// with the decoded revert reason when it would revert
+func (b *BSCChain) SimulateSend(ctx context.Context, from, to, amount, token string) error {
+	if b.preflight == nil {
+		return ErrSimulationUnavailable
+	}
+	token = strings.TrimSpace(token)
+	if token == "" || strings.EqualFold(token, "BNB") {
+		resolved, err := b.spendableAmount(ctx, from, amount)
+		if err != nil {
+			return err
+		}
+		amount = resolved
+	} else if IsMaxAmount(amount) {
+		return ErrSimulationUnavailable
 	}
+	return simulateEVMSend(ctx, b.preflight, from, to, amount, token, "BNB")
+}
+
+// SetTransferFeeCheck enables DetectTransferFee. Tokens in known are reported
+// with their configured fee percent; others are simulated through simulate,
+// reading their decimals through call.
+func (b *BSCChain) SetTransferFeeCheck(call EVMCallFunc, simulate EVMSimulateFunc, known map[string]float64) {
+	b.transferFees = newEVMTransferFeeCheck(call, simulate, known)
+}
+
+// DetectTransferFee reports the fee a fee-on-transfer token takes from a send
+func (b *BSCChain) DetectTransferFee(ctx context.Context, from, to, amount, token string) (*TokenTransferFee, error) {
+	return b.transferFees.detect(ctx, from, to, amount, token, "BNB")
+}
 
-	// Get public key
-	publicKeyECDSA, ok := privateKey.Public().(*ecdsa.PublicKey)
+// DetectPermitSupport reads the EIP-2612 and EIP-3009 support of token
+func (b *BSCChain) DetectPermitSupport(ctx context.Context, token, owner string) (*TokenPermitSupport, error) {
+	chainID, ok := new(big.Int).SetString(b.chainID, 10)
 	if !ok {
-		return nil, errors.New("failed to get public key")
+		return nil, fmt.Errorf("invalid chain ID %q", b.chainID)
 	}
+	return DetectPermitSupport(ctx, b.preflight, chainID, token, owner)
+}
 
-	// Generate BSC address (same as Ethereum address format)
-	address := crypto.PubkeyToAddress(*publicKeyECDSA)
+// SignPermit signs a gasless approval of request.Token with privateKey
+func (b *BSCChain) SignPermit(ctx context.Context, request PermitRequest, privateKey string) (*SignedPermit, error) {
+	support, err := b.DetectPermitSupport(ctx, request.Token, request.Owner)
+	if err != nil {
+		return nil, err
+	}
+	return SignPermitRequest(support, request, privateKey)
+}
 
-	// Convert keys to hex strings
-	privateKeyHex := hexutil.Encode(crypto.FromECDSA(privateKey))
-	publicKeyHex := hexutil.Encode(crypto.FromECDSAPub(publicKeyECDSA))
+// GetChainName returns the name of the chain
+func (b *BSCChain) GetChainName() string {
+	return b.name
+}
 
-	return &WalletInfo{
-		Address:    address.Hex(),
-		PublicKey:  publicKeyHex,
-		PrivateKey: privateKeyHex,
-		Mnemonic:   mnemonic,
-	}, nil
+// CreateWallet generates a new BSC wallet (same as Ethereum since it's EVM-compatible)
+func (b *BSCChain) CreateWallet(ctx context.Context) (*WalletInfo, error) {
+	// Generate mnemonic phrase
+	mnemonic, err := newMnemonic(b.entropy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Derive the first account along the default BIP-44 path
+	return deriveEVMWallet(mnemonic, fmt.Sprintf(EVMAccountPathFormat, 0))
 }
 
 // ImportFromMnemonic imports a wallet from mnemonic phrase with derivation path
@@ -101,46 +165,12 @@ func (b *BSCChain) ImportFromMnemonic(ctx context.Context, mnemonic, derivationP
 		return nil, errors.New("invalid mnemonic phrase")
 	}
 	
-	// Generate seed from mnemonic
-	seed := bip39.NewSeed(mnemonic, "")
-	
 	// Use default derivation path if not provided
 	if derivationPath == "" {
 		derivationPath = "m/44'/60'/0'/0/0" // BSC uses same derivation as Ethereum
 	}
 	
-	// For simplicity, use the seed to derive the private key
-	// In production, you'd implement proper HD wallet derivation with the path
-	if len(seed) < 32 {
-		return nil, errors.New("insufficient seed length")
-	}
-	
-	// Create private key from seed
-	privateKey, err := crypto.ToECDSA(seed[:32])
-	if err != nil {
-		return nil, fmt.Errorf("failed to create private key: %w", err)
-	}
-	
-	// Derive public key
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return nil, errors.New("failed to get public key")
-	}
-	
-	// Generate BSC address (same as Ethereum)
-	address := crypto.PubkeyToAddress(*publicKeyECDSA)
-	
-	// Convert keys to hex strings
-	privateKeyHex := hexutil.Encode(crypto.FromECDSA(privateKey))
-	publicKeyHex := hexutil.Encode(crypto.FromECDSAPub(publicKeyECDSA))
-	
-	return &WalletInfo{
-		Address:    address.Hex(),
-		PublicKey:  publicKeyHex,
-		PrivateKey: privateKeyHex,
-		Mnemonic:   mnemonic,
-	}, nil
+	return deriveEVMWallet(mnemonic, derivationPath)
 }
 
 // GetBalance retrieves the balance for a BSC address
@@ -172,35 +202,31 @@ func (b *BSCChain) GetBalance(ctx context.Context, address string, token string)
 		// TODO: In a real implementation, verify it's a valid BEP-20 contract
 	}
 
-	// Try to get balance using DEX aggregator if available
-	if b.dexAggregator != nil {
-		providers := b.dexAggregator.GetSupportedProviders(b.chainID)
-		if len(providers) > 0 {
-			// Try first available provider
-			provider, err := b.dexAggregator.GetProviderByName(providers[0])
-			if err == nil {
-				balanceInfo, err := provider.GetBalance(ctx, address, token, b.chainID)
-				if err == nil {
-					b.logger.Debug("BSC balance retrieved via DEX provider",
-						zap.String("provider", providers[0]),
-						zap.String("balance", balanceInfo.Balance))
-					return balanceInfo.Balance, nil
-				}
-				b.logger.Warn("BSC DEX provider balance failed, falling back to mock",
-					zap.String("provider", providers[0]),
-					zap.Error(err))
-			}
-		}
-	}
+	return b.fetchBalance(ctx, address, token, b.balanceConfig)
+}
 
-	// TODO: Implement actual balance retrieval from BSC node
-	// This is a mock implementation that returns "0"
-	// In a real implementation, you would:
-	// 1. Connect to a BSC node (Binance API, QuickNode, etc.)
-	// 2. For BNB: Use ethclient.BalanceAt() to get the balance
-	// 3. For BEP-20: Use the contract's balanceOf function
-	// 4. Convert from Wei to BNB or token decimals
-	return "0", nil
+// fetchBalance reads the balance of address through the sources ordered by cfg
+func (b *BSCChain) fetchBalance(ctx context.Context, address, token string, cfg config.BalanceConfig) (string, error) {
+	return fetchBalance(ctx, cfg, b.logger, "bsc",
+		func(ctx context.Context) (string, error) {
+			// TODO: Implement actual balance retrieval from BSC node
+			// In a real implementation, you would:
+			// 1. Connect to a BSC node (Binance API, QuickNode, etc.)
+			// 2. For BNB: Use ethclient.BalanceAt() to get the balance
+			// 3. For BEP-20: Use the contract's balanceOf function
+			// 4. Convert from Wei to BNB or token decimals
+			return "", errors.New("BSC RPC balance retrieval not implemented")
+		},
+		func(ctx context.Context) (string, error) {
+			balance, provider, err := getDEXBalance(ctx, b.dexAggregator, b.chainID, address, token)
+			if err != nil {
+				return "", err
+			}
+			b.logger.Debug("BSC balance retrieved via DEX provider",
+				zap.String("provider", provider),
+				zap.String("balance", balance))
+			return balance, nil
+		})
 }
 
 // SendTransaction sends a transaction on the BSC network
@@ -258,6 +284,19 @@ func (b *BSCChain) SendTransaction(ctx context.Context, from, to string, amount
 		return "", errors.New("cannot send to the same address")
 	}
 
+	// Keep the gas reserve: resolve "max" and refuse sends that would dip into it
+	if isERC20 {
+		if IsMaxAmount(amount) {
+			return "", errors.New("max amount is only supported for native BNB transfers")
+		}
+	} else {
+		resolved, err := b.spendableAmount(ctx, from, amount)
+		if err != nil {
+			return "", err
+		}
+		amount = resolved
+	}
+
 	// Try to execute swap using DEX aggregator if it's a token swap
 	if b.dexAggregator != nil && isERC20 {
 		swapParams := dex.SwapParams{
@@ -314,6 +353,81 @@ func (b *BSCChain) SendTransaction(ctx context.Context, from, to string, amount
 	return hash.Hex(), nil
 }
 
+// SetNativeReserve sets the amount of BNB that native sends must leave for gas
+func (b *BSCChain) SetNativeReserve(reserve float64) {
+	b.nativeReserve = reserve
+}
+
+// NativeReserve returns the amount of BNB kept back for gas
+func (b *BSCChain) NativeReserve() float64 {
+	return b.nativeReserve
+}
+
+// spendableAmount resolves a native send amount against the gas reserve
+func (b *BSCChain) spendableAmount(ctx context.Context, from, amount string) (string, error) {
+	return resolveNativeAmount(ctx, b.logger, "bsc", amount, b.nativeReserve, func(ctx context.Context) (string, error) {
+		// Unlike GetBalance, never fall back to "0": an unknown balance must not look empty
+		cfg := b.balanceConfig
+		cfg.FailOnUnavailable = true
+		return b.fetchBalance(ctx, from, "BNB", cfg)
+	})
+}
+
+// WrapNative deposits amount BNB of owner into WBNB
+func (b *BSCChain) WrapNative(ctx context.Context, owner, amount, privateKey string) (*WrapResult, error) {
+	return b.nativeWrap().wrap(ctx, owner, amount, privateKey)
+}
+
+// UnwrapNative withdraws amount WBNB of owner back into BNB
+func (b *BSCChain) UnwrapNative(ctx context.Context, owner, amount, privateKey string) (*WrapResult, error) {
+	return b.nativeWrap().unwrap(ctx, owner, amount, privateKey)
+}
+
+func (b *BSCChain) nativeWrap() evmNativeWrap {
+	return evmNativeWrap{
+		chainName: "bsc",
+		native:    "BNB",
+		balance: func(ctx context.Context, address, token string) (string, error) {
+			cfg := b.balanceConfig
+			cfg.FailOnUnavailable = true
+			return b.fetchBalance(ctx, address, token, cfg)
+		},
+		spendable: b.spendableAmount,
+	}
+}
+
+// SetGasConfig sets the default gas strategy and the max fee ceiling in gwei (0 disables the ceiling)
+func (b *BSCChain) SetGasConfig(strategy string, maxFeeGwei float64) {
+	b.gasStrategy = strategy
+	b.maxFeeGwei = maxFeeGwei
+}
+
+// SampleMempool returns recent base fees and pending pool depth for BSC
+func (b *BSCChain) SampleMempool(ctx context.Context) (MempoolSample, error) {
+	// TODO: Implement actual fee market sampling
+	// In a real implementation, you would:
+	// 1. Call eth_feeHistory for the last few blocks to get base fees and reward percentiles
+	// 2. Call txpool_status (or a provider equivalent) for the pending pool depth
+	return MempoolSample{
+		BaseFeesGwei:    []float64{3, 3, 3, 3, 3},
+		PriorityFeeGwei: 0,
+		PendingTxCount:  2500,
+		NormalPoolDepth: 4000,
+	}, nil
+}
+
+// SuggestGasParams prices a BSC transaction for strategy, falling back to the configured strategy
+func (b *BSCChain) SuggestGasParams(ctx context.Context, strategy string) (*GasParams, error) {
+	if strategy == "" {
+		strategy = b.gasStrategy
+	}
+	sample, err := b.SampleMempool(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample mempool: %w", err)
+	}
+	return ComputeGasParams(strategy, sample, b.maxFeeGwei)
+}
+
 // EstimateGas estimates gas requirements for a BSC transaction
 func (b *BSCChain) EstimateGas(ctx context.Context, from, to string, amount string, token string) (gasLimit uint64, gasPrice string, err error) {
 	// Validate addresses
@@ -451,13 +565,17 @@ func (b *BSCChain) ConfirmTransaction(ctx context.Context, txHash string, requir
 		transactionFee = "0.000260" // Higher fee for token transfers
 	}
 
+	fee, _ := NewFee("bsc", transactionFee)
+
 	return &TransactionConfirmation{
 		Status:                status,
 		Confirmations:         confirmations,
 		RequiredConfirmations: requiredConfirmations,
 		BlockNumber:           blockNumber,
+		BlockHash:             mockBlockHash("bsc", blockNumber),
 		GasUsed:               gasUsed,
 		TransactionFee:        transactionFee,
+		Fee:                   fee,
 		Timestamp:             timestamp,
 		TxHash:                txHash,
 	}, nil
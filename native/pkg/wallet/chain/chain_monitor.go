@@ -0,0 +1,436 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
+	"go.uber.org/zap"
+)
+
+// BlockHead is a block as the chain monitor sees it. Solana slots carry no
+// hash, so only stalls are detected there.
+type BlockHead struct {
+	Number     uint64
+	Hash       string
+	ParentHash string
+	Timestamp  time.Time // zero when the source does not report it
+}
+
+// BlockHeadFunc reads the block at number, or the latest block when number
+// is nil. Sources without hashes are only asked for the latest block.
+type BlockHeadFunc func(ctx context.Context, number *uint64) (BlockHead, error)
+
+// errBlockNotFound is returned for a block number the node does not have
+var errBlockNotFound = errors.New("block not found")
+
+// NewEVMBlockHeadFunc returns a BlockHeadFunc reading blocks with
+// eth_getBlockByNumber from endpoints, in order until one answers
+func NewEVMBlockHeadFunc(chainName string, endpoints []string) BlockHeadFunc {
+	client := httpclient.New(chainName+"-rpc", httpclient.WithTimeout(15*time.Second))
+	return func(ctx context.Context, number *uint64) (BlockHead, error) {
+		if len(endpoints) == 0 {
+			return BlockHead{}, fmt.Errorf("no %s RPC endpoints configured", chainName)
+		}
+		tag := "latest"
+		if number != nil {
+			tag = "0x" + strconv.FormatUint(*number, 16)
+		}
+		var lastErr error
+		for _, endpoint := range endpoints {
+			head, err := evmGetBlockHead(ctx, client, endpoint, tag)
+			if err == nil || errors.Is(err, errBlockNotFound) {
+				DefaultRPCHealth.RecordSuccess(chainName)
+				return head, err
+			}
+			lastErr = err
+		}
+		return BlockHead{}, fmt.Errorf("all RPC endpoints failed, last error: %w", lastErr)
+	}
+}
+
+func evmGetBlockHead(ctx context.Context, client *http.Client, endpoint, tag string) (BlockHead, error) {
+	body, err := json.Marshal(RPCRequest{JSONRPC: "2.0", ID: 1, Method: "eth_getBlockByNumber", Params: []any{tag, false}})
+	if err != nil {
+		return BlockHead{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return BlockHead{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return BlockHead{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return BlockHead{}, &rpcHTTPError{StatusCode: resp.StatusCode}
+	}
+
+	var response struct {
+		Result *struct {
+			Number     string `json:"number"`
+			Hash       string `json:"hash"`
+			ParentHash string `json:"parentHash"`
+			Timestamp  string `json:"timestamp"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&response); err != nil {
+		return BlockHead{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if response.Error != nil {
+		return BlockHead{}, fmt.Errorf("RPC error %d: %s", response.Error.Code, response.Error.Message)
+	}
+	if response.Result == nil {
+		return BlockHead{}, fmt.Errorf("%w: %s", errBlockNotFound, tag)
+	}
+	number, err := strconv.ParseUint(strings.TrimPrefix(response.Result.Number, "0x"), 16, 64)
+	if err != nil {
+		return BlockHead{}, fmt.Errorf("invalid block number %q", response.Result.Number)
+	}
+	head := BlockHead{Number: number, Hash: strings.ToLower(response.Result.Hash), ParentHash: strings.ToLower(response.Result.ParentHash)}
+	if response.Result.Timestamp != "" {
+		seconds, err := strconv.ParseUint(strings.TrimPrefix(response.Result.Timestamp, "0x"), 16, 64)
+		if err != nil {
+			return BlockHead{}, fmt.Errorf("invalid block timestamp %q", response.Result.Timestamp)
+		}
+		head.Timestamp = time.Unix(int64(seconds), 0).UTC()
+	}
+	return head, nil
+}
+
+// NewSolanaSlotHeadFunc returns a BlockHeadFunc reporting the slot rpcManager
+// has reached at commitment
+func NewSolanaSlotHeadFunc(rpcManager *SolanaRPCManager, commitment string) BlockHeadFunc {
+	return func(ctx context.Context, number *uint64) (BlockHead, error) {
+		if number != nil {
+			return BlockHead{}, errors.New("solana slots are only read at the head")
+		}
+		slot, err := rpcManager.GetSlot(ctx, commitment)
+		if err != nil {
+			return BlockHead{}, err
+		}
+		return BlockHead{Number: slot}, nil
+	}
+}
+
+// ChainReorg reports blocks that were replaced on an EVM chain
+type ChainReorg struct {
+	Chain string
+	// Depth is how many recently seen blocks are no longer canonical. When
+	// AtLeast is set, every remembered block was replaced and the reorg may
+	// be deeper.
+	Depth   int
+	AtLeast bool
+	// CommonAncestor is the last remembered block still canonical; zero when AtLeast is set
+	CommonAncestor uint64
+	OldHead        BlockHead
+	NewHead        BlockHead
+}
+
+// ChainStall reports a chain that produced no new block or slot for too long
+type ChainStall struct {
+	Chain       string
+	LastBlock   uint64    // highest block or slot seen; zero when none was ever read
+	LastAdvance time.Time // when the monitor last saw the chain move, or started watching it
+	StalledFor  time.Duration
+	LastError   string // the last error reading the head, when the RPC is what failed
+}
+
+// ReorgListener is notified of a reorg found by a ChainMonitor
+type ReorgListener func(reorg ChainReorg)
+
+// StallListener is notified of a stall found by a ChainMonitor
+type StallListener func(stall ChainStall)
+
+// monitoredChain is the state the monitor keeps for one chain
+type monitoredChain struct {
+	name       string
+	head       BlockHeadFunc
+	stallAfter time.Duration
+
+	hashes      map[uint64]string // recent canonical hashes by block number
+	tip         BlockHead         // newest head accepted
+	highest     uint64
+	lastAdvance time.Time
+	lastErr     error
+	stalled     bool
+}
+
+// ChainMonitor polls the head of each chain it watches, reporting reorgs on
+// chains with block hashes and chains that stop producing blocks
+type ChainMonitor struct {
+	cfg    config.ChainMonitorConfig
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu             sync.Mutex
+	chains         []*monitoredChain
+	reorgListeners []ReorgListener
+	stallListeners []StallListener
+}
+
+// NewChainMonitor creates a monitor that watches no chain until AddChain is called
+func NewChainMonitor(cfg config.ChainMonitorConfig, logger *zap.Logger) *ChainMonitor {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &ChainMonitor{cfg: cfg, logger: logger, now: time.Now}
+}
+
+// NewConfiguredChainMonitor creates a monitor watching every enabled chain of
+// cfg through its configured RPC endpoints
+func NewConfiguredChainMonitor(cfg *config.Config, logger *zap.Logger) (*ChainMonitor, error) {
+	monitor := NewChainMonitor(cfg.Chains.Monitor, logger)
+	if cfg.Chains.Ethereum.Enabled {
+		// Retried and failed over across the endpoints like every other Ethereum call
+		ethRPC := newETHRPCClient("ethereum", cfg.Chains.Ethereum.RPCEndpoints, cfg.Chains.Ethereum.RPCRetry)
+		monitor.AddChain("ethereum", ethRPC.blockHead)
+	}
+	if cfg.Chains.BSC.Enabled {
+		monitor.AddChain("bsc", NewEVMBlockHeadFunc("bsc", cfg.Chains.BSC.RPCEndpoints))
+	}
+	if cfg.Chains.Solana.Enabled {
+		rpcManager, err := NewSolanaRPCManager(cfg.Chains.Solana.RPCEndpoints, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create solana RPC manager: %w", err)
+		}
+		monitor.AddChain("solana", NewSolanaSlotHeadFunc(rpcManager, cfg.Chains.Solana.Commitment))
+	}
+	return monitor, nil
+}
+
+// AddChain starts watching chainName, whose blocks head reads
+func (m *ChainMonitor) AddChain(chainName string, head BlockHeadFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.chains = append(m.chains, &monitoredChain{
+		name:       chainName,
+		head:       head,
+		stallAfter: m.cfg.StallWindow(chainName),
+		hashes:     make(map[uint64]string),
+	})
+}
+
+// OnReorg registers listener to be called for every reorg at least
+// min_reorg_depth deep
+func (m *ChainMonitor) OnReorg(listener ReorgListener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reorgListeners = append(m.reorgListeners, listener)
+}
+
+// OnStall registers listener to be called once each time a chain stalls
+func (m *ChainMonitor) OnStall(listener StallListener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stallListeners = append(m.stallListeners, listener)
+}
+
+// Run polls every chain each poll_interval until ctx is done
+func (m *ChainMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		m.Poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll reads the head of every chain once, notifying listeners of reorgs and
+// stalls it finds
+func (m *ChainMonitor) Poll(ctx context.Context) {
+	m.mu.Lock()
+	chains := append([]*monitoredChain(nil), m.chains...)
+	m.mu.Unlock()
+	for _, c := range chains {
+		if ctx.Err() != nil {
+			return
+		}
+		m.poll(ctx, c)
+	}
+}
+
+func (m *ChainMonitor) poll(ctx context.Context, c *monitoredChain) {
+	if c.lastAdvance.IsZero() {
+		c.lastAdvance = m.now()
+	}
+	pollCtx := ctx
+	if m.cfg.PollInterval > 0 {
+		var cancel context.CancelFunc
+		pollCtx, cancel = context.WithTimeout(ctx, m.cfg.PollInterval)
+		defer cancel()
+	}
+
+	latest, err := c.head(pollCtx, nil)
+	if err == nil && latest.Hash != "" {
+		err = m.checkReorg(pollCtx, c, latest)
+	}
+	c.lastErr = err
+	if err != nil {
+		m.logger.Debug("Failed to read chain head", zap.String("chain", c.name), zap.Error(err))
+	} else {
+		if latest.Hash == "" {
+			c.tip = latest
+		}
+		if latest.Number > c.highest {
+			if c.stalled {
+				m.logger.Info("Chain resumed", zap.String("chain", c.name), zap.Uint64("block", latest.Number))
+			}
+			c.highest, c.lastAdvance, c.stalled = latest.Number, m.now(), false
+		}
+	}
+	m.checkStall(c)
+}
+
+// checkReorg follows the parent hashes of latest back to a remembered block,
+// reading the blocks in between, and reports the remembered blocks it passes
+// as replaced. The blocks read are remembered, so the history stays contiguous.
+func (m *ChainMonitor) checkReorg(ctx context.Context, c *monitoredChain, latest BlockHead) error {
+	if len(c.hashes) == 0 {
+		m.remember(c, latest)
+		return nil
+	}
+	// The same head, or a lagging endpoint still serving a remembered block
+	if hash, ok := c.hashes[latest.Number]; ok && hash == latest.Hash {
+		return nil
+	}
+	lowest := c.tip.Number
+	for number := range c.hashes {
+		if number < lowest {
+			lowest = number
+		}
+	}
+	if latest.Number < lowest {
+		// Too far behind to compare: an endpoint that is out of sync rather
+		// than a reorg deeper than the chain's finality
+		m.logger.Debug("Ignoring a head below the remembered blocks",
+			zap.String("chain", c.name), zap.Uint64("head", latest.Number))
+		return nil
+	}
+	if latest.Number-c.tip.Number > uint64(m.cfg.HistoryDepth) {
+		m.logger.Info("Chain head moved past the remembered blocks, starting over",
+			zap.String("chain", c.name), zap.Uint64("old_head", c.tip.Number), zap.Uint64("new_head", latest.Number))
+		c.hashes = make(map[uint64]string)
+		m.remember(c, latest)
+		return nil
+	}
+
+	canonical := make(map[uint64]string)
+	var ancestor uint64
+	found := false
+	parent := latest.ParentHash
+	for number := latest.Number; number > lowest; {
+		number--
+		if hash, ok := c.hashes[number]; ok && hash == parent {
+			ancestor, found = number, true
+			break
+		}
+		n := number
+		block, err := c.head(ctx, &n)
+		if err != nil {
+			return err
+		}
+		canonical[number], parent = block.Hash, block.ParentHash
+	}
+
+	if !found || ancestor < c.tip.Number {
+		reorg := ChainReorg{Chain: c.name, AtLeast: !found, OldHead: c.tip, NewHead: latest}
+		if found {
+			reorg.Depth, reorg.CommonAncestor = int(c.tip.Number-ancestor), ancestor
+		} else {
+			reorg.Depth = int(c.tip.Number-lowest) + 1
+		}
+		m.reportReorg(reorg)
+	}
+
+	for number := range c.hashes {
+		if !found || number > ancestor {
+			delete(c.hashes, number)
+		}
+	}
+	for number, hash := range canonical {
+		c.hashes[number] = hash
+	}
+	m.remember(c, latest)
+	return nil
+}
+
+// remember makes latest the head of c and forgets blocks more than
+// history_depth below it
+func (m *ChainMonitor) remember(c *monitoredChain, latest BlockHead) {
+	c.tip = latest
+	c.hashes[latest.Number] = latest.Hash
+	for number := range c.hashes {
+		if latest.Number-number >= uint64(m.cfg.HistoryDepth) {
+			delete(c.hashes, number)
+		}
+	}
+}
+
+func (m *ChainMonitor) reportReorg(reorg ChainReorg) {
+	fields := []zap.Field{
+		zap.String("chain", reorg.Chain),
+		zap.Int("depth", reorg.Depth),
+		zap.Bool("at_least", reorg.AtLeast),
+		zap.Uint64("old_head", reorg.OldHead.Number),
+		zap.Uint64("new_head", reorg.NewHead.Number),
+	}
+	if reorg.Depth < m.cfg.MinReorgDepth {
+		m.logger.Info("Chain reorg below the reported depth", fields...)
+		return
+	}
+	m.logger.Warn("Chain reorg detected", fields...)
+
+	m.mu.Lock()
+	listeners := append([]ReorgListener(nil), m.reorgListeners...)
+	m.mu.Unlock()
+	for _, listener := range listeners {
+		listener(reorg)
+	}
+}
+
+// checkStall reports c once when it has not advanced within its stall window
+func (m *ChainMonitor) checkStall(c *monitoredChain) {
+	if c.stallAfter <= 0 || c.stalled {
+		return
+	}
+	stalledFor := m.now().Sub(c.lastAdvance)
+	if stalledFor < c.stallAfter {
+		return
+	}
+	c.stalled = true
+	stall := ChainStall{Chain: c.name, LastBlock: c.highest, LastAdvance: c.lastAdvance, StalledFor: stalledFor}
+	if c.lastErr != nil {
+		stall.LastError = c.lastErr.Error()
+	}
+	m.logger.Warn("Chain stalled",
+		zap.String("chain", c.name),
+		zap.Uint64("last_block", c.highest),
+		zap.Duration("stalled_for", stalledFor),
+		zap.String("last_error", stall.LastError))
+
+	m.mu.Lock()
+	listeners := append([]StallListener(nil), m.stallListeners...)
+	m.mu.Unlock()
+	for _, listener := range listeners {
+		listener(stall)
+	}
+}
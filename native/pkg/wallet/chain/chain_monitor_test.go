@@ -0,0 +1,256 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChain is a chain of blocks whose hashes name their fork
+type fakeChain struct {
+	blocks []BlockHead // canonical blocks by number, from 0
+	err    error
+	calls  int
+}
+
+// extend appends n blocks mined on fork
+func (c *fakeChain) extend(fork string, n int) {
+	for i := 0; i < n; i++ {
+		number := uint64(len(c.blocks))
+		parent := ""
+		if number > 0 {
+			parent = c.blocks[number-1].Hash
+		}
+		c.blocks = append(c.blocks, BlockHead{Number: number, Hash: fmt.Sprintf("0x%s%d", fork, number), ParentHash: parent})
+	}
+}
+
+// reorg replaces the last depth blocks with ones mined on fork
+func (c *fakeChain) reorg(fork string, depth, length int) {
+	c.blocks = c.blocks[:len(c.blocks)-depth]
+	c.extend(fork, length)
+}
+
+func (c *fakeChain) head(_ context.Context, number *uint64) (BlockHead, error) {
+	c.calls++
+	if c.err != nil {
+		return BlockHead{}, c.err
+	}
+	if number == nil {
+		return c.blocks[len(c.blocks)-1], nil
+	}
+	if *number >= uint64(len(c.blocks)) {
+		return BlockHead{}, errBlockNotFound
+	}
+	return c.blocks[*number], nil
+}
+
+func newTestMonitor(cfg config.ChainMonitorConfig) (*ChainMonitor, *[]ChainReorg, *[]ChainStall, *time.Time) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	monitor := NewChainMonitor(cfg, nil)
+	monitor.now = func() time.Time { return now }
+	var reorgs []ChainReorg
+	var stalls []ChainStall
+	monitor.OnReorg(func(reorg ChainReorg) { reorgs = append(reorgs, reorg) })
+	monitor.OnStall(func(stall ChainStall) { stalls = append(stalls, stall) })
+	return monitor, &reorgs, &stalls, &now
+}
+
+func TestChainMonitorReorg(t *testing.T) {
+	ctx := context.Background()
+	monitor, reorgs, _, _ := newTestMonitor(config.ChainMonitorConfig{PollInterval: time.Second, HistoryDepth: 8, MinReorgDepth: 1})
+	chain := &fakeChain{}
+	chain.extend("a", 10)
+	monitor.AddChain("ethereum", chain.head)
+
+	monitor.Poll(ctx)
+	chain.extend("a", 1)
+	monitor.Poll(ctx)
+	// Several blocks between polls are checked against the remembered head
+	chain.extend("a", 3)
+	monitor.Poll(ctx)
+	// Polling the same head again is not a reorg
+	monitor.Poll(ctx)
+	assert.Empty(t, *reorgs)
+
+	chain.reorg("b", 2, 3)
+	monitor.Poll(ctx)
+	require.Len(t, *reorgs, 1)
+	reorg := (*reorgs)[0]
+	assert.Equal(t, "ethereum", reorg.Chain)
+	assert.Equal(t, 2, reorg.Depth)
+	assert.False(t, reorg.AtLeast)
+	assert.Equal(t, uint64(11), reorg.CommonAncestor)
+	assert.Equal(t, "0xa13", reorg.OldHead.Hash)
+	assert.Equal(t, "0xb14", reorg.NewHead.Hash)
+
+	// The replaced blocks are forgotten, so the new fork extends cleanly
+	chain.extend("b", 1)
+	monitor.Poll(ctx)
+	assert.Len(t, *reorgs, 1)
+
+	t.Run("same height", func(t *testing.T) {
+		chain.reorg("c", 1, 1)
+		monitor.Poll(ctx)
+		require.Len(t, *reorgs, 2)
+		assert.Equal(t, 1, (*reorgs)[1].Depth)
+	})
+
+	t.Run("deeper than the history", func(t *testing.T) {
+		chain.extend("c", 3)
+		monitor.Poll(ctx)
+		chain.reorg("d", 12, 13)
+		monitor.Poll(ctx)
+		require.Len(t, *reorgs, 3)
+		assert.Equal(t, 8, (*reorgs)[2].Depth)
+		assert.True(t, (*reorgs)[2].AtLeast)
+	})
+
+	t.Run("lagging endpoint", func(t *testing.T) {
+		tip := chain.blocks
+		chain.blocks = tip[:len(tip)-2]
+		monitor.Poll(ctx)
+		chain.blocks = tip[:len(tip)-10]
+		monitor.Poll(ctx)
+		chain.blocks = tip
+		chain.extend("d", 1)
+		monitor.Poll(ctx)
+		assert.Len(t, *reorgs, 3)
+	})
+}
+
+func TestChainMonitorMinReorgDepth(t *testing.T) {
+	monitor, reorgs, _, _ := newTestMonitor(config.ChainMonitorConfig{PollInterval: time.Second, HistoryDepth: 8, MinReorgDepth: 2})
+	chain := &fakeChain{}
+	chain.extend("a", 5)
+	monitor.AddChain("bsc", chain.head)
+	monitor.Poll(context.Background())
+	chain.extend("a", 4)
+	monitor.Poll(context.Background())
+
+	chain.reorg("b", 1, 2)
+	monitor.Poll(context.Background())
+	assert.Empty(t, *reorgs, "one-block reorgs are only logged")
+
+	chain.reorg("c", 3, 3)
+	monitor.Poll(context.Background())
+	require.Len(t, *reorgs, 1)
+	assert.Equal(t, 3, (*reorgs)[0].Depth)
+}
+
+func TestChainMonitorStall(t *testing.T) {
+	monitor, _, stalls, now := newTestMonitor(config.ChainMonitorConfig{
+		PollInterval: time.Second,
+		HistoryDepth: 8,
+		StallAfter:   map[string]time.Duration{"sol": time.Minute},
+	})
+	slot := uint64(100)
+	var headErr error
+	monitor.AddChain("solana", func(_ context.Context, number *uint64) (BlockHead, error) {
+		require.Nil(t, number, "slots are only read at the head")
+		return BlockHead{Number: slot}, headErr
+	})
+	// Chains without a stall window are never reported
+	unchecked := &fakeChain{}
+	unchecked.extend("a", 1)
+	monitor.AddChain("ethereum", unchecked.head)
+
+	monitor.Poll(context.Background())
+	*now = now.Add(50 * time.Second)
+	monitor.Poll(context.Background())
+	assert.Empty(t, *stalls)
+
+	*now = now.Add(20 * time.Second)
+	monitor.Poll(context.Background())
+	require.Len(t, *stalls, 1)
+	assert.Equal(t, "solana", (*stalls)[0].Chain)
+	assert.Equal(t, uint64(100), (*stalls)[0].LastBlock)
+	assert.Equal(t, 70*time.Second, (*stalls)[0].StalledFor)
+
+	// Reported once per stall
+	*now = now.Add(time.Minute)
+	monitor.Poll(context.Background())
+	assert.Len(t, *stalls, 1)
+
+	// Moving again clears it; an unreachable RPC counts as no progress
+	slot++
+	monitor.Poll(context.Background())
+	headErr = errors.New("connection refused")
+	*now = now.Add(2 * time.Minute)
+	monitor.Poll(context.Background())
+	require.Len(t, *stalls, 2)
+	assert.Equal(t, uint64(101), (*stalls)[1].LastBlock)
+	assert.Equal(t, "connection refused", (*stalls)[1].LastError)
+}
+
+func TestEVMBlockHeadFunc(t *testing.T) {
+	server := newRPCTestServer(t, map[string]rpcMethod{
+		"eth_getBlockByNumber": func(call rpcCall) (string, error) {
+			params := call.params()
+			assert.Equal(t, false, params[1])
+			switch params[0] {
+			case "latest":
+				return `{"number":"0x1b4","hash":"0xABC","parentHash":"0xDEF"}`, nil
+			case "0x1b3":
+				return `{"number":"0x1b3","hash":"0xdef","parentHash":"0x123"}`, nil
+			}
+			return `null`, nil
+		},
+	})
+
+	head := NewEVMBlockHeadFunc("ethereum", []string{"http://127.0.0.1:1", server.URL})
+	latest, err := head(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, BlockHead{Number: 436, Hash: "0xabc", ParentHash: "0xdef"}, latest)
+
+	number := uint64(435)
+	parent, err := head(context.Background(), &number)
+	require.NoError(t, err)
+	assert.Equal(t, latest.ParentHash, parent.Hash)
+
+	number = 1000
+	_, err = head(context.Background(), &number)
+	assert.ErrorIs(t, err, errBlockNotFound)
+
+	_, err = NewEVMBlockHeadFunc("ethereum", nil)(context.Background(), nil)
+	assert.ErrorContains(t, err, "no ethereum RPC endpoints configured")
+}
+
+func TestConfiguredChainMonitorRetriesEthereumHeads(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) <= 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = io.WriteString(w, `{"jsonrpc":"2.0","id":1,"result":{"number":"0x1b4","hash":"0xabc","parentHash":"0xdef"}}`)
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Chains.Ethereum.Enabled = true
+	cfg.Chains.Ethereum.RPCEndpoints = []string{server.URL}
+	cfg.Chains.Ethereum.RPCRetry = testRPCRetry
+	cfg.Chains.BSC.Enabled = false
+	cfg.Chains.Solana.Enabled = false
+	monitor, err := NewConfiguredChainMonitor(cfg, nil)
+	require.NoError(t, err)
+	require.Len(t, monitor.chains, 1)
+
+	// A rate limited endpoint is retried like every other Ethereum call
+	head, err := monitor.chains[0].head(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(436), head.Number)
+	assert.Equal(t, int32(3), hits.Load())
+}
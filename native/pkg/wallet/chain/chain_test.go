@@ -2,10 +2,18 @@ package chain
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
+	"github.com/algonius/algonius-wallet/native/pkg/dex/providers"
+	"go.uber.org/zap"
 )
 
 func TestETHChain_ConfirmTransaction(t *testing.T) {
+	t.Setenv("RUN_MODE", "test")
 	chain := NewETHChainLegacy()
 	ctx := context.Background()
 
@@ -44,6 +52,7 @@ func TestETHChain_ConfirmTransaction(t *testing.T) {
 }
 
 func TestETHChain_ConfirmTransaction_DefaultConfirmations(t *testing.T) {
+	t.Setenv("RUN_MODE", "test")
 	chain := NewETHChainLegacy()
 	ctx := context.Background()
 
@@ -85,6 +94,7 @@ func TestETHChain_ConfirmTransaction_InvalidHash(t *testing.T) {
 }
 
 func TestETHChain_ConfirmTransaction_HashNormalization(t *testing.T) {
+	t.Setenv("RUN_MODE", "test")
 	chain := NewETHChainLegacy()
 	ctx := context.Background()
 
@@ -150,7 +160,7 @@ func TestChainFactory_GetChain(t *testing.T) {
 	factory := NewChainFactory()
 
 	// Test valid chains
-	validChains := []string{"ETH", "ETHEREUM", "BSC", "BINANCE"}
+	validChains := []string{"ETH", "ETHEREUM", "BSC", "BINANCE", "Binance Smart Chain", "sol", "SOLANA"}
 	for _, chainName := range validChains {
 		chain, err := factory.GetChain(chainName)
 		if err != nil {
@@ -195,4 +205,268 @@ func TestChainFactory_GetSupportedChains(t *testing.T) {
 	if !hasBSC {
 		t.Error("Expected BSC to be in supported chains")
 	}
-}
\ No newline at end of file
+}
+func TestRPCHealthTracker(t *testing.T) {
+	tracker := NewRPCHealthTracker()
+
+	if _, ok := tracker.LastSuccess("ethereum"); ok {
+		t.Error("expected no recorded success for a fresh tracker")
+	}
+
+	before := time.Now().UTC().Add(-time.Second)
+	tracker.RecordSuccess("ETH")
+
+	ts, ok := tracker.LastSuccess("ethereum")
+	if !ok {
+		t.Fatal("expected success recorded under normalized chain name")
+	}
+	if ts.Before(before) {
+		t.Errorf("recorded timestamp %s is too old", ts)
+	}
+
+	snapshot := tracker.Snapshot()
+	if len(snapshot) != 1 {
+		t.Errorf("expected 1 chain in snapshot, got %d", len(snapshot))
+	}
+	if _, ok := snapshot["ethereum"]; !ok {
+		t.Error("expected ethereum in snapshot")
+	}
+}
+
+func TestETHChain_GetBalanceSources(t *testing.T) {
+	ctx := context.Background()
+	address := "0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b6"
+	logger := zap.NewNop()
+	aggregator := dex.NewDEXAggregator(logger)
+	if err := aggregator.RegisterProvider(providers.NewMockProvider(providers.MockConfig{Name: "mock"}, logger)); err != nil {
+		t.Fatalf("failed to register provider: %v", err)
+	}
+
+	chain := NewETHChain(aggregator, logger)
+
+	// Default ordering falls through the unconfigured RPC source to the DEX provider
+	balance, err := chain.GetBalance(ctx, address, "ETH")
+	if err != nil || balance != "1000000000000000000" {
+		t.Errorf("expected DEX balance with default ordering, got %q, %v", balance, err)
+	}
+
+	// rpc-only never consults the DEX provider
+	chain.SetBalanceConfig(config.BalanceConfig{Sources: config.BalanceSourcesRPCOnly})
+	balance, err = chain.GetBalance(ctx, address, "ETH")
+	if err != nil || balance != "0" {
+		t.Errorf("expected silent zero for rpc-only, got %q, %v", balance, err)
+	}
+
+	// Failing loudly surfaces the outage instead of a misleading zero
+	chain.SetBalanceConfig(config.BalanceConfig{Sources: config.BalanceSourcesRPCOnly, FailOnUnavailable: true})
+	if _, err = chain.GetBalance(ctx, address, "ETH"); !errors.Is(err, ErrBalanceUnavailable) {
+		t.Errorf("expected ErrBalanceUnavailable, got %v", err)
+	}
+
+	chain.SetBalanceConfig(config.BalanceConfig{Sources: config.BalanceSourcesDEXOnly, FailOnUnavailable: true})
+	balance, err = chain.GetBalance(ctx, address, "ETH")
+	if err != nil || balance != "1000000000000000000" {
+		t.Errorf("expected DEX balance for dex-only, got %q, %v", balance, err)
+	}
+}
+
+func TestSolanaChain_GetBalanceFailOnUnavailable(t *testing.T) {
+	chain := NewSolanaChainLegacy()
+	address := "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+
+	balance, err := chain.GetBalance(context.Background(), address, "SOL")
+	if err != nil || balance != "0" {
+		t.Errorf("expected legacy zero balance, got %q, %v", balance, err)
+	}
+
+	chain.SetBalanceConfig(config.BalanceConfig{Sources: config.BalanceSourcesRPCThenDEX, FailOnUnavailable: true})
+	if _, err := chain.GetBalance(context.Background(), address, "SOL"); !errors.Is(err, ErrBalanceUnavailable) {
+		t.Errorf("expected ErrBalanceUnavailable, got %v", err)
+	}
+}
+
+func TestReorgDetector(t *testing.T) {
+	detector := &ReorgDetector{}
+
+	if reorg := detector.Observe(&TransactionConfirmation{BlockNumber: 100, BlockHash: "0xaaaa"}); reorg != nil {
+		t.Fatalf("first inclusion should not be a reorg: %+v", reorg)
+	}
+	if reorg := detector.Observe(&TransactionConfirmation{BlockNumber: 100, BlockHash: "0xaaaa"}); reorg != nil {
+		t.Fatalf("same block should not be a reorg: %+v", reorg)
+	}
+
+	reorg := detector.Observe(&TransactionConfirmation{BlockNumber: 101, BlockHash: "0xbbbb"})
+	if reorg == nil {
+		t.Fatal("expected reorg when block hash changes")
+	}
+	if reorg.PreviousBlockHash != "0xaaaa" || reorg.NewBlockHash != "0xbbbb" || reorg.NewBlockNumber != 101 {
+		t.Errorf("unexpected reorg details: %+v", reorg)
+	}
+
+	if reorg := detector.Observe(nil); reorg == nil || reorg.NewBlockHash != "" {
+		t.Errorf("expected reorg when receipt disappears, got %+v", reorg)
+	}
+	if reorg := detector.Observe(&TransactionConfirmation{BlockNumber: 102, BlockHash: "0xcccc"}); reorg != nil {
+		t.Errorf("re-inclusion after a drop should not be reported again: %+v", reorg)
+	}
+}
+
+func TestComputeGasParams(t *testing.T) {
+	calm := MempoolSample{
+		BaseFeesGwei:    []float64{20, 20, 20},
+		PriorityFeeGwei: 2,
+		PendingTxCount:  1000,
+		NormalPoolDepth: 5000,
+	}
+	congested := MempoolSample{
+		BaseFeesGwei:    []float64{20, 25, 30},
+		PriorityFeeGwei: 2,
+		PendingTxCount:  15000,
+		NormalPoolDepth: 5000,
+	}
+
+	fast, err := ComputeGasParams(GasStrategyFast, calm, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fast.Multiplier != 1.5 || fast.MaxFeeGwei != 33 {
+		t.Errorf("unexpected fast params: %+v", fast)
+	}
+
+	standard, err := ComputeGasParams("", calm, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if standard.Strategy != GasStrategyStandard {
+		t.Errorf("expected empty strategy to default to standard, got %q", standard.Strategy)
+	}
+
+	// Calm market: dynamic stays at the floor
+	quiet, err := ComputeGasParams(GasStrategyDynamic, calm, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if quiet.Multiplier != minDynamicGasMultiplier || quiet.MaxFeeGwei != 22 {
+		t.Errorf("unexpected dynamic params in calm market: %+v", quiet)
+	}
+
+	// Base fee up 50% and the pool three times its normal depth: both terms saturate
+	busy, err := ComputeGasParams(GasStrategyDynamic, congested, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if busy.Multiplier != maxDynamicGasMultiplier || busy.PriorityFeeGwei != 4 || busy.MaxFeeGwei != 64 {
+		t.Errorf("unexpected dynamic params in congested market: %+v", busy)
+	}
+
+	capped, err := ComputeGasParams(GasStrategyDynamic, congested, 40)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !capped.Capped || capped.MaxFeeGwei != 40 || capped.PriorityFeeGwei != 4 {
+		t.Errorf("expected max fee capped at ceiling: %+v", capped)
+	}
+
+	if _, err := ComputeGasParams(GasStrategyDynamic, congested, 25); err == nil {
+		t.Error("expected error when ceiling is below the current base fee")
+	}
+	if _, err := ComputeGasParams("turbo", calm, 0); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
+
+func TestScaleGasParams(t *testing.T) {
+	params := &GasParams{Strategy: GasStrategyStandard, BaseFeeGwei: 20, PriorityFeeGwei: 2, MaxFeeGwei: 27, Multiplier: 1.25}
+
+	scaled := ScaleGasParams(params, 1.2, 0)
+	if scaled.PriorityFeeGwei != 2.4 || scaled.MaxFeeGwei != 32.4 || scaled.BaseFeeGwei != 20 || scaled.Capped {
+		t.Errorf("unexpected scaled params: %+v", scaled)
+	}
+	if params.MaxFeeGwei != 27 {
+		t.Errorf("expected the original params to be left alone: %+v", params)
+	}
+
+	capped := ScaleGasParams(params, 2, 30)
+	if !capped.Capped || capped.MaxFeeGwei != 30 || capped.PriorityFeeGwei != 4 {
+		t.Errorf("expected max fee capped at the ceiling: %+v", capped)
+	}
+}
+
+func TestETHChain_SuggestGasParamsUsesConfig(t *testing.T) {
+	chain := NewETHChainLegacy()
+	chain.SetGasConfig(GasStrategyDynamic, 21)
+
+	params, err := chain.SuggestGasParams(context.Background(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params.Strategy != GasStrategyDynamic {
+		t.Errorf("expected configured dynamic strategy, got %q", params.Strategy)
+	}
+	if !params.Capped || params.MaxFeeGwei != 21 {
+		t.Errorf("expected max fee capped at 21 gwei: %+v", params)
+	}
+
+	params, err = chain.SuggestGasParams(context.Background(), GasStrategySlow)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params.Strategy != GasStrategySlow {
+		t.Errorf("expected explicit strategy to override config, got %q", params.Strategy)
+	}
+}
+
+func TestResolveNativeAmount(t *testing.T) {
+	ctx := context.Background()
+	balance := func(ctx context.Context) (string, error) { return "1.5", nil }
+	unavailable := func(ctx context.Context) (string, error) { return "", ErrBalanceUnavailable }
+
+	amount, err := resolveNativeAmount(ctx, nil, "ethereum", "max", 0.005, balance)
+	if err != nil || amount != "1.495" {
+		t.Errorf("expected max to resolve to 1.495, got %q (%v)", amount, err)
+	}
+
+	if amount, err := resolveNativeAmount(ctx, nil, "ethereum", "1.495", 0.005, balance); err != nil || amount != "1.495" {
+		t.Errorf("expected amount up to the reserve to pass, got %q (%v)", amount, err)
+	}
+
+	if _, err := resolveNativeAmount(ctx, nil, "ethereum", "1.4951", 0.005, balance); !errors.Is(err, ErrNativeReserveViolation) {
+		t.Errorf("expected reserve violation, got %v", err)
+	}
+
+	tiny := func(ctx context.Context) (string, error) { return "0.004", nil }
+	if _, err := resolveNativeAmount(ctx, nil, "bsc", "MAX", 0.005, tiny); !errors.Is(err, ErrNativeReserveViolation) {
+		t.Errorf("expected max below reserve to fail, got %v", err)
+	}
+
+	// Explicit amounts pass when the balance is unknown; max cannot be resolved
+	if amount, err := resolveNativeAmount(ctx, nil, "ethereum", "1", 0.005, unavailable); err != nil || amount != "1" {
+		t.Errorf("expected explicit amount to pass without balance, got %q (%v)", amount, err)
+	}
+	if _, err := resolveNativeAmount(ctx, nil, "ethereum", "max", 0.005, unavailable); !errors.Is(err, ErrBalanceUnavailable) {
+		t.Errorf("expected max to fail without balance, got %v", err)
+	}
+
+	if available, err := AvailableToSend("0.001", 0.005); err != nil || available != "0" {
+		t.Errorf("expected available to floor at 0, got %q (%v)", available, err)
+	}
+}
+
+func TestETHChain_SendTransactionKeepsNativeReserve(t *testing.T) {
+	chain := NewETHChainLegacy()
+	chain.SetNativeReserve(0.01)
+	from := "0x1111111111111111111111111111111111111111"
+	to := "0x2222222222222222222222222222222222222222"
+	key := "0x0000000000000000000000000000000000000000000000000000000000000001"
+
+	// No balance source is reachable, so max cannot be resolved
+	if _, err := chain.SendTransaction(context.Background(), from, to, "max", "ETH", key); err == nil {
+		t.Error("expected max send to fail when the balance is unavailable")
+	}
+	if _, err := chain.SendTransaction(context.Background(), from, to, "max", "0x3333333333333333333333333333333333333333", key); err == nil {
+		t.Error("expected max to be rejected for ERC-20 transfers")
+	}
+	if _, err := chain.SendTransaction(context.Background(), from, to, "0.5", "ETH", key); err != nil {
+		t.Errorf("unexpected error for explicit amount: %v", err)
+	}
+}
@@ -0,0 +1,366 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
+)
+
+// Likelihoods of a ConfirmationTimeEstimate
+const (
+	InclusionLikely   = "likely"
+	InclusionPossible = "possible"
+	InclusionUnlikely = "unlikely"
+)
+
+const (
+	// feeHistoryBlocks is how many recent blocks an EVM estimate samples
+	feeHistoryBlocks = 20
+	// feeHistoryBarPercentile is the reward percentile a tip has to reach to
+	// count as competitive in a block: the cheaper quarter of the block paid less
+	feeHistoryBarPercentile = 25
+	// performanceSamples is how many Solana performance samples, a minute
+	// each, the slot time is averaged over
+	performanceSamples = 5
+)
+
+// ProposedFee is a fee to estimate the confirmation time of. On EVM chains
+// PriorityFee is the tip and MaxFee the fee cap, both in gwei; a zero MaxFee
+// uses twice the next base fee plus the tip. On Solana PriorityFee is the
+// compute unit price in micro-lamports and MaxFee is unused.
+type ProposedFee struct {
+	PriorityFee float64 `json:"priority_fee"`
+	MaxFee      float64 `json:"max_fee,omitempty"`
+}
+
+// ConfirmationTimeEstimate is the expected time for a fee to be included,
+// from how many recent blocks the fee would have made it into
+type ConfirmationTimeEstimate struct {
+	Chain       string  `json:"chain"`
+	PriorityFee float64 `json:"priority_fee"`
+	MaxFee      float64 `json:"max_fee,omitempty"` // EVM fee cap used, defaulted when not proposed
+	FeeUnit     string  `json:"fee_unit"`          // gwei, or micro-lamports per compute unit
+	NextBaseFee float64 `json:"next_base_fee,omitempty"`
+	// MedianPriorityFee is the median fee paid in the sampled blocks, in FeeUnit
+	MedianPriorityFee float64 `json:"median_priority_fee"`
+	// InclusionRate is the share of the sampled blocks the fee would have
+	// been competitive in
+	InclusionRate float64       `json:"inclusion_rate"`
+	SampledBlocks int           `json:"sampled_blocks"`
+	BlockTime     time.Duration `json:"block_time"`
+	Likelihood    string        `json:"likelihood"`
+	// MinBlocks and MaxBlocks are the blocks to inclusion with even and 90%
+	// odds; both are 0 when the fee is not expected to be included at all
+	MinBlocks int           `json:"min_blocks"`
+	MaxBlocks int           `json:"max_blocks"`
+	MinTime   time.Duration `json:"min_time"`
+	MaxTime   time.Duration `json:"max_time"`
+	Reason    string        `json:"reason,omitempty"` // why an unlikely fee falls short
+}
+
+// ConfirmationTimeEstimator is implemented by chains that can estimate the
+// time to inclusion of a fee from recent blocks
+type ConfirmationTimeEstimator interface {
+	EstimateConfirmationTime(ctx context.Context, fee ProposedFee) (*ConfirmationTimeEstimate, error)
+}
+
+// EVMFeeHistory is an eth_feeHistory response in gwei, oldest block first
+type EVMFeeHistory struct {
+	OldestBlock uint64
+	// BaseFees has one more entry than the sampled blocks: the base fee of
+	// the next block
+	BaseFees     []float64
+	GasUsedRatio []float64
+	// Rewards holds the priority fees paid at each requested percentile, per block
+	Rewards [][]float64
+}
+
+// EVMFeeHistoryFunc reads the fee history of the latest blocks at the reward
+// percentiles, as returned by eth_feeHistory
+type EVMFeeHistoryFunc func(ctx context.Context, blocks int, percentiles []float64) (*EVMFeeHistory, error)
+
+// NewEVMFeeHistoryFunc returns an EVMFeeHistoryFunc querying endpoints in
+// order until one answers. chainName is recorded in DefaultRPCHealth on success.
+func NewEVMFeeHistoryFunc(chainName string, endpoints []string) EVMFeeHistoryFunc {
+	client := httpclient.New(chainName+"-rpc", httpclient.WithTimeout(15*time.Second))
+	return func(ctx context.Context, blocks int, percentiles []float64) (*EVMFeeHistory, error) {
+		if len(endpoints) == 0 {
+			return nil, fmt.Errorf("no %s RPC endpoints configured", chainName)
+		}
+		var lastErr error
+		for _, endpoint := range endpoints {
+			history, err := evmFeeHistory(ctx, client, endpoint, blocks, percentiles)
+			if err == nil {
+				DefaultRPCHealth.RecordSuccess(chainName)
+				return history, nil
+			}
+			lastErr = err
+		}
+		return nil, fmt.Errorf("all RPC endpoints failed, last error: %w", lastErr)
+	}
+}
+
+func evmFeeHistory(ctx context.Context, client *http.Client, endpoint string, blocks int, percentiles []float64) (*EVMFeeHistory, error) {
+	params := []any{"0x" + strconv.FormatInt(int64(blocks), 16), "latest", percentiles}
+	body, err := json.Marshal(RPCRequest{JSONRPC: "2.0", ID: 1, Method: "eth_feeHistory", Params: params})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &rpcHTTPError{StatusCode: resp.StatusCode}
+	}
+
+	var response struct {
+		Result *struct {
+			OldestBlock   string     `json:"oldestBlock"`
+			BaseFeePerGas []string   `json:"baseFeePerGas"`
+			GasUsedRatio  []float64  `json:"gasUsedRatio"`
+			Reward        [][]string `json:"reward"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf("RPC error %d: %s", response.Error.Code, response.Error.Message)
+	}
+	if response.Result == nil {
+		return nil, errors.New("empty fee history")
+	}
+
+	history := &EVMFeeHistory{GasUsedRatio: response.Result.GasUsedRatio}
+	if history.OldestBlock, err = strconv.ParseUint(strings.TrimPrefix(response.Result.OldestBlock, "0x"), 16, 64); err != nil {
+		return nil, fmt.Errorf("invalid oldest block %q", response.Result.OldestBlock)
+	}
+	for _, baseFee := range response.Result.BaseFeePerGas {
+		gwei, err := weiHexToGwei(baseFee)
+		if err != nil {
+			return nil, err
+		}
+		history.BaseFees = append(history.BaseFees, gwei)
+	}
+	for _, blockRewards := range response.Result.Reward {
+		rewards := make([]float64, 0, len(blockRewards))
+		for _, reward := range blockRewards {
+			gwei, err := weiHexToGwei(reward)
+			if err != nil {
+				return nil, err
+			}
+			rewards = append(rewards, gwei)
+		}
+		history.Rewards = append(history.Rewards, rewards)
+	}
+	return history, nil
+}
+
+// weiHexToGwei converts a hex quantity of wei to gwei
+func weiHexToGwei(quantity string) (float64, error) {
+	wei, ok := new(big.Int).SetString(strings.TrimPrefix(quantity, "0x"), 16)
+	if !ok {
+		return 0, fmt.Errorf("invalid quantity %q", quantity)
+	}
+	gwei, _ := new(big.Rat).SetFrac(wei, big.NewInt(1e9)).Float64()
+	return gwei, nil
+}
+
+// evmConfirmationTime samples the fee history of chainName and estimates fee against it
+func evmConfirmationTime(ctx context.Context, chainName string, feeHistory EVMFeeHistoryFunc, fee ProposedFee) (*ConfirmationTimeEstimate, error) {
+	if feeHistory == nil {
+		return nil, fmt.Errorf("%s fee history is not configured", chainName)
+	}
+	history, err := feeHistory(ctx, feeHistoryBlocks, []float64{feeHistoryBarPercentile, 50})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee history: %w", err)
+	}
+	return EstimateEVMConfirmationTime(chainName, history, fee, chainTimings[chainName].blockTime)
+}
+
+// EstimateEVMConfirmationTime estimates how soon fee is included on an EVM
+// chain. history must carry the 25th and 50th reward percentiles. A block
+// counts as one the fee would have made if the fee cap covered its base fee
+// and the tip left over reached the block's 25th percentile reward; a fee
+// cap below the next base fee cannot be included until base fees fall.
+func EstimateEVMConfirmationTime(chainName string, history *EVMFeeHistory, fee ProposedFee, blockTime time.Duration) (*ConfirmationTimeEstimate, error) {
+	if fee.PriorityFee < 0 || fee.MaxFee < 0 {
+		return nil, errors.New("fees must not be negative")
+	}
+	blocks := len(history.Rewards)
+	if blocks == 0 || len(history.BaseFees) <= blocks {
+		return nil, errors.New("fee history has no blocks")
+	}
+	nextBaseFee := history.BaseFees[blocks]
+	if fee.MaxFee == 0 {
+		fee.MaxFee = 2*nextBaseFee + fee.PriorityFee
+	}
+	if fee.MaxFee < fee.PriorityFee {
+		return nil, fmt.Errorf("max fee %.4g gwei is below the priority fee %.4g gwei", fee.MaxFee, fee.PriorityFee)
+	}
+
+	included := 0
+	medians := make([]float64, 0, blocks)
+	for i, rewards := range history.Rewards {
+		if len(rewards) < 2 {
+			return nil, fmt.Errorf("fee history block %d has no reward percentiles", i)
+		}
+		medians = append(medians, rewards[1])
+		tip := math.Min(fee.PriorityFee, fee.MaxFee-history.BaseFees[i])
+		if tip >= 0 && tip >= rewards[0] {
+			included++
+		}
+	}
+
+	estimate := &ConfirmationTimeEstimate{
+		Chain:             chainName,
+		PriorityFee:       fee.PriorityFee,
+		MaxFee:            roundGwei(fee.MaxFee),
+		FeeUnit:           "gwei",
+		NextBaseFee:       roundGwei(nextBaseFee),
+		MedianPriorityFee: roundGwei(median(medians)),
+		SampledBlocks:     blocks,
+		BlockTime:         blockTime,
+	}
+	if fee.MaxFee < nextBaseFee {
+		estimate.setInclusion(0)
+		estimate.Reason = fmt.Sprintf("max fee %.4g gwei is below the next base fee %.4g gwei", fee.MaxFee, nextBaseFee)
+		return estimate, nil
+	}
+	estimate.setInclusion(float64(included) / float64(blocks))
+	if included == 0 {
+		estimate.Reason = fmt.Sprintf("the priority fee is below what the cheaper quarter of transactions paid in each of the last %d blocks", blocks)
+	}
+	return estimate, nil
+}
+
+// EstimateSolanaConfirmationTime estimates how soon a compute unit price of
+// fee.PriorityFee micro-lamports lands, from the lowest price that landed in
+// each recent slot
+func EstimateSolanaConfirmationTime(fees []PrioritizationFee, fee ProposedFee, slotTime time.Duration) (*ConfirmationTimeEstimate, error) {
+	if fee.PriorityFee < 0 {
+		return nil, errors.New("fees must not be negative")
+	}
+	if len(fees) == 0 {
+		return nil, errors.New("no recent prioritization fees")
+	}
+	included := 0
+	paid := make([]float64, 0, len(fees))
+	for _, slot := range fees {
+		paid = append(paid, float64(slot.PrioritizationFee))
+		if fee.PriorityFee >= float64(slot.PrioritizationFee) {
+			included++
+		}
+	}
+	estimate := &ConfirmationTimeEstimate{
+		Chain:             "solana",
+		PriorityFee:       fee.PriorityFee,
+		FeeUnit:           "micro-lamports/CU",
+		MedianPriorityFee: median(paid),
+		SampledBlocks:     len(fees),
+		BlockTime:         slotTime,
+	}
+	estimate.setInclusion(float64(included) / float64(len(fees)))
+	if included == 0 {
+		estimate.Reason = fmt.Sprintf("every one of the last %d slots only took higher priority fees", len(fees))
+	}
+	return estimate, nil
+}
+
+// solanaSlotTime averages the slot time of samples, falling back to the
+// nominal slot time without them
+func solanaSlotTime(samples []PerformanceSample) time.Duration {
+	var slots, seconds uint64
+	for _, sample := range samples {
+		slots += sample.NumSlots
+		seconds += sample.SamplePeriodSecs
+	}
+	if slots == 0 || seconds == 0 {
+		return chainTimings["solana"].blockTime
+	}
+	return time.Duration(seconds) * time.Second / time.Duration(slots)
+}
+
+// setInclusion fills the block range for a per-block inclusion chance of
+// rate: blocks are assumed independent, so the wait is geometric
+func (e *ConfirmationTimeEstimate) setInclusion(rate float64) {
+	e.InclusionRate = math.Round(rate*1000) / 1000
+	switch {
+	case rate >= 0.75:
+		e.Likelihood = InclusionLikely
+	case rate >= 0.25:
+		e.Likelihood = InclusionPossible
+	default:
+		e.Likelihood = InclusionUnlikely
+	}
+	if rate <= 0 {
+		e.MinBlocks, e.MaxBlocks, e.MinTime, e.MaxTime = 0, 0, 0, 0
+		return
+	}
+	e.MinBlocks = blocksForOdds(rate, 0.5)
+	e.MaxBlocks = blocksForOdds(rate, 0.9)
+	e.MinTime = time.Duration(e.MinBlocks) * e.BlockTime
+	e.MaxTime = time.Duration(e.MaxBlocks) * e.BlockTime
+}
+
+// blocksForOdds returns the blocks needed for inclusion with odds at a
+// per-block chance of rate
+func blocksForOdds(rate, odds float64) int {
+	if rate >= 1 {
+		return 1
+	}
+	return int(math.Max(1, math.Ceil(math.Log(1-odds)/math.Log(1-rate))))
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// EstimateConfirmationTime estimates how soon a compute unit price of
+// fee.PriorityFee lands from the fees paid in recent slots and their
+// measured slot time
+func (s *SolanaChain) EstimateConfirmationTime(ctx context.Context, fee ProposedFee) (*ConfirmationTimeEstimate, error) {
+	if s.rpcManager == nil {
+		return nil, errors.New("solana RPC is not configured")
+	}
+	fees, err := s.rpcManager.GetRecentPrioritizationFees(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent prioritization fees: %w", err)
+	}
+	samples, err := s.rpcManager.GetRecentPerformanceSamples(ctx, performanceSamples)
+	if err != nil {
+		// The nominal slot time still gives a usable estimate
+		samples = nil
+	}
+	return EstimateSolanaConfirmationTime(fees, fee, solanaSlotTime(samples))
+}
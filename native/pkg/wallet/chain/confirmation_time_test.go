@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateEVMConfirmationTime(t *testing.T) {
+	history := &EVMFeeHistory{
+		OldestBlock: 100,
+		BaseFees:    []float64{10, 10, 12, 12, 12},
+		Rewards:     [][]float64{{1, 2}, {3, 4}, {1, 2}, {1, 2}},
+	}
+
+	estimate, err := EstimateEVMConfirmationTime("ethereum", history, ProposedFee{PriorityFee: 2}, 12*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 26.0, estimate.MaxFee, "defaults to twice the next base fee plus the tip")
+	assert.Equal(t, 12.0, estimate.NextBaseFee)
+	assert.Equal(t, 2.0, estimate.MedianPriorityFee)
+	assert.Equal(t, 0.75, estimate.InclusionRate)
+	assert.Equal(t, InclusionLikely, estimate.Likelihood)
+	assert.Equal(t, 1, estimate.MinBlocks)
+	assert.Equal(t, 2, estimate.MaxBlocks)
+	assert.Equal(t, 12*time.Second, estimate.MinTime)
+	assert.Equal(t, 24*time.Second, estimate.MaxTime)
+	assert.Empty(t, estimate.Reason)
+
+	// A tight fee cap leaves less of the tip once the base fee rises
+	estimate, err = EstimateEVMConfirmationTime("ethereum", history, ProposedFee{PriorityFee: 2, MaxFee: 12.5}, 12*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 0.25, estimate.InclusionRate)
+	assert.Equal(t, InclusionPossible, estimate.Likelihood)
+	assert.Equal(t, 3, estimate.MinBlocks)
+	assert.Equal(t, 9, estimate.MaxBlocks)
+
+	estimate, err = EstimateEVMConfirmationTime("ethereum", history, ProposedFee{PriorityFee: 1, MaxFee: 11}, 12*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, InclusionUnlikely, estimate.Likelihood)
+	assert.Zero(t, estimate.MaxBlocks)
+	assert.Contains(t, estimate.Reason, "below the next base fee")
+
+	estimate, err = EstimateEVMConfirmationTime("ethereum", history, ProposedFee{PriorityFee: 0.5}, 12*time.Second)
+	require.NoError(t, err)
+	assert.Zero(t, estimate.InclusionRate)
+	assert.Contains(t, estimate.Reason, "last 4 blocks")
+
+	_, err = EstimateEVMConfirmationTime("ethereum", history, ProposedFee{PriorityFee: 3, MaxFee: 2}, 12*time.Second)
+	assert.ErrorContains(t, err, "below the priority fee")
+	_, err = EstimateEVMConfirmationTime("ethereum", &EVMFeeHistory{BaseFees: []float64{1}}, ProposedFee{PriorityFee: 1}, 12*time.Second)
+	assert.ErrorContains(t, err, "no blocks")
+}
+
+func TestEstimateSolanaConfirmationTime(t *testing.T) {
+	fees := []PrioritizationFee{{Slot: 1}, {Slot: 2}, {Slot: 3, PrioritizationFee: 100}, {Slot: 4, PrioritizationFee: 1000}, {Slot: 5}}
+	slotTime := solanaSlotTime([]PerformanceSample{{NumSlots: 150, SamplePeriodSecs: 60}})
+	assert.Equal(t, 400*time.Millisecond, slotTime)
+	assert.Equal(t, chainTimings["solana"].blockTime, solanaSlotTime(nil))
+
+	estimate, err := EstimateSolanaConfirmationTime(fees, ProposedFee{PriorityFee: 100}, slotTime)
+	require.NoError(t, err)
+	assert.Equal(t, "solana", estimate.Chain)
+	assert.Equal(t, 0.8, estimate.InclusionRate)
+	assert.Equal(t, InclusionLikely, estimate.Likelihood)
+	assert.Zero(t, estimate.MedianPriorityFee)
+	assert.Equal(t, 1, estimate.MinBlocks)
+	assert.Equal(t, 2, estimate.MaxBlocks)
+	assert.Equal(t, 800*time.Millisecond, estimate.MaxTime)
+
+	estimate, err = EstimateSolanaConfirmationTime(fees[2:4], ProposedFee{PriorityFee: 10}, slotTime)
+	require.NoError(t, err)
+	assert.Equal(t, InclusionUnlikely, estimate.Likelihood)
+	assert.NotEmpty(t, estimate.Reason)
+
+	_, err = EstimateSolanaConfirmationTime(nil, ProposedFee{PriorityFee: 10}, slotTime)
+	assert.Error(t, err)
+}
+
+func TestEVMFeeHistoryFunc(t *testing.T) {
+	server := newRPCTestServer(t, map[string]rpcMethod{
+		"eth_feeHistory": func(call rpcCall) (string, error) {
+			assert.Equal(t, []any{"0x2", "latest", []any{25.0, 50.0}}, call.params())
+			return `{"oldestBlock":"0x64","baseFeePerGas":["0x2540be400","0x2540be400","0x2cb417800"],"gasUsedRatio":[0.5,0.9],"reward":[["0x3b9aca00","0x77359400"],["0x0","0x3b9aca00"]]}`, nil
+		},
+	})
+
+	history, err := NewEVMFeeHistoryFunc("ethereum", []string{"http://127.0.0.1:1", server.URL})(context.Background(), 2, []float64{25, 50})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), history.OldestBlock)
+	assert.Equal(t, []float64{10, 10, 12}, history.BaseFees)
+	assert.Equal(t, []float64{0.5, 0.9}, history.GasUsedRatio)
+	assert.Equal(t, [][]float64{{1, 2}, {0, 1}}, history.Rewards)
+
+	_, err = NewEVMFeeHistoryFunc("bsc", nil)(context.Background(), 2, nil)
+	assert.ErrorContains(t, err, "no bsc RPC endpoints configured")
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	bip39 "github.com/tyler-smith/go-bip39"
+)
+
+// mnemonicEntropyBytes is the entropy behind a 12 word mnemonic
+const mnemonicEntropyBytes = 16
+
+// entropyConfigurable is implemented by chains whose wallet generation reads
+// from a replaceable entropy source
+type entropyConfigurable interface {
+	SetEntropySource(source io.Reader)
+}
+
+// newMnemonic reads 128 bits from source and encodes them as a 12 word
+// mnemonic. A nil source reads from crypto/rand, which is what every chain
+// uses unless a test replaced it.
+func newMnemonic(source io.Reader) (string, error) {
+	if source == nil {
+		source = rand.Reader
+	}
+	entropy := make([]byte, mnemonicEntropyBytes)
+	if _, err := io.ReadFull(source, entropy); err != nil {
+		return "", fmt.Errorf("failed to generate entropy: %w", err)
+	}
+
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate mnemonic: %w", err)
+	}
+	return mnemonic, nil
+}
+
+// seededEntropy is a deterministic byte stream: SHA-256 of the seed followed
+// by a block counter
+type seededEntropy struct {
+	seed    []byte
+	counter uint64
+	block   []byte
+}
+
+// NewSeededEntropy returns a reader that yields the same bytes for the same
+// seed, so tests can pass it to SetEntropySource and assert the exact
+// mnemonic and address CreateWallet produces. Its output is predictable from
+// the seed; it must never back a real wallet.
+func NewSeededEntropy(seed string) io.Reader {
+	return &seededEntropy{seed: []byte(seed)}
+}
+
+// Read fills p from the stream, never failing
+func (s *seededEntropy) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(s.block) == 0 {
+			var counter [8]byte
+			binary.BigEndian.PutUint64(counter[:], s.counter)
+			s.counter++
+			sum := sha256.Sum256(append(append([]byte{}, s.seed...), counter[:]...))
+			s.block = sum[:]
+		}
+		copied := copy(p[n:], s.block)
+		s.block = s.block[copied:]
+		n += copied
+	}
+	return n, nil
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	zeroEntropyMnemonic    = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	fixtureEntropyMnemonic = "east travel code roof strong broom basic cover border gossip plastic corn"
+)
+
+type entropyChain interface {
+	IChain
+	SetEntropySource(source io.Reader)
+}
+
+func TestCreateWalletWithSeededEntropy(t *testing.T) {
+	tests := []struct {
+		name     string
+		chain    entropyChain
+		source   func() io.Reader
+		mnemonic string
+		address  string
+	}{
+		{"eth zero entropy", NewETHChainLegacy(), zeroEntropy, zeroEntropyMnemonic, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"},
+		{"bsc zero entropy", NewBSCChainLegacy(), zeroEntropy, zeroEntropyMnemonic, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"},
+		{"solana zero entropy", NewSolanaChainLegacy(), zeroEntropy, zeroEntropyMnemonic, "EHqmfkN89RJ7Y33CXM6uCzhVeuywHoJXZZLszBHHZy7o"},
+		{"eth seeded", NewETHChainLegacy(), fixtureEntropy, fixtureEntropyMnemonic, "0xD1ebDa6EcDf98c8BF2203fD0F40936162A41eEfe"},
+		{"bsc seeded", NewBSCChainLegacy(), fixtureEntropy, fixtureEntropyMnemonic, "0xD1ebDa6EcDf98c8BF2203fD0F40936162A41eEfe"},
+		{"solana seeded", NewSolanaChainLegacy(), fixtureEntropy, fixtureEntropyMnemonic, "DXuAu24PtBc9deX6ZAfVnewgzPqsVsmbtgGVToLxNwFX"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.chain.SetEntropySource(tt.source())
+			info, err := tt.chain.CreateWallet(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.mnemonic, info.Mnemonic)
+			assert.Equal(t, tt.address, info.Address)
+
+			// Wallets are created on the default import path
+			imported, err := tt.chain.ImportFromMnemonic(context.Background(), info.Mnemonic, "")
+			require.NoError(t, err)
+			assert.Equal(t, info.Address, imported.Address)
+		})
+	}
+}
+
+func TestCreateWalletDefaultsToSecureRandomness(t *testing.T) {
+	chain := NewETHChainLegacy()
+	chain.SetEntropySource(fixtureEntropy())
+	chain.SetEntropySource(nil)
+
+	first, err := chain.CreateWallet(context.Background())
+	require.NoError(t, err)
+	second, err := chain.CreateWallet(context.Background())
+	require.NoError(t, err)
+	assert.NotEqual(t, fixtureEntropyMnemonic, first.Mnemonic)
+	assert.NotEqual(t, first.Mnemonic, second.Mnemonic)
+}
+
+func TestCreateWalletFailsOnShortEntropy(t *testing.T) {
+	chain := NewSolanaChainLegacy()
+	chain.SetEntropySource(bytes.NewReader(make([]byte, 8)))
+
+	_, err := chain.CreateWallet(context.Background())
+	assert.ErrorContains(t, err, "failed to generate entropy")
+}
+
+func TestSeededEntropyIsReproducible(t *testing.T) {
+	first := make([]byte, 100)
+	second := make([]byte, 100)
+	_, err := io.ReadFull(NewSeededEntropy("fixture"), first)
+	require.NoError(t, err)
+	_, err = io.ReadFull(NewSeededEntropy("fixture"), second)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	other := make([]byte, 100)
+	_, err = io.ReadFull(NewSeededEntropy("other"), other)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, other)
+}
+
+func TestChainFactorySetEntropySource(t *testing.T) {
+	factory := NewChainFactory()
+
+	for _, name := range []string{"ETH", "ETHEREUM", "BSC", "SOLANA"} {
+		// Chains share the reader, so each wallet gets a fresh one
+		factory.SetEntropySource(fixtureEntropy())
+		chain, err := factory.GetChain(name)
+		require.NoError(t, err)
+		info, err := chain.CreateWallet(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, fixtureEntropyMnemonic, info.Mnemonic, name)
+	}
+}
+
+func zeroEntropy() io.Reader {
+	return bytes.NewReader(make([]byte, mnemonicEntropyBytes))
+}
+
+func fixtureEntropy() io.Reader {
+	return NewSeededEntropy("fixture")
+}
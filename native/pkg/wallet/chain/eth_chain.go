@@ -3,9 +3,11 @@ package chain
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"errors"
 	"fmt"
+	"io"
+	"math/big"
+	"os"
 	"strings"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	bip39 "github.com/tyler-smith/go-bip39"
+	"github.com/algonius/algonius-wallet/native/pkg/config"
 	"github.com/algonius/algonius-wallet/native/pkg/dex"
 	"go.uber.org/zap"
 )
@@ -23,6 +26,18 @@ type ETHChain struct {
 	dexAggregator dex.IDEXAggregator
 	logger       *zap.Logger
 	chainID      string
+	balanceConfig config.BalanceConfig
+	gasStrategy   string
+	maxFeeGwei    float64
+	nativeReserve float64
+	recipients    *evmRecipientCheck
+	preflight     EVMCallFunc
+	transferFees  *evmTransferFeeCheck
+	feeHistory    EVMFeeHistoryFunc
+	quantity      EVMQuantityFunc
+	confirmations *evmConfirmationRPC
+	rpc           *evmClientPool
+	entropy       io.Reader
 }
 
 // NewETHChain creates a new ETH chain instance
@@ -43,60 +58,132 @@ func NewETHChainLegacy() *ETHChain {
 	}
 }
 
-// GetChainName returns the name of the chain
-func (e *ETHChain) GetChainName() string {
-	return e.name
+// SetEntropySource replaces the randomness CreateWallet draws mnemonics
+// from. nil restores crypto/rand.
+func (e *ETHChain) SetEntropySource(source io.Reader) {
+	e.entropy = source
 }
 
-// CreateWallet generates a new Ethereum wallet
-func (e *ETHChain) CreateWallet(ctx context.Context) (*WalletInfo, error) {
-	// Generate entropy for mnemonic
-	entropy, err := bip39.NewEntropy(128) // 128 bits = 12 words
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate entropy: %w", err)
-	}
+// SetBalanceConfig sets the balance source ordering used by GetBalance
+func (e *ETHChain) SetBalanceConfig(cfg config.BalanceConfig) {
+	e.balanceConfig = cfg
+}
 
-	// Generate mnemonic phrase
-	mnemonic, err := bip39.NewMnemonic(entropy)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate mnemonic: %w", err)
-	}
+// SetRecipientCheck enables ClassifyRecipient, reading contract code through
+// getCode. Token transfers to safeContracts are not flagged.
+func (e *ETHChain) SetRecipientCheck(getCode EVMCodeFunc, safeContracts []string) {
+	e.recipients = newEVMRecipientCheck(getCode, safeContracts)
+}
 
-	// Generate seed from mnemonic
-	seed := bip39.NewSeed(mnemonic, "")
+// ClassifyRecipient reports whether address is an EOA or a contract, flagging
+// token transfers to contracts that are not known to handle them
+func (e *ETHChain) ClassifyRecipient(ctx context.Context, address, token string) (*RecipientClassification, error) {
+	return e.recipients.classify(ctx, address, token, "ETH")
+}
 
-	// For simplicity, we'll use the first 32 bytes as private key
-	// In a production system, you'd want to use proper HD wallet derivation
-	if len(seed) < 32 {
-		return nil, errors.New("insufficient seed length")
-	}
+// SetRPCEndpoints makes GetBalance read balances from endpoints, failing over
+// between them without retrying
+func (e *ETHChain) SetRPCEndpoints(endpoints []string) {
+	e.setRPCClient(newETHRPCClient("ethereum", endpoints, config.RPCRetryConfig{}))
+}
 
-	// Create private key from seed
-	privateKey, err := crypto.ToECDSA(seed[:32])
-	if err != nil {
-		return nil, fmt.Errorf("failed to create private key: %w", err)
+// setRPCClient makes GetBalance read balances through rpc
+func (e *ETHChain) setRPCClient(rpc *ethRPCClient) {
+	e.rpc = newEVMClientPool(rpc)
+}
+
+// SetPreflight enables SimulateSend, dry-running sends through call
+func (e *ETHChain) SetPreflight(call EVMCallFunc) {
+	e.preflight = call
+}
+
+// SetFeeHistory enables EstimateConfirmationTime, sampling recent blocks through feeHistory
+func (e *ETHChain) SetFeeHistory(feeHistory EVMFeeHistoryFunc) {
+	e.feeHistory = feeHistory
+}
+
+// SetGasRPC makes EstimateGas price sends from the network, reading gas
+// prices and limits through quantity
+func (e *ETHChain) SetGasRPC(quantity EVMQuantityFunc) {
+	e.quantity = quantity
+}
+
+// SetConfirmationRPC makes ConfirmTransaction read receipts through
+// receipts, the latest block number through quantity and block timestamps
+// through blocks
+func (e *ETHChain) SetConfirmationRPC(receipts EVMReceiptFunc, quantity EVMQuantityFunc, blocks BlockHeadFunc) {
+	e.confirmations = &evmConfirmationRPC{receipts: receipts, quantity: quantity, blocks: blocks}
+}
+
+// EstimateConfirmationTime estimates how soon fee is included from the fees paid in recent blocks
+func (e *ETHChain) EstimateConfirmationTime(ctx context.Context, fee ProposedFee) (*ConfirmationTimeEstimate, error) {
+	return evmConfirmationTime(ctx, "ethereum", e.feeHistory, fee)
+}
+
+// SimulateSend runs the send as an eth_call and returns a *SimulationError
+// with the decoded revert reason when it would revert
+func (e *ETHChain) SimulateSend(ctx context.Context, from, to, amount, token string) error {
+	if e.preflight == nil {
+		return ErrSimulationUnavailable
 	}
+	token = strings.TrimSpace(token)
+	if token == "" || strings.EqualFold(token, "ETH") {
+		resolved, err := e.spendableAmount(ctx, from, amount)
+		if err != nil {
+			return err
+		}
+		amount = resolved
+	} else if IsMaxAmount(amount) {
+		return ErrSimulationUnavailable
+	}
+	return simulateEVMSend(ctx, e.preflight, from, to, amount, token, "ETH")
+}
+
+// SetTransferFeeCheck enables DetectTransferFee. Tokens in known are reported
+// with their configured fee percent; others are simulated through simulate,
+// reading their decimals through call.
+func (e *ETHChain) SetTransferFeeCheck(call EVMCallFunc, simulate EVMSimulateFunc, known map[string]float64) {
+	e.transferFees = newEVMTransferFeeCheck(call, simulate, known)
+}
 
-	// Derive public key
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
+// DetectTransferFee reports the fee a fee-on-transfer token takes from a send
+func (e *ETHChain) DetectTransferFee(ctx context.Context, from, to, amount, token string) (*TokenTransferFee, error) {
+	return e.transferFees.detect(ctx, from, to, amount, token, "ETH")
+}
+
+// DetectPermitSupport reads the EIP-2612 and EIP-3009 support of token
+func (e *ETHChain) DetectPermitSupport(ctx context.Context, token, owner string) (*TokenPermitSupport, error) {
+	chainID, ok := new(big.Int).SetString(e.chainID, 10)
 	if !ok {
-		return nil, errors.New("failed to get public key")
+		return nil, fmt.Errorf("invalid chain ID %q", e.chainID)
 	}
+	return DetectPermitSupport(ctx, e.preflight, chainID, token, owner)
+}
 
-	// Generate Ethereum address
-	address := crypto.PubkeyToAddress(*publicKeyECDSA)
+// SignPermit signs a gasless approval of request.Token with privateKey
+func (e *ETHChain) SignPermit(ctx context.Context, request PermitRequest, privateKey string) (*SignedPermit, error) {
+	support, err := e.DetectPermitSupport(ctx, request.Token, request.Owner)
+	if err != nil {
+		return nil, err
+	}
+	return SignPermitRequest(support, request, privateKey)
+}
 
-	// Convert keys to hex strings
-	privateKeyHex := hexutil.Encode(crypto.FromECDSA(privateKey))
-	publicKeyHex := hexutil.Encode(crypto.FromECDSAPub(publicKeyECDSA))
+// GetChainName returns the name of the chain
+func (e *ETHChain) GetChainName() string {
+	return e.name
+}
 
-	return &WalletInfo{
-		Address:    address.Hex(),
-		PublicKey:  publicKeyHex,
-		PrivateKey: privateKeyHex,
-		Mnemonic:   mnemonic,
-	}, nil
+// CreateWallet generates a new Ethereum wallet
+func (e *ETHChain) CreateWallet(ctx context.Context) (*WalletInfo, error) {
+	// Generate mnemonic phrase
+	mnemonic, err := newMnemonic(e.entropy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Derive the first account along the default BIP-44 path
+	return deriveEVMWallet(mnemonic, fmt.Sprintf(EVMAccountPathFormat, 0))
 }
 
 // ImportFromMnemonic imports a wallet from mnemonic phrase with derivation path
@@ -106,46 +193,12 @@ func (e *ETHChain) ImportFromMnemonic(ctx context.Context, mnemonic, derivationP
 		return nil, errors.New("invalid mnemonic phrase")
 	}
 	
-	// Generate seed from mnemonic
-	seed := bip39.NewSeed(mnemonic, "")
-	
 	// Use default derivation path if not provided
 	if derivationPath == "" {
 		derivationPath = "m/44'/60'/0'/0/0" // Ethereum default
 	}
 	
-	// For simplicity, use the seed to derive the private key
-	// In production, you'd implement proper HD wallet derivation with the path
-	if len(seed) < 32 {
-		return nil, errors.New("insufficient seed length")
-	}
-	
-	// Create private key from seed
-	privateKey, err := crypto.ToECDSA(seed[:32])
-	if err != nil {
-		return nil, fmt.Errorf("failed to create private key: %w", err)
-	}
-	
-	// Derive public key
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return nil, errors.New("failed to get public key")
-	}
-	
-	// Generate Ethereum address
-	address := crypto.PubkeyToAddress(*publicKeyECDSA)
-	
-	// Convert keys to hex strings
-	privateKeyHex := hexutil.Encode(crypto.FromECDSA(privateKey))
-	publicKeyHex := hexutil.Encode(crypto.FromECDSAPub(publicKeyECDSA))
-	
-	return &WalletInfo{
-		Address:    address.Hex(),
-		PublicKey:  publicKeyHex,
-		PrivateKey: privateKeyHex,
-		Mnemonic:   mnemonic,
-	}, nil
+	return deriveEVMWallet(mnemonic, derivationPath)
 }
 
 // GetBalance retrieves the balance for an Ethereum address
@@ -173,39 +226,41 @@ func (e *ETHChain) GetBalance(ctx context.Context, address string, token string)
 		if !common.IsHexAddress(token) {
 			return "", fmt.Errorf("unsupported token: %s", token)
 		}
-		// For now, we'll treat any valid hex address as a potential ERC-20 token
-		// TODO: In a real implementation, verify it's a valid ERC-20 contract
-	}
-
-	// Try to get balance using DEX aggregator if available
-	if e.dexAggregator != nil {
-		providers := e.dexAggregator.GetSupportedProviders(e.chainID)
-		if len(providers) > 0 {
-			// Try first available provider
-			provider, err := e.dexAggregator.GetProviderByName(providers[0])
-			if err == nil {
-				balanceInfo, err := provider.GetBalance(ctx, address, token, e.chainID)
-				if err == nil {
-					e.logger.Debug("Balance retrieved via DEX provider",
-						zap.String("provider", providers[0]),
-						zap.String("balance", balanceInfo.Balance))
-					return balanceInfo.Balance, nil
-				}
-				e.logger.Warn("DEX provider balance failed, falling back to mock",
-					zap.String("provider", providers[0]),
-					zap.Error(err))
+		// An address without an ERC-20 contract has no balance to report,
+		// not a zero one. When the RPC is unreachable the sources below
+		// decide.
+		if e.rpc != nil {
+			if _, err := e.rpc.tokenDecimals(ctx, token); errors.Is(err, ErrInvalidTokenContract) {
+				return "", err
 			}
 		}
 	}
 
-	// TODO: Implement actual balance retrieval from Ethereum node
-	// This is a mock implementation that returns "0"
-	// In a real implementation, you would:
-	// 1. Connect to an Ethereum node (Infura, Alchemy, etc.)
-	// 2. For ETH: Use ethclient.BalanceAt() to get the balance
-	// 3. For ERC-20: Use the contract's balanceOf function
-	// 4. Convert from Wei to Ether or token decimals
-	return "0", nil
+	return e.fetchBalance(ctx, address, token, e.balanceConfig)
+}
+
+// fetchBalance reads the balance of address through the sources ordered by cfg
+func (e *ETHChain) fetchBalance(ctx context.Context, address, token string, cfg config.BalanceConfig) (string, error) {
+	return fetchBalance(ctx, cfg, e.logger, "ethereum",
+		func(ctx context.Context) (string, error) {
+			if e.rpc == nil {
+				return "", errors.New("no ethereum RPC endpoints configured")
+			}
+			if common.IsHexAddress(token) {
+				return e.rpc.tokenBalance(ctx, address, token)
+			}
+			return e.rpc.nativeBalance(ctx, address)
+		},
+		func(ctx context.Context) (string, error) {
+			balance, provider, err := getDEXBalance(ctx, e.dexAggregator, e.chainID, address, token)
+			if err != nil {
+				return "", err
+			}
+			e.logger.Debug("Balance retrieved via DEX provider",
+				zap.String("provider", provider),
+				zap.String("balance", balance))
+			return balance, nil
+		})
 }
 
 // SendTransaction sends a transaction on the Ethereum network
@@ -263,6 +318,19 @@ func (e *ETHChain) SendTransaction(ctx context.Context, from, to string, amount
 		return "", errors.New("cannot send to the same address")
 	}
 
+	// Keep the gas reserve: resolve "max" and refuse sends that would dip into it
+	if isERC20 {
+		if IsMaxAmount(amount) {
+			return "", errors.New("max amount is only supported for native ETH transfers")
+		}
+	} else {
+		resolved, err := e.spendableAmount(ctx, from, amount)
+		if err != nil {
+			return "", err
+		}
+		amount = resolved
+	}
+
 	// Try to execute swap using DEX aggregator if it's a token swap
 	if e.dexAggregator != nil && isERC20 {
 		swapParams := dex.SwapParams{
@@ -319,6 +387,82 @@ func (e *ETHChain) SendTransaction(ctx context.Context, from, to string, amount
 	return hash.Hex(), nil
 }
 
+// SetNativeReserve sets the amount of ETH that native sends must leave for gas
+func (e *ETHChain) SetNativeReserve(reserve float64) {
+	e.nativeReserve = reserve
+}
+
+// NativeReserve returns the amount of ETH kept back for gas
+func (e *ETHChain) NativeReserve() float64 {
+	return e.nativeReserve
+}
+
+// spendableAmount resolves a native send amount against the gas reserve
+func (e *ETHChain) spendableAmount(ctx context.Context, from, amount string) (string, error) {
+	return resolveNativeAmount(ctx, e.logger, "ethereum", amount, e.nativeReserve, func(ctx context.Context) (string, error) {
+		// Unlike GetBalance, never fall back to "0": an unknown balance must not look empty
+		cfg := e.balanceConfig
+		cfg.FailOnUnavailable = true
+		return e.fetchBalance(ctx, from, "ETH", cfg)
+	})
+}
+
+// WrapNative deposits amount ETH of owner into WETH
+func (e *ETHChain) WrapNative(ctx context.Context, owner, amount, privateKey string) (*WrapResult, error) {
+	return e.nativeWrap().wrap(ctx, owner, amount, privateKey)
+}
+
+// UnwrapNative withdraws amount WETH of owner back into ETH
+func (e *ETHChain) UnwrapNative(ctx context.Context, owner, amount, privateKey string) (*WrapResult, error) {
+	return e.nativeWrap().unwrap(ctx, owner, amount, privateKey)
+}
+
+func (e *ETHChain) nativeWrap() evmNativeWrap {
+	return evmNativeWrap{
+		chainName: "ethereum",
+		native:    "ETH",
+		balance: func(ctx context.Context, address, token string) (string, error) {
+			cfg := e.balanceConfig
+			cfg.FailOnUnavailable = true
+			return e.fetchBalance(ctx, address, token, cfg)
+		},
+		spendable: e.spendableAmount,
+	}
+}
+
+// SetGasConfig sets the default gas strategy and the max fee ceiling in gwei (0 disables the ceiling)
+func (e *ETHChain) SetGasConfig(strategy string, maxFeeGwei float64) {
+	e.gasStrategy = strategy
+	e.maxFeeGwei = maxFeeGwei
+}
+
+// SampleMempool returns recent base fees and the median priority fee for
+// Ethereum from the fee history. Without fee history configured it returns a
+// fixed sample.
+func (e *ETHChain) SampleMempool(ctx context.Context) (MempoolSample, error) {
+	if e.feeHistory != nil {
+		return sampleEVMFeeMarket(ctx, e.feeHistory, e.quantity)
+	}
+	return MempoolSample{
+		BaseFeesGwei:    []float64{18.2, 18.9, 19.4, 20.1, 20.6},
+		PriorityFeeGwei: 1.5,
+		PendingTxCount:  6500,
+		NormalPoolDepth: 5000,
+	}, nil
+}
+
+// SuggestGasParams prices a Ethereum transaction for strategy, falling back to the configured strategy
+func (e *ETHChain) SuggestGasParams(ctx context.Context, strategy string) (*GasParams, error) {
+	if strategy == "" {
+		strategy = e.gasStrategy
+	}
+	sample, err := e.SampleMempool(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample mempool: %w", err)
+	}
+	return ComputeGasParams(strategy, sample, e.maxFeeGwei)
+}
+
 // EstimateGas estimates gas requirements for an Ethereum transaction
 func (e *ETHChain) EstimateGas(ctx context.Context, from, to string, amount string, token string) (gasLimit uint64, gasPrice string, err error) {
 	// Validate addresses
@@ -334,20 +478,20 @@ func (e *ETHChain) EstimateGas(ctx context.Context, from, to string, amount stri
 	if token == "" {
 		token = "ETH"
 	}
+	baseGasLimit, err := defaultEVMGasLimit(token, "ETH")
+	if err != nil {
+		return 0, "", err
+	}
 
-	// Basic gas estimation based on transaction type
-	var baseGasLimit uint64
-	var baseGasPrice string = "20" // 20 gwei as default
-
-	if strings.ToUpper(token) == "ETH" {
-		// Simple ETH transfer
-		baseGasLimit = 21000
-	} else {
-		// ERC-20 token transfer requires more gas
-		if !common.IsHexAddress(token) {
-			return 0, "", fmt.Errorf("invalid token contract address: %s", token)
+	// Price the send from the network when its RPC is configured
+	if e.quantity != nil {
+		estimate, err := e.EstimateGasFees(ctx, from, to, amount, token)
+		if err == nil {
+			return estimate.GasLimit, estimate.GasPrice(), nil
+		}
+		if e.logger != nil {
+			e.logger.Warn("Network gas estimate failed, falling back", zap.Error(err))
 		}
-		baseGasLimit = 65000 // Typical gas for ERC-20 transfer
 	}
 
 	// Try to get gas estimate from DEX aggregator if available
@@ -377,14 +521,84 @@ func (e *ETHChain) EstimateGas(ctx context.Context, from, to string, amount stri
 		}
 	}
 
-	// TODO: In a real implementation, you would:
-	// 1. Connect to an Ethereum node
-	// 2. Use eth_estimateGas to get actual gas estimate
-	// 3. Get current gas price from the network
-	// 4. Apply safety multipliers (e.g., 1.2x for gas limit)
-	// 5. Check for network congestion and adjust gas price
+	// Typical gas and 20 gwei when neither the node nor a DEX provider answered
+	return baseGasLimit, "20", nil
+}
 
-	return baseGasLimit, baseGasPrice, nil
+// EstimateCallGas estimates the gas of a contract call with eth_estimateGas; a
+// call the node expects to revert fails
+func (e *ETHChain) EstimateCallGas(ctx context.Context, call EVMCall) (uint64, error) {
+	if !common.IsHexAddress(call.From) || !common.IsHexAddress(call.To) {
+		return 0, errors.New("invalid call address format")
+	}
+	if e.quantity == nil {
+		return 0, ErrCallGasUnavailable
+	}
+	return evmCallGas(ctx, e.quantity, call)
+}
+
+// EstimateGasFees prices a send from the network: eth_estimateGas against the
+// transfer's call data for the gas limit, eth_gasPrice for the legacy price
+// and the fee history, priced with the configured gas strategy, for the
+// EIP-1559 fees. The typical gas of the send is used when the node cannot
+// estimate it, e.g. because the sender lacks the funds.
+func (e *ETHChain) EstimateGasFees(ctx context.Context, from, to, amount, token string) (*GasEstimate, error) {
+	if !common.IsHexAddress(from) {
+		return nil, errors.New("invalid from address format")
+	}
+	if !common.IsHexAddress(to) {
+		return nil, errors.New("invalid to address format")
+	}
+	token = strings.TrimSpace(token)
+	gasLimit, err := defaultEVMGasLimit(token, "ETH")
+	if err != nil {
+		return nil, err
+	}
+	if e.quantity == nil {
+		return nil, errors.New("no ethereum RPC endpoints configured for gas estimates")
+	}
+
+	if limit, err := estimateEVMGasLimit(ctx, e.quantity, e.preflight, from, to, amount, token, "ETH"); err == nil {
+		gasLimit = limit
+	} else if e.logger != nil {
+		e.logger.Debug("eth_estimateGas failed, using the typical gas limit", zap.Error(err))
+	}
+	estimate := &GasEstimate{GasLimit: gasLimit}
+
+	var feeErr error
+	if e.feeHistory != nil {
+		var params *GasParams
+		sample, err := sampleEVMFeeMarket(ctx, e.feeHistory, e.quantity)
+		if err == nil {
+			params, err = ComputeGasParams(e.gasStrategy, sample, e.maxFeeGwei)
+		}
+		if err == nil {
+			estimate.Strategy = params.Strategy
+			estimate.BaseFeeGwei = params.BaseFeeGwei
+			estimate.PriorityFeeGwei = params.PriorityFeeGwei
+			estimate.MaxFeeGwei = params.MaxFeeGwei
+		}
+		feeErr = err
+	}
+
+	gasPrice, err := e.quantity(ctx, "eth_gasPrice")
+	if err == nil {
+		estimate.GasPriceGwei = weiToGwei(gasPrice)
+	} else if estimate.MaxFeeGwei == 0 {
+		if feeErr != nil {
+			return nil, fmt.Errorf("failed to get gas price: %w (fee history: %v)", err, feeErr)
+		}
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	// A legacy send bids as much as the strategy's EIP-1559 fees would pay
+	if bid := estimate.BaseFeeGwei + estimate.PriorityFeeGwei; bid > estimate.GasPriceGwei {
+		estimate.GasPriceGwei = bid
+	}
+	if e.maxFeeGwei > 0 && estimate.GasPriceGwei > e.maxFeeGwei {
+		estimate.GasPriceGwei = e.maxFeeGwei
+	}
+	estimate.GasPriceGwei = roundGwei(estimate.GasPriceGwei)
+	return estimate, nil
 }
 
 // ConfirmTransaction checks the confirmation status of an Ethereum transaction
@@ -415,18 +629,17 @@ func (e *ETHChain) ConfirmTransaction(ctx context.Context, txHash string, requir
 		requiredConfirmations = 6 // Default for Ethereum
 	}
 
-	// TODO: Implement actual transaction confirmation checking
-	// This is a mock implementation for development purposes
-	// In a real implementation, you would:
-	// 1. Connect to an Ethereum node (ethclient.Dial)
-	// 2. Get transaction receipt using client.TransactionReceipt(ctx, common.HexToHash(txHash))
-	// 3. Get current block number using client.BlockNumber(ctx)
-	// 4. Calculate confirmations = currentBlock - txReceipt.BlockNumber
-	// 5. Get block details for timestamp using client.BlockByNumber()
-	// 6. Return actual transaction details
-
-	// For development purposes, simulate different transaction states
-	// This will be replaced with actual blockchain queries
+	// Test runs are not connected to a node
+	if os.Getenv("RUN_MODE") == "test" {
+		return simulatedETHConfirmation(txHash, requiredConfirmations), nil
+	}
+
+	return e.confirmations.confirm(ctx, "ethereum", txHash, requiredConfirmations)
+}
+
+// simulatedETHConfirmation derives a stable status from the hash of txHash
+// for test runs
+func simulatedETHConfirmation(txHash string, requiredConfirmations uint64) *TransactionConfirmation {
 	var status string
 	var confirmations uint64
 	var blockNumber uint64 = 18500000 // Mock block number
@@ -456,14 +669,18 @@ func (e *ETHChain) ConfirmTransaction(ctx context.Context, txHash string, requir
 		transactionFee = "0.001040" // Higher fee for token transfers
 	}
 
+	fee, _ := NewFee("ethereum", transactionFee)
+
 	return &TransactionConfirmation{
 		Status:                status,
 		Confirmations:         confirmations,
 		RequiredConfirmations: requiredConfirmations,
 		BlockNumber:           blockNumber,
+		BlockHash:             mockBlockHash("ethereum", blockNumber),
 		GasUsed:               gasUsed,
 		TransactionFee:        transactionFee,
+		Fee:                   fee,
 		Timestamp:             timestamp,
 		TxHash:                txHash,
-	}, nil
+	}
 }
@@ -0,0 +1,294 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// An endpoint failing ethEndpointFailureThreshold calls in a row is skipped
+// for ethEndpointCooldown, unless every endpoint is
+const (
+	ethEndpointFailureThreshold = 3
+	ethEndpointCooldown         = 30 * time.Second
+)
+
+// ethMaxRetryDelay caps the exponential backoff between retries
+const ethMaxRetryDelay = 10 * time.Second
+
+// rpcHTTPError is returned when an RPC endpoint answers with a status other than 200
+type rpcHTTPError struct {
+	StatusCode int
+}
+
+func (e *rpcHTTPError) Error() string {
+	return fmt.Sprintf("HTTP error: %d", e.StatusCode)
+}
+
+// ethEndpointHealth counts the consecutive failures of an endpoint
+type ethEndpointHealth struct {
+	failures       int
+	unhealthyUntil time.Time
+}
+
+// ethRPCClient spreads calls across the RPC endpoints of an EVM chain. Each
+// call starts at the next endpoint in turn and fails over to the others; once
+// all failed, it is retried with exponential backoff while the failures are
+// transient. Endpoints failing repeatedly are left out for a while.
+type ethRPCClient struct {
+	chainName  string
+	endpoints  []string
+	httpClient *http.Client
+	maxRetries int
+	baseDelay  time.Duration
+
+	mu     sync.Mutex
+	next   int
+	health map[string]*ethEndpointHealth
+	now    func() time.Time
+}
+
+func newETHRPCClient(chainName string, endpoints []string, retry config.RPCRetryConfig) *ethRPCClient {
+	return &ethRPCClient{
+		chainName: chainName,
+		endpoints: endpoints,
+		// Retries are left to do, which knows the other endpoints
+		httpClient: httpclient.New(chainName+"-rpc", httpclient.WithTimeout(15*time.Second), httpclient.WithRetries(0)),
+		maxRetries: retry.MaxRetries,
+		baseDelay:  retry.BaseDelay,
+		health:     make(map[string]*ethEndpointHealth),
+		now:        time.Now,
+	}
+}
+
+// do runs attempt against the endpoints until one answers. An error from the
+// node itself, such as a reverted call, is an answer and is returned without
+// trying the others.
+func (c *ethRPCClient) do(ctx context.Context, attempt func(endpoint string) error) error {
+	if len(c.endpoints) == 0 {
+		return fmt.Errorf("no %s RPC endpoints configured", c.chainName)
+	}
+	var lastErr error
+	for retry := 0; ; retry++ {
+		for _, endpoint := range c.order() {
+			err := attempt(endpoint)
+			if isRPCAnswer(err) {
+				c.succeeded(endpoint)
+				DefaultRPCHealth.RecordSuccess(c.chainName)
+				return err
+			}
+			c.failed(endpoint)
+			lastErr = err
+			if ctx.Err() != nil {
+				return fmt.Errorf("all RPC endpoints failed, last error: %w", lastErr)
+			}
+		}
+		if retry >= c.maxRetries || !isTransientRPCError(lastErr) {
+			break
+		}
+		if err := sleepContext(ctx, c.retryDelay(retry)); err != nil {
+			break
+		}
+	}
+	return fmt.Errorf("all RPC endpoints failed, last error: %w", lastErr)
+}
+
+// order returns the healthy endpoints starting from the next one in turn, or
+// all of them when none is healthy
+func (c *ethRPCClient) order() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	start := c.next % len(c.endpoints)
+	c.next++
+	now := c.now()
+	healthy := make([]string, 0, len(c.endpoints))
+	all := make([]string, 0, len(c.endpoints))
+	for i := range c.endpoints {
+		endpoint := c.endpoints[(start+i)%len(c.endpoints)]
+		all = append(all, endpoint)
+		if health := c.health[endpoint]; health == nil || !now.Before(health.unhealthyUntil) {
+			healthy = append(healthy, endpoint)
+		}
+	}
+	if len(healthy) == 0 {
+		return all
+	}
+	return healthy
+}
+
+func (c *ethRPCClient) succeeded(endpoint string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.health, endpoint)
+}
+
+func (c *ethRPCClient) failed(endpoint string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	health := c.health[endpoint]
+	if health == nil {
+		health = &ethEndpointHealth{}
+		c.health[endpoint] = health
+	}
+	health.failures++
+	if health.failures >= ethEndpointFailureThreshold {
+		health.unhealthyUntil = c.now().Add(ethEndpointCooldown)
+	}
+}
+
+// retryDelay returns the wait before retry, doubling the base delay each time
+func (c *ethRPCClient) retryDelay(retry int) time.Duration {
+	delay := c.baseDelay
+	for i := 0; i < retry && delay < ethMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, ethMaxRetryDelay)
+}
+
+// isRPCAnswer reports whether err came from the node rather than from
+// reaching it
+func isRPCAnswer(err error) bool {
+	var callErr *evmCallError
+	var rpcErr rpc.Error
+	return err == nil || errors.As(err, &callErr) || errors.As(err, &rpcErr) ||
+		errors.Is(err, ErrInvalidTokenContract) || errors.Is(err, errBlockNotFound) ||
+		errors.Is(err, ErrTransactionNotFound)
+}
+
+// isTransientRPCError reports whether err may go away on its own: the
+// endpoint is rate limiting or failing (HTTP 429 or 5xx), dropped the
+// connection or timed out
+func isTransientRPCError(err error) bool {
+	var httpErr *rpcHTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+	var gethHTTPErr rpc.HTTPError
+	if errors.As(err, &gethHTTPErr) {
+		return gethHTTPErr.StatusCode == http.StatusTooManyRequests || gethHTTPErr.StatusCode >= 500
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// quantity is an EVMQuantityFunc
+func (c *ethRPCClient) quantity(ctx context.Context, method string, params ...any) (*big.Int, error) {
+	if params == nil {
+		params = []any{}
+	}
+	var quantity *big.Int
+	err := c.do(ctx, func(endpoint string) error {
+		var err error
+		quantity, err = evmQuantity(ctx, c.httpClient, endpoint, method, params)
+		return err
+	})
+	return quantity, err
+}
+
+// call is an EVMCallFunc
+func (c *ethRPCClient) call(ctx context.Context, call EVMCall) (string, error) {
+	if len(c.endpoints) == 0 {
+		return "", fmt.Errorf("%w: no %s RPC endpoints configured", ErrSimulationUnavailable, c.chainName)
+	}
+	var output string
+	err := c.do(ctx, func(endpoint string) error {
+		var err error
+		output, err = evmCall(ctx, c.httpClient, endpoint, call)
+		return err
+	})
+	return output, err
+}
+
+// simulate is an EVMSimulateFunc
+func (c *ethRPCClient) simulate(ctx context.Context, call EVMCall) ([]EVMLog, error) {
+	if len(c.endpoints) == 0 {
+		return nil, fmt.Errorf("%w: no %s RPC endpoints configured", ErrSimulationUnavailable, c.chainName)
+	}
+	var logs []EVMLog
+	err := c.do(ctx, func(endpoint string) error {
+		var err error
+		logs, err = evmSimulate(ctx, c.httpClient, endpoint, call)
+		return err
+	})
+	var callErr *evmCallError
+	if err != nil && !errors.As(err, &callErr) {
+		return nil, fmt.Errorf("%w: %v", ErrSimulationUnavailable, err)
+	}
+	return logs, err
+}
+
+// code is an EVMCodeFunc
+func (c *ethRPCClient) code(ctx context.Context, address string) (string, error) {
+	var code string
+	err := c.do(ctx, func(endpoint string) error {
+		var err error
+		code, err = evmGetCode(ctx, c.httpClient, endpoint, address)
+		return err
+	})
+	return code, err
+}
+
+// feeHistory is an EVMFeeHistoryFunc
+func (c *ethRPCClient) feeHistory(ctx context.Context, blocks int, percentiles []float64) (*EVMFeeHistory, error) {
+	var history *EVMFeeHistory
+	err := c.do(ctx, func(endpoint string) error {
+		var err error
+		history, err = evmFeeHistory(ctx, c.httpClient, endpoint, blocks, percentiles)
+		return err
+	})
+	return history, err
+}
+
+// receipt is an EVMReceiptFunc
+func (c *ethRPCClient) receipt(ctx context.Context, txHash string) (*EVMReceipt, error) {
+	var receipt *EVMReceipt
+	err := c.do(ctx, func(endpoint string) error {
+		var err error
+		receipt, err = evmGetReceipt(ctx, c.httpClient, endpoint, txHash)
+		return err
+	})
+	return receipt, err
+}
+
+// blockHead is a BlockHeadFunc
+func (c *ethRPCClient) blockHead(ctx context.Context, number *uint64) (BlockHead, error) {
+	tag := "latest"
+	if number != nil {
+		tag = "0x" + strconv.FormatUint(*number, 16)
+	}
+	var head BlockHead
+	err := c.do(ctx, func(endpoint string) error {
+		var err error
+		head, err = evmGetBlockHead(ctx, c.httpClient, endpoint, tag)
+		return err
+	})
+	return head, err
+}
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var testRPCRetry = config.RPCRetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond}
+
+// newCountingRPCServer answers every request with status and body, counting them
+func newCountingRPCServer(t *testing.T, status int, body string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+const testGasPriceResponse = `{"jsonrpc":"2.0","id":1,"result":"0x3b9aca00"}`
+
+func TestETHRPCClientFailsOverToHealthyEndpoint(t *testing.T) {
+	ctx := context.Background()
+	failing, failingHits := newCountingRPCServer(t, http.StatusInternalServerError, "")
+	healthy, healthyHits := newCountingRPCServer(t, http.StatusOK, testGasPriceResponse)
+	client := newETHRPCClient("ethereum", []string{failing.URL, healthy.URL}, testRPCRetry)
+
+	for i := 0; i < 10; i++ {
+		price, err := client.quantity(ctx, "eth_gasPrice")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1_000_000_000), price.Int64())
+	}
+	assert.Equal(t, int32(10), healthyHits.Load())
+	// Calls alternate their first endpoint until the failing one is benched
+	assert.Equal(t, int32(ethEndpointFailureThreshold), failingHits.Load())
+
+	// It is tried again once the cooldown has passed
+	client.now = func() time.Time { return time.Now().Add(ethEndpointCooldown) }
+	for i := 0; i < 2; i++ {
+		_, err := client.quantity(ctx, "eth_gasPrice")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(ethEndpointFailureThreshold+1), failingHits.Load())
+}
+
+func TestETHRPCClientRetriesTransientErrors(t *testing.T) {
+	ctx := context.Background()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) <= 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = io.WriteString(w, testGasPriceResponse)
+	}))
+	t.Cleanup(server.Close)
+
+	client := newETHRPCClient("ethereum", []string{server.URL}, testRPCRetry)
+	_, err := client.quantity(ctx, "eth_gasPrice")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), hits.Load())
+
+	hits.Store(0)
+	client = newETHRPCClient("ethereum", []string{server.URL}, config.RPCRetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond})
+	_, err = client.quantity(ctx, "eth_gasPrice")
+	assert.ErrorContains(t, err, "HTTP error: 429")
+	assert.Equal(t, int32(2), hits.Load())
+}
+
+func TestETHRPCClientDoesNotRetryPermanentErrors(t *testing.T) {
+	ctx := context.Background()
+	server, hits := newCountingRPCServer(t, http.StatusBadRequest, "")
+	client := newETHRPCClient("ethereum", []string{server.URL}, testRPCRetry)
+
+	_, err := client.quantity(ctx, "eth_gasPrice")
+	assert.ErrorContains(t, err, "HTTP error: 400")
+	assert.Equal(t, int32(1), hits.Load())
+}
+
+func TestETHRPCClientReturnsNodeErrors(t *testing.T) {
+	ctx := context.Background()
+	reverting, revertingHits := newCountingRPCServer(t, http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted"}}`)
+	healthy, healthyHits := newCountingRPCServer(t, http.StatusOK, testGasPriceResponse)
+	client := newETHRPCClient("ethereum", []string{reverting.URL, healthy.URL}, testRPCRetry)
+
+	// A revert is the node's answer, not a reason to ask another one
+	_, err := client.quantity(ctx, "eth_estimateGas", EVMCall{To: testUSDC})
+	var callErr *evmCallError
+	require.ErrorAs(t, err, &callErr)
+	assert.Equal(t, int32(1), revertingHits.Load())
+	assert.Equal(t, int32(0), healthyHits.Load())
+}
+
+func TestETHRPCClientRetryDelay(t *testing.T) {
+	client := newETHRPCClient("ethereum", nil, config.RPCRetryConfig{BaseDelay: time.Second})
+	assert.Equal(t, time.Second, client.retryDelay(0))
+	assert.Equal(t, 2*time.Second, client.retryDelay(1))
+	assert.Equal(t, 8*time.Second, client.retryDelay(3))
+	assert.Equal(t, ethMaxRetryDelay, client.retryDelay(10))
+}
+
+func TestETHChainGetBalanceFailsOver(t *testing.T) {
+	ctx := context.Background()
+	failing, failingHits := newCountingRPCServer(t, http.StatusInternalServerError, "")
+	server := newERC20TestServer(t, map[string]int{})
+	chain := NewETHChain(nil, zap.NewNop())
+	chain.SetBalanceConfig(config.BalanceConfig{Sources: config.BalanceSourcesRPCOnly, FailOnUnavailable: true})
+	chain.setRPCClient(newETHRPCClient("ethereum", []string{failing.URL, server.URL}, testRPCRetry))
+
+	for i := 0; i < 2; i++ {
+		balance, err := chain.GetBalance(ctx, testHolder, "ETH")
+		require.NoError(t, err)
+		assert.Equal(t, "1.5", balance)
+	}
+	assert.Equal(t, int32(1), failingHits.Load(), "the second call starts at the healthy endpoint")
+}
@@ -24,21 +24,13 @@ func (e *ETHChain) SignMessage(privateKeyHex, message string) (string, error) {
 		return "", fmt.Errorf("failed to parse private key: %w", err)
 	}
 
-	// Convert message to bytes if it's a hex string
-	var messageBytes []byte
-	if strings.HasPrefix(message, "0x") {
-		// If it's a hex string, decode it
-		messageBytes, err = hexutil.Decode(message)
-		if err != nil {
-			return "", fmt.Errorf("failed to decode hex message: %w", err)
-		}
-	} else {
-		// Otherwise, treat it as a regular string
-		messageBytes = []byte(message)
+	messageBytes, err := evmMessageBytes(message)
+	if err != nil {
+		return "", err
 	}
 
 	// Sign the message using Ethereum's signing standard (EIP-191)
-	signature, err := crypto.Sign(crypto.Keccak256Hash([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(messageBytes), messageBytes))).Bytes(), privateKey)
+	signature, err := crypto.Sign(eip191Hash(messageBytes), privateKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign message: %w", err)
 	}
@@ -53,4 +45,51 @@ func (e *ETHChain) SignMessage(privateKeyHex, message string) (string, error) {
 	// Convert the signature to hex format
 	signatureHex := hexutil.Encode(signature)
 	return signatureHex, nil
-}
\ No newline at end of file
+}
+
+// RecoverMessageSigner returns the checksummed address whose key produced
+// signatureHex, an EIP-191 signature over message as returned by SignMessage.
+// Both the 27/28 and 0/1 recovery id conventions are accepted.
+func RecoverMessageSigner(message, signatureHex string) (string, error) {
+	signature, err := hexutil.Decode(signatureHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode signature: %w", err)
+	}
+	if len(signature) != 65 {
+		return "", fmt.Errorf("invalid signature length: expected 65 bytes, got %d", len(signature))
+	}
+	messageBytes, err := evmMessageBytes(message)
+	if err != nil {
+		return "", err
+	}
+
+	// crypto.SigToPub wants the 0/1 recovery id
+	signature = append([]byte{}, signature...)
+	if signature[64] == 27 || signature[64] == 28 {
+		signature[64] -= 27
+	}
+	publicKey, err := crypto.SigToPub(eip191Hash(messageBytes), signature)
+	if err != nil {
+		return "", fmt.Errorf("failed to recover signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*publicKey).Hex(), nil
+}
+
+// evmMessageBytes decodes a 0x-prefixed message as hex and takes anything
+// else as text
+func evmMessageBytes(message string) ([]byte, error) {
+	if !strings.HasPrefix(message, "0x") {
+		return []byte(message), nil
+	}
+	messageBytes, err := hexutil.Decode(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode hex message: %w", err)
+	}
+	return messageBytes, nil
+}
+
+// eip191Hash is the hash personal_sign signs: the message prefixed with
+// "\x19Ethereum Signed Message:\n" and its length
+func eip191Hash(messageBytes []byte) []byte {
+	return crypto.Keccak256Hash([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(messageBytes), messageBytes))).Bytes()
+}
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrInvalidTokenContract is returned when a token address does not hold an
+// ERC-20 contract, so its balance cannot be read
+var ErrInvalidTokenContract = errors.New("not an ERC-20 token contract")
+
+// ERC-20 selectors used to read token balances
+const erc20BalanceOfSelector = "70a08231" // balanceOf(address)
+
+// evmClientPool reads an EVM chain through the endpoints of rpc. Each
+// endpoint is dialed once and its client kept.
+type evmClientPool struct {
+	rpc *ethRPCClient
+
+	mu       sync.Mutex
+	clients  map[string]*ethclient.Client
+	decimals map[common.Address]uint8
+}
+
+func newEVMClientPool(rpc *ethRPCClient) *evmClientPool {
+	return &evmClientPool{
+		rpc:      rpc,
+		clients:  make(map[string]*ethclient.Client),
+		decimals: make(map[common.Address]uint8),
+	}
+}
+
+// client returns the cached client of endpoint, dialing it the first time
+func (p *evmClientPool) client(ctx context.Context, endpoint string) (*ethclient.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if client, ok := p.clients[endpoint]; ok {
+		return client, nil
+	}
+	client, err := ethclient.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	p.clients[endpoint] = client
+	return client, nil
+}
+
+// do runs call against the endpoints until one answers
+func (p *evmClientPool) do(ctx context.Context, call func(client *ethclient.Client) error) error {
+	return p.rpc.do(ctx, func(endpoint string) error {
+		client, err := p.client(ctx, endpoint)
+		if err != nil {
+			return err
+		}
+		return call(client)
+	})
+}
+
+// nativeBalance returns the native balance of address in whole coins
+func (p *evmClientPool) nativeBalance(ctx context.Context, address string) (string, error) {
+	var wei *big.Int
+	err := p.do(ctx, func(client *ethclient.Client) error {
+		var err error
+		wei, err = client.BalanceAt(ctx, common.HexToAddress(address), nil)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return formatUnits(wei, 18), nil
+}
+
+// tokenBalance returns the balanceOf(address) of an ERC-20 token, scaled by
+// the token's own decimals
+func (p *evmClientPool) tokenBalance(ctx context.Context, address, token string) (string, error) {
+	decimals, err := p.tokenDecimals(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	data := common.FromHex(erc20BalanceOfSelector)
+	data = append(data, common.LeftPadBytes(common.HexToAddress(address).Bytes(), 32)...)
+	output, err := p.callToken(ctx, token, data, "balanceOf(address)")
+	if err != nil {
+		return "", err
+	}
+	return formatUnits(new(big.Int).SetBytes(output), int(decimals)), nil
+}
+
+// tokenDecimals reads decimals() of an ERC-20 token once and remembers it. A
+// token address without code, or whose contract does not answer decimals(),
+// fails with ErrInvalidTokenContract.
+func (p *evmClientPool) tokenDecimals(ctx context.Context, token string) (uint8, error) {
+	if !common.IsHexAddress(token) {
+		return 0, fmt.Errorf("invalid token contract address: %s", token)
+	}
+	contract := common.HexToAddress(token)
+	p.mu.Lock()
+	decimals, ok := p.decimals[contract]
+	p.mu.Unlock()
+	if ok {
+		return decimals, nil
+	}
+
+	var code []byte
+	if err := p.do(ctx, func(client *ethclient.Client) error {
+		var err error
+		code, err = client.CodeAt(ctx, contract, nil)
+		return err
+	}); err != nil {
+		return 0, fmt.Errorf("failed to read token contract: %w", err)
+	}
+	if len(code) == 0 {
+		return 0, fmt.Errorf("%w: no contract is deployed at %s", ErrInvalidTokenContract, contract.Hex())
+	}
+	output, err := p.callToken(ctx, token, common.FromHex(erc20DecimalsSelector), "decimals()")
+	if err != nil {
+		return 0, err
+	}
+	value := new(big.Int).SetBytes(output)
+	if !value.IsUint64() || value.Uint64() > 77 {
+		return 0, fmt.Errorf("%w: %s reports %s decimals", ErrInvalidTokenContract, contract.Hex(), value)
+	}
+
+	decimals = uint8(value.Uint64())
+	p.mu.Lock()
+	p.decimals[contract] = decimals
+	p.mu.Unlock()
+	return decimals, nil
+}
+
+// callToken calls method of token and returns its single word result. A
+// revert or a result that is not a word means the contract is not an ERC-20.
+func (p *evmClientPool) callToken(ctx context.Context, token string, data []byte, method string) ([]byte, error) {
+	contract := common.HexToAddress(token)
+	var output []byte
+	err := p.do(ctx, func(client *ethclient.Client) error {
+		var err error
+		output, err = client.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+		return err
+	})
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return nil, fmt.Errorf("%w: %s %s failed: %v", ErrInvalidTokenContract, contract.Hex(), method, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	if len(output) < 32 {
+		return nil, fmt.Errorf("%w: %s returned no %s result", ErrInvalidTokenContract, contract.Hex(), method)
+	}
+	return output[:32], nil
+}
+
+// formatUnits renders units of a token with decimals places as a decimal
+// string without trailing zeros
+func formatUnits(units *big.Int, decimals int) string {
+	if decimals == 0 {
+		return units.String()
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	s := new(big.Rat).SetFrac(units, scale).FloatString(decimals)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
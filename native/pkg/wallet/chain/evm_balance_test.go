@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	testHolder   = "0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b6"
+	testUSDC     = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	testNoCode   = "0x1111111111111111111111111111111111111111"
+	testNotToken = "0x2222222222222222222222222222222222222222"
+)
+
+// newERC20TestServer answers eth_getBalance, eth_getCode and eth_call with
+// canned responses: testUSDC has 6 decimals and testNotToken reverts every call
+func newERC20TestServer(t *testing.T, calls map[string]int) *httptest.Server {
+	return newRPCTestServer(t, map[string]rpcMethod{
+		"eth_getBalance": rpcResult(`"0x14d1120d7b160000"`), // 1.5 ETH
+		"eth_getCode": func(call rpcCall) (string, error) {
+			var address string
+			call.param(0, &address)
+			if strings.EqualFold(address, testNoCode) {
+				return `"0x"`, nil
+			}
+			return `"0x6080604052"`, nil
+		},
+		"eth_call": func(call rpcCall) (string, error) {
+			var tx struct {
+				To    string `json:"to"`
+				Input string `json:"input"`
+				Data  string `json:"data"`
+			}
+			call.param(0, &tx)
+			input := tx.Input + tx.Data
+			calls[input[:10]]++
+			if strings.EqualFold(tx.To, testNotToken) {
+				return "", &rpcTestError{Code: 3, Message: "execution reverted"}
+			}
+			switch input[:10] {
+			case "0x313ce567":
+				return `"0x0000000000000000000000000000000000000000000000000000000000000006"`, nil
+			case "0x70a08231":
+				assert.Contains(t, strings.ToLower(input), strings.ToLower(testHolder[2:]))
+				return `"0x00000000000000000000000000000000000000000000000000000000499602d2"`, nil
+			}
+			return `null`, nil
+		},
+	})
+}
+
+func TestETHChainGetBalanceRPC(t *testing.T) {
+	ctx := context.Background()
+	calls := map[string]int{}
+	server := newERC20TestServer(t, calls)
+	chain := NewETHChain(nil, zap.NewNop())
+	chain.SetBalanceConfig(config.BalanceConfig{Sources: config.BalanceSourcesRPCOnly, FailOnUnavailable: true})
+	// The first endpoint is down, so every call fails over to the server
+	chain.SetRPCEndpoints([]string{"http://127.0.0.1:1", server.URL})
+
+	balance, err := chain.GetBalance(ctx, testHolder, "ETH")
+	require.NoError(t, err)
+	assert.Equal(t, "1.5", balance)
+
+	balance, err = chain.GetBalance(ctx, testHolder, testUSDC)
+	require.NoError(t, err)
+	assert.Equal(t, "1234.56789", balance, "scaled by the token's 6 decimals")
+	balance, err = chain.GetBalance(ctx, testHolder, testUSDC)
+	require.NoError(t, err)
+	assert.Equal(t, "1234.56789", balance)
+	assert.Equal(t, 1, calls["0x313ce567"], "decimals are read once per token")
+	assert.Equal(t, 2, calls["0x70a08231"])
+
+	// Addresses that are not ERC-20 contracts fail instead of reading as zero,
+	// even when other sources would be tried
+	chain.SetBalanceConfig(config.BalanceConfig{Sources: config.BalanceSourcesRPCThenDEX})
+	_, err = chain.GetBalance(ctx, testHolder, testNoCode)
+	assert.ErrorIs(t, err, ErrInvalidTokenContract)
+	assert.ErrorContains(t, err, "no contract is deployed")
+	_, err = chain.GetBalance(ctx, testHolder, testNotToken)
+	assert.ErrorIs(t, err, ErrInvalidTokenContract)
+	assert.ErrorContains(t, err, "decimals()")
+}
+
+func TestETHChainGetBalanceRPCUnavailable(t *testing.T) {
+	chain := NewETHChain(nil, zap.NewNop())
+	chain.SetBalanceConfig(config.BalanceConfig{Sources: config.BalanceSourcesRPCOnly, FailOnUnavailable: true})
+	chain.SetRPCEndpoints([]string{"http://127.0.0.1:1"})
+
+	// An unreachable node says nothing about the token, so the outage is reported
+	_, err := chain.GetBalance(context.Background(), testHolder, testUSDC)
+	assert.ErrorIs(t, err, ErrBalanceUnavailable)
+	assert.NotErrorIs(t, err, ErrInvalidTokenContract)
+}
+
+func TestFormatUnits(t *testing.T) {
+	for _, tc := range []struct {
+		units    int64
+		decimals int
+		want     string
+	}{
+		{1500000000000000000, 18, "1.5"},
+		{1234567890, 6, "1234.56789"},
+		{0, 6, "0"},
+		{42, 0, "42"},
+		{1, 8, "0.00000001"},
+	} {
+		assert.Equal(t, tc.want, formatUnits(big.NewInt(tc.units), tc.decimals))
+	}
+}
@@ -0,0 +1,229 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// EVMReceipt is the part of a transaction receipt ConfirmTransaction reads
+type EVMReceipt struct {
+	BlockNumber       uint64
+	BlockHash         string
+	Status            uint64 // 1 for success, 0 for a reverted transaction
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int // wei; nil when the node does not report it
+}
+
+// EVMReceiptFunc reads the receipt of a transaction with
+// eth_getTransactionReceipt. It returns a nil receipt without error while the
+// transaction is not mined, and ErrTransactionNotFound when the node knows
+// neither a receipt nor the transaction.
+type EVMReceiptFunc func(ctx context.Context, txHash string) (*EVMReceipt, error)
+
+// NewEVMReceiptFunc returns an EVMReceiptFunc querying endpoints in order
+// until one answers. An RPC error is an answer: it is returned without trying
+// the others.
+func NewEVMReceiptFunc(chainName string, endpoints []string) EVMReceiptFunc {
+	client := httpclient.New(chainName+"-rpc", httpclient.WithTimeout(15*time.Second))
+	return func(ctx context.Context, txHash string) (*EVMReceipt, error) {
+		if len(endpoints) == 0 {
+			return nil, fmt.Errorf("no %s RPC endpoints configured", chainName)
+		}
+		var lastErr error
+		for _, endpoint := range endpoints {
+			receipt, err := evmGetReceipt(ctx, client, endpoint, txHash)
+			var callErr *evmCallError
+			if err == nil || errors.As(err, &callErr) || errors.Is(err, ErrTransactionNotFound) {
+				DefaultRPCHealth.RecordSuccess(chainName)
+				return receipt, err
+			}
+			lastErr = err
+		}
+		return nil, fmt.Errorf("all RPC endpoints failed, last error: %w", lastErr)
+	}
+}
+
+func evmGetReceipt(ctx context.Context, client *http.Client, endpoint, txHash string) (*EVMReceipt, error) {
+	body, err := json.Marshal(RPCRequest{JSONRPC: "2.0", ID: 1, Method: "eth_getTransactionReceipt", Params: []any{txHash}})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &rpcHTTPError{StatusCode: resp.StatusCode}
+	}
+
+	var response struct {
+		Result *struct {
+			BlockNumber       string `json:"blockNumber"`
+			BlockHash         string `json:"blockHash"`
+			Status            string `json:"status"`
+			GasUsed           string `json:"gasUsed"`
+			EffectiveGasPrice string `json:"effectiveGasPrice"`
+		} `json:"result"`
+		Error *struct {
+			Code    int         `json:"code"`
+			Message string      `json:"message"`
+			Data    interface{} `json:"data"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if response.Error != nil {
+		return nil, &evmCallError{code: response.Error.Code, message: response.Error.Message, data: response.Error.Data}
+	}
+	if response.Result == nil {
+		return nil, evmRequireTransaction(ctx, client, endpoint, txHash)
+	}
+
+	receipt := &EVMReceipt{BlockHash: strings.ToLower(response.Result.BlockHash)}
+	if receipt.BlockNumber, err = hexutil.DecodeUint64(response.Result.BlockNumber); err != nil {
+		return nil, fmt.Errorf("invalid receipt block number %q", response.Result.BlockNumber)
+	}
+	if receipt.Status, err = hexutil.DecodeUint64(response.Result.Status); err != nil {
+		return nil, fmt.Errorf("invalid receipt status %q", response.Result.Status)
+	}
+	if receipt.GasUsed, err = hexutil.DecodeUint64(response.Result.GasUsed); err != nil {
+		return nil, fmt.Errorf("invalid receipt gas used %q", response.Result.GasUsed)
+	}
+	if response.Result.EffectiveGasPrice != "" {
+		if receipt.EffectiveGasPrice, err = hexutil.DecodeBig(response.Result.EffectiveGasPrice); err != nil {
+			return nil, fmt.Errorf("invalid receipt gas price %q", response.Result.EffectiveGasPrice)
+		}
+	}
+	return receipt, nil
+}
+
+// evmRequireTransaction returns ErrTransactionNotFound unless the node knows
+// txHash through eth_getTransactionByHash, as a pending transaction does
+func evmRequireTransaction(ctx context.Context, client *http.Client, endpoint, txHash string) error {
+	body, err := json.Marshal(RPCRequest{JSONRPC: "2.0", ID: 1, Method: "eth_getTransactionByHash", Params: []any{txHash}})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &rpcHTTPError{StatusCode: resp.StatusCode}
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int         `json:"code"`
+			Message string      `json:"message"`
+			Data    interface{} `json:"data"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if response.Error != nil {
+		return &evmCallError{code: response.Error.Code, message: response.Error.Message, data: response.Error.Data}
+	}
+	if len(response.Result) == 0 || string(response.Result) == "null" {
+		return ErrTransactionNotFound
+	}
+	return nil
+}
+
+// evmConfirmationRPC reads what ConfirmTransaction needs from an EVM node
+type evmConfirmationRPC struct {
+	receipts EVMReceiptFunc
+	quantity EVMQuantityFunc
+	blocks   BlockHeadFunc
+}
+
+// confirm builds the confirmation of txHash from its receipt. A transaction
+// without a receipt is pending with no confirmations, or ErrTransactionNotFound
+// when the node does not know it at all; a mined one counts the block it is in
+// as its first confirmation.
+func (r *evmConfirmationRPC) confirm(ctx context.Context, chainName, txHash string, requiredConfirmations uint64) (*TransactionConfirmation, error) {
+	if r == nil {
+		return nil, fmt.Errorf("no %s RPC endpoints configured", chainName)
+	}
+	confirmation := &TransactionConfirmation{
+		Status:                "pending",
+		RequiredConfirmations: requiredConfirmations,
+		TxHash:                txHash,
+	}
+
+	receipt, err := r.receipts(ctx, txHash)
+	if errors.Is(err, ErrTransactionNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction receipt: %w", err)
+	}
+	if receipt == nil {
+		return confirmation, nil
+	}
+
+	latest, err := r.quantity(ctx, "eth_blockNumber")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block number: %w", err)
+	}
+	if !latest.IsUint64() {
+		return nil, fmt.Errorf("invalid block number %s", latest)
+	}
+	// A node behind the one that answered the receipt still has it in a block
+	confirmation.Confirmations = 1
+	if current := latest.Uint64(); current >= receipt.BlockNumber {
+		confirmation.Confirmations = current - receipt.BlockNumber + 1
+	}
+
+	head, err := r.blocks(ctx, &receipt.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", receipt.BlockNumber, err)
+	}
+
+	confirmation.BlockNumber = receipt.BlockNumber
+	confirmation.BlockHash = receipt.BlockHash
+	confirmation.Timestamp = head.Timestamp
+	confirmation.GasUsed = fmt.Sprintf("%d", receipt.GasUsed)
+	if receipt.EffectiveGasPrice != nil {
+		wei := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+		confirmation.TransactionFee = formatUnits(wei, 18)
+		if confirmation.Fee, err = NewFee(chainName, confirmation.TransactionFee); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case receipt.Status == 0:
+		confirmation.Status = "failed"
+	case confirmation.Confirmations >= requiredConfirmations:
+		confirmation.Status = "confirmed"
+	}
+	return confirmation, nil
+}
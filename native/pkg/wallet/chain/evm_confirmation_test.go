@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testMinedHash    = "0x1111111111111111111111111111111111111111111111111111111111111111"
+	testRevertedHash = "0x2222222222222222222222222222222222222222222222222222222222222222"
+	testUnminedHash  = "0x3333333333333333333333333333333333333333333333333333333333333333"
+	testBlockHash    = "0x4444444444444444444444444444444444444444444444444444444444444444"
+	testUnknownHash  = "0x5555555555555555555555555555555555555555555555555555555555555555"
+)
+
+// newConfirmationTestChain answers receipts for testMinedHash and
+// testRevertedHash, both in block 0x64 (100), with the chain head at 0x69
+// (105), and knows testUnminedHash as a pending transaction
+func newConfirmationTestChain(t *testing.T) *ETHChain {
+	t.Helper()
+	// 21000 gas at 20 gwei
+	receipt := `{"blockNumber":"0x64","blockHash":"` + testBlockHash + `","gasUsed":"0x5208","effectiveGasPrice":"0x4a817c800","status":`
+	server := newRPCTestServer(t, map[string]rpcMethod{
+		"eth_getTransactionReceipt": func(call rpcCall) (string, error) {
+			var hash string
+			call.param(0, &hash)
+			switch hash {
+			case testMinedHash:
+				return receipt + `"0x1"}`, nil
+			case testRevertedHash:
+				return receipt + `"0x0"}`, nil
+			}
+			return `null`, nil
+		},
+		"eth_getTransactionByHash": func(call rpcCall) (string, error) {
+			var hash string
+			call.param(0, &hash)
+			if hash == testUnminedHash {
+				return `{"hash":"` + testUnminedHash + `","blockNumber":null}`, nil
+			}
+			return `null`, nil
+		},
+		"eth_blockNumber": rpcResult(`"0x69"`),
+		"eth_getBlockByNumber": func(call rpcCall) (string, error) {
+			var number string
+			call.param(0, &number)
+			assert.Equal(t, "0x64", number)
+			return `{"number":"0x64","hash":"` + testBlockHash + `","parentHash":"0x00","timestamp":"0x6553f100"}`, nil
+		},
+	})
+
+	chain := NewETHChainLegacy()
+	chain.SetConfirmationRPC(NewEVMReceiptFunc("ethereum", []string{server.URL}), NewEVMQuantityFunc("ethereum", []string{server.URL}), NewEVMBlockHeadFunc("ethereum", []string{server.URL}))
+	return chain
+}
+
+func TestETHChainConfirmTransactionRPC(t *testing.T) {
+	ctx := context.Background()
+	chain := newConfirmationTestChain(t)
+
+	t.Run("confirmed", func(t *testing.T) {
+		confirmation, err := chain.ConfirmTransaction(ctx, testMinedHash, 6)
+		require.NoError(t, err)
+		assert.Equal(t, "confirmed", confirmation.Status)
+		assert.Equal(t, uint64(6), confirmation.Confirmations)
+		assert.Equal(t, uint64(100), confirmation.BlockNumber)
+		assert.Equal(t, testBlockHash, confirmation.BlockHash)
+		assert.Equal(t, "21000", confirmation.GasUsed)
+		assert.Equal(t, "0.00042", confirmation.TransactionFee)
+		require.NotNil(t, confirmation.Fee)
+		assert.Equal(t, "420000000000000", confirmation.Fee.SmallestUnit)
+		assert.Equal(t, time.Unix(0x6553f100, 0).UTC(), confirmation.Timestamp)
+	})
+
+	t.Run("awaiting confirmations", func(t *testing.T) {
+		confirmation, err := chain.ConfirmTransaction(ctx, testMinedHash, 12)
+		require.NoError(t, err)
+		assert.Equal(t, "pending", confirmation.Status)
+		assert.Equal(t, uint64(6), confirmation.Confirmations)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		confirmation, err := chain.ConfirmTransaction(ctx, testRevertedHash, 6)
+		require.NoError(t, err)
+		assert.Equal(t, "failed", confirmation.Status)
+		assert.Equal(t, uint64(100), confirmation.BlockNumber)
+		assert.Equal(t, "0.00042", confirmation.TransactionFee)
+	})
+
+	t.Run("not mined", func(t *testing.T) {
+		confirmation, err := chain.ConfirmTransaction(ctx, testUnminedHash, 6)
+		require.NoError(t, err)
+		assert.Equal(t, "pending", confirmation.Status)
+		assert.Zero(t, confirmation.Confirmations)
+		assert.Zero(t, confirmation.BlockNumber)
+		assert.Nil(t, confirmation.Fee)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := chain.ConfirmTransaction(ctx, testUnknownHash, 6)
+		assert.ErrorIs(t, err, ErrTransactionNotFound)
+	})
+}
+
+func TestETHChainConfirmTransactionWithoutRPC(t *testing.T) {
+	_, err := NewETHChainLegacy().ConfirmTransaction(context.Background(), testMinedHash, 6)
+	assert.ErrorContains(t, err, "no ethereum RPC endpoints configured")
+}
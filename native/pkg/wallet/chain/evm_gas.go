@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Gas limits used when the node cannot estimate a send
+const (
+	defaultNativeTransferGas = 21000
+	defaultTokenTransferGas  = 65000
+)
+
+// GasEstimate is the gas limit and price of an EVM send. GasPriceGwei is the
+// legacy gas price; the EIP-1559 fields are zero when the fee market could not
+// be sampled.
+type GasEstimate struct {
+	GasLimit        uint64  `json:"gas_limit"`
+	GasPriceGwei    float64 `json:"gas_price_gwei"`
+	Strategy        string  `json:"strategy,omitempty"`
+	BaseFeeGwei     float64 `json:"base_fee_gwei,omitempty"`
+	PriorityFeeGwei float64 `json:"priority_fee_gwei,omitempty"`
+	MaxFeeGwei      float64 `json:"max_fee_gwei,omitempty"`
+}
+
+// GasPrice returns the legacy gas price in gwei the way EstimateGas reports it
+func (g *GasEstimate) GasPrice() string {
+	return strconv.FormatFloat(roundGwei(g.GasPriceGwei), 'f', -1, 64)
+}
+
+// GasFeeEstimator is implemented by chains that price a send from the
+// network with both legacy and EIP-1559 fees
+type GasFeeEstimator interface {
+	EstimateGasFees(ctx context.Context, from, to, amount, token string) (*GasEstimate, error)
+}
+
+// ErrCallGasUnavailable is returned by EstimateCallGas when no RPC endpoint is
+// configured to estimate the call with
+var ErrCallGasUnavailable = errors.New("contract call gas estimate unavailable")
+
+// CallGasEstimator is implemented by chains that estimate the gas of an
+// arbitrary contract call, such as an NFT safeTransferFrom
+type CallGasEstimator interface {
+	EstimateCallGas(ctx context.Context, call EVMCall) (uint64, error)
+}
+
+// EVMQuantityFunc calls a JSON-RPC method answering a hex quantity, such as
+// eth_gasPrice, eth_maxPriorityFeePerGas or eth_estimateGas
+type EVMQuantityFunc func(ctx context.Context, method string, params ...any) (*big.Int, error)
+
+// NewEVMQuantityFunc returns an EVMQuantityFunc querying endpoints in order
+// until one answers. An RPC error, such as a send eth_estimateGas expects to
+// revert, is an answer: it is returned without trying the others.
+func NewEVMQuantityFunc(chainName string, endpoints []string) EVMQuantityFunc {
+	client := httpclient.New(chainName+"-rpc", httpclient.WithTimeout(15*time.Second))
+	return func(ctx context.Context, method string, params ...any) (*big.Int, error) {
+		if len(endpoints) == 0 {
+			return nil, fmt.Errorf("no %s RPC endpoints configured", chainName)
+		}
+		if params == nil {
+			params = []any{}
+		}
+		var lastErr error
+		for _, endpoint := range endpoints {
+			quantity, err := evmQuantity(ctx, client, endpoint, method, params)
+			var callErr *evmCallError
+			if err == nil || errors.As(err, &callErr) {
+				DefaultRPCHealth.RecordSuccess(chainName)
+				return quantity, err
+			}
+			lastErr = err
+		}
+		return nil, fmt.Errorf("all RPC endpoints failed, last error: %w", lastErr)
+	}
+}
+
+func evmQuantity(ctx context.Context, client *http.Client, endpoint, method string, params []any) (*big.Int, error) {
+	body, err := json.Marshal(RPCRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &rpcHTTPError{StatusCode: resp.StatusCode}
+	}
+
+	var response struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Code    int         `json:"code"`
+			Message string      `json:"message"`
+			Data    interface{} `json:"data"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if response.Error != nil {
+		return nil, &evmCallError{code: response.Error.Code, message: response.Error.Message, data: response.Error.Data}
+	}
+	quantity, err := hexutil.DecodeBig(response.Result)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s result %q: %w", method, response.Result, err)
+	}
+	return quantity, nil
+}
+
+// sampleEVMFeeMarket reads the recent base fees and the median priority fee
+// through feeHistory. eth_maxPriorityFeePerGas is asked for the priority fee
+// when the history carries no rewards.
+func sampleEVMFeeMarket(ctx context.Context, feeHistory EVMFeeHistoryFunc, quantity EVMQuantityFunc) (MempoolSample, error) {
+	history, err := feeHistory(ctx, feeHistoryBlocks, []float64{50})
+	if err != nil {
+		return MempoolSample{}, fmt.Errorf("failed to get fee history: %w", err)
+	}
+	if len(history.BaseFees) == 0 {
+		return MempoolSample{}, errors.New("fee history has no base fees")
+	}
+
+	sample := MempoolSample{BaseFeesGwei: history.BaseFees}
+	var rewards []float64
+	for _, blockRewards := range history.Rewards {
+		if len(blockRewards) > 0 {
+			rewards = append(rewards, blockRewards[0])
+		}
+	}
+	if len(rewards) > 0 {
+		sort.Float64s(rewards)
+		sample.PriorityFeeGwei = rewards[len(rewards)/2]
+	} else if quantity != nil {
+		tip, err := quantity(ctx, "eth_maxPriorityFeePerGas")
+		if err != nil {
+			return MempoolSample{}, fmt.Errorf("failed to get priority fee: %w", err)
+		}
+		sample.PriorityFeeGwei = weiToGwei(tip)
+	}
+	return sample, nil
+}
+
+// estimateEVMGasLimit asks the node for the gas a native or ERC-20 send uses.
+// call reads the token's decimals to encode a token transfer.
+func estimateEVMGasLimit(ctx context.Context, quantity EVMQuantityFunc, call EVMCallFunc, from, to, amount, token, nativeSymbol string) (uint64, error) {
+	message := EVMCall{From: from, To: to}
+	if token == "" || strings.EqualFold(token, nativeSymbol) {
+		wei, err := scaleAmount(amount, 18)
+		if err != nil {
+			return 0, err
+		}
+		message.Value = hexutil.EncodeBig(wei)
+	} else {
+		if call == nil {
+			return 0, errors.New("token decimals cannot be read")
+		}
+		transfer, _, err := erc20TransferMessage(ctx, call, from, to, amount, token)
+		if err != nil {
+			return 0, err
+		}
+		message = transfer
+	}
+	return evmCallGas(ctx, quantity, message)
+}
+
+// evmCallGas runs eth_estimateGas against message
+func evmCallGas(ctx context.Context, quantity EVMQuantityFunc, message EVMCall) (uint64, error) {
+	gas, err := quantity(ctx, "eth_estimateGas", message)
+	if err != nil {
+		return 0, err
+	}
+	if !gas.IsUint64() {
+		return 0, fmt.Errorf("invalid gas estimate %s", gas)
+	}
+	return gas.Uint64(), nil
+}
+
+// defaultEVMGasLimit returns the typical gas of a native or token send
+func defaultEVMGasLimit(token, nativeSymbol string) (uint64, error) {
+	if token == "" || strings.EqualFold(token, nativeSymbol) {
+		return defaultNativeTransferGas, nil
+	}
+	if !common.IsHexAddress(token) {
+		return 0, fmt.Errorf("invalid token contract address: %s", token)
+	}
+	return defaultTokenTransferGas, nil
+}
+
+// weiToGwei converts an amount of wei to gwei
+func weiToGwei(wei *big.Int) float64 {
+	gwei, _ := new(big.Rat).SetFrac(wei, big.NewInt(1e9)).Float64()
+	return gwei
+}
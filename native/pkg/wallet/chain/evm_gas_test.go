@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gweiHex encodes gwei as a hex quantity of wei
+func gweiHex(gwei float64) string {
+	wei, _ := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(1e9)).Int(nil)
+	return hexutil.EncodeBig(wei)
+}
+
+// gasTestNode answers the fee market and gas estimate calls of an Ethereum node
+type gasTestNode struct {
+	rewards      bool   // whether eth_feeHistory reports rewards
+	estimateErr  string // eth_estimateGas error message, when set
+	gasPriceGwei float64
+}
+
+func newGasTestChain(t *testing.T, node gasTestNode, strategy string) *ETHChain {
+	t.Helper()
+	reward := `[]`
+	if node.rewards {
+		reward = `[["` + gweiHex(1) + `"],["` + gweiHex(3) + `"],["` + gweiHex(2) + `"]]`
+	}
+	server := newRPCTestServer(t, map[string]rpcMethod{
+		"eth_feeHistory":           rpcResult(`{"oldestBlock":"0x64","baseFeePerGas":["` + gweiHex(10) + `","` + gweiHex(11) + `","` + gweiHex(12) + `","` + gweiHex(12.5) + `"],"gasUsedRatio":[0.5,0.6,0.7],"reward":` + reward + `}`),
+		"eth_maxPriorityFeePerGas": rpcResult(`"` + gweiHex(4) + `"`),
+		"eth_gasPrice":             rpcResult(`"` + gweiHex(node.gasPriceGwei) + `"`),
+		// decimals() of the token
+		"eth_call": rpcResult(`"0x0000000000000000000000000000000000000000000000000000000000000006"`),
+		"eth_estimateGas": func(call rpcCall) (string, error) {
+			if node.estimateErr != "" {
+				return "", errors.New(node.estimateErr)
+			}
+			var tx map[string]any
+			call.param(0, &tx)
+			if data, _ := tx["data"].(string); strings.HasPrefix(data, "0x"+erc20TransferSelector) {
+				return `"0xcb20"`, nil // 52000
+			}
+			assert.Equal(t, "0xde0b6b3a7640000", tx["value"])
+			return `"0x5208"`, nil
+		},
+	})
+
+	chain := NewETHChainLegacy()
+	chain.SetGasConfig(strategy, 0)
+	chain.SetFeeHistory(NewEVMFeeHistoryFunc("ethereum", []string{server.URL}))
+	chain.SetGasRPC(NewEVMQuantityFunc("ethereum", []string{server.URL}))
+	chain.SetPreflight(NewEVMCallFunc("ethereum", []string{server.URL}))
+	return chain
+}
+
+const (
+	testGasFrom  = "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+	testGasTo    = "0x2F62f2B4c5fcd7570a709DeC05D68EA19c82A9ec"
+	testGasToken = "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+)
+
+func TestETHChainEstimateGasFees(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("strategies scale the median priority fee", func(t *testing.T) {
+		expected := map[string]GasEstimate{
+			// next base fee 12.5 gwei, median tip 2 gwei
+			GasStrategySlow:     {GasLimit: 21000, GasPriceGwei: 14.5, Strategy: GasStrategySlow, BaseFeeGwei: 12.5, PriorityFeeGwei: 2, MaxFeeGwei: 14.5},
+			GasStrategyStandard: {GasLimit: 21000, GasPriceGwei: 15, Strategy: GasStrategyStandard, BaseFeeGwei: 12.5, PriorityFeeGwei: 2.5, MaxFeeGwei: 18.125},
+			GasStrategyFast:     {GasLimit: 21000, GasPriceGwei: 15.5, Strategy: GasStrategyFast, BaseFeeGwei: 12.5, PriorityFeeGwei: 3, MaxFeeGwei: 21.75},
+		}
+		for strategy, want := range expected {
+			chain := newGasTestChain(t, gasTestNode{rewards: true, gasPriceGwei: 13}, strategy)
+			estimate, err := chain.EstimateGasFees(ctx, testGasFrom, testGasTo, "1", "")
+			require.NoError(t, err, strategy)
+			assert.Equal(t, want, *estimate, strategy)
+		}
+	})
+
+	t.Run("legacy gas price from eth_gasPrice", func(t *testing.T) {
+		chain := newGasTestChain(t, gasTestNode{rewards: true, gasPriceGwei: 30}, GasStrategyStandard)
+		gasLimit, gasPrice, err := chain.EstimateGas(ctx, testGasFrom, testGasTo, "1", "ETH")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(21000), gasLimit)
+		assert.Equal(t, "30", gasPrice)
+	})
+
+	t.Run("token transfer call data", func(t *testing.T) {
+		chain := newGasTestChain(t, gasTestNode{rewards: true, gasPriceGwei: 13}, GasStrategyStandard)
+		gasLimit, _, err := chain.EstimateGas(ctx, testGasFrom, testGasTo, "25", testGasToken)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(52000), gasLimit)
+	})
+
+	t.Run("typical gas when the node cannot estimate", func(t *testing.T) {
+		chain := newGasTestChain(t, gasTestNode{rewards: true, estimateErr: "insufficient funds for transfer", gasPriceGwei: 13}, GasStrategyStandard)
+		estimate, err := chain.EstimateGasFees(ctx, testGasFrom, testGasTo, "25", testGasToken)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(defaultTokenTransferGas), estimate.GasLimit)
+	})
+
+	t.Run("priority fee from the node without rewards", func(t *testing.T) {
+		chain := newGasTestChain(t, gasTestNode{gasPriceGwei: 13}, GasStrategySlow)
+		params, err := chain.SuggestGasParams(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, 4.0, params.PriorityFeeGwei)
+		assert.Equal(t, 12.5, params.BaseFeeGwei)
+	})
+
+	t.Run("no RPC configured", func(t *testing.T) {
+		gasLimit, gasPrice, err := NewETHChainLegacy().EstimateGas(ctx, testGasFrom, testGasTo, "1", "")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(defaultNativeTransferGas), gasLimit)
+		assert.Equal(t, "20", gasPrice)
+	})
+}
+
+func TestETHChainEstimateCallGas(t *testing.T) {
+	ctx := context.Background()
+	call := EVMCall{From: testGasFrom, To: testGasToken, Data: "0x42842e0e"}
+
+	_, err := NewETHChainLegacy().EstimateCallGas(ctx, call)
+	require.ErrorIs(t, err, ErrCallGasUnavailable)
+
+	chain := newGasTestChain(t, gasTestNode{estimateErr: "execution reverted", gasPriceGwei: 20}, "")
+	_, err = chain.EstimateCallGas(ctx, call)
+	require.ErrorContains(t, err, "execution reverted")
+}
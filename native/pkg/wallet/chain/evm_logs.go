@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// Defaults for ChunkedLogFetcher. Public RPC providers commonly cap
+// eth_getLogs at a few thousand blocks per request.
+const (
+	DefaultLogChunkSize   uint64  = 2000
+	DefaultLogConcurrency         = 4
+	DefaultLogRequestRate float64 = 10 // requests per second
+)
+
+// LogQuery is an inclusive block range filter for eth_getLogs
+type LogQuery struct {
+	FromBlock uint64
+	ToBlock   uint64
+	Addresses []string
+	Topics    [][]string // per position alternatives; an empty position matches anything
+}
+
+// EVMLog is a log entry returned by eth_getLogs
+type EVMLog struct {
+	Address     string   `json:"address"`
+	Topics      []string `json:"topics"`
+	Data        string   `json:"data"`
+	BlockNumber uint64   `json:"blockNumber"`
+	BlockHash   string   `json:"blockHash"`
+	TxHash      string   `json:"transactionHash"`
+	TxIndex     uint     `json:"transactionIndex"`
+	LogIndex    uint     `json:"logIndex"`
+	Removed     bool     `json:"removed"`
+}
+
+// LogQueryFunc performs a single eth_getLogs request
+type LogQueryFunc func(ctx context.Context, query LogQuery) ([]EVMLog, error)
+
+// logRangeErrors are fragments of the errors providers return for block spans
+// or result sets above their limits
+var logRangeErrors = []string{
+	"query returned more than",
+	"block range",
+	"range too large",
+	"range is too large",
+	"too many blocks",
+	"exceed maximum block range",
+	"log response size exceeded",
+	"response size exceeded",
+}
+
+// IsLogRangeError reports whether err is a provider rejecting an eth_getLogs
+// request for spanning too many blocks or matching too many logs, which a
+// narrower range can avoid
+func IsLogRangeError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	for _, fragment := range logRangeErrors {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChunkedLogFetcher splits wide eth_getLogs queries into windows providers
+// accept, fetches them concurrently under a shared rate limit and merges the
+// results. A window rejected for its size is halved and retried.
+type ChunkedLogFetcher struct {
+	query       LogQueryFunc
+	chunkSize   uint64
+	concurrency int
+	limiter     *rate.Limiter
+}
+
+// NewChunkedLogFetcher creates a fetcher issuing requests through query. Zero
+// or negative settings use DefaultLogChunkSize, DefaultLogConcurrency and
+// DefaultLogRequestRate.
+func NewChunkedLogFetcher(query LogQueryFunc, chunkSize uint64, concurrency int, requestsPerSecond float64) *ChunkedLogFetcher {
+	if chunkSize == 0 {
+		chunkSize = DefaultLogChunkSize
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultLogConcurrency
+	}
+	if requestsPerSecond <= 0 {
+		requestsPerSecond = DefaultLogRequestRate
+	}
+	return &ChunkedLogFetcher{
+		query:       query,
+		chunkSize:   chunkSize,
+		concurrency: concurrency,
+		limiter:     rate.NewLimiter(rate.Limit(requestsPerSecond), concurrency),
+	}
+}
+
+// FetchLogs returns the logs matching query across its whole block range,
+// ordered by block and log index
+func (f *ChunkedLogFetcher) FetchLogs(ctx context.Context, query LogQuery) ([]EVMLog, error) {
+	if query.FromBlock > query.ToBlock {
+		return nil, fmt.Errorf("invalid block range: from %d is after to %d", query.FromBlock, query.ToBlock)
+	}
+
+	var windows [][2]uint64
+	for from := query.FromBlock; ; from += f.chunkSize {
+		to := query.ToBlock
+		if query.ToBlock-from >= f.chunkSize {
+			to = from + f.chunkSize - 1
+		}
+		windows = append(windows, [2]uint64{from, to})
+		if to == query.ToBlock {
+			break
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]EVMLog, len(windows))
+	var (
+		wg       sync.WaitGroup
+		errMu    sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, f.concurrency)
+	for i, window := range windows {
+		wg.Add(1)
+		go func(i int, from, to uint64) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			logs, err := f.fetchWindow(ctx, query, from, to)
+			if err != nil {
+				errMu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				errMu.Unlock()
+				return
+			}
+			results[i] = logs
+		}(i, window[0], window[1])
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	var merged []EVMLog
+	for _, logs := range results {
+		merged = append(merged, logs...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].BlockNumber != merged[j].BlockNumber {
+			return merged[i].BlockNumber < merged[j].BlockNumber
+		}
+		return merged[i].LogIndex < merged[j].LogIndex
+	})
+	return merged, nil
+}
+
+// fetchWindow queries one window, halving it for as long as the provider
+// rejects it for its size
+func (f *ChunkedLogFetcher) fetchWindow(ctx context.Context, query LogQuery, from, to uint64) ([]EVMLog, error) {
+	if err := f.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	window := query
+	window.FromBlock, window.ToBlock = from, to
+	logs, err := f.query(ctx, window)
+	if err == nil {
+		return logs, nil
+	}
+	if !IsLogRangeError(err) || from == to || errors.Is(err, context.Canceled) {
+		return nil, fmt.Errorf("eth_getLogs for blocks %d-%d failed: %w", from, to, err)
+	}
+
+	mid := from + (to-from)/2
+	left, err := f.fetchWindow(ctx, query, from, mid)
+	if err != nil {
+		return nil, err
+	}
+	right, err := f.fetchWindow(ctx, query, mid+1, to)
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
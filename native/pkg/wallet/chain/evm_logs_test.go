@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// limitedLogProvider serves one log per block and, like public RPCs, rejects
+// requests spanning more than maxSpan blocks or matching more than maxResults logs
+type limitedLogProvider struct {
+	maxSpan    uint64
+	maxResults int
+
+	mu       sync.Mutex
+	requests []LogQuery
+	rejected int
+}
+
+func (p *limitedLogProvider) query(_ context.Context, query LogQuery) ([]EVMLog, error) {
+	p.mu.Lock()
+	p.requests = append(p.requests, query)
+	p.mu.Unlock()
+
+	if span := query.ToBlock - query.FromBlock + 1; span > p.maxSpan {
+		p.mu.Lock()
+		p.rejected++
+		p.mu.Unlock()
+		return nil, fmt.Errorf("exceed maximum block range: %d", p.maxSpan)
+	}
+	if count := int(query.ToBlock - query.FromBlock + 1); p.maxResults > 0 && count > p.maxResults {
+		p.mu.Lock()
+		p.rejected++
+		p.mu.Unlock()
+		return nil, fmt.Errorf("query returned more than %d results", p.maxResults)
+	}
+
+	logs := make([]EVMLog, 0, query.ToBlock-query.FromBlock+1)
+	for block := query.FromBlock; block <= query.ToBlock; block++ {
+		logs = append(logs, EVMLog{BlockNumber: block, TxHash: fmt.Sprintf("0x%x", block)})
+	}
+	return logs, nil
+}
+
+func TestChunkedLogFetcherSplitsWideRanges(t *testing.T) {
+	provider := &limitedLogProvider{maxSpan: 100}
+	fetcher := NewChunkedLogFetcher(provider.query, 100, 3, 1000)
+
+	logs, err := fetcher.FetchLogs(context.Background(), LogQuery{FromBlock: 1000, ToBlock: 1549, Addresses: []string{"0xtoken"}})
+	require.NoError(t, err)
+
+	require.Len(t, logs, 550)
+	for i, log := range logs {
+		assert.Equal(t, uint64(1000+i), log.BlockNumber, "logs must be merged in block order")
+	}
+	assert.Len(t, provider.requests, 6)
+	assert.Zero(t, provider.rejected)
+	for _, request := range provider.requests {
+		assert.Equal(t, []string{"0xtoken"}, request.Addresses, "filters are kept on every window")
+	}
+}
+
+func TestChunkedLogFetcherHalvesRejectedWindows(t *testing.T) {
+	// The configured chunk is wider than the provider allows and dense windows
+	// hit the result cap, so windows have to be halved until they fit
+	provider := &limitedLogProvider{maxSpan: 300, maxResults: 120}
+	fetcher := NewChunkedLogFetcher(provider.query, 1000, 2, 1000)
+
+	logs, err := fetcher.FetchLogs(context.Background(), LogQuery{FromBlock: 0, ToBlock: 1999})
+	require.NoError(t, err)
+
+	require.Len(t, logs, 2000)
+	for i, log := range logs {
+		require.Equal(t, uint64(i), log.BlockNumber)
+	}
+	assert.NotZero(t, provider.rejected)
+}
+
+func TestChunkedLogFetcherStopsOnOtherErrors(t *testing.T) {
+	calls := 0
+	var mu sync.Mutex
+	fetcher := NewChunkedLogFetcher(func(ctx context.Context, query LogQuery) ([]EVMLog, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return nil, errors.New("connection refused")
+	}, 10, 1, 1000)
+
+	_, err := fetcher.FetchLogs(context.Background(), LogQuery{FromBlock: 0, ToBlock: 99})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection refused")
+	assert.Equal(t, 1, calls, "a non-range error must not be retried or split")
+
+	_, err = fetcher.FetchLogs(context.Background(), LogQuery{FromBlock: 10, ToBlock: 5})
+	assert.Error(t, err)
+}
+
+func TestIsLogRangeError(t *testing.T) {
+	assert.True(t, IsLogRangeError(errors.New("Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range")))
+	assert.True(t, IsLogRangeError(errors.New("query returned more than 10000 results")))
+	assert.False(t, IsLogRangeError(errors.New("execution reverted")))
+	assert.False(t, IsLogRangeError(nil))
+}
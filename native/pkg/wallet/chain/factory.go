@@ -3,6 +3,7 @@ package chain
 
 import (
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 
@@ -26,12 +27,9 @@ func NewChainFactory() *ChainFactory {
 	}
 
 	// Register available chains (legacy mode without DEX aggregator)
-	factory.RegisterChain("ETH", NewETHChainLegacy())
-	factory.RegisterChain("ETHEREUM", NewETHChainLegacy())
-	factory.RegisterChain("BSC", NewBSCChainLegacy())
-	factory.RegisterChain("BINANCE", NewBSCChainLegacy())
-	factory.RegisterChain("SOL", NewSolanaChainLegacy())
-	factory.RegisterChain("SOLANA", NewSolanaChainLegacy())
+	factory.RegisterChain("ethereum", NewETHChainLegacy())
+	factory.RegisterChain("bsc", NewBSCChainLegacy())
+	factory.RegisterChain("solana", NewSolanaChainLegacy())
 
 	return factory
 }
@@ -45,39 +43,97 @@ func NewChainFactoryWithDEX(dexAggregator dex.IDEXAggregator, logger *zap.Logger
 	}
 
 	// Register chains with DEX aggregator support
-	factory.RegisterChain("ETH", NewETHChain(dexAggregator, logger))
-	factory.RegisterChain("ETHEREUM", NewETHChain(dexAggregator, logger))
-	factory.RegisterChain("BSC", NewBSCChain(dexAggregator, logger))
-	factory.RegisterChain("BINANCE", NewBSCChain(dexAggregator, logger))
+	ethChain := NewETHChain(dexAggregator, logger)
+	if config != nil {
+		ethChain.SetGasConfig(config.Chains.Ethereum.GasStrategy, config.Chains.Ethereum.MaxFee)
+		ethChain.SetNativeReserve(config.Chains.Ethereum.ReserveNative)
+		// Every Ethereum call shares one client, so endpoint health is tracked across them
+		ethRPC := newETHRPCClient("ethereum", config.Chains.Ethereum.RPCEndpoints, config.Chains.Ethereum.RPCRetry)
+		ethChain.setRPCClient(ethRPC)
+		ethChain.SetRecipientCheck(ethRPC.code, config.Security.SafeContractRecipients)
+		ethChain.SetPreflight(ethRPC.call)
+		ethChain.SetFeeHistory(ethRPC.feeHistory)
+		ethChain.SetGasRPC(ethRPC.quantity)
+		ethChain.SetConfirmationRPC(ethRPC.receipt, ethRPC.quantity, ethRPC.blockHead)
+		ethChain.SetTransferFeeCheck(ethRPC.call, ethRPC.simulate, config.Chains.Ethereum.FeeOnTransferTokens)
+	}
+	factory.RegisterChain("ethereum", ethChain)
+	bscChain := NewBSCChain(dexAggregator, logger)
+	if config != nil {
+		bscChain.SetGasConfig(config.Chains.BSC.GasStrategy, config.Chains.BSC.MaxFee)
+		bscChain.SetNativeReserve(config.Chains.BSC.ReserveNative)
+		bscChain.SetRecipientCheck(NewEVMCodeFunc("bsc", config.Chains.BSC.RPCEndpoints), config.Security.SafeContractRecipients)
+		preflight := NewEVMCallFunc("bsc", config.Chains.BSC.RPCEndpoints)
+		bscChain.SetPreflight(preflight)
+		bscChain.SetFeeHistory(NewEVMFeeHistoryFunc("bsc", config.Chains.BSC.RPCEndpoints))
+		bscChain.SetTransferFeeCheck(preflight, NewEVMSimulateFunc("bsc", config.Chains.BSC.RPCEndpoints), config.Chains.BSC.FeeOnTransferTokens)
+	}
+	factory.RegisterChain("bsc", bscChain)
 	
 	// Handle potential error from NewSolanaChain with injected configuration
 	if config != nil {
 		if solanaChain, err := NewSolanaChain(dexAggregator, logger, &config.Chains.Solana, &config.DEX); err == nil {
-			factory.RegisterChain("SOL", solanaChain)
-			factory.RegisterChain("SOLANA", solanaChain)
+			factory.RegisterChain("solana", solanaChain)
 		} else {
 			// Fallback to legacy Solana chain if enhanced version fails
 			logger.Warn("Failed to create enhanced Solana chain, using legacy version", zap.Error(err))
 			legacyChain := NewSolanaChainLegacy()
-			factory.RegisterChain("SOL", legacyChain)
-			factory.RegisterChain("SOLANA", legacyChain)
+			factory.RegisterChain("solana", legacyChain)
 		}
 	} else {
 		// No config provided, use legacy chain
 		logger.Warn("No configuration provided, using legacy Solana chain")
 		legacyChain := NewSolanaChainLegacy()
-		factory.RegisterChain("SOL", legacyChain)
-		factory.RegisterChain("SOLANA", legacyChain)
+		factory.RegisterChain("solana", legacyChain)
+	}
+
+	if config != nil {
+		factory.applyBalanceConfig(config.Chains.Balance)
 	}
 
 	return factory
 }
 
-// RegisterChain registers a new chain implementation
+// balanceConfigurable is implemented by chains whose balance source ordering can be configured
+type balanceConfigurable interface {
+	SetBalanceConfig(cfg config.BalanceConfig)
+}
+
+// applyBalanceConfig propagates the balance source ordering to every registered chain
+func (cf *ChainFactory) applyBalanceConfig(cfg config.BalanceConfig) {
+	cf.mu.RLock()
+	defer cf.mu.RUnlock()
+	for _, chain := range cf.chains {
+		if configurable, ok := chain.(balanceConfigurable); ok {
+			configurable.SetBalanceConfig(cfg)
+		}
+	}
+}
+
+// SetEntropySource makes every registered chain draw new wallet mnemonics
+// from source. Production never calls it, leaving crypto/rand in place;
+// tests pass NewSeededEntropy to get reproducible wallets.
+func (cf *ChainFactory) SetEntropySource(source io.Reader) {
+	cf.mu.RLock()
+	defer cf.mu.RUnlock()
+	for _, chain := range cf.chains {
+		if configurable, ok := chain.(entropyConfigurable); ok {
+			configurable.SetEntropySource(source)
+		}
+	}
+}
+
+// RegisterChain registers a new chain implementation under name and every
+// alias of it
 func (cf *ChainFactory) RegisterChain(name string, chain IChain) {
 	cf.mu.Lock()
 	defer cf.mu.Unlock()
-	cf.chains[strings.ToUpper(name)] = chain
+	cf.chains[chainKey(name)] = chain
+}
+
+// chainKey is the registry key of a chain name or alias
+func chainKey(name string) string {
+	return strings.ToUpper(config.NormalizeChain(name))
 }
 
 // GetChain returns a chain implementation by name
@@ -85,7 +141,7 @@ func (cf *ChainFactory) GetChain(name string) (IChain, error) {
 	cf.mu.RLock()
 	defer cf.mu.RUnlock()
 	
-	chain, exists := cf.chains[strings.ToUpper(name)]
+	chain, exists := cf.chains[chainKey(name)]
 	if !exists {
 		return nil, fmt.Errorf("unsupported chain: %s", name)
 	}
@@ -120,17 +176,14 @@ func (cf *ChainFactory) SetDEXAggregator(dexAggregator dex.IDEXAggregator, logge
 	cf.logger = logger
 
 	// Re-register chains with DEX support
-	cf.chains["ETH"] = NewETHChain(dexAggregator, logger)
-	cf.chains["ETHEREUM"] = NewETHChain(dexAggregator, logger)
-	cf.chains["BSC"] = NewBSCChain(dexAggregator, logger)
-	cf.chains["BINANCE"] = NewBSCChain(dexAggregator, logger)
+	cf.chains[chainKey("ethereum")] = NewETHChain(dexAggregator, logger)
+	cf.chains[chainKey("bsc")] = NewBSCChain(dexAggregator, logger)
 	// Handle potential error from NewSolanaChain - use legacy since no config available
 	if logger != nil {
 		logger.Warn("No configuration provided for Solana chain, using legacy version")
 	}
 	legacyChain := NewSolanaChainLegacy()
-	cf.chains["SOL"] = legacyChain
-	cf.chains["SOLANA"] = legacyChain
+	cf.chains[chainKey("solana")] = legacyChain
 
 	if logger != nil {
 		logger.Info("Chain factory updated with DEX aggregator support")
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+)
+
+// Fee is a transaction fee in the same shape on every chain, so callers can
+// compare fees without knowing whether a chain prices gas in gwei or compute
+// units in microlamports
+type Fee struct {
+	Amount       string `json:"amount"`              // in the native token, e.g. "0.00042"
+	Symbol       string `json:"symbol"`              // ETH, BNB or SOL
+	SmallestUnit string `json:"smallest_unit"`       // Amount in Unit, as an integer
+	Unit         string `json:"unit"`                // wei or lamports
+	USDValue     string `json:"usd_value,omitempty"` // Amount in USD with two decimals, when a price is known
+}
+
+// feeDenomination is how a chain's native token is divided
+type feeDenomination struct {
+	symbol   string
+	unit     string
+	decimals int64
+}
+
+var feeDenominations = map[string]feeDenomination{
+	"ethereum": {symbol: "ETH", unit: "wei", decimals: 18},
+	"bsc":      {symbol: "BNB", unit: "wei", decimals: 18},
+	"solana":   {symbol: "SOL", unit: "lamports", decimals: 9},
+}
+
+// NewFee describes amount, a fee in the native token of chainName. Precision
+// below the smallest unit is rounded down.
+func NewFee(chainName, amount string) (*Fee, error) {
+	denomination, ok := feeDenominations[config.NormalizeChain(chainName)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported chain: %s", chainName)
+	}
+	native, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok || native.Sign() < 0 {
+		return nil, fmt.Errorf("invalid fee: %s", amount)
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(denomination.decimals), nil)
+	smallest := new(big.Rat).Mul(native, new(big.Rat).SetInt(scale))
+	units := new(big.Int).Quo(smallest.Num(), smallest.Denom())
+	return &Fee{
+		Amount:       formatDecimal(native),
+		Symbol:       denomination.symbol,
+		SmallestUnit: units.String(),
+		Unit:         denomination.unit,
+	}, nil
+}
+
+// EstimateFee describes the fee of a gas estimate as returned by
+// IChain.EstimateGas, see TransferFee
+func EstimateFee(chainName string, gasLimit uint64, gasPrice string) (*Fee, error) {
+	amount, err := TransferFee(chainName, gasLimit, gasPrice)
+	if err != nil {
+		return nil, err
+	}
+	return NewFee(chainName, amount)
+}
+
+// String renders the fee as its native amount and symbol
+func (f *Fee) String() string {
+	return f.Amount + " " + f.Symbol
+}
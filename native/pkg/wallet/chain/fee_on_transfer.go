@@ -0,0 +1,252 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Where a transfer fee was learned from
+const (
+	TransferFeeSourceSimulation = "simulation"
+	TransferFeeSourceKnownToken = "known_token"
+)
+
+// erc20TransferTopic is the topic of Transfer(address,address,uint256)
+const erc20TransferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+
+// TokenTransferFee describes a fee-on-transfer token: the token contract keeps part
+// of every transfer, so the recipient receives less than was sent. Amounts are
+// in token units.
+type TokenTransferFee struct {
+	Token    string  `json:"token"`
+	Sent     string  `json:"sent"`
+	Received string  `json:"received"`
+	Percent  float64 `json:"percent"`
+	Source   string  `json:"source"` // TransferFeeSourceSimulation or TransferFeeSourceKnownToken
+}
+
+// TransferFeeDetector is implemented by chains that can tell whether a token
+// transfer delivers less than is sent
+type TransferFeeDetector interface {
+	// DetectTransferFee returns the fee token takes when amount is sent from
+	// from to to, or nil when the recipient receives the full amount.
+	// ErrSimulationUnavailable means the token could not be checked.
+	DetectTransferFee(ctx context.Context, from, to, amount, token string) (*TokenTransferFee, error)
+}
+
+// EVMSimulateFunc executes call with eth_simulateV1 against the latest block
+// and returns the logs it emitted
+type EVMSimulateFunc func(ctx context.Context, call EVMCall) ([]EVMLog, error)
+
+// NewEVMSimulateFunc returns an EVMSimulateFunc querying endpoints in order
+// until one answers. Nodes without eth_simulateV1 make it return
+// ErrSimulationUnavailable.
+func NewEVMSimulateFunc(chainName string, endpoints []string) EVMSimulateFunc {
+	client := httpclient.New(chainName+"-rpc", httpclient.WithTimeout(15*time.Second))
+	return func(ctx context.Context, call EVMCall) ([]EVMLog, error) {
+		if len(endpoints) == 0 {
+			return nil, fmt.Errorf("%w: no %s RPC endpoints configured", ErrSimulationUnavailable, chainName)
+		}
+		var lastErr error
+		for _, endpoint := range endpoints {
+			logs, err := evmSimulate(ctx, client, endpoint, call)
+			var callErr *evmCallError
+			if err == nil || errors.As(err, &callErr) {
+				return logs, err
+			}
+			lastErr = err
+		}
+		return nil, fmt.Errorf("%w: all RPC endpoints failed, last error: %v", ErrSimulationUnavailable, lastErr)
+	}
+}
+
+func evmSimulate(ctx context.Context, client *http.Client, endpoint string, call EVMCall) ([]EVMLog, error) {
+	payload := map[string]any{
+		"blockStateCalls": []map[string]any{{"calls": []EVMCall{call}}},
+	}
+	body, err := json.Marshal(RPCRequest{JSONRPC: "2.0", ID: 1, Method: "eth_simulateV1", Params: []any{payload, "latest"}})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &rpcHTTPError{StatusCode: resp.StatusCode}
+	}
+
+	// Simulated logs carry hex block numbers, so only the fields used are decoded
+	var response struct {
+		Result []struct {
+			Calls []struct {
+				Status string `json:"status"`
+				Logs   []struct {
+					Address string   `json:"address"`
+					Topics  []string `json:"topics"`
+					Data    string   `json:"data"`
+				} `json:"logs"`
+				Error *struct {
+					Code    int         `json:"code"`
+					Message string      `json:"message"`
+					Data    interface{} `json:"data"`
+				} `json:"error"`
+			} `json:"calls"`
+		} `json:"result"`
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if response.Error != nil {
+		// Typically -32601 method not found on nodes without eth_simulateV1
+		return nil, fmt.Errorf("%w: eth_simulateV1: %s", ErrSimulationUnavailable, response.Error.Message)
+	}
+	if len(response.Result) == 0 || len(response.Result[0].Calls) == 0 {
+		return nil, fmt.Errorf("%w: empty eth_simulateV1 result", ErrSimulationUnavailable)
+	}
+
+	result := response.Result[0].Calls[0]
+	if result.Error != nil || result.Status == "0x0" {
+		callErr := &evmCallError{message: "execution reverted"}
+		if result.Error != nil {
+			callErr = &evmCallError{code: result.Error.Code, message: result.Error.Message, data: result.Error.Data}
+		}
+		return nil, callErr
+	}
+	logs := make([]EVMLog, len(result.Logs))
+	for i, log := range result.Logs {
+		logs[i] = EVMLog{Address: log.Address, Topics: log.Topics, Data: log.Data}
+	}
+	return logs, nil
+}
+
+// evmTransferFeeCheck detects fee-on-transfer ERC-20 tokens, from the
+// configured list first, then by simulating the transfer and adding up the
+// Transfer events that credit the recipient
+type evmTransferFeeCheck struct {
+	call     EVMCallFunc
+	simulate EVMSimulateFunc
+	known    map[string]float64 // lower-case contract address to fee percent
+}
+
+func newEVMTransferFeeCheck(call EVMCallFunc, simulate EVMSimulateFunc, known map[string]float64) *evmTransferFeeCheck {
+	normalized := make(map[string]float64, len(known))
+	for token, fee := range known {
+		normalized[strings.ToLower(token)] = fee
+	}
+	return &evmTransferFeeCheck{call: call, simulate: simulate, known: normalized}
+}
+
+func (c *evmTransferFeeCheck) detect(ctx context.Context, from, to, amount, token, nativeSymbol string) (*TokenTransferFee, error) {
+	token = strings.TrimSpace(token)
+	if token == "" || strings.EqualFold(token, nativeSymbol) {
+		return nil, nil
+	}
+	if c == nil {
+		return nil, ErrSimulationUnavailable
+	}
+	if percent, ok := c.known[strings.ToLower(token)]; ok {
+		sent, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+		if !ok {
+			return &TokenTransferFee{Token: token, Percent: percent, Source: TransferFeeSourceKnownToken}, nil
+		}
+		// Parsed from its decimal text so 2.5 stays exactly 2.5
+		rate, _ := new(big.Rat).SetString(strconv.FormatFloat(percent, 'f', -1, 64))
+		kept := new(big.Rat).Sub(big.NewRat(1, 1), rate.Quo(rate, big.NewRat(100, 1)))
+		received := new(big.Rat).Mul(sent, kept)
+		return &TokenTransferFee{
+			Token:    token,
+			Sent:     formatTokenRat(sent),
+			Received: formatTokenRat(received),
+			Percent:  percent,
+			Source:   TransferFeeSourceKnownToken,
+		}, nil
+	}
+	if c.call == nil || c.simulate == nil || IsMaxAmount(amount) {
+		return nil, ErrSimulationUnavailable
+	}
+	if !common.IsHexAddress(from) || !common.IsHexAddress(to) {
+		return nil, errors.New("invalid address format")
+	}
+
+	message, sentUnits, err := erc20TransferMessage(ctx, c.call, from, to, amount, token)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := c.simulate(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	receivedUnits, found := creditedAmount(logs, token, to)
+	if !found {
+		return nil, fmt.Errorf("%w: the simulated transfer emitted no Transfer event to the recipient", ErrSimulationUnavailable)
+	}
+	if receivedUnits.Cmp(sentUnits) >= 0 {
+		return nil, nil
+	}
+
+	ratio := new(big.Rat).SetFrac(receivedUnits, sentUnits)
+	sent, _ := new(big.Rat).SetString(strings.TrimSpace(amount))
+	fee := new(big.Rat).Sub(big.NewRat(1, 1), ratio)
+	percent, _ := new(big.Rat).Mul(fee, big.NewRat(100, 1)).Float64()
+	return &TokenTransferFee{
+		Token:    token,
+		Sent:     formatTokenRat(sent),
+		Received: formatTokenRat(new(big.Rat).Mul(sent, ratio)),
+		Percent:  percent,
+		Source:   TransferFeeSourceSimulation,
+	}, nil
+}
+
+// creditedAmount adds up the Transfer events of token crediting recipient
+func creditedAmount(logs []EVMLog, token, recipient string) (*big.Int, bool) {
+	recipientTopic := hexutil.Encode(common.LeftPadBytes(common.HexToAddress(recipient).Bytes(), 32))
+	total := new(big.Int)
+	found := false
+	for _, log := range logs {
+		if !strings.EqualFold(log.Address, token) || len(log.Topics) != 3 ||
+			!strings.EqualFold(log.Topics[0], erc20TransferTopic) || !strings.EqualFold(log.Topics[2], recipientTopic) {
+			continue
+		}
+		value, err := hexutil.DecodeBig(trimHexZeros(log.Data))
+		if err != nil {
+			continue
+		}
+		total.Add(total, value)
+		found = true
+	}
+	return total, found
+}
+
+// formatTokenRat renders a token amount with up to 18 decimals and no trailing zeros
+func formatTokenRat(amount *big.Rat) string {
+	text := amount.FloatString(18)
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+	return text
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// feeOnTransferNode is an RPC node whose token keeps feeBasisPoints of every
+// transfer, sending it to a fee collector like taxed tokens do
+func feeOnTransferNode(t *testing.T, feeBasisPoints int64) *httptest.Server {
+	collector := "0x000000000000000000000000000000000000fee1"
+	topic := func(address string) string {
+		return hexutil.Encode(common.LeftPadBytes(common.HexToAddress(address).Bytes(), 32))
+	}
+	word := func(value *big.Int) string {
+		return hexutil.Encode(common.LeftPadBytes(value.Bytes(), 32))
+	}
+	return newRPCTestServer(t, map[string]rpcMethod{
+		"eth_call": rpcResult(`"0x0000000000000000000000000000000000000000000000000000000000000012"`),
+		"eth_simulateV1": func(rpc rpcCall) (string, error) {
+			var payload struct {
+				BlockStateCalls []struct {
+					Calls []EVMCall `json:"calls"`
+				} `json:"blockStateCalls"`
+			}
+			rpc.param(0, &payload)
+			call := payload.BlockStateCalls[0].Calls[0]
+			data := strings.TrimPrefix(call.Data, "0x"+erc20TransferSelector)
+			recipient := common.HexToAddress(data[24:64]).Hex()
+			amount, _ := new(big.Int).SetString(data[64:], 16)
+			fee := new(big.Int).Div(new(big.Int).Mul(amount, big.NewInt(feeBasisPoints)), big.NewInt(10_000))
+			received := new(big.Int).Sub(amount, fee)
+
+			logs := fmt.Sprintf(`{"address":%q,"topics":[%q,%q,%q],"data":%q,"blockNumber":"0x10"}`,
+				call.To, erc20TransferTopic, topic(call.From), topic(recipient), word(received))
+			if fee.Sign() > 0 {
+				logs += fmt.Sprintf(`,{"address":%q,"topics":[%q,%q,%q],"data":%q,"blockNumber":"0x10"}`,
+					call.To, erc20TransferTopic, topic(call.From), topic(collector), word(fee))
+			}
+			return `[{"number":"0x10","calls":[{"status":"0x1","returnData":"0x01","logs":[` + logs + `]}]}]`, nil
+		},
+	})
+}
+
+func TestDetectTransferFeeBySimulation(t *testing.T) {
+	server := feeOnTransferNode(t, 500)
+	chain := NewBSCChain(nil, zap.NewNop())
+	chain.SetTransferFeeCheck(NewEVMCallFunc("bsc", []string{server.URL}), NewEVMSimulateFunc("bsc", []string{server.URL}), nil)
+
+	fee, err := chain.DetectTransferFee(context.Background(), testWallet, testContract, "200", testToken)
+	require.NoError(t, err)
+	require.NotNil(t, fee)
+	assert.Equal(t, testToken, fee.Token)
+	assert.Equal(t, "200", fee.Sent)
+	assert.Equal(t, "190", fee.Received)
+	assert.InDelta(t, 5.0, fee.Percent, 1e-9)
+	assert.Equal(t, TransferFeeSourceSimulation, fee.Source)
+
+	// Native sends never take a token fee
+	fee, err = chain.DetectTransferFee(context.Background(), testWallet, testContract, "1", "BNB")
+	require.NoError(t, err)
+	assert.Nil(t, fee)
+}
+
+func TestDetectTransferFeeFullDelivery(t *testing.T) {
+	server := feeOnTransferNode(t, 0)
+	chain := NewETHChain(nil, zap.NewNop())
+	chain.SetTransferFeeCheck(NewEVMCallFunc("ethereum", []string{server.URL}), NewEVMSimulateFunc("ethereum", []string{server.URL}), nil)
+
+	fee, err := chain.DetectTransferFee(context.Background(), testWallet, testContract, "3.25", testToken)
+	require.NoError(t, err)
+	assert.Nil(t, fee)
+}
+
+func TestDetectTransferFeeKnownToken(t *testing.T) {
+	chain := NewETHChain(nil, zap.NewNop())
+	// No node is needed for tokens flagged in the configuration
+	chain.SetTransferFeeCheck(nil, nil, map[string]float64{testToken: 2.5})
+
+	fee, err := chain.DetectTransferFee(context.Background(), testWallet, testContract, "100", strings.ToLower(testToken))
+	require.NoError(t, err)
+	require.NotNil(t, fee)
+	assert.Equal(t, "97.5", fee.Received)
+	assert.Equal(t, 2.5, fee.Percent)
+	assert.Equal(t, TransferFeeSourceKnownToken, fee.Source)
+
+	// Unknown tokens cannot be checked without a node
+	_, err = chain.DetectTransferFee(context.Background(), testWallet, testContract, "100", testContract)
+	assert.ErrorIs(t, err, ErrSimulationUnavailable)
+}
+
+func TestEVMSimulateFuncUnsupported(t *testing.T) {
+	server := newRPCTestServer(t, map[string]rpcMethod{
+		"eth_simulateV1": func(rpcCall) (string, error) {
+			return "", &rpcTestError{Code: -32601, Message: "the method eth_simulateV1 does not exist/is not available"}
+		},
+	})
+
+	_, err := NewEVMSimulateFunc("ethereum", []string{server.URL})(context.Background(), EVMCall{To: testToken})
+	assert.ErrorIs(t, err, ErrSimulationUnavailable)
+	_, err = NewEVMSimulateFunc("ethereum", nil)(context.Background(), EVMCall{To: testToken})
+	assert.ErrorIs(t, err, ErrSimulationUnavailable)
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFee(t *testing.T) {
+	tests := []struct {
+		chain, amount string
+		want          Fee
+	}{
+		{"ethereum", "0.00042", Fee{Amount: "0.00042", Symbol: "ETH", SmallestUnit: "420000000000000", Unit: "wei"}},
+		{"BSC", "0.000105", Fee{Amount: "0.000105", Symbol: "BNB", SmallestUnit: "105000000000000", Unit: "wei"}},
+		{"sol", "0.000005", Fee{Amount: "0.000005", Symbol: "SOL", SmallestUnit: "5000", Unit: "lamports"}},
+		// Below a lamport is rounded down
+		{"solana", "0.0000050001", Fee{Amount: "0.0000050001", Symbol: "SOL", SmallestUnit: "5000", Unit: "lamports"}},
+	}
+	for _, tt := range tests {
+		fee, err := NewFee(tt.chain, tt.amount)
+		require.NoError(t, err, tt.chain)
+		assert.Equal(t, tt.want, *fee, tt.chain)
+	}
+
+	_, err := NewFee("polygon", "1")
+	assert.Error(t, err)
+	_, err = NewFee("ethereum", "-1")
+	assert.Error(t, err)
+}
+
+func TestEstimateFee(t *testing.T) {
+	fee, err := EstimateFee("ethereum", 21000, "20")
+	require.NoError(t, err)
+	assert.Equal(t, "0.00042 ETH", fee.String())
+	assert.Equal(t, "420000000000000", fee.SmallestUnit)
+
+	// 200000 CU at 1000 microlamports plus the signature fee
+	fee, err = EstimateFee("solana", 200000, "1000")
+	require.NoError(t, err)
+	assert.Equal(t, "5200", fee.SmallestUnit)
+	assert.Equal(t, "0.0000052", fee.Amount)
+}
+
+func TestConfirmTransactionReportsFee(t *testing.T) {
+	t.Setenv("RUN_MODE", "test")
+	chains := map[string]IChain{"ETH": NewETHChainLegacy(), "BNB": NewBSCChainLegacy(), "SOL": NewSolanaChainLegacy()}
+	hashes := map[string]string{
+		"ETH": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		"BNB": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		"SOL": "5VERv8NMvzbJMEkV8xnrLkEaWRtSz9CosKDYjCJjBRnbJLgp8uirBgmQpjKhoR4tjF3ZpRzrFmBV6UjKdiSZkQUW",
+	}
+	for symbol, c := range chains {
+		confirmation, err := c.ConfirmTransaction(context.Background(), hashes[symbol], 1)
+		require.NoError(t, err, symbol)
+		require.NotNil(t, confirmation.Fee, symbol)
+		assert.Equal(t, symbol, confirmation.Fee.Symbol)
+		// The string field is kept for existing consumers
+		assert.NotEmpty(t, confirmation.TransactionFee, symbol)
+		assert.NotEmpty(t, confirmation.Fee.SmallestUnit, symbol)
+	}
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+)
+
+// chainTiming is the average block production and finality depth of a chain
+type chainTiming struct {
+	blockTime      time.Duration
+	finalityBlocks uint64
+	finality       string
+}
+
+// chainTimings are averages observed on mainnet; actual times vary with load
+var chainTimings = map[string]chainTiming{
+	// Blocks are final once two epochs of 32 slots are justified
+	"ethereum": {blockTime: 12 * time.Second, finalityBlocks: 64, finality: "two epochs (Casper FFG)"},
+	// Fast finality finalizes a block once its child is attested
+	"bsc": {blockTime: 750 * time.Millisecond, finalityBlocks: 2, finality: "fast finality (BEP-126)"},
+	// A slot is rooted, i.e. finalized, 32 confirmed slots later
+	"solana": {blockTime: 400 * time.Millisecond, finalityBlocks: 32, finality: "32 confirmed slots (finalized commitment)"},
+}
+
+// FinalityEstimate estimates how long a transaction takes to be considered
+// confirmed by the wallet and to become final on chain
+type FinalityEstimate struct {
+	Chain                 string        `json:"chain"`
+	BlockTime             time.Duration `json:"block_time"`
+	RequiredConfirmations int           `json:"required_confirmations"`
+	TimeToConfirm         time.Duration `json:"time_to_confirm"`
+	FinalityBlocks        uint64        `json:"finality_blocks"`
+	TimeToFinality        time.Duration `json:"time_to_finality"`
+	Finality              string        `json:"finality"` // how the chain finalizes blocks
+}
+
+// EstimateFinality returns the estimate for requiredConfirmations blocks on
+// chainName. It returns false for a chain without known timings.
+func EstimateFinality(chainName string, requiredConfirmations int) (FinalityEstimate, bool) {
+	chainName = config.NormalizeChain(chainName)
+	timing, ok := chainTimings[chainName]
+	if !ok {
+		return FinalityEstimate{}, false
+	}
+	if requiredConfirmations < 0 {
+		requiredConfirmations = 0
+	}
+	return FinalityEstimate{
+		Chain:                 chainName,
+		BlockTime:             timing.blockTime,
+		RequiredConfirmations: requiredConfirmations,
+		TimeToConfirm:         time.Duration(requiredConfirmations) * timing.blockTime,
+		FinalityBlocks:        timing.finalityBlocks,
+		TimeToFinality:        time.Duration(timing.finalityBlocks) * timing.blockTime,
+		Finality:              timing.finality,
+	}, true
+}
@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateFinality(t *testing.T) {
+	estimate, ok := EstimateFinality("sol", 1)
+	require.True(t, ok)
+	assert.Equal(t, "solana", estimate.Chain)
+	assert.Equal(t, 400*time.Millisecond, estimate.TimeToConfirm)
+	assert.Equal(t, 12800*time.Millisecond, estimate.TimeToFinality)
+
+	estimate, ok = EstimateFinality("ETH", 12)
+	require.True(t, ok)
+	assert.Equal(t, 144*time.Second, estimate.TimeToConfirm)
+	assert.Equal(t, uint64(64), estimate.FinalityBlocks)
+
+	_, ok = EstimateFinality("polygon", 6)
+	assert.False(t, ok)
+}
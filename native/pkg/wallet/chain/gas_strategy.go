@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Gas strategies selectable through the chain gas_strategy setting
+const (
+	GasStrategySlow     = "slow"
+	GasStrategyStandard = "standard"
+	GasStrategyFast     = "fast"
+	GasStrategyDynamic  = "dynamic"
+)
+
+// Bounds applied to the dynamic strategy's fee multiplier
+const (
+	minDynamicGasMultiplier = 1.0
+	maxDynamicGasMultiplier = 2.0
+)
+
+// staticGasMultipliers scale the current base fee and priority fee for the fixed strategies
+var staticGasMultipliers = map[string]float64{
+	GasStrategySlow:     1.0,
+	GasStrategyStandard: 1.25,
+	GasStrategyFast:     1.5,
+}
+
+// ErrGasStrategyUnsupported is returned for chains without EVM-style fee markets
+var ErrGasStrategyUnsupported = errors.New("gas strategies are not supported on this chain")
+
+// MempoolSample captures recent fee-market conditions used to price a transaction
+type MempoolSample struct {
+	BaseFeesGwei    []float64 // base fee of recent blocks, oldest first
+	PriorityFeeGwei float64   // median priority fee paid in recent blocks
+	PendingTxCount  int       // transactions currently waiting in the pending pool
+	NormalPoolDepth int       // pending pool size considered uncongested for the chain
+}
+
+// GasParams are the fee parameters chosen for a transaction
+type GasParams struct {
+	Strategy        string  `json:"strategy"`
+	BaseFeeGwei     float64 `json:"base_fee_gwei"`
+	PriorityFeeGwei float64 `json:"priority_fee_gwei"`
+	MaxFeeGwei      float64 `json:"max_fee_gwei"`
+	Multiplier      float64 `json:"multiplier"`
+	BaseFeeTrend    float64 `json:"base_fee_trend"` // relative change across the sampled blocks
+	PoolPressure    float64 `json:"pool_pressure"`  // pending pool depth relative to NormalPoolDepth
+	Capped          bool    `json:"capped"`         // true when MaxFeeGwei was clamped to the ceiling
+}
+
+// GasAdvisor is implemented by chains that can price transactions from mempool conditions
+type GasAdvisor interface {
+	// SuggestGasParams returns fee parameters for strategy, or the configured strategy when empty
+	SuggestGasParams(ctx context.Context, strategy string) (*GasParams, error)
+}
+
+// IsValidGasStrategy reports whether strategy is one of the supported gas strategies
+func IsValidGasStrategy(strategy string) bool {
+	strategy = strings.ToLower(strings.TrimSpace(strategy))
+	_, static := staticGasMultipliers[strategy]
+	return static || strategy == GasStrategyDynamic
+}
+
+// ComputeGasParams prices a transaction for the given strategy. Static strategies
+// apply a fixed multiplier; the dynamic strategy raises the multiplier when base
+// fees are trending up or the pending pool is deeper than normal. The resulting
+// max fee never exceeds maxFeeCeilingGwei when it is positive.
+func ComputeGasParams(strategy string, sample MempoolSample, maxFeeCeilingGwei float64) (*GasParams, error) {
+	strategy = strings.ToLower(strings.TrimSpace(strategy))
+	if strategy == "" {
+		strategy = GasStrategyStandard
+	}
+	if !IsValidGasStrategy(strategy) {
+		return nil, fmt.Errorf("unsupported gas strategy: %s", strategy)
+	}
+	if len(sample.BaseFeesGwei) == 0 {
+		return nil, errors.New("mempool sample has no base fee history")
+	}
+
+	baseFee := sample.BaseFeesGwei[len(sample.BaseFeesGwei)-1]
+	params := &GasParams{
+		Strategy:     strategy,
+		BaseFeeGwei:  baseFee,
+		BaseFeeTrend: baseFeeTrend(sample.BaseFeesGwei),
+		PoolPressure: poolPressure(sample),
+	}
+
+	if multiplier, ok := staticGasMultipliers[strategy]; ok {
+		params.Multiplier = multiplier
+		params.PriorityFeeGwei = sample.PriorityFeeGwei * multiplier
+	} else {
+		multiplier := minDynamicGasMultiplier
+		// Rising base fees: bid ahead of the curve so the tx survives a few more blocks
+		if params.BaseFeeTrend > 0 {
+			multiplier += math.Min(params.BaseFeeTrend, 0.5)
+		}
+		// Congested pool: compete harder for inclusion
+		multiplier += clamp((params.PoolPressure-1)*0.25, 0, 0.5)
+		params.Multiplier = clamp(multiplier, minDynamicGasMultiplier, maxDynamicGasMultiplier)
+		params.PriorityFeeGwei = sample.PriorityFeeGwei * (1 + clamp(params.PoolPressure-1, 0, 1))
+	}
+
+	params.MaxFeeGwei = baseFee*params.Multiplier + params.PriorityFeeGwei
+
+	if maxFeeCeilingGwei > 0 {
+		if maxFeeCeilingGwei < baseFee {
+			return nil, fmt.Errorf("max fee ceiling %.2f gwei is below the current base fee %.2f gwei", maxFeeCeilingGwei, baseFee)
+		}
+		if params.MaxFeeGwei > maxFeeCeilingGwei {
+			params.MaxFeeGwei = maxFeeCeilingGwei
+			if params.PriorityFeeGwei > maxFeeCeilingGwei-baseFee {
+				params.PriorityFeeGwei = maxFeeCeilingGwei - baseFee
+			}
+			params.Capped = true
+		}
+	}
+
+	params.PriorityFeeGwei = roundGwei(params.PriorityFeeGwei)
+	params.MaxFeeGwei = roundGwei(params.MaxFeeGwei)
+	params.Multiplier = math.Round(params.Multiplier*1000) / 1000
+	return params, nil
+}
+
+// ScaleGasParams returns a copy of params with the priority fee and max fee
+// raised by multiplier, a safety margin on top of the strategy's own pricing.
+// The base fee is left as observed, and the max fee is clamped to
+// maxFeeCeilingGwei again when it is positive.
+func ScaleGasParams(params *GasParams, multiplier, maxFeeCeilingGwei float64) *GasParams {
+	scaled := *params
+	if multiplier == 1 {
+		return &scaled
+	}
+	scaled.PriorityFeeGwei = params.PriorityFeeGwei * multiplier
+	scaled.MaxFeeGwei = params.MaxFeeGwei * multiplier
+	if maxFeeCeilingGwei > 0 && scaled.MaxFeeGwei > maxFeeCeilingGwei {
+		scaled.MaxFeeGwei = maxFeeCeilingGwei
+		scaled.PriorityFeeGwei = math.Min(scaled.PriorityFeeGwei, maxFeeCeilingGwei-params.BaseFeeGwei)
+		scaled.Capped = true
+	}
+	scaled.PriorityFeeGwei = roundGwei(scaled.PriorityFeeGwei)
+	scaled.MaxFeeGwei = roundGwei(scaled.MaxFeeGwei)
+	return &scaled
+}
+
+// baseFeeTrend returns the relative change between the oldest and newest base fee
+func baseFeeTrend(baseFees []float64) float64 {
+	first, last := baseFees[0], baseFees[len(baseFees)-1]
+	if first <= 0 {
+		return 0
+	}
+	return (last - first) / first
+}
+
+// poolPressure returns pending pool depth relative to its normal depth
+func poolPressure(sample MempoolSample) float64 {
+	if sample.NormalPoolDepth <= 0 {
+		return 0
+	}
+	return float64(sample.PendingTxCount) / float64(sample.NormalPoolDepth)
+}
+
+func clamp(value, lower, upper float64) float64 {
+	return math.Max(lower, math.Min(upper, value))
+}
+
+func roundGwei(value float64) float64 {
+	return math.Round(value*1e4) / 1e4
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// hardenedOffset marks a BIP-32 child index as hardened
+const hardenedOffset = 0x80000000
+
+// DeriveSecp256k1Key derives the BIP-32 private key at path (e.g. m/44'/60'/0'/0/1) from seed
+func DeriveSecp256k1Key(seed []byte, path string) (*ecdsa.PrivateKey, error) {
+	indexes, err := parseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, chainCode := hmacSHA512([]byte("Bitcoin seed"), seed)
+	order := crypto.S256().Params().N
+	for _, index := range indexes {
+		var data []byte
+		if index >= hardenedOffset {
+			data = append([]byte{0}, key...)
+		} else {
+			parent, err := crypto.ToECDSA(key)
+			if err != nil {
+				return nil, err
+			}
+			data = crypto.CompressPubkey(&parent.PublicKey)
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		il, ir := hmacSHA512(chainCode, data)
+		tweak := new(big.Int).SetBytes(il)
+		if tweak.Cmp(order) >= 0 {
+			return nil, errors.New("invalid child key, derive the next index instead")
+		}
+		child := tweak.Add(tweak, new(big.Int).SetBytes(key))
+		child.Mod(child, order)
+		if child.Sign() == 0 {
+			return nil, errors.New("invalid child key, derive the next index instead")
+		}
+		key = child.FillBytes(make([]byte, 32))
+		chainCode = ir
+	}
+	return crypto.ToECDSA(key)
+}
+
+// DeriveEd25519Key derives the SLIP-10 ed25519 private key at path (e.g.
+// m/44'/501'/1'/0'). ed25519 only supports hardened derivation.
+func DeriveEd25519Key(seed []byte, path string) (ed25519.PrivateKey, error) {
+	indexes, err := parseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, chainCode := hmacSHA512([]byte("ed25519 seed"), seed)
+	for _, index := range indexes {
+		if index < hardenedOffset {
+			return nil, fmt.Errorf("invalid derivation path %q: ed25519 requires hardened indexes", path)
+		}
+		data := binary.BigEndian.AppendUint32(append([]byte{0}, key...), index)
+		key, chainCode = hmacSHA512(chainCode, data)
+	}
+	return ed25519.NewKeyFromSeed(key), nil
+}
+
+// parseDerivationPath splits an absolute path such as m/44'/60'/0'/0/0 into
+// child indexes, with hardened components offset by hardenedOffset
+func parseDerivationPath(path string) ([]uint32, error) {
+	components := strings.Split(strings.TrimSpace(path), "/")
+	if len(components) < 2 || components[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q: must start with m/", path)
+	}
+
+	indexes := make([]uint32, 0, len(components)-1)
+	for _, component := range components[1:] {
+		hardened := strings.HasSuffix(component, "'") || strings.HasSuffix(component, "h")
+		value, err := strconv.ParseUint(strings.TrimRight(component, "'h"), 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path %q: bad component %q", path, component)
+		}
+		index := uint32(value)
+		if hardened {
+			index += hardenedOffset
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
+
+func hmacSHA512(key, data []byte) (left, right []byte) {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
@@ -20,10 +20,14 @@ type TransactionConfirmation struct {
 	Confirmations        uint64    `json:"confirmations"`          // Current number of confirmations
 	RequiredConfirmations uint64   `json:"required_confirmations"` // Required confirmations for finality
 	BlockNumber          uint64    `json:"block_number"`           // Block number containing the transaction
+	BlockHash            string    `json:"block_hash,omitempty"`   // Hash of the block containing the transaction
 	GasUsed              string    `json:"gas_used"`               // Gas units used
 	TransactionFee       string    `json:"transaction_fee"`        // Transaction fee in native currency
+	Fee                  *Fee      `json:"fee,omitempty"`          // TransactionFee with its symbol and smallest-unit amount
 	Timestamp            time.Time `json:"timestamp"`              // Block timestamp
 	TxHash               string    `json:"tx_hash"`                // Transaction hash
+	RevertReason         *RevertReason `json:"revert_reason,omitempty"` // Decoded revert data of a failed EVM transaction
+	Commitment           string    `json:"commitment,omitempty"`   // Solana commitment level the status was checked at
 }
 
 // IChain defines the interface for blockchain-specific operations
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// AmountMax requests a transfer of the whole balance available to send
+const AmountMax = "max"
+
+// ErrNativeReserveViolation is returned when a native transfer would leave less than the gas reserve
+var ErrNativeReserveViolation = errors.New("transfer would leave less than the native gas reserve")
+
+// NativeReserver is implemented by chains that keep part of the native balance back for gas
+type NativeReserver interface {
+	// NativeReserve returns the amount of native token that sends must leave behind
+	NativeReserve() float64
+}
+
+// IsMaxAmount reports whether amount requests the full available balance
+func IsMaxAmount(amount string) bool {
+	return strings.EqualFold(strings.TrimSpace(amount), AmountMax)
+}
+
+// AvailableToSend returns balance minus reserve, floored at zero
+func AvailableToSend(balance string, reserve float64) (string, error) {
+	available, err := availableAfterReserve(balance, reserve)
+	if err != nil {
+		return "", err
+	}
+	if available.Sign() < 0 {
+		available.SetInt64(0)
+	}
+	return formatDecimal(available), nil
+}
+
+// resolveNativeAmount applies the gas reserve to a native transfer. "max" becomes
+// the balance minus the reserve; explicit amounts are rejected if they would dip
+// into the reserve. When the balance cannot be read, explicit amounts are let
+// through (the node still rejects overdrafts) but "max" cannot be resolved.
+func resolveNativeAmount(ctx context.Context, logger *zap.Logger, chainName, amount string, reserve float64, balanceFn func(ctx context.Context) (string, error)) (string, error) {
+	isMax := IsMaxAmount(amount)
+	if !isMax && reserve <= 0 {
+		return amount, nil
+	}
+
+	balance, err := balanceFn(ctx)
+	if err != nil {
+		if isMax {
+			return "", fmt.Errorf("cannot resolve max amount: %w", err)
+		}
+		if logger != nil {
+			logger.Warn("Balance unavailable, native reserve not enforced",
+				zap.String("chain", chainName),
+				zap.Error(err))
+		}
+		return amount, nil
+	}
+
+	available, err := availableAfterReserve(balance, reserve)
+	if err != nil {
+		return "", err
+	}
+
+	if isMax {
+		if available.Sign() <= 0 {
+			return "", fmt.Errorf("%w: balance %s does not exceed reserve %g", ErrNativeReserveViolation, balance, reserve)
+		}
+		return formatDecimal(available), nil
+	}
+
+	requested, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok {
+		return "", fmt.Errorf("invalid amount: %s", amount)
+	}
+	if requested.Cmp(available) > 0 {
+		return "", fmt.Errorf("%w: sending %s of balance %s leaves less than %g", ErrNativeReserveViolation, amount, balance, reserve)
+	}
+	return amount, nil
+}
+
+func availableAfterReserve(balance string, reserve float64) (*big.Rat, error) {
+	total, ok := new(big.Rat).SetString(strings.TrimSpace(balance))
+	if !ok {
+		return nil, fmt.Errorf("invalid balance: %s", balance)
+	}
+	reserved := new(big.Rat)
+	if reserve > 0 {
+		// Go through the shortest decimal form so 0.005 stays exactly 0.005
+		reserved.SetString(strconv.FormatFloat(reserve, 'f', -1, 64))
+	}
+	return total.Sub(total, reserved), nil
+}
+
+// formatDecimal renders r with up to 18 decimals and no trailing zeros
+func formatDecimal(r *big.Rat) string {
+	s := r.FloatString(18)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
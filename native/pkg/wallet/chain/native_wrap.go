@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Directions of a WrapResult
+const (
+	WrapDirectionWrap   = "wrap"
+	WrapDirectionUnwrap = "unwrap"
+)
+
+// WrappedNativeToken is the wrapped form of a chain's native coin
+type WrappedNativeToken struct {
+	Symbol   string
+	Address  string // WETH-style contract on EVM chains, the native mint on Solana
+	Decimals int
+}
+
+// wrappedNativeTokens lists the wrapped native token of each chain
+var wrappedNativeTokens = map[string]WrappedNativeToken{
+	"ethereum": {Symbol: "WETH", Address: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", Decimals: 18},
+	"bsc":      {Symbol: "WBNB", Address: "0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c", Decimals: 18},
+	"solana":   {Symbol: "WSOL", Address: "So11111111111111111111111111111111111111112", Decimals: 9},
+}
+
+// WrappedNative returns the wrapped native token of a normalized chain
+func WrappedNative(chainName string) (WrappedNativeToken, bool) {
+	token, ok := wrappedNativeTokens[chainName]
+	return token, ok
+}
+
+// IsWrappedNative reports whether token, a symbol or address, is the wrapped
+// native token of a normalized chain
+func IsWrappedNative(chainName, token string) bool {
+	wrapped, ok := wrappedNativeTokens[chainName]
+	if !ok {
+		return false
+	}
+	token = strings.TrimSpace(token)
+	if chainName == "solana" {
+		return token == wrapped.Address || strings.EqualFold(token, wrapped.Symbol)
+	}
+	return strings.EqualFold(token, wrapped.Address) || strings.EqualFold(token, wrapped.Symbol)
+}
+
+// NativeWrapper is implemented by chains that can convert their native coin to
+// and from its wrapped token
+type NativeWrapper interface {
+	// WrapNative converts amount of the native coin held by owner, whose key
+	// is privateKey, into the wrapped token. "max" wraps everything above the
+	// native reserve.
+	WrapNative(ctx context.Context, owner, amount, privateKey string) (*WrapResult, error)
+	// UnwrapNative converts amount of owner's wrapped token ("max" for all of
+	// it) back into the native coin
+	UnwrapNative(ctx context.Context, owner, amount, privateKey string) (*WrapResult, error)
+}
+
+// WrapResult describes a broadcast wrap or unwrap
+type WrapResult struct {
+	Chain        string             `json:"chain"`
+	Direction    string             `json:"direction"` // "wrap" or "unwrap"
+	TxHash       string             `json:"tx_hash"`
+	Owner        string             `json:"owner"`
+	Amount       string             `json:"amount"` // native coin moved into or out of the wrapped token
+	NativeSymbol string             `json:"native_symbol"`
+	Wrapped      WrappedNativeToken `json:"wrapped"`
+	// WrappedBalance is the wrapped token balance once the transaction lands;
+	// empty when the balance before it could not be read
+	WrappedBalance string `json:"wrapped_balance,omitempty"`
+	// Account is the Solana token account that holds the wrapped SOL
+	Account string `json:"account,omitempty"`
+	// AccountCreated and AccountClosed report Solana token accounts opened
+	// or closed, whose rent moves with them
+	AccountCreated bool `json:"account_created,omitempty"`
+	AccountClosed  bool `json:"account_closed,omitempty"`
+}
+
+// WETH-style contract function selectors
+const (
+	wethDepositSelector  = "d0e30db0" // deposit()
+	wethWithdrawSelector = "2e1a7d4d" // withdraw(uint256)
+)
+
+// BuildWETHDepositCalldata encodes deposit(), which wraps the ETH sent with it
+func BuildWETHDepositCalldata() []byte {
+	return common.FromHex(wethDepositSelector)
+}
+
+// BuildWETHWithdrawCalldata encodes withdraw(wad), which unwraps wad wei
+func BuildWETHWithdrawCalldata(wad *big.Int) []byte {
+	return append(common.FromHex(wethWithdrawSelector), common.LeftPadBytes(wad.Bytes(), 32)...)
+}
+
+// evmNativeWrap wraps and unwraps the native coin of an EVM chain through its
+// WETH-style contract
+type evmNativeWrap struct {
+	chainName string
+	native    string
+	// balance reads a balance, failing rather than reporting zero when it is unavailable
+	balance func(ctx context.Context, address, token string) (string, error)
+	// spendable resolves a native amount against the balance and the gas reserve
+	spendable func(ctx context.Context, from, amount string) (string, error)
+}
+
+func (w evmNativeWrap) wrap(ctx context.Context, owner, amount, privateKey string) (*WrapResult, error) {
+	wrapped := wrappedNativeTokens[w.chainName]
+	if err := checkEVMOwnerKey(owner, privateKey); err != nil {
+		return nil, err
+	}
+	resolved, err := w.spendable(ctx, owner, amount)
+	if err != nil {
+		return nil, err
+	}
+	// The reserve check lets explicit amounts through when it has no reserve
+	// to keep, so the balance is checked here too
+	if balance, err := w.balance(ctx, owner, w.native); err == nil {
+		if _, err := resolveWrappedAmount(resolved, balance, w.native); err != nil {
+			return nil, fmt.Errorf("cannot wrap %s %s with a balance of %s", resolved, w.native, balance)
+		}
+	}
+	wei, err := scaleAmount(resolved, int64(wrapped.Decimals))
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO: sign and broadcast deposit() with value wei against the wrapped token contract once EVM broadcasting is implemented
+	calldata := BuildWETHDepositCalldata()
+	result := w.result(WrapDirectionWrap, owner, resolved, calldata, wei)
+	if before, err := w.balance(ctx, owner, wrapped.Address); err == nil {
+		result.WrappedBalance = addDecimal(before, resolved, 1)
+	}
+	return result, nil
+}
+
+func (w evmNativeWrap) unwrap(ctx context.Context, owner, amount, privateKey string) (*WrapResult, error) {
+	wrapped := wrappedNativeTokens[w.chainName]
+	if err := checkEVMOwnerKey(owner, privateKey); err != nil {
+		return nil, err
+	}
+	balance, err := w.balance(ctx, owner, wrapped.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s balance: %w", wrapped.Symbol, err)
+	}
+	resolved, err := resolveWrappedAmount(amount, balance, wrapped.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	wei, err := scaleAmount(resolved, int64(wrapped.Decimals))
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO: sign and broadcast withdraw(wei) against the wrapped token contract once EVM broadcasting is implemented
+	calldata := BuildWETHWithdrawCalldata(wei)
+	result := w.result(WrapDirectionUnwrap, owner, resolved, calldata, wei)
+	result.WrappedBalance = addDecimal(balance, resolved, -1)
+	return result, nil
+}
+
+func (w evmNativeWrap) result(direction, owner, amount string, calldata []byte, wei *big.Int) *WrapResult {
+	wrapped := wrappedNativeTokens[w.chainName]
+	txHash := crypto.Keccak256Hash(calldata, wei.Bytes(), []byte(owner), []byte(wrapped.Address), []byte(fmt.Sprintf("%d", time.Now().UnixNano())))
+	return &WrapResult{
+		Chain:        w.chainName,
+		Direction:    direction,
+		TxHash:       txHash.Hex(),
+		Owner:        owner,
+		Amount:       amount,
+		NativeSymbol: w.native,
+		Wrapped:      wrapped,
+	}
+}
+
+// checkEVMOwnerKey makes sure privateKey controls owner
+func checkEVMOwnerKey(owner, privateKey string) error {
+	if !common.IsHexAddress(owner) {
+		return fmt.Errorf("invalid owner address: %s", owner)
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(privateKey, "0x"))
+	if err != nil {
+		return errors.New("invalid private key")
+	}
+	if crypto.PubkeyToAddress(key.PublicKey) != common.HexToAddress(owner) {
+		return fmt.Errorf("private key does not belong to %s", owner)
+	}
+	return nil
+}
+
+// resolveWrappedAmount resolves an unwrap amount against the wrapped balance:
+// "max" is all of it, and explicit amounts may not exceed it
+func resolveWrappedAmount(amount, balance, symbol string) (string, error) {
+	held, ok := new(big.Rat).SetString(strings.TrimSpace(balance))
+	if !ok {
+		return "", fmt.Errorf("invalid %s balance: %s", symbol, balance)
+	}
+	if IsMaxAmount(amount) {
+		if held.Sign() <= 0 {
+			return "", fmt.Errorf("no %s to unwrap", symbol)
+		}
+		return formatDecimal(held), nil
+	}
+	requested, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok || requested.Sign() <= 0 {
+		return "", fmt.Errorf("invalid amount: %s", amount)
+	}
+	if requested.Cmp(held) > 0 {
+		return "", fmt.Errorf("cannot unwrap %s %s with a balance of %s", amount, symbol, formatDecimal(held))
+	}
+	return strings.TrimSpace(amount), nil
+}
+
+// addDecimal returns a + sign*b for decimal strings, or "" when either does not parse
+func addDecimal(a, b string, sign int) string {
+	x, okA := new(big.Rat).SetString(strings.TrimSpace(a))
+	y, okB := new(big.Rat).SetString(strings.TrimSpace(b))
+	if !okA || !okB {
+		return ""
+	}
+	if sign < 0 {
+		y.Neg(y)
+	}
+	return formatDecimal(x.Add(x, y))
+}
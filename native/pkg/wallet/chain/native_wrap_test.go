@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsWrappedNative(t *testing.T) {
+	assert.True(t, IsWrappedNative("ethereum", "weth"))
+	assert.True(t, IsWrappedNative("ethereum", "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"))
+	assert.True(t, IsWrappedNative("bsc", "WBNB"))
+	assert.True(t, IsWrappedNative("solana", "So11111111111111111111111111111111111111112"))
+	assert.False(t, IsWrappedNative("solana", "so11111111111111111111111111111111111111112"), "mints are case-sensitive")
+	assert.False(t, IsWrappedNative("ethereum", "ETH"))
+	assert.False(t, IsWrappedNative("bsc", "WETH"))
+	assert.False(t, IsWrappedNative("polygon", "WETH"))
+}
+
+func TestBuildWETHCalldata(t *testing.T) {
+	assert.Equal(t, "d0e30db0", hex.EncodeToString(BuildWETHDepositCalldata()))
+	calldata := BuildWETHWithdrawCalldata(big.NewInt(1_500_000_000_000_000_000))
+	require.Len(t, calldata, 36)
+	assert.Equal(t, "2e1a7d4d", hex.EncodeToString(calldata[:4]))
+	assert.Equal(t, big.NewInt(1_500_000_000_000_000_000), new(big.Int).SetBytes(calldata[4:]))
+}
+
+func TestResolveWrappedAmount(t *testing.T) {
+	amount, err := resolveWrappedAmount("max", "1.25", "WETH")
+	require.NoError(t, err)
+	assert.Equal(t, "1.25", amount)
+
+	amount, err = resolveWrappedAmount("0.5", "1.25", "WETH")
+	require.NoError(t, err)
+	assert.Equal(t, "0.5", amount)
+
+	_, err = resolveWrappedAmount("2", "1.25", "WETH")
+	assert.ErrorContains(t, err, "cannot unwrap 2 WETH with a balance of 1.25")
+	_, err = resolveWrappedAmount("max", "0", "WETH")
+	assert.ErrorContains(t, err, "no WETH to unwrap")
+	_, err = resolveWrappedAmount("-1", "1", "WETH")
+	assert.ErrorContains(t, err, "invalid amount")
+}
+
+func TestEVMNativeWrap(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	owner := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	privateKey := hex.EncodeToString(crypto.FromECDSA(key))
+
+	balances := map[string]string{"ETH": "2", wrappedNativeTokens["ethereum"].Address: "0.75"}
+	wrap := evmNativeWrap{
+		chainName: "ethereum",
+		native:    "ETH",
+		balance: func(_ context.Context, _, token string) (string, error) {
+			if balance, ok := balances[token]; ok {
+				return balance, nil
+			}
+			return "", errors.New("balance unavailable")
+		},
+		spendable: func(_ context.Context, _, amount string) (string, error) {
+			if IsMaxAmount(amount) {
+				return "1.99", nil
+			}
+			return amount, nil
+		},
+	}
+
+	result, err := wrap.wrap(context.Background(), owner, "1", privateKey)
+	require.NoError(t, err)
+	assert.Equal(t, WrapDirectionWrap, result.Direction)
+	assert.Equal(t, "1", result.Amount)
+	assert.Equal(t, "WETH", result.Wrapped.Symbol)
+	assert.Equal(t, "1.75", result.WrappedBalance)
+	assert.Len(t, result.TxHash, 66)
+
+	result, err = wrap.wrap(context.Background(), owner, "max", privateKey)
+	require.NoError(t, err)
+	assert.Equal(t, "1.99", result.Amount)
+
+	_, err = wrap.wrap(context.Background(), owner, "3", privateKey)
+	assert.ErrorContains(t, err, "cannot wrap 3 ETH with a balance of 2")
+
+	result, err = wrap.unwrap(context.Background(), owner, "0.25", privateKey)
+	require.NoError(t, err)
+	assert.Equal(t, WrapDirectionUnwrap, result.Direction)
+	assert.Equal(t, "0.5", result.WrappedBalance)
+
+	result, err = wrap.unwrap(context.Background(), owner, "max", privateKey)
+	require.NoError(t, err)
+	assert.Equal(t, "0.75", result.Amount)
+	assert.Equal(t, "0", result.WrappedBalance)
+
+	_, err = wrap.unwrap(context.Background(), owner, "1", privateKey)
+	assert.ErrorContains(t, err, "cannot unwrap 1 WETH")
+
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = wrap.wrap(context.Background(), owner, "1", hex.EncodeToString(crypto.FromECDSA(other)))
+	assert.ErrorContains(t, err, "does not belong to")
+}
@@ -0,0 +1,302 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Gasless approval standards a token can implement
+const (
+	// PermitTypeEIP2612 approves a spender with a signed permit instead of an
+	// approve transaction
+	PermitTypeEIP2612 = "eip2612"
+	// PermitTypeEIP3009 authorizes a relayer to move tokens straight to a
+	// recipient with transferWithAuthorization
+	PermitTypeEIP3009 = "eip3009"
+)
+
+// ErrPermitNotSupported is returned when a token does not implement the
+// requested gasless approval standard
+var ErrPermitNotSupported = errors.New("token does not support gasless approvals")
+
+// Selectors of the calls used to detect and use permits
+const (
+	permitDomainSeparatorSelector     = "3644e515" // DOMAIN_SEPARATOR()
+	permitNoncesSelector              = "7ecebe00" // nonces(address)
+	permitTypehashSelector            = "30adf81f" // PERMIT_TYPEHASH()
+	permitAuthorizationStateSelector  = "e94a0102" // authorizationState(address,bytes32)
+	erc20NameSelector                 = "06fdde03" // name()
+	eip712VersionSelector             = "54fd4d50" // version()
+	permitSelector                    = "d505accf" // permit(address,address,uint256,uint256,uint8,bytes32,bytes32)
+	transferWithAuthorizationSelector = "e3ee160e" // transferWithAuthorization(address,address,uint256,uint256,uint256,bytes32,uint8,bytes32,bytes32)
+)
+
+// EIP-712 type hashes of the signed structs
+var (
+	eip712DomainTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	permitTypeHash       = crypto.Keccak256Hash([]byte("Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)"))
+	transferAuthTypeHash = crypto.Keccak256Hash([]byte("TransferWithAuthorization(address from,address to,uint256 value,uint256 validAfter,uint256 validBefore,bytes32 nonce)"))
+	abiStringArguments   = func() abi.Arguments {
+		typ, _ := abi.NewType("string", "", nil)
+		return abi.Arguments{{Type: typ}}
+	}()
+)
+
+// PermitSigner is implemented by chains that can sign gasless token approvals
+type PermitSigner interface {
+	// DetectPermitSupport reads which standards token implements and the
+	// EIP-712 domain and nonce owner signs with
+	DetectPermitSupport(ctx context.Context, token, owner string) (*TokenPermitSupport, error)
+
+	// SignPermit signs request with privateKey, the key of request.Owner
+	SignPermit(ctx context.Context, request PermitRequest, privateKey string) (*SignedPermit, error)
+}
+
+// TokenPermitSupport describes the gasless approvals a token accepts
+type TokenPermitSupport struct {
+	Token           string
+	Name            string
+	Version         string
+	Decimals        int64
+	ChainID         *big.Int
+	DomainSeparator common.Hash
+	EIP2612         bool
+	EIP3009         bool
+	Nonce           *big.Int // EIP-2612 nonce of the owner
+}
+
+// Supports reports whether the token implements permitType
+func (s *TokenPermitSupport) Supports(permitType string) bool {
+	switch permitType {
+	case PermitTypeEIP2612:
+		return s.EIP2612
+	case PermitTypeEIP3009:
+		return s.EIP3009
+	}
+	return false
+}
+
+// PermitRequest describes an approval to sign. For EIP-2612 Spender may pull
+// Amount from Owner until ValidBefore; for EIP-3009 anyone may move Amount
+// from Owner to Spender between ValidAfter and ValidBefore.
+type PermitRequest struct {
+	Type        string
+	Token       string
+	Owner       string
+	Spender     string
+	Amount      string // in whole tokens
+	ValidAfter  int64  // unix seconds, EIP-3009 only
+	ValidBefore int64  // unix seconds
+}
+
+// SignedPermit is a signed approval and the call that redeems it
+type SignedPermit struct {
+	Type        string
+	Token       string
+	Owner       string
+	Spender     string
+	Value       *big.Int // in the token's smallest unit
+	Nonce       string   // decimal for EIP-2612, bytes32 hex for EIP-3009
+	ValidAfter  int64
+	ValidBefore int64
+	Digest      common.Hash
+	Signature   string // 0x-prefixed r || s || v with v 27 or 28
+	V           uint8
+	R           common.Hash
+	S           common.Hash
+	// Calldata is the permit or transferWithAuthorization call that a relayer
+	// submits to Token, or that a router bundles ahead of its swap
+	Calldata string
+}
+
+// DetectPermitSupport reports the EIP-2612 and EIP-3009 support of token. Only
+// tokens whose EIP-712 domain can be reproduced are reported as supported,
+// since a permit signed in another domain is rejected on chain.
+func DetectPermitSupport(ctx context.Context, call EVMCallFunc, chainID *big.Int, token, owner string) (*TokenPermitSupport, error) {
+	if call == nil {
+		return nil, ErrSimulationUnavailable
+	}
+	if !common.IsHexAddress(token) {
+		return nil, fmt.Errorf("invalid token contract address: %s", token)
+	}
+	if !common.IsHexAddress(owner) {
+		return nil, fmt.Errorf("invalid owner address: %s", owner)
+	}
+	tokenAddress := common.HexToAddress(token)
+	ownerWord := hexutil.Encode(common.LeftPadBytes(common.HexToAddress(owner).Bytes(), 32))[2:]
+	support := &TokenPermitSupport{Token: tokenAddress.Hex(), ChainID: chainID}
+
+	output, err := call(ctx, EVMCall{To: support.Token, Data: "0x" + erc20DecimalsSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token decimals: %w", err)
+	}
+	decimals, err := hexutil.DecodeBig(trimHexZeros(output))
+	if err != nil || !decimals.IsInt64() || decimals.Int64() > 77 {
+		return nil, fmt.Errorf("invalid token decimals: %s", output)
+	}
+	support.Decimals = decimals.Int64()
+
+	// A revert or a short answer means the function does not exist
+	word := func(selector, args string) ([]byte, bool) {
+		output, err := call(ctx, EVMCall{To: support.Token, Data: "0x" + selector + args})
+		if err != nil {
+			return nil, false
+		}
+		data, err := hexutil.Decode(output)
+		if err != nil || len(data) < 32 {
+			return nil, false
+		}
+		return data, true
+	}
+
+	domainSeparator, hasDomain := word(permitDomainSeparatorSelector, "")
+	if nonce, ok := word(permitNoncesSelector, ownerWord); ok && hasDomain {
+		support.EIP2612 = true
+		support.Nonce = new(big.Int).SetBytes(nonce[:32])
+		// DAI-style permits sign a different struct under the same name
+		if typeHash, ok := word(permitTypehashSelector, ""); ok && common.BytesToHash(typeHash[:32]) != permitTypeHash {
+			support.EIP2612 = false
+		}
+	}
+	_, support.EIP3009 = word(permitAuthorizationStateSelector, ownerWord+strings.Repeat("0", 64))
+	if !support.EIP2612 && !support.EIP3009 {
+		return support, nil
+	}
+
+	name, ok := word(erc20NameSelector, "")
+	if !ok {
+		return nil, fmt.Errorf("failed to read the name of token %s for its EIP-712 domain", support.Token)
+	}
+	support.Name = decodeABIString(name)
+
+	versions := []string{"1", "2"}
+	if version, ok := word(eip712VersionSelector, ""); ok {
+		versions = []string{decodeABIString(version)}
+	}
+	for _, version := range versions {
+		separator := PermitDomainSeparator(support.Name, version, chainID, tokenAddress)
+		if !hasDomain || separator == common.BytesToHash(domainSeparator[:32]) {
+			support.Version = version
+			support.DomainSeparator = separator
+			return support, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot reproduce the EIP-712 domain of token %s; a permit signed for it would be rejected", support.Token)
+}
+
+// SignPermitRequest signs request in the domain of support with privateKeyHex
+func SignPermitRequest(support *TokenPermitSupport, request PermitRequest, privateKeyHex string) (*SignedPermit, error) {
+	if !support.Supports(request.Type) {
+		return nil, fmt.Errorf("%w: %s does not implement %s", ErrPermitNotSupported, support.Token, request.Type)
+	}
+	if !common.IsHexAddress(request.Owner) || !common.IsHexAddress(request.Spender) {
+		return nil, errors.New("owner and spender must be valid hex addresses")
+	}
+	value, err := scaleAmount(request.Amount, support.Decimals)
+	if err != nil {
+		return nil, err
+	}
+	if request.ValidBefore <= request.ValidAfter {
+		return nil, errors.New("the authorization expires before it becomes valid")
+	}
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	owner := common.HexToAddress(request.Owner)
+	if crypto.PubkeyToAddress(privateKey.PublicKey) != owner {
+		return nil, errors.New("private key does not belong to the owner")
+	}
+	spender := common.HexToAddress(request.Spender)
+
+	signed := &SignedPermit{
+		Type:        request.Type,
+		Token:       support.Token,
+		Owner:       owner.Hex(),
+		Spender:     spender.Hex(),
+		Value:       value,
+		ValidBefore: request.ValidBefore,
+	}
+	var structHash common.Hash
+	var nonce32 [32]byte
+	switch request.Type {
+	case PermitTypeEIP2612:
+		signed.Nonce = support.Nonce.String()
+		structHash = crypto.Keccak256Hash(permitTypeHash.Bytes(), abiWord(owner.Bytes()), abiWord(spender.Bytes()),
+			abiWord(value.Bytes()), abiWord(support.Nonce.Bytes()), abiWord(big.NewInt(request.ValidBefore).Bytes()))
+	case PermitTypeEIP3009:
+		// Authorization nonces are random; the contract only rejects reuse
+		if _, err := rand.Read(nonce32[:]); err != nil {
+			return nil, fmt.Errorf("failed to generate authorization nonce: %w", err)
+		}
+		signed.Nonce = hexutil.Encode(nonce32[:])
+		signed.ValidAfter = request.ValidAfter
+		structHash = crypto.Keccak256Hash(transferAuthTypeHash.Bytes(), abiWord(owner.Bytes()), abiWord(spender.Bytes()),
+			abiWord(value.Bytes()), abiWord(big.NewInt(request.ValidAfter).Bytes()), abiWord(big.NewInt(request.ValidBefore).Bytes()), nonce32[:])
+	}
+
+	signed.Digest = crypto.Keccak256Hash([]byte{0x19, 0x01}, support.DomainSeparator.Bytes(), structHash.Bytes())
+	signature, err := crypto.Sign(signed.Digest.Bytes(), privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign permit: %w", err)
+	}
+	signature[64] += 27
+	signed.Signature = hexutil.Encode(signature)
+	signed.R = common.BytesToHash(signature[:32])
+	signed.S = common.BytesToHash(signature[32:64])
+	signed.V = signature[64]
+
+	var calldata []byte
+	switch request.Type {
+	case PermitTypeEIP2612:
+		calldata = append(common.FromHex(permitSelector), abiWord(owner.Bytes())...)
+		calldata = append(calldata, abiWord(spender.Bytes())...)
+		calldata = append(calldata, abiWord(value.Bytes())...)
+		calldata = append(calldata, abiWord(big.NewInt(request.ValidBefore).Bytes())...)
+	case PermitTypeEIP3009:
+		calldata = append(common.FromHex(transferWithAuthorizationSelector), abiWord(owner.Bytes())...)
+		calldata = append(calldata, abiWord(spender.Bytes())...)
+		calldata = append(calldata, abiWord(value.Bytes())...)
+		calldata = append(calldata, abiWord(big.NewInt(request.ValidAfter).Bytes())...)
+		calldata = append(calldata, abiWord(big.NewInt(request.ValidBefore).Bytes())...)
+		calldata = append(calldata, nonce32[:]...)
+	}
+	calldata = append(calldata, abiWord([]byte{signed.V})...)
+	calldata = append(calldata, signed.R.Bytes()...)
+	calldata = append(calldata, signed.S.Bytes()...)
+	signed.Calldata = hexutil.Encode(calldata)
+	return signed, nil
+}
+
+// PermitDomainSeparator returns the EIP-712 domain separator of a token
+func PermitDomainSeparator(name, version string, chainID *big.Int, token common.Address) common.Hash {
+	return crypto.Keccak256Hash(eip712DomainTypeHash.Bytes(), crypto.Keccak256([]byte(name)), crypto.Keccak256([]byte(version)),
+		abiWord(chainID.Bytes()), abiWord(token.Bytes()))
+}
+
+// abiWord left-pads b to a 32-byte ABI word
+func abiWord(b []byte) []byte {
+	return common.LeftPadBytes(b, 32)
+}
+
+// decodeABIString decodes a string return value, accepting the bytes32 some
+// older tokens return instead
+func decodeABIString(data []byte) string {
+	if values, err := abiStringArguments.Unpack(data); err == nil && len(values) == 1 {
+		if text, ok := values[0].(string); ok {
+			return text
+		}
+	}
+	return strings.TrimRight(string(data[:32]), "\x00")
+}
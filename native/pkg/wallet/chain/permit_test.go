@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePermitToken answers eth_calls like a token with the given functions;
+// missing ones revert
+func fakePermitToken(t *testing.T, name, version string, functions map[string]string) EVMCallFunc {
+	t.Helper()
+	word := func(b []byte) string { return hexutil.Encode(common.LeftPadBytes(b, 32)) }
+	encodeString := func(text string) string {
+		data, err := abiStringArguments.Pack(text)
+		require.NoError(t, err)
+		return hexutil.Encode(data)
+	}
+	answers := map[string]string{
+		erc20DecimalsSelector:            word([]byte{6}),
+		erc20NameSelector:                encodeString(name),
+		permitNoncesSelector:             word([]byte{4}),
+		permitAuthorizationStateSelector: word(nil),
+		permitDomainSeparatorSelector:    PermitDomainSeparator(name, version, big.NewInt(1), common.HexToAddress(testToken)).Hex(),
+	}
+	if version != "" {
+		answers[eip712VersionSelector] = encodeString(version)
+	}
+	for selector, output := range functions {
+		if output == "" {
+			delete(answers, selector)
+		} else {
+			answers[selector] = output
+		}
+	}
+	return func(_ context.Context, call EVMCall) (string, error) {
+		output, ok := answers[strings.TrimPrefix(call.Data, "0x")[:8]]
+		if !ok {
+			return "", &rpcDataError{data: "0x"}
+		}
+		return output, nil
+	}
+}
+
+func TestDetectPermitSupport(t *testing.T) {
+	ctx := context.Background()
+	chainID := big.NewInt(1)
+
+	support, err := DetectPermitSupport(ctx, fakePermitToken(t, "USD Coin", "2", nil), chainID, testToken, testWallet)
+	require.NoError(t, err)
+	assert.True(t, support.EIP2612)
+	assert.True(t, support.EIP3009)
+	assert.Equal(t, "USD Coin", support.Name)
+	assert.Equal(t, "2", support.Version)
+	assert.Equal(t, int64(6), support.Decimals)
+	assert.Equal(t, int64(4), support.Nonce.Int64())
+
+	// Without version() the domain separator decides between the common versions
+	support, err = DetectPermitSupport(ctx, fakePermitToken(t, "Uniswap", "2", map[string]string{eip712VersionSelector: ""}), chainID, testToken, testWallet)
+	require.NoError(t, err)
+	assert.Equal(t, "2", support.Version)
+
+	// A DAI-style permit has its own struct and is not EIP-2612
+	daiTypeHash := crypto.Keccak256Hash([]byte("Permit(address holder,address spender,uint256 nonce,uint256 expiry,bool allowed)")).Hex()
+	support, err = DetectPermitSupport(ctx, fakePermitToken(t, "Dai Stablecoin", "1", map[string]string{
+		permitTypehashSelector:           daiTypeHash,
+		permitAuthorizationStateSelector: "",
+	}), chainID, testToken, testWallet)
+	require.NoError(t, err)
+	assert.False(t, support.EIP2612)
+	assert.False(t, support.EIP3009)
+
+	// A domain separator that cannot be reproduced would make every permit fail
+	_, err = DetectPermitSupport(ctx, fakePermitToken(t, "Odd", "1", map[string]string{
+		permitDomainSeparatorSelector: common.Hash{1}.Hex(),
+	}), chainID, testToken, testWallet)
+	assert.ErrorContains(t, err, "cannot reproduce the EIP-712 domain")
+
+	_, err = DetectPermitSupport(ctx, nil, chainID, testToken, testWallet)
+	assert.True(t, errors.Is(err, ErrSimulationUnavailable))
+}
+
+func TestSignPermitRequest(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	privateKey := hexutil.Encode(crypto.FromECDSA(key))
+	owner := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	support, err := DetectPermitSupport(context.Background(), fakePermitToken(t, "USD Coin", "2", nil), big.NewInt(1), testToken, owner)
+	require.NoError(t, err)
+	domain := apitypes.TypedDataDomain{Name: "USD Coin", Version: "2", ChainId: math.NewHexOrDecimal256(1), VerifyingContract: testToken}
+
+	t.Run("EIP-2612 permit", func(t *testing.T) {
+		signed, err := SignPermitRequest(support, PermitRequest{
+			Type: PermitTypeEIP2612, Token: testToken, Owner: owner, Spender: testContract, Amount: "12.5", ValidBefore: 1_900_000_000,
+		}, privateKey)
+		require.NoError(t, err)
+		assert.Equal(t, int64(12_500_000), signed.Value.Int64())
+		assert.Equal(t, "4", signed.Nonce)
+
+		hash, _, err := apitypes.TypedDataAndHash(apitypes.TypedData{
+			Types: apitypes.Types{
+				"EIP712Domain": {{Name: "name", Type: "string"}, {Name: "version", Type: "string"}, {Name: "chainId", Type: "uint256"}, {Name: "verifyingContract", Type: "address"}},
+				"Permit":       {{Name: "owner", Type: "address"}, {Name: "spender", Type: "address"}, {Name: "value", Type: "uint256"}, {Name: "nonce", Type: "uint256"}, {Name: "deadline", Type: "uint256"}},
+			},
+			PrimaryType: "Permit",
+			Domain:      domain,
+			Message: apitypes.TypedDataMessage{
+				"owner": owner, "spender": testContract, "value": "12500000", "nonce": "4", "deadline": "1900000000",
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, common.BytesToHash(hash), signed.Digest)
+		assertSignedBy(t, signed, owner)
+
+		calldata := common.FromHex(signed.Calldata)
+		require.Len(t, calldata, 4+7*32)
+		assert.Equal(t, crypto.Keccak256([]byte("permit(address,address,uint256,uint256,uint8,bytes32,bytes32)"))[:4], calldata[:4])
+		assert.Equal(t, signed.V, calldata[4+5*32-1])
+	})
+
+	t.Run("EIP-3009 transfer authorization", func(t *testing.T) {
+		signed, err := SignPermitRequest(support, PermitRequest{
+			Type: PermitTypeEIP3009, Token: testToken, Owner: owner, Spender: testContract, Amount: "3", ValidAfter: 0, ValidBefore: 1_900_000_000,
+		}, privateKey)
+		require.NoError(t, err)
+		require.Len(t, signed.Nonce, 66)
+
+		hash, _, err := apitypes.TypedDataAndHash(apitypes.TypedData{
+			Types: apitypes.Types{
+				"EIP712Domain":              {{Name: "name", Type: "string"}, {Name: "version", Type: "string"}, {Name: "chainId", Type: "uint256"}, {Name: "verifyingContract", Type: "address"}},
+				"TransferWithAuthorization": {{Name: "from", Type: "address"}, {Name: "to", Type: "address"}, {Name: "value", Type: "uint256"}, {Name: "validAfter", Type: "uint256"}, {Name: "validBefore", Type: "uint256"}, {Name: "nonce", Type: "bytes32"}},
+			},
+			PrimaryType: "TransferWithAuthorization",
+			Domain:      domain,
+			Message: apitypes.TypedDataMessage{
+				"from": owner, "to": testContract, "value": "3000000", "validAfter": "0", "validBefore": "1900000000", "nonce": signed.Nonce,
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, common.BytesToHash(hash), signed.Digest)
+		assertSignedBy(t, signed, owner)
+
+		calldata := common.FromHex(signed.Calldata)
+		require.Len(t, calldata, 4+9*32)
+		assert.Equal(t, crypto.Keccak256([]byte("transferWithAuthorization(address,address,uint256,uint256,uint256,bytes32,uint8,bytes32,bytes32)"))[:4], calldata[:4])
+	})
+
+	t.Run("rejections", func(t *testing.T) {
+		_, err := SignPermitRequest(&TokenPermitSupport{Token: testToken}, PermitRequest{Type: PermitTypeEIP2612, Owner: owner, Spender: testContract, Amount: "1", ValidBefore: 1}, privateKey)
+		assert.ErrorIs(t, err, ErrPermitNotSupported)
+
+		_, err = SignPermitRequest(support, PermitRequest{Type: PermitTypeEIP2612, Owner: testWallet, Spender: testContract, Amount: "1", ValidBefore: 1}, privateKey)
+		assert.ErrorContains(t, err, "does not belong to the owner")
+
+		_, err = SignPermitRequest(support, PermitRequest{Type: PermitTypeEIP3009, Owner: owner, Spender: testContract, Amount: "1", ValidAfter: 5, ValidBefore: 5}, privateKey)
+		assert.ErrorContains(t, err, "expires before it becomes valid")
+	})
+}
+
+func assertSignedBy(t *testing.T, signed *SignedPermit, owner string) {
+	t.Helper()
+	signature := common.FromHex(signed.Signature)
+	require.Len(t, signature, 65)
+	signature[64] -= 27
+	publicKey, err := crypto.SigToPub(signed.Digest.Bytes(), signature)
+	require.NoError(t, err)
+	assert.Equal(t, owner, crypto.PubkeyToAddress(*publicKey).Hex())
+}
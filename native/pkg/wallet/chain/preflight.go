@@ -0,0 +1,319 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+)
+
+var (
+	// ErrSimulationReverted is wrapped by a SimulationError: the send would
+	// fail on chain and was not broadcast
+	ErrSimulationReverted = errors.New("transaction would revert")
+	// ErrSimulationUnavailable is returned when a send cannot be simulated,
+	// e.g. no RPC endpoint is configured; the send goes ahead unchecked
+	ErrSimulationUnavailable = errors.New("send simulation unavailable")
+)
+
+// SendSimulator is implemented by chains that can dry-run a send against the
+// current chain state before it is signed and broadcast
+type SendSimulator interface {
+	// SimulateSend returns a *SimulationError when sending amount of token
+	// from from to to would revert
+	SimulateSend(ctx context.Context, from, to, amount, token string) error
+}
+
+// SimulationError reports a send that failed simulation
+type SimulationError struct {
+	Reason *RevertReason // decoded revert reason
+	Logs   []string      // program logs, on Solana
+}
+
+func (e *SimulationError) Error() string {
+	if e.Reason == nil || e.Reason.Message == "" {
+		return ErrSimulationReverted.Error()
+	}
+	return ErrSimulationReverted.Error() + ": " + e.Reason.Message
+}
+
+func (e *SimulationError) Unwrap() error {
+	return ErrSimulationReverted
+}
+
+type skipSimulationKey struct{}
+
+// WithoutSimulation returns a context whose sends are broadcast without the
+// preflight simulation
+func WithoutSimulation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipSimulationKey{}, true)
+}
+
+// SimulationSkipped reports whether ctx opts out of the preflight simulation
+func SimulationSkipped(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipSimulationKey{}).(bool)
+	return skip
+}
+
+// EVMCall is the message of an eth_call; Value and Data are 0x-prefixed hex
+type EVMCall struct {
+	From  string `json:"from,omitempty"`
+	To    string `json:"to"`
+	Value string `json:"value,omitempty"`
+	Data  string `json:"data,omitempty"`
+}
+
+// EVMCallFunc executes call against the latest block and returns its output,
+// as returned by eth_call. A revert is returned as an error carrying the
+// revert data, see RevertReasonFromError.
+type EVMCallFunc func(ctx context.Context, call EVMCall) (string, error)
+
+// evmCallError is a JSON-RPC error returned by eth_call, with its revert data
+type evmCallError struct {
+	code    int
+	message string
+	data    interface{}
+}
+
+func (e *evmCallError) Error() string {
+	return fmt.Sprintf("RPC error %d: %s", e.code, e.message)
+}
+
+func (e *evmCallError) ErrorData() interface{} {
+	return e.data
+}
+
+// NewEVMCallFunc returns an EVMCallFunc querying endpoints in order until one
+// answers. A revert is an answer: it is returned without trying the others.
+func NewEVMCallFunc(chainName string, endpoints []string) EVMCallFunc {
+	client := httpclient.New(chainName+"-rpc", httpclient.WithTimeout(15*time.Second))
+	return func(ctx context.Context, call EVMCall) (string, error) {
+		if len(endpoints) == 0 {
+			return "", fmt.Errorf("%w: no %s RPC endpoints configured", ErrSimulationUnavailable, chainName)
+		}
+		var lastErr error
+		for _, endpoint := range endpoints {
+			output, err := evmCall(ctx, client, endpoint, call)
+			var callErr *evmCallError
+			if err == nil || errors.As(err, &callErr) {
+				DefaultRPCHealth.RecordSuccess(chainName)
+				return output, err
+			}
+			lastErr = err
+		}
+		return "", fmt.Errorf("all RPC endpoints failed, last error: %w", lastErr)
+	}
+}
+
+func evmCall(ctx context.Context, client *http.Client, endpoint string, call EVMCall) (string, error) {
+	body, err := json.Marshal(RPCRequest{JSONRPC: "2.0", ID: 1, Method: "eth_call", Params: []any{call, "latest"}})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", &rpcHTTPError{StatusCode: resp.StatusCode}
+	}
+
+	var response struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Code    int         `json:"code"`
+			Message string      `json:"message"`
+			Data    interface{} `json:"data"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&response); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if response.Error != nil {
+		return "", &evmCallError{code: response.Error.Code, message: response.Error.Message, data: response.Error.Data}
+	}
+	return response.Result, nil
+}
+
+// ERC-20 selectors used to simulate token sends
+const (
+	erc20TransferSelector = "a9059cbb" // transfer(address,uint256)
+	erc20DecimalsSelector = "313ce567" // decimals()
+)
+
+// simulateEVMSend dry-runs a native or ERC-20 send with eth_call. amount is
+// already resolved against the native reserve.
+func simulateEVMSend(ctx context.Context, call EVMCallFunc, from, to, amount, token, nativeSymbol string) error {
+	if call == nil {
+		return ErrSimulationUnavailable
+	}
+	if !common.IsHexAddress(from) || !common.IsHexAddress(to) {
+		return errors.New("invalid address format")
+	}
+
+	token = strings.TrimSpace(token)
+	message := EVMCall{From: from, To: to}
+	if token == "" || strings.EqualFold(token, nativeSymbol) {
+		wei, err := scaleAmount(amount, 18)
+		if err != nil {
+			return err
+		}
+		message.Value = hexutil.EncodeBig(wei)
+	} else {
+		transfer, _, err := erc20TransferMessage(ctx, call, from, to, amount, token)
+		if err != nil {
+			return err
+		}
+		message = transfer
+	}
+
+	if _, err := call(ctx, message); err != nil {
+		if reason := RevertReasonFromError(err); reason != nil {
+			return &SimulationError{Reason: reason}
+		}
+		// Nodes that drop the revert data still answer with an RPC error
+		var callErr *evmCallError
+		if errors.As(err, &callErr) {
+			return &SimulationError{Reason: &RevertReason{Kind: RevertKindEmpty, Message: callErr.message}}
+		}
+		return err
+	}
+	return nil
+}
+
+// erc20TransferMessage builds the transfer(to, amount) call of token sent by
+// from, reading the token's decimals through call. It also returns the amount
+// in the token's smallest unit.
+func erc20TransferMessage(ctx context.Context, call EVMCallFunc, from, to, amount, token string) (EVMCall, *big.Int, error) {
+	if !common.IsHexAddress(token) {
+		return EVMCall{}, nil, fmt.Errorf("invalid token contract address: %s", token)
+	}
+	output, err := call(ctx, EVMCall{To: token, Data: "0x" + erc20DecimalsSelector})
+	if err != nil {
+		return EVMCall{}, nil, fmt.Errorf("failed to read token decimals: %w", err)
+	}
+	decimals, err := hexutil.DecodeBig(trimHexZeros(output))
+	if err != nil || !decimals.IsInt64() || decimals.Int64() > 77 {
+		return EVMCall{}, nil, fmt.Errorf("invalid token decimals: %s", output)
+	}
+	units, err := scaleAmount(amount, decimals.Int64())
+	if err != nil {
+		return EVMCall{}, nil, err
+	}
+	return EVMCall{
+		From: from,
+		To:   token,
+		Data: "0x" + erc20TransferSelector +
+			hexutil.Encode(common.LeftPadBytes(common.HexToAddress(to).Bytes(), 32))[2:] +
+			hexutil.Encode(common.LeftPadBytes(units.Bytes(), 32))[2:],
+	}, units, nil
+}
+
+// scaleAmount converts a decimal amount to an integer of decimals places
+func scaleAmount(amount string, decimals int64) (*big.Int, error) {
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok || value.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount: %s", amount)
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil)
+	value.Mul(value, new(big.Rat).SetInt(scale))
+	return new(big.Int).Quo(value.Num(), value.Denom()), nil
+}
+
+// trimHexZeros strips the leading zeros of an ABI-encoded word so it parses
+// as a hexutil big integer
+func trimHexZeros(word string) string {
+	digits := strings.TrimLeft(strings.TrimPrefix(word, "0x"), "0")
+	if digits == "" {
+		digits = "0"
+	}
+	return "0x" + digits
+}
+
+// SolanaSimulationResult is the simulateTransaction response
+type SolanaSimulationResult struct {
+	Context struct {
+		Slot uint64 `json:"slot"`
+	} `json:"context"`
+	Value struct {
+		Err           any      `json:"err"`
+		Logs          []string `json:"logs"`
+		UnitsConsumed uint64   `json:"unitsConsumed"`
+	} `json:"value"`
+}
+
+// SimulateSend dry-runs a native SOL transfer with simulateTransaction. SPL
+// token sends are not simulated.
+func (s *SolanaChain) SimulateSend(ctx context.Context, from, to, amount, token string) error {
+	if s.rpcManager == nil {
+		return ErrSimulationUnavailable
+	}
+	token = strings.TrimSpace(token)
+	if (token != "" && !strings.EqualFold(token, "SOL")) || IsMaxAmount(amount) {
+		return ErrSimulationUnavailable
+	}
+	payer, err := solana.PublicKeyFromBase58(from)
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	recipient, err := solana.PublicKeyFromBase58(to)
+	if err != nil {
+		return fmt.Errorf("invalid to address: %w", err)
+	}
+	lamports, err := parseSOLAmount(amount)
+	if err != nil {
+		return err
+	}
+
+	// The node replaces the blockhash and skips signature checks, so the
+	// transfer is simulated unsigned
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{system.NewTransferInstruction(lamports, payer, recipient).Build()},
+		solana.Hash{},
+		solana.TransactionPayer(payer),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create transfer: %w", err)
+	}
+	tx.Signatures = make([]solana.Signature, tx.Message.Header.NumRequiredSignatures)
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode transfer: %w", err)
+	}
+
+	result, err := s.rpcManager.SimulateTransaction(ctx, base64.StdEncoding.EncodeToString(raw), s.commitment(ctx))
+	if err != nil {
+		return err
+	}
+	if result.Value.Err == nil {
+		return nil
+	}
+	message := fmt.Sprintf("%v", result.Value.Err)
+	if encoded, err := json.Marshal(result.Value.Err); err == nil {
+		message = string(encoded)
+	}
+	return &SimulationError{
+		Reason: &RevertReason{Kind: RevertKindError, Message: message},
+		Logs:   result.Value.Logs,
+	}
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestEVMCallFuncReturnsRevertData(t *testing.T) {
+	revertData := hexutil.Encode(encodeRevert(t, "Error(string)", []string{"string"}, "insufficient balance"))
+	calls := 0
+	server := newRPCTestServer(t, map[string]rpcMethod{
+		"eth_call": func(rpcCall) (string, error) {
+			calls++
+			return "", &rpcTestError{Code: 3, Message: "execution reverted", Data: revertData}
+		},
+	})
+
+	// The revert is the answer: the second endpoint is not asked
+	call := NewEVMCallFunc("ethereum", []string{server.URL, server.URL})
+	_, err := call(context.Background(), EVMCall{From: testWallet, To: testContract, Value: "0x1"})
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+	reason := RevertReasonFromError(err)
+	require.NotNil(t, reason)
+	assert.Equal(t, "insufficient balance", reason.Message)
+
+	_, err = NewEVMCallFunc("ethereum", nil)(context.Background(), EVMCall{To: testContract})
+	assert.ErrorIs(t, err, ErrSimulationUnavailable)
+}
+
+func TestETHSimulateSend(t *testing.T) {
+	var sent []EVMCall
+	revert := hexutil.Encode(encodeRevert(t, "Error(string)", []string{"string"}, "ERC20: transfer amount exceeds balance"))
+	chain := NewETHChain(nil, zap.NewNop())
+	chain.SetPreflight(func(_ context.Context, call EVMCall) (string, error) {
+		sent = append(sent, call)
+		switch {
+		case call.Data == "0x"+erc20DecimalsSelector:
+			return "0x0000000000000000000000000000000000000000000000000000000000000006", nil
+		case strings.HasPrefix(call.Data, "0x"+erc20TransferSelector):
+			return "", &rpcDataError{data: revert}
+		}
+		return "0x", nil
+	})
+
+	t.Run("native send", func(t *testing.T) {
+		sent = nil
+		require.NoError(t, chain.SimulateSend(context.Background(), testWallet, testContract, "0.5", "ETH"))
+		require.Len(t, sent, 1)
+		assert.Equal(t, "0x6f05b59d3b20000", sent[0].Value)
+		assert.Equal(t, testContract, sent[0].To)
+	})
+
+	t.Run("token send that would revert", func(t *testing.T) {
+		sent = nil
+		err := chain.SimulateSend(context.Background(), testWallet, testContract, "1.5", testToken)
+		require.ErrorIs(t, err, ErrSimulationReverted)
+		var simErr *SimulationError
+		require.True(t, errors.As(err, &simErr))
+		assert.Equal(t, RevertKindError, simErr.Reason.Kind)
+		assert.Equal(t, "ERC20: transfer amount exceeds balance", simErr.Reason.Message)
+
+		require.Len(t, sent, 2)
+		assert.Equal(t, testToken, sent[1].To)
+		// transfer(testContract, 1.5 * 10^6)
+		assert.True(t, strings.HasSuffix(sent[1].Data, "16e360"), sent[1].Data)
+		assert.Contains(t, sent[1].Data, strings.ToLower(testContract[2:]))
+	})
+
+	t.Run("no preflight configured", func(t *testing.T) {
+		err := NewETHChain(nil, zap.NewNop()).SimulateSend(context.Background(), testWallet, testContract, "0.5", "")
+		assert.ErrorIs(t, err, ErrSimulationUnavailable)
+	})
+}
+
+func TestSolanaSimulateSend(t *testing.T) {
+	t.Setenv("RUN_MODE", "")
+	simulation := `{"err":null,"logs":[]}`
+	chain := newSolanaTestChain(t, map[string]rpcMethod{
+		"simulateTransaction": func(rpcCall) (string, error) {
+			return `{"context":{"slot":1},"value":` + simulation + `}`, nil
+		},
+	})
+
+	from := solana.NewWallet().PublicKey().String()
+	to := solana.NewWallet().PublicKey().String()
+	require.NoError(t, chain.SimulateSend(context.Background(), from, to, "0.1", "SOL"))
+
+	simulation = `{"err":{"InstructionError":[0,{"Custom":1}]},"logs":["Program 11111111111111111111111111111111 failed: custom program error: 0x1"]}`
+	err := chain.SimulateSend(context.Background(), from, to, "0.1", "")
+	require.ErrorIs(t, err, ErrSimulationReverted)
+	var simErr *SimulationError
+	require.True(t, errors.As(err, &simErr))
+	assert.Contains(t, simErr.Reason.Message, "InstructionError")
+	assert.Len(t, simErr.Logs, 1)
+
+	// SPL token sends are not simulated
+	assert.ErrorIs(t, chain.SimulateSend(context.Background(), from, to, "1", "DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263"), ErrSimulationUnavailable)
+}
@@ -0,0 +1,309 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+)
+
+// Types of EVM transaction reported by DecodeEVMRawTransaction
+const (
+	EVMTxTypeLegacy     = "legacy"
+	EVMTxTypeAccessList = "access_list"
+	EVMTxTypeDynamicFee = "dynamic_fee"
+	EVMTxTypeBlob       = "blob"
+)
+
+// DecodedEVMTransaction is a raw EVM transaction with its fields decoded
+type DecodedEVMTransaction struct {
+	Type    string
+	ChainID *big.Int // nil for a pre-EIP-155 legacy transaction
+	Hash    string
+	Nonce   uint64
+	To      string // empty for a contract deployment
+	Value   *big.Int
+	Gas     uint64
+	// GasPrice is set for legacy and access list transactions, GasTipCap and
+	// GasFeeCap for dynamic fee and blob transactions; all in wei
+	GasPrice  *big.Int
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+	Data      []byte
+	Signed    bool
+	From      string // recovered from the signature, empty when unsigned
+}
+
+// DecodeEVMRawTransaction decodes a hex encoded transaction as produced by
+// eth_signTransaction: legacy RLP or an EIP-2718 typed envelope, signed or not.
+func DecodeEVMRawTransaction(raw string) (*DecodedEVMTransaction, error) {
+	encoded, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(raw), "0x"), "0X"))
+	if err != nil {
+		return nil, fmt.Errorf("raw transaction is not hex encoded: %w", err)
+	}
+	if len(encoded) == 0 {
+		return nil, errors.New("raw transaction is empty")
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(encoded); err != nil {
+		return nil, fmt.Errorf("not an RLP encoded transaction: %w", err)
+	}
+
+	v, r, s := tx.RawSignatureValues()
+	decoded := &DecodedEVMTransaction{
+		Nonce:  tx.Nonce(),
+		Value:  tx.Value(),
+		Gas:    tx.Gas(),
+		Data:   tx.Data(),
+		Signed: r.Sign() != 0 && s.Sign() != 0,
+		Hash:   tx.Hash().Hex(),
+	}
+	if to := tx.To(); to != nil {
+		decoded.To = to.Hex()
+	}
+
+	switch tx.Type() {
+	case types.LegacyTxType:
+		decoded.Type = EVMTxTypeLegacy
+		decoded.GasPrice = tx.GasPrice()
+		switch {
+		case decoded.Signed && tx.Protected():
+			decoded.ChainID = tx.ChainId()
+		case !decoded.Signed && v.Sign() != 0:
+			// An unsigned EIP-155 payload carries the chain ID in v
+			decoded.ChainID = new(big.Int).Set(v)
+		}
+	case types.AccessListTxType:
+		decoded.Type = EVMTxTypeAccessList
+		decoded.GasPrice = tx.GasPrice()
+		decoded.ChainID = tx.ChainId()
+	case types.DynamicFeeTxType:
+		decoded.Type = EVMTxTypeDynamicFee
+		decoded.GasTipCap = tx.GasTipCap()
+		decoded.GasFeeCap = tx.GasFeeCap()
+		decoded.ChainID = tx.ChainId()
+	case types.BlobTxType:
+		decoded.Type = EVMTxTypeBlob
+		decoded.GasTipCap = tx.GasTipCap()
+		decoded.GasFeeCap = tx.GasFeeCap()
+		decoded.ChainID = tx.ChainId()
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d", tx.Type())
+	}
+
+	if decoded.Signed {
+		from, err := types.Sender(types.LatestSignerForChainID(decoded.ChainID), tx)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature: %w", err)
+		}
+		decoded.From = from.Hex()
+	}
+	return decoded, nil
+}
+
+// DecodedSolanaTransaction is a raw Solana transaction with its message decoded
+type DecodedSolanaTransaction struct {
+	Version         string // legacy or v0
+	RecentBlockhash string // a durable nonce value for nonce transactions
+	FeePayer        string
+	// Signatures holds one base58 signature per required signer, empty where
+	// the signer has not signed yet
+	Signatures []string
+	Signed     bool // every required signature is present and valid
+	Accounts   []SolanaAccountMeta
+	// AddressTableLookups counts the lookup tables of a v0 message; accounts
+	// loaded from them cannot be resolved offline
+	AddressTableLookups int
+	Instructions        []DecodedSolanaInstruction
+}
+
+// SolanaAccountMeta is an account listed in a transaction message
+type SolanaAccountMeta struct {
+	Address  string
+	Signer   bool
+	Writable bool
+}
+
+// DecodedSolanaInstruction is an instruction of a Solana transaction. Type and
+// Summary are only set for instructions of well-known programs.
+type DecodedSolanaInstruction struct {
+	ProgramID string
+	Program   string
+	Type      string
+	Accounts  []string
+	Data      string // hex encoded
+	Summary   string
+}
+
+// solanaProgramNames names the programs whose instructions are decoded or
+// commonly seen in wallet transactions
+var solanaProgramNames = map[solana.PublicKey]string{
+	solana.SystemProgramID:                    "System",
+	solana.ComputeBudget:                      "Compute Budget",
+	solana.TokenProgramID:                     "SPL Token",
+	solana.Token2022ProgramID:                 "SPL Token-2022",
+	solana.SPLAssociatedTokenAccountProgramID: "Associated Token Account",
+	solana.MemoProgramID:                      "Memo",
+	solana.StakeProgramID:                     "Stake",
+	solana.VoteProgramID:                      "Vote",
+	solana.AddressLookupTableProgramID:        "Address Lookup Table",
+}
+
+// DecodeSolanaRawTransaction decodes a base64 encoded transaction, legacy or
+// v0, signed or not.
+func DecodeSolanaRawTransaction(raw string) (*DecodedSolanaTransaction, error) {
+	encoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("raw transaction is not base64 encoded: %w", err)
+	}
+	tx, err := solana.TransactionFromBytes(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("not a Solana transaction: %w", err)
+	}
+	message := tx.Message
+
+	decoded := &DecodedSolanaTransaction{
+		Version:             "legacy",
+		RecentBlockhash:     message.RecentBlockhash.String(),
+		AddressTableLookups: len(message.AddressTableLookups),
+	}
+	if message.IsVersioned() {
+		decoded.Version = "v0"
+	}
+	if len(message.AccountKeys) > 0 {
+		decoded.FeePayer = message.AccountKeys[0].String()
+	}
+
+	// Unsigned transactions may omit the signatures or carry zeroed ones
+	signed := message.Header.NumRequiredSignatures > 0
+	for i := 0; i < int(message.Header.NumRequiredSignatures); i++ {
+		if i >= len(tx.Signatures) || tx.Signatures[i].IsZero() {
+			decoded.Signatures = append(decoded.Signatures, "")
+			signed = false
+			continue
+		}
+		decoded.Signatures = append(decoded.Signatures, tx.Signatures[i].String())
+	}
+	decoded.Signed = signed && tx.VerifySignatures() == nil
+
+	for _, key := range message.AccountKeys {
+		decoded.Accounts = append(decoded.Accounts, SolanaAccountMeta{
+			Address:  key.String(),
+			Signer:   message.IsSigner(key),
+			Writable: message.IsWritableStatic(key),
+		})
+	}
+
+	for _, compiled := range message.Instructions {
+		instruction := DecodedSolanaInstruction{
+			ProgramID: solanaAccountAt(message, compiled.ProgramIDIndex),
+			Data:      hex.EncodeToString(compiled.Data),
+		}
+		for _, index := range compiled.Accounts {
+			instruction.Accounts = append(instruction.Accounts, solanaAccountAt(message, index))
+		}
+		if programID, err := solana.PublicKeyFromBase58(instruction.ProgramID); err == nil {
+			instruction.Program = solanaProgramNames[programID]
+			describeSolanaInstruction(programID, &instruction, compiled.Data)
+		}
+		decoded.Instructions = append(decoded.Instructions, instruction)
+	}
+	return decoded, nil
+}
+
+// solanaAccountAt resolves an account index of message, naming accounts that
+// are loaded from an address lookup table by their index
+func solanaAccountAt(message solana.Message, index uint16) string {
+	if int(index) < len(message.AccountKeys) {
+		return message.AccountKeys[index].String()
+	}
+	return fmt.Sprintf("lookup table account #%d", index)
+}
+
+// describeSolanaInstruction sets the type and summary of instructions of the
+// System, Compute Budget, SPL Token and Memo programs
+func describeSolanaInstruction(programID solana.PublicKey, instruction *DecodedSolanaInstruction, data []byte) {
+	account := func(i int) string {
+		if i < len(instruction.Accounts) {
+			return instruction.Accounts[i]
+		}
+		return "?"
+	}
+
+	switch programID {
+	case solana.SystemProgramID:
+		if len(data) < 4 {
+			return
+		}
+		id := binary.LittleEndian.Uint32(data)
+		instruction.Type = system.InstructionIDToName(id)
+		if id == system.Instruction_Transfer && len(data) >= 12 {
+			lamports := binary.LittleEndian.Uint64(data[4:])
+			instruction.Summary = fmt.Sprintf("Transfer %s SOL from %s to %s", formatLamports(lamports), account(0), account(1))
+		} else if id == system.Instruction_AdvanceNonceAccount {
+			instruction.Summary = "Advance durable nonce account " + account(0)
+		}
+	case solana.ComputeBudget:
+		if len(data) == 0 {
+			return
+		}
+		switch {
+		case data[0] == 1 && len(data) >= 5:
+			instruction.Type = "RequestHeapFrame"
+			instruction.Summary = fmt.Sprintf("Request a %d byte heap", binary.LittleEndian.Uint32(data[1:]))
+		case data[0] == 2 && len(data) >= 5:
+			instruction.Type = "SetComputeUnitLimit"
+			instruction.Summary = fmt.Sprintf("Limit compute to %d units", binary.LittleEndian.Uint32(data[1:]))
+		case data[0] == 3 && len(data) >= 9:
+			instruction.Type = "SetComputeUnitPrice"
+			instruction.Summary = fmt.Sprintf("Pay %d microlamports per compute unit", binary.LittleEndian.Uint64(data[1:]))
+		}
+	case solana.TokenProgramID, solana.Token2022ProgramID:
+		if len(data) == 0 {
+			return
+		}
+		switch {
+		case data[0] == 3 && len(data) >= 9:
+			instruction.Type = "Transfer"
+			instruction.Summary = fmt.Sprintf("Transfer %d base units from token account %s to %s", binary.LittleEndian.Uint64(data[1:]), account(0), account(1))
+		case data[0] == 4 && len(data) >= 9:
+			instruction.Type = "Approve"
+			instruction.Summary = fmt.Sprintf("Allow %s to spend %d base units from token account %s", account(1), binary.LittleEndian.Uint64(data[1:]), account(0))
+		case data[0] == 5:
+			instruction.Type = "Revoke"
+			instruction.Summary = "Revoke the delegate of token account " + account(0)
+		case data[0] == 9:
+			instruction.Type = "CloseAccount"
+			instruction.Summary = fmt.Sprintf("Close token account %s, sending its rent to %s", account(0), account(1))
+		case data[0] == 12 && len(data) >= 10:
+			instruction.Type = "TransferChecked"
+			amount := new(big.Rat).SetFrac(new(big.Int).SetUint64(binary.LittleEndian.Uint64(data[1:])), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(data[9])), nil))
+			instruction.Summary = fmt.Sprintf("Transfer %s of mint %s from token account %s to %s", formatDecimal(amount), account(1), account(0), account(2))
+		}
+	case solana.SPLAssociatedTokenAccountProgramID:
+		instruction.Type = "Create"
+		if len(data) > 0 && data[0] == 1 {
+			instruction.Type = "CreateIdempotent"
+		}
+		instruction.Summary = fmt.Sprintf("Create token account %s of mint %s for %s", account(1), account(3), account(2))
+	case solana.MemoProgramID:
+		instruction.Type = "Memo"
+		if utf8.Valid(data) {
+			instruction.Summary = "Memo: " + string(data)
+		}
+	}
+}
+
+// formatLamports renders lamports in SOL without trailing zeros
+func formatLamports(lamports uint64) string {
+	return formatDecimal(new(big.Rat).SetFrac(new(big.Int).SetUint64(lamports), big.NewInt(1_000_000_000)))
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeEVMRawTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	to := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	data := common.FromHex("0xa9059cbb000000000000000000000000222222222222222222222222222222222222222200000000000000000000000000000000000000000000000000000000000f4240")
+
+	signed, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     7,
+		GasTipCap: big.NewInt(2_000_000_000),
+		GasFeeCap: big.NewInt(40_000_000_000),
+		Gas:       65000,
+		To:        &to,
+		Data:      data,
+	})
+	require.NoError(t, err)
+	raw, err := signed.MarshalBinary()
+	require.NoError(t, err)
+
+	decoded, err := DecodeEVMRawTransaction("0x" + hex.EncodeToString(raw))
+	require.NoError(t, err)
+	assert.Equal(t, EVMTxTypeDynamicFee, decoded.Type)
+	assert.Equal(t, int64(1), decoded.ChainID.Int64())
+	assert.Equal(t, uint64(7), decoded.Nonce)
+	assert.Equal(t, to.Hex(), decoded.To)
+	assert.Equal(t, uint64(65000), decoded.Gas)
+	assert.Equal(t, int64(40_000_000_000), decoded.GasFeeCap.Int64())
+	assert.Equal(t, data, decoded.Data)
+	assert.True(t, decoded.Signed)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey).Hex(), decoded.From)
+	assert.Equal(t, signed.Hash().Hex(), decoded.Hash)
+
+	// An unsigned EIP-155 legacy payload carries its chain ID in v
+	unsigned := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(5_000_000_000), Gas: 21000, To: &to, Value: big.NewInt(1e16), V: big.NewInt(56), R: new(big.Int), S: new(big.Int)})
+	raw, err = unsigned.MarshalBinary()
+	require.NoError(t, err)
+	decoded, err = DecodeEVMRawTransaction(hex.EncodeToString(raw))
+	require.NoError(t, err)
+	assert.Equal(t, EVMTxTypeLegacy, decoded.Type)
+	assert.False(t, decoded.Signed)
+	assert.Empty(t, decoded.From)
+	assert.Equal(t, int64(56), decoded.ChainID.Int64())
+	assert.Equal(t, int64(1e16), decoded.Value.Int64())
+
+	_, err = DecodeEVMRawTransaction("0xzz")
+	assert.ErrorContains(t, err, "not hex encoded")
+	_, err = DecodeEVMRawTransaction("0x0102")
+	assert.Error(t, err)
+}
+
+func TestDecodeSolanaRawTransaction(t *testing.T) {
+	owner := solana.NewWallet().PrivateKey
+	recipient := solana.NewWallet().PublicKey()
+	blockhash := solana.MustHashFromBase58("4uQeVj5tqViQh7yWWGStvkEG1Zmhx6uasJtWCJziofM")
+
+	computeLimit := solana.NewInstruction(solana.ComputeBudget, solana.AccountMetaSlice{}, []byte{2, 0x40, 0x0d, 0x03, 0x00})
+	memo := solana.NewInstruction(solana.MemoProgramID, solana.AccountMetaSlice{}, []byte("invoice 42"))
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{
+			computeLimit,
+			system.NewTransferInstruction(1_500_000_000, owner.PublicKey(), recipient).Build(),
+			memo,
+		},
+		blockhash,
+		solana.TransactionPayer(owner.PublicKey()),
+	)
+	require.NoError(t, err)
+
+	encoded, err := tx.ToBase64()
+	require.NoError(t, err)
+	decoded, err := DecodeSolanaRawTransaction(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", decoded.Version)
+	assert.Equal(t, owner.PublicKey().String(), decoded.FeePayer)
+	assert.Equal(t, blockhash.String(), decoded.RecentBlockhash)
+	assert.Equal(t, []string{""}, decoded.Signatures)
+	assert.False(t, decoded.Signed)
+	require.NotEmpty(t, decoded.Accounts)
+	assert.True(t, decoded.Accounts[0].Signer)
+	assert.True(t, decoded.Accounts[0].Writable)
+
+	require.Len(t, decoded.Instructions, 3)
+	assert.Equal(t, "Compute Budget", decoded.Instructions[0].Program)
+	assert.Equal(t, "Limit compute to 200000 units", decoded.Instructions[0].Summary)
+	assert.Equal(t, "Transfer", decoded.Instructions[1].Type)
+	assert.Equal(t, "Transfer 1.5 SOL from "+owner.PublicKey().String()+" to "+recipient.String(), decoded.Instructions[1].Summary)
+	assert.Equal(t, "Memo: invoice 42", decoded.Instructions[2].Summary)
+
+	_, err = tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(owner.PublicKey()) {
+			return &owner
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	encoded, err = tx.ToBase64()
+	require.NoError(t, err)
+	decoded, err = DecodeSolanaRawTransaction(encoded)
+	require.NoError(t, err)
+	assert.True(t, decoded.Signed)
+	assert.Equal(t, tx.Signatures[0].String(), decoded.Signatures[0])
+
+	_, err = DecodeSolanaRawTransaction("not base64!")
+	assert.ErrorContains(t, err, "not base64 encoded")
+}
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
+	"github.com/ethereum/go-ethereum/common"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// Recipient kinds reported by ClassifyRecipient
+const (
+	RecipientEOA          = "eoa"           // externally owned account or plain wallet
+	RecipientContract     = "contract"      // EVM contract, or a Solana account owned by a program
+	RecipientTokenAccount = "token_account" // Solana account owned by an SPL token program
+	RecipientProgram      = "program"       // executable Solana program
+)
+
+// eip7702DelegationPrefix marks the code of an EOA that delegates to a contract
+// under EIP-7702; such accounts are still controlled by their key
+const eip7702DelegationPrefix = "0xef0100"
+
+// RecipientClassification describes the recipient of a transfer. Warning is
+// set when the transfer is likely to lose funds and needs explicit confirmation.
+type RecipientClassification struct {
+	Address   string `json:"address"`
+	Kind      string `json:"kind"`
+	KnownSafe bool   `json:"known_safe,omitempty"` // contract listed as able to receive tokens
+	Label     string `json:"label,omitempty"`      // address book label
+	Category  string `json:"category,omitempty"`   // address book category
+	Warning   string `json:"warning,omitempty"`
+}
+
+// RecipientClassifier is implemented by chains that can tell wallets from
+// contracts before sending token to address
+type RecipientClassifier interface {
+	ClassifyRecipient(ctx context.Context, address, token string) (*RecipientClassification, error)
+}
+
+// EVMCodeFunc returns the bytecode deployed at address, as returned by eth_getCode
+type EVMCodeFunc func(ctx context.Context, address string) (string, error)
+
+// NewEVMCodeFunc returns an EVMCodeFunc querying endpoints in order until one
+// answers. chainName is recorded in DefaultRPCHealth on success.
+func NewEVMCodeFunc(chainName string, endpoints []string) EVMCodeFunc {
+	client := httpclient.New(chainName+"-rpc", httpclient.WithTimeout(15*time.Second))
+	return func(ctx context.Context, address string) (string, error) {
+		if len(endpoints) == 0 {
+			return "", fmt.Errorf("no %s RPC endpoints configured", chainName)
+		}
+		var lastErr error
+		for _, endpoint := range endpoints {
+			code, err := evmGetCode(ctx, client, endpoint, address)
+			if err == nil {
+				DefaultRPCHealth.RecordSuccess(chainName)
+				return code, nil
+			}
+			lastErr = err
+		}
+		return "", fmt.Errorf("all RPC endpoints failed, last error: %w", lastErr)
+	}
+}
+
+func evmGetCode(ctx context.Context, client *http.Client, endpoint, address string) (string, error) {
+	body, err := json.Marshal(RPCRequest{JSONRPC: "2.0", ID: 1, Method: "eth_getCode", Params: []any{address, "latest"}})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", &rpcHTTPError{StatusCode: resp.StatusCode}
+	}
+
+	var response struct {
+		Result string    `json:"result"`
+		Error  *RPCError `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&response); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if response.Error != nil {
+		return "", fmt.Errorf("RPC error %d: %s", response.Error.Code, response.Error.Message)
+	}
+	return response.Result, nil
+}
+
+// evmRecipientCheck classifies EVM recipients through eth_getCode. Token
+// transfers to contracts not in safeContracts carry a warning.
+type evmRecipientCheck struct {
+	getCode       EVMCodeFunc
+	safeContracts map[string]bool
+}
+
+func newEVMRecipientCheck(getCode EVMCodeFunc, safeContracts []string) *evmRecipientCheck {
+	safe := make(map[string]bool, len(safeContracts))
+	for _, address := range safeContracts {
+		safe[strings.ToLower(strings.TrimSpace(address))] = true
+	}
+	return &evmRecipientCheck{getCode: getCode, safeContracts: safe}
+}
+
+func (c *evmRecipientCheck) classify(ctx context.Context, address, token, nativeSymbol string) (*RecipientClassification, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid recipient address: %s", address)
+	}
+	if c == nil || c.getCode == nil {
+		return nil, errors.New("recipient check requires an RPC endpoint")
+	}
+	code, err := c.getCode(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipient code: %w", err)
+	}
+
+	classification := &RecipientClassification{Address: address, Kind: RecipientEOA}
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" || code == "0x" || strings.HasPrefix(code, eip7702DelegationPrefix) {
+		return classification, nil
+	}
+	classification.Kind = RecipientContract
+	classification.KnownSafe = c.safeContracts[strings.ToLower(address)]
+
+	token = strings.TrimSpace(token)
+	isToken := token != "" && !strings.EqualFold(token, nativeSymbol)
+	switch {
+	case !isToken || classification.KnownSafe:
+	case strings.EqualFold(token, address):
+		classification.Warning = "the recipient is the token's own contract; tokens sent to it are usually lost"
+	default:
+		classification.Warning = "the recipient is a contract that may not be able to move received tokens; exchange deposit addresses may also need a memo"
+	}
+	return classification, nil
+}
+
+// ClassifyRecipient implements RecipientClassifier for Solana. Sending SOL to
+// an account owned by a token program, or to a program, strands the lamports.
+func (s *SolanaChain) ClassifyRecipient(ctx context.Context, address, token string) (*RecipientClassification, error) {
+	if _, err := solana.PublicKeyFromBase58(address); err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
+	if s.rpcManager == nil {
+		return nil, errors.New("solana RPC manager not initialized")
+	}
+	info, err := s.rpcManager.GetAccountInfo(ctx, address, s.commitment(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipient account: %w", err)
+	}
+
+	classification := &RecipientClassification{Address: address, Kind: RecipientEOA}
+	if info.Value == nil {
+		return classification, nil
+	}
+	switch {
+	case info.Value.Executable:
+		classification.Kind = RecipientProgram
+	case info.Value.Owner == SPLTokenProgramID || info.Value.Owner == SPLToken2022ProgramID:
+		classification.Kind = RecipientTokenAccount
+	case info.Value.Owner != solana.SystemProgramID.String():
+		classification.Kind = RecipientContract
+	}
+
+	sendsSOL := token == "" || strings.EqualFold(strings.TrimSpace(token), "SOL")
+	if sendsSOL && classification.Kind == RecipientTokenAccount {
+		classification.Warning = "the recipient is a token account owned by the SPL token program; SOL sent to it cannot be spent by the owner's wallet"
+	} else if sendsSOL && classification.Kind == RecipientProgram {
+		classification.Warning = "the recipient is a program; SOL sent to it is usually lost"
+	}
+	return classification, nil
+}
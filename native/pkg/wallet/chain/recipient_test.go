@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	testWallet   = "0x2F62f2B4c5fcd7570a709DeC05D68EA19c82A9ec"
+	testContract = "0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D"
+	testToken    = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+)
+
+func TestEVMCodeFuncQueriesGetCode(t *testing.T) {
+	server := newRPCTestServer(t, map[string]rpcMethod{
+		"eth_getCode": func(call rpcCall) (string, error) {
+			assert.Equal(t, []any{testContract, "latest"}, call.params())
+			return `"0x6080"`, nil
+		},
+	})
+	failing := newRPCTestServer(t, map[string]rpcMethod{
+		"eth_getCode": func(rpcCall) (string, error) {
+			return "", errors.New("boom")
+		},
+	})
+
+	code, err := NewEVMCodeFunc("ethereum", []string{failing.URL, server.URL})(context.Background(), testContract)
+	require.NoError(t, err)
+	assert.Equal(t, "0x6080", code)
+
+	_, err = NewEVMCodeFunc("ethereum", nil)(context.Background(), testContract)
+	assert.Error(t, err)
+}
+
+func TestEVMClassifyRecipient(t *testing.T) {
+	codes := map[string]string{
+		testWallet:   "0x",
+		testContract: "0x6080604052",
+		testToken:    "0x6080604052",
+	}
+	getCode := func(_ context.Context, address string) (string, error) {
+		return codes[address], nil
+	}
+	chain := NewETHChain(nil, zap.NewNop())
+	chain.SetRecipientCheck(getCode, nil)
+
+	t.Run("wallet", func(t *testing.T) {
+		recipient, err := chain.ClassifyRecipient(context.Background(), testWallet, testToken)
+		require.NoError(t, err)
+		assert.Equal(t, RecipientEOA, recipient.Kind)
+		assert.Empty(t, recipient.Warning)
+	})
+
+	t.Run("native transfer to contract", func(t *testing.T) {
+		recipient, err := chain.ClassifyRecipient(context.Background(), testContract, "ETH")
+		require.NoError(t, err)
+		assert.Equal(t, RecipientContract, recipient.Kind)
+		assert.Empty(t, recipient.Warning)
+	})
+
+	t.Run("token transfer to contract", func(t *testing.T) {
+		recipient, err := chain.ClassifyRecipient(context.Background(), testContract, testToken)
+		require.NoError(t, err)
+		assert.Equal(t, RecipientContract, recipient.Kind)
+		assert.False(t, recipient.KnownSafe)
+		assert.NotEmpty(t, recipient.Warning)
+	})
+
+	t.Run("token sent to its own contract", func(t *testing.T) {
+		recipient, err := chain.ClassifyRecipient(context.Background(), testToken, testToken)
+		require.NoError(t, err)
+		assert.Contains(t, recipient.Warning, "token's own contract")
+	})
+
+	t.Run("known-safe contract", func(t *testing.T) {
+		safe := NewBSCChain(nil, zap.NewNop())
+		safe.SetRecipientCheck(getCode, []string{" 0x7A250D5630B4CF539739DF2C5DACB4C659F2488D "})
+		recipient, err := safe.ClassifyRecipient(context.Background(), testContract, testToken)
+		require.NoError(t, err)
+		assert.True(t, recipient.KnownSafe)
+		assert.Empty(t, recipient.Warning)
+	})
+
+	t.Run("EIP-7702 delegated wallet", func(t *testing.T) {
+		codes[testWallet] = "0xef0100" + testContract[2:]
+		defer func() { codes[testWallet] = "0x" }()
+		recipient, err := chain.ClassifyRecipient(context.Background(), testWallet, testToken)
+		require.NoError(t, err)
+		assert.Equal(t, RecipientEOA, recipient.Kind)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		_, err := chain.ClassifyRecipient(context.Background(), "0x1234", testToken)
+		assert.Error(t, err)
+	})
+}
+
+// newRecipientTestChain serves getAccountInfo from accounts, keyed by address
+func newRecipientTestChain(t *testing.T, accounts map[string]string) *SolanaChain {
+	t.Helper()
+	return newSolanaTestChain(t, map[string]rpcMethod{
+		"getAccountInfo": func(call rpcCall) (string, error) {
+			var address string
+			call.param(0, &address)
+			value := "null"
+			if account, ok := accounts[address]; ok {
+				value = account
+			}
+			return `{"context":{"slot":1},"value":` + value + `}`, nil
+		},
+	})
+}
+
+func TestSolanaClassifyRecipient(t *testing.T) {
+	t.Setenv("RUN_MODE", "")
+	wallet := solana.NewWallet().PublicKey().String()
+	unfunded := solana.NewWallet().PublicKey().String()
+	tokenAccount := solana.NewWallet().PublicKey().String()
+	program := solana.NewWallet().PublicKey().String()
+	chain := newRecipientTestChain(t, map[string]string{
+		wallet:       `{"lamports":1000,"owner":"11111111111111111111111111111111","executable":false,"data":["","base64"]}`,
+		tokenAccount: `{"lamports":2039280,"owner":"` + SPLTokenProgramID + `","executable":false,"data":["","base64"]}`,
+		program:      `{"lamports":1141440,"owner":"BPFLoaderUpgradeab1e11111111111111111111111","executable":true,"data":["","base64"]}`,
+	})
+
+	cases := []struct {
+		name    string
+		address string
+		token   string
+		kind    string
+		warns   bool
+	}{
+		{"wallet", wallet, "SOL", RecipientEOA, false},
+		{"unfunded wallet", unfunded, "", RecipientEOA, false},
+		{"SOL to token account", tokenAccount, "SOL", RecipientTokenAccount, true},
+		{"SPL token to token account", tokenAccount, "DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263", RecipientTokenAccount, false},
+		{"SOL to program", program, "", RecipientProgram, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recipient, err := chain.ClassifyRecipient(context.Background(), tc.address, tc.token)
+			require.NoError(t, err)
+			assert.Equal(t, tc.kind, recipient.Kind)
+			assert.Equal(t, tc.warns, recipient.Warning != "")
+		})
+	}
+
+	_, err := chain.ClassifyRecipient(context.Background(), "not-base58!", "SOL")
+	assert.Error(t, err)
+}
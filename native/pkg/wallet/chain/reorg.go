@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrTransactionNotFound is returned by ConfirmTransaction when the node has no
+// receipt for the transaction, e.g. after a reorg dropped its block.
+var ErrTransactionNotFound = errors.New("transaction receipt not found")
+
+// Reorg describes a transaction leaving the block it was previously confirmed in.
+// NewBlockHash is empty when the transaction is no longer in any canonical block.
+type Reorg struct {
+	PreviousBlockNumber uint64
+	PreviousBlockHash   string
+	NewBlockNumber      uint64
+	NewBlockHash        string
+}
+
+// ReorgDetector remembers the block a transaction was last seen in across
+// confirmation polls and reports when that block is no longer canonical.
+type ReorgDetector struct {
+	blockNumber uint64
+	blockHash   string
+}
+
+// Observe records the latest confirmation result. A nil confirmation, or one
+// without a block hash, means the receipt is currently missing. It returns a
+// non-nil Reorg when the transaction was previously included in a different block.
+func (d *ReorgDetector) Observe(confirmation *TransactionConfirmation) *Reorg {
+	var blockNumber uint64
+	var blockHash string
+	if confirmation != nil {
+		blockNumber = confirmation.BlockNumber
+		blockHash = confirmation.BlockHash
+	}
+
+	if d.blockHash == "" || blockHash == d.blockHash {
+		d.blockNumber, d.blockHash = blockNumber, blockHash
+		return nil
+	}
+
+	reorg := &Reorg{
+		PreviousBlockNumber: d.blockNumber,
+		PreviousBlockHash:   d.blockHash,
+		NewBlockNumber:      blockNumber,
+		NewBlockHash:        blockHash,
+	}
+	d.blockNumber, d.blockHash = blockNumber, blockHash
+	return reorg
+}
+
+// mockBlockHash derives a stable block hash for the mock confirmation responses
+func mockBlockHash(chainName string, blockNumber uint64) string {
+	return crypto.Keccak256Hash([]byte(fmt.Sprintf("%s:%d", chainName, blockNumber))).Hex()
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+)
+
+// ReplacementTracker links transactions that occupy the same nonce slot of an
+// account. When one of them confirms, every other hash in the slot can never
+// confirm, so monitors polling those hashes can stop early.
+type ReplacementTracker struct {
+	slots   map[string]*nonceSlot // by replacementKey(chain, hash)
+	byNonce map[string]*nonceSlot // by chain/from/nonce
+	mutex   sync.Mutex
+}
+
+// nonceSlot holds the hashes broadcast for one account nonce
+type nonceSlot struct {
+	key       string
+	nonce     uint64
+	hashes    map[string]bool
+	confirmed string
+}
+
+// NewReplacementTracker creates an empty tracker
+func NewReplacementTracker() *ReplacementTracker {
+	return &ReplacementTracker{
+		slots:   make(map[string]*nonceSlot),
+		byNonce: make(map[string]*nonceSlot),
+	}
+}
+
+// DefaultReplacementTracker is the process-wide tracker shared by senders and monitors
+var DefaultReplacementTracker = NewReplacementTracker()
+
+// Track records that txHash was broadcast from address with nonce on chainName.
+// A second hash tracked for the same nonce is a replacement (speed-up or cancel).
+func (t *ReplacementTracker) Track(chainName, from string, nonce uint64, txHash string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := config.NormalizeChain(chainName) + "/" + strings.ToLower(from) + "/" + strconv.FormatUint(nonce, 10)
+	slot, ok := t.byNonce[key]
+	if !ok {
+		slot = &nonceSlot{key: key, nonce: nonce, hashes: make(map[string]bool)}
+		t.byNonce[key] = slot
+	}
+	slot.hashes[strings.ToLower(txHash)] = true
+	t.slots[replacementKey(chainName, txHash)] = slot
+}
+
+// MarkConfirmed records that txHash confirmed, superseding the rest of its slot
+func (t *ReplacementTracker) MarkConfirmed(chainName, txHash string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if slot, ok := t.slots[replacementKey(chainName, txHash)]; ok {
+		slot.confirmed = strings.ToLower(txHash)
+	}
+}
+
+// ReplacedBy returns the confirmed hash that replaced txHash, if any, and the
+// nonce both were sent with.
+func (t *ReplacementTracker) ReplacedBy(chainName, txHash string) (replacement string, nonce uint64, ok bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	slot, found := t.slots[replacementKey(chainName, txHash)]
+	if !found || slot.confirmed == "" || slot.confirmed == strings.ToLower(txHash) {
+		return "", 0, false
+	}
+	return slot.confirmed, slot.nonce, true
+}
+
+// Forget drops txHash once its monitor has finished. The slot is released when
+// no monitored hash refers to it any more.
+func (t *ReplacementTracker) Forget(chainName, txHash string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := replacementKey(chainName, txHash)
+	slot, ok := t.slots[key]
+	if !ok {
+		return
+	}
+	delete(t.slots, key)
+	delete(slot.hashes, strings.ToLower(txHash))
+	if len(slot.hashes) == 0 {
+		delete(t.byNonce, slot.key)
+	}
+}
+
+func replacementKey(chainName, txHash string) string {
+	return config.NormalizeChain(chainName) + "/" + strings.ToLower(txHash)
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplacementTracker(t *testing.T) {
+	tracker := NewReplacementTracker()
+	tracker.Track("ethereum", "0xABC", 3, "0xOriginal")
+	tracker.Track("ethereum", "0xabc", 3, "0xSpeedUp")
+	tracker.Track("ethereum", "0xabc", 4, "0xNext")
+
+	_, _, ok := tracker.ReplacedBy("ethereum", "0xoriginal")
+	assert.False(t, ok, "nothing confirmed yet")
+
+	tracker.MarkConfirmed("ETHEREUM", "0xSpeedUp")
+
+	replacement, nonce, ok := tracker.ReplacedBy("ethereum", "0xOriginal")
+	assert.True(t, ok)
+	assert.Equal(t, "0xspeedup", replacement)
+	assert.Equal(t, uint64(3), nonce)
+
+	_, _, ok = tracker.ReplacedBy("ethereum", "0xSpeedUp")
+	assert.False(t, ok, "the confirmed hash is not replaced")
+	_, _, ok = tracker.ReplacedBy("ethereum", "0xNext")
+	assert.False(t, ok, "other nonces are unaffected")
+	_, _, ok = tracker.ReplacedBy("bsc", "0xOriginal")
+	assert.False(t, ok, "slots are per chain")
+
+	tracker.Forget("ethereum", "0xSpeedUp")
+	tracker.Forget("ethereum", "0xOriginal")
+	assert.NotContains(t, tracker.slots, "ethereum/0xoriginal")
+	assert.Len(t, tracker.byNonce, 1)
+}
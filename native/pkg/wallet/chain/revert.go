@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Kinds of revert reason reported in RevertReason.Kind
+const (
+	RevertKindError   = "error"   // Error(string) from require/revert with a message
+	RevertKindPanic   = "panic"   // Panic(uint256) from a failed assert, overflow, etc.
+	RevertKindCustom  = "custom"  // custom error matched against a known or provided ABI
+	RevertKindUnknown = "unknown" // custom error whose selector matched no ABI
+	RevertKindEmpty   = "empty"   // reverted without any data
+)
+
+// RevertReason is the decoded revert data of a failed EVM call or transaction
+type RevertReason struct {
+	Kind     string `json:"kind"`
+	Selector string `json:"selector,omitempty"` // 4-byte selector, 0x-prefixed
+	Name     string `json:"name,omitempty"`     // custom error name
+	Message  string `json:"message"`            // human-readable reason
+	Data     string `json:"data,omitempty"`     // raw revert data, so it can be re-decoded with another ABI
+}
+
+// knownErrorABI lists custom errors commonly raised by tokens and DEX routers
+const knownErrorABI = `[
+	{"type":"error","name":"ERC20InsufficientBalance","inputs":[{"name":"sender","type":"address"},{"name":"balance","type":"uint256"},{"name":"needed","type":"uint256"}]},
+	{"type":"error","name":"ERC20InsufficientAllowance","inputs":[{"name":"spender","type":"address"},{"name":"allowance","type":"uint256"},{"name":"needed","type":"uint256"}]},
+	{"type":"error","name":"ERC20InvalidReceiver","inputs":[{"name":"receiver","type":"address"}]},
+	{"type":"error","name":"ERC20InvalidSender","inputs":[{"name":"sender","type":"address"}]},
+	{"type":"error","name":"ERC721NonexistentToken","inputs":[{"name":"tokenId","type":"uint256"}]},
+	{"type":"error","name":"ERC721IncorrectOwner","inputs":[{"name":"sender","type":"address"},{"name":"tokenId","type":"uint256"},{"name":"owner","type":"address"}]},
+	{"type":"error","name":"OwnableUnauthorizedAccount","inputs":[{"name":"account","type":"address"}]},
+	{"type":"error","name":"V3TooLittleReceived","inputs":[]},
+	{"type":"error","name":"V3TooMuchRequested","inputs":[]},
+	{"type":"error","name":"V2TooLittleReceived","inputs":[]},
+	{"type":"error","name":"V2TooMuchRequested","inputs":[]},
+	{"type":"error","name":"TransactionDeadlinePassed","inputs":[]},
+	{"type":"error","name":"InsufficientETH","inputs":[]},
+	{"type":"error","name":"InsufficientToken","inputs":[]},
+	{"type":"error","name":"AllowanceExpired","inputs":[{"name":"deadline","type":"uint256"}]},
+	{"type":"error","name":"InsufficientAllowance","inputs":[{"name":"amount","type":"uint256"}]}
+]`
+
+var (
+	knownErrorsMu sync.RWMutex
+	knownErrors   []abi.ABI
+)
+
+func init() {
+	if err := RegisterErrorABI(knownErrorABI); err != nil {
+		panic(fmt.Sprintf("invalid built-in error ABI: %v", err))
+	}
+}
+
+// RegisterErrorABI adds the custom errors of a JSON contract ABI to the set
+// consulted by every DecodeRevertReason call
+func RegisterErrorABI(jsonABI string) error {
+	parsed, err := abi.JSON(strings.NewReader(jsonABI))
+	if err != nil {
+		return fmt.Errorf("invalid ABI: %w", err)
+	}
+	knownErrorsMu.Lock()
+	defer knownErrorsMu.Unlock()
+	knownErrors = append(knownErrors, parsed)
+	return nil
+}
+
+// DecodeRevertReason decodes EVM revert data. Custom errors are matched
+// against abis first and then against the registered ABIs; an unmatched
+// custom error is reported by its 4-byte selector.
+func DecodeRevertReason(data []byte, abis ...abi.ABI) *RevertReason {
+	if len(data) == 0 {
+		return &RevertReason{Kind: RevertKindEmpty, Message: "execution reverted without a reason"}
+	}
+	if len(data) < 4 {
+		return &RevertReason{Kind: RevertKindUnknown, Message: "malformed revert data", Data: hexutil.Encode(data)}
+	}
+
+	reason := &RevertReason{Selector: hexutil.Encode(data[:4]), Data: hexutil.Encode(data)}
+	if message, err := abi.UnpackRevert(data); err == nil {
+		reason.Kind = RevertKindError
+		reason.Message = message
+		if reason.Selector == panicSelector {
+			reason.Kind = RevertKindPanic
+		}
+		return reason
+	}
+
+	knownErrorsMu.RLock()
+	candidates := append(append([]abi.ABI{}, abis...), knownErrors...)
+	knownErrorsMu.RUnlock()
+
+	var id [4]byte
+	copy(id[:], data[:4])
+	for i := range candidates {
+		customErr, err := candidates[i].ErrorByID(id)
+		if err != nil {
+			continue
+		}
+		values, err := customErr.Unpack(data)
+		if err != nil {
+			continue
+		}
+		reason.Kind = RevertKindCustom
+		reason.Name = customErr.Name
+		reason.Message = formatCustomError(customErr, values)
+		return reason
+	}
+
+	reason.Kind = RevertKindUnknown
+	reason.Message = "unknown custom error " + reason.Selector
+	return reason
+}
+
+// DecodeRevertReasonHex decodes 0x-prefixed hex revert data, see DecodeRevertReason
+func DecodeRevertReasonHex(data string, abis ...abi.ABI) (*RevertReason, error) {
+	if data == "" || data == "0x" {
+		return DecodeRevertReason(nil, abis...), nil
+	}
+	raw, err := hexutil.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid revert data: %w", err)
+	}
+	return DecodeRevertReason(raw, abis...), nil
+}
+
+// dataError is implemented by JSON-RPC errors that carry data, such as the
+// revert data of a failed eth_call or eth_estimateGas
+type dataError interface {
+	Error() string
+	ErrorData() interface{}
+}
+
+// RevertReasonFromError extracts and decodes the revert data carried by an RPC
+// error. It returns nil when err carries no revert data.
+func RevertReasonFromError(err error, abis ...abi.ABI) *RevertReason {
+	var withData dataError
+	if !errors.As(err, &withData) {
+		return nil
+	}
+	data, ok := withData.ErrorData().(string)
+	if !ok || !strings.HasPrefix(data, "0x") {
+		return nil
+	}
+	reason, decodeErr := DecodeRevertReasonHex(data, abis...)
+	if decodeErr != nil {
+		return nil
+	}
+	return reason
+}
+
+// panicSelector is the selector of Panic(uint256)
+const panicSelector = "0x4e487b71"
+
+// formatCustomError renders a decoded custom error as Name(arg=value, ...)
+func formatCustomError(customErr *abi.Error, unpacked interface{}) string {
+	values, _ := unpacked.([]interface{})
+	args := make([]string, 0, len(values))
+	for i, value := range values {
+		name := fmt.Sprintf("arg%d", i)
+		if i < len(customErr.Inputs) && customErr.Inputs[i].Name != "" {
+			name = customErr.Inputs[i].Name
+		}
+		args = append(args, name+"="+formatABIValue(value))
+	}
+	return fmt.Sprintf("%s(%s)", customErr.Name, strings.Join(args, ", "))
+}
+
+func formatABIValue(value interface{}) string {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Hex()
+	case *big.Int:
+		return v.String()
+	case []byte:
+		return hexutil.Encode(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// String returns the human-readable reason
+func (r *RevertReason) String() string {
+	if r == nil {
+		return ""
+	}
+	return r.Message
+}
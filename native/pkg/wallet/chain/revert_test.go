@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeRevert builds revert data for the error signature sig with the given argument types and values
+func encodeRevert(t *testing.T, sig string, types []string, values ...interface{}) []byte {
+	t.Helper()
+	args := make(abi.Arguments, 0, len(types))
+	for _, name := range types {
+		typ, err := abi.NewType(name, "", nil)
+		require.NoError(t, err)
+		args = append(args, abi.Argument{Type: typ})
+	}
+	packed, err := args.Pack(values...)
+	require.NoError(t, err)
+	return append(crypto.Keccak256([]byte(sig))[:4], packed...)
+}
+
+// rpcDataError mimics the go-ethereum RPC error returned for a reverted call
+type rpcDataError struct{ data string }
+
+func (e *rpcDataError) Error() string          { return "execution reverted" }
+func (e *rpcDataError) ErrorData() interface{} { return e.data }
+
+func TestDecodeRevertReasonStandardErrors(t *testing.T) {
+	reason := DecodeRevertReason(encodeRevert(t, "Error(string)", []string{"string"}, "insufficient output amount"))
+	assert.Equal(t, RevertKindError, reason.Kind)
+	assert.Equal(t, "0x08c379a0", reason.Selector)
+	assert.Equal(t, "insufficient output amount", reason.Message)
+
+	reason = DecodeRevertReason(encodeRevert(t, "Panic(uint256)", []string{"uint256"}, big.NewInt(0x11)))
+	assert.Equal(t, RevertKindPanic, reason.Kind)
+	assert.Contains(t, reason.Message, "overflow")
+
+	reason = DecodeRevertReason(nil)
+	assert.Equal(t, RevertKindEmpty, reason.Kind)
+
+	reason = DecodeRevertReason([]byte{0x01, 0x02})
+	assert.Equal(t, RevertKindUnknown, reason.Kind)
+}
+
+func TestDecodeRevertReasonCustomErrors(t *testing.T) {
+	sender := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	data := encodeRevert(t, "ERC20InsufficientBalance(address,uint256,uint256)",
+		[]string{"address", "uint256", "uint256"}, sender, big.NewInt(5), big.NewInt(10))
+
+	reason := DecodeRevertReason(data)
+	assert.Equal(t, RevertKindCustom, reason.Kind)
+	assert.Equal(t, "ERC20InsufficientBalance", reason.Name)
+	assert.Equal(t, fmt.Sprintf("ERC20InsufficientBalance(sender=%s, balance=5, needed=10)", sender.Hex()), reason.Message)
+
+	// An error outside the built-in set surfaces its selector until an ABI is supplied
+	data = encodeRevert(t, "SlippageExceeded(uint256)", []string{"uint256"}, big.NewInt(42))
+	selector := fmt.Sprintf("0x%x", data[:4])
+	reason = DecodeRevertReason(data)
+	assert.Equal(t, RevertKindUnknown, reason.Kind)
+	assert.Equal(t, selector, reason.Selector)
+	assert.Contains(t, reason.Message, selector)
+
+	contractABI, err := abi.JSON(strings.NewReader(`[{"type":"error","name":"SlippageExceeded","inputs":[{"name":"minOut","type":"uint256"}]}]`))
+	require.NoError(t, err)
+	reason, err = DecodeRevertReasonHex(reason.Data, contractABI)
+	require.NoError(t, err)
+	assert.Equal(t, RevertKindCustom, reason.Kind)
+	assert.Equal(t, "SlippageExceeded(minOut=42)", reason.Message)
+}
+
+func TestRevertReasonFromError(t *testing.T) {
+	data := encodeRevert(t, "Error(string)", []string{"string"}, "paused")
+	wrapped := fmt.Errorf("failed to estimate gas: %w", &rpcDataError{data: fmt.Sprintf("0x%x", data)})
+
+	reason := RevertReasonFromError(wrapped)
+	require.NotNil(t, reason)
+	assert.Equal(t, "paused", reason.Message)
+
+	assert.Nil(t, RevertReasonFromError(fmt.Errorf("connection refused")))
+	assert.Nil(t, RevertReasonFromError(&rpcDataError{data: "not hex"}))
+}
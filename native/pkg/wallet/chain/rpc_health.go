@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+)
+
+// RPCHealthTracker records when each chain last completed a remote call successfully.
+// It is a passive health signal: nothing is probed, callers report their own successes.
+type RPCHealthTracker struct {
+	lastSuccess map[string]time.Time
+	mutex       sync.RWMutex
+}
+
+// NewRPCHealthTracker creates an empty tracker
+func NewRPCHealthTracker() *RPCHealthTracker {
+	return &RPCHealthTracker{
+		lastSuccess: make(map[string]time.Time),
+	}
+}
+
+// DefaultRPCHealth is the process-wide tracker updated by chain implementations
+var DefaultRPCHealth = NewRPCHealthTracker()
+
+// RecordSuccess marks a successful remote call for the given chain at the current time
+func (t *RPCHealthTracker) RecordSuccess(chainName string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.lastSuccess[rpcHealthKey(chainName)] = time.Now().UTC()
+}
+
+// LastSuccess returns the time of the last successful remote call for the chain
+func (t *RPCHealthTracker) LastSuccess(chainName string) (time.Time, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	ts, ok := t.lastSuccess[rpcHealthKey(chainName)]
+	return ts, ok
+}
+
+// Snapshot returns a copy of all recorded timestamps keyed by normalized chain name
+func (t *RPCHealthTracker) Snapshot() map[string]time.Time {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	snapshot := make(map[string]time.Time, len(t.lastSuccess))
+	for chainName, ts := range t.lastSuccess {
+		snapshot[chainName] = ts
+	}
+	return snapshot
+}
+
+// rpcHealthKey maps chain aliases onto the names used in status output
+func rpcHealthKey(chainName string) string {
+	return config.NormalizeChain(chainName)
+}
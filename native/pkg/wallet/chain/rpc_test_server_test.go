@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// rpcCall is a JSON-RPC request received by a test node
+type rpcCall struct {
+	t      *testing.T
+	Method string
+	Params []json.RawMessage
+}
+
+// param decodes parameter i of the call into v
+func (c rpcCall) param(i int, v any) {
+	c.t.Helper()
+	require.Greater(c.t, len(c.Params), i, "%s has no parameter %d", c.Method, i)
+	require.NoError(c.t, json.Unmarshal(c.Params[i], v))
+}
+
+// params decodes every parameter of the call
+func (c rpcCall) params() []any {
+	c.t.Helper()
+	params := make([]any, len(c.Params))
+	for i := range c.Params {
+		c.param(i, &params[i])
+	}
+	return params
+}
+
+// rpcTestError is the JSON-RPC error object a test node answers with
+type rpcTestError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+func (e *rpcTestError) Error() string {
+	return e.Message
+}
+
+// rpcMethod answers a call with its raw JSON result. An *rpcTestError is sent
+// as is, and any other error as a -32000 error with its message.
+type rpcMethod func(call rpcCall) (string, error)
+
+// rpcResult answers every call with the raw JSON result
+func rpcResult(result string) rpcMethod {
+	return func(rpcCall) (string, error) {
+		return result, nil
+	}
+}
+
+// rpcJSON encodes v as a raw JSON result
+func rpcJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// newRPCTestServer serves methods over JSON-RPC until the test ends. Calls to
+// any other method fail the test.
+func newRPCTestServer(t *testing.T, methods map[string]rpcMethod) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		response := map[string]any{"jsonrpc": "2.0", "id": request.ID}
+
+		// Parameters passed by name are seen as a single parameter
+		var params []json.RawMessage
+		if err := json.Unmarshal(request.Params, &params); err != nil && len(request.Params) > 0 {
+			params = []json.RawMessage{request.Params}
+		}
+
+		method, ok := methods[request.Method]
+		if !ok {
+			t.Errorf("unexpected RPC method %s", request.Method)
+			response["error"] = &rpcTestError{Code: -32601, Message: "the method " + request.Method + " does not exist/is not available"}
+			require.NoError(t, json.NewEncoder(w).Encode(response))
+			return
+		}
+		result, err := method(rpcCall{t: t, Method: request.Method, Params: params})
+		var rpcErr *rpcTestError
+		switch {
+		case errors.As(err, &rpcErr):
+			response["error"] = rpcErr
+		case err != nil:
+			response["error"] = &rpcTestError{Code: -32000, Message: err.Error()}
+		default:
+			response["result"] = json.RawMessage(result)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newSolanaTestChain returns a Solana chain at confirmed commitment whose
+// only RPC endpoint serves methods
+func newSolanaTestChain(t *testing.T, methods map[string]rpcMethod) *SolanaChain {
+	t.Helper()
+	server := newRPCTestServer(t, methods)
+	rpcManager, err := NewSolanaRPCManager([]string{server.URL}, zap.NewNop())
+	require.NoError(t, err)
+	return &SolanaChain{rpcManager: rpcManager, config: &config.SolanaChainConfig{Commitment: "confirmed"}, logger: zap.NewNop()}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestRecoverMessageSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	for _, message := range []string{"login nonce 42", "0xdeadbeef"} {
+		signature, err := NewETHChainLegacy().SignMessage(hex.EncodeToString(crypto.FromECDSA(key)), message)
+		require.NoError(t, err)
+
+		signer, err := RecoverMessageSigner(message, signature)
+		require.NoError(t, err)
+		assert.Equal(t, address, signer, message)
+
+		// A tampered message recovers some other key
+		signer, err = RecoverMessageSigner(message+"00", signature)
+		require.NoError(t, err)
+		assert.NotEqual(t, address, signer, message)
+	}
+
+	// 0/1 recovery ids are accepted as well as 27/28
+	signature, err := NewBSCChainLegacy().SignMessage(hex.EncodeToString(crypto.FromECDSA(key)), "hello")
+	require.NoError(t, err)
+	raw, err := hexutil.Decode(signature)
+	require.NoError(t, err)
+	raw[64] -= 27
+	signer, err := RecoverMessageSigner("hello", hexutil.Encode(raw))
+	require.NoError(t, err)
+	assert.Equal(t, address, signer)
+
+	_, err = RecoverMessageSigner("hello", "0x1234")
+	assert.ErrorContains(t, err, "invalid signature length")
+	_, err = RecoverMessageSigner("hello", "not-hex")
+	assert.ErrorContains(t, err, "failed to decode signature")
+}
+
+func TestVerifySolanaMessage(t *testing.T) {
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize))
+	address := base58.Encode(key.Public().(ed25519.PublicKey))
+	other := base58.Encode(ed25519.NewKeyFromSeed(bytes.Repeat([]byte{8}, ed25519.SeedSize)).Public().(ed25519.PublicKey))
+
+	for _, message := range []string{"login nonce 42", "__SOLANA_RAW_BYTES__:\x01\x02"} {
+		signature, err := NewSolanaChainLegacy().SignMessage(base58.Encode(key), message)
+		require.NoError(t, err)
+
+		valid, err := VerifySolanaMessage(address, message, signature)
+		require.NoError(t, err)
+		assert.True(t, valid, message)
+
+		valid, err = VerifySolanaMessage(address, message+"!", signature)
+		require.NoError(t, err)
+		assert.False(t, valid, "tampered message")
+
+		valid, err = VerifySolanaMessage(other, message, signature)
+		require.NoError(t, err)
+		assert.False(t, valid, "wrong signer")
+	}
+
+	_, err := VerifySolanaMessage("0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8", "hello", "1111")
+	assert.ErrorContains(t, err, "invalid Solana address")
+	_, err = VerifySolanaMessage(address, "hello", "1111")
+	assert.ErrorContains(t, err, "invalid signature length")
+}
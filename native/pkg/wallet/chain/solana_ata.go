@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	solana "github.com/gagliardetto/solana-go"
+	"go.uber.org/zap"
+)
+
+// SPLTokenAccountSize is the size in bytes of an SPL token account, which sets its rent
+const SPLTokenAccountSize = 165
+
+// defaultTokenAccountRentLamports is the rent-exempt minimum of a token account,
+// used when the RPC cannot be asked
+const defaultTokenAccountRentLamports = 2039280
+
+// AccountCreationEstimator is implemented by chains where a transfer may have
+// to create the recipient's token account first
+type AccountCreationEstimator interface {
+	// EstimateAccountCreation reports whether sending token to recipient creates
+	// an account, and the rent the sender pays for it
+	EstimateAccountCreation(ctx context.Context, recipient, token string) (*AccountCreationCost, error)
+}
+
+// AccountCreationCost is the extra cost of creating a recipient token account
+type AccountCreationCost struct {
+	Required     bool   `json:"required"`
+	Account      string `json:"account,omitempty"` // associated token account of the recipient
+	RentLamports uint64 `json:"rent_lamports"`
+	Rent         string `json:"rent"` // RentLamports in SOL
+}
+
+// EstimateAccountCreation checks whether recipient already has an associated
+// token account for the mint token. When it does not, transfers create one and
+// the sender pays its rent-exempt minimum, on top of the network fee.
+func (s *SolanaChain) EstimateAccountCreation(ctx context.Context, recipient, token string) (*AccountCreationCost, error) {
+	token = strings.TrimSpace(token)
+	if token == "" || strings.EqualFold(token, "SOL") {
+		return &AccountCreationCost{Rent: "0"}, nil
+	}
+	owner, err := solana.PublicKeyFromBase58(recipient)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
+	mint, err := solana.PublicKeyFromBase58(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token mint address: %s", token)
+	}
+	if s.rpcManager == nil {
+		return nil, errors.New("solana RPC manager not initialized")
+	}
+
+	commitment := s.commitment(ctx)
+	programID := solana.TokenProgramID
+	mintInfo, err := s.rpcManager.GetAccountInfo(ctx, mint.String(), commitment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mint account: %w", err)
+	}
+	if mintInfo.Value != nil && mintInfo.Value.Owner == SPLToken2022ProgramID {
+		programID = solana.Token2022ProgramID
+	}
+
+	ata, _, err := solana.FindProgramAddress(
+		[][]byte{owner[:], programID[:], mint[:]},
+		solana.SPLAssociatedTokenAccountProgramID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive associated token account: %w", err)
+	}
+	account, err := s.rpcManager.GetAccountInfo(ctx, ata.String(), commitment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get associated token account: %w", err)
+	}
+	cost := &AccountCreationCost{Account: ata.String(), Rent: "0"}
+	if account.Value != nil {
+		return cost, nil
+	}
+
+	cost.Required = true
+	cost.RentLamports, err = s.rpcManager.GetMinimumBalanceForRentExemption(ctx, SPLTokenAccountSize)
+	if err != nil || cost.RentLamports == 0 {
+		s.logger.Warn("Token account rent unavailable, using the default",
+			zap.Uint64("lamports", defaultTokenAccountRentLamports), zap.Error(err))
+		cost.RentLamports = defaultTokenAccountRentLamports
+	}
+	cost.Rent = formatDecimal(new(big.Rat).SetFrac64(int64(cost.RentLamports), 1_000_000_000))
+	return cost, nil
+}
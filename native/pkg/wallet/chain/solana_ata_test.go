@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newATATestChain serves getAccountInfo from accounts, keyed by address, and a
+// fixed rent for token accounts
+func newATATestChain(t *testing.T, accounts map[string]string) *SolanaChain {
+	t.Helper()
+	return newSolanaTestChain(t, map[string]rpcMethod{
+		"getAccountInfo": func(call rpcCall) (string, error) {
+			var address string
+			call.param(0, &address)
+			value := "null"
+			if owner, ok := accounts[address]; ok {
+				value = `{"lamports":2039280,"owner":"` + owner + `","data":["","base64"]}`
+			}
+			return `{"context":{"slot":1},"value":` + value + `}`, nil
+		},
+		"getMinimumBalanceForRentExemption": func(call rpcCall) (string, error) {
+			var size float64
+			call.param(0, &size)
+			assert.Equal(t, float64(SPLTokenAccountSize), size)
+			return "2039280", nil
+		},
+	})
+}
+
+func TestEstimateAccountCreation(t *testing.T) {
+	t.Setenv("RUN_MODE", "")
+	recipient := solana.NewWallet().PublicKey()
+	mint := solana.NewWallet().PublicKey()
+	ata, _, err := solana.FindAssociatedTokenAddress(recipient, mint)
+	require.NoError(t, err)
+
+	t.Run("missing ATA", func(t *testing.T) {
+		chain := newATATestChain(t, map[string]string{mint.String(): SPLTokenProgramID})
+		cost, err := chain.EstimateAccountCreation(context.Background(), recipient.String(), mint.String())
+		require.NoError(t, err)
+		assert.True(t, cost.Required)
+		assert.Equal(t, ata.String(), cost.Account)
+		assert.Equal(t, uint64(2039280), cost.RentLamports)
+		assert.Equal(t, "0.00203928", cost.Rent)
+	})
+
+	t.Run("existing ATA", func(t *testing.T) {
+		chain := newATATestChain(t, map[string]string{
+			mint.String(): SPLTokenProgramID,
+			ata.String():  SPLTokenProgramID,
+		})
+		cost, err := chain.EstimateAccountCreation(context.Background(), recipient.String(), mint.String())
+		require.NoError(t, err)
+		assert.False(t, cost.Required)
+		assert.Equal(t, ata.String(), cost.Account)
+		assert.Zero(t, cost.RentLamports)
+		assert.Equal(t, "0", cost.Rent)
+	})
+
+	t.Run("token-2022 mint", func(t *testing.T) {
+		chain := newATATestChain(t, map[string]string{mint.String(): SPLToken2022ProgramID})
+		cost, err := chain.EstimateAccountCreation(context.Background(), recipient.String(), mint.String())
+		require.NoError(t, err)
+		assert.True(t, cost.Required)
+		assert.NotEqual(t, ata.String(), cost.Account, "token-2022 accounts derive from their own program")
+	})
+
+	t.Run("native SOL", func(t *testing.T) {
+		cost, err := NewSolanaChainLegacy().EstimateAccountCreation(context.Background(), recipient.String(), "SOL")
+		require.NoError(t, err)
+		assert.False(t, cost.Required)
+	})
+}
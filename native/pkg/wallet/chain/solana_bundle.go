@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/chains/solana/broadcast"
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"go.uber.org/zap"
+)
+
+// MaxBundleTransactions is the number of caller transactions a bundle can hold;
+// the remaining slot is taken by the tip transaction.
+const MaxBundleTransactions = broadcast.MaxBundleSize - 1
+
+// BundleTransactionSpec describes one transaction of a bundle. Either To and
+// Amount (a native SOL transfer) or Transaction (a base64-encoded, pre-built
+// transaction such as a DEX swap) must be set.
+type BundleTransactionSpec struct {
+	To          string `json:"to,omitempty"`
+	Amount      string `json:"amount,omitempty"` // in SOL
+	Transaction string `json:"transaction,omitempty"`
+}
+
+// BundleSubmitter is implemented by chains that can land several transactions atomically
+type BundleSubmitter interface {
+	// SubmitBundle signs specs in order with privateKey and submits them as one bundle
+	SubmitBundle(ctx context.Context, specs []BundleTransactionSpec, privateKey string, tipLamports uint64) (*broadcast.BundleResult, error)
+}
+
+// bundleChannel is the part of broadcast.JitoBundleChannel used to submit bundles
+type bundleChannel interface {
+	SubmitBundle(ctx context.Context, txs []*solana.Transaction, payer solana.PrivateKey, tipLamports uint64) (*broadcast.BundleResult, error)
+}
+
+// SubmitBundle signs each spec and submits them, plus a tip, as a single Jito bundle
+func (s *SolanaChain) SubmitBundle(ctx context.Context, specs []BundleTransactionSpec, privateKey string, tipLamports uint64) (*broadcast.BundleResult, error) {
+	if len(specs) == 0 {
+		return nil, errors.New("bundle must contain at least one transaction")
+	}
+	if len(specs) > MaxBundleTransactions {
+		return nil, fmt.Errorf("%w: %d transactions given, at most %d allowed", broadcast.ErrBundleTooLarge, len(specs), MaxBundleTransactions)
+	}
+
+	channel, err := s.bundleChannel()
+	if err != nil {
+		return nil, err
+	}
+
+	payer, err := solana.PrivateKeyFromBase58(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	var blockhash solana.Hash
+	txs := make([]*solana.Transaction, 0, len(specs))
+	for i, spec := range specs {
+		var tx *solana.Transaction
+		if spec.Transaction != "" {
+			tx, err = solana.TransactionFromBase64(spec.Transaction)
+			if err != nil {
+				return nil, fmt.Errorf("transaction %d: failed to decode: %w", i, err)
+			}
+		} else {
+			// All transfers share one blockhash so the bundle expires as a unit
+			if blockhash.IsZero() {
+				if blockhash, err = s.latestBlockhash(ctx); err != nil {
+					return nil, err
+				}
+			}
+			if tx, err = buildTransferTransaction(payer, spec, blockhash); err != nil {
+				return nil, fmt.Errorf("transaction %d: %w", i, err)
+			}
+		}
+
+		if _, err := tx.PartialSign(func(key solana.PublicKey) *solana.PrivateKey {
+			if key.Equals(payer.PublicKey()) {
+				return &payer
+			}
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("transaction %d: failed to sign: %w", i, err)
+		}
+		txs = append(txs, tx)
+	}
+
+	result, err := channel.SubmitBundle(ctx, txs, payer, tipLamports)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Submitted Solana bundle",
+		zap.String("bundle_id", result.BundleID),
+		zap.Int("transactions", len(txs)),
+		zap.String("status", result.Status))
+
+	return result, nil
+}
+
+// bundleChannel returns the registered jito-bundle broadcast channel
+func (s *SolanaChain) bundleChannel() (bundleChannel, error) {
+	const name = "jito-bundle"
+	if s.broadcastManager == nil {
+		return nil, fmt.Errorf("%w: %s", broadcast.ErrChannelNotFound, name)
+	}
+	channel, ok := s.broadcastManager.GetChannel(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", broadcast.ErrChannelNotFound, name)
+	}
+	if !channel.IsEnabled() {
+		return nil, fmt.Errorf("%w: %s", broadcast.ErrChannelDisabled, name)
+	}
+	submitter, ok := channel.(bundleChannel)
+	if !ok {
+		return nil, fmt.Errorf("channel %s cannot submit bundles", name)
+	}
+	return submitter, nil
+}
+
+// latestBlockhash fetches a recent blockhash for new transactions
+func (s *SolanaChain) latestBlockhash(ctx context.Context) (solana.Hash, error) {
+	if s.rpcManager == nil {
+		return solana.Hash{}, errors.New("solana RPC is not configured")
+	}
+	result, err := s.rpcManager.GetLatestBlockhash(ctx, s.commitment(ctx))
+	if err != nil {
+		return solana.Hash{}, fmt.Errorf("failed to get blockhash: %w", err)
+	}
+	blockhash, err := solana.HashFromBase58(result.Value.Blockhash)
+	if err != nil {
+		return solana.Hash{}, fmt.Errorf("invalid blockhash %q: %w", result.Value.Blockhash, err)
+	}
+	return blockhash, nil
+}
+
+// buildTransferTransaction creates an unsigned native SOL transfer from payer
+func buildTransferTransaction(payer solana.PrivateKey, spec BundleTransactionSpec, blockhash solana.Hash) (*solana.Transaction, error) {
+	to, err := solana.PublicKeyFromBase58(spec.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to address: %w", err)
+	}
+	lamports, err := parseSOLAmount(spec.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{
+			system.NewTransferInstruction(lamports, payer.PublicKey(), to).Build(),
+		},
+		blockhash,
+		solana.TransactionPayer(payer.PublicKey()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transfer: %w", err)
+	}
+	return tx, nil
+}
+
+// parseSOLAmount converts a decimal SOL amount to lamports
+func parseSOLAmount(amount string) (uint64, error) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(amount), 64)
+	if err != nil || value <= 0 || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("invalid amount: %q", amount)
+	}
+	return uint64(math.Round(value * float64(solana.LAMPORTS_PER_SOL))), nil
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/chains/solana/broadcast"
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	solana "github.com/gagliardetto/solana-go"
+	"go.uber.org/zap"
+)
+
+// fakeBundleChannel records the transactions it is asked to submit
+type fakeBundleChannel struct {
+	enabled bool
+	txs     []*solana.Transaction
+	err     error
+}
+
+func (f *fakeBundleChannel) GetName() string  { return "jito-bundle" }
+func (f *fakeBundleChannel) IsEnabled() bool  { return f.enabled }
+func (f *fakeBundleChannel) GetPriority() int { return 4 }
+func (f *fakeBundleChannel) Close() error     { return nil }
+
+func (f *fakeBundleChannel) BroadcastTransaction(context.Context, *broadcast.BroadcastParams) (*broadcast.BroadcastResult, error) {
+	return nil, errors.New("not used")
+}
+
+func (f *fakeBundleChannel) GetTransactionStatus(context.Context, string) (*broadcast.TransactionStatus, error) {
+	return nil, errors.New("not used")
+}
+
+func (f *fakeBundleChannel) SubmitBundle(_ context.Context, txs []*solana.Transaction, _ solana.PrivateKey, tipLamports uint64) (*broadcast.BundleResult, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.txs = txs
+	return &broadcast.BundleResult{BundleID: "bundle-1", Status: "pending", TipLamports: tipLamports}, nil
+}
+
+func newBundleTestChain(channel *fakeBundleChannel) *SolanaChain {
+	manager := broadcast.NewBroadcastManager(&config.BroadcastConfig{})
+	if channel != nil {
+		manager.RegisterChannel(channel)
+	}
+	return &SolanaChain{
+		name:             "SOLANA",
+		chainID:          "501",
+		logger:           zap.NewNop(),
+		config:           &config.SolanaChainConfig{Commitment: "confirmed"},
+		rpcManager:       &SolanaRPCManager{logger: zap.NewNop(), runMode: "test"},
+		broadcastManager: manager,
+	}
+}
+
+func TestSolanaChain_SubmitBundle(t *testing.T) {
+	payer, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+	recipient := solana.NewWallet().PublicKey().String()
+	specs := []BundleTransactionSpec{
+		{To: recipient, Amount: "0.5"},
+		{To: recipient, Amount: "0.25"},
+	}
+
+	channel := &fakeBundleChannel{enabled: true}
+	result, err := newBundleTestChain(channel).SubmitBundle(context.Background(), specs, payer.String(), 5000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.BundleID != "bundle-1" || result.TipLamports != 5000 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(channel.txs) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(channel.txs))
+	}
+	for i, tx := range channel.txs {
+		if err := tx.VerifySignatures(); err != nil {
+			t.Errorf("transaction %d is not signed by the payer: %v", i, err)
+		}
+		if tx.Message.RecentBlockhash != channel.txs[0].Message.RecentBlockhash {
+			t.Errorf("transaction %d does not share the bundle blockhash", i)
+		}
+	}
+}
+
+func TestSolanaChain_SubmitBundleErrors(t *testing.T) {
+	payer, _ := solana.NewRandomPrivateKey()
+	recipient := solana.NewWallet().PublicKey().String()
+	spec := BundleTransactionSpec{To: recipient, Amount: "1"}
+
+	tooMany := make([]BundleTransactionSpec, MaxBundleTransactions+1)
+	for i := range tooMany {
+		tooMany[i] = spec
+	}
+	_, err := newBundleTestChain(&fakeBundleChannel{enabled: true}).SubmitBundle(context.Background(), tooMany, payer.String(), 0)
+	if !errors.Is(err, broadcast.ErrBundleTooLarge) {
+		t.Errorf("expected ErrBundleTooLarge, got %v", err)
+	}
+
+	_, err = newBundleTestChain(nil).SubmitBundle(context.Background(), []BundleTransactionSpec{spec}, payer.String(), 0)
+	if !errors.Is(err, broadcast.ErrChannelNotFound) {
+		t.Errorf("expected ErrChannelNotFound, got %v", err)
+	}
+
+	_, err = newBundleTestChain(&fakeBundleChannel{}).SubmitBundle(context.Background(), []BundleTransactionSpec{spec}, payer.String(), 0)
+	if !errors.Is(err, broadcast.ErrChannelDisabled) {
+		t.Errorf("expected ErrChannelDisabled, got %v", err)
+	}
+
+	rejected := &fakeBundleChannel{enabled: true, err: broadcast.ErrBundleRejected}
+	_, err = newBundleTestChain(rejected).SubmitBundle(context.Background(), []BundleTransactionSpec{spec}, payer.String(), 0)
+	if !errors.Is(err, broadcast.ErrBundleRejected) {
+		t.Errorf("expected ErrBundleRejected, got %v", err)
+	}
+
+	_, err = newBundleTestChain(&fakeBundleChannel{enabled: true}).SubmitBundle(context.Background(), []BundleTransactionSpec{{To: recipient, Amount: "-1"}}, payer.String(), 0)
+	if err == nil {
+		t.Error("expected error for negative amount")
+	}
+}
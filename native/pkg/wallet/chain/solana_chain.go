@@ -4,9 +4,10 @@ package chain
 import (
 	"context"
 	"crypto/ed25519"
+	"encoding/base64"
 	"errors"
 	"fmt"
-	"os"
+	"io"
 	"strings"
 	"time"
 
@@ -28,6 +29,8 @@ type SolanaChain struct {
 	rpcManager       *SolanaRPCManager
 	retryManager     *SolanaRetryManager
 	broadcastManager *broadcast.BroadcastManager
+	balanceConfig    config.BalanceConfig
+	entropy          io.Reader
 }
 
 // NewSolanaChain creates a new Solana chain instance with enhanced blockchain integration
@@ -118,6 +121,25 @@ func NewSolanaChainLegacy() *SolanaChain {
 	}
 }
 
+// SetEntropySource replaces the randomness CreateWallet draws mnemonics
+// from. nil restores crypto/rand.
+func (s *SolanaChain) SetEntropySource(source io.Reader) {
+	s.entropy = source
+}
+
+// SetBalanceConfig sets the balance source ordering used by GetBalance
+func (s *SolanaChain) SetBalanceConfig(cfg config.BalanceConfig) {
+	s.balanceConfig = cfg
+}
+
+// NativeReserve returns the configured amount of SOL kept back for fees
+func (s *SolanaChain) NativeReserve() float64 {
+	if s.config == nil {
+		return 0
+	}
+	return s.config.ReserveSOL
+}
+
 // DeriveSolanaPrivateKey derives a Solana private key from seed and path
 func DeriveSolanaPrivateKey(seed []byte, path string) (ed25519.PrivateKey, error) {
 	// For simplicity, we'll generate the key directly from the seed for now
@@ -140,16 +162,10 @@ func (s *SolanaChain) GetChainName() string {
 
 // CreateWallet generates a new Solana wallet
 func (s *SolanaChain) CreateWallet(ctx context.Context) (*WalletInfo, error) {
-	// Generate entropy for mnemonic
-	entropy, err := bip39.NewEntropy(128) // 128 bits = 12 words
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate entropy: %w", err)
-	}
-
 	// Generate mnemonic phrase
-	mnemonic, err := bip39.NewMnemonic(entropy)
+	mnemonic, err := newMnemonic(s.entropy)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate mnemonic: %w", err)
+		return nil, err
 	}
 
 	// Generate seed from mnemonic
@@ -232,74 +248,64 @@ func (s *SolanaChain) GetBalance(ctx context.Context, address string, token stri
 		return "", errors.New("invalid Solana address format")
 	}
 
-	// Normalize token name
-	token = strings.ToUpper(strings.TrimSpace(token))
-	if token == "" {
-		token = "SOL"
+	// Normalize token name; SPL tokens are named by their case-sensitive mint
+	token = strings.TrimSpace(token)
+	rpcBalance := func(ctx context.Context) (string, error) {
+		return s.getRPCBalance(ctx, address)
 	}
-
-	// For now, only support SOL (native token)
-	if token != "SOL" {
+	if token == "" || strings.EqualFold(token, "SOL") {
+		token = "SOL"
+	} else if isSolanaPublicKey(token) {
+		rpcBalance = func(ctx context.Context) (string, error) {
+			return s.getRPCTokenBalance(ctx, address, token)
+		}
+	} else {
 		return "", fmt.Errorf("unsupported token: %s", token)
 	}
 
-	// Try to get balance using RPC manager if available
-	if s.rpcManager != nil {
-		result, err := s.rpcManager.GetBalance(ctx, address, s.config.Commitment)
-		if err == nil {
-			// Convert lamports to SOL (1 SOL = 1,000,000,000 lamports)
-			balanceSOL := float64(result.Value) / 1000000000.0
-			
-			// Format balance: for zero balance, return "0", otherwise show appropriate precision
-			var balanceStr string
-			if result.Value == 0 {
-				balanceStr = "0"
-			} else if balanceSOL >= 1 {
-				balanceStr = fmt.Sprintf("%.6f", balanceSOL)
-			} else {
-				balanceStr = fmt.Sprintf("%.9f", balanceSOL)
+	return fetchBalance(ctx, s.balanceConfig, s.logger, "solana", rpcBalance,
+		func(ctx context.Context) (string, error) {
+			balance, provider, err := getDEXBalance(ctx, s.dexAggregator, s.chainID, address, token)
+			if err != nil {
+				return "", err
 			}
-			
-			s.logger.Debug("Solana balance retrieved via RPC",
-				zap.String("address", address),
-				zap.Uint64("lamports", result.Value),
-				zap.String("balance_sol", balanceStr))
-			
-			return balanceStr, nil
-		}
-		
-		s.logger.Warn("Solana RPC balance failed, falling back to DEX provider",
-			zap.Error(err))
+			s.logger.Debug("Solana balance retrieved via DEX provider",
+				zap.String("provider", provider),
+				zap.String("balance", balance))
+			return balance, nil
+		})
+}
+
+// getRPCBalance reads the SOL balance of address through the RPC manager
+func (s *SolanaChain) getRPCBalance(ctx context.Context, address string) (string, error) {
+	if s.rpcManager == nil {
+		return "", errors.New("no Solana RPC endpoint configured")
 	}
 
-	// Try to get balance using DEX aggregator as fallback
-	if s.dexAggregator != nil {
-		providers := s.dexAggregator.GetSupportedProviders(s.chainID)
-		if len(providers) > 0 {
-			// Try first available provider
-			provider, err := s.dexAggregator.GetProviderByName(providers[0])
-			if err == nil {
-				balanceInfo, err := provider.GetBalance(ctx, address, token, s.chainID)
-				if err == nil {
-					s.logger.Debug("Solana balance retrieved via DEX provider",
-						zap.String("provider", providers[0]),
-						zap.String("balance", balanceInfo.Balance))
-					return balanceInfo.Balance, nil
-				}
-				s.logger.Warn("Solana DEX provider balance failed, falling back to mock",
-					zap.String("provider", providers[0]),
-					zap.Error(err))
-			}
-		}
+	result, err := s.rpcManager.GetBalance(ctx, address, s.commitment(ctx))
+	if err != nil {
+		return "", err
 	}
 
-	// TODO: Implement actual balance retrieval from Solana RPC
-	// This is a mock implementation that returns "0"
-	// In a real implementation, you would:
-	// 1. Connect to a Solana RPC endpoint
-	// 2. Use getBalance RPC method to get the balance
-	// 3. Convert from lamports to SOL (1 SOL = 1,000,000,000 lamports)
-	return "0", nil
+	// Convert lamports to SOL (1 SOL = 1,000,000,000 lamports)
+	balanceSOL := float64(result.Value) / 1000000000.0
+
+	// Format balance: for zero balance, return "0", otherwise show appropriate precision
+	var balanceStr string
+	if result.Value == 0 {
+		balanceStr = "0"
+	} else if balanceSOL >= 1 {
+		balanceStr = fmt.Sprintf("%.6f", balanceSOL)
+	} else {
+		balanceStr = fmt.Sprintf("%.9f", balanceSOL)
+	}
+
+	s.logger.Debug("Solana balance retrieved via RPC",
+		zap.String("address", address),
+		zap.Uint64("lamports", result.Value),
+		zap.String("balance_sol", balanceStr))
+
+	return balanceStr, nil
 }
 
 // SendTransaction sends a transaction on the Solana network
@@ -389,35 +395,50 @@ func (s *SolanaChain) SendTransaction(ctx context.Context, from, to string, amou
 }
 
 // sendTransactionWithRetry executes a Solana transaction with intelligent retry logic
-func (s *SolanaChain) sendTransactionWithRetry(ctx context.Context, from, to, amount, token, _ string) (string, error) {
+func (s *SolanaChain) sendTransactionWithRetry(ctx context.Context, from, to, amount, token, privateKey string) (string, error) {
 	if s.retryManager == nil || s.rpcManager == nil {
 		// Fallback to mock implementation if managers not available
 		return s.createMockTransaction(from, to, amount, token)
 	}
 	
-	// Parse amount to lamports (assuming SOL for now)
-	// In a real implementation, this would handle different token decimals
-	amountLamports := uint64(1000000) // Mock: 0.001 SOL in lamports
-	
-	// Get recent blockhash
-	blockhashResult, err := s.rpcManager.GetLatestBlockhash(ctx, s.config.Commitment)
-	if err != nil {
-		s.logger.Error("Failed to get latest blockhash", zap.Error(err))
-		return "", fmt.Errorf("failed to get blockhash: %w", err)
-	}
-	
 	// Prepare transaction parameters
 	txParams := &TransactionParams{
 		From:            from,
 		To:              to,
-		Amount:          amountLamports,
 		TokenMint:       token,
+		PrivateKey:      privateKey,
 		Slippage:        0.005, // 0.5% default slippage
-		RecentBlockhash: blockhashResult.Value.Blockhash,
 		JitoTipAmount:   s.config.Jito.BaseTipLamports,
 		GasStrategy:     s.config.Retry.GasStrategy,
 	}
 	
+	// Resolve the amount in lamports or token units
+	if err := s.prepareTransfer(ctx, txParams, amount); err != nil {
+		return "", err
+	}
+	
+	if s.DurableNonceEnabled() {
+		// Use the nonce account's stored blockhash so the transaction does not expire
+		nonce, err := s.GetNonceAccount(ctx, s.config.DurableNonce.NonceAccount)
+		if err != nil {
+			s.logger.Error("Failed to get durable nonce", zap.Error(err))
+			return "", fmt.Errorf("failed to get durable nonce: %w", err)
+		}
+		if nonce.Authority != from {
+			return "", fmt.Errorf("nonce authority %s is not the sending wallet %s", nonce.Authority, from)
+		}
+		txParams.RecentBlockhash = nonce.Nonce
+		txParams.NonceAccount = nonce.Address
+	} else {
+		// Get recent blockhash
+		blockhashResult, err := s.rpcManager.GetLatestBlockhash(ctx, s.commitment(ctx))
+		if err != nil {
+			s.logger.Error("Failed to get latest blockhash", zap.Error(err))
+			return "", fmt.Errorf("failed to get blockhash: %w", err)
+		}
+		txParams.RecentBlockhash = blockhashResult.Value.Blockhash
+	}
+	
 	// Execute transaction with retry logic
 	result, err := s.retryManager.ExecuteWithRetry(ctx, txParams, s.executeTransactionAttempt)
 	if err != nil {
@@ -443,13 +464,28 @@ func (s *SolanaChain) executeTransactionAttempt(ctx context.Context, params *Tra
 		zap.Uint64("amount", params.Amount),
 		zap.String("blockhash", params.RecentBlockhash))
 	
-	// Create transaction (simplified for now)
-	transactionData, signature := s.createTransaction(params)
+	// A retry cleared the blockhash the previous attempt used
+	if params.RecentBlockhash == "" {
+		blockhash, err := s.latestBlockhash(ctx)
+		if err != nil {
+			return "", err
+		}
+		params.RecentBlockhash = blockhash.String()
+	}
+	
+	transactionBase64, signature, err := s.createTransaction(params)
+	if err != nil {
+		return "", err
+	}
+	transactionData, err := base64.StdEncoding.DecodeString(transactionBase64)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode transaction: %w", err)
+	}
 	
 	// Prepare broadcast parameters
 	broadcastParams := &broadcast.BroadcastParams{
 		SignedTransaction:   transactionData,
-		TransactionBase64:   string(transactionData), // Would be base64 in real impl
+		TransactionBase64:   transactionBase64,
 		Signature:          signature,
 		From:               params.From,
 		To:                 params.To,
@@ -457,10 +493,11 @@ func (s *SolanaChain) executeTransactionAttempt(ctx context.Context, params *Tra
 		Token:              params.TokenMint,
 		SkipPreflight:      false,
 		MaxRetries:         3,
-		PreflightCommitment: s.config.Commitment,
+		PreflightCommitment: s.commitment(ctx),
 		Timeout:            30 * time.Second,
 		Metadata: map[string]any{
 			"blockhash":      params.RecentBlockhash,
+			"nonce_account":  params.NonceAccount,
 			"jito_tip":       params.JitoTipAmount,
 			"slippage":       params.Slippage,
 			"gas_strategy":   params.GasStrategy,
@@ -482,36 +519,6 @@ func (s *SolanaChain) executeTransactionAttempt(ctx context.Context, params *Tra
 	return result.Signature, nil
 }
 
-// createTransaction creates and signs a Solana transaction
-func (s *SolanaChain) createTransaction(params *TransactionParams) ([]byte, string) {
-	// In a real implementation, this would:
-	// 1. Create proper Solana transaction with transfer instruction
-	// 2. Sign with ed25519 private key  
-	// 3. Serialize to bytes
-	// 4. Return both serialized transaction and signature
-	
-	if os.Getenv("RUN_MODE") == "test" {
-		// Generate mock transaction data and signature
-		mockTxData := []byte(fmt.Sprintf("MockTxData_%s_%d", params.From[:8], time.Now().Unix()))
-		mockSignature := fmt.Sprintf("MockSignature_%s_%d", params.From[:8], time.Now().Unix())
-		return mockTxData, mockSignature
-	}
-	
-	// TODO: Implement actual Solana transaction creation
-	// This would involve:
-	// - Creating transfer or token transfer instructions
-	// - Setting compute budget and priority fees
-	// - Adding Jito tip instruction if enabled
-	// - Signing with private key
-	// - Serializing to wire format
-	
-	simulatedTxData := []byte("SimulatedSolanaTransaction")
-	simulatedSignature := "SimulatedSolanaSignature"
-	
-	return simulatedTxData, simulatedSignature
-}
-
-
 // createMockTransaction creates a mock transaction signature for fallback
 func (s *SolanaChain) createMockTransaction(from, to, amount, token string) (string, error) {
 	s.logger.Debug("Creating mock transaction (fallback mode)",
@@ -626,6 +633,10 @@ func (s *SolanaChain) ConfirmTransaction(ctx context.Context, txHash string, req
 		requiredConfirmations = 1 // Default for Solana (single confirmation is typically sufficient)
 	}
 
+	if s.rpcManager != nil {
+		return s.confirmViaRPC(ctx, txHash, requiredConfirmations)
+	}
+
 	// TODO: Implement actual transaction confirmation checking for Solana
 	// This is a mock implementation for development purposes
 	// In a real implementation, you would:
@@ -664,6 +675,8 @@ func (s *SolanaChain) ConfirmTransaction(ctx context.Context, txHash string, req
 		transactionFee = "0.000015" // Higher fee for token transfers
 	}
 
+	fee, _ := NewFee("solana", transactionFee)
+
 	return &TransactionConfirmation{
 		Status:                status,
 		Confirmations:         confirmations,
@@ -671,7 +684,43 @@ func (s *SolanaChain) ConfirmTransaction(ctx context.Context, txHash string, req
 		BlockNumber:           blockNumber,
 		GasUsed:               gasUsed,
 		TransactionFee:        transactionFee,
+		Fee:                   fee,
 		Timestamp:             timestamp,
 		TxHash:                txHash,
+		Commitment:            s.commitment(ctx),
 	}, nil
+}
+
+// confirmViaRPC reads the signature status and reports the transaction as
+// confirmed once it has reached the commitment level of the call
+func (s *SolanaChain) confirmViaRPC(ctx context.Context, txHash string, requiredConfirmations uint64) (*TransactionConfirmation, error) {
+	commitment := s.commitment(ctx)
+	result, err := s.rpcManager.GetSignatureStatus(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signature status: %w", err)
+	}
+	if len(result.Value) == 0 || result.Value[0] == nil {
+		return nil, ErrTransactionNotFound
+	}
+	status := result.Value[0]
+
+	confirmation := &TransactionConfirmation{
+		Status:                "pending",
+		RequiredConfirmations: requiredConfirmations,
+		BlockNumber:           status.Slot,
+		TxHash:                txHash,
+		Commitment:            commitment,
+	}
+	switch {
+	case status.Err != nil:
+		confirmation.Status = "failed"
+	case CommitmentSatisfied(status.ConfirmationStatus, commitment):
+		confirmation.Status = "confirmed"
+		// Finalized signatures no longer report a confirmation count
+		confirmation.Confirmations = requiredConfirmations
+		if status.Confirmations != nil && *status.Confirmations > requiredConfirmations {
+			confirmation.Confirmations = *status.Confirmations
+		}
+	}
+	return confirmation, nil
 }
\ No newline at end of file
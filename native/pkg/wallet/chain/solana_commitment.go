@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Solana commitment levels, from fastest to safest
+const (
+	CommitmentProcessed = "processed"
+	CommitmentConfirmed = "confirmed"
+	CommitmentFinalized = "finalized"
+)
+
+// commitmentRank orders commitment levels so a reached level can be compared
+// with a required one
+var commitmentRank = map[string]int{
+	CommitmentProcessed: 1,
+	CommitmentConfirmed: 2,
+	CommitmentFinalized: 3,
+}
+
+// IsValidCommitment reports whether level is a supported Solana commitment level
+func IsValidCommitment(level string) bool {
+	_, ok := commitmentRank[strings.ToLower(strings.TrimSpace(level))]
+	return ok
+}
+
+// ValidateCommitment returns an error unless level is processed, confirmed or finalized
+func ValidateCommitment(level string) error {
+	if !IsValidCommitment(level) {
+		return fmt.Errorf("unsupported commitment %q (supported: processed, confirmed, finalized)", level)
+	}
+	return nil
+}
+
+// CommitmentSatisfied reports whether a transaction that reached the reached
+// commitment level meets the required one
+func CommitmentSatisfied(reached, required string) bool {
+	reachedRank, ok := commitmentRank[strings.ToLower(reached)]
+	if !ok {
+		return false
+	}
+	return reachedRank >= commitmentRank[strings.ToLower(required)]
+}
+
+type commitmentKey struct{}
+
+// WithCommitment returns a context that overrides the configured Solana
+// commitment level for the balance, send and confirmation calls made with it
+func WithCommitment(ctx context.Context, level string) context.Context {
+	return context.WithValue(ctx, commitmentKey{}, strings.ToLower(strings.TrimSpace(level)))
+}
+
+// CommitmentFromContext returns the commitment override carried by ctx, if any
+func CommitmentFromContext(ctx context.Context) (string, bool) {
+	level, ok := ctx.Value(commitmentKey{}).(string)
+	return level, ok && level != ""
+}
+
+// commitment returns the commitment level for a call: the per-call override,
+// then the configured level, then confirmed
+func (s *SolanaChain) commitment(ctx context.Context) string {
+	if level, ok := CommitmentFromContext(ctx); ok {
+		return level
+	}
+	if s.config != nil && s.config.Commitment != "" {
+		return strings.ToLower(s.config.Commitment)
+	}
+	return CommitmentConfirmed
+}
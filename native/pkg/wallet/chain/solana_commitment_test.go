@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCommitment(t *testing.T) {
+	for _, level := range []string{"processed", "confirmed", "finalized", " Finalized "} {
+		assert.NoError(t, ValidateCommitment(level), level)
+	}
+	for _, level := range []string{"", "max", "recent"} {
+		assert.Error(t, ValidateCommitment(level), level)
+	}
+}
+
+func TestCommitmentSatisfied(t *testing.T) {
+	assert.True(t, CommitmentSatisfied("finalized", "confirmed"))
+	assert.True(t, CommitmentSatisfied("confirmed", "confirmed"))
+	assert.False(t, CommitmentSatisfied("confirmed", "finalized"))
+	assert.False(t, CommitmentSatisfied("processed", "confirmed"))
+	assert.False(t, CommitmentSatisfied("", "processed"))
+}
+
+func TestSolanaChainCommitmentOverride(t *testing.T) {
+	solana := &SolanaChain{config: &config.SolanaChainConfig{Commitment: "processed"}}
+	assert.Equal(t, "processed", solana.commitment(context.Background()))
+	assert.Equal(t, "finalized", solana.commitment(WithCommitment(context.Background(), "Finalized")))
+
+	assert.Equal(t, "confirmed", NewSolanaChainLegacy().commitment(context.Background()))
+}
+
+func TestSolanaChainConfirmTransactionCommitment(t *testing.T) {
+	t.Setenv("RUN_MODE", "")
+	var status string
+	var commitments []string
+	solana := newSolanaTestChain(t, map[string]rpcMethod{
+		"getSignatureStatuses": func(rpcCall) (string, error) {
+			return `{"context":{"slot":300},"value":[` + status + `]}`, nil
+		},
+		"getBalance": func(call rpcCall) (string, error) {
+			var options struct {
+				Commitment string `json:"commitment"`
+			}
+			call.param(1, &options)
+			commitments = append(commitments, options.Commitment)
+			return `{"context":{"slot":300},"value":1000000000}`, nil
+		},
+	})
+	signature := base58.Encode(bytes.Repeat([]byte{7}, 64))
+	ctx := context.Background()
+	finalized := WithCommitment(ctx, CommitmentFinalized)
+
+	status = `{"slot":250,"confirmations":12,"confirmationStatus":"confirmed","err":null}`
+	confirmation, err := solana.ConfirmTransaction(ctx, signature, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "confirmed", confirmation.Status)
+	assert.Equal(t, "confirmed", confirmation.Commitment)
+	assert.Equal(t, uint64(12), confirmation.Confirmations)
+	assert.Equal(t, uint64(250), confirmation.BlockNumber)
+
+	confirmation, err = solana.ConfirmTransaction(finalized, signature, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "pending", confirmation.Status, "confirmed is not enough when finalized is required")
+	assert.Equal(t, "finalized", confirmation.Commitment)
+
+	status = `{"slot":250,"confirmations":null,"confirmationStatus":"finalized","err":null}`
+	confirmation, err = solana.ConfirmTransaction(finalized, signature, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "confirmed", confirmation.Status)
+	assert.Equal(t, uint64(1), confirmation.Confirmations)
+
+	status = `{"slot":250,"confirmations":null,"confirmationStatus":"finalized","err":{"InstructionError":[0,{"Custom":1}]}}`
+	confirmation, err = solana.ConfirmTransaction(ctx, signature, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "failed", confirmation.Status)
+
+	status = "null"
+	_, err = solana.ConfirmTransaction(ctx, signature, 1)
+	assert.True(t, errors.Is(err, ErrTransactionNotFound))
+
+	_, err = solana.getRPCBalance(ctx, "9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	require.NoError(t, err)
+	_, err = solana.getRPCBalance(finalized, "9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"confirmed", "finalized"}, commitments)
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	solana "github.com/gagliardetto/solana-go"
+	"go.uber.org/zap"
+)
+
+// ErrFeePayerUnfunded is wrapped by a FeePayerUnfundedError
+var ErrFeePayerUnfunded = errors.New("fee payer cannot cover the transaction fee")
+
+// FeePayerUnfundedError reports a fee payer whose balance is below the fee
+type FeePayerUnfundedError struct {
+	Address  string
+	Balance  uint64 // lamports
+	Required uint64 // lamports
+}
+
+func (e *FeePayerUnfundedError) Error() string {
+	return fmt.Sprintf("%s: %s holds %d lamports, the fee is %d", ErrFeePayerUnfunded, e.Address, e.Balance, e.Required)
+}
+
+func (e *FeePayerUnfundedError) Unwrap() error {
+	return ErrFeePayerUnfunded
+}
+
+// FeePayerSender is implemented by chains that can charge the fee of a send
+// to an account other than the sender
+type FeePayerSender interface {
+	// SendTransactionWithFeePayer sends like SendTransaction, with the fee paid
+	// by the account of feePayerKey, which signs alongside privateKey
+	SendTransactionWithFeePayer(ctx context.Context, from, to, amount, token, privateKey, feePayerKey string) (string, error)
+}
+
+type feePayerContextKey struct{}
+
+// WithFeePayer returns a context whose sends have their fee paid by the
+// wallet account at address instead of the sender
+func WithFeePayer(ctx context.Context, address string) context.Context {
+	return context.WithValue(ctx, feePayerContextKey{}, strings.TrimSpace(address))
+}
+
+// FeePayerFromContext returns the fee payer carried by ctx, if any
+func FeePayerFromContext(ctx context.Context) (string, bool) {
+	address, ok := ctx.Value(feePayerContextKey{}).(string)
+	return address, ok && address != ""
+}
+
+// SendTransactionWithFeePayer sends a native SOL transfer from the owner of
+// privateKey with the fee paid by the owner of feePayerKey. The fee payer's
+// balance is checked against the fee before anything is signed.
+func (s *SolanaChain) SendTransactionWithFeePayer(ctx context.Context, from, to, amount, token, privateKey, feePayerKey string) (string, error) {
+	if s.rpcManager == nil {
+		return "", errors.New("solana RPC is not configured")
+	}
+	token = strings.TrimSpace(token)
+	if token != "" && !strings.EqualFold(token, "SOL") {
+		return "", errors.New("a separate fee payer is only supported for native SOL transfers")
+	}
+	owner, err := solana.PrivateKeyFromBase58(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid private key: %w", err)
+	}
+	if owner.PublicKey().String() != from {
+		return "", errors.New("private key does not match the from address")
+	}
+	feePayer, err := solana.PrivateKeyFromBase58(feePayerKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid fee payer key: %w", err)
+	}
+	if from == to {
+		return "", errors.New("cannot send to the same address")
+	}
+
+	tx, err := s.buildSponsoredTransfer(ctx, owner, feePayer, to, amount)
+	if err != nil {
+		return "", err
+	}
+	fee := uint64(len(tx.Signatures)) * solanaSignatureFeeLamports
+	balance, err := s.rpcManager.GetBalance(ctx, feePayer.PublicKey().String(), s.commitment(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to get fee payer balance: %w", err)
+	}
+	if balance.Value < fee {
+		return "", &FeePayerUnfundedError{Address: feePayer.PublicKey().String(), Balance: balance.Value, Required: fee}
+	}
+
+	encoded, err := tx.ToBase64()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	signature, err := s.rpcManager.SendTransaction(ctx, encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	s.logger.Info("Sent Solana transfer with a separate fee payer",
+		zap.String("from", from),
+		zap.String("to", to),
+		zap.String("fee_payer", feePayer.PublicKey().String()),
+		zap.Uint64("fee_lamports", fee),
+		zap.String("signature", signature))
+
+	return signature, nil
+}
+
+// buildSponsoredTransfer creates a transfer from owner whose fee is paid by
+// feePayer, signed by both
+func (s *SolanaChain) buildSponsoredTransfer(ctx context.Context, owner, feePayer solana.PrivateKey, to, amount string) (*solana.Transaction, error) {
+	transfer, err := buildTransferInstruction(owner.PublicKey(), to, amount)
+	if err != nil {
+		return nil, err
+	}
+	blockhash, err := s.latestBlockhash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{transfer},
+		blockhash,
+		solana.TransactionPayer(feePayer.PublicKey()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transfer: %w", err)
+	}
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		switch {
+		case key.Equals(owner.PublicKey()):
+			return &owner
+		case key.Equals(feePayer.PublicKey()):
+			return &feePayer
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to sign transfer: %w", err)
+	}
+	return tx, nil
+}
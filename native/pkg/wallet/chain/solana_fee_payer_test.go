@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolanaSendTransactionWithFeePayer(t *testing.T) {
+	t.Setenv("RUN_MODE", "")
+	owner := solana.NewWallet().PrivateKey
+	feePayer := solana.NewWallet().PrivateKey
+	recipient := solana.NewWallet().PublicKey()
+	blockhash := solana.HashFromBytes(recipient.Bytes())
+
+	feePayerBalance := uint64(1_000_000)
+	var sent []string
+	chain := newSolanaTestChain(t, map[string]rpcMethod{
+		"getLatestBlockhash": rpcResult(`{"context":{"slot":1},"value":{"blockhash":"` + blockhash.String() + `","lastValidBlockHeight":100}}`),
+		"getBalance": func(call rpcCall) (string, error) {
+			var address string
+			call.param(0, &address)
+			assert.Equal(t, feePayer.PublicKey().String(), address, "only the fee payer's balance is checked")
+			return fmt.Sprintf(`{"context":{"slot":1},"value":%d}`, feePayerBalance), nil
+		},
+		"sendTransaction": func(call rpcCall) (string, error) {
+			var transaction string
+			call.param(0, &transaction)
+			sent = append(sent, transaction)
+			return `"sponsoredSignature"`, nil
+		},
+	})
+
+	signature, err := chain.SendTransactionWithFeePayer(context.Background(), owner.PublicKey().String(), recipient.String(), "0.25", "SOL", owner.String(), feePayer.String())
+	require.NoError(t, err)
+	assert.Equal(t, "sponsoredSignature", signature)
+	require.Len(t, sent, 1)
+
+	tx, err := solana.TransactionFromBase64(sent[0])
+	require.NoError(t, err)
+	// The fee is charged to the first account key, which must be the fee payer
+	assert.Equal(t, feePayer.PublicKey(), tx.Message.AccountKeys[0])
+	assert.Equal(t, uint8(2), tx.Message.Header.NumRequiredSignatures)
+	require.NoError(t, tx.VerifySignatures())
+
+	instruction := tx.Message.Instructions[0]
+	accounts, err := instruction.ResolveInstructionAccounts(&tx.Message)
+	require.NoError(t, err)
+	decoded, err := system.DecodeInstruction(accounts, instruction.Data)
+	require.NoError(t, err)
+	transfer, ok := decoded.Impl.(*system.Transfer)
+	require.True(t, ok)
+	assert.Equal(t, uint64(250_000_000), *transfer.Lamports)
+	assert.Equal(t, owner.PublicKey(), transfer.GetFundingAccount().PublicKey)
+	assert.Equal(t, recipient, transfer.GetRecipientAccount().PublicKey)
+
+	t.Run("unfunded fee payer", func(t *testing.T) {
+		sent = nil
+		feePayerBalance = 9_999
+		_, err := chain.SendTransactionWithFeePayer(context.Background(), owner.PublicKey().String(), recipient.String(), "0.25", "", owner.String(), feePayer.String())
+		require.ErrorIs(t, err, ErrFeePayerUnfunded)
+		var unfunded *FeePayerUnfundedError
+		require.ErrorAs(t, err, &unfunded)
+		assert.Equal(t, uint64(10_000), unfunded.Required)
+		assert.Empty(t, sent)
+	})
+
+	t.Run("token transfer", func(t *testing.T) {
+		_, err := chain.SendTransactionWithFeePayer(context.Background(), owner.PublicKey().String(), recipient.String(), "1", "DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263", owner.String(), feePayer.String())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// stakeWithdrawerOffset is where the withdraw authority sits in a stake account
+const stakeWithdrawerOffset = 44
+
+// LockedBalances reports the SOL of address that cannot be sent: the rent
+// exemption a system account keeps while it stays open, and the stake accounts
+// address can withdraw from. SPL tokens have nothing locked.
+func (s *SolanaChain) LockedBalances(ctx context.Context, address, token string) ([]LockedBalance, error) {
+	token = strings.ToUpper(strings.TrimSpace(token))
+	if token != "" && token != "SOL" {
+		return nil, nil
+	}
+	if s.rpcManager == nil {
+		return nil, errors.New("no Solana RPC endpoint configured")
+	}
+	if _, err := solana.PublicKeyFromBase58(address); err != nil {
+		return nil, fmt.Errorf("invalid Solana address: %w", err)
+	}
+
+	rent, err := s.rpcManager.GetMinimumBalanceForRentExemption(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rent exemption: %w", err)
+	}
+	locked := []LockedBalance{{
+		Kind:        LockedKindRentReserve,
+		Amount:      formatLamports(rent),
+		Description: "rent-exempt minimum; only closing the account releases it",
+	}}
+
+	stakeAccounts, err := s.rpcManager.GetProgramAccounts(ctx, solana.StakeProgramID.String(), ProgramAccountFilter{
+		DataSize: StakeAccountSize,
+		Offset:   stakeWithdrawerOffset,
+		Bytes:    address,
+	}, s.commitment(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stake accounts: %w", err)
+	}
+	if len(stakeAccounts) > 0 {
+		var staked uint64
+		for _, account := range stakeAccounts {
+			staked += account.Account.Lamports
+		}
+		locked = append(locked, LockedBalance{
+			Kind:        LockedKindStaked,
+			Amount:      formatLamports(staked),
+			Description: fmt.Sprintf("held in %d stake account(s); deactivate and withdraw to spend", len(stakeAccounts)),
+			External:    true,
+		})
+	}
+	return locked, nil
+}
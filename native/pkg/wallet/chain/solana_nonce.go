@@ -0,0 +1,238 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"go.uber.org/zap"
+)
+
+// NonceAccountSize is the data length of a system program nonce account
+const NonceAccountSize = 80
+
+// nonceStateInitialized is the state value of a nonce account holding a durable blockhash
+const nonceStateInitialized = 1
+
+var (
+	// ErrDurableNonceDisabled is returned when a durable-nonce operation needs chains.solana.durable_nonce
+	ErrDurableNonceDisabled = errors.New("durable nonce is not enabled")
+	// ErrNonceAccountNotFound is returned when the nonce account does not exist or is not initialized
+	ErrNonceAccountNotFound = errors.New("nonce account not found or not initialized")
+)
+
+// NonceAccountInfo is the decoded state of a durable nonce account
+type NonceAccountInfo struct {
+	Address              string `json:"address"`
+	Authority            string `json:"authority"`
+	Nonce                string `json:"nonce"` // stored blockhash used in place of a recent one
+	LamportsPerSignature uint64 `json:"lamports_per_signature"`
+}
+
+// DurableNonceEnabled reports whether transactions are built against the configured nonce account
+func (s *SolanaChain) DurableNonceEnabled() bool {
+	return s.config != nil && s.config.DurableNonce.Enabled && s.config.DurableNonce.NonceAccount != ""
+}
+
+// GetNonceAccount fetches and decodes the nonce account at address
+func (s *SolanaChain) GetNonceAccount(ctx context.Context, address string) (*NonceAccountInfo, error) {
+	if s.rpcManager == nil {
+		return nil, errors.New("solana RPC is not configured")
+	}
+	if _, err := solana.PublicKeyFromBase58(address); err != nil {
+		return nil, fmt.Errorf("invalid nonce account address: %w", err)
+	}
+
+	result, err := s.rpcManager.GetAccountInfo(ctx, address, s.commitment(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce account: %w", err)
+	}
+	if result.Value == nil || len(result.Value.Data) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNonceAccountNotFound, address)
+	}
+	if result.Value.Owner != solana.SystemProgramID.String() {
+		return nil, fmt.Errorf("%w: %s is owned by %s", ErrNonceAccountNotFound, address, result.Value.Owner)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(result.Value.Data[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce account data: %w", err)
+	}
+	info, err := decodeNonceAccount(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", address, err)
+	}
+	info.Address = address
+	return info, nil
+}
+
+// CreateNonceAccount funds and initializes a new nonce account whose authority
+// is the wallet behind privateKey. It returns the new account's address and the
+// signature of the creating transaction.
+func (s *SolanaChain) CreateNonceAccount(ctx context.Context, privateKey string) (address string, signature string, err error) {
+	if s.rpcManager == nil {
+		return "", "", errors.New("solana RPC is not configured")
+	}
+	payer, err := solana.PrivateKeyFromBase58(privateKey)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid private key: %w", err)
+	}
+	nonceKey, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate nonce account key: %w", err)
+	}
+
+	rent, err := s.rpcManager.GetMinimumBalanceForRentExemption(ctx, NonceAccountSize)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get rent exemption: %w", err)
+	}
+	blockhash, err := s.latestBlockhash(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	tx, err := buildCreateNonceAccountTransaction(payer, nonceKey, rent, blockhash)
+	if err != nil {
+		return "", "", err
+	}
+	encoded, err := tx.ToBase64()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	signature, err = s.rpcManager.SendTransaction(ctx, encoded)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create nonce account: %w", err)
+	}
+
+	s.logger.Info("Created Solana nonce account",
+		zap.String("nonce_account", nonceKey.PublicKey().String()),
+		zap.String("authority", payer.PublicKey().String()),
+		zap.String("signature", signature))
+
+	return nonceKey.PublicKey().String(), signature, nil
+}
+
+// PresignTransfer signs a native SOL transfer against the configured nonce
+// account and returns it base64-encoded. The transaction stays valid until the
+// nonce is advanced, so it can be submitted long after signing.
+func (s *SolanaChain) PresignTransfer(ctx context.Context, to, amount, privateKey string) (string, error) {
+	if !s.DurableNonceEnabled() {
+		return "", ErrDurableNonceDisabled
+	}
+	payer, err := solana.PrivateKeyFromBase58(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid private key: %w", err)
+	}
+	nonce, err := s.GetNonceAccount(ctx, s.config.DurableNonce.NonceAccount)
+	if err != nil {
+		return "", err
+	}
+
+	transfer, err := buildTransferInstruction(payer.PublicKey(), to, amount)
+	if err != nil {
+		return "", err
+	}
+	tx, err := buildDurableNonceTransaction([]solana.Instruction{transfer}, payer.PublicKey(), nonce)
+	if err != nil {
+		return "", err
+	}
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(payer.PublicKey()) {
+			return &payer
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to sign: %w", err)
+	}
+	return tx.ToBase64()
+}
+
+// buildDurableNonceTransaction prepends the AdvanceNonceAccount instruction the
+// runtime requires as the first instruction and uses the stored nonce as the
+// transaction's blockhash. The nonce authority must be the fee payer.
+func buildDurableNonceTransaction(instructions []solana.Instruction, payer solana.PublicKey, nonce *NonceAccountInfo) (*solana.Transaction, error) {
+	nonceAccount, err := solana.PublicKeyFromBase58(nonce.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce account address: %w", err)
+	}
+	if nonce.Authority != payer.String() {
+		return nil, fmt.Errorf("nonce authority %s is not the sending wallet %s", nonce.Authority, payer)
+	}
+	blockhash, err := solana.HashFromBase58(nonce.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce value %q: %w", nonce.Nonce, err)
+	}
+
+	advance := system.NewAdvanceNonceAccountInstruction(nonceAccount, solana.SysVarRecentBlockHashesPubkey, payer).Build()
+	tx, err := solana.NewTransaction(
+		append([]solana.Instruction{advance}, instructions...),
+		blockhash,
+		solana.TransactionPayer(payer),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create durable nonce transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// buildCreateNonceAccountTransaction creates and initializes nonceKey as a
+// nonce account funded by payer, signed by both
+func buildCreateNonceAccountTransaction(payer, nonceKey solana.PrivateKey, rentLamports uint64, blockhash solana.Hash) (*solana.Transaction, error) {
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{
+			system.NewCreateAccountInstruction(rentLamports, NonceAccountSize, solana.SystemProgramID, payer.PublicKey(), nonceKey.PublicKey()).Build(),
+			system.NewInitializeNonceAccountInstruction(payer.PublicKey(), nonceKey.PublicKey(), solana.SysVarRecentBlockHashesPubkey, solana.SysVarRentPubkey).Build(),
+		},
+		blockhash,
+		solana.TransactionPayer(payer.PublicKey()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nonce account transaction: %w", err)
+	}
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		switch {
+		case key.Equals(payer.PublicKey()):
+			return &payer
+		case key.Equals(nonceKey.PublicKey()):
+			return &nonceKey
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to sign nonce account transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// buildTransferInstruction creates a native SOL transfer instruction from payer
+func buildTransferInstruction(payer solana.PublicKey, to, amount string) (solana.Instruction, error) {
+	recipient, err := solana.PublicKeyFromBase58(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to address: %w", err)
+	}
+	lamports, err := parseSOLAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	return system.NewTransferInstruction(lamports, payer, recipient).Build(), nil
+}
+
+// decodeNonceAccount parses the system program nonce account layout:
+// version u32, state u32, authority [32]byte, nonce [32]byte, lamports per signature u64
+func decodeNonceAccount(data []byte) (*NonceAccountInfo, error) {
+	if len(data) < NonceAccountSize {
+		return nil, fmt.Errorf("%w: data is %d bytes, expected %d", ErrNonceAccountNotFound, len(data), NonceAccountSize)
+	}
+	if state := binary.LittleEndian.Uint32(data[4:8]); state != nonceStateInitialized {
+		return nil, fmt.Errorf("%w: state %d", ErrNonceAccountNotFound, state)
+	}
+	return &NonceAccountInfo{
+		Authority:            solana.PublicKeyFromBytes(data[8:40]).String(),
+		Nonce:                solana.HashFromBytes(data[40:72]).String(),
+		LamportsPerSignature: binary.LittleEndian.Uint64(data[72:80]),
+	}, nil
+}
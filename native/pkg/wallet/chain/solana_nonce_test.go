@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+)
+
+// encodeNonceAccount lays out an initialized system program nonce account
+func encodeNonceAccount(authority solana.PublicKey, nonce solana.Hash, lamportsPerSignature uint64) []byte {
+	data := make([]byte, NonceAccountSize)
+	binary.LittleEndian.PutUint32(data[0:4], 1)
+	binary.LittleEndian.PutUint32(data[4:8], nonceStateInitialized)
+	copy(data[8:40], authority[:])
+	copy(data[40:72], nonce[:])
+	binary.LittleEndian.PutUint64(data[72:80], lamportsPerSignature)
+	return data
+}
+
+func TestDecodeNonceAccount(t *testing.T) {
+	authority := solana.NewWallet().PublicKey()
+	nonce := solana.HashFromBytes(authority.Bytes())
+
+	info, err := decodeNonceAccount(encodeNonceAccount(authority, nonce, 5000))
+	if err != nil {
+		t.Fatalf("decodeNonceAccount: %v", err)
+	}
+	if info.Authority != authority.String() || info.Nonce != nonce.String() || info.LamportsPerSignature != 5000 {
+		t.Fatalf("unexpected nonce account %+v", info)
+	}
+
+	uninitialized := make([]byte, NonceAccountSize)
+	if _, err := decodeNonceAccount(uninitialized); !errors.Is(err, ErrNonceAccountNotFound) {
+		t.Fatalf("expected ErrNonceAccountNotFound, got %v", err)
+	}
+	if _, err := decodeNonceAccount(uninitialized[:10]); !errors.Is(err, ErrNonceAccountNotFound) {
+		t.Fatalf("expected ErrNonceAccountNotFound for short data, got %v", err)
+	}
+}
+
+func TestBuildDurableNonceTransaction(t *testing.T) {
+	payer := solana.NewWallet()
+	nonceAccount := solana.NewWallet().PublicKey()
+	recipient := solana.NewWallet().PublicKey()
+	nonce := solana.HashFromBytes(recipient.Bytes())
+
+	info, err := decodeNonceAccount(encodeNonceAccount(payer.PublicKey(), nonce, 5000))
+	if err != nil {
+		t.Fatalf("decodeNonceAccount: %v", err)
+	}
+	info.Address = nonceAccount.String()
+
+	transfer, err := buildTransferInstruction(payer.PublicKey(), recipient.String(), "0.5")
+	if err != nil {
+		t.Fatalf("buildTransferInstruction: %v", err)
+	}
+	tx, err := buildDurableNonceTransaction([]solana.Instruction{transfer}, payer.PublicKey(), info)
+	if err != nil {
+		t.Fatalf("buildDurableNonceTransaction: %v", err)
+	}
+
+	if tx.Message.RecentBlockhash != nonce {
+		t.Fatalf("expected blockhash %s, got %s", nonce, tx.Message.RecentBlockhash)
+	}
+	if len(tx.Message.Instructions) != 2 {
+		t.Fatalf("expected 2 instructions, got %d", len(tx.Message.Instructions))
+	}
+
+	first := tx.Message.Instructions[0]
+	accounts, err := first.ResolveInstructionAccounts(&tx.Message)
+	if err != nil {
+		t.Fatalf("resolve accounts: %v", err)
+	}
+	decoded, err := system.DecodeInstruction(accounts, first.Data)
+	if err != nil {
+		t.Fatalf("decode first instruction: %v", err)
+	}
+	advance, ok := decoded.Impl.(*system.AdvanceNonceAccount)
+	if !ok {
+		t.Fatalf("expected AdvanceNonceAccount first, got %T", decoded.Impl)
+	}
+	if !advance.GetNonceAccount().PublicKey.Equals(nonceAccount) || !advance.GetNonceAuthorityAccount().PublicKey.Equals(payer.PublicKey()) {
+		t.Fatalf("unexpected advance accounts %+v", advance.AccountMetaSlice)
+	}
+
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(payer.PublicKey()) {
+			return &payer.PrivateKey
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if err := tx.VerifySignatures(); err != nil {
+		t.Fatalf("verify signatures: %v", err)
+	}
+}
+
+func TestBuildDurableNonceTransactionRequiresPayerAuthority(t *testing.T) {
+	payer := solana.NewWallet().PublicKey()
+	info := &NonceAccountInfo{
+		Address:   solana.NewWallet().PublicKey().String(),
+		Authority: solana.NewWallet().PublicKey().String(),
+		Nonce:     solana.HashFromBytes(payer.Bytes()).String(),
+	}
+	if _, err := buildDurableNonceTransaction(nil, payer, info); err == nil {
+		t.Fatal("expected error when nonce authority is not the payer")
+	}
+}
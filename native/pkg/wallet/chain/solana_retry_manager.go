@@ -24,8 +24,12 @@ type TransactionParams struct {
 	To               string
 	Amount           uint64
 	TokenMint        string // Optional: for SPL token transfers
+	TokenDecimals    uint8  // decimals of TokenMint; Amount is in its smallest unit
+	CreateRecipientAccount bool // create the recipient's associated token account first
+	PrivateKey       string // base58 ed25519 key of From, which signs and pays
 	Slippage         float64
 	RecentBlockhash  string
+	NonceAccount     string // Optional: durable nonce account whose nonce is RecentBlockhash
 	JitoTipAmount    uint64
 	MaxRetries       int
 	GasStrategy      string
@@ -171,9 +175,11 @@ func (rm *SolanaRetryManager) ExecuteWithRetry(
 
 // updateParamsForRetry updates transaction parameters for retry attempts
 func (rm *SolanaRetryManager) updateParamsForRetry(params *TransactionParams, attempt int, lastError error) error {
-	// Update blockhash for fresh transaction
-	// This would typically involve calling GetLatestBlockhash, but we'll simulate it here
-	params.RecentBlockhash = fmt.Sprintf("UpdatedBlockhash%d_%d", attempt, time.Now().Unix())
+	// Clear the blockhash so the executor fetches a fresh one; a durable nonce
+	// stays valid until it is advanced
+	if params.NonceAccount == "" {
+		params.RecentBlockhash = ""
+	}
 	
 	// Increase slippage for slippage-related errors
 	if rm.isSlippageError(lastError) {
@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
 	"go.uber.org/zap"
 )
 
@@ -66,17 +67,119 @@ type BalanceResult struct {
 	Value uint64 `json:"value"`
 }
 
+// AccountInfoResult represents getAccountInfo response with base64 data.
+// Value is nil when the account does not exist.
+type AccountInfoResult struct {
+	Context struct {
+		Slot uint64 `json:"slot"`
+	} `json:"context"`
+	Value *struct {
+		Lamports   uint64   `json:"lamports"`
+		Owner      string   `json:"owner"`
+		Data       []string `json:"data"` // [payload, encoding]
+		Executable bool     `json:"executable"`
+	} `json:"value"`
+}
+
+// ProgramAccount is one account of a getProgramAccounts response
+type ProgramAccount struct {
+	Pubkey  string `json:"pubkey"`
+	Account struct {
+		Lamports uint64   `json:"lamports"`
+		Owner    string   `json:"owner"`
+		Data     []string `json:"data"` // [payload, encoding]
+	} `json:"account"`
+}
+
+// TokenAccountsResult represents a jsonParsed getTokenAccountsByOwner response
+type TokenAccountsResult struct {
+	Context struct {
+		Slot uint64 `json:"slot"`
+	} `json:"context"`
+	Value []TokenAccount `json:"value"`
+}
+
+// TokenAccount is one token account of a getTokenAccountsByOwner response
+type TokenAccount struct {
+	Pubkey  string `json:"pubkey"`
+	Account struct {
+		Owner string `json:"owner"` // token program
+		Data  struct {
+			Parsed struct {
+				Info struct {
+					Mint        string `json:"mint"`
+					Owner       string `json:"owner"`
+					TokenAmount struct {
+						Amount   string `json:"amount"`
+						Decimals int    `json:"decimals"`
+					} `json:"tokenAmount"`
+				} `json:"info"`
+			} `json:"parsed"`
+		} `json:"data"`
+	} `json:"account"`
+}
+
+// ProgramAccountFilter restricts getProgramAccounts to accounts holding Bytes
+// (base58) at Offset; a zero DataSize leaves the size unchecked
+type ProgramAccountFilter struct {
+	DataSize uint64
+	Offset   uint64
+	Bytes    string
+}
+
+// VoteAccountsResult represents getVoteAccounts response, split into
+// validators that are voting and those that have fallen behind
+type VoteAccountsResult struct {
+	Current    []VoteAccountInfo `json:"current"`
+	Delinquent []VoteAccountInfo `json:"delinquent"`
+}
+
+// VoteAccountInfo is one validator of a getVoteAccounts response
+type VoteAccountInfo struct {
+	VotePubkey     string `json:"votePubkey"`
+	NodePubkey     string `json:"nodePubkey"`
+	ActivatedStake uint64 `json:"activatedStake"`
+	Commission     uint8  `json:"commission"`
+}
+
+// SignatureInfo is one entry of a getSignaturesForAddress response
+type SignatureInfo struct {
+	Signature string `json:"signature"`
+	Slot      uint64 `json:"slot"`
+	Err       any    `json:"err"`
+}
+
+// PrioritizationFee is one slot of a getRecentPrioritizationFees response:
+// the lowest compute unit price, in micro-lamports, paid by a transaction
+// that landed in the slot
+type PrioritizationFee struct {
+	Slot              uint64 `json:"slot"`
+	PrioritizationFee uint64 `json:"prioritizationFee"`
+}
+
+// PerformanceSample is one entry of a getRecentPerformanceSamples response
+type PerformanceSample struct {
+	Slot             uint64 `json:"slot"`
+	NumSlots         uint64 `json:"numSlots"`
+	NumTransactions  uint64 `json:"numTransactions"`
+	SamplePeriodSecs uint64 `json:"samplePeriodSecs"`
+}
+
 // SignatureStatusResult represents signature status response
 type SignatureStatusResult struct {
 	Context struct {
 		Slot uint64 `json:"slot"`
 	} `json:"context"`
-	Value []struct {
-		Slot               uint64  `json:"slot"`
-		Confirmations      *uint64 `json:"confirmations"`
-		ConfirmationStatus string  `json:"confirmationStatus"`
-		Err                *string `json:"err"`
-	} `json:"value"`
+	Value []*SignatureStatus `json:"value"`
+}
+
+// SignatureStatus is the status of one signature; it is nil when the
+// signature is unknown to the node
+type SignatureStatus struct {
+	Slot               uint64  `json:"slot"`
+	Confirmations      *uint64 `json:"confirmations"`
+	ConfirmationStatus string  `json:"confirmationStatus"`
+	Err                any     `json:"err"`
 }
 
 // NewSolanaRPCManager creates a new RPC manager with failover support
@@ -86,9 +189,10 @@ func NewSolanaRPCManager(endpoints []string, logger *zap.Logger) (*SolanaRPCMana
 	}
 	
 	manager := &SolanaRPCManager{
-		httpClient: &http.Client{
-			Timeout: time.Second * 30,
-		},
+		// One retry per endpoint; the manager fails over to the next endpoint after that
+		httpClient: httpclient.New("solana-rpc",
+			httpclient.WithTimeout(time.Second*30),
+			httpclient.WithRetries(1)),
 		endpoints: endpoints,
 		logger:    logger,
 		runMode:   os.Getenv("RUN_MODE"),
@@ -140,6 +244,8 @@ func (rm *SolanaRPCManager) callRPC(ctx context.Context, method string, params a
 			zap.Int("attempt", i+1))
 		
 		if err := rm.doRPCCall(ctx, endpoint, method, params, result); err == nil {
+			DefaultRPCHealth.RecordSuccess("solana")
+			
 			// Success - reset to working endpoint if we had switched
 			rm.mutex.RLock()
 			currentIdx := rm.currentIdx
@@ -243,6 +349,38 @@ func (rm *SolanaRPCManager) executeMockOperation(method string, result any) erro
 		if statusResult, ok := result.(*SignatureStatusResult); ok {
 			*statusResult = *rm.getMockSignatureStatus("mock_signature")
 		}
+	case "getSignaturesForAddress":
+		if signatures, ok := result.(*[]SignatureInfo); ok {
+			*signatures = []SignatureInfo{}
+		}
+	case "getProgramAccounts":
+		if accounts, ok := result.(*[]ProgramAccount); ok {
+			*accounts = []ProgramAccount{}
+		}
+	case "getTokenAccountsByOwner":
+		if accounts, ok := result.(*TokenAccountsResult); ok {
+			*accounts = TokenAccountsResult{Value: []TokenAccount{}}
+		}
+	case "getSlot":
+		if slot, ok := result.(*uint64); ok {
+			*slot = uint64(time.Now().UnixMilli() / 400) // one slot every 400ms
+		}
+	case "getRecentPrioritizationFees":
+		if fees, ok := result.(*[]PrioritizationFee); ok {
+			slot := uint64(time.Now().UnixMilli() / 400)
+			*fees = make([]PrioritizationFee, 0, 20)
+			for i := uint64(0); i < 20; i++ {
+				*fees = append(*fees, PrioritizationFee{Slot: slot - 19 + i, PrioritizationFee: (i % 4) * 500})
+			}
+		}
+	case "getRecentPerformanceSamples":
+		if samples, ok := result.(*[]PerformanceSample); ok {
+			*samples = []PerformanceSample{{Slot: uint64(time.Now().UnixMilli() / 400), NumSlots: 150, NumTransactions: 300000, SamplePeriodSecs: 60}}
+		}
+	case "getMinimumBalanceForRentExemption":
+		if lamports, ok := result.(*uint64); ok {
+			*lamports = 1447680 // rent-exempt minimum for an 80-byte nonce account
+		}
 	default:
 		rm.logger.Debug("Mock operation not implemented for method", zap.String("method", method))
 	}
@@ -275,6 +413,24 @@ func (rm *SolanaRPCManager) SendTransaction(ctx context.Context, transaction str
 	return result, err
 }
 
+// SimulateTransaction simulates a base64-encoded transaction with failover.
+// Signatures are not verified and the blockhash is replaced by a recent one.
+func (rm *SolanaRPCManager) SimulateTransaction(ctx context.Context, transaction string, commitment string) (*SolanaSimulationResult, error) {
+	var result SolanaSimulationResult
+	params := []any{
+		transaction,
+		map[string]any{
+			"encoding":               "base64",
+			"sigVerify":              false,
+			"replaceRecentBlockhash": true,
+			"commitment":             commitment,
+		},
+	}
+	
+	err := rm.callRPC(ctx, "simulateTransaction", params, &result)
+	return &result, err
+}
+
 // GetSignatureStatus gets transaction status with failover
 func (rm *SolanaRPCManager) GetSignatureStatus(ctx context.Context, signature string) (*SignatureStatusResult, error) {
 	var result SignatureStatusResult
@@ -303,6 +459,143 @@ func (rm *SolanaRPCManager) GetBalance(ctx context.Context, address string, comm
 	return &result, err
 }
 
+// GetAccountInfo gets an account's lamports, owner and base64 data with failover
+func (rm *SolanaRPCManager) GetAccountInfo(ctx context.Context, address string, commitment string) (*AccountInfoResult, error) {
+	var result AccountInfoResult
+	params := []any{
+		address,
+		map[string]any{
+			"commitment": commitment,
+			"encoding":   "base64",
+		},
+	}
+	
+	err := rm.callRPC(ctx, "getAccountInfo", params, &result)
+	return &result, err
+}
+
+// GetSlot gets the slot the node has reached at commitment
+func (rm *SolanaRPCManager) GetSlot(ctx context.Context, commitment string) (uint64, error) {
+	var result uint64
+	params := []any{
+		map[string]any{
+			"commitment": commitment,
+		},
+	}
+	
+	err := rm.callRPC(ctx, "getSlot", params, &result)
+	return result, err
+}
+
+// GetRecentPrioritizationFees gets the lowest priority fee paid in each of the
+// recent slots, by transactions writing to all of accounts when any are given
+func (rm *SolanaRPCManager) GetRecentPrioritizationFees(ctx context.Context, accounts []string) ([]PrioritizationFee, error) {
+	var result []PrioritizationFee
+	params := []any{}
+	if len(accounts) > 0 {
+		params = append(params, accounts)
+	}
+	
+	err := rm.callRPC(ctx, "getRecentPrioritizationFees", params, &result)
+	return result, err
+}
+
+// GetRecentPerformanceSamples gets up to limit of the most recent performance samples, newest first
+func (rm *SolanaRPCManager) GetRecentPerformanceSamples(ctx context.Context, limit int) ([]PerformanceSample, error) {
+	var result []PerformanceSample
+	err := rm.callRPC(ctx, "getRecentPerformanceSamples", []any{limit}, &result)
+	return result, err
+}
+
+// GetMinimumBalanceForRentExemption gets the lamports an account of dataSize bytes needs to be rent exempt
+func (rm *SolanaRPCManager) GetMinimumBalanceForRentExemption(ctx context.Context, dataSize uint64) (uint64, error) {
+	var result uint64
+	err := rm.callRPC(ctx, "getMinimumBalanceForRentExemption", []any{dataSize}, &result)
+	return result, err
+}
+
+// GetSignaturesForAddress gets up to limit of the most recent signatures involving address
+func (rm *SolanaRPCManager) GetSignaturesForAddress(ctx context.Context, address string, limit int) ([]SignatureInfo, error) {
+	var result []SignatureInfo
+	params := []any{
+		address,
+		map[string]any{
+			"limit": limit,
+		},
+	}
+	
+	err := rm.callRPC(ctx, "getSignaturesForAddress", params, &result)
+	return result, err
+}
+
+// GetVoteAccounts gets the validator owning voteAccount, or every validator when voteAccount is empty
+func (rm *SolanaRPCManager) GetVoteAccounts(ctx context.Context, voteAccount string, commitment string) (*VoteAccountsResult, error) {
+	var result VoteAccountsResult
+	config := map[string]any{
+		"commitment": commitment,
+	}
+	if voteAccount != "" {
+		config["votePubkey"] = voteAccount
+	}
+	
+	err := rm.callRPC(ctx, "getVoteAccounts", []any{config}, &result)
+	return &result, err
+}
+
+// GetStakeMinimumDelegation gets the smallest stake, in lamports, a stake account can delegate
+func (rm *SolanaRPCManager) GetStakeMinimumDelegation(ctx context.Context, commitment string) (uint64, error) {
+	var result BalanceResult
+	params := []any{
+		map[string]any{
+			"commitment": commitment,
+		},
+	}
+	
+	err := rm.callRPC(ctx, "getStakeMinimumDelegation", params, &result)
+	return result.Value, err
+}
+
+// GetProgramAccounts gets the accounts owned by programID that match filter with failover
+func (rm *SolanaRPCManager) GetProgramAccounts(ctx context.Context, programID string, filter ProgramAccountFilter, commitment string) ([]ProgramAccount, error) {
+	var filters []any
+	if filter.DataSize > 0 {
+		filters = append(filters, map[string]any{"dataSize": filter.DataSize})
+	}
+	if filter.Bytes != "" {
+		filters = append(filters, map[string]any{"memcmp": map[string]any{"offset": filter.Offset, "bytes": filter.Bytes}})
+	}
+	var result []ProgramAccount
+	params := []any{
+		programID,
+		map[string]any{
+			"commitment": commitment,
+			"encoding":   "base64",
+			"filters":    filters,
+		},
+	}
+	
+	err := rm.callRPC(ctx, "getProgramAccounts", params, &result)
+	return result, err
+}
+
+// GetTokenAccountsByOwner gets owner's token accounts for mint, parsed, with failover
+func (rm *SolanaRPCManager) GetTokenAccountsByOwner(ctx context.Context, owner, mint string, commitment string) (*TokenAccountsResult, error) {
+	var result TokenAccountsResult
+	params := []any{
+		owner,
+		map[string]any{
+			"mint": mint,
+		},
+		map[string]any{
+			"commitment": commitment,
+			"encoding":   "jsonParsed",
+		},
+	}
+	
+	err := rm.callRPC(ctx, "getTokenAccountsByOwner", params, &result)
+	return &result, err
+}
+
 // Mock response generators for testing
 func (rm *SolanaRPCManager) getMockBlockhash() *BlockhashResult {
 	return &BlockhashResult{
@@ -315,7 +608,7 @@ func (rm *SolanaRPCManager) getMockBlockhash() *BlockhashResult {
 			Blockhash            string `json:"blockhash"`
 			LastValidBlockHeight uint64 `json:"lastValidBlockHeight"`
 		}{
-			Blockhash:            "MockBlockhash123456789abcdef",
+			Blockhash:            "8XZQ79NRDVjRLu1xsbms2vGi1JFPEEDATk8szHQRNSud", // valid base58 so mock transactions can be built
 			LastValidBlockHeight: 123456790,
 		},
 	}
@@ -333,12 +626,7 @@ func (rm *SolanaRPCManager) getMockSignatureStatus(_ string) *SignatureStatusRes
 		}{
 			Slot: 123456789,
 		},
-		Value: []struct {
-			Slot               uint64  `json:"slot"`
-			Confirmations      *uint64 `json:"confirmations"`
-			ConfirmationStatus string  `json:"confirmationStatus"`
-			Err                *string `json:"err"`
-		}{
+		Value: []*SignatureStatus{
 			{
 				Slot:               123456789,
 				Confirmations:      &confirmations,
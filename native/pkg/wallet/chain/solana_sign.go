@@ -28,18 +28,8 @@ func (s *SolanaChain) SignMessage(privateKeyHex, message string) (string, error)
 	// Convert to ed25519.PrivateKey
 	privateKey := ed25519.PrivateKey(privateKeyBytes)
 
-	// Check if this is a special Solana raw bytes message
-	var messageBytes []byte
-	if strings.HasPrefix(message, "__SOLANA_RAW_BYTES__:") {
-		// Extract the raw bytes after the marker
-		messageBytes = []byte(message[21:]) // Skip "__SOLANA_RAW_BYTES__:"
-	} else {
-		// Otherwise, treat it as a regular string
-		messageBytes = []byte(message)
-	}
-
 	// Sign the message using Ed25519 (64-byte signature, no v value)
-	signature := ed25519.Sign(privateKey, messageBytes)
+	signature := ed25519.Sign(privateKey, solanaMessageBytes(message))
 
 	// Ensure the signature is exactly 64 bytes
 	if len(signature) != 64 {
@@ -55,4 +45,31 @@ func (s *SolanaChain) SignMessage(privateKeyHex, message string) (string, error)
 		zap.Int("signature_length", len(signature)))
 	
 	return signatureBase58, nil
-}
\ No newline at end of file
+}
+
+// VerifySolanaMessage reports whether signature, a base58 Ed25519 signature
+// as returned by SignMessage, was made over message by the key of address.
+// Ed25519 cannot recover a signer, so the address must be given.
+func VerifySolanaMessage(address, message, signature string) (bool, error) {
+	publicKey, err := base58.Decode(address)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return false, fmt.Errorf("invalid Solana address: %s", address)
+	}
+	signatureBytes, err := base58.Decode(signature)
+	if err != nil {
+		return false, fmt.Errorf("failed to decode signature: %w", err)
+	}
+	if len(signatureBytes) != ed25519.SignatureSize {
+		return false, fmt.Errorf("invalid signature length: expected %d bytes, got %d", ed25519.SignatureSize, len(signatureBytes))
+	}
+	return ed25519.Verify(ed25519.PublicKey(publicKey), solanaMessageBytes(message), signatureBytes), nil
+}
+
+// solanaMessageBytes strips the "__SOLANA_RAW_BYTES__:" marker that wraps raw
+// byte messages and takes anything else as text
+func solanaMessageBytes(message string) []byte {
+	if strings.HasPrefix(message, "__SOLANA_RAW_BYTES__:") {
+		return []byte(message[21:])
+	}
+	return []byte(message)
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// isSolanaPublicKey reports whether value is a base58 encoded 32-byte public
+// key, such as a wallet address or a token mint
+func isSolanaPublicKey(value string) bool {
+	_, err := solana.PublicKeyFromBase58(value)
+	return err == nil
+}
+
+// getRPCTokenBalance reads the balance address holds of the SPL token mint
+// through the RPC manager. Balances of all of the owner's token accounts for
+// the mint are added up; an owner without any holds "0".
+func (s *SolanaChain) getRPCTokenBalance(ctx context.Context, address, mint string) (string, error) {
+	if s.rpcManager == nil {
+		return "", errors.New("no Solana RPC endpoint configured")
+	}
+
+	result, err := s.rpcManager.GetTokenAccountsByOwner(ctx, address, mint, s.commitment(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	total := new(big.Int)
+	decimals := 0
+	for _, account := range result.Value {
+		info := account.Account.Data.Parsed.Info
+		if info.Mint != "" && info.Mint != mint {
+			continue
+		}
+		units, ok := new(big.Int).SetString(info.TokenAmount.Amount, 10)
+		if !ok {
+			return "", fmt.Errorf("invalid amount %q in token account %s", info.TokenAmount.Amount, account.Pubkey)
+		}
+		total.Add(total, units)
+		decimals = info.TokenAmount.Decimals
+	}
+	return formatUnits(total, decimals), nil
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSPLOwner = "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+
+// tokenAccountJSON renders a jsonParsed token account holding amount base units of mint
+func tokenAccountJSON(pubkey, mint, amount string, decimals int) string {
+	return `{"pubkey":"` + pubkey + `","account":{"lamports":2039280,"owner":"` + SPLTokenProgramID + `","data":{"program":"spl-token","parsed":{"type":"account","info":{` +
+		`"mint":"` + mint + `","owner":"` + testSPLOwner + `","state":"initialized","tokenAmount":{"amount":"` + amount + `","decimals":` + strconv.Itoa(decimals) + `}}}}}}`
+}
+
+// newSPLBalanceTestChain answers getTokenAccountsByOwner with accounts
+func newSPLBalanceTestChain(t *testing.T, accounts ...string) *SolanaChain {
+	t.Helper()
+	chain := newSolanaTestChain(t, map[string]rpcMethod{
+		"getTokenAccountsByOwner": func(call rpcCall) (string, error) {
+			params := call.params()
+			assert.Equal(t, testSPLOwner, params[0])
+			assert.Equal(t, map[string]any{"mint": testUSDCMint}, params[1])
+			assert.Equal(t, "jsonParsed", params[2].(map[string]any)["encoding"])
+			return `{"context":{"slot":1},"value":[` + strings.Join(accounts, ",") + `]}`, nil
+		},
+	})
+	chain.SetBalanceConfig(config.BalanceConfig{Sources: config.BalanceSourcesRPCOnly, FailOnUnavailable: true})
+	return chain
+}
+
+func TestSolanaChainGetSPLTokenBalance(t *testing.T) {
+	t.Setenv("RUN_MODE", "")
+
+	t.Run("sums every token account of the mint", func(t *testing.T) {
+		chain := newSPLBalanceTestChain(t,
+			tokenAccountJSON("4fYNw3dojWmQ4dXtSGE9epjRGy9pFSx62YypT7avPYvA", testUSDCMint, "1500000", 6),
+			tokenAccountJSON("7UX2i7SucgLMQcfZ75s3VXmZZY4YRUyJN9X1RgfMoDUi", testUSDCMint, "250", 6),
+		)
+		balance, err := chain.GetBalance(context.Background(), testSPLOwner, testUSDCMint)
+		require.NoError(t, err)
+		assert.Equal(t, "1.50025", balance)
+	})
+
+	t.Run("no token account", func(t *testing.T) {
+		chain := newSPLBalanceTestChain(t)
+		balance, err := chain.GetBalance(context.Background(), testSPLOwner, testUSDCMint)
+		require.NoError(t, err)
+		assert.Equal(t, "0", balance)
+	})
+
+	t.Run("unknown symbol", func(t *testing.T) {
+		chain := newSPLBalanceTestChain(t)
+		_, err := chain.GetBalance(context.Background(), testSPLOwner, "BONK")
+		assert.ErrorContains(t, err, "unsupported token: BONK")
+	})
+}
@@ -0,0 +1,457 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/chains/solana/broadcast"
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/stake"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"go.uber.org/zap"
+)
+
+// StakeAccountSize is the data length of a stake program account
+const StakeAccountSize = 200
+
+// Stake account states as stored in the first four bytes of the account
+const (
+	stakeStateUninitialized = 0
+	stakeStateInitialized   = 1
+	stakeStateDelegated     = 2
+)
+
+var (
+	// ErrInvalidVoteAccount is returned when a delegation targets an address
+	// that is not the vote account of a current, non-delinquent validator
+	ErrInvalidVoteAccount = errors.New("not the vote account of an active validator")
+	// ErrStakeAccountNotFound is returned when a stake account does not exist or is not owned by the stake program
+	ErrStakeAccountNotFound = errors.New("stake account not found")
+	// ErrStakeAuthorityMismatch is returned when the signing wallet is not the stake or withdraw authority of a stake account
+	ErrStakeAuthorityMismatch = errors.New("wallet is not the authority of the stake account")
+)
+
+// StakeManager is implemented by chains that can delegate native stake to validators
+type StakeManager interface {
+	// DelegateStake creates a stake account funded with amount SOL from the
+	// wallet of privateKey and delegates it to voteAccount
+	DelegateStake(ctx context.Context, voteAccount, amount, privateKey string) (*StakeResult, error)
+	// DeactivateStake starts the cooldown of a delegated stake account
+	DeactivateStake(ctx context.Context, stakeAccount, privateKey string) (*StakeResult, error)
+	// WithdrawStake moves amount SOL ("max" for everything) out of an inactive stake account to to
+	WithdrawStake(ctx context.Context, stakeAccount, to, amount, privateKey string) (*StakeResult, error)
+}
+
+// StakeResult describes a broadcast staking transaction
+type StakeResult struct {
+	Signature    string `json:"signature"`
+	Channel      string `json:"channel"`
+	StakeAccount string `json:"stake_account"`
+	VoteAccount  string `json:"vote_account,omitempty"`
+	Recipient    string `json:"recipient,omitempty"`
+	Lamports     uint64 `json:"lamports"`
+	Amount       string `json:"amount"` // Lamports in SOL
+}
+
+// StakeAccountInfo is the decoded state of a stake program account
+type StakeAccountInfo struct {
+	Address           string `json:"address"`
+	Lamports          uint64 `json:"lamports"`
+	State             uint32 `json:"state"`
+	RentExemptReserve uint64 `json:"rent_exempt_reserve"`
+	Staker            string `json:"staker"`
+	Withdrawer        string `json:"withdrawer"`
+	VoteAccount       string `json:"vote_account,omitempty"`
+	DelegatedLamports uint64 `json:"delegated_lamports,omitempty"`
+	ActivationEpoch   uint64 `json:"activation_epoch,omitempty"`
+	DeactivationEpoch uint64 `json:"deactivation_epoch,omitempty"`
+}
+
+// Delegated reports whether the account holds a delegation, active or cooling down
+func (info *StakeAccountInfo) Delegated() bool {
+	return info.State == stakeStateDelegated
+}
+
+// Deactivating reports whether a delegation has been deactivated
+func (info *StakeAccountInfo) Deactivating() bool {
+	return info.Delegated() && info.DeactivationEpoch != math.MaxUint64
+}
+
+// DelegateStake creates a new stake account whose staker and withdrawer are
+// the wallet, funds it with amount SOL plus its rent exemption and delegates
+// it to voteAccount, all in one transaction. "max" stakes everything above the
+// native reserve, the rent exemption and the fee.
+func (s *SolanaChain) DelegateStake(ctx context.Context, voteAccount, amount, privateKey string) (*StakeResult, error) {
+	if s.rpcManager == nil {
+		return nil, errors.New("solana RPC is not configured")
+	}
+	owner, err := solana.PrivateKeyFromBase58(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	vote, err := solana.PublicKeyFromBase58(voteAccount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not a valid address", ErrInvalidVoteAccount, voteAccount)
+	}
+	if err := s.checkVoteAccount(ctx, voteAccount); err != nil {
+		return nil, err
+	}
+
+	rent, err := s.rpcManager.GetMinimumBalanceForRentExemption(ctx, StakeAccountSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rent exemption: %w", err)
+	}
+	lamports, err := s.stakeableLamports(ctx, owner.PublicKey().String(), amount, rent)
+	if err != nil {
+		return nil, err
+	}
+	if minimum, err := s.rpcManager.GetStakeMinimumDelegation(ctx, s.commitment(ctx)); err != nil {
+		s.logger.Warn("Minimum stake delegation unavailable, not enforced", zap.Error(err))
+	} else if lamports < minimum {
+		return nil, fmt.Errorf("stake of %d lamports is below the minimum delegation of %d lamports", lamports, minimum)
+	}
+
+	stakeKey, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate stake account key: %w", err)
+	}
+	blockhash, err := s.latestBlockhash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := buildDelegateStakeTransaction(owner, stakeKey, vote, lamports, rent, blockhash)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.broadcastSignedTransaction(ctx, tx, owner.PublicKey().String(), voteAccount, lamports, "delegate_stake")
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Delegated Solana stake",
+		zap.String("stake_account", stakeKey.PublicKey().String()),
+		zap.String("vote_account", voteAccount),
+		zap.Uint64("lamports", lamports),
+		zap.String("signature", result.Signature))
+
+	return &StakeResult{
+		Signature:    result.Signature,
+		Channel:      result.Channel,
+		StakeAccount: stakeKey.PublicKey().String(),
+		VoteAccount:  voteAccount,
+		Lamports:     lamports,
+		Amount:       formatLamports(lamports),
+	}, nil
+}
+
+// DeactivateStake deactivates a delegated stake account staked by the wallet.
+// The stake stops earning at the end of the epoch and can be withdrawn once
+// its cooldown is over.
+func (s *SolanaChain) DeactivateStake(ctx context.Context, stakeAccount, privateKey string) (*StakeResult, error) {
+	owner, err := solana.PrivateKeyFromBase58(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	info, err := s.GetStakeAccount(ctx, stakeAccount)
+	if err != nil {
+		return nil, err
+	}
+	if info.Staker != owner.PublicKey().String() {
+		return nil, fmt.Errorf("%w: %s stakes %s", ErrStakeAuthorityMismatch, info.Staker, stakeAccount)
+	}
+	if !info.Delegated() {
+		return nil, fmt.Errorf("stake account %s is not delegated", stakeAccount)
+	}
+	if info.Deactivating() {
+		return nil, fmt.Errorf("stake account %s was already deactivated in epoch %d", stakeAccount, info.DeactivationEpoch)
+	}
+
+	blockhash, err := s.latestBlockhash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	account := solana.MustPublicKeyFromBase58(info.Address)
+	tx, err := buildSignedStakeTransaction(owner, blockhash,
+		stake.NewDeactivateInstruction(account, owner.PublicKey()).Build())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.broadcastSignedTransaction(ctx, tx, owner.PublicKey().String(), stakeAccount, 0, "deactivate_stake")
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Deactivated Solana stake",
+		zap.String("stake_account", stakeAccount),
+		zap.String("vote_account", info.VoteAccount),
+		zap.String("signature", result.Signature))
+
+	return &StakeResult{
+		Signature:    result.Signature,
+		Channel:      result.Channel,
+		StakeAccount: stakeAccount,
+		VoteAccount:  info.VoteAccount,
+		Lamports:     info.DelegatedLamports,
+		Amount:       formatLamports(info.DelegatedLamports),
+	}, nil
+}
+
+// WithdrawStake withdraws amount SOL from a stake account the wallet is the
+// withdrawer of. "max" empties the account, which closes it. Stake that is
+// still delegated must be deactivated first.
+func (s *SolanaChain) WithdrawStake(ctx context.Context, stakeAccount, to, amount, privateKey string) (*StakeResult, error) {
+	owner, err := solana.PrivateKeyFromBase58(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	if to == "" {
+		to = owner.PublicKey().String()
+	}
+	recipient, err := solana.PublicKeyFromBase58(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to address: %w", err)
+	}
+	info, err := s.GetStakeAccount(ctx, stakeAccount)
+	if err != nil {
+		return nil, err
+	}
+	if info.Withdrawer != owner.PublicKey().String() {
+		return nil, fmt.Errorf("%w: %s withdraws from %s", ErrStakeAuthorityMismatch, info.Withdrawer, stakeAccount)
+	}
+	if info.Delegated() && !info.Deactivating() {
+		return nil, fmt.Errorf("stake account %s is still delegated to %s; deactivate it and wait for the cooldown first", stakeAccount, info.VoteAccount)
+	}
+
+	lamports := info.Lamports
+	if !IsMaxAmount(amount) {
+		if lamports, err = parseSOLAmount(amount); err != nil {
+			return nil, err
+		}
+		if lamports > info.Lamports {
+			return nil, fmt.Errorf("cannot withdraw %d lamports from a stake account holding %d", lamports, info.Lamports)
+		}
+	}
+
+	blockhash, err := s.latestBlockhash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	account := solana.MustPublicKeyFromBase58(info.Address)
+	tx, err := buildSignedStakeTransaction(owner, blockhash,
+		stake.NewWithdrawInstruction(lamports, account, recipient, owner.PublicKey()).Build())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.broadcastSignedTransaction(ctx, tx, stakeAccount, to, lamports, "withdraw_stake")
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Withdrew Solana stake",
+		zap.String("stake_account", stakeAccount),
+		zap.String("to", to),
+		zap.Uint64("lamports", lamports),
+		zap.String("signature", result.Signature))
+
+	return &StakeResult{
+		Signature:    result.Signature,
+		Channel:      result.Channel,
+		StakeAccount: stakeAccount,
+		VoteAccount:  info.VoteAccount,
+		Recipient:    to,
+		Lamports:     lamports,
+		Amount:       formatLamports(lamports),
+	}, nil
+}
+
+// GetStakeAccount fetches and decodes the stake account at address
+func (s *SolanaChain) GetStakeAccount(ctx context.Context, address string) (*StakeAccountInfo, error) {
+	if s.rpcManager == nil {
+		return nil, errors.New("solana RPC is not configured")
+	}
+	if _, err := solana.PublicKeyFromBase58(address); err != nil {
+		return nil, fmt.Errorf("invalid stake account address: %w", err)
+	}
+
+	result, err := s.rpcManager.GetAccountInfo(ctx, address, s.commitment(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stake account: %w", err)
+	}
+	if result.Value == nil || len(result.Value.Data) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrStakeAccountNotFound, address)
+	}
+	if result.Value.Owner != solana.StakeProgramID.String() {
+		return nil, fmt.Errorf("%w: %s is owned by %s", ErrStakeAccountNotFound, address, result.Value.Owner)
+	}
+	data, err := base64.StdEncoding.DecodeString(result.Value.Data[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode stake account data: %w", err)
+	}
+	info, err := decodeStakeAccount(data)
+	if err != nil {
+		return nil, err
+	}
+	info.Address = address
+	info.Lamports = result.Value.Lamports
+	return info, nil
+}
+
+// checkVoteAccount accepts only the vote account of a validator that is
+// currently voting; delegating to a delinquent one earns nothing
+func (s *SolanaChain) checkVoteAccount(ctx context.Context, voteAccount string) error {
+	accounts, err := s.rpcManager.GetVoteAccounts(ctx, voteAccount, s.commitment(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to get vote accounts: %w", err)
+	}
+	for _, account := range accounts.Current {
+		if account.VotePubkey == voteAccount {
+			return nil
+		}
+	}
+	for _, account := range accounts.Delinquent {
+		if account.VotePubkey == voteAccount {
+			return fmt.Errorf("%w: validator %s is delinquent", ErrInvalidVoteAccount, voteAccount)
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidVoteAccount, voteAccount)
+}
+
+// stakeableLamports resolves the amount to delegate from owner's balance,
+// which must also cover the stake account's rent exemption, the fee of the
+// two signatures and the native reserve
+func (s *SolanaChain) stakeableLamports(ctx context.Context, owner, amount string, rent uint64) (uint64, error) {
+	balance, err := s.rpcManager.GetBalance(ctx, owner, s.commitment(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get balance: %w", err)
+	}
+	reserve := uint64(math.Round(s.NativeReserve() * float64(solana.LAMPORTS_PER_SOL)))
+	overhead := rent + 2*solanaSignatureFeeLamports + reserve
+
+	if IsMaxAmount(amount) {
+		if balance.Value <= overhead {
+			return 0, fmt.Errorf("%w: balance of %d lamports does not cover the stake account rent, fee and reserve", ErrNativeReserveViolation, balance.Value)
+		}
+		return balance.Value - overhead, nil
+	}
+	lamports, err := parseSOLAmount(amount)
+	if err != nil {
+		return 0, err
+	}
+	if lamports > balance.Value || balance.Value-lamports < overhead {
+		return 0, fmt.Errorf("%w: staking %s SOL of %s SOL leaves less than the %s SOL needed for rent, fee and reserve",
+			ErrNativeReserveViolation, amount, formatLamports(balance.Value), formatLamports(overhead))
+	}
+	return lamports, nil
+}
+
+// broadcastSignedTransaction submits a signed transaction through the broadcast
+// manager, tagged with operation
+func (s *SolanaChain) broadcastSignedTransaction(ctx context.Context, tx *solana.Transaction, from, to string, lamports uint64, operation string) (*broadcast.BroadcastResult, error) {
+	if s.broadcastManager == nil {
+		return nil, errors.New("solana broadcast is not configured")
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	result, err := s.broadcastManager.BroadcastWithFallback(ctx, &broadcast.BroadcastParams{
+		SignedTransaction:   raw,
+		TransactionBase64:   base64.StdEncoding.EncodeToString(raw),
+		Signature:           tx.Signatures[0].String(),
+		From:                from,
+		To:                  to,
+		Amount:              lamports,
+		Token:               "SOL",
+		MaxRetries:          3,
+		PreflightCommitment: s.commitment(ctx),
+		Timeout:             30 * time.Second,
+		Metadata: map[string]any{
+			"operation": operation,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to broadcast transaction: %w", err)
+	}
+	return result, nil
+}
+
+// buildDelegateStakeTransaction creates stakeKey as a stake account holding
+// lamports above its rent exemption, initializes it with owner as staker and
+// withdrawer and delegates it to vote, signed by both keys
+func buildDelegateStakeTransaction(owner, stakeKey solana.PrivateKey, vote solana.PublicKey, lamports, rent uint64, blockhash solana.Hash) (*solana.Transaction, error) {
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{
+			system.NewCreateAccountInstruction(lamports+rent, StakeAccountSize, solana.StakeProgramID, owner.PublicKey(), stakeKey.PublicKey()).Build(),
+			stake.NewInitializeInstruction(owner.PublicKey(), owner.PublicKey(), stakeKey.PublicKey()).Build(),
+			stake.NewDelegateStakeInstruction(vote, owner.PublicKey(), stakeKey.PublicKey()).Build(),
+		},
+		blockhash,
+		solana.TransactionPayer(owner.PublicKey()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stake transaction: %w", err)
+	}
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		switch {
+		case key.Equals(owner.PublicKey()):
+			return &owner
+		case key.Equals(stakeKey.PublicKey()):
+			return &stakeKey
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to sign stake transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// buildSignedStakeTransaction wraps a single stake instruction authorized and paid for by owner
+func buildSignedStakeTransaction(owner solana.PrivateKey, blockhash solana.Hash, instruction solana.Instruction) (*solana.Transaction, error) {
+	tx, err := solana.NewTransaction([]solana.Instruction{instruction}, blockhash, solana.TransactionPayer(owner.PublicKey()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stake transaction: %w", err)
+	}
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(owner.PublicKey()) {
+			return &owner
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to sign stake transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// decodeStakeAccount parses the stake program account layout: state u32, then
+// for initialized and delegated accounts rent exempt reserve u64, staker and
+// withdrawer [32]byte, lockup (48 bytes), and for delegated accounts voter
+// [32]byte, stake u64, activation epoch u64 and deactivation epoch u64
+func decodeStakeAccount(data []byte) (*StakeAccountInfo, error) {
+	if len(data) < StakeAccountSize {
+		return nil, fmt.Errorf("%w: data is %d bytes, expected %d", ErrStakeAccountNotFound, len(data), StakeAccountSize)
+	}
+	info := &StakeAccountInfo{State: binary.LittleEndian.Uint32(data[0:4])}
+	switch info.State {
+	case stakeStateInitialized, stakeStateDelegated:
+	case stakeStateUninitialized:
+		return nil, fmt.Errorf("%w: account is not initialized", ErrStakeAccountNotFound)
+	default:
+		return nil, fmt.Errorf("%w: unsupported state %d", ErrStakeAccountNotFound, info.State)
+	}
+	info.RentExemptReserve = binary.LittleEndian.Uint64(data[4:12])
+	info.Staker = solana.PublicKeyFromBytes(data[12:44]).String()
+	info.Withdrawer = solana.PublicKeyFromBytes(data[44:76]).String()
+	if info.State == stakeStateDelegated {
+		info.VoteAccount = solana.PublicKeyFromBytes(data[124:156]).String()
+		info.DelegatedLamports = binary.LittleEndian.Uint64(data[156:164])
+		info.ActivationEpoch = binary.LittleEndian.Uint64(data[164:172])
+		info.DeactivationEpoch = binary.LittleEndian.Uint64(data[172:180])
+	}
+	return info, nil
+}
@@ -0,0 +1,349 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/chains/solana/broadcast"
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/stake"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testStakeRent = 2_282_880
+
+// recordingChannel is a broadcast channel that keeps what it is given
+type recordingChannel struct {
+	sent []*broadcast.BroadcastParams
+}
+
+func (c *recordingChannel) GetName() string  { return "recording" }
+func (c *recordingChannel) IsEnabled() bool  { return true }
+func (c *recordingChannel) GetPriority() int { return 0 }
+func (c *recordingChannel) Close() error     { return nil }
+func (c *recordingChannel) BroadcastTransaction(_ context.Context, params *broadcast.BroadcastParams) (*broadcast.BroadcastResult, error) {
+	c.sent = append(c.sent, params)
+	return &broadcast.BroadcastResult{Success: true, Signature: params.Signature, Channel: c.GetName(), Status: "pending"}, nil
+}
+func (c *recordingChannel) GetTransactionStatus(context.Context, string) (*broadcast.TransactionStatus, error) {
+	return nil, nil
+}
+
+// encodeStakeAccount lays out a stake account; a zero voter leaves it initialized but undelegated
+func encodeStakeAccount(staker, withdrawer, voter solana.PublicKey, stakeLamports, deactivationEpoch uint64) []byte {
+	data := make([]byte, StakeAccountSize)
+	binary.LittleEndian.PutUint32(data[0:4], stakeStateInitialized)
+	binary.LittleEndian.PutUint64(data[4:12], testStakeRent)
+	copy(data[12:44], staker[:])
+	copy(data[44:76], withdrawer[:])
+	if !voter.IsZero() {
+		binary.LittleEndian.PutUint32(data[0:4], stakeStateDelegated)
+		copy(data[124:156], voter[:])
+		binary.LittleEndian.PutUint64(data[156:164], stakeLamports)
+		binary.LittleEndian.PutUint64(data[164:172], 500)
+		binary.LittleEndian.PutUint64(data[172:180], deactivationEpoch)
+	}
+	return data
+}
+
+// stakeTestNode fakes the RPC methods staking uses
+type stakeTestNode struct {
+	balance      uint64
+	current      []string
+	delinquent   []string
+	stakeAccount []byte
+	stakeBalance uint64
+}
+
+func newStakeTestChain(t *testing.T, node *stakeTestNode, reserve float64) (*SolanaChain, *recordingChannel) {
+	t.Helper()
+	t.Setenv("RUN_MODE", "")
+	blockhash := solana.HashFromBytes(solana.NewWallet().PublicKey().Bytes())
+	voteAccounts := func(addresses []string) []VoteAccountInfo {
+		accounts := make([]VoteAccountInfo, 0, len(addresses))
+		for _, address := range addresses {
+			accounts = append(accounts, VoteAccountInfo{VotePubkey: address})
+		}
+		return accounts
+	}
+	stakeAccount := func() map[string]any {
+		return map[string]any{
+			"lamports": node.stakeBalance,
+			"owner":    solana.StakeProgramID.String(),
+			"data":     []string{base64.StdEncoding.EncodeToString(node.stakeAccount), "base64"},
+		}
+	}
+	chain := newSolanaTestChain(t, map[string]rpcMethod{
+		"getLatestBlockhash": func(rpcCall) (string, error) {
+			return rpcJSON(map[string]any{"context": map[string]any{"slot": 1}, "value": map[string]any{"blockhash": blockhash.String(), "lastValidBlockHeight": 100}})
+		},
+		"getBalance": func(rpcCall) (string, error) {
+			return rpcJSON(map[string]any{"context": map[string]any{"slot": 1}, "value": node.balance})
+		},
+		"getMinimumBalanceForRentExemption": rpcResult(strconv.Itoa(testStakeRent)),
+		"getStakeMinimumDelegation":         rpcResult(`{"context":{"slot":1},"value":1000000}`),
+		"getVoteAccounts": func(rpcCall) (string, error) {
+			return rpcJSON(VoteAccountsResult{Current: voteAccounts(node.current), Delinquent: voteAccounts(node.delinquent)})
+		},
+		"getProgramAccounts": func(rpcCall) (string, error) {
+			accounts := []map[string]any{}
+			if node.stakeAccount != nil {
+				accounts = append(accounts, map[string]any{"pubkey": solana.NewWallet().PublicKey().String(), "account": stakeAccount()})
+			}
+			return rpcJSON(accounts)
+		},
+		"getAccountInfo": func(rpcCall) (string, error) {
+			if node.stakeAccount == nil {
+				return `{"context":{"slot":1},"value":null}`, nil
+			}
+			return rpcJSON(map[string]any{"context": map[string]any{"slot": 1}, "value": stakeAccount()})
+		},
+	})
+	chain.config.ReserveSOL = reserve
+
+	channel := &recordingChannel{}
+	chain.broadcastManager = broadcast.NewBroadcastManager(&config.BroadcastConfig{})
+	chain.broadcastManager.RegisterChannel(channel)
+	return chain, channel
+}
+
+// decodeStakeInstructions verifies the signatures of a broadcast transaction and decodes its instructions
+func decodeStakeInstructions(t *testing.T, params *broadcast.BroadcastParams) (*solana.Transaction, []any) {
+	t.Helper()
+	tx, err := solana.TransactionFromBase64(params.TransactionBase64)
+	require.NoError(t, err)
+	require.NoError(t, tx.VerifySignatures())
+	assert.Equal(t, tx.Signatures[0].String(), params.Signature)
+
+	decoded := make([]any, 0, len(tx.Message.Instructions))
+	for _, instruction := range tx.Message.Instructions {
+		accounts, err := instruction.ResolveInstructionAccounts(&tx.Message)
+		require.NoError(t, err)
+		program, err := tx.Message.Program(instruction.ProgramIDIndex)
+		require.NoError(t, err)
+		switch program {
+		case solana.SystemProgramID:
+			inst, err := system.DecodeInstruction(accounts, instruction.Data)
+			require.NoError(t, err)
+			decoded = append(decoded, inst.Impl)
+		case solana.StakeProgramID:
+			inst, err := stake.DecodeInstruction(accounts, instruction.Data)
+			require.NoError(t, err)
+			decoded = append(decoded, inst.Impl)
+		default:
+			t.Fatalf("unexpected program %s", program)
+		}
+	}
+	return tx, decoded
+}
+
+func TestSolanaDelegateStake(t *testing.T) {
+	owner := solana.NewWallet().PrivateKey
+	vote := solana.NewWallet().PublicKey()
+	node := &stakeTestNode{balance: 3 * solana.LAMPORTS_PER_SOL, current: []string{vote.String()}}
+	chain, channel := newStakeTestChain(t, node, 0.01)
+
+	result, err := chain.DelegateStake(context.Background(), vote.String(), "1.5", owner.String())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1_500_000_000), result.Lamports)
+	assert.Equal(t, vote.String(), result.VoteAccount)
+	assert.Equal(t, "recording", result.Channel)
+	require.Len(t, channel.sent, 1)
+	assert.Equal(t, "delegate_stake", channel.sent[0].Metadata["operation"])
+
+	tx, instructions := decodeStakeInstructions(t, channel.sent[0])
+	assert.Equal(t, owner.PublicKey(), tx.Message.AccountKeys[0])
+	assert.Equal(t, uint8(2), tx.Message.Header.NumRequiredSignatures)
+	require.Len(t, instructions, 3)
+
+	create, ok := instructions[0].(*system.CreateAccount)
+	require.True(t, ok)
+	assert.Equal(t, uint64(1_500_000_000+testStakeRent), *create.Lamports)
+	assert.Equal(t, uint64(StakeAccountSize), *create.Space)
+	assert.Equal(t, solana.StakeProgramID, *create.Owner)
+	assert.Equal(t, result.StakeAccount, create.GetNewAccount().PublicKey.String())
+
+	initialize, ok := instructions[1].(*stake.Initialize)
+	require.True(t, ok)
+	assert.Equal(t, owner.PublicKey(), *initialize.Authorized.Staker)
+	assert.Equal(t, owner.PublicKey(), *initialize.Authorized.Withdrawer)
+
+	delegate, ok := instructions[2].(*stake.DelegateStake)
+	require.True(t, ok)
+	assert.Equal(t, vote, delegate.AccountMetaSlice[1].PublicKey)
+	assert.Equal(t, result.StakeAccount, delegate.AccountMetaSlice[0].PublicKey.String())
+
+	t.Run("max keeps rent, fee and reserve", func(t *testing.T) {
+		result, err := chain.DelegateStake(context.Background(), vote.String(), "max", owner.String())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3_000_000_000-testStakeRent-10_000-10_000_000), result.Lamports)
+	})
+
+	t.Run("reserve", func(t *testing.T) {
+		_, err := chain.DelegateStake(context.Background(), vote.String(), "2.99", owner.String())
+		assert.ErrorIs(t, err, ErrNativeReserveViolation)
+	})
+
+	t.Run("below minimum delegation", func(t *testing.T) {
+		_, err := chain.DelegateStake(context.Background(), vote.String(), "0.0001", owner.String())
+		assert.ErrorContains(t, err, "below the minimum delegation")
+	})
+
+	t.Run("vote accounts", func(t *testing.T) {
+		delinquent := solana.NewWallet().PublicKey().String()
+		node.delinquent = []string{delinquent}
+		for _, address := range []string{delinquent, solana.NewWallet().PublicKey().String(), "not-an-address"} {
+			_, err := chain.DelegateStake(context.Background(), address, "1", owner.String())
+			assert.ErrorIs(t, err, ErrInvalidVoteAccount, address)
+		}
+	})
+
+	assert.Len(t, channel.sent, 2, "rejected delegations are not broadcast")
+}
+
+func TestSolanaDeactivateStake(t *testing.T) {
+	owner := solana.NewWallet().PrivateKey
+	vote := solana.NewWallet().PublicKey()
+	stakeAccount := solana.NewWallet().PublicKey()
+	node := &stakeTestNode{
+		stakeAccount: encodeStakeAccount(owner.PublicKey(), owner.PublicKey(), vote, 2*solana.LAMPORTS_PER_SOL, math.MaxUint64),
+		stakeBalance: 2*solana.LAMPORTS_PER_SOL + testStakeRent,
+	}
+	chain, channel := newStakeTestChain(t, node, 0)
+
+	result, err := chain.DeactivateStake(context.Background(), stakeAccount.String(), owner.String())
+	require.NoError(t, err)
+	assert.Equal(t, vote.String(), result.VoteAccount)
+	assert.Equal(t, 2*solana.LAMPORTS_PER_SOL, result.Lamports)
+	require.Len(t, channel.sent, 1)
+	_, instructions := decodeStakeInstructions(t, channel.sent[0])
+	require.Len(t, instructions, 1)
+	deactivate, ok := instructions[0].(*stake.Deactivate)
+	require.True(t, ok)
+	assert.Equal(t, stakeAccount, deactivate.AccountMetaSlice[0].PublicKey)
+	assert.Equal(t, owner.PublicKey(), deactivate.AccountMetaSlice[2].PublicKey)
+
+	_, err = chain.DeactivateStake(context.Background(), stakeAccount.String(), solana.NewWallet().PrivateKey.String())
+	assert.ErrorIs(t, err, ErrStakeAuthorityMismatch)
+
+	node.stakeAccount = encodeStakeAccount(owner.PublicKey(), owner.PublicKey(), vote, 2*solana.LAMPORTS_PER_SOL, 600)
+	_, err = chain.DeactivateStake(context.Background(), stakeAccount.String(), owner.String())
+	assert.ErrorContains(t, err, "already deactivated")
+
+	node.stakeAccount = nil
+	_, err = chain.DeactivateStake(context.Background(), stakeAccount.String(), owner.String())
+	assert.ErrorIs(t, err, ErrStakeAccountNotFound)
+	assert.Len(t, channel.sent, 1)
+}
+
+func TestSolanaWithdrawStake(t *testing.T) {
+	owner := solana.NewWallet().PrivateKey
+	vote := solana.NewWallet().PublicKey()
+	stakeAccount := solana.NewWallet().PublicKey()
+	balance := 2*solana.LAMPORTS_PER_SOL + testStakeRent
+	node := &stakeTestNode{
+		stakeAccount: encodeStakeAccount(owner.PublicKey(), owner.PublicKey(), vote, 2*solana.LAMPORTS_PER_SOL, 600),
+		stakeBalance: balance,
+	}
+	chain, channel := newStakeTestChain(t, node, 0)
+
+	result, err := chain.WithdrawStake(context.Background(), stakeAccount.String(), "", "max", owner.String())
+	require.NoError(t, err)
+	assert.Equal(t, balance, result.Lamports)
+	assert.Equal(t, owner.PublicKey().String(), result.Recipient)
+	require.Len(t, channel.sent, 1)
+	_, instructions := decodeStakeInstructions(t, channel.sent[0])
+	require.Len(t, instructions, 1)
+	withdraw, ok := instructions[0].(*stake.Withdraw)
+	require.True(t, ok)
+	assert.Equal(t, balance, *withdraw.Lamports)
+	assert.Equal(t, owner.PublicKey(), withdraw.GetRecipientAccount().PublicKey)
+	assert.Equal(t, owner.PublicKey(), withdraw.GetWithdrawAuthority().PublicKey)
+
+	recipient := solana.NewWallet().PublicKey()
+	result, err = chain.WithdrawStake(context.Background(), stakeAccount.String(), recipient.String(), "0.5", owner.String())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(500_000_000), result.Lamports)
+	assert.Equal(t, recipient.String(), result.Recipient)
+
+	for name, amount := range map[string]string{"more than the account holds": "5", "invalid": "abc"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := chain.WithdrawStake(context.Background(), stakeAccount.String(), "", amount, owner.String())
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("still delegated", func(t *testing.T) {
+		node.stakeAccount = encodeStakeAccount(owner.PublicKey(), owner.PublicKey(), vote, 2*solana.LAMPORTS_PER_SOL, math.MaxUint64)
+		_, err := chain.WithdrawStake(context.Background(), stakeAccount.String(), "", "max", owner.String())
+		assert.ErrorContains(t, err, "deactivate it")
+	})
+
+	t.Run("other withdrawer", func(t *testing.T) {
+		node.stakeAccount = encodeStakeAccount(owner.PublicKey(), solana.NewWallet().PublicKey(), solana.PublicKey{}, 0, 0)
+		_, err := chain.WithdrawStake(context.Background(), stakeAccount.String(), "", "max", owner.String())
+		assert.ErrorIs(t, err, ErrStakeAuthorityMismatch)
+	})
+	assert.Len(t, channel.sent, 2)
+}
+
+func TestDecodeStakeAccount(t *testing.T) {
+	staker := solana.NewWallet().PublicKey()
+	withdrawer := solana.NewWallet().PublicKey()
+	vote := solana.NewWallet().PublicKey()
+
+	info, err := decodeStakeAccount(encodeStakeAccount(staker, withdrawer, vote, 42, math.MaxUint64))
+	require.NoError(t, err)
+	assert.Equal(t, staker.String(), info.Staker)
+	assert.Equal(t, withdrawer.String(), info.Withdrawer)
+	assert.Equal(t, vote.String(), info.VoteAccount)
+	assert.Equal(t, uint64(42), info.DelegatedLamports)
+	assert.Equal(t, uint64(testStakeRent), info.RentExemptReserve)
+	assert.True(t, info.Delegated())
+	assert.False(t, info.Deactivating())
+
+	info, err = decodeStakeAccount(encodeStakeAccount(staker, withdrawer, solana.PublicKey{}, 0, 0))
+	require.NoError(t, err)
+	assert.False(t, info.Delegated())
+	assert.Empty(t, info.VoteAccount)
+
+	for name, data := range map[string][]byte{
+		"uninitialized": make([]byte, StakeAccountSize),
+		"short":         encodeStakeAccount(staker, withdrawer, vote, 1, 1)[:100],
+	} {
+		_, err := decodeStakeAccount(data)
+		assert.ErrorIs(t, err, ErrStakeAccountNotFound, name)
+	}
+}
+
+func TestSolanaLockedBalances(t *testing.T) {
+	owner := solana.NewWallet().PublicKey()
+	node := &stakeTestNode{}
+	chain, _ := newStakeTestChain(t, node, 0)
+
+	locked, err := chain.LockedBalances(context.Background(), owner.String(), "SOL")
+	require.NoError(t, err)
+	require.Len(t, locked, 1, "no stake accounts")
+	assert.Equal(t, LockedBalance{Kind: LockedKindRentReserve, Amount: "0.00228288", Description: "rent-exempt minimum; only closing the account releases it"}, locked[0])
+
+	node.stakeAccount = encodeStakeAccount(owner, owner, solana.NewWallet().PublicKey(), 2*solana.LAMPORTS_PER_SOL, math.MaxUint64)
+	node.stakeBalance = 2*solana.LAMPORTS_PER_SOL + testStakeRent
+	locked, err = chain.LockedBalances(context.Background(), owner.String(), "")
+	require.NoError(t, err)
+	require.Len(t, locked, 2)
+	assert.Equal(t, LockedKindStaked, locked[1].Kind)
+	assert.Equal(t, "2.00228288", locked[1].Amount)
+	assert.True(t, locked[1].External, "stake accounts are not part of the wallet's balance")
+
+	locked, err = chain.LockedBalances(context.Background(), owner.String(), "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	require.NoError(t, err)
+	assert.Empty(t, locked, "SPL tokens have nothing locked")
+}
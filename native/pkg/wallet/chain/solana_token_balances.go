@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// SPL token program IDs whose balances appear in transaction metadata
+const (
+	SPLTokenProgramID     = "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"
+	SPLToken2022ProgramID = "TokenzQdBNbLqP5VEhdkAS6EPFLC1PEnvd4ZJZsw4TpN"
+)
+
+// TokenBalanceDeltaReader is implemented by chains that can report how a
+// confirmed transaction changed token balances
+type TokenBalanceDeltaReader interface {
+	// GetTokenBalanceDeltas returns the net change of every token account the transaction touched
+	GetTokenBalanceDeltas(ctx context.Context, txHash string) ([]TokenBalanceDelta, error)
+}
+
+// TokenBalanceDelta is the net change of one token account in a transaction
+type TokenBalanceDelta struct {
+	Account    string `json:"account"`         // token account address
+	Owner      string `json:"owner,omitempty"` // wallet owning the token account
+	Mint       string `json:"mint"`
+	Program    string `json:"program,omitempty"` // "spl-token" or "spl-token-2022"
+	Decimals   int    `json:"decimals"`
+	PreAmount  string `json:"pre_amount"`  // UI amount before the transaction
+	PostAmount string `json:"post_amount"` // UI amount after the transaction
+	Delta      string `json:"delta"`       // signed UI amount, e.g. "+1523" or "-0.5"
+	RawDelta   string `json:"raw_delta"`   // signed change in base units
+}
+
+// SolanaTransactionResult is the subset of a jsonParsed getTransaction response used for balance deltas
+type SolanaTransactionResult struct {
+	Slot        uint64                 `json:"slot"`
+	BlockTime   *int64                 `json:"blockTime"`
+	Meta        *SolanaTransactionMeta `json:"meta"`
+	Transaction struct {
+		Message struct {
+			AccountKeys []solanaAccountKey `json:"accountKeys"`
+		} `json:"message"`
+	} `json:"transaction"`
+}
+
+// SolanaTransactionMeta holds the balances recorded before and after execution
+type SolanaTransactionMeta struct {
+	Err               any                  `json:"err"`
+	Fee               uint64               `json:"fee"`
+	PreTokenBalances  []SolanaTokenBalance `json:"preTokenBalances"`
+	PostTokenBalances []SolanaTokenBalance `json:"postTokenBalances"`
+	LoadedAddresses   *struct {
+		Writable []string `json:"writable"`
+		Readonly []string `json:"readonly"`
+	} `json:"loadedAddresses,omitempty"`
+}
+
+// SolanaTokenBalance is a token account balance from transaction metadata
+type SolanaTokenBalance struct {
+	AccountIndex  int    `json:"accountIndex"`
+	Mint          string `json:"mint"`
+	Owner         string `json:"owner,omitempty"`
+	ProgramID     string `json:"programId,omitempty"`
+	UITokenAmount struct {
+		Amount   string `json:"amount"`
+		Decimals int    `json:"decimals"`
+	} `json:"uiTokenAmount"`
+}
+
+// solanaAccountKey accepts both the plain string keys of json encoding and the
+// {"pubkey": ...} objects of jsonParsed encoding
+type solanaAccountKey string
+
+func (k *solanaAccountKey) UnmarshalJSON(data []byte) error {
+	var key string
+	if err := json.Unmarshal(data, &key); err == nil {
+		*k = solanaAccountKey(key)
+		return nil
+	}
+	var parsed struct {
+		Pubkey string `json:"pubkey"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return err
+	}
+	*k = solanaAccountKey(parsed.Pubkey)
+	return nil
+}
+
+// ComputeTokenBalanceDeltas derives the net change of each token account from
+// the pre and post token balances of tx. Accounts opened or closed by the
+// transaction count as zero on the missing side; unchanged accounts are
+// omitted. Deltas are ordered by owner, mint and account.
+func ComputeTokenBalanceDeltas(tx *SolanaTransactionResult) []TokenBalanceDelta {
+	if tx == nil || tx.Meta == nil {
+		return nil
+	}
+
+	accounts := make([]string, 0, len(tx.Transaction.Message.AccountKeys))
+	for _, key := range tx.Transaction.Message.AccountKeys {
+		accounts = append(accounts, string(key))
+	}
+	// Versioned transactions in json encoding list lookup-table accounts separately
+	if loaded := tx.Meta.LoadedAddresses; loaded != nil {
+		accounts = append(accounts, loaded.Writable...)
+		accounts = append(accounts, loaded.Readonly...)
+	}
+
+	type balancePair struct {
+		pre, post *SolanaTokenBalance
+	}
+	pairs := make(map[int]*balancePair)
+	var indexes []int
+	pairFor := func(index int) *balancePair {
+		pair, ok := pairs[index]
+		if !ok {
+			pair = &balancePair{}
+			pairs[index] = pair
+			indexes = append(indexes, index)
+		}
+		return pair
+	}
+	for i := range tx.Meta.PreTokenBalances {
+		pairFor(tx.Meta.PreTokenBalances[i].AccountIndex).pre = &tx.Meta.PreTokenBalances[i]
+	}
+	for i := range tx.Meta.PostTokenBalances {
+		pairFor(tx.Meta.PostTokenBalances[i].AccountIndex).post = &tx.Meta.PostTokenBalances[i]
+	}
+
+	var deltas []TokenBalanceDelta
+	for _, index := range indexes {
+		pair := pairs[index]
+		known := pair.post
+		if known == nil {
+			known = pair.pre
+		}
+		pre, post := tokenBalanceUnits(pair.pre), tokenBalanceUnits(pair.post)
+		change := new(big.Int).Sub(post, pre)
+		if change.Sign() == 0 {
+			continue
+		}
+
+		decimals := known.UITokenAmount.Decimals
+		delta := TokenBalanceDelta{
+			Owner:      known.Owner,
+			Mint:       known.Mint,
+			Program:    tokenProgramName(known.ProgramID),
+			Decimals:   decimals,
+			PreAmount:  formatTokenUnits(pre, decimals),
+			PostAmount: formatTokenUnits(post, decimals),
+			Delta:      formatTokenUnits(change, decimals),
+			RawDelta:   change.String(),
+		}
+		if change.Sign() > 0 {
+			delta.Delta = "+" + delta.Delta
+			delta.RawDelta = "+" + delta.RawDelta
+		}
+		if index >= 0 && index < len(accounts) {
+			delta.Account = accounts[index]
+		}
+		deltas = append(deltas, delta)
+	}
+
+	sort.SliceStable(deltas, func(i, j int) bool {
+		if deltas[i].Owner != deltas[j].Owner {
+			return deltas[i].Owner < deltas[j].Owner
+		}
+		if deltas[i].Mint != deltas[j].Mint {
+			return deltas[i].Mint < deltas[j].Mint
+		}
+		return deltas[i].Account < deltas[j].Account
+	})
+	return deltas
+}
+
+// tokenBalanceUnits returns the raw amount of balance, zero when it is absent or malformed
+func tokenBalanceUnits(balance *SolanaTokenBalance) *big.Int {
+	if balance == nil {
+		return new(big.Int)
+	}
+	units, ok := new(big.Int).SetString(balance.UITokenAmount.Amount, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return units
+}
+
+// tokenProgramName names the token program owning an account, empty when the RPC did not report it
+func tokenProgramName(programID string) string {
+	switch programID {
+	case SPLTokenProgramID:
+		return "spl-token"
+	case SPLToken2022ProgramID:
+		return "spl-token-2022"
+	}
+	return programID
+}
+
+// formatTokenUnits renders signed base units with decimals and no trailing zeros
+func formatTokenUnits(units *big.Int, decimals int) string {
+	if decimals <= 0 {
+		return units.String()
+	}
+	r := new(big.Rat).SetFrac(units, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	s := strings.TrimRight(r.FloatString(decimals), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// GetTransaction fetches a transaction with jsonParsed encoding. It returns
+// ErrTransactionNotFound when the node does not know the signature.
+func (rm *SolanaRPCManager) GetTransaction(ctx context.Context, signature string, commitment string) (*SolanaTransactionResult, error) {
+	var result *SolanaTransactionResult
+	params := []any{
+		signature,
+		map[string]any{
+			"encoding":                       "jsonParsed",
+			"commitment":                     commitment,
+			"maxSupportedTransactionVersion": 0,
+		},
+	}
+	if err := rm.callRPC(ctx, "getTransaction", params, &result); err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, ErrTransactionNotFound
+	}
+	return result, nil
+}
+
+// GetTokenBalanceDeltas reports the SPL and Token-2022 balance changes of a
+// transaction from its pre and post token balances
+func (s *SolanaChain) GetTokenBalanceDeltas(ctx context.Context, txHash string) ([]TokenBalanceDelta, error) {
+	if s.rpcManager == nil {
+		return nil, errors.New("solana RPC is not configured")
+	}
+	// getTransaction does not accept processed
+	commitment := s.commitment(ctx)
+	if commitment == CommitmentProcessed {
+		commitment = CommitmentConfirmed
+	}
+	tx, err := s.rpcManager.GetTransaction(ctx, txHash, commitment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction %s: %w", txHash, err)
+	}
+	if tx.Meta == nil {
+		return nil, fmt.Errorf("transaction %s has no metadata", txHash)
+	}
+	return ComputeTokenBalanceDeltas(tx), nil
+}
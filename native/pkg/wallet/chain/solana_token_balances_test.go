@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testUSDCMint = "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"
+	testBONKMint = "DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263"
+	testPYUSD    = "2b1kV6DkPAnxd5ixfnxCpjxmKwqjjaYmCZfHsFu24GXo" // Token-2022 mint
+)
+
+// swapTransactionJSON is a jsonParsed getTransaction result of a wallet
+// swapping 100 USDC for 1523 BONK through a pool, opening its BONK account,
+// and closing an emptied Token-2022 account
+const swapTransactionJSON = `{
+	"slot": 250000000,
+	"blockTime": 1760000000,
+	"meta": {
+		"err": null,
+		"fee": 5000,
+		"preTokenBalances": [
+			{"accountIndex": 1, "mint": "` + testUSDCMint + `", "owner": "Wallet111", "programId": "` + SPLTokenProgramID + `", "uiTokenAmount": {"amount": "250000000", "decimals": 6}},
+			{"accountIndex": 3, "mint": "` + testUSDCMint + `", "owner": "Pool111", "programId": "` + SPLTokenProgramID + `", "uiTokenAmount": {"amount": "1000000000", "decimals": 6}},
+			{"accountIndex": 4, "mint": "` + testBONKMint + `", "owner": "Pool111", "programId": "` + SPLTokenProgramID + `", "uiTokenAmount": {"amount": "500000000", "decimals": 5}},
+			{"accountIndex": 5, "mint": "` + testPYUSD + `", "owner": "Wallet111", "programId": "` + SPLToken2022ProgramID + `", "uiTokenAmount": {"amount": "42", "decimals": 6}},
+			{"accountIndex": 6, "mint": "` + testUSDCMint + `", "owner": "Other111", "programId": "` + SPLTokenProgramID + `", "uiTokenAmount": {"amount": "7", "decimals": 6}}
+		],
+		"postTokenBalances": [
+			{"accountIndex": 1, "mint": "` + testUSDCMint + `", "owner": "Wallet111", "programId": "` + SPLTokenProgramID + `", "uiTokenAmount": {"amount": "150000000", "decimals": 6}},
+			{"accountIndex": 2, "mint": "` + testBONKMint + `", "owner": "Wallet111", "programId": "` + SPLTokenProgramID + `", "uiTokenAmount": {"amount": "152300000", "decimals": 5}},
+			{"accountIndex": 3, "mint": "` + testUSDCMint + `", "owner": "Pool111", "programId": "` + SPLTokenProgramID + `", "uiTokenAmount": {"amount": "1100000000", "decimals": 6}},
+			{"accountIndex": 4, "mint": "` + testBONKMint + `", "owner": "Pool111", "programId": "` + SPLTokenProgramID + `", "uiTokenAmount": {"amount": "347700000", "decimals": 5}},
+			{"accountIndex": 6, "mint": "` + testUSDCMint + `", "owner": "Other111", "programId": "` + SPLTokenProgramID + `", "uiTokenAmount": {"amount": "7", "decimals": 6}}
+		]
+	},
+	"transaction": {
+		"message": {
+			"accountKeys": [
+				{"pubkey": "Wallet111", "signer": true, "writable": true},
+				{"pubkey": "WalletUSDC", "signer": false, "writable": true},
+				{"pubkey": "WalletBONK", "signer": false, "writable": true},
+				{"pubkey": "PoolUSDC", "signer": false, "writable": true},
+				{"pubkey": "PoolBONK", "signer": false, "writable": true},
+				{"pubkey": "WalletPYUSD", "signer": false, "writable": true},
+				{"pubkey": "OtherUSDC", "signer": false, "writable": false}
+			]
+		}
+	}
+}`
+
+func TestComputeTokenBalanceDeltas(t *testing.T) {
+	var tx SolanaTransactionResult
+	require.NoError(t, json.Unmarshal([]byte(swapTransactionJSON), &tx))
+
+	deltas := ComputeTokenBalanceDeltas(&tx)
+	require.Len(t, deltas, 5, "the unchanged account is omitted")
+
+	byAccount := make(map[string]TokenBalanceDelta)
+	for _, delta := range deltas {
+		byAccount[delta.Account] = delta
+	}
+
+	bonk := byAccount["WalletBONK"]
+	assert.Equal(t, "Wallet111", bonk.Owner)
+	assert.Equal(t, testBONKMint, bonk.Mint)
+	assert.Equal(t, "+1523", bonk.Delta, "an account opened by the transaction starts from zero")
+	assert.Equal(t, "+152300000", bonk.RawDelta)
+	assert.Equal(t, "0", bonk.PreAmount)
+	assert.Equal(t, "1523", bonk.PostAmount)
+	assert.Equal(t, "spl-token", bonk.Program)
+
+	assert.Equal(t, "-100", byAccount["WalletUSDC"].Delta)
+	assert.Equal(t, "+100", byAccount["PoolUSDC"].Delta)
+	assert.Equal(t, "-1523", byAccount["PoolBONK"].Delta)
+
+	pyusd := byAccount["WalletPYUSD"]
+	assert.Equal(t, "-0.000042", pyusd.Delta, "a closed account ends at zero")
+	assert.Equal(t, "spl-token-2022", pyusd.Program)
+
+	// Ordered by owner, then mint
+	assert.Equal(t, "Pool111", deltas[0].Owner)
+	assert.Equal(t, "Wallet111", deltas[len(deltas)-1].Owner)
+
+	assert.Nil(t, ComputeTokenBalanceDeltas(&SolanaTransactionResult{}))
+}
+
+func TestComputeTokenBalanceDeltasLoadedAddresses(t *testing.T) {
+	// json-encoded versioned transactions list lookup-table accounts after the static keys
+	raw := `{
+		"meta": {
+			"preTokenBalances": [],
+			"postTokenBalances": [{"accountIndex": 2, "mint": "` + testUSDCMint + `", "owner": "Wallet111", "uiTokenAmount": {"amount": "5", "decimals": 0}}],
+			"loadedAddresses": {"writable": ["LookupUSDC"], "readonly": []}
+		},
+		"transaction": {"message": {"accountKeys": ["Wallet111", "Program111"]}}
+	}`
+	var tx SolanaTransactionResult
+	require.NoError(t, json.Unmarshal([]byte(raw), &tx))
+
+	deltas := ComputeTokenBalanceDeltas(&tx)
+	require.Len(t, deltas, 1)
+	assert.Equal(t, "LookupUSDC", deltas[0].Account)
+	assert.Equal(t, "+5", deltas[0].Delta)
+	assert.Empty(t, deltas[0].Program, "older nodes do not report the program")
+}
+
+func TestSolanaChainGetTokenBalanceDeltas(t *testing.T) {
+	t.Setenv("RUN_MODE", "")
+	var result string
+	solana := newSolanaTestChain(t, map[string]rpcMethod{
+		"getTransaction": func(call rpcCall) (string, error) {
+			var options struct {
+				Encoding string `json:"encoding"`
+			}
+			call.param(1, &options)
+			assert.Equal(t, "jsonParsed", options.Encoding)
+			return result, nil
+		},
+	})
+
+	result = swapTransactionJSON
+	deltas, err := solana.GetTokenBalanceDeltas(context.Background(), "5sig")
+	require.NoError(t, err)
+	assert.Len(t, deltas, 5)
+
+	result = "null"
+	_, err = solana.GetTokenBalanceDeltas(context.Background(), "5sig")
+	assert.True(t, errors.Is(err, ErrTransactionNotFound))
+
+	_, err = NewSolanaChainLegacy().GetTokenBalanceDeltas(context.Background(), "5sig")
+	assert.Error(t, err, "legacy chains have no RPC")
+}
@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+
+	solana "github.com/gagliardetto/solana-go"
+	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+)
+
+// Compute units requested for a transfer, leaving room for the compute budget,
+// nonce and tip instructions and, for tokens, creating the recipient's account
+const (
+	solanaTransferComputeUnits      = 1_000
+	solanaTokenTransferComputeUnits = 40_000
+)
+
+// solanaBaseComputeUnitPrice is the priority fee of the slow gas strategy, in
+// micro-lamports per compute unit
+const solanaBaseComputeUnitPrice = 1_000
+
+// splMintDecimalsOffset is where an SPL mint account stores its decimals,
+// after the optional mint authority (4+32 bytes) and the supply (8 bytes)
+const splMintDecimalsOffset = 44
+
+// jitoTipAccounts are the accounts Jito block engines accept tips on
+var jitoTipAccounts = []string{
+	"96gYZGLnJYVFmbjzopPSU6QiEV5fGqZNyN9nmNhvrZU5",
+	"HFqU5x63VTqvQss8hp11i4wVV8bD44PvwucfZ2bU7gRe",
+	"Cw8CFyM9FkoMi7K7Crf6HNQqf4uEMzpKw6QNghXLvLkY",
+	"ADaUMid9yfUytqMBgopwjb2DTLSokTSzL1zt6iGPaS49",
+	"DfXygSm4jCyNCybVYYK6DwvWqjKee8pbDmJGcLWNDXjh",
+	"ADuUkR4vqLUMWXxW9gh6D6L8pMSawimctcNZ5pGwDcEt",
+	"DttWaMuVvTiduZRnguLF7jNxTgiMBZ1hyAumKUiL2KRL",
+	"3AVi9Tg9Uo68tJfuvoKvqKNWKkC5wPdSSdeBnizKZ6jT",
+}
+
+// solanaComputeUnitPrice returns the priority fee paid under strategy. The
+// retry strategies (level_up and the like) only escalate the Jito tip, so
+// they and unknown strategies price like standard.
+func solanaComputeUnitPrice(strategy string) uint64 {
+	multiplier, ok := staticGasMultipliers[strings.ToLower(strings.TrimSpace(strategy))]
+	if !ok {
+		multiplier = staticGasMultipliers[GasStrategyStandard]
+	}
+	return uint64(math.Round(solanaBaseComputeUnitPrice * multiplier))
+}
+
+// prepareTransfer fills in what createTransaction needs to know about the
+// token of params: the amount in its smallest unit, its decimals and whether
+// the recipient's token account has to be created
+func (s *SolanaChain) prepareTransfer(ctx context.Context, params *TransactionParams, amount string) error {
+	if params.TokenMint == "" || strings.EqualFold(params.TokenMint, "SOL") {
+		params.TokenMint = ""
+		lamports, err := parseSOLAmount(amount)
+		if err != nil {
+			return err
+		}
+		params.Amount = lamports
+		return nil
+	}
+
+	mint, err := solana.PublicKeyFromBase58(params.TokenMint)
+	if err != nil {
+		return fmt.Errorf("invalid token mint address: %s", params.TokenMint)
+	}
+	recipient, err := solana.PublicKeyFromBase58(params.To)
+	if err != nil {
+		return fmt.Errorf("invalid to address: %w", err)
+	}
+	commitment := s.commitment(ctx)
+	mintInfo, err := s.rpcManager.GetAccountInfo(ctx, mint.String(), commitment)
+	if err != nil {
+		return fmt.Errorf("failed to get mint account: %w", err)
+	}
+	if mintInfo.Value == nil || len(mintInfo.Value.Data) == 0 {
+		return fmt.Errorf("token mint %s not found", mint)
+	}
+	if mintInfo.Value.Owner != SPLTokenProgramID {
+		return fmt.Errorf("token mint %s is owned by %s, only SPL Token mints can be sent", mint, mintInfo.Value.Owner)
+	}
+	data, err := base64.StdEncoding.DecodeString(mintInfo.Value.Data[0])
+	if err != nil || len(data) <= splMintDecimalsOffset {
+		return fmt.Errorf("failed to decode token mint %s", mint)
+	}
+	params.TokenDecimals = data[splMintDecimalsOffset]
+
+	raw, err := scaleAmount(amount, int64(params.TokenDecimals))
+	if err != nil {
+		return err
+	}
+	if raw.Sign() <= 0 || !raw.IsUint64() {
+		return fmt.Errorf("invalid amount: %s", amount)
+	}
+	params.Amount = raw.Uint64()
+
+	ata, _, err := solana.FindAssociatedTokenAddress(recipient, mint)
+	if err != nil {
+		return fmt.Errorf("failed to derive associated token account: %w", err)
+	}
+	account, err := s.rpcManager.GetAccountInfo(ctx, ata.String(), commitment)
+	if err != nil {
+		return fmt.Errorf("failed to get associated token account: %w", err)
+	}
+	params.CreateRecipientAccount = account.Value == nil
+	return nil
+}
+
+// createTransaction builds the transfer described by params, signs it with
+// params.PrivateKey and returns it base64-encoded in wire format along with
+// its signature. Instructions are, in order: AdvanceNonceAccount when a nonce
+// account is used, the compute budget, the recipient's token account when it
+// has to be created, the transfer and the Jito tip when Jito is enabled.
+func (s *SolanaChain) createTransaction(params *TransactionParams) (string, string, error) {
+	payer, err := solana.PrivateKeyFromBase58(params.PrivateKey)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid private key: %w", err)
+	}
+	if payer.PublicKey().String() != params.From {
+		return "", "", errors.New("private key does not match the from address")
+	}
+	recipient, err := solana.PublicKeyFromBase58(params.To)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid to address: %w", err)
+	}
+	blockhash, err := solana.HashFromBase58(params.RecentBlockhash)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid blockhash %q: %w", params.RecentBlockhash, err)
+	}
+
+	var instructions []solana.Instruction
+	if params.NonceAccount != "" {
+		nonceAccount, err := solana.PublicKeyFromBase58(params.NonceAccount)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid nonce account address: %w", err)
+		}
+		// The runtime only honours a durable nonce advanced by the first instruction
+		instructions = append(instructions, system.NewAdvanceNonceAccountInstruction(nonceAccount, solana.SysVarRecentBlockHashesPubkey, payer.PublicKey()).Build())
+	}
+
+	computeUnits := uint32(solanaTransferComputeUnits)
+	if params.TokenMint != "" {
+		computeUnits = solanaTokenTransferComputeUnits
+	}
+	instructions = append(instructions,
+		computebudget.NewSetComputeUnitLimitInstruction(computeUnits).Build(),
+		computebudget.NewSetComputeUnitPriceInstruction(solanaComputeUnitPrice(params.GasStrategy)).Build(),
+	)
+
+	if params.TokenMint == "" {
+		instructions = append(instructions, system.NewTransferInstruction(params.Amount, payer.PublicKey(), recipient).Build())
+	} else {
+		mint, err := solana.PublicKeyFromBase58(params.TokenMint)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid token mint address: %s", params.TokenMint)
+		}
+		source, _, err := solana.FindAssociatedTokenAddress(payer.PublicKey(), mint)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to derive sender token account: %w", err)
+		}
+		destination, _, err := solana.FindAssociatedTokenAddress(recipient, mint)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to derive recipient token account: %w", err)
+		}
+		if params.CreateRecipientAccount {
+			instructions = append(instructions, associatedtokenaccount.NewCreateInstruction(payer.PublicKey(), recipient, mint).Build())
+		}
+		instructions = append(instructions,
+			token.NewTransferCheckedInstruction(params.Amount, params.TokenDecimals, source, mint, destination, payer.PublicKey(), nil).Build())
+	}
+
+	if tip := s.jitoTip(params.JitoTipAmount); tip > 0 {
+		tipAccount := solana.MustPublicKeyFromBase58(jitoTipAccounts[rand.IntN(len(jitoTipAccounts))])
+		instructions = append(instructions, system.NewTransferInstruction(tip, payer.PublicKey(), tipAccount).Build())
+	}
+
+	tx, err := solana.NewTransaction(instructions, blockhash, solana.TransactionPayer(payer.PublicKey()))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create transfer: %w", err)
+	}
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(payer.PublicKey()) {
+			return &payer
+		}
+		return nil
+	}); err != nil {
+		return "", "", fmt.Errorf("failed to sign transfer: %w", err)
+	}
+	encoded, err := tx.ToBase64()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode transfer: %w", err)
+	}
+	return encoded, tx.Signatures[0].String(), nil
+}
+
+// jitoTip returns the tip to attach to a transaction, capped at the configured
+// maximum, or zero when Jito is disabled
+func (s *SolanaChain) jitoTip(lamports uint64) uint64 {
+	if s.config == nil || !s.config.Jito.Enabled {
+		return 0
+	}
+	if limit := s.config.Jito.MaxTipLamports; limit > 0 && lamports > limit {
+		return limit
+	}
+	return lamports
+}
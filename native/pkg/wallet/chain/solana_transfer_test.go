@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	solana "github.com/gagliardetto/solana-go"
+	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// decodeTransfer verifies a transaction built by createTransaction and decodes its instructions
+func decodeTransfer(t *testing.T, encoded, signature string) (*solana.Transaction, []any) {
+	t.Helper()
+	tx, err := solana.TransactionFromBase64(encoded)
+	require.NoError(t, err)
+	require.NoError(t, tx.VerifySignatures())
+	assert.Equal(t, tx.Signatures[0].String(), signature)
+
+	decoded := make([]any, 0, len(tx.Message.Instructions))
+	for _, instruction := range tx.Message.Instructions {
+		accounts, err := instruction.ResolveInstructionAccounts(&tx.Message)
+		require.NoError(t, err)
+		program, err := tx.Message.Program(instruction.ProgramIDIndex)
+		require.NoError(t, err)
+		var impl any
+		switch program {
+		case solana.SystemProgramID:
+			inst, err := system.DecodeInstruction(accounts, instruction.Data)
+			require.NoError(t, err)
+			impl = inst.Impl
+		case solana.ComputeBudget:
+			inst, err := computebudget.DecodeInstruction(accounts, instruction.Data)
+			require.NoError(t, err)
+			impl = inst.Impl
+		case solana.TokenProgramID:
+			inst, err := token.DecodeInstruction(accounts, instruction.Data)
+			require.NoError(t, err)
+			impl = inst.Impl
+		case solana.SPLAssociatedTokenAccountProgramID:
+			inst, err := associatedtokenaccount.DecodeInstruction(accounts, instruction.Data)
+			require.NoError(t, err)
+			impl = inst.Impl
+		default:
+			t.Fatalf("unexpected program %s", program)
+		}
+		decoded = append(decoded, impl)
+	}
+	return tx, decoded
+}
+
+func newTransferParams(payer solana.PrivateKey) *TransactionParams {
+	return &TransactionParams{
+		From:            payer.PublicKey().String(),
+		To:              solana.NewWallet().PublicKey().String(),
+		Amount:          250_000_000,
+		PrivateKey:      payer.String(),
+		RecentBlockhash: solana.HashFromBytes(solana.NewWallet().PublicKey().Bytes()).String(),
+		GasStrategy:     GasStrategyFast,
+	}
+}
+
+func TestSolanaCreateTransactionSOL(t *testing.T) {
+	payer := solana.NewWallet().PrivateKey
+	params := newTransferParams(payer)
+	chain := &SolanaChain{config: &config.SolanaChainConfig{}, logger: zap.NewNop()}
+
+	encoded, signature, err := chain.createTransaction(params)
+	require.NoError(t, err)
+	tx, instructions := decodeTransfer(t, encoded, signature)
+	assert.Equal(t, payer.PublicKey(), tx.Message.AccountKeys[0])
+	assert.Equal(t, params.RecentBlockhash, tx.Message.RecentBlockhash.String())
+	require.Len(t, instructions, 3)
+
+	limit, ok := instructions[0].(*computebudget.SetComputeUnitLimit)
+	require.True(t, ok)
+	assert.Equal(t, uint32(solanaTransferComputeUnits), limit.Units)
+	price, ok := instructions[1].(*computebudget.SetComputeUnitPrice)
+	require.True(t, ok)
+	assert.Equal(t, uint64(1_500), price.MicroLamports)
+
+	transfer, ok := instructions[2].(*system.Transfer)
+	require.True(t, ok)
+	assert.Equal(t, uint64(250_000_000), *transfer.Lamports)
+	assert.Equal(t, payer.PublicKey(), transfer.GetFundingAccount().PublicKey)
+	assert.Equal(t, params.To, transfer.GetRecipientAccount().PublicKey.String())
+}
+
+func TestSolanaCreateTransactionJitoTip(t *testing.T) {
+	payer := solana.NewWallet().PrivateKey
+	params := newTransferParams(payer)
+	params.JitoTipAmount = 50_000
+	chain := &SolanaChain{
+		config: &config.SolanaChainConfig{Jito: config.JitoConfig{Enabled: true, MaxTipLamports: 20_000}},
+		logger: zap.NewNop(),
+	}
+
+	encoded, signature, err := chain.createTransaction(params)
+	require.NoError(t, err)
+	_, instructions := decodeTransfer(t, encoded, signature)
+	require.Len(t, instructions, 4)
+
+	tip, ok := instructions[3].(*system.Transfer)
+	require.True(t, ok)
+	assert.Equal(t, uint64(20_000), *tip.Lamports)
+	assert.Contains(t, jitoTipAccounts, tip.GetRecipientAccount().PublicKey.String())
+}
+
+func TestSolanaCreateTransactionDurableNonce(t *testing.T) {
+	payer := solana.NewWallet().PrivateKey
+	params := newTransferParams(payer)
+	params.NonceAccount = solana.NewWallet().PublicKey().String()
+	chain := &SolanaChain{config: &config.SolanaChainConfig{}, logger: zap.NewNop()}
+
+	encoded, signature, err := chain.createTransaction(params)
+	require.NoError(t, err)
+	_, instructions := decodeTransfer(t, encoded, signature)
+	require.Len(t, instructions, 4)
+
+	advance, ok := instructions[0].(*system.AdvanceNonceAccount)
+	require.True(t, ok)
+	assert.Equal(t, params.NonceAccount, advance.GetNonceAccount().PublicKey.String())
+	assert.Equal(t, payer.PublicKey(), advance.GetNonceAuthorityAccount().PublicKey)
+}
+
+func TestSolanaCreateTransactionToken(t *testing.T) {
+	payer := solana.NewWallet().PrivateKey
+	mint := solana.NewWallet().PublicKey()
+	params := newTransferParams(payer)
+	params.TokenMint = mint.String()
+	params.TokenDecimals = 6
+	params.Amount = 1_500_000
+	params.CreateRecipientAccount = true
+	chain := &SolanaChain{config: &config.SolanaChainConfig{}, logger: zap.NewNop()}
+
+	encoded, signature, err := chain.createTransaction(params)
+	require.NoError(t, err)
+	_, instructions := decodeTransfer(t, encoded, signature)
+	require.Len(t, instructions, 4)
+
+	limit, ok := instructions[0].(*computebudget.SetComputeUnitLimit)
+	require.True(t, ok)
+	assert.Equal(t, uint32(solanaTokenTransferComputeUnits), limit.Units)
+
+	recipient := solana.MustPublicKeyFromBase58(params.To)
+	create, ok := instructions[2].(*associatedtokenaccount.Create)
+	require.True(t, ok)
+	destination, _, err := solana.FindAssociatedTokenAddress(recipient, mint)
+	require.NoError(t, err)
+	require.Len(t, create.AccountMetaSlice, 7)
+	assert.Equal(t, destination, create.AccountMetaSlice[1].PublicKey)
+	assert.Equal(t, recipient, create.AccountMetaSlice[2].PublicKey)
+	assert.Equal(t, mint, create.AccountMetaSlice[3].PublicKey)
+
+	transfer, ok := instructions[3].(*token.TransferChecked)
+	require.True(t, ok)
+	assert.Equal(t, uint64(1_500_000), *transfer.Amount)
+	assert.Equal(t, uint8(6), *transfer.Decimals)
+	source, _, err := solana.FindAssociatedTokenAddress(payer.PublicKey(), mint)
+	require.NoError(t, err)
+	assert.Equal(t, source, transfer.GetSourceAccount().PublicKey)
+	assert.Equal(t, destination, transfer.GetDestinationAccount().PublicKey)
+	assert.Equal(t, payer.PublicKey(), transfer.GetOwnerAccount().PublicKey)
+}
+
+func TestSolanaCreateTransactionRejectsForeignKey(t *testing.T) {
+	params := newTransferParams(solana.NewWallet().PrivateKey)
+	params.From = solana.NewWallet().PublicKey().String()
+	chain := &SolanaChain{config: &config.SolanaChainConfig{}, logger: zap.NewNop()}
+
+	_, _, err := chain.createTransaction(params)
+	assert.ErrorContains(t, err, "does not match the from address")
+}
+
+func TestSolanaPrepareTransfer(t *testing.T) {
+	t.Setenv("RUN_MODE", "")
+	mint := solana.NewWallet().PublicKey()
+	recipient := solana.NewWallet().PublicKey()
+	mintData := make([]byte, 82)
+	mintData[splMintDecimalsOffset] = 6
+	chain := newSolanaTestChain(t, map[string]rpcMethod{
+		"getAccountInfo": func(call rpcCall) (string, error) {
+			var address string
+			call.param(0, &address)
+			value := "null"
+			if address == mint.String() {
+				value = `{"lamports":1461600,"owner":"` + SPLTokenProgramID + `","data":["` + base64.StdEncoding.EncodeToString(mintData) + `","base64"]}`
+			}
+			return `{"context":{"slot":1},"value":` + value + `}`, nil
+		},
+	})
+
+	t.Run("SOL", func(t *testing.T) {
+		params := &TransactionParams{To: recipient.String(), TokenMint: "SOL"}
+		require.NoError(t, chain.prepareTransfer(context.Background(), params, "0.25"))
+		assert.Empty(t, params.TokenMint)
+		assert.Equal(t, uint64(250_000_000), params.Amount)
+	})
+
+	t.Run("SPL token", func(t *testing.T) {
+		params := &TransactionParams{To: recipient.String(), TokenMint: mint.String()}
+		require.NoError(t, chain.prepareTransfer(context.Background(), params, "1.5"))
+		assert.Equal(t, uint8(6), params.TokenDecimals)
+		assert.Equal(t, uint64(1_500_000), params.Amount)
+		assert.True(t, params.CreateRecipientAccount)
+	})
+
+	t.Run("unknown mint", func(t *testing.T) {
+		params := &TransactionParams{To: recipient.String(), TokenMint: solana.NewWallet().PublicKey().String()}
+		assert.ErrorContains(t, chain.prepareTransfer(context.Background(), params, "1"), "not found")
+	})
+}
@@ -0,0 +1,256 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	solana "github.com/gagliardetto/solana-go"
+	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"go.uber.org/zap"
+)
+
+// WrapNative moves amount SOL of owner into its wrapped SOL account, creating
+// the associated token account first when owner has none. The SOL must leave
+// the native reserve, the fee and, for a new account, its rent.
+func (s *SolanaChain) WrapNative(ctx context.Context, owner, amount, privateKey string) (*WrapResult, error) {
+	key, account, err := s.wrappedSOLAccount(owner, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	held, exists, err := s.wrappedSOLBalance(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	var rent uint64
+	if !exists {
+		if rent, err = s.tokenAccountRent(ctx); err != nil {
+			return nil, err
+		}
+	}
+	lamports, err := s.wrappableLamports(ctx, owner, amount, rent)
+	if err != nil {
+		return nil, err
+	}
+
+	instructions := make([]solana.Instruction, 0, 3)
+	if !exists {
+		instructions = append(instructions, associatedtokenaccount.NewCreateInstruction(key.PublicKey(), key.PublicKey(), solana.SolMint).Build())
+	}
+	instructions = append(instructions,
+		system.NewTransferInstruction(lamports, key.PublicKey(), account).Build(),
+		token.NewSyncNativeInstruction(account).Build(),
+	)
+	tx, err := s.signWrapTransaction(ctx, instructions, key)
+	if err != nil {
+		return nil, err
+	}
+	broadcastResult, err := s.broadcastSignedTransaction(ctx, tx, owner, account.String(), lamports, "wrap_sol")
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Wrapped SOL",
+		zap.String("account", account.String()),
+		zap.Uint64("lamports", lamports),
+		zap.Bool("account_created", !exists),
+		zap.String("signature", broadcastResult.Signature))
+
+	return s.wrapResult(WrapDirectionWrap, broadcastResult.Signature, owner, account, lamports, held+lamports, !exists, false), nil
+}
+
+// UnwrapNative turns amount of owner's wrapped SOL back into SOL. Unwrapping
+// the whole balance closes the wrapped SOL account, which also returns its
+// rent; a partial amount moves through a temporary token account that is
+// closed in the same transaction, since SOL only leaves a token account when it
+// is closed.
+func (s *SolanaChain) UnwrapNative(ctx context.Context, owner, amount, privateKey string) (*WrapResult, error) {
+	key, account, err := s.wrappedSOLAccount(owner, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	held, exists, err := s.wrappedSOLBalance(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("no WSOL to unwrap: owner has no wrapped SOL account")
+	}
+	resolved, err := resolveWrappedAmount(amount, formatLamports(held), "WSOL")
+	if err != nil {
+		return nil, err
+	}
+	lamports, err := parseSOLAmount(resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	signers := []solana.PrivateKey{key}
+	var instructions []solana.Instruction
+	closeAccount := lamports == held
+	if closeAccount {
+		instructions = []solana.Instruction{
+			token.NewCloseAccountInstruction(account, key.PublicKey(), key.PublicKey(), nil).Build(),
+		}
+	} else {
+		rent, err := s.tokenAccountRent(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// The temporary account's rent comes back when it is closed, but the
+		// owner has to front it
+		if _, err := s.wrappableLamports(ctx, owner, "0", rent+solanaSignatureFeeLamports); err != nil {
+			return nil, err
+		}
+		temporary, err := solana.NewRandomPrivateKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate temporary account key: %w", err)
+		}
+		signers = append(signers, temporary)
+		instructions = []solana.Instruction{
+			system.NewCreateAccountInstruction(rent, SPLTokenAccountSize, solana.TokenProgramID, key.PublicKey(), temporary.PublicKey()).Build(),
+			token.NewInitializeAccount3Instruction(key.PublicKey(), temporary.PublicKey(), solana.SolMint).Build(),
+			token.NewTransferCheckedInstruction(lamports, 9, account, solana.SolMint, temporary.PublicKey(), key.PublicKey(), nil).Build(),
+			token.NewCloseAccountInstruction(temporary.PublicKey(), key.PublicKey(), key.PublicKey(), nil).Build(),
+		}
+	}
+	tx, err := s.signWrapTransaction(ctx, instructions, signers...)
+	if err != nil {
+		return nil, err
+	}
+	broadcastResult, err := s.broadcastSignedTransaction(ctx, tx, account.String(), owner, lamports, "unwrap_sol")
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Unwrapped SOL",
+		zap.String("account", account.String()),
+		zap.Uint64("lamports", lamports),
+		zap.Bool("account_closed", closeAccount),
+		zap.String("signature", broadcastResult.Signature))
+
+	return s.wrapResult(WrapDirectionUnwrap, broadcastResult.Signature, owner, account, lamports, held-lamports, false, closeAccount), nil
+}
+
+// wrappedSOLAccount checks that privateKey controls owner and returns the key
+// and owner's associated wrapped SOL account
+func (s *SolanaChain) wrappedSOLAccount(owner, privateKey string) (solana.PrivateKey, solana.PublicKey, error) {
+	if s.rpcManager == nil {
+		return nil, solana.PublicKey{}, errors.New("solana RPC is not configured")
+	}
+	key, err := solana.PrivateKeyFromBase58(privateKey)
+	if err != nil {
+		return nil, solana.PublicKey{}, fmt.Errorf("invalid private key: %w", err)
+	}
+	if key.PublicKey().String() != owner {
+		return nil, solana.PublicKey{}, fmt.Errorf("private key does not belong to %s", owner)
+	}
+	account, _, err := solana.FindAssociatedTokenAddress(key.PublicKey(), solana.SolMint)
+	if err != nil {
+		return nil, solana.PublicKey{}, fmt.Errorf("failed to derive wrapped SOL account: %w", err)
+	}
+	return key, account, nil
+}
+
+// wrappedSOLBalance returns the wrapped lamports in account and whether the
+// account exists
+func (s *SolanaChain) wrappedSOLBalance(ctx context.Context, account solana.PublicKey) (uint64, bool, error) {
+	info, err := s.rpcManager.GetAccountInfo(ctx, account.String(), s.commitment(ctx))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get wrapped SOL account: %w", err)
+	}
+	if info.Value == nil {
+		return 0, false, nil
+	}
+	if len(info.Value.Data) == 0 {
+		return 0, false, fmt.Errorf("wrapped SOL account %s has no data", account)
+	}
+	data, err := base64.StdEncoding.DecodeString(info.Value.Data[0])
+	if err != nil || len(data) < 72 {
+		return 0, false, fmt.Errorf("failed to decode wrapped SOL account %s", account)
+	}
+	// An SPL token account starts with its mint, owner and amount
+	return binary.LittleEndian.Uint64(data[64:72]), true, nil
+}
+
+// tokenAccountRent returns the rent-exempt minimum of a token account
+func (s *SolanaChain) tokenAccountRent(ctx context.Context) (uint64, error) {
+	rent, err := s.rpcManager.GetMinimumBalanceForRentExemption(ctx, SPLTokenAccountSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get token account rent: %w", err)
+	}
+	return rent, nil
+}
+
+// wrappableLamports resolves the amount of SOL to wrap from owner's balance,
+// which must also cover overhead lamports, the fee and the native reserve
+func (s *SolanaChain) wrappableLamports(ctx context.Context, owner, amount string, overhead uint64) (uint64, error) {
+	balance, err := s.rpcManager.GetBalance(ctx, owner, s.commitment(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get balance: %w", err)
+	}
+	reserve := uint64(math.Round(s.NativeReserve() * float64(solana.LAMPORTS_PER_SOL)))
+	overhead += solanaSignatureFeeLamports + reserve
+
+	if IsMaxAmount(amount) {
+		if balance.Value <= overhead {
+			return 0, fmt.Errorf("%w: balance of %d lamports does not cover the fee, rent and reserve", ErrNativeReserveViolation, balance.Value)
+		}
+		return balance.Value - overhead, nil
+	}
+	var lamports uint64
+	if amount != "0" {
+		if lamports, err = parseSOLAmount(amount); err != nil {
+			return 0, err
+		}
+	}
+	if lamports > balance.Value || balance.Value-lamports < overhead {
+		return 0, fmt.Errorf("%w: using %s SOL of %s SOL leaves less than the %s SOL needed for fee, rent and reserve",
+			ErrNativeReserveViolation, formatLamports(lamports), formatLamports(balance.Value), formatLamports(overhead))
+	}
+	return lamports, nil
+}
+
+// signWrapTransaction builds instructions into a transaction paid for by the
+// first signer and signs it with all of them
+func (s *SolanaChain) signWrapTransaction(ctx context.Context, instructions []solana.Instruction, signers ...solana.PrivateKey) (*solana.Transaction, error) {
+	blockhash, err := s.latestBlockhash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := solana.NewTransaction(instructions, blockhash, solana.TransactionPayer(signers[0].PublicKey()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		for i := range signers {
+			if key.Equals(signers[i].PublicKey()) {
+				return &signers[i]
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return tx, nil
+}
+
+func (s *SolanaChain) wrapResult(direction, signature, owner string, account solana.PublicKey, lamports, wrappedAfter uint64, created, closed bool) *WrapResult {
+	return &WrapResult{
+		Chain:          "solana",
+		Direction:      direction,
+		TxHash:         signature,
+		Owner:          owner,
+		Amount:         formatLamports(lamports),
+		NativeSymbol:   "SOL",
+		Wrapped:        wrappedNativeTokens["solana"],
+		WrappedBalance: formatLamports(wrappedAfter),
+		Account:        account.String(),
+		AccountCreated: created,
+		AccountClosed:  closed,
+	}
+}
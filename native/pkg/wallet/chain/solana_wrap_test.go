@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/chains/solana/broadcast"
+	solana "github.com/gagliardetto/solana-go"
+	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeWrappedSOLAccount lays out an SPL token account of the native mint
+func encodeWrappedSOLAccount(owner solana.PublicKey, lamports uint64) []byte {
+	data := make([]byte, SPLTokenAccountSize)
+	copy(data[0:32], solana.SolMint[:])
+	copy(data[32:64], owner[:])
+	binary.LittleEndian.PutUint64(data[64:72], lamports)
+	return data
+}
+
+// decodeWrapInstructions verifies the signatures of a broadcast transaction and decodes its instructions
+func decodeWrapInstructions(t *testing.T, params *broadcast.BroadcastParams) (*solana.Transaction, []any) {
+	t.Helper()
+	tx, err := solana.TransactionFromBase64(params.TransactionBase64)
+	require.NoError(t, err)
+	require.NoError(t, tx.VerifySignatures())
+
+	decoded := make([]any, 0, len(tx.Message.Instructions))
+	for _, instruction := range tx.Message.Instructions {
+		accounts, err := instruction.ResolveInstructionAccounts(&tx.Message)
+		require.NoError(t, err)
+		program, err := tx.Message.Program(instruction.ProgramIDIndex)
+		require.NoError(t, err)
+		switch program {
+		case solana.SystemProgramID:
+			inst, err := system.DecodeInstruction(accounts, instruction.Data)
+			require.NoError(t, err)
+			decoded = append(decoded, inst.Impl)
+		case solana.TokenProgramID:
+			inst, err := token.DecodeInstruction(accounts, instruction.Data)
+			require.NoError(t, err)
+			decoded = append(decoded, inst.Impl)
+		case solana.SPLAssociatedTokenAccountProgramID:
+			inst, err := associatedtokenaccount.DecodeInstruction(accounts, instruction.Data)
+			require.NoError(t, err)
+			decoded = append(decoded, inst.Impl)
+		default:
+			t.Fatalf("unexpected program %s", program)
+		}
+	}
+	return tx, decoded
+}
+
+func TestSolanaWrapNative(t *testing.T) {
+	owner := solana.NewWallet().PrivateKey
+	account, _, err := solana.FindAssociatedTokenAddress(owner.PublicKey(), solana.SolMint)
+	require.NoError(t, err)
+	// The fake node serves the wrapped SOL account as its only account
+	node := &stakeTestNode{balance: 2 * solana.LAMPORTS_PER_SOL}
+	chain, channel := newStakeTestChain(t, node, 0.01)
+
+	result, err := chain.WrapNative(context.Background(), owner.PublicKey().String(), "1.5", owner.String())
+	require.NoError(t, err)
+	assert.Equal(t, "1.5", result.Amount)
+	assert.Equal(t, "1.5", result.WrappedBalance)
+	assert.Equal(t, account.String(), result.Account)
+	assert.True(t, result.AccountCreated)
+	require.Len(t, channel.sent, 1)
+	assert.Equal(t, "wrap_sol", channel.sent[0].Metadata["operation"])
+
+	_, instructions := decodeWrapInstructions(t, channel.sent[0])
+	require.Len(t, instructions, 3)
+	_, ok := instructions[0].(*associatedtokenaccount.Create)
+	require.True(t, ok)
+	transfer, ok := instructions[1].(*system.Transfer)
+	require.True(t, ok)
+	assert.Equal(t, uint64(1_500_000_000), *transfer.Lamports)
+	assert.Equal(t, account, transfer.GetRecipientAccount().PublicKey)
+	_, ok = instructions[2].(*token.SyncNative)
+	require.True(t, ok)
+
+	t.Run("existing account", func(t *testing.T) {
+		node.stakeAccount = encodeWrappedSOLAccount(owner.PublicKey(), 250_000_000)
+		t.Cleanup(func() { node.stakeAccount = nil })
+		result, err := chain.WrapNative(context.Background(), owner.PublicKey().String(), "max", owner.String())
+		require.NoError(t, err)
+		assert.False(t, result.AccountCreated)
+		// Without an account to create, only the fee and reserve stay behind
+		assert.Equal(t, "1.989995", result.Amount)
+		assert.Equal(t, "2.239995", result.WrappedBalance)
+		_, instructions := decodeWrapInstructions(t, channel.sent[len(channel.sent)-1])
+		assert.Len(t, instructions, 2)
+	})
+
+	t.Run("reserve", func(t *testing.T) {
+		_, err := chain.WrapNative(context.Background(), owner.PublicKey().String(), "1.99", owner.String())
+		assert.ErrorIs(t, err, ErrNativeReserveViolation)
+	})
+
+	t.Run("foreign key", func(t *testing.T) {
+		_, err := chain.WrapNative(context.Background(), solana.NewWallet().PublicKey().String(), "1", owner.String())
+		assert.ErrorContains(t, err, "does not belong to")
+	})
+
+	assert.Len(t, channel.sent, 2, "rejected wraps are not broadcast")
+}
+
+func TestSolanaUnwrapNative(t *testing.T) {
+	owner := solana.NewWallet().PrivateKey
+	account, _, err := solana.FindAssociatedTokenAddress(owner.PublicKey(), solana.SolMint)
+	require.NoError(t, err)
+	node := &stakeTestNode{balance: solana.LAMPORTS_PER_SOL}
+	chain, channel := newStakeTestChain(t, node, 0)
+
+	_, err = chain.UnwrapNative(context.Background(), owner.PublicKey().String(), "max", owner.String())
+	assert.ErrorContains(t, err, "no wrapped SOL account")
+
+	node.stakeAccount = encodeWrappedSOLAccount(owner.PublicKey(), 750_000_000)
+
+	result, err := chain.UnwrapNative(context.Background(), owner.PublicKey().String(), "max", owner.String())
+	require.NoError(t, err)
+	assert.Equal(t, "0.75", result.Amount)
+	assert.Equal(t, "0", result.WrappedBalance)
+	assert.True(t, result.AccountClosed)
+	require.Len(t, channel.sent, 1)
+	assert.Equal(t, "unwrap_sol", channel.sent[0].Metadata["operation"])
+	_, instructions := decodeWrapInstructions(t, channel.sent[0])
+	require.Len(t, instructions, 1)
+	closeAccount, ok := instructions[0].(*token.CloseAccount)
+	require.True(t, ok)
+	assert.Equal(t, account, closeAccount.GetAccount().PublicKey)
+
+	result, err = chain.UnwrapNative(context.Background(), owner.PublicKey().String(), "0.25", owner.String())
+	require.NoError(t, err)
+	assert.Equal(t, "0.5", result.WrappedBalance)
+	assert.False(t, result.AccountClosed)
+	tx, instructions := decodeWrapInstructions(t, channel.sent[1])
+	assert.Equal(t, uint8(2), tx.Message.Header.NumRequiredSignatures)
+	require.Len(t, instructions, 4)
+	create, ok := instructions[0].(*system.CreateAccount)
+	require.True(t, ok)
+	temporary := create.GetNewAccount().PublicKey
+	transfer, ok := instructions[2].(*token.TransferChecked)
+	require.True(t, ok)
+	assert.Equal(t, uint64(250_000_000), *transfer.Amount)
+	assert.Equal(t, account, transfer.GetSourceAccount().PublicKey)
+	assert.Equal(t, temporary, transfer.GetDestinationAccount().PublicKey)
+	closeTemporary, ok := instructions[3].(*token.CloseAccount)
+	require.True(t, ok)
+	assert.Equal(t, temporary, closeTemporary.GetAccount().PublicKey)
+	assert.Equal(t, owner.PublicKey(), closeTemporary.GetDestinationAccount().PublicKey)
+
+	_, err = chain.UnwrapNative(context.Background(), owner.PublicKey().String(), "1", owner.String())
+	assert.ErrorContains(t, err, "cannot unwrap 1 WSOL")
+
+	t.Run("partial unwrap needs rent for the temporary account", func(t *testing.T) {
+		node.balance = 1_000_000
+		_, err := chain.UnwrapNative(context.Background(), owner.PublicKey().String(), "0.25", owner.String())
+		assert.ErrorIs(t, err, ErrNativeReserveViolation)
+	})
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+)
+
+// solanaSignatureFeeLamports is the base fee charged per transaction signature
+const solanaSignatureFeeLamports = 5000
+
+// SpendableBalance describes how much of a token an address can actually send
+type SpendableBalance struct {
+	Chain         string `json:"chain"`
+	Address       string `json:"address"`
+	Token         string `json:"token"`
+	NativeToken   string `json:"native_token"`
+	IsNative      bool   `json:"is_native"`
+	Balance       string `json:"balance"`        // balance of Token
+	NativeBalance string `json:"native_balance"` // balance that pays the fee
+	Reserve       string `json:"reserve"`        // native amount sends keep back
+	EstimatedFee  string `json:"estimated_fee"`  // fee for one transfer, in native units
+	Fee           *Fee   `json:"fee,omitempty"`  // EstimatedFee with its symbol and smallest-unit amount
+	Spendable     string `json:"spendable"`      // most of Token a single send can move
+	FeeCovered    bool   `json:"fee_covered"`    // native balance pays the fee without touching the reserve
+}
+
+// TransferFee converts a gas estimate into native units. EVM chains price gas in
+// gwei; Solana prices compute units in microlamports on top of the signature fee.
+func TransferFee(chainName string, gasLimit uint64, gasPrice string) (string, error) {
+	price, ok := new(big.Rat).SetString(strings.TrimSpace(gasPrice))
+	if !ok {
+		return "", fmt.Errorf("invalid gas price: %s", gasPrice)
+	}
+	fee := new(big.Rat).Mul(price, new(big.Rat).SetInt64(int64(gasLimit)))
+
+	switch config.NormalizeChain(chainName) {
+	case "solana":
+		// microlamports -> lamports, plus the signature fee, -> SOL
+		fee.Quo(fee, big.NewRat(1_000_000, 1))
+		fee.Add(fee, big.NewRat(solanaSignatureFeeLamports, 1))
+		fee.Quo(fee, big.NewRat(1_000_000_000, 1))
+	default:
+		// gwei -> ETH/BNB
+		fee.Quo(fee, big.NewRat(1_000_000_000, 1))
+	}
+	return formatDecimal(fee), nil
+}
+
+// ComputeSpendable fills Spendable and FeeCovered from the balances, reserve and
+// estimated fee already set on sb. Native sends pay the fee out of the amount
+// being sent; token sends only need the native balance to cover it.
+func ComputeSpendable(sb *SpendableBalance, reserve float64) error {
+	fee, ok := new(big.Rat).SetString(strings.TrimSpace(sb.EstimatedFee))
+	if !ok {
+		return fmt.Errorf("invalid fee: %s", sb.EstimatedFee)
+	}
+	available, err := availableAfterReserve(sb.NativeBalance, reserve)
+	if err != nil {
+		return err
+	}
+	sb.FeeCovered = available.Cmp(fee) >= 0
+
+	if !sb.IsNative {
+		if _, ok := new(big.Rat).SetString(strings.TrimSpace(sb.Balance)); !ok {
+			return fmt.Errorf("invalid balance: %s", sb.Balance)
+		}
+		sb.Spendable = sb.Balance
+		return nil
+	}
+
+	spendable := available.Sub(available, fee)
+	if spendable.Sign() < 0 {
+		spendable.SetInt64(0)
+	}
+	sb.Spendable = formatDecimal(spendable)
+	return nil
+}
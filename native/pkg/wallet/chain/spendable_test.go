@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+package chain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferFee(t *testing.T) {
+	fee, err := TransferFee("ethereum", 21000, "20")
+	require.NoError(t, err)
+	assert.Equal(t, "0.00042", fee)
+
+	// 150 CU at 1 microlamport adds 0.00015 lamports to the 5000 lamport signature fee
+	fee, err = TransferFee("solana", 150, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "0.00000500000015", fee)
+
+	_, err = TransferFee("bsc", 21000, "fast")
+	assert.Error(t, err)
+}
+
+func TestComputeSpendable(t *testing.T) {
+	t.Run("native subtracts reserve and fee", func(t *testing.T) {
+		sb := &SpendableBalance{IsNative: true, Balance: "1", NativeBalance: "1", EstimatedFee: "0.00042"}
+		require.NoError(t, ComputeSpendable(sb, 0.005))
+		assert.Equal(t, "0.99458", sb.Spendable)
+		assert.True(t, sb.FeeCovered)
+	})
+
+	t.Run("native floors at zero", func(t *testing.T) {
+		sb := &SpendableBalance{IsNative: true, Balance: "0.005", NativeBalance: "0.005", EstimatedFee: "0.00042"}
+		require.NoError(t, ComputeSpendable(sb, 0.005))
+		assert.Equal(t, "0", sb.Spendable)
+		assert.False(t, sb.FeeCovered)
+	})
+
+	t.Run("token keeps full balance and flags fee coverage", func(t *testing.T) {
+		sb := &SpendableBalance{Balance: "250.5", NativeBalance: "0.0052", EstimatedFee: "0.0013"}
+		require.NoError(t, ComputeSpendable(sb, 0.005))
+		assert.Equal(t, "250.5", sb.Spendable)
+		assert.False(t, sb.FeeCovered)
+
+		sb.NativeBalance = "0.01"
+		require.NoError(t, ComputeSpendable(sb, 0.005))
+		assert.True(t, sb.FeeCovered)
+	})
+
+	t.Run("invalid fee", func(t *testing.T) {
+		sb := &SpendableBalance{IsNative: true, Balance: "1", NativeBalance: "1", EstimatedFee: "?"}
+		assert.Error(t, ComputeSpendable(sb, 0))
+	})
+}
+
+func TestComputeBalanceBreakdown(t *testing.T) {
+	b := &BalanceBreakdown{Balance: "2", Breakdown: []LockedBalance{
+		{Kind: LockedKindRentReserve, Amount: "0.00089088"},
+		{Kind: LockedKindInFlight, Amount: "0.5"},
+		{Kind: LockedKindStaked, Amount: "10", External: true},
+	}}
+	require.NoError(t, ComputeBalanceBreakdown(b))
+	assert.Equal(t, "12", b.Total)
+	assert.Equal(t, "1.49910912", b.Available)
+	assert.Equal(t, "10.50089088", b.Locked)
+
+	// Overlapping locks never make the available balance negative
+	b = &BalanceBreakdown{Balance: "0.1", Breakdown: []LockedBalance{{Kind: LockedKindInFlight, Amount: "0.5"}}}
+	require.NoError(t, ComputeBalanceBreakdown(b))
+	assert.Equal(t, "0", b.Available)
+	assert.Equal(t, "0.1", b.Locked)
+
+	assert.Error(t, ComputeBalanceBreakdown(&BalanceBreakdown{Balance: "lots"}))
+}
@@ -14,8 +14,12 @@ type TokenInfo struct {
 	IsNative    bool     `json:"is_native"`    // Whether this is the native token of the chain
 	Decimals    int      `json:"decimals"`     // Token decimals
 	Description string   `json:"description"`  // Human-readable description
+	LogoURI     string   `json:"logo_uri,omitempty"` // Logo from the bundled token list
 }
 
+// TokenListLogoBase is the root of the bundled token list's logo assets
+const TokenListLogoBase = "https://raw.githubusercontent.com/trustwallet/assets/master/blockchains/"
+
 // TokenMappingConfig provides centralized token identifier resolution
 type TokenMappingConfig struct {
 	tokens map[string]*TokenInfo
@@ -43,6 +47,7 @@ func (tm *TokenMappingConfig) initializeDefaultMappings() {
 		IsNative:    true,
 		Decimals:    18,
 		Description: "Ethereum native token",
+		LogoURI:     TokenListLogoBase + "ethereum/info/logo.png",
 	}
 	tm.registerToken(ethToken)
 
@@ -54,6 +59,7 @@ func (tm *TokenMappingConfig) initializeDefaultMappings() {
 		IsNative:    true,
 		Decimals:    18,
 		Description: "Binance Smart Chain native token",
+		LogoURI:     TokenListLogoBase + "smartchain/info/logo.png",
 	}
 	tm.registerToken(bnbToken)
 
@@ -65,6 +71,7 @@ func (tm *TokenMappingConfig) initializeDefaultMappings() {
 		IsNative:    true,
 		Decimals:    9,
 		Description: "Solana native token",
+		LogoURI:     TokenListLogoBase + "solana/info/logo.png",
 	}
 	tm.registerToken(solToken)
 }
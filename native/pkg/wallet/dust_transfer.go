@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+)
+
+// DustTransferCheck is the outcome of comparing a send against the chain's
+// minimum transfer amount, or against its estimated fee when no minimum applies
+type DustTransferCheck struct {
+	Chain        string
+	Token        string
+	Amount       string
+	IsNative     bool
+	EstimatedFee string // in the chain's native token; empty when unknown
+	Minimum      string // the configured minimum; empty when the fee heuristic applied
+	Dust         bool
+	Reason       string
+}
+
+// CheckDustTransfer reports whether sending amount of token on a normalized
+// chain is dust. A configured minimum for the native token or for the token's
+// symbol or address takes precedence. Otherwise a native amount must exceed
+// estimatedFee, and a token amount must be worth more in USD than the fee,
+// which is only checked when priceFeed can price both. An amount that cannot
+// be parsed is never dust, it is rejected by the send itself.
+func CheckDustTransfer(ctx context.Context, minimum config.MinTransferConfig, priceFeed PriceFeed, chainName, token, amount, estimatedFee string) *DustTransferCheck {
+	nativeSymbol := NativeTokenSymbol(chainName)
+	metadata := LookupTokenMetadata(chainName, token)
+	check := &DustTransferCheck{
+		Chain:        chainName,
+		Token:        token,
+		Amount:       amount,
+		IsNative:     metadata.IsNative,
+		EstimatedFee: estimatedFee,
+	}
+
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok || value.Sign() <= 0 {
+		return check
+	}
+	fee, feeKnown := new(big.Rat).SetString(strings.TrimSpace(estimatedFee))
+	feeText := ""
+	if feeKnown {
+		feeText = fmt.Sprintf(" (estimated fee %s %s)", estimatedFee, nativeSymbol)
+	}
+
+	symbol := metadata.Symbol
+	if symbol == "" {
+		symbol = token
+	}
+	limit, configured := minimum.Native, minimum.Native > 0
+	if !check.IsNative {
+		limit, configured = minimum.TokenMinimum(token)
+		if !configured && metadata.Symbol != "" {
+			limit, configured = minimum.TokenMinimum(metadata.Symbol)
+		}
+	}
+
+	switch {
+	case configured:
+		check.Minimum = fmt.Sprint(limit)
+		if value.Cmp(new(big.Rat).SetFloat64(limit)) < 0 {
+			check.Dust = true
+			check.Reason = fmt.Sprintf("amount %s %s is below the %g %s minimum transfer%s", amount, symbol, limit, symbol, feeText)
+		}
+	case !feeKnown:
+	case check.IsNative:
+		if value.Cmp(fee) <= 0 {
+			check.Dust = true
+			check.Reason = fmt.Sprintf("amount %s %s does not exceed the estimated fee of %s %s", amount, symbol, estimatedFee, nativeSymbol)
+		}
+	case priceFeed != nil:
+		tokenPrice, err := CurrentUSDPrice(ctx, priceFeed, symbol)
+		if err != nil {
+			break
+		}
+		nativePrice, err := CurrentUSDPrice(ctx, priceFeed, nativeSymbol)
+		if err != nil {
+			break
+		}
+		amountUSD, _ := new(big.Rat).Mul(value, new(big.Rat).SetFloat64(tokenPrice.Price)).Float64()
+		feeUSD, _ := new(big.Rat).Mul(fee, new(big.Rat).SetFloat64(nativePrice.Price)).Float64()
+		if amountUSD <= feeUSD {
+			check.Dust = true
+			check.Reason = fmt.Sprintf("amount %s %s ($%.4f) does not exceed the estimated fee of %s %s ($%.4f)", amount, symbol, amountUSD, estimatedFee, nativeSymbol, feeUSD)
+		}
+	}
+	return check
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+)
+
+func TestCheckDustTransfer(t *testing.T) {
+	ctx := context.Background()
+	feed := fixedPriceFeed{"ETH": 2000, "USDC": 1, "PEPE": 0.00001}
+	minimum := config.MinTransferConfig{Native: 0.001, Tokens: map[string]float64{"USDC": 1}}
+	const usdc = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+
+	cases := []struct {
+		name    string
+		minimum config.MinTransferConfig
+		token   string
+		amount  string
+		dust    bool
+	}{
+		{"native above the minimum", minimum, "", "0.01", false},
+		{"native below the minimum", minimum, "ETH", "0.0005", true},
+		{"token minimum matched by contract symbol", minimum, usdc, "0.5", true},
+		{"token at the minimum", minimum, "USDC", "1", false},
+		{"native without a minimum below the fee", config.MinTransferConfig{}, "", "0.0003", true},
+		{"native without a minimum above the fee", config.MinTransferConfig{}, "", "0.0005", false},
+		{"unlisted token worth less than the fee", minimum, "PEPE", "10000", true},
+		{"unlisted token worth more than the fee", minimum, "PEPE", "1000000", false},
+		{"unpriced token is not checked", minimum, "0x1111111111111111111111111111111111111111", "0.000001", false},
+		{"unparseable amount", minimum, "", "max", false},
+	}
+	for _, tc := range cases {
+		check := CheckDustTransfer(ctx, tc.minimum, feed, "ethereum", tc.token, tc.amount, "0.00042")
+		if check.Dust != tc.dust {
+			t.Errorf("%s: expected dust=%v, got %+v", tc.name, tc.dust, check)
+		}
+		if check.Dust && !strings.Contains(check.Reason, "0.00042 ETH") {
+			t.Errorf("%s: expected the reason to compare against the fee, got %q", tc.name, check.Reason)
+		}
+	}
+
+	if CheckDustTransfer(ctx, config.MinTransferConfig{}, feed, "ethereum", "", "0.0001", "").Dust {
+		t.Error("expected no heuristic without a fee estimate")
+	}
+}
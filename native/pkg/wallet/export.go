@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/security"
+	"go.uber.org/zap"
+)
+
+// Audit actions recorded for wallet exports
+const (
+	AuditActionWalletExport       = "wallet_export"
+	AuditActionWalletExportDenied = "wallet_export_denied"
+)
+
+// Wrong passwords tolerated by ExportWallet before it refuses further attempts
+// until the oldest failure leaves the window
+const (
+	maxExportFailures   = 5
+	exportFailureWindow = 15 * time.Minute
+)
+
+var (
+	// ErrIncorrectPassword is returned when the password does not decrypt the wallet
+	ErrIncorrectPassword = errors.New("incorrect password")
+	// ErrExportRateLimited is returned by ExportWallet after too many wrong passwords
+	ErrExportRateLimited = errors.New("too many failed export attempts")
+)
+
+// WalletExport is the secret material of a wallet, for backup or migration.
+// Mnemonic is empty for wallets imported from a private key.
+type WalletExport struct {
+	Address    string `json:"address"`
+	Mnemonic   string `json:"mnemonic,omitempty"`
+	PrivateKey string `json:"private_key"`
+}
+
+// exportGuard counts recent wrong export passwords
+type exportGuard struct {
+	mu       sync.Mutex
+	failures []time.Time
+	now      func() time.Time
+}
+
+func newExportGuard() *exportGuard {
+	return &exportGuard{now: time.Now}
+}
+
+// retryAfter returns how long to wait before another attempt is allowed, or
+// zero when one is allowed now
+func (g *exportGuard) retryAfter() time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.now()
+	recent := g.failures[:0]
+	for _, failedAt := range g.failures {
+		if now.Sub(failedAt) < exportFailureWindow {
+			recent = append(recent, failedAt)
+		}
+	}
+	g.failures = recent
+	if len(recent) < maxExportFailures {
+		return 0
+	}
+	return recent[0].Add(exportFailureWindow).Sub(now)
+}
+
+func (g *exportGuard) fail() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.failures = append(g.failures, g.now())
+}
+
+func (g *exportGuard) reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.failures = nil
+}
+
+// ExportWallet decrypts the mnemonic and private key of the selected wallet.
+// The wallet must be unlocked and password is checked again against the
+// stored keys. Every attempt is audited, and after maxExportFailures wrong
+// passwords within exportFailureWindow attempts fail with ErrExportRateLimited.
+func (wm *WalletManager) ExportWallet(ctx context.Context, password, source string) (*WalletExport, error) {
+	if err := wm.requireUnlocked(); err != nil {
+		return nil, err
+	}
+	encryptedWallet, err := wm.loadWallet()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wallet: %w", err)
+	}
+	if encryptedWallet.WatchOnly || encryptedWallet.Hardware || encryptedWallet.EncryptedPrivateKey == nil {
+		return nil, errors.New("wallet holds no exportable keys")
+	}
+
+	if wait := wm.exportGuard.retryAfter(); wait > 0 {
+		wm.auditExport(AuditActionWalletExportDenied, "rate_limited", source, encryptedWallet.Address)
+		return nil, fmt.Errorf("%w, retry in %s", ErrExportRateLimited, wait.Round(time.Second))
+	}
+
+	privateKey, err := security.DecryptWithPassword(encryptedWallet.EncryptedPrivateKey, password)
+	if err != nil {
+		wm.exportGuard.fail()
+		wm.auditExport(AuditActionWalletExportDenied, "incorrect_password", source, encryptedWallet.Address)
+		return nil, ErrIncorrectPassword
+	}
+	var mnemonic string
+	if encryptedWallet.EncryptedMnemonic != nil {
+		if mnemonic, err = security.DecryptWithPassword(encryptedWallet.EncryptedMnemonic, password); err != nil {
+			return nil, fmt.Errorf("failed to decrypt mnemonic: %w", err)
+		}
+	}
+
+	wm.exportGuard.reset()
+	wm.auditExport(AuditActionWalletExport, "backup", source, encryptedWallet.Address)
+	wm.logger.Warn("Wallet secrets exported", zap.String("address", encryptedWallet.Address), zap.String("source", source))
+	return &WalletExport{
+		Address:    encryptedWallet.Address,
+		Mnemonic:   mnemonic,
+		PrivateKey: privateKey,
+	}, nil
+}
+
+// auditExport records an export attempt in the audit log
+func (wm *WalletManager) auditExport(action, reason, source, address string) {
+	if _, err := wm.auditLogger.LogSecurityEvent(action, reason, "", source, address); err != nil {
+		wm.logger.Error("Failed to audit wallet export", zap.String("action", action), zap.Error(err))
+	}
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+)
+
+const exportTestMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestExportWalletReturnsSecrets(t *testing.T) {
+	ctx := context.Background()
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	address, _, _, err := wm.ImportWallet(ctx, exportTestMnemonic, "password123", "ethereum", "")
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+
+	export, err := wm.ExportWallet(ctx, "password123", "user")
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if export.Address != address || export.Mnemonic != exportTestMnemonic || export.PrivateKey == "" {
+		t.Errorf("unexpected export %+v", export)
+	}
+	if entries := wm.auditLogger.GetAuditLogByAction(AuditActionWalletExport); len(entries) != 1 {
+		t.Errorf("expected one export audit entry, got %d", len(entries))
+	}
+}
+
+func TestExportWalletRejectsWrongPassword(t *testing.T) {
+	ctx := context.Background()
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	if _, _, _, err := wm.ImportWallet(ctx, exportTestMnemonic, "password123", "ethereum", ""); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+
+	if _, err := wm.ExportWallet(ctx, "wrong-password", "user"); !errors.Is(err, ErrIncorrectPassword) {
+		t.Fatalf("expected ErrIncorrectPassword, got %v", err)
+	}
+	if entries := wm.auditLogger.GetAuditLogByAction(AuditActionWalletExportDenied); len(entries) != 1 {
+		t.Errorf("expected one denied export audit entry, got %d", len(entries))
+	}
+}
+
+func TestExportWalletRequiresUnlock(t *testing.T) {
+	ctx := context.Background()
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	if _, _, _, err := wm.ImportWallet(ctx, exportTestMnemonic, "password123", "ethereum", ""); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	wm.LockWallet()
+
+	if _, err := wm.ExportWallet(ctx, "password123", "user"); !errors.Is(err, ErrWalletLocked) {
+		t.Fatalf("expected ErrWalletLocked, got %v", err)
+	}
+}
+
+func TestExportWalletRateLimitsWrongPasswords(t *testing.T) {
+	ctx := context.Background()
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	if _, _, _, err := wm.ImportWallet(ctx, exportTestMnemonic, "password123", "ethereum", ""); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	now := time.Now()
+	wm.exportGuard.now = func() time.Time { return now }
+
+	for i := 0; i < maxExportFailures; i++ {
+		if _, err := wm.ExportWallet(ctx, "wrong-password", "user"); !errors.Is(err, ErrIncorrectPassword) {
+			t.Fatalf("attempt %d: expected ErrIncorrectPassword, got %v", i+1, err)
+		}
+	}
+	// The right password is refused too until the window passes
+	if _, err := wm.ExportWallet(ctx, "password123", "user"); !errors.Is(err, ErrExportRateLimited) {
+		t.Fatalf("expected ErrExportRateLimited, got %v", err)
+	}
+
+	now = now.Add(exportFailureWindow)
+	if _, err := wm.ExportWallet(ctx, "password123", "user"); err != nil {
+		t.Fatalf("expected export after the window, got %v", err)
+	}
+}
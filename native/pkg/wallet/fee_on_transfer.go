@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// DetectTransferFee checks whether sending amount of token from from to to on
+// chainName loses part of it to a transfer fee. It returns nil when the
+// recipient would receive the full amount.
+func (wm *WalletManager) DetectTransferFee(ctx context.Context, chainName, from, to, amount, token string) (*chain.TokenTransferFee, error) {
+	chainImpl, err := wm.chainFactory.GetChain(NormalizeChain(chainName))
+	if err != nil {
+		return nil, err
+	}
+	detector, ok := chainImpl.(chain.TransferFeeDetector)
+	if !ok {
+		return nil, fmt.Errorf("transfer fee detection is not supported on %s", chainName)
+	}
+	return detector.DetectTransferFee(ctx, from, to, amount, token)
+}
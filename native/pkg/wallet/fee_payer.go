@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// ErrFeePayerNotOwned is returned when the fee payer requested for a send is
+// not an account of the unlocked wallet
+var ErrFeePayerNotOwned = errors.New("fee payer is not an account of this wallet")
+
+// feePayerKey returns the private key of the fee payer requested with
+// chain.WithFeePayer, or "" when the sender pays its own fee
+func (wm *WalletManager) feePayerKey(ctx context.Context, chainName, from string) (string, error) {
+	feePayer, ok := chain.FeePayerFromContext(ctx)
+	if !ok || feePayer == from {
+		return "", nil
+	}
+	if chainName != "solana" {
+		return "", fmt.Errorf("a separate fee payer is not supported on %s", chainName)
+	}
+	privateKey, err := wm.signingKey(feePayer, "pay a transaction fee")
+	if errors.Is(err, ErrAddressNotInWallet) {
+		return "", fmt.Errorf("%w: %s", ErrFeePayerNotOwned, feePayer)
+	}
+	return privateKey, err
+}
+
+// sendWithFeePayer sends from from with the fee paid by the account of
+// feePayerKey; both accounts sign
+func (wm *WalletManager) sendWithFeePayer(ctx context.Context, chainImpl chain.IChain, from, to, amount, token, feePayerKey string) (string, error) {
+	sender, ok := chainImpl.(chain.FeePayerSender)
+	if !ok {
+		return "", errors.New("separate fee payers are not supported on solana in this build")
+	}
+	privateKey, err := wm.signingKey(from, "sign a sponsored transaction")
+	if err != nil {
+		return "", err
+	}
+	return sender.SendTransactionWithFeePayer(ctx, from, to, amount, token, privateKey, feePayerKey)
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// FiatValue is an estimated USD value of a transaction amount
+type FiatValue struct {
+	USD        float64
+	Price      float64   // USD price of one unit of Symbol
+	Symbol     string    // symbol that was priced
+	PricedAt   time.Time // zero when the current price was used
+	Historical bool
+	// Stale is set when the feed was down and its last known price, quoted
+	// at PricedAt, was used instead
+	Stale bool
+	// Unavailable explains why the price feed could not value the amount; USD
+	// is zero when it is set
+	Unavailable string
+}
+
+// EstimateUSDValue values amount of token on chainName in USD. A zero at uses
+// the current price, as for pending transactions; otherwise the price at that
+// time is used, as for confirmed ones, which needs a TokenHistoricalPriceFeed
+// or HistoricalPriceFeed (stablecoins aside). token may be empty or the native
+// symbol, a symbol, or a contract/mint from the bundled token list. EVM native amounts given as hex
+// wei, as dApps send them, are converted first. ok is false when the amount
+// cannot be valued at all, so callers can simply omit the value; when the
+// feed itself is down the value is marked Unavailable with the reason.
+func EstimateUSDValue(ctx context.Context, priceFeed PriceFeed, chainName, token, amount string, at time.Time) (value *FiatValue, ok bool) {
+	if priceFeed == nil {
+		return nil, false
+	}
+	chainName = NormalizeChain(chainName)
+	symbol := fiatValueSymbol(chainName, token)
+	if symbol == "" {
+		return nil, false
+	}
+
+	amount = strings.TrimSpace(amount)
+	if strings.HasPrefix(strings.ToLower(amount), "0x") {
+		if symbol != NativeTokenSymbol(chainName) || chainName == "solana" {
+			return nil, false
+		}
+		amount = formatUnits(parseHexBig(amount), 18)
+	}
+	units, parsed := new(big.Rat).SetString(amount)
+	if !parsed || units.Sign() < 0 {
+		return nil, false
+	}
+
+	value = &FiatValue{Symbol: symbol, PricedAt: at, Historical: !at.IsZero()}
+	var price float64
+	var err error
+	if at.IsZero() {
+		var current PriceResult
+		current, err = CurrentUSDPrice(ctx, priceFeed, symbol)
+		price, value.Stale, value.PricedAt = current.Price, current.Stale, current.FetchedAt
+	} else if historical, isHistorical := priceFeed.(TokenHistoricalPriceFeed); isHistorical {
+		// The feed can price the token at any time, so any failure means the
+		// history could not be fetched
+		price, err = historical.GetHistoricalPrice(ctx, token, chainName, at)
+		if err != nil && !errors.Is(err, ErrPriceFeedUnavailable) {
+			err = fmt.Errorf("%w: %v", ErrPriceFeedUnavailable, err)
+		}
+	} else if historical, isHistorical := priceFeed.(HistoricalPriceFeed); isHistorical {
+		price, err = historical.USDPriceAt(ctx, symbol, at)
+	} else if usdStablecoins[symbol] {
+		price = 1
+	} else {
+		return nil, false
+	}
+	if errors.Is(err, ErrPriceFeedUnavailable) {
+		value.Unavailable = err.Error()
+		return value, true
+	}
+	if err != nil || price <= 0 {
+		return nil, false
+	}
+
+	value.Price = price
+	value.USD, _ = new(big.Rat).Mul(units, new(big.Rat).SetFloat64(price)).Float64()
+	return value, true
+}
+
+// PriceFee sets fee.USDValue from the current price of the fee's native
+// token. The fee is left without a USD value when there is no feed or no
+// price, even a stale one.
+func PriceFee(ctx context.Context, priceFeed PriceFeed, fee *chain.Fee) {
+	if priceFeed == nil || fee == nil {
+		return
+	}
+	amount, ok := new(big.Rat).SetString(fee.Amount)
+	if !ok {
+		return
+	}
+	price, err := CurrentUSDPrice(ctx, priceFeed, fee.Symbol)
+	if err != nil || price.Price <= 0 {
+		return
+	}
+	usd, _ := new(big.Rat).Mul(amount, new(big.Rat).SetFloat64(price.Price)).Float64()
+	fee.USDValue = fmt.Sprintf("%.2f", usd)
+}
+
+// fiatValueSymbol resolves token to the symbol a price feed understands
+func fiatValueSymbol(chainName, token string) string {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return NativeTokenSymbol(chainName)
+	}
+	if info := LookupTokenMetadata(chainName, token); info.Known {
+		return strings.ToUpper(info.Symbol)
+	}
+	// Addresses missing from the token list cannot be priced by symbol
+	if len(token) > 11 || strings.HasPrefix(strings.ToLower(token), "0x") {
+		return ""
+	}
+	return strings.ToUpper(token)
+}
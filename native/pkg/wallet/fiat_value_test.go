@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// historicalPriceFeed prices by symbol now and from a fixed table in the past
+type historicalPriceFeed struct {
+	fixedPriceFeed
+	past map[string]float64
+}
+
+func (f historicalPriceFeed) USDPriceAt(_ context.Context, symbol string, _ time.Time) (float64, error) {
+	if price, ok := f.past[symbol]; ok {
+		return price, nil
+	}
+	return 0, fmt.Errorf("no historical price for %s", symbol)
+}
+
+func TestEstimateUSDValue(t *testing.T) {
+	ctx := context.Background()
+	current := fixedPriceFeed{"ETH": 2000, "SOL": 150, "USDC": 1}
+	confirmedAt := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+
+	cases := []struct {
+		name   string
+		feed   PriceFeed
+		chain  string
+		token  string
+		amount string
+		at     time.Time
+		want   float64
+		ok     bool
+	}{
+		{"native at current price", current, "eth", "", "1.5", time.Time{}, 3000, true},
+		{"native symbol", current, "solana", "SOL", "2", time.Time{}, 300, true},
+		{"hex wei from a dApp", current, "ethereum", "ETH", "0xde0b6b3a7640000", time.Time{}, 2000, true},
+		{"token contract from the bundled list", current, "ethereum", "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", "25", time.Time{}, 25, true},
+		{"historical price", historicalPriceFeed{current, map[string]float64{"ETH": 1000}}, "ethereum", "ETH", "2", confirmedAt, 2000, true},
+		{"stablecoin without a historical feed", current, "ethereum", "USDC", "10", confirmedAt, 10, true},
+		{"no historical price source", current, "ethereum", "ETH", "1", confirmedAt, 0, false},
+		{"unpriced token", current, "ethereum", "PEPE", "1", time.Time{}, 0, false},
+		{"unknown contract", current, "ethereum", "0x1111111111111111111111111111111111111111", "1", time.Time{}, 0, false},
+		{"unparseable amount", current, "ethereum", "", "lots", time.Time{}, 0, false},
+		{"no price feed", nil, "ethereum", "", "1", time.Time{}, 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, ok := EstimateUSDValue(ctx, tc.feed, tc.chain, tc.token, tc.amount, tc.at)
+			if ok != tc.ok {
+				t.Fatalf("expected ok=%t, got %t (%+v)", tc.ok, ok, value)
+			}
+			if !ok {
+				return
+			}
+			if math.Abs(value.USD-tc.want) > 1e-9 {
+				t.Errorf("expected $%.2f, got $%.2f", tc.want, value.USD)
+			}
+			if value.Historical != !tc.at.IsZero() {
+				t.Errorf("expected historical=%t", !tc.at.IsZero())
+			}
+		})
+	}
+}
+
+// outagePriceFeed is a price feed that is down but remembers earlier quotes
+type outagePriceFeed struct {
+	lastKnown map[string]float64
+	quotedAt  time.Time
+}
+
+func (f outagePriceFeed) USDPrice(_ context.Context, symbol string) (float64, error) {
+	return 0, fmt.Errorf("%w: quote timed out", ErrPriceFeedUnavailable)
+}
+
+func (f outagePriceFeed) LastKnownUSDPrice(symbol string) (float64, time.Time, bool) {
+	price, ok := f.lastKnown[symbol]
+	return price, f.quotedAt, ok
+}
+
+func TestEstimateUSDValueWhileFeedIsDown(t *testing.T) {
+	ctx := context.Background()
+	quotedAt := time.Now().Add(-20 * time.Minute)
+	feed := outagePriceFeed{lastKnown: map[string]float64{"ETH": 2000}, quotedAt: quotedAt}
+
+	value, ok := EstimateUSDValue(ctx, feed, "ethereum", "ETH", "2", time.Time{})
+	if !ok || !value.Stale || value.USD != 4000 || !value.PricedAt.Equal(quotedAt) {
+		t.Errorf("expected a stale value from the last known price, got %+v", value)
+	}
+
+	value, ok = EstimateUSDValue(ctx, feed, "solana", "SOL", "2", time.Time{})
+	if !ok || value.USD != 0 || !strings.Contains(value.Unavailable, "quote timed out") {
+		t.Errorf("expected the value to be unavailable with the reason, got %+v", value)
+	}
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// ErrInsecurePermissions is returned by CheckStoragePermissions when the
+// policy refuses to run with wallet files others can access
+var ErrInsecurePermissions = errors.New("wallet files are accessible beyond their owner")
+
+// CheckStoragePermissions checks that the persisted wallet state, including
+// the encrypted wallet, is private to its owner. Exposed paths are logged,
+// hardened or refused according to onInsecure, one of the
+// config.FilePermissions* actions. Stores without file permissions pass.
+func (wm *WalletManager) CheckStoragePermissions(onInsecure string) error {
+	hardener, ok := wm.store.(storage.PermissionHardener)
+	if !ok {
+		return nil
+	}
+	issues, err := hardener.CheckPermissions()
+	if err != nil {
+		return fmt.Errorf("failed to check wallet file permissions: %w", err)
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(onInsecure)) {
+	case config.FilePermissionsRefuse:
+		descriptions := make([]string, len(issues))
+		for i, issue := range issues {
+			descriptions[i] = issue.String()
+		}
+		return fmt.Errorf("%w: %s", ErrInsecurePermissions, strings.Join(descriptions, "; "))
+	case config.FilePermissionsHarden:
+		_, err := wm.HardenStoragePermissions()
+		return err
+	default:
+		for _, issue := range issues {
+			wm.logger.Warn("Wallet file is accessible by group or others",
+				zap.String("path", issue.Path),
+				zap.String("mode", fmt.Sprintf("%04o", issue.Mode)),
+				zap.String("expected", fmt.Sprintf("%04o", issue.Want)))
+		}
+		return nil
+	}
+}
+
+// HardenStoragePermissions restricts the persisted wallet state to its owner
+// again and returns the paths it changed
+func (wm *WalletManager) HardenStoragePermissions() ([]storage.PermissionIssue, error) {
+	hardener, ok := wm.store.(storage.PermissionHardener)
+	if !ok {
+		return nil, nil
+	}
+	hardened, err := hardener.HardenPermissions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to harden wallet file permissions: %w", err)
+	}
+	for _, issue := range hardened {
+		wm.logger.Info("Restricted wallet file permissions",
+			zap.String("path", issue.Path),
+			zap.String("mode", fmt.Sprintf("%04o", issue.Want)))
+	}
+	return hardened, nil
+}
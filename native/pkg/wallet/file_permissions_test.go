@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestCheckStoragePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not enforced on windows")
+	}
+	dir := filepath.Join(t.TempDir(), "mainnet")
+	store, err := storage.NewFileStateStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	core, logs := observer.New(zapcore.InfoLevel)
+	wm := NewWalletManagerWithStore(store, zap.New(core))
+	if err := store.Put(context.Background(), storage.NamespaceWallets, legacyWalletStoreKey, []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if err := wm.CheckStoragePermissions(config.FilePermissionsRefuse); err != nil {
+		t.Fatalf("expected private wallet files to pass, got %v", err)
+	}
+
+	// Loosen the wallet file as a misconfigured deployment might
+	walletFile := filepath.Join(dir, storage.NamespaceWallets, "wallet.json")
+	if err := os.Chmod(walletFile, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := wm.CheckStoragePermissions(config.FilePermissionsWarn); err != nil {
+		t.Fatalf("warn must not fail, got %v", err)
+	}
+	warnings := logs.FilterMessage("Wallet file is accessible by group or others").All()
+	if len(warnings) != 1 || warnings[0].ContextMap()["path"] != walletFile || warnings[0].ContextMap()["mode"] != "0644" {
+		t.Fatalf("expected a warning naming the wallet file, got %v", warnings)
+	}
+
+	if err := wm.CheckStoragePermissions(config.FilePermissionsRefuse); !errors.Is(err, ErrInsecurePermissions) {
+		t.Fatalf("expected refuse to fail with ErrInsecurePermissions, got %v", err)
+	}
+
+	if err := wm.CheckStoragePermissions(config.FilePermissionsHarden); err != nil {
+		t.Fatalf("harden failed: %v", err)
+	}
+	info, err := os.Stat(walletFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected the wallet file to be restored to 0600, got %o", perm)
+	}
+
+	if err := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil).CheckStoragePermissions(config.FilePermissionsRefuse); err != nil {
+		t.Errorf("expected stores without file permissions to pass, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/security"
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// ErrWalletFrozen is returned by unlocking, signing and sending while the wallet is frozen
+var ErrWalletFrozen = errors.New("wallet is frozen")
+
+// ErrWalletNotFrozen is returned when unfreezing a wallet that is not frozen
+var ErrWalletNotFrozen = errors.New("wallet is not frozen")
+
+// Audit actions recorded for freezes
+const (
+	AuditActionWalletFreeze   = "wallet_freeze"
+	AuditActionWalletUnfreeze = "wallet_unfreeze"
+)
+
+// freezeStoreKey is the key of the freeze state within storage.NamespaceSecurity
+const freezeStoreKey = "freeze"
+
+// FreezeStatus describes the emergency freeze of the wallet
+type FreezeStatus struct {
+	Frozen   bool      `json:"frozen"`
+	Reason   string    `json:"reason,omitempty"`
+	Source   string    `json:"source,omitempty"` // "ai_agent", "user", "system"
+	FrozenAt time.Time `json:"frozen_at,omitempty"`
+}
+
+// FreezeListener is notified after the wallet is frozen or unfrozen
+type FreezeListener func(status FreezeStatus)
+
+// OnFreezeChange registers listener to be called after every freeze and unfreeze,
+// e.g. to cancel in-flight work and notify subscribers
+func (wm *WalletManager) OnFreezeChange(listener FreezeListener) {
+	wm.freezeMu.Lock()
+	defer wm.freezeMu.Unlock()
+	wm.freezeListeners = append(wm.freezeListeners, listener)
+}
+
+// FreezeWallet immediately locks the wallet and refuses unlocking, signing and
+// sending until UnfreezeWallet is called. The freeze is persisted so restarting
+// the host does not lift it. Freezing a frozen wallet returns the existing freeze.
+func (wm *WalletManager) FreezeWallet(ctx context.Context, reason, source string) (*FreezeStatus, error) {
+	// Drop the keys before anything else can fail
+	wm.LockWallet()
+
+	wm.freezeMu.Lock()
+	if wm.frozen != nil {
+		status := *wm.frozen
+		wm.freezeMu.Unlock()
+		return &status, nil
+	}
+	status := FreezeStatus{Frozen: true, Reason: reason, Source: source, FrozenAt: time.Now().UTC()}
+	wm.frozen = &status
+	listeners := append([]FreezeListener(nil), wm.freezeListeners...)
+	wm.freezeMu.Unlock()
+
+	// The freeze holds in memory even if it cannot be persisted
+	data, err := json.Marshal(status)
+	if err == nil {
+		err = wm.store.Put(ctx, storage.NamespaceSecurity, freezeStoreKey, data)
+	}
+	if err != nil {
+		wm.logger.Error("Failed to persist wallet freeze", zap.Error(err))
+	}
+
+	wm.auditFreezeChange(AuditActionWalletFreeze, reason, source)
+	wm.logger.Warn("Wallet frozen", zap.String("reason", reason), zap.String("source", source))
+	for _, listener := range listeners {
+		listener(status)
+	}
+
+	if err != nil {
+		return &status, fmt.Errorf("wallet frozen but the freeze could not be persisted: %w", err)
+	}
+	return &status, nil
+}
+
+// UnfreezeWallet lifts the freeze after verifying the wallet password. The
+// wallet stays locked and has to be unlocked separately.
+func (wm *WalletManager) UnfreezeWallet(ctx context.Context, password, source string) error {
+	if !wm.IsFrozen() {
+		return ErrWalletNotFrozen
+	}
+	if wm.HasWallet() {
+		encryptedWallet, err := wm.loadWallet()
+		if err != nil {
+			return fmt.Errorf("failed to load wallet: %w", err)
+		}
+		if _, err := security.DecryptWithPassword(encryptedWallet.EncryptedPrivateKey, password); err != nil {
+			return fmt.Errorf("incorrect password or corrupted wallet: %w", err)
+		}
+	}
+
+	if err := wm.store.Delete(ctx, storage.NamespaceSecurity, freezeStoreKey); err != nil {
+		return fmt.Errorf("failed to clear wallet freeze: %w", err)
+	}
+
+	wm.freezeMu.Lock()
+	wm.frozen = nil
+	listeners := append([]FreezeListener(nil), wm.freezeListeners...)
+	wm.freezeMu.Unlock()
+
+	wm.auditFreezeChange(AuditActionWalletUnfreeze, "", source)
+	wm.logger.Info("Wallet unfrozen", zap.String("source", source))
+	for _, listener := range listeners {
+		listener(FreezeStatus{Frozen: false, Source: source})
+	}
+	return nil
+}
+
+// IsFrozen returns whether the wallet is frozen
+func (wm *WalletManager) IsFrozen() bool {
+	wm.freezeMu.RLock()
+	defer wm.freezeMu.RUnlock()
+	return wm.frozen != nil
+}
+
+// GetFreezeStatus returns the current freeze state
+func (wm *WalletManager) GetFreezeStatus() *FreezeStatus {
+	wm.freezeMu.RLock()
+	defer wm.freezeMu.RUnlock()
+	if wm.frozen == nil {
+		return &FreezeStatus{Frozen: false}
+	}
+	status := *wm.frozen
+	return &status
+}
+
+// restoreFreeze reloads a freeze persisted before the host restarted
+func (wm *WalletManager) restoreFreeze(ctx context.Context) error {
+	data, err := wm.store.Get(ctx, storage.NamespaceSecurity, freezeStoreKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var status FreezeStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return fmt.Errorf("failed to parse wallet freeze: %w", err)
+	}
+	status.Frozen = true
+	wm.freezeMu.Lock()
+	wm.frozen = &status
+	wm.freezeMu.Unlock()
+	return nil
+}
+
+// auditFreezeChange records a freeze or unfreeze in the audit log
+func (wm *WalletManager) auditFreezeChange(action, reason, source string) {
+	var address string
+	if walletData, err := wm.loadWallet(); err == nil {
+		address = walletData.Address
+	}
+	if _, err := wm.auditLogger.LogSecurityEvent(action, reason, "", source, address); err != nil {
+		wm.logger.Error("Failed to audit wallet freeze change", zap.String("action", action), zap.Error(err))
+	}
+}
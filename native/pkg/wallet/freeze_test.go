@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+)
+
+func TestWalletManagerFreezeSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStateStore()
+	wm := NewWalletManagerWithStore(store, nil)
+
+	address, _, _, err := wm.ImportWallet(ctx, "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "password123", "ethereum", "")
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+
+	var notified []FreezeStatus
+	wm.OnFreezeChange(func(status FreezeStatus) { notified = append(notified, status) })
+
+	status, err := wm.FreezeWallet(ctx, "key leaked", "ai_agent")
+	if err != nil {
+		t.Fatalf("freeze failed: %v", err)
+	}
+	if !status.Frozen || status.Reason != "key leaked" || status.FrozenAt.IsZero() {
+		t.Errorf("unexpected freeze status %+v", status)
+	}
+	if wm.IsUnlocked() {
+		t.Error("expected freezing to lock the wallet")
+	}
+	if _, err := wm.SignMessage(ctx, address, "hello"); !errors.Is(err, ErrWalletFrozen) {
+		t.Errorf("expected ErrWalletFrozen from signing, got %v", err)
+	}
+	if len(notified) != 1 || !notified[0].Frozen {
+		t.Errorf("expected one freeze notification, got %+v", notified)
+	}
+
+	// Freezing again keeps the original freeze
+	again, _ := wm.FreezeWallet(ctx, "second", "user")
+	if again.Reason != "key leaked" || len(notified) != 1 {
+		t.Errorf("expected the existing freeze to be kept, got %+v", again)
+	}
+
+	// A restarted host is still frozen and refuses a normal unlock
+	restarted := NewWalletManagerWithStore(store, nil)
+	if !restarted.IsFrozen() || restarted.GetFreezeStatus().Reason != "key leaked" {
+		t.Fatalf("expected freeze to survive restart, got %+v", restarted.GetFreezeStatus())
+	}
+	if err := restarted.UnlockWallet("", "password123"); !errors.Is(err, ErrWalletFrozen) {
+		t.Errorf("expected unlock to be refused while frozen, got %v", err)
+	}
+
+	if err := restarted.UnfreezeWallet(ctx, "wrong-password", "user"); err == nil {
+		t.Error("expected unfreeze with a wrong password to fail")
+	}
+	if err := restarted.UnfreezeWallet(ctx, "password123", "user"); err != nil {
+		t.Fatalf("unfreeze failed: %v", err)
+	}
+	if restarted.IsFrozen() || restarted.IsUnlocked() {
+		t.Error("expected an unfrozen but still locked wallet")
+	}
+	if err := restarted.UnfreezeWallet(ctx, "password123", "user"); !errors.Is(err, ErrWalletNotFrozen) {
+		t.Errorf("expected ErrWalletNotFrozen, got %v", err)
+	}
+	if err := restarted.UnlockWallet("", "password123"); err != nil {
+		t.Errorf("expected unlock after unfreeze, got %v", err)
+	}
+	if NewWalletManagerWithStore(store, nil).IsFrozen() {
+		t.Error("expected unfreeze to be persisted")
+	}
+
+	freezes := restarted.auditLogger.GetAuditLogByAction(AuditActionWalletFreeze)
+	unfreezes := restarted.auditLogger.GetAuditLogByAction(AuditActionWalletUnfreeze)
+	if len(freezes) != 1 || freezes[0].Source != "ai_agent" || freezes[0].WalletAddress != address {
+		t.Errorf("expected one audited freeze by the agent, got %+v", freezes)
+	}
+	if len(unfreezes) != 1 || unfreezes[0].Source != "user" {
+		t.Errorf("expected one audited unfreeze by the user, got %+v", unfreezes)
+	}
+}
@@ -0,0 +1,276 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
+)
+
+// TokenHistoricalPriceFeed is implemented by price feeds that can price any
+// token of a chain at a past time, not just the symbols they quote live
+type TokenHistoricalPriceFeed interface {
+	// GetHistoricalPrice returns the USD price of token on chainName at at.
+	// token may be empty or the native symbol, a symbol, or a contract/mint.
+	GetHistoricalPrice(ctx context.Context, token, chainName string, at time.Time) (float64, error)
+}
+
+// HistoricalPriceSource looks up past USD prices from an external service
+type HistoricalPriceSource interface {
+	// HistoricalUSDPrice prices the native token of chainName when address is
+	// empty, or the token contract/mint at address otherwise
+	HistoricalUSDPrice(ctx context.Context, chainName, address string, at time.Time) (float64, error)
+}
+
+// maxPriceHistoryResponse bounds how much of a price history response is read
+const maxPriceHistoryResponse = 4 << 20
+
+// CoinGecko identifies native coins by coin id and tokens by asset platform
+// and contract
+var (
+	coinGeckoNativeIDs = map[string]string{
+		"ethereum": "ethereum",
+		"bsc":      "binancecoin",
+		"solana":   "solana",
+	}
+	coinGeckoPlatforms = map[string]string{
+		"ethereum": "ethereum",
+		"bsc":      "binance-smart-chain",
+		"solana":   "solana",
+	}
+)
+
+// CoinGecko returns hourly points for ranges within the last 90 days and daily
+// points before that, so the window around a time widens with its age
+const (
+	coinGeckoHourlyHorizon = 90 * 24 * time.Hour
+	coinGeckoHourlyWindow  = time.Hour
+	coinGeckoDailyWindow   = 24 * time.Hour
+)
+
+// CoinGeckoPriceSource reads past prices from the CoinGecko market chart API
+type CoinGeckoPriceSource struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewHistoricalPriceSource creates the source cfg selects, or nil when price
+// history is disabled
+func NewHistoricalPriceSource(cfg config.PriceHistoryConfig) HistoricalPriceSource {
+	if cfg.Source != config.PriceHistorySourceCoinGecko {
+		return nil
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &CoinGeckoPriceSource{
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+		apiKey:  cfg.APIKey,
+		client:  httpclient.New("price-history", httpclient.WithTimeout(timeout)),
+	}
+}
+
+// HistoricalUSDPrice returns the price point closest to at
+func (s *CoinGeckoPriceSource) HistoricalUSDPrice(ctx context.Context, chainName, address string, at time.Time) (float64, error) {
+	var path string
+	if address == "" {
+		id, ok := coinGeckoNativeIDs[chainName]
+		if !ok {
+			return 0, fmt.Errorf("no CoinGecko coin for %s", chainName)
+		}
+		path = "/coins/" + id
+	} else {
+		platform, ok := coinGeckoPlatforms[chainName]
+		if !ok {
+			return 0, fmt.Errorf("no CoinGecko platform for %s", chainName)
+		}
+		path = "/coins/" + platform + "/contract/" + url.PathEscape(address)
+	}
+
+	window := coinGeckoHourlyWindow
+	if time.Since(at) > coinGeckoHourlyHorizon {
+		window = coinGeckoDailyWindow
+	}
+	query := url.Values{}
+	query.Set("vs_currency", "usd")
+	query.Set("from", fmt.Sprint(at.Add(-window).Unix()))
+	query.Set("to", fmt.Sprint(at.Add(window).Unix()))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+path+"/market_chart/range?"+query.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("x-cg-demo-api-key", s.apiKey)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	var chart struct {
+		Prices [][2]float64 `json:"prices"` // [unix milliseconds, price]
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxPriceHistoryResponse)).Decode(&chart); err != nil {
+		return 0, fmt.Errorf("failed to decode price history: %w", err)
+	}
+
+	target := float64(at.UnixMilli())
+	price, closest := 0.0, -1.0
+	for _, point := range chart.Prices {
+		distance := point[0] - target
+		if distance < 0 {
+			distance = -distance
+		}
+		if point[1] > 0 && (closest < 0 || distance < closest) {
+			price, closest = point[1], distance
+		}
+	}
+	if closest < 0 {
+		return 0, fmt.Errorf("no price recorded near %s", at.UTC().Format(time.RFC3339))
+	}
+	return price, nil
+}
+
+// historicalPriceCache keeps past prices, which never change, up to a fixed
+// number of entries and evicts the oldest first
+type historicalPriceCache struct {
+	mu     sync.Mutex
+	size   int
+	prices map[string]float64
+	order  []string
+}
+
+func newHistoricalPriceCache(size int) *historicalPriceCache {
+	return &historicalPriceCache{size: size, prices: make(map[string]float64)}
+}
+
+// historicalPriceKey identifies a price to the minute, the finest resolution
+// any source offers
+func historicalPriceKey(chainName, address string, at time.Time) string {
+	return fmt.Sprintf("%s:%s:%d", chainName, strings.ToLower(address), at.Truncate(time.Minute).Unix())
+}
+
+func (c *historicalPriceCache) get(key string) (float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	price, ok := c.prices[key]
+	return price, ok
+}
+
+func (c *historicalPriceCache) put(key string, price float64) {
+	if c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.prices[key]; ok {
+		return
+	}
+	for len(c.order) >= c.size {
+		delete(c.prices, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.prices[key] = price
+	c.order = append(c.order, key)
+}
+
+// SetHistoricalPriceSource makes GetHistoricalPrice, and USDPriceAt for times
+// no remembered quote covers, fall back to source, keeping up to cacheSize of
+// its prices. A nil source leaves them to remembered quotes only.
+func (f *DEXPriceFeed) SetHistoricalPriceSource(source HistoricalPriceSource, cacheSize int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.historicalSource = source
+	f.historicalCache = newHistoricalPriceCache(cacheSize)
+}
+
+// GetHistoricalPrice returns the USD price of token on chainName at at.
+// Stablecoins are taken at face value and native tokens use a quote the feed
+// took near at when it has one; everything else comes from the historical
+// price source. Failures to reach the source wrap ErrPriceFeedUnavailable.
+func (f *DEXPriceFeed) GetHistoricalPrice(ctx context.Context, token, chainName string, at time.Time) (float64, error) {
+	chainName = NormalizeChain(chainName)
+	symbol, address, err := historicalPriceToken(chainName, token)
+	if err != nil {
+		return 0, err
+	}
+	if usdStablecoins[symbol] {
+		return 1, nil
+	}
+	if address == "" {
+		if price, ok := f.recordedPriceAt(symbol, at); ok {
+			return price, nil
+		}
+	}
+
+	f.mutex.Lock()
+	source, cache := f.historicalSource, f.historicalCache
+	f.mutex.Unlock()
+	if source == nil {
+		return 0, fmt.Errorf("%w: no price history source is configured", ErrPriceFeedUnavailable)
+	}
+	key := historicalPriceKey(chainName, address, at)
+	if price, ok := cache.get(key); ok {
+		return price, nil
+	}
+	price, err := source.HistoricalUSDPrice(ctx, chainName, address, at)
+	if err != nil {
+		return 0, fmt.Errorf("%w: no %s price history: %v", ErrPriceFeedUnavailable, symbolOrAddress(symbol, address), err)
+	}
+	cache.put(key, price)
+	return price, nil
+}
+
+// historicalPriceToken resolves token on chainName to its symbol and the
+// contract/mint a price source looks up; the address is empty for the native
+// token
+func historicalPriceToken(chainName, token string) (symbol, address string, err error) {
+	nativeSymbol := NativeTokenSymbol(chainName)
+	if nativeSymbol == "" {
+		return "", "", fmt.Errorf("unsupported chain: %s", chainName)
+	}
+	token = strings.TrimSpace(token)
+	if token == "" || strings.EqualFold(token, nativeSymbol) {
+		return nativeSymbol, "", nil
+	}
+	// Addresses are longer than any symbol
+	if len(token) > 11 || strings.HasPrefix(strings.ToLower(token), "0x") {
+		metadata := LookupTokenMetadata(chainName, token)
+		return strings.ToUpper(metadata.Symbol), metadata.Address, nil
+	}
+	symbol = strings.ToUpper(token)
+	if usdStablecoins[symbol] {
+		return symbol, "", nil
+	}
+	if listed, ok := DefaultTokenLists.ResolveSymbol(chainName, symbol); ok {
+		return symbol, listed.Address, nil
+	}
+	for contract, known := range knownTokens {
+		if known.chain == chainName && known.symbol == symbol && chainName != "solana" {
+			return symbol, contract, nil
+		}
+	}
+	return "", "", fmt.Errorf("no %s contract is known for %s", chainName, symbol)
+}
+
+func symbolOrAddress(symbol, address string) string {
+	if symbol != "" {
+		return symbol
+	}
+	return address
+}
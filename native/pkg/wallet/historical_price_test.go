@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+)
+
+// recordingPriceSource prices every lookup at price and remembers what it was asked
+type recordingPriceSource struct {
+	price   float64
+	err     error
+	lookups []string
+}
+
+func (s *recordingPriceSource) HistoricalUSDPrice(_ context.Context, chainName, address string, _ time.Time) (float64, error) {
+	s.lookups = append(s.lookups, chainName+":"+address)
+	return s.price, s.err
+}
+
+func TestCoinGeckoPriceSource(t *testing.T) {
+	at := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	var paths, keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		keys = append(keys, r.Header.Get("x-cg-demo-api-key"))
+		if r.URL.Query().Get("vs_currency") != "usd" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		ms := at.UnixMilli()
+		_, _ = w.Write([]byte(`{"prices":[[` + strconv.FormatInt(ms-3_000_000, 10) + `,1700],[` + strconv.FormatInt(ms+600_000, 10) + `,1800],[` + strconv.FormatInt(ms+3_000_000, 10) + `,1900]]}`))
+	}))
+	defer server.Close()
+
+	source := NewHistoricalPriceSource(config.PriceHistoryConfig{Source: config.PriceHistorySourceCoinGecko, BaseURL: server.URL + "/", APIKey: "demo"})
+	price, err := source.HistoricalUSDPrice(context.Background(), "ethereum", "", at)
+	if err != nil || price != 1800 {
+		t.Fatalf("expected the closest point, got %v, %v", price, err)
+	}
+	if _, err := source.HistoricalUSDPrice(context.Background(), "bsc", "0x55d398326f99059fF775485246999027B3197955", at); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"/coins/ethereum/market_chart/range", "/coins/binance-smart-chain/contract/0x55d398326f99059fF775485246999027B3197955/market_chart/range"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") || keys[0] != "demo" {
+		t.Errorf("unexpected requests %v with keys %v", paths, keys)
+	}
+
+	if NewHistoricalPriceSource(config.PriceHistoryConfig{Source: config.PriceHistorySourceNone}) != nil {
+		t.Error("expected no source when price history is disabled")
+	}
+}
+
+func TestDEXPriceFeedGetHistoricalPrice(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2025, 6, 1, 12, 0, 30, 0, time.UTC)
+	feed := NewDEXPriceFeed(&quoteAggregator{toAmount: "2000"})
+
+	if _, err := feed.GetHistoricalPrice(ctx, "ETH", "ethereum", at); !errors.Is(err, ErrPriceFeedUnavailable) {
+		t.Fatalf("expected history to be unavailable without a source, got %v", err)
+	}
+	if price, err := feed.GetHistoricalPrice(ctx, "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", "eth", at); err != nil || price != 1 {
+		t.Errorf("stablecoins are taken at face value, got %v, %v", price, err)
+	}
+
+	source := &recordingPriceSource{price: 2500}
+	feed.SetHistoricalPriceSource(source, 16)
+	for i := 0; i < 2; i++ {
+		if price, err := feed.GetHistoricalPrice(ctx, "", "ethereum", at.Add(time.Duration(i)*time.Second)); err != nil || price != 2500 {
+			t.Fatalf("expected the source price, got %v, %v", price, err)
+		}
+	}
+	if price, err := feed.GetHistoricalPrice(ctx, "WETH", "ethereum", at); err != nil || price != 2500 {
+		t.Fatalf("expected the source price, got %v, %v", price, err)
+	}
+	if strings.Join(source.lookups, " ") != "ethereum: ethereum:0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2" {
+		t.Errorf("expected one lookup per token, the rest cached, got %v", source.lookups)
+	}
+
+	// Native prices also back USDPriceAt once no remembered quote is close
+	if price, err := feed.USDPriceAt(ctx, "BNB", at); err != nil || price != 2500 {
+		t.Errorf("expected USDPriceAt to fall back to the source, got %v, %v", price, err)
+	}
+
+	source.err = errors.New("rate limited")
+	if _, err := feed.GetHistoricalPrice(ctx, "SOL", "solana", at); !errors.Is(err, ErrPriceFeedUnavailable) || !strings.Contains(err.Error(), "rate limited") {
+		t.Errorf("expected the source failure to be reported as unavailable, got %v", err)
+	}
+	if _, err := feed.GetHistoricalPrice(ctx, "PEPE", "ethereum", at); err == nil {
+		t.Error("expected an error for a symbol without a known contract")
+	}
+}
@@ -18,6 +18,7 @@ type HistoricalTransaction struct {
 	Token             string    `json:"token,omitempty"`
 	TokenSymbol       string    `json:"token_symbol,omitempty"`
 	Type              string    `json:"type"`              // "transfer", "swap", "contract_call"
+	Category          string    `json:"category,omitempty"` // see ClassifyTransaction
 	Status            string    `json:"status"`            // "confirmed", "failed"
 	GasUsed           string    `json:"gas_used,omitempty"`
 	GasPrice          string    `json:"gas_price,omitempty"`
@@ -25,6 +26,10 @@ type HistoricalTransaction struct {
 	Timestamp         time.Time `json:"timestamp"`
 	Confirmations     uint64    `json:"confirmations"`
 	
+	// Address book labels of From and To, with their category
+	FromLabel         string    `json:"from_label,omitempty"`
+	ToLabel           string    `json:"to_label,omitempty"`
+	
 	// Contract interaction details
 	ContractAddress   string    `json:"contract_address,omitempty"`
 	MethodName        string    `json:"method_name,omitempty"`
@@ -51,4 +56,6 @@ type TransactionHistoryFilter struct {
 	ToBlock    *uint64
 	Limit      int
 	Offset     int
+	Categories []string // see NormalizeTxCategories
+	Cursor     string   // TransactionHistoryPage.NextCursor of the previous page
 }
\ No newline at end of file
@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Transaction categories assigned to historical transactions
+const (
+	TxCategoryTransfer      = "transfer"
+	TxCategoryTokenTransfer = "token_transfer"
+	TxCategorySwap          = "swap"
+	TxCategoryApproval      = "approval"
+	TxCategoryContractCall  = "contract_call"
+	TxCategoryNFTTransfer   = "nft_transfer"
+)
+
+// SupportedTxCategories lists every category produced by ClassifyTransaction
+var SupportedTxCategories = []string{
+	TxCategoryTransfer,
+	TxCategoryTokenTransfer,
+	TxCategorySwap,
+	TxCategoryApproval,
+	TxCategoryContractCall,
+	TxCategoryNFTTransfer,
+}
+
+// Well-known 4-byte function selectors grouped by category
+var (
+	approvalSelectors = map[string]bool{
+		"0x095ea7b3": true, // approve(address,uint256)
+		"0xa22cb465": true, // setApprovalForAll(address,bool)
+		"0xd505accf": true, // permit(address,address,uint256,uint256,uint8,bytes32,bytes32)
+		"0x39509351": true, // increaseAllowance(address,uint256)
+	}
+
+	tokenTransferSelectors = map[string]bool{
+		"0xa9059cbb": true, // transfer(address,uint256)
+	}
+
+	// transferFrom(address,address,uint256) is both the ERC-20 and the ERC-721
+	// transferFrom; the transfer logs tell them apart
+	transferFromSelector = "0x23b872dd"
+
+	nftTransferSelectors = map[string]bool{
+		"0x42842e0e": true, // ERC-721 safeTransferFrom(address,address,uint256)
+		"0xb88d4fde": true, // ERC-721 safeTransferFrom(address,address,uint256,bytes)
+		"0xf242432a": true, // ERC-1155 safeTransferFrom(address,address,uint256,uint256,bytes)
+		"0x2eb2c2d6": true, // ERC-1155 safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)
+	}
+
+	swapSelectors = map[string]bool{
+		"0x7ff36ab5": true, // swapExactETHForTokens
+		"0xb6f9de95": true, // swapExactETHForTokensSupportingFeeOnTransferTokens
+		"0xfb3bdb41": true, // swapETHForExactTokens
+		"0x18cbafe5": true, // swapExactTokensForETH
+		"0x791ac947": true, // swapExactTokensForETHSupportingFeeOnTransferTokens
+		"0x4a25d94a": true, // swapTokensForExactETH
+		"0x38ed1739": true, // swapExactTokensForTokens
+		"0x5c11d795": true, // swapExactTokensForTokensSupportingFeeOnTransferTokens
+		"0x8803dbee": true, // swapTokensForExactTokens
+		"0x414bf389": true, // Uniswap V3 exactInputSingle
+		"0xc04b8d59": true, // Uniswap V3 exactInput
+		"0xdb3e2198": true, // Uniswap V3 exactOutputSingle
+		"0x3593564c": true, // Universal Router execute
+		"0x12aa3caf": true, // 1inch swap
+	}
+)
+
+// ClassifyTransaction derives a category for a historical transaction from its
+// decoded calldata (selector or method name) and its token transfer logs.
+func ClassifyTransaction(tx *HistoricalTransaction) string {
+	if tx == nil {
+		return ""
+	}
+
+	if methodSelector(tx.InputData) == transferFromSelector || strings.EqualFold(strings.TrimSpace(tx.MethodName), "transferFrom") {
+		return classifyTransferFrom(tx)
+	}
+
+	if category := classifyBySelector(methodSelector(tx.InputData)); category != "" {
+		return category
+	}
+
+	if category := classifyByMethodName(tx.MethodName); category != "" {
+		return category
+	}
+
+	if category := classifyByTokenTransfers(tx); category != "" {
+		return category
+	}
+
+	hasCalldata := methodSelector(tx.InputData) != ""
+	if tx.ContractAddress != "" || hasCalldata {
+		return TxCategoryContractCall
+	}
+
+	// A token contract address in place of a native symbol means this was a
+	// token interaction even when no calldata was recorded.
+	if strings.HasPrefix(tx.Token, "0x") && len(tx.Token) == 42 {
+		return TxCategoryTokenTransfer
+	}
+
+	return TxCategoryTransfer
+}
+
+// NormalizeTxCategories parses a comma-separated category list and validates
+// every entry against SupportedTxCategories.
+func NormalizeTxCategories(raw string) ([]string, error) {
+	var categories []string
+	for _, part := range strings.Split(raw, ",") {
+		category := strings.ToLower(strings.TrimSpace(part))
+		if category == "" {
+			continue
+		}
+		if !isSupportedTxCategory(category) {
+			return nil, fmt.Errorf("unsupported category %q (supported: %s)", category, strings.Join(SupportedTxCategories, ", "))
+		}
+		categories = append(categories, category)
+	}
+	return categories, nil
+}
+
+// FilterTransactionsByCategory returns the transactions whose category is in categories.
+// Transactions without a category are classified on the fly.
+func FilterTransactionsByCategory(txs []*HistoricalTransaction, categories []string) []*HistoricalTransaction {
+	if len(categories) == 0 {
+		return txs
+	}
+
+	wanted := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		wanted[category] = true
+	}
+
+	filtered := make([]*HistoricalTransaction, 0, len(txs))
+	for _, tx := range txs {
+		if tx.Category == "" {
+			tx.Category = ClassifyTransaction(tx)
+		}
+		if wanted[tx.Category] {
+			filtered = append(filtered, tx)
+		}
+	}
+	return filtered
+}
+
+func isSupportedTxCategory(category string) bool {
+	for _, supported := range SupportedTxCategories {
+		if category == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// methodSelector extracts the lowercase 4-byte selector from hex calldata
+func methodSelector(inputData string) string {
+	data := strings.ToLower(strings.TrimSpace(inputData))
+	if !strings.HasPrefix(data, "0x") || len(data) < 10 {
+		return ""
+	}
+	return data[:10]
+}
+
+func classifyBySelector(selector string) string {
+	switch {
+	case selector == "":
+		return ""
+	case approvalSelectors[selector]:
+		return TxCategoryApproval
+	case nftTransferSelectors[selector]:
+		return TxCategoryNFTTransfer
+	case swapSelectors[selector]:
+		return TxCategorySwap
+	case tokenTransferSelectors[selector]:
+		return TxCategoryTokenTransfer
+	}
+	return ""
+}
+
+func classifyByMethodName(method string) string {
+	name := strings.ToLower(strings.TrimSpace(method))
+	switch {
+	case name == "":
+		return ""
+	case name == "approve" || name == "setapprovalforall" || name == "permit" || name == "increaseallowance":
+		return TxCategoryApproval
+	case name == "safetransferfrom" || name == "safebatchtransferfrom":
+		return TxCategoryNFTTransfer
+	case strings.Contains(name, "swap") || name == "exactinput" || name == "exactinputsingle" ||
+		name == "exactoutput" || name == "exactoutputsingle":
+		return TxCategorySwap
+	case name == "transfer" || name == "transferchecked":
+		return TxCategoryTokenTransfer
+	}
+	return ""
+}
+
+// classifyTransferFrom tells an ERC-721 transferFrom from an ERC-20 one by its
+// transfer logs: ERC-721 tokens have no decimals
+func classifyTransferFrom(tx *HistoricalTransaction) string {
+	for _, transfer := range tx.TokenTransfers {
+		if transfer.TokenDecimals == 0 {
+			return TxCategoryNFTTransfer
+		}
+	}
+	return TxCategoryTokenTransfer
+}
+
+// classifyByTokenTransfers inspects decoded transfer logs. Tokens moving both out
+// of and into the sender indicate a swap; NFT transfers carry zero decimals.
+func classifyByTokenTransfers(tx *HistoricalTransaction) string {
+	if len(tx.TokenTransfers) == 0 {
+		return ""
+	}
+
+	sentToken, receivedToken, nft := false, false, false
+	for _, transfer := range tx.TokenTransfers {
+		if strings.EqualFold(transfer.From, tx.From) {
+			sentToken = true
+		}
+		if strings.EqualFold(transfer.To, tx.From) {
+			receivedToken = true
+		}
+		if transfer.TokenDecimals == 0 && transfer.Value == "1" {
+			nft = true
+		}
+	}
+
+	sentNative := tx.Value != "" && tx.Value != "0"
+	switch {
+	case receivedToken && (sentToken || sentNative):
+		return TxCategorySwap
+	case nft:
+		return TxCategoryNFTTransfer
+	case len(tx.TokenTransfers) == 1 && sentToken:
+		return TxCategoryTokenTransfer
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"testing"
+)
+
+func TestClassifyTransaction(t *testing.T) {
+	sender := "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+	router := "0x10ed43c718714eb63d5aa57b78b54704e256024e"
+
+	tests := []struct {
+		name     string
+		tx       *HistoricalTransaction
+		expected string
+	}{
+		{
+			name:     "native transfer",
+			tx:       &HistoricalTransaction{From: sender, To: router, Value: "1.5", Token: "ETH"},
+			expected: TxCategoryTransfer,
+		},
+		{
+			name:     "erc20 transfer selector",
+			tx:       &HistoricalTransaction{From: sender, To: router, Value: "0", InputData: "0xa9059cbb000000"},
+			expected: TxCategoryTokenTransfer,
+		},
+		{
+			name: "erc20 transferFrom selector",
+			tx: &HistoricalTransaction{
+				From:           sender,
+				To:             router,
+				InputData:      "0x23b872dd000000",
+				TokenTransfers: []TokenTransfer{{From: router, To: sender, Value: "25", TokenAddress: router, TokenDecimals: 18}},
+			},
+			expected: TxCategoryTokenTransfer,
+		},
+		{
+			name: "erc721 transferFrom selector",
+			tx: &HistoricalTransaction{
+				From:           sender,
+				To:             router,
+				InputData:      "0x23b872dd000000",
+				TokenTransfers: []TokenTransfer{{From: sender, To: router, Value: "1", TokenAddress: router, TokenSymbol: "BAYC"}},
+			},
+			expected: TxCategoryNFTTransfer,
+		},
+		{
+			name:     "approval selector",
+			tx:       &HistoricalTransaction{From: sender, To: router, InputData: "0x095ea7b3000000"},
+			expected: TxCategoryApproval,
+		},
+		{
+			name:     "erc1155 transfer selector",
+			tx:       &HistoricalTransaction{From: sender, To: router, InputData: "0xF242432A000000"},
+			expected: TxCategoryNFTTransfer,
+		},
+		{
+			name:     "swap by method name",
+			tx:       &HistoricalTransaction{From: sender, To: router, ContractAddress: router, MethodName: "swapExactETHForTokens"},
+			expected: TxCategorySwap,
+		},
+		{
+			name: "swap inferred from transfer logs",
+			tx: &HistoricalTransaction{
+				From:            sender,
+				To:              router,
+				ContractAddress: router,
+				InputData:       "0xdeadbeef00",
+				TokenTransfers: []TokenTransfer{
+					{From: sender, To: router, Value: "10", TokenDecimals: 18},
+					{From: router, To: sender, Value: "25", TokenDecimals: 6},
+				},
+			},
+			expected: TxCategorySwap,
+		},
+		{
+			name:     "unknown contract call",
+			tx:       &HistoricalTransaction{From: sender, To: router, ContractAddress: router, InputData: "0xdeadbeef00"},
+			expected: TxCategoryContractCall,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyTransaction(tt.tx); got != tt.expected {
+				t.Errorf("ClassifyTransaction() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeTxCategories(t *testing.T) {
+	categories, err := NormalizeTxCategories(" Swap, approval ,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(categories) != 2 || categories[0] != TxCategorySwap || categories[1] != TxCategoryApproval {
+		t.Errorf("unexpected categories: %v", categories)
+	}
+
+	if _, err := NormalizeTxCategories("mint"); err == nil {
+		t.Error("expected error for unsupported category")
+	}
+}
+
+func TestFilterTransactionsByCategory(t *testing.T) {
+	txs := []*HistoricalTransaction{
+		{Hash: "a", Value: "1"},
+		{Hash: "b", MethodName: "approve", ContractAddress: "0x1"},
+		{Hash: "c", MethodName: "swapExactTokensForTokens", ContractAddress: "0x2"},
+	}
+
+	filtered := FilterTransactionsByCategory(txs, []string{TxCategoryApproval, TxCategorySwap})
+	if len(filtered) != 2 || filtered[0].Hash != "b" || filtered[1].Hash != "c" {
+		t.Errorf("unexpected filter result: %+v", filtered)
+	}
+
+	if all := FilterTransactionsByCategory(txs, nil); len(all) != len(txs) {
+		t.Errorf("expected no filtering without categories, got %d", len(all))
+	}
+}
@@ -0,0 +1,264 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// historyReorgDepth is how many blocks above a page boundary are re-read when
+// the chain is found to have changed since the previous page
+const historyReorgDepth = 64
+
+// ErrInvalidHistoryCursor is returned for a cursor that was not issued for
+// the queried address
+var ErrInvalidHistoryCursor = errors.New("invalid history cursor")
+
+// TransactionHistoryPage is one page of an address's transaction history
+type TransactionHistoryPage struct {
+	Transactions []*HistoricalTransaction `json:"transactions"`
+	// NextCursor continues after this page; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Reorged lists the chains that changed under the cursor. Their recent
+	// blocks were read again and transactions already returned were dropped.
+	Reorged []string `json:"reorged,omitempty"`
+}
+
+// historyCursor is the decoded form of TransactionHistoryPage.NextCursor
+type historyCursor struct {
+	Address string                      `json:"address"`
+	Last    historyPosition             `json:"last"`
+	Chains  map[string]*historyBoundary `json:"chains,omitempty"`
+}
+
+// historyPosition locates a transaction in history order: newest first,
+// ties broken by block, index, chain and hash
+type historyPosition struct {
+	Timestamp time.Time `json:"timestamp"`
+	Block     uint64    `json:"block"`
+	Index     uint64    `json:"index"`
+	Chain     string    `json:"chain"`
+	Hash      string    `json:"hash"`
+}
+
+// historyBoundary is where the pages so far stopped on one chain
+type historyBoundary struct {
+	Last      historyPosition `json:"last"`
+	BlockHash string          `json:"block_hash,omitempty"`
+	// Seen holds the transactions returned from the historyReorgDepth blocks
+	// above the boundary, which a reorg could move past it
+	Seen []seenTransaction `json:"seen,omitempty"`
+}
+
+type seenTransaction struct {
+	Hash      string `json:"hash"`
+	Block     uint64 `json:"block"`
+	BlockHash string `json:"block_hash,omitempty"`
+}
+
+// PageTransactionHistory returns the page of txs selected by filter. Pages
+// continue from filter.Cursor instead of an offset, tracking the block hash
+// at each chain's boundary: when it changes between pages the blocks above
+// the boundary are read again and de-duplicated by transaction hash, so a
+// reorg neither repeats nor skips transactions. filter.Offset is not used.
+func PageTransactionHistory(txs []*HistoricalTransaction, filter TransactionHistoryFilter) (*TransactionHistoryPage, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	var cursor *historyCursor
+	if filter.Cursor != "" {
+		decoded, err := decodeHistoryCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(decoded.Address, filter.Address) {
+			return nil, fmt.Errorf("%w: issued for another address", ErrInvalidHistoryCursor)
+		}
+		cursor = decoded
+	}
+
+	sorted := append([]*HistoricalTransaction(nil), FilterTransactionsByCategory(txs, filter.Categories)...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return positionOf(sorted[i]).before(positionOf(sorted[j]))
+	})
+
+	page := &TransactionHistoryPage{Transactions: []*HistoricalTransaction{}}
+	var eligible []*HistoricalTransaction
+	if cursor == nil {
+		eligible = sorted
+	} else {
+		reorged := make(map[string]bool)
+		for chainName, boundary := range cursor.Chains {
+			if boundary.changed(chainName, sorted) {
+				reorged[chainName] = true
+				page.Reorged = append(page.Reorged, chainName)
+			}
+		}
+		sort.Strings(page.Reorged)
+
+		for _, tx := range sorted {
+			boundary, ok := cursor.Chains[tx.Chain]
+			switch {
+			case !ok:
+				if cursor.Last.before(positionOf(tx)) {
+					eligible = append(eligible, tx)
+				}
+			case boundary.seen(tx.Hash):
+			case reorged[tx.Chain]:
+				if tx.BlockNumber <= boundary.Last.Block+historyReorgDepth {
+					eligible = append(eligible, tx)
+				}
+			case boundary.Last.before(positionOf(tx)):
+				eligible = append(eligible, tx)
+			}
+		}
+	}
+
+	if len(eligible) > limit {
+		page.Transactions = eligible[:limit]
+		page.NextCursor = nextHistoryCursor(filter.Address, cursor, page.Transactions, sorted).encode()
+	} else {
+		page.Transactions = append(page.Transactions, eligible...)
+	}
+	return page, nil
+}
+
+// positionOf returns the history position of tx
+func positionOf(tx *HistoricalTransaction) historyPosition {
+	return historyPosition{
+		Timestamp: tx.Timestamp,
+		Block:     tx.BlockNumber,
+		Index:     tx.TransactionIndex,
+		Chain:     tx.Chain,
+		Hash:      strings.ToLower(tx.Hash),
+	}
+}
+
+// before reports whether p comes before other in history order
+func (p historyPosition) before(other historyPosition) bool {
+	switch {
+	case !p.Timestamp.Equal(other.Timestamp):
+		return p.Timestamp.After(other.Timestamp)
+	case p.Block != other.Block:
+		return p.Block > other.Block
+	case p.Index != other.Index:
+		return p.Index > other.Index
+	case p.Chain != other.Chain:
+		return p.Chain < other.Chain
+	default:
+		return p.Hash < other.Hash
+	}
+}
+
+// changed reports whether chainName was reorganized under the boundary: its
+// boundary block now has another hash, or a transaction already returned
+// moved to another block
+func (b *historyBoundary) changed(chainName string, txs []*HistoricalTransaction) bool {
+	seen := make(map[string]seenTransaction, len(b.Seen))
+	for _, tx := range b.Seen {
+		seen[tx.Hash] = tx
+	}
+	for _, tx := range txs {
+		if tx.Chain != chainName {
+			continue
+		}
+		if tx.BlockNumber == b.Last.Block && tx.BlockHash != "" && b.BlockHash != "" && !strings.EqualFold(tx.BlockHash, b.BlockHash) {
+			return true
+		}
+		if earlier, ok := seen[strings.ToLower(tx.Hash)]; ok {
+			if earlier.Block != tx.BlockNumber || (earlier.BlockHash != "" && !strings.EqualFold(earlier.BlockHash, tx.BlockHash)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// seen reports whether the transaction hash was already returned
+func (b *historyBoundary) seen(hash string) bool {
+	hash = strings.ToLower(hash)
+	for _, tx := range b.Seen {
+		if tx.Hash == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// nextHistoryCursor builds the cursor following returned. Block hashes are
+// taken from current, so a reorg already handled is not reported again.
+func nextHistoryCursor(address string, previous *historyCursor, returned, current []*HistoricalTransaction) *historyCursor {
+	next := &historyCursor{
+		Address: address,
+		Last:    positionOf(returned[len(returned)-1]),
+		Chains:  make(map[string]*historyBoundary),
+	}
+	if previous != nil {
+		for chainName, boundary := range previous.Chains {
+			next.Chains[chainName] = boundary
+		}
+	}
+
+	blockHashes := make(map[string]string, len(current))
+	for _, tx := range current {
+		blockHashes[tx.Chain+"/"+strings.ToLower(tx.Hash)] = tx.BlockHash
+	}
+
+	pageSeen := make(map[string][]seenTransaction)
+	for _, tx := range returned {
+		boundary := &historyBoundary{Last: positionOf(tx), BlockHash: tx.BlockHash}
+		if earlier, ok := next.Chains[tx.Chain]; ok {
+			boundary.Seen = earlier.Seen
+		}
+		next.Chains[tx.Chain] = boundary
+		pageSeen[tx.Chain] = append(pageSeen[tx.Chain], seenTransaction{Hash: strings.ToLower(tx.Hash), Block: tx.BlockNumber, BlockHash: tx.BlockHash})
+	}
+
+	for chainName, returnedSeen := range pageSeen {
+		boundary := next.Chains[chainName]
+		var seen []seenTransaction
+		for _, tx := range append(append([]seenTransaction(nil), boundary.Seen...), returnedSeen...) {
+			if tx.Block > boundary.Last.Block+historyReorgDepth {
+				continue
+			}
+			if blockHash, ok := blockHashes[chainName+"/"+tx.Hash]; ok {
+				tx.BlockHash = blockHash
+			}
+			seen = append(seen, tx)
+		}
+		boundary.Seen = seen
+	}
+	return next
+}
+
+// encode renders the cursor as an opaque URL-safe token
+func (c *historyCursor) encode() string {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeHistoryCursor parses a token from historyCursor.encode
+func decodeHistoryCursor(token string) (*historyCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(token))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHistoryCursor, err)
+	}
+	var cursor historyCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHistoryCursor, err)
+	}
+	return &cursor, nil
+}
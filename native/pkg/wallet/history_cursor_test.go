@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+const historyTestAddress = "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+
+var historyTestBase = time.Unix(1700000000, 0)
+
+// historyTx is a transfer of historyTestAddress mined at block, 12s per block
+func historyTx(chainName, hash string, block, index uint64, blockHash string) *HistoricalTransaction {
+	return &HistoricalTransaction{
+		Hash:             hash,
+		Chain:            chainName,
+		BlockNumber:      block,
+		BlockHash:        blockHash,
+		TransactionIndex: index,
+		From:             historyTestAddress,
+		To:               "0x8ba1f109551bD432803012645Hac136c22C4F9B",
+		Value:            "1",
+		Type:             "transfer",
+		Status:           "confirmed",
+		Timestamp:        historyTestBase.Add(time.Duration(block) * 12 * time.Second),
+	}
+}
+
+func pageHashes(page *TransactionHistoryPage) []string {
+	hashes := make([]string, 0, len(page.Transactions))
+	for _, tx := range page.Transactions {
+		hashes = append(hashes, tx.Hash)
+	}
+	return hashes
+}
+
+func TestPageTransactionHistoryWalksAllPages(t *testing.T) {
+	var txs []*HistoricalTransaction
+	for i := uint64(0); i < 5; i++ {
+		txs = append(txs,
+			historyTx("ethereum", fmt.Sprintf("0xe%d", i), 100+i, 0, fmt.Sprintf("0xeb%d", i)),
+			historyTx("bsc", fmt.Sprintf("0xb%d", i), 100+i, 0, fmt.Sprintf("0xbb%d", i)))
+	}
+
+	filter := TransactionHistoryFilter{Address: historyTestAddress, Limit: 3}
+	seen := make(map[string]bool)
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination did not end")
+		}
+		page, err := PageTransactionHistory(txs, filter)
+		if err != nil {
+			t.Fatalf("page failed: %v", err)
+		}
+		for _, hash := range pageHashes(page) {
+			if seen[hash] {
+				t.Fatalf("transaction %s returned twice", hash)
+			}
+			seen[hash] = true
+		}
+		if len(page.Reorged) != 0 {
+			t.Errorf("unexpected reorg on %v", page.Reorged)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+
+		// Transactions mined after the first page must not shift later pages
+		if pages == 0 {
+			txs = append(txs, historyTx("ethereum", "0xnew", 200, 0, "0xnewblock"))
+		}
+	}
+	if len(seen) != 10 || seen["0xnew"] {
+		t.Errorf("expected the ten original transactions, got %v", seen)
+	}
+}
+
+func TestPageTransactionHistoryRecoversFromReorg(t *testing.T) {
+	txs := []*HistoricalTransaction{
+		historyTx("ethereum", "0xa", 105, 0, "0x105"),
+		historyTx("ethereum", "0xb", 104, 1, "0x104"),
+		historyTx("ethereum", "0xc", 104, 0, "0x104"),
+		historyTx("ethereum", "0xd", 103, 0, "0x103"),
+	}
+	filter := TransactionHistoryFilter{Address: historyTestAddress, Limit: 2}
+	first, err := PageTransactionHistory(txs, filter)
+	if err != nil {
+		t.Fatalf("first page failed: %v", err)
+	}
+	if got := pageHashes(first); len(got) != 2 || got[0] != "0xa" || got[1] != "0xb" {
+		t.Fatalf("unexpected first page: %v", got)
+	}
+
+	// Block 104 is replaced and 0xc is mined again in the new block 105, above
+	// the boundary: an offset or position alone would skip it
+	reorged := []*HistoricalTransaction{
+		historyTx("ethereum", "0xa", 105, 0, "0x105b"),
+		historyTx("ethereum", "0xc", 105, 1, "0x105b"),
+		historyTx("ethereum", "0xb", 104, 0, "0x104b"),
+		historyTx("ethereum", "0xd", 103, 0, "0x103"),
+	}
+	filter.Cursor = first.NextCursor
+	second, err := PageTransactionHistory(reorged, filter)
+	if err != nil {
+		t.Fatalf("second page failed: %v", err)
+	}
+	if got := pageHashes(second); len(got) != 2 || got[0] != "0xc" || got[1] != "0xd" {
+		t.Errorf("expected 0xc and 0xd without duplicates, got %v", got)
+	}
+	if len(second.Reorged) != 1 || second.Reorged[0] != "ethereum" {
+		t.Errorf("expected a reorg on ethereum, got %v", second.Reorged)
+	}
+	if second.NextCursor != "" {
+		t.Error("expected the last page")
+	}
+}
+
+func TestPageTransactionHistoryRejectsForeignCursor(t *testing.T) {
+	txs := []*HistoricalTransaction{
+		historyTx("ethereum", "0xa", 105, 0, "0x105"),
+		historyTx("ethereum", "0xb", 104, 0, "0x104"),
+	}
+	page, err := PageTransactionHistory(txs, TransactionHistoryFilter{Address: historyTestAddress, Limit: 1})
+	if err != nil || page.NextCursor == "" {
+		t.Fatalf("expected a next cursor, got %+v (%v)", page, err)
+	}
+
+	_, err = PageTransactionHistory(txs, TransactionHistoryFilter{Address: "0x8ba1f109551bD432803012645Hac136c22C4F9B", Cursor: page.NextCursor})
+	if !errors.Is(err, ErrInvalidHistoryCursor) {
+		t.Errorf("expected ErrInvalidHistoryCursor for another address, got %v", err)
+	}
+	_, err = PageTransactionHistory(txs, TransactionHistoryFilter{Address: historyTestAddress, Cursor: "not a cursor"})
+	if !errors.Is(err, ErrInvalidHistoryCursor) {
+		t.Errorf("expected ErrInvalidHistoryCursor for garbage, got %v", err)
+	}
+}
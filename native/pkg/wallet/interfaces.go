@@ -1,6 +1,11 @@
 package wallet
 
-import "context"
+import (
+	"context"
+
+	"github.com/algonius/algonius-wallet/native/pkg/chains/solana/broadcast"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
 
 type IWalletManager interface {
 	CreateWallet(ctx context.Context, chain, password string) (address string, publicKey string, mnemonic string, err error)
@@ -9,17 +14,68 @@ type IWalletManager interface {
 	GetStatus(ctx context.Context) (*WalletStatus, error)
 	SendTransaction(ctx context.Context, chain, from, to, amount, token string) (txHash string, err error)
 	EstimateGas(ctx context.Context, chain, from, to, amount, token string) (gasLimit uint64, gasPrice string, err error)
+	SuggestGasParams(ctx context.Context, chainName, strategy string) (*chain.GasParams, error)
+	EstimateConfirmationTime(ctx context.Context, chainName string, fee chain.ProposedFee) (*chain.ConfirmationTimeEstimate, error)
+	NativeReserve(chainName string) float64
+	DefaultChain() string
+	GetSpendableBalance(ctx context.Context, chainName, address, token string) (*chain.SpendableBalance, error)
+	GetBalanceBreakdown(ctx context.Context, chainName, address, token string) (*chain.BalanceBreakdown, error)
+	SubmitBundle(ctx context.Context, from string, specs []chain.BundleTransactionSpec, tipLamports uint64) (*broadcast.BundleResult, error)
+	SignTokenPermit(ctx context.Context, chainName string, request chain.PermitRequest) (*chain.SignedPermit, error)
+	DelegateStake(ctx context.Context, owner, voteAccount, amount string) (*chain.StakeResult, error)
+	DeactivateStake(ctx context.Context, owner, stakeAccount string) (*chain.StakeResult, error)
+	WithdrawStake(ctx context.Context, owner, stakeAccount, to, amount string) (*chain.StakeResult, error)
+	WrapNative(ctx context.Context, chainName, owner, amount string) (*chain.WrapResult, error)
+	UnwrapNative(ctx context.Context, chainName, owner, amount string) (*chain.WrapResult, error)
+	GetTokenBalanceDeltas(ctx context.Context, chainName, txHash string) ([]chain.TokenBalanceDelta, error)
+	ClassifyRecipient(ctx context.Context, chainName, address, token string) (*chain.RecipientClassification, error)
+	DetectTransferFee(ctx context.Context, chainName, from, to, amount, token string) (*chain.TokenTransferFee, error)
+	AuthorizeSigning(ctx context.Context, req SigningRequest) error
+	CheckSigningPolicy(ctx context.Context, req SigningRequest) error
 	GetPendingTransactions(ctx context.Context, chain, address, transactionType string, limit, offset int) ([]*PendingTransaction, error)
 	RejectTransactions(ctx context.Context, transactionIds []string, reason, details string, notifyUser, auditLog bool) ([]TransactionRejectionResult, error)
 	GetTransactionHistory(ctx context.Context, address string, fromBlock, toBlock *uint64, limit, offset int) ([]*HistoricalTransaction, error)
+	GetTransactionHistoryPage(ctx context.Context, filter TransactionHistoryFilter) (*TransactionHistoryPage, error)
+	GetBalanceHistory(ctx context.Context, filter BalanceHistoryFilter) ([]*BalanceSnapshot, error)
 	GetAccounts(ctx context.Context) ([]string, error)
+	DiscoverAccounts(ctx context.Context, chainName string, maxAccounts, gapLimit int) ([]*DerivedAccount, error)
+	ImportAccounts(ctx context.Context, chainName string, count int) ([]*DerivedAccount, error)
 	AddPendingTransaction(ctx context.Context, tx *PendingTransaction) error
+	AutoApproveTransaction(ctx context.Context, tx *PendingTransaction, rule, origin string) (txHash string, err error)
 	SignMessage(ctx context.Context, address, message string) (signature string, err error)
+	GetPrivateKeyForAddress(ctx context.Context, address string) (string, error)
+	ReserveSpend(chainName, from, to, amount, token string) (*SpendReservation, error)
+	CheckSpendLimit(chainName, from, to, amount, token string) error
+	ValidateTransfer(chainName, from, to, amount string) error
+	GetNFTs(ctx context.Context, chain, owner string) ([]*NFTAsset, error)
+	TransferNFT(ctx context.Context, chain, from, to, contractAddress, tokenID, amount string) (txHash string, err error)
+	ScheduleTransaction(ctx context.Context, job *ScheduledTransaction) (*ScheduledTransaction, error)
+	ListScheduledTransactions(ctx context.Context, status string) ([]*ScheduledTransaction, error)
+	CancelScheduledTransaction(ctx context.Context, id string) (*ScheduledTransaction, error)
+	CreatePriceTrigger(ctx context.Context, trigger *PriceTrigger) (*PriceTrigger, error)
+	ListPriceTriggers(ctx context.Context, status string) ([]*PriceTrigger, error)
+	CancelPriceTrigger(ctx context.Context, id string) (*PriceTrigger, error)
+	AddAddressBookEntry(ctx context.Context, entry *AddressBookEntry) (*AddressBookEntry, error)
+	RemoveAddressBookEntry(ctx context.Context, chainName, address string) (*AddressBookEntry, error)
+	ListAddressBook(ctx context.Context, chainName, category string) ([]*AddressBookEntry, error)
+	LookupAddressBook(chainName, address string) (*AddressBookEntry, bool)
+	AddressBookPolicy(category string) string
+	CheckRecipient(chainName, address string) error
 	
 	// Wallet storage and security methods
-	UnlockWallet(password string) error
+	UnlockWallet(address, password string) error
 	LockWallet()
 	IsUnlocked() bool
 	HasWallet() bool
 	GetCurrentWallet() *WalletStatus
+	ListWallets(ctx context.Context) ([]*StoredWallet, error)
+	SwitchWallet(ctx context.Context, address string) error
+	SetWalletLabel(ctx context.Context, address, label string) (*StoredWallet, error)
+	ExportWallet(ctx context.Context, password, source string) (*WalletExport, error)
+
+	// Emergency freeze: locks the wallet and blocks unlocking until unfrozen with the password
+	FreezeWallet(ctx context.Context, reason, source string) (*FreezeStatus, error)
+	UnfreezeWallet(ctx context.Context, password, source string) error
+	IsFrozen() bool
+	GetFreezeStatus() *FreezeStatus
 }
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+)
+
+// LargeTransactionCheck is the outcome of comparing a send or approval against
+// the chain's large transaction threshold
+type LargeTransactionCheck struct {
+	Chain    string
+	Token    string
+	Amount   string
+	IsNative bool
+	USDValue float64 // 0 when the token could not be priced
+	// USDStale is set when USDValue uses the feed's last known price
+	USDStale bool
+	// USDUnavailable explains why the USD limit could not be checked because
+	// the price feed was down
+	USDUnavailable string
+	Threshold      config.LargeTxThresholdConfig
+	Large          bool
+	Reasons        []string
+}
+
+// CheckLargeTransaction reports whether moving amount of token on a normalized
+// chain meets threshold. The native limit only applies to the chain's native
+// token; the USD limit applies to any token priceFeed can price. An amount
+// that cannot be parsed is never large, it is rejected by the send itself.
+func CheckLargeTransaction(ctx context.Context, threshold config.LargeTxThresholdConfig, priceFeed PriceFeed, chainName, token, amount string) *LargeTransactionCheck {
+	nativeSymbol := NativeTokenSymbol(chainName)
+	check := &LargeTransactionCheck{
+		Chain:     chainName,
+		Token:     token,
+		Amount:    amount,
+		IsNative:  token == "" || strings.EqualFold(token, nativeSymbol),
+		Threshold: threshold,
+	}
+	if !threshold.Enabled() {
+		return check
+	}
+
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok || value.Sign() <= 0 {
+		return check
+	}
+
+	if check.IsNative && threshold.Native > 0 && value.Cmp(new(big.Rat).SetFloat64(threshold.Native)) >= 0 {
+		check.Large = true
+		check.Reasons = append(check.Reasons, fmt.Sprintf("amount %s %s meets the %g %s threshold", amount, nativeSymbol, threshold.Native, nativeSymbol))
+	}
+
+	symbol := token
+	if check.IsNative {
+		symbol = nativeSymbol
+	}
+	if threshold.USD > 0 && priceFeed != nil && symbol != "" {
+		if price, err := CurrentUSDPrice(ctx, priceFeed, symbol); errors.Is(err, ErrPriceFeedUnavailable) {
+			check.USDUnavailable = err.Error()
+		} else if err == nil {
+			check.USDStale = price.Stale
+			check.USDValue, _ = new(big.Rat).Mul(value, new(big.Rat).SetFloat64(price.Price)).Float64()
+			if check.USDValue >= threshold.USD {
+				check.Large = true
+				check.Reasons = append(check.Reasons, fmt.Sprintf("value $%.2f meets the $%.2f threshold", check.USDValue, threshold.USD))
+			}
+		}
+	}
+	return check
+}
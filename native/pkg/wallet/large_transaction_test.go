@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+)
+
+type fixedPriceFeed map[string]float64
+
+func (f fixedPriceFeed) USDPrice(_ context.Context, symbol string) (float64, error) {
+	if price, ok := f[symbol]; ok {
+		return price, nil
+	}
+	return 0, fmt.Errorf("no price for %s", symbol)
+}
+
+func TestCheckLargeTransaction(t *testing.T) {
+	ctx := context.Background()
+	feed := fixedPriceFeed{"ETH": 2000, "USDC": 1}
+	threshold := config.LargeTxThresholdConfig{Native: 5, USD: 5000}
+
+	cases := []struct {
+		name   string
+		token  string
+		amount string
+		large  bool
+	}{
+		{"small native send", "", "1", false},
+		{"native limit met exactly", "ETH", "5", true},
+		{"usd limit met below native limit", "", "2.5", true},
+		{"priced token over usd limit", "USDC", "6000", true},
+		{"unpriced token is only checked in usd", "0xdAC17F958D2ee523a2206206994597C13D831ec7", "1000000", false},
+		{"unparseable amount", "", "lots", false},
+	}
+	for _, tc := range cases {
+		check := CheckLargeTransaction(ctx, threshold, feed, "ethereum", tc.token, tc.amount)
+		if check.Large != tc.large {
+			t.Errorf("%s: expected large=%v, got %+v", tc.name, tc.large, check)
+		}
+	}
+
+	check := CheckLargeTransaction(ctx, threshold, feed, "ethereum", "", "10")
+	if len(check.Reasons) != 2 || check.USDValue != 20000 {
+		t.Errorf("expected both limits to be reported, got %+v", check)
+	}
+
+	if CheckLargeTransaction(ctx, config.LargeTxThresholdConfig{}, feed, "ethereum", "", "1000").Large {
+		t.Error("expected a disabled threshold never to flag a transaction")
+	}
+}
+
+func TestCheckLargeTransactionWhileFeedIsDown(t *testing.T) {
+	ctx := context.Background()
+	threshold := config.LargeTxThresholdConfig{Native: 50, USD: 5000}
+
+	check := CheckLargeTransaction(ctx, threshold, outagePriceFeed{}, "ethereum", "", "3")
+	if check.Large || check.USDUnavailable == "" {
+		t.Errorf("expected the usd limit to be reported as unchecked, got %+v", check)
+	}
+
+	feed := outagePriceFeed{lastKnown: map[string]float64{"ETH": 2000}, quotedAt: time.Now().Add(-time.Hour)}
+	check = CheckLargeTransaction(ctx, threshold, feed, "ethereum", "", "3")
+	if !check.Large || !check.USDStale || check.USDValue != 6000 {
+		t.Errorf("expected the last known price to enforce the usd limit, got %+v", check)
+	}
+}
@@ -6,19 +6,28 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/algonius/algonius-wallet/native/pkg/chains/solana/broadcast"
 	"github.com/algonius/algonius-wallet/native/pkg/config"
 	"github.com/algonius/algonius-wallet/native/pkg/dex"
 	"github.com/algonius/algonius-wallet/native/pkg/security"
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
 	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
 	"github.com/mr-tron/base58"
 	"go.uber.org/zap"
 )
 
+// ErrWalletLocked is returned by signing and sending operations while the wallet is locked
+var ErrWalletLocked = errors.New("wallet is locked")
+
 // EncryptedWalletData represents encrypted wallet storage format
 type EncryptedWalletData struct {
 	Address          string                 `json:"address"`
@@ -28,6 +37,10 @@ type EncryptedWalletData struct {
 	Chains           map[string]bool        `json:"chains"`
 	CreatedAt        int64                  `json:"created_at"`
 	LastUsed         int64                  `json:"last_used"`
+	Accounts         []*DerivedAccount      `json:"accounts,omitempty"` // additional accounts found by DiscoverAccounts
+	Label            string                 `json:"label,omitempty"`      // human-friendly name set by SetWalletLabel
+	WatchOnly        bool                   `json:"watch_only,omitempty"` // address tracked without key material
+	Hardware         bool                   `json:"hardware,omitempty"`   // keys held by a hardware signer
 }
 
 // DecryptedWalletData represents decrypted wallet data in memory
@@ -37,6 +50,7 @@ type DecryptedWalletData struct {
 	PrivateKey string            `json:"private_key"`
 	Mnemonic   string            `json:"mnemonic"`
 	ChainData  map[string]*ChainSpecificData `json:"chain_data,omitempty"` // Chain-specific addresses and keys
+	Accounts   map[string]*ChainSpecificData `json:"accounts,omitempty"`   // Discovered accounts keyed by address
 }
 
 // ChainSpecificData holds chain-specific wallet information
@@ -48,47 +62,88 @@ type ChainSpecificData struct {
 
 type WalletManager struct {
 	chainFactory *chain.ChainFactory
-	// Storage configuration
-	walletDir    string
-	// Current wallet state (only loaded when user enters password)
+	// Persistent state (wallets, pending transactions, audit log)
+	store        storage.StateStore
+	// Current wallet state (only loaded when user enters password). walletMu
+	// guards it and the selection below, since native messaging and MCP calls
+	// run concurrently.
+	walletMu      sync.RWMutex
 	currentWallet *WalletStatus
 	currentWalletData *DecryptedWalletData
 	isUnlocked   bool
+	// Address of the selected wallet, and the keys of every wallet unlocked this session
+	selectedAddress string
+	unlockedWallets map[string]*DecryptedWalletData
 	// Audit logger for security events
 	auditLogger *AuditLogger
-	// Mock storage for pending transactions
-	pendingTxs []*PendingTransaction
+	// Pending transactions indexed by hash, chain and address
+	pending *PendingStore
 	// Logger for debugging and monitoring
 	logger *zap.Logger
+	// Bounds for the account scan run by DiscoverAccounts
+	accountDiscovery config.AccountDiscoveryConfig
+	// Balance time series and the schedule it is recorded on
+	balanceHistory   *BalanceHistory
+	balanceSnapshots config.BalanceSnapshotConfig
+	// Emergency freeze, persisted across restarts, and the hooks notified when it changes
+	freezeMu        sync.RWMutex
+	frozen          *FreezeStatus
+	freezeListeners []FreezeListener
+	// Recent sends, so an accidental identical send returns the first result
+	sendDedup *sendDeduplicator
+	// Transactions scheduled to be sent later, persisted across restarts
+	scheduler *TransactionScheduler
+	// Price-triggered sends and swaps, persisted across restarts
+	priceTriggers *PriceTriggerWatcher
+	// Named recipients, and the categories sends to which are blocked or warned about
+	addressBook *AddressBook
+	// Chain used when a request does not name one (wallet.default_chain)
+	defaultChain string
+	// Background balance and price fetch run after unlock, and the hooks told when it ends
+	cacheWarmup     config.CacheWarmupConfig
+	warmupMu        sync.Mutex
+	cancelWarmup    context.CancelFunc
+	warmupListeners []CacheWarmupListener
+	priceFeed       PriceFeed
+	// Policies consulted before anything is signed, in registration order
+	signingMu       sync.RWMutex
+	signingPolicies []SigningPolicy
+	// Wrong passwords given to ExportWallet, to throttle guessing
+	exportGuard *exportGuard
+	// Caps on outgoing transactions per chain, and the hooks told when one is hit
+	spendMu             sync.RWMutex
+	spendLimits         map[string]config.ChainSpendLimits
+	spendLimitListeners []SpendLimitListener
+	// Amounts checked against the daily limits whose transactions are still being sent
+	spendReserved spendReservations
 }
 
-// NewWalletManager constructs a new WalletManager.
+// NewWalletManager constructs a new WalletManager backed by the mainnet state in
+// the wallet home directory.
 func NewWalletManager() *WalletManager {
-	// Get wallet directory from environment or default
-	walletHomeDir := getWalletHomeDir()
-	walletDir := filepath.Join(walletHomeDir, "wallets")
-	
-	// Create wallet directory if it doesn't exist
-	os.MkdirAll(walletDir, 0700)
-	
-	// Use no-op logger if none provided
-	logger := zap.NewNop()
-	
-	return &WalletManager{
-		chainFactory: chain.NewChainFactory(),
-		walletDir:    walletDir,
-		auditLogger:  NewAuditLogger(),
-		pendingTxs:   make([]*PendingTransaction, 0),
-		isUnlocked:   false,
-		logger:       logger,
+	store, err := storage.NewFileStateStore(filepath.Join(getWalletHomeDir(), config.NetworkModeMainnet))
+	if err != nil {
+		// getWalletHomeDir never returns an empty path, so this only guards against future changes
+		return NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	}
+	return NewWalletManagerWithStore(store, nil)
+}
+
+// NewWalletManagerWithStore constructs a new WalletManager that persists state to store.
+// A nil logger is replaced with a no-op logger.
+func NewWalletManagerWithStore(store storage.StateStore, logger *zap.Logger) *WalletManager {
+	if logger == nil {
+		logger = zap.NewNop()
 	}
+	return newWalletManager(chain.NewChainFactory(), store, logger)
 }
 
 // NewWalletManagerWithConfig constructs a new WalletManager with configuration.
 func NewWalletManagerWithConfig(config *config.Config, dexAggregator dex.IDEXAggregator, logger *zap.Logger) *WalletManager {
 	// Get wallet directory from config or environment
 	logger.Info("NewWalletManagerWithConfig starting", 
-		zap.String("config_data_dir", config.Wallet.DataDir))
+		zap.String("config_data_dir", config.Wallet.DataDir),
+		zap.String("storage_backend", config.Wallet.Storage.Backend))
 	
 	// Ensure the DataDir path is properly expanded (handle ~ if present)
 	dataDir := config.Wallet.DataDir
@@ -102,13 +157,35 @@ func NewWalletManagerWithConfig(config *config.Config, dexAggregator dex.IDEXAgg
 		}
 	}
 	
-	walletDir := filepath.Join(dataDir, "wallets")
-	logger.Info("NewWalletManagerWithConfig wallet directory constructed", 
-		zap.String("wallet_dir", walletDir),
-		zap.String("expanded_data_dir", dataDir))
+	// Each network mode keeps its state apart so testnet activity never touches mainnet wallets
+	networkDir, err := storage.NetworkDir(dataDir, config.Wallet.NetworkMode)
+	if err != nil {
+		logger.Error("Invalid network mode, using mainnet storage",
+			zap.String("network_mode", config.Wallet.NetworkMode),
+			zap.Error(err))
+		networkDir, _ = storage.NetworkDir(dataDir, "")
+	}
+	if backend := strings.ToLower(strings.TrimSpace(config.Wallet.Storage.Backend)); backend == "" || backend == storage.BackendFile {
+		migrated, err := storage.MigrateLegacyLayout(dataDir)
+		if err != nil {
+			logger.Error("Failed to migrate wallet state into the mainnet directory", zap.Error(err))
+		} else if len(migrated) > 0 {
+			logger.Info("Migrated wallet state into the mainnet directory", zap.Strings("namespaces", migrated))
+		}
+	}
+	logger.Info("Using network-scoped wallet storage",
+		zap.String("network_mode", config.Wallet.NetworkMode),
+		zap.String("state_dir", networkDir))
 	
-	// Create wallet directory if it doesn't exist
-	os.MkdirAll(walletDir, 0700)
+	store, err := storage.New(config.Wallet.Storage, networkDir)
+	if err != nil {
+		logger.Error("Failed to create configured storage backend, using filesystem storage",
+			zap.String("backend", config.Wallet.Storage.Backend),
+			zap.Error(err))
+		if store, err = storage.NewFileStateStore(networkDir); err != nil {
+			store = storage.NewMemoryStateStore()
+		}
+	}
 	
 	// Create chain factory with configuration
 	var chainFactory *chain.ChainFactory
@@ -118,14 +195,97 @@ func NewWalletManagerWithConfig(config *config.Config, dexAggregator dex.IDEXAgg
 		chainFactory = chain.NewChainFactory()
 	}
 	
-	return &WalletManager{
+	wm := newWalletManager(chainFactory, store, logger)
+	if config.Wallet.AccountDiscovery.MaxAccounts > 0 && config.Wallet.AccountDiscovery.GapLimit > 0 {
+		wm.accountDiscovery = config.Wallet.AccountDiscovery
+	}
+	wm.balanceSnapshots = config.Wallet.BalanceSnapshots
+	if replay := config.Wallet.ReplayProtection; replay.Enabled {
+		wm.sendDedup = newSendDeduplicator(replay.Window)
+	} else {
+		wm.sendDedup = newSendDeduplicator(0)
+	}
+	wm.pending.SetMaxEntries(config.Wallet.MaxPendingTransactions)
+	wm.addressBook.SetPolicies(config.Wallet.AddressBook)
+	if defaultChain := NormalizeChain(config.Wallet.DefaultChain); defaultChain != "" {
+		wm.defaultChain = defaultChain
+	}
+	wm.cacheWarmup = config.Wallet.CacheWarmup
+	if config.Security.SigningPolicy.Enabled() {
+		wm.RegisterSigningPolicy(NewRuleSigningPolicy(config.Security.SigningPolicy))
+	}
+	wm.setSpendLimits(config.Security.SpendLimits)
+	return wm
+}
+
+// newWalletManager wires a WalletManager and restores persisted audit and pending state
+func newWalletManager(chainFactory *chain.ChainFactory, store storage.StateStore, logger *zap.Logger) *WalletManager {
+	auditLogger, err := NewAuditLoggerWithStore(store)
+	if err != nil {
+		logger.Warn("Failed to load persisted audit log, starting empty", zap.Error(err))
+		auditLogger = NewAuditLogger()
+		auditLogger.store = store
+	}
+	
+	wm := &WalletManager{
 		chainFactory: chainFactory,
-		walletDir:    walletDir,
-		auditLogger:  NewAuditLogger(),
-		pendingTxs:   make([]*PendingTransaction, 0),
+		store:        store,
+		auditLogger:  auditLogger,
+		pending:      NewPendingStore(store),
 		isUnlocked:   false,
 		logger:       logger,
+		accountDiscovery: config.DefaultConfig().Wallet.AccountDiscovery,
+		balanceHistory:   NewBalanceHistory(store),
+		balanceSnapshots: config.DefaultConfig().Wallet.BalanceSnapshots,
+		sendDedup:        newSendDeduplicator(config.DefaultConfig().Wallet.ReplayProtection.Window),
+		scheduler:        NewTransactionScheduler(store),
+		priceTriggers:    NewPriceTriggerWatcher(store),
+		addressBook:      NewAddressBook(store),
+		defaultChain:     "ethereum",
+		exportGuard:      newExportGuard(),
+	}
+	
+	if err := wm.pending.Load(context.Background()); err != nil {
+		logger.Warn("Failed to load persisted pending transactions", zap.Error(err))
+	}
+	
+	if err := wm.scheduler.Load(context.Background()); err != nil {
+		logger.Warn("Failed to load persisted scheduled transactions", zap.Error(err))
+	}
+	
+	if err := wm.priceTriggers.Load(context.Background()); err != nil {
+		logger.Warn("Failed to load persisted price triggers", zap.Error(err))
+	}
+	
+	if err := wm.addressBook.Load(context.Background()); err != nil {
+		logger.Warn("Failed to load persisted address book", zap.Error(err))
 	}
+	
+	if err := wm.restoreFreeze(context.Background()); err != nil {
+		logger.Error("Failed to load persisted wallet freeze", zap.Error(err))
+	}
+	
+	if err := wm.restoreSelectedWallet(context.Background()); err != nil {
+		logger.Warn("Failed to restore the selected wallet", zap.Error(err))
+	}
+	
+	// Development fixtures live alongside real transactions but are never
+	// persisted, and are only seeded for test runs (RUN_MODE=test)
+	if os.Getenv("RUN_MODE") == "test" {
+		for _, tx := range wm.generateMockPendingTransactions("", "", "") {
+			_ = wm.pending.addFixture(tx)
+		}
+	}
+	
+	return wm
+}
+
+// SetEntropySource replaces the randomness CreateWallet draws mnemonics from
+// on every chain; nil restores crypto/rand. It exists for tests, which pass
+// chain.NewSeededEntropy to assert the exact wallet produced. No production
+// code path calls it.
+func (wm *WalletManager) SetEntropySource(source io.Reader) {
+	wm.chainFactory.SetEntropySource(source)
 }
 
 // getWalletHomeDir returns the wallet home directory, respecting environment override
@@ -208,20 +368,21 @@ func (wm *WalletManager) CreateWallet(ctx context.Context, chainName, password s
 
 	// Store the wallet status
 	createdTime := time.Now().Unix()
-	wm.currentWallet = NewWalletStatus(walletInfo.Address, walletInfo.PublicKey)
-	wm.currentWallet.LastUsed = createdTime
+	status := NewWalletStatus(walletInfo.Address, walletInfo.PublicKey)
+	status.LastUsed = createdTime
 
 	// Add supported chains based on created chain
 	switch normalizedChain {
 	case "ethereum":
-		wm.currentWallet.Chains["ethereum"] = true
-		wm.currentWallet.Chains["bsc"] = true // BSC is Ethereum-compatible
+		status.Chains["ethereum"] = true
+		status.Chains["bsc"] = true // BSC is Ethereum-compatible
 	case "bsc":
-		wm.currentWallet.Chains["bsc"] = true
-		wm.currentWallet.Chains["ethereum"] = true // ETH is BSC-compatible
+		status.Chains["bsc"] = true
+		status.Chains["ethereum"] = true // ETH is BSC-compatible
 	case "solana":
-		wm.currentWallet.Chains["solana"] = true
+		status.Chains["solana"] = true
 	}
+	wm.setCurrentWallet(status)
 
 	// Create encrypted wallet data structure
 	encryptedWallet := &EncryptedWalletData{
@@ -248,7 +409,7 @@ func (wm *WalletManager) CreateWallet(ctx context.Context, chainName, password s
 	
 	// Save encrypted wallet to disk
 	wm.logger.Info("CreateWallet saving wallet to disk")
-	err = wm.saveWalletToDisk(encryptedWallet)
+	err = wm.saveWallet(encryptedWallet)
 	if err != nil {
 		wm.logger.Error("CreateWallet failed to save wallet to disk", 
 			zap.Error(err))
@@ -258,7 +419,7 @@ func (wm *WalletManager) CreateWallet(ctx context.Context, chainName, password s
 	
 	// Load decrypted data into memory for immediate use
 	wm.logger.Info("CreateWallet loading wallet into memory and unlocking")
-	wm.currentWalletData = &DecryptedWalletData{
+	walletData := &DecryptedWalletData{
 		Address:    walletInfo.Address,
 		PublicKey:  walletInfo.PublicKey,
 		PrivateKey: walletInfo.PrivateKey,
@@ -267,13 +428,17 @@ func (wm *WalletManager) CreateWallet(ctx context.Context, chainName, password s
 	}
 	
 	// Add chain-specific data
-	wm.currentWalletData.ChainData[normalizedChain] = &ChainSpecificData{
+	walletData.ChainData[normalizedChain] = &ChainSpecificData{
 		Address:    walletInfo.Address,
 		PublicKey:  walletInfo.PublicKey,
 		PrivateKey: walletInfo.PrivateKey,
 	}
 	
-	wm.isUnlocked = true
+	// The new wallet becomes the selected one
+	wm.setUnlockedWallet(walletData)
+	if err := wm.persistSelectedWallet(walletInfo.Address); err != nil {
+		wm.logger.Warn("CreateWallet failed to persist the selected wallet", zap.Error(err))
+	}
 
 	wm.logger.Info("CreateWallet completed successfully", 
 		zap.String("address", walletInfo.Address),
@@ -329,26 +494,29 @@ func (wm *WalletManager) ImportWallet(ctx context.Context, mnemonic, password, c
 	}
 
 	// Check if wallet already exists (same address)
-	if wm.currentWallet != nil && wm.currentWallet.Address == walletInfo.Address {
+	if _, err := wm.findWalletRecord(ctx, walletInfo.Address); err == nil {
 		return "", "", 0, errors.New("wallet already exists")
+	} else if !errors.Is(err, ErrWalletNotFound) {
+		return "", "", 0, err
 	}
 
 	// Store the wallet status
 	importTime := time.Now().Unix()
-	wm.currentWallet = NewWalletStatus(walletInfo.Address, walletInfo.PublicKey)
-	wm.currentWallet.LastUsed = importTime
+	status := NewWalletStatus(walletInfo.Address, walletInfo.PublicKey)
+	status.LastUsed = importTime
 
 	// Add supported chains based on imported chain
 	switch normalizedChain {
 	case "ethereum":
-		wm.currentWallet.Chains["ethereum"] = true
-		wm.currentWallet.Chains["bsc"] = true // BSC is Ethereum-compatible
+		status.Chains["ethereum"] = true
+		status.Chains["bsc"] = true // BSC is Ethereum-compatible
 	case "bsc":
-		wm.currentWallet.Chains["bsc"] = true
-		wm.currentWallet.Chains["ethereum"] = true // ETH is BSC-compatible
+		status.Chains["bsc"] = true
+		status.Chains["ethereum"] = true // ETH is BSC-compatible
 	case "solana":
-		wm.currentWallet.Chains["solana"] = true
+		status.Chains["solana"] = true
 	}
+	wm.setCurrentWallet(status)
 
 	// Create encrypted wallet data structure
 	encryptedWallet := &EncryptedWalletData{
@@ -374,39 +542,31 @@ func (wm *WalletManager) ImportWallet(ctx context.Context, mnemonic, password, c
 	}
 	
 	// Save encrypted wallet to disk
-	err = wm.saveWalletToDisk(encryptedWallet)
+	err = wm.saveWallet(encryptedWallet)
 	if err != nil {
 		return "", "", 0, fmt.Errorf("failed to save wallet: %w", err)
 	}
 
-	// Load decrypted data into memory
-	if wm.currentWalletData == nil {
-		wm.currentWalletData = &DecryptedWalletData{
-			Address:    walletInfo.Address,
-			PublicKey:  walletInfo.PublicKey,
-			PrivateKey: walletInfo.PrivateKey,
-			Mnemonic:   walletInfo.Mnemonic,
-			ChainData:  make(map[string]*ChainSpecificData),
-		}
-	} else {
-		// Update existing wallet data
-		wm.currentWalletData.Address = walletInfo.Address
-		wm.currentWalletData.PublicKey = walletInfo.PublicKey
-		wm.currentWalletData.PrivateKey = walletInfo.PrivateKey
-		wm.currentWalletData.Mnemonic = walletInfo.Mnemonic
-		if wm.currentWalletData.ChainData == nil {
-			wm.currentWalletData.ChainData = make(map[string]*ChainSpecificData)
-		}
+	// Load decrypted data into memory; the imported wallet becomes the selected one
+	walletData := &DecryptedWalletData{
+		Address:    walletInfo.Address,
+		PublicKey:  walletInfo.PublicKey,
+		PrivateKey: walletInfo.PrivateKey,
+		Mnemonic:   walletInfo.Mnemonic,
+		ChainData:  make(map[string]*ChainSpecificData),
 	}
 	
 	// Add chain-specific data
-	wm.currentWalletData.ChainData[normalizedChain] = &ChainSpecificData{
+	walletData.ChainData[normalizedChain] = &ChainSpecificData{
 		Address:    walletInfo.Address,
 		PublicKey:  walletInfo.PublicKey,
 		PrivateKey: walletInfo.PrivateKey,
 	}
 
-	wm.isUnlocked = true
+	wm.setUnlockedWallet(walletData)
+	if err := wm.persistSelectedWallet(walletInfo.Address); err != nil {
+		wm.logger.Warn("ImportWallet failed to persist the selected wallet", zap.Error(err))
+	}
 
 	return walletInfo.Address, walletInfo.PublicKey, importTime, nil
 }
@@ -428,7 +588,7 @@ func (wm *WalletManager) GetBalance(ctx context.Context, address, token string)
 		return "", errors.New("address is required")
 	}
 	if token == "" {
-		token = "ETH"
+		token = NativeTokenSymbol(wm.defaultChain)
 	}
 
 	// Use centralized token mapping to determine the target chain
@@ -458,23 +618,22 @@ func (wm *WalletManager) guessChainFromAddress(address, token string) string {
 	if strings.HasPrefix(address, "0x") && len(address) == 42 {
 		// Check if token looks like a contract address
 		if strings.HasPrefix(token, "0x") && len(token) == 42 {
-			// Contract address - default to Ethereum for now
+			// Contract address - use the default EVM chain for now
 			// In a more sophisticated implementation, we could maintain a registry
 			// of known contract addresses per chain
-			return "ETH"
+			return DefaultEVMChain(wm.defaultChain)
 		}
 		// Hex address but not contract token - could be ETH or BSC (same format)
-		// Default to Ethereum for backward compatibility
-		return "ETH"
+		return DefaultEVMChain(wm.defaultChain)
 	}
 	
 	// For base58 addresses, likely Solana
 	if wm.isBase58Address(address) {
-		return "SOL"
+		return "solana"
 	}
 	
 	// Default fallback
-	return "ETH"
+	return wm.defaultChain
 }
 
 // isBase58Address checks if an address appears to be a valid Solana base58 address
@@ -498,7 +657,8 @@ func (wm *WalletManager) isBase58Address(address string) bool {
 
 // GetStatus returns the current wallet status.
 func (wm *WalletManager) GetStatus(ctx context.Context) (*WalletStatus, error) {
-	if wm.currentWallet == nil {
+	current := wm.GetCurrentWallet()
+	if current == nil {
 		// Return a default status if no wallet is created yet
 		return &WalletStatus{
 			Address:   "",
@@ -510,16 +670,22 @@ func (wm *WalletManager) GetStatus(ctx context.Context) (*WalletStatus, error) {
 				"solana":   true,
 			},
 			LastUsed: 0,
+			LastSuccessfulCall: LastSuccessfulRPCCalls(),
+			ProviderBreakers:   ProviderBreakers(),
 		}, nil
 	}
 	
-	return wm.currentWallet, nil
+	// Return a copy so the RPC health snapshot doesn't leak into the stored wallet status
+	status := *current
+	status.LastSuccessfulCall = LastSuccessfulRPCCalls()
+	status.ProviderBreakers = ProviderBreakers()
+	return &status, nil
 }
 
 // SendTransaction sends a transaction on the specified chain.
 func (wm *WalletManager) SendTransaction(ctx context.Context, chain, from, to, amount, token string) (string, error) {
-	if wm.currentWallet == nil {
-		return "", errors.New("no wallet available - create a wallet first")
+	if err := wm.requireUnlocked(); err != nil {
+		return "", err
 	}
 
 	// Validate required parameters
@@ -530,9 +696,21 @@ func (wm *WalletManager) SendTransaction(ctx context.Context, chain, from, to, a
 	normalizedChain := NormalizeChain(chain)
 
 	// Additional security checks
-	if err := wm.validateTransactionSecurity(normalizedChain, from, to, amount, token); err != nil {
+	if err := wm.validateTransfer(normalizedChain, from, to, amount); err != nil {
 		return "", fmt.Errorf("security validation failed: %w", err)
 	}
+	// Hold the amount against the spend limits until the send is recorded or fails
+	reservation, err := wm.ReserveSpend(normalizedChain, from, to, amount, token)
+	if err != nil {
+		return "", fmt.Errorf("security validation failed: %w", err)
+	}
+	defer reservation.Release()
+	if err := wm.checkSelectedWallet(ctx, from); err != nil {
+		return "", err
+	}
+	if err := wm.AuthorizeSigning(ctx, SigningRequest{Kind: SigningKindSend, Chain: normalizedChain, From: from, To: to, Amount: amount, Token: token}); err != nil {
+		return "", err
+	}
 
 	// Get the chain implementation
 	chainImpl, err := wm.chainFactory.GetChain(chain)
@@ -540,28 +718,103 @@ func (wm *WalletManager) SendTransaction(ctx context.Context, chain, from, to, a
 		return "", err
 	}
 
-	// TODO: In a real implementation, we would need to retrieve the private key
-	// For now, we'll use a mock private key since wallet storage is not fully implemented
-	mockPrivateKey := "0x0000000000000000000000000000000000000000000000000000000000000001"
-	if normalizedChain == "solana" {
-		// Solana path expects base58 private key format.
-		mockPrivateKey = "11111111111111111111111111111111"
+	// A separate fee payer must be an account of this wallet
+	feePayerKey, err := wm.feePayerKey(ctx, normalizedChain, from)
+	if err != nil {
+		return "", err
 	}
 
-	// Send the transaction using the chain implementation
-	return chainImpl.SendTransaction(ctx, from, to, amount, token, mockPrivateKey)
+	// Sign with the key of from, which must belong to the unlocked wallet;
+	// sponsored sends look both keys up themselves
+	var privateKey string
+	if feePayerKey == "" {
+		if privateKey, err = wm.GetPrivateKeyForAddress(ctx, from); err != nil {
+			return "", err
+		}
+	}
+
+	// Send the transaction using the chain implementation, unless it repeats a recent send
+	txHash, duplicate, err := wm.sendDedup.do(SendDigest(normalizedChain, from, to, amount, token), func() (string, error) {
+		// Sponsored sends are not simulated: the simulation would charge the fee to from
+		if feePayerKey != "" {
+			return wm.sendWithFeePayer(ctx, chainImpl, from, to, amount, token, feePayerKey)
+		}
+		if err := wm.simulateSend(ctx, chainImpl, normalizedChain, from, to, amount, token); err != nil {
+			return "", err
+		}
+		return chainImpl.SendTransaction(ctx, from, to, amount, token, privateKey)
+	})
+	if duplicate {
+		wm.logger.Warn("Duplicate send suppressed, returning the earlier transaction",
+			zap.String("chain", normalizedChain),
+			zap.String("from", from),
+			zap.String("to", to),
+			zap.String("amount", amount),
+			zap.String("tx_hash", txHash))
+	} else if err == nil {
+		reservation.Commit(txHash)
+	}
+	return txHash, err
+}
+
+// simulateSend dry-runs a send on chains implementing chain.SendSimulator,
+// unless ctx opts out with chain.WithoutSimulation. Only a send that would
+// revert is refused: when the simulation itself fails the send goes ahead.
+func (wm *WalletManager) simulateSend(ctx context.Context, chainImpl chain.IChain, chainName, from, to, amount, token string) error {
+	simulator, ok := chainImpl.(chain.SendSimulator)
+	if !ok || chain.SimulationSkipped(ctx) {
+		return nil
+	}
+	err := simulator.SimulateSend(ctx, from, to, amount, token)
+	switch {
+	case err == nil, errors.Is(err, chain.ErrSimulationUnavailable):
+		return nil
+	case errors.Is(err, chain.ErrSimulationReverted):
+		wm.logger.Warn("Send aborted, simulation reverted",
+			zap.String("chain", chainName),
+			zap.String("from", from),
+			zap.String("to", to),
+			zap.String("amount", amount),
+			zap.Error(err))
+		return err
+	default:
+		wm.logger.Warn("Send simulation failed, sending without it",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		return nil
+	}
 }
 
-// validateTransactionSecurity performs basic security validations
+// validateTransactionSecurity performs basic security validations, including
+// the spend limits. It only checks them: paths that sign and broadcast at
+// once take a reservation with ReserveSpend instead.
 func (wm *WalletManager) validateTransactionSecurity(chain, from, to, amount, token string) error {
 	normalizedChain := NormalizeChain(chain)
+	if err := wm.validateTransfer(normalizedChain, from, to, amount); err != nil {
+		return err
+	}
+
+	// Refuse transactions above the configured per-transaction and daily caps
+	return wm.checkSpendLimit(normalizedChain, from, to, amount, token)
+}
+
+// ValidateTransfer runs the address, amount and address book checks a send
+// runs before signing, without the spend limits
+func (wm *WalletManager) ValidateTransfer(chainName, from, to, amount string) error {
+	return wm.validateTransfer(chainName, from, to, amount)
+}
+
+// validateTransfer checks the addresses and amount of a transfer and the
+// recipient's address book category
+func (wm *WalletManager) validateTransfer(chain, from, to, amount string) error {
+	normalizedChain := NormalizeChain(chain)
 
 	// Validate addresses
-	if !wm.isValidAddress(normalizedChain, from) {
-		return errors.New("invalid from address")
+	if err := validateAddress(normalizedChain, from); err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
 	}
-	if !wm.isValidAddress(normalizedChain, to) {
-		return errors.New("invalid to address")
+	if err := validateAddress(normalizedChain, to); err != nil {
+		return fmt.Errorf("invalid to address: %w", err)
 	}
 
 	// Validate amount format (basic check)
@@ -579,35 +832,17 @@ func (wm *WalletManager) validateTransactionSecurity(chain, from, to, amount, to
 		return errors.New("cannot send to the same address")
 	}
 
-	// TODO: In a real implementation, add more security checks:
-	// - Check balance before sending
-	// - Implement transaction amount limits
-	// - Add confirmation mechanisms for large transactions
-	// - Validate gas limits and prevent excessive fees
+	// Refuse recipients the address book files under a blocked category
+	if err := wm.addressBook.checkRecipient(normalizedChain, to); err != nil {
+		return err
+	}
 
 	return nil
 }
 
 // isValidAddress checks whether an address is valid for the given chain.
 func (wm *WalletManager) isValidAddress(chain, address string) bool {
-	switch NormalizeChain(chain) {
-	case "ethereum", "bsc":
-		if len(address) != 42 {
-			return false
-		}
-		return strings.HasPrefix(address, "0x")
-	case "solana":
-		if address == "" {
-			return false
-		}
-		decoded, err := base58.Decode(address)
-		if err != nil {
-			return false
-		}
-		return len(decoded) == 32
-	default:
-		return false
-	}
+	return validateAddress(chain, address) == nil
 }
 
 // validateAmount performs basic amount validation
@@ -642,14 +877,228 @@ func (wm *WalletManager) EstimateGas(ctx context.Context, chain, from, to, amoun
 	return chainImpl.EstimateGas(ctx, from, to, amount, token)
 }
 
+// SuggestGasParams prices a transaction on chainName from current mempool conditions.
+// An empty strategy uses the strategy configured for the chain.
+func (wm *WalletManager) SuggestGasParams(ctx context.Context, chainName, strategy string) (*chain.GasParams, error) {
+	chainImpl, err := wm.chainFactory.GetChain(chainName)
+	if err != nil {
+		return nil, err
+	}
+
+	advisor, ok := chainImpl.(chain.GasAdvisor)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", chain.ErrGasStrategyUnsupported, NormalizeChain(chainName))
+	}
+	return advisor.SuggestGasParams(ctx, strategy)
+}
+
+// EstimateConfirmationTime estimates how soon fee is included on chainName from
+// the fees paid in recent blocks. It only reads the chain, so it works while
+// the wallet is locked.
+func (wm *WalletManager) EstimateConfirmationTime(ctx context.Context, chainName string, fee chain.ProposedFee) (*chain.ConfirmationTimeEstimate, error) {
+	chainImpl, err := wm.chainFactory.GetChain(chainName)
+	if err != nil {
+		return nil, err
+	}
+
+	estimator, ok := chainImpl.(chain.ConfirmationTimeEstimator)
+	if !ok {
+		return nil, fmt.Errorf("confirmation time estimates are not supported on %s", NormalizeChain(chainName))
+	}
+	return estimator.EstimateConfirmationTime(ctx, fee)
+}
+
+// NativeReserve returns the native balance that sends on chainName keep back for gas.
+// Chains without a reserve report 0.
+func (wm *WalletManager) NativeReserve(chainName string) float64 {
+	chainImpl, err := wm.chainFactory.GetChain(chainName)
+	if err != nil {
+		return 0
+	}
+	if reserver, ok := chainImpl.(chain.NativeReserver); ok {
+		return reserver.NativeReserve()
+	}
+	return 0
+}
+
+// GetSpendableBalance reports how much of token address can send on chainName once
+// the transfer fee and native reserve are accounted for. An empty token means the
+// chain's native token; other tokens are reported in full along with whether the
+// native balance can pay for the transfer.
+func (wm *WalletManager) GetSpendableBalance(ctx context.Context, chainName, address, token string) (*chain.SpendableBalance, error) {
+	if address == "" {
+		return nil, errors.New("address is required")
+	}
+
+	normalizedChain := NormalizeChain(chainName)
+	nativeToken := NativeTokenSymbol(normalizedChain)
+	if nativeToken == "" {
+		return nil, fmt.Errorf("unsupported chain: %s", chainName)
+	}
+	chainImpl, err := wm.chainFactory.GetChain(normalizedChain)
+	if err != nil {
+		return nil, err
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		token = nativeToken
+	}
+	isNative := strings.EqualFold(token, nativeToken)
+
+	sb := &chain.SpendableBalance{
+		Chain:       normalizedChain,
+		Address:     address,
+		Token:       token,
+		NativeToken: nativeToken,
+		IsNative:    isNative,
+	}
+
+	if sb.Balance, err = chainImpl.GetBalance(ctx, address, token); err != nil {
+		return nil, fmt.Errorf("failed to get %s balance: %w", token, err)
+	}
+	sb.NativeBalance = sb.Balance
+	if !isNative {
+		if sb.NativeBalance, err = chainImpl.GetBalance(ctx, address, nativeToken); err != nil {
+			return nil, fmt.Errorf("failed to get %s balance: %w", nativeToken, err)
+		}
+	}
+
+	// A self-transfer of the full balance is priced like any other transfer
+	gasLimit, gasPrice, err := chainImpl.EstimateGas(ctx, address, address, sb.Balance, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate fee: %w", err)
+	}
+	// Sends pay up to the suggested max fee, so budget for that when the chain offers one
+	if advisor, ok := chainImpl.(chain.GasAdvisor); ok {
+		if params, err := advisor.SuggestGasParams(ctx, ""); err == nil && params.MaxFeeGwei > 0 {
+			gasPrice = strconv.FormatFloat(params.MaxFeeGwei, 'f', -1, 64)
+		}
+	}
+	if sb.Fee, err = chain.EstimateFee(normalizedChain, gasLimit, gasPrice); err != nil {
+		return nil, err
+	}
+	sb.EstimatedFee = sb.Fee.Amount
+
+	reserve := wm.NativeReserve(normalizedChain)
+	sb.Reserve = strconv.FormatFloat(reserve, 'f', -1, 64)
+	if err := chain.ComputeSpendable(sb, reserve); err != nil {
+		return nil, err
+	}
+	return sb, nil
+}
+
+// DefaultChain returns the chain used when a request does not name one
+func (wm *WalletManager) DefaultChain() string {
+	return wm.defaultChain
+}
+
+// DefaultEVMChain returns defaultChain when it is an EVM chain and ethereum
+// otherwise, for requests that can only target an EVM chain such as
+// eth_sendTransaction or a 0x address
+func DefaultEVMChain(defaultChain string) string {
+	if defaultChain == "bsc" {
+		return "bsc"
+	}
+	return "ethereum"
+}
+
+// NativeTokenSymbol returns the fee-paying token of a normalized chain name
+func NativeTokenSymbol(normalizedChain string) string {
+	switch normalizedChain {
+	case "ethereum":
+		return "ETH"
+	case "bsc":
+		return "BNB"
+	case "solana":
+		return "SOL"
+	default:
+		return ""
+	}
+}
+
+// SubmitBundle signs specs with the unlocked Solana key of from and lands them
+// atomically as a Jito bundle with the given tip (0 uses the configured base tip)
+func (wm *WalletManager) SubmitBundle(ctx context.Context, from string, specs []chain.BundleTransactionSpec, tipLamports uint64) (*broadcast.BundleResult, error) {
+	if err := wm.requireUnlocked(); err != nil {
+		return nil, err
+	}
+
+	privateKey, err := wm.signingKey(from, "sign a Jito bundle")
+	if err != nil {
+		return nil, err
+	}
+	for _, spec := range specs {
+		if err := wm.AuthorizeSigning(ctx, SigningRequest{Kind: SigningKindBundle, Chain: "solana", From: from, To: spec.To, Amount: spec.Amount}); err != nil {
+			return nil, err
+		}
+	}
+
+	chainImpl, err := wm.chainFactory.GetChain("solana")
+	if err != nil {
+		return nil, err
+	}
+	submitter, ok := chainImpl.(chain.BundleSubmitter)
+	if !ok {
+		return nil, errors.New("bundles are not supported on solana in this build")
+	}
+	return submitter.SubmitBundle(ctx, specs, privateKey, tipLamports)
+}
+
+// GetTokenBalanceDeltas reports how the transaction txHash changed token
+// balances on chainName. Only chains implementing chain.TokenBalanceDeltaReader
+// (currently Solana) support it.
+func (wm *WalletManager) GetTokenBalanceDeltas(ctx context.Context, chainName, txHash string) ([]chain.TokenBalanceDelta, error) {
+	chainImpl, err := wm.chainFactory.GetChain(NormalizeChain(chainName))
+	if err != nil {
+		return nil, err
+	}
+	reader, ok := chainImpl.(chain.TokenBalanceDeltaReader)
+	if !ok {
+		return nil, fmt.Errorf("token balance deltas are not supported on %s", chainName)
+	}
+	return reader.GetTokenBalanceDeltas(ctx, txHash)
+}
+
+// ClassifyRecipient tells whether address on chainName is a wallet or a
+// contract, and whether sending token there needs explicit confirmation
+func (wm *WalletManager) ClassifyRecipient(ctx context.Context, chainName, address, token string) (*chain.RecipientClassification, error) {
+	chainImpl, err := wm.chainFactory.GetChain(NormalizeChain(chainName))
+	if err != nil {
+		return nil, err
+	}
+	classifier, ok := chainImpl.(chain.RecipientClassifier)
+	if !ok {
+		return nil, fmt.Errorf("recipient classification is not supported on %s", chainName)
+	}
+	recipient, err := classifier.ClassifyRecipient(ctx, address, token)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Name the recipient from the address book, and warn where its category asks for it
+	if entry, ok := wm.addressBook.Lookup(chainName, address); ok {
+		recipient.Label = entry.Label
+		recipient.Category = entry.Category
+		if policy := wm.addressBook.Policy(entry.Category); policy != "" {
+			warning := fmt.Sprintf("the address book lists this recipient as %s", entry.Display())
+			if policy == AddressPolicyBlock {
+				warning += "; sends to it are blocked"
+			}
+			if recipient.Warning != "" {
+				warning = recipient.Warning + "; " + warning
+			}
+			recipient.Warning = warning
+		}
+	}
+	return recipient, nil
+}
+
 // GetPendingTransactions retrieves pending transactions with optional filtering and pagination
 func (wm *WalletManager) GetPendingTransactions(ctx context.Context, chain, address, transactionType string, limit, offset int) ([]*PendingTransaction, error) {
-	// For now, we'll return mock pending transactions for development purposes
-	// In a real implementation, this would:
-	// 1. Query the blockchain network for pending transactions
-	// 2. Filter by owned wallet addresses
-	// 3. Apply the specified filters (chain, address, type)
-	// 4. Return paginated results
+	// Transactions come from the pending store, which holds DApp-submitted
+	// transactions and, in test runs, the development fixtures seeded at startup.
+	// TODO: also query the blockchain network for pending transactions of owned addresses
 	
 	// Validate parameters
 	if limit <= 0 {
@@ -662,21 +1111,18 @@ func (wm *WalletManager) GetPendingTransactions(ctx context.Context, chain, addr
 		offset = 0
 	}
 	
-	// Generate mock pending transactions for development
-	mockTxs := wm.generateMockPendingTransactions(chain, address, transactionType)
-	
-	// Apply pagination
-	start := offset
-	if start >= len(mockTxs) {
-		return []*PendingTransaction{}, nil
+	txs := wm.pending.List(PendingTransactionFilter{
+		Chain:   chain,
+		Address: address,
+		Type:    transactionType,
+		Limit:   limit,
+		Offset:  offset,
+	})
+	for _, tx := range txs {
+		tx.FromLabel = wm.addressBook.label(tx.Chain, tx.From)
+		tx.ToLabel = wm.addressBook.label(tx.Chain, tx.To)
 	}
-	
-	end := start + limit
-	if end > len(mockTxs) {
-		end = len(mockTxs)
-	}
-	
-	return mockTxs[start:end], nil
+	return txs, nil
 }
 
 // generateMockPendingTransactions creates mock pending transactions for development
@@ -749,7 +1195,7 @@ func (wm *WalletManager) generateMockPendingTransactions(chain, address, transac
 	
 	for _, tx := range mockTxs {
 		// Filter by chain
-		if chain != "" && strings.ToLower(tx.Chain) != strings.ToLower(chain) {
+		if chain != "" && NormalizeChain(tx.Chain) != NormalizeChain(chain) {
 			continue
 		}
 		
@@ -773,6 +1219,13 @@ func (wm *WalletManager) generateMockPendingTransactions(chain, address, transac
 
 // RejectTransactions rejects multiple pending transactions with specified reasons
 func (wm *WalletManager) RejectTransactions(ctx context.Context, transactionIds []string, reason, details string, notifyUser, auditLog bool) ([]TransactionRejectionResult, error) {
+	// Normalize the reason so audit entries stay queryable by a fixed taxonomy
+	rejectionReason, err := ParseRejectionReason(reason, details)
+	if err != nil {
+		return nil, err
+	}
+	reason = string(rejectionReason)
+
 	results := make([]TransactionRejectionResult, 0, len(transactionIds))
 	
 	for _, txHash := range transactionIds {
@@ -781,63 +1234,51 @@ func (wm *WalletManager) RejectTransactions(ctx context.Context, transactionIds
 			Success:         false,
 		}
 
-		// Find the transaction in mock data
-		var foundTx *PendingTransaction
-		for _, mockTx := range wm.generateMockPendingTransactions("", "", "") {
-			if mockTx.Hash == txHash {
-				foundTx = mockTx
-				break
+		// Validate and apply the rejection atomically so concurrent callers cannot
+		// reject the same transaction twice
+		rejectionTime := time.Now()
+		var auditLogId string
+		foundTx, err := wm.pending.Update(ctx, txHash, func(tx *PendingTransaction) error {
+			// Check if transaction is already rejected or completed
+			if tx.Status == "rejected" {
+				return errors.New("transaction already rejected")
+			}
+			if tx.Status == "confirmed" {
+				return errors.New("cannot reject confirmed transaction")
 			}
-		}
-
-		if foundTx == nil {
-			result.ErrorMessage = "transaction not found"
-			results = append(results, result)
-			continue
-		}
 
-		// Check if transaction is already rejected or completed
-		if foundTx.Status == "rejected" {
-			result.ErrorMessage = "transaction already rejected"
-			results = append(results, result)
-			continue
-		}
+			// Validate transaction ownership (basic check)
+			if current := wm.GetCurrentWallet(); current != nil && tx.From != current.Address {
+				return errors.New("unauthorized: transaction does not belong to current wallet")
+			}
 
-		if foundTx.Status == "confirmed" {
-			result.ErrorMessage = "cannot reject confirmed transaction"
-			results = append(results, result)
-			continue
-		}
+			// Log to audit trail if requested
+			if auditLog {
+				logId, err := wm.auditLogger.LogTransactionRejection(txHash, reason, details, tx.From)
+				if err != nil {
+					return fmt.Errorf("audit logging failed: %v", err)
+				}
+				auditLogId = logId
+				tx.RejectionAuditLogId = auditLogId
+			}
 
-		// Validate transaction ownership (basic check)
-		if wm.currentWallet != nil && foundTx.From != wm.currentWallet.Address {
-			result.ErrorMessage = "unauthorized: transaction does not belong to current wallet"
+			// Update transaction status
+			tx.Status = "rejected"
+			tx.RejectedAt = &rejectionTime
+			tx.RejectionReason = reason
+			tx.RejectionDetails = details
+			return nil
+		})
+		if err != nil {
+			if errors.Is(err, ErrPendingTransactionNotFound) {
+				result.ErrorMessage = "transaction not found"
+			} else {
+				result.ErrorMessage = err.Error()
+			}
 			results = append(results, result)
 			continue
 		}
 
-		// Perform the rejection
-		rejectionTime := time.Now()
-		
-		// Update transaction status
-		foundTx.Status = "rejected"
-		foundTx.RejectedAt = &rejectionTime
-		foundTx.RejectionReason = reason
-		foundTx.RejectionDetails = details
-
-		// Log to audit trail if requested
-		var auditLogId string
-		if auditLog {
-			logId, err := wm.auditLogger.LogTransactionRejection(txHash, reason, details, foundTx.From)
-			if err != nil {
-				result.ErrorMessage = fmt.Sprintf("audit logging failed: %v", err)
-				results = append(results, result)
-				continue
-			}
-			auditLogId = logId
-			foundTx.RejectionAuditLogId = auditLogId
-		}
-
 		// Send user notification if requested
 		if notifyUser {
 			// In a real implementation, this would send actual notifications
@@ -894,8 +1335,7 @@ func (wm *WalletManager) GetTransactionHistory(ctx context.Context, address stri
 	// 4. Apply block range filters
 	// 5. Return paginated results
 	
-	// Generate mock historical transactions
-	mockTxs := wm.generateMockHistoricalTransactions(address, fromBlock, toBlock)
+	mockTxs := wm.fetchTransactionHistory(address, fromBlock, toBlock)
 	
 	// Apply pagination
 	start := offset
@@ -911,6 +1351,32 @@ func (wm *WalletManager) GetTransactionHistory(ctx context.Context, address stri
 	return mockTxs[start:end], nil
 }
 
+// GetTransactionHistoryPage retrieves a page of historical transactions for
+// filter.Address, continuing from filter.Cursor. Unlike offsets, cursors stay
+// consistent when a chain reorganizes between pages; see PageTransactionHistory.
+func (wm *WalletManager) GetTransactionHistoryPage(ctx context.Context, filter TransactionHistoryFilter) (*TransactionHistoryPage, error) {
+	if filter.Address == "" {
+		return nil, errors.New("address is required")
+	}
+	txs := wm.fetchTransactionHistory(filter.Address, filter.FromBlock, filter.ToBlock)
+	return PageTransactionHistory(txs, filter)
+}
+
+// fetchTransactionHistory returns every historical transaction of address in
+// the block range, classified and labelled from the address book
+func (wm *WalletManager) fetchTransactionHistory(address string, fromBlock, toBlock *uint64) []*HistoricalTransaction {
+	// Generate mock historical transactions
+	txs := wm.generateMockHistoricalTransactions(address, fromBlock, toBlock)
+	
+	// Tag each transaction so callers can tell swaps, approvals and transfers apart
+	for _, tx := range txs {
+		tx.Category = ClassifyTransaction(tx)
+		tx.FromLabel = wm.addressBook.label(tx.Chain, tx.From)
+		tx.ToLabel = wm.addressBook.label(tx.Chain, tx.To)
+	}
+	return txs
+}
+
 // generateMockHistoricalTransactions creates mock historical transactions for development
 func (wm *WalletManager) generateMockHistoricalTransactions(address string, fromBlock, toBlock *uint64) []*HistoricalTransaction {
 	baseTime := time.Now()
@@ -1062,15 +1528,38 @@ func (wm *WalletManager) generateMockHistoricalTransactions(address string, from
 	return filteredTxs
 }
 
-// GetAccounts returns available wallet addresses
+// GetAccounts returns the address of every stored wallet and its discovered
+// accounts. The selected wallet comes first, as dApps treat the first account
+// as the active one.
 func (wm *WalletManager) GetAccounts(ctx context.Context) ([]string, error) {
-	// In a real implementation, this would return all available wallet addresses
-	// For now, return the current wallet address if available
-	if wm.currentWallet == nil || wm.currentWallet.Address == "" {
-		return []string{}, nil
+	records, err := wm.loadWalletRecords(ctx)
+	if err != nil {
+		return nil, err
 	}
 	
-	return []string{wm.currentWallet.Address}, nil
+	accounts := []string{}
+	seen := make(map[string]bool)
+	add := func(address string) {
+		if address != "" && !seen[address] {
+			seen[address] = true
+			accounts = append(accounts, address)
+		}
+	}
+	selectedAddress := wm.selectedWalletAddress()
+	for _, record := range records {
+		if record.data.Address == selectedAddress {
+			add(record.data.Address)
+		}
+	}
+	selected := len(accounts)
+	for _, record := range records {
+		add(record.data.Address)
+		for _, account := range record.data.Accounts {
+			add(account.Address)
+		}
+	}
+	sort.Strings(accounts[selected:])
+	return accounts, nil
 }
 
 // AddPendingTransaction adds a new pending transaction to the queue
@@ -1090,73 +1579,42 @@ func (wm *WalletManager) AddPendingTransaction(ctx context.Context, tx *PendingT
 		return errors.New("to address is required")
 	}
 	
-	// Check if transaction already exists
-	for _, existing := range wm.pendingTxs {
-		if existing.Hash == tx.Hash {
-			return fmt.Errorf("transaction with hash %s already exists", tx.Hash)
-		}
-	}
-	
-	// Add to pending transactions
-	wm.pendingTxs = append(wm.pendingTxs, tx)
-	
-	return nil
+	return wm.pending.Add(ctx, tx)
 }
 
 // Wallet Storage Methods
 
-// saveWalletToDisk saves encrypted wallet data to disk
-func (wm *WalletManager) saveWalletToDisk(walletData *EncryptedWalletData) error {
-	walletFile := filepath.Join(wm.walletDir, "wallet.json")
-	
-	wm.logger.Info("saveWalletToDisk starting", 
-		zap.String("wallet_file", walletFile),
-		zap.String("wallet_dir", wm.walletDir))
-	
-	// Ensure wallet directory exists
-	if err := os.MkdirAll(wm.walletDir, 0700); err != nil {
-		wm.logger.Error("saveWalletToDisk failed to create wallet directory", 
-			zap.Error(err),
-			zap.String("wallet_dir", wm.walletDir))
-		return fmt.Errorf("failed to create wallet directory: %w", err)
-	}
-	wm.logger.Info("saveWalletToDisk wallet directory exists/created")
-	
+// saveWallet persists encrypted wallet data to the state store
+func (wm *WalletManager) saveWallet(walletData *EncryptedWalletData) error {
 	// Convert to JSON
 	jsonData, err := json.MarshalIndent(walletData, "", "  ")
 	if err != nil {
-		wm.logger.Error("saveWalletToDisk failed to marshal wallet data", 
+		wm.logger.Error("saveWallet failed to marshal wallet data", 
 			zap.Error(err))
 		return fmt.Errorf("failed to marshal wallet data: %w", err)
 	}
-	wm.logger.Info("saveWalletToDisk wallet data marshaled", 
-		zap.Int("json_size", len(jsonData)))
 	
-	// Write to file with restricted permissions
-	err = os.WriteFile(walletFile, jsonData, 0600)
-	if err != nil {
-		wm.logger.Error("saveWalletToDisk failed to write wallet file", 
-			zap.Error(err),
-			zap.String("wallet_file", walletFile))
+	if err := wm.store.Put(context.Background(), storage.NamespaceWallets, walletData.Address, jsonData); err != nil {
+		wm.logger.Error("saveWallet failed to store wallet data", 
+			zap.Error(err))
 		return fmt.Errorf("failed to write wallet file: %w", err)
 	}
 	
-	wm.logger.Info("saveWalletToDisk completed successfully", 
-		zap.String("wallet_file", walletFile))
+	wm.logger.Info("saveWallet completed successfully", 
+		zap.Int("json_size", len(jsonData)))
 	return nil
 }
 
-// loadWalletFromDisk loads encrypted wallet data from disk
-func (wm *WalletManager) loadWalletFromDisk() (*EncryptedWalletData, error) {
-	walletFile := filepath.Join(wm.walletDir, "wallet.json")
-	
-	// Check if wallet file exists
-	if _, err := os.Stat(walletFile); os.IsNotExist(err) {
+// loadWallet loads the encrypted data of the selected wallet from the state store
+func (wm *WalletManager) loadWallet() (*EncryptedWalletData, error) {
+	selectedAddress := wm.selectedWalletAddress()
+	if selectedAddress == "" {
+		return nil, errors.New("no wallet found")
+	}
+	jsonData, err := wm.store.Get(context.Background(), storage.NamespaceWallets, selectedAddress)
+	if errors.Is(err, storage.ErrNotFound) {
 		return nil, errors.New("no wallet found")
 	}
-	
-	// Read wallet file
-	jsonData, err := os.ReadFile(walletFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read wallet file: %w", err)
 	}
@@ -1171,12 +1629,27 @@ func (wm *WalletManager) loadWalletFromDisk() (*EncryptedWalletData, error) {
 	return &walletData, nil
 }
 
-// UnlockWallet decrypts and loads wallet data into memory with password
-func (wm *WalletManager) UnlockWallet(password string) error {
-	// Load encrypted wallet data from disk
-	encryptedWallet, err := wm.loadWalletFromDisk()
-	if err != nil {
-		return fmt.Errorf("failed to load wallet: %w", err)
+// UnlockWallet decrypts the stored wallet with address and selects it. An
+// empty address unlocks the selected wallet.
+func (wm *WalletManager) UnlockWallet(address, password string) error {
+	if wm.IsFrozen() {
+		return ErrWalletFrozen
+	}
+	
+	// Load encrypted wallet data from the state store
+	var encryptedWallet *EncryptedWalletData
+	if address == "" {
+		data, err := wm.loadWallet()
+		if err != nil {
+			return fmt.Errorf("failed to load wallet: %w", err)
+		}
+		encryptedWallet = data
+	} else {
+		record, err := wm.findWalletRecord(context.Background(), address)
+		if err != nil {
+			return fmt.Errorf("failed to load wallet: %w", err)
+		}
+		encryptedWallet = record.data
 	}
 	
 	// Decrypt private key
@@ -1192,59 +1665,101 @@ func (wm *WalletManager) UnlockWallet(password string) error {
 	}
 	
 	// Load decrypted data into memory
-	wm.currentWalletData = &DecryptedWalletData{
+	walletData := &DecryptedWalletData{
 		Address:    encryptedWallet.Address,
 		PublicKey:  encryptedWallet.PublicKey,
 		PrivateKey: privateKey,
 		Mnemonic:   mnemonic,
 	}
+	wm.setUnlockedWallet(walletData)
 	
-	// Load wallet status
-	wm.currentWallet = &WalletStatus{
-		Address:   encryptedWallet.Address,
-		PublicKey: encryptedWallet.PublicKey,
-		Chains:    encryptedWallet.Chains,
-		LastUsed:  encryptedWallet.LastUsed,
+	if err := wm.restoreDerivedAccounts(walletData, encryptedWallet.Accounts); err != nil {
+		wm.logger.Warn("Failed to restore discovered accounts", zap.Error(err))
 	}
 	
-	wm.isUnlocked = true
+	// Load wallet status and remember the selection across restarts
+	if err := wm.selectWallet(encryptedWallet); err != nil {
+		return err
+	}
+	wm.startCacheWarmup()
 	
 	return nil
 }
 
-// LockWallet clears sensitive data from memory
+// LockWallet clears the sensitive data of every unlocked wallet from memory
 func (wm *WalletManager) LockWallet() {
-	if wm.currentWalletData != nil {
+	wm.stopCacheWarmup()
+	wm.walletMu.Lock()
+	defer wm.walletMu.Unlock()
+	for address, walletData := range wm.unlockedWallets {
 		// Clear sensitive data
-		wm.currentWalletData.PrivateKey = ""
-		wm.currentWalletData.Mnemonic = ""
-		wm.currentWalletData = nil
+		walletData.PrivateKey = ""
+		walletData.Mnemonic = ""
+		for _, account := range walletData.Accounts {
+			account.PrivateKey = ""
+		}
+		delete(wm.unlockedWallets, address)
 	}
+	wm.currentWalletData = nil
 	wm.isUnlocked = false
 }
 
 // IsUnlocked returns whether the wallet is currently unlocked
 func (wm *WalletManager) IsUnlocked() bool {
+	wm.walletMu.RLock()
+	defer wm.walletMu.RUnlock()
 	return wm.isUnlocked && wm.currentWalletData != nil
 }
 
-// HasWallet returns whether a wallet file exists on disk
+// unlockedWalletData returns the keys of the selected wallet, failing like
+// requireUnlocked while it is locked or frozen
+func (wm *WalletManager) unlockedWalletData() (*DecryptedWalletData, error) {
+	if err := wm.requireUnlocked(); err != nil {
+		return nil, err
+	}
+	wm.walletMu.RLock()
+	defer wm.walletMu.RUnlock()
+	if !wm.isUnlocked || wm.currentWalletData == nil {
+		return nil, ErrWalletLocked
+	}
+	return wm.currentWalletData, nil
+}
+
+// requireUnlocked guards every operation that signs or sends with the wallet's keys
+func (wm *WalletManager) requireUnlocked() error {
+	if wm.IsFrozen() {
+		return ErrWalletFrozen
+	}
+	if !wm.IsUnlocked() {
+		return ErrWalletLocked
+	}
+	return nil
+}
+
+// HasWallet returns whether a wallet has been persisted
 func (wm *WalletManager) HasWallet() bool {
-	walletFile := filepath.Join(wm.walletDir, "wallet.json")
-	_, err := os.Stat(walletFile)
-	return err == nil
+	keys, err := wm.store.List(context.Background(), storage.NamespaceWallets)
+	return err == nil && len(keys) > 0
 }
 
-// GetCurrentWallet returns the current wallet status
+// GetCurrentWallet returns the status of the selected wallet
 func (wm *WalletManager) GetCurrentWallet() *WalletStatus {
+	wm.walletMu.RLock()
+	defer wm.walletMu.RUnlock()
 	return wm.currentWallet
 }
 
+// setCurrentWallet replaces the status of the selected wallet
+func (wm *WalletManager) setCurrentWallet(status *WalletStatus) {
+	wm.walletMu.Lock()
+	defer wm.walletMu.Unlock()
+	wm.currentWallet = status
+}
+
 // SignMessage signs a message with the private key of the specified address
 func (wm *WalletManager) SignMessage(ctx context.Context, address, message string) (signature string, err error) {
-	// Check if wallet is unlocked
-	if !wm.IsUnlocked() {
-		return "", errors.New("wallet is locked")
+	if err := wm.requireUnlocked(); err != nil {
+		return "", err
 	}
 	
 	// Determine which chain to use based on the message type for Solana
@@ -1265,21 +1780,13 @@ func (wm *WalletManager) SignMessage(ctx context.Context, address, message strin
 		}
 	}
 	
-	// Get the appropriate private key for the chain
-	privateKey := wm.currentWalletData.PrivateKey
-	walletAddress := wm.currentWalletData.Address
-	
-	// If we have chain-specific data, use it
-	if wm.currentWalletData.ChainData != nil {
-		if chainData, exists := wm.currentWalletData.ChainData[chainName]; exists {
-			privateKey = chainData.PrivateKey
-			walletAddress = chainData.Address
-		}
+	// The address must belong to the unlocked wallet
+	privateKey, err := wm.signingKey(address, "sign a message")
+	if err != nil {
+		return "", err
 	}
-	
-	// Check if the address matches the current wallet
-	if walletAddress != address {
-		return "", errors.New("address does not match current wallet")
+	if err := wm.AuthorizeSigning(ctx, SigningRequest{Kind: SigningKindMessage, Chain: chainName, From: address, Message: message}); err != nil {
+		return "", err
 	}
 	
 	// Get chain implementation
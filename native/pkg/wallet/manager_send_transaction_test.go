@@ -2,18 +2,45 @@ package wallet
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	solana "github.com/gagliardetto/solana-go"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
-func TestWalletManagerSendTransactionSolanaValidAddresses(t *testing.T) {
-	wm := NewWalletManager()
-	wm.currentWallet = NewWalletStatus("wallet-ready", "pubkey")
+// Placeholder keys in the format each chain expects; they do not match the
+// test addresses, so only chains that do not sign accept them
+const (
+	testEVMPrivateKey    = "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	testSolanaPrivateKey = "4NMwxzmYj2uvHuq8xoqhY8RXg63KSVJM1DXkpbmkUY7YQWuoyQgFnnzn6yo3CMnqZasnNPNuAT2TLwQsCaKkUddp"
+)
+
+// unlockForTest puts wm in the state UnlockWallet leaves it in for address,
+// holding a placeholder key for it
+func unlockForTest(wm *WalletManager, address string) {
+	privateKey := testSolanaPrivateKey
+	if strings.HasPrefix(address, "0x") {
+		privateKey = testEVMPrivateKey
+	}
+	wm.currentWallet = NewWalletStatus(address, "pubkey")
+	wm.currentWalletData = &DecryptedWalletData{Address: address, PublicKey: "pubkey", PrivateKey: privateKey}
+	wm.isUnlocked = true
+}
 
+func TestWalletManagerSendTransactionSolanaValidAddresses(t *testing.T) {
 	from := "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK"
 	to := "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY"
+	wm := NewWalletManager()
+	unlockForTest(wm, from)
 
 	txHash, err := wm.SendTransaction(context.Background(), "solana", from, to, "0.1", "")
 	require.NoError(t, err)
@@ -22,7 +49,7 @@ func TestWalletManagerSendTransactionSolanaValidAddresses(t *testing.T) {
 
 func TestWalletManagerSendTransactionSolanaInvalidFromAddress(t *testing.T) {
 	wm := NewWalletManager()
-	wm.currentWallet = NewWalletStatus("wallet-ready", "pubkey")
+	unlockForTest(wm, "wallet-ready")
 
 	_, err := wm.SendTransaction(
 		context.Background(),
@@ -35,3 +62,192 @@ func TestWalletManagerSendTransactionSolanaInvalidFromAddress(t *testing.T) {
 	require.Error(t, err)
 	require.True(t, strings.Contains(err.Error(), "invalid from address"))
 }
+
+func TestWalletManagerSigningOperationsRequireUnlock(t *testing.T) {
+	wm := NewWalletManager()
+	wm.currentWallet = NewWalletStatus("FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK", "pubkey")
+	ctx := context.Background()
+
+	_, err := wm.SendTransaction(ctx, "solana", "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK", "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY", "0.1", "")
+	require.ErrorIs(t, err, ErrWalletLocked)
+
+	_, err = wm.SignMessage(ctx, "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK", "hello")
+	require.ErrorIs(t, err, ErrWalletLocked)
+
+	_, err = wm.TransferNFT(ctx, "ethereum", nftTestOwner, "0x1111111111111111111111111111111111111111", "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d", "1234", "")
+	require.ErrorIs(t, err, ErrWalletLocked)
+
+	_, err = wm.SubmitBundle(ctx, "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK", nil, 0)
+	require.ErrorIs(t, err, ErrWalletLocked)
+
+	// Locking after an unlock clears the keys and re-arms the guard
+	unlockForTest(wm, "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK")
+	wm.LockWallet()
+	_, err = wm.SendTransaction(ctx, "solana", "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK", "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY", "0.1", "")
+	require.ErrorIs(t, err, ErrWalletLocked)
+}
+
+// simulatedChain reverts every simulated send with revert and counts the
+// sends that reach the chain
+type simulatedChain struct {
+	*chain.ETHChain
+	revert error
+	sends  int
+}
+
+func (c *simulatedChain) SimulateSend(ctx context.Context, from, to, amount, token string) error {
+	return c.revert
+}
+
+func (c *simulatedChain) SendTransaction(ctx context.Context, from, to, amount, token, privateKey string) (string, error) {
+	c.sends++
+	return c.ETHChain.SendTransaction(ctx, from, to, amount, token, privateKey)
+}
+
+func TestWalletManagerSendTransactionAbortsWhenSimulationReverts(t *testing.T) {
+	from := "0x1111111111111111111111111111111111111111"
+	to := "0x2222222222222222222222222222222222222222"
+	wm := NewWalletManager()
+	unlockForTest(wm, from)
+	simulated := &simulatedChain{
+		ETHChain: chain.NewETHChainLegacy(),
+		revert:   &chain.SimulationError{Reason: &chain.RevertReason{Kind: chain.RevertKindError, Message: "insufficient balance"}},
+	}
+	wm.chainFactory.RegisterChain("ethereum", simulated)
+
+	_, err := wm.SendTransaction(context.Background(), "ethereum", from, to, "1", "")
+	require.ErrorIs(t, err, chain.ErrSimulationReverted)
+	require.Contains(t, err.Error(), "insufficient balance")
+	require.Zero(t, simulated.sends, "a send that would revert must not be broadcast")
+
+	// Opting out broadcasts it anyway
+	txHash, err := wm.SendTransaction(chain.WithoutSimulation(context.Background()), "ethereum", from, to, "1", "")
+	require.NoError(t, err)
+	require.NotEmpty(t, txHash)
+	require.Equal(t, 1, simulated.sends)
+
+	// A simulation that cannot run does not block the send
+	simulated.revert = chain.ErrSimulationUnavailable
+	_, err = wm.SendTransaction(context.Background(), "ethereum", from, to, "2", "")
+	require.NoError(t, err)
+	require.Equal(t, 2, simulated.sends)
+}
+
+// sponsoredChain records the sends made with a separate fee payer
+type sponsoredChain struct {
+	*chain.SolanaChain
+	privateKey  string
+	feePayerKey string
+}
+
+func (c *sponsoredChain) SendTransactionWithFeePayer(ctx context.Context, from, to, amount, token, privateKey, feePayerKey string) (string, error) {
+	c.privateKey = privateKey
+	c.feePayerKey = feePayerKey
+	return "sponsored", nil
+}
+
+func TestWalletManagerSendTransactionWithFeePayer(t *testing.T) {
+	from := "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK"
+	to := "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY"
+	feePayer := "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+	wm := NewWalletManager()
+	unlockForTest(wm, from)
+	wm.currentWalletData.PrivateKey = "owner-key"
+	wm.rememberAccount(wm.currentWalletData, feePayer, "pubkey", "fee-payer-key")
+	sponsored := &sponsoredChain{SolanaChain: chain.NewSolanaChainLegacy()}
+	wm.chainFactory.RegisterChain("solana", sponsored)
+
+	txHash, err := wm.SendTransaction(chain.WithFeePayer(context.Background(), feePayer), "solana", from, to, "0.1", "")
+	require.NoError(t, err)
+	require.Equal(t, "sponsored", txHash)
+	require.Equal(t, "owner-key", sponsored.privateKey)
+	require.Equal(t, "fee-payer-key", sponsored.feePayerKey)
+
+	// The fee payer must be held by the wallet
+	_, err = wm.SendTransaction(chain.WithFeePayer(context.Background(), to), "solana", from, feePayer, "0.2", "")
+	require.ErrorIs(t, err, ErrFeePayerNotOwned)
+
+	_, err = wm.SendTransaction(chain.WithFeePayer(context.Background(), feePayer), "ethereum", "0x1111111111111111111111111111111111111111", "0x2222222222222222222222222222222222222222", "1", "")
+	require.Error(t, err)
+}
+
+// keyRecordingChain records the key each send is signed with before handing
+// it to the real chain
+type keyRecordingChain struct {
+	*chain.SolanaChain
+	privateKeys []string
+}
+
+func (c *keyRecordingChain) SendTransaction(ctx context.Context, from, to, amount, token, privateKey string) (string, error) {
+	c.privateKeys = append(c.privateKeys, privateKey)
+	return c.SolanaChain.SendTransaction(ctx, from, to, amount, token, privateKey)
+}
+
+func TestWalletManagerSendTransactionSignsWithImportedSolanaKey(t *testing.T) {
+	t.Setenv("RUN_MODE", "")
+	ctx := context.Background()
+	recipient := "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY"
+	blockhash := solana.HashFromBytes(solana.MustPublicKeyFromBase58(recipient).Bytes())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Method string `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		switch request.Method {
+		case "getLatestBlockhash":
+			_, _ = io.WriteString(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":1},"value":{"blockhash":"`+blockhash.String()+`","lastValidBlockHeight":100}}}`)
+		case "getBalance":
+			_, _ = io.WriteString(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":1},"value":5000000000}}`)
+		default:
+			t.Errorf("unexpected RPC method %s", request.Method)
+			_, _ = io.WriteString(w, `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`)
+		}
+	}))
+	defer server.Close()
+
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	from, _, _, err := wm.ImportWallet(ctx, exportTestMnemonic, "password123", "solana", "m/44'/501'/0'/0'")
+	require.NoError(t, err)
+	require.NoError(t, wm.UnlockWallet(from, "password123"))
+
+	cfg := config.DefaultConfig().Chains.Solana
+	cfg.RPCEndpoints = []string{server.URL}
+	solanaChain, err := chain.NewSolanaChain(nil, zap.NewNop(), &cfg, nil)
+	require.NoError(t, err)
+	recorder := &keyRecordingChain{SolanaChain: solanaChain}
+	wm.chainFactory.RegisterChain("solana", recorder)
+
+	// The real chain refuses to sign with a key that does not match from
+	txHash, err := wm.SendTransaction(chain.WithoutSimulation(ctx), "solana", from, recipient, "0.25", "")
+	require.NoError(t, err)
+	require.NotEmpty(t, txHash)
+	require.Len(t, recorder.privateKeys, 1)
+	signer, err := solana.PrivateKeyFromBase58(recorder.privateKeys[0])
+	require.NoError(t, err)
+	require.Equal(t, from, signer.PublicKey().String())
+
+	// Accounts the wallet does not hold have no key to sign with
+	_, err = wm.SendTransaction(ctx, "solana", recipient, from, "0.25", "")
+	require.ErrorIs(t, err, ErrAddressNotInWallet)
+	require.Len(t, recorder.privateKeys, 1)
+}
+
+func TestWalletManagerSendTransactionPassesWalletKeyToSolanaChain(t *testing.T) {
+	from := "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK"
+	to := "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY"
+	wm := NewWalletManager()
+	unlockForTest(wm, from)
+	recorder := &keyRecordingChain{SolanaChain: chain.NewSolanaChainLegacy()}
+	wm.chainFactory.RegisterChain("solana", recorder)
+
+	_, err := wm.SendTransaction(context.Background(), "solana", from, to, "0.1", "")
+	require.NoError(t, err)
+	require.Equal(t, []string{testSolanaPrivateKey}, recorder.privateKeys)
+
+	// A locked wallet hands no key to the chain
+	wm.LockWallet()
+	_, err = wm.SendTransaction(context.Background(), "solana", from, to, "0.1", "")
+	require.ErrorIs(t, err, ErrWalletLocked)
+	require.Len(t, recorder.privateKeys, 1)
+}
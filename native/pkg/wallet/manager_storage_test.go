@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+func TestWalletManagerPersistsStateThroughStore(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStateStore()
+	wm := NewWalletManagerWithStore(store, nil)
+
+	if wm.HasWallet() {
+		t.Fatal("expected no wallet in an empty store")
+	}
+	if _, _, _, err := wm.ImportWallet(ctx, "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "password123", "ethereum", ""); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if keys, _ := store.List(ctx, storage.NamespaceWallets); len(keys) != 1 {
+		t.Errorf("expected wallet to be stored, got keys %v", keys)
+	}
+
+	tx := &PendingTransaction{Hash: "0xfeed", Chain: "ethereum", From: "0x1", To: "0x2", Amount: "1", Status: "pending"}
+	if err := wm.AddPendingTransaction(ctx, tx); err != nil {
+		t.Fatalf("add pending failed: %v", err)
+	}
+	if _, err := wm.auditLogger.LogTransactionRejection("0xfeed", string(RejectionReasonUserRequest), "", "0x1"); err != nil {
+		t.Fatalf("audit log failed: %v", err)
+	}
+
+	restarted := NewWalletManagerWithStore(store, nil)
+	if !restarted.HasWallet() {
+		t.Error("expected wallet to survive restart")
+	}
+	if _, ok := restarted.pending.Get("0xfeed"); !ok {
+		t.Error("expected pending transaction to survive restart")
+	}
+	if entries := restarted.auditLogger.GetAuditLogByReason(RejectionReasonUserRequest); len(entries) != 1 {
+		t.Errorf("expected audit entry to survive restart, got %d", len(entries))
+	}
+	if err := restarted.AddPendingTransaction(ctx, tx); err == nil {
+		t.Error("expected duplicate pending transaction to be rejected after restart")
+	}
+}
+
+func TestCreateWalletWithSeededEntropyIsReproducible(t *testing.T) {
+	ctx := context.Background()
+	create := func() (string, string) {
+		wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+		wm.SetEntropySource(chain.NewSeededEntropy("fixture"))
+		address, _, mnemonic, err := wm.CreateWallet(ctx, "ethereum", "password123")
+		if err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+		return address, mnemonic
+	}
+
+	address, mnemonic := create()
+	if mnemonic != "east travel code roof strong broom basic cover border gossip plastic corn" {
+		t.Errorf("unexpected mnemonic %q", mnemonic)
+	}
+	if address != "0xD1ebDa6EcDf98c8BF2203fD0F40936162A41eEfe" {
+		t.Errorf("unexpected address %s", address)
+	}
+	if again, _ := create(); again != address {
+		t.Errorf("expected the same seed to give the same wallet, got %s and %s", address, again)
+	}
+}
+
+func TestGuessChainFromAddressUsesDefaultChain(t *testing.T) {
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	evmAddress := "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+	usdt := "0x55d398326f99059fF775485246999027B3197955"
+
+	if got := wm.guessChainFromAddress(evmAddress, usdt); got != "ethereum" {
+		t.Errorf("expected ethereum by default, got %s", got)
+	}
+
+	wm.defaultChain = "bsc"
+	if got := wm.guessChainFromAddress(evmAddress, usdt); got != "bsc" {
+		t.Errorf("expected the bsc default for a hex address, got %s", got)
+	}
+
+	// A Solana default cannot hold a 0x address
+	wm.defaultChain = "solana"
+	if got := wm.guessChainFromAddress(evmAddress, usdt); got != "ethereum" {
+		t.Errorf("expected ethereum for a hex address, got %s", got)
+	}
+	if got := wm.guessChainFromAddress("not-an-address", "unknown"); got != "solana" {
+		t.Errorf("expected the solana default as fallback, got %s", got)
+	}
+}
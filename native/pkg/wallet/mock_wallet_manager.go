@@ -4,6 +4,8 @@ package wallet
 import (
 	"context"
 
+	"github.com/algonius/algonius-wallet/native/pkg/chains/solana/broadcast"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -48,6 +50,156 @@ func (m *MockWalletManager) EstimateGas(ctx context.Context, chain, from, to, am
 	return args.Get(0).(uint64), args.String(1), args.Error(2)
 }
 
+// SuggestGasParams mocks the SuggestGasParams method
+func (m *MockWalletManager) SuggestGasParams(ctx context.Context, chainName, strategy string) (*chain.GasParams, error) {
+	args := m.Called(ctx, chainName, strategy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*chain.GasParams), args.Error(1)
+}
+
+// EstimateConfirmationTime mocks the EstimateConfirmationTime method
+func (m *MockWalletManager) EstimateConfirmationTime(ctx context.Context, chainName string, fee chain.ProposedFee) (*chain.ConfirmationTimeEstimate, error) {
+	args := m.Called(ctx, chainName, fee)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*chain.ConfirmationTimeEstimate), args.Error(1)
+}
+
+// NativeReserve mocks the NativeReserve method
+func (m *MockWalletManager) NativeReserve(chainName string) float64 {
+	args := m.Called(chainName)
+	return args.Get(0).(float64)
+}
+
+// DefaultChain mocks the DefaultChain method
+func (m *MockWalletManager) DefaultChain() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+// GetSpendableBalance mocks the GetSpendableBalance method
+func (m *MockWalletManager) GetSpendableBalance(ctx context.Context, chainName, address, token string) (*chain.SpendableBalance, error) {
+	args := m.Called(ctx, chainName, address, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*chain.SpendableBalance), args.Error(1)
+}
+
+// GetBalanceBreakdown mocks the GetBalanceBreakdown method
+func (m *MockWalletManager) GetBalanceBreakdown(ctx context.Context, chainName, address, token string) (*chain.BalanceBreakdown, error) {
+	args := m.Called(ctx, chainName, address, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*chain.BalanceBreakdown), args.Error(1)
+}
+
+// SubmitBundle mocks the SubmitBundle method
+func (m *MockWalletManager) SubmitBundle(ctx context.Context, from string, specs []chain.BundleTransactionSpec, tipLamports uint64) (*broadcast.BundleResult, error) {
+	args := m.Called(ctx, from, specs, tipLamports)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*broadcast.BundleResult), args.Error(1)
+}
+
+// SignTokenPermit mocks the SignTokenPermit method
+func (m *MockWalletManager) SignTokenPermit(ctx context.Context, chainName string, request chain.PermitRequest) (*chain.SignedPermit, error) {
+	args := m.Called(ctx, chainName, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*chain.SignedPermit), args.Error(1)
+}
+
+// DelegateStake mocks the DelegateStake method
+func (m *MockWalletManager) DelegateStake(ctx context.Context, owner, voteAccount, amount string) (*chain.StakeResult, error) {
+	args := m.Called(ctx, owner, voteAccount, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*chain.StakeResult), args.Error(1)
+}
+
+// DeactivateStake mocks the DeactivateStake method
+func (m *MockWalletManager) DeactivateStake(ctx context.Context, owner, stakeAccount string) (*chain.StakeResult, error) {
+	args := m.Called(ctx, owner, stakeAccount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*chain.StakeResult), args.Error(1)
+}
+
+// WithdrawStake mocks the WithdrawStake method
+func (m *MockWalletManager) WithdrawStake(ctx context.Context, owner, stakeAccount, to, amount string) (*chain.StakeResult, error) {
+	args := m.Called(ctx, owner, stakeAccount, to, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*chain.StakeResult), args.Error(1)
+}
+
+// WrapNative mocks the WrapNative method
+func (m *MockWalletManager) WrapNative(ctx context.Context, chainName, owner, amount string) (*chain.WrapResult, error) {
+	args := m.Called(ctx, chainName, owner, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*chain.WrapResult), args.Error(1)
+}
+
+// UnwrapNative mocks the UnwrapNative method
+func (m *MockWalletManager) UnwrapNative(ctx context.Context, chainName, owner, amount string) (*chain.WrapResult, error) {
+	args := m.Called(ctx, chainName, owner, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*chain.WrapResult), args.Error(1)
+}
+
+// GetTokenBalanceDeltas mocks the GetTokenBalanceDeltas method
+func (m *MockWalletManager) GetTokenBalanceDeltas(ctx context.Context, chainName, txHash string) ([]chain.TokenBalanceDelta, error) {
+	args := m.Called(ctx, chainName, txHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]chain.TokenBalanceDelta), args.Error(1)
+}
+
+// ClassifyRecipient mocks the ClassifyRecipient method
+func (m *MockWalletManager) ClassifyRecipient(ctx context.Context, chainName, address, token string) (*chain.RecipientClassification, error) {
+	args := m.Called(ctx, chainName, address, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*chain.RecipientClassification), args.Error(1)
+}
+
+// DetectTransferFee mocks the DetectTransferFee method
+func (m *MockWalletManager) DetectTransferFee(ctx context.Context, chainName, from, to, amount, token string) (*chain.TokenTransferFee, error) {
+	args := m.Called(ctx, chainName, from, to, amount, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*chain.TokenTransferFee), args.Error(1)
+}
+
+// AuthorizeSigning mocks the AuthorizeSigning method
+func (m *MockWalletManager) AuthorizeSigning(ctx context.Context, req SigningRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+// CheckSigningPolicy mocks the CheckSigningPolicy method
+func (m *MockWalletManager) CheckSigningPolicy(ctx context.Context, req SigningRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
 // GetPendingTransactions mocks the GetPendingTransactions method
 func (m *MockWalletManager) GetPendingTransactions(ctx context.Context, chain, address, transactionType string, limit, offset int) ([]*PendingTransaction, error) {
 	args := m.Called(ctx, chain, address, transactionType, limit, offset)
@@ -66,27 +218,99 @@ func (m *MockWalletManager) GetTransactionHistory(ctx context.Context, address s
 	return args.Get(0).([]*HistoricalTransaction), args.Error(1)
 }
 
+// GetTransactionHistoryPage mocks the GetTransactionHistoryPage method
+func (m *MockWalletManager) GetTransactionHistoryPage(ctx context.Context, filter TransactionHistoryFilter) (*TransactionHistoryPage, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*TransactionHistoryPage), args.Error(1)
+}
+
+// GetBalanceHistory mocks the GetBalanceHistory method
+func (m *MockWalletManager) GetBalanceHistory(ctx context.Context, filter BalanceHistoryFilter) ([]*BalanceSnapshot, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*BalanceSnapshot), args.Error(1)
+}
+
 // GetAccounts mocks the GetAccounts method
 func (m *MockWalletManager) GetAccounts(ctx context.Context) ([]string, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]string), args.Error(1)
 }
 
+// DiscoverAccounts mocks the DiscoverAccounts method
+func (m *MockWalletManager) DiscoverAccounts(ctx context.Context, chainName string, maxAccounts, gapLimit int) ([]*DerivedAccount, error) {
+	args := m.Called(ctx, chainName, maxAccounts, gapLimit)
+	return args.Get(0).([]*DerivedAccount), args.Error(1)
+}
+
+// ImportAccounts mocks the ImportAccounts method
+func (m *MockWalletManager) ImportAccounts(ctx context.Context, chainName string, count int) ([]*DerivedAccount, error) {
+	args := m.Called(ctx, chainName, count)
+	return args.Get(0).([]*DerivedAccount), args.Error(1)
+}
+
 // AddPendingTransaction mocks the AddPendingTransaction method
 func (m *MockWalletManager) AddPendingTransaction(ctx context.Context, tx *PendingTransaction) error {
 	args := m.Called(ctx, tx)
 	return args.Error(0)
 }
 
+// AutoApproveTransaction mocks the AutoApproveTransaction method
+func (m *MockWalletManager) AutoApproveTransaction(ctx context.Context, tx *PendingTransaction, rule, origin string) (string, error) {
+	args := m.Called(ctx, tx, rule, origin)
+	return args.String(0), args.Error(1)
+}
+
 // SignMessage mocks the SignMessage method
 func (m *MockWalletManager) SignMessage(ctx context.Context, address, message string) (string, error) {
 	args := m.Called(ctx, address, message)
 	return args.String(0), args.Error(1)
 }
 
+// GetPrivateKeyForAddress mocks the GetPrivateKeyForAddress method
+func (m *MockWalletManager) GetPrivateKeyForAddress(ctx context.Context, address string) (string, error) {
+	args := m.Called(ctx, address)
+	return args.String(0), args.Error(1)
+}
+
+// ReserveSpend mocks the ReserveSpend method
+func (m *MockWalletManager) ReserveSpend(chainName, from, to, amount, token string) (*SpendReservation, error) {
+	args := m.Called(chainName, from, to, amount, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*SpendReservation), args.Error(1)
+}
+
+// CheckSpendLimit mocks the CheckSpendLimit method
+func (m *MockWalletManager) CheckSpendLimit(chainName, from, to, amount, token string) error {
+	args := m.Called(chainName, from, to, amount, token)
+	return args.Error(0)
+}
+
+// ValidateTransfer mocks the ValidateTransfer method
+func (m *MockWalletManager) ValidateTransfer(chainName, from, to, amount string) error {
+	args := m.Called(chainName, from, to, amount)
+	return args.Error(0)
+}
+
+// GetNFTs mocks the GetNFTs method
+func (m *MockWalletManager) GetNFTs(ctx context.Context, chain, owner string) ([]*NFTAsset, error) {
+	args := m.Called(ctx, chain, owner)
+	return args.Get(0).([]*NFTAsset), args.Error(1)
+}
+
+// TransferNFT mocks the TransferNFT method
+func (m *MockWalletManager) TransferNFT(ctx context.Context, chain, from, to, contractAddress, tokenID, amount string) (string, error) {
+	args := m.Called(ctx, chain, from, to, contractAddress, tokenID, amount)
+	return args.String(0), args.Error(1)
+}
+
 // UnlockWallet mocks the UnlockWallet method
-func (m *MockWalletManager) UnlockWallet(password string) error {
-	args := m.Called(password)
+func (m *MockWalletManager) UnlockWallet(address, password string) error {
+	args := m.Called(address, password)
 	return args.Error(0)
 }
 
@@ -111,4 +335,163 @@ func (m *MockWalletManager) HasWallet() bool {
 func (m *MockWalletManager) GetCurrentWallet() *WalletStatus {
 	args := m.Called()
 	return args.Get(0).(*WalletStatus)
-}
\ No newline at end of file
+}
+
+// ListWallets mocks the ListWallets method
+func (m *MockWalletManager) ListWallets(ctx context.Context) ([]*StoredWallet, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*StoredWallet), args.Error(1)
+}
+
+// SwitchWallet mocks the SwitchWallet method
+func (m *MockWalletManager) SwitchWallet(ctx context.Context, address string) error {
+	args := m.Called(ctx, address)
+	return args.Error(0)
+}
+
+// SetWalletLabel mocks the SetWalletLabel method
+func (m *MockWalletManager) SetWalletLabel(ctx context.Context, address, label string) (*StoredWallet, error) {
+	args := m.Called(ctx, address, label)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*StoredWallet), args.Error(1)
+}
+
+// ExportWallet mocks the ExportWallet method
+func (m *MockWalletManager) ExportWallet(ctx context.Context, password, source string) (*WalletExport, error) {
+	args := m.Called(ctx, password, source)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*WalletExport), args.Error(1)
+}
+
+// FreezeWallet mocks the FreezeWallet method
+func (m *MockWalletManager) FreezeWallet(ctx context.Context, reason, source string) (*FreezeStatus, error) {
+	args := m.Called(ctx, reason, source)
+	return args.Get(0).(*FreezeStatus), args.Error(1)
+}
+
+// UnfreezeWallet mocks the UnfreezeWallet method
+func (m *MockWalletManager) UnfreezeWallet(ctx context.Context, password, source string) error {
+	args := m.Called(ctx, password, source)
+	return args.Error(0)
+}
+
+// IsFrozen mocks the IsFrozen method
+func (m *MockWalletManager) IsFrozen() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+// GetFreezeStatus mocks the GetFreezeStatus method
+func (m *MockWalletManager) GetFreezeStatus() *FreezeStatus {
+	args := m.Called()
+	return args.Get(0).(*FreezeStatus)
+}
+
+// ScheduleTransaction mocks the ScheduleTransaction method
+func (m *MockWalletManager) ScheduleTransaction(ctx context.Context, job *ScheduledTransaction) (*ScheduledTransaction, error) {
+	args := m.Called(ctx, job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ScheduledTransaction), args.Error(1)
+}
+
+// ListScheduledTransactions mocks the ListScheduledTransactions method
+func (m *MockWalletManager) ListScheduledTransactions(ctx context.Context, status string) ([]*ScheduledTransaction, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*ScheduledTransaction), args.Error(1)
+}
+
+// CancelScheduledTransaction mocks the CancelScheduledTransaction method
+func (m *MockWalletManager) CancelScheduledTransaction(ctx context.Context, id string) (*ScheduledTransaction, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ScheduledTransaction), args.Error(1)
+}
+
+// CreatePriceTrigger mocks the CreatePriceTrigger method
+func (m *MockWalletManager) CreatePriceTrigger(ctx context.Context, trigger *PriceTrigger) (*PriceTrigger, error) {
+	args := m.Called(ctx, trigger)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PriceTrigger), args.Error(1)
+}
+
+// ListPriceTriggers mocks the ListPriceTriggers method
+func (m *MockWalletManager) ListPriceTriggers(ctx context.Context, status string) ([]*PriceTrigger, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*PriceTrigger), args.Error(1)
+}
+
+// CancelPriceTrigger mocks the CancelPriceTrigger method
+func (m *MockWalletManager) CancelPriceTrigger(ctx context.Context, id string) (*PriceTrigger, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PriceTrigger), args.Error(1)
+}
+
+// AddAddressBookEntry mocks the AddAddressBookEntry method
+func (m *MockWalletManager) AddAddressBookEntry(ctx context.Context, entry *AddressBookEntry) (*AddressBookEntry, error) {
+	args := m.Called(ctx, entry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*AddressBookEntry), args.Error(1)
+}
+
+// RemoveAddressBookEntry mocks the RemoveAddressBookEntry method
+func (m *MockWalletManager) RemoveAddressBookEntry(ctx context.Context, chainName, address string) (*AddressBookEntry, error) {
+	args := m.Called(ctx, chainName, address)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*AddressBookEntry), args.Error(1)
+}
+
+// ListAddressBook mocks the ListAddressBook method
+func (m *MockWalletManager) ListAddressBook(ctx context.Context, chainName, category string) ([]*AddressBookEntry, error) {
+	args := m.Called(ctx, chainName, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*AddressBookEntry), args.Error(1)
+}
+
+// LookupAddressBook mocks the LookupAddressBook method
+func (m *MockWalletManager) LookupAddressBook(chainName, address string) (*AddressBookEntry, bool) {
+	args := m.Called(chainName, address)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1)
+	}
+	return args.Get(0).(*AddressBookEntry), args.Bool(1)
+}
+
+// AddressBookPolicy mocks the AddressBookPolicy method
+func (m *MockWalletManager) AddressBookPolicy(category string) string {
+	args := m.Called(category)
+	return args.String(0)
+}
+
+// CheckRecipient mocks the CheckRecipient method
+func (m *MockWalletManager) CheckRecipient(chainName, address string) error {
+	args := m.Called(chainName, address)
+	return args.Error(0)
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"go.uber.org/zap"
+)
+
+// WrapNative converts amount of owner's native coin ("max" for everything
+// above the reserve) into the chain's wrapped native token
+func (wm *WalletManager) WrapNative(ctx context.Context, chainName, owner, amount string) (*chain.WrapResult, error) {
+	normalizedChain := NormalizeChain(chainName)
+	wrapper, wrapped, privateKey, err := wm.nativeWrapper(normalizedChain, owner)
+	if err != nil {
+		return nil, err
+	}
+	if err := wm.validateTransactionSecurity(normalizedChain, owner, wrapped.Address, amount, ""); err != nil {
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+	if err := wm.AuthorizeSigning(ctx, SigningRequest{Kind: SigningKindWrap, Chain: normalizedChain, From: owner, To: wrapped.Address, Amount: amount}); err != nil {
+		return nil, err
+	}
+	result, err := wrapper.WrapNative(ctx, owner, amount, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	wm.logWrap(result)
+	return result, nil
+}
+
+// UnwrapNative converts amount of owner's wrapped native token ("max" for all
+// of it) back into the native coin
+func (wm *WalletManager) UnwrapNative(ctx context.Context, chainName, owner, amount string) (*chain.WrapResult, error) {
+	normalizedChain := NormalizeChain(chainName)
+	wrapper, wrapped, privateKey, err := wm.nativeWrapper(normalizedChain, owner)
+	if err != nil {
+		return nil, err
+	}
+	if err := wm.AuthorizeSigning(ctx, SigningRequest{Kind: SigningKindWrap, Chain: normalizedChain, From: owner, To: owner, Amount: amount, Token: wrapped.Address}); err != nil {
+		return nil, err
+	}
+	result, err := wrapper.UnwrapNative(ctx, owner, amount, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	wm.logWrap(result)
+	return result, nil
+}
+
+// nativeWrapper returns the wrapping support of a normalized chain, its
+// wrapped native token and the key of owner
+func (wm *WalletManager) nativeWrapper(chainName, owner string) (chain.NativeWrapper, chain.WrappedNativeToken, string, error) {
+	if err := wm.requireUnlocked(); err != nil {
+		return nil, chain.WrappedNativeToken{}, "", err
+	}
+	wrapped, ok := chain.WrappedNative(chainName)
+	if !ok {
+		return nil, chain.WrappedNativeToken{}, "", fmt.Errorf("%s has no wrapped native token", chainName)
+	}
+	privateKey, err := wm.ownerSigningKey(owner, "wrap or unwrap native tokens")
+	if err != nil {
+		return nil, chain.WrappedNativeToken{}, "", err
+	}
+	chainImpl, err := wm.chainFactory.GetChain(chainName)
+	if err != nil {
+		return nil, chain.WrappedNativeToken{}, "", err
+	}
+	wrapper, ok := chainImpl.(chain.NativeWrapper)
+	if !ok {
+		return nil, chain.WrappedNativeToken{}, "", fmt.Errorf("wrapping is not supported on %s in this build", chainName)
+	}
+	return wrapper, wrapped, privateKey, nil
+}
+
+func (wm *WalletManager) logWrap(result *chain.WrapResult) {
+	wm.logger.Info("Native token "+result.Direction+" submitted",
+		zap.String("chain", result.Chain),
+		zap.String("owner", result.Owner),
+		zap.String("amount", result.Amount),
+		zap.String("wrapped", result.Wrapped.Symbol),
+		zap.String("tx_hash", result.TxHash))
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/stretchr/testify/require"
+)
+
+// wrappingChain records the wrap calls it receives
+type wrappingChain struct {
+	*chain.SolanaChain
+	calls []string
+	keys  []string
+}
+
+func (c *wrappingChain) WrapNative(_ context.Context, owner, amount, privateKey string) (*chain.WrapResult, error) {
+	c.calls = append(c.calls, "wrap "+owner+" "+amount)
+	c.keys = append(c.keys, privateKey)
+	return &chain.WrapResult{Direction: chain.WrapDirectionWrap, TxHash: "wrapped", Owner: owner, Amount: amount}, nil
+}
+
+func (c *wrappingChain) UnwrapNative(_ context.Context, owner, amount, privateKey string) (*chain.WrapResult, error) {
+	c.calls = append(c.calls, "unwrap "+owner+" "+amount)
+	c.keys = append(c.keys, privateKey)
+	return &chain.WrapResult{Direction: chain.WrapDirectionUnwrap, TxHash: "unwrapped", Owner: owner, Amount: amount}, nil
+}
+
+func TestWalletManagerNativeWrap(t *testing.T) {
+	owner := "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK"
+	ctx := context.Background()
+
+	wm := NewWalletManager()
+	_, err := wm.WrapNative(ctx, "solana", owner, "1")
+	require.ErrorIs(t, err, ErrWalletLocked)
+
+	unlockForTest(wm, owner)
+	wm.currentWalletData.PrivateKey = "owner-key"
+	wrapping := &wrappingChain{SolanaChain: chain.NewSolanaChainLegacy()}
+	wm.chainFactory.RegisterChain("solana", wrapping)
+
+	result, err := wm.WrapNative(ctx, "sol", owner, "1.5")
+	require.NoError(t, err)
+	require.Equal(t, "wrapped", result.TxHash)
+	_, err = wm.UnwrapNative(ctx, "solana", owner, "max")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"wrap " + owner + " 1.5", "unwrap " + owner + " max"}, wrapping.calls)
+	require.Equal(t, []string{"owner-key", "owner-key"}, wrapping.keys)
+
+	// Rejected before reaching the chain
+	_, err = wm.WrapNative(ctx, "solana", "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY", "1")
+	require.ErrorIs(t, err, ErrOwnerNotInWallet)
+	_, err = wm.UnwrapNative(ctx, "ethereum", "0x1111111111111111111111111111111111111111", "1")
+	require.ErrorIs(t, err, ErrOwnerNotInWallet)
+	_, err = wm.WrapNative(ctx, "polygon", owner, "1")
+	require.ErrorContains(t, err, "has no wrapped native token")
+	require.Len(t, wrapping.calls, 2)
+}
@@ -0,0 +1,107 @@
+package wallet
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/stretchr/testify/require"
+)
+
+const nftTestOwner = "0x742D35Cc6634c0532925a3B8D4C2B79c2b86A7a8"
+
+func TestWalletManagerGetNFTs(t *testing.T) {
+	wm := NewWalletManager()
+
+	nfts, err := wm.GetNFTs(context.Background(), "eth", nftTestOwner)
+	require.NoError(t, err)
+	require.NotEmpty(t, nfts)
+	for _, nft := range nfts {
+		require.Equal(t, "ethereum", nft.Chain)
+		require.Equal(t, nftTestOwner, nft.Owner)
+		require.NotEmpty(t, nft.MetadataURI)
+	}
+
+	_, err = wm.GetNFTs(context.Background(), "solana", nftTestOwner)
+	require.Error(t, err)
+}
+
+func TestWalletManagerTransferNFT(t *testing.T) {
+	wm := NewWalletManager()
+	unlockForTest(wm, nftTestOwner)
+	to := "0x1111111111111111111111111111111111111111"
+
+	txHash, err := wm.TransferNFT(context.Background(), "ethereum", nftTestOwner, to, "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d", "1234", "")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(txHash, "0x"))
+
+	// Not owned
+	_, err = wm.TransferNFT(context.Background(), "ethereum", nftTestOwner, to, "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D", "9999", "1")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "does not own")
+
+	// ERC-721 cannot move more than one
+	_, err = wm.TransferNFT(context.Background(), "ethereum", nftTestOwner, to, "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D", "1234", "2")
+	require.Error(t, err)
+
+	// ERC-1155 amount above holdings
+	_, err = wm.TransferNFT(context.Background(), "ethereum", nftTestOwner, to, "0x495f947276749Ce646f68AC8c248420045cb7b5e", "42", "5")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "insufficient NFT balance")
+
+	// From an address outside the unlocked wallet
+	_, err = wm.TransferNFT(context.Background(), "ethereum", "0x3333333333333333333333333333333333333333", to, "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d", "1234", "")
+	require.ErrorIs(t, err, ErrAddressNotInWallet)
+}
+
+// callGasChain records the contract calls it is asked to estimate
+type callGasChain struct {
+	*chain.ETHChain
+	calls []chain.EVMCall
+	err   error
+}
+
+func (c *callGasChain) EstimateCallGas(ctx context.Context, call chain.EVMCall) (uint64, error) {
+	c.calls = append(c.calls, call)
+	return 85000, c.err
+}
+
+func TestWalletManagerTransferNFTEstimatesSafeTransferFrom(t *testing.T) {
+	wm := NewWalletManager()
+	unlockForTest(wm, nftTestOwner)
+	eth := &callGasChain{ETHChain: chain.NewETHChainLegacy()}
+	wm.chainFactory.RegisterChain("ethereum", eth)
+	to := "0x1111111111111111111111111111111111111111"
+	contract := "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D"
+
+	_, err := wm.TransferNFT(context.Background(), "ethereum", nftTestOwner, to, contract, "1234", "")
+	require.NoError(t, err)
+	require.Len(t, eth.calls, 1)
+	require.Equal(t, contract, eth.calls[0].To)
+	require.True(t, strings.HasPrefix(eth.calls[0].Data, "0x42842e0e"), "expected safeTransferFrom calldata, got %s", eth.calls[0].Data)
+
+	eth.err = errors.New("execution reverted")
+	_, err = wm.TransferNFT(context.Background(), "ethereum", nftTestOwner, to, contract, "1234", "")
+	require.ErrorContains(t, err, "failed to estimate gas for NFT transfer")
+}
+
+func TestBuildNFTTransferCalldata(t *testing.T) {
+	to := "0x0000000000000000000000000000000000000002"
+
+	data, err := BuildNFTTransferCalldata(NFTStandardERC721, nftTestOwner, to, "1234", "1")
+	require.NoError(t, err)
+	require.Len(t, data, 4+3*32)
+	require.Equal(t, "42842e0e", hex.EncodeToString(data[:4]))
+
+	data, err = BuildNFTTransferCalldata(NFTStandardERC1155, nftTestOwner, to, "42", "3")
+	require.NoError(t, err)
+	require.Len(t, data, 4+6*32)
+	require.Equal(t, "f242432a", hex.EncodeToString(data[:4]))
+	require.Equal(t, byte(3), data[4+4*32-1])
+
+	_, err = BuildNFTTransferCalldata(NFTStandardERC721, nftTestOwner, to, "not-a-number", "1")
+	require.Error(t, err)
+}
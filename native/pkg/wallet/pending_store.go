@@ -0,0 +1,349 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+)
+
+var (
+	// ErrPendingTransactionExists is returned when adding a hash that is already tracked
+	ErrPendingTransactionExists = errors.New("transaction already exists")
+	// ErrPendingTransactionNotFound is returned when a hash is not tracked
+	ErrPendingTransactionNotFound = errors.New("transaction not found")
+	// ErrPendingQueueFull is returned when adding would exceed the configured maximum
+	ErrPendingQueueFull = errors.New("pending transaction queue is full")
+)
+
+// pendingEntry is a tracked transaction plus its bookkeeping
+type pendingEntry struct {
+	tx      *PendingTransaction
+	seq     uint64 // insertion order, used for stable listing
+	persist bool   // false for development fixtures that must never reach the state store
+}
+
+// PendingStore indexes pending transactions by hash, chain and address.
+// All methods are safe for concurrent use and hand out copies, so callers
+// can never mutate a tracked transaction outside of Update.
+type PendingStore struct {
+	mu        sync.RWMutex
+	byHash    map[string]*pendingEntry
+	byChain   map[string]map[string]struct{}
+	byAddress map[string]map[string]struct{}
+	nextSeq   uint64
+	store     storage.StateStore
+	// maxEntries caps the transactions queued through Add; 0 means no limit
+	maxEntries int
+}
+
+// NewPendingStore creates a PendingStore that writes persistent entries to store.
+// A nil store keeps everything in memory.
+func NewPendingStore(store storage.StateStore) *PendingStore {
+	return &PendingStore{
+		byHash:    make(map[string]*pendingEntry),
+		byChain:   make(map[string]map[string]struct{}),
+		byAddress: make(map[string]map[string]struct{}),
+		store:     store,
+	}
+}
+
+// Load restores transactions previously persisted to the state store
+func (ps *PendingStore) Load(ctx context.Context) error {
+	if ps.store == nil {
+		return nil
+	}
+
+	hashes, err := ps.store.List(ctx, storage.NamespacePending)
+	if err != nil {
+		return err
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for _, hash := range hashes {
+		data, err := ps.store.Get(ctx, storage.NamespacePending, hash)
+		if err != nil {
+			return err
+		}
+		var tx PendingTransaction
+		if err := json.Unmarshal(data, &tx); err != nil {
+			return fmt.Errorf("failed to parse pending transaction %s: %w", hash, err)
+		}
+		if _, exists := ps.byHash[tx.Hash]; !exists {
+			ps.insertLocked(&tx, true)
+		}
+	}
+	return nil
+}
+
+// SetMaxEntries caps how many transactions Add may queue; 0 removes the cap.
+// Transactions already tracked are kept even if they exceed it.
+func (ps *PendingStore) SetMaxEntries(max int) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.maxEntries = max
+}
+
+// Add tracks tx and persists it. Adding a hash that is already tracked fails
+// with ErrPendingTransactionExists. When the queue is at its maximum the
+// oldest settled transactions are evicted to make room; if none are settled
+// Add fails with ErrPendingQueueFull.
+func (ps *PendingStore) Add(ctx context.Context, tx *PendingTransaction) error {
+	return ps.add(ctx, tx, true)
+}
+
+// addFixture tracks tx in memory only
+func (ps *PendingStore) addFixture(tx *PendingTransaction) error {
+	return ps.add(context.Background(), tx, false)
+}
+
+func (ps *PendingStore) add(ctx context.Context, tx *PendingTransaction, persist bool) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if _, exists := ps.byHash[tx.Hash]; exists {
+		return fmt.Errorf("%w: %s", ErrPendingTransactionExists, tx.Hash)
+	}
+
+	if persist && ps.maxEntries > 0 {
+		if err := ps.makeRoomLocked(ctx); err != nil {
+			return err
+		}
+	}
+
+	stored := *tx
+	// Persist before indexing so a storage failure does not leave an unsaved transaction queued
+	if persist {
+		if err := ps.persistLocked(ctx, &stored); err != nil {
+			return err
+		}
+	}
+	ps.insertLocked(&stored, persist)
+	return nil
+}
+
+// Get returns a copy of the transaction with the given hash
+func (ps *PendingStore) Get(hash string) (*PendingTransaction, bool) {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	entry, ok := ps.byHash[hash]
+	if !ok {
+		return nil, false
+	}
+	tx := *entry.tx
+	return &tx, true
+}
+
+// List returns copies of the transactions matching filter in insertion order.
+// Chain and address narrow the search through their indexes; Limit <= 0 means no limit.
+func (ps *PendingStore) List(filter PendingTransactionFilter) []*PendingTransaction {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	var candidates []*pendingEntry
+	switch {
+	case filter.Chain != "" || filter.Address != "":
+		candidates = ps.indexedCandidatesLocked(filter.Chain, filter.Address)
+	default:
+		candidates = make([]*pendingEntry, 0, len(ps.byHash))
+		for _, entry := range ps.byHash {
+			candidates = append(candidates, entry)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].seq < candidates[j].seq })
+
+	result := make([]*PendingTransaction, 0, len(candidates))
+	for _, entry := range candidates {
+		if filter.Type != "" && !strings.EqualFold(entry.tx.Type, filter.Type) {
+			continue
+		}
+		tx := *entry.tx
+		result = append(result, &tx)
+	}
+
+	if filter.Offset > 0 {
+		if filter.Offset >= len(result) {
+			return []*PendingTransaction{}
+		}
+		result = result[filter.Offset:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(result) {
+		result = result[:filter.Limit]
+	}
+	return result
+}
+
+// Update applies fn to a copy of the tracked transaction and commits the result
+// atomically. If fn returns an error the transaction is left unchanged.
+func (ps *PendingStore) Update(ctx context.Context, hash string, fn func(tx *PendingTransaction) error) (*PendingTransaction, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	entry, ok := ps.byHash[hash]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPendingTransactionNotFound, hash)
+	}
+
+	updated := *entry.tx
+	if err := fn(&updated); err != nil {
+		return nil, err
+	}
+	updated.Hash = hash // the primary key is immutable
+
+	if entry.persist {
+		if err := ps.persistLocked(ctx, &updated); err != nil {
+			return nil, err
+		}
+	}
+
+	ps.unindexLocked(entry.tx)
+	entry.tx = &updated
+	ps.indexLocked(entry.tx)
+
+	result := updated
+	return &result, nil
+}
+
+// Len returns the number of tracked transactions
+func (ps *PendingStore) Len() int {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	return len(ps.byHash)
+}
+
+// makeRoomLocked evicts the oldest settled transactions until the queue is
+// below its maximum. Transactions still awaiting a decision are never evicted.
+func (ps *PendingStore) makeRoomLocked(ctx context.Context) error {
+	var queued, settled []*pendingEntry
+	for _, entry := range ps.byHash {
+		if !entry.persist {
+			continue // development fixtures do not take up queue space
+		}
+		queued = append(queued, entry)
+		if isSettledStatus(entry.tx.Status) {
+			settled = append(settled, entry)
+		}
+	}
+	excess := len(queued) - ps.maxEntries + 1
+	if excess <= 0 {
+		return nil
+	}
+	if len(settled) < excess {
+		return fmt.Errorf("%w: %d of %d transactions are awaiting approval", ErrPendingQueueFull, len(queued)-len(settled), ps.maxEntries)
+	}
+
+	sort.Slice(settled, func(i, j int) bool { return settled[i].seq < settled[j].seq })
+	for _, entry := range settled[:excess] {
+		if ps.store != nil {
+			if err := ps.store.Delete(ctx, storage.NamespacePending, entry.tx.Hash); err != nil {
+				return fmt.Errorf("failed to evict pending transaction: %w", err)
+			}
+		}
+		ps.unindexLocked(entry.tx)
+		delete(ps.byHash, entry.tx.Hash)
+	}
+	return nil
+}
+
+// isSettledStatus reports whether a transaction no longer needs a decision
+func isSettledStatus(status string) bool {
+	switch strings.ToLower(status) {
+	case "confirmed", "failed", "rejected":
+		return true
+	}
+	return false
+}
+
+func (ps *PendingStore) insertLocked(tx *PendingTransaction, persist bool) {
+	ps.nextSeq++
+	ps.byHash[tx.Hash] = &pendingEntry{tx: tx, seq: ps.nextSeq, persist: persist}
+	ps.indexLocked(tx)
+}
+
+func (ps *PendingStore) persistLocked(ctx context.Context, tx *PendingTransaction) error {
+	if ps.store == nil {
+		return nil
+	}
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pending transaction: %w", err)
+	}
+	if err := ps.store.Put(ctx, storage.NamespacePending, tx.Hash, data); err != nil {
+		return fmt.Errorf("failed to persist pending transaction: %w", err)
+	}
+	return nil
+}
+
+// indexedCandidatesLocked intersects the chain and address indexes
+func (ps *PendingStore) indexedCandidatesLocked(chainName, address string) []*pendingEntry {
+	var sets []map[string]struct{}
+	if chainName != "" {
+		sets = append(sets, ps.byChain[NormalizeChain(chainName)])
+	}
+	if address != "" {
+		sets = append(sets, ps.byAddress[strings.ToLower(address)])
+	}
+
+	// Iterate the smallest set and check membership in the rest
+	sort.Slice(sets, func(i, j int) bool { return len(sets[i]) < len(sets[j]) })
+	candidates := make([]*pendingEntry, 0, len(sets[0]))
+	for hash := range sets[0] {
+		matches := true
+		for _, other := range sets[1:] {
+			if _, ok := other[hash]; !ok {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			candidates = append(candidates, ps.byHash[hash])
+		}
+	}
+	return candidates
+}
+
+func (ps *PendingStore) indexLocked(tx *PendingTransaction) {
+	addToIndex(ps.byChain, NormalizeChain(tx.Chain), tx.Hash)
+	for _, address := range pendingAddressKeys(tx) {
+		addToIndex(ps.byAddress, address, tx.Hash)
+	}
+}
+
+func (ps *PendingStore) unindexLocked(tx *PendingTransaction) {
+	removeFromIndex(ps.byChain, NormalizeChain(tx.Chain), tx.Hash)
+	for _, address := range pendingAddressKeys(tx) {
+		removeFromIndex(ps.byAddress, address, tx.Hash)
+	}
+}
+
+// pendingAddressKeys returns the lower-cased from/to addresses of tx without duplicates
+func pendingAddressKeys(tx *PendingTransaction) []string {
+	from, to := strings.ToLower(tx.From), strings.ToLower(tx.To)
+	if to == "" || to == from {
+		return []string{from}
+	}
+	return []string{from, to}
+}
+
+func addToIndex(index map[string]map[string]struct{}, key, hash string) {
+	if index[key] == nil {
+		index[key] = make(map[string]struct{})
+	}
+	index[key][hash] = struct{}{}
+}
+
+func removeFromIndex(index map[string]map[string]struct{}, key, hash string) {
+	delete(index[key], hash)
+	if len(index[key]) == 0 {
+		delete(index, key)
+	}
+}
@@ -0,0 +1,323 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+)
+
+func newTestPendingTx(hash, chainName, from, txType string) *PendingTransaction {
+	return &PendingTransaction{
+		Hash:   hash,
+		Chain:  chainName,
+		From:   from,
+		To:     "0x1111111111111111111111111111111111111111",
+		Amount: "1",
+		Type:   txType,
+		Status: "pending",
+	}
+}
+
+func TestPendingStoreIndexes(t *testing.T) {
+	ctx := context.Background()
+	ps := NewPendingStore(nil)
+
+	alice := "0xAAAAaaaaAAAAaaaaAAAAaaaaAAAAaaaaAAAAaaaa"
+	bob := "0xBBBBbbbbBBBBbbbbBBBBbbbbBBBBbbbbBBBBbbbb"
+	for _, tx := range []*PendingTransaction{
+		newTestPendingTx("0x01", "ethereum", alice, "transfer"),
+		newTestPendingTx("0x02", "bsc", alice, "swap"),
+		newTestPendingTx("0x03", "ethereum", bob, "swap"),
+	} {
+		if err := ps.Add(ctx, tx); err != nil {
+			t.Fatalf("add failed: %v", err)
+		}
+	}
+
+	if err := ps.Add(ctx, newTestPendingTx("0x01", "ethereum", alice, "transfer")); !errors.Is(err, ErrPendingTransactionExists) {
+		t.Errorf("expected ErrPendingTransactionExists, got %v", err)
+	}
+
+	if tx, ok := ps.Get("0x02"); !ok || tx.Chain != "bsc" {
+		t.Errorf("unexpected lookup result: %+v, %v", tx, ok)
+	}
+
+	cases := []struct {
+		name   string
+		filter PendingTransactionFilter
+		want   []string
+	}{
+		{"all in insertion order", PendingTransactionFilter{}, []string{"0x01", "0x02", "0x03"}},
+		{"chain alias", PendingTransactionFilter{Chain: "ETH"}, []string{"0x01", "0x03"}},
+		{"address case-insensitive", PendingTransactionFilter{Address: "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}, []string{"0x01", "0x02"}},
+		{"chain and address", PendingTransactionFilter{Chain: "ethereum", Address: alice}, []string{"0x01"}},
+		{"type", PendingTransactionFilter{Type: "SWAP"}, []string{"0x02", "0x03"}},
+		{"pagination", PendingTransactionFilter{Limit: 1, Offset: 1}, []string{"0x02"}},
+		{"offset past end", PendingTransactionFilter{Offset: 5}, []string{}},
+	}
+	for _, tc := range cases {
+		got := ps.List(tc.filter)
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: expected %v, got %d results", tc.name, tc.want, len(got))
+			continue
+		}
+		for i := range tc.want {
+			if got[i].Hash != tc.want[i] {
+				t.Errorf("%s: expected %v at %d, got %s", tc.name, tc.want[i], i, got[i].Hash)
+			}
+		}
+	}
+
+	// Returned values are copies
+	got, _ := ps.Get("0x01")
+	got.Status = "tampered"
+	if tx, _ := ps.Get("0x01"); tx.Status != "pending" {
+		t.Error("mutating a returned transaction must not affect the store")
+	}
+}
+
+func TestPendingStoreUpdate(t *testing.T) {
+	ctx := context.Background()
+	backing := storage.NewMemoryStateStore()
+	ps := NewPendingStore(backing)
+	if err := ps.Add(ctx, newTestPendingTx("0x01", "ethereum", "0xabc", "transfer")); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	if _, err := ps.Update(ctx, "0x01", func(tx *PendingTransaction) error {
+		tx.Status = "rejected"
+		return errors.New("abort")
+	}); err == nil {
+		t.Fatal("expected update error")
+	}
+	if tx, _ := ps.Get("0x01"); tx.Status != "pending" {
+		t.Errorf("failed update must not change the transaction, got %s", tx.Status)
+	}
+
+	updated, err := ps.Update(ctx, "0x01", func(tx *PendingTransaction) error {
+		tx.Status = "confirmed"
+		tx.Chain = "bsc"
+		return nil
+	})
+	if err != nil || updated.Status != "confirmed" {
+		t.Fatalf("unexpected update result: %+v, %v", updated, err)
+	}
+	if len(ps.List(PendingTransactionFilter{Chain: "ethereum"})) != 0 || len(ps.List(PendingTransactionFilter{Chain: "bsc"})) != 1 {
+		t.Error("expected chain index to follow the update")
+	}
+
+	if _, err := ps.Update(ctx, "0x99", func(*PendingTransaction) error { return nil }); !errors.Is(err, ErrPendingTransactionNotFound) {
+		t.Errorf("expected ErrPendingTransactionNotFound, got %v", err)
+	}
+
+	reloaded := NewPendingStore(backing)
+	if err := reloaded.Load(ctx); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if tx, ok := reloaded.Get("0x01"); !ok || tx.Status != "confirmed" {
+		t.Errorf("expected persisted update, got %+v", tx)
+	}
+
+	if err := ps.addFixture(newTestPendingTx("0xf1", "ethereum", "0xabc", "transfer")); err != nil {
+		t.Fatalf("add fixture failed: %v", err)
+	}
+	if keys, _ := backing.List(ctx, storage.NamespacePending); len(keys) != 1 {
+		t.Errorf("fixtures must not be persisted, got keys %v", keys)
+	}
+}
+
+func TestPendingStoreConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	ps := NewPendingStore(storage.NewMemoryStateStore())
+
+	const workers = 16
+	const perWorker = 50
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				hash := fmt.Sprintf("0x%02x%04x", w, i)
+				if err := ps.Add(ctx, newTestPendingTx(hash, "ethereum", fmt.Sprintf("0x%040x", w), "transfer")); err != nil {
+					t.Errorf("add failed: %v", err)
+				}
+				ps.List(PendingTransactionFilter{Chain: "ethereum", Limit: 10})
+				ps.Get(hash)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if ps.Len() != workers*perWorker {
+		t.Fatalf("expected %d transactions, got %d", workers*perWorker, ps.Len())
+	}
+
+	// Concurrent status transitions on the same hash: exactly one caller wins
+	var winners int32
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := ps.Update(ctx, "0x000000", func(tx *PendingTransaction) error {
+				if tx.Status != "pending" {
+					return errors.New("already handled")
+				}
+				tx.Status = "rejected"
+				return nil
+			})
+			if err == nil {
+				atomic.AddInt32(&winners, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if winners != 1 {
+		t.Errorf("expected exactly one successful status transition, got %d", winners)
+	}
+}
+
+func TestRejectTransactionsConcurrentlyRejectsOnce(t *testing.T) {
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	hash := "0xreject"
+	if err := wm.AddPendingTransaction(context.Background(), newTestPendingTx(hash, "ethereum", "0xabc", "transfer")); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var successes int32
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results, err := wm.RejectTransactions(context.Background(), []string{hash}, "user_request", "", false, true)
+			if err == nil && len(results) == 1 && results[0].Success {
+				atomic.AddInt32(&successes, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if successes != 1 {
+		t.Errorf("expected exactly one successful rejection, got %d", successes)
+	}
+	if tx, _ := wm.pending.Get(hash); tx.Status != "rejected" || tx.RejectionAuditLogId == "" {
+		t.Errorf("expected rejected transaction with audit id, got %+v", tx)
+	}
+}
+
+func TestPendingStoreRejectsWhenFull(t *testing.T) {
+	ctx := context.Background()
+	ps := NewPendingStore(storage.NewMemoryStateStore())
+	ps.SetMaxEntries(3)
+	_ = ps.addFixture(newTestPendingTx("0xfixture", "ethereum", "0xcccc", "transfer"))
+
+	for i := 0; i < 3; i++ {
+		if err := ps.Add(ctx, newTestPendingTx(fmt.Sprintf("0x%02d", i), "ethereum", "0xaaaa", "transfer")); err != nil {
+			t.Fatalf("add %d failed: %v", i, err)
+		}
+	}
+
+	err := ps.Add(ctx, newTestPendingTx("0x03", "ethereum", "0xaaaa", "transfer"))
+	if !errors.Is(err, ErrPendingQueueFull) {
+		t.Fatalf("expected ErrPendingQueueFull for the 4th transaction, got %v", err)
+	}
+	if _, ok := ps.Get("0x03"); ok {
+		t.Error("rejected transaction must not be tracked")
+	}
+	if ps.Len() != 4 {
+		t.Errorf("expected 3 queued transactions plus the fixture, got %d", ps.Len())
+	}
+}
+
+func TestPendingStoreEvictsSettledTransactionsWhenFull(t *testing.T) {
+	ctx := context.Background()
+	backing := storage.NewMemoryStateStore()
+	ps := NewPendingStore(backing)
+	ps.SetMaxEntries(2)
+
+	for _, hash := range []string{"0x01", "0x02"} {
+		if err := ps.Add(ctx, newTestPendingTx(hash, "ethereum", "0xaaaa", "transfer")); err != nil {
+			t.Fatalf("add failed: %v", err)
+		}
+	}
+	if _, err := ps.Update(ctx, "0x01", func(tx *PendingTransaction) error {
+		tx.Status = "rejected"
+		return nil
+	}); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+
+	if err := ps.Add(ctx, newTestPendingTx("0x03", "ethereum", "0xaaaa", "transfer")); err != nil {
+		t.Fatalf("expected the rejected transaction to be evicted, got %v", err)
+	}
+	if _, ok := ps.Get("0x01"); ok {
+		t.Error("oldest settled transaction should have been evicted")
+	}
+	if _, err := backing.Get(ctx, storage.NamespacePending, "0x01"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("evicted transaction should be deleted from the store, got %v", err)
+	}
+	if len(ps.List(PendingTransactionFilter{Address: "0xaaaa"})) != 2 {
+		t.Error("evicted transaction should be removed from the indexes")
+	}
+
+	if err := ps.Add(ctx, newTestPendingTx("0x04", "ethereum", "0xaaaa", "transfer")); !errors.Is(err, ErrPendingQueueFull) {
+		t.Errorf("pending transactions must never be evicted, got %v", err)
+	}
+}
+
+func TestGetPendingTransactionsSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := storage.NewFileStateStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wm := NewWalletManagerWithStore(store, nil)
+	if txs, _ := wm.GetPendingTransactions(ctx, "", "", "", 100, 0); len(txs) != 0 {
+		t.Fatalf("expected no mock transactions outside test runs, got %d", len(txs))
+	}
+	if err := wm.AddPendingTransaction(ctx, newTestPendingTx("0xdapp1", "ethereum", "0xabc", "transfer")); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if err := wm.AddPendingTransaction(ctx, newTestPendingTx("0xdapp2", "bsc", "0xdef", "swap")); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	store, err = storage.NewFileStateStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restarted := NewWalletManagerWithStore(store, nil)
+	txs, err := restarted.GetPendingTransactions(ctx, "", "", "", 100, 0)
+	if err != nil || len(txs) != 2 {
+		t.Fatalf("expected both transactions after restart, got %d, %v", len(txs), err)
+	}
+	if txs, _ := restarted.GetPendingTransactions(ctx, "bsc", "0xdef", "swap", 10, 0); len(txs) != 1 || txs[0].Hash != "0xdapp2" {
+		t.Errorf("expected the filters to apply to persisted transactions, got %+v", txs)
+	}
+	if txs, _ := restarted.GetPendingTransactions(ctx, "", "", "", 1, 1); len(txs) != 1 {
+		t.Errorf("expected pagination to apply, got %d transactions", len(txs))
+	}
+}
+
+func TestMockPendingTransactionsOnlyInTestRuns(t *testing.T) {
+	t.Setenv("RUN_MODE", "test")
+	store := storage.NewMemoryStateStore()
+	wm := NewWalletManagerWithStore(store, nil)
+	txs, _ := wm.GetPendingTransactions(context.Background(), "", "", "", 100, 0)
+	if len(txs) == 0 {
+		t.Fatal("expected development fixtures when RUN_MODE=test")
+	}
+	if keys, _ := store.List(context.Background(), storage.NamespacePending); len(keys) != 0 {
+		t.Errorf("fixtures must not be persisted, got keys %v", keys)
+	}
+}
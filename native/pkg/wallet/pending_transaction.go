@@ -25,6 +25,10 @@ type PendingTransaction struct {
 	SubmittedAt               time.Time `json:"submitted_at"`
 	LastChecked               time.Time `json:"last_checked"`
 	
+	// Address book labels of From and To, with their category; set when listed
+	FromLabel                 string    `json:"from_label,omitempty"`
+	ToLabel                   string    `json:"to_label,omitempty"`
+	
 	// Rejection-related fields
 	RejectedAt               *time.Time `json:"rejected_at,omitempty"`
 	RejectionReason          string     `json:"rejection_reason,omitempty"`
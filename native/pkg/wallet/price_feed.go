@@ -0,0 +1,273 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
+)
+
+// PriceFeed resolves the USD price of a token symbol
+type PriceFeed interface {
+	USDPrice(ctx context.Context, symbol string) (float64, error)
+}
+
+// HistoricalPriceFeed is implemented by price feeds that can also price a
+// symbol at a past time, used to value confirmed transactions
+type HistoricalPriceFeed interface {
+	USDPriceAt(ctx context.Context, symbol string, at time.Time) (float64, error)
+}
+
+// LastKnownPriceFeed is implemented by price feeds that remember their latest
+// quote, so valuations can fall back to it while the feed is failing
+type LastKnownPriceFeed interface {
+	LastKnownUSDPrice(symbol string) (price float64, fetchedAt time.Time, ok bool)
+}
+
+// ErrPriceFeedUnavailable marks errors caused by the price source being down,
+// as opposed to a symbol it cannot price
+var ErrPriceFeedUnavailable = errors.New("price feed unavailable")
+
+// DefaultPriceTTL is how long DEXPriceFeed reuses a quoted price
+const DefaultPriceTTL = time.Minute
+
+// MaxStalePriceAge bounds how old a last known price may be to stand in for a
+// live one
+const MaxStalePriceAge = 24 * time.Hour
+
+// Circuit breaker of DEXPriceFeed: after priceFeedFailureThreshold consecutive
+// failed quotes the DEX is left alone for priceFeedCooldown
+const (
+	priceFeedFailureThreshold = 3
+	priceFeedCooldown         = 30 * time.Second
+)
+
+// Bounds on the quotes DEXPriceFeed keeps to answer USDPriceAt
+const (
+	// HistoricalPriceTolerance is how far a remembered quote may be from the requested time
+	HistoricalPriceTolerance = 15 * time.Minute
+	maxPriceHistory          = 1440
+)
+
+// priceQuote describes how a native token is quoted against a USD stablecoin
+type priceQuote struct {
+	chain   string
+	chainID string
+	quoteIn string
+	address string // placeholder sender required by quote validation
+}
+
+var priceQuotes = map[string]priceQuote{
+	"ETH": {chain: "ethereum", chainID: "1", quoteIn: "USDT", address: "0x0000000000000000000000000000000000000000"},
+	"BNB": {chain: "bsc", chainID: "56", quoteIn: "USDT", address: "0x0000000000000000000000000000000000000000"},
+	"SOL": {chain: "solana", chainID: "501", quoteIn: "USDC", address: "11111111111111111111111111111111"},
+}
+
+// USD stablecoins are taken at face value rather than quoted
+var usdStablecoins = map[string]bool{"USDT": true, "USDC": true, "DAI": true, "BUSD": true}
+
+// DEXPriceFeed prices native tokens by quoting one unit against a stablecoin
+// through the DEX aggregator, caching each price for a short while. Repeated
+// quote failures open a circuit breaker that fails fast until it cools down.
+type DEXPriceFeed struct {
+	aggregator dex.IDEXAggregator
+	ttl        time.Duration
+	cache      map[string]cachedPrice
+	history    map[string][]cachedPrice // quotes in fetch order, oldest first
+	failures   int                      // consecutive failed quotes
+	openUntil  time.Time                // quotes are skipped until then
+	// Past prices beyond the remembered quotes, and the prices already read
+	historicalSource HistoricalPriceSource
+	historicalCache  *historicalPriceCache
+	mutex            sync.Mutex
+}
+
+type cachedPrice struct {
+	price     float64
+	fetchedAt time.Time
+}
+
+// NewDEXPriceFeed creates a price feed backed by aggregator
+func NewDEXPriceFeed(aggregator dex.IDEXAggregator) *DEXPriceFeed {
+	return &DEXPriceFeed{
+		aggregator: aggregator,
+		ttl:        DefaultPriceTTL,
+		cache:      make(map[string]cachedPrice),
+		history:    make(map[string][]cachedPrice),
+
+		historicalCache: newHistoricalPriceCache(0),
+	}
+}
+
+// SetAggregator replaces the aggregator used for quotes, for hosts that build
+// the aggregator after the feed has been handed out
+func (f *DEXPriceFeed) SetAggregator(aggregator dex.IDEXAggregator) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.aggregator = aggregator
+	f.cache = make(map[string]cachedPrice)
+	f.history = make(map[string][]cachedPrice)
+}
+
+// USDPrice returns the USD price of one unit of symbol
+func (f *DEXPriceFeed) USDPrice(ctx context.Context, symbol string) (float64, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if usdStablecoins[symbol] {
+		return 1, nil
+	}
+	quote, ok := priceQuotes[symbol]
+	if !ok {
+		return 0, fmt.Errorf("no price source for %s", symbol)
+	}
+
+	f.mutex.Lock()
+	aggregator := f.aggregator
+	cached, ok := f.cache[symbol]
+	openUntil := f.openUntil
+	f.mutex.Unlock()
+	if aggregator == nil {
+		return 0, fmt.Errorf("%w: no DEX aggregator", ErrPriceFeedUnavailable)
+	}
+	if ok && time.Since(cached.fetchedAt) < f.ttl {
+		return cached.price, nil
+	}
+	if wait := time.Until(openUntil); wait > 0 {
+		return 0, fmt.Errorf("%w: paused after %d failed quotes, retrying in %s", ErrPriceFeedUnavailable, priceFeedFailureThreshold, wait.Round(time.Second))
+	}
+
+	result, err := aggregator.GetBestQuote(ctx, dex.SwapParams{
+		FromToken:   symbol,
+		ToToken:     quote.quoteIn,
+		Amount:      "1",
+		Slippage:    0.005,
+		FromAddress: quote.address,
+		ToAddress:   quote.address,
+		ChainID:     quote.chainID,
+	})
+	if err != nil {
+		f.recordFailure()
+		return 0, fmt.Errorf("%w: failed to quote %s: %v", ErrPriceFeedUnavailable, symbol, err)
+	}
+	price, err := strconv.ParseFloat(result.ToAmount, 64)
+	if err != nil || price <= 0 {
+		f.recordFailure()
+		return 0, fmt.Errorf("%w: invalid %s quote amount %q", ErrPriceFeedUnavailable, symbol, result.ToAmount)
+	}
+
+	quoted := cachedPrice{price: price, fetchedAt: time.Now()}
+	f.mutex.Lock()
+	f.failures = 0
+	f.openUntil = time.Time{}
+	f.cache[symbol] = quoted
+	history := append(f.history[symbol], quoted)
+	if len(history) > maxPriceHistory {
+		history = history[len(history)-maxPriceHistory:]
+	}
+	f.history[symbol] = history
+	f.mutex.Unlock()
+	return price, nil
+}
+
+// recordFailure counts a failed quote and opens the circuit breaker once the
+// failures reach priceFeedFailureThreshold
+func (f *DEXPriceFeed) recordFailure() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.failures++
+	if f.failures >= priceFeedFailureThreshold {
+		f.openUntil = time.Now().Add(priceFeedCooldown)
+		f.failures = 0
+	}
+}
+
+// LastKnownUSDPrice returns the most recent quote for symbol, however old
+func (f *DEXPriceFeed) LastKnownUSDPrice(symbol string) (float64, time.Time, bool) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if usdStablecoins[symbol] {
+		return 1, time.Now(), true
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	cached, ok := f.cache[symbol]
+	return cached.price, cached.fetchedAt, ok
+}
+
+// PriceResult is a current USD price and whether it is a stale stand-in
+type PriceResult struct {
+	Price     float64
+	Stale     bool
+	FetchedAt time.Time // when a stale price was quoted; zero for live prices
+}
+
+// CurrentUSDPrice prices symbol now. When the feed fails it falls back to the
+// feed's last known price if that is at most MaxStalePriceAge old, otherwise
+// the feed's error is returned.
+func CurrentUSDPrice(ctx context.Context, priceFeed PriceFeed, symbol string) (PriceResult, error) {
+	price, err := priceFeed.USDPrice(ctx, symbol)
+	if err == nil {
+		return PriceResult{Price: price}, nil
+	}
+	if lastKnown, ok := priceFeed.(LastKnownPriceFeed); ok {
+		if price, fetchedAt, found := lastKnown.LastKnownUSDPrice(symbol); found && price > 0 && time.Since(fetchedAt) <= MaxStalePriceAge {
+			return PriceResult{Price: price, Stale: true, FetchedAt: fetchedAt}, nil
+		}
+	}
+	return PriceResult{}, err
+}
+
+// USDPriceAt returns the USD price of symbol at a past time. The DEX only
+// quotes current prices, so this is the quote taken closest to at while the
+// host was running, provided it is within HistoricalPriceTolerance; times
+// within the cache TTL of now are quoted live. Native tokens fall back to the
+// historical price source when one is set.
+func (f *DEXPriceFeed) USDPriceAt(ctx context.Context, symbol string, at time.Time) (float64, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if usdStablecoins[symbol] {
+		return 1, nil
+	}
+	if time.Since(at) < f.ttl {
+		return f.USDPrice(ctx, symbol)
+	}
+	if price, ok := f.recordedPriceAt(symbol, at); ok {
+		return price, nil
+	}
+
+	f.mutex.Lock()
+	source := f.historicalSource
+	f.mutex.Unlock()
+	if quote, ok := priceQuotes[symbol]; ok && source != nil {
+		return f.GetHistoricalPrice(ctx, symbol, quote.chain, at)
+	}
+	return 0, fmt.Errorf("no %s price recorded near %s", symbol, at.UTC().Format(time.RFC3339))
+}
+
+// recordedPriceAt returns the remembered quote of symbol closest to at, if it
+// is within HistoricalPriceTolerance
+func (f *DEXPriceFeed) recordedPriceAt(symbol string, at time.Time) (float64, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	var closest *cachedPrice
+	for i := range f.history[symbol] {
+		quote := &f.history[symbol][i]
+		if closest == nil || absDuration(quote.fetchedAt.Sub(at)) < absDuration(closest.fetchedAt.Sub(at)) {
+			closest = quote
+		}
+	}
+	if closest == nil || absDuration(closest.fetchedAt.Sub(at)) > HistoricalPriceTolerance {
+		return 0, false
+	}
+	return closest.price, true
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/dex"
+)
+
+// quoteAggregator quotes every swap at a fixed output amount
+type quoteAggregator struct {
+	dex.IDEXAggregator
+	toAmount string
+	quotes   int
+}
+
+func (a *quoteAggregator) GetBestQuote(_ context.Context, params dex.SwapParams) (*dex.SwapQuote, error) {
+	a.quotes++
+	return &dex.SwapQuote{FromToken: params.FromToken, ToToken: params.ToToken, ToAmount: a.toAmount}, nil
+}
+
+func TestDEXPriceFeedUSDPriceAt(t *testing.T) {
+	ctx := context.Background()
+	aggregator := &quoteAggregator{toAmount: "2000"}
+	feed := NewDEXPriceFeed(aggregator)
+
+	if _, err := feed.USDPriceAt(ctx, "ETH", time.Now().Add(-time.Hour)); err == nil {
+		t.Error("expected no historical price before any quote was taken")
+	}
+	if price, err := feed.USDPriceAt(ctx, "eth", time.Now()); err != nil || price != 2000 {
+		t.Fatalf("expected a live quote for the present, got %v, %v", price, err)
+	}
+
+	// Quotes are remembered and the closest one within the tolerance is used
+	feed.history["ETH"] = append([]cachedPrice{{price: 1500, fetchedAt: time.Now().Add(-2 * time.Hour)}}, feed.history["ETH"]...)
+	if price, err := feed.USDPriceAt(ctx, "ETH", time.Now().Add(-2*time.Hour-5*time.Minute)); err != nil || price != 1500 {
+		t.Errorf("expected the quote from two hours ago, got %v, %v", price, err)
+	}
+	if price, err := feed.USDPriceAt(ctx, "ETH", time.Now().Add(-10*time.Minute)); err != nil || price != 2000 {
+		t.Errorf("expected the latest quote, got %v, %v", price, err)
+	}
+	if _, err := feed.USDPriceAt(ctx, "ETH", time.Now().Add(-time.Hour)); err == nil {
+		t.Error("expected no price when every quote is outside the tolerance")
+	}
+	if aggregator.quotes != 1 {
+		t.Errorf("historical lookups must not quote the DEX, got %d quotes", aggregator.quotes)
+	}
+	if price, err := feed.USDPriceAt(ctx, "USDC", time.Now().Add(-24*time.Hour)); err != nil || price != 1 {
+		t.Errorf("stablecoins are taken at face value, got %v, %v", price, err)
+	}
+}
+
+// failingAggregator fails every quote while err is set
+type failingAggregator struct {
+	quoteAggregator
+	err error
+}
+
+func (a *failingAggregator) GetBestQuote(ctx context.Context, params dex.SwapParams) (*dex.SwapQuote, error) {
+	if a.err != nil {
+		a.quotes++
+		return nil, a.err
+	}
+	return a.quoteAggregator.GetBestQuote(ctx, params)
+}
+
+func TestDEXPriceFeedCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	aggregator := &failingAggregator{quoteAggregator: quoteAggregator{toAmount: "2000"}}
+	feed := NewDEXPriceFeed(aggregator)
+
+	if _, err := feed.USDPrice(ctx, "ETH"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	feed.cache["ETH"] = cachedPrice{price: 2000, fetchedAt: time.Now().Add(-10 * time.Minute)}
+
+	aggregator.err = errors.New("upstream timeout")
+	for i := 0; i < priceFeedFailureThreshold; i++ {
+		if _, err := feed.USDPrice(ctx, "ETH"); !errors.Is(err, ErrPriceFeedUnavailable) {
+			t.Fatalf("expected ErrPriceFeedUnavailable, got %v", err)
+		}
+	}
+	quotes := aggregator.quotes
+	if _, err := feed.USDPrice(ctx, "ETH"); !errors.Is(err, ErrPriceFeedUnavailable) || !strings.Contains(err.Error(), "retrying in") {
+		t.Errorf("expected the open circuit to fail fast, got %v", err)
+	}
+	if aggregator.quotes != quotes {
+		t.Error("an open circuit must not quote the DEX")
+	}
+
+	// The last known quote stands in while the feed is down
+	price, err := CurrentUSDPrice(ctx, feed, "ETH")
+	if err != nil || !price.Stale || price.Price != 2000 {
+		t.Errorf("expected the stale last known price, got %+v, %v", price, err)
+	}
+	if _, err := CurrentUSDPrice(ctx, feed, "SOL"); !errors.Is(err, ErrPriceFeedUnavailable) {
+		t.Errorf("expected no stand-in for a symbol never quoted, got %v", err)
+	}
+	feed.cache["ETH"] = cachedPrice{price: 2000, fetchedAt: time.Now().Add(-MaxStalePriceAge - time.Minute)}
+	if _, err := CurrentUSDPrice(ctx, feed, "ETH"); err == nil {
+		t.Error("expected prices older than MaxStalePriceAge to be ignored")
+	}
+
+	// Once the cooldown has passed, a successful quote closes the circuit
+	aggregator.err = nil
+	feed.openUntil = time.Now().Add(-time.Second)
+	if price, err := feed.USDPrice(ctx, "ETH"); err != nil || price != 2000 {
+		t.Errorf("expected a live quote after the cooldown, got %v, %v", price, err)
+	}
+	if _, err := feed.USDPrice(ctx, "DOGE"); err == nil || errors.Is(err, ErrPriceFeedUnavailable) {
+		t.Errorf("an unsupported symbol is not a feed outage, got %v", err)
+	}
+}
@@ -0,0 +1,492 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// Price trigger comparisons
+const (
+	TriggerBelow = "below" // fires when the price is at or below the threshold
+	TriggerAbove = "above" // fires when the price is at or above the threshold
+)
+
+// Price trigger actions
+const (
+	TriggerActionSend = "send"
+	TriggerActionSwap = "swap"
+)
+
+// Price trigger statuses
+const (
+	TriggerStatusActive    = "active"
+	TriggerStatusFiring    = "firing"
+	TriggerStatusFired     = "fired" // fired as often as allowed
+	TriggerStatusFailed    = "failed"
+	TriggerStatusCancelled = "cancelled"
+)
+
+// priceTriggerPollInterval is how often RunPriceTriggers evaluates the triggers
+const priceTriggerPollInterval = 30 * time.Second
+
+var (
+	// ErrPriceTriggerNotFound is returned for an unknown trigger id
+	ErrPriceTriggerNotFound = errors.New("price trigger not found")
+	// ErrPriceTriggerFinished is returned when cancelling a trigger that already finished
+	ErrPriceTriggerFinished = errors.New("price trigger already finished")
+)
+
+// PriceTriggerAction is what a trigger does when its condition is met: send
+// Amount of Token to To, or swap Amount of Token for ToToken
+type PriceTriggerAction struct {
+	Kind     string  `json:"kind"`
+	Chain    string  `json:"chain"`
+	From     string  `json:"from"`
+	To       string  `json:"to,omitempty"` // send recipient
+	Amount   string  `json:"amount"`
+	Token    string  `json:"token,omitempty"`    // sent token or swap input, native when empty
+	ToToken  string  `json:"to_token,omitempty"` // swap output
+	Slippage float64 `json:"slippage,omitempty"` // swap slippage, e.g. 0.005
+}
+
+// PriceTrigger runs Action when the USD price of Symbol crosses Threshold.
+// A trigger that may fire again is re-armed only once the condition stops
+// holding, so one dip fires it once however long it lasts.
+type PriceTrigger struct {
+	ID         string             `json:"id"`
+	Symbol     string             `json:"symbol"`
+	Comparison string             `json:"comparison"`
+	Threshold  float64            `json:"threshold"` // USD
+	Action     PriceTriggerAction `json:"action"`
+	MaxFires   int                `json:"max_fires"`          // 0 fires until cancelled
+	Cooldown   time.Duration      `json:"cooldown,omitempty"` // minimum time between fires
+	Fires      int                `json:"fires"`
+	Armed      bool               `json:"armed"`
+	Status     string             `json:"status"`
+
+	ConfirmLarge             bool `json:"confirm_large,omitempty"`
+	ConfirmContractRecipient bool `json:"confirm_contract_recipient,omitempty"`
+
+	LastPrice     float64   `json:"last_price,omitempty"`
+	LastCheckedAt time.Time `json:"last_checked_at,omitempty"`
+	LastFiredAt   time.Time `json:"last_fired_at,omitempty"`
+	LastTxHash    string    `json:"last_tx_hash,omitempty"`
+	LastError     string    `json:"last_error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Met reports whether price satisfies the trigger's condition
+func (t *PriceTrigger) Met(price float64) bool {
+	if t.Comparison == TriggerAbove {
+		return price >= t.Threshold
+	}
+	return price <= t.Threshold
+}
+
+// PriceTriggerExecutor runs the action of a trigger that fired and returns the
+// transaction hash
+type PriceTriggerExecutor func(ctx context.Context, trigger *PriceTrigger) (string, error)
+
+// PriceTriggerFiring is the outcome of one firing of a trigger
+type PriceTriggerFiring struct {
+	Trigger PriceTrigger // state after the firing
+	Price   float64      // price that met the condition
+	TxHash  string
+	Err     error
+}
+
+// PriceTriggerListener is notified after every firing
+type PriceTriggerListener func(firing PriceTriggerFiring)
+
+// PriceTriggerWatcher keeps price triggers in storage.NamespaceTriggers and
+// fires them as prices move. Like TransactionScheduler it persists a firing
+// before running the action, so an action interrupted by a restart is not
+// run twice.
+type PriceTriggerWatcher struct {
+	mu        sync.Mutex
+	evalMu    sync.Mutex // serializes Evaluate
+	triggers  map[string]*PriceTrigger
+	store     storage.StateStore
+	listeners []PriceTriggerListener
+	now       func() time.Time
+}
+
+// NewPriceTriggerWatcher creates a watcher persisting to store. A nil store
+// keeps triggers in memory.
+func NewPriceTriggerWatcher(store storage.StateStore) *PriceTriggerWatcher {
+	return &PriceTriggerWatcher{
+		triggers: make(map[string]*PriceTrigger),
+		store:    store,
+		now:      time.Now,
+	}
+}
+
+// Load restores persisted triggers. A firing interrupted by a restart counts
+// as failed and is not retried.
+func (w *PriceTriggerWatcher) Load(ctx context.Context) error {
+	if w.store == nil {
+		return nil
+	}
+	ids, err := w.store.List(ctx, storage.NamespaceTriggers)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, id := range ids {
+		data, err := w.store.Get(ctx, storage.NamespaceTriggers, id)
+		if err != nil {
+			return err
+		}
+		var trigger PriceTrigger
+		if err := json.Unmarshal(data, &trigger); err != nil {
+			return fmt.Errorf("failed to parse price trigger %s: %w", id, err)
+		}
+		if trigger.Status == TriggerStatusFiring {
+			trigger.LastError = "interrupted by a restart; the action was not retried to avoid running it twice"
+			finishFiring(&trigger, false)
+			if err := w.persistLocked(ctx, &trigger); err != nil {
+				return err
+			}
+		}
+		w.triggers[trigger.ID] = &trigger
+	}
+	return nil
+}
+
+// OnFire registers listener to be called after every firing
+func (w *PriceTriggerWatcher) OnFire(listener PriceTriggerListener) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.listeners = append(w.listeners, listener)
+}
+
+// Add validates and stores a new trigger and returns a copy with its id. The
+// trigger is armed, so it fires on the first evaluation if the condition
+// already holds.
+func (w *PriceTriggerWatcher) Add(ctx context.Context, trigger *PriceTrigger) (*PriceTrigger, error) {
+	if err := validatePriceTrigger(trigger); err != nil {
+		return nil, err
+	}
+	id, err := generatePriceTriggerID()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := *trigger
+	stored.ID = id
+	stored.Symbol = strings.ToUpper(strings.TrimSpace(trigger.Symbol))
+	stored.Action.Chain = NormalizeChain(trigger.Action.Chain)
+	stored.Fires = 0
+	stored.Armed = true
+	stored.Status = TriggerStatusActive
+	stored.CreatedAt = w.now()
+	stored.LastPrice, stored.LastCheckedAt, stored.LastFiredAt = 0, time.Time{}, time.Time{}
+	stored.LastTxHash, stored.LastError = "", ""
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.persistLocked(ctx, &stored); err != nil {
+		return nil, err
+	}
+	w.triggers[id] = &stored
+	result := stored
+	return &result, nil
+}
+
+func validatePriceTrigger(trigger *PriceTrigger) error {
+	if strings.TrimSpace(trigger.Symbol) == "" {
+		return errors.New("symbol is required")
+	}
+	if trigger.Comparison != TriggerBelow && trigger.Comparison != TriggerAbove {
+		return fmt.Errorf("comparison must be %s or %s", TriggerBelow, TriggerAbove)
+	}
+	if trigger.Threshold <= 0 {
+		return errors.New("threshold must be a positive USD price")
+	}
+	if trigger.MaxFires < 0 {
+		return errors.New("max_fires must not be negative")
+	}
+	if trigger.Cooldown < 0 {
+		return errors.New("cooldown must not be negative")
+	}
+
+	action := trigger.Action
+	if action.From == "" || action.Amount == "" {
+		return errors.New("from and amount are required")
+	}
+	if strings.EqualFold(strings.TrimSpace(action.Amount), "max") {
+		return errors.New("amount must be a fixed value; \"max\" cannot be used in a trigger")
+	}
+	switch action.Kind {
+	case TriggerActionSend:
+		if action.To == "" {
+			return errors.New("to is required for a send action")
+		}
+	case TriggerActionSwap:
+		if action.Token == "" || action.ToToken == "" {
+			return errors.New("token and to_token are required for a swap action")
+		}
+		if strings.EqualFold(action.Token, action.ToToken) {
+			return errors.New("token and to_token must differ")
+		}
+		if action.Slippage < 0 || action.Slippage > 0.5 {
+			return errors.New("slippage must be between 0 and 0.5")
+		}
+	default:
+		return fmt.Errorf("action must be %s or %s", TriggerActionSend, TriggerActionSwap)
+	}
+	return nil
+}
+
+// Get returns a copy of the trigger with id
+func (w *PriceTriggerWatcher) Get(id string) (*PriceTrigger, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	trigger, ok := w.triggers[id]
+	if !ok {
+		return nil, ErrPriceTriggerNotFound
+	}
+	result := *trigger
+	return &result, nil
+}
+
+// List returns copies of the triggers with status, or all of them when status
+// is empty, oldest first
+func (w *PriceTriggerWatcher) List(status string) []*PriceTrigger {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	result := make([]*PriceTrigger, 0, len(w.triggers))
+	for _, trigger := range w.triggers {
+		if status == "" || trigger.Status == status {
+			copied := *trigger
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// Cancel stops a trigger from firing again. An action already running is not
+// interrupted.
+func (w *PriceTriggerWatcher) Cancel(ctx context.Context, id string) (*PriceTrigger, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	trigger, ok := w.triggers[id]
+	if !ok {
+		return nil, ErrPriceTriggerNotFound
+	}
+	if trigger.Status != TriggerStatusActive && trigger.Status != TriggerStatusFiring {
+		return nil, fmt.Errorf("%w: status is %s", ErrPriceTriggerFinished, trigger.Status)
+	}
+	updated := *trigger
+	updated.Status = TriggerStatusCancelled
+	if err := w.persistLocked(ctx, &updated); err != nil {
+		return nil, err
+	}
+	*trigger = updated
+	result := updated
+	return &result, nil
+}
+
+// Evaluate prices every active trigger through feed and runs the action of
+// those whose condition is met. Each symbol is priced once per call; triggers
+// whose price cannot be read are left for the next evaluation. It returns how
+// many triggers fired.
+func (w *PriceTriggerWatcher) Evaluate(ctx context.Context, feed PriceFeed, execute PriceTriggerExecutor) int {
+	w.evalMu.Lock()
+	defer w.evalMu.Unlock()
+
+	prices := make(map[string]float64)
+	failed := make(map[string]bool)
+	fired := 0
+	for _, active := range w.List(TriggerStatusActive) {
+		price, ok := prices[active.Symbol]
+		if !ok && !failed[active.Symbol] {
+			p, err := feed.USDPrice(ctx, active.Symbol)
+			if err != nil {
+				failed[active.Symbol] = true
+			} else {
+				price, ok = p, true
+				prices[active.Symbol] = p
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		trigger, fire := w.observe(ctx, active.ID, price)
+		if !fire {
+			continue
+		}
+		fired++
+		txHash, err := execute(ctx, trigger)
+		w.completeFiring(ctx, trigger.ID, price, txHash, err)
+	}
+	return fired
+}
+
+// observe records price for a trigger, re-arming it when its condition no
+// longer holds, and marks it as firing when it should fire
+func (w *PriceTriggerWatcher) observe(ctx context.Context, id string, price float64) (*PriceTrigger, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	trigger, ok := w.triggers[id]
+	if !ok || trigger.Status != TriggerStatusActive {
+		return nil, false // cancelled meanwhile
+	}
+	now := w.now()
+	trigger.LastPrice = price
+	trigger.LastCheckedAt = now
+
+	if !trigger.Met(price) {
+		if !trigger.Armed {
+			trigger.Armed = true
+			_ = w.persistLocked(ctx, trigger)
+		}
+		return nil, false
+	}
+	if !trigger.Armed || (trigger.Cooldown > 0 && now.Sub(trigger.LastFiredAt) < trigger.Cooldown) {
+		return nil, false
+	}
+
+	updated := *trigger
+	updated.Status = TriggerStatusFiring
+	updated.Armed = false
+	updated.Fires++
+	updated.LastFiredAt = now
+	if err := w.persistLocked(ctx, &updated); err != nil {
+		return nil, false // retried on the next evaluation
+	}
+	*trigger = updated
+	result := updated
+	return &result, true
+}
+
+// completeFiring records the outcome of an action and notifies the listeners
+func (w *PriceTriggerWatcher) completeFiring(ctx context.Context, id string, price float64, txHash string, actionErr error) {
+	w.mu.Lock()
+	trigger := w.triggers[id]
+	trigger.LastTxHash = txHash
+	trigger.LastError = ""
+	if actionErr != nil {
+		trigger.LastError = actionErr.Error()
+	}
+	if trigger.Status == TriggerStatusFiring {
+		finishFiring(trigger, actionErr == nil)
+	}
+	_ = w.persistLocked(ctx, trigger)
+	firing := PriceTriggerFiring{Trigger: *trigger, Price: price, TxHash: txHash, Err: actionErr}
+	listeners := append([]PriceTriggerListener(nil), w.listeners...)
+	w.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(firing)
+	}
+}
+
+// finishFiring moves a firing trigger to its next state. A trigger that fires
+// once fails with its action; a repeating one stays active either way.
+func finishFiring(trigger *PriceTrigger, succeeded bool) {
+	switch {
+	case trigger.MaxFires == 1 && !succeeded:
+		trigger.Status = TriggerStatusFailed
+	case trigger.MaxFires > 0 && trigger.Fires >= trigger.MaxFires:
+		trigger.Status = TriggerStatusFired
+	default:
+		trigger.Status = TriggerStatusActive
+	}
+}
+
+func (w *PriceTriggerWatcher) persistLocked(ctx context.Context, trigger *PriceTrigger) error {
+	if w.store == nil {
+		return nil
+	}
+	data, err := json.Marshal(trigger)
+	if err != nil {
+		return fmt.Errorf("failed to marshal price trigger: %w", err)
+	}
+	if err := w.store.Put(ctx, storage.NamespaceTriggers, trigger.ID, data); err != nil {
+		return fmt.Errorf("failed to persist price trigger %s: %w", trigger.ID, err)
+	}
+	return nil
+}
+
+// generatePriceTriggerID generates a unique price trigger ID
+func generatePriceTriggerID() (string, error) {
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return "trig_" + hex.EncodeToString(bytes), nil
+}
+
+// CreatePriceTrigger validates a trigger and starts watching it
+func (wm *WalletManager) CreatePriceTrigger(ctx context.Context, trigger *PriceTrigger) (*PriceTrigger, error) {
+	if err := wm.requireUnlocked(); err != nil {
+		return nil, err
+	}
+	action := trigger.Action
+	chainName := NormalizeChain(action.Chain)
+	if !wm.isValidAddress(chainName, action.From) {
+		return nil, errors.New("security validation failed: invalid from address")
+	}
+	if action.Kind == TriggerActionSend {
+		if err := wm.validateTransactionSecurity(chainName, action.From, action.To, action.Amount, action.Token); err != nil {
+			return nil, fmt.Errorf("security validation failed: %w", err)
+		}
+	}
+	return wm.priceTriggers.Add(ctx, trigger)
+}
+
+// ListPriceTriggers returns the price triggers with status, or all of them
+// when status is empty, oldest first
+func (wm *WalletManager) ListPriceTriggers(ctx context.Context, status string) ([]*PriceTrigger, error) {
+	return wm.priceTriggers.List(status), nil
+}
+
+// CancelPriceTrigger stops the price trigger with id from firing again
+func (wm *WalletManager) CancelPriceTrigger(ctx context.Context, id string) (*PriceTrigger, error) {
+	return wm.priceTriggers.Cancel(ctx, id)
+}
+
+// OnPriceTriggerFired registers listener to be called after every firing of a
+// price trigger, e.g. to notify subscribers
+func (wm *WalletManager) OnPriceTriggerFired(listener PriceTriggerListener) {
+	wm.priceTriggers.OnFire(listener)
+}
+
+// RunPriceTriggers evaluates the price triggers against feed until ctx is
+// done, running the actions of those that fire through execute
+func (wm *WalletManager) RunPriceTriggers(ctx context.Context, feed PriceFeed, execute PriceTriggerExecutor) {
+	ticker := time.NewTicker(priceTriggerPollInterval)
+	defer ticker.Stop()
+	for {
+		if fired := wm.priceTriggers.Evaluate(ctx, feed, execute); fired > 0 {
+			wm.logger.Debug("Price triggers fired", zap.Int("triggers", fired))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
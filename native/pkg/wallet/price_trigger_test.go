@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+)
+
+// newTestTriggerWatcher returns a watcher on backing whose clock is *now
+func newTestTriggerWatcher(t *testing.T, backing storage.StateStore, now *time.Time) *PriceTriggerWatcher {
+	t.Helper()
+	w := NewPriceTriggerWatcher(backing)
+	w.now = func() time.Time { return *now }
+	if err := w.Load(context.Background()); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	return w
+}
+
+func newTestTrigger(comparison string, threshold float64, maxFires int) *PriceTrigger {
+	return &PriceTrigger{
+		Symbol:     "eth",
+		Comparison: comparison,
+		Threshold:  threshold,
+		MaxFires:   maxFires,
+		Action: PriceTriggerAction{
+			Kind:   TriggerActionSend,
+			Chain:  "ETH",
+			From:   "0xAAAAaaaaAAAAaaaaAAAAaaaaAAAAaaaaAAAAaaaa",
+			To:     "0x1111111111111111111111111111111111111111",
+			Amount: "0.1",
+		},
+	}
+}
+
+func TestPriceTriggerFiresOnce(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	w := newTestTriggerWatcher(t, storage.NewMemoryStateStore(), &now)
+
+	var firings []PriceTriggerFiring
+	w.OnFire(func(firing PriceTriggerFiring) { firings = append(firings, firing) })
+
+	trigger, err := w.Add(ctx, newTestTrigger(TriggerBelow, 2000, 1))
+	if err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if trigger.Symbol != "ETH" || trigger.Action.Chain != "ethereum" || trigger.Status != TriggerStatusActive {
+		t.Fatalf("unexpected trigger: %+v", trigger)
+	}
+
+	runs := 0
+	execute := func(_ context.Context, trigger *PriceTrigger) (string, error) {
+		runs++
+		if trigger.Status != TriggerStatusFiring {
+			t.Errorf("firing should be persisted before the action runs, got %s", trigger.Status)
+		}
+		return "0xabc", nil
+	}
+	feed := fixedPriceFeed{"ETH": 2100}
+	if fired := w.Evaluate(ctx, feed, execute); fired != 0 {
+		t.Fatalf("trigger fired above its threshold")
+	}
+
+	feed["ETH"] = 1990
+	if fired := w.Evaluate(ctx, feed, execute); fired != 1 || runs != 1 {
+		t.Fatalf("expected one firing, got %d firings and %d runs", fired, runs)
+	}
+	feed["ETH"] = 2100
+	w.Evaluate(ctx, feed, execute)
+	feed["ETH"] = 1900
+	if fired := w.Evaluate(ctx, feed, execute); fired != 0 {
+		t.Errorf("one-shot trigger fired again")
+	}
+
+	got, _ := w.Get(trigger.ID)
+	if got.Status != TriggerStatusFired || got.Fires != 1 || got.LastTxHash != "0xabc" {
+		t.Errorf("unexpected state after firing: %+v", got)
+	}
+	if len(firings) != 1 || firings[0].Price != 1990 || firings[0].Trigger.Status != TriggerStatusFired {
+		t.Errorf("listener not notified with the outcome: %+v", firings)
+	}
+}
+
+func TestRepeatingPriceTriggerRearms(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	w := newTestTriggerWatcher(t, nil, &now)
+
+	trigger := newTestTrigger(TriggerAbove, 3000, 3)
+	trigger.Cooldown = time.Hour
+	trigger, err := w.Add(ctx, trigger)
+	if err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	fail := true
+	execute := func(context.Context, *PriceTrigger) (string, error) {
+		if fail {
+			return "", errors.New("wallet is locked")
+		}
+		return "0xabc", nil
+	}
+	feed := fixedPriceFeed{"ETH": 3100}
+
+	// A failed action of a repeating trigger counts as a firing and keeps it active
+	w.Evaluate(ctx, feed, execute)
+	got, _ := w.Get(trigger.ID)
+	if got.Status != TriggerStatusActive || got.Fires != 1 || got.LastError != "wallet is locked" {
+		t.Fatalf("unexpected state after failed firing: %+v", got)
+	}
+
+	// The price staying above the threshold does not fire it again
+	fail = false
+	now = now.Add(2 * time.Hour)
+	if fired := w.Evaluate(ctx, feed, execute); fired != 0 {
+		t.Fatalf("trigger fired again without the condition clearing")
+	}
+
+	// Clearing re-arms it, but the cooldown still applies
+	feed["ETH"] = 2900
+	w.Evaluate(ctx, feed, execute)
+	feed["ETH"] = 3050
+	now = now.Add(30 * time.Minute)
+	if fired := w.Evaluate(ctx, feed, execute); fired != 1 {
+		t.Fatalf("re-armed trigger did not fire")
+	}
+	feed["ETH"] = 2900
+	w.Evaluate(ctx, feed, execute)
+	feed["ETH"] = 3050
+	now = now.Add(10 * time.Minute)
+	if fired := w.Evaluate(ctx, feed, execute); fired != 0 {
+		t.Fatalf("trigger fired within its cooldown")
+	}
+	now = now.Add(time.Hour)
+	w.Evaluate(ctx, feed, execute)
+
+	got, _ = w.Get(trigger.ID)
+	if got.Status != TriggerStatusFired || got.Fires != 3 || got.LastError != "" {
+		t.Errorf("trigger should finish after max_fires: %+v", got)
+	}
+}
+
+func TestPriceTriggerSkipsUnpricedSymbols(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	w := newTestTriggerWatcher(t, nil, &now)
+
+	trigger := newTestTrigger(TriggerBelow, 1, 1)
+	trigger.Symbol = "PEPE"
+	added, _ := w.Add(ctx, trigger)
+
+	fired := w.Evaluate(ctx, fixedPriceFeed{}, func(context.Context, *PriceTrigger) (string, error) { return "0xabc", nil })
+	got, _ := w.Get(added.ID)
+	if fired != 0 || got.Status != TriggerStatusActive || !got.LastCheckedAt.IsZero() {
+		t.Errorf("trigger without a price should wait: fired=%d %+v", fired, got)
+	}
+}
+
+func TestPriceTriggersSurviveRestart(t *testing.T) {
+	ctx := context.Background()
+	backing := storage.NewMemoryStateStore()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	w := newTestTriggerWatcher(t, backing, &now)
+
+	waiting, err := w.Add(ctx, newTestTrigger(TriggerBelow, 1000, 1))
+	if err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	interrupted, _ := w.Add(ctx, newTestTrigger(TriggerBelow, 3000, 1))
+
+	// Simulate a crash while the action was running
+	if _, fire := w.observe(ctx, interrupted.ID, 2000); !fire {
+		t.Fatalf("failed to start firing %s", interrupted.ID)
+	}
+
+	restarted := newTestTriggerWatcher(t, backing, &now)
+	if got, err := restarted.Get(waiting.ID); err != nil || got.Status != TriggerStatusActive || !got.Armed {
+		t.Errorf("waiting trigger not restored: %+v, %v", got, err)
+	}
+	if got, _ := restarted.Get(interrupted.ID); got.Status != TriggerStatusFailed || got.LastError == "" {
+		t.Errorf("interrupted action must not be retried: %+v", got)
+	}
+
+	runs := 0
+	restarted.Evaluate(ctx, fixedPriceFeed{"ETH": 2000}, func(context.Context, *PriceTrigger) (string, error) {
+		runs++
+		return "0xabc", nil
+	})
+	if runs != 0 {
+		t.Errorf("interrupted trigger fired again after restart")
+	}
+}
+
+func TestCancelPriceTrigger(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	w := newTestTriggerWatcher(t, nil, &now)
+
+	trigger, _ := w.Add(ctx, newTestTrigger(TriggerBelow, 2000, 0))
+	cancelled, err := w.Cancel(ctx, trigger.ID)
+	if err != nil || cancelled.Status != TriggerStatusCancelled {
+		t.Fatalf("cancel failed: %+v, %v", cancelled, err)
+	}
+	if fired := w.Evaluate(ctx, fixedPriceFeed{"ETH": 1000}, func(context.Context, *PriceTrigger) (string, error) { return "0xabc", nil }); fired != 0 {
+		t.Errorf("cancelled trigger fired")
+	}
+	if _, err := w.Cancel(ctx, trigger.ID); !errors.Is(err, ErrPriceTriggerFinished) {
+		t.Errorf("expected ErrPriceTriggerFinished, got %v", err)
+	}
+	if _, err := w.Cancel(ctx, "trig_missing"); !errors.Is(err, ErrPriceTriggerNotFound) {
+		t.Errorf("expected ErrPriceTriggerNotFound, got %v", err)
+	}
+}
+
+func TestPriceTriggerValidation(t *testing.T) {
+	w := NewPriceTriggerWatcher(nil)
+	swap := func(from, to string) PriceTriggerAction {
+		return PriceTriggerAction{Kind: TriggerActionSwap, Chain: "ethereum", From: "0xa", Amount: "1", Token: from, ToToken: to}
+	}
+	send := PriceTriggerAction{Kind: TriggerActionSend, Chain: "ethereum", From: "0xa", To: "0xb", Amount: "1"}
+	cases := map[string]*PriceTrigger{
+		"missing symbol":     {Comparison: TriggerBelow, Threshold: 1, Action: send},
+		"bad comparison":     {Symbol: "ETH", Comparison: "equals", Threshold: 1, Action: send},
+		"zero threshold":     {Symbol: "ETH", Comparison: TriggerBelow, Action: send},
+		"negative max fires": {Symbol: "ETH", Comparison: TriggerBelow, Threshold: 1, Action: send, MaxFires: -1},
+		"send without to":    {Symbol: "ETH", Comparison: TriggerBelow, Threshold: 1, Action: PriceTriggerAction{Kind: TriggerActionSend, From: "0xa", Amount: "1"}},
+		"max amount":         {Symbol: "ETH", Comparison: TriggerBelow, Threshold: 1, Action: PriceTriggerAction{Kind: TriggerActionSend, From: "0xa", To: "0xb", Amount: "max"}},
+		"swap to itself":     {Symbol: "ETH", Comparison: TriggerBelow, Threshold: 1, Action: swap("USDC", "usdc")},
+		"swap without token": {Symbol: "ETH", Comparison: TriggerBelow, Threshold: 1, Action: swap("", "ETH")},
+		"unknown action":     {Symbol: "ETH", Comparison: TriggerBelow, Threshold: 1, Action: PriceTriggerAction{Kind: "bridge", From: "0xa", Amount: "1"}},
+	}
+	for name, trigger := range cases {
+		if _, err := w.Add(context.Background(), trigger); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	if _, err := wm.CreatePriceTrigger(context.Background(), newTestTrigger(TriggerBelow, 2000, 1)); !errors.Is(err, ErrWalletLocked) {
+		t.Errorf("creating a trigger requires an unlocked wallet, got %v", err)
+	}
+}
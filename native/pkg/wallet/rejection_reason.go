@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RejectionReason is the standardized reason recorded when a pending transaction is rejected
+type RejectionReason string
+
+// Standardized rejection reasons shared by every rejection entrypoint
+const (
+	RejectionReasonSuspiciousActivity   RejectionReason = "suspicious_activity"
+	RejectionReasonHighGasFee           RejectionReason = "high_gas_fee"
+	RejectionReasonUserRequest          RejectionReason = "user_request"
+	RejectionReasonSecurityConcern      RejectionReason = "security_concern"
+	RejectionReasonDuplicateTransaction RejectionReason = "duplicate_transaction"
+	RejectionReasonOther                RejectionReason = "other" // requires details
+)
+
+// ValidRejectionReasons lists the accepted rejection reasons in display order
+var ValidRejectionReasons = []RejectionReason{
+	RejectionReasonSuspiciousActivity,
+	RejectionReasonHighGasFee,
+	RejectionReasonUserRequest,
+	RejectionReasonSecurityConcern,
+	RejectionReasonDuplicateTransaction,
+	RejectionReasonOther,
+}
+
+// ParseRejectionReason normalizes and validates a rejection reason.
+// The "other" reason is only accepted together with free-text details.
+func ParseRejectionReason(reason, details string) (RejectionReason, error) {
+	normalized := RejectionReason(strings.ToLower(strings.TrimSpace(reason)))
+	if normalized == "" {
+		return "", fmt.Errorf("rejection reason is required (valid: %s)", RejectionReasonList())
+	}
+
+	for _, valid := range ValidRejectionReasons {
+		if normalized == valid {
+			if normalized == RejectionReasonOther && strings.TrimSpace(details) == "" {
+				return "", fmt.Errorf("details are required when reason is %q", RejectionReasonOther)
+			}
+			return normalized, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid rejection reason %q (valid: %s)", reason, RejectionReasonList())
+}
+
+// RejectionReasonList returns the valid reasons as a comma-separated string
+func RejectionReasonList() string {
+	names := make([]string, len(ValidRejectionReasons))
+	for i, reason := range ValidRejectionReasons {
+		names[i] = string(reason)
+	}
+	return strings.Join(names, ", ")
+}
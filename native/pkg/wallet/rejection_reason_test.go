@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseRejectionReason(t *testing.T) {
+	tests := []struct {
+		name      string
+		reason    string
+		details   string
+		expected  RejectionReason
+		expectErr bool
+	}{
+		{name: "known reason", reason: "high_gas_fee", expected: RejectionReasonHighGasFee},
+		{name: "case and whitespace normalized", reason: "  Suspicious_Activity ", expected: RejectionReasonSuspiciousActivity},
+		{name: "other with details", reason: "other", details: "recipient looks like a phishing contract", expected: RejectionReasonOther},
+		{name: "other without details", reason: "other", expectErr: true},
+		{name: "free text reason", reason: "I don't like it", expectErr: true},
+		{name: "empty reason", reason: "", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRejectionReason(tt.reason, tt.details)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("ParseRejectionReason(%q) expected error, got %q", tt.reason, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRejectionReason(%q) unexpected error: %v", tt.reason, err)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseRejectionReason(%q) = %q, want %q", tt.reason, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRejectTransactionsValidatesReason(t *testing.T) {
+	wm := NewWalletManager()
+
+	if _, err := wm.RejectTransactions(context.Background(), []string{"0xabc"}, "because", "", false, true); err == nil {
+		t.Error("expected error for reason outside the taxonomy")
+	}
+
+	results, err := wm.RejectTransactions(context.Background(), []string{"0xabc"}, "USER_REQUEST", "", false, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+}
@@ -0,0 +1,410 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// Scheduled transaction statuses
+const (
+	ScheduleStatusScheduled = "scheduled"
+	ScheduleStatusExecuting = "executing"
+	ScheduleStatusCompleted = "completed"
+	ScheduleStatusFailed    = "failed"
+	ScheduleStatusCancelled = "cancelled"
+)
+
+// MinScheduleInterval is the shortest recurrence a scheduled transaction may use
+const MinScheduleInterval = time.Minute
+
+// scheduledPollInterval is how often RunScheduledTransactions looks for due transactions
+const scheduledPollInterval = 15 * time.Second
+
+var (
+	// ErrScheduledTransactionNotFound is returned for an unknown schedule id
+	ErrScheduledTransactionNotFound = errors.New("scheduled transaction not found")
+	// ErrScheduledTransactionFinished is returned when cancelling a schedule that already finished
+	ErrScheduledTransactionFinished = errors.New("scheduled transaction already finished")
+)
+
+// ScheduledTransaction is a transfer to send later, once or every Interval.
+// The confirmations are given when scheduling and are checked again against
+// the limits in force when each run executes.
+type ScheduledTransaction struct {
+	ID        string        `json:"id"`
+	Chain     string        `json:"chain"`
+	From      string        `json:"from"`
+	To        string        `json:"to"`
+	Amount    string        `json:"amount"`
+	Token     string        `json:"token,omitempty"`
+	ExecuteAt time.Time     `json:"execute_at"`         // next run
+	Interval  time.Duration `json:"interval,omitempty"` // 0 runs once
+	MaxRuns   int           `json:"max_runs,omitempty"` // 0 repeats until cancelled
+	Runs      int           `json:"runs"`
+	Status    string        `json:"status"`
+
+	ConfirmLarge             bool `json:"confirm_large,omitempty"`
+	ConfirmContractRecipient bool `json:"confirm_contract_recipient,omitempty"`
+
+	LastRunAt  time.Time `json:"last_run_at,omitempty"`
+	LastTxHash string    `json:"last_tx_hash,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Recurring reports whether the transaction runs more than once
+func (s *ScheduledTransaction) Recurring() bool {
+	return s.Interval > 0
+}
+
+// ScheduledSender sends one run of a scheduled transaction and returns its hash
+type ScheduledSender func(ctx context.Context, job *ScheduledTransaction) (string, error)
+
+// ScheduledExecution is the outcome of one run of a scheduled transaction
+type ScheduledExecution struct {
+	Job    ScheduledTransaction // state after the run
+	TxHash string
+	Err    error
+}
+
+// ScheduledExecutionListener is notified after every run of a scheduled transaction
+type ScheduledExecutionListener func(execution ScheduledExecution)
+
+// TransactionScheduler keeps scheduled transactions in storage.NamespaceScheduled
+// and runs them when due. A run is persisted as executing before it is sent, so
+// a run interrupted by a restart is never sent twice.
+type TransactionScheduler struct {
+	mu        sync.Mutex
+	runMu     sync.Mutex // serializes RunDue
+	jobs      map[string]*ScheduledTransaction
+	store     storage.StateStore
+	listeners []ScheduledExecutionListener
+	now       func() time.Time
+}
+
+// NewTransactionScheduler creates a scheduler persisting to store. A nil
+// store keeps schedules in memory.
+func NewTransactionScheduler(store storage.StateStore) *TransactionScheduler {
+	return &TransactionScheduler{
+		jobs:  make(map[string]*ScheduledTransaction),
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// Load restores persisted schedules. Runs that were executing when the host
+// stopped are not retried: one-off schedules are marked failed and recurring
+// ones move on to their next run.
+func (s *TransactionScheduler) Load(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+	ids, err := s.store.List(ctx, storage.NamespaceScheduled)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		data, err := s.store.Get(ctx, storage.NamespaceScheduled, id)
+		if err != nil {
+			return err
+		}
+		var job ScheduledTransaction
+		if err := json.Unmarshal(data, &job); err != nil {
+			return fmt.Errorf("failed to parse scheduled transaction %s: %w", id, err)
+		}
+		if job.Status == ScheduleStatusExecuting {
+			job.LastError = "interrupted by a restart; the run was not retried to avoid sending it twice"
+			s.finishRunLocked(&job, false)
+			if err := s.persistLocked(ctx, &job); err != nil {
+				return err
+			}
+		}
+		s.jobs[job.ID] = &job
+	}
+	return nil
+}
+
+// OnExecution registers listener to be called after every run
+func (s *TransactionScheduler) OnExecution(listener ScheduledExecutionListener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, listener)
+}
+
+// Add validates and stores a new schedule and returns a copy with its id
+func (s *TransactionScheduler) Add(ctx context.Context, job *ScheduledTransaction) (*ScheduledTransaction, error) {
+	if job.From == "" || job.To == "" || job.Amount == "" {
+		return nil, errors.New("from, to, and amount are required")
+	}
+	if strings.EqualFold(strings.TrimSpace(job.Amount), "max") {
+		return nil, errors.New("amount must be a fixed value; \"max\" cannot be scheduled")
+	}
+	if job.ExecuteAt.IsZero() {
+		return nil, errors.New("execute_at is required")
+	}
+	if job.Interval < 0 || (job.Interval > 0 && job.Interval < MinScheduleInterval) {
+		return nil, fmt.Errorf("interval must be at least %s", MinScheduleInterval)
+	}
+	if job.MaxRuns < 0 {
+		return nil, errors.New("max_runs must not be negative")
+	}
+
+	id, err := generateScheduleID()
+	if err != nil {
+		return nil, err
+	}
+	stored := *job
+	stored.ID = id
+	stored.Chain = NormalizeChain(job.Chain)
+	stored.Runs = 0
+	stored.Status = ScheduleStatusScheduled
+	stored.CreatedAt = s.now()
+	stored.LastRunAt, stored.LastTxHash, stored.LastError = time.Time{}, "", ""
+	if stored.Interval == 0 {
+		stored.MaxRuns = 1
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.persistLocked(ctx, &stored); err != nil {
+		return nil, err
+	}
+	s.jobs[id] = &stored
+	result := stored
+	return &result, nil
+}
+
+// Get returns a copy of the schedule with id
+func (s *TransactionScheduler) Get(id string) (*ScheduledTransaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrScheduledTransactionNotFound
+	}
+	result := *job
+	return &result, nil
+}
+
+// List returns copies of the schedules with status, or all of them when status
+// is empty, ordered by next run
+func (s *TransactionScheduler) List(status string) []*ScheduledTransaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]*ScheduledTransaction, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		if status == "" || job.Status == status {
+			copied := *job
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].ExecuteAt.Equal(result[j].ExecuteAt) {
+			return result[i].ExecuteAt.Before(result[j].ExecuteAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// Cancel stops a schedule from running again. A run already being sent is
+// not interrupted.
+func (s *TransactionScheduler) Cancel(ctx context.Context, id string) (*ScheduledTransaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrScheduledTransactionNotFound
+	}
+	if job.Status != ScheduleStatusScheduled && job.Status != ScheduleStatusExecuting {
+		return nil, fmt.Errorf("%w: status is %s", ErrScheduledTransactionFinished, job.Status)
+	}
+	updated := *job
+	updated.Status = ScheduleStatusCancelled
+	if err := s.persistLocked(ctx, &updated); err != nil {
+		return nil, err
+	}
+	*job = updated
+	result := updated
+	return &result, nil
+}
+
+// RunDue sends every schedule whose next run is due, oldest first, and
+// returns how many runs were attempted
+func (s *TransactionScheduler) RunDue(ctx context.Context, send ScheduledSender) int {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	now := s.now()
+	due := make([]*ScheduledTransaction, 0)
+	for _, job := range s.List(ScheduleStatusScheduled) {
+		if !job.ExecuteAt.After(now) {
+			due = append(due, job)
+		}
+	}
+
+	runs := 0
+	for _, pending := range due {
+		job, ok := s.startRun(ctx, pending.ID)
+		if !ok {
+			continue
+		}
+		runs++
+		txHash, err := send(ctx, job)
+		s.completeRun(ctx, job.ID, txHash, err)
+	}
+	return runs
+}
+
+// startRun marks a due schedule as executing and returns a copy to send
+func (s *TransactionScheduler) startRun(ctx context.Context, id string) (*ScheduledTransaction, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok || job.Status != ScheduleStatusScheduled {
+		return nil, false // cancelled meanwhile
+	}
+	updated := *job
+	updated.Status = ScheduleStatusExecuting
+	updated.Runs++
+	updated.LastRunAt = s.now()
+	if err := s.persistLocked(ctx, &updated); err != nil {
+		return nil, false // retried on the next poll
+	}
+	*job = updated
+	result := updated
+	return &result, true
+}
+
+// completeRun records the outcome of a run and notifies the listeners
+func (s *TransactionScheduler) completeRun(ctx context.Context, id, txHash string, sendErr error) {
+	s.mu.Lock()
+	job := s.jobs[id]
+	job.LastTxHash = txHash
+	job.LastError = ""
+	if sendErr != nil {
+		job.LastError = sendErr.Error()
+	}
+	if job.Status == ScheduleStatusExecuting {
+		s.finishRunLocked(job, sendErr == nil)
+	}
+	_ = s.persistLocked(ctx, job)
+	execution := ScheduledExecution{Job: *job, TxHash: txHash, Err: sendErr}
+	listeners := append([]ScheduledExecutionListener(nil), s.listeners...)
+	s.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(execution)
+	}
+}
+
+// finishRunLocked moves an executing schedule to its next state. Recurring
+// schedules skip runs missed while the host was down rather than catching up.
+func (s *TransactionScheduler) finishRunLocked(job *ScheduledTransaction, succeeded bool) {
+	if !job.Recurring() {
+		job.Status = ScheduleStatusFailed
+		if succeeded {
+			job.Status = ScheduleStatusCompleted
+		}
+		return
+	}
+	if job.MaxRuns > 0 && job.Runs >= job.MaxRuns {
+		job.Status = ScheduleStatusCompleted
+		return
+	}
+	now := s.now()
+	for !job.ExecuteAt.After(now) {
+		job.ExecuteAt = job.ExecuteAt.Add(job.Interval)
+	}
+	job.Status = ScheduleStatusScheduled
+}
+
+func (s *TransactionScheduler) persistLocked(ctx context.Context, job *ScheduledTransaction) error {
+	if s.store == nil {
+		return nil
+	}
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal scheduled transaction: %w", err)
+	}
+	if err := s.store.Put(ctx, storage.NamespaceScheduled, job.ID, data); err != nil {
+		return fmt.Errorf("failed to persist scheduled transaction %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+// generateScheduleID generates a unique scheduled transaction ID
+func generateScheduleID() (string, error) {
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return "sched_" + hex.EncodeToString(bytes), nil
+}
+
+// ScheduleTransaction validates a transfer and schedules it to be sent at
+// job.ExecuteAt, and every job.Interval after that when set
+func (wm *WalletManager) ScheduleTransaction(ctx context.Context, job *ScheduledTransaction) (*ScheduledTransaction, error) {
+	if err := wm.requireUnlocked(); err != nil {
+		return nil, err
+	}
+	if err := wm.validateTransactionSecurity(job.Chain, job.From, job.To, job.Amount, job.Token); err != nil {
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+	return wm.scheduler.Add(ctx, job)
+}
+
+// ListScheduledTransactions returns the scheduled transactions with status,
+// or all of them when status is empty, ordered by next run
+func (wm *WalletManager) ListScheduledTransactions(ctx context.Context, status string) ([]*ScheduledTransaction, error) {
+	return wm.scheduler.List(status), nil
+}
+
+// CancelScheduledTransaction stops the scheduled transaction with id from running again
+func (wm *WalletManager) CancelScheduledTransaction(ctx context.Context, id string) (*ScheduledTransaction, error) {
+	return wm.scheduler.Cancel(ctx, id)
+}
+
+// OnScheduledExecution registers listener to be called after every run of a
+// scheduled transaction, e.g. to notify subscribers
+func (wm *WalletManager) OnScheduledExecution(listener ScheduledExecutionListener) {
+	wm.scheduler.OnExecution(listener)
+}
+
+// RunScheduledTransactions sends due scheduled transactions through send until
+// ctx is done. A nil send uses SendTransaction without further checks.
+func (wm *WalletManager) RunScheduledTransactions(ctx context.Context, send ScheduledSender) {
+	if send == nil {
+		send = func(ctx context.Context, job *ScheduledTransaction) (string, error) {
+			return wm.SendTransaction(ctx, job.Chain, job.From, job.To, job.Amount, job.Token)
+		}
+	}
+
+	ticker := time.NewTicker(scheduledPollInterval)
+	defer ticker.Stop()
+	for {
+		if runs := wm.scheduler.RunDue(ctx, send); runs > 0 {
+			wm.logger.Debug("Scheduled transactions executed", zap.Int("runs", runs))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+)
+
+// newTestScheduler returns a scheduler on backing whose clock is *now
+func newTestScheduler(t *testing.T, backing storage.StateStore, now *time.Time) *TransactionScheduler {
+	t.Helper()
+	s := NewTransactionScheduler(backing)
+	s.now = func() time.Time { return *now }
+	if err := s.Load(context.Background()); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	return s
+}
+
+func newTestSchedule(at time.Time, interval time.Duration, maxRuns int) *ScheduledTransaction {
+	return &ScheduledTransaction{
+		Chain:     "ETH",
+		From:      "0xAAAAaaaaAAAAaaaaAAAAaaaaAAAAaaaaAAAAaaaa",
+		To:        "0x1111111111111111111111111111111111111111",
+		Amount:    "0.1",
+		ExecuteAt: at,
+		Interval:  interval,
+		MaxRuns:   maxRuns,
+	}
+}
+
+func TestScheduledTransactionRunsOnceWhenDue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestScheduler(t, storage.NewMemoryStateStore(), &now)
+
+	var executions []ScheduledExecution
+	s.OnExecution(func(execution ScheduledExecution) { executions = append(executions, execution) })
+
+	job, err := s.Add(ctx, newTestSchedule(now.Add(time.Hour), 0, 0))
+	if err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if job.Chain != "ethereum" || job.Status != ScheduleStatusScheduled {
+		t.Fatalf("unexpected schedule: %+v", job)
+	}
+
+	sends := 0
+	send := func(_ context.Context, job *ScheduledTransaction) (string, error) {
+		sends++
+		if job.Status != ScheduleStatusExecuting {
+			t.Errorf("run should be persisted as executing before it is sent, got %s", job.Status)
+		}
+		return "0xabc", nil
+	}
+	if runs := s.RunDue(ctx, send); runs != 0 {
+		t.Fatalf("schedule ran %d times before it was due", runs)
+	}
+
+	now = now.Add(time.Hour)
+	if runs := s.RunDue(ctx, send); runs != 1 || sends != 1 {
+		t.Fatalf("expected one run, got %d runs and %d sends", runs, sends)
+	}
+	if runs := s.RunDue(ctx, send); runs != 0 {
+		t.Errorf("one-off schedule ran again")
+	}
+
+	got, _ := s.Get(job.ID)
+	if got.Status != ScheduleStatusCompleted || got.Runs != 1 || got.LastTxHash != "0xabc" {
+		t.Errorf("unexpected state after run: %+v", got)
+	}
+	if len(executions) != 1 || executions[0].TxHash != "0xabc" || executions[0].Job.Status != ScheduleStatusCompleted {
+		t.Errorf("listener not notified with the outcome: %+v", executions)
+	}
+}
+
+func TestScheduledTransactionRecurrence(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestScheduler(t, nil, &now)
+
+	job, err := s.Add(ctx, newTestSchedule(now, 24*time.Hour, 3))
+	if err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	fail := true
+	send := func(context.Context, *ScheduledTransaction) (string, error) {
+		if fail {
+			return "", errors.New("wallet is locked")
+		}
+		return "0xabc", nil
+	}
+
+	// A failed run of a recurring schedule moves on to the next one
+	s.RunDue(ctx, send)
+	got, _ := s.Get(job.ID)
+	if got.Status != ScheduleStatusScheduled || got.Runs != 1 || got.LastError != "wallet is locked" {
+		t.Fatalf("unexpected state after failed run: %+v", got)
+	}
+	if want := now.Add(24 * time.Hour); !got.ExecuteAt.Equal(want) {
+		t.Errorf("next run = %s, want %s", got.ExecuteAt, want)
+	}
+
+	// Runs missed while the host was down are skipped, not caught up
+	fail = false
+	now = now.Add(50 * time.Hour)
+	if runs := s.RunDue(ctx, send); runs != 1 {
+		t.Fatalf("expected a single catch-up run, got %d", runs)
+	}
+	got, _ = s.Get(job.ID)
+	if want := time.Date(2026, 1, 4, 12, 0, 0, 0, time.UTC); !got.ExecuteAt.Equal(want) || got.LastError != "" {
+		t.Errorf("unexpected state after late run: %+v", got)
+	}
+
+	now = now.Add(24 * time.Hour)
+	s.RunDue(ctx, send)
+	got, _ = s.Get(job.ID)
+	if got.Status != ScheduleStatusCompleted || got.Runs != 3 {
+		t.Errorf("schedule should complete after max_runs: %+v", got)
+	}
+}
+
+func TestScheduledTransactionsSurviveRestart(t *testing.T) {
+	ctx := context.Background()
+	backing := storage.NewMemoryStateStore()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestScheduler(t, backing, &now)
+
+	later, err := s.Add(ctx, newTestSchedule(now.Add(time.Hour), 0, 0))
+	if err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	once, _ := s.Add(ctx, newTestSchedule(now, 0, 0))
+	daily, _ := s.Add(ctx, newTestSchedule(now, 24*time.Hour, 0))
+
+	// Simulate a crash while both due runs were being sent
+	for _, id := range []string{once.ID, daily.ID} {
+		if _, ok := s.startRun(ctx, id); !ok {
+			t.Fatalf("failed to start run of %s", id)
+		}
+	}
+
+	restarted := newTestScheduler(t, backing, &now)
+	if got, err := restarted.Get(later.ID); err != nil || got.Status != ScheduleStatusScheduled || !got.ExecuteAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("future schedule not restored: %+v, %v", got, err)
+	}
+	if got, _ := restarted.Get(once.ID); got.Status != ScheduleStatusFailed || got.LastError == "" {
+		t.Errorf("interrupted one-off run must not be retried: %+v", got)
+	}
+	if got, _ := restarted.Get(daily.ID); got.Status != ScheduleStatusScheduled || !got.ExecuteAt.After(now) {
+		t.Errorf("interrupted recurring run should move to its next run: %+v", got)
+	}
+
+	sends := 0
+	restarted.RunDue(ctx, func(context.Context, *ScheduledTransaction) (string, error) {
+		sends++
+		return "0xabc", nil
+	})
+	if sends != 0 {
+		t.Errorf("interrupted runs were sent again after restart")
+	}
+}
+
+func TestCancelScheduledTransaction(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestScheduler(t, nil, &now)
+
+	job, _ := s.Add(ctx, newTestSchedule(now, time.Hour, 0))
+	cancelled, err := s.Cancel(ctx, job.ID)
+	if err != nil || cancelled.Status != ScheduleStatusCancelled {
+		t.Fatalf("cancel failed: %+v, %v", cancelled, err)
+	}
+	if runs := s.RunDue(ctx, func(context.Context, *ScheduledTransaction) (string, error) { return "0xabc", nil }); runs != 0 {
+		t.Errorf("cancelled schedule ran")
+	}
+	if _, err := s.Cancel(ctx, job.ID); !errors.Is(err, ErrScheduledTransactionFinished) {
+		t.Errorf("expected ErrScheduledTransactionFinished, got %v", err)
+	}
+	if _, err := s.Cancel(ctx, "sched_missing"); !errors.Is(err, ErrScheduledTransactionNotFound) {
+		t.Errorf("expected ErrScheduledTransactionNotFound, got %v", err)
+	}
+	if jobs := s.List(ScheduleStatusCancelled); len(jobs) != 1 {
+		t.Errorf("expected the cancelled schedule to be listed, got %d", len(jobs))
+	}
+}
+
+func TestScheduleTransactionValidation(t *testing.T) {
+	now := time.Now()
+	s := NewTransactionScheduler(nil)
+	cases := map[string]*ScheduledTransaction{
+		"missing amount":     {Chain: "ethereum", From: "0xa", To: "0xb", ExecuteAt: now},
+		"max amount":         {Chain: "ethereum", From: "0xa", To: "0xb", Amount: "max", ExecuteAt: now},
+		"missing time":       {Chain: "ethereum", From: "0xa", To: "0xb", Amount: "1"},
+		"interval too short": {Chain: "ethereum", From: "0xa", To: "0xb", Amount: "1", ExecuteAt: now, Interval: time.Second},
+		"negative max runs":  {Chain: "ethereum", From: "0xa", To: "0xb", Amount: "1", ExecuteAt: now, Interval: time.Hour, MaxRuns: -1},
+	}
+	for name, job := range cases {
+		if _, err := s.Add(context.Background(), job); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	if _, err := wm.ScheduleTransaction(context.Background(), newTestSchedule(now.Add(time.Hour), 0, 0)); !errors.Is(err, ErrWalletLocked) {
+		t.Errorf("scheduling requires an unlocked wallet, got %v", err)
+	}
+}
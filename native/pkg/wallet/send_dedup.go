@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SendDigest identifies a send by the fields that make up its signed payload.
+// The nonce is assigned by the chain at broadcast, so two sends with the same
+// digest would sign the same transfer one nonce apart. Amounts are compared by
+// value ("0.10" equals "0.1") and EVM addresses case-insensitively.
+func SendDigest(chainName, from, to, amount, token string) string {
+	chainName = NormalizeChain(chainName)
+	if chainName != "solana" {
+		from, to, token = strings.ToLower(from), strings.ToLower(to), strings.ToLower(token)
+	}
+	if strings.EqualFold(token, NativeTokenSymbol(chainName)) {
+		token = ""
+	}
+	amount = strings.TrimSpace(amount)
+	if value, ok := new(big.Rat).SetString(amount); ok {
+		amount = value.RatString()
+	} else {
+		amount = strings.ToLower(amount)
+	}
+	payload := strings.Join([]string{chainName, strings.TrimSpace(from), strings.TrimSpace(to), amount, strings.TrimSpace(token)}, "\x00")
+	return common.BytesToHash(crypto.Keccak256([]byte(payload))).Hex()
+}
+
+// sendDeduplicator remembers sends for a short window so an identical send
+// made by accident returns the first result instead of broadcasting again.
+// An identical send arriving while the first is in flight waits for it.
+type sendDeduplicator struct {
+	window  time.Duration
+	mu      sync.Mutex
+	entries map[string]*sentTransaction
+	now     func() time.Time
+}
+
+// sentTransaction is the outcome of a send, available once done is closed
+type sentTransaction struct {
+	done   chan struct{}
+	txHash string
+	err    error
+	sentAt time.Time
+}
+
+// newSendDeduplicator creates a deduplicator; a zero window disables it
+func newSendDeduplicator(window time.Duration) *sendDeduplicator {
+	return &sendDeduplicator{
+		window:  window,
+		entries: make(map[string]*sentTransaction),
+		now:     time.Now,
+	}
+}
+
+// do runs send unless a send with the same digest succeeded within the window,
+// in which case its transaction hash is returned with duplicate=true. Failed
+// sends are forgotten so they can be retried.
+func (d *sendDeduplicator) do(digest string, send func() (string, error)) (txHash string, duplicate bool, err error) {
+	if d == nil || d.window <= 0 {
+		txHash, err = send()
+		return txHash, false, err
+	}
+
+	d.mu.Lock()
+	now := d.now()
+	for key, entry := range d.entries {
+		if entry.isDone() && now.Sub(entry.sentAt) > d.window {
+			delete(d.entries, key)
+		}
+	}
+	if entry, ok := d.entries[digest]; ok {
+		d.mu.Unlock()
+		<-entry.done
+		return entry.txHash, entry.err == nil, entry.err
+	}
+	entry := &sentTransaction{done: make(chan struct{})}
+	d.entries[digest] = entry
+	d.mu.Unlock()
+
+	entry.txHash, entry.err = send()
+
+	d.mu.Lock()
+	entry.sentAt = d.now()
+	if entry.err != nil {
+		delete(d.entries, digest)
+	}
+	d.mu.Unlock()
+	close(entry.done)
+	return entry.txHash, false, entry.err
+}
+
+func (e *sentTransaction) isDone() bool {
+	select {
+	case <-e.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// countingSendChain numbers each broadcast so repeated sends are distinguishable
+type countingSendChain struct {
+	*chain.SolanaChain
+	sends int32
+	fail  bool
+}
+
+func (c *countingSendChain) SendTransaction(ctx context.Context, from, to, amount, token, privateKey string) (string, error) {
+	n := atomic.AddInt32(&c.sends, 1)
+	if c.fail {
+		return "", fmt.Errorf("rpc unavailable")
+	}
+	return fmt.Sprintf("tx-%d", n), nil
+}
+
+func TestWalletManagerSendTransactionSuppressesDuplicates(t *testing.T) {
+	ctx := context.Background()
+	from := "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK"
+	to := "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY"
+
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	unlockForTest(wm, from)
+	sender := &countingSendChain{SolanaChain: chain.NewSolanaChainLegacy()}
+	wm.chainFactory.RegisterChain("solana", sender)
+	now := time.Now()
+	wm.sendDedup.now = func() time.Time { return now }
+
+	first, err := wm.SendTransaction(ctx, "solana", from, to, "0.1", "")
+	if err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	second, err := wm.SendTransaction(ctx, "sol", from, to, "0.10", "SOL")
+	if err != nil {
+		t.Fatalf("duplicate send failed: %v", err)
+	}
+	if first != second || atomic.LoadInt32(&sender.sends) != 1 {
+		t.Errorf("expected the duplicate to return %s without broadcasting, got %s after %d sends", first, second, sender.sends)
+	}
+
+	// A different amount is a different transaction
+	if third, _ := wm.SendTransaction(ctx, "solana", from, to, "0.2", ""); third == first {
+		t.Error("expected a different amount to be broadcast")
+	}
+
+	// Once the window has passed the same send goes out again
+	now = now.Add(time.Minute)
+	if again, _ := wm.SendTransaction(ctx, "solana", from, to, "0.1", ""); again == first {
+		t.Error("expected the send to be broadcast again after the window")
+	}
+	if sends := atomic.LoadInt32(&sender.sends); sends != 3 {
+		t.Errorf("expected 3 broadcasts, got %d", sends)
+	}
+}
+
+func TestSendDeduplicatorConcurrentAndFailedSends(t *testing.T) {
+	dedup := newSendDeduplicator(time.Minute)
+	release := make(chan struct{})
+	var sends int32
+	send := func() (string, error) {
+		atomic.AddInt32(&sends, 1)
+		<-release
+		return "0xabc", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, 3)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _, _ = dedup.do("digest", send)
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if sends != 1 || results[0] != "0xabc" || results[1] != "0xabc" || results[2] != "0xabc" {
+		t.Errorf("expected one broadcast shared by all callers, got %d sends and %v", sends, results)
+	}
+
+	// Failures are not remembered, so the send can be retried
+	if _, _, err := dedup.do("failing", func() (string, error) { return "", fmt.Errorf("boom") }); err == nil {
+		t.Fatal("expected the failure to be returned")
+	}
+	txHash, duplicate, err := dedup.do("failing", func() (string, error) { return "0xdef", nil })
+	if err != nil || duplicate || txHash != "0xdef" {
+		t.Errorf("expected a retry after failure to broadcast, got %s, %v, %v", txHash, duplicate, err)
+	}
+
+	// A zero window disables detection
+	disabled := newSendDeduplicator(0)
+	disabled.do("digest", func() (string, error) { return "1", nil })
+	if _, duplicate, _ := disabled.do("digest", func() (string, error) { return "2", nil }); duplicate {
+		t.Error("expected no duplicate detection with a zero window")
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// AuditActionPrivateKeyAccess is recorded every time a private key is handed
+// out for signing
+const AuditActionPrivateKeyAccess = "private_key_access"
+
+// ErrAddressNotInWallet is returned when a key is requested for an address the
+// unlocked wallet does not hold
+var ErrAddressNotInWallet = errors.New("address does not belong to the unlocked wallet")
+
+// GetPrivateKeyForAddress returns the decrypted private key of address, which
+// must be the selected wallet's address, one of its chain addresses or a
+// discovered account. It fails with ErrWalletLocked while the wallet is locked
+// and audits every key it returns.
+func (wm *WalletManager) GetPrivateKeyForAddress(ctx context.Context, address string) (string, error) {
+	return wm.signingKey(address, "sign a transaction")
+}
+
+// signingKey is the single audited lookup behind every key the wallet signs
+// with; purpose completes the audit entry "private key read to ..."
+func (wm *WalletManager) signingKey(address, purpose string) (string, error) {
+	if err := wm.requireUnlocked(); err != nil {
+		return "", err
+	}
+	privateKey, ok := wm.privateKeyFor(address)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrAddressNotInWallet, address)
+	}
+
+	if _, err := wm.auditLogger.LogSecurityEvent(AuditActionPrivateKeyAccess, "signing", "private key read to "+purpose, "system", address); err != nil {
+		wm.logger.Error("Failed to audit private key access", zap.Error(err))
+	}
+	wm.logger.Info("Private key accessed for signing", zap.String("address", address), zap.String("purpose", purpose))
+	return privateKey, nil
+}
+
+// ownerSigningKey is signingKey for operations on behalf of an owner, which
+// report a foreign address as ErrOwnerNotInWallet
+func (wm *WalletManager) ownerSigningKey(owner, purpose string) (string, error) {
+	privateKey, err := wm.signingKey(owner, purpose)
+	if errors.Is(err, ErrAddressNotInWallet) {
+		return "", fmt.Errorf("%w: %s", ErrOwnerNotInWallet, owner)
+	}
+	return privateKey, err
+}
+
+// privateKeyFor looks address up among the keys of the unlocked wallet
+func (wm *WalletManager) privateKeyFor(address string) (string, bool) {
+	wm.walletMu.RLock()
+	defer wm.walletMu.RUnlock()
+	data := wm.currentWalletData
+	if data == nil || address == "" {
+		return "", false
+	}
+	if sameAddress(data.Address, address) {
+		return data.PrivateKey, true
+	}
+	for _, chainData := range data.ChainData {
+		if sameAddress(chainData.Address, address) {
+			return chainData.PrivateKey, true
+		}
+	}
+	for _, account := range data.Accounts {
+		if sameAddress(account.Address, address) {
+			return account.PrivateKey, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+)
+
+func TestGetPrivateKeyForAddress(t *testing.T) {
+	ctx := context.Background()
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	address, _, _, err := wm.ImportWallet(ctx, testMnemonicA, "password123", "ethereum", "")
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+
+	privateKey, err := wm.GetPrivateKeyForAddress(ctx, strings.ToLower(address))
+	if err != nil {
+		t.Fatalf("expected the unlocked wallet to supply its key, got %v", err)
+	}
+	if privateKey == "" || privateKey != wm.currentWalletData.PrivateKey {
+		t.Errorf("unexpected private key %q", privateKey)
+	}
+	if entries := wm.auditLogger.GetAuditLogByAction(AuditActionPrivateKeyAccess); len(entries) != 1 || entries[0].WalletAddress != strings.ToLower(address) {
+		t.Errorf("expected one audited key access, got %+v", entries)
+	}
+
+	// Every other signing path goes through the same audited lookup
+	if _, err := wm.SignMessage(ctx, address, "hello"); err != nil {
+		t.Fatalf("sign message failed: %v", err)
+	}
+	entries := wm.auditLogger.GetAuditLogByAction(AuditActionPrivateKeyAccess)
+	if len(entries) != 2 || !strings.Contains(entries[1].Details, "sign a message") {
+		t.Errorf("expected the message signature to be audited, got %+v", entries)
+	}
+
+	if _, err := wm.GetPrivateKeyForAddress(ctx, "0x0000000000000000000000000000000000000001"); !errors.Is(err, ErrAddressNotInWallet) {
+		t.Errorf("expected ErrAddressNotInWallet, got %v", err)
+	}
+
+	wm.LockWallet()
+	if _, err := wm.GetPrivateKeyForAddress(ctx, address); !errors.Is(err, ErrWalletLocked) {
+		t.Errorf("expected ErrWalletLocked, got %v", err)
+	}
+	if entries := wm.auditLogger.GetAuditLogByAction(AuditActionPrivateKeyAccess); len(entries) != 2 {
+		t.Errorf("expected refused requests not to be audited as accesses, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,275 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"go.uber.org/zap"
+)
+
+// Kinds of operation a SigningPolicy is asked about
+const (
+	SigningKindSend        = "send"         // native and token sends, including approved dApp transactions
+	SigningKindMessage     = "message"      // personal_sign and other message signatures
+	SigningKindSwap        = "swap"         // DEX swaps
+	SigningKindNFTTransfer = "nft_transfer" // NFT transfers
+	SigningKindPermit      = "permit"       // off-chain token approvals (EIP-2612, EIP-3009)
+	SigningKindBundle      = "bundle"       // each transaction of a Solana bundle
+	SigningKindStake       = "stake"        // Solana stake delegation, deactivation and withdrawal
+	SigningKindWrap        = "wrap"         // wrapping and unwrapping the native coin (WETH, WSOL)
+)
+
+// AuditActionSigningDenied is recorded for every request a signing policy denies
+const AuditActionSigningDenied = "signing_denied"
+
+// ErrSigningDenied is matched by the error returned for a request a signing policy denies
+var ErrSigningDenied = errors.New("signing denied by policy")
+
+// SigningRequest is the normalized transaction a SigningPolicy decides on.
+// Fields that do not apply to Kind are empty.
+type SigningRequest struct {
+	Kind    string `json:"kind"`
+	Chain   string `json:"chain"`              // ethereum, bsc or solana
+	From    string `json:"from"`               // signing account
+	To      string `json:"to,omitempty"`       // recipient, spender, vote or stake account
+	Amount  string `json:"amount,omitempty"`   // in whole units; may be "max"
+	Token   string `json:"token,omitempty"`    // token contract, mint or symbol; empty for the native coin
+	ToToken string `json:"to_token,omitempty"` // token bought by a swap
+	TokenID string `json:"token_id,omitempty"` // NFT token ID
+	Message string `json:"message,omitempty"`  // message to be signed
+}
+
+// SigningDecision is a SigningPolicy's answer to a request
+type SigningDecision struct {
+	Allow  bool
+	Reason string // why the request was denied, reported to the caller
+}
+
+// SigningPolicy decides whether a transaction may be signed. Policies are
+// consulted just before signing, after the request has been validated, so an
+// implementation can call out to an external approval service. An error
+// denies the request.
+type SigningPolicy interface {
+	// Name identifies the policy in denials and the audit log
+	Name() string
+
+	// Evaluate decides on req
+	Evaluate(ctx context.Context, req SigningRequest) (SigningDecision, error)
+}
+
+// SigningDeniedError reports the policy that denied a request and its reason
+type SigningDeniedError struct {
+	Policy string
+	Reason string
+}
+
+func (e *SigningDeniedError) Error() string {
+	return fmt.Sprintf("signing denied by %s policy: %s", e.Policy, e.Reason)
+}
+
+// Is makes errors.Is(err, ErrSigningDenied) hold for every denial
+func (e *SigningDeniedError) Is(target error) bool {
+	return target == ErrSigningDenied
+}
+
+// AllowAllPolicy allows everything. It is used while no other policy is registered.
+type AllowAllPolicy struct{}
+
+// Name returns "allow_all"
+func (AllowAllPolicy) Name() string {
+	return "allow_all"
+}
+
+// Evaluate allows req
+func (AllowAllPolicy) Evaluate(ctx context.Context, req SigningRequest) (SigningDecision, error) {
+	return SigningDecision{Allow: true}, nil
+}
+
+// RuleSigningPolicy decides requests with the rules of security.signing_policy
+type RuleSigningPolicy struct {
+	config config.SigningPolicyConfig
+}
+
+// NewRuleSigningPolicy creates a policy from cfg, which must have been validated
+func NewRuleSigningPolicy(cfg config.SigningPolicyConfig) *RuleSigningPolicy {
+	return &RuleSigningPolicy{config: cfg}
+}
+
+// Name returns "rules"
+func (p *RuleSigningPolicy) Name() string {
+	return "rules"
+}
+
+// Evaluate applies the first rule matching req, or the default when none does
+func (p *RuleSigningPolicy) Evaluate(ctx context.Context, req SigningRequest) (SigningDecision, error) {
+	for _, rule := range p.config.Rules {
+		if !signingRuleMatches(rule, req) {
+			continue
+		}
+		if rule.Action == "allow" {
+			return SigningDecision{Allow: true}, nil
+		}
+		reason := rule.Reason
+		if reason == "" {
+			reason = "matched rule " + rule.Name
+		}
+		return SigningDecision{Reason: reason}, nil
+	}
+	if p.config.Default == "deny" {
+		return SigningDecision{Reason: "no rule allows this request"}, nil
+	}
+	return SigningDecision{Allow: true}, nil
+}
+
+// signingRuleMatches reports whether every condition set on rule holds for req
+func signingRuleMatches(rule config.SigningPolicyRule, req SigningRequest) bool {
+	if len(rule.Kinds) > 0 && !containsFold(rule.Kinds, req.Kind) {
+		return false
+	}
+	if len(rule.Chains) > 0 {
+		matched := false
+		for _, chain := range rule.Chains {
+			if NormalizeChain(chain) == req.Chain {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(rule.To) > 0 && !containsFold(rule.To, req.To) {
+		return false
+	}
+	if len(rule.Tokens) > 0 {
+		token := req.Token
+		if isNativeSymbol(req.Chain, token) {
+			token = "native"
+		}
+		if !containsFold(rule.Tokens, token) {
+			return false
+		}
+	}
+	if rule.AmountAbove > 0 && !amountAbove(req.Amount, rule.AmountAbove) {
+		return false
+	}
+	return true
+}
+
+// isNativeSymbol reports whether token names the native coin of chainName
+func isNativeSymbol(chainName, token string) bool {
+	switch strings.ToUpper(strings.TrimSpace(token)) {
+	case "", "NATIVE":
+		return true
+	case "ETH":
+		return chainName == "ethereum"
+	case "BNB":
+		return chainName == "bsc"
+	case "SOL":
+		return chainName == "solana"
+	default:
+		return false
+	}
+}
+
+// amountAbove reports whether amount exceeds limit. Requests without an amount
+// never do, while amounts that cannot be compared, such as "max", are assumed to.
+func amountAbove(amount string, limit float64) bool {
+	amount = strings.TrimSpace(amount)
+	if amount == "" {
+		return false
+	}
+	value, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return true
+	}
+	// Parsed from the shortest decimal form so 0.1 means exactly 0.1
+	bound, _ := new(big.Rat).SetString(strconv.FormatFloat(limit, 'f', -1, 64))
+	return value.Cmp(bound) > 0
+}
+
+// RegisterSigningPolicy adds policy to the policies consulted before signing.
+// Policies are consulted in registration order and the first denial wins.
+// Register policies at startup, before the wallet is used.
+func (wm *WalletManager) RegisterSigningPolicy(policy SigningPolicy) {
+	wm.signingMu.Lock()
+	defer wm.signingMu.Unlock()
+	wm.signingPolicies = append(wm.signingPolicies, policy)
+}
+
+// AuthorizeSigning consults the registered signing policies, or AllowAllPolicy
+// when there are none, about req. A denial is audited and returned as a
+// *SigningDeniedError.
+func (wm *WalletManager) AuthorizeSigning(ctx context.Context, req SigningRequest) error {
+	req.Chain = NormalizeChain(req.Chain)
+	if denial := wm.evaluateSigning(ctx, req); denial != nil {
+		wm.auditSigningDenial(req, denial)
+		return denial
+	}
+	return nil
+}
+
+// CheckSigningPolicy reports whether the signing policies would allow req,
+// like AuthorizeSigning but without auditing a denial. Dry runs use it.
+func (wm *WalletManager) CheckSigningPolicy(ctx context.Context, req SigningRequest) error {
+	req.Chain = NormalizeChain(req.Chain)
+	if denial := wm.evaluateSigning(ctx, req); denial != nil {
+		return denial
+	}
+	return nil
+}
+
+// evaluateSigning returns the first policy denial of req, or nil
+func (wm *WalletManager) evaluateSigning(ctx context.Context, req SigningRequest) *SigningDeniedError {
+	wm.signingMu.RLock()
+	policies := append([]SigningPolicy(nil), wm.signingPolicies...)
+	wm.signingMu.RUnlock()
+	if len(policies) == 0 {
+		policies = []SigningPolicy{AllowAllPolicy{}}
+	}
+
+	for _, policy := range policies {
+		decision, err := policy.Evaluate(ctx, req)
+		if err != nil {
+			decision = SigningDecision{Reason: fmt.Sprintf("policy could not be evaluated: %v", err)}
+		}
+		if decision.Allow {
+			continue
+		}
+		if decision.Reason == "" {
+			decision.Reason = "denied without a reason"
+		}
+		return &SigningDeniedError{Policy: policy.Name(), Reason: decision.Reason}
+	}
+	return nil
+}
+
+// auditSigningDenial records a denied request in the audit log
+func (wm *WalletManager) auditSigningDenial(req SigningRequest, denial *SigningDeniedError) {
+	details := fmt.Sprintf("%s on %s denied by the %s policy", req.Kind, req.Chain, denial.Policy)
+	if req.Amount != "" {
+		token := req.Token
+		if token == "" {
+			token = "native"
+		}
+		details += fmt.Sprintf(": %s %s", req.Amount, token)
+	}
+	if req.To != "" {
+		details += " to " + req.To
+	}
+	if _, err := wm.auditLogger.LogSecurityEvent(AuditActionSigningDenied, denial.Reason, details, "system", req.From); err != nil {
+		wm.logger.Error("Failed to audit signing denial", zap.Error(err))
+	}
+	wm.logger.Warn("Signing denied by policy",
+		zap.String("kind", req.Kind),
+		zap.String("chain", req.Chain),
+		zap.String("from", req.From),
+		zap.String("policy", denial.Policy),
+		zap.String("reason", denial.Reason))
+}
@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// approvalServicePolicy stands in for a policy calling an external approval service
+type approvalServicePolicy struct {
+	err      error
+	requests []SigningRequest
+}
+
+func (p *approvalServicePolicy) Name() string {
+	return "approval_service"
+}
+
+func (p *approvalServicePolicy) Evaluate(ctx context.Context, req SigningRequest) (SigningDecision, error) {
+	p.requests = append(p.requests, req)
+	if p.err != nil {
+		return SigningDecision{}, p.err
+	}
+	return SigningDecision{Allow: true}, nil
+}
+
+func TestRuleSigningPolicy(t *testing.T) {
+	policy := NewRuleSigningPolicy(config.SigningPolicyConfig{
+		Default: "allow",
+		Rules: []config.SigningPolicyRule{
+			{Name: "treasury", Action: "allow", To: []string{"0x3333333333333333333333333333333333333333"}},
+			{Name: "cap-eth", Action: "deny", Reason: "sends above 5 ETH need the treasury wallet", Kinds: []string{"send"}, Chains: []string{"eth"}, Tokens: []string{"native"}, AmountAbove: 5},
+			{Name: "no-messages", Action: "deny", Kinds: []string{"message"}},
+		},
+	})
+	send := SigningRequest{Kind: SigningKindSend, Chain: "ethereum", From: "0x1111111111111111111111111111111111111111", To: "0x2222222222222222222222222222222222222222"}
+
+	for _, tc := range []struct {
+		name   string
+		req    func(SigningRequest) SigningRequest
+		allow  bool
+		reason string
+	}{
+		{"small send", func(r SigningRequest) SigningRequest { r.Amount = "5"; return r }, true, ""},
+		{"large send", func(r SigningRequest) SigningRequest { r.Amount = "5.01"; r.Token = "ETH"; return r }, false, "sends above 5 ETH need the treasury wallet"},
+		{"max send", func(r SigningRequest) SigningRequest { r.Amount = "max"; return r }, false, "sends above 5 ETH need the treasury wallet"},
+		{"large token send", func(r SigningRequest) SigningRequest {
+			r.Amount = "100"
+			r.Token = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+			return r
+		}, true, ""},
+		{"large send on bsc", func(r SigningRequest) SigningRequest { r.Amount = "100"; r.Chain = "bsc"; return r }, true, ""},
+		{"large send to treasury", func(r SigningRequest) SigningRequest {
+			r.Amount = "100"
+			r.To = "0x3333333333333333333333333333333333333333"
+			return r
+		}, true, ""},
+		{"message", func(r SigningRequest) SigningRequest { r.Kind = SigningKindMessage; r.To = ""; return r }, false, "matched rule no-messages"},
+	} {
+		decision, err := policy.Evaluate(context.Background(), tc.req(send))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if decision.Allow != tc.allow || decision.Reason != tc.reason {
+			t.Errorf("%s: expected allow=%v reason %q, got %+v", tc.name, tc.allow, tc.reason, decision)
+		}
+	}
+
+	strict := NewRuleSigningPolicy(config.SigningPolicyConfig{Default: "deny"})
+	if decision, _ := strict.Evaluate(context.Background(), send); decision.Allow {
+		t.Error("expected a deny default to deny requests no rule matches")
+	}
+}
+
+func TestWalletManagerAuthorizeSigning(t *testing.T) {
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	req := SigningRequest{Kind: SigningKindSend, Chain: "ETH", From: "0x1111111111111111111111111111111111111111", To: "0x2222222222222222222222222222222222222222", Amount: "1"}
+
+	if err := wm.AuthorizeSigning(context.Background(), req); err != nil {
+		t.Fatalf("expected everything to be allowed without a policy, got %v", err)
+	}
+
+	service := &approvalServicePolicy{}
+	wm.RegisterSigningPolicy(service)
+	if err := wm.AuthorizeSigning(context.Background(), req); err != nil {
+		t.Fatalf("expected the approval service to allow the request, got %v", err)
+	}
+	if len(service.requests) != 1 || service.requests[0].Chain != "ethereum" {
+		t.Errorf("expected the policy to see the normalized request, got %+v", service.requests)
+	}
+
+	// A policy that cannot decide denies
+	service.err = errors.New("approval service unreachable")
+	err := wm.AuthorizeSigning(context.Background(), req)
+	var denial *SigningDeniedError
+	if !errors.As(err, &denial) || !errors.Is(err, ErrSigningDenied) {
+		t.Fatalf("expected a signing denial, got %v", err)
+	}
+	if denial.Policy != "approval_service" || !strings.Contains(denial.Reason, "approval service unreachable") {
+		t.Errorf("unexpected denial %+v", denial)
+	}
+
+	entries := wm.auditLogger.GetAuditLogByAction(AuditActionSigningDenied)
+	if len(entries) != 1 {
+		t.Fatalf("expected the denial to be audited once, got %+v", entries)
+	}
+	if entries[0].WalletAddress != req.From || !strings.Contains(entries[0].Details, "send on ethereum denied by the approval_service policy") {
+		t.Errorf("unexpected audit entry %+v", entries[0])
+	}
+
+	// Checking the policies for a dry run does not audit the denial
+	if err := wm.CheckSigningPolicy(context.Background(), req); !errors.Is(err, ErrSigningDenied) {
+		t.Fatalf("expected the check to report the denial, got %v", err)
+	}
+	if entries := wm.auditLogger.GetAuditLogByAction(AuditActionSigningDenied); len(entries) != 1 {
+		t.Errorf("expected only the authorization to be audited, got %d entries", len(entries))
+	}
+}
+
+func TestWalletManagerSendTransactionDeniedByPolicy(t *testing.T) {
+	from := "0x1111111111111111111111111111111111111111"
+	to := "0x2222222222222222222222222222222222222222"
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	unlockForTest(wm, from)
+	simulated := &simulatedChain{ETHChain: chain.NewETHChainLegacy()}
+	wm.chainFactory.RegisterChain("ethereum", simulated)
+	wm.RegisterSigningPolicy(NewRuleSigningPolicy(config.SigningPolicyConfig{
+		Rules: []config.SigningPolicyRule{{Name: "cap", Action: "deny", Reason: "too large", AmountAbove: 1}},
+	}))
+
+	_, err := wm.SendTransaction(context.Background(), "ethereum", from, to, "2", "")
+	if !errors.Is(err, ErrSigningDenied) || !strings.Contains(err.Error(), "too large") {
+		t.Fatalf("expected the policy to deny the send, got %v", err)
+	}
+	if simulated.sends != 0 {
+		t.Error("a denied send must not be broadcast")
+	}
+
+	if _, err := wm.SendTransaction(context.Background(), "ethereum", from, to, "0.5", ""); err != nil {
+		t.Fatalf("expected a send below the cap to go through, got %v", err)
+	}
+}
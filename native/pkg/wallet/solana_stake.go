@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+// DelegateStake stakes amount SOL ("max" for everything above the reserve)
+// from owner with the validator behind voteAccount, through a new stake
+// account that owner controls
+func (wm *WalletManager) DelegateStake(ctx context.Context, owner, voteAccount, amount string) (*chain.StakeResult, error) {
+	stakeManager, privateKey, err := wm.solanaStakeManager(owner)
+	if err != nil {
+		return nil, err
+	}
+	if err := wm.validateTransactionSecurity("solana", owner, voteAccount, amount, "SOL"); err != nil {
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+	if err := wm.AuthorizeSigning(ctx, SigningRequest{Kind: SigningKindStake, Chain: "solana", From: owner, To: voteAccount, Amount: amount}); err != nil {
+		return nil, err
+	}
+	return stakeManager.DelegateStake(ctx, voteAccount, amount, privateKey)
+}
+
+// DeactivateStake deactivates stakeAccount, which owner must be the staker of
+func (wm *WalletManager) DeactivateStake(ctx context.Context, owner, stakeAccount string) (*chain.StakeResult, error) {
+	stakeManager, privateKey, err := wm.solanaStakeManager(owner)
+	if err != nil {
+		return nil, err
+	}
+	if !wm.isValidAddress("solana", stakeAccount) {
+		return nil, errors.New("invalid stake account address")
+	}
+	if err := wm.AuthorizeSigning(ctx, SigningRequest{Kind: SigningKindStake, Chain: "solana", From: owner, To: stakeAccount}); err != nil {
+		return nil, err
+	}
+	return stakeManager.DeactivateStake(ctx, stakeAccount, privateKey)
+}
+
+// WithdrawStake withdraws amount SOL ("max" for everything) from the inactive
+// stakeAccount to to, or back to owner when to is empty. Any other recipient
+// passes the same checks as the recipient of a send.
+func (wm *WalletManager) WithdrawStake(ctx context.Context, owner, stakeAccount, to, amount string) (*chain.StakeResult, error) {
+	stakeManager, privateKey, err := wm.solanaStakeManager(owner)
+	if err != nil {
+		return nil, err
+	}
+	if !wm.isValidAddress("solana", stakeAccount) {
+		return nil, errors.New("invalid stake account address")
+	}
+	// Only SOL leaving the wallet counts against the spend limits
+	var reservation *SpendReservation
+	if to != "" && to != owner {
+		if err := wm.validateTransfer("solana", owner, to, amount); err != nil {
+			return nil, fmt.Errorf("security validation failed: %w", err)
+		}
+		if reservation, err = wm.ReserveSpend("solana", owner, to, amount, "SOL"); err != nil {
+			return nil, fmt.Errorf("security validation failed: %w", err)
+		}
+		defer reservation.Release()
+	}
+	if err := wm.AuthorizeSigning(ctx, SigningRequest{Kind: SigningKindStake, Chain: "solana", From: owner, To: stakeAccount, Amount: amount}); err != nil {
+		return nil, err
+	}
+	result, err := stakeManager.WithdrawStake(ctx, stakeAccount, to, amount, privateKey)
+	if err == nil {
+		reservation.Commit(result.Signature)
+	}
+	return result, err
+}
+
+// solanaStakeManager returns the Solana chain's staking support and the key of owner
+func (wm *WalletManager) solanaStakeManager(owner string) (chain.StakeManager, string, error) {
+	if err := wm.requireUnlocked(); err != nil {
+		return nil, "", err
+	}
+	privateKey, err := wm.ownerSigningKey(owner, "sign a staking transaction")
+	if err != nil {
+		return nil, "", err
+	}
+	chainImpl, err := wm.chainFactory.GetChain("solana")
+	if err != nil {
+		return nil, "", err
+	}
+	stakeManager, ok := chainImpl.(chain.StakeManager)
+	if !ok {
+		return nil, "", errors.New("staking is not supported on solana in this build")
+	}
+	return stakeManager, privateKey, nil
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/stretchr/testify/require"
+)
+
+// stakingChain records the staking calls it receives
+type stakingChain struct {
+	*chain.SolanaChain
+	calls []string
+	keys  []string
+}
+
+func (c *stakingChain) DelegateStake(_ context.Context, voteAccount, amount, privateKey string) (*chain.StakeResult, error) {
+	c.calls = append(c.calls, "delegate "+voteAccount+" "+amount)
+	c.keys = append(c.keys, privateKey)
+	return &chain.StakeResult{Signature: "delegated", VoteAccount: voteAccount}, nil
+}
+
+func (c *stakingChain) DeactivateStake(_ context.Context, stakeAccount, privateKey string) (*chain.StakeResult, error) {
+	c.calls = append(c.calls, "deactivate "+stakeAccount)
+	c.keys = append(c.keys, privateKey)
+	return &chain.StakeResult{Signature: "deactivated", StakeAccount: stakeAccount}, nil
+}
+
+func (c *stakingChain) WithdrawStake(_ context.Context, stakeAccount, to, amount, privateKey string) (*chain.StakeResult, error) {
+	c.calls = append(c.calls, "withdraw "+stakeAccount+" "+to+" "+amount)
+	c.keys = append(c.keys, privateKey)
+	return &chain.StakeResult{Signature: "withdrawn", StakeAccount: stakeAccount}, nil
+}
+
+func TestWalletManagerStaking(t *testing.T) {
+	owner := "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK"
+	vote := "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY"
+	stakeAccount := "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+	ctx := context.Background()
+
+	wm := NewWalletManager()
+	_, err := wm.DelegateStake(ctx, owner, vote, "1")
+	require.ErrorIs(t, err, ErrWalletLocked)
+
+	unlockForTest(wm, owner)
+	wm.currentWalletData.PrivateKey = "owner-key"
+	staking := &stakingChain{SolanaChain: chain.NewSolanaChainLegacy()}
+	wm.chainFactory.RegisterChain("solana", staking)
+
+	result, err := wm.DelegateStake(ctx, owner, vote, "1.5")
+	require.NoError(t, err)
+	require.Equal(t, "delegated", result.Signature)
+
+	_, err = wm.DeactivateStake(ctx, owner, stakeAccount)
+	require.NoError(t, err)
+
+	_, err = wm.WithdrawStake(ctx, owner, stakeAccount, "", "max")
+	require.NoError(t, err)
+	_, err = wm.WithdrawStake(ctx, owner, stakeAccount, vote, "0.5")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"delegate " + vote + " 1.5",
+		"deactivate " + stakeAccount,
+		"withdraw " + stakeAccount + "  max",
+		"withdraw " + stakeAccount + " " + vote + " 0.5",
+	}, staking.calls)
+	require.Equal(t, []string{"owner-key", "owner-key", "owner-key", "owner-key"}, staking.keys)
+
+	// Rejected before reaching the chain
+	_, err = wm.DelegateStake(ctx, vote, owner, "1")
+	require.ErrorIs(t, err, ErrOwnerNotInWallet)
+	_, err = wm.DelegateStake(ctx, owner, "0x1111111111111111111111111111111111111111", "1")
+	require.ErrorContains(t, err, "security validation failed")
+	_, err = wm.DeactivateStake(ctx, owner, "not-an-account")
+	require.ErrorContains(t, err, "invalid stake account address")
+	_, err = wm.WithdrawStake(ctx, owner, stakeAccount, "0x1111111111111111111111111111111111111111", "1")
+	require.ErrorContains(t, err, "security validation failed")
+	require.Len(t, staking.calls, 4)
+}
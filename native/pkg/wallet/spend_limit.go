@@ -0,0 +1,366 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"go.uber.org/zap"
+)
+
+// Audit actions recorded for spend limits
+const (
+	AuditActionSpend              = "spend"
+	AuditActionSpendLimitExceeded = "spend_limit_exceeded"
+)
+
+// Caps a SpendLimitExceededError can report
+const (
+	SpendLimitMaxTxValue = "max_tx_value"
+	SpendLimitDaily      = "daily_limit"
+)
+
+// spendWindow is how far back daily limits count outgoing transactions
+const spendWindow = 24 * time.Hour
+
+// ErrSpendLimitExceeded is matched by the error returned for a transaction above a spend limit
+var ErrSpendLimitExceeded = errors.New("spend limit exceeded")
+
+// SpendLimitExceededError reports a transaction refused by security.spend_limits
+type SpendLimitExceededError struct {
+	Chain  string
+	From   string
+	To     string
+	Amount string
+	Token  string  // as requested; empty for the native coin
+	Limit  string  // SpendLimitMaxTxValue or SpendLimitDaily
+	Max    float64 // the configured cap
+	Spent  string  // sent or being sent over the last 24 hours before this transaction, for daily limits
+}
+
+func (e *SpendLimitExceededError) Error() string {
+	name := spendTokenName(e.Chain, e.Token)
+	if e.Limit == SpendLimitDaily {
+		return fmt.Sprintf("spend limit exceeded: sending %s %s on %s would exceed the daily limit of %s %s (%s already sent in the last 24 hours)",
+			e.Amount, name, e.Chain, formatLimit(e.Max), name, e.Spent)
+	}
+	return fmt.Sprintf("spend limit exceeded: %s %s on %s is above the per-transaction limit of %s %s",
+		e.Amount, name, e.Chain, formatLimit(e.Max), name)
+}
+
+// Is makes errors.Is(err, ErrSpendLimitExceeded) hold for every refusal
+func (e *SpendLimitExceededError) Is(target error) bool {
+	return target == ErrSpendLimitExceeded
+}
+
+// SpendLimitListener is notified after a transaction is refused by a spend limit
+type SpendLimitListener func(exceeded SpendLimitExceededError)
+
+// OnSpendLimitExceeded registers listener to be called for every transaction
+// refused by a spend limit, e.g. to notify subscribers
+func (wm *WalletManager) OnSpendLimitExceeded(listener SpendLimitListener) {
+	wm.spendMu.Lock()
+	defer wm.spendMu.Unlock()
+	wm.spendLimitListeners = append(wm.spendLimitListeners, listener)
+}
+
+// setSpendLimits replaces the spend limits, keyed by chain name or alias
+func (wm *WalletManager) setSpendLimits(limits map[string]config.ChainSpendLimits) {
+	normalized := make(map[string]config.ChainSpendLimits, len(limits))
+	for chainName, chainLimits := range limits {
+		normalized[NormalizeChain(chainName)] = chainLimits
+	}
+	wm.spendMu.Lock()
+	defer wm.spendMu.Unlock()
+	wm.spendLimits = normalized
+}
+
+// checkSpendLimit refuses a transaction above the cap configured for its
+// token, or one that would take the token's total sent over the last 24
+// hours, together with the amounts reserved by sends in flight, above its
+// daily limit. Amounts that cannot be compared, such as "max", are refused
+// whenever a cap applies. Refusals are audited and reported to the spend
+// limit listeners. Nothing is held: see ReserveSpend.
+func (wm *WalletManager) checkSpendLimit(chainName, from, to, amount, token string) error {
+	key := spendTokenKey(chainName, token)
+	limit, ok := wm.spendLimitFor(chainName, key)
+	if !ok {
+		return nil
+	}
+
+	wm.spendReserved.mu.Lock()
+	exceeded := wm.exceededSpendLimit(limit, chainName, key, from, to, amount, token)
+	wm.spendReserved.mu.Unlock()
+	if exceeded != nil {
+		wm.refuseSpend(exceeded)
+		return exceeded
+	}
+	return nil
+}
+
+// CheckSpendLimit reports whether a send would exceed the spend limits,
+// counting the amounts reserved by sends in flight, without reserving it or
+// recording a refusal. Dry runs use it to predict ReserveSpend.
+func (wm *WalletManager) CheckSpendLimit(chainName, from, to, amount, token string) error {
+	chainName = NormalizeChain(chainName)
+	key := spendTokenKey(chainName, token)
+	limit, ok := wm.spendLimitFor(chainName, key)
+	if !ok {
+		return nil
+	}
+
+	wm.spendReserved.mu.Lock()
+	defer wm.spendReserved.mu.Unlock()
+	if exceeded := wm.exceededSpendLimit(limit, chainName, key, from, to, amount, token); exceeded != nil {
+		return exceeded
+	}
+	return nil
+}
+
+// spendReservations are the amounts held by ReserveSpend, keyed by
+// normalized chain and spend key. mu also serializes the checks that count
+// them, so two sends cannot both pass against the same headroom.
+type spendReservations struct {
+	mu     sync.Mutex
+	amount map[string]*big.Rat
+}
+
+// SpendReservation holds an outgoing amount against the daily spend limits
+// while its transaction is signed and broadcast. Commit records the spend
+// once broadcast and Release drops it when the transaction is not sent;
+// both are safe to call on a nil reservation and after either has run.
+type SpendReservation struct {
+	wm     *WalletManager
+	chain  string
+	from   string
+	amount string
+	token  string
+	key    string
+	held   *big.Rat // counted in spendReserved, nil when not held
+	closed bool
+}
+
+// ReserveSpend is the step every path that signs and broadcasts outgoing
+// value takes before signing: sends (including auto-approved, scheduled and
+// price-triggered ones), approve_transaction, swaps, NFT transfers and
+// stake withdrawals to another address. It refuses amount as
+// checkSpendLimit does and otherwise holds it until the returned
+// reservation is committed or released, so concurrent transactions cannot
+// together pass a daily limit.
+//
+// Paths whose value stays with the wallet (wrapping, delegating stake),
+// that sign without broadcasting (token permits), or that only queue a
+// transaction for later (ScheduleTransaction, CreatePriceTrigger; the send
+// reserves when it runs) check the limits with validateTransactionSecurity
+// and hold nothing.
+func (wm *WalletManager) ReserveSpend(chainName, from, to, amount, token string) (*SpendReservation, error) {
+	chainName = NormalizeChain(chainName)
+	key := spendTokenKey(chainName, token)
+	reservation := &SpendReservation{wm: wm, chain: chainName, from: from, amount: amount, token: token, key: key}
+	limit, ok := wm.spendLimitFor(chainName, key)
+	if !ok {
+		return reservation, nil
+	}
+
+	wm.spendReserved.mu.Lock()
+	exceeded := wm.exceededSpendLimit(limit, chainName, key, from, to, amount, token)
+	if exceeded == nil {
+		if held, ok := new(big.Rat).SetString(strings.TrimSpace(amount)); ok {
+			if wm.spendReserved.amount == nil {
+				wm.spendReserved.amount = make(map[string]*big.Rat)
+			}
+			reservedKey := chainName + "|" + key
+			if wm.spendReserved.amount[reservedKey] == nil {
+				wm.spendReserved.amount[reservedKey] = new(big.Rat)
+			}
+			wm.spendReserved.amount[reservedKey].Add(wm.spendReserved.amount[reservedKey], held)
+			reservation.held = held
+		}
+	}
+	wm.spendReserved.mu.Unlock()
+	if exceeded != nil {
+		wm.refuseSpend(exceeded)
+		return nil, exceeded
+	}
+	return reservation, nil
+}
+
+// Commit records the broadcast transaction txHash so daily limits count it,
+// and drops the reservation
+func (r *SpendReservation) Commit(txHash string) {
+	if r == nil {
+		return
+	}
+	r.wm.spendReserved.mu.Lock()
+	defer r.wm.spendReserved.mu.Unlock()
+	if r.closed {
+		return
+	}
+	// Recorded before the hold is dropped, so the amount is always counted
+	r.wm.recordSpend(r.chain, r.from, r.amount, r.token, txHash)
+	r.unhold()
+}
+
+// Release drops the reservation of a transaction that was not sent
+func (r *SpendReservation) Release() {
+	if r == nil {
+		return
+	}
+	r.wm.spendReserved.mu.Lock()
+	defer r.wm.spendReserved.mu.Unlock()
+	if !r.closed {
+		r.unhold()
+	}
+}
+
+// unhold takes the reserved amount back out of spendReserved; the caller holds its mu
+func (r *SpendReservation) unhold() {
+	r.closed = true
+	if r.held == nil {
+		return
+	}
+	reservedKey := r.chain + "|" + r.key
+	if reserved := r.wm.spendReserved.amount[reservedKey]; reserved != nil {
+		reserved.Sub(reserved, r.held)
+		if reserved.Sign() <= 0 {
+			delete(r.wm.spendReserved.amount, reservedKey)
+		}
+	}
+}
+
+// exceededSpendLimit reports how amount breaks limit, or nil when it does
+// not; the caller holds spendReserved.mu
+func (wm *WalletManager) exceededSpendLimit(limit config.SpendLimit, chainName, key, from, to, amount, token string) *SpendLimitExceededError {
+	exceeded := &SpendLimitExceededError{Chain: chainName, From: from, To: to, Amount: amount, Token: token}
+	switch {
+	case limit.MaxTxValue > 0 && amountAbove(amount, limit.MaxTxValue):
+		exceeded.Limit, exceeded.Max = SpendLimitMaxTxValue, limit.MaxTxValue
+	case limit.DailyLimit > 0:
+		spent := wm.spentSince(chainName, key, time.Now().Add(-spendWindow))
+		if reserved := wm.spendReserved.amount[chainName+"|"+key]; reserved != nil {
+			spent.Add(spent, reserved)
+		}
+		total, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+		if ok {
+			total.Add(total, spent)
+		}
+		if ok && !amountAbove(total.FloatString(18), limit.DailyLimit) {
+			return nil
+		}
+		exceeded.Limit, exceeded.Max = SpendLimitDaily, limit.DailyLimit
+		exceeded.Spent = formatRat(spent)
+	default:
+		return nil
+	}
+	return exceeded
+}
+
+// refuseSpend audits a refused transaction and tells the spend limit listeners
+func (wm *WalletManager) refuseSpend(exceeded *SpendLimitExceededError) {
+	wm.auditSpendLimit(exceeded)
+	wm.spendMu.RLock()
+	listeners := append([]SpendLimitListener(nil), wm.spendLimitListeners...)
+	wm.spendMu.RUnlock()
+	for _, listener := range listeners {
+		listener(*exceeded)
+	}
+}
+
+// spendLimitFor returns the limit configured for the token with spend key key
+// on a normalized chain, if either of its caps is set
+func (wm *WalletManager) spendLimitFor(chainName, key string) (config.SpendLimit, bool) {
+	wm.spendMu.RLock()
+	chainLimits, ok := wm.spendLimits[chainName]
+	wm.spendMu.RUnlock()
+	if !ok {
+		return config.SpendLimit{}, false
+	}
+	if key == "" {
+		return chainLimits.Native, chainLimits.Native.Enabled()
+	}
+	for token, limit := range chainLimits.Tokens {
+		if spendTokenKey(chainName, token) == key && limit.Enabled() {
+			return limit, true
+		}
+	}
+	return config.SpendLimit{}, false
+}
+
+// spentSince totals the spends of the token with spend key key on a
+// normalized chain recorded after since
+func (wm *WalletManager) spentSince(chainName, key string, since time.Time) *big.Rat {
+	total := new(big.Rat)
+	for _, entry := range wm.auditLogger.GetAuditLogByAction(AuditActionSpend) {
+		if entry.Chain != chainName || !entry.Timestamp.After(since) || spendTokenKey(chainName, entry.Token) != key {
+			continue
+		}
+		if amount, ok := new(big.Rat).SetString(entry.Amount); ok {
+			total.Add(total, amount)
+		}
+	}
+	return total
+}
+
+// recordSpend audits a broadcast send so daily limits count it
+func (wm *WalletManager) recordSpend(chainName, from, amount, token, txHash string) {
+	if _, err := wm.auditLogger.LogSpend(txHash, chainName, token, amount, from); err != nil {
+		wm.logger.Error("Failed to record send for spend limits", zap.String("tx_hash", txHash), zap.Error(err))
+	}
+}
+
+// auditSpendLimit records a refused transaction in the audit log
+func (wm *WalletManager) auditSpendLimit(exceeded *SpendLimitExceededError) {
+	if _, err := wm.auditLogger.LogSecurityEvent(AuditActionSpendLimitExceeded, exceeded.Limit, exceeded.Error(), "system", exceeded.From); err != nil {
+		wm.logger.Error("Failed to audit spend limit refusal", zap.Error(err))
+	}
+	wm.logger.Warn("Transaction refused by spend limit",
+		zap.String("chain", exceeded.Chain),
+		zap.String("from", exceeded.From),
+		zap.String("amount", exceeded.Amount),
+		zap.String("token", exceeded.Token),
+		zap.String("limit", exceeded.Limit))
+}
+
+// spendTokenKey identifies the token spends and limits are tracked by on a
+// normalized chain: empty for the native coin, otherwise the address, with
+// listed symbols resolved so USDC and its address match. EVM addresses are
+// lowercased; SPL mints are case-sensitive base58 and kept as they are.
+func spendTokenKey(chainName, token string) string {
+	if isNativeSymbol(chainName, token) {
+		return ""
+	}
+	if listed, ok := ResolveTokenSymbol(chainName, token); ok {
+		token = listed.Address
+	}
+	token = strings.TrimSpace(token)
+	if strings.HasPrefix(token, "0x") || strings.HasPrefix(token, "0X") {
+		return strings.ToLower(token)
+	}
+	return token
+}
+
+// spendTokenName names token in messages, using the chain's symbol for the native coin
+func spendTokenName(chainName, token string) string {
+	if isNativeSymbol(chainName, token) {
+		return NativeTokenSymbol(chainName)
+	}
+	return strings.TrimSpace(token)
+}
+
+// formatLimit prints a configured cap in its shortest decimal form
+func formatLimit(limit float64) string {
+	return strconv.FormatFloat(limit, 'f', -1, 64)
+}
+
+// formatRat prints an amount without trailing zeros
+func formatRat(value *big.Rat) string {
+	formatted := value.FloatString(18)
+	formatted = strings.TrimRight(formatted, "0")
+	return strings.TrimSuffix(formatted, ".")
+}
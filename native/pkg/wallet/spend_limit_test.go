@@ -0,0 +1,302 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
+
+const (
+	spendFrom = "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK"
+	spendTo   = "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY"
+	spendUSDC = "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"
+)
+
+func newSpendLimitTestManager(t *testing.T, limits config.ChainSpendLimits) (*WalletManager, *countingSendChain, *[]SpendLimitExceededError) {
+	t.Helper()
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	unlockForTest(wm, spendFrom)
+	sender := &countingSendChain{SolanaChain: chain.NewSolanaChainLegacy()}
+	wm.chainFactory.RegisterChain("solana", sender)
+	wm.setSpendLimits(map[string]config.ChainSpendLimits{"sol": limits})
+	var (
+		mu       sync.Mutex
+		exceeded []SpendLimitExceededError
+	)
+	wm.OnSpendLimitExceeded(func(e SpendLimitExceededError) {
+		mu.Lock()
+		defer mu.Unlock()
+		exceeded = append(exceeded, e)
+	})
+	return wm, sender, &exceeded
+}
+
+func TestSpendLimitRejectsSendCrossingDailyLimit(t *testing.T) {
+	ctx := context.Background()
+	wm, sender, exceeded := newSpendLimitTestManager(t, config.ChainSpendLimits{
+		Native: config.SpendLimit{MaxTxValue: 0.5, DailyLimit: 1},
+	})
+
+	for _, amount := range []string{"0.3", "0.25", "0.35"} {
+		if _, err := wm.SendTransaction(ctx, "solana", spendFrom, spendTo, amount, ""); err != nil {
+			t.Fatalf("send of %s SOL within the daily limit failed: %v", amount, err)
+		}
+	}
+
+	_, err := wm.SendTransaction(ctx, "solana", spendFrom, spendTo, "0.2", "SOL")
+	var limitErr *SpendLimitExceededError
+	if !errors.Is(err, ErrSpendLimitExceeded) || !errors.As(err, &limitErr) {
+		t.Fatalf("expected the send crossing the daily limit to be refused, got %v", err)
+	}
+	if limitErr.Limit != SpendLimitDaily || limitErr.Spent != "0.9" {
+		t.Errorf("expected a daily limit refusal after 0.9 SOL, got %s after %s", limitErr.Limit, limitErr.Spent)
+	}
+	if !strings.Contains(err.Error(), "daily limit of 1 SOL") {
+		t.Errorf("error should name the limit: %v", err)
+	}
+	if sends := atomic.LoadInt32(&sender.sends); sends != 3 {
+		t.Errorf("expected only the 3 sends within the limit to be broadcast, got %d", sends)
+	}
+
+	// What is left of the limit can still be sent
+	if _, err := wm.SendTransaction(ctx, "solana", spendFrom, spendTo, "0.1", ""); err != nil {
+		t.Errorf("send up to the daily limit should succeed: %v", err)
+	}
+
+	if len(*exceeded) != 1 || (*exceeded)[0].Amount != "0.2" || (*exceeded)[0].From != spendFrom {
+		t.Errorf("expected listeners to be told about the refused send, got %+v", *exceeded)
+	}
+	if audited := wm.auditLogger.GetAuditLogByAction(AuditActionSpendLimitExceeded); len(audited) != 1 {
+		t.Errorf("expected the refusal to be audited, got %d entries", len(audited))
+	}
+	if spends := wm.auditLogger.GetAuditLogByAction(AuditActionSpend); len(spends) != 4 {
+		t.Errorf("expected 4 recorded spends, got %d", len(spends))
+	}
+}
+
+func TestSpendLimitRejectsLargeAndUncomparableAmounts(t *testing.T) {
+	ctx := context.Background()
+	wm, sender, exceeded := newSpendLimitTestManager(t, config.ChainSpendLimits{
+		Native: config.SpendLimit{MaxTxValue: 0.5},
+	})
+
+	for _, amount := range []string{"0.51", "max"} {
+		_, err := wm.SendTransaction(ctx, "solana", spendFrom, spendTo, amount, "")
+		var limitErr *SpendLimitExceededError
+		if !errors.As(err, &limitErr) || limitErr.Limit != SpendLimitMaxTxValue {
+			t.Errorf("expected %s SOL to be refused by max_tx_value, got %v", amount, err)
+		}
+	}
+	if sender.sends != 0 || len(*exceeded) != 2 {
+		t.Errorf("expected 2 refusals and no broadcasts, got %d refusals and %d sends", len(*exceeded), sender.sends)
+	}
+	if _, err := wm.SendTransaction(ctx, "solana", spendFrom, spendTo, "0.5", ""); err != nil {
+		t.Errorf("a send at max_tx_value should succeed: %v", err)
+	}
+}
+
+func TestSpendLimitTracksNativeAndTokensSeparately(t *testing.T) {
+	ctx := context.Background()
+	wm, _, _ := newSpendLimitTestManager(t, config.ChainSpendLimits{
+		Native: config.SpendLimit{DailyLimit: 1},
+		Tokens: map[string]config.SpendLimit{"usdc": {DailyLimit: 100}},
+	})
+
+	if _, err := wm.SendTransaction(ctx, "solana", spendFrom, spendTo, "1", ""); err != nil {
+		t.Fatalf("native send failed: %v", err)
+	}
+	// The native limit is used up, but tokens count against their own
+	if _, err := wm.SendTransaction(ctx, "solana", spendFrom, spendTo, "60", "USDC"); err != nil {
+		t.Fatalf("USDC send should not count against the native limit: %v", err)
+	}
+	// The mint and the symbol are the same token
+	if _, err := wm.SendTransaction(ctx, "solana", spendFrom, spendTo, "50", spendUSDC); !errors.Is(err, ErrSpendLimitExceeded) {
+		t.Errorf("expected the USDC daily limit to count sends by symbol, got %v", err)
+	}
+	if _, err := wm.SendTransaction(ctx, "solana", spendFrom, spendTo, "0.01", ""); !errors.Is(err, ErrSpendLimitExceeded) {
+		t.Errorf("expected the native daily limit to be used up, got %v", err)
+	}
+	// Tokens without a limit are not capped
+	if _, err := wm.SendTransaction(ctx, "solana", spendFrom, spendTo, "1000000", "Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB"); err != nil {
+		t.Errorf("unlimited token should not be capped: %v", err)
+	}
+}
+
+func TestSpendLimitMatchesSPLMintsExactly(t *testing.T) {
+	ctx := context.Background()
+	usdt := "Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB"
+	wm, _, _ := newSpendLimitTestManager(t, config.ChainSpendLimits{
+		Tokens: map[string]config.SpendLimit{usdt: {MaxTxValue: 100}},
+	})
+
+	if _, err := wm.SendTransaction(ctx, "solana", spendFrom, spendTo, "150", usdt); !errors.Is(err, ErrSpendLimitExceeded) {
+		t.Errorf("expected the mint's max_tx_value to apply, got %v", err)
+	}
+	// Another case is another mint, which has no limit
+	if key := spendTokenKey("solana", strings.ToLower(usdt)); key == spendTokenKey("solana", usdt) {
+		t.Errorf("expected SPL mints to keep their case, got %q", key)
+	}
+	if key := spendTokenKey("ethereum", "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"); key != "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48" {
+		t.Errorf("expected EVM addresses to be lowercased, got %q", key)
+	}
+}
+
+func TestSpendLimitIgnoresSpendsOutsideWindow(t *testing.T) {
+	wm, _, _ := newSpendLimitTestManager(t, config.ChainSpendLimits{
+		Native: config.SpendLimit{DailyLimit: 1},
+	})
+	for _, entry := range []AuditLogEntry{
+		{ID: "old", Action: AuditActionSpend, Chain: "solana", Amount: "5", Timestamp: time.Now().Add(-25 * time.Hour)},
+		{ID: "recent", Action: AuditActionSpend, Chain: "solana", Token: "SOL", Amount: "0.75", Timestamp: time.Now().Add(-23 * time.Hour)},
+		{ID: "other-chain", Action: AuditActionSpend, Chain: "ethereum", Amount: "5", Timestamp: time.Now()},
+	} {
+		if err := wm.auditLogger.record(entry); err != nil {
+			t.Fatalf("record failed: %v", err)
+		}
+	}
+
+	if err := wm.validateTransactionSecurity("solana", spendFrom, spendTo, "0.25", ""); err != nil {
+		t.Errorf("only the recent spend should count: %v", err)
+	}
+	if err := wm.validateTransactionSecurity("solana", spendFrom, spendTo, "0.26", ""); !errors.Is(err, ErrSpendLimitExceeded) {
+		t.Errorf("expected 0.75 + 0.26 SOL to exceed the daily limit, got %v", err)
+	}
+	// Chains without limits are not checked
+	if err := wm.validateTransactionSecurity("ethereum", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "0xFb6916095CA1dF60bB79cE92Ce3Ea74c37C5D35a", "100", ""); err != nil {
+		t.Errorf("ethereum has no spend limits: %v", err)
+	}
+}
+
+// slowSendChain takes a while to broadcast, so concurrent sends overlap
+type slowSendChain struct {
+	*countingSendChain
+}
+
+func (c *slowSendChain) SendTransaction(ctx context.Context, from, to, amount, token, privateKey string) (string, error) {
+	time.Sleep(20 * time.Millisecond)
+	return c.countingSendChain.SendTransaction(ctx, from, to, amount, token, privateKey)
+}
+
+func TestSpendLimitHoldsConcurrentSends(t *testing.T) {
+	ctx := context.Background()
+	wm, sender, _ := newSpendLimitTestManager(t, config.ChainSpendLimits{
+		Native: config.SpendLimit{DailyLimit: 1},
+	})
+	wm.chainFactory.RegisterChain("solana", &slowSendChain{countingSendChain: sender})
+
+	// 20 distinct sends of about 0.1 SOL race for a 1 SOL daily limit
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		sent = new(big.Rat)
+	)
+	for i := 0; i < 20; i++ {
+		amount := fmt.Sprintf("0.1%02d", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := wm.SendTransaction(ctx, "solana", spendFrom, spendTo, amount, "")
+			switch {
+			case err == nil:
+				value, _ := new(big.Rat).SetString(amount)
+				mu.Lock()
+				sent.Add(sent, value)
+				mu.Unlock()
+			case !errors.Is(err, ErrSpendLimitExceeded):
+				t.Errorf("send of %s SOL failed: %v", amount, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if sent.Cmp(big.NewRat(1, 1)) > 0 {
+		t.Errorf("concurrent sends together passed the daily limit: %s SOL sent", formatRat(sent))
+	}
+	if sends := atomic.LoadInt32(&sender.sends); sends == 0 || sends >= 20 {
+		t.Errorf("expected some but not all sends to be broadcast, got %d", sends)
+	}
+	if spends := wm.auditLogger.GetAuditLogByAction(AuditActionSpend); len(spends) != int(sender.sends) {
+		t.Errorf("expected every broadcast to be recorded, got %d spends for %d sends", len(spends), sender.sends)
+	}
+	if len(wm.spendReserved.amount) != 0 {
+		t.Errorf("reservations should be dropped once the sends finish, got %v", wm.spendReserved.amount)
+	}
+}
+
+func TestSpendLimitReleasesFailedSends(t *testing.T) {
+	ctx := context.Background()
+	wm, sender, _ := newSpendLimitTestManager(t, config.ChainSpendLimits{
+		Native: config.SpendLimit{DailyLimit: 1},
+	})
+
+	sender.fail = true
+	if _, err := wm.SendTransaction(ctx, "solana", spendFrom, spendTo, "1", ""); err == nil {
+		t.Fatal("expected the broadcast to fail")
+	}
+	sender.fail = false
+	if _, err := wm.SendTransaction(ctx, "solana", spendFrom, spendTo, "1", ""); err != nil {
+		t.Errorf("a failed send must not use up the daily limit: %v", err)
+	}
+
+	// A reservation holds the limit until it is released
+	reservation, err := wm.ReserveSpend("solana", spendFrom, spendTo, "0", "")
+	if err != nil {
+		t.Fatalf("reserving nothing should succeed: %v", err)
+	}
+	reservation.Release()
+	reservation.Commit("tx-after-release")
+	if spends := wm.auditLogger.GetAuditLogByAction(AuditActionSpend); len(spends) != 1 {
+		t.Errorf("a released reservation must not be recorded, got %d spends", len(spends))
+	}
+}
+
+func TestCheckSpendLimitHoldsNothing(t *testing.T) {
+	wm, _, exceeded := newSpendLimitTestManager(t, config.ChainSpendLimits{
+		Native: config.SpendLimit{DailyLimit: 1},
+	})
+
+	reservation, err := wm.ReserveSpend("solana", spendFrom, spendTo, "0.6", "")
+	if err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+	defer reservation.Release()
+
+	// The check counts the reservation in flight but holds nothing itself
+	for i := 0; i < 2; i++ {
+		if err := wm.CheckSpendLimit("sol", spendFrom, spendTo, "0.4", ""); err != nil {
+			t.Fatalf("0.4 SOL fits next to the 0.6 held: %v", err)
+		}
+	}
+	if err := wm.CheckSpendLimit("sol", spendFrom, spendTo, "0.5", ""); !errors.Is(err, ErrSpendLimitExceeded) {
+		t.Fatalf("expected 0.5 SOL to exceed the daily limit, got %v", err)
+	}
+	if len(*exceeded) != 0 || len(wm.auditLogger.GetAuditLogByAction(AuditActionSpendLimitExceeded)) != 0 {
+		t.Error("a check must not be reported or audited as a refusal")
+	}
+}
+
+func TestSpendLimitAppliesToAutoApproval(t *testing.T) {
+	ctx := context.Background()
+	wm, sender, _ := newSpendLimitTestManager(t, config.ChainSpendLimits{
+		Native: config.SpendLimit{MaxTxValue: 0.5},
+	})
+
+	tx := &PendingTransaction{Chain: "solana", From: spendFrom, To: spendTo, Amount: "0.6", Token: "SOL"}
+	if _, err := wm.AutoApproveTransaction(ctx, tx, "small", "https://jup.ag"); !errors.Is(err, ErrSpendLimitExceeded) {
+		t.Errorf("expected auto-approval above max_tx_value to be refused, got %v", err)
+	}
+	if sender.sends != 0 {
+		t.Errorf("nothing should be broadcast, got %d sends", sender.sends)
+	}
+}
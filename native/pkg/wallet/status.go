@@ -1,7 +1,12 @@
 // SPDX-License-Identifier: Apache-2.0
 package wallet
 
-import "time"
+import (
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+)
 
 // WalletStatus represents the current status of a wallet.
 type WalletStatus struct {
@@ -10,6 +15,12 @@ type WalletStatus struct {
 	Ready     bool              `json:"ready"`
 	Chains    map[string]bool   `json:"chains,omitempty"`
 	LastUsed  int64             `json:"last_used,omitempty"`
+	
+	// LastSuccessfulCall maps chain name to the unix time of its last successful RPC call
+	LastSuccessfulCall map[string]int64 `json:"last_successful_call,omitempty"`
+
+	// ProviderBreakers reports the circuit breaker of every external HTTP provider host
+	ProviderBreakers []httpclient.BreakerStats `json:"provider_breakers,omitempty"`
 }
 
 // NewWalletStatus creates a new WalletStatus with default values.
@@ -22,3 +33,18 @@ func NewWalletStatus(address, publicKey string) *WalletStatus {
 		LastUsed:  time.Now().Unix(),
 	}
 }
+
+// LastSuccessfulRPCCalls returns the last successful RPC time per chain as unix seconds
+func LastSuccessfulRPCCalls() map[string]int64 {
+	snapshot := chain.DefaultRPCHealth.Snapshot()
+	calls := make(map[string]int64, len(snapshot))
+	for chainName, ts := range snapshot {
+		calls[chainName] = ts.Unix()
+	}
+	return calls
+}
+
+// ProviderBreakers returns the circuit breaker state of each external HTTP provider host
+func ProviderBreakers() []httpclient.BreakerStats {
+	return httpclient.DefaultRegistry.Snapshot()
+}
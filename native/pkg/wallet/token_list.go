@@ -0,0 +1,360 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mr-tron/base58"
+	"go.uber.org/zap"
+)
+
+// maxTokenListSize bounds how much of a token list source is read
+const maxTokenListSize = 20 << 20
+
+// maxTokenListTokens is the token limit of the Uniswap token-list schema
+const maxTokenListTokens = 10000
+
+// tokenListChains maps token-list chain ids to normalized chains. Solana lists
+// use 101 for mainnet-beta.
+var tokenListChains = map[int64]string{
+	1:   "ethereum",
+	56:  "bsc",
+	101: "solana",
+}
+
+// TokenList is a token list in the Uniswap token-list format
+type TokenList struct {
+	Name      string           `json:"name"`
+	Timestamp string           `json:"timestamp"`
+	Version   TokenListVersion `json:"version"`
+	Tokens    []TokenListToken `json:"tokens"`
+}
+
+// TokenListVersion is the semantic version of a token list
+type TokenListVersion struct {
+	Major int `json:"major"`
+	Minor int `json:"minor"`
+	Patch int `json:"patch"`
+}
+
+// TokenListToken is one entry of a token list
+type TokenListToken struct {
+	ChainID  int64    `json:"chainId"`
+	Address  string   `json:"address"`
+	Name     string   `json:"name"`
+	Symbol   string   `json:"symbol"`
+	Decimals int      `json:"decimals"`
+	LogoURI  string   `json:"logoURI,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// ParseTokenList decodes and validates a token list. Tokens on chains the
+// wallet does not support are kept but ignored when the list is imported.
+func ParseTokenList(data []byte) (*TokenList, error) {
+	var list TokenList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid token list JSON: %w", err)
+	}
+	if strings.TrimSpace(list.Name) == "" {
+		return nil, errors.New("token list name is required")
+	}
+	if list.Version.Major < 0 || list.Version.Minor < 0 || list.Version.Patch < 0 {
+		return nil, errors.New("token list version must not be negative")
+	}
+	if len(list.Tokens) == 0 {
+		return nil, errors.New("token list has no tokens")
+	}
+	if len(list.Tokens) > maxTokenListTokens {
+		return nil, fmt.Errorf("token list has %d tokens, at most %d are allowed", len(list.Tokens), maxTokenListTokens)
+	}
+	for i, token := range list.Tokens {
+		if err := validateTokenListToken(token); err != nil {
+			return nil, fmt.Errorf("token %d (%s): %w", i, token.Symbol, err)
+		}
+	}
+	return &list, nil
+}
+
+func validateTokenListToken(token TokenListToken) error {
+	if token.ChainID <= 0 {
+		return fmt.Errorf("chainId must be positive, got %d", token.ChainID)
+	}
+	if symbol := strings.TrimSpace(token.Symbol); symbol == "" || len(symbol) > 20 {
+		return fmt.Errorf("symbol must be 1 to 20 characters, got %q", token.Symbol)
+	}
+	if token.Decimals < 0 || token.Decimals > 255 {
+		return fmt.Errorf("decimals must be between 0 and 255, got %d", token.Decimals)
+	}
+	switch tokenListChains[token.ChainID] {
+	case "ethereum", "bsc":
+		if !common.IsHexAddress(token.Address) || !strings.HasPrefix(token.Address, "0x") {
+			return fmt.Errorf("invalid EVM address %q", token.Address)
+		}
+	case "solana":
+		if decoded, err := base58.Decode(token.Address); err != nil || len(decoded) != 32 {
+			return fmt.Errorf("invalid Solana mint address %q", token.Address)
+		}
+	default:
+		if strings.TrimSpace(token.Address) == "" {
+			return errors.New("address is required")
+		}
+	}
+	return nil
+}
+
+// ListedToken is a token imported from a token list
+type ListedToken struct {
+	Chain    string `json:"chain"`
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name,omitempty"`
+	Decimals int    `json:"decimals"`
+	LogoURI  string `json:"logo_uri,omitempty"`
+	List     string `json:"list"` // name of the list the token came from
+}
+
+// TokenListRegistry indexes imported token lists by chain and address and by
+// chain and symbol. It is safe for concurrent use.
+type TokenListRegistry struct {
+	mu        sync.RWMutex
+	byAddress map[string]*ListedToken
+	bySymbol  map[string]*ListedToken
+}
+
+// NewTokenListRegistry creates an empty registry
+func NewTokenListRegistry() *TokenListRegistry {
+	return &TokenListRegistry{
+		byAddress: make(map[string]*ListedToken),
+		bySymbol:  make(map[string]*ListedToken),
+	}
+}
+
+// DefaultTokenLists holds the configured token lists and is consulted before
+// the bundled token list
+var DefaultTokenLists = NewTokenListRegistry()
+
+// Replace swaps the registry contents for lists. When lists disagree on an
+// address or symbol, the earlier list wins, and within a list the first entry.
+func (r *TokenListRegistry) Replace(lists []*TokenList) {
+	byAddress := make(map[string]*ListedToken)
+	bySymbol := make(map[string]*ListedToken)
+	for _, list := range lists {
+		for _, entry := range list.Tokens {
+			chainName, ok := tokenListChains[entry.ChainID]
+			if !ok {
+				continue
+			}
+			token := &ListedToken{
+				Chain:    chainName,
+				Address:  entry.Address,
+				Symbol:   strings.TrimSpace(entry.Symbol),
+				Name:     entry.Name,
+				Decimals: entry.Decimals,
+				LogoURI:  entry.LogoURI,
+				List:     list.Name,
+			}
+			addressKey := tokenAddressKey(chainName, entry.Address)
+			if _, exists := byAddress[addressKey]; !exists {
+				byAddress[addressKey] = token
+			}
+			symbolKey := chainName + ":" + strings.ToUpper(token.Symbol)
+			if _, exists := bySymbol[symbolKey]; !exists {
+				bySymbol[symbolKey] = token
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byAddress = byAddress
+	r.bySymbol = bySymbol
+}
+
+// Lookup returns the imported token at address on a normalized chain
+func (r *TokenListRegistry) Lookup(chainName, address string) (*ListedToken, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	token, ok := r.byAddress[tokenAddressKey(chainName, address)]
+	return token, ok
+}
+
+// tokenAddressKey keys a token address on a normalized chain. EVM addresses
+// are matched in any case; Solana mints are base58 and only match exactly.
+func tokenAddressKey(chainName, address string) string {
+	address = strings.TrimSpace(address)
+	if chainName != "solana" {
+		address = strings.ToLower(address)
+	}
+	return chainName + ":" + address
+}
+
+// ResolveSymbol returns the imported token with symbol on a normalized chain
+func (r *TokenListRegistry) ResolveSymbol(chainName, symbol string) (*ListedToken, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	token, ok := r.bySymbol[chainName+":"+strings.ToUpper(strings.TrimSpace(symbol))]
+	return token, ok
+}
+
+// Len returns the number of imported tokens
+func (r *TokenListRegistry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.byAddress)
+}
+
+// ResolveTokenSymbol resolves a token symbol on a normalized chain to its
+// contract or mint address, preferring imported token lists over the bundled one
+func ResolveTokenSymbol(chainName, symbol string) (*ListedToken, bool) {
+	if token, ok := DefaultTokenLists.ResolveSymbol(chainName, symbol); ok {
+		return token, true
+	}
+	for address, known := range knownTokens {
+		if known.chain == chainName && strings.EqualFold(known.symbol, strings.TrimSpace(symbol)) {
+			return &ListedToken{Chain: chainName, Address: knownTokenAddress(address), Symbol: known.symbol, Decimals: known.decimals}, true
+		}
+	}
+	return nil, false
+}
+
+// TokenAliases returns the other names the imported and bundled token lists
+// know token by on a normalized chain: the address of a listed symbol, or the
+// symbol of a listed address. Tokens the lists do not know have none.
+func TokenAliases(chainName, token string) []string {
+	if listed, ok := ResolveTokenSymbol(chainName, token); ok {
+		return []string{listed.Address}
+	}
+	if listed, ok := DefaultTokenLists.Lookup(chainName, token); ok {
+		return []string{listed.Symbol}
+	}
+	if known, ok := lookupKnownToken(token); ok && known.chain == chainName {
+		return []string{known.symbol}
+	}
+	return nil
+}
+
+// TokenListImporter loads the configured token list sources into a registry
+type TokenListImporter struct {
+	sources  []string
+	interval time.Duration
+	registry *TokenListRegistry
+	client   *http.Client
+	logger   *zap.Logger
+
+	mu   sync.Mutex
+	last map[string]*TokenList // last good copy of each source
+}
+
+// NewTokenListImporter creates an importer for cfg that fills registry
+func NewTokenListImporter(cfg config.TokenListsConfig, registry *TokenListRegistry, logger *zap.Logger) *TokenListImporter {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &TokenListImporter{
+		sources:  cfg.Sources,
+		interval: cfg.RefreshInterval,
+		registry: registry,
+		client:   httpclient.New("token-list", httpclient.WithTimeout(30*time.Second)),
+		logger:   logger,
+		last:     make(map[string]*TokenList),
+	}
+}
+
+// Refresh reads every source and rebuilds the registry. A source that cannot
+// be read keeps its previously loaded copy; the first such error is returned.
+func (i *TokenListImporter) Refresh(ctx context.Context) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var firstErr error
+	lists := make([]*TokenList, 0, len(i.sources))
+	for _, source := range i.sources {
+		list, err := i.load(ctx, source)
+		if err != nil {
+			i.logger.Warn("Failed to import token list", zap.String("source", source), zap.Error(err))
+			if firstErr == nil {
+				firstErr = fmt.Errorf("token list %s: %w", source, err)
+			}
+			list = i.last[source]
+		} else {
+			i.last[source] = list
+		}
+		if list != nil {
+			lists = append(lists, list)
+		}
+	}
+	i.registry.Replace(lists)
+	i.logger.Debug("Token lists imported", zap.Int("lists", len(lists)), zap.Int("tokens", i.registry.Len()))
+	return firstErr
+}
+
+// Run imports the token lists immediately and then every refresh interval
+// until ctx is done. It returns at once when no sources are configured.
+func (i *TokenListImporter) Run(ctx context.Context) {
+	if len(i.sources) == 0 {
+		return
+	}
+	_ = i.Refresh(ctx)
+	if i.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = i.Refresh(ctx)
+		}
+	}
+}
+
+// load reads source, an http(s) URL or a local file path
+func (i *TokenListImporter) load(ctx context.Context, source string) (*TokenList, error) {
+	source = strings.TrimSpace(source)
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		file, err := os.Open(strings.TrimPrefix(source, "file://"))
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		data, err := io.ReadAll(io.LimitReader(file, maxTokenListSize))
+		if err != nil {
+			return nil, err
+		}
+		return ParseTokenList(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenListSize))
+	if err != nil {
+		return nil, err
+	}
+	return ParseTokenList(data)
+}
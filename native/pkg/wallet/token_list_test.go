@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+)
+
+const testTokenListJSON = `{
+  "name": "Test List",
+  "timestamp": "2026-01-01T00:00:00.000Z",
+  "version": {"major": 1, "minor": 2, "patch": 0},
+  "tokens": [
+    {"chainId": 1, "address": "0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984", "name": "Uniswap", "symbol": "UNI", "decimals": 18, "logoURI": "https://example.com/uni.png"},
+    {"chainId": 1, "address": "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", "name": "USD Coin (custom)", "symbol": "USDC", "decimals": 6},
+    {"chainId": 101, "address": "DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263", "name": "Bonk", "symbol": "BONK", "decimals": 5},
+    {"chainId": 137, "address": "0x0000000000000000000000000000000000001010", "name": "Polygon", "symbol": "POL", "decimals": 18}
+  ]
+}`
+
+func TestParseTokenListValidates(t *testing.T) {
+	if _, err := ParseTokenList([]byte(testTokenListJSON)); err != nil {
+		t.Fatalf("valid token list rejected: %v", err)
+	}
+
+	cases := map[string]string{
+		"not JSON":          `tokens`,
+		"missing name":      `{"version":{"major":1},"tokens":[{"chainId":1,"address":"0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984","symbol":"UNI","decimals":18}]}`,
+		"no tokens":         `{"name":"x","version":{"major":1},"tokens":[]}`,
+		"bad chain id":      `{"name":"x","version":{"major":1},"tokens":[{"chainId":0,"address":"0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984","symbol":"UNI","decimals":18}]}`,
+		"bad EVM address":   `{"name":"x","version":{"major":1},"tokens":[{"chainId":56,"address":"0x1234","symbol":"UNI","decimals":18}]}`,
+		"bad Solana mint":   `{"name":"x","version":{"major":1},"tokens":[{"chainId":101,"address":"0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984","symbol":"UNI","decimals":18}]}`,
+		"decimals too big":  `{"name":"x","version":{"major":1},"tokens":[{"chainId":1,"address":"0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984","symbol":"UNI","decimals":300}]}`,
+		"symbol is missing": `{"name":"x","version":{"major":1},"tokens":[{"chainId":1,"address":"0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984","decimals":18}]}`,
+	}
+	for name, data := range cases {
+		if _, err := ParseTokenList([]byte(data)); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
+
+func TestTokenListImporterLoadsFileAndURL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	if err := os.WriteFile(path, []byte(testTokenListJSON), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// A second list that conflicts with the first on UNI
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Replace(testTokenListJSON, `"symbol": "UNI", "decimals": 18`, `"symbol": "UNI", "decimals": 8`, 1)))
+	}))
+	defer server.Close()
+
+	registry := NewTokenListRegistry()
+	importer := NewTokenListImporter(config.TokenListsConfig{Sources: []string{path, server.URL}}, registry, nil)
+	if err := importer.Refresh(context.Background()); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+
+	if registry.Len() != 3 {
+		t.Errorf("expected 3 tokens on supported chains, got %d", registry.Len())
+	}
+	uni, ok := registry.ResolveSymbol("ethereum", "uni")
+	if !ok || uni.Address != "0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984" {
+		t.Fatalf("UNI not resolved: %+v", uni)
+	}
+	if uni.Decimals != 18 {
+		t.Errorf("the first configured list should win conflicts, got %d decimals", uni.Decimals)
+	}
+	if bonk, ok := registry.Lookup("solana", "DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263"); !ok || bonk.Symbol != "BONK" {
+		t.Errorf("BONK mint not indexed: %+v", bonk)
+	}
+	if _, ok := registry.ResolveSymbol("ethereum", "POL"); ok {
+		t.Error("tokens on unsupported chains must be ignored")
+	}
+
+	// A source that breaks keeps its last good copy
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := importer.Refresh(context.Background()); err == nil {
+		t.Error("expected the broken source to be reported")
+	}
+	if uni, _ := registry.ResolveSymbol("ethereum", "UNI"); uni == nil || uni.Decimals != 18 {
+		t.Errorf("broken source should keep its previous tokens, got %+v", uni)
+	}
+}
+
+func TestImportedTokensTakePrecedenceOverBundledList(t *testing.T) {
+	list, err := ParseTokenList([]byte(testTokenListJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	DefaultTokenLists.Replace([]*TokenList{list})
+	defer DefaultTokenLists.Replace(nil)
+
+	usdc := LookupTokenMetadata("ethereum", "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	if !usdc.Known || usdc.Description != "USD Coin (custom)" {
+		t.Errorf("imported entry should override the bundled one: %+v", usdc)
+	}
+	if token, ok := ResolveTokenSymbol("ethereum", "UNI"); !ok || token.List != "Test List" {
+		t.Errorf("UNI should resolve from the imported list: %+v", token)
+	}
+	// Imported SPL mints only match exactly
+	if bonk := LookupTokenMetadata("solana", "DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263"); !bonk.Known || bonk.Symbol != "BONK" {
+		t.Errorf("BONK should be found by its mint: %+v", bonk)
+	}
+	if bonk := LookupTokenMetadata("solana", strings.ToLower("DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263")); bonk.Known {
+		t.Errorf("a lowercased mint must not match BONK: %+v", bonk)
+	}
+	// Symbols missing from the imported lists still resolve from the bundled list
+	if token, ok := ResolveTokenSymbol("bsc", "BUSD"); !ok || token.Address != "0xe9e7cea3dedca5984780bafc599bd69add087d56" {
+		t.Errorf("BUSD should resolve from the bundled list: %+v", token)
+	}
+}
+
+func TestTokenListImporterRunWithoutSources(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		NewTokenListImporter(config.TokenListsConfig{RefreshInterval: time.Millisecond}, NewTokenListRegistry(), nil).Run(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run should return at once when no sources are configured")
+	}
+}
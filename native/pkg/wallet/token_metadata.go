@@ -0,0 +1,256 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/algonius/algonius-wallet/native/pkg/utils/httpclient"
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Defaults used when MetadataConfig leaves a field empty
+const (
+	DefaultIPFSGateway      = "https://ipfs.io/ipfs/"
+	DefaultMetadataCacheTTL = time.Hour
+	DefaultMetadataTimeout  = 10 * time.Second
+)
+
+// maxMetadataSize bounds the metadata JSON read from a single URI
+const maxMetadataSize = 1 << 20
+
+// trustWalletChainDirs maps normalized chains to their directory in the bundled token list
+var trustWalletChainDirs = map[string]string{
+	"ethereum": "ethereum",
+	"bsc":      "smartchain",
+	"solana":   "solana",
+}
+
+// TokenMetadata describes a fungible token from the bundled token list
+type TokenMetadata struct {
+	Chain       string `json:"chain"`
+	Address     string `json:"address,omitempty"` // contract or mint; empty for the native token
+	Symbol      string `json:"symbol,omitempty"`
+	Description string `json:"description,omitempty"`
+	Decimals    int    `json:"decimals,omitempty"`
+	IsNative    bool   `json:"is_native"`
+	LogoURI     string `json:"logo_uri,omitempty"`
+	Known       bool   `json:"known"` // false when the token is not in the bundled list
+}
+
+// LookupTokenMetadata resolves token on a normalized chain from the imported
+// token lists, then the bundled one. token may be empty or the native symbol
+// for the native token, or a contract/mint address. Unknown tokens are
+// returned with Known=false and only the fields that could be determined.
+func LookupTokenMetadata(chainName, token string) *TokenMetadata {
+	token = strings.TrimSpace(token)
+	nativeSymbol := NativeTokenSymbol(chainName)
+	if token == "" || (nativeSymbol != "" && strings.EqualFold(token, nativeSymbol)) {
+		metadata := &TokenMetadata{Chain: chainName, Symbol: nativeSymbol, IsNative: true}
+		if info, err := chain.DefaultTokenMapping.GetTokenInfo(nativeSymbol); err == nil && nativeSymbol != "" {
+			metadata.Description = info.Description
+			metadata.Decimals = info.Decimals
+			metadata.LogoURI = info.LogoURI
+			metadata.Known = true
+		}
+		return metadata
+	}
+
+	address := token
+	if common.IsHexAddress(token) {
+		address = common.HexToAddress(token).Hex()
+	}
+	metadata := &TokenMetadata{Chain: chainName, Address: address}
+	if listed, ok := DefaultTokenLists.Lookup(chainName, token); ok {
+		metadata.Symbol = listed.Symbol
+		metadata.Description = listed.Name
+		metadata.Decimals = listed.Decimals
+		metadata.LogoURI = listed.LogoURI
+		metadata.Known = true
+		return metadata
+	}
+	known, ok := lookupKnownToken(token)
+	if !ok || known.chain != chainName {
+		return metadata
+	}
+	metadata.Symbol = known.symbol
+	metadata.Decimals = known.decimals
+	metadata.Known = true
+	if dir, ok := trustWalletChainDirs[chainName]; ok {
+		metadata.LogoURI = chain.TokenListLogoBase + dir + "/assets/" + address + "/logo.png"
+	}
+	return metadata
+}
+
+// NFTMetadata is the subset of an ERC-721/ERC-1155/Metaplex metadata JSON the wallet surfaces
+type NFTMetadata struct {
+	Name        string                   `json:"name,omitempty"`
+	Description string                   `json:"description,omitempty"`
+	Image       string                   `json:"image,omitempty"`
+	ExternalURL string                   `json:"external_url,omitempty"`
+	Attributes  []map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// MetadataFetcher fetches metadata JSON over HTTP, rewriting ipfs:// URIs to a
+// gateway and caching each document for a configurable TTL
+type MetadataFetcher struct {
+	gateway string
+	ttl     time.Duration
+	client  *http.Client
+	cache   map[string]cachedMetadata
+	mutex   sync.Mutex
+}
+
+type cachedMetadata struct {
+	metadata  *NFTMetadata
+	fetchedAt time.Time
+}
+
+// NewMetadataFetcher creates a fetcher from cfg, filling empty fields with defaults
+func NewMetadataFetcher(cfg config.MetadataConfig) *MetadataFetcher {
+	gateway := cfg.IPFSGateway
+	if gateway == "" {
+		gateway = DefaultIPFSGateway
+	}
+	if !strings.HasSuffix(gateway, "/") {
+		gateway += "/"
+	}
+	ttl := cfg.CacheTTL
+	if ttl == 0 {
+		ttl = DefaultMetadataCacheTTL
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = DefaultMetadataTimeout
+	}
+	return &MetadataFetcher{
+		gateway: gateway,
+		ttl:     ttl,
+		client:  httpclient.New("nft-metadata", httpclient.WithTimeout(timeout)),
+		cache:   make(map[string]cachedMetadata),
+	}
+}
+
+// ResolveURI rewrites ipfs:// (and ipfs://ipfs/) URIs to the configured gateway;
+// other URIs are returned unchanged
+func (f *MetadataFetcher) ResolveURI(uri string) string {
+	uri = strings.TrimSpace(uri)
+	if !strings.HasPrefix(strings.ToLower(uri), "ipfs://") {
+		return uri
+	}
+	path := uri[len("ipfs://"):]
+	path = strings.TrimPrefix(path, "ipfs/")
+	return f.gateway + path
+}
+
+// Fetch returns the metadata document at uri, from cache while it is fresh.
+// data:application/json URIs, common for on-chain metadata, are decoded inline.
+func (f *MetadataFetcher) Fetch(ctx context.Context, uri string) (*NFTMetadata, error) {
+	if strings.TrimSpace(uri) == "" {
+		return nil, errors.New("metadata URI is empty")
+	}
+
+	f.mutex.Lock()
+	cached, ok := f.cache[uri]
+	f.mutex.Unlock()
+	if ok && time.Since(cached.fetchedAt) < f.ttl {
+		return cached.metadata, nil
+	}
+
+	body, err := f.read(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	var metadata NFTMetadata
+	if err := json.Unmarshal(body, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid metadata JSON at %s: %w", uri, err)
+	}
+	metadata.Image = f.ResolveURI(metadata.Image)
+
+	f.mutex.Lock()
+	f.cache[uri] = cachedMetadata{metadata: &metadata, fetchedAt: time.Now()}
+	f.mutex.Unlock()
+	return &metadata, nil
+}
+
+// read returns the raw document behind uri
+func (f *MetadataFetcher) read(ctx context.Context, uri string) ([]byte, error) {
+	if strings.HasPrefix(uri, "data:") {
+		header, payload, found := strings.Cut(uri[len("data:"):], ",")
+		if !found {
+			return nil, errors.New("malformed data URI")
+		}
+		if strings.HasSuffix(header, ";base64") {
+			return base64.StdEncoding.DecodeString(payload)
+		}
+		decoded, err := url.PathUnescape(payload)
+		return []byte(decoded), err
+	}
+
+	resolved := f.ResolveURI(uri)
+	if !strings.HasPrefix(resolved, "http://") && !strings.HasPrefix(resolved, "https://") {
+		return nil, fmt.Errorf("unsupported metadata URI scheme: %s", uri)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, resolved, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata from %s: %w", resolved, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("metadata request to %s returned HTTP %d", resolved, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize))
+}
+
+// ExpandERC1155URI substitutes the {id} placeholder of an ERC-1155 uri() with
+// the token ID as 64 lowercase hex digits, as the standard requires
+func ExpandERC1155URI(uri, tokenID string) string {
+	if !strings.Contains(uri, "{id}") {
+		return uri
+	}
+	id, ok := new(big.Int).SetString(tokenID, 10)
+	if hex := strings.TrimPrefix(strings.ToLower(tokenID), "0x"); !ok && hex != strings.ToLower(tokenID) {
+		id, ok = new(big.Int).SetString(hex, 16)
+	}
+	if !ok {
+		return uri
+	}
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", id))
+}
+
+// EnrichNFTs fills the name, description and image of each NFT from its metadata
+// URI. NFTs whose metadata cannot be fetched are left as they are.
+func (f *MetadataFetcher) EnrichNFTs(ctx context.Context, nfts []*NFTAsset) {
+	for _, nft := range nfts {
+		uri := nft.MetadataURI
+		if nft.Standard == NFTStandardERC1155 {
+			uri = ExpandERC1155URI(uri, nft.TokenID)
+		}
+		metadata, err := f.Fetch(ctx, uri)
+		if err != nil {
+			continue
+		}
+		if nft.Name == "" {
+			nft.Name = metadata.Name
+		}
+		nft.Description = metadata.Description
+		nft.Image = metadata.Image
+	}
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+)
+
+func TestLookupTokenMetadata(t *testing.T) {
+	native := LookupTokenMetadata("bsc", "")
+	if !native.Known || !native.IsNative || native.Symbol != "BNB" || !strings.HasSuffix(native.LogoURI, "smartchain/info/logo.png") {
+		t.Errorf("unexpected native metadata %+v", native)
+	}
+
+	usdc := LookupTokenMetadata("ethereum", "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	if !usdc.Known || usdc.Symbol != "USDC" || usdc.Decimals != 6 {
+		t.Errorf("unexpected token metadata %+v", usdc)
+	}
+	if !strings.HasSuffix(usdc.LogoURI, "ethereum/assets/0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48/logo.png") {
+		t.Errorf("expected a checksummed logo path, got %s", usdc.LogoURI)
+	}
+
+	// SPL mints resolve to their base58 spelling and only match in it
+	mint := "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"
+	if listed, ok := ResolveTokenSymbol("solana", "usdc"); !ok || listed.Address != mint {
+		t.Errorf("expected the USDC mint, got %+v", listed)
+	}
+	if spl := LookupTokenMetadata("solana", mint); !spl.Known || spl.Symbol != "USDC" || !strings.Contains(spl.LogoURI, mint) {
+		t.Errorf("expected the USDC mint to be known, got %+v", spl)
+	}
+	for _, wrongCase := range []string{strings.ToLower(mint), strings.ToUpper(mint)} {
+		if spl := LookupTokenMetadata("solana", wrongCase); spl.Known || spl.Address != wrongCase {
+			t.Errorf("a mint in the wrong case names another account and must not be corrected, got %+v", spl)
+		}
+		if symbol := tokenSymbol(wrongCase); symbol == "USDC" {
+			t.Errorf("expected %s not to be named USDC", wrongCase)
+		}
+	}
+
+	// The bundled list is per chain: an Ethereum contract is unknown on BSC
+	if LookupTokenMetadata("bsc", "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48").Known {
+		t.Error("expected an Ethereum token to be unknown on bsc")
+	}
+	unknown := LookupTokenMetadata("ethereum", "0x1111111111111111111111111111111111111111")
+	if unknown.Known || unknown.Symbol != "" || unknown.LogoURI != "" {
+		t.Errorf("expected only the address for an unknown token, got %+v", unknown)
+	}
+}
+
+func TestMetadataFetcherCachesAndResolvesIPFS(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/ipfs/QmHash/1.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"name":"Ape #1","description":"An ape","image":"ipfs://QmImage/1.png"}`))
+	}))
+	defer server.Close()
+
+	fetcher := NewMetadataFetcher(config.MetadataConfig{IPFSGateway: server.URL + "/ipfs", CacheTTL: time.Minute})
+	ctx := context.Background()
+
+	metadata, err := fetcher.Fetch(ctx, "ipfs://QmHash/1.json")
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if metadata.Name != "Ape #1" || metadata.Image != server.URL+"/ipfs/QmImage/1.png" {
+		t.Errorf("unexpected metadata %+v", metadata)
+	}
+	if _, err := fetcher.Fetch(ctx, "ipfs://QmHash/1.json"); err != nil || atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("expected the cached document to be reused, got %d requests, %v", requests, err)
+	}
+
+	if _, err := fetcher.Fetch(ctx, "ipfs://QmMissing"); err == nil {
+		t.Error("expected an error for a missing document")
+	}
+
+	inline := "data:application/json;base64," + base64.StdEncoding.EncodeToString([]byte(`{"name":"On-chain"}`))
+	if metadata, err := fetcher.Fetch(ctx, inline); err != nil || metadata.Name != "On-chain" {
+		t.Errorf("expected inline metadata to decode, got %+v, %v", metadata, err)
+	}
+}
+
+func TestMetadataFetcherEnrichNFTs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/000000000000000000000000000000000000000000000000000000000000002a.json") {
+			w.Write([]byte(`{"name":"Item 42","image":"https://example.com/42.png"}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	nfts := []*NFTAsset{
+		{Standard: NFTStandardERC1155, TokenID: "42", MetadataURI: server.URL + "/meta/{id}.json"},
+		{Standard: NFTStandardERC721, TokenID: "1", Name: "Kept", MetadataURI: server.URL + "/missing.json"},
+	}
+	NewMetadataFetcher(config.MetadataConfig{}).EnrichNFTs(context.Background(), nfts)
+
+	if nfts[0].Name != "Item 42" || nfts[0].Image != "https://example.com/42.png" {
+		t.Errorf("expected ERC-1155 metadata to be resolved, got %+v", nfts[0])
+	}
+	if nfts[1].Name != "Kept" || nfts[1].Image != "" {
+		t.Errorf("expected unavailable metadata to be omitted, got %+v", nfts[1])
+	}
+}
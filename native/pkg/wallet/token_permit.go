@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"go.uber.org/zap"
+)
+
+// ErrOwnerNotInWallet is returned when a permit or a staking operation is
+// requested for an address the unlocked wallet holds no key for
+var ErrOwnerNotInWallet = errors.New("owner is not an account of this wallet")
+
+// SignTokenPermit signs an EIP-2612 permit or EIP-3009 transfer authorization
+// for request.Owner on an EVM chain. Nothing is broadcast: the signed
+// authorization is returned for a relayer or a router that accepts permits.
+// The spender passes the same checks as the recipient of a send.
+func (wm *WalletManager) SignTokenPermit(ctx context.Context, chainName string, request chain.PermitRequest) (*chain.SignedPermit, error) {
+	if err := wm.requireUnlocked(); err != nil {
+		return nil, err
+	}
+	normalizedChain := NormalizeChain(chainName)
+	if normalizedChain != "ethereum" && normalizedChain != "bsc" {
+		return nil, fmt.Errorf("token permits are only supported on EVM chains, not %s", normalizedChain)
+	}
+	if err := wm.validateTransactionSecurity(normalizedChain, request.Owner, request.Spender, request.Amount, request.Token); err != nil {
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+	privateKey, err := wm.ownerSigningKey(request.Owner, "sign a token permit")
+	if err != nil {
+		return nil, err
+	}
+	if err := wm.AuthorizeSigning(ctx, SigningRequest{Kind: SigningKindPermit, Chain: normalizedChain, From: request.Owner, To: request.Spender, Amount: request.Amount, Token: request.Token}); err != nil {
+		return nil, err
+	}
+
+	chainImpl, err := wm.chainFactory.GetChain(normalizedChain)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := chainImpl.(chain.PermitSigner)
+	if !ok {
+		return nil, fmt.Errorf("token permits are not supported on %s in this build", normalizedChain)
+	}
+	signed, err := signer.SignPermit(ctx, request, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	wm.logger.Info("Signed token permit",
+		zap.String("chain", normalizedChain),
+		zap.String("type", signed.Type),
+		zap.String("token", signed.Token),
+		zap.String("owner", signed.Owner),
+		zap.String("spender", signed.Spender),
+		zap.String("value", signed.Value.String()))
+	return signed, nil
+}
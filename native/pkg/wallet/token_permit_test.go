@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/wallet/chain"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSignTokenPermit(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	owner := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	request := chain.PermitRequest{
+		Type:        chain.PermitTypeEIP2612,
+		Token:       "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		Owner:       owner,
+		Spender:     "0x2222222222222222222222222222222222222222",
+		Amount:      "10",
+		ValidBefore: 1_900_000_000,
+	}
+	ctx := context.Background()
+
+	wm := NewWalletManager()
+	_, err = wm.SignTokenPermit(ctx, "ethereum", request)
+	require.ErrorIs(t, err, ErrWalletLocked)
+
+	unlockForTest(wm, "wallet-ready")
+	wm.currentWalletData.ChainData = map[string]*ChainSpecificData{
+		"ethereum": {Address: owner, PrivateKey: hex.EncodeToString(crypto.FromECDSA(key))},
+	}
+
+	// A token without permit functions: only decimals() answers
+	eth := chain.NewETHChain(nil, zap.NewNop())
+	eth.SetPreflight(func(_ context.Context, call chain.EVMCall) (string, error) {
+		if call.Data == "0x313ce567" {
+			return "0x0000000000000000000000000000000000000000000000000000000000000012", nil
+		}
+		return "", errors.New("execution reverted")
+	})
+	wm.chainFactory.RegisterChain("ethereum", eth)
+
+	_, err = wm.SignTokenPermit(ctx, "eth", request)
+	require.ErrorIs(t, err, chain.ErrPermitNotSupported)
+
+	other := request
+	other.Owner = "0x1111111111111111111111111111111111111111"
+	_, err = wm.SignTokenPermit(ctx, "ethereum", other)
+	require.ErrorIs(t, err, ErrOwnerNotInWallet)
+
+	_, err = wm.SignTokenPermit(ctx, "solana", request)
+	require.ErrorContains(t, err, "only supported on EVM chains")
+
+	self := request
+	self.Spender = owner
+	_, err = wm.SignTokenPermit(ctx, "ethereum", self)
+	require.ErrorContains(t, err, "security validation failed")
+}
@@ -88,7 +88,11 @@ func ConfirmTransaction(ctx context.Context, chainName, txHash string, requiredC
 	}
 
 	// Create cache key
-	cacheKey := strings.ToLower(chainName) + ":" + strings.ToLower(txHash)
+	cacheKey := NormalizeChain(chainName) + ":" + strings.ToLower(txHash)
+	if commitment, ok := chain.CommitmentFromContext(ctx); ok {
+		// Statuses checked at different commitment levels must not be shared
+		cacheKey += ":" + commitment
+	}
 
 	// Check cache first
 	if cached, found := transactionCache.Get(cacheKey); found {
@@ -132,7 +136,7 @@ func SendTransaction(from, to, amount, token, chain string) (string, error) {
 	if strings.ToUpper(token) != "ETH" && token != "" {
 		return "", errors.New("unsupported token: only ETH is supported")
 	}
-	if strings.ToUpper(chain) != "ETH" && chain != "" {
+	if chain != "" && NormalizeChain(chain) != "ethereum" {
 		return "", errors.New("unsupported chain: only ETH is supported")
 	}
 	// TODO: Integrate with blockchain node or provider
@@ -0,0 +1,360 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Risk levels attached to decoded transaction intents
+const (
+	RiskLevelLow    = "low"
+	RiskLevelMedium = "medium"
+	RiskLevelHigh   = "high"
+)
+
+// Risk flags explaining a raised risk level
+const (
+	RiskFlagUnlimitedApproval = "unlimited_approval"
+	RiskFlagApprovalForAll    = "approval_for_all"
+	RiskFlagUnknownContract   = "unknown_contract"
+	RiskFlagValueToContract   = "value_to_unknown_contract"
+)
+
+// TransactionIntent is a human-readable interpretation of raw EVM transaction fields
+type TransactionIntent struct {
+	Action       string   `json:"action"`             // one of the TxCategory* values
+	Summary      string   `json:"summary"`            // e.g. "Swap 1 ETH for USDC on Uniswap"
+	Protocol     string   `json:"protocol,omitempty"` // known router or contract name
+	Method       string   `json:"method,omitempty"`   // 4-byte selector of the call
+	TokenSymbols []string `json:"token_symbols,omitempty"`
+	RiskLevel    string   `json:"risk_level"`
+	RiskFlags    []string `json:"risk_flags,omitempty"`
+	Recipient    string   `json:"recipient,omitempty"` // who receives the value or tokens of a transfer
+}
+
+// knownToken is a token contract the decoder can name and scale. It doubles as
+// the bundled token list behind LookupTokenMetadata.
+type knownToken struct {
+	symbol   string
+	decimals int
+	chain    string
+}
+
+// Contracts recognised by DecodeTransactionIntent, keyed by lowercase address
+var (
+	knownProtocols = map[string]string{
+		"0x7a250d5630b4cf539739df2c5dacb4c659f2488d": "Uniswap",     // Uniswap V2 Router02
+		"0xe592427a0aece92de3edee1f18e0157c05861564": "Uniswap",     // Uniswap V3 SwapRouter
+		"0x68b3465833fb72a70ecdf485e0e4c7bd8665fc45": "Uniswap",     // Uniswap V3 SwapRouter02
+		"0x3fc91a3afd70395cd496c647d5a6cc9d4b2b7fad": "Uniswap",     // Universal Router
+		"0x10ed43c718714eb63d5aa57b78b54704e256024e": "PancakeSwap", // PancakeSwap V2 Router
+		"0x1111111254eeb25477b68fb85ed929f73a960582": "1inch",       // 1inch Aggregation Router V5
+		"0xd9e1ce17f2641f24ae83637ab66a2cca9c378b9f": "SushiSwap",   // SushiSwap Router
+	}
+
+	knownTokens = map[string]knownToken{
+		"0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2": {"WETH", 18, "ethereum"},
+		"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48": {"USDC", 6, "ethereum"},
+		"0xdac17f958d2ee523a2206206994597c13d831ec7": {"USDT", 6, "ethereum"},
+		"0x6b175474e89094c44da98b954eedeac495271d0f": {"DAI", 18, "ethereum"},
+		"0x2260fac5e5542a773aa44fbcfedf7c193bc2c599": {"WBTC", 8, "ethereum"},
+		"0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c": {"WBNB", 18, "bsc"},
+		"0x55d398326f99059ff775485246999027b3197955": {"USDT", 18, "bsc"},
+		"0x8ac76a51cc950d9822d68b83fe1ad97b32cd580d": {"USDC", 18, "bsc"},
+		"0xe9e7cea3dedca5984780bafc599bd69add087d56": {"BUSD", 18, "bsc"},
+		// SPL mints, keyed lowercase like the EVM contracts
+		"epjfwdd5aufqssqem2qn1xzybapc8g4weggkzwytdt1v": {"USDC", 6, "solana"},
+		"es9vmfrzacermjfrf4h2fyd4kconky11mcce8benwnyb": {"USDT", 6, "solana"},
+	}
+
+	// splMints spells the SPL mints in knownTokens as they are on chain; base58
+	// is case-sensitive, so only that spelling names the mint
+	splMints = map[string]string{
+		"epjfwdd5aufqssqem2qn1xzybapc8g4weggkzwytdt1v": "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+		"es9vmfrzacermjfrf4h2fyd4kconky11mcce8benwnyb": "Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB",
+	}
+
+	// unlimitedApprovalThreshold treats allowances of 2^255 and above as unlimited
+	unlimitedApprovalThreshold = new(big.Int).Lsh(big.NewInt(1), 255)
+)
+
+// Selectors whose arguments DecodeTransactionIntent unpacks
+const (
+	selectorTransfer                 = "0xa9059cbb"
+	selectorApprove                  = "0x095ea7b3"
+	selectorSetApprovalForAll        = "0xa22cb465"
+	selectorSwapExactETHForTokens    = "0x7ff36ab5"
+	selectorSwapExactETHForTokensFee = "0xb6f9de95"
+	selectorSwapExactTokensForETH    = "0x18cbafe5"
+	selectorSwapExactTokensForETHFee = "0x791ac947"
+	selectorSwapExactTokensForTokens = "0x38ed1739"
+	selectorSwapExactTokensForTokFee = "0x5c11d795"
+)
+
+// DecodeTransactionIntent interprets an eth_sendTransaction request on chainName.
+// value is the hex wei amount and data the hex calldata, both as sent by dApps.
+// Unrecognised calls still produce an intent, flagged as an unknown contract.
+func DecodeTransactionIntent(chainName, to, value, data string) *TransactionIntent {
+	native := NativeTokenSymbol(NormalizeChain(chainName))
+	if native == "" {
+		native = "ETH"
+	}
+	wei := parseHexBig(value)
+	protocol := knownProtocols[strings.ToLower(to)]
+	selector := methodSelector(data)
+
+	intent := &TransactionIntent{
+		Protocol:  protocol,
+		Method:    selector,
+		RiskLevel: RiskLevelLow,
+	}
+
+	if selector == "" {
+		intent.Action = TxCategoryTransfer
+		intent.Summary = fmt.Sprintf("Send %s %s to %s", formatUnits(wei, 18), native, shortAddress(to))
+		intent.TokenSymbols = []string{native}
+		intent.Recipient = to
+		return intent
+	}
+
+	args := splitWords(data)
+	switch selector {
+	case selectorTransfer:
+		token := tokenSymbol(to)
+		intent.Action = TxCategoryTokenTransfer
+		intent.Summary = fmt.Sprintf("Send %s %s to %s", tokenAmount(to, wordInt(args, 1)), token, shortAddress(wordAddress(args, 0)))
+		intent.TokenSymbols = []string{token}
+		intent.Recipient = wordAddress(args, 0)
+		return intent
+
+	case selectorApprove:
+		token := tokenSymbol(to)
+		spender := contractName(wordAddress(args, 0))
+		amount := wordInt(args, 1)
+		intent.Action = TxCategoryApproval
+		intent.TokenSymbols = []string{token}
+		if amount.Cmp(unlimitedApprovalThreshold) >= 0 {
+			intent.Summary = fmt.Sprintf("Approve %s to spend unlimited %s", spender, token)
+			intent.raise(RiskLevelHigh, RiskFlagUnlimitedApproval)
+		} else {
+			intent.Summary = fmt.Sprintf("Approve %s to spend %s %s", spender, tokenAmount(to, amount), token)
+		}
+		return intent
+
+	case selectorSetApprovalForAll:
+		operator := contractName(wordAddress(args, 0))
+		intent.Action = TxCategoryApproval
+		if wordInt(args, 1).Sign() != 0 {
+			intent.Summary = fmt.Sprintf("Allow %s to transfer all NFTs in collection %s", operator, shortAddress(to))
+			intent.raise(RiskLevelHigh, RiskFlagApprovalForAll)
+		} else {
+			intent.Summary = fmt.Sprintf("Revoke %s access to NFTs in collection %s", operator, shortAddress(to))
+		}
+		return intent
+
+	case selectorSwapExactETHForTokens, selectorSwapExactETHForTokensFee:
+		out := tokenSymbol(lastPathToken(args, 1))
+		intent.Action = TxCategorySwap
+		intent.Summary = swapSummary(formatUnits(wei, 18)+" "+native, out, protocol)
+		intent.TokenSymbols = []string{native, out}
+
+	case selectorSwapExactTokensForETH, selectorSwapExactTokensForETHFee,
+		selectorSwapExactTokensForTokens, selectorSwapExactTokensForTokFee:
+		path := pathTokens(args, 2)
+		in, out := "", native
+		if len(path) > 0 {
+			in = path[0]
+			if selector == selectorSwapExactTokensForTokens || selector == selectorSwapExactTokensForTokFee {
+				out = tokenSymbol(path[len(path)-1])
+			}
+		}
+		intent.Action = TxCategorySwap
+		intent.Summary = swapSummary(tokenAmount(in, wordInt(args, 0))+" "+tokenSymbol(in), out, protocol)
+		intent.TokenSymbols = []string{tokenSymbol(in), out}
+
+	default:
+		intent.Action = classifyBySelector(selector)
+		if intent.Action == "" {
+			intent.Action = TxCategoryContractCall
+		}
+		target := contractName(to)
+		if intent.Action == TxCategorySwap {
+			intent.Summary = "Swap tokens on " + target
+		} else {
+			intent.Summary = fmt.Sprintf("Call %s on %s", selector, target)
+		}
+		if wei.Sign() > 0 {
+			intent.Summary += fmt.Sprintf(" with %s %s", formatUnits(wei, 18), native)
+		}
+	}
+
+	if protocol == "" {
+		intent.raise(RiskLevelMedium, RiskFlagUnknownContract)
+		if wei.Sign() > 0 {
+			intent.raise(RiskLevelMedium, RiskFlagValueToContract)
+		}
+	}
+	return intent
+}
+
+// raise adds flag and lifts the risk level to at least level
+func (i *TransactionIntent) raise(level, flag string) {
+	i.RiskFlags = append(i.RiskFlags, flag)
+	if riskRank(level) > riskRank(i.RiskLevel) {
+		i.RiskLevel = level
+	}
+}
+
+func riskRank(level string) int {
+	switch level {
+	case RiskLevelHigh:
+		return 2
+	case RiskLevelMedium:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func swapSummary(in, out, protocol string) string {
+	summary := fmt.Sprintf("Swap %s for %s", in, out)
+	if protocol != "" {
+		summary += " on " + protocol
+	}
+	return summary
+}
+
+// splitWords returns the 32-byte ABI words following the selector
+func splitWords(data string) [][]byte {
+	raw := common.FromHex(data)
+	if len(raw) < 4 {
+		return nil
+	}
+	raw = raw[4:]
+	words := make([][]byte, 0, len(raw)/32)
+	for len(raw) >= 32 {
+		words = append(words, raw[:32])
+		raw = raw[32:]
+	}
+	return words
+}
+
+func wordInt(words [][]byte, i int) *big.Int {
+	if i >= len(words) {
+		return new(big.Int)
+	}
+	return new(big.Int).SetBytes(words[i])
+}
+
+func wordAddress(words [][]byte, i int) string {
+	if i >= len(words) {
+		return ""
+	}
+	return common.BytesToAddress(words[i][12:]).Hex()
+}
+
+// pathTokens decodes the dynamic address[] whose offset is stored in word i
+func pathTokens(words [][]byte, i int) []string {
+	offset := wordInt(words, i)
+	if !offset.IsInt64() || offset.Int64()%32 != 0 {
+		return nil
+	}
+	start := int(offset.Int64() / 32)
+	length := wordInt(words, start)
+	if !length.IsInt64() || start+1+int(length.Int64()) > len(words) {
+		return nil
+	}
+	path := make([]string, 0, length.Int64())
+	for j := 0; j < int(length.Int64()); j++ {
+		path = append(path, wordAddress(words, start+1+j))
+	}
+	return path
+}
+
+func lastPathToken(words [][]byte, i int) string {
+	path := pathTokens(words, i)
+	if len(path) == 0 {
+		return ""
+	}
+	return path[len(path)-1]
+}
+
+// knownTokenAddress returns the address of the knownTokens entry keyed key as
+// it is spelled on chain
+func knownTokenAddress(key string) string {
+	if mint, ok := splMints[key]; ok {
+		return mint
+	}
+	return key
+}
+
+// lookupKnownToken returns the knownTokens entry at address. EVM addresses
+// match in any case, but an SPL mint only matches when spelled exactly: a
+// mint in another case names a different, unknown account.
+func lookupKnownToken(address string) (knownToken, bool) {
+	key := strings.ToLower(strings.TrimSpace(address))
+	if mint, ok := splMints[key]; ok && strings.TrimSpace(address) != mint {
+		return knownToken{}, false
+	}
+	token, ok := knownTokens[key]
+	return token, ok
+}
+
+// tokenSymbol names a token contract, falling back to its shortened address
+func tokenSymbol(address string) string {
+	if token, ok := lookupKnownToken(address); ok {
+		return token.symbol
+	}
+	if address == "" {
+		return "unknown token"
+	}
+	return shortAddress(address)
+}
+
+// tokenAmount scales raw units by the token's decimals when they are known
+func tokenAmount(address string, units *big.Int) string {
+	if token, ok := lookupKnownToken(address); ok {
+		return formatUnits(units, token.decimals)
+	}
+	return units.String() + " units of"
+}
+
+func contractName(address string) string {
+	if name, ok := knownProtocols[strings.ToLower(address)]; ok {
+		return name
+	}
+	return shortAddress(address)
+}
+
+func shortAddress(address string) string {
+	if len(address) <= 12 {
+		return address
+	}
+	return address[:6] + "…" + address[len(address)-4:]
+}
+
+func parseHexBig(value string) *big.Int {
+	v := strings.TrimSpace(value)
+	if v == "" {
+		return new(big.Int)
+	}
+	n, ok := new(big.Int).SetString(strings.TrimPrefix(strings.ToLower(v), "0x"), 16)
+	if !ok {
+		return new(big.Int)
+	}
+	return n
+}
+
+// formatUnits renders units with the given decimals and no trailing zeros
+func formatUnits(units *big.Int, decimals int) string {
+	if decimals == 0 {
+		return units.String()
+	}
+	r := new(big.Rat).SetFrac(units, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	s := strings.TrimRight(r.FloatString(decimals), "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	intentTestUniswapV2 = "0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D"
+	intentTestWETH      = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+	intentTestUSDC      = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	intentTestRecipient = "0x742d35Cc6634C0532925a3b8D4C2B79C2b86A7A8"
+)
+
+// abiWord left-pads a hex value (with or without 0x) to one 32-byte word
+func abiWord(hex string) string {
+	hex = strings.TrimPrefix(strings.ToLower(hex), "0x")
+	return strings.Repeat("0", 64-len(hex)) + hex
+}
+
+func TestDecodeTransactionIntentNativeTransfer(t *testing.T) {
+	intent := DecodeTransactionIntent("ethereum", intentTestRecipient, "0xde0b6b3a7640000", "")
+	assert.Equal(t, TxCategoryTransfer, intent.Action)
+	assert.Equal(t, "Send 1 ETH to 0x742d…A7A8", intent.Summary)
+	assert.Equal(t, RiskLevelLow, intent.RiskLevel)
+}
+
+func TestDecodeTransactionIntentSwapExactETHForTokens(t *testing.T) {
+	data := "0x7ff36ab5" +
+		abiWord("1") + // amountOutMin
+		abiWord("80") + // offset of path
+		abiWord(intentTestRecipient) +
+		abiWord("ffffffff") + // deadline
+		abiWord("2") +
+		abiWord(intentTestWETH) +
+		abiWord(intentTestUSDC)
+
+	intent := DecodeTransactionIntent("eth", intentTestUniswapV2, "0xde0b6b3a7640000", data)
+	assert.Equal(t, TxCategorySwap, intent.Action)
+	assert.Equal(t, "Swap 1 ETH for USDC on Uniswap", intent.Summary)
+	assert.Equal(t, "Uniswap", intent.Protocol)
+	assert.Equal(t, []string{"ETH", "USDC"}, intent.TokenSymbols)
+	assert.Equal(t, RiskLevelLow, intent.RiskLevel)
+	assert.Empty(t, intent.RiskFlags)
+}
+
+func TestDecodeTransactionIntentApprovals(t *testing.T) {
+	unlimited := DecodeTransactionIntent("ethereum", intentTestUSDC, "", "0x095ea7b3"+abiWord(intentTestUniswapV2)+strings.Repeat("f", 64))
+	assert.Equal(t, TxCategoryApproval, unlimited.Action)
+	assert.Equal(t, "Approve Uniswap to spend unlimited USDC", unlimited.Summary)
+	assert.Equal(t, RiskLevelHigh, unlimited.RiskLevel)
+	assert.Contains(t, unlimited.RiskFlags, RiskFlagUnlimitedApproval)
+
+	bounded := DecodeTransactionIntent("ethereum", intentTestUSDC, "", "0x095ea7b3"+abiWord(intentTestUniswapV2)+abiWord("5f5e100"))
+	assert.Equal(t, "Approve Uniswap to spend 100 USDC", bounded.Summary)
+	assert.Equal(t, RiskLevelLow, bounded.RiskLevel)
+
+	forAll := DecodeTransactionIntent("ethereum", intentTestRecipient, "", "0xa22cb465"+abiWord(intentTestRecipient)+abiWord("1"))
+	assert.Equal(t, RiskLevelHigh, forAll.RiskLevel)
+	assert.Contains(t, forAll.RiskFlags, RiskFlagApprovalForAll)
+}
+
+func TestDecodeTransactionIntentUnknownContract(t *testing.T) {
+	intent := DecodeTransactionIntent("bsc", intentTestRecipient, "0x1", "0xdeadbeef"+abiWord("1"))
+	assert.Equal(t, TxCategoryContractCall, intent.Action)
+	assert.Equal(t, "Call 0xdeadbeef on 0x742d…A7A8 with 0.000000000000000001 BNB", intent.Summary)
+	assert.Equal(t, RiskLevelMedium, intent.RiskLevel)
+	assert.Equal(t, []string{RiskFlagUnknownContract, RiskFlagValueToContract}, intent.RiskFlags)
+}
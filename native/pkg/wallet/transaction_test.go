@@ -110,6 +110,7 @@ func TestConfirmTransaction_UnsupportedChain(t *testing.T) {
 }
 
 func TestConfirmTransaction_ValidRequest(t *testing.T) {
+	t.Setenv("RUN_MODE", "test")
 	factory := chain.NewChainFactory()
 	ctx := context.Background()
 
@@ -143,6 +144,7 @@ func TestConfirmTransaction_ValidRequest(t *testing.T) {
 }
 
 func TestConfirmTransaction_DefaultConfirmations(t *testing.T) {
+	t.Setenv("RUN_MODE", "test")
 	factory := chain.NewChainFactory()
 	ctx := context.Background()
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/algonius/algonius-wallet/native/pkg/config"
 	bip39 "github.com/tyler-smith/go-bip39"
 )
 
@@ -65,31 +66,14 @@ func ValidateChain(chain string) error {
 		return errors.New("chain cannot be empty")
 	}
 
-	// Normalize chain name
-	normalizedChain := strings.ToLower(strings.TrimSpace(chain))
-	
-	supportedChains := []string{"ethereum", "eth", "bsc", "binance", "solana", "sol"}
-	for _, supported := range supportedChains {
-		if normalizedChain == supported {
-			return nil
-		}
+	if !config.IsSupportedChain(chain) {
+		return fmt.Errorf("unsupported chain: %s (supported: ethereum, bsc, solana)", chain)
 	}
-
-	return fmt.Errorf("unsupported chain: %s (supported: ethereum, bsc, solana)", chain)
+	return nil
 }
 
-// NormalizeChain normalizes chain names to standard format
+// NormalizeChain normalizes chain names to standard format: ethereum, bsc or
+// solana. See config.NormalizeChain for the accepted aliases.
 func NormalizeChain(chain string) string {
-	normalizedChain := strings.ToLower(strings.TrimSpace(chain))
-	
-	switch normalizedChain {
-	case "eth", "ethereum":
-		return "ethereum"
-	case "bsc", "binance":
-		return "bsc"
-	case "sol", "solana":
-		return "solana"
-	default:
-		return normalizedChain
-	}
-}
\ No newline at end of file
+	return config.NormalizeChain(chain)
+}
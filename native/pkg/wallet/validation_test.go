@@ -178,6 +178,16 @@ func TestValidateChain(t *testing.T) {
 			chain:     "  ethereum  ",
 			expectErr: false,
 		},
+		{
+			name:      "binance smart chain",
+			chain:     "Binance Smart Chain",
+			expectErr: false,
+		},
+		{
+			name:      "sol",
+			chain:     "sol",
+			expectErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -234,6 +244,51 @@ func TestNormalizeChain(t *testing.T) {
 			chain:    "binance",
 			expected: "bsc",
 		},
+		{
+			name:     "BINANCE uppercase",
+			chain:    "BINANCE",
+			expected: "bsc",
+		},
+		{
+			name:     "binance smart chain",
+			chain:    "binance smart chain",
+			expected: "bsc",
+		},
+		{
+			name:     "Binance Smart Chain title case",
+			chain:    "Binance Smart Chain",
+			expected: "bsc",
+		},
+		{
+			name:     "binance smart chain with other separators",
+			chain:    "binance_smart-chain",
+			expected: "bsc",
+		},
+		{
+			name:     "binance smart chain with extra spaces",
+			chain:    " binance  smart   chain ",
+			expected: "bsc",
+		},
+		{
+			name:     "solana",
+			chain:    "solana",
+			expected: "solana",
+		},
+		{
+			name:     "SOLANA uppercase",
+			chain:    "SOLANA",
+			expected: "solana",
+		},
+		{
+			name:     "sol",
+			chain:    "sol",
+			expected: "solana",
+		},
+		{
+			name:     "SOL uppercase",
+			chain:    "SOL",
+			expected: "solana",
+		},
 		{
 			name:     "chain with spaces",
 			chain:    "  ethereum  ",
@@ -244,6 +299,11 @@ func TestNormalizeChain(t *testing.T) {
 			chain:    "bitcoin",
 			expected: "bitcoin",
 		},
+		{
+			name:     "unsupported chain is lower-cased",
+			chain:    " Polygon ",
+			expected: "polygon",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// MaxWalletLabelLength is the longest label SetWalletLabel accepts, in characters
+const MaxWalletLabelLength = 64
+
+// ErrWalletNotFound is returned when no stored wallet has the requested address
+var ErrWalletNotFound = errors.New("wallet not found")
+
+// ErrInvalidWalletLabel is returned by SetWalletLabel for labels it does not accept
+var ErrInvalidWalletLabel = errors.New("invalid wallet label")
+
+// StoredWallet describes a persisted wallet without any key material
+type StoredWallet struct {
+	Address   string   `json:"address"`
+	Label     string   `json:"label,omitempty"`
+	Chains    []string `json:"chains"`
+	CreatedAt int64    `json:"created_at"`
+	LastUsed  int64    `json:"last_used"`
+	WatchOnly bool     `json:"watch_only"`
+	Hardware  bool     `json:"hardware"`
+	// Accounts is the number of additional accounts found by DiscoverAccounts
+	Accounts int `json:"accounts"`
+	// Selected marks the wallet GetCurrentWallet, sending and signing use
+	Selected bool `json:"selected"`
+	// Unlocked reports whether the wallet's keys are loaded this session
+	Unlocked bool `json:"unlocked"`
+}
+
+// newStoredWallet copies the public fields of an encrypted wallet record
+func newStoredWallet(data *EncryptedWalletData) *StoredWallet {
+	chains := make([]string, 0, len(data.Chains))
+	for chainName, supported := range data.Chains {
+		if supported {
+			chains = append(chains, chainName)
+		}
+	}
+	sort.Strings(chains)
+	return &StoredWallet{
+		Address:   data.Address,
+		Label:     data.Label,
+		Chains:    chains,
+		CreatedAt: data.CreatedAt,
+		LastUsed:  data.LastUsed,
+		WatchOnly: data.WatchOnly,
+		Hardware:  data.Hardware,
+		Accounts:  len(data.Accounts),
+	}
+}
+
+// ListWallets returns every wallet in the state store, oldest first, marking
+// the selected one. It reads only the unencrypted metadata, so the wallet does
+// not need to be unlocked.
+func (wm *WalletManager) ListWallets(ctx context.Context) ([]*StoredWallet, error) {
+	records, err := wm.loadWalletRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wallets := make([]*StoredWallet, 0, len(records))
+	wm.walletMu.RLock()
+	for _, record := range records {
+		wallet := newStoredWallet(record.data)
+		wallet.Selected = record.data.Address == wm.selectedAddress
+		wallet.Unlocked = wm.unlockedWallets[record.data.Address] != nil
+		wallets = append(wallets, wallet)
+	}
+	wm.walletMu.RUnlock()
+	sort.SliceStable(wallets, func(i, j int) bool {
+		return wallets[i].CreatedAt < wallets[j].CreatedAt
+	})
+	return wallets, nil
+}
+
+// SetWalletLabel assigns a human-friendly label to the stored wallet with
+// address and persists it with the wallet. An empty label removes it.
+func (wm *WalletManager) SetWalletLabel(ctx context.Context, address, label string) (*StoredWallet, error) {
+	label = strings.TrimSpace(label)
+	if err := validateWalletLabel(label); err != nil {
+		return nil, err
+	}
+
+	records, err := wm.loadWalletRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if !sameAddress(record.data.Address, address) {
+			continue
+		}
+		record.data.Label = label
+		jsonData, err := json.MarshalIndent(record.data, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal wallet data: %w", err)
+		}
+		if err := wm.store.Put(ctx, storage.NamespaceWallets, record.key, jsonData); err != nil {
+			return nil, fmt.Errorf("failed to write wallet file: %w", err)
+		}
+		wm.logger.Info("Wallet label updated", zap.String("address", record.data.Address))
+		return newStoredWallet(record.data), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrWalletNotFound, address)
+}
+
+// validateWalletLabel rejects labels that are too long or contain control characters
+func validateWalletLabel(label string) error {
+	if utf8.RuneCountInString(label) > MaxWalletLabelLength {
+		return fmt.Errorf("%w: must be at most %d characters", ErrInvalidWalletLabel, MaxWalletLabelLength)
+	}
+	for _, r := range label {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("%w: must not contain control characters", ErrInvalidWalletLabel)
+		}
+	}
+	return nil
+}
+
+// sameAddress compares addresses, ignoring case for hex addresses only since
+// base58 addresses are case-sensitive
+func sameAddress(a, b string) bool {
+	if strings.HasPrefix(a, "0x") || strings.HasPrefix(a, "0X") {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// walletRecord is an encrypted wallet together with its state store key
+type walletRecord struct {
+	key  string
+	data *EncryptedWalletData
+}
+
+// loadWalletRecords reads every wallet record in storage.NamespaceWallets
+func (wm *WalletManager) loadWalletRecords(ctx context.Context) ([]walletRecord, error) {
+	keys, err := wm.store.List(ctx, storage.NamespaceWallets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallets: %w", err)
+	}
+	records := make([]walletRecord, 0, len(keys))
+	for _, key := range keys {
+		jsonData, err := wm.store.Get(ctx, storage.NamespaceWallets, key)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read wallet %s: %w", key, err)
+		}
+		var data EncryptedWalletData
+		if err := json.Unmarshal(jsonData, &data); err != nil || data.Address == "" {
+			wm.logger.Warn("Skipping unreadable wallet record", zap.String("key", key), zap.Error(err))
+			continue
+		}
+		records = append(records, walletRecord{key: key, data: &data})
+	}
+	return records, nil
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+)
+
+func TestListWalletsAndSetLabel(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStateStore()
+	wm := NewWalletManagerWithStore(store, nil)
+
+	wallets, err := wm.ListWallets(ctx)
+	if err != nil || len(wallets) != 0 {
+		t.Fatalf("expected no wallets in an empty store, got %v, %v", wallets, err)
+	}
+
+	address, _, _, err := wm.ImportWallet(ctx, "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "password123", "ethereum", "")
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+
+	wallet, err := wm.SetWalletLabel(ctx, strings.ToLower(address), "  Trading  ")
+	if err != nil {
+		t.Fatalf("set label failed: %v", err)
+	}
+	if wallet.Label != "Trading" || wallet.Address != address {
+		t.Errorf("unexpected labelled wallet %+v", wallet)
+	}
+
+	// Labels persist with the wallet and are visible without unlocking
+	wallets, err = NewWalletManagerWithStore(store, nil).ListWallets(ctx)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(wallets) != 1 {
+		t.Fatalf("expected one wallet, got %d", len(wallets))
+	}
+	got := wallets[0]
+	if got.Label != "Trading" || got.CreatedAt == 0 || got.WatchOnly || got.Hardware {
+		t.Errorf("unexpected wallet %+v", got)
+	}
+	if strings.Join(got.Chains, ",") != "bsc,ethereum" {
+		t.Errorf("unexpected chains %v", got.Chains)
+	}
+
+	if _, err := wm.SetWalletLabel(ctx, "0x0000000000000000000000000000000000000001", "x"); !errors.Is(err, ErrWalletNotFound) {
+		t.Errorf("expected ErrWalletNotFound, got %v", err)
+	}
+	if _, err := wm.SetWalletLabel(ctx, address, strings.Repeat("a", MaxWalletLabelLength+1)); !errors.Is(err, ErrInvalidWalletLabel) {
+		t.Error("expected error for an overlong label")
+	}
+	if _, err := wm.SetWalletLabel(ctx, address, "bad\nlabel"); !errors.Is(err, ErrInvalidWalletLabel) {
+		t.Error("expected error for a label with control characters")
+	}
+
+	if wallet, err := wm.SetWalletLabel(ctx, address, ""); err != nil || wallet.Label != "" {
+		t.Errorf("expected label to be cleared, got %+v, %v", wallet, err)
+	}
+}
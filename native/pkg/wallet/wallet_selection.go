@@ -0,0 +1,219 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// legacyWalletStoreKey is the key the single wallet was stored under before
+// wallets were keyed by address. It is migrated on first load.
+const legacyWalletStoreKey = "wallet"
+
+// selectedWalletStoreKey is the key of the selected wallet's address within
+// storage.NamespaceSettings
+const selectedWalletStoreKey = "selected_wallet"
+
+// migrateLegacyWallet moves a wallet stored under legacyWalletStoreKey to its
+// address key. A wallet already stored under that address wins.
+func (wm *WalletManager) migrateLegacyWallet(ctx context.Context) error {
+	jsonData, err := wm.store.Get(ctx, storage.NamespaceWallets, legacyWalletStoreKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read legacy wallet: %w", err)
+	}
+	var data EncryptedWalletData
+	if err := json.Unmarshal(jsonData, &data); err != nil || data.Address == "" {
+		return fmt.Errorf("failed to parse legacy wallet: %v", err)
+	}
+	if _, err := wm.store.Get(ctx, storage.NamespaceWallets, data.Address); errors.Is(err, storage.ErrNotFound) {
+		if err := wm.store.Put(ctx, storage.NamespaceWallets, data.Address, jsonData); err != nil {
+			return fmt.Errorf("failed to migrate legacy wallet: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to read wallet %s: %w", data.Address, err)
+	}
+	if err := wm.store.Delete(ctx, storage.NamespaceWallets, legacyWalletStoreKey); err != nil {
+		return fmt.Errorf("failed to remove legacy wallet: %w", err)
+	}
+	wm.logger.Info("Migrated legacy wallet to per-address storage", zap.String("address", data.Address))
+	return nil
+}
+
+// restoreSelectedWallet selects the wallet that was selected when the host
+// last ran, falling back to the most recently used one
+func (wm *WalletManager) restoreSelectedWallet(ctx context.Context) error {
+	if err := wm.migrateLegacyWallet(ctx); err != nil {
+		wm.logger.Warn("Failed to migrate legacy wallet", zap.Error(err))
+	}
+	records, err := wm.loadWalletRecords(ctx)
+	if err != nil {
+		return err
+	}
+	persisted, err := wm.store.Get(ctx, storage.NamespaceSettings, selectedWalletStoreKey)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("failed to read selected wallet: %w", err)
+	}
+	var selected *EncryptedWalletData
+	for _, record := range records {
+		if record.data.Address == string(persisted) {
+			wm.setSelectedAddress(record.data.Address)
+			return nil
+		}
+		if selected == nil || record.data.LastUsed > selected.LastUsed ||
+			(record.data.LastUsed == selected.LastUsed && record.data.CreatedAt > selected.CreatedAt) {
+			selected = record.data
+		}
+	}
+	if selected != nil {
+		wm.setSelectedAddress(selected.Address)
+	}
+	return nil
+}
+
+// findWalletRecord returns the stored wallet with address
+func (wm *WalletManager) findWalletRecord(ctx context.Context, address string) (*walletRecord, error) {
+	records, err := wm.loadWalletRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		if sameAddress(records[i].data.Address, address) {
+			return &records[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrWalletNotFound, address)
+}
+
+// SwitchWallet selects the stored wallet with address for GetCurrentWallet,
+// sending and signing. A wallet unlocked earlier in this session stays
+// unlocked; any other has to be unlocked with UnlockWallet before it signs.
+func (wm *WalletManager) SwitchWallet(ctx context.Context, address string) error {
+	if address == "" {
+		return errors.New("address is required")
+	}
+	if wm.IsFrozen() {
+		return ErrWalletFrozen
+	}
+	record, err := wm.findWalletRecord(ctx, address)
+	if err != nil {
+		return err
+	}
+	previous := wm.selectedWalletAddress()
+	if err := wm.selectWallet(record.data); err != nil {
+		return err
+	}
+	// Warm the caches for the newly selected wallet's addresses
+	if previous != record.data.Address {
+		wm.stopCacheWarmup()
+		if wm.IsUnlocked() {
+			wm.startCacheWarmup()
+		}
+	}
+	wm.logger.Info("Switched wallet",
+		zap.String("address", record.data.Address),
+		zap.Bool("unlocked", wm.IsUnlocked()))
+	return nil
+}
+
+// selectWallet makes data the selected wallet, restoring its keys when it was
+// unlocked before, and persists the selection so it survives a restart
+func (wm *WalletManager) selectWallet(data *EncryptedWalletData) error {
+	data.LastUsed = time.Now().Unix()
+	if err := wm.saveWallet(data); err != nil {
+		return fmt.Errorf("failed to save wallet: %w", err)
+	}
+	if err := wm.persistSelectedWallet(data.Address); err != nil {
+		return err
+	}
+
+	wm.walletMu.Lock()
+	defer wm.walletMu.Unlock()
+	wm.selectedAddress = data.Address
+	wm.currentWallet = &WalletStatus{
+		Address:   data.Address,
+		PublicKey: data.PublicKey,
+		Chains:    data.Chains,
+		LastUsed:  data.LastUsed,
+	}
+	wm.currentWalletData = wm.unlockedWallets[data.Address]
+	wm.isUnlocked = wm.currentWalletData != nil
+	return nil
+}
+
+// selectedWalletAddress returns the address of the selected wallet
+func (wm *WalletManager) selectedWalletAddress() string {
+	wm.walletMu.RLock()
+	defer wm.walletMu.RUnlock()
+	return wm.selectedAddress
+}
+
+// setSelectedAddress selects the wallet with address without unlocking it
+func (wm *WalletManager) setSelectedAddress(address string) {
+	wm.walletMu.Lock()
+	defer wm.walletMu.Unlock()
+	wm.selectedAddress = address
+}
+
+// persistSelectedWallet records address as the selected wallet
+func (wm *WalletManager) persistSelectedWallet(address string) error {
+	if err := wm.store.Put(context.Background(), storage.NamespaceSettings, selectedWalletStoreKey, []byte(address)); err != nil {
+		return fmt.Errorf("failed to save selected wallet: %w", err)
+	}
+	return nil
+}
+
+// setUnlockedWallet keeps the decrypted keys of a wallet for this session and
+// selects it
+func (wm *WalletManager) setUnlockedWallet(data *DecryptedWalletData) {
+	wm.walletMu.Lock()
+	defer wm.walletMu.Unlock()
+	if wm.unlockedWallets == nil {
+		wm.unlockedWallets = make(map[string]*DecryptedWalletData)
+	}
+	wm.unlockedWallets[data.Address] = data
+	wm.selectedAddress = data.Address
+	wm.currentWalletData = data
+	wm.isUnlocked = true
+}
+
+// checkSelectedWallet refuses from addresses that belong to a stored wallet
+// other than the selected one, whose keys are not the ones that would sign
+func (wm *WalletManager) checkSelectedWallet(ctx context.Context, from string) error {
+	records, err := wm.loadWalletRecords(ctx)
+	if err != nil {
+		return err
+	}
+	selectedAddress := wm.selectedWalletAddress()
+	for _, record := range records {
+		if sameAddress(record.data.Address, selectedAddress) {
+			continue
+		}
+		if walletOwns(record.data, from) {
+			return fmt.Errorf("%s belongs to wallet %s, which is not the selected wallet; switch to it first", from, record.data.Address)
+		}
+	}
+	return nil
+}
+
+// walletOwns reports whether address is the wallet's own address or one of
+// its discovered accounts
+func walletOwns(data *EncryptedWalletData, address string) bool {
+	if sameAddress(data.Address, address) {
+		return true
+	}
+	for _, account := range data.Accounts {
+		if sameAddress(account.Address, address) {
+			return true
+		}
+	}
+	return false
+}
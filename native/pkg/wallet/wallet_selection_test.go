@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: Apache-2.0
+package wallet
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/pkg/storage"
+)
+
+const (
+	testMnemonicA = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	testMnemonicB = "legal winner thank year wave sausage worth useful legal winner thank yellow"
+)
+
+func TestMigrateLegacyWallet(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStateStore()
+	address, _, _, err := NewWalletManagerWithStore(store, nil).ImportWallet(ctx, testMnemonicA, "password123", "ethereum", "")
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+
+	// Rewrite the wallet the way releases before per-address storage did
+	data, err := store.Get(ctx, storage.NamespaceWallets, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, storage.NamespaceWallets, address); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, storage.NamespaceSettings, selectedWalletStoreKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(ctx, storage.NamespaceWallets, legacyWalletStoreKey, data); err != nil {
+		t.Fatal(err)
+	}
+
+	wm := NewWalletManagerWithStore(store, nil)
+	keys, _ := store.List(ctx, storage.NamespaceWallets)
+	if len(keys) != 1 || keys[0] != address {
+		t.Fatalf("expected the wallet to move to its address key, got %v", keys)
+	}
+	if !wm.HasWallet() {
+		t.Fatal("expected the migrated wallet to be found")
+	}
+	if err := wm.UnlockWallet("", "password123"); err != nil {
+		t.Fatalf("unlock after migration failed: %v", err)
+	}
+	if got := wm.GetCurrentWallet(); got == nil || got.Address != address {
+		t.Errorf("expected %s to be selected, got %+v", address, got)
+	}
+}
+
+func TestSwitchWallet(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStateStore()
+	wm := NewWalletManagerWithStore(store, nil)
+	addressA, _, _, err := wm.ImportWallet(ctx, testMnemonicA, "password123", "ethereum", "")
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	addressB, _, _, err := wm.ImportWallet(ctx, testMnemonicB, "password456", "ethereum", "")
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if _, _, _, err := wm.ImportWallet(ctx, testMnemonicA, "password123", "ethereum", ""); err == nil {
+		t.Error("expected importing a stored wallet again to fail")
+	}
+
+	// The last imported wallet is selected and listed first
+	accounts, err := wm.GetAccounts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(accounts, ",") != addressB+","+addressA {
+		t.Errorf("unexpected accounts %v", accounts)
+	}
+
+	// Sending from a wallet other than the selected one is refused
+	if _, err := wm.SendTransaction(ctx, "ethereum", addressA, addressB, "0.1", "ETH"); err == nil || !strings.Contains(err.Error(), "not the selected wallet") {
+		t.Errorf("expected a send from the unselected wallet to fail, got %v", err)
+	}
+
+	// Both wallets were unlocked this session, so switching keeps them unlocked
+	if err := wm.SwitchWallet(ctx, strings.ToLower(addressA)); err != nil {
+		t.Fatalf("switch failed: %v", err)
+	}
+	if got := wm.GetCurrentWallet(); got.Address != addressA || !wm.IsUnlocked() {
+		t.Errorf("expected %s to be selected and unlocked, got %+v", addressA, got)
+	}
+	wallets, err := wm.ListWallets(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range wallets {
+		if w.Selected != (w.Address == addressA) || !w.Unlocked {
+			t.Errorf("unexpected wallet %+v", w)
+		}
+	}
+
+	if err := wm.SwitchWallet(ctx, "0x0000000000000000000000000000000000000001"); !errors.Is(err, ErrWalletNotFound) {
+		t.Errorf("expected ErrWalletNotFound, got %v", err)
+	}
+
+	// The selection survives a restart; other wallets unlock by address
+	restarted := NewWalletManagerWithStore(store, nil)
+	if err := restarted.UnlockWallet("", "password123"); err != nil {
+		t.Fatalf("unlock of the selected wallet failed: %v", err)
+	}
+	if got := restarted.GetCurrentWallet(); got.Address != addressA {
+		t.Errorf("expected %s to stay selected, got %s", addressA, got.Address)
+	}
+	if err := restarted.SwitchWallet(ctx, addressB); err != nil {
+		t.Fatalf("switch failed: %v", err)
+	}
+	if restarted.IsUnlocked() {
+		t.Error("expected a wallet not unlocked this session to stay locked")
+	}
+	if err := restarted.UnlockWallet(addressB, "password123"); err == nil {
+		t.Error("expected the other wallet's password to be rejected")
+	}
+	if err := restarted.UnlockWallet(addressB, "password456"); err != nil || !restarted.IsUnlocked() {
+		t.Fatalf("unlock by address failed: %v", err)
+	}
+
+	restarted.LockWallet()
+	if err := restarted.SwitchWallet(ctx, addressA); err != nil {
+		t.Fatal(err)
+	}
+	if restarted.IsUnlocked() {
+		t.Error("expected LockWallet to lock every wallet")
+	}
+}
+
+func TestWalletStateConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	wm := NewWalletManagerWithStore(storage.NewMemoryStateStore(), nil)
+	addressA, _, _, err := wm.ImportWallet(ctx, testMnemonicA, "password123", "ethereum", "")
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	addressB, _, _, err := wm.ImportWallet(ctx, testMnemonicB, "password456", "ethereum", "")
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+
+	// Native messaging and MCP calls unlock, lock, switch and list at the same time
+	var wg sync.WaitGroup
+	run := func(op func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 5; i++ {
+				op()
+			}
+		}()
+	}
+	run(func() { _ = wm.UnlockWallet(addressA, "password123") })
+	run(func() { _ = wm.UnlockWallet(addressB, "password456") })
+	run(wm.LockWallet)
+	run(func() { _ = wm.SwitchWallet(ctx, addressA) })
+	run(func() { _, _ = wm.ListWallets(ctx) })
+	run(func() { _, _ = wm.GetAccounts(ctx) })
+	run(func() { _, _ = wm.GetPrivateKeyForAddress(ctx, addressB) })
+	run(func() { _ = wm.IsUnlocked(); _ = wm.GetCurrentWallet() })
+	wg.Wait()
+
+	if _, err := wm.FreezeWallet(ctx, "test", "test"); err != nil {
+		t.Fatalf("freeze failed: %v", err)
+	}
+	if wm.IsUnlocked() {
+		t.Error("expected the freeze to lock every wallet")
+	}
+}
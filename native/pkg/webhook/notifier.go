@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"go.uber.org/zap"
+)
+
+// Headers sent with every delivery. The signature covers the timestamp and the
+// body, joined by a dot, so a captured request cannot be replayed with a new
+// timestamp; the delivery id stays the same across retries of one payload.
+const (
+	HeaderSignature  = "X-Algonius-Signature"
+	HeaderTimestamp  = "X-Algonius-Timestamp"
+	HeaderDeliveryID = "X-Algonius-Delivery"
+)
+
+// Notifier POSTs JSON payloads to webhook URLs, signing them with the
+// configured secret and retrying failed deliveries with exponential backoff
+type Notifier struct {
+	client      *http.Client
+	secret      []byte
+	maxAttempts int
+	retryDelay  time.Duration
+	timeout     time.Duration
+	logger      *zap.Logger
+}
+
+// NewNotifier creates a notifier from the callbacks configuration. Unset
+// values fall back to the defaults.
+func NewNotifier(cfg config.CallbacksConfig, logger *zap.Logger) *Notifier {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	n := &Notifier{
+		client:      &http.Client{},
+		secret:      []byte(cfg.Secret),
+		maxAttempts: cfg.MaxAttempts,
+		retryDelay:  cfg.RetryDelay,
+		timeout:     cfg.Timeout,
+		logger:      logger,
+	}
+	if n.maxAttempts <= 0 {
+		n.maxAttempts = config.DefaultCallbackMaxAttempts
+	}
+	if n.retryDelay <= 0 {
+		n.retryDelay = config.DefaultCallbackRetryDelay
+	}
+	if n.timeout <= 0 {
+		n.timeout = config.DefaultCallbackTimeout
+	}
+	return n
+}
+
+// ValidateURL checks that target is an absolute http or https URL
+func ValidateURL(target string) error {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("URL must use http or https, got %q", parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("URL has no host")
+	}
+	return nil
+}
+
+// Sign returns the value of the signature header for body sent at timestamp
+// (Unix seconds): "sha256=" followed by the hex HMAC-SHA256 of "timestamp.body"
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Deliver sends payload to target, retrying network errors, 429 and 5xx
+// responses until an attempt succeeds, the attempts are used up or ctx ends.
+// Other 4xx responses are not retried.
+func (n *Notifier) Deliver(ctx context.Context, target string, payload any) error {
+	if err := ValidateURL(target); err != nil {
+		return err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	deliveryID := newDeliveryID()
+
+	delay := n.retryDelay
+	for attempt := 1; ; attempt++ {
+		retryable, err := n.send(ctx, target, deliveryID, body)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= n.maxAttempts {
+			return fmt.Errorf("webhook delivery failed after %d attempt(s): %w", attempt, err)
+		}
+		n.logger.Warn("Webhook delivery failed, retrying",
+			zap.String("delivery_id", deliveryID),
+			zap.Int("attempt", attempt),
+			zap.Duration("retry_in", delay),
+			zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("webhook delivery cancelled after %d attempt(s): %w", attempt, err)
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// send makes a single delivery attempt and reports whether a failure is worth retrying
+func (n *Notifier) send(ctx context.Context, target, deliveryID string, body []byte) (bool, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(attemptCtx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderDeliveryID, deliveryID)
+	if len(n.secret) > 0 {
+		req.Header.Set(HeaderSignature, Sign(n.secret, timestamp, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+}
+
+// newDeliveryID returns a random identifier receivers can deduplicate retries by
+func newDeliveryID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingServer answers with the queued status codes, then 200
+type recordingServer struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (s *recordingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r)
+	s.bodies = append(s.bodies, body)
+	status := http.StatusOK
+	if len(s.statuses) > 0 {
+		status, s.statuses = s.statuses[0], s.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func newTestNotifier(secret string, attempts int) *Notifier {
+	return NewNotifier(config.CallbacksConfig{
+		Secret:      secret,
+		MaxAttempts: attempts,
+		RetryDelay:  time.Millisecond,
+		Timeout:     time.Second,
+	}, nil)
+}
+
+func TestNotifierSignsAndRetries(t *testing.T) {
+	recorder := &recordingServer{statuses: []int{http.StatusBadGateway, http.StatusTooManyRequests}}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	err := newTestNotifier("s3cret", 3).Deliver(context.Background(), server.URL, map[string]string{"status": "confirmed"})
+	require.NoError(t, err)
+	require.Len(t, recorder.requests, 3)
+
+	first, last := recorder.requests[0], recorder.requests[2]
+	assert.Equal(t, first.Header.Get(HeaderDeliveryID), last.Header.Get(HeaderDeliveryID), "retries keep the delivery id")
+	assert.Equal(t, "application/json", last.Header.Get("Content-Type"))
+
+	var payload map[string]string
+	require.NoError(t, json.Unmarshal(recorder.bodies[2], &payload))
+	assert.Equal(t, "confirmed", payload["status"])
+	assert.Equal(t, Sign([]byte("s3cret"), last.Header.Get(HeaderTimestamp), recorder.bodies[2]), last.Header.Get(HeaderSignature))
+	assert.NotEqual(t, Sign([]byte("other"), last.Header.Get(HeaderTimestamp), recorder.bodies[2]), last.Header.Get(HeaderSignature))
+}
+
+func TestNotifierGivesUp(t *testing.T) {
+	recorder := &recordingServer{statuses: []int{500, 500, 500, 500}}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	err := newTestNotifier("", 2).Deliver(context.Background(), server.URL, "payload")
+	require.ErrorContains(t, err, "after 2 attempt(s)")
+	require.Len(t, recorder.requests, 2)
+	assert.Empty(t, recorder.requests[0].Header.Get(HeaderSignature), "no secret, no signature")
+
+	// Client errors are not retried
+	recorder = &recordingServer{statuses: []int{http.StatusNotFound}}
+	server = httptest.NewServer(recorder)
+	defer server.Close()
+	err = newTestNotifier("", 5).Deliver(context.Background(), server.URL, "payload")
+	require.ErrorContains(t, err, "status 404")
+	require.Len(t, recorder.requests, 1)
+}
+
+func TestValidateURL(t *testing.T) {
+	for _, target := range []string{"https://agent.example/hook", "http://127.0.0.1:8080/cb"} {
+		assert.NoError(t, ValidateURL(target), target)
+	}
+	for _, target := range []string{"", "ftp://agent.example/hook", "https://", "/relative", "://bad"} {
+		assert.Error(t, ValidateURL(target), target)
+	}
+}
@@ -138,7 +138,8 @@ func TestApproveTransactionToolRealFlow(t *testing.T) {
 		rejectArgs := map[string]any{
 			"transaction_hash": txHash,
 			"action":           "reject",
-			"reason":           "Suspicious transaction - high amount to unknown address from untrusted origin",
+			"reason":           "suspicious_activity",
+			"details":          "High amount to unknown address from untrusted origin",
 		}
 		rejectResult, err := mcpClient.CallTool("approve_transaction", rejectArgs)
 		require.NoError(t, err, "failed to reject transaction")
@@ -329,7 +330,8 @@ func TestApproveTransactionToolReject(t *testing.T) {
 	args := map[string]interface{}{
 		"transaction_hash": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
 		"action":           "reject",
-		"reason":           "Transaction appears suspicious",
+		"reason":           "suspicious_activity",
+		"details":          "Transaction appears suspicious",
 	}
 	result, err := client.CallTool("approve_transaction", args)
 	require.NoError(t, err, "failed to call approve_transaction tool for rejection")
@@ -355,6 +357,11 @@ func TestApproveTransactionToolParameterValidation(t *testing.T) {
 
 	require.NoError(t, client.Initialize(ctx), "failed to initialize MCP client")
 
+	// Approving needs an unlocked wallet before the transaction is looked up
+	createResult, err := client.CallTool("create_wallet", map[string]interface{}{"chain": "ETH"})
+	require.NoError(t, err, "failed to create wallet")
+	require.NotNil(t, createResult, "create wallet result should not be nil")
+
 	testCases := []struct {
 		name          string
 		args          map[string]interface{}
@@ -438,6 +445,11 @@ func TestApproveTransactionToolActionTypes(t *testing.T) {
 
 	require.NoError(t, client.Initialize(ctx), "failed to initialize MCP client")
 
+	// Approving needs an unlocked wallet before the transaction is looked up
+	createResult, err := client.CallTool("create_wallet", map[string]interface{}{"chain": "ETH"})
+	require.NoError(t, err, "failed to create wallet")
+	require.NotNil(t, createResult, "create wallet result should not be nil")
+
 	testCases := []struct {
 		name   string
 		action string
@@ -453,7 +465,7 @@ func TestApproveTransactionToolActionTypes(t *testing.T) {
 		{
 			name:   "reject_action_with_reason",
 			action: "reject",
-			reason: "Suspicious transaction",
+			reason: "suspicious_activity",
 			valid:  true,
 		},
 		{
@@ -465,7 +477,7 @@ func TestApproveTransactionToolActionTypes(t *testing.T) {
 		{
 			name:   "reject_uppercase",
 			action: "REJECT",
-			reason: "Test reason",
+			reason: "user_request",
 			valid:  false, // should be case sensitive
 		},
 		{
@@ -521,6 +533,11 @@ func TestApproveTransactionToolTransactionHashFormats(t *testing.T) {
 
 	require.NoError(t, client.Initialize(ctx), "failed to initialize MCP client")
 
+	// Approving needs an unlocked wallet before the transaction is looked up
+	createResult, err := client.CallTool("create_wallet", map[string]interface{}{"chain": "ETH"})
+	require.NoError(t, err, "failed to create wallet")
+	require.NotNil(t, createResult, "create wallet result should not be nil")
+
 	testCases := []struct {
 		name            string
 		transactionHash string
@@ -590,44 +607,55 @@ func TestApproveTransactionToolReasonValidation(t *testing.T) {
 	testCases := []struct {
 		name        string
 		reason      string
-		expectError bool
+		details     string
+		expectError string
 		description string
 	}{
 		{
-			name:        "simple_reason",
-			reason:      "Transaction looks suspicious",
-			expectError: false,
-			description: "simple rejection reason should be accepted",
+			name:        "listed_reason",
+			reason:      "suspicious_activity",
+			description: "a reason from the taxonomy should be accepted",
+		},
+		{
+			name:        "listed_reason_with_details",
+			reason:      "high_gas_fee",
+			details:     "Gas is 3x the usual price",
+			description: "details may accompany any reason",
 		},
 		{
-			name:        "detailed_reason",
-			reason:      "Transaction amount is unusually high and destination address is not in whitelist",
-			expectError: false,
-			description: "detailed rejection reason should be accepted",
+			name:        "mixed_case_reason",
+			reason:      "  User_Request ",
+			description: "reasons are matched ignoring case and surrounding spaces",
 		},
 		{
-			name:        "reason_with_special_chars",
-			reason:      "Reason: Transaction contains special chars & symbols! @#$%",
-			expectError: false,
-			description: "reason with special characters should be accepted",
+			name:        "other_with_details",
+			reason:      "other",
+			details:     "交易看起来可疑 - Reason with special chars & symbols! @#$%",
+			description: "other is accepted with free-text details",
+		},
+		{
+			name:        "other_without_details",
+			reason:      "other",
+			expectError: "details are required",
+			description: "other needs details",
+		},
+		{
+			name:        "free_text_reason",
+			reason:      "Transaction looks suspicious",
+			expectError: "invalid rejection reason",
+			description: "free text is not a valid reason",
 		},
 		{
 			name:        "empty_reason",
 			reason:      "",
-			expectError: true,
+			expectError: "reason",
 			description: "empty reason should be rejected for reject action",
 		},
 		{
 			name:        "whitespace_only_reason",
 			reason:      "   ",
-			expectError: false,
-			description: "whitespace-only reason should be accepted as valid string",
-		},
-		{
-			name:        "unicode_reason",
-			reason:      "交易看起来可疑 - Transaction looks suspicious",
-			expectError: false,
-			description: "unicode characters in reason should be accepted",
+			expectError: "rejection reason is required",
+			description: "whitespace-only reason should be rejected",
 		},
 	}
 
@@ -638,16 +666,20 @@ func TestApproveTransactionToolReasonValidation(t *testing.T) {
 				"action":           "reject",
 				"reason":           tc.reason,
 			}
+			if tc.details != "" {
+				args["details"] = tc.details
+			}
 
 			result, err := client.CallTool("approve_transaction", args)
 			require.NoError(t, err, "tool call should not return Go error")
 			require.NotNil(t, result, "result should not be nil")
 
-			if tc.expectError {
+			if tc.expectError != "" {
 				require.True(t, result.IsError, tc.description)
 				textContent := getTextContent(result)
 				assert.Contains(t, strings.ToLower(textContent), "reason",
 					"error should mention reason field")
+				assert.Contains(t, textContent, tc.expectError, tc.description)
 			} else {
 				// Valid reasons should pass validation but fail on transaction not found
 				if result.IsError {
@@ -757,6 +789,11 @@ func TestApproveTransactionToolErrorHandling(t *testing.T) {
 
 	require.NoError(t, client.Initialize(ctx), "failed to initialize MCP client")
 
+	// Approving needs an unlocked wallet before the transaction is looked up
+	createResult, err := client.CallTool("create_wallet", map[string]interface{}{"chain": "ETH"})
+	require.NoError(t, err, "failed to create wallet")
+	require.NotNil(t, createResult, "create wallet result should not be nil")
+
 	errorTestCases := []struct {
 		name          string
 		args          map[string]interface{}
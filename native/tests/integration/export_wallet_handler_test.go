@@ -0,0 +1,138 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/algonius/algonius-wallet/native/tests/integration/env"
+	"github.com/stretchr/testify/require"
+)
+
+const exportTestMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// importWalletForExport imports the test mnemonic over native messaging and
+// returns the address
+func importWalletForExport(ctx context.Context, t *testing.T, nativeMsg *env.NativeMessagingManager, password string) string {
+	importResponse, err := nativeMsg.RpcRequest(ctx, "import_wallet", map[string]interface{}{
+		"mnemonic":       exportTestMnemonic,
+		"password":       password,
+		"chain":          "ethereum",
+		"derivationPath": "m/44'/60'/0'/0/0",
+	})
+	require.NoError(t, err, "failed to import wallet")
+	require.NotContains(t, importResponse, "error", "import wallet should not return error")
+
+	result, ok := importResponse["result"].(map[string]interface{})
+	require.True(t, ok, "result should be a map")
+	address, ok := result["address"].(string)
+	require.True(t, ok, "address should be a string")
+
+	// Wait for wallet to be saved
+	time.Sleep(100 * time.Millisecond)
+	return address
+}
+
+func TestExportWalletHandler_Success(t *testing.T) {
+	ctx := context.Background()
+	testEnv, err := env.NewMcpHostTestEnvironment(nil)
+	require.NoError(t, err, "failed to create test environment")
+	defer testEnv.Cleanup()
+
+	require.NoError(t, testEnv.Setup(ctx), "failed to setup test environment")
+
+	nativeMsg := testEnv.GetNativeMsg()
+	require.NotNil(t, nativeMsg, "Native messaging manager should not be nil")
+
+	address := importWalletForExport(ctx, t, nativeMsg, "ExportPassword123!")
+
+	exportResponse, err := nativeMsg.RpcRequest(ctx, "export_wallet", map[string]interface{}{
+		"password": "ExportPassword123!",
+	})
+	require.NoError(t, err, "failed to export wallet")
+	require.NotContains(t, exportResponse, "error", "export wallet should not return error")
+
+	result, ok := exportResponse["result"].(map[string]interface{})
+	require.True(t, ok, "export result should be a map")
+	require.Equal(t, address, result["address"], "export should be of the imported wallet")
+	require.Equal(t, exportTestMnemonic, result["mnemonic"], "export should return the imported mnemonic")
+	privateKey, ok := result["private_key"].(string)
+	require.True(t, ok, "private key should be a string")
+	require.NotEmpty(t, privateKey, "private key should not be empty")
+}
+
+func TestExportWalletHandler_WrongPassword(t *testing.T) {
+	ctx := context.Background()
+	testEnv, err := env.NewMcpHostTestEnvironment(nil)
+	require.NoError(t, err, "failed to create test environment")
+	defer testEnv.Cleanup()
+
+	require.NoError(t, testEnv.Setup(ctx), "failed to setup test environment")
+
+	nativeMsg := testEnv.GetNativeMsg()
+	require.NotNil(t, nativeMsg, "Native messaging manager should not be nil")
+
+	importWalletForExport(ctx, t, nativeMsg, "ExportPassword123!")
+
+	exportResponse, err := nativeMsg.RpcRequest(ctx, "export_wallet", map[string]interface{}{
+		"password": "WrongPassword123!",
+	})
+	require.NoError(t, err, "RPC call should not fail")
+	require.NotContains(t, exportResponse, "result", "export wallet should not return secrets for a wrong password")
+	require.Contains(t, exportResponse, "error", "export wallet should return error for wrong password")
+
+	errorInfo, ok := exportResponse["error"].(map[string]interface{})
+	require.True(t, ok, "error should be a map")
+	require.Equal(t, float64(-32001), errorInfo["code"], "should return invalid password error code")
+	require.Contains(t, errorInfo["message"], "Failed to export wallet", "error message should indicate export failure")
+}
+
+func TestExportWalletHandler_Locked(t *testing.T) {
+	ctx := context.Background()
+	testEnv, err := env.NewMcpHostTestEnvironment(nil)
+	require.NoError(t, err, "failed to create test environment")
+	defer testEnv.Cleanup()
+
+	require.NoError(t, testEnv.Setup(ctx), "failed to setup test environment")
+
+	nativeMsg := testEnv.GetNativeMsg()
+	require.NotNil(t, nativeMsg, "Native messaging manager should not be nil")
+
+	importWalletForExport(ctx, t, nativeMsg, "ExportPassword123!")
+
+	_, err = nativeMsg.RpcRequest(ctx, "lock_wallet", map[string]interface{}{})
+	require.NoError(t, err, "failed to lock wallet")
+
+	exportResponse, err := nativeMsg.RpcRequest(ctx, "export_wallet", map[string]interface{}{
+		"password": "ExportPassword123!",
+	})
+	require.NoError(t, err, "RPC call should not fail")
+	require.Contains(t, exportResponse, "error", "export wallet should refuse while locked")
+
+	errorInfo, ok := exportResponse["error"].(map[string]interface{})
+	require.True(t, ok, "error should be a map")
+	require.Equal(t, float64(-32001), errorInfo["code"], "should return the locked wallet error code")
+}
+
+func TestExportWalletNotExposedOverMCP(t *testing.T) {
+	ctx := context.Background()
+	testEnv, err := env.NewMcpHostTestEnvironment(nil)
+	require.NoError(t, err, "failed to create test environment")
+	defer testEnv.Cleanup()
+
+	require.NoError(t, testEnv.Setup(ctx), "failed to setup test environment")
+
+	client := testEnv.GetMcpClient()
+	require.NotNil(t, client, "MCP client should not be nil")
+	require.NoError(t, client.Initialize(ctx), "failed to initialize MCP client")
+
+	result, err := client.CallTool("export_wallet", map[string]interface{}{
+		"password": "ExportPassword123!",
+	})
+	if err == nil {
+		require.True(t, result.IsError, "export_wallet must not be an MCP tool")
+	}
+}
@@ -0,0 +1,91 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/tests/integration/env"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetGasEstimateTool(t *testing.T) {
+	ctx := context.Background()
+	testEnv, err := env.NewMcpHostTestEnvironment(nil)
+	require.NoError(t, err, "failed to create test environment")
+	defer testEnv.Cleanup()
+
+	require.NoError(t, testEnv.Setup(ctx), "failed to setup test environment")
+
+	client := testEnv.GetMcpClient()
+	require.NotNil(t, client, "MCP client should not be nil")
+
+	require.NoError(t, client.Initialize(ctx), "failed to initialize MCP client")
+
+	const (
+		evmFrom = "0x742d35Cc6634C0532925a3b844Bc454e4438f44e"
+		evmTo   = "0x1234567890123456789012345678901234567890"
+		solFrom = "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+		solTo   = "7EcDhSYGxXyscszYEp35KHN8vvw3svAuLKTzXwCFLtV"
+	)
+
+	for _, tc := range []struct {
+		chain  string
+		from   string
+		to     string
+		symbol string
+	}{
+		{chain: "ethereum", from: evmFrom, to: evmTo, symbol: "ETH"},
+		{chain: "bsc", from: evmFrom, to: evmTo, symbol: "BNB"},
+	} {
+		t.Run(tc.chain, func(t *testing.T) {
+			result, err := client.CallTool("get_gas_estimate", map[string]interface{}{
+				"chain":  tc.chain,
+				"from":   tc.from,
+				"to":     tc.to,
+				"amount": "0.01",
+			})
+			require.NoError(t, err, "failed to call get_gas_estimate tool")
+			require.False(t, result.IsError, "gas estimate should not return error: %v", result.Content)
+
+			text := result.Content[0].(mcp.TextContent).Text
+			assert.Contains(t, text, "### Gas Estimate")
+			assert.Contains(t, text, "- **Chain**: `"+tc.chain+"`")
+			assert.Regexp(t, regexp.MustCompile("- \\*\\*Gas Limit\\*\\*: `[1-9][0-9]*`"), text)
+			assert.Regexp(t, regexp.MustCompile("- \\*\\*Gas Price\\*\\*: `[0-9.]+ gwei`"), text)
+			assert.Regexp(t, regexp.MustCompile("- \\*\\*Estimated Fee\\*\\*: `[0-9.]+ "+tc.symbol+"`"), text)
+		})
+	}
+
+	t.Run("solana", func(t *testing.T) {
+		result, err := client.CallTool("get_gas_estimate", map[string]interface{}{
+			"chain":  "solana",
+			"from":   solFrom,
+			"to":     solTo,
+			"amount": "0.5",
+		})
+		require.NoError(t, err, "failed to call get_gas_estimate tool")
+		require.False(t, result.IsError, "gas estimate should not return error: %v", result.Content)
+
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Regexp(t, regexp.MustCompile("- \\*\\*Compute Units\\*\\*: `[1-9][0-9]*`"), text)
+		assert.Regexp(t, regexp.MustCompile("- \\*\\*Compute Unit Price\\*\\*: `[0-9.]+ micro-lamports`"), text)
+		assert.Regexp(t, regexp.MustCompile("- \\*\\*Estimated Fee\\*\\*: `[0-9.]+ SOL`"), text)
+	})
+
+	t.Run("InvalidAddress", func(t *testing.T) {
+		result, err := client.CallTool("get_gas_estimate", map[string]interface{}{
+			"chain":  "ethereum",
+			"from":   evmFrom,
+			"to":     solTo,
+			"amount": "0.01",
+		})
+		require.NoError(t, err, "call should succeed but return error result")
+		assert.True(t, result.IsError, "a Solana recipient should be rejected on Ethereum")
+	})
+}
@@ -0,0 +1,63 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/tests/integration/env"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTransactionStatusTool(t *testing.T) {
+	ctx := context.Background()
+	testEnv, err := env.NewMcpHostTestEnvironment(nil)
+	require.NoError(t, err, "failed to create test environment")
+	defer testEnv.Cleanup()
+
+	require.NoError(t, testEnv.Setup(ctx), "failed to setup test environment")
+
+	client := testEnv.GetMcpClient()
+	require.NotNil(t, client, "MCP client should not be nil")
+
+	require.NoError(t, client.Initialize(ctx), "failed to initialize MCP client")
+
+	status := regexp.MustCompile("- \\*\\*Status\\*\\*: `(confirmed|pending|failed)`")
+	txHash := "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"
+
+	t.Run("WithChain", func(t *testing.T) {
+		result, err := client.CallTool("get_transaction_status", map[string]interface{}{
+			"transaction_hash": txHash,
+			"chain":            "ethereum",
+		})
+		require.NoError(t, err, "failed to call get_transaction_status tool")
+		require.False(t, result.IsError, "status lookup should not return error: %v", result.Content)
+
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, "### Transaction Status:")
+		assert.Contains(t, text, "- **Chain**: `ethereum`")
+		assert.Regexp(t, status, text)
+	})
+
+	t.Run("DetectsChainFor0xHashes", func(t *testing.T) {
+		result, err := client.CallTool("get_transaction_status", map[string]interface{}{
+			"transaction_hash": txHash,
+		})
+		require.NoError(t, err, "failed to call get_transaction_status tool")
+		require.False(t, result.IsError, "status lookup without a chain should not return error: %v", result.Content)
+		assert.Regexp(t, status, result.Content[0].(mcp.TextContent).Text)
+	})
+
+	t.Run("UndetectableHashNeedsChain", func(t *testing.T) {
+		result, err := client.CallTool("get_transaction_status", map[string]interface{}{
+			"transaction_hash": "not-a-hash",
+		})
+		require.NoError(t, err, "call should succeed but return error result")
+		assert.True(t, result.IsError, "a hash of unknown format without a chain should be rejected")
+	})
+}
@@ -0,0 +1,151 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algonius/algonius-wallet/native/tests/integration/env"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendTokenTool(t *testing.T) {
+	ctx := context.Background()
+	testEnv, err := env.NewMcpHostTestEnvironment(nil)
+	require.NoError(t, err, "failed to create test environment")
+	defer testEnv.Cleanup()
+
+	require.NoError(t, testEnv.Setup(ctx), "failed to setup test environment")
+
+	client := testEnv.GetMcpClient()
+	require.NotNil(t, client, "MCP client should not be nil")
+
+	require.NoError(t, client.Initialize(ctx), "failed to initialize MCP client")
+
+	// Solana sends are signed with the key of the created wallet
+	createResult, err := client.CallTool("create_wallet", map[string]interface{}{"chain": "solana"})
+	require.NoError(t, err, "failed to call create_wallet tool")
+	require.NotNil(t, createResult, "create_wallet tool result should not be nil")
+	solFrom, err := extractAddress(getTextContent(createResult))
+	require.NoError(t, err, "create_wallet should report the wallet address")
+
+	// Native SOL is converted to lamports
+	solResult, err := client.CallTool("send_token", map[string]interface{}{
+		"chain":  "sol",
+		"from":   solFrom,
+		"to":     "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY",
+		"token":  "SOL",
+		"amount": "0.2",
+	})
+	require.NoError(t, err, "failed to call send_token tool for SOL")
+	require.NotNil(t, solResult, "send_token tool result should not be nil for SOL")
+	require.False(t, solResult.IsError, "send_token SOL path should succeed: %s", getTextContent(solResult))
+	solText := getTextContent(solResult)
+	require.Contains(t, solText, "**Token**: `SOL` (native)")
+	require.Contains(t, solText, "**Base Unit Amount**: `200000000`")
+	require.Contains(t, solText, "**Transaction Hash**")
+
+	// SPL tokens are resolved by symbol to their mint and decimals
+	splResult, err := client.CallTool("send_token", map[string]interface{}{
+		"chain":  "solana",
+		"from":   solFrom,
+		"to":     "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY",
+		"token":  "USDC",
+		"amount": "12.5",
+	})
+	require.NoError(t, err, "failed to call send_token tool for SPL USDC")
+	require.NotNil(t, splResult, "send_token tool result should not be nil for SPL USDC")
+	require.False(t, splResult.IsError, "send_token SPL path should succeed: %s", getTextContent(splResult))
+	splText := getTextContent(splResult)
+	require.Contains(t, splText, "`EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v`")
+	require.Contains(t, splText, "**Base Unit Amount**: `12500000`")
+
+	// Native ETH and ERC-20 sends reach the wallet manager; without a funded
+	// wallet they may fail there, but never on resolving the token
+	ethResult, err := client.CallTool("send_token", map[string]interface{}{
+		"chain":  "ethereum",
+		"from":   "0x1234567890123456789012345678901234567890",
+		"to":     "0x0987654321098765432109876543210987654321",
+		"token":  "ETH",
+		"amount": "1.5",
+	})
+	require.NoError(t, err, "failed to call send_token tool for ETH")
+	require.NotNil(t, ethResult, "send_token tool result should not be nil for ETH")
+	require.NotContains(t, getTextContent(ethResult), "TOKEN_NOT_SUPPORTED")
+
+	erc20Result, err := client.CallTool("send_token", map[string]interface{}{
+		"chain":  "ethereum",
+		"from":   "0x1234567890123456789012345678901234567890",
+		"to":     "0x0987654321098765432109876543210987654321",
+		"token":  "USDC",
+		"amount": "100",
+	})
+	require.NoError(t, err, "failed to call send_token tool for ERC-20")
+	require.NotNil(t, erc20Result, "send_token tool result should not be nil for ERC-20")
+	require.NotContains(t, getTextContent(erc20Result), "TOKEN_NOT_SUPPORTED")
+}
+
+func TestSendTokenToolValidation(t *testing.T) {
+	ctx := context.Background()
+	testEnv, err := env.NewMcpHostTestEnvironment(nil)
+	require.NoError(t, err, "failed to create test environment")
+	defer testEnv.Cleanup()
+
+	require.NoError(t, testEnv.Setup(ctx), "failed to setup test environment")
+
+	client := testEnv.GetMcpClient()
+	require.NotNil(t, client, "MCP client should not be nil")
+
+	require.NoError(t, client.Initialize(ctx), "failed to initialize MCP client")
+
+	testCases := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{
+			name: "missing token",
+			args: map[string]interface{}{
+				"chain":  "ethereum",
+				"from":   "0x1234567890123456789012345678901234567890",
+				"to":     "0x0987654321098765432109876543210987654321",
+				"amount": "1",
+			},
+			want: "token",
+		},
+		{
+			name: "unknown symbol",
+			args: map[string]interface{}{
+				"chain":  "ethereum",
+				"from":   "0x1234567890123456789012345678901234567890",
+				"to":     "0x0987654321098765432109876543210987654321",
+				"token":  "NOT_A_TOKEN",
+				"amount": "1",
+			},
+			want: "NOT_A_TOKEN",
+		},
+		{
+			name: "too many decimals",
+			args: map[string]interface{}{
+				"chain":  "ethereum",
+				"from":   "0x1234567890123456789012345678901234567890",
+				"to":     "0x0987654321098765432109876543210987654321",
+				"token":  "USDC",
+				"amount": "0.0000001",
+			},
+			want: "decimals",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := client.CallTool("send_token", tc.args)
+			require.NoError(t, err, "send_token should report validation errors in the result")
+			require.NotNil(t, result)
+			require.True(t, result.IsError, "expected a validation error")
+			require.Contains(t, getTextContent(result), tc.want)
+		})
+	}
+}
@@ -56,10 +56,15 @@ func TestSendTransactionTool(t *testing.T) {
 	require.NoError(t, err, "failed to call send_transaction tool for BSC")
 	require.NotNil(t, bscResult, "send_transaction tool result should not be nil for BSC")
 
-	// Test Solana transaction (alias path: sol -> solana)
+	// Test Solana transaction (alias path: sol -> solana), signed with the key
+	// of a created Solana wallet
+	solCreateResult, err := client.CallTool("create_wallet", map[string]interface{}{"chain": "solana"})
+	require.NoError(t, err, "failed to call create_wallet tool for Solana")
+	solFrom, err := extractAddress(getTextContent(solCreateResult))
+	require.NoError(t, err, "create_wallet should report the Solana wallet address")
 	solArgs := map[string]interface{}{
 		"chain":  "sol",
-		"from":   "FnVyf9f7hFmA6N5HtV6nQWmvMRGsiE9zraFMvx6bMpiK",
+		"from":   solFrom,
 		"to":     "5oNDL3swdJJF1g9DzJiZ4ynHXgszjAEpUkxVYejchzrY",
 		"amount": "0.2",
 		"token":  "SOL",